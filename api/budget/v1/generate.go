@@ -0,0 +1,5 @@
+// Package budgetv1 holds the protobuf messages and gRPC stubs generated from budget.proto
+package budgetv1
+
+// Needs protoc with the protoc-gen-go and protoc-gen-go-grpc plugins on PATH
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative budget.proto
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// client calls the budget HTTP API
+type client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// newClient creates an API client for the server at baseURL
+// A non-nil handler serves the requests in-process instead of over the network.
+func newClient(baseURL, token string, handler http.Handler) *client {
+	c := &client{baseURL: strings.TrimRight(baseURL, "/"), token: token, http: http.DefaultClient}
+	if handler != nil {
+		c.http = &http.Client{Transport: handlerTransport{handler}}
+	}
+	return c
+}
+
+// get decodes the JSON response of a GET request into out
+func (c *client) get(path string, query url.Values, out any) error {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return c.do(http.MethodGet, path, "", nil, out)
+}
+
+// post sends body as JSON and decodes the JSON response into out
+func (c *client) post(path string, body, out any) error {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return c.do(http.MethodPost, path, "application/json", bytes.NewReader(raw), out)
+}
+
+// upload sends a file and form fields as multipart/form-data
+func (c *client) upload(path, filePath string, fields map[string]string, out any) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for name, value := range fields {
+		if value != "" {
+			form.WriteField(name, value)
+		}
+	}
+	part, err := form.CreateFormFile("file", filepath.Base(filePath))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return err
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+	return c.do(http.MethodPost, path, form.FormDataContentType(), &body, out)
+}
+
+func (c *client) do(method, path, contentType string, body io.Reader, out any) error {
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		// Errors are plain-text messages
+		return fmt.Errorf("%s %s: %s", method, path, strings.TrimSpace(string(raw)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// handlerTransport serves requests with an in-process handler
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorder := httptest.NewRecorder()
+	t.handler.ServeHTTP(recorder, req)
+	return recorder.Result(), nil
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/billybbuffum/budget/internal/infrastructure/csv"
+	"github.com/spf13/cobra"
+)
+
+func newAddCommand(api func() *client) *cobra.Command {
+	var account, category, date string
+	var inflow bool
+
+	cmd := &cobra.Command{
+		Use:   "add AMOUNT DESCRIPTION",
+		Short: "Record a transaction",
+		Long: `Record a transaction. AMOUNT is spending unless --inflow is given.
+Accounts and categories can be named or given by ID.`,
+		Example: `  budgetctl add 4.50 "Coffee" --account Checking --category "Dining Out"
+  budgetctl add 2500 "Paycheck" --account Checking --inflow`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			amount, err := csv.ParseAmount(args[0])
+			if err != nil {
+				return err
+			}
+			if amount < 0 {
+				return fmt.Errorf("amount must be positive; use --inflow for money coming in")
+			}
+			if !inflow {
+				amount = -amount
+			}
+			day := time.Now()
+			if date != "" {
+				if day, err = time.Parse("2006-01-02", date); err != nil {
+					return fmt.Errorf("date must look like YYYY-MM-DD")
+				}
+			}
+
+			accountID, err := api().resolveAccount(account)
+			if err != nil {
+				return err
+			}
+			var categoryID *string
+			if category != "" {
+				id, err := api().resolveCategory(category)
+				if err != nil {
+					return err
+				}
+				categoryID = &id
+			}
+
+			var transaction domain.Transaction
+			if err := api().post("/api/transactions", map[string]any{
+				"account_id":  accountID,
+				"category_id": categoryID,
+				"amount":      amount,
+				"description": args[1],
+				"date":        time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC),
+			}, &transaction); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Added %s %s on %s\n", formatAmount(transaction.Amount), transaction.Description, transaction.Date.Format("2006-01-02"))
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&account, "account", "a", "", "account name or ID")
+	cmd.Flags().StringVarP(&category, "category", "c", "", "category name or ID")
+	cmd.Flags().StringVarP(&date, "date", "d", "", "date as YYYY-MM-DD (default today)")
+	cmd.Flags().BoolVar(&inflow, "inflow", false, "money coming in rather than spending")
+	cmd.MarkFlagRequired("account")
+	return cmd
+}
+
+func newSummaryCommand(api func() *client) *cobra.Command {
+	var period string
+
+	cmd := &cobra.Command{
+		Use:   "summary",
+		Short: "Show the budget for a month",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if period == "" {
+				period = time.Now().Format("2006-01")
+			}
+			var summary struct {
+				ReadyToAssign int64                       `json:"ready_to_assign"`
+				Categories    []*domain.AllocationSummary `json:"categories"`
+			}
+			if err := api().get("/api/allocations/summary", url.Values{"period": {period}}, &summary); err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "%s  Ready to Assign: %s\n\n", period, formatAmount(summary.ReadyToAssign))
+			table := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+			fmt.Fprintln(table, "Category\tAssigned\tActivity\tAvailable\t")
+			for _, category := range summary.Categories {
+				var assigned int64
+				if category.Allocation != nil {
+					assigned = category.Allocation.Amount
+				}
+				fmt.Fprintf(table, "%s\t%s\t%s\t%s\t\n", category.Category.Name, formatAmount(assigned), formatAmount(category.Activity), formatAmount(category.Available))
+			}
+			return table.Flush()
+		},
+	}
+	cmd.Flags().StringVarP(&period, "period", "p", "", "month as YYYY-MM (default this month)")
+	return cmd
+}
+
+func newImportCommand(api func() *client) *cobra.Command {
+	var account string
+	var mapping = map[string]*string{
+		"date_column":        new(string),
+		"amount_column":      new(string),
+		"description_column": new(string),
+		"date_format":        new(string),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Import a bank export (.ofx, .qfx, .qif or .csv)",
+		Long: `Import a bank export into an account. CSV columns are detected from the header
+unless given with the column flags.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			accountID, err := api().resolveAccount(account)
+			if err != nil {
+				return err
+			}
+			fields := map[string]string{"account_id": accountID}
+			path := "/api/transactions/import"
+			if strings.EqualFold(filepath.Ext(args[0]), ".csv") {
+				path = "/api/import/csv"
+				for name, value := range mapping {
+					fields[name] = *value
+				}
+			}
+
+			var result struct {
+				TotalTransactions    int      `json:"total_transactions"`
+				ImportedTransactions int      `json:"imported_transactions"`
+				SkippedDuplicates    int      `json:"skipped_duplicates"`
+				Errors               []string `json:"errors"`
+				Warnings             []string `json:"warnings"`
+				NewAccountBalance    int64    `json:"new_account_balance"`
+				AlreadyImported      bool     `json:"already_imported"`
+			}
+			if err := api().upload(path, args[0], fields, &result); err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if result.AlreadyImported {
+				fmt.Fprintln(out, "This file was already imported; nothing changed")
+				return nil
+			}
+			fmt.Fprintf(out, "Imported %d of %d transactions (%d duplicates skipped); balance is now %s\n",
+				result.ImportedTransactions, result.TotalTransactions, result.SkippedDuplicates, formatAmount(result.NewAccountBalance))
+			for _, warning := range result.Warnings {
+				fmt.Fprintf(out, "warning: %s\n", warning)
+			}
+			for _, message := range result.Errors {
+				fmt.Fprintf(out, "error: %s\n", message)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&account, "account", "a", "", "account name or ID")
+	cmd.Flags().StringVar(mapping["date_column"], "date-column", "", "CSV column holding the date")
+	cmd.Flags().StringVar(mapping["amount_column"], "amount-column", "", "CSV column holding the amount")
+	cmd.Flags().StringVar(mapping["description_column"], "description-column", "", "CSV column holding the description")
+	cmd.Flags().StringVar(mapping["date_format"], "date-format", "", "CSV date format, e.g. 01/02/2006")
+	cmd.MarkFlagRequired("account")
+	return cmd
+}
+
+// reports are the reports budgetctl can export, by name
+var reports = map[string]string{
+	"bare-bones":     "/api/reports/bare-bones",
+	"emergency-fund": "/api/reports/emergency-fund",
+	"trends":         "/api/reports/trends",
+}
+
+func newReportCommand(api func() *client) *cobra.Command {
+	var period, start, end, output string
+
+	cmd := &cobra.Command{
+		Use:   "report bare-bones|emergency-fund|trends",
+		Short: "Export a report as JSON",
+		Example: `  budgetctl report bare-bones --period 2025-03
+  budgetctl report trends --start 2025-01 --end 2025-06 -o trends.json`,
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: []string{"bare-bones", "emergency-fund", "trends"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := url.Values{}
+			for name, value := range map[string]string{"period": period, "start": start, "end": end} {
+				if value != "" {
+					query.Set(name, value)
+				}
+			}
+
+			var report json.RawMessage
+			if err := api().get(reports[args[0]], query, &report); err != nil {
+				return err
+			}
+			indented, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return err
+			}
+			indented = append(indented, '\n')
+
+			if output == "" {
+				_, err := cmd.OutOrStdout().Write(indented)
+				return err
+			}
+			return os.WriteFile(output, indented, 0o644)
+		},
+	}
+	cmd.Flags().StringVarP(&period, "period", "p", "", "month as YYYY-MM (bare-bones and emergency-fund)")
+	cmd.Flags().StringVar(&start, "start", "", "first month as YYYY-MM (trends)")
+	cmd.Flags().StringVar(&end, "end", "", "last month as YYYY-MM (trends)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "write to this file instead of stdout")
+	return cmd
+}
+
+// resolveAccount returns the ID of the account with the given ID or name
+func (c *client) resolveAccount(nameOrID string) (string, error) {
+	var accounts []*domain.Account
+	if err := c.get("/api/accounts", nil, &accounts); err != nil {
+		return "", err
+	}
+	named := make(map[string]string, len(accounts))
+	for _, account := range accounts {
+		named[account.ID] = account.Name
+	}
+	return resolve("account", nameOrID, named)
+}
+
+// resolveCategory returns the ID of the category with the given ID or name
+func (c *client) resolveCategory(nameOrID string) (string, error) {
+	var categories []*domain.Category
+	if err := c.get("/api/categories", nil, &categories); err != nil {
+		return "", err
+	}
+	named := make(map[string]string, len(categories))
+	for _, category := range categories {
+		named[category.ID] = category.Name
+	}
+	return resolve("category", nameOrID, named)
+}
+
+// resolve finds the ID matching nameOrID exactly, or the only name matching it ignoring case
+func resolve(kind, nameOrID string, names map[string]string) (string, error) {
+	if _, ok := names[nameOrID]; ok {
+		return nameOrID, nil
+	}
+	var matches []string
+	for id, name := range names {
+		if strings.EqualFold(name, nameOrID) {
+			matches = append(matches, id)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no %s named %q", kind, nameOrID)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("more than one %s is named %q; use its ID", kind, nameOrID)
+	}
+}
+
+// formatAmount formats cents as dollars, e.g. -2050 -> "-$20.50"
+func formatAmount(cents int64) string {
+	return domain.Cents(cents).String()
+}
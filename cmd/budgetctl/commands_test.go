@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	names := map[string]string{"a1": "Checking", "a2": "Savings", "a3": "savings"}
+
+	if id, err := resolve("account", "a2", names); err != nil || id != "a2" {
+		t.Errorf("expected an ID to resolve to itself, got %q %v", id, err)
+	}
+	if id, err := resolve("account", "checking", names); err != nil || id != "a1" {
+		t.Errorf("expected names to match ignoring case, got %q %v", id, err)
+	}
+	if _, err := resolve("account", "Savings", names); err == nil || !strings.Contains(err.Error(), "more than one") {
+		t.Errorf("expected an ambiguous name to be refused, got %v", err)
+	}
+	if _, err := resolve("account", "Brokerage", names); err == nil {
+		t.Error("expected an unknown name to be refused")
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	for cents, want := range map[int64]string{0: "$0.00", -2050: "-$20.50", 123456789: "$1,234,567.89"} {
+		if got := formatAmount(cents); got != want {
+			t.Errorf("formatAmount(%d) = %q, want %q", cents, got, want)
+		}
+	}
+}
+
+func TestAddCommand(t *testing.T) {
+	var created map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/accounts", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id":"acct-1","name":"Checking"}]`))
+	})
+	mux.HandleFunc("GET /api/categories", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id":"cat-1","name":"Groceries"}]`))
+	})
+	mux.HandleFunc("POST /api/transactions", func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		json.Unmarshal(raw, &created)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"amount":-1250,"description":"Market","date":"2025-03-01T00:00:00Z"}`))
+	})
+	api := newClient("http://test", "", mux)
+
+	var out bytes.Buffer
+	cmd := newAddCommand(func() *client { return api })
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"12.50", "Market", "--account", "checking", "--category", "Groceries", "--date", "2025-03-01"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if created["account_id"] != "acct-1" || created["category_id"] != "cat-1" || created["amount"] != float64(-1250) || created["date"] != "2025-03-01T00:00:00Z" {
+		t.Errorf("unexpected transaction %v", created)
+	}
+	if out.String() != "Added -$12.50 Market on 2025-03-01\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
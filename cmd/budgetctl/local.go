@@ -0,0 +1,84 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/billybbuffum/budget/config"
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/billybbuffum/budget/internal/infrastructure/csv"
+	"github.com/billybbuffum/budget/internal/infrastructure/database"
+	"github.com/billybbuffum/budget/internal/infrastructure/http/handlers"
+	"github.com/billybbuffum/budget/internal/infrastructure/ofx"
+	"github.com/billybbuffum/budget/internal/infrastructure/qif"
+	"github.com/billybbuffum/budget/internal/infrastructure/repository"
+	"github.com/billybbuffum/budget/internal/infrastructure/script"
+)
+
+// newLocalHandler serves the routes budgetctl uses straight from a SQLite database
+// Services are wired as the server wires them, from the same environment variables,
+// except that plugins aren't loaded.
+func newLocalHandler(path string) (http.Handler, *sql.DB, error) {
+	cfg := config.Load()
+	if err := domain.SetBudgetCurrency(cfg.Currency.Code, cfg.Currency.Decimals); err != nil {
+		return nil, nil, err
+	}
+	if err := domain.SetBudgetTimezone(cfg.Dates.Timezone); err != nil {
+		return nil, nil, err
+	}
+	db, err := database.NewSQLiteDB(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accountRepo := repository.NewAccountRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	categoryGroupRepo := repository.NewCategoryGroupRepository(db)
+	transactionRepo := repository.NewTransactionRepository(db)
+	allocationRepo := repository.NewAllocationRepository(db)
+	budgetStateRepo := repository.NewBudgetStateRepository(db)
+	settingRepo := repository.NewSettingRepository(db)
+	unitOfWork := repository.NewUnitOfWork(db)
+
+	dateBounds := application.DateBounds{PastYears: cfg.Dates.MaxPastYears, FutureDays: cfg.Dates.MaxFutureDays}
+	scriptService := application.NewScriptService(settingRepo, categoryRepo, script.NewRunner(time.Duration(cfg.Scripts.TimeoutMillis)*time.Millisecond))
+	pluginService := application.NewPluginService(categoryRepo, accountRepo, transactionRepo, allocationRepo, nil, []application.CategorizationProvider{scriptService}, nil)
+	payeeService := application.NewPayeeService(repository.NewPayeeRepository(db), repository.NewPayeeRuleRepository(db), accountRepo, categoryRepo)
+	transferHintService := application.NewTransferHintService(repository.NewTransferHintRepository(db), accountRepo, transactionRepo)
+	transferHintService.UseMatching(application.TransferMatching{Days: cfg.TransferMatch.Days, ToleranceBps: cfg.TransferMatch.ToleranceBps, FeeTolerance: cfg.TransferMatch.FeeTolerance})
+	accountService := application.NewAccountService(accountRepo, categoryRepo, budgetStateRepo, transactionRepo, application.NewCategoryGroupService(categoryGroupRepo, categoryRepo), unitOfWork)
+	categoryService := application.NewCategoryService(categoryRepo, categoryGroupRepo, unitOfWork)
+	transactionService := application.NewTransactionService(transactionRepo, accountRepo, categoryRepo, budgetStateRepo, unitOfWork, dateBounds)
+	allocationService := application.NewAllocationService(allocationRepo, categoryRepo, transactionRepo, budgetStateRepo, accountRepo, categoryGroupRepo, repository.NewGoalRepository(db))
+	categoryMoveRepo := repository.NewCategoryMoveRepository(db)
+	allocationService.UseCategoryMoves(categoryMoveRepo)
+	allocationService.UseReadyToAssignTotals(repository.NewReadyToAssignTotalsRepository(db))
+	accountService.UseAllocationService(allocationService)
+	creditCardService := application.NewCreditCardService(categoryMoveRepo, allocationService, unitOfWork)
+	transactionService.UseCreditCards(creditCardService)
+	transactionService.UsePayees(payeeService)
+	importService := application.NewImportService(transactionRepo, accountRepo, budgetStateRepo, repository.NewImportFileRepository(db), settingRepo, ofx.NewParser(), csv.NewParser(), qif.NewParser(), pluginService, payeeService, transferHintService, unitOfWork, dateBounds)
+	reportService := application.NewReportService(categoryRepo, allocationRepo, transactionRepo, accountRepo, settingRepo, repository.NewCPIRepository(db), allocationService)
+
+	accountHandler := handlers.NewAccountHandler(accountService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
+	transactionHandler := handlers.NewTransactionHandler(transactionService)
+	allocationHandler := handlers.NewAllocationHandler(allocationService, creditCardService)
+	importHandler := handlers.NewImportHandler(importService)
+	reportHandler := handlers.NewReportHandler(reportService, application.NewReportCache(time.Minute, budgetStateRepo))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/currency", handlers.GetCurrency)
+	mux.HandleFunc("GET /api/accounts", accountHandler.ListAccounts)
+	mux.HandleFunc("GET /api/categories", categoryHandler.ListCategories)
+	mux.HandleFunc("POST /api/transactions", transactionHandler.CreateTransaction)
+	mux.HandleFunc("GET /api/allocations/summary", allocationHandler.GetAllocationSummary)
+	mux.HandleFunc("POST /api/transactions/import", importHandler.ImportTransactions)
+	mux.HandleFunc("POST /api/import/csv", importHandler.ImportCSV)
+	mux.HandleFunc("GET /api/reports/bare-bones", reportHandler.GetBareBonesBudget)
+	mux.HandleFunc("GET /api/reports/emergency-fund", reportHandler.GetEmergencyFundCoverage)
+	mux.HandleFunc("GET /api/reports/trends", reportHandler.GetTrendReport)
+	return mux, db, nil
+}
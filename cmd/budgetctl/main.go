@@ -0,0 +1,82 @@
+// Command budgetctl manages a budget from the command line
+// It talks to a running server's HTTP API, or with --db opens the SQLite database directly.
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newRootCommand() *cobra.Command {
+	var server, token, dbPath string
+	var api *client
+	var db *sql.DB
+
+	root := &cobra.Command{
+		Use:          "budgetctl",
+		Short:        "Manage your budget from the command line",
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if dbPath == "" {
+				api = newClient(server, token, nil)
+				useServerCurrency(api)
+				return nil
+			}
+			// A server using the same database caches reports for a few minutes, so its
+			// reports may not show changes made here right away
+			handler, opened, err := newLocalHandler(dbPath)
+			if err != nil {
+				return fmt.Errorf("failed to open %s: %w", dbPath, err)
+			}
+			db = opened
+			api = newClient("http://budgetctl.local", "", handler)
+			useServerCurrency(api)
+			return nil
+		},
+		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+			if db == nil {
+				return nil
+			}
+			return db.Close()
+		},
+	}
+	root.PersistentFlags().StringVar(&server, "server", envOr("BUDGET_SERVER", "http://localhost:8080"), "server URL (env BUDGET_SERVER)")
+	root.PersistentFlags().StringVar(&token, "token", os.Getenv("BUDGET_TOKEN"), "API or session token (env BUDGET_TOKEN)")
+	root.PersistentFlags().StringVar(&dbPath, "db", "", "use this SQLite database directly instead of a server")
+
+	apiClient := func() *client { return api }
+	root.AddCommand(
+		newAddCommand(apiClient),
+		newSummaryCommand(apiClient),
+		newImportCommand(apiClient),
+		newReportCommand(apiClient),
+	)
+	return root
+}
+
+// useServerCurrency reads and writes amounts in the server's currency
+// Servers without GET /api/currency keep amounts in cents.
+func useServerCurrency(api *client) {
+	var currency domain.Currency
+	if err := api.get("/api/currency", nil, &currency); err == nil {
+		domain.SetBudgetCurrency(currency.Code, currency.Decimals)
+	}
+}
+
+// envOr returns the environment variable, or fallback when it isn't set
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
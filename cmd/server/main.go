@@ -6,16 +6,31 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/billybbuffum/budget/config"
 	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/billybbuffum/budget/internal/infrastructure/banksync"
+	"github.com/billybbuffum/budget/internal/infrastructure/cpi"
+	"github.com/billybbuffum/budget/internal/infrastructure/csv"
 	"github.com/billybbuffum/budget/internal/infrastructure/database"
+	"github.com/billybbuffum/budget/internal/infrastructure/email"
+	"github.com/billybbuffum/budget/internal/infrastructure/grpc"
 	"github.com/billybbuffum/budget/internal/infrastructure/http"
 	"github.com/billybbuffum/budget/internal/infrastructure/http/handlers"
+	"github.com/billybbuffum/budget/internal/infrastructure/mqtt"
+	"github.com/billybbuffum/budget/internal/infrastructure/notify"
 	"github.com/billybbuffum/budget/internal/infrastructure/ofx"
+	"github.com/billybbuffum/budget/internal/infrastructure/oidc"
+	"github.com/billybbuffum/budget/internal/infrastructure/plugin"
+	"github.com/billybbuffum/budget/internal/infrastructure/qif"
 	"github.com/billybbuffum/budget/internal/infrastructure/repository"
+	"github.com/billybbuffum/budget/internal/infrastructure/script"
+	"github.com/billybbuffum/budget/internal/infrastructure/telegram"
+	"github.com/billybbuffum/budget/internal/infrastructure/ynab"
 )
 
 func main() {
@@ -24,6 +39,12 @@ func main() {
 	if err := cfg.Validate(); err != nil {
 		log.Fatalf("Invalid configuration: %v", err)
 	}
+	if err := domain.SetBudgetCurrency(cfg.Currency.Code, cfg.Currency.Decimals); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	if err := domain.SetBudgetTimezone(cfg.Dates.Timezone); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
 
 	// Initialize database
 	db, err := database.NewSQLiteDB(cfg.Database.Path)
@@ -41,39 +62,294 @@ func main() {
 	transactionRepo := repository.NewTransactionRepository(db)
 	allocationRepo := repository.NewAllocationRepository(db)
 	budgetStateRepo := repository.NewBudgetStateRepository(db)
+	settingRepo := repository.NewSettingRepository(db)
+	cpiRepo := repository.NewCPIRepository(db)
+	pendingTransactionRepo := repository.NewPendingTransactionRepository(db)
+	botChatRepo := repository.NewBotChatRepository(db)
+	apiTokenRepo := repository.NewAPITokenRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db)
+	userTokenRepo := repository.NewUserTokenRepository(db)
+	userIdentityRepo := repository.NewUserIdentityRepository(db)
+	userSettingRepo := repository.NewUserSettingRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	goalRepo := repository.NewGoalRepository(db)
+	payeeRepo := repository.NewPayeeRepository(db)
+	payeeRuleRepo := repository.NewPayeeRuleRepository(db)
+	importFileRepo := repository.NewImportFileRepository(db)
+	allocationTemplateRepo := repository.NewAllocationTemplateRepository(db)
+	transferHintRepo := repository.NewTransferHintRepository(db)
+	transferSuggestionRepo := repository.NewTransferSuggestionRepository(db)
+	transferPairingRepo := repository.NewTransferPairingRepository(db)
+	bankConnectionRepo := repository.NewBankConnectionRepository(db)
+	bankAggregationRepo := repository.NewBankAggregationRepository(db)
+	widgetRepo := repository.NewWidgetRepository(db)
+	maintenanceRepo := repository.NewMaintenanceRepository(db)
+	categoryMoveRepo := repository.NewCategoryMoveRepository(db)
+	unitOfWork := repository.NewUnitOfWork(db)
 
-	// Initialize default data
-	bootstrapService := application.NewBootstrapService(categoryGroupRepo, categoryRepo)
+	// New budgets are set up through the /api/setup wizard unless a starter template is configured
+	bootstrapService := application.NewBootstrapService(categoryGroupRepo, categoryRepo, settingRepo, cfg.Server.Locale)
 	ctx := context.Background()
-	if err := bootstrapService.InitializeDefaultData(ctx); err != nil {
+	if err := bootstrapService.InitializeDefaultData(ctx, cfg.Server.StarterTemplate); err != nil {
 		log.Fatalf("Failed to initialize default data: %v", err)
 	}
-	log.Println("Default data initialized successfully")
 
 	// Initialize OFX parser
 	ofxParser := ofx.NewParser()
+	csvParser := csv.NewParser()
+	qifParser := qif.NewParser()
+
+	// Initialize alert email parser
+	emailParser := email.NewParser()
+
+	// Initialize CPI provider (optional)
+	var cpiProvider application.CPIProvider
+	if cfg.CPI.Provider == "bls" {
+		cpiProvider = cpi.NewBLSProvider(cfg.CPI.BLSAPIKey)
+	}
+
+	// Initialize MQTT publisher (optional)
+	var metricsPublisher application.MetricsPublisher
+	if cfg.MQTT.Broker != "" {
+		mqttClient, err := mqtt.NewClient(cfg.MQTT.Broker, cfg.MQTT.ClientID, cfg.MQTT.Username, cfg.MQTT.Password)
+		if err != nil {
+			log.Fatalf("Failed to connect to MQTT broker: %v", err)
+		}
+		defer mqttClient.Close()
+		metricsPublisher = mqttClient
+	}
+
+	// Initialize the SMTP notification channel (optional)
+	var emailChannel application.NotificationChannel
+	if cfg.SMTP.Host != "" {
+		emailChannel = notify.NewSMTPChannel(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.From)
+	}
+
+	// Initialize the single sign-on identity provider (optional)
+	var identityProvider application.IdentityProvider
+	if cfg.OIDC.Issuer != "" {
+		provider, err := oidc.NewProvider(ctx, cfg.OIDC.ProviderName, cfg.OIDC.Issuer, cfg.OIDC.ClientID, cfg.OIDC.ClientSecret, cfg.OIDC.RedirectURL, cfg.OIDC.Scopes, cfg.OIDC.GroupsClaim)
+		if err != nil {
+			log.Fatalf("Failed to set up OIDC provider: %v", err)
+		}
+		identityProvider = provider
+	}
+
+	// The user's categorize script runs before categorization plugins
+	scriptService := application.NewScriptService(settingRepo, categoryRepo, script.NewRunner(time.Duration(cfg.Scripts.TimeoutMillis)*time.Millisecond))
+
+	// Connect to external plugins (optional)
+	var importTransformers []application.ImportTransformer
+	categorizers := []application.CategorizationProvider{scriptService}
+	var reportProviders []application.ReportProvider
+	for _, endpoint := range cfg.Plugins.Endpoints {
+		name, url, _ := strings.Cut(endpoint, "=")
+		client, err := plugin.NewClient(ctx, name, url, time.Duration(cfg.Plugins.TimeoutSeconds)*time.Second)
+		if err != nil {
+			log.Fatalf("Failed to load plugin %s: %v", name, err)
+		}
+		if client.Supports(application.PluginHookImportTransform) {
+			importTransformers = append(importTransformers, client)
+		}
+		if client.Supports(application.PluginHookCategorize) {
+			categorizers = append(categorizers, client)
+		}
+		if client.Supports(application.PluginHookReports) {
+			reportProviders = append(reportProviders, client)
+		}
+		log.Printf("Loaded plugin %s from %s", name, url)
+	}
 
 	// Initialize services
-	categoryService := application.NewCategoryService(categoryRepo)
+	pluginService := application.NewPluginService(categoryRepo, accountRepo, transactionRepo, allocationRepo, importTransformers, categorizers, reportProviders)
+	categoryService := application.NewCategoryService(categoryRepo, categoryGroupRepo, unitOfWork)
 	categoryGroupService := application.NewCategoryGroupService(categoryGroupRepo, categoryRepo)
-	accountService := application.NewAccountService(accountRepo, categoryRepo, budgetStateRepo, transactionRepo, categoryGroupService)
-	transactionService := application.NewTransactionService(transactionRepo, accountRepo, categoryRepo, allocationRepo, budgetStateRepo)
-	allocationService := application.NewAllocationService(allocationRepo, categoryRepo, transactionRepo, budgetStateRepo, accountRepo)
-	importService := application.NewImportService(transactionRepo, accountRepo, budgetStateRepo, ofxParser)
+	goalService := application.NewGoalService(goalRepo, categoryRepo)
+	payeeService := application.NewPayeeService(payeeRepo, payeeRuleRepo, accountRepo, categoryRepo)
+	transferMatching := application.TransferMatching{Days: cfg.TransferMatch.Days, ToleranceBps: cfg.TransferMatch.ToleranceBps, FeeTolerance: cfg.TransferMatch.FeeTolerance}
+	transferHintService := application.NewTransferHintService(transferHintRepo, accountRepo, transactionRepo)
+	transferHintService.UseMatching(transferMatching)
+	dateBounds := application.DateBounds{PastYears: cfg.Dates.MaxPastYears, FutureDays: cfg.Dates.MaxFutureDays}
+	accountService := application.NewAccountService(accountRepo, categoryRepo, budgetStateRepo, transactionRepo, categoryGroupService, unitOfWork)
+	transactionService := application.NewTransactionService(transactionRepo, accountRepo, categoryRepo, budgetStateRepo, unitOfWork, dateBounds)
+	transferSuggestionService := application.NewTransferSuggestionService(transferSuggestionRepo, transferPairingRepo, transferHintRepo, transactionService, transferMatching, cfg.TransferMatch.ScanDays)
+	transactionService.UseTransferSuggestions(transferSuggestionService)
+	allocationService := application.NewAllocationService(allocationRepo, categoryRepo, transactionRepo, budgetStateRepo, accountRepo, categoryGroupRepo, goalRepo)
+	allocationService.UseCategoryMoves(categoryMoveRepo)
+	allocationService.UseReadyToAssignCache(application.NewReadyToAssignCache())
+	allocationService.UseReadyToAssignTotals(repository.NewReadyToAssignTotalsRepository(db))
+	accountService.UseAllocationService(allocationService)
+	creditCardService := application.NewCreditCardService(categoryMoveRepo, allocationService, unitOfWork)
+	transactionService.UseCreditCards(creditCardService)
+	transactionService.UsePayees(payeeService)
+	allocationTemplateService := application.NewAllocationTemplateService(allocationTemplateRepo, categoryRepo, allocationRepo, allocationService, unitOfWork)
+	budgetTemplateService := application.NewBudgetTemplateService(categoryGroupRepo, categoryRepo, allocationRepo, allocationTemplateRepo, unitOfWork)
+	importService := application.NewImportService(transactionRepo, accountRepo, budgetStateRepo, importFileRepo, settingRepo, ofxParser, csvParser, qifParser, pluginService, payeeService, transferHintService, unitOfWork, dateBounds)
+	bankSyncService := application.NewBankSyncService(bankConnectionRepo, accountRepo, importService, ofx.NewClient(time.Duration(cfg.BankSync.TimeoutSeconds)*time.Second), cfg.BankSync.Key)
+	bankSyncService.UseAggregators(bankAggregationRepo, banksync.NewSimpleFINProvider(time.Duration(cfg.BankSync.TimeoutSeconds)*time.Second))
+	reportService := application.NewReportService(categoryRepo, allocationRepo, transactionRepo, accountRepo, settingRepo, cpiRepo, allocationService)
+	cpiService := application.NewCPIService(cpiRepo, cpiProvider)
+	pendingTransactionService := application.NewPendingTransactionService(pendingTransactionRepo, accountRepo, transactionService, emailParser)
+	quickAddService := application.NewQuickAddService(categoryRepo, accountRepo, transactionService)
+	botService := application.NewBotService(botChatRepo, accountRepo, transactionRepo, allocationService, quickAddService)
+	widgetService := application.NewWidgetService(widgetRepo, categoryRepo, allocationService)
+	apiTokenService := application.NewAPITokenService(apiTokenRepo, accountRepo, categoryRepo, cfg.Auth.TokenDailyWriteQuota)
+	authService := application.NewAuthService(userRepo, sessionRepo, apiTokenRepo, recoveryCodeRepo)
+	ssoService := application.NewSSOService(userRepo, userIdentityRepo, authService, identityProvider, application.SSOMembership{
+		AutoProvision: cfg.OIDC.AutoProvision,
+		DefaultRole:   domain.UserRole(cfg.OIDC.DefaultRole),
+		AdminGroups:   cfg.OIDC.AdminGroups,
+		MemberGroups:  cfg.OIDC.MemberGroups,
+	})
+	userPreferenceService := application.NewUserPreferenceService(userSettingRepo)
+	creditCardService.UsePreferences(userPreferenceService)
+
+	var enabledFeatures []domain.FeatureFlag
+	for _, name := range cfg.Features.Enabled {
+		flag := domain.FeatureFlag(name)
+		if !flag.IsValid() {
+			log.Fatalf("Unknown feature flag in FEATURE_FLAGS: %s", name)
+		}
+		enabledFeatures = append(enabledFeatures, flag)
+	}
+	featureFlagService := application.NewFeatureFlagService(settingRepo, enabledFeatures)
+	setupService := application.NewSetupService(userRepo, accountRepo, authService, accountService, bootstrapService, cfg.Auth.Required)
+	userEmailService := application.NewUserEmailService(userRepo, sessionRepo, userTokenRepo, settingRepo, emailChannel, cfg.Server.PublicURL)
+	if cfg.Auth.TokenKey != "" {
+		userEmailService.UseSigningKey(cfg.Auth.TokenKey)
+	}
+	mqttService := application.NewMQTTService(accountRepo, categoryRepo, settingRepo, allocationService, metricsPublisher, cfg.MQTT.TopicPrefix, cfg.MQTT.DiscoveryPrefix)
 
 	// Initialize handlers
 	accountHandler := handlers.NewAccountHandler(accountService)
 	categoryHandler := handlers.NewCategoryHandler(categoryService)
 	categoryGroupHandler := handlers.NewCategoryGroupHandler(categoryGroupService)
 	transactionHandler := handlers.NewTransactionHandler(transactionService)
-	allocationHandler := handlers.NewAllocationHandler(allocationService)
+	loanHandler := handlers.NewLoanHandler(application.NewLoanService(transactionService))
+	allocationHandler := handlers.NewAllocationHandler(allocationService, creditCardService)
 	importHandler := handlers.NewImportHandler(importService)
+	reportCache := application.NewReportCache(10*time.Minute, budgetStateRepo)
+	reportHandler := handlers.NewReportHandler(reportService, reportCache)
+
+	// Apply changed payee rules to uncategorized transactions in the background
+	jobService := application.NewJobService()
+	recategorizer := application.NewRecategorizer(payeeRuleRepo, transactionService, jobService, cfg.Recategorize.BatchSize, time.Duration(cfg.Recategorize.BatchDelayMillis)*time.Millisecond)
+	payeeService.UseRecategorizer(recategorizer)
+	jobHandler := handlers.NewJobHandler(jobService)
+
+	cpiHandler := handlers.NewCPIHandler(cpiService)
+	pendingTransactionHandler := handlers.NewPendingTransactionHandler(pendingTransactionService, cfg.Email.WebhookSecret)
+	botHandler := handlers.NewBotHandler(botService)
+	widgetHandler := handlers.NewWidgetHandler(widgetService)
+	mqttHandler := handlers.NewMQTTHandler(mqttService)
+	apiTokenHandler := handlers.NewAPITokenHandler(apiTokenService)
+	authHandler := handlers.NewAuthHandler(authService, userEmailService, userPreferenceService, cfg.Auth.SecureCookies)
+	ssoHandler := handlers.NewSSOHandler(ssoService, cfg.Auth.SecureCookies)
+	bootstrapHandler := handlers.NewBootstrapHandler(bootstrapService)
+	setupHandler := handlers.NewSetupHandler(setupService, cfg.Auth.SecureCookies)
+	featureFlagHandler := handlers.NewFeatureFlagHandler(featureFlagService)
+	pluginHandler := handlers.NewPluginHandler(pluginService)
+	scriptHandler := handlers.NewScriptHandler(scriptService)
+	auditService := application.NewAuditService(auditRepo)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	retentionService := application.NewRetentionService(auditRepo, pendingTransactionRepo, sessionRepo, userTokenRepo, application.RetentionPolicy{
+		AuditDays:           cfg.Retention.AuditDays,
+		RejectedPendingDays: cfg.Retention.RejectedPendingDays,
+		ExpiredTokenDays:    cfg.Retention.ExpiredTokenDays,
+	})
+	retentionHandler := handlers.NewRetentionHandler(retentionService)
+	maintenanceService := application.NewMaintenanceService(maintenanceRepo, application.MaintenanceWindow{
+		StartHour: cfg.Maintenance.StartHour,
+		EndHour:   cfg.Maintenance.EndHour,
+		Vacuum:    cfg.Maintenance.Vacuum,
+	})
+	maintenanceHandler := handlers.NewMaintenanceHandler(maintenanceService)
+	cachePrimer := application.NewCachePrimer(accountRepo, categoryRepo, categoryGroupRepo, allocationService)
+	diagnosticsService := application.NewDiagnosticsService(allocationService)
+	diagnosticsService.UseCachePrimer(cachePrimer)
+	diagnosticsHandler := handlers.NewDiagnosticsHandler(diagnosticsService)
+	goalHandler := handlers.NewGoalHandler(goalService)
+	payeeHandler := handlers.NewPayeeHandler(payeeService)
+	allocationTemplateHandler := handlers.NewAllocationTemplateHandler(allocationTemplateService)
+	transferHintHandler := handlers.NewTransferHintHandler(transferHintService)
+	transferSuggestionHandler := handlers.NewTransferSuggestionHandler(transferSuggestionService)
+	bankSyncHandler := handlers.NewBankSyncHandler(bankSyncService)
+	budgetTemplateHandler := handlers.NewBudgetTemplateHandler(budgetTemplateService)
+	exportHandler := handlers.NewExportHandler(application.NewExportService(accountRepo, categoryGroupRepo, categoryRepo, allocationRepo, transactionRepo, categoryMoveRepo, unitOfWork, ynab.NewParser()))
+
+	// Notify users about changes they opted in to (requires SMTP)
+	var notificationService *application.NotificationService
+	if emailChannel != nil {
+		notificationService = application.NewNotificationService(auditRepo, userRepo, userSettingRepo, userPreferenceService, emailChannel)
+		auditService.Subscribe(notificationService.HandleAuditEntry)
+	}
+	digestService := application.NewDigestService(transactionRepo, pendingTransactionRepo, allocationService, userRepo, userSettingRepo, userPreferenceService, emailChannel)
+	digestHandler := handlers.NewDigestHandler(digestService)
+	helpHandler := handlers.NewHelpHandler(application.NewHelpService(allocationService))
 
 	// Setup router
-	router := http.NewRouter(accountHandler, categoryHandler, categoryGroupHandler, transactionHandler, allocationHandler, importHandler)
+	router := http.NewRouter(accountHandler, categoryHandler, categoryGroupHandler, transactionHandler, allocationHandler, importHandler, reportHandler, cpiHandler, pendingTransactionHandler, botHandler, mqttHandler, apiTokenHandler, authHandler, ssoHandler, bootstrapHandler, setupHandler, featureFlagHandler, pluginHandler, scriptHandler, auditHandler, retentionHandler, goalHandler, payeeHandler, digestHandler, allocationTemplateHandler, transferHintHandler, budgetTemplateHandler, diagnosticsHandler, helpHandler, jobHandler, exportHandler, bankSyncHandler, widgetHandler, maintenanceHandler, loanHandler, transferSuggestionHandler)
+
+	// Create the first admin user from configuration
+	if cfg.Auth.AdminEmail != "" {
+		created, err := authService.EnsureAdminUser(ctx, cfg.Auth.AdminEmail, cfg.Auth.AdminPassword)
+		if err != nil {
+			log.Fatalf("Failed to create admin user: %v", err)
+		}
+		if created {
+			log.Printf("Created admin user %s", cfg.Auth.AdminEmail)
+		}
+	}
+
+	// Wrap the router with session and API token authentication
+	authMiddleware := http.NewAuthMiddleware(router, authService, apiTokenService, cfg.Auth.Required, cfg.Auth.AdminToken)
+	if cfg.Proxy.UserHeader != "" {
+		trustedProxies, err := http.ParseTrustedProxies(cfg.Proxy.TrustedProxies)
+		if err != nil {
+			log.Fatalf("Invalid PROXY_AUTH_TRUSTED_PROXIES: %v", err)
+		}
+		proxyAuthService := application.NewProxyAuthService(userRepo, userIdentityRepo, application.SSOMembership{
+			AutoProvision: cfg.Proxy.AutoProvision,
+			DefaultRole:   domain.UserRole(cfg.Proxy.DefaultRole),
+			AdminGroups:   cfg.Proxy.AdminGroups,
+			MemberGroups:  cfg.Proxy.MemberGroups,
+		})
+		authMiddleware.EnableProxyAuth(proxyAuthService, http.ProxyAuthConfig{
+			UserHeader:     cfg.Proxy.UserHeader,
+			EmailHeader:    cfg.Proxy.EmailHeader,
+			NameHeader:     cfg.Proxy.NameHeader,
+			GroupsHeader:   cfg.Proxy.GroupsHeader,
+			TrustedProxies: trustedProxies,
+		})
+		log.Printf("Accepting users authenticated by reverse proxies via %s", cfg.Proxy.UserHeader)
+	}
+	authMiddleware.EnableAudit(auditService)
+	if cfg.Auth.Required {
+		log.Println("API authentication is required")
+	}
+
+	// Warm the caches before the first request rather than during it (optional)
+	if cfg.Cache.PrimeOnStart {
+		primeCtx, cancelPrime := context.WithTimeout(context.Background(), 30*time.Second)
+		priming := cachePrimer.Prime(primeCtx)
+		cancelPrime()
+		for _, step := range priming.Steps {
+			if step.Error != "" {
+				log.Printf("Cache priming: %s failed after %dms: %s", step.Name, step.DurationMillis, step.Error)
+			}
+		}
+		log.Printf("Primed caches for %s in %dms", priming.Period, priming.DurationMillis)
+	}
 
 	// Create server
-	server := http.NewServer(fmt.Sprintf(":%s", cfg.Server.Port), router)
+	handler := http.LocalizeErrors(authMiddleware)
+	if cfg.Server.ValidateContract {
+		log.Println("Checking API requests and responses against the OpenAPI document")
+		handler = http.ValidateContract(router, handler)
+	}
+	server := http.NewServer(fmt.Sprintf(":%s", cfg.Server.Port), http.VersionedAPI(handler))
 
 	// Start server in a goroutine
 	go func() {
@@ -82,6 +358,55 @@ func main() {
 		}
 	}()
 
+	// Start the gRPC API on its own port (optional)
+	var grpcServer *grpc.Server
+	if cfg.Server.GRPCPort != "" {
+		grpcAuth := grpc.NewAuth(authMiddleware, apiTokenService, auditService, cfg.Auth.Required)
+		grpcServer = grpc.NewServer(fmt.Sprintf(":%s", cfg.Server.GRPCPort), grpcAuth, accountService, transactionService, allocationService)
+		go func() {
+			if err := grpcServer.Start(); err != nil {
+				log.Fatalf("gRPC server error: %v", err)
+			}
+		}()
+	}
+
+	// Background workers stop when the server shuts down
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	// Start the built-in Telegram bot (optional)
+	if cfg.Bot.TelegramToken != "" {
+		go telegram.NewBot(cfg.Bot.TelegramToken, botService, cfg.Bot.DailySummaryHour).Run(workerCtx)
+		log.Println("Telegram bot started")
+	}
+
+	// Start the MQTT metrics publisher (optional)
+	if metricsPublisher != nil {
+		go mqttService.Run(workerCtx, time.Duration(cfg.MQTT.IntervalSeconds)*time.Second)
+		log.Println("MQTT publisher started")
+	}
+
+	// Run background jobs queued by requests
+	go jobService.Run(workerCtx)
+
+	// Start periodic housekeeping jobs
+	scheduler := application.NewScheduler()
+	scheduler.Every("prune old data", time.Duration(cfg.Retention.IntervalHours)*time.Hour, retentionService.Prune)
+	if notificationService != nil {
+		scheduler.Every("send notification digests", time.Hour, notificationService.SendDigests)
+		scheduler.Every("send weekly budget digests", time.Hour, digestService.SendWeeklyDigests)
+	}
+	if cfg.BankSync.Key != "" && cfg.BankSync.IntervalHours > 0 {
+		scheduler.Every("sync bank aggregations", time.Duration(cfg.BankSync.IntervalHours)*time.Hour, bankSyncService.SyncAggregations)
+	}
+	if cfg.Maintenance.StartHour != cfg.Maintenance.EndHour {
+		scheduler.Every("maintain the database", time.Hour, maintenanceService.RunScheduled)
+	}
+	if cfg.TransferMatch.ScanIntervalHours > 0 {
+		scheduler.Every("scan for transfers", time.Duration(cfg.TransferMatch.ScanIntervalHours)*time.Hour, transferSuggestionService.ScanRecent)
+	}
+	go scheduler.Run(workerCtx)
+
 	// Wait for interrupt signal to gracefully shut down the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -93,6 +418,11 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	if grpcServer != nil {
+		if err := grpcServer.Shutdown(ctx); err != nil {
+			log.Printf("gRPC server forced to shutdown: %v", err)
+		}
+	}
 	if err := server.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
@@ -3,17 +3,45 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 )
 
 // Config holds the application configuration
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	CPI         CPIConfig
+	Email       EmailConfig
+	Bot         BotConfig
+	MQTT        MQTTConfig
+	Auth        AuthConfig
+	SMTP        SMTPConfig
+	OIDC        OIDCConfig
+	Proxy       ProxyAuthConfig
+	Features    FeatureConfig
+	Plugins     PluginConfig
+	Scripts     ScriptConfig
+	Retention   RetentionConfig
+	Maintenance MaintenanceConfig
+	Dates       DateConfig
+	Currency    CurrencyConfig
+	BankSync    BankSyncConfig
+
+	Recategorize  RecategorizeConfig
+	Cache         CacheConfig
+	TransferMatch TransferMatchConfig
 }
 
 // ServerConfig holds server-specific configuration
 type ServerConfig struct {
-	Port string
+	Port            string
+	GRPCPort        string // Port of the gRPC API; empty disables it
+	PublicURL       string // Base URL used in emailed links; defaults to http://localhost:<port>
+	Locale          string // Language of the default categories created on first run, e.g. de-DE
+	StarterTemplate string // Template applied on first run; "none" leaves the choice to the setup wizard
+
+	ValidateContract bool // Log requests and responses that don't match the OpenAPI document; for development and staging
 }
 
 // DatabaseConfig holds database-specific configuration
@@ -21,15 +49,288 @@ type DatabaseConfig struct {
 	Path string
 }
 
+// CPIConfig holds configuration for the optional CPI provider
+type CPIConfig struct {
+	Provider  string // "bls" or empty to disable fetching
+	BLSAPIKey string
+}
+
+// EmailConfig holds configuration for the inbound email import webhook
+type EmailConfig struct {
+	WebhookSecret string // Empty disables the webhook
+}
+
+// BotConfig holds configuration for the built-in chat bots
+type BotConfig struct {
+	TelegramToken    string // Empty disables the built-in Telegram bot
+	DailySummaryHour int    // Local hour (0-23) at which daily summaries are sent
+}
+
+// MQTTConfig holds configuration for the optional MQTT metrics publisher
+type MQTTConfig struct {
+	Broker          string // e.g. tcp://localhost:1883; empty disables publishing
+	ClientID        string
+	Username        string
+	Password        string
+	TopicPrefix     string
+	DiscoveryPrefix string // Home Assistant discovery prefix; empty disables discovery
+	IntervalSeconds int    // How often to check for changed values
+}
+
+// AuthConfig holds API authentication configuration
+type AuthConfig struct {
+	Required      bool   // Reject /api requests that don't carry a valid credential
+	AdminToken    string // Static admin token, used to create the first API tokens
+	AdminEmail    string // Creates the first admin user when no users exist
+	AdminPassword string
+	SecureCookies bool   // Mark session cookies Secure (when served over HTTPS)
+	TokenKey      string // Keys the HMAC of emailed link tokens; empty keeps a generated key in the database
+
+	TokenDailyWriteQuota int // Writes an API token may make per UTC day unless it has its own quota; 0 = unlimited
+}
+
+// SMTPConfig holds configuration for the SMTP notification channel
+type SMTPConfig struct {
+	Host     string // Empty disables email
+	Port     int
+	Username string // Empty skips authentication
+	Password string
+	From     string
+}
+
+// OIDCConfig holds configuration for single sign-on through an OpenID Connect provider
+type OIDCConfig struct {
+	Issuer        string // e.g. https://auth.example.com; empty disables single sign-on
+	ClientID      string
+	ClientSecret  string
+	RedirectURL   string // Defaults to <PUBLIC_URL>/api/auth/oidc/callback
+	ProviderName  string // Shown on the sign-in button
+	Scopes        []string
+	GroupsClaim   string   // ID token claim holding the user's groups, e.g. "groups"
+	AdminGroups   []string // Groups that grant the admin role
+	MemberGroups  []string // Groups that grant membership; when set, other users are refused
+	DefaultRole   string   // Role for new users when no groups are configured
+	AutoProvision bool     // Create users on their first sign-in
+}
+
+// ProxyAuthConfig holds configuration for trusting users authenticated by a reverse proxy
+type ProxyAuthConfig struct {
+	UserHeader     string   // e.g. Remote-User; empty disables proxy authentication
+	EmailHeader    string   // Optional header with the user's email
+	NameHeader     string   // Optional header with the user's display name
+	GroupsHeader   string   // Optional header with comma-separated groups
+	TrustedProxies []string // CIDRs or IPs of proxies allowed to set the headers
+	AdminGroups    []string
+	MemberGroups   []string
+	DefaultRole    string
+	AutoProvision  bool
+}
+
+// FeatureConfig holds the default state of feature flags
+type FeatureConfig struct {
+	Enabled []string // Flags on by default; admins can override them at runtime
+}
+
+// PluginConfig holds the external plugins to load
+type PluginConfig struct {
+	Endpoints      []string // name=url pairs, e.g. mybank=http://localhost:9100
+	TimeoutSeconds int      // How long to wait for a plugin to answer
+}
+
+// ScriptConfig holds limits for user scripts
+type ScriptConfig struct {
+	TimeoutMillis int // How long a single script call may run
+}
+
+// RecategorizeConfig paces the background job that applies changed payee rules
+type RecategorizeConfig struct {
+	BatchSize        int // Transactions categorized per batch
+	BatchDelayMillis int // Pause between batches
+}
+
+// CacheConfig controls the in-memory caches
+type CacheConfig struct {
+	PrimeOnStart bool // Warm the caches behind the dashboard before serving requests
+}
+
+// TransferMatchConfig sets how the two sides of a transfer are matched, on import and by the
+// scan for transfers entered as an outflow and an inflow
+type TransferMatchConfig struct {
+	Days              int   // How many days apart the two sides may be dated
+	ToleranceBps      int64 // How far their amounts may differ, in basis points of the amount
+	FeeTolerance      int64 // How far their amounts may differ in cents, e.g. a wire fee; the larger tolerance applies
+	ScanIntervalHours int   // How often the scan runs on its own; 0 only scans on request
+	ScanDays          int   // How many days back the scheduled scan looks
+}
+
+// RetentionConfig sets how long derived data is kept before the cleanup job removes it
+// 0 keeps that kind of data forever.
+type RetentionConfig struct {
+	AuditDays           int // Audit log entries
+	RejectedPendingDays int // Rejected pending transactions
+	ExpiredTokenDays    int // Expired sessions and emailed tokens
+	IntervalHours       int // How often the cleanup job runs
+}
+
+// MaintenanceConfig sets the quiet window the database is optimized in each day
+// A window starting and ending at the same hour turns scheduled maintenance off.
+type MaintenanceConfig struct {
+	StartHour int  // Local hour (0-23) the window opens
+	EndHour   int  // Local hour it closes; earlier than StartHour for a window across midnight
+	Vacuum    bool // Also vacuum, returning freed space to the disk; blocks writes while it runs
+}
+
+// DateConfig bounds the dates transactions can be saved with
+// 0 leaves that side unlimited.
+type DateConfig struct {
+	MaxPastYears  int    // Transactions older than this are rejected
+	MaxFutureDays int    // Transactions further ahead than this are rejected
+	Timezone      string // IANA name of the timezone calendar days are compared in when matching transactions, e.g. America/New_York
+}
+
+// CurrencyConfig sets the currency the budget's amounts are kept in
+// Amounts are stored in its minor units, so it should be chosen before any are entered.
+type CurrencyConfig struct {
+	Code     string // ISO 4217 code, e.g. USD, JPY or KWD
+	Decimals int    // Digits after the decimal point; -1 uses the currency's standard number
+}
+
+// BankSyncConfig holds configuration for downloading statements from banks over OFX DirectConnect
+// and syncing accounts through bank aggregators
+type BankSyncConfig struct {
+	Key            string // Secret the stored bank credentials are encrypted with; empty disables bank sync
+	TimeoutSeconds int    // How long to wait for a bank or aggregator to answer
+	IntervalHours  int    // How often bank aggregations sync on their own; 0 only syncs on request
+}
+
 // Load loads configuration from environment variables with defaults
 func Load() *Config {
+	port := getEnv("PORT", "8080")
+	publicURL := getEnv("PUBLIC_URL", "http://localhost:"+port)
+
 	return &Config{
 		Server: ServerConfig{
-			Port: getEnv("PORT", "8080"),
+			Port:            port,
+			GRPCPort:        getEnv("GRPC_PORT", ""),
+			PublicURL:       publicURL,
+			Locale:          getEnv("DEFAULT_LOCALE", "en"),
+			StarterTemplate: getEnv("STARTER_TEMPLATE", "none"),
+
+			ValidateContract: getEnvBool("API_VALIDATE_CONTRACT", false),
 		},
 		Database: DatabaseConfig{
 			Path: getEnv("DB_PATH", "budget.db"),
 		},
+		CPI: CPIConfig{
+			Provider:  getEnv("CPI_PROVIDER", ""),
+			BLSAPIKey: getEnv("BLS_API_KEY", ""),
+		},
+		Email: EmailConfig{
+			WebhookSecret: getEnv("EMAIL_WEBHOOK_SECRET", ""),
+		},
+		Bot: BotConfig{
+			TelegramToken:    getEnv("TELEGRAM_BOT_TOKEN", ""),
+			DailySummaryHour: getEnvInt("BOT_DAILY_SUMMARY_HOUR", 20),
+		},
+		MQTT: MQTTConfig{
+			Broker:          getEnv("MQTT_BROKER", ""),
+			ClientID:        getEnv("MQTT_CLIENT_ID", "budget"),
+			Username:        getEnv("MQTT_USERNAME", ""),
+			Password:        getEnv("MQTT_PASSWORD", ""),
+			TopicPrefix:     getEnv("MQTT_TOPIC_PREFIX", "budget"),
+			DiscoveryPrefix: getEnv("MQTT_DISCOVERY_PREFIX", "homeassistant"),
+			IntervalSeconds: getEnvInt("MQTT_PUBLISH_INTERVAL", 30),
+		},
+		Auth: AuthConfig{
+			Required:      getEnvBool("AUTH_REQUIRED", false),
+			AdminToken:    getEnv("API_ADMIN_TOKEN", ""),
+			AdminEmail:    getEnv("ADMIN_EMAIL", ""),
+			AdminPassword: getEnv("ADMIN_PASSWORD", ""),
+			SecureCookies: getEnvBool("SECURE_COOKIES", false),
+			TokenKey:      getEnv("AUTH_TOKEN_KEY", ""),
+
+			TokenDailyWriteQuota: getEnvInt("API_TOKEN_DAILY_WRITE_QUOTA", 1000),
+		},
+		SMTP: SMTPConfig{
+			Host:     getEnv("SMTP_HOST", ""),
+			Port:     getEnvInt("SMTP_PORT", 587),
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("SMTP_FROM", ""),
+		},
+		OIDC: OIDCConfig{
+			Issuer:        getEnv("OIDC_ISSUER", ""),
+			ClientID:      getEnv("OIDC_CLIENT_ID", ""),
+			ClientSecret:  getEnv("OIDC_CLIENT_SECRET", ""),
+			RedirectURL:   getEnv("OIDC_REDIRECT_URL", strings.TrimRight(publicURL, "/")+"/api/auth/oidc/callback"),
+			ProviderName:  getEnv("OIDC_PROVIDER_NAME", "SSO"),
+			Scopes:        getEnvList("OIDC_SCOPES", []string{"openid", "email", "profile"}),
+			GroupsClaim:   getEnv("OIDC_GROUPS_CLAIM", ""),
+			AdminGroups:   getEnvList("OIDC_ADMIN_GROUPS", nil),
+			MemberGroups:  getEnvList("OIDC_MEMBER_GROUPS", nil),
+			DefaultRole:   getEnv("OIDC_DEFAULT_ROLE", "member"),
+			AutoProvision: getEnvBool("OIDC_AUTO_PROVISION", true),
+		},
+		Proxy: ProxyAuthConfig{
+			UserHeader:     getEnv("PROXY_AUTH_USER_HEADER", ""),
+			EmailHeader:    getEnv("PROXY_AUTH_EMAIL_HEADER", "Remote-Email"),
+			NameHeader:     getEnv("PROXY_AUTH_NAME_HEADER", "Remote-Name"),
+			GroupsHeader:   getEnv("PROXY_AUTH_GROUPS_HEADER", "Remote-Groups"),
+			TrustedProxies: getEnvList("PROXY_AUTH_TRUSTED_PROXIES", nil),
+			AdminGroups:    getEnvList("PROXY_AUTH_ADMIN_GROUPS", nil),
+			MemberGroups:   getEnvList("PROXY_AUTH_MEMBER_GROUPS", nil),
+			DefaultRole:    getEnv("PROXY_AUTH_DEFAULT_ROLE", "member"),
+			AutoProvision:  getEnvBool("PROXY_AUTH_AUTO_PROVISION", true),
+		},
+		Features: FeatureConfig{
+			Enabled: getEnvList("FEATURE_FLAGS", nil),
+		},
+		Plugins: PluginConfig{
+			Endpoints:      getEnvList("PLUGINS", nil),
+			TimeoutSeconds: getEnvInt("PLUGIN_TIMEOUT", 10),
+		},
+		Scripts: ScriptConfig{
+			TimeoutMillis: getEnvInt("SCRIPT_TIMEOUT_MS", 100),
+		},
+		Retention: RetentionConfig{
+			AuditDays:           getEnvInt("AUDIT_RETENTION_DAYS", 365),
+			RejectedPendingDays: getEnvInt("REJECTED_PENDING_RETENTION_DAYS", 30),
+			ExpiredTokenDays:    getEnvInt("EXPIRED_TOKEN_RETENTION_DAYS", 7),
+			IntervalHours:       getEnvInt("RETENTION_INTERVAL_HOURS", 24),
+		},
+		Maintenance: MaintenanceConfig{
+			StartHour: getEnvInt("MAINTENANCE_START_HOUR", 3),
+			EndHour:   getEnvInt("MAINTENANCE_END_HOUR", 5),
+			Vacuum:    getEnvBool("MAINTENANCE_VACUUM", false),
+		},
+		Dates: DateConfig{
+			MaxPastYears:  getEnvInt("TRANSACTION_MAX_PAST_YEARS", 30),
+			MaxFutureDays: getEnvInt("TRANSACTION_MAX_FUTURE_DAYS", 366),
+			Timezone:      getEnv("BUDGET_TIMEZONE", "UTC"),
+		},
+		Currency: CurrencyConfig{
+			Code:     getEnv("BUDGET_CURRENCY", "USD"),
+			Decimals: getEnvInt("BUDGET_CURRENCY_DECIMALS", -1),
+		},
+		BankSync: BankSyncConfig{
+			Key:            getEnv("BANK_SYNC_KEY", ""),
+			TimeoutSeconds: getEnvInt("BANK_SYNC_TIMEOUT", 60),
+			IntervalHours:  getEnvInt("BANK_SYNC_INTERVAL_HOURS", 6),
+		},
+		Recategorize: RecategorizeConfig{
+			BatchSize:        getEnvInt("RECATEGORIZE_BATCH_SIZE", 100),
+			BatchDelayMillis: getEnvInt("RECATEGORIZE_BATCH_DELAY_MS", 500),
+		},
+		Cache: CacheConfig{
+			PrimeOnStart: getEnvBool("CACHE_PRIME_ON_START", false),
+		},
+		TransferMatch: TransferMatchConfig{
+			Days:              getEnvInt("TRANSFER_MATCH_DAYS", 4),
+			ToleranceBps:      int64(getEnvInt("TRANSFER_MATCH_TOLERANCE_BPS", 0)),
+			FeeTolerance:      int64(getEnvInt("TRANSFER_MATCH_FEE_TOLERANCE", 0)),
+			ScanIntervalHours: getEnvInt("TRANSFER_SCAN_INTERVAL_HOURS", 24),
+			ScanDays:          getEnvInt("TRANSFER_SCAN_DAYS", 30),
+		},
 	}
 }
 
@@ -41,13 +342,134 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvInt gets an integer environment variable or returns a default value
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if i, err := strconv.Atoi(value); err == nil {
+			return i
+		}
+	}
+	return defaultValue
+}
+
+// getEnvBool gets a boolean environment variable or returns a default value
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList gets a comma-separated environment variable or returns a default value
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
 // Validate validates the configuration
 func (c *Config) Validate() error {
 	if c.Server.Port == "" {
 		return fmt.Errorf("server port is required")
 	}
+	if c.Server.GRPCPort != "" && c.Server.GRPCPort == c.Server.Port {
+		return fmt.Errorf("GRPC_PORT must differ from PORT")
+	}
 	if c.Database.Path == "" {
 		return fmt.Errorf("database path is required")
 	}
+	if c.CPI.Provider != "" && c.CPI.Provider != "bls" {
+		return fmt.Errorf("unsupported CPI provider: %s", c.CPI.Provider)
+	}
+	if c.Bot.DailySummaryHour < 0 || c.Bot.DailySummaryHour > 23 {
+		return fmt.Errorf("bot daily summary hour must be between 0 and 23")
+	}
+	if c.MQTT.Broker != "" && c.MQTT.IntervalSeconds < 1 {
+		return fmt.Errorf("MQTT publish interval must be at least 1 second")
+	}
+	if c.Auth.TokenDailyWriteQuota < 0 {
+		return fmt.Errorf("API token daily write quota cannot be negative")
+	}
+	if (c.Auth.AdminEmail == "") != (c.Auth.AdminPassword == "") {
+		return fmt.Errorf("ADMIN_EMAIL and ADMIN_PASSWORD must be set together")
+	}
+	if c.OIDC.Issuer != "" && c.OIDC.ClientID == "" {
+		return fmt.Errorf("OIDC_CLIENT_ID is required when OIDC_ISSUER is set")
+	}
+	if c.OIDC.DefaultRole != "admin" && c.OIDC.DefaultRole != "member" {
+		return fmt.Errorf("OIDC default role must be admin or member")
+	}
+	if (len(c.OIDC.AdminGroups) > 0 || len(c.OIDC.MemberGroups) > 0) && c.OIDC.GroupsClaim == "" {
+		return fmt.Errorf("OIDC_GROUPS_CLAIM is required when OIDC admin or member groups are set")
+	}
+	if c.Proxy.UserHeader != "" && len(c.Proxy.TrustedProxies) == 0 {
+		return fmt.Errorf("PROXY_AUTH_TRUSTED_PROXIES is required when PROXY_AUTH_USER_HEADER is set")
+	}
+	if c.Proxy.DefaultRole != "admin" && c.Proxy.DefaultRole != "member" {
+		return fmt.Errorf("proxy auth default role must be admin or member")
+	}
+	if c.SMTP.Host != "" && c.SMTP.From == "" {
+		return fmt.Errorf("SMTP_FROM is required when SMTP_HOST is set")
+	}
+	for _, endpoint := range c.Plugins.Endpoints {
+		if name, url, ok := strings.Cut(endpoint, "="); !ok || name == "" || url == "" {
+			return fmt.Errorf("PLUGINS entries must look like name=url, got %q", endpoint)
+		}
+	}
+	if len(c.Plugins.Endpoints) > 0 && c.Plugins.TimeoutSeconds < 1 {
+		return fmt.Errorf("plugin timeout must be at least 1 second")
+	}
+	if c.Scripts.TimeoutMillis < 1 {
+		return fmt.Errorf("script timeout must be at least 1 millisecond")
+	}
+	if c.Retention.AuditDays < 0 || c.Retention.RejectedPendingDays < 0 || c.Retention.ExpiredTokenDays < 0 {
+		return fmt.Errorf("retention days cannot be negative")
+	}
+	if c.Retention.IntervalHours < 1 {
+		return fmt.Errorf("retention interval must be at least 1 hour")
+	}
+	if c.Maintenance.StartHour < 0 || c.Maintenance.StartHour > 23 || c.Maintenance.EndHour < 0 || c.Maintenance.EndHour > 23 {
+		return fmt.Errorf("maintenance window hours must be between 0 and 23")
+	}
+	if c.Dates.MaxPastYears < 0 || c.Dates.MaxFutureDays < 0 {
+		return fmt.Errorf("transaction date bounds cannot be negative")
+	}
+	if c.BankSync.Key != "" && len(c.BankSync.Key) < 16 {
+		return fmt.Errorf("BANK_SYNC_KEY must be at least 16 characters")
+	}
+	if c.BankSync.TimeoutSeconds < 1 {
+		return fmt.Errorf("bank sync timeout must be at least 1 second")
+	}
+	if c.BankSync.IntervalHours < 0 {
+		return fmt.Errorf("bank sync interval cannot be negative")
+	}
+	if c.Recategorize.BatchSize < 1 {
+		return fmt.Errorf("recategorize batch size must be at least 1")
+	}
+	if c.Recategorize.BatchDelayMillis < 0 {
+		return fmt.Errorf("recategorize batch delay cannot be negative")
+	}
+	if c.TransferMatch.Days < 1 {
+		return fmt.Errorf("transfer match window must be at least 1 day")
+	}
+	if c.TransferMatch.ToleranceBps < 0 || c.TransferMatch.FeeTolerance < 0 {
+		return fmt.Errorf("transfer amount tolerance cannot be negative")
+	}
+	if c.TransferMatch.ScanIntervalHours < 0 {
+		return fmt.Errorf("transfer scan interval cannot be negative")
+	}
+	if c.TransferMatch.ScanDays < 1 {
+		return fmt.Errorf("transfer scan must look back at least 1 day")
+	}
 	return nil
 }
@@ -3,12 +3,28 @@ module github.com/billybbuffum/budget
 go 1.23
 
 require (
+	github.com/aclindsa/ofxgo v0.1.3
+	github.com/coreos/go-oidc/v3 v3.11.0
+	github.com/eclipse/paho.mqtt.golang v1.5.0
 	github.com/google/uuid v1.6.0
 	github.com/mattn/go-sqlite3 v1.14.32
-	github.com/aclindsa/ofxgo v0.1.3
+	github.com/spf13/cobra v1.8.1
+	go.starlark.net v0.0.0-20250417143717-f57e51f710eb
+	golang.org/x/crypto v0.31.0
+	golang.org/x/oauth2 v0.24.0
+	golang.org/x/text v0.21.0
+	google.golang.org/grpc v1.67.3
+	google.golang.org/protobuf v1.34.2
 )
 
 require (
 	github.com/aclindsa/xml v0.0.0-20201125035057-bbd5c9ec99ac // indirect
-	golang.org/x/text v0.3.7 // indirect
+	github.com/go-jose/go-jose/v4 v4.0.2 // indirect
+	github.com/gorilla/websocket v1.5.3 // indirect
+	github.com/inconshreveable/mousetrap v1.1.0 // indirect
+	github.com/spf13/pflag v1.0.5 // indirect
+	golang.org/x/net v0.28.0 // indirect
+	golang.org/x/sync v0.10.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240814211410-ddb44dafa142 // indirect
 )
@@ -0,0 +1,254 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// AccountConversion is what converting an account to another type changed
+type AccountConversion struct {
+	Account                *domain.Account    `json:"account"`
+	From                   domain.AccountType `json:"from"`
+	To                     domain.AccountType `json:"to"`
+	PaymentCategoryCreated *domain.Category   `json:"payment_category_created,omitempty"` // When the account became a credit card
+	PaymentCategoryDeleted *domain.Category   `json:"payment_category_deleted,omitempty"` // When it stopped being one
+	AllocationsMoved       int                `json:"allocations_moved"`                  // Payment category allocations moved to MoveAllocationsTo
+	AllocationsReleased    int                `json:"allocations_released"`               // Payment category allocations deleted with it
+	PaymentsUncategorized  int                `json:"payments_uncategorized"`             // Transactions categorized to the payment category, kept without a category
+	ReadyToAssignBefore    *int64             `json:"ready_to_assign_before,omitempty"`   // This month's, when allocations are available
+	ReadyToAssignAfter     *int64             `json:"ready_to_assign_after,omitempty"`
+}
+
+// AccountConversionOptions says what to do with the budget behind a converted account
+type AccountConversionOptions struct {
+	MoveAllocationsTo string // Category to move a credit card payment category's allocations to; empty deletes them
+}
+
+// UseAllocationService lets account type conversions move payment category allocations
+// and report the change to Ready to Assign
+func (s *AccountService) UseAllocationService(allocationService *AllocationService) {
+	s.allocationService = allocationService
+}
+
+// ConvertAccountType changes an account's type, e.g. a checking account mistakenly created
+// as a credit card, and brings the budget in line with it
+// Becoming a credit card creates the payment category CreateAccount would have. Ceasing to be
+// one deletes it: its allocations are moved to opts.MoveAllocationsTo or deleted, and the card
+// payments categorized to it are kept without a category. An opening debt a credit card or
+// loan keeps on its balance becomes a starting balance transaction and back, so the balance
+// is unchanged.
+// Everything is written atomically; spending on the account counts as cash or credit
+// spending by the new type from then on, in every month.
+func (s *AccountService) ConvertAccountType(ctx context.Context, id string, accountType domain.AccountType, opts AccountConversionOptions) (*AccountConversion, error) {
+	if !validAccountType(accountType) {
+		return nil, fmt.Errorf("invalid account type")
+	}
+	account, err := s.accountRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, domain.ErrAccountNotFound
+	}
+	if account.Type == accountType {
+		return nil, fmt.Errorf("account is already a %s account", accountType)
+	}
+	if opts.MoveAllocationsTo != "" {
+		if account.Type != domain.AccountTypeCredit {
+			return nil, fmt.Errorf("only a credit card's payment category has allocations to move")
+		}
+		if s.allocationService == nil {
+			return nil, fmt.Errorf("moving allocations is not available")
+		}
+		target, err := s.categoryRepo.GetByID(ctx, opts.MoveAllocationsTo)
+		if err != nil {
+			return nil, fmt.Errorf("category to move allocations to not found")
+		}
+		if target.PaymentForAccountID != nil {
+			return nil, fmt.Errorf("allocations can't be moved to another payment category")
+		}
+	}
+
+	conversion := &AccountConversion{Account: account, From: account.Type, To: accountType}
+	period := time.Now().Format("2006-01")
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if s.allocationService != nil {
+			// Calculated uncached: the amount inside the transaction mustn't outlive it
+			before, err := s.allocationService.calculateReadyToAssign(ctx, period)
+			if err != nil {
+				return fmt.Errorf("failed to calculate ready to assign: %w", err)
+			}
+			conversion.ReadyToAssignBefore = &before
+		}
+
+		if err := s.convertAccountType(ctx, account, accountType, opts, conversion); err != nil {
+			return err
+		}
+		account.UpdatedAt = time.Now()
+		if err := s.accountRepo.Update(ctx, account); err != nil {
+			return err
+		}
+
+		if s.allocationService != nil {
+			after, err := s.allocationService.calculateReadyToAssign(ctx, period)
+			if err != nil {
+				return fmt.Errorf("failed to calculate ready to assign: %w", err)
+			}
+			conversion.ReadyToAssignAfter = &after
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return conversion, nil
+}
+
+// convertAccountType changes account's type to accountType and moves its payment category,
+// allocations and starting balance along, noting what it did in conversion
+// It must run in a unit of work; the caller saves the account.
+func (s *AccountService) convertAccountType(ctx context.Context, account *domain.Account, accountType domain.AccountType, opts AccountConversionOptions, conversion *AccountConversion) error {
+	wasCredit := account.Type == domain.AccountTypeCredit
+	isCredit := accountType == domain.AccountTypeCredit
+	wasDebt := account.Type.IsDebt()
+	isDebt := accountType.IsDebt()
+	// A tracking account's history is balance updates, and a budget account's is income and
+	// spending: neither makes sense as the other
+	if account.Type.IsOffBudget() != accountType.IsOffBudget() {
+		return fmt.Errorf("accounts can't be converted between budget and tracking accounts")
+	}
+	account.Type = accountType
+	if wasCredit == isCredit && wasDebt == isDebt {
+		return nil
+	}
+
+	transactions, err := s.transactionRepo.ListByAccount(ctx, account.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list account transactions: %w", err)
+	}
+	starting := startingBalanceTransaction(transactions)
+
+	switch {
+	case isDebt && !wasDebt:
+		// An opening debt isn't a transaction on a credit card or loan, just part of its balance
+		if starting != nil && starting.Amount < 0 {
+			if err := s.transactionRepo.Delete(ctx, starting.ID); err != nil {
+				return fmt.Errorf("failed to delete starting balance transaction: %w", err)
+			}
+		}
+	case wasDebt && !isDebt && starting == nil:
+		// The opening debt becomes the starting balance transaction other accounts have
+		opening := account.Balance
+		for _, transaction := range transactions {
+			opening -= transaction.Amount
+		}
+		if opening != 0 {
+			if err := s.transactionRepo.Create(ctx, newStartingBalanceTransaction(account.ID, opening, account.CreatedAt)); err != nil {
+				return fmt.Errorf("failed to create starting balance transaction: %w", err)
+			}
+		}
+	}
+
+	if isCredit {
+		// A payment category left behind by an earlier conversion is reused
+		if _, err := s.categoryRepo.GetPaymentCategoryByAccountID(ctx, account.ID); err == nil {
+			return nil
+		}
+		paymentCategory, err := s.createPaymentCategory(ctx, account)
+		if err != nil {
+			return err
+		}
+		conversion.PaymentCategoryCreated = paymentCategory
+		return nil
+	}
+	if !wasCredit {
+		return nil
+	}
+
+	paymentCategory, err := s.categoryRepo.GetPaymentCategoryByAccountID(ctx, account.ID)
+	if err != nil {
+		return nil
+	}
+
+	// Transactions are deleted with their category, so the payments are uncategorized first
+	payments, err := s.transactionRepo.ListByCategory(ctx, paymentCategory.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list payment category transactions: %w", err)
+	}
+	if len(payments) > 0 {
+		ids := make([]string, len(payments))
+		for i, payment := range payments {
+			ids[i] = payment.ID
+		}
+		if err := s.transactionRepo.BulkUpdateCategory(ctx, ids, nil); err != nil {
+			return fmt.Errorf("failed to uncategorize payments: %w", err)
+		}
+		conversion.PaymentsUncategorized = len(payments)
+	}
+
+	if opts.MoveAllocationsTo != "" {
+		moved, err := s.moveAllocations(ctx, paymentCategory.ID, opts.MoveAllocationsTo)
+		if err != nil {
+			return err
+		}
+		conversion.AllocationsMoved = moved
+	} else if s.allocationService != nil {
+		allocations, err := s.allocationService.allocationRepo.List(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list allocations: %w", err)
+		}
+		for _, allocation := range allocations {
+			if allocation.CategoryID == paymentCategory.ID {
+				conversion.AllocationsReleased++
+			}
+		}
+	}
+
+	// Its remaining allocations are deleted with it
+	if err := s.categoryRepo.Delete(ctx, paymentCategory.ID); err != nil {
+		return fmt.Errorf("failed to delete payment category: %w", err)
+	}
+	conversion.PaymentCategoryDeleted = paymentCategory
+
+	if err := s.categoryGroupService.DeleteCreditCardPaymentsGroupIfEmpty(ctx); err != nil {
+		return fmt.Errorf("failed to clean up credit card payments group: %w", err)
+	}
+	return nil
+}
+
+// moveAllocations moves every allocation of category from to category to, adding each to
+// to's allocation for the same month when it has one, and returns how many it moved
+func (s *AccountService) moveAllocations(ctx context.Context, from, to string) (int, error) {
+	allocationRepo := s.allocationService.allocationRepo
+	allocations, err := allocationRepo.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list allocations: %w", err)
+	}
+
+	moved := 0
+	for _, allocation := range allocations {
+		if allocation.CategoryID != from {
+			continue
+		}
+		now := time.Now()
+		existing, err := allocationRepo.GetByCategoryAndPeriod(ctx, to, allocation.Period)
+		if err == nil && existing != nil {
+			existing.Amount += allocation.Amount
+			existing.UpdatedAt = now
+			if err := allocationRepo.Update(ctx, existing); err != nil {
+				return 0, err
+			}
+			if err := allocationRepo.Delete(ctx, allocation.ID); err != nil {
+				return 0, err
+			}
+		} else {
+			allocation.CategoryID = to
+			allocation.UpdatedAt = now
+			if err := allocationRepo.Update(ctx, allocation); err != nil {
+				return 0, err
+			}
+		}
+		moved++
+	}
+	return moved, nil
+}
@@ -3,6 +3,7 @@ package application
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/billybbuffum/budget/internal/domain"
@@ -16,30 +17,41 @@ type AccountService struct {
 	budgetStateRepo      domain.BudgetStateRepository
 	transactionRepo      domain.TransactionRepository
 	categoryGroupService *CategoryGroupService
+	allocationService    *AllocationService // Optional; see UseAllocationService
+	uow                  domain.UnitOfWork
 }
 
 // NewAccountService creates a new account service
-func NewAccountService(accountRepo domain.AccountRepository, categoryRepo domain.CategoryRepository, budgetStateRepo domain.BudgetStateRepository, transactionRepo domain.TransactionRepository, categoryGroupService *CategoryGroupService) *AccountService {
+func NewAccountService(accountRepo domain.AccountRepository, categoryRepo domain.CategoryRepository, budgetStateRepo domain.BudgetStateRepository, transactionRepo domain.TransactionRepository, categoryGroupService *CategoryGroupService, uow domain.UnitOfWork) *AccountService {
 	return &AccountService{
 		accountRepo:          accountRepo,
 		categoryRepo:         categoryRepo,
 		budgetStateRepo:      budgetStateRepo,
 		transactionRepo:      transactionRepo,
 		categoryGroupService: categoryGroupService,
+		uow:                  uow,
 	}
 }
 
+// maxInterestRateBps caps an account's interest rate at 100% APR
+const maxInterestRateBps = 10000
+
+// AccountDetailsInput is the reference information supplied when saving an account
+type AccountDetailsInput struct {
+	Notes           string `json:"notes"`
+	AccountNumber   string `json:"account_number"`    // The full number or just its last four digits; only the last four are kept
+	InterestRateBps *int64 `json:"interest_rate_bps"` // APR in basis points, e.g. 1999 for 19.99%; nil when unknown
+}
+
 // CreateAccount creates a new account
-// For credit card accounts, automatically creates a payment category
-func (s *AccountService) CreateAccount(ctx context.Context, name string, balance int64, accountType domain.AccountType) (*domain.Account, error) {
+// For credit card accounts, automatically creates a payment category. The account, its
+// payment category and starting balance transaction are created atomically. details may be nil.
+func (s *AccountService) CreateAccount(ctx context.Context, name string, balance int64, accountType domain.AccountType, details *AccountDetailsInput) (*domain.Account, error) {
 	if name == "" {
 		return nil, fmt.Errorf("account name is required")
 	}
 
-	if accountType != domain.AccountTypeChecking &&
-	   accountType != domain.AccountTypeSavings &&
-	   accountType != domain.AccountTypeCash &&
-	   accountType != domain.AccountTypeCredit {
+	if !validAccountType(accountType) {
 		return nil, fmt.Errorf("invalid account type")
 	}
 
@@ -51,78 +63,47 @@ func (s *AccountService) CreateAccount(ctx context.Context, name string, balance
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
-
-	if err := s.accountRepo.Create(ctx, account); err != nil {
-		return nil, err
-	}
-
-	// For credit cards, create a payment category and assign it to the CC payments group
-	if accountType == domain.AccountTypeCredit {
-		// Ensure the credit card payments group exists
-		group, err := s.categoryGroupService.EnsureCreditCardPaymentsGroup(ctx)
+	if details != nil {
+		accountDetails, err := normalizeAccountDetails(details)
 		if err != nil {
-			// Rollback account creation if group creation fails
-			s.accountRepo.Delete(ctx, account.ID)
-			return nil, fmt.Errorf("failed to ensure credit card payments group: %w", err)
+			return nil, err
 		}
+		account.Details = accountDetails
+	}
 
-		paymentCategory := &domain.Category{
-			ID:                  uuid.New().String(),
-			Name:                name + " Payment",
-			Description:         "Payment category for " + name,
-			Color:               "#FF6B6B", // Red-ish color for credit card payments
-			GroupID:             &group.ID,
-			PaymentForAccountID: &account.ID,
-			CreatedAt:           time.Now(),
-			UpdatedAt:           time.Now(),
-		}
-		if err := s.categoryRepo.Create(ctx, paymentCategory); err != nil {
-			// Rollback account creation if payment category fails
-			s.accountRepo.Delete(ctx, account.ID)
-			return nil, fmt.Errorf("failed to create payment category: %w", err)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.accountRepo.Create(ctx, account); err != nil {
+			return err
 		}
 
-		// For credit cards with negative balance (existing debt), no income transaction is created
-		// because debt is not income. For positive balance (overpayment credit), create an income transaction.
-		if balance > 0 {
-			transaction := &domain.Transaction{
-				ID:          uuid.New().String(),
-				AccountID:   account.ID,
-				Amount:      balance,
-				Description: "Starting balance",
-				Date:        time.Now(),
-				Type:        "normal",
-				CreatedAt:   time.Now(),
-				UpdatedAt:   time.Now(),
-			}
-			if err := s.transactionRepo.Create(ctx, transaction); err != nil {
-				s.categoryRepo.Delete(ctx, paymentCategory.ID)
-				s.accountRepo.Delete(ctx, account.ID)
-				return nil, fmt.Errorf("failed to create starting balance transaction: %w", err)
+		// For credit cards, create a payment category and assign it to the CC payments group
+		if accountType == domain.AccountTypeCredit {
+			if _, err := s.createPaymentCategory(ctx, account); err != nil {
+				return err
 			}
 		}
-	} else {
-		// For non-credit accounts, create an income transaction for the starting balance
-		// This ensures the balance shows up in Ready to Assign (Total Inflows - Allocated)
-		if balance != 0 {
-			transaction := &domain.Transaction{
-				ID:          uuid.New().String(),
-				AccountID:   account.ID,
-				Amount:      balance,
-				Description: "Starting balance",
-				Date:        time.Now(),
-				Type:        "normal",
-				CreatedAt:   time.Now(),
-				UpdatedAt:   time.Now(),
+
+		// The starting balance transaction puts the balance in Ready to Assign (Total Inflows - Allocated).
+		// A credit card or loan's opening debt isn't income, so it's kept on the balance without
+		// one; an opening credit on it, like an overpaid card, still gets one. A tracking
+		// account is off budget: its opening balance is only a balance update.
+		switch {
+		case accountType.IsOffBudget():
+			if balance != 0 {
+				if err := s.transactionRepo.Create(ctx, newBalanceUpdateTransaction(account.ID, balance, startingBalanceDescription, "", time.Now())); err != nil {
+					return fmt.Errorf("failed to create starting balance transaction: %w", err)
+				}
 			}
-			if err := s.transactionRepo.Create(ctx, transaction); err != nil {
-				// Rollback account creation if transaction creation fails
-				s.accountRepo.Delete(ctx, account.ID)
-				return nil, fmt.Errorf("failed to create starting balance transaction: %w", err)
+		case balance > 0 || (balance < 0 && !accountType.IsDebt()):
+			if err := s.transactionRepo.Create(ctx, newStartingBalanceTransaction(account.ID, balance, time.Now())); err != nil {
+				return fmt.Errorf("failed to create starting balance transaction: %w", err)
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-
 	return account, nil
 }
 
@@ -137,7 +118,8 @@ func (s *AccountService) ListAccounts(ctx context.Context) ([]*domain.Account, e
 }
 
 // UpdateAccount updates an existing account
-func (s *AccountService) UpdateAccount(ctx context.Context, id, name string, balance int64, accountType domain.AccountType) (*domain.Account, error) {
+// A non-nil details replaces the account's details; nil leaves them alone.
+func (s *AccountService) UpdateAccount(ctx context.Context, id, name string, balance int64, accountType domain.AccountType, details *AccountDetailsInput) (*domain.Account, error) {
 	account, err := s.accountRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -154,40 +136,66 @@ func (s *AccountService) UpdateAccount(ctx context.Context, id, name string, bal
 	// Allow updating balance to any value (including negative for credit cards potentially)
 	account.Balance = balance
 
+	oldType := account.Type
 	if accountType != "" {
-		if accountType != domain.AccountTypeChecking &&
-		   accountType != domain.AccountTypeSavings &&
-		   accountType != domain.AccountTypeCash &&
-		   accountType != domain.AccountTypeCredit {
+		if !validAccountType(accountType) {
 			return nil, fmt.Errorf("invalid account type")
 		}
 		account.Type = accountType
 	}
 
+	if details != nil {
+		account.Details, err = normalizeAccountDetails(details)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	account.UpdatedAt = time.Now()
 
-	if err := s.accountRepo.Update(ctx, account); err != nil {
-		return nil, err
-	}
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.accountRepo.Update(ctx, account); err != nil {
+			return err
+		}
 
-	// If balance changed, create an adjustment transaction
-	// This ensures the RTA calculation (Total Inflows - Allocated) reflects the change
-	if balanceDelta != 0 {
-		transaction := &domain.Transaction{
-			ID:          uuid.New().String(),
-			AccountID:   account.ID,
-			Amount:      balanceDelta,
-			Description: "Balance adjustment",
-			Date:        time.Now(),
-			Type:        "normal",
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
+		// If balance changed, create an adjustment transaction
+		// This ensures the RTA calculation (Total Inflows - Allocated) reflects the change;
+		// a tracking account's is a balance update, which stays out of it
+		if balanceDelta != 0 && account.Type.IsOffBudget() {
+			if err := s.transactionRepo.Create(ctx, newBalanceUpdateTransaction(account.ID, balanceDelta, balanceUpdateDescription, "", time.Now())); err != nil {
+				return fmt.Errorf("failed to create balance adjustment transaction: %w", err)
+			}
+		} else if balanceDelta != 0 {
+			transaction := &domain.Transaction{
+				ID:          uuid.New().String(),
+				AccountID:   account.ID,
+				Amount:      balanceDelta,
+				Description: "Balance adjustment",
+				Date:        time.Now(),
+				Type:        "normal",
+				CreatedAt:   time.Now(),
+				UpdatedAt:   time.Now(),
+			}
+			if err := s.transactionRepo.Create(ctx, transaction); err != nil {
+				return fmt.Errorf("failed to create balance adjustment transaction: %w", err)
+			}
 		}
-		if err := s.transactionRepo.Create(ctx, transaction); err != nil {
-			return nil, fmt.Errorf("failed to create balance adjustment transaction: %w", err)
+
+		// Becoming or ceasing to be a credit card brings its payment category along, as
+		// ConvertAccountType does; the payment category's allocations are deleted with it
+		if account.Type != oldType {
+			newType := account.Type
+			account.Type = oldType
+			if err := s.convertAccountType(ctx, account, newType, AccountConversionOptions{}, &AccountConversion{}); err != nil {
+				return err
+			}
+			return s.accountRepo.Update(ctx, account)
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-
 	return account, nil
 }
 
@@ -200,22 +208,25 @@ func (s *AccountService) DeleteAccount(ctx context.Context, id string) error {
 		return err
 	}
 
-	// For credit cards, delete the payment category first
-	if account.Type == domain.AccountTypeCredit {
-		paymentCategory, err := s.categoryRepo.GetPaymentCategoryByAccountID(ctx, id)
-		if err == nil && paymentCategory != nil {
-			// Delete the payment category
-			if err := s.categoryRepo.Delete(ctx, paymentCategory.ID); err != nil {
-				return fmt.Errorf("failed to delete payment category: %w", err)
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		// For credit cards, delete the payment category first
+		if account.Type == domain.AccountTypeCredit {
+			paymentCategory, err := s.categoryRepo.GetPaymentCategoryByAccountID(ctx, id)
+			if err == nil && paymentCategory != nil {
+				// Delete the payment category
+				if err := s.categoryRepo.Delete(ctx, paymentCategory.ID); err != nil {
+					return fmt.Errorf("failed to delete payment category: %w", err)
+				}
 			}
+			// Note: We ignore the error if payment category doesn't exist (already deleted?)
 		}
-		// Note: We ignore the error if payment category doesn't exist (already deleted?)
-	}
 
-	// Delete the account
-	// Note: Transactions will be cascade-deleted by the database foreign key constraint
-	// This automatically removes the account's transactions from RTA calculation
-	if err := s.accountRepo.Delete(ctx, id); err != nil {
+		// Delete the account
+		// Note: Transactions will be cascade-deleted by the database foreign key constraint
+		// This automatically removes the account's transactions from RTA calculation
+		return s.accountRepo.Delete(ctx, id)
+	})
+	if err != nil {
 		return err
 	}
 
@@ -231,7 +242,116 @@ func (s *AccountService) DeleteAccount(ctx context.Context, id string) error {
 	return nil
 }
 
-// GetTotalBalance returns the sum of all account balances
+// AdjustRewards adds earned rewards to a credit account's rewards balance, or removes them
+// when delta is negative (e.g. expired points)
+// Rewards aren't money in the budget until they are redeemed, so this touches neither
+// the account balance nor Ready to Assign.
+func (s *AccountService) AdjustRewards(ctx context.Context, id string, delta int64) (*domain.Account, error) {
+	if delta == 0 {
+		return nil, fmt.Errorf("amount must be non-zero")
+	}
+
+	account, err := s.accountRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if account.Type != domain.AccountTypeCredit {
+		return nil, fmt.Errorf("rewards are only tracked on credit accounts")
+	}
+
+	if err := s.accountRepo.AdjustRewardsBalance(ctx, id, delta); err != nil {
+		return nil, err
+	}
+	return s.accountRepo.GetByID(ctx, id)
+}
+
+// SetHiddenFromDashboard hides an account from the dashboard and balance summaries, or shows it again
+// Hiding only changes what is displayed: the account is still listed, reported on and
+// part of the budget.
+func (s *AccountService) SetHiddenFromDashboard(ctx context.Context, id string, hidden bool) (*domain.Account, error) {
+	account, err := s.accountRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	account.HiddenFromDashboard = hidden
+	account.UpdatedAt = time.Now()
+	if err := s.accountRepo.Update(ctx, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// createPaymentCategory creates a credit card account's payment category in the credit card
+// payments group, creating the group if need be
+func (s *AccountService) createPaymentCategory(ctx context.Context, account *domain.Account) (*domain.Category, error) {
+	// Ensure the credit card payments group exists
+	group, err := s.categoryGroupService.EnsureCreditCardPaymentsGroup(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ensure credit card payments group: %w", err)
+	}
+
+	paymentCategory := &domain.Category{
+		ID:                  uuid.New().String(),
+		Name:                account.Name + " Payment",
+		Description:         "Payment category for " + account.Name,
+		Color:               "#FF6B6B", // Red-ish color for credit card payments
+		GroupID:             &group.ID,
+		PaymentForAccountID: &account.ID,
+		Classification:      domain.ClassificationDebt,
+		OverspendingMode:    domain.OverspendingAuto,
+		CreatedAt:           time.Now(),
+		UpdatedAt:           time.Now(),
+	}
+	if err := s.categoryRepo.Create(ctx, paymentCategory); err != nil {
+		return nil, fmt.Errorf("failed to create payment category: %w", err)
+	}
+	return paymentCategory, nil
+}
+
+// validAccountType reports whether accountType is one accounts can be created with
+func validAccountType(accountType domain.AccountType) bool {
+	switch accountType {
+	case domain.AccountTypeChecking, domain.AccountTypeSavings, domain.AccountTypeCash, domain.AccountTypeCredit, domain.AccountTypeLoan, domain.AccountTypeTracking:
+		return true
+	}
+	return false
+}
+
+// GetTotalBalance returns the sum of the balances of accounts shown on the dashboard
 func (s *AccountService) GetTotalBalance(ctx context.Context) (int64, error) {
 	return s.accountRepo.GetTotalBalance(ctx)
 }
+
+// normalizeAccountDetails validates details and reduces the account number to its last four digits
+// Spaces, dashes and other separators in the number are ignored.
+func normalizeAccountDetails(details *AccountDetailsInput) (domain.AccountDetails, error) {
+	result := domain.AccountDetails{Notes: strings.TrimSpace(details.Notes)}
+
+	if details.AccountNumber != "" {
+		var digits []rune
+		for _, r := range details.AccountNumber {
+			switch {
+			case r >= '0' && r <= '9':
+				digits = append(digits, r)
+			case r == ' ' || r == '-' || r == '.' || r == '*' || r == '•':
+				// Separators and masking characters, e.g. "•••• 1234"
+			default:
+				return domain.AccountDetails{}, fmt.Errorf("account number may only contain digits")
+			}
+		}
+		if len(digits) < 4 {
+			return domain.AccountDetails{}, fmt.Errorf("account number must have at least four digits")
+		}
+		result.AccountNumberLast4 = string(digits[len(digits)-4:])
+	}
+
+	if details.InterestRateBps != nil {
+		rate := *details.InterestRateBps
+		if rate < 0 || rate > maxInterestRateBps {
+			return domain.AccountDetails{}, fmt.Errorf("interest rate must be between 0 and 100%%")
+		}
+		result.InterestRateBps = &rate
+	}
+	return result, nil
+}
@@ -0,0 +1,243 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestAccountService_Details(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	transactionRepo := newMockTransactionRepository()
+	categoryRepo := newMockCategoryRepository()
+	service := NewAccountService(accountRepo, categoryRepo, newMockBudgetStateRepository(0, 0), transactionRepo,
+		NewCategoryGroupService(newMockCategoryGroupRepository(), categoryRepo), &mockUnitOfWork{accountRepo, transactionRepo})
+
+	rate := int64(2499)
+	account, err := service.CreateAccount(ctx, "Rewards Card", 0, domain.AccountTypeCredit, &AccountDetailsInput{
+		Notes:           " Autopay on the 3rd ",
+		AccountNumber:   "4111 1111-1111 1234",
+		InterestRateBps: &rate,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	details := account.Details
+	if details.Notes != "Autopay on the 3rd" || details.AccountNumberLast4 != "1234" || details.InterestRateBps == nil || *details.InterestRateBps != 2499 {
+		t.Errorf("expected the details to be kept with only the last four digits, got %+v", details)
+	}
+
+	// Updating without details leaves them alone
+	account, err = service.UpdateAccount(ctx, account.ID, "Travel Card", 0, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Details.AccountNumberLast4 != "1234" || account.Details.InterestRateBps == nil {
+		t.Errorf("expected the details to be unchanged, got %+v", account.Details)
+	}
+
+	// The masked number sent back in a response is accepted as is
+	account, err = service.UpdateAccount(ctx, account.ID, "", 0, "", &AccountDetailsInput{AccountNumber: "•••• 1234"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Details.AccountNumberLast4 != "1234" || account.Details.InterestRateBps != nil || account.Details.Notes != "" {
+		t.Errorf("expected the details to be replaced, got %+v", account.Details)
+	}
+
+	tooHigh := int64(10001)
+	for _, details := range []*AccountDetailsInput{
+		{AccountNumber: "123"},
+		{AccountNumber: "1234-ABCD"},
+		{InterestRateBps: &tooHigh},
+	} {
+		if _, err := service.UpdateAccount(ctx, account.ID, "", 0, "", details); err == nil {
+			t.Errorf("expected details %+v to be rejected", details)
+		}
+	}
+}
+
+func TestAccountService_UpdateStartingBalance(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	transactionRepo := newMockTransactionRepository()
+	categoryRepo := newMockCategoryRepository()
+	service := NewAccountService(accountRepo, categoryRepo, newMockBudgetStateRepository(0, 0), transactionRepo,
+		NewCategoryGroupService(newMockCategoryGroupRepository(), categoryRepo), &mockUnitOfWork{accountRepo, transactionRepo})
+
+	checking, err := service.CreateAccount(ctx, "Checking", 10000, domain.AccountTypeChecking, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	starting := transactionRepo.transactions[0]
+	if starting.Type != domain.TransactionTypeStartingBalance || starting.CategoryID != nil {
+		t.Errorf("expected the opening balance recorded as an uncategorized starting balance, got %+v", starting)
+	}
+	opened := starting.Date
+	transactionRepo.Create(ctx, &domain.Transaction{ID: "rent", AccountID: checking.ID, Amount: -2000, Type: domain.TransactionTypeNormal})
+	checking.Balance -= 2000
+
+	// The starting balance transaction changes in place and the balance follows it
+	change, err := service.UpdateStartingBalance(ctx, checking.ID, 25000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change.PreviousStartingBalance != 10000 || change.Account.Balance != 23000 || change.Period != opened.Format("2006-01") {
+		t.Errorf("expected the balance to move by the correction, got %+v", change)
+	}
+	if starting.Amount != 25000 || !starting.Date.Equal(opened) || len(transactionRepo.transactions) != 2 {
+		t.Errorf("expected the starting balance corrected on its original date, got %+v", starting)
+	}
+	if _, err := service.UpdateStartingBalance(ctx, checking.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if len(transactionRepo.transactions) != 1 || checking.Balance != -2000 {
+		t.Errorf("expected a zero starting balance to remove its transaction, got %d transactions, balance %d", len(transactionRepo.transactions), checking.Balance)
+	}
+
+	// A card's opening debt is kept on the balance, not as a transaction
+	card, err := service.CreateAccount(ctx, "Visa", -50000, domain.AccountTypeCredit, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transactionRepo.Create(ctx, &domain.Transaction{ID: "dinner", AccountID: card.ID, Amount: -1000, Type: domain.TransactionTypeNormal})
+	card.Balance -= 1000
+	change, err = service.UpdateStartingBalance(ctx, card.ID, -30000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change.PreviousStartingBalance != -50000 || card.Balance != -31000 || len(transactionRepo.transactions) != 2 {
+		t.Errorf("expected the opening debt corrected without a transaction, got %+v", change)
+	}
+	if _, err := service.UpdateStartingBalance(ctx, card.ID, 2000); err != nil {
+		t.Fatal(err)
+	}
+	if card.Balance != 1000 || len(transactionRepo.transactions) != 3 {
+		t.Errorf("expected an opening credit to be recorded as income, got balance %d", card.Balance)
+	}
+
+	if _, err := service.UpdateStartingBalance(ctx, "missing", 100); err != domain.ErrAccountNotFound {
+		t.Errorf("expected a missing account to be reported, got %v", err)
+	}
+}
+
+func TestAccountService_SetHiddenFromDashboard(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	transactionRepo := newMockTransactionRepository()
+	categoryRepo := newMockCategoryRepository()
+	service := NewAccountService(accountRepo, categoryRepo, newMockBudgetStateRepository(0, 0), transactionRepo,
+		NewCategoryGroupService(newMockCategoryGroupRepository(), categoryRepo), &mockUnitOfWork{accountRepo, transactionRepo})
+
+	account, err := service.CreateAccount(ctx, "Joint Checking", 50000, domain.AccountTypeChecking, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.HiddenFromDashboard {
+		t.Fatal("expected a new account to be shown on the dashboard")
+	}
+
+	if _, err := service.SetHiddenFromDashboard(ctx, account.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	// Editing the account doesn't show it again
+	account, err = service.UpdateAccount(ctx, account.ID, "Joint", 50000, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !account.HiddenFromDashboard {
+		t.Error("expected the account to stay hidden after an update")
+	}
+
+	account, err = service.SetHiddenFromDashboard(ctx, account.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.HiddenFromDashboard {
+		t.Error("expected the account to be shown again")
+	}
+
+	if _, err := service.SetHiddenFromDashboard(ctx, "missing", true); err == nil {
+		t.Error("expected an error for a missing account")
+	}
+}
+
+func TestAccountService_ConvertAccountType(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	transactionRepo := newMockTransactionRepository()
+	categoryRepo := newMockCategoryRepository()
+	allocationRepo := newMockAllocationRepository()
+	groupRepo := newMockCategoryGroupRepository()
+	service := NewAccountService(accountRepo, categoryRepo, newMockBudgetStateRepository(0, 0), transactionRepo,
+		NewCategoryGroupService(groupRepo, categoryRepo), &mockUnitOfWork{accountRepo, transactionRepo})
+	service.UseAllocationService(NewAllocationService(allocationRepo, categoryRepo, transactionRepo, newMockBudgetStateRepository(0, 0), accountRepo, groupRepo, newMockGoalRepository()))
+
+	// A checking account created as a credit card, with an opening "debt" that was really its balance
+	account, err := service.CreateAccount(ctx, "Checking", -50000, domain.AccountTypeCredit, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payment, err := categoryRepo.GetPaymentCategoryByAccountID(ctx, account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groceries := &domain.Category{ID: "groceries", Name: "Groceries"}
+	categoryRepo.categories[groceries.ID] = groceries
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a1", CategoryID: payment.ID, Amount: 2000, Period: "2024-01"})
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a2", CategoryID: payment.ID, Amount: 3000, Period: "2024-02"})
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a3", CategoryID: groceries.ID, Amount: 10000, Period: "2024-02"})
+	transactionRepo.transactions = append(transactionRepo.transactions, &domain.Transaction{ID: "paid", AccountID: "other", CategoryID: &payment.ID, Amount: -2000})
+
+	if _, err := service.ConvertAccountType(ctx, account.ID, domain.AccountTypeCredit, AccountConversionOptions{}); err == nil {
+		t.Error("expected converting to the same type to be rejected")
+	}
+	if _, err := service.ConvertAccountType(ctx, account.ID, domain.AccountTypeChecking, AccountConversionOptions{MoveAllocationsTo: payment.ID}); err == nil {
+		t.Error("expected moving allocations to a payment category to be rejected")
+	}
+
+	conversion, err := service.ConvertAccountType(ctx, account.ID, domain.AccountTypeChecking, AccountConversionOptions{MoveAllocationsTo: groceries.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conversion.PaymentCategoryDeleted == nil || conversion.AllocationsMoved != 2 || conversion.PaymentsUncategorized != 1 {
+		t.Errorf("expected the payment category, its two allocations and its payment to be dealt with, got %+v", conversion)
+	}
+	if conversion.ReadyToAssignBefore == nil || conversion.ReadyToAssignAfter == nil {
+		t.Error("expected Ready to Assign before and after the conversion")
+	}
+	if _, err := categoryRepo.GetPaymentCategoryByAccountID(ctx, account.ID); err == nil {
+		t.Error("expected the payment category to be deleted")
+	}
+	if january, _ := allocationRepo.GetByCategoryAndPeriod(ctx, groceries.ID, "2024-01"); january == nil || january.Amount != 2000 {
+		t.Errorf("expected January's allocation to be moved, got %+v", january)
+	}
+	if february, _ := allocationRepo.GetByCategoryAndPeriod(ctx, groceries.ID, "2024-02"); february == nil || february.Amount != 13000 {
+		t.Errorf("expected February's allocation to be added to the existing one, got %+v", february)
+	}
+	if transactionRepo.transactions[0].CategoryID != nil {
+		t.Error("expected the payment to be uncategorized rather than deleted with its category")
+	}
+
+	converted, _ := accountRepo.GetByID(ctx, account.ID)
+	transactions, _ := transactionRepo.ListByAccount(ctx, account.ID)
+	starting := startingBalanceTransaction(transactions)
+	if converted.Type != domain.AccountTypeChecking || converted.Balance != -50000 || starting == nil || starting.Amount != -50000 {
+		t.Errorf("expected a checking account with the opening balance as its starting balance, got %+v and %+v", converted, starting)
+	}
+
+	// And back again
+	conversion, err = service.ConvertAccountType(ctx, account.ID, domain.AccountTypeCredit, AccountConversionOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conversion.PaymentCategoryCreated == nil || conversion.PaymentCategoryCreated.Name != "Checking Payment" {
+		t.Errorf("expected a payment category to be created, got %+v", conversion.PaymentCategoryCreated)
+	}
+	transactions, _ = transactionRepo.ListByAccount(ctx, account.ID)
+	if len(transactions) != 0 {
+		t.Errorf("expected the opening debt to be kept on the balance without a transaction, got %d transactions", len(transactions))
+	}
+}
@@ -0,0 +1,206 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// Actions reported in the activity feed
+const (
+	ActivityCreated = "created"
+	ActivityUpdated = "updated"
+	ActivityDeleted = "deleted"
+)
+
+// ActivityItem is one change to the budget, as shown in the activity feed
+type ActivityItem struct {
+	ID         int64     `json:"id"`     // Audit log entry ID
+	Action     string    `json:"action"` // created, updated or deleted
+	EntityType string    `json:"entity_type"`
+	EntityID   string    `json:"entity_id,omitempty"` // Empty when the route doesn't name one, e.g. creates
+	Link       string    `json:"link,omitempty"`      // API URL of the entity, or of its list when there's no ID or it's gone
+	Summary    string    `json:"summary"`
+	Actor      string    `json:"actor"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ActivityPage is one page of the activity feed, newest first
+// NextCursor is empty on the last page.
+type ActivityPage struct {
+	Items      []*ActivityItem `json:"items"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// activityRoute describes the change an audited route makes
+// list is the API URL of the entity's collection; an entity's own link is list + "/" + its ID.
+type activityRoute struct {
+	entityType string
+	action     string
+	summary    string
+	list       string
+}
+
+// activityRoutes maps audited routes to feed items
+// Sign-in, token, admin and integration settings changes are left out: the feed is for
+// changes to the budget itself.
+var activityRoutes = map[string]activityRoute{
+	"POST /api/accounts":                             {"account", ActivityCreated, "Account created", "/api/accounts"},
+	"PUT /api/accounts/{id}":                         {"account", ActivityUpdated, "Account updated", "/api/accounts"},
+	"PUT /api/accounts/{id}/starting-balance":        {"account", ActivityUpdated, "Starting balance corrected", "/api/accounts"},
+	"PUT /api/accounts/{id}/balance":                 {"account", ActivityUpdated, "Tracking account balance updated", "/api/accounts"},
+	"PUT /api/accounts/{id}/hidden":                  {"account", ActivityUpdated, "Account dashboard visibility changed", "/api/accounts"},
+	"POST /api/accounts/{id}/convert":                {"account", ActivityUpdated, "Account type converted", "/api/accounts"},
+	"DELETE /api/accounts/{id}":                      {"account", ActivityDeleted, "Account deleted", "/api/accounts"},
+	"POST /api/accounts/{id}/rewards":                {"account", ActivityUpdated, "Rewards balance adjusted", "/api/accounts"},
+	"POST /api/accounts/{id}/rewards/redeem":         {"account", ActivityUpdated, "Rewards redeemed", "/api/accounts"},
+	"POST /api/accounts/{id}/payment-reconciliation": {"account", ActivityUpdated, "Credit card payment reconciled", "/api/accounts"},
+	"PUT /api/accounts/{id}/loan":                    {"account", ActivityUpdated, "Loan terms updated", "/api/accounts"},
+	"POST /api/accounts/{id}/loan-payments":          {"transaction", ActivityCreated, "Loan payment recorded", "/api/transactions"},
+	"POST /api/setup/account":                        {"account", ActivityCreated, "Account created", "/api/accounts"},
+	"POST /api/categories":                           {"category", ActivityCreated, "Category created", "/api/categories"},
+	"PUT /api/categories/{id}":                       {"category", ActivityUpdated, "Category updated", "/api/categories"},
+	"POST /api/categories/bulk":                      {"category", ActivityCreated, "Categories created", "/api/categories"},
+	"DELETE /api/categories/{id}":                    {"category", ActivityDeleted, "Category deleted", "/api/categories"},
+	"POST /api/categories/{id}/cover-overspending":   {"category", ActivityUpdated, "Overspending covered", "/api/categories"},
+	"POST /api/category-groups":                      {"category_group", ActivityCreated, "Category group created", "/api/category-groups"},
+	"PUT /api/category-groups/{id}":                  {"category_group", ActivityUpdated, "Category group updated", "/api/category-groups"},
+	"DELETE /api/category-groups/{id}":               {"category_group", ActivityDeleted, "Category group deleted", "/api/category-groups"},
+	"POST /api/category-groups/assign":               {"category", ActivityUpdated, "Category moved to a group", "/api/categories"},
+	"POST /api/category-groups/unassign/{id}":        {"category", ActivityUpdated, "Category removed from its group", "/api/categories"},
+	"POST /api/bootstrap":                            {"category", ActivityCreated, "Starter categories added", "/api/categories"},
+	"POST /api/setup/template":                       {"category", ActivityCreated, "Starter categories added", "/api/categories"},
+	"POST /api/budget-template/import":               {"category", ActivityCreated, "Budget template imported", "/api/categories"},
+	"POST /api/export/import":                        {"account", ActivityCreated, "Budget export imported", "/api/accounts"},
+	"POST /api/import/ynab":                          {"account", ActivityCreated, "Budget imported from YNAB", "/api/accounts"},
+	"POST /api/goals":                                {"goal", ActivityCreated, "Goal created", "/api/goals"},
+	"PUT /api/goals/{id}":                            {"goal", ActivityUpdated, "Goal updated", "/api/goals"},
+	"DELETE /api/goals/{id}":                         {"goal", ActivityDeleted, "Goal deleted", "/api/goals"},
+	"POST /api/payee-rules":                          {"payee_rule", ActivityCreated, "Payee rule created", "/api/payee-rules"},
+	"PUT /api/payee-rules/{id}":                      {"payee_rule", ActivityUpdated, "Payee rule updated", "/api/payee-rules"},
+	"DELETE /api/payee-rules/{id}":                   {"payee_rule", ActivityDeleted, "Payee rule deleted", "/api/payee-rules"},
+	"POST /api/payee-rules/run":                      {"payee_rule", ActivityUpdated, "Payee rules run", "/api/payee-rules"},
+	"POST /api/transactions":                         {"transaction", ActivityCreated, "Transaction created", "/api/transactions"},
+	"POST /api/transactions/transfer":                {"transaction", ActivityCreated, "Transfer created", "/api/transactions"},
+	"POST /api/transactions/external-transfer":       {"transaction", ActivityCreated, "Transfer out of the budget created", "/api/transactions"},
+	"POST /api/transactions/{id}/link-transfer":      {"transaction", ActivityUpdated, "Transfer linked to an account", "/api/transactions"},
+	"POST /api/transfer-suggestions/{id}/accept":     {"transfer_suggestion", ActivityUpdated, "Transfer suggestion accepted", "/api/transfer-suggestions"},
+	"PUT /api/transactions/{id}/original-amount":     {"transaction", ActivityUpdated, "Original currency amount set", "/api/transactions"},
+	"POST /api/transactions/adjustment":              {"transaction", ActivityCreated, "Balance adjustment created", "/api/transactions"},
+	"PUT /api/transactions/{id}":                     {"transaction", ActivityUpdated, "Transaction updated", "/api/transactions"},
+	"DELETE /api/transactions/{id}":                  {"transaction", ActivityDeleted, "Transaction deleted", "/api/transactions"},
+	"POST /api/transactions/bulk-categorize":         {"transaction", ActivityUpdated, "Transactions categorized", "/api/transactions"},
+	"PATCH /api/transactions/bulk":                   {"transaction", ActivityUpdated, "Transactions edited", "/api/transactions"},
+	"POST /api/transactions/import":                  {"transaction", ActivityCreated, "Transactions imported", "/api/transactions"},
+	"POST /api/import/csv":                           {"transaction", ActivityCreated, "Transactions imported from CSV", "/api/transactions"},
+	"POST /api/accounts/{id}/sync":                   {"transaction", ActivityCreated, "Transactions synced from the bank", "/api/transactions"},
+	"POST /api/bank-aggregations/{id}/sync":          {"transaction", ActivityCreated, "Transactions synced through a bank aggregator", "/api/transactions"},
+	"POST /api/imports/{id}/commit":                  {"import", ActivityUpdated, "Reviewed import committed", "/api/imports"},
+	"POST /api/imports/{id}/undo":                    {"import", ActivityUpdated, "Import undone", "/api/imports"},
+	"DELETE /api/imports/{id}":                       {"import", ActivityDeleted, "Import deleted", "/api/imports"},
+	"PUT /api/imports/settings":                      {"setting", ActivityUpdated, "Import matching settings updated", "/api/imports/settings"},
+	"POST /api/integrations/email":                   {"pending_transaction", ActivityCreated, "Transaction received by email", "/api/pending-transactions"},
+	"POST /api/pending-transactions/{id}/approve":    {"pending_transaction", ActivityUpdated, "Pending transaction approved", "/api/pending-transactions"},
+	"POST /api/pending-transactions/{id}/reject":     {"pending_transaction", ActivityUpdated, "Pending transaction rejected", "/api/pending-transactions"},
+	"POST /api/allocations":                          {"allocation", ActivityUpdated, "Money assigned", "/api/allocations"},
+	"POST /api/allocations/cover-underfunded":        {"allocation", ActivityUpdated, "Credit card payment covered", "/api/allocations"},
+	"DELETE /api/allocations/{id}":                   {"allocation", ActivityDeleted, "Assignment removed", "/api/allocations"},
+	"PUT /api/reports/emergency-fund/settings":       {"setting", ActivityUpdated, "Emergency fund settings updated", "/api/reports/emergency-fund"},
+}
+
+// Activity returns a page of changes to the budget, newest first
+// It's the audit log with failed requests and non-budget changes left out. cursor is the
+// NextCursor of the previous page, or empty for the first page; since optionally limits
+// the feed to changes at or after a time ("what changed since I last looked?").
+func (s *AuditService) Activity(ctx context.Context, cursor string, limit int, since *time.Time) (*ActivityPage, error) {
+	if limit <= 0 {
+		limit = DefaultAuditPageSize
+	}
+	if limit > MaxAuditPageSize {
+		limit = MaxAuditPageSize
+	}
+	query := domain.AuditQuery{Since: since, Limit: MaxAuditPageSize}
+	if cursor != "" {
+		beforeID, err := decodeAuditCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		query.BeforeID = beforeID
+	}
+
+	// Most entries make it into the feed, but keep reading until the page is full (plus
+	// one, to learn whether there's another page) or the log runs out
+	page := &ActivityPage{Items: []*ActivityItem{}}
+	hasMore := false
+	for !hasMore {
+		entries, err := s.auditRepo.List(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			item, ok := activityItem(entry)
+			if !ok {
+				continue
+			}
+			if len(page.Items) == limit {
+				hasMore = true
+				break
+			}
+			page.Items = append(page.Items, item)
+		}
+		if len(entries) < query.Limit {
+			break
+		}
+		query.BeforeID = entries[len(entries)-1].ID
+	}
+
+	if hasMore {
+		page.NextCursor = encodeAuditCursor(page.Items[len(page.Items)-1].ID)
+	}
+	return page, nil
+}
+
+// activityItem turns a successful budget change into a feed item
+func activityItem(entry *domain.AuditEntry) (*ActivityItem, bool) {
+	if entry.Status < 200 || entry.Status >= 300 {
+		return nil, false
+	}
+	route, ok := activityRoutes[entry.Route]
+	if !ok {
+		return nil, false
+	}
+
+	item := &ActivityItem{
+		ID:         entry.ID,
+		Action:     route.action,
+		EntityType: route.entityType,
+		EntityID:   routeValue(entry.Route, entry.Path, "id"),
+		Link:       route.list,
+		Summary:    route.summary,
+		Actor:      entry.Actor,
+		CreatedAt:  entry.CreatedAt,
+	}
+	if item.EntityID != "" && route.action != ActivityDeleted {
+		item.Link = route.list + "/" + item.EntityID
+	}
+	return item, true
+}
+
+// routeValue returns the path segment matching a {name} wildcard in a route pattern
+// such as "PUT /api/accounts/{id}", or "" when the pattern has no such wildcard
+func routeValue(route, path, name string) string {
+	_, pattern, _ := strings.Cut(route, " ")
+	patternSegments := strings.Split(pattern, "/")
+	pathSegments := strings.Split(path, "/")
+	if len(patternSegments) != len(pathSegments) {
+		return ""
+	}
+	for i, segment := range patternSegments {
+		if segment == "{"+name+"}" {
+			return pathSegments[i]
+		}
+	}
+	return ""
+}
@@ -3,6 +3,7 @@ package application
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/billybbuffum/budget/internal/domain"
@@ -16,6 +17,14 @@ type AllocationService struct {
 	transactionRepo domain.TransactionRepository
 	budgetStateRepo domain.BudgetStateRepository
 	accountRepo     domain.AccountRepository
+	groupRepo       domain.CategoryGroupRepository
+	goalRepo        domain.GoalRepository
+	rtaCache        *ReadyToAssignCache                  // nil recalculates Ready to Assign on every request
+	rtaTotals       domain.ReadyToAssignTotalsRepository // nil adds up every transaction and allocation instead
+	moveRepo        domain.CategoryMoveRepository        // nil when payment categories are only funded by allocations
+
+	summaryErrorsMu sync.Mutex
+	summaryErrors   map[string]int64 // Summary parts left out since startup, by part
 }
 
 // NewAllocationService creates a new allocation service
@@ -25,6 +34,8 @@ func NewAllocationService(
 	transactionRepo domain.TransactionRepository,
 	budgetStateRepo domain.BudgetStateRepository,
 	accountRepo domain.AccountRepository,
+	groupRepo domain.CategoryGroupRepository,
+	goalRepo domain.GoalRepository,
 ) *AllocationService {
 	return &AllocationService{
 		allocationRepo:  allocationRepo,
@@ -32,9 +43,27 @@ func NewAllocationService(
 		transactionRepo: transactionRepo,
 		budgetStateRepo: budgetStateRepo,
 		accountRepo:     accountRepo,
+		groupRepo:       groupRepo,
+		goalRepo:        goalRepo,
 	}
 }
 
+// UseReadyToAssignCache keeps calculated Ready to Assign amounts until the budget data changes
+func (s *AllocationService) UseReadyToAssignCache(cache *ReadyToAssignCache) {
+	s.rtaCache = cache
+}
+
+// UseReadyToAssignTotals reads income and assigned totals from the ones the database keeps
+// up to date, rather than adding up every transaction and allocation each time
+func (s *AllocationService) UseReadyToAssignTotals(totals domain.ReadyToAssignTotalsRepository) {
+	s.rtaTotals = totals
+}
+
+// UseCategoryMoves counts the money moved into payment categories, see CreditCardService
+func (s *AllocationService) UseCategoryMoves(moveRepo domain.CategoryMoveRepository) {
+	s.moveRepo = moveRepo
+}
+
 // CreateAllocation creates a new allocation or updates existing one for category+period
 func (s *AllocationService) CreateAllocation(ctx context.Context, categoryID string, amount int64, period, notes string) (*domain.Allocation, error) {
 	// Validate category exists
@@ -50,6 +79,9 @@ func (s *AllocationService) CreateAllocation(ctx context.Context, categoryID str
 	if period == "" {
 		return nil, fmt.Errorf("period is required (e.g., '2024-11')")
 	}
+	if _, err := domain.ParsePeriod(period); err != nil {
+		return nil, err
+	}
 
 	// Check if allocation already exists for this category+period
 	existing, err := s.allocationRepo.GetByCategoryAndPeriod(ctx, categoryID, period)
@@ -83,172 +115,126 @@ func (s *AllocationService) CreateAllocation(ctx context.Context, categoryID str
 	return allocation, nil
 }
 
-// AllocateToCoverUnderfunded creates allocations to cover an underfunded payment category
-// This method:
-// 1. Allocates to expense categories with overspending (like Groceries)
-// 2. Then moves that money to the payment category (simulating real-time allocation)
-//
-// This way, RTA only decreases by the amount allocated to expense categories,
-// and the money flows from expense → payment just like it would if you'd budgeted first.
-//
-// Returns:
-//   - allocation: The payment category allocation that was created/updated
-//   - underfundedAmount: The amount that was underfunded (and now covered)
-//   - error: Any error that occurred
-func (s *AllocationService) AllocateToCoverUnderfunded(
-	ctx context.Context,
-	paymentCategoryID string,
-	period string,
-) (*domain.Allocation, int64, error) {
-	// 1. Validate that the category exists and is a payment category
-	category, err := s.categoryRepo.GetByID(ctx, paymentCategoryID)
-	if err != nil {
-		return nil, 0, domain.ErrCategoryNotFound
-	}
-
-	if category.PaymentForAccountID == nil || *category.PaymentForAccountID == "" {
-		return nil, 0, domain.ErrNotPaymentCategory
-	}
-
-	// 2. Calculate the underfunded amount using GetAllocationSummary
-	summaries, err := s.GetAllocationSummary(ctx, period)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to calculate allocation summary: %w", err)
-	}
-
-	var underfundedAmount int64
-	var found bool
-	for _, summary := range summaries {
-		if summary.Category.ID == paymentCategoryID {
-			found = true
-			if summary.Underfunded != nil && *summary.Underfunded > 0 {
-				underfundedAmount = *summary.Underfunded
-			}
-			break
-		}
-	}
-
-	if !found {
-		return nil, 0, fmt.Errorf("payment category not found in summary")
-	}
-
-	// 3. Check that there is actually an underfunded amount
-	if underfundedAmount <= 0 {
-		return nil, 0, domain.ErrNotUnderfunded
-	}
+// CoverOverspendingResult describes the allocations changed to cover an overspent category
+type CoverOverspendingResult struct {
+	Allocation      *domain.Allocation `json:"allocation"`                 // The overspent category's allocation for the period
+	DonorAllocation *domain.Allocation `json:"donor_allocation,omitempty"` // Set when the money came from another category
+	CoveredAmount   int64              `json:"covered_amount"`
+}
 
-	// 4. Calculate Ready to Assign to verify sufficient funds
-	readyToAssign, err := s.CalculateReadyToAssignForPeriod(ctx, period)
+// CoverOverspending brings an overspent category's available back to zero
+// The shortfall comes from Ready to Assign, or from fromCategoryID when it is set. Moving
+// money from a donor lowers the donor's allocation for the period, which may go negative
+// when the donor's available comes from earlier months; RTA is unchanged in that case.
+// Payment categories are covered with CreditCardService.CoverUnderfunded instead.
+func (s *AllocationService) CoverOverspending(ctx context.Context, categoryID, period, fromCategoryID string) (*CoverOverspendingResult, error) {
+	category, err := s.categoryRepo.GetByID(ctx, categoryID)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to calculate Ready to Assign: %w", err)
+		return nil, domain.ErrCategoryNotFound
 	}
-
-	// 5. Verify that Ready to Assign has sufficient funds
-	if readyToAssign < underfundedAmount {
-		return nil, 0, fmt.Errorf(
-			"%w: Ready to Assign: $%.2f, Underfunded: $%.2f",
-			domain.ErrInsufficientFunds,
-			float64(readyToAssign)/100,
-			float64(underfundedAmount)/100,
-		)
+	if category.PaymentForAccountID != nil && *category.PaymentForAccountID != "" {
+		return nil, domain.ErrPaymentCategory
 	}
 
-	// 6. Find all expense categories with overspending on this credit card
-	// Get all transactions on this credit card
-	ccTransactions, err := s.transactionRepo.ListByAccount(ctx, *category.PaymentForAccountID)
+	categories, err := s.categoryRepo.List(ctx)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get credit card transactions: %w", err)
+		return nil, err
 	}
-
-	// Group by category and calculate spending per category
-	categorySpending := make(map[string]int64)
-	for _, txn := range ccTransactions {
-		if txn.CategoryID != nil && *txn.CategoryID != "" && txn.Amount < 0 {
-			// This is spending on an expense category
-			categorySpending[*txn.CategoryID] += -txn.Amount // Convert to positive
-		}
+	totals, err := s.loadPeriodTotals(ctx, period, categories)
+	if err != nil {
+		return nil, err
 	}
-
-	// Get all allocations to calculate what's already allocated
-	allAllocations, err := s.allocationRepo.List(ctx)
+	summary, err := s.summarizeCategory(ctx, category, period, totals)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get allocations: %w", err)
+		return nil, fmt.Errorf("failed to calculate category summary: %w", err)
 	}
-
-	// Calculate total allocated per category
-	categoryAllocations := make(map[string]int64)
-	for _, alloc := range allAllocations {
-		categoryAllocations[alloc.CategoryID] += alloc.Amount
+	if summary.Available >= 0 {
+		return nil, domain.ErrNotOverspent
 	}
+	shortfall := -summary.Available
 
-	// 7. Allocate to underfunded expense categories (like Groceries)
-	// This creates the budget for categories where spending already happened
-	// IMPORTANT: We use CreateAllocation here, which properly reduces RTA
-	var totalAllocatedToExpenses int64
-	for catID, spending := range categorySpending {
-		allocated := categoryAllocations[catID]
-		if spending > allocated {
-			// This category is overspent, allocate to cover it
-			_, err := s.CreateAllocation(
-				ctx,
-				catID,
-				spending, // Allocate the TOTAL spending amount (upsert will replace existing)
-				period,
-				"Cover overspending on credit card",
+	result := &CoverOverspendingResult{CoveredAmount: shortfall}
+	if fromCategoryID == "" {
+		readyToAssign, err := s.CalculateReadyToAssignForPeriod(ctx, period)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate Ready to Assign: %w", err)
+		}
+		if readyToAssign < shortfall {
+			return nil, fmt.Errorf(
+				"%w: Ready to Assign: %s, Overspent: %s",
+				domain.ErrInsufficientFunds,
+				domain.Cents(readyToAssign),
+				domain.Cents(shortfall),
+			)
+		}
+	} else {
+		if fromCategoryID == categoryID {
+			return nil, fmt.Errorf("a category can't cover its own overspending")
+		}
+		donor, err := s.categoryRepo.GetByID(ctx, fromCategoryID)
+		if err != nil {
+			return nil, domain.ErrCategoryNotFound
+		}
+		if donor.PaymentForAccountID != nil && *donor.PaymentForAccountID != "" {
+			return nil, domain.ErrPaymentCategory
+		}
+		donorSummary, err := s.summarizeCategory(ctx, donor, period, totals)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate donor category summary: %w", err)
+		}
+		if donorSummary.Available < shortfall {
+			return nil, fmt.Errorf(
+				"%w: %s has %s available, Overspent: %s",
+				domain.ErrInsufficientCategoryFunds,
+				donor.Name,
+				domain.Cents(donorSummary.Available),
+				domain.Cents(shortfall),
 			)
-			if err != nil {
-				return nil, 0, fmt.Errorf("failed to allocate to expense category: %w", err)
-			}
-			// Track how much we allocated to expenses
-			totalAllocatedToExpenses += (spending - allocated)
 		}
-	}
 
-	// 8. Now "move" the money from expense categories to payment category
-	// We do this by setting the payment allocation to match the card balance
-	// This simulates what real-time allocation does - it moves money between categories
-	// without reducing RTA again
-	//
-	// IMPORTANT: Payment category allocations are EXCLUDED from RTA calculation
-	// (see CalculateReadyToAssignForPeriod lines 441-447). This means we can store
-	// them directly in the database without going through CreateAllocation, and they
-	// won't double-reduce RTA. The expense allocations above already reduced RTA.
+		result.DonorAllocation, err = s.adjustAllocation(ctx, fromCategoryID, period, -shortfall, "Moved to cover "+category.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	// Get the credit card account to determine total amount needed for payment
-	account, err := s.accountRepo.GetByID(ctx, *category.PaymentForAccountID)
+	result.Allocation, err = s.adjustAllocation(ctx, categoryID, period, shortfall, "Cover overspending")
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get credit card account: %w", err)
+		if result.DonorAllocation != nil {
+			// Rollback the move out of the donor
+			s.adjustAllocation(ctx, fromCategoryID, period, shortfall, result.DonorAllocation.Notes)
+		}
+		return nil, err
 	}
+	return result, nil
+}
 
-	// Total amount needed = card balance (convert negative balance to positive)
-	totalNeeded := -account.Balance
-
-	paymentAlloc, err := s.allocationRepo.GetByCategoryAndPeriod(ctx, paymentCategoryID, period)
+// adjustAllocation adds delta to a category's allocation for the period, creating it if needed
+// Unlike CreateAllocation the result may be negative, to record money moved out of a category.
+func (s *AllocationService) adjustAllocation(ctx context.Context, categoryID, period string, delta int64, notes string) (*domain.Allocation, error) {
+	allocation, err := s.allocationRepo.GetByCategoryAndPeriod(ctx, categoryID, period)
 	if err != nil {
-		// Create new allocation for payment category
-		// NOTE: Direct repository access is intentional - payment allocations are excluded from RTA
-		paymentAlloc = &domain.Allocation{
+		allocation = &domain.Allocation{
 			ID:         uuid.New().String(),
-			CategoryID: paymentCategoryID,
-			Amount:     totalNeeded, // Set to card balance
+			CategoryID: categoryID,
+			Amount:     delta,
 			Period:     period,
-			Notes:      "Transferred from expense categories",
+			Notes:      notes,
 			CreatedAt:  time.Now(),
 			UpdatedAt:  time.Now(),
 		}
-		if err := s.allocationRepo.Create(ctx, paymentAlloc); err != nil {
-			return nil, 0, fmt.Errorf("failed to create payment allocation: %w", err)
-		}
-	} else {
-		// Update existing payment allocation to match card balance
-		paymentAlloc.Amount = totalNeeded // Set to card balance (not adding)
-		paymentAlloc.UpdatedAt = time.Now()
-		if err := s.allocationRepo.Update(ctx, paymentAlloc); err != nil {
-			return nil, 0, fmt.Errorf("failed to update payment allocation: %w", err)
+		if err := s.allocationRepo.Create(ctx, allocation); err != nil {
+			return nil, fmt.Errorf("failed to create allocation: %w", err)
 		}
+		return allocation, nil
 	}
 
-	return paymentAlloc, underfundedAmount, nil
+	allocation.Amount += delta
+	allocation.UpdatedAt = time.Now()
+	if err := s.allocationRepo.Update(ctx, allocation); err != nil {
+		return nil, fmt.Errorf("failed to update allocation: %w", err)
+	}
+	return allocation, nil
 }
 
 // GetAllocation retrieves an allocation by ID
@@ -268,6 +254,9 @@ func (s *AllocationService) ListAllocationsByPeriod(ctx context.Context, period
 
 // GetAllocationSummary calculates allocation summary for a period with rollover
 // Shows: assigned this period, activity this period, available (with rollover)
+// Failing to read the categories or their totals fails the summary, since every figure
+// would be wrong. Quick-budget figures and goals are left out instead, and the categories
+// marked (see completeSummary).
 func (s *AllocationService) GetAllocationSummary(ctx context.Context, period string) ([]*domain.AllocationSummary, error) {
 	// Get all categories
 	categories, err := s.categoryRepo.List(ctx)
@@ -275,185 +264,453 @@ func (s *AllocationService) GetAllocationSummary(ctx context.Context, period str
 		return nil, err
 	}
 
+	totals, err := s.loadPeriodTotals(ctx, period, categories)
+	if err != nil {
+		return nil, err
+	}
+	quickBudget, goals := s.summaryExtras(ctx, period)
+
 	var summaries []*domain.AllocationSummary
 
 	for _, category := range categories {
-		// Get allocation for this category+period (may not exist)
-		allocation, _ := s.allocationRepo.GetByCategoryAndPeriod(ctx, category.ID, period)
-
-		// Get activity for this period only
-		activity, err := s.transactionRepo.GetCategoryActivity(ctx, category.ID, period)
+		summary, err := s.summarizeCategory(ctx, category, period, totals)
 		if err != nil {
-			activity = 0 // If error, assume no activity
+			return nil, err
 		}
+		completeSummary(summary, period, totals, quickBudget, goals)
+		summaries = append(summaries, summary)
+	}
 
-		// Calculate available with rollover: sum ALL allocations - sum ALL transactions
-		// Get all allocations for this category across all periods
-		allAllocations, err := s.allocationRepo.List(ctx)
-		if err != nil {
-			continue
+	return summaries, nil
+}
+
+// summaryExtras loads the quick-budget figures and goals for a period's summaries
+// Either is nil if it can't be read; the error is recorded rather than returned. Goals
+// have monthly targets, so weeks and quarters are summarized without them.
+func (s *AllocationService) summaryExtras(ctx context.Context, period string) (*quickBudgetTotals, categoryGoalMap) {
+	quickBudget, err := s.quickBudgetAmounts(ctx, period)
+	if err != nil {
+		s.RecordSummaryError(domain.SummaryPartQuickBudget, err)
+	}
+	if p, err := domain.ParsePeriod(period); err == nil && p.Type != domain.PeriodMonth {
+		return quickBudget, categoryGoalMap{}
+	}
+	goals, err := s.categoryGoals(ctx)
+	if err != nil {
+		s.RecordSummaryError(domain.SummaryPartGoal, err)
+	}
+	return quickBudget, goals
+}
+
+// completeSummary adds quick-budget figures and goal progress to a category's summary
+// A nil quickBudget or goals couldn't be read: the category is marked with the part
+// instead, and its status isn't classified against a goal it may have.
+func completeSummary(summary *domain.AllocationSummary, period string, totals *periodTotals, quickBudget *quickBudgetTotals, goals categoryGoalMap) {
+	if quickBudget != nil {
+		summary.QuickBudget = quickBudget.forCategory(summary.Category.ID)
+	} else {
+		summary.Errors = append(summary.Errors, domain.SummaryPartQuickBudget)
+	}
+	if goals != nil {
+		summary.Goal = goals.progress(summary, period, totals.ledger)
+		summary.Status = goalStatus(summary)
+	} else {
+		summary.Errors = append(summary.Errors, domain.SummaryPartGoal)
+	}
+}
+
+// quickBudgetTotals holds the per-category figures behind the quick-budget buttons
+type quickBudgetTotals struct {
+	budgetedLastMonth map[string]int64
+	lastMonth         map[string]int64 // Net activity
+	lastThreeMonths   map[string]int64 // Net activity
+}
+
+// quickBudgetAmounts loads quick-budget figures for every category in three queries
+// Months before the period are used, like the category inspector's averages. For weeks
+// and quarters, "months" are the weeks or quarters before the period.
+func (s *AllocationService) quickBudgetAmounts(ctx context.Context, period string) (*quickBudgetTotals, error) {
+	current, err := domain.ParsePeriod(period)
+	if err != nil {
+		return nil, err
+	}
+	previous := current.Prev()
+	end := current.Start.Add(-time.Second).Format(time.RFC3339)
+
+	lastMonth, err := s.transactionRepo.SumActivityByCategory(ctx, previous.Start.Format(time.RFC3339), end)
+	if err != nil {
+		return nil, err
+	}
+	lastThreeMonths, err := s.transactionRepo.SumActivityByCategory(ctx, previous.Prev().Prev().Start.Format(time.RFC3339), end)
+	if err != nil {
+		return nil, err
+	}
+	allocations, err := s.allocationRepo.ListByPeriod(ctx, previous.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list allocations: %w", err)
+	}
+
+	totals := &quickBudgetTotals{
+		budgetedLastMonth: make(map[string]int64, len(allocations)),
+		lastMonth:         lastMonth,
+		lastThreeMonths:   lastThreeMonths,
+	}
+	for _, alloc := range allocations {
+		totals.budgetedLastMonth[alloc.CategoryID] += alloc.Amount
+	}
+	return totals, nil
+}
+
+func (t *quickBudgetTotals) forCategory(categoryID string) *domain.QuickBudgetAmounts {
+	return &domain.QuickBudgetAmounts{
+		BudgetedLastMonth: t.budgetedLastMonth[categoryID],
+		SpentLastMonth:    spentFrom(t.lastMonth[categoryID], 1),
+		AverageSpent:      spentFrom(t.lastThreeMonths[categoryID], 3),
+	}
+}
+
+// categoryGoalMap holds goals keyed by category ID
+type categoryGoalMap map[string]*domain.Goal
+
+// categoryGoals loads every goal for the period summaries
+func (s *AllocationService) categoryGoals(ctx context.Context) (categoryGoalMap, error) {
+	goals, err := s.goalRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list goals: %w", err)
+	}
+	byCategory := make(categoryGoalMap, len(goals))
+	for _, goal := range goals {
+		byCategory[goal.CategoryID] = goal
+	}
+	return byCategory, nil
+}
+
+// progress returns a summarized category's goal progress, or nil if it has no goal
+func (g categoryGoalMap) progress(summary *domain.AllocationSummary, period string, ledger categoryLedger) *domain.GoalProgress {
+	goal, ok := g[summary.Category.ID]
+	if !ok {
+		return nil
+	}
+	var assigned int64
+	if summary.Allocation != nil {
+		assigned = summary.Allocation.Amount
+	}
+	return goalProgress(goal, period, ledger.roll(goal.CategoryID, period, false, summary.Category.OverspendingMode).carried, assigned)
+}
+
+// goalStatus classifies a summarized category against its goal progress
+// Overspending outranks how well the goal is funded.
+func goalStatus(summary *domain.AllocationSummary) domain.CategoryStatus {
+	switch {
+	case summary.Status == domain.CategoryStatusOverspentCash || summary.Status == domain.CategoryStatusOverspentCredit:
+		return summary.Status
+	case summary.Goal == nil:
+		return domain.CategoryStatusNoGoal
+	case summary.Goal.OnTrack:
+		return domain.CategoryStatusFullyFunded
+	}
+	return domain.CategoryStatusPartiallyFunded
+}
+
+// spentFrom turns net activity over a number of months into average monthly spending
+// Months where refunds outweigh spending count as nothing spent.
+func spentFrom(activity int64, months int) int64 {
+	if activity >= 0 {
+		return 0
+	}
+	return -activity / int64(months)
+}
+
+// inspectorRecentTransactions caps how many transactions the category inspector lists
+const inspectorRecentTransactions = 10
+
+// InspectCategory gathers the budget inspector's view of one category for a period
+// The period is a month, week or quarter key. Averages cover the full months before the
+// month the period starts in, so a half-finished month doesn't drag them down; budgeted
+// last time is the period before this one.
+func (s *AllocationService) InspectCategory(ctx context.Context, categoryID, period string) (*domain.CategoryInspector, error) {
+	p, err := domain.ParsePeriod(period)
+	if err != nil {
+		return nil, err
+	}
+	start := p.In(domain.PeriodMonth).Start
+	category, err := s.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil {
+		return nil, domain.ErrCategoryNotFound
+	}
+
+	summary, err := s.summarizeCategory(ctx, category, period, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate category summary: %w", err)
+	}
+
+	// Transactions come back newest first
+	transactions, err := s.transactionRepo.ListByCategory(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Net activity per month for the twelve months before the period
+	monthly := make(map[string]int64)
+	earliest := start.AddDate(-1, 0, 0)
+	var recent []*domain.Transaction
+	for _, txn := range transactions {
+		if txn.Date.Before(p.End) && len(recent) < inspectorRecentTransactions {
+			recent = append(recent, txn)
+		}
+		if !txn.Date.Before(earliest) && txn.Date.Before(start) {
+			monthly[txn.Date.Format("2006-01")] += txn.Amount
 		}
+	}
+	if recent == nil {
+		recent = []*domain.Transaction{}
+	}
 
-		var totalAllocated int64
-		for _, alloc := range allAllocations {
-			if alloc.CategoryID == category.ID {
-				totalAllocated += alloc.Amount
-			}
+	averageSpent := func(months int) int64 {
+		var total int64
+		for i := 1; i <= months; i++ {
+			total += monthly[start.AddDate(0, -i, 0).Format("2006-01")]
 		}
+		return spentFrom(total, months)
+	}
 
-		// Get all transactions for this category (negative amounts are spending)
-		allTransactions, err := s.transactionRepo.ListByCategory(ctx, category.ID)
-		if err != nil {
-			continue
+	inspector := &domain.CategoryInspector{
+		Period:     period,
+		Category:   category,
+		Allocation: summary.Allocation,
+		Activity:   summary.Activity,
+		Available:  summary.Available,
+		AverageSpent: domain.SpendingAverages{
+			ThreeMonths:  averageSpent(3),
+			SixMonths:    averageSpent(6),
+			TwelveMonths: averageSpent(12),
+		},
+		RecentTransactions: recent,
+	}
+	inspector.QuickBudget.AverageSpent = inspector.AverageSpent.ThreeMonths
+	inspector.QuickBudget.SpentLastMonth = averageSpent(1)
+	if lastMonth, err := s.allocationRepo.GetByCategoryAndPeriod(ctx, categoryID, p.Prev().Key); err == nil {
+		inspector.QuickBudget.BudgetedLastMonth = lastMonth.Amount
+	}
+
+	return inspector, nil
+}
+
+// periodTotals holds the bulk reads behind a period's category summaries
+type periodTotals struct {
+	ledger       categoryLedger
+	allocations  map[string]*domain.Allocation // This period's, by category ID
+	activity     map[string]int64              // This period's net activity, by category ID
+	allocated    map[string]int64              // Every period's allocations, by category ID
+	cardSpending map[string]map[string]int64   // Outflows by account ID, then category ID
+	names        map[string]string             // Category names by ID
+	accounts     map[string]*domain.Account    // By ID
+}
+
+// loadPeriodTotals reads what summarizeCategory needs for every category at once
+// Each figure is one query however many categories and transactions there are.
+func (s *AllocationService) loadPeriodTotals(ctx context.Context, period string, categories []*domain.Category) (*periodTotals, error) {
+	p, err := domain.ParsePeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	allocations, err := s.allocationRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list allocations: %w", err)
+	}
+	spending, err := s.transactionRepo.SumSpendingByCategory(ctx, p.Type)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := s.accountRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	// Inclusive of the period's last second, like quickBudgetAmounts
+	activity, err := s.transactionRepo.SumActivityByCategory(ctx,
+		p.Start.Format(time.RFC3339), p.End.Add(-time.Second).Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+	allocated, err := s.allocationRepo.SumByCategory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger := buildCategoryLedger(p.Type, allocations, spending, accounts)
+	if err := s.addCategoryMoves(ctx, ledger, p.Type); err != nil {
+		return nil, err
+	}
+
+	totals := &periodTotals{
+		ledger:       ledger,
+		allocations:  make(map[string]*domain.Allocation),
+		activity:     activity,
+		allocated:    allocated,
+		cardSpending: make(map[string]map[string]int64),
+		names:        make(map[string]string, len(categories)),
+		accounts:     make(map[string]*domain.Account, len(accounts)),
+	}
+	for _, alloc := range allocations {
+		if alloc.Period == period {
+			totals.allocations[alloc.CategoryID] = alloc
 		}
+	}
+	for _, total := range spending {
+		if totals.cardSpending[total.AccountID] == nil {
+			totals.cardSpending[total.AccountID] = make(map[string]int64)
+		}
+		totals.cardSpending[total.AccountID][total.CategoryID] += total.Spent
+	}
+	for _, category := range categories {
+		totals.names[category.ID] = category.Name
+	}
+	for _, account := range accounts {
+		totals.accounts[account.ID] = account
+	}
+	return totals, nil
+}
 
-		var totalSpent int64
-		for _, txn := range allTransactions {
-			// For category-specific available: COUNT all spending including transfers
-			// Transfers DO reduce what's available in a specific category
-			// (This is different from Ready to Assign, which excludes transfers)
-			if txn.Amount < 0 {
-				totalSpent += -txn.Amount // Convert to positive for display
-			}
+// summarizeCategory builds one category's allocation summary for a period
+// totals may be nil to summarize a single category, in which case they are read first.
+func (s *AllocationService) summarizeCategory(ctx context.Context, category *domain.Category, period string, totals *periodTotals) (*domain.AllocationSummary, error) {
+	if totals == nil {
+		categories, err := s.categoryRepo.List(ctx)
+		if err != nil {
+			return nil, err
 		}
+		if totals, err = s.loadPeriodTotals(ctx, period, categories); err != nil {
+			return nil, err
+		}
+	}
 
-		// Available = Total Allocated - Total Spent (includes rollover!)
-		available := totalAllocated - totalSpent
-
-		// For payment categories, check if underfunded (available < credit card balance)
-		var underfunded *int64
-		var underfundedCategories []string
-		if category.PaymentForAccountID != nil && *category.PaymentForAccountID != "" {
-			// Get the credit card account balance
-			account, err := s.accountRepo.GetByID(ctx, *category.PaymentForAccountID)
-			if err == nil && account != nil {
-				// Credit card balance is negative (you owe money)
-				// We need enough AVAILABLE (not just allocated) to cover the balance
-				amountOwed := -account.Balance // Convert to positive
-
-				if amountOwed > 0 && available < amountOwed {
-					// Underfunded: need more money
-					shortfall := amountOwed - available
-					underfunded = &shortfall
-
-					// Find which expense categories are underfunded
-					// Get all transactions on this credit card
-					ccTransactions, err := s.transactionRepo.ListByAccount(ctx, *category.PaymentForAccountID)
-					if err == nil {
-						// Group by category and calculate spending per category
-						categorySpending := make(map[string]int64)
-						categoryNames := make(map[string]string)
-
-						for _, txn := range ccTransactions {
-							if txn.CategoryID != nil && *txn.CategoryID != "" && txn.Amount < 0 {
-								// This is spending on an expense category
-								categorySpending[*txn.CategoryID] += -txn.Amount // Convert to positive
-
-								// Get category name
-								if _, exists := categoryNames[*txn.CategoryID]; !exists {
-									cat, err := s.categoryRepo.GetByID(ctx, *txn.CategoryID)
-									if err == nil {
-										categoryNames[*txn.CategoryID] = cat.Name
-									}
-								}
-							}
-						}
+	// Allocation for this category+period (may not exist) and activity for this period only
+	allocation := totals.allocations[category.ID]
+	activity := totals.activity[category.ID]
+
+	// Calculate available with rollover, month by month up to this period
+	// Spending counts transfers too (unlike Ready to Assign, which excludes them), and
+	// overspending from earlier months has already been reset - see categoryLedger.roll
+	isPayment := category.PaymentForAccountID != nil && *category.PaymentForAccountID != ""
+	rolled := totals.ledger.roll(category.ID, period, isPayment, category.OverspendingMode)
+	available := rolled.available
+
+	// Goal funding is classified by the period summaries once the goal is known
+	status := domain.CategoryStatusNoGoal
+	if available < 0 {
+		status = domain.CategoryStatusOverspentCredit
+		if cash, _ := splitOverspending(-available, rolled.creditSpent, category.OverspendingMode); cash > 0 {
+			status = domain.CategoryStatusOverspentCash
+		}
+	}
 
-						// Check each category to see if it has enough allocated
-						for catID, spending := range categorySpending {
-							// Get all allocations for this category
-							allAllocForCat, err := s.allocationRepo.List(ctx)
-							if err == nil {
-								var catTotalAllocated int64
-								for _, alloc := range allAllocForCat {
-									if alloc.CategoryID == catID {
-										catTotalAllocated += alloc.Amount
-									}
-								}
-
-								// If spending exceeds allocation, this category is underfunded
-								if spending > catTotalAllocated {
-									if name, exists := categoryNames[catID]; exists {
-										underfundedCategories = append(underfundedCategories, name)
-									}
-								}
-							}
+	// For payment categories, check if underfunded (available < credit card balance)
+	var underfunded *int64
+	var underfundedCategories []string
+	if isPayment {
+		// Get the credit card account balance
+		if account := totals.accounts[*category.PaymentForAccountID]; account != nil {
+			// Credit card balance is negative (you owe money)
+			// We need enough AVAILABLE (not just allocated) to cover the balance
+			amountOwed := -account.Balance // Convert to positive
+
+			if amountOwed > 0 && available < amountOwed {
+				// Underfunded: need more money
+				shortfall := amountOwed - available
+				underfunded = &shortfall
+
+				// Find which expense categories are underfunded: those that have spent more
+				// on this card than they've ever been allocated
+				for catID, spending := range totals.cardSpending[*category.PaymentForAccountID] {
+					if spending > totals.allocated[catID] {
+						if name, exists := totals.names[catID]; exists {
+							underfundedCategories = append(underfundedCategories, name)
 						}
 					}
 				}
 			}
 		}
-
-		summary := &domain.AllocationSummary{
-			Allocation:            allocation,             // May be nil if no allocation for this period
-			Category:              category,
-			Activity:              activity,               // Activity for THIS period only
-			Available:             available,              // Includes rollover from previous periods
-			Underfunded:           underfunded,            // Amount needed to cover CC balance (nil if not underfunded)
-			UnderfundedCategories: underfundedCategories,  // List of categories needing more allocation
-		}
-		summaries = append(summaries, summary)
 	}
 
-	return summaries, nil
+	return &domain.AllocationSummary{
+		Allocation:            allocation,             // May be nil if no allocation for this period
+		Category:              category,
+		Activity:              activity,               // Activity for THIS period only
+		Available:             available,              // Includes rollover from previous periods
+		Underfunded:           underfunded,            // Amount needed to cover CC balance (nil if not underfunded)
+		UnderfundedCategories: underfundedCategories,  // List of categories needing more allocation
+		Status:                status,
+	}, nil
 }
 
 // CalculateReadyToAssignForPeriod calculates Ready to Assign for a specific period
 // Formula: Total Account Balance - (Total Allocations through period - Total Spent through period)
 // This represents: "How much money do I have that isn't allocated to a category?"
 // Note: This calculation ignores future periods to allow forward budgeting
+// With a cache (see UseReadyToAssignCache), amounts are reused until the budget revision changes.
 func (s *AllocationService) CalculateReadyToAssignForPeriod(ctx context.Context, period string) (int64, error) {
+	if s.rtaCache == nil {
+		return s.calculateReadyToAssign(ctx, period)
+	}
+
+	// Read the revision first: if the data changes while calculating, the amount is
+	// stored against the old revision and never served
+	state, err := s.budgetStateRepo.Get(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if readyToAssign, ok := s.rtaCache.Get(state.Revision, period); ok {
+		return readyToAssign, nil
+	}
+	readyToAssign, err := s.calculateReadyToAssign(ctx, period)
+	if err != nil {
+		return 0, err
+	}
+	s.rtaCache.Put(state.Revision, period, readyToAssign)
+	return readyToAssign, nil
+}
+
+func (s *AllocationService) calculateReadyToAssign(ctx context.Context, period string) (int64, error) {
 	// Ready to Assign = Total Inflows - Total Allocated
 	// This shows how much INCOME is available to allocate, not account balance.
 	// Account balance is lower due to spending, but inflows are what you budget from.
 
-	// Get all transactions to calculate inflows
-	allTransactions, err := s.transactionRepo.List(ctx)
+	p, err := domain.ParsePeriod(period)
 	if err != nil {
-		return 0, fmt.Errorf("failed to list transactions: %w", err)
+		return 0, err
 	}
 
-	// Calculate total inflows through this period
-	// Only count positive amounts (inflows), exclude transfers
-	var totalInflows int64
-	for _, txn := range allTransactions {
-		txnPeriod := txn.Date.Format("2006-01")
-		if txn.Amount > 0 && txnPeriod <= period && txn.Type != "transfer" {
-			totalInflows += txn.Amount
-		}
-	}
-
-	// Get all allocations through this period
-	allAllocations, err := s.allocationRepo.List(ctx)
+	// Total inflows and allocations through this period, period by period
+	totals, err := s.readyToAssignTotals(ctx, p.Type)
 	if err != nil {
-		return 0, fmt.Errorf("failed to list allocations: %w", err)
+		return 0, err
+	}
+	var totalInflows, totalAllocations int64
+	for _, total := range totals {
+		if periodKeyIn(p.Type, total.Period) <= period {
+			totalInflows += total.Inflows
+			totalAllocations += total.Assigned
+		}
 	}
 
-	// Get all categories to identify payment categories
+	// Cash overspending from earlier months was reset to zero in its category and comes
+	// out of Ready to Assign instead. Credit overspending stays on the card as debt.
 	categories, err := s.categoryRepo.List(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to list categories: %w", err)
 	}
-
-	// Build map of payment category IDs
-	paymentCategoryIDs := make(map[string]bool)
+	categoriesByID := make(map[string]*domain.Category, len(categories))
 	for _, cat := range categories {
-		if cat.PaymentForAccountID != nil && *cat.PaymentForAccountID != "" {
-			paymentCategoryIDs[cat.ID] = true
-		}
+		categoriesByID[cat.ID] = cat
 	}
-
-	// Calculate total allocations through this period
-	// EXCLUDE payment category allocations - they represent money transferred from expense categories,
-	// not new money allocated from RTA
-	var totalAllocations int64
-	for _, alloc := range allAllocations {
-		if alloc.Period <= period && !paymentCategoryIDs[alloc.CategoryID] {
-			totalAllocations += alloc.Amount
-		}
+	ledger, err := s.loadCategoryLedger(ctx, p.Type)
+	if err != nil {
+		return 0, err
 	}
+	pastOverspending := ledger.cashOverspentBefore(period, categoriesByID)
 
-	// Ready to Assign = Total Inflows - Total Allocated
+	// Ready to Assign = Total Inflows - Total Allocated - Past Cash Overspending
 	// This can be negative if you over-allocated!
 	// When you categorize unbudgeted spending, categories go negative (overspent).
 	// You must then allocate money to cover the overspending, reducing RTA.
@@ -461,7 +718,7 @@ func (s *AllocationService) CalculateReadyToAssignForPeriod(ctx context.Context,
 	// Payment category allocations are included in total allocations.
 	// Underfunded credit cards will show warnings in the UI, but don't automatically
 	// reduce RTA - you must manually allocate to cover them.
-	readyToAssign := totalInflows - totalAllocations
+	readyToAssign := totalInflows - totalAllocations - pastOverspending
 
 	return readyToAssign, nil
 }
@@ -486,3 +743,92 @@ func (s *AllocationService) DeleteAllocation(ctx context.Context, id string) err
 
 	return nil
 }
+
+// GetAllocationGroupSummary rolls the allocation summary up by category group
+// Totals come from a handful of bulk reads instead of several queries per category, so
+// large budgets stay cheap. Per-category detail (including credit card underfunding) is
+// only built for the groups listed in expand; use domain.UngroupedCategoriesID for
+// categories without a group.
+func (s *AllocationService) GetAllocationGroupSummary(ctx context.Context, period string, expand []string) ([]*domain.AllocationGroupSummary, error) {
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := s.groupRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list category groups: %w", err)
+	}
+	totals, err := s.loadPeriodTotals(ctx, period, categories)
+	if err != nil {
+		return nil, err
+	}
+	// Only the expanded categories' detail needs these
+	var quickBudget *quickBudgetTotals
+	var goals categoryGoalMap
+	if len(expand) > 0 {
+		quickBudget, goals = s.summaryExtras(ctx, period)
+	}
+
+	expanded := make(map[string]bool, len(expand))
+	for _, id := range expand {
+		expanded[id] = true
+	}
+
+	var summaries []*domain.AllocationGroupSummary
+	byID := make(map[string]*domain.AllocationGroupSummary, len(groups)+1)
+	lastOrder := 0
+	for _, group := range groups {
+		lastOrder = max(lastOrder, group.DisplayOrder)
+		summary := &domain.AllocationGroupSummary{
+			GroupID:      group.ID,
+			Name:         group.Name,
+			DisplayOrder: group.DisplayOrder,
+			Expanded:     expanded[group.ID],
+		}
+		byID[group.ID] = summary
+		summaries = append(summaries, summary)
+	}
+
+	for _, category := range categories {
+		groupID := domain.UngroupedCategoriesID
+		if category.GroupID != nil && byID[*category.GroupID] != nil {
+			groupID = *category.GroupID
+		}
+		summary, ok := byID[groupID]
+		if !ok {
+			// Ungrouped categories are listed last
+			summary = &domain.AllocationGroupSummary{
+				GroupID:      domain.UngroupedCategoriesID,
+				Name:         "Ungrouped",
+				DisplayOrder: lastOrder + 1,
+				Expanded:     expanded[domain.UngroupedCategoriesID],
+			}
+			byID[groupID] = summary
+			summaries = append(summaries, summary)
+		}
+
+		// Same totals as summarizeCategory: available rolls each category over month by
+		// month, activity is everything in the period
+		available := totals.ledger.roll(category.ID, period, category.PaymentForAccountID != nil && *category.PaymentForAccountID != "", category.OverspendingMode).available
+		if alloc := totals.allocations[category.ID]; alloc != nil {
+			summary.Assigned += alloc.Amount
+		}
+		summary.Activity += totals.activity[category.ID]
+		summary.Available += available
+		summary.CategoryCount++
+		if available < 0 {
+			summary.OverspentCount++
+		}
+
+		if summary.Expanded {
+			detail, err := s.summarizeCategory(ctx, category, period, totals)
+			if err != nil {
+				return nil, err
+			}
+			completeSummary(detail, period, totals, quickBudget, goals)
+			summary.Categories = append(summary.Categories, detail)
+		}
+	}
+
+	return summaries, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -83,6 +84,14 @@ func (m *mockAllocationRepository) List(ctx context.Context) ([]*domain.Allocati
 	return result, nil
 }
 
+func (m *mockAllocationRepository) SumByCategory(ctx context.Context) (map[string]int64, error) {
+	totals := make(map[string]int64)
+	for _, allocation := range m.allocations {
+		totals[allocation.CategoryID] += allocation.Amount
+	}
+	return totals, nil
+}
+
 func (m *mockAllocationRepository) Update(ctx context.Context, allocation *domain.Allocation) error {
 	if m.updateError != nil {
 		return m.updateError
@@ -174,6 +183,7 @@ type mockTransactionRepository struct {
 	transactions           []*domain.Transaction
 	categoryActivityResult int64
 	categoryActivityError  error
+	lastQuery              domain.TransactionQuery
 }
 
 func newMockTransactionRepository() *mockTransactionRepository {
@@ -234,6 +244,37 @@ func (m *mockTransactionRepository) ListUncategorized(ctx context.Context) ([]*d
 	return result, nil
 }
 
+func (m *mockTransactionRepository) Search(ctx context.Context, query domain.TransactionQuery) ([]*domain.Transaction, int, error) {
+	m.lastQuery = query
+	var matches []*domain.Transaction
+	for _, t := range m.transactions {
+		if query.AccountID != "" && t.AccountID != query.AccountID {
+			continue
+		}
+		if query.Search != "" && !strings.Contains(strings.ToLower(t.Description), strings.ToLower(query.Search)) {
+			continue
+		}
+		if !containsWords(t.Description, strings.Fields(query.Text)) {
+			continue
+		}
+		matches = append(matches, t)
+	}
+	page := matches
+	if query.Limit > 0 {
+		page = matches[min(query.Offset, len(matches)):min(query.Offset+query.Limit, len(matches))]
+	}
+	return page, len(matches), nil
+}
+
+func containsWords(text string, words []string) bool {
+	for _, word := range words {
+		if !strings.Contains(strings.ToLower(text), strings.ToLower(word)) {
+			return false
+		}
+	}
+	return true
+}
+
 func (m *mockTransactionRepository) GetCategoryActivity(ctx context.Context, categoryID, period string) (int64, error) {
 	if m.categoryActivityError != nil {
 		return 0, m.categoryActivityError
@@ -241,14 +282,64 @@ func (m *mockTransactionRepository) GetCategoryActivity(ctx context.Context, cat
 	return m.categoryActivityResult, nil
 }
 
+func (m *mockTransactionRepository) SumActivityByCategory(ctx context.Context, startDate, endDate string) (map[string]int64, error) {
+	start, _ := time.Parse(time.RFC3339, startDate)
+	end, _ := time.Parse(time.RFC3339, endDate)
+	totals := make(map[string]int64)
+	for _, t := range m.transactions {
+		if t.CategoryID != nil && !t.Date.Before(start) && !t.Date.After(end) {
+			totals[*t.CategoryID] += t.Amount
+		}
+	}
+	return totals, nil
+}
+
+func (m *mockTransactionRepository) SumSpendingByCategory(ctx context.Context, periodType domain.PeriodType) ([]*domain.CategorySpending, error) {
+	var totals []*domain.CategorySpending
+	for _, t := range m.transactions {
+		if t.CategoryID == nil || *t.CategoryID == "" || t.Amount >= 0 {
+			continue
+		}
+		totals = append(totals, &domain.CategorySpending{
+			CategoryID: *t.CategoryID, AccountID: t.AccountID, Period: domain.PeriodContaining(periodType, t.Date).Key, Spent: -t.Amount,
+		})
+	}
+	return totals, nil
+}
+
+func (m *mockTransactionRepository) SumInflowsByPeriod(ctx context.Context, periodType domain.PeriodType) (map[string]int64, error) {
+	totals := make(map[string]int64)
+	for _, t := range m.transactions {
+		if t.Amount > 0 && t.Type != domain.TransactionTypeTransfer && t.Type != domain.TransactionTypeAdjustment {
+			totals[domain.PeriodContaining(periodType, t.Date).Key] += t.Amount
+		}
+	}
+	return totals, nil
+}
+
 func (m *mockTransactionRepository) FindDuplicate(ctx context.Context, accountID string, date time.Time, amount int64, description string) (*domain.Transaction, error) {
 	return nil, nil
 }
 
 func (m *mockTransactionRepository) FindByFitID(ctx context.Context, accountID string, fitID string) (*domain.Transaction, error) {
+	for _, t := range m.transactions {
+		if t.AccountID == accountID && t.FitID != nil && *t.FitID == fitID {
+			return t, nil
+		}
+	}
 	return nil, nil
 }
 
+func (m *mockTransactionRepository) ListByImport(ctx context.Context, importID string) ([]*domain.Transaction, error) {
+	var result []*domain.Transaction
+	for _, t := range m.transactions {
+		if t.ImportID != nil && *t.ImportID == importID {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
 func (m *mockTransactionRepository) Update(ctx context.Context, transaction *domain.Transaction) error {
 	for i, t := range m.transactions {
 		if t.ID == transaction.ID {
@@ -260,6 +351,34 @@ func (m *mockTransactionRepository) Update(ctx context.Context, transaction *dom
 }
 
 func (m *mockTransactionRepository) BulkUpdateCategory(ctx context.Context, transactionIDs []string, categoryID *string) error {
+	for _, id := range transactionIDs {
+		for _, t := range m.transactions {
+			if t.ID == id {
+				t.CategoryID = categoryID
+			}
+		}
+	}
+	return nil
+}
+
+func (m *mockTransactionRepository) BulkUpdate(ctx context.Context, transactionIDs []string, edit domain.TransactionBulkEdit) error {
+	for _, id := range transactionIDs {
+		for _, t := range m.transactions {
+			if t.ID != id {
+				continue
+			}
+			if edit.PayeeID != nil {
+				t.PayeeID = edit.PayeeID
+				if *edit.PayeeID == "" {
+					t.PayeeID = nil
+				}
+			}
+			if edit.AccountID != nil {
+				t.AccountID = *edit.AccountID
+			}
+			t.Date = t.Date.AddDate(0, 0, edit.ShiftDays)
+		}
+	}
 	return nil
 }
 
@@ -352,6 +471,18 @@ func (m *mockAccountRepository) Update(ctx context.Context, account *domain.Acco
 	return nil
 }
 
+func (m *mockAccountRepository) AdjustRewardsBalance(ctx context.Context, id string, delta int64) error {
+	account, ok := m.accounts[id]
+	if !ok {
+		return errors.New("account not found")
+	}
+	if account.RewardsBalance+delta < 0 {
+		return errors.New("insufficient rewards balance")
+	}
+	account.RewardsBalance += delta
+	return nil
+}
+
 func (m *mockAccountRepository) Delete(ctx context.Context, id string) error {
 	delete(m.accounts, id)
 	return nil
@@ -364,130 +495,107 @@ func (m *mockAccountRepository) GetTotalBalance(ctx context.Context) (int64, err
 	return m.totalBalance, nil
 }
 
-// Test AllocateToCoverUnderfunded
+type mockCategoryGroupRepository struct {
+	groups []*domain.CategoryGroup
+}
 
-func TestAllocationService_AllocateToCoverUnderfunded_Success(t *testing.T) {
-	// Setup
-	allocationRepo := newMockAllocationRepository()
-	categoryRepo := newMockCategoryRepository()
-	transactionRepo := newMockTransactionRepository()
-	budgetStateRepo := newMockBudgetStateRepository(100000, 50000) // $1000 balance, $500 RTA
-	accountRepo := newMockAccountRepository(100000)
+func newMockCategoryGroupRepository() *mockCategoryGroupRepository {
+	return &mockCategoryGroupRepository{}
+}
 
-	// Create credit card account with $200 debt
-	accountID := "credit-card-account-id"
-	creditCardAccount := &domain.Account{
-		ID:        accountID,
-		Name:      "Test Credit Card",
-		Type:      "credit",
-		Balance:   -20000, // Owe $200
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	accountRepo.accounts[accountID] = creditCardAccount
-
-	// Create payment category
-	paymentCategoryID := "payment-category-id"
-	paymentCategory := &domain.Category{
-		ID:                  paymentCategoryID,
-		Name:                "Credit Card Payment",
-		PaymentForAccountID: &accountID,
-		CreatedAt:           time.Now(),
-		UpdatedAt:           time.Now(),
-	}
-	categoryRepo.categories[paymentCategoryID] = paymentCategory
-
-	// Create regular category with spending
-	regularCategoryID := "regular-category-id"
-	regularCategory := &domain.Category{
-		ID:        regularCategoryID,
-		Name:      "Groceries",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	categoryRepo.categories[regularCategoryID] = regularCategory
-
-	// Simulate $200 spent on credit card (underfunded)
-	transactionRepo.categoryActivityResult = 20000 // $200 in cents
-
-	// Add income transaction to provide RTA
-	incomeCategoryID := "income-category-id"
-	incomeCategory := &domain.Category{
-		ID:        incomeCategoryID,
-		Name:      "Salary",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	categoryRepo.categories[incomeCategoryID] = incomeCategory
-
-	incomeTransaction := &domain.Transaction{
-		ID:          "income-txn-id",
-		AccountID:   "checking-account-id",
-		CategoryID:  &incomeCategoryID,
-		Amount:      20000, // $200 income
-		Description: "Salary",
-		Date:        time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
-	}
-	transactionRepo.transactions = append(transactionRepo.transactions, incomeTransaction)
-
-	// Add credit card spending transaction
-	ccTransaction := &domain.Transaction{
-		ID:          "cc-txn-id",
-		AccountID:   accountID,
-		CategoryID:  &regularCategoryID,
-		Amount:      -20000, // $200 spending
-		Description: "Groceries",
-		Date:        time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
-	}
-	transactionRepo.transactions = append(transactionRepo.transactions, ccTransaction)
+func (m *mockCategoryGroupRepository) Create(ctx context.Context, group *domain.CategoryGroup) error {
+	m.groups = append(m.groups, group)
+	return nil
+}
 
-	service := NewAllocationService(
-		allocationRepo,
-		categoryRepo,
-		transactionRepo,
-		budgetStateRepo,
-		accountRepo,
-	)
+func (m *mockCategoryGroupRepository) GetByID(ctx context.Context, id string) (*domain.CategoryGroup, error) {
+	for _, group := range m.groups {
+		if group.ID == id {
+			return group, nil
+		}
+	}
+	return nil, errors.New("category group not found")
+}
 
-	// Act
-	allocation, underfunded, err := service.AllocateToCoverUnderfunded(
-		context.Background(),
-		paymentCategoryID,
-		"2025-10",
-	)
+func (m *mockCategoryGroupRepository) List(ctx context.Context) ([]*domain.CategoryGroup, error) {
+	return m.groups, nil
+}
 
-	// Assert
-	if err != nil {
-		t.Fatalf("AllocateToCoverUnderfunded() unexpected error = %v", err)
-	}
+func (m *mockCategoryGroupRepository) Update(ctx context.Context, group *domain.CategoryGroup) error {
+	return nil
+}
 
-	if allocation == nil {
-		t.Fatal("AllocateToCoverUnderfunded() allocation is nil")
+func (m *mockCategoryGroupRepository) Delete(ctx context.Context, id string) error {
+	for i, group := range m.groups {
+		if group.ID == id {
+			m.groups = append(m.groups[:i], m.groups[i+1:]...)
+			break
+		}
 	}
+	return nil
+}
+
+type mockGoalRepository struct {
+	goals     map[string]*domain.Goal
+	listError error
+}
 
-	if underfunded != 20000 {
-		t.Errorf("AllocateToCoverUnderfunded() underfunded = %d, want 20000", underfunded)
+func newMockGoalRepository() *mockGoalRepository {
+	return &mockGoalRepository{goals: make(map[string]*domain.Goal)}
+}
+
+func (m *mockGoalRepository) Create(ctx context.Context, goal *domain.Goal) error {
+	m.goals[goal.ID] = goal
+	return nil
+}
+
+func (m *mockGoalRepository) GetByID(ctx context.Context, id string) (*domain.Goal, error) {
+	goal, ok := m.goals[id]
+	if !ok {
+		return nil, domain.ErrGoalNotFound
 	}
+	return goal, nil
+}
 
-	if allocation.CategoryID != paymentCategoryID {
-		t.Errorf("AllocateToCoverUnderfunded() allocation.CategoryID = %s, want %s", allocation.CategoryID, paymentCategoryID)
+func (m *mockGoalRepository) GetByCategoryID(ctx context.Context, categoryID string) (*domain.Goal, error) {
+	for _, goal := range m.goals {
+		if goal.CategoryID == categoryID {
+			return goal, nil
+		}
 	}
+	return nil, domain.ErrGoalNotFound
+}
 
-	if allocation.Period != "2025-10" {
-		t.Errorf("AllocateToCoverUnderfunded() allocation.Period = %s, want 2025-10", allocation.Period)
+func (m *mockGoalRepository) List(ctx context.Context) ([]*domain.Goal, error) {
+	if m.listError != nil {
+		return nil, m.listError
 	}
+	var result []*domain.Goal
+	for _, goal := range m.goals {
+		result = append(result, goal)
+	}
+	return result, nil
+}
+
+func (m *mockGoalRepository) Update(ctx context.Context, goal *domain.Goal) error {
+	m.goals[goal.ID] = goal
+	return nil
+}
 
-	if allocation.Amount != 20000 {
-		t.Errorf("AllocateToCoverUnderfunded() allocation.Amount = %d, want 20000", allocation.Amount)
+func (m *mockGoalRepository) Delete(ctx context.Context, id string) error {
+	if _, ok := m.goals[id]; !ok {
+		return domain.ErrGoalNotFound
 	}
+	delete(m.goals, id)
+	return nil
 }
 
-func TestAllocationService_AllocateToCoverUnderfunded_CategoryNotFound(t *testing.T) {
-	// Setup
+// Test AllocateToCoverUnderfunded
+
+func TestAllocationService_SyncPaymentCategoryAllocations_NotExists(t *testing.T) {
+	// This test verifies that the syncPaymentCategoryAllocations function
+	// no longer exists in the AllocationService
+
 	allocationRepo := newMockAllocationRepository()
 	categoryRepo := newMockCategoryRepository()
 	transactionRepo := newMockTransactionRepository()
@@ -500,521 +608,626 @@ func TestAllocationService_AllocateToCoverUnderfunded_CategoryNotFound(t *testin
 		transactionRepo,
 		budgetStateRepo,
 		accountRepo,
+		newMockCategoryGroupRepository(),
+		newMockGoalRepository(),
 	)
 
-	// Act
-	allocation, underfunded, err := service.AllocateToCoverUnderfunded(
-		context.Background(),
-		"non-existent-category-id",
-		"2025-10",
-	)
-
-	// Assert
-	if err == nil {
-		t.Fatal("AllocateToCoverUnderfunded() expected error, got nil")
-	}
-
-	if err.Error() != "category not found" {
-		t.Errorf("AllocateToCoverUnderfunded() error = %v, want 'category not found'", err)
-	}
+	// Verify the service doesn't have a syncPaymentCategoryAllocations method
+	// This is a compile-time check - if the method exists, this test will fail
+	_ = service
 
-	if allocation != nil {
-		t.Errorf("AllocateToCoverUnderfunded() allocation should be nil")
-	}
+	// Note: In Go, we can't directly check if a method exists at runtime
+	// without using reflection. The absence of the method is verified
+	// by the fact that this test compiles successfully without trying
+	// to call service.syncPaymentCategoryAllocations()
 
-	if underfunded != 0 {
-		t.Errorf("AllocateToCoverUnderfunded() underfunded = %d, want 0", underfunded)
-	}
+	t.Log("Verified: syncPaymentCategoryAllocations function does not exist")
 }
 
-func TestAllocationService_AllocateToCoverUnderfunded_NotPaymentCategory(t *testing.T) {
-	// Setup
+func TestAllocationService_GetAllocationGroupSummary(t *testing.T) {
+	ctx := context.Background()
 	allocationRepo := newMockAllocationRepository()
 	categoryRepo := newMockCategoryRepository()
 	transactionRepo := newMockTransactionRepository()
-	budgetStateRepo := newMockBudgetStateRepository(100000, 50000)
-	accountRepo := newMockAccountRepository(100000)
-
-	// Create regular expense category (not a payment category)
-	regularCategoryID := "regular-category-id"
-	regularCategory := &domain.Category{
-		ID:                  regularCategoryID,
-		Name:                "Groceries",
-		PaymentForAccountID: nil, // NOT a payment category
-		CreatedAt:           time.Now(),
-		UpdatedAt:           time.Now(),
+	groupRepo := newMockCategoryGroupRepository()
+
+	groupRepo.Create(ctx, &domain.CategoryGroup{ID: "bills", Name: "Bills", DisplayOrder: 1})
+	groupRepo.Create(ctx, &domain.CategoryGroup{ID: "fun", Name: "Fun", DisplayOrder: 2})
+	bills, fun := "bills", "fun"
+	categoryRepo.Create(ctx, &domain.Category{ID: "rent", Name: "Rent", GroupID: &bills})
+	categoryRepo.Create(ctx, &domain.Category{ID: "power", Name: "Power", GroupID: &bills})
+	categoryRepo.Create(ctx, &domain.Category{ID: "games", Name: "Games", GroupID: &fun})
+	categoryRepo.Create(ctx, &domain.Category{ID: "misc", Name: "Misc"})
+
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a1", CategoryID: "rent", Period: "2025-09", Amount: 100000})
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a2", CategoryID: "rent", Period: "2025-10", Amount: 100000})
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a3", CategoryID: "games", Period: "2025-10", Amount: 2000})
+
+	spend := func(categoryID string, amount int64, date time.Time) {
+		id := categoryID
+		transactionRepo.Create(ctx, &domain.Transaction{ID: fmt.Sprintf("t%d", len(transactionRepo.transactions)), CategoryID: &id, Amount: amount, Date: date})
+	}
+	spend("rent", -100000, time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC))
+	spend("rent", -100000, time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC))
+	spend("power", -5000, time.Date(2025, 10, 31, 12, 0, 0, 0, time.UTC))
+	spend("games", -1500, time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)) // Next period
+
+	service := NewAllocationService(allocationRepo, categoryRepo, transactionRepo, newMockBudgetStateRepository(0, 0), newMockAccountRepository(0), groupRepo, newMockGoalRepository())
+	summaries, err := service.GetAllocationGroupSummary(ctx, "2025-10", []string{"fun"})
+	if err != nil {
+		t.Fatal(err)
 	}
-	categoryRepo.categories[regularCategoryID] = regularCategory
-
-	service := NewAllocationService(
-		allocationRepo,
-		categoryRepo,
-		transactionRepo,
-		budgetStateRepo,
-		accountRepo,
-	)
-
-	// Act
-	allocation, underfunded, err := service.AllocateToCoverUnderfunded(
-		context.Background(),
-		regularCategoryID,
-		"2025-10",
-	)
-
-	// Assert
-	if err == nil {
-		t.Fatal("AllocateToCoverUnderfunded() expected error, got nil")
+	if len(summaries) != 3 {
+		t.Fatalf("expected Bills, Fun and Ungrouped, got %d groups", len(summaries))
 	}
 
-	if err.Error() != "category is not a payment category" {
-		t.Errorf("AllocateToCoverUnderfunded() error = %v, want 'category is not a payment category'", err)
+	billsSummary, funSummary, ungrouped := summaries[0], summaries[1], summaries[2]
+	if billsSummary.Assigned != 100000 || billsSummary.Activity != -105000 || billsSummary.Available != -5000 {
+		t.Errorf("unexpected Bills rollup: %+v", billsSummary)
 	}
-
-	if allocation != nil {
-		t.Errorf("AllocateToCoverUnderfunded() allocation should be nil")
+	if billsSummary.CategoryCount != 2 || billsSummary.OverspentCount != 1 {
+		t.Errorf("expected 2 categories with 1 overspent in Bills, got %+v", billsSummary)
 	}
-
-	if underfunded != 0 {
-		t.Errorf("AllocateToCoverUnderfunded() underfunded = %d, want 0", underfunded)
+	if billsSummary.Expanded || billsSummary.Categories != nil {
+		t.Error("expected collapsed groups to leave out category detail")
+	}
+	if funSummary.Activity != 0 || funSummary.Available != 2000 { // November's spending isn't October's
+		t.Errorf("unexpected Fun rollup: %+v", funSummary)
+	}
+	if !funSummary.Expanded || len(funSummary.Categories) != 1 || funSummary.Categories[0].Category.ID != "games" {
+		t.Errorf("expected Fun to include its category detail, got %+v", funSummary.Categories)
+	}
+	if ungrouped.GroupID != domain.UngroupedCategoriesID || ungrouped.CategoryCount != 1 || ungrouped.DisplayOrder != 3 {
+		t.Errorf("unexpected ungrouped rollup: %+v", ungrouped)
 	}
 }
 
-func TestAllocationService_AllocateToCoverUnderfunded_NotUnderfunded(t *testing.T) {
-	// Setup
+func TestAllocationService_GetAllocationSummary_UnderfundedCategories(t *testing.T) {
+	ctx := context.Background()
 	allocationRepo := newMockAllocationRepository()
 	categoryRepo := newMockCategoryRepository()
 	transactionRepo := newMockTransactionRepository()
-	budgetStateRepo := newMockBudgetStateRepository(100000, 50000)
-	accountRepo := newMockAccountRepository(100000)
-
-	// Create payment category
-	accountID := "credit-card-account-id"
-	paymentCategoryID := "payment-category-id"
-	paymentCategory := &domain.Category{
-		ID:                  paymentCategoryID,
-		Name:                "Credit Card Payment",
-		PaymentForAccountID: &accountID,
-		CreatedAt:           time.Now(),
-		UpdatedAt:           time.Now(),
-	}
-	categoryRepo.categories[paymentCategoryID] = paymentCategory
-
-	// Create existing allocation that fully covers spending
-	existingAllocation := &domain.Allocation{
-		ID:         "existing-allocation-id",
-		CategoryID: paymentCategoryID,
-		Period:     "2025-10",
-		Amount:     30000, // $300 allocated
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
-	}
-	allocationRepo.allocations[existingAllocation.ID] = existingAllocation
-	key := fmt.Sprintf("%s:%s", existingAllocation.CategoryID, existingAllocation.Period)
-	allocationRepo.categoryPeriodMap[key] = existingAllocation
-
-	// Simulate $200 spent (less than allocated, so not underfunded)
-	transactionRepo.categoryActivityResult = 20000 // $200 in cents
+	accountRepo := newMockAccountRepository(0)
+
+	accountRepo.Create(ctx, &domain.Account{ID: "visa", Name: "Visa", Type: domain.AccountTypeCredit, Balance: -30000})
+	accountRepo.Create(ctx, &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking})
+	visa := "visa"
+	categoryRepo.Create(ctx, &domain.Category{ID: "visa-payment", Name: "Visa Payment", PaymentForAccountID: &visa})
+	categoryRepo.Create(ctx, &domain.Category{ID: "dining", Name: "Dining"})
+	categoryRepo.Create(ctx, &domain.Category{ID: "fuel", Name: "Fuel"})
+
+	// Dining has been allocated less than it put on the card over two months; fuel's
+	// overspending was on checking, so the card doesn't hold it
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a1", CategoryID: "dining", Period: "2025-09", Amount: 10000})
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a2", CategoryID: "dining", Period: "2025-10", Amount: 10000})
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a3", CategoryID: "fuel", Period: "2025-10", Amount: 5000})
+	spend := func(accountID, categoryID string, amount int64, date time.Time) {
+		id := categoryID
+		transactionRepo.Create(ctx, &domain.Transaction{ID: fmt.Sprintf("t%d", len(transactionRepo.transactions)), AccountID: accountID, CategoryID: &id, Amount: amount, Date: date})
+	}
+	spend("visa", "dining", -12000, time.Date(2025, 9, 12, 0, 0, 0, 0, time.UTC))
+	spend("visa", "dining", -13000, time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC))
+	spend("visa", "fuel", -5000, time.Date(2025, 10, 4, 0, 0, 0, 0, time.UTC))
+	spend("checking", "fuel", -9000, time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC))
+
+	service := NewAllocationService(allocationRepo, categoryRepo, transactionRepo, newMockBudgetStateRepository(0, 0), accountRepo, newMockCategoryGroupRepository(), newMockGoalRepository())
+	summaries, err := service.GetAllocationSummary(ctx, "2025-10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var payment *domain.AllocationSummary
+	for _, summary := range summaries {
+		if summary.Category.ID == "visa-payment" {
+			payment = summary
+		}
+	}
+	if payment == nil || payment.Underfunded == nil || *payment.Underfunded != 30000 {
+		t.Fatalf("expected the payment category to be $300 short, got %+v", payment)
+	}
+	if len(payment.UnderfundedCategories) != 1 || payment.UnderfundedCategories[0] != "Dining" {
+		t.Errorf("expected only Dining to be underfunded on the card, got %v", payment.UnderfundedCategories)
+	}
+}
 
-	service := NewAllocationService(
-		allocationRepo,
-		categoryRepo,
-		transactionRepo,
-		budgetStateRepo,
-		accountRepo,
-	)
+func TestAllocationService_CoverOverspending(t *testing.T) {
+	groceriesID := "groceries-id"
+	diningID := "dining-id"
+	newService := func() (*AllocationService, *mockAllocationRepository) {
+		allocationRepo := newMockAllocationRepository()
+		categoryRepo := newMockCategoryRepository()
+		transactionRepo := newMockTransactionRepository()
+		categoryRepo.categories[groceriesID] = &domain.Category{ID: groceriesID, Name: "Groceries"}
+		categoryRepo.categories[diningID] = &domain.Category{ID: diningID, Name: "Dining Out"}
+
+		// $100 income, $50 spent on groceries with nothing assigned
+		transactionRepo.transactions = []*domain.Transaction{
+			{ID: "income", AccountID: "checking", Amount: 10000, Date: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)},
+			{ID: "spend", AccountID: "checking", CategoryID: &groceriesID, Amount: -5000, Date: time.Date(2025, 10, 2, 0, 0, 0, 0, time.UTC)},
+		}
+		service := NewAllocationService(
+			allocationRepo,
+			categoryRepo,
+			transactionRepo,
+			newMockBudgetStateRepository(5000, 10000),
+			newMockAccountRepository(5000),
+			newMockCategoryGroupRepository(),
+			newMockGoalRepository(),
+		)
+		return service, allocationRepo
+	}
+
+	t.Run("from ready to assign", func(t *testing.T) {
+		service, _ := newService()
+		result, err := service.CoverOverspending(context.Background(), groceriesID, "2025-10", "")
+		if err != nil {
+			t.Fatalf("CoverOverspending() unexpected error = %v", err)
+		}
+		if result.CoveredAmount != 5000 || result.Allocation.Amount != 5000 {
+			t.Errorf("CoverOverspending() covered %d with allocation %d, want 5000", result.CoveredAmount, result.Allocation.Amount)
+		}
+		if result.DonorAllocation != nil {
+			t.Errorf("CoverOverspending() DonorAllocation = %+v, want nil", result.DonorAllocation)
+		}
+	})
 
-	// Act
-	allocation, underfunded, err := service.AllocateToCoverUnderfunded(
-		context.Background(),
-		paymentCategoryID,
-		"2025-10",
-	)
+	t.Run("from donor category", func(t *testing.T) {
+		service, allocationRepo := newService()
+		allocationRepo.Create(context.Background(), &domain.Allocation{ID: "dining-alloc", CategoryID: diningID, Period: "2025-10", Amount: 8000})
 
-	// Assert
-	if err == nil {
-		t.Fatal("AllocateToCoverUnderfunded() expected error, got nil")
-	}
+		result, err := service.CoverOverspending(context.Background(), groceriesID, "2025-10", diningID)
+		if err != nil {
+			t.Fatalf("CoverOverspending() unexpected error = %v", err)
+		}
+		if result.DonorAllocation == nil || result.DonorAllocation.Amount != 3000 {
+			t.Errorf("CoverOverspending() DonorAllocation = %+v, want amount 3000", result.DonorAllocation)
+		}
+		if result.Allocation.Amount != 5000 {
+			t.Errorf("CoverOverspending() Allocation.Amount = %d, want 5000", result.Allocation.Amount)
+		}
+	})
 
-	if err.Error() != "payment category is not underfunded" {
-		t.Errorf("AllocateToCoverUnderfunded() error = %v, want 'payment category is not underfunded'", err)
-	}
+	t.Run("donor without enough funds", func(t *testing.T) {
+		service, allocationRepo := newService()
+		allocationRepo.Create(context.Background(), &domain.Allocation{ID: "dining-alloc", CategoryID: diningID, Period: "2025-10", Amount: 1000})
 
-	if allocation != nil {
-		t.Errorf("AllocateToCoverUnderfunded() allocation should be nil")
-	}
+		_, err := service.CoverOverspending(context.Background(), groceriesID, "2025-10", diningID)
+		if !errors.Is(err, domain.ErrInsufficientCategoryFunds) {
+			t.Errorf("CoverOverspending() error = %v, want %v", err, domain.ErrInsufficientCategoryFunds)
+		}
+	})
 
-	if underfunded != 0 {
-		t.Errorf("AllocateToCoverUnderfunded() underfunded = %d, want 0", underfunded)
-	}
+	t.Run("not overspent", func(t *testing.T) {
+		service, _ := newService()
+		_, err := service.CoverOverspending(context.Background(), diningID, "2025-10", "")
+		if !errors.Is(err, domain.ErrNotOverspent) {
+			t.Errorf("CoverOverspending() error = %v, want %v", err, domain.ErrNotOverspent)
+		}
+	})
 }
 
-func TestAllocationService_AllocateToCoverUnderfunded_InsufficientFunds(t *testing.T) {
-	// Setup
-	allocationRepo := newMockAllocationRepository()
+func TestAllocationService_InspectCategory(t *testing.T) {
+	groceriesID := "groceries-id"
 	categoryRepo := newMockCategoryRepository()
-	transactionRepo := newMockTransactionRepository()
-	budgetStateRepo := newMockBudgetStateRepository(100000, 10000) // Only $100 RTA
-	accountRepo := newMockAccountRepository(100000)
+	categoryRepo.categories[groceriesID] = &domain.Category{ID: groceriesID, Name: "Groceries"}
 
-	// Create credit card account with $500 debt
-	accountID := "credit-card-account-id"
-	creditCardAccount := &domain.Account{
-		ID:        accountID,
-		Name:      "Test Credit Card",
-		Type:      "credit",
-		Balance:   -50000, // Owe $500
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	accountRepo.accounts[accountID] = creditCardAccount
-
-	// Create payment category
-	paymentCategoryID := "payment-category-id"
-	paymentCategory := &domain.Category{
-		ID:                  paymentCategoryID,
-		Name:                "Credit Card Payment",
-		PaymentForAccountID: &accountID,
-		CreatedAt:           time.Now(),
-		UpdatedAt:           time.Now(),
-	}
-	categoryRepo.categories[paymentCategoryID] = paymentCategory
-
-	// Simulate $500 spent (underfunded = $500)
-	transactionRepo.categoryActivityResult = 50000 // $500 in cents
-
-	// Add income transaction - only $100, insufficient for $500 underfunded
-	incomeCategoryID := "income-category-id"
-	incomeCategory := &domain.Category{
-		ID:        incomeCategoryID,
-		Name:      "Salary",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	categoryRepo.categories[incomeCategoryID] = incomeCategory
-
-	incomeTransaction := &domain.Transaction{
-		ID:          "income-txn-id",
-		AccountID:   "checking-account-id",
-		CategoryID:  &incomeCategoryID,
-		Amount:      10000, // Only $100 income (insufficient)
-		Description: "Salary",
-		Date:        time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
-	}
-	transactionRepo.transactions = append(transactionRepo.transactions, incomeTransaction)
+	allocationRepo := newMockAllocationRepository()
+	allocationRepo.Create(context.Background(), &domain.Allocation{ID: "sep", CategoryID: groceriesID, Period: "2025-09", Amount: 40000})
+	allocationRepo.Create(context.Background(), &domain.Allocation{ID: "oct", CategoryID: groceriesID, Period: "2025-10", Amount: 45000})
+
+	// Newest first, as the repository returns them
+	transactionRepo := newMockTransactionRepository()
+	for i, txn := range []struct {
+		date   time.Time
+		amount int64
+	}{
+		{time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC), -10000},
+		{time.Date(2025, 9, 20, 0, 0, 0, 0, time.UTC), -30000},
+		{time.Date(2025, 9, 21, 0, 0, 0, 0, time.UTC), 6000}, // Refund
+		{time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC), -45000},
+		{time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), -24000},
+	} {
+		transactionRepo.transactions = append(transactionRepo.transactions, &domain.Transaction{
+			ID: fmt.Sprintf("txn-%d", i), AccountID: "checking", CategoryID: &groceriesID, Amount: txn.amount, Date: txn.date,
+		})
+	}
 
 	service := NewAllocationService(
 		allocationRepo,
 		categoryRepo,
 		transactionRepo,
-		budgetStateRepo,
-		accountRepo,
+		newMockBudgetStateRepository(0, 0),
+		newMockAccountRepository(0),
+		newMockCategoryGroupRepository(),
+		newMockGoalRepository(),
 	)
 
-	// Act
-	allocation, underfunded, err := service.AllocateToCoverUnderfunded(
-		context.Background(),
-		paymentCategoryID,
-		"2025-10",
-	)
-
-	// Assert
-	if err == nil {
-		t.Fatal("AllocateToCoverUnderfunded() expected error, got nil")
+	inspector, err := service.InspectCategory(context.Background(), groceriesID, "2025-10")
+	if err != nil {
+		t.Fatalf("InspectCategory() unexpected error = %v", err)
 	}
 
-	expectedErrMsg := "insufficient funds in Ready to Assign: Ready to Assign: $100.00, Underfunded: $500.00"
-	if err.Error() != expectedErrMsg {
-		t.Errorf("AllocateToCoverUnderfunded() error = %v, want '%s'", err, expectedErrMsg)
+	want := domain.SpendingAverages{ThreeMonths: 23000, SixMonths: 11500, TwelveMonths: 7750}
+	if inspector.AverageSpent != want {
+		t.Errorf("InspectCategory() AverageSpent = %+v, want %+v", inspector.AverageSpent, want)
+	}
+	if inspector.QuickBudget.BudgetedLastMonth != 40000 || inspector.QuickBudget.SpentLastMonth != 24000 || inspector.QuickBudget.AverageSpent != 23000 {
+		t.Errorf("InspectCategory() QuickBudget = %+v", inspector.QuickBudget)
+	}
+	if inspector.Allocation == nil || inspector.Allocation.Amount != 45000 {
+		t.Errorf("InspectCategory() Allocation = %+v, want amount 45000", inspector.Allocation)
+	}
+	if len(inspector.RecentTransactions) != 5 {
+		t.Errorf("InspectCategory() returned %d recent transactions, want 5", len(inspector.RecentTransactions))
 	}
 
-	if allocation != nil {
-		t.Errorf("AllocateToCoverUnderfunded() allocation should be nil")
+	if _, err := service.InspectCategory(context.Background(), "missing", "2025-10"); !errors.Is(err, domain.ErrCategoryNotFound) {
+		t.Errorf("InspectCategory() error = %v, want %v", err, domain.ErrCategoryNotFound)
 	}
 
-	if underfunded != 0 {
-		t.Errorf("AllocateToCoverUnderfunded() underfunded = %d, want 0", underfunded)
+	// A week averages the months before the one it starts in and looks back a week for its budget
+	allocationRepo.Create(context.Background(), &domain.Allocation{ID: "w40", CategoryID: groceriesID, Period: "2025-W40", Amount: 9000})
+	weekly, err := service.InspectCategory(context.Background(), groceriesID, "2025-W41")
+	if err != nil {
+		t.Fatalf("InspectCategory() week unexpected error = %v", err)
+	}
+	if weekly.AverageSpent != want || weekly.QuickBudget.BudgetedLastMonth != 9000 || len(weekly.RecentTransactions) != 5 {
+		t.Errorf("InspectCategory() week = %+v", weekly)
+	}
+	if _, err := service.InspectCategory(context.Background(), groceriesID, "2025-13"); !errors.Is(err, domain.ErrInvalidPeriod) {
+		t.Errorf("InspectCategory() error = %v, want %v", err, domain.ErrInvalidPeriod)
 	}
 }
 
-func TestAllocationService_AllocateToCoverUnderfunded_UpsertBehavior(t *testing.T) {
-	// Setup
-	allocationRepo := newMockAllocationRepository()
+func TestAllocationService_GetAllocationSummary_QuickBudget(t *testing.T) {
+	groceriesID := "groceries-id"
+	rentID := "rent-id"
 	categoryRepo := newMockCategoryRepository()
-	transactionRepo := newMockTransactionRepository()
-	budgetStateRepo := newMockBudgetStateRepository(100000, 50000) // $1000 balance, $500 RTA
-	accountRepo := newMockAccountRepository(100000)
+	categoryRepo.categories[groceriesID] = &domain.Category{ID: groceriesID, Name: "Groceries"}
+	categoryRepo.categories[rentID] = &domain.Category{ID: rentID, Name: "Rent"}
 
-	// Create credit card account with $150 debt
-	accountID := "credit-card-account-id"
-	creditCardAccount := &domain.Account{
-		ID:        accountID,
-		Name:      "Test Credit Card",
-		Type:      "credit",
-		Balance:   -15000, // Owe $150
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	accountRepo.accounts[accountID] = creditCardAccount
-
-	// Create payment category
-	paymentCategoryID := "payment-category-id"
-	paymentCategory := &domain.Category{
-		ID:                  paymentCategoryID,
-		Name:                "Credit Card Payment",
-		PaymentForAccountID: &accountID,
-		CreatedAt:           time.Now(),
-		UpdatedAt:           time.Now(),
-	}
-	categoryRepo.categories[paymentCategoryID] = paymentCategory
-
-	// Create regular category for spending
-	regularCategoryID := "regular-category-id"
-	regularCategory := &domain.Category{
-		ID:        regularCategoryID,
-		Name:      "Groceries",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	categoryRepo.categories[regularCategoryID] = regularCategory
-
-	// Create existing allocation of $100 to payment category
-	existingAllocation := &domain.Allocation{
-		ID:         "existing-allocation-id",
-		CategoryID: paymentCategoryID,
-		Period:     "2025-10",
-		Amount:     10000, // $100 allocated
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
-	}
-	allocationRepo.allocations[existingAllocation.ID] = existingAllocation
-	key := fmt.Sprintf("%s:%s", existingAllocation.CategoryID, existingAllocation.Period)
-	allocationRepo.categoryPeriodMap[key] = existingAllocation
-
-	// Simulate $150 spent (underfunded = $50 because $100 already allocated)
-	transactionRepo.categoryActivityResult = 15000 // $150 in cents
-
-	// Add income transaction - $150 to cover existing $100 allocation + $50 underfunded
-	incomeCategoryID := "income-category-id"
-	incomeCategory := &domain.Category{
-		ID:        incomeCategoryID,
-		Name:      "Salary",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	categoryRepo.categories[incomeCategoryID] = incomeCategory
-
-	incomeTransaction := &domain.Transaction{
-		ID:          "income-txn-id",
-		AccountID:   "checking-account-id",
-		CategoryID:  &incomeCategoryID,
-		Amount:      15000, // $150 income
-		Description: "Salary",
-		Date:        time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
-	}
-	transactionRepo.transactions = append(transactionRepo.transactions, incomeTransaction)
-
-	// Add credit card spending transaction
-	ccTransaction := &domain.Transaction{
-		ID:          "cc-txn-id",
-		AccountID:   accountID,
-		CategoryID:  &regularCategoryID,
-		Amount:      -15000, // $150 spending
-		Description: "Groceries",
-		Date:        time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
-	}
-	transactionRepo.transactions = append(transactionRepo.transactions, ccTransaction)
+	allocationRepo := newMockAllocationRepository()
+	allocationRepo.Create(context.Background(), &domain.Allocation{ID: "sep", CategoryID: groceriesID, Period: "2025-09", Amount: 40000})
+
+	transactionRepo := newMockTransactionRepository()
+	transactionRepo.transactions = []*domain.Transaction{
+		{ID: "oct", AccountID: "checking", CategoryID: &groceriesID, Amount: -10000, Date: time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC)},
+		{ID: "sep", AccountID: "checking", CategoryID: &groceriesID, Amount: -30000, Date: time.Date(2025, 9, 30, 23, 0, 0, 0, time.UTC)},
+		{ID: "jul", AccountID: "checking", CategoryID: &groceriesID, Amount: -15000, Date: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "jun", AccountID: "checking", CategoryID: &groceriesID, Amount: -99000, Date: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)},
+	}
 
 	service := NewAllocationService(
 		allocationRepo,
 		categoryRepo,
 		transactionRepo,
-		budgetStateRepo,
-		accountRepo,
-	)
-
-	// Act
-	allocation, underfunded, err := service.AllocateToCoverUnderfunded(
-		context.Background(),
-		paymentCategoryID,
-		"2025-10",
+		newMockBudgetStateRepository(0, 0),
+		newMockAccountRepository(0),
+		newMockCategoryGroupRepository(),
+		newMockGoalRepository(),
 	)
 
-	// Assert
+	summaries, err := service.GetAllocationSummary(context.Background(), "2025-10")
 	if err != nil {
-		t.Fatalf("AllocateToCoverUnderfunded() unexpected error = %v", err)
+		t.Fatalf("GetAllocationSummary() unexpected error = %v", err)
 	}
 
-	if allocation == nil {
-		t.Fatal("AllocateToCoverUnderfunded() allocation is nil")
+	want := map[string]domain.QuickBudgetAmounts{
+		groceriesID: {BudgetedLastMonth: 40000, SpentLastMonth: 30000, AverageSpent: 15000},
+		rentID:      {},
 	}
-
-	if underfunded != 5000 {
-		t.Errorf("AllocateToCoverUnderfunded() underfunded = %d, want 5000", underfunded)
-	}
-
-	// Verify the allocation was updated (upsert behavior)
-	// The amount should be the original $100 + new $50 = $150
-	updatedAllocation := allocationRepo.categoryPeriodMap[key]
-	if updatedAllocation.Amount != 15000 {
-		t.Errorf("AllocateToCoverUnderfunded() updated allocation amount = %d, want 15000", updatedAllocation.Amount)
-	}
-
-	// Should only have one allocation for this category/period combination
-	count := 0
-	for _, alloc := range allocationRepo.allocations {
-		if alloc.CategoryID == paymentCategoryID && alloc.Period == "2025-10" {
-			count++
+	for _, summary := range summaries {
+		if summary.QuickBudget == nil || *summary.QuickBudget != want[summary.Category.ID] {
+			t.Errorf("GetAllocationSummary() %s QuickBudget = %+v, want %+v", summary.Category.Name, summary.QuickBudget, want[summary.Category.ID])
 		}
 	}
-	if count != 1 {
-		t.Errorf("AllocateToCoverUnderfunded() found %d allocations, want 1 (upsert behavior)", count)
-	}
 }
 
-func TestAllocationService_AllocateToCoverUnderfunded_ExactlyEnoughFunds(t *testing.T) {
-	// Setup
-	allocationRepo := newMockAllocationRepository()
+func TestAllocationService_GetAllocationSummary_Goals(t *testing.T) {
+	vacationID := "vacation-id"
+	groceriesID := "groceries-id"
+	insuranceID := "insurance-id"
 	categoryRepo := newMockCategoryRepository()
-	transactionRepo := newMockTransactionRepository()
-	budgetStateRepo := newMockBudgetStateRepository(100000, 20000) // Exactly $200 RTA
-	accountRepo := newMockAccountRepository(100000)
+	categoryRepo.categories[vacationID] = &domain.Category{ID: vacationID, Name: "Vacation"}
+	categoryRepo.categories[groceriesID] = &domain.Category{ID: groceriesID, Name: "Groceries"}
+	categoryRepo.categories[insuranceID] = &domain.Category{ID: insuranceID, Name: "Insurance"}
 
-	// Create credit card account with $200 debt
-	accountID := "credit-card-account-id"
-	creditCardAccount := &domain.Account{
-		ID:        accountID,
-		Name:      "Test Credit Card",
-		Type:      "credit",
-		Balance:   -20000, // Owe $200
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	accountRepo.accounts[accountID] = creditCardAccount
-
-	// Create payment category
-	paymentCategoryID := "payment-category-id"
-	paymentCategory := &domain.Category{
-		ID:                  paymentCategoryID,
-		Name:                "Credit Card Payment",
-		PaymentForAccountID: &accountID,
-		CreatedAt:           time.Now(),
-		UpdatedAt:           time.Now(),
-	}
-	categoryRepo.categories[paymentCategoryID] = paymentCategory
-
-	// Create regular category for spending
-	regularCategoryID := "regular-category-id"
-	regularCategory := &domain.Category{
-		ID:        regularCategoryID,
-		Name:      "Groceries",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	categoryRepo.categories[regularCategoryID] = regularCategory
-
-	// Simulate exactly $200 spent (underfunded = $200, exactly matches RTA)
-	transactionRepo.categoryActivityResult = 20000 // $200 in cents
-
-	// Add income transaction - exactly $200 to match underfunded amount
-	incomeCategoryID := "income-category-id"
-	incomeCategory := &domain.Category{
-		ID:        incomeCategoryID,
-		Name:      "Salary",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	categoryRepo.categories[incomeCategoryID] = incomeCategory
-
-	incomeTransaction := &domain.Transaction{
-		ID:          "income-txn-id",
-		AccountID:   "checking-account-id",
-		CategoryID:  &incomeCategoryID,
-		Amount:      20000, // Exactly $200 income
-		Description: "Salary",
-		Date:        time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
-	}
-	transactionRepo.transactions = append(transactionRepo.transactions, incomeTransaction)
-
-	// Add credit card spending transaction
-	ccTransaction := &domain.Transaction{
-		ID:          "cc-txn-id",
-		AccountID:   accountID,
-		CategoryID:  &regularCategoryID,
-		Amount:      -20000, // $200 spending
-		Description: "Groceries",
-		Date:        time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
-	}
-	transactionRepo.transactions = append(transactionRepo.transactions, ccTransaction)
+	allocationRepo := newMockAllocationRepository()
+	allocationRepo.Create(context.Background(), &domain.Allocation{ID: "a1", CategoryID: vacationID, Period: "2025-09", Amount: 30000})
+	allocationRepo.Create(context.Background(), &domain.Allocation{ID: "a2", CategoryID: vacationID, Period: "2025-10", Amount: 10000})
+	allocationRepo.Create(context.Background(), &domain.Allocation{ID: "a3", CategoryID: groceriesID, Period: "2025-10", Amount: 40000})
+
+	january := "2026-01"
+	march := "2025-03"
+	goalRepo := newMockGoalRepository()
+	goalRepo.Create(context.Background(), &domain.Goal{ID: "g1", CategoryID: vacationID, TargetAmount: 120000, TargetDate: &january, Cadence: domain.GoalCadenceOnce})
+	goalRepo.Create(context.Background(), &domain.Goal{ID: "g2", CategoryID: groceriesID, TargetAmount: 40000, Cadence: domain.GoalCadenceMonthly})
+	goalRepo.Create(context.Background(), &domain.Goal{ID: "g3", CategoryID: insuranceID, TargetAmount: 60000, TargetDate: &march, Cadence: domain.GoalCadenceYearly})
 
 	service := NewAllocationService(
 		allocationRepo,
 		categoryRepo,
-		transactionRepo,
-		budgetStateRepo,
-		accountRepo,
+		newMockTransactionRepository(),
+		newMockBudgetStateRepository(0, 0),
+		newMockAccountRepository(0),
+		newMockCategoryGroupRepository(),
+		goalRepo,
 	)
 
-	// Act
-	allocation, underfunded, err := service.AllocateToCoverUnderfunded(
-		context.Background(),
-		paymentCategoryID,
-		"2025-10",
-	)
+	summaries, err := service.GetAllocationSummary(context.Background(), "2025-10")
+	if err != nil {
+		t.Fatalf("GetAllocationSummary() unexpected error = %v", err)
+	}
+
+	type want struct {
+		due       string
+		funded    int64
+		suggested int64
+		needed    int64
+		onTrack   bool
+	}
+	wants := map[string]want{
+		// 90000 left over Oct-Jan is 22500 a month; 10000 is assigned so far
+		vacationID:  {due: "2026-01", funded: 40000, suggested: 22500, needed: 12500},
+		groceriesID: {funded: 40000, suggested: 40000, onTrack: true},
+		// March has passed, so the next due month is March 2026: 60000 over six months
+		insuranceID: {due: "2026-03", suggested: 10000, needed: 10000},
+	}
+	for _, summary := range summaries {
+		w := wants[summary.Category.ID]
+		g := summary.Goal
+		if g == nil {
+			t.Errorf("GetAllocationSummary() %s has no goal progress", summary.Category.Name)
+			continue
+		}
+		if g.DuePeriod != w.due || g.Funded != w.funded || g.SuggestedMonthly != w.suggested || g.NeededThisPeriod != w.needed || g.OnTrack != w.onTrack {
+			t.Errorf("GetAllocationSummary() %s Goal = %+v, want %+v", summary.Category.Name, *g, w)
+		}
+	}
+}
+
+func TestAllocationService_SummaryStatus(t *testing.T) {
+	ctx := context.Background()
+	ids := []string{"groceries", "rent", "dining", "gifts", "fun"}
+	categoryRepo := newMockCategoryRepository()
+	for _, id := range ids {
+		categoryRepo.categories[id] = &domain.Category{ID: id, Name: id}
+	}
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Type: domain.AccountTypeChecking}
+	accountRepo.accounts["visa"] = &domain.Account{ID: "visa", Type: domain.AccountTypeCredit}
+
+	allocationRepo := newMockAllocationRepository()
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a1", CategoryID: "groceries", Period: "2025-10", Amount: 40000})
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a2", CategoryID: "rent", Period: "2025-10", Amount: 50000})
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a3", CategoryID: "dining", Period: "2025-10", Amount: 5000})
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a4", CategoryID: "gifts", Period: "2025-10", Amount: 5000})
+
+	goalRepo := newMockGoalRepository()
+	goalRepo.Create(ctx, &domain.Goal{ID: "g1", CategoryID: "groceries", TargetAmount: 40000, Cadence: domain.GoalCadenceMonthly})
+	goalRepo.Create(ctx, &domain.Goal{ID: "g2", CategoryID: "rent", TargetAmount: 100000, Cadence: domain.GoalCadenceMonthly})
+	goalRepo.Create(ctx, &domain.Goal{ID: "g3", CategoryID: "dining", TargetAmount: 5000, Cadence: domain.GoalCadenceMonthly})
+
+	october := time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)
+	dining, gifts := "dining", "gifts"
+	transactionRepo := newMockTransactionRepository()
+	transactionRepo.transactions = []*domain.Transaction{
+		{ID: "t1", AccountID: "checking", CategoryID: &dining, Amount: -6000, Date: october},
+		{ID: "t2", AccountID: "visa", CategoryID: &gifts, Amount: -7000, Date: october},
+	}
 
-	// Assert
+	service := NewAllocationService(allocationRepo, categoryRepo, transactionRepo, newMockBudgetStateRepository(0, 0), accountRepo, newMockCategoryGroupRepository(), goalRepo)
+	summaries, err := service.GetAllocationSummary(ctx, "2025-10")
 	if err != nil {
-		t.Fatalf("AllocateToCoverUnderfunded() unexpected error = %v", err)
+		t.Fatalf("GetAllocationSummary() unexpected error = %v", err)
 	}
 
-	if allocation == nil {
-		t.Fatal("AllocateToCoverUnderfunded() allocation is nil")
+	wants := map[string]domain.CategoryStatus{
+		"groceries": domain.CategoryStatusFullyFunded,
+		"rent":      domain.CategoryStatusPartiallyFunded,
+		"dining":    domain.CategoryStatusOverspentCash, // Overspending outranks a funded goal
+		"gifts":     domain.CategoryStatusOverspentCredit,
+		"fun":       domain.CategoryStatusNoGoal,
 	}
+	for _, summary := range summaries {
+		if want := wants[summary.Category.ID]; summary.Status != want {
+			t.Errorf("GetAllocationSummary() %s Status = %s, want %s", summary.Category.ID, summary.Status, want)
+		}
+	}
+}
 
-	if underfunded != 20000 {
-		t.Errorf("AllocateToCoverUnderfunded() underfunded = %d, want 20000", underfunded)
+func TestAllocationService_OverspendingRollover(t *testing.T) {
+	ctx := context.Background()
+	groceriesID := "groceries-id"
+	central := time.FixedZone("CDT", -5*60*60)
+
+	tests := []struct {
+		name         string
+		mode         domain.OverspendingMode
+		transactions []*domain.Transaction
+		wantSepAvail int64
+		wantOctAvail int64
+		wantOctRTA   int64
+		wantSepRTA   int64
+	}{
+		{
+			name: "cash overspending resets and comes out of next month's RTA",
+			transactions: []*domain.Transaction{
+				{AccountID: "checking", Amount: -15000, Date: time.Date(2025, 9, 30, 23, 59, 59, 0, time.UTC)},
+			},
+			wantSepAvail: -5000, wantOctAvail: 20000,
+			wantSepRTA: 70000, wantOctRTA: 45000,
+		},
+		{
+			name: "credit overspending resets without touching RTA",
+			transactions: []*domain.Transaction{
+				{AccountID: "visa", Amount: -15000, Date: time.Date(2025, 9, 30, 23, 59, 59, 0, time.UTC)},
+			},
+			wantSepAvail: -5000, wantOctAvail: 20000,
+			wantSepRTA: 70000, wantOctRTA: 50000,
+		},
+		{
+			name: "mixed overspending only charges the cash part",
+			transactions: []*domain.Transaction{
+				{AccountID: "checking", Amount: -12000, Date: time.Date(2025, 9, 10, 0, 0, 0, 0, time.UTC)},
+				{AccountID: "visa", Amount: -3000, Date: time.Date(2025, 9, 20, 0, 0, 0, 0, time.UTC)},
+			},
+			wantSepAvail: -5000, wantOctAvail: 20000,
+			wantSepRTA: 70000, wantOctRTA: 48000,
+		},
+		{
+			name: "spending at midnight belongs to the new month",
+			transactions: []*domain.Transaction{
+				{AccountID: "checking", Amount: -15000, Date: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)},
+			},
+			wantSepAvail: 10000, wantOctAvail: 15000,
+			wantSepRTA: 70000, wantOctRTA: 50000,
+		},
+		{
+			name: "dates with an offset fall in their UTC month",
+			transactions: []*domain.Transaction{
+				{AccountID: "checking", Amount: -15000, Date: time.Date(2025, 9, 30, 22, 0, 0, 0, central)},
+			},
+			wantSepAvail: 10000, wantOctAvail: 15000,
+			wantSepRTA: 70000, wantOctRTA: 50000,
+		},
+		{
+			name: "leftover money rolls forward",
+			transactions: []*domain.Transaction{
+				{AccountID: "checking", Amount: -4000, Date: time.Date(2025, 9, 15, 0, 0, 0, 0, time.UTC)},
+			},
+			wantSepAvail: 6000, wantOctAvail: 26000,
+			wantSepRTA: 70000, wantOctRTA: 50000,
+		},
+		{
+			name: "cash mode charges credit overspending to RTA",
+			mode: domain.OverspendingCash,
+			transactions: []*domain.Transaction{
+				{AccountID: "visa", Amount: -15000, Date: time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)},
+			},
+			wantSepAvail: -5000, wantOctAvail: 20000,
+			wantSepRTA: 70000, wantOctRTA: 45000,
+		},
+		{
+			name: "credit mode leaves cash overspending out of RTA",
+			mode: domain.OverspendingCredit,
+			transactions: []*domain.Transaction{
+				{AccountID: "checking", Amount: -12000, Date: time.Date(2025, 9, 10, 0, 0, 0, 0, time.UTC)},
+				{AccountID: "visa", Amount: -3000, Date: time.Date(2025, 9, 20, 0, 0, 0, 0, time.UTC)},
+			},
+			wantSepAvail: -5000, wantOctAvail: 20000,
+			wantSepRTA: 70000, wantOctRTA: 50000,
+		},
 	}
 
-	if allocation.Amount != 20000 {
-		t.Errorf("AllocateToCoverUnderfunded() allocation.Amount = %d, want 20000", allocation.Amount)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categoryRepo := newMockCategoryRepository()
+			categoryRepo.categories[groceriesID] = &domain.Category{ID: groceriesID, Name: "Groceries", OverspendingMode: tt.mode}
+
+			accountRepo := newMockAccountRepository(0)
+			accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Type: domain.AccountTypeChecking}
+			accountRepo.accounts["visa"] = &domain.Account{ID: "visa", Type: domain.AccountTypeCredit}
+
+			// $800 income; $100 assigned in September, $200 in October
+			allocationRepo := newMockAllocationRepository()
+			allocationRepo.Create(ctx, &domain.Allocation{ID: "sep", CategoryID: groceriesID, Period: "2025-09", Amount: 10000})
+			allocationRepo.Create(ctx, &domain.Allocation{ID: "oct", CategoryID: groceriesID, Period: "2025-10", Amount: 20000})
+
+			transactionRepo := newMockTransactionRepository()
+			transactionRepo.transactions = []*domain.Transaction{
+				{ID: "income", AccountID: "checking", Amount: 80000, Date: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)},
+			}
+			for i, txn := range tt.transactions {
+				txn.ID = fmt.Sprintf("txn-%d", i)
+				txn.CategoryID = &groceriesID
+				transactionRepo.transactions = append(transactionRepo.transactions, txn)
+			}
+
+			service := NewAllocationService(allocationRepo, categoryRepo, transactionRepo, newMockBudgetStateRepository(0, 0), accountRepo, newMockCategoryGroupRepository(), newMockGoalRepository())
+
+			for _, check := range []struct {
+				period    string
+				wantAvail int64
+				wantRTA   int64
+			}{
+				{"2025-09", tt.wantSepAvail, tt.wantSepRTA},
+				{"2025-10", tt.wantOctAvail, tt.wantOctRTA},
+			} {
+				summaries, err := service.GetAllocationSummary(ctx, check.period)
+				if err != nil {
+					t.Fatalf("GetAllocationSummary(%s) unexpected error = %v", check.period, err)
+				}
+				if got := summaries[0].Available; got != check.wantAvail {
+					t.Errorf("%s Available = %d, want %d", check.period, got, check.wantAvail)
+				}
+				rta, err := service.CalculateReadyToAssignForPeriod(ctx, check.period)
+				if err != nil {
+					t.Fatalf("CalculateReadyToAssignForPeriod(%s) unexpected error = %v", check.period, err)
+				}
+				if rta != check.wantRTA {
+					t.Errorf("%s Ready to Assign = %d, want %d", check.period, rta, check.wantRTA)
+				}
+			}
+		})
 	}
 }
 
-func TestAllocationService_SyncPaymentCategoryAllocations_NotExists(t *testing.T) {
-	// This test verifies that the syncPaymentCategoryAllocations function
-	// no longer exists in the AllocationService
+func TestAllocationService_WeeklyAndQuarterlyPeriods(t *testing.T) {
+	ctx := context.Background()
+	groceriesID := "groceries"
 
-	allocationRepo := newMockAllocationRepository()
 	categoryRepo := newMockCategoryRepository()
-	transactionRepo := newMockTransactionRepository()
-	budgetStateRepo := newMockBudgetStateRepository(100000, 50000)
-	accountRepo := newMockAccountRepository(100000)
+	categoryRepo.categories[groceriesID] = &domain.Category{ID: groceriesID, Name: "Groceries"}
 
-	service := NewAllocationService(
-		allocationRepo,
-		categoryRepo,
-		transactionRepo,
-		budgetStateRepo,
-		accountRepo,
-	)
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Type: domain.AccountTypeChecking}
 
-	// Verify the service doesn't have a syncPaymentCategoryAllocations method
-	// This is a compile-time check - if the method exists, this test will fail
-	_ = service
+	// Budgeting per paycheck: each week gets its own allocation
+	allocationRepo := newMockAllocationRepository()
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "w41", CategoryID: groceriesID, Period: "2025-W41", Amount: 20000})
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "w42", CategoryID: groceriesID, Period: "2025-W42", Amount: 20000})
 
-	// Note: In Go, we can't directly check if a method exists at runtime
-	// without using reflection. The absence of the method is verified
-	// by the fact that this test compiles successfully without trying
-	// to call service.syncPaymentCategoryAllocations()
+	transactionRepo := newMockTransactionRepository()
+	transactionRepo.transactions = []*domain.Transaction{
+		{ID: "paycheck", AccountID: "checking", Type: domain.TransactionTypeNormal, Amount: 100000, Date: time.Date(2025, 10, 6, 9, 0, 0, 0, time.UTC)},
+		{ID: "shop-1", AccountID: "checking", CategoryID: &groceriesID, Amount: -15000, Date: time.Date(2025, 10, 8, 0, 0, 0, 0, time.UTC)},
+		{ID: "shop-2", AccountID: "checking", CategoryID: &groceriesID, Amount: -30000, Date: time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)},
+	}
+
+	service := NewAllocationService(allocationRepo, categoryRepo, transactionRepo, newMockBudgetStateRepository(0, 0), accountRepo, newMockCategoryGroupRepository(), newMockGoalRepository())
+
+	tests := []struct {
+		period        string
+		activity      int64
+		available     int64
+		readyToAssign int64
+	}{
+		{"2025-W41", -15000, 5000, 80000},
+		{"2025-W42", -30000, -5000, 60000}, // The first week's $50 rolls over
+		{"2025-10", -45000, -5000, 60000},  // Weekly allocations count in the month they start in
+		{"2025-Q4", -45000, -5000, 60000},
+	}
+	for _, tt := range tests {
+		summaries, err := service.GetAllocationSummary(ctx, tt.period)
+		if err != nil {
+			t.Fatalf("GetAllocationSummary(%s) unexpected error = %v", tt.period, err)
+		}
+		if len(summaries) != 1 {
+			t.Fatalf("GetAllocationSummary(%s) = %d summaries, want 1", tt.period, len(summaries))
+		}
+		if got := summaries[0]; got.Activity != tt.activity || got.Available != tt.available {
+			t.Errorf("%s: activity %d, available %d; want %d, %d", tt.period, got.Activity, got.Available, tt.activity, tt.available)
+		}
+		readyToAssign, err := service.CalculateReadyToAssignForPeriod(ctx, tt.period)
+		if err != nil {
+			t.Fatalf("CalculateReadyToAssignForPeriod(%s) unexpected error = %v", tt.period, err)
+		}
+		if readyToAssign != tt.readyToAssign {
+			t.Errorf("%s: Ready to Assign = %d, want %d", tt.period, readyToAssign, tt.readyToAssign)
+		}
+	}
 
-	t.Log("Verified: syncPaymentCategoryAllocations function does not exist")
+	if _, err := service.CreateAllocation(ctx, groceriesID, 1000, "2025-W60", ""); !errors.Is(err, domain.ErrInvalidPeriod) {
+		t.Errorf("CreateAllocation() with week 60 error = %v, want %v", err, domain.ErrInvalidPeriod)
+	}
 }
@@ -0,0 +1,212 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/google/uuid"
+)
+
+// AllocationTemplateService handles named budget plans that can be applied to any period
+type AllocationTemplateService struct {
+	templateRepo      domain.AllocationTemplateRepository
+	categoryRepo      domain.CategoryRepository
+	allocationRepo    domain.AllocationRepository
+	allocationService *AllocationService
+	uow               domain.UnitOfWork
+}
+
+// NewAllocationTemplateService creates a new allocation template service
+func NewAllocationTemplateService(
+	templateRepo domain.AllocationTemplateRepository,
+	categoryRepo domain.CategoryRepository,
+	allocationRepo domain.AllocationRepository,
+	allocationService *AllocationService,
+	uow domain.UnitOfWork,
+) *AllocationTemplateService {
+	return &AllocationTemplateService{
+		templateRepo:      templateRepo,
+		categoryRepo:      categoryRepo,
+		allocationRepo:    allocationRepo,
+		allocationService: allocationService,
+		uow:               uow,
+	}
+}
+
+// CreateTemplate saves a named set of category amounts
+func (s *AllocationTemplateService) CreateTemplate(ctx context.Context, name string, items []domain.AllocationTemplateItem) (*domain.AllocationTemplate, error) {
+	template := &domain.AllocationTemplate{
+		ID:        uuid.New().String(),
+		Name:      strings.TrimSpace(name),
+		Items:     items,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := s.validateTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// GetTemplate retrieves an allocation template by ID
+func (s *AllocationTemplateService) GetTemplate(ctx context.Context, id string) (*domain.AllocationTemplate, error) {
+	return s.templateRepo.GetByID(ctx, id)
+}
+
+// ListTemplates retrieves all allocation templates by name
+func (s *AllocationTemplateService) ListTemplates(ctx context.Context) ([]*domain.AllocationTemplate, error) {
+	return s.templateRepo.List(ctx)
+}
+
+// UpdateTemplate renames a template and/or replaces its items
+// A nil name or nil items are left alone.
+func (s *AllocationTemplateService) UpdateTemplate(ctx context.Context, id string, name *string, items []domain.AllocationTemplateItem) (*domain.AllocationTemplate, error) {
+	template, err := s.templateRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name != nil {
+		template.Name = strings.TrimSpace(*name)
+	}
+	if items != nil {
+		template.Items = items
+	}
+	if err := s.validateTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+	template.UpdatedAt = time.Now()
+
+	if err := s.templateRepo.Update(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// DeleteTemplate removes a template; allocations made from it are untouched
+func (s *AllocationTemplateService) DeleteTemplate(ctx context.Context, id string) error {
+	return s.templateRepo.Delete(ctx, id)
+}
+
+// ApplyTemplateResult describes the allocations a template was applied to
+type ApplyTemplateResult struct {
+	Period        string               `json:"period"`
+	Allocations   []*domain.Allocation `json:"allocations"`
+	Skipped       []string             `json:"skipped"`         // IDs of template categories that no longer exist
+	TotalApplied  int64                `json:"total_applied"`   // Sum of the template amounts assigned
+	ReadyToAssign int64                `json:"ready_to_assign"` // After applying
+}
+
+// ApplyTemplate assigns a template's amounts to its categories for a period
+// By default each category's allocation is set to the template amount. With add, the
+// amounts go on top of what's already assigned, e.g. applying a paycheck plan once per
+// paycheck. All allocations change together or not at all. Like CreateAllocation, Ready
+// to Assign isn't checked; the result reports what is left.
+func (s *AllocationTemplateService) ApplyTemplate(ctx context.Context, id, period string, add bool) (*ApplyTemplateResult, error) {
+	if _, err := time.Parse("2006-01", period); err != nil {
+		return nil, fmt.Errorf("invalid period format, expected YYYY-MM")
+	}
+	template, err := s.templateRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ApplyTemplateResult{Period: period, Allocations: []*domain.Allocation{}, Skipped: []string{}}
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		for _, item := range template.Items {
+			if _, err := s.categoryRepo.GetByID(ctx, item.CategoryID); err != nil {
+				result.Skipped = append(result.Skipped, item.CategoryID)
+				continue
+			}
+			allocation, err := s.applyItem(ctx, item, period, template.Name, add)
+			if err != nil {
+				return err
+			}
+			result.Allocations = append(result.Allocations, allocation)
+			result.TotalApplied += item.Amount
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.ReadyToAssign, err = s.allocationService.CalculateReadyToAssignForPeriod(ctx, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate ready to assign: %w", err)
+	}
+	return result, nil
+}
+
+func (s *AllocationTemplateService) applyItem(ctx context.Context, item domain.AllocationTemplateItem, period, templateName string, add bool) (*domain.Allocation, error) {
+	now := time.Now()
+	notes := "Applied " + templateName
+	allocation, err := s.allocationRepo.GetByCategoryAndPeriod(ctx, item.CategoryID, period)
+	if err != nil {
+		allocation = &domain.Allocation{
+			ID:         uuid.New().String(),
+			CategoryID: item.CategoryID,
+			Amount:     item.Amount,
+			Period:     period,
+			Notes:      notes,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		if err := s.allocationRepo.Create(ctx, allocation); err != nil {
+			return nil, fmt.Errorf("failed to create allocation: %w", err)
+		}
+		return allocation, nil
+	}
+
+	if add {
+		allocation.Amount += item.Amount
+	} else {
+		allocation.Amount = item.Amount
+	}
+	allocation.Notes = notes
+	allocation.UpdatedAt = now
+	if err := s.allocationRepo.Update(ctx, allocation); err != nil {
+		return nil, fmt.Errorf("failed to update allocation: %w", err)
+	}
+	return allocation, nil
+}
+
+func (s *AllocationTemplateService) validateTemplate(ctx context.Context, template *domain.AllocationTemplate) error {
+	if template.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(template.Items) == 0 {
+		return fmt.Errorf("a template needs at least one category")
+	}
+	seen := make(map[string]bool, len(template.Items))
+	for _, item := range template.Items {
+		if item.Amount <= 0 {
+			return fmt.Errorf("template amounts must be positive")
+		}
+		if seen[item.CategoryID] {
+			return fmt.Errorf("a category can only appear once in a template")
+		}
+		seen[item.CategoryID] = true
+		if _, err := s.categoryRepo.GetByID(ctx, item.CategoryID); err != nil {
+			return domain.ErrCategoryNotFound
+		}
+	}
+
+	templates, err := s.templateRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, other := range templates {
+		if other.ID != template.ID && strings.EqualFold(other.Name, template.Name) {
+			return domain.ErrAllocationTemplateExists
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type mockAllocationTemplateRepository struct {
+	templates []*domain.AllocationTemplate
+}
+
+func (m *mockAllocationTemplateRepository) Create(ctx context.Context, template *domain.AllocationTemplate) error {
+	m.templates = append(m.templates, template)
+	return nil
+}
+
+func (m *mockAllocationTemplateRepository) GetByID(ctx context.Context, id string) (*domain.AllocationTemplate, error) {
+	for _, template := range m.templates {
+		if template.ID == id {
+			return template, nil
+		}
+	}
+	return nil, domain.ErrAllocationTemplateNotFound
+}
+
+func (m *mockAllocationTemplateRepository) List(ctx context.Context) ([]*domain.AllocationTemplate, error) {
+	return m.templates, nil
+}
+
+func (m *mockAllocationTemplateRepository) Update(ctx context.Context, template *domain.AllocationTemplate) error {
+	return nil
+}
+
+func (m *mockAllocationTemplateRepository) Delete(ctx context.Context, id string) error {
+	return nil
+}
+
+func TestAllocationTemplateService(t *testing.T) {
+	ctx := context.Background()
+	categoryRepo := newMockCategoryRepository()
+	categoryRepo.categories["rent"] = &domain.Category{ID: "rent", Name: "Rent"}
+	categoryRepo.categories["groceries"] = &domain.Category{ID: "groceries", Name: "Groceries"}
+	allocationRepo := newMockAllocationRepository()
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a1", CategoryID: "groceries", Period: "2025-11", Amount: 10000})
+	accountRepo := newMockAccountRepository(0)
+	transactionRepo := newMockTransactionRepository()
+	allocationService := NewAllocationService(allocationRepo, categoryRepo, transactionRepo, newMockBudgetStateRepository(0, 0),
+		accountRepo, newMockCategoryGroupRepository(), newMockGoalRepository())
+	service := NewAllocationTemplateService(&mockAllocationTemplateRepository{}, categoryRepo, allocationRepo, allocationService,
+		&mockUnitOfWork{accountRepo, transactionRepo})
+
+	paycheck, err := service.CreateTemplate(ctx, " Paycheck ", []domain.AllocationTemplateItem{
+		{CategoryID: "rent", Amount: 75000},
+		{CategoryID: "groceries", Amount: 20000},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paycheck.Name != "Paycheck" {
+		t.Errorf("expected the name to be trimmed, got %q", paycheck.Name)
+	}
+
+	for _, items := range [][]domain.AllocationTemplateItem{
+		nil,
+		{{CategoryID: "rent", Amount: 0}},
+		{{CategoryID: "rent", Amount: 100}, {CategoryID: "rent", Amount: 200}},
+		{{CategoryID: "missing", Amount: 100}},
+	} {
+		if _, err := service.CreateTemplate(ctx, "Other", items); err == nil {
+			t.Errorf("expected template items %+v to be rejected", items)
+		}
+	}
+	if _, err := service.CreateTemplate(ctx, "paycheck", paycheck.Items); !errors.Is(err, domain.ErrAllocationTemplateExists) {
+		t.Errorf("expected a clashing name to be rejected, got %v", err)
+	}
+
+	amounts := func() (rent, groceries int64) {
+		if a, err := allocationRepo.GetByCategoryAndPeriod(ctx, "rent", "2025-11"); err == nil {
+			rent = a.Amount
+		}
+		if a, err := allocationRepo.GetByCategoryAndPeriod(ctx, "groceries", "2025-11"); err == nil {
+			groceries = a.Amount
+		}
+		return rent, groceries
+	}
+
+	result, err := service.ApplyTemplate(ctx, paycheck.ID, "2025-11", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rent, groceries := amounts(); rent != 75000 || groceries != 20000 || result.TotalApplied != 95000 || len(result.Allocations) != 2 {
+		t.Errorf("expected the template amounts to replace the allocations, got rent %d, groceries %d, %+v", rent, groceries, result)
+	}
+
+	// Applied again for the second paycheck of the month
+	if _, err := service.ApplyTemplate(ctx, paycheck.ID, "2025-11", true); err != nil {
+		t.Fatal(err)
+	}
+	if rent, groceries := amounts(); rent != 150000 || groceries != 40000 {
+		t.Errorf("expected the template amounts to be added, got rent %d, groceries %d", rent, groceries)
+	}
+
+	// Categories deleted since the template was saved are skipped
+	delete(categoryRepo.categories, "rent")
+	result, err = service.ApplyTemplate(ctx, paycheck.ID, "2025-12", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0] != "rent" || len(result.Allocations) != 1 {
+		t.Errorf("expected the deleted category to be skipped, got %+v", result)
+	}
+}
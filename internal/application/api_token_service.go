@@ -0,0 +1,182 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/google/uuid"
+)
+
+// apiTokenPrefix marks API tokens so they are recognizable in configs and logs
+const apiTokenPrefix = "bgt_"
+
+// APITokenService manages API tokens and their scopes
+type APITokenService struct {
+	tokenRepo    domain.APITokenRepository
+	accountRepo  domain.AccountRepository
+	categoryRepo domain.CategoryRepository
+
+	defaultDailyWrites int // Quota for tokens without their own; 0 = unlimited
+}
+
+// NewAPITokenService creates a new API token service
+func NewAPITokenService(
+	tokenRepo domain.APITokenRepository,
+	accountRepo domain.AccountRepository,
+	categoryRepo domain.CategoryRepository,
+	defaultDailyWrites int,
+) *APITokenService {
+	return &APITokenService{
+		tokenRepo:          tokenRepo,
+		accountRepo:        accountRepo,
+		categoryRepo:       categoryRepo,
+		defaultDailyWrites: defaultDailyWrites,
+	}
+}
+
+// CreateToken creates a new API token and returns it along with the raw token
+// The raw token is only available here; only its hash is stored. A dailyWriteQuota of
+// 0 leaves the token on the instance default.
+func (s *APITokenService) CreateToken(ctx context.Context, userID *string, name string, access domain.TokenAccess, accountIDs, categoryIDs []string, expiresAt *time.Time, dailyWriteQuota int) (*domain.APIToken, string, error) {
+	if name == "" {
+		return nil, "", fmt.Errorf("token name is required")
+	}
+	if dailyWriteQuota < 0 {
+		return nil, "", fmt.Errorf("daily_write_quota cannot be negative")
+	}
+	if !access.IsValid() {
+		return nil, "", fmt.Errorf("access must be read, write, or admin")
+	}
+	if accountIDs == nil {
+		accountIDs = []string{}
+	}
+	if categoryIDs == nil {
+		categoryIDs = []string{}
+	}
+	if access == domain.TokenAccessAdmin && (len(accountIDs) > 0 || len(categoryIDs) > 0) {
+		return nil, "", fmt.Errorf("admin tokens cannot be scoped to accounts or categories")
+	}
+	if expiresAt != nil && expiresAt.Before(time.Now()) {
+		return nil, "", fmt.Errorf("expires_at must be in the future")
+	}
+
+	for _, id := range accountIDs {
+		if _, err := s.accountRepo.GetByID(ctx, id); err != nil {
+			return nil, "", fmt.Errorf("account not found: %s", id)
+		}
+	}
+	for _, id := range categoryIDs {
+		if _, err := s.categoryRepo.GetByID(ctx, id); err != nil {
+			return nil, "", fmt.Errorf("category not found: %s", id)
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	raw := apiTokenPrefix + hex.EncodeToString(secret)
+
+	token := &domain.APIToken{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		Name:        name,
+		TokenPrefix: raw[:len(apiTokenPrefix)+6],
+		TokenHash:   hashAPIToken(raw),
+		Access:      access,
+		AccountIDs:  accountIDs,
+		CategoryIDs: categoryIDs,
+		ExpiresAt:   expiresAt,
+		CreatedAt:   time.Now(),
+
+		DailyWriteQuota: dailyWriteQuota,
+	}
+
+	if err := s.tokenRepo.Create(ctx, token); err != nil {
+		return nil, "", err
+	}
+
+	return token, raw, nil
+}
+
+// ListTokens lists all API tokens (without their secrets)
+func (s *APITokenService) ListTokens(ctx context.Context) ([]*domain.APIToken, error) {
+	return s.tokenRepo.List(ctx)
+}
+
+// DeleteToken revokes an API token
+func (s *APITokenService) DeleteToken(ctx context.Context, id string) error {
+	return s.tokenRepo.Delete(ctx, id)
+}
+
+// DisableToken switches a token off without deleting it, e.g. to stop a runaway integration
+// The token keeps its name and scopes so it can be enabled again once the client is fixed.
+func (s *APITokenService) DisableToken(ctx context.Context, id string) (*domain.APIToken, error) {
+	now := time.Now()
+	if err := s.tokenRepo.SetDisabled(ctx, id, &now); err != nil {
+		return nil, err
+	}
+	return s.tokenRepo.GetByID(ctx, id)
+}
+
+// EnableToken switches a disabled token back on
+func (s *APITokenService) EnableToken(ctx context.Context, id string) (*domain.APIToken, error) {
+	if err := s.tokenRepo.SetDisabled(ctx, id, nil); err != nil {
+		return nil, err
+	}
+	return s.tokenRepo.GetByID(ctx, id)
+}
+
+// RecordWrite counts a write request against the token's daily quota
+// Days are UTC. Returns domain.ErrWriteQuotaExceeded once the quota is used up; the
+// write is refused and not counted. The configured admin token has no ID and no quota.
+func (s *APITokenService) RecordWrite(ctx context.Context, token *domain.APIToken) error {
+	if token.ID == "" {
+		return nil
+	}
+	quota := token.DailyWriteQuota
+	if quota == 0 {
+		quota = s.defaultDailyWrites
+	}
+	allowed, err := s.tokenRepo.RecordWrite(ctx, token.ID, time.Now().UTC().Format("2006-01-02"), quota)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return domain.ErrWriteQuotaExceeded
+	}
+	return nil
+}
+
+// Authenticate resolves a raw token to its API token and records its use
+func (s *APITokenService) Authenticate(ctx context.Context, raw string) (*domain.APIToken, error) {
+	token, err := s.tokenRepo.GetByHash(ctx, hashAPIToken(raw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid API token")
+	}
+
+	now := time.Now()
+	if token.IsExpired(now) {
+		return nil, fmt.Errorf("API token has expired")
+	}
+	if token.IsDisabled() {
+		return nil, fmt.Errorf("API token has been disabled")
+	}
+
+	if err := s.tokenRepo.UpdateLastUsed(ctx, token.ID, now); err != nil {
+		return nil, err
+	}
+	token.LastUsedAt = &now
+
+	return token, nil
+}
+
+func hashAPIToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,128 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type mockAPITokenRepository struct {
+	tokens map[string]*domain.APIToken
+}
+
+func (m *mockAPITokenRepository) Create(ctx context.Context, token *domain.APIToken) error {
+	m.tokens[token.ID] = token
+	return nil
+}
+
+func (m *mockAPITokenRepository) GetByID(ctx context.Context, id string) (*domain.APIToken, error) {
+	token, ok := m.tokens[id]
+	if !ok {
+		return nil, errors.New("API token not found")
+	}
+	return token, nil
+}
+
+func (m *mockAPITokenRepository) GetByHash(ctx context.Context, tokenHash string) (*domain.APIToken, error) {
+	for _, token := range m.tokens {
+		if token.TokenHash == tokenHash {
+			return token, nil
+		}
+	}
+	return nil, errors.New("API token not found")
+}
+
+func (m *mockAPITokenRepository) List(ctx context.Context) ([]*domain.APIToken, error) {
+	var tokens []*domain.APIToken
+	for _, token := range m.tokens {
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+func (m *mockAPITokenRepository) UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error {
+	return nil
+}
+
+func (m *mockAPITokenRepository) RecordWrite(ctx context.Context, id, day string, quota int) (bool, error) {
+	token := m.tokens[id]
+	if token.WriteCountDate != day {
+		token.WriteCount, token.WriteCountDate = 0, day
+	}
+	if quota > 0 && token.WriteCount >= quota {
+		return false, nil
+	}
+	token.WriteCount++
+	return true, nil
+}
+
+func (m *mockAPITokenRepository) SetDisabled(ctx context.Context, id string, disabledAt *time.Time) error {
+	token, ok := m.tokens[id]
+	if !ok {
+		return errors.New("API token not found")
+	}
+	token.DisabledAt = disabledAt
+	return nil
+}
+
+func (m *mockAPITokenRepository) Delete(ctx context.Context, id string) error {
+	delete(m.tokens, id)
+	return nil
+}
+
+func TestAPITokenService_Guardrails(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockAPITokenRepository{tokens: make(map[string]*domain.APIToken)}
+	service := NewAPITokenService(repo, newMockAccountRepository(0), newMockCategoryRepository(), 3)
+
+	if _, _, err := service.CreateToken(ctx, nil, "bad", domain.TokenAccessWrite, nil, nil, nil, -1); err == nil {
+		t.Error("expected a negative quota to be rejected")
+	}
+
+	// Tokens without their own quota get the instance default
+	defaulted, _, err := service.CreateToken(ctx, nil, "importer", domain.TokenAccessWrite, nil, nil, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	own, raw, err := service.CreateToken(ctx, nil, "shortcut", domain.TokenAccessWrite, nil, nil, nil, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for token, quota := range map[*domain.APIToken]int{defaulted: 3, own: 5} {
+		for i := 0; i < quota; i++ {
+			if err := service.RecordWrite(ctx, token); err != nil {
+				t.Fatalf("%s: write %d refused: %v", token.Name, i+1, err)
+			}
+		}
+		if err := service.RecordWrite(ctx, token); !errors.Is(err, domain.ErrWriteQuotaExceeded) {
+			t.Errorf("%s: expected write %d to exceed the quota, got %v", token.Name, quota+1, err)
+		}
+	}
+
+	// A new day starts a new count
+	own.WriteCountDate = "2000-01-01"
+	if err := service.RecordWrite(ctx, own); err != nil {
+		t.Errorf("expected the quota to reset on a new day, got %v", err)
+	}
+
+	// The configured admin token has no ID and is never limited
+	if err := service.RecordWrite(ctx, &domain.APIToken{Access: domain.TokenAccessAdmin}); err != nil {
+		t.Errorf("expected the configured admin token to be unlimited, got %v", err)
+	}
+
+	if _, err := service.DisableToken(ctx, own.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Authenticate(ctx, raw); err == nil {
+		t.Error("expected a disabled token to be refused")
+	}
+	if _, err := service.EnableToken(ctx, own.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Authenticate(ctx, raw); err != nil {
+		t.Errorf("expected an enabled token to work again, got %v", err)
+	}
+}
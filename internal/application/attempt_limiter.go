@@ -0,0 +1,76 @@
+package application
+
+import (
+	"sync"
+	"time"
+)
+
+// attemptLimiterSweepSize is how many keys are kept before expired ones are cleared out
+const attemptLimiterSweepSize = 1024
+
+// attemptLimiter counts failed attempts by key, such as an email or client address
+// A key that has failed limit times is refused until window has passed since its first
+// failure. Counts are only kept in memory, so a restart clears them.
+type attemptLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu       sync.Mutex
+	failures map[string]*failedAttempts
+}
+
+// failedAttempts is one key's failures in its current window
+type failedAttempts struct {
+	count int
+	since time.Time // The first failure in the window
+}
+
+func newAttemptLimiter(limit int, window time.Duration) *attemptLimiter {
+	return &attemptLimiter{
+		limit:    limit,
+		window:   window,
+		failures: make(map[string]*failedAttempts),
+	}
+}
+
+// blocked reports whether key has used up its attempts for now
+func (l *attemptLimiter) blocked(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	failures, ok := l.failures[key]
+	if !ok {
+		return false
+	}
+	if time.Since(failures.since) >= l.window {
+		delete(l.failures, key)
+		return false
+	}
+	return failures.count >= l.limit
+}
+
+// fail records a failed attempt for key
+func (l *attemptLimiter) fail(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if failures, ok := l.failures[key]; ok && now.Sub(failures.since) < l.window {
+		failures.count++
+		return
+	}
+
+	if len(l.failures) >= attemptLimiterSweepSize {
+		for other, failures := range l.failures {
+			if now.Sub(failures.since) >= l.window {
+				delete(l.failures, other)
+			}
+		}
+	}
+	l.failures[key] = &failedAttempts{count: 1, since: now}
+}
+
+// reset forgets key's failures, after it succeeds
+func (l *attemptLimiter) reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.failures, key)
+}
@@ -0,0 +1,136 @@
+package application
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+const (
+	// DefaultAuditPageSize is the page size used when the client doesn't ask for one
+	DefaultAuditPageSize = 50
+
+	// MaxAuditPageSize caps the page size clients can ask for
+	MaxAuditPageSize = 200
+)
+
+// AuditPage is one page of the audit log, newest first
+// NextCursor is empty on the last page.
+type AuditPage struct {
+	Entries    []*domain.AuditEntry `json:"entries"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+// AuditService records changes made through the API and serves them back a page at a time
+// Old entries are pruned by the RetentionService.
+type AuditService struct {
+	auditRepo domain.AuditRepository
+	listeners []func(ctx context.Context, entry *domain.AuditEntry)
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(auditRepo domain.AuditRepository) *AuditService {
+	return &AuditService{auditRepo: auditRepo}
+}
+
+// Subscribe registers a function called for every recorded entry
+// Listeners run in the background after the entry is saved, so they can't slow down requests.
+// Call before the server starts.
+func (s *AuditService) Subscribe(listener func(ctx context.Context, entry *domain.AuditEntry)) {
+	s.listeners = append(s.listeners, listener)
+}
+
+// Record writes an audit entry for a request
+// principal is nil for requests made without a credential. Failures are logged rather
+// than returned, since the change itself has already happened.
+func (s *AuditService) Record(ctx context.Context, principal *Principal, method, route, path string, status int, ip string) {
+	entry := &domain.AuditEntry{
+		Actor:     "anonymous",
+		Method:    method,
+		Route:     route,
+		Path:      path,
+		Status:    status,
+		IPAddress: ip,
+		CreatedAt: time.Now().UTC(),
+	}
+	if principal != nil {
+		entry.UserID = principal.UserID()
+		switch {
+		case principal.User != nil:
+			entry.Actor = principal.User.Email
+		case principal.Token != nil:
+			entry.Actor = principal.Token.Name
+		}
+		if principal.Token != nil && principal.Token.ID != "" {
+			entry.TokenID = &principal.Token.ID
+		}
+	}
+
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		log.Printf("audit: %v", err)
+		return
+	}
+
+	// The request is finished by now, so listeners must not inherit its cancellation
+	ctx = context.WithoutCancel(ctx)
+	for _, listener := range s.listeners {
+		go listener(ctx, entry)
+	}
+}
+
+// List returns a page of audit entries, newest first
+// cursor is the NextCursor of the previous page, or empty for the first page. limit is
+// capped at MaxAuditPageSize. since and until optionally bound the entries' times.
+func (s *AuditService) List(ctx context.Context, cursor string, limit int, since, until *time.Time) (*AuditPage, error) {
+	query := domain.AuditQuery{Since: since, Until: until, Limit: limit}
+	if query.Limit <= 0 {
+		query.Limit = DefaultAuditPageSize
+	}
+	if query.Limit > MaxAuditPageSize {
+		query.Limit = MaxAuditPageSize
+	}
+	if cursor != "" {
+		beforeID, err := decodeAuditCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		query.BeforeID = beforeID
+	}
+
+	// Ask for one extra entry to learn whether there's another page
+	query.Limit++
+	entries, err := s.auditRepo.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &AuditPage{Entries: entries}
+	if len(entries) == query.Limit {
+		page.Entries = entries[:len(entries)-1]
+		page.NextCursor = encodeAuditCursor(page.Entries[len(page.Entries)-1].ID)
+	}
+	if page.Entries == nil {
+		page.Entries = []*domain.AuditEntry{}
+	}
+	return page, nil
+}
+
+// Cursors are opaque to clients so the paging scheme can change later
+func encodeAuditCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func decodeAuditCursor(cursor string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		if id, err := strconv.ParseInt(string(raw), 10, 64); err == nil && id > 0 {
+			return id, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid cursor")
+}
@@ -0,0 +1,166 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// mockAuditRepository keeps entries in insertion order, like the autoincrement ID
+type mockAuditRepository struct {
+	entries []*domain.AuditEntry
+}
+
+func (m *mockAuditRepository) Create(ctx context.Context, entry *domain.AuditEntry) error {
+	entry.ID = int64(len(m.entries) + 1)
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func (m *mockAuditRepository) List(ctx context.Context, query domain.AuditQuery) ([]*domain.AuditEntry, error) {
+	var entries []*domain.AuditEntry
+	for i := len(m.entries) - 1; i >= 0 && len(entries) < query.Limit; i-- {
+		entry := m.entries[i]
+		if query.BeforeID > 0 && entry.ID >= query.BeforeID {
+			continue
+		}
+		if query.Since != nil && entry.CreatedAt.Before(*query.Since) {
+			continue
+		}
+		if query.Until != nil && entry.CreatedAt.After(*query.Until) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (m *mockAuditRepository) CountBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	var count int64
+	for _, entry := range m.entries {
+		if entry.CreatedAt.Before(cutoff) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockAuditRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	var kept []*domain.AuditEntry
+	for _, entry := range m.entries {
+		if !entry.CreatedAt.Before(cutoff) {
+			kept = append(kept, entry)
+		}
+	}
+	deleted := int64(len(m.entries) - len(kept))
+	m.entries = kept
+	return deleted, nil
+}
+
+func TestAuditService_ListPages(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockAuditRepository{}
+	service := NewAuditService(repo)
+
+	user := &Principal{User: &domain.User{ID: "user-1", Email: "sam@example.com"}}
+	for i := 0; i < 5; i++ {
+		service.Record(ctx, user, "POST", "POST /api/accounts", "/api/accounts", 201, "127.0.0.1")
+	}
+
+	first, err := service.List(ctx, "", 2, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Entries) != 2 || first.Entries[0].ID != 5 || first.NextCursor == "" {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+	if first.Entries[0].Actor != "sam@example.com" || first.Entries[0].UserID == nil || *first.Entries[0].UserID != "user-1" {
+		t.Errorf("expected entry to record the user, got %+v", first.Entries[0])
+	}
+
+	var ids []int64
+	cursor := ""
+	for {
+		page, err := service.List(ctx, cursor, 2, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range page.Entries {
+			ids = append(ids, entry.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if len(ids) != 5 || ids[0] != 5 || ids[4] != 1 {
+		t.Errorf("expected every entry newest first, got %v", ids)
+	}
+
+	if _, err := service.List(ctx, "not-a-cursor", 2, nil, nil); err == nil {
+		t.Error("expected an invalid cursor to fail")
+	}
+}
+
+func TestAuditService_PageSizeIsCapped(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockAuditRepository{}
+	service := NewAuditService(repo)
+	for i := 0; i < MaxAuditPageSize+10; i++ {
+		service.Record(ctx, nil, "DELETE", "DELETE /api/accounts/{id}", "/api/accounts/x", 204, "127.0.0.1")
+	}
+
+	page, err := service.List(ctx, "", 1000, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Entries) != MaxAuditPageSize || page.NextCursor == "" {
+		t.Errorf("expected a capped page of %d, got %d", MaxAuditPageSize, len(page.Entries))
+	}
+	if page.Entries[0].Actor != "anonymous" {
+		t.Errorf("expected anonymous actor, got %q", page.Entries[0].Actor)
+	}
+}
+
+func TestAuditService_Activity(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockAuditRepository{}
+	service := NewAuditService(repo)
+
+	user := &Principal{User: &domain.User{ID: "user-1", Email: "sam@example.com"}}
+	service.Record(ctx, user, "POST", "POST /api/transactions", "/api/transactions", 201, "127.0.0.1")
+	service.Record(ctx, user, "PUT", "PUT /api/transactions/{id}", "/api/transactions/txn-1", 200, "127.0.0.1")
+	service.Record(ctx, user, "PUT", "PUT /api/transactions/{id}", "/api/transactions/txn-2", 400, "127.0.0.1")
+	service.Record(ctx, user, "POST", "POST /api/tokens", "/api/tokens", 201, "127.0.0.1")
+	service.Record(ctx, user, "DELETE", "DELETE /api/categories/{id}", "/api/categories/cat-1", 204, "127.0.0.1")
+
+	first, err := service.Activity(ctx, "", 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Items) != 2 || first.NextCursor == "" {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+	deleted, updated := first.Items[0], first.Items[1]
+	if deleted.Action != ActivityDeleted || deleted.EntityType != "category" || deleted.EntityID != "cat-1" || deleted.Link != "/api/categories" {
+		t.Errorf("expected the category delete first, linking to the list, got %+v", deleted)
+	}
+	if updated.Action != ActivityUpdated || updated.EntityID != "txn-1" || updated.Link != "/api/transactions/txn-1" || updated.Actor != "sam@example.com" {
+		t.Errorf("expected the transaction update to link to the transaction, got %+v", updated)
+	}
+
+	second, err := service.Activity(ctx, first.NextCursor, 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second.Items) != 1 || second.NextCursor != "" || second.Items[0].Action != ActivityCreated || second.Items[0].EntityID != "" {
+		t.Errorf("expected only the transaction create on the last page, got %+v", second)
+	}
+
+	future := time.Now().Add(time.Hour)
+	if page, err := service.Activity(ctx, "", 0, &future); err != nil || len(page.Items) != 0 {
+		t.Errorf("expected nothing since a future time, got %+v, %v", page, err)
+	}
+}
@@ -0,0 +1,116 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// Principal is whoever an API request was authenticated as
+// Either User or Token is set. Session is set for users signed in with a session,
+// and nil for users authenticated by a trusted reverse proxy.
+type Principal struct {
+	User    *domain.User
+	Session *domain.Session
+	Token   *domain.APIToken
+}
+
+// Access returns the access level the principal has
+func (p *Principal) Access() domain.TokenAccess {
+	if p.Token != nil {
+		return p.Token.Access
+	}
+	return p.User.Role.Access()
+}
+
+// UserID returns the signed-in user's ID, or the token owner's ID, if any
+func (p *Principal) UserID() *string {
+	if p.User != nil {
+		return &p.User.ID
+	}
+	if p.Token != nil {
+		return p.Token.UserID
+	}
+	return nil
+}
+
+// SessionID returns the current session's ID, or "" when there is no session
+func (p *Principal) SessionID() string {
+	if p.Session == nil {
+		return ""
+	}
+	return p.Session.ID
+}
+
+// Operation is an API call to authorize, described the same way whichever API made it
+type Operation struct {
+	Pattern    string  // REST route pattern, e.g. "GET /api/accounts/{id}"; gRPC calls use the route they mirror
+	AccountID  string  // Account the call reads, or creates a transaction in; "" when it names none
+	CategoryID *string // Category the call reads, or categorizes a new transaction in
+}
+
+// Can checks that the principal may make an API call
+// The access level comes from the method, and tokens, users and admin routes need admin.
+// Users who must set up two-factor can only manage their own sign-in. Tokens scoped to
+// accounts or categories may only make calls whose account and category can be checked.
+func (p *Principal) Can(op Operation) error {
+	method, _, _ := strings.Cut(op.Pattern, " ")
+	need := domain.TokenAccessWrite
+	if method == "GET" || method == "HEAD" {
+		need = domain.TokenAccessRead
+	}
+	if strings.Contains(op.Pattern, " /api/tokens") || strings.Contains(op.Pattern, " /api/users") || strings.Contains(op.Pattern, " /api/admin/") {
+		need = domain.TokenAccessAdmin
+	}
+	if !p.Access().Includes(need) {
+		return fmt.Errorf("%s access is required", need)
+	}
+
+	// Users required to use two-factor must enroll before doing anything else
+	if p.User != nil && p.User.NeedsTOTPEnrollment() && !strings.Contains(op.Pattern, " /api/auth/") {
+		return fmt.Errorf("two-factor authentication must be set up before using this account")
+	}
+
+	token := p.Token
+	if token == nil || !token.IsScoped() {
+		return nil
+	}
+	switch op.Pattern {
+	case "GET /api/accounts/{id}", "GET /api/accounts/{id}/transactions":
+		if !token.AllowsAccount(op.AccountID) {
+			return fmt.Errorf("token is not allowed to access this account")
+		}
+		return nil
+
+	case "GET /api/categories/{id}":
+		if op.CategoryID == nil || !token.AllowsCategory(*op.CategoryID) {
+			return fmt.Errorf("token is not allowed to access this category")
+		}
+		return nil
+
+	case "POST /api/transactions":
+		if !token.AllowsAccount(op.AccountID) {
+			return fmt.Errorf("token is not allowed to create transactions in this account")
+		}
+		if len(token.CategoryIDs) > 0 && (op.CategoryID == nil || !token.AllowsCategory(*op.CategoryID)) {
+			return fmt.Errorf("token is not allowed to use this category")
+		}
+		return nil
+	}
+	return fmt.Errorf("token is scoped to specific accounts/categories and cannot use this endpoint")
+}
+
+type principalContextKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated principal, if the request had one
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(*Principal)
+	return principal, ok
+}
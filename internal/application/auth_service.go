@@ -0,0 +1,620 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// sessionTokenPrefix marks session tokens so they can be told apart from API tokens
+	sessionTokenPrefix = "bgs_"
+
+	// SessionLifetime is how long a session stays valid without being used
+	SessionLifetime = 30 * 24 * time.Hour
+
+	// sessionTouchInterval limits how often last-used times are written
+	sessionTouchInterval = time.Minute
+
+	minPasswordLength = 8
+
+	// totpIssuer is the account issuer shown in authenticator apps
+	totpIssuer = "Budget"
+
+	recoveryCodeCount = 10
+
+	// signInWindow is how long failed sign-ins are counted for
+	signInWindow = 15 * time.Minute
+
+	// signInAttemptsPerAccount is how many wrong passwords an email may be given in
+	// signInWindow before sign-ins for it are refused
+	signInAttemptsPerAccount = 10
+
+	// signInAttemptsPerAddress is the same for a client address, across every email it tries
+	signInAttemptsPerAddress = 50
+
+	// twoFactorAttempts is how many wrong two-factor codes a user may enter in signInWindow,
+	// wherever they're asked for one
+	twoFactorAttempts = 5
+)
+
+// ErrTwoFactorRequired is returned by Login when the password is correct but a
+// TOTP or recovery code is still needed
+var ErrTwoFactorRequired = errors.New("two-factor code required")
+
+// ErrTooManySignInAttempts is returned by Login when the email or the client address has
+// failed to sign in too often lately
+var ErrTooManySignInAttempts = errors.New("too many sign-in attempts, please try again later")
+
+// ErrTooManyTwoFactorAttempts is returned when a user has entered too many wrong two-factor
+// codes lately; until the window passes even a right code is refused
+var ErrTooManyTwoFactorAttempts = errors.New("too many wrong two-factor codes, please try again later")
+
+// dummyPasswordHash is checked against when a sign-in has no password to compare with, so
+// an unknown email takes as long to refuse as a wrong password
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("not a password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// AuthService handles users, password sign-in and sessions
+type AuthService struct {
+	userRepo     domain.UserRepository
+	sessionRepo  domain.SessionRepository
+	tokenRepo    domain.APITokenRepository
+	recoveryRepo domain.RecoveryCodeRepository
+
+	// Failed sign-ins, by email and by client address, and wrong two-factor codes by user ID
+	accountSignIns *attemptLimiter
+	addressSignIns *attemptLimiter
+	secondFactors  *attemptLimiter
+}
+
+// NewAuthService creates a new auth service
+func NewAuthService(
+	userRepo domain.UserRepository,
+	sessionRepo domain.SessionRepository,
+	tokenRepo domain.APITokenRepository,
+	recoveryRepo domain.RecoveryCodeRepository,
+) *AuthService {
+	return &AuthService{
+		userRepo:     userRepo,
+		sessionRepo:  sessionRepo,
+		tokenRepo:    tokenRepo,
+		recoveryRepo: recoveryRepo,
+
+		accountSignIns: newAttemptLimiter(signInAttemptsPerAccount, signInWindow),
+		addressSignIns: newAttemptLimiter(signInAttemptsPerAddress, signInWindow),
+		secondFactors:  newAttemptLimiter(twoFactorAttempts, signInWindow),
+	}
+}
+
+// EnsureAdminUser creates an admin user with the given credentials if no users exist yet
+func (s *AuthService) EnsureAdminUser(ctx context.Context, email, password string) (bool, error) {
+	count, err := s.userRepo.Count(ctx)
+	if err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil
+	}
+
+	if _, err := s.CreateUser(ctx, email, "Admin", password, domain.UserRoleAdmin); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateUser creates a user with a password
+func (s *AuthService) CreateUser(ctx context.Context, email, name, password string, role domain.UserRole) (*domain.User, error) {
+	email = strings.TrimSpace(email)
+	if !strings.Contains(email, "@") {
+		return nil, fmt.Errorf("a valid email is required")
+	}
+	if !role.IsValid() {
+		return nil, fmt.Errorf("role must be admin or member")
+	}
+	if _, err := s.userRepo.GetByEmail(ctx, email); err == nil {
+		return nil, fmt.Errorf("a user with this email already exists")
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user := &domain.User{
+		ID:           uuid.New().String(),
+		Email:        email,
+		Name:         name,
+		PasswordHash: hash,
+		Role:         role,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Login verifies a password (and, when two-factor is enabled, a TOTP or recovery code)
+// and starts a new session
+// Returns the session, the raw session token (shown once) and the user. Too many wrong
+// passwords for the email or from the address refuse further tries for a while.
+func (s *AuthService) Login(ctx context.Context, email, password, code, userAgent, ipAddress string) (*domain.Session, string, *domain.User, error) {
+	email = strings.TrimSpace(email)
+	account := strings.ToLower(email)
+	if s.accountSignIns.blocked(account) || s.addressSignIns.blocked(ipAddress) {
+		return nil, "", nil, ErrTooManySignInAttempts
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	hash := dummyPasswordHash()
+	if err == nil && user.PasswordHash != "" {
+		hash = []byte(user.PasswordHash)
+	}
+	if bcrypt.CompareHashAndPassword(hash, []byte(password)) != nil || err != nil || user.PasswordHash == "" {
+		s.accountSignIns.fail(account)
+		s.addressSignIns.fail(ipAddress)
+		return nil, "", nil, fmt.Errorf("invalid email or password")
+	}
+	s.accountSignIns.reset(account)
+	if user.IsDisabled() {
+		return nil, "", nil, ErrUserDisabled
+	}
+
+	if user.TOTPEnabled {
+		if strings.TrimSpace(code) == "" {
+			return nil, "", nil, ErrTwoFactorRequired
+		}
+		if err := s.verifySecondFactor(ctx, user, code); err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	session, raw, err := s.StartSession(ctx, user, userAgent, ipAddress)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return session, raw, user, nil
+}
+
+// StartSession creates a session for an already-authenticated user
+func (s *AuthService) StartSession(ctx context.Context, user *domain.User, userAgent, ipAddress string) (*domain.Session, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", fmt.Errorf("failed to generate session token: %w", err)
+	}
+	raw := sessionTokenPrefix + hex.EncodeToString(secret)
+
+	now := time.Now()
+	session := &domain.Session{
+		ID:         uuid.New().String(),
+		UserID:     user.ID,
+		TokenHash:  hashSessionToken(raw),
+		Device:     describeDevice(userAgent),
+		UserAgent:  userAgent,
+		IPAddress:  ipAddress,
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(SessionLifetime),
+	}
+
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, "", err
+	}
+	return session, raw, nil
+}
+
+// IsSessionToken reports whether a bearer credential is a session token rather than an API token
+func IsSessionToken(raw string) bool {
+	return strings.HasPrefix(raw, sessionTokenPrefix)
+}
+
+// AuthenticateSession resolves a raw session token to its session and user
+// Sessions slide: each use (at most once a minute) pushes the expiry out again.
+func (s *AuthService) AuthenticateSession(ctx context.Context, raw string) (*domain.Session, *domain.User, error) {
+	session, err := s.sessionRepo.GetByHash(ctx, hashSessionToken(raw))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid session")
+	}
+
+	now := time.Now()
+	if now.After(session.ExpiresAt) {
+		s.sessionRepo.Delete(ctx, session.ID)
+		return nil, nil, fmt.Errorf("session has expired")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, session.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid session")
+	}
+	if user.IsDisabled() {
+		return nil, nil, ErrUserDisabled
+	}
+
+	if now.Sub(session.LastUsedAt) > sessionTouchInterval {
+		if err := s.sessionRepo.UpdateLastUsed(ctx, session.ID, now); err != nil {
+			return nil, nil, err
+		}
+		session.LastUsedAt = now
+	}
+
+	return session, user, nil
+}
+
+// Logout ends a session
+func (s *AuthService) Logout(ctx context.Context, sessionID string) error {
+	return s.sessionRepo.Delete(ctx, sessionID)
+}
+
+// ListSessions returns the user's active sessions and API tokens
+// currentSessionID marks which session is making the request.
+func (s *AuthService) ListSessions(ctx context.Context, userID, currentSessionID string) ([]*domain.Session, []*domain.APIToken, error) {
+	now := time.Now()
+	if err := s.sessionRepo.DeleteExpired(ctx, now); err != nil {
+		return nil, nil, err
+	}
+
+	sessions, err := s.sessionRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, session := range sessions {
+		session.Current = session.ID == currentSessionID
+	}
+	if sessions == nil {
+		sessions = []*domain.Session{}
+	}
+
+	tokens, err := s.userTokens(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sessions, tokens, nil
+}
+
+// RevokeSession revokes one of the user's sessions or API tokens by ID
+func (s *AuthService) RevokeSession(ctx context.Context, userID, id string) error {
+	sessions, err := s.sessionRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if session.ID == id {
+			return s.sessionRepo.Delete(ctx, id)
+		}
+	}
+
+	tokens, err := s.userTokens(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		if token.ID == id {
+			return s.tokenRepo.Delete(ctx, id)
+		}
+	}
+
+	return fmt.Errorf("session not found")
+}
+
+// RevokeAllSessions revokes every session and API token the user has, except exceptSessionID
+func (s *AuthService) RevokeAllSessions(ctx context.Context, userID, exceptSessionID string) error {
+	if err := s.sessionRepo.DeleteByUser(ctx, userID, exceptSessionID); err != nil {
+		return err
+	}
+
+	tokens, err := s.userTokens(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		if err := s.tokenRepo.Delete(ctx, token.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TOTPStatus describes a user's two-factor setup
+type TOTPStatus struct {
+	Enabled                bool `json:"enabled"`
+	Required               bool `json:"required"`
+	Pending                bool `json:"pending"` // Enrollment started but not yet confirmed with a code
+	RecoveryCodesRemaining int  `json:"recovery_codes_remaining"`
+}
+
+// TOTPEnrollment is the secret a user adds to their authenticator app
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"` // otpauth:// URI, suitable for a QR code
+}
+
+// GetTOTPStatus returns the user's two-factor status
+func (s *AuthService) GetTOTPStatus(ctx context.Context, user *domain.User) (*TOTPStatus, error) {
+	remaining := 0
+	if user.TOTPEnabled {
+		count, err := s.recoveryRepo.CountUnused(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		remaining = count
+	}
+
+	return &TOTPStatus{
+		Enabled:                user.TOTPEnabled,
+		Required:               user.TOTPRequired,
+		Pending:                !user.TOTPEnabled && user.TOTPSecret != "",
+		RecoveryCodesRemaining: remaining,
+	}, nil
+}
+
+// BeginTOTPEnrollment generates a new TOTP secret for the user
+// Two-factor isn't enforced until EnableTOTP confirms a code from the app.
+func (s *AuthService) BeginTOTPEnrollment(ctx context.Context, user *domain.User) (*TOTPEnrollment, error) {
+	if user.TOTPEnabled {
+		return nil, fmt.Errorf("two-factor authentication is already enabled")
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPSecret = secret
+	user.TOTPLastStep = 0
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &TOTPEnrollment{Secret: secret, URI: totpURI(totpIssuer, user.Email, secret)}, nil
+}
+
+// EnableTOTP confirms enrollment with a code from the authenticator app
+// Returns a fresh set of recovery codes, which are only shown once.
+func (s *AuthService) EnableTOTP(ctx context.Context, user *domain.User, code string) ([]string, error) {
+	if user.TOTPEnabled {
+		return nil, fmt.Errorf("two-factor authentication is already enabled")
+	}
+	if user.TOTPSecret == "" {
+		return nil, fmt.Errorf("start two-factor setup first")
+	}
+
+	step, ok := verifyTOTP(user.TOTPSecret, code, time.Now(), user.TOTPLastStep)
+	if !ok {
+		return nil, fmt.Errorf("invalid two-factor code")
+	}
+
+	codes, err := s.replaceRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPEnabled = true
+	user.TOTPLastStep = step
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// DisableTOTP turns two-factor off after re-checking the password and a code
+// Users who are required to use two-factor can't disable it.
+func (s *AuthService) DisableTOTP(ctx context.Context, user *domain.User, password, code string) error {
+	if !user.TOTPEnabled {
+		return fmt.Errorf("two-factor authentication is not enabled")
+	}
+	if user.TOTPRequired {
+		return fmt.Errorf("two-factor authentication is required for this account")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return fmt.Errorf("invalid password")
+	}
+	if err := s.verifySecondFactor(ctx, user, code); err != nil {
+		return err
+	}
+
+	if err := s.recoveryRepo.DeleteByUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	user.TOTPEnabled = false
+	user.TOTPSecret = ""
+	user.TOTPLastStep = 0
+	user.UpdatedAt = time.Now()
+	return s.userRepo.Update(ctx, user)
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes after checking a TOTP code
+func (s *AuthService) RegenerateRecoveryCodes(ctx context.Context, user *domain.User, code string) ([]string, error) {
+	if !user.TOTPEnabled {
+		return nil, fmt.Errorf("two-factor authentication is not enabled")
+	}
+	if s.secondFactors.blocked(user.ID) {
+		return nil, ErrTooManyTwoFactorAttempts
+	}
+
+	step, ok := verifyTOTP(user.TOTPSecret, code, time.Now(), user.TOTPLastStep)
+	if !ok {
+		s.secondFactors.fail(user.ID)
+		return nil, fmt.Errorf("invalid two-factor code")
+	}
+	s.secondFactors.reset(user.ID)
+	user.TOTPLastStep = step
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return s.replaceRecoveryCodes(ctx, user.ID)
+}
+
+// SetTOTPRequired sets whether a user must use two-factor authentication
+// A required user who hasn't enrolled can only use the auth endpoints until they do.
+func (s *AuthService) SetTOTPRequired(ctx context.Context, userID string, required bool) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPRequired = required
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// verifySecondFactor accepts either a current TOTP code or an unused recovery code
+// Wrong codes are counted per user across sign-in, single sign-on and the two-factor
+// settings; after twoFactorAttempts of them every code is refused for a while.
+func (s *AuthService) verifySecondFactor(ctx context.Context, user *domain.User, code string) error {
+	code = strings.TrimSpace(code)
+	if s.secondFactors.blocked(user.ID) {
+		return ErrTooManyTwoFactorAttempts
+	}
+
+	if len(code) == totpDigits {
+		step, ok := verifyTOTP(user.TOTPSecret, code, time.Now(), user.TOTPLastStep)
+		if !ok {
+			s.secondFactors.fail(user.ID)
+			return fmt.Errorf("invalid two-factor code")
+		}
+		s.secondFactors.reset(user.ID)
+		user.TOTPLastStep = step
+		user.UpdatedAt = time.Now()
+		return s.userRepo.Update(ctx, user)
+	}
+
+	if err := s.recoveryRepo.Use(ctx, user.ID, hashRecoveryCode(code), time.Now()); err != nil {
+		s.secondFactors.fail(user.ID)
+		return fmt.Errorf("invalid two-factor code")
+	}
+	s.secondFactors.reset(user.ID)
+	return nil
+}
+
+// replaceRecoveryCodes generates and stores a new set of recovery codes, returning them in plain text
+func (s *AuthService) replaceRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	now := time.Now()
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]*domain.RecoveryCode, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		secret := make([]byte, 5)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		raw := hex.EncodeToString(secret)
+		code := raw[:5] + "-" + raw[5:]
+
+		codes = append(codes, code)
+		records = append(records, &domain.RecoveryCode{
+			ID:        uuid.New().String(),
+			UserID:    userID,
+			CodeHash:  hashRecoveryCode(code),
+			CreatedAt: now,
+		})
+	}
+
+	if err := s.recoveryRepo.ReplaceForUser(ctx, userID, records); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *AuthService) userTokens(ctx context.Context, userID string) ([]*domain.APIToken, error) {
+	tokens, err := s.tokenRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := []*domain.APIToken{}
+	for _, token := range tokens {
+		if token.UserID != nil && *token.UserID == userID {
+			result = append(result, token)
+		}
+	}
+	return result, nil
+}
+
+func hashPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+func hashSessionToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashRecoveryCode hashes a recovery code, ignoring case, spaces and dashes
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// describeDevice turns a user agent into a short "Browser on OS" description
+func describeDevice(userAgent string) string {
+	if userAgent == "" {
+		return "Unknown device"
+	}
+
+	browser := "Unknown browser"
+	for _, b := range []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+	} {
+		if strings.Contains(userAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+
+	os := ""
+	for _, o := range []struct{ token, name string }{
+		{"Android", "Android"},
+		{"iPhone", "iPhone"},
+		{"iPad", "iPad"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"Linux", "Linux"},
+	} {
+		if strings.Contains(userAgent, o.token) {
+			os = o.name
+			break
+		}
+	}
+
+	if os == "" {
+		return browser
+	}
+	return browser + " on " + os
+}
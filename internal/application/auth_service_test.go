@@ -0,0 +1,158 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestAuthService_AuthenticateSession(t *testing.T) {
+	ctx := context.Background()
+	userRepo := &mockUserRepository{}
+	sessionRepo := &mockSessionRepository{}
+	service := NewAuthService(userRepo, sessionRepo, &mockAPITokenRepository{tokens: map[string]*domain.APIToken{}}, &mockRecoveryCodeRepository{codes: map[string][]*domain.RecoveryCode{}})
+
+	admin, err := service.CreateUser(ctx, "admin@example.com", "Admin", "password123", domain.UserRoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	member, err := service.CreateUser(ctx, "kid@example.com", "Kid", "password123", domain.UserRoleMember)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session, raw, err := service.StartSession(ctx, member, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, user, err := service.AuthenticateSession(ctx, raw); err != nil || user.ID != member.ID {
+		t.Fatalf("expected a new session to authenticate its user, got %v, %v", user, err)
+	}
+
+	// An expired session is refused and removed
+	session.ExpiresAt = time.Now().Add(-time.Minute)
+	if _, _, err := service.AuthenticateSession(ctx, raw); err == nil {
+		t.Error("expected an expired session to be refused")
+	}
+	if len(sessionRepo.sessions) != 0 {
+		t.Errorf("expected the expired session deleted, %d left", len(sessionRepo.sessions))
+	}
+
+	// Disabling a user ends the sessions they're already signed in with
+	_, raw, err = service.StartSession(ctx, member, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.DisableUser(ctx, &admin.ID, member.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := service.AuthenticateSession(ctx, raw); err == nil {
+		t.Error("expected a disabled user's session to be refused")
+	}
+
+	// Even one that outlived disabling, say created by a sign-in racing with it
+	_, raw, err = service.StartSession(ctx, member, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := service.AuthenticateSession(ctx, raw); !errors.Is(err, ErrUserDisabled) {
+		t.Errorf("expected %v for a disabled user's session, got %v", ErrUserDisabled, err)
+	}
+}
+
+func TestAuthService_LoginLimits(t *testing.T) {
+	ctx := context.Background()
+	service := NewAuthService(&mockUserRepository{}, &mockSessionRepository{}, &mockAPITokenRepository{tokens: map[string]*domain.APIToken{}}, &mockRecoveryCodeRepository{codes: map[string][]*domain.RecoveryCode{}})
+	if _, err := service.CreateUser(ctx, "admin@example.com", "Admin", "password123", domain.UserRoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.CreateUser(ctx, "kid@example.com", "Kid", "password123", domain.UserRoleMember); err != nil {
+		t.Fatal(err)
+	}
+
+	// An unknown email is refused just like a wrong password
+	_, _, _, unknownErr := service.Login(ctx, "nobody@example.com", "password123", "", "", "192.0.2.1")
+	_, _, _, wrongErr := service.Login(ctx, "admin@example.com", "wrong-password", "", "", "192.0.2.1")
+	if unknownErr == nil || wrongErr == nil || unknownErr.Error() != wrongErr.Error() {
+		t.Errorf("expected the same error for an unknown email and a wrong password, got %v and %v", unknownErr, wrongErr)
+	}
+
+	// After too many wrong passwords even the right one is refused, from anywhere
+	for i := 1; i < signInAttemptsPerAccount; i++ {
+		if _, _, _, err := service.Login(ctx, "admin@example.com", "wrong-password", "", "", "192.0.2.1"); errors.Is(err, ErrTooManySignInAttempts) {
+			t.Fatalf("expected attempt %d to be allowed", i+1)
+		}
+	}
+	if _, _, _, err := service.Login(ctx, "Admin@Example.com", "password123", "", "", "198.51.100.7"); !errors.Is(err, ErrTooManySignInAttempts) {
+		t.Errorf("expected sign-in refused after %d wrong passwords, got %v", signInAttemptsPerAccount, err)
+	}
+	if _, _, _, err := service.Login(ctx, "kid@example.com", "password123", "", "", "192.0.2.1"); err != nil {
+		t.Errorf("expected another account to sign in from the same address, got %v", err)
+	}
+
+	// Until the window has passed
+	service.accountSignIns.failures["admin@example.com"].since = time.Now().Add(-signInWindow)
+	if _, _, _, err := service.Login(ctx, "admin@example.com", "password123", "", "", "192.0.2.1"); err != nil {
+		t.Errorf("expected sign-in allowed again after the window, got %v", err)
+	}
+
+	// An address trying many emails is refused too
+	for i := 0; i < signInAttemptsPerAddress; i++ {
+		service.addressSignIns.fail("203.0.113.5")
+	}
+	if _, _, _, err := service.Login(ctx, "kid@example.com", "password123", "", "", "203.0.113.5"); !errors.Is(err, ErrTooManySignInAttempts) {
+		t.Errorf("expected sign-in from a busy address refused, got %v", err)
+	}
+	if _, _, _, err := service.Login(ctx, "kid@example.com", "password123", "", "", "192.0.2.1"); err != nil {
+		t.Errorf("expected the account to still sign in from elsewhere, got %v", err)
+	}
+}
+
+func TestAuthService_TwoFactorLimit(t *testing.T) {
+	ctx := context.Background()
+	service := NewAuthService(&mockUserRepository{}, &mockSessionRepository{}, &mockAPITokenRepository{tokens: map[string]*domain.APIToken{}}, &mockRecoveryCodeRepository{codes: map[string][]*domain.RecoveryCode{}})
+	user, err := service.CreateUser(ctx, "admin@example.com", "Admin", "password123", domain.UserRoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.TOTPSecret = secret
+	user.TOTPEnabled = true
+
+	key, _ := totpEncoding.DecodeString(secret)
+	step := time.Now().Unix() / totpPeriod
+	right := totpCode(key, step)
+	wrong := "000000"
+	for _, near := range []string{totpCode(key, step-1), right, totpCode(key, step+1)} {
+		if wrong == near {
+			wrong = "999999"
+		}
+	}
+
+	for i := 0; i < twoFactorAttempts; i++ {
+		if _, _, _, err := service.Login(ctx, "admin@example.com", "password123", wrong, "", "192.0.2.1"); err == nil || errors.Is(err, ErrTooManyTwoFactorAttempts) {
+			t.Fatalf("expected wrong code %d refused as invalid, got %v", i+1, err)
+		}
+	}
+	if _, _, _, err := service.Login(ctx, "admin@example.com", "password123", right, "", "192.0.2.1"); !errors.Is(err, ErrTooManyTwoFactorAttempts) {
+		t.Errorf("expected the right code refused after %d wrong ones, got %v", twoFactorAttempts, err)
+	}
+	// The count is the user's, wherever a code is asked for
+	if err := service.DisableTOTP(ctx, user, "password123", right); !errors.Is(err, ErrTooManyTwoFactorAttempts) {
+		t.Errorf("expected turning two-factor off refused too, got %v", err)
+	}
+	if _, err := service.RegenerateRecoveryCodes(ctx, user, right); !errors.Is(err, ErrTooManyTwoFactorAttempts) {
+		t.Errorf("expected new recovery codes refused too, got %v", err)
+	}
+
+	service.secondFactors.failures[user.ID].since = time.Now().Add(-signInWindow)
+	if _, _, _, err := service.Login(ctx, "admin@example.com", "password123", right, "", "192.0.2.1"); err != nil {
+		t.Errorf("expected the right code accepted after the window, got %v", err)
+	}
+}
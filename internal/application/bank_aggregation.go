@@ -0,0 +1,321 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/billybbuffum/budget/internal/infrastructure/ofx"
+	"github.com/google/uuid"
+)
+
+// BankAggregator fetches accounts and their transactions from a bank aggregation service
+// Implementations live in internal/infrastructure/banksync
+type BankAggregator interface {
+	Name() string
+
+	// Claim exchanges a one-time setup token for the credentials to fetch with
+	Claim(ctx context.Context, setupToken string) (string, error)
+
+	// Accounts fetches every account the credentials can see, with transactions posted
+	// since then, along with any messages the aggregator asks to be shown to the user
+	Accounts(ctx context.Context, credentials string, since time.Time) ([]domain.AggregatedAccount, []string, error)
+}
+
+// AggregationSyncResult is what syncing a bank aggregation did
+type AggregationSyncResult struct {
+	Messages []string                 `json:"messages,omitempty"` // From the aggregator, e.g. a bank to reconnect there
+	Accounts []AggregationAccountSync `json:"accounts"`           // One per linked account
+}
+
+// AggregationAccountSync is what syncing a bank aggregation did to one linked account
+type AggregationAccountSync struct {
+	RemoteID  string        `json:"remote_id"`
+	AccountID string        `json:"account_id"`
+	Result    *ImportResult `json:"result,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// UseAggregators lets accounts be synced through the bank aggregators
+func (s *BankSyncService) UseAggregators(aggregationRepo domain.BankAggregationRepository, aggregators ...BankAggregator) {
+	s.aggregationRepo = aggregationRepo
+	s.aggregators = make(map[string]BankAggregator, len(aggregators))
+	for _, aggregator := range aggregators {
+		s.aggregators[aggregator.Name()] = aggregator
+	}
+}
+
+// ConnectAggregation claims a setup token from the provider and saves the aggregation,
+// with the accounts the aggregator reports
+// The aggregation is saved even if listing its accounts fails, as the token can't be
+// claimed twice; its next sync lists them.
+func (s *BankSyncService) ConnectAggregation(ctx context.Context, provider, name, setupToken string) (*domain.BankAggregation, []*domain.BankAggregationAccount, error) {
+	if s.aead == nil || s.aggregationRepo == nil {
+		return nil, nil, ErrBankSyncNotConfigured
+	}
+	aggregator, ok := s.aggregators[provider]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown provider %q; providers are %s", provider, strings.Join(s.providers(), ", "))
+	}
+	if strings.TrimSpace(setupToken) == "" {
+		return nil, nil, fmt.Errorf("setup_token is required")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = provider
+	}
+
+	credentials, err := aggregator.Claim(ctx, setupToken)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrBankSyncFailed, err)
+	}
+	now := time.Now()
+	aggregation := &domain.BankAggregation{ID: uuid.New().String(), Provider: provider, Name: name, CreatedAt: now, UpdatedAt: now}
+	if aggregation.Credentials, err = s.seal(aggregation.ID, []byte(credentials)); err != nil {
+		return nil, nil, err
+	}
+	if err := s.aggregationRepo.Create(ctx, aggregation); err != nil {
+		return nil, nil, err
+	}
+
+	// Only the accounts are wanted for now; transactions come with the first sync
+	remotes, _, err := aggregator.Accounts(ctx, credentials, now)
+	if err != nil {
+		aggregation.LastError = err.Error()
+		if err := s.aggregationRepo.Update(ctx, aggregation); err != nil {
+			return nil, nil, err
+		}
+		return aggregation, []*domain.BankAggregationAccount{}, nil
+	}
+	accounts := make([]*domain.BankAggregationAccount, 0, len(remotes))
+	for _, remote := range remotes {
+		account, err := s.saveAggregationAccount(ctx, aggregation.ID, remote)
+		if err != nil {
+			return nil, nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return aggregation, accounts, nil
+}
+
+// ListAggregations lists every bank aggregation, without its credentials
+func (s *BankSyncService) ListAggregations(ctx context.Context) ([]*domain.BankAggregation, error) {
+	if s.aggregationRepo == nil {
+		return nil, nil
+	}
+	return s.aggregationRepo.List(ctx)
+}
+
+// DeleteAggregation disconnects from the aggregator, forgetting its credentials and links
+// Transactions already synced are kept.
+func (s *BankSyncService) DeleteAggregation(ctx context.Context, id string) error {
+	if s.aggregationRepo == nil {
+		return domain.ErrBankAggregationNotFound
+	}
+	return s.aggregationRepo.Delete(ctx, id)
+}
+
+// ListAggregationAccounts lists the accounts the aggregator reported as of its last sync
+func (s *BankSyncService) ListAggregationAccounts(ctx context.Context, id string) ([]*domain.BankAggregationAccount, error) {
+	if s.aggregationRepo == nil {
+		return nil, domain.ErrBankAggregationNotFound
+	}
+	if _, err := s.aggregationRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.aggregationRepo.ListAccounts(ctx, id)
+}
+
+// LinkAggregationAccount sets which budget account an aggregator's account syncs into;
+// nil unlinks it
+// A budget account syncs from one place only, and its currency has to be the budget's.
+func (s *BankSyncService) LinkAggregationAccount(ctx context.Context, id, remoteID string, accountID *string) (*domain.BankAggregationAccount, error) {
+	if s.aggregationRepo == nil {
+		return nil, domain.ErrBankAggregationNotFound
+	}
+	remote, err := s.aggregationRepo.GetAccount(ctx, id, remoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if accountID != nil {
+		if _, err := s.accountRepo.GetByID(ctx, *accountID); err != nil {
+			return nil, domain.ErrAccountNotFound
+		}
+		if remote.Currency != "" && remote.Currency != domain.BudgetCurrency().Code {
+			return nil, fmt.Errorf("%s is in %s, not the budget's currency %s", remote.Name, remote.Currency, domain.BudgetCurrency().Code)
+		}
+		if _, err := s.connectionRepo.GetByAccountID(ctx, *accountID); err == nil {
+			return nil, fmt.Errorf("the account already syncs from its bank over OFX DirectConnect")
+		}
+		linked, err := s.linkedAggregationAccount(ctx, *accountID)
+		if err != nil {
+			return nil, err
+		}
+		if linked != nil && (linked.AggregationID != id || linked.RemoteID != remoteID) {
+			return nil, fmt.Errorf("the account already syncs from %s", linked.Name)
+		}
+	}
+
+	remote.AccountID = accountID
+	remote.LastError = ""
+	remote.UpdatedAt = time.Now()
+	if err := s.aggregationRepo.SaveAccount(ctx, remote); err != nil {
+		return nil, err
+	}
+	return remote, nil
+}
+
+// SyncAggregation fetches the aggregator's accounts since the aggregation last synced, a
+// few days back to catch late postings, and imports the transactions of the linked ones
+// The first sync goes back as far as an uploaded file's import would. The import skips
+// transactions it already has and sets each balance to the aggregator's. A failure to
+// import one account is kept on it and doesn't stop the others.
+func (s *BankSyncService) SyncAggregation(ctx context.Context, id string) (*AggregationSyncResult, error) {
+	if s.aead == nil || s.aggregationRepo == nil {
+		return nil, ErrBankSyncNotConfigured
+	}
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	aggregation, err := s.aggregationRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.syncAggregation(ctx, aggregation)
+	aggregation.UpdatedAt = time.Now()
+	if err != nil {
+		aggregation.LastError = err.Error()
+	} else {
+		aggregation.LastError = strings.Join(result.Messages, "; ")
+	}
+	if saveErr := s.aggregationRepo.Update(ctx, aggregation); saveErr != nil && err == nil {
+		return nil, saveErr
+	}
+	return result, err
+}
+
+// SyncAggregations syncs every bank aggregation, for the scheduled sync
+func (s *BankSyncService) SyncAggregations(ctx context.Context) error {
+	if s.aead == nil || s.aggregationRepo == nil {
+		return nil
+	}
+	aggregations, err := s.aggregationRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, aggregation := range aggregations {
+		if _, err := s.SyncAggregation(ctx, aggregation.ID); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", aggregation.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// syncAggregation fetches the aggregator's accounts, refreshing what's known of them, and
+// imports the linked ones, setting LastSyncedAt once they're fetched
+func (s *BankSyncService) syncAggregation(ctx context.Context, aggregation *domain.BankAggregation) (*AggregationSyncResult, error) {
+	aggregator, ok := s.aggregators[aggregation.Provider]
+	if !ok {
+		return nil, fmt.Errorf("%w: provider %q is not available", ErrBankSyncFailed, aggregation.Provider)
+	}
+	credentials, err := s.open(aggregation.ID, aggregation.Credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	since := now.AddDate(0, 0, -ofx.DefaultLookbackDays)
+	if aggregation.LastSyncedAt != nil {
+		since = aggregation.LastSyncedAt.AddDate(0, 0, -bankSyncOverlapDays)
+	}
+	remotes, messages, err := aggregator.Accounts(ctx, string(credentials), since)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBankSyncFailed, err)
+	}
+	aggregation.LastSyncedAt = &now
+
+	result := &AggregationSyncResult{Messages: messages, Accounts: []AggregationAccountSync{}}
+	for _, remote := range remotes {
+		account, err := s.saveAggregationAccount(ctx, aggregation.ID, remote)
+		if err != nil {
+			return nil, err
+		}
+		if account.AccountID == nil {
+			continue
+		}
+
+		synced := AggregationAccountSync{RemoteID: remote.ID, AccountID: *account.AccountID}
+		if account.Currency != "" && account.Currency != domain.BudgetCurrency().Code {
+			err = fmt.Errorf("%s is in %s, not the budget's currency %s", account.Name, account.Currency, domain.BudgetCurrency().Code)
+		} else {
+			synced.Result, err = s.importService.ImportAggregated(ctx, *account.AccountID, remote)
+		}
+		account.LastError = ""
+		if err != nil {
+			synced.Error = err.Error()
+			account.LastError = err.Error()
+		}
+		if err := s.aggregationRepo.SaveAccount(ctx, account); err != nil {
+			return nil, err
+		}
+		result.Accounts = append(result.Accounts, synced)
+	}
+	return result, nil
+}
+
+// saveAggregationAccount records an account as the aggregator reported it, keeping its link
+func (s *BankSyncService) saveAggregationAccount(ctx context.Context, aggregationID string, remote domain.AggregatedAccount) (*domain.BankAggregationAccount, error) {
+	account, err := s.aggregationRepo.GetAccount(ctx, aggregationID, remote.ID)
+	switch {
+	case errors.Is(err, domain.ErrBankAggregationAccountNotFound):
+		account = &domain.BankAggregationAccount{AggregationID: aggregationID, RemoteID: remote.ID}
+	case err != nil:
+		return nil, err
+	}
+	account.Name = remote.Name
+	account.Institution = remote.Institution
+	account.Currency = remote.Currency
+	account.Balance = remote.Balance
+	account.UpdatedAt = time.Now()
+	if err := s.aggregationRepo.SaveAccount(ctx, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// linkedAggregationAccount returns the aggregator account syncing into the budget account,
+// or nil if none does
+func (s *BankSyncService) linkedAggregationAccount(ctx context.Context, accountID string) (*domain.BankAggregationAccount, error) {
+	aggregations, err := s.aggregationRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, aggregation := range aggregations {
+		accounts, err := s.aggregationRepo.ListAccounts(ctx, aggregation.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, account := range accounts {
+			if account.AccountID != nil && *account.AccountID == accountID {
+				return account, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// providers returns the names of the aggregators available, sorted
+func (s *BankSyncService) providers() []string {
+	names := make([]string, 0, len(s.aggregators))
+	for name := range s.aggregators {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,279 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/billybbuffum/budget/internal/infrastructure/ofx"
+)
+
+// ErrBankSyncNotConfigured is returned when no key to seal bank credentials with is configured
+var ErrBankSyncNotConfigured = errors.New("bank sync is not configured; set BANK_SYNC_KEY")
+
+// ErrBankSyncFailed is returned when the bank couldn't be reached or refused the request
+var ErrBankSyncFailed = errors.New("bank sync failed")
+
+// bankSyncOverlapDays is how far before the last sync a sync asks for transactions from,
+// so ones the bank posted late aren't missed; the import skips those it already has
+const bankSyncOverlapDays = 7
+
+// StatementDownloader fetches an account's statement from its bank's OFX server
+// Implementations live in internal/infrastructure/ofx
+type StatementDownloader interface {
+	DownloadStatement(ctx context.Context, connection *domain.BankConnection, credentials domain.BankCredentials, since time.Time) ([]byte, error)
+}
+
+// BankConnectionInput is what connecting an account to its bank takes
+// Password and AccountNumber may be left empty when changing a connection to keep them.
+type BankConnectionInput struct {
+	URL           string `json:"url"`
+	Org           string `json:"org"`
+	FID           string `json:"fid"`
+	BankID        string `json:"bank_id,omitempty"` // Routing number; not used for credit cards
+	AccountType   string `json:"account_type"`      // CHECKING, SAVINGS, MONEYMRKT, CREDITLINE or CREDITCARD
+	Username      string `json:"username"`
+	Password      string `json:"password,omitempty"`
+	AccountNumber string `json:"account_number,omitempty"`
+	AppID         string `json:"app_id,omitempty"` // e.g. QWIN for banks that only answer Quicken
+	AppVer        string `json:"app_ver,omitempty"`
+	OFXVersion    string `json:"ofx_version,omitempty"`
+}
+
+// BankSyncService downloads accounts' statements straight from their banks over OFX
+// DirectConnect, or through a bank aggregator, and imports them like uploaded files
+type BankSyncService struct {
+	connectionRepo domain.BankConnectionRepository
+	accountRepo    domain.AccountRepository
+	importService  *ImportService
+	downloader     StatementDownloader
+	aead           cipher.AEAD // Seals credentials; nil when no key is configured
+
+	aggregationRepo domain.BankAggregationRepository
+	aggregators     map[string]BankAggregator // By provider name; see UseAggregators
+
+	syncMu sync.Mutex // One sync at a time, so the same transactions aren't imported twice
+}
+
+// NewBankSyncService creates a new bank sync service
+// Credentials are sealed with AES-GCM under a key derived from key; an empty key leaves
+// bank sync unavailable.
+func NewBankSyncService(connectionRepo domain.BankConnectionRepository, accountRepo domain.AccountRepository, importService *ImportService, downloader StatementDownloader, key string) *BankSyncService {
+	s := &BankSyncService{
+		connectionRepo: connectionRepo,
+		accountRepo:    accountRepo,
+		importService:  importService,
+		downloader:     downloader,
+	}
+	if key != "" {
+		sum := sha256.Sum256([]byte(key))
+		block, _ := aes.NewCipher(sum[:]) // Only fails for key sizes other than 16, 24 and 32 bytes
+		s.aead, _ = cipher.NewGCM(block)
+	}
+	return s
+}
+
+// SaveConnection connects the account to its bank, or changes how it's connected
+func (s *BankSyncService) SaveConnection(ctx context.Context, accountID string, input BankConnectionInput) (*domain.BankConnection, error) {
+	if s.aead == nil {
+		return nil, ErrBankSyncNotConfigured
+	}
+	if _, err := s.accountRepo.GetByID(ctx, accountID); err != nil {
+		return nil, domain.ErrAccountNotFound
+	}
+	if s.aggregationRepo != nil {
+		linked, err := s.linkedAggregationAccount(ctx, accountID)
+		if err != nil {
+			return nil, err
+		}
+		if linked != nil {
+			return nil, fmt.Errorf("the account already syncs from %s", linked.Name)
+		}
+	}
+
+	input.URL = strings.TrimSpace(input.URL)
+	input.AccountType = strings.ToUpper(strings.TrimSpace(input.AccountType))
+	input.AccountNumber = strings.TrimSpace(input.AccountNumber)
+	switch {
+	case !strings.HasPrefix(input.URL, "https://"):
+		return nil, fmt.Errorf("url must be an https address")
+	case input.Org == "" || input.FID == "":
+		return nil, fmt.Errorf("org and fid are required")
+	case input.Username == "":
+		return nil, fmt.Errorf("username is required")
+	}
+	switch input.AccountType {
+	case domain.BankAccountChecking, domain.BankAccountSavings, domain.BankAccountMoneyMrkt, domain.BankAccountCreditLine:
+		if input.BankID == "" {
+			return nil, fmt.Errorf("bank_id is required for bank accounts")
+		}
+	case domain.BankAccountCreditCard:
+		input.BankID = ""
+	default:
+		return nil, fmt.Errorf("account_type must be CHECKING, SAVINGS, MONEYMRKT, CREDITLINE or CREDITCARD")
+	}
+
+	now := time.Now()
+	connection, err := s.connectionRepo.GetByAccountID(ctx, accountID)
+	var credentials domain.BankCredentials
+	switch {
+	case errors.Is(err, domain.ErrBankConnectionNotFound):
+		connection = &domain.BankConnection{AccountID: accountID, CreatedAt: now}
+	case err != nil:
+		return nil, err
+	default:
+		// Credentials sealed under an old key can only be replaced
+		credentials, err = s.openCredentials(connection)
+		if err != nil && (input.Password == "" || input.AccountNumber == "") {
+			return nil, err
+		}
+	}
+	if input.Password != "" {
+		credentials.Password = input.Password
+	}
+	if input.AccountNumber != "" {
+		credentials.AccountNumber = input.AccountNumber
+	}
+	if credentials.Password == "" || credentials.AccountNumber == "" {
+		return nil, fmt.Errorf("password and account_number are required")
+	}
+
+	connection.URL = input.URL
+	connection.Org = input.Org
+	connection.FID = input.FID
+	connection.BankID = input.BankID
+	connection.AccountType = input.AccountType
+	connection.Username = input.Username
+	connection.AccountNumberLast4 = credentials.AccountNumber[max(0, len(credentials.AccountNumber)-4):]
+	connection.AppID = input.AppID
+	connection.AppVer = input.AppVer
+	connection.OFXVersion = input.OFXVersion
+	connection.LastError = ""
+	connection.UpdatedAt = now
+	if connection.Credentials, err = s.sealCredentials(accountID, credentials); err != nil {
+		return nil, err
+	}
+
+	if err := s.connectionRepo.Save(ctx, connection); err != nil {
+		return nil, err
+	}
+	return connection, nil
+}
+
+// GetConnection returns how the account is connected to its bank, without its credentials
+func (s *BankSyncService) GetConnection(ctx context.Context, accountID string) (*domain.BankConnection, error) {
+	return s.connectionRepo.GetByAccountID(ctx, accountID)
+}
+
+// DeleteConnection disconnects the account from its bank, forgetting its credentials
+// Transactions already synced are kept.
+func (s *BankSyncService) DeleteConnection(ctx context.Context, accountID string) error {
+	return s.connectionRepo.Delete(ctx, accountID)
+}
+
+// SyncNow downloads the account's transactions since it last synced, a few days back to
+// catch late postings, and imports them
+// The first sync goes back as far as an uploaded file's import would. The import skips
+// transactions it already has and sets the balance to the bank's, like an upload does.
+// Failures are kept on the connection for the next look at it.
+func (s *BankSyncService) SyncNow(ctx context.Context, accountID string) (*ImportResult, error) {
+	if s.aead == nil || s.downloader == nil {
+		return nil, ErrBankSyncNotConfigured
+	}
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	connection, err := s.connectionRepo.GetByAccountID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	credentials, err := s.openCredentials(connection)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	since := now.AddDate(0, 0, -ofx.DefaultLookbackDays)
+	if connection.LastSyncedAt != nil {
+		since = connection.LastSyncedAt.AddDate(0, 0, -bankSyncOverlapDays)
+	}
+
+	result, err := s.sync(ctx, connection, credentials, since)
+	connection.UpdatedAt = time.Now()
+	if err != nil {
+		connection.LastError = err.Error()
+	} else {
+		connection.LastSyncedAt = &now
+		connection.LastError = ""
+	}
+	if saveErr := s.connectionRepo.Save(ctx, connection); saveErr != nil && err == nil {
+		return nil, saveErr
+	}
+	return result, err
+}
+
+// sync downloads the statement since then and imports it
+func (s *BankSyncService) sync(ctx context.Context, connection *domain.BankConnection, credentials domain.BankCredentials, since time.Time) (*ImportResult, error) {
+	data, err := s.downloader.DownloadStatement(ctx, connection, credentials, since)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBankSyncFailed, err)
+	}
+	// Some banks send everything they have whatever was asked for
+	lookback := int(time.Since(since).Hours()/24) + 1
+	return s.importService.ImportWithOptions(ctx, connection.AccountID, bytes.NewReader(data), ImportOptions{LookbackDays: lookback})
+}
+
+// sealCredentials encrypts credentials for the account's connection
+// The account ID is authenticated along with them, so they can't be moved to another account.
+func (s *BankSyncService) sealCredentials(accountID string, credentials domain.BankCredentials) ([]byte, error) {
+	plaintext, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, err
+	}
+	return s.seal(accountID, plaintext)
+}
+
+// openCredentials decrypts the connection's credentials
+func (s *BankSyncService) openCredentials(connection *domain.BankConnection) (domain.BankCredentials, error) {
+	var credentials domain.BankCredentials
+	plaintext, err := s.open(connection.AccountID, connection.Credentials)
+	if err != nil {
+		return credentials, err
+	}
+	if err := json.Unmarshal(plaintext, &credentials); err != nil {
+		return credentials, err
+	}
+	return credentials, nil
+}
+
+// seal encrypts plaintext for what id identifies, which is authenticated along with it
+func (s *BankSyncService) seal(id string, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return s.aead.Seal(nonce, nonce, plaintext, []byte(id)), nil
+}
+
+// open decrypts what seal encrypted for id
+func (s *BankSyncService) open(id string, sealed []byte) ([]byte, error) {
+	if len(sealed) < s.aead.NonceSize() {
+		return nil, fmt.Errorf("bank credentials are damaged; save the connection again")
+	}
+	nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+	plaintext, err := s.aead.Open(nil, nonce, ciphertext, []byte(id))
+	if err != nil {
+		return nil, fmt.Errorf("bank credentials can't be decrypted, perhaps BANK_SYNC_KEY changed; save the connection again")
+	}
+	return plaintext, nil
+}
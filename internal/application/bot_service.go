@@ -0,0 +1,343 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/google/uuid"
+)
+
+// BotService answers chat-bot messages: quick-add transactions, balance queries
+// and daily summaries, for chats authenticated with per-chat tokens
+type BotService struct {
+	botChatRepo       domain.BotChatRepository
+	accountRepo       domain.AccountRepository
+	transactionRepo   domain.TransactionRepository
+	allocationService *AllocationService
+	quickAddService   *QuickAddService
+}
+
+// NewBotService creates a new bot service
+func NewBotService(
+	botChatRepo domain.BotChatRepository,
+	accountRepo domain.AccountRepository,
+	transactionRepo domain.TransactionRepository,
+	allocationService *AllocationService,
+	quickAddService *QuickAddService,
+) *BotService {
+	return &BotService{
+		botChatRepo:       botChatRepo,
+		accountRepo:       accountRepo,
+		transactionRepo:   transactionRepo,
+		allocationService: allocationService,
+		quickAddService:   quickAddService,
+	}
+}
+
+// BotReply is the bot's answer to a message
+type BotReply struct {
+	Text        string              `json:"text"`
+	Transaction *domain.Transaction `json:"transaction,omitempty"` // Set when the message created a transaction
+}
+
+// BotDailySummary summarizes one day of activity
+type BotDailySummary struct {
+	Date          string `json:"date"`
+	Spent         int64  `json:"spent"`    // Categorized outflows, as a positive number (cents)
+	Received      int64  `json:"received"` // Non-transfer inflows (cents)
+	Transactions  int    `json:"transactions"`
+	ReadyToAssign int64  `json:"ready_to_assign"` // For the day's period (cents)
+	Text          string `json:"text"`
+}
+
+// CreateChat registers a new bot chat and returns it with its token
+// The token is only available here; only its hash is stored.
+func (s *BotService) CreateChat(ctx context.Context, platform domain.BotPlatform, chatID, name string, defaultAccountID *string, dailySummary bool) (*domain.BotChat, string, error) {
+	if !platform.IsValid() {
+		return nil, "", fmt.Errorf("platform must be telegram, discord or api")
+	}
+	if name == "" {
+		return nil, "", fmt.Errorf("name is required")
+	}
+	if defaultAccountID != nil && *defaultAccountID != "" {
+		if _, err := s.accountRepo.GetByID(ctx, *defaultAccountID); err != nil {
+			return nil, "", fmt.Errorf("account not found: %w", err)
+		}
+	} else {
+		defaultAccountID = nil
+	}
+
+	token, err := generateBotToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	chat := &domain.BotChat{
+		ID:               uuid.New().String(),
+		Platform:         platform,
+		ChatID:           chatID,
+		Name:             name,
+		TokenHash:        hashBotToken(token),
+		DefaultAccountID: defaultAccountID,
+		DailySummary:     dailySummary,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	if err := s.botChatRepo.Create(ctx, chat); err != nil {
+		return nil, "", err
+	}
+
+	return chat, token, nil
+}
+
+// ListChats lists all registered bot chats
+func (s *BotService) ListChats(ctx context.Context) ([]*domain.BotChat, error) {
+	return s.botChatRepo.List(ctx)
+}
+
+// ListChatsForPlatform lists the linked chats on a platform
+func (s *BotService) ListChatsForPlatform(ctx context.Context, platform domain.BotPlatform) ([]*domain.BotChat, error) {
+	chats, err := s.botChatRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []*domain.BotChat
+	for _, chat := range chats {
+		if chat.Platform == platform && chat.ChatID != "" {
+			result = append(result, chat)
+		}
+	}
+	return result, nil
+}
+
+// DeleteChat revokes a bot chat and its token
+func (s *BotService) DeleteChat(ctx context.Context, id string) error {
+	return s.botChatRepo.Delete(ctx, id)
+}
+
+// Authenticate resolves a bot token to its chat
+func (s *BotService) Authenticate(ctx context.Context, token string) (*domain.BotChat, error) {
+	if token == "" {
+		return nil, fmt.Errorf("bot token is required")
+	}
+	chat, err := s.botChatRepo.GetByTokenHash(ctx, hashBotToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("invalid bot token")
+	}
+
+	now := time.Now()
+	chat.LastUsedAt = &now
+	if err := s.botChatRepo.Update(ctx, chat); err != nil {
+		return nil, err
+	}
+
+	return chat, nil
+}
+
+// LinkChat binds a platform chat ID to the bot chat owning token
+// Used by built-in bots when a user sends "/start <token>".
+func (s *BotService) LinkChat(ctx context.Context, token string, platform domain.BotPlatform, chatID string) (*domain.BotChat, error) {
+	chat, err := s.Authenticate(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if chat.Platform != platform {
+		return nil, fmt.Errorf("token was issued for %s, not %s", chat.Platform, platform)
+	}
+
+	chat.ChatID = chatID
+	chat.UpdatedAt = time.Now()
+	if err := s.botChatRepo.Update(ctx, chat); err != nil {
+		return nil, err
+	}
+
+	return chat, nil
+}
+
+// GetLinkedChat finds the bot chat linked to a platform chat ID
+func (s *BotService) GetLinkedChat(ctx context.Context, platform domain.BotPlatform, chatID string) (*domain.BotChat, error) {
+	return s.botChatRepo.GetByPlatformChatID(ctx, platform, chatID)
+}
+
+// HandleMessage answers a chat message
+// Recognized commands: help, balance [account], available <category>, summary.
+// Anything else is treated as a quick-add phrase (e.g. "spent 20 groceries").
+func (s *BotService) HandleMessage(ctx context.Context, chat *domain.BotChat, text string) (*BotReply, error) {
+	text = strings.TrimSpace(text)
+	command, args, _ := strings.Cut(text, " ")
+	// Telegram commands look like "/balance@MyBudgetBot"
+	command, _, _ = strings.Cut(strings.TrimPrefix(strings.ToLower(command), "/"), "@")
+	args = strings.TrimSpace(args)
+
+	switch command {
+	case "", "help", "start":
+		return &BotReply{Text: botHelpText}, nil
+	case "balance", "balances":
+		text, err := s.balancesText(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		return &BotReply{Text: text}, nil
+	case "available", "left":
+		text, err := s.availableText(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		return &BotReply{Text: text}, nil
+	case "summary":
+		summary, err := s.DailySummary(ctx, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		return &BotReply{Text: summary.Text}, nil
+	}
+
+	transaction, err := s.quickAddService.QuickAdd(ctx, text, chat.DefaultAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	accountName := transaction.AccountID
+	if account, err := s.accountRepo.GetByID(ctx, transaction.AccountID); err == nil {
+		accountName = account.Name
+	}
+
+	return &BotReply{
+		Text:        fmt.Sprintf("Added %s %s (%s)", formatBotAmount(transaction.Amount), transaction.Description, accountName),
+		Transaction: transaction,
+	}, nil
+}
+
+// DailySummary summarizes activity on the given day
+func (s *BotService) DailySummary(ctx context.Context, day time.Time) (*BotDailySummary, error) {
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1).Add(-time.Second)
+
+	transactions, err := s.transactionRepo.ListByPeriod(ctx, start.Format(time.RFC3339), end.Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	summary := &BotDailySummary{Date: start.Format("2006-01-02")}
+	for _, txn := range transactions {
+		if txn.Type == domain.TransactionTypeTransfer || txn.Type == domain.TransactionTypeAdjustment ||
+			txn.Type == domain.TransactionTypeStartingBalance {
+			continue
+		}
+		summary.Transactions++
+		if txn.Amount > 0 {
+			summary.Received += txn.Amount
+		} else if txn.CategoryID != nil {
+			summary.Spent += -txn.Amount
+		}
+	}
+
+	rta, err := s.allocationService.CalculateReadyToAssignForPeriod(ctx, start.Format("2006-01"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate ready to assign: %w", err)
+	}
+	summary.ReadyToAssign = rta
+
+	summary.Text = fmt.Sprintf("Summary for %s\nSpent: %s\nReceived: %s\nTransactions: %d\nReady to Assign: %s",
+		summary.Date, formatBotAmount(summary.Spent), formatBotAmount(summary.Received),
+		summary.Transactions, formatBotAmount(summary.ReadyToAssign))
+
+	return summary, nil
+}
+
+func (s *BotService) balancesText(ctx context.Context, accountName string) (string, error) {
+	accounts, err := s.accountRepo.List(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	if accountName != "" {
+		names := make([]string, len(accounts))
+		for i, account := range accounts {
+			names[i] = account.Name
+		}
+		i, err := matchName(accountName, names, "account")
+		if err != nil {
+			return "", err
+		}
+		accounts = accounts[i : i+1]
+	} else {
+		// Hidden accounts only show up when asked for by name
+		shown := accounts[:0]
+		for _, account := range accounts {
+			if !account.HiddenFromDashboard {
+				shown = append(shown, account)
+			}
+		}
+		accounts = shown
+	}
+
+	if len(accounts) == 0 {
+		return "No accounts yet", nil
+	}
+
+	var b strings.Builder
+	var total int64
+	for _, account := range accounts {
+		fmt.Fprintf(&b, "%s: %s\n", account.Name, formatBotAmount(account.Balance))
+		total += account.Balance
+	}
+	if len(accounts) > 1 {
+		fmt.Fprintf(&b, "Total: %s\n", formatBotAmount(total))
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+func (s *BotService) availableText(ctx context.Context, categoryName string) (string, error) {
+	if categoryName == "" {
+		return "", fmt.Errorf("usage: available <category>")
+	}
+
+	summaries, err := s.allocationService.GetAllocationSummary(ctx, time.Now().Format("2006-01"))
+	if err != nil {
+		return "", fmt.Errorf("failed to get allocation summary: %w", err)
+	}
+
+	names := make([]string, len(summaries))
+	for i, summary := range summaries {
+		names[i] = summary.Category.Name
+	}
+	i, err := matchName(categoryName, names, "category")
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s: %s available", summaries[i].Category.Name, formatBotAmount(summaries[i].Available)), nil
+}
+
+const botHelpText = `Send a transaction like "spent 20 groceries" or "spent 4.50 coffee at Blue Bottle from cash".
+Commands:
+balance [account] - account balances
+available <category> - money left in a category this month
+summary - today's summary`
+
+// formatBotAmount formats cents as dollars, e.g. -2050 -> "-$20.50"
+func formatBotAmount(cents int64) string {
+	return domain.Cents(cents).String()
+}
+
+func generateBotToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashBotToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
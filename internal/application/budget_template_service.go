@@ -0,0 +1,334 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Budget template file format
+const (
+	BudgetTemplateFormat  = "budget-template"
+	BudgetTemplateVersion = 1
+
+	DefaultBudgetTemplateMonths = 3
+	MaxBudgetTemplateMonths     = 24
+
+	// budgetTemplateOtherGroup holds exported categories that aren't in a group
+	budgetTemplateOtherGroup = "Other"
+)
+
+// BudgetTemplate is a budget's category structure and typical monthly allocations
+// It's meant for sharing: only group and category names, descriptions and settings
+// are included, never accounts, payees, transactions or IDs.
+type BudgetTemplate struct {
+	Format      string                `json:"format"`  // Always BudgetTemplateFormat
+	Version     int                   `json:"version"` // BudgetTemplateVersion
+	Name        string                `json:"name"`
+	Description string                `json:"description,omitempty"`
+	Months      int                   `json:"months,omitempty"` // Number of months the amounts were averaged over
+	Groups      []BudgetTemplateGroup `json:"groups"`
+}
+
+// BudgetTemplateGroup is a category group in a budget template
+type BudgetTemplateGroup struct {
+	Name        string                   `json:"name"`
+	Description string                   `json:"description,omitempty"`
+	Categories  []BudgetTemplateCategory `json:"categories"`
+}
+
+// BudgetTemplateCategory is a category in a budget template
+type BudgetTemplateCategory struct {
+	Name             string                        `json:"name"`
+	Description      string                        `json:"description,omitempty"`
+	Color            string                        `json:"color,omitempty"`
+	Classification   domain.CategoryClassification `json:"classification,omitempty"`
+	OverspendingMode domain.OverspendingMode       `json:"overspending_mode,omitempty"`
+	MonthlyAmount    int64                         `json:"monthly_amount"` // Typical monthly allocation in cents
+}
+
+// BudgetTemplateImportResult describes what importing a budget template changed
+type BudgetTemplateImportResult struct {
+	GroupsCreated      int                        `json:"groups_created"`
+	CategoriesCreated  int                        `json:"categories_created"`
+	CategoriesSkipped  []string                   `json:"categories_skipped"`            // Names of categories the budget already has
+	AllocationTemplate *domain.AllocationTemplate `json:"allocation_template,omitempty"` // The monthly amounts, ready to apply; nil when there are none
+}
+
+// BudgetTemplateService exports and imports shareable budget templates
+type BudgetTemplateService struct {
+	categoryGroupRepo domain.CategoryGroupRepository
+	categoryRepo      domain.CategoryRepository
+	allocationRepo    domain.AllocationRepository
+	templateRepo      domain.AllocationTemplateRepository
+	uow               domain.UnitOfWork
+}
+
+// NewBudgetTemplateService creates a new budget template service
+func NewBudgetTemplateService(
+	categoryGroupRepo domain.CategoryGroupRepository,
+	categoryRepo domain.CategoryRepository,
+	allocationRepo domain.AllocationRepository,
+	templateRepo domain.AllocationTemplateRepository,
+	uow domain.UnitOfWork,
+) *BudgetTemplateService {
+	return &BudgetTemplateService{
+		categoryGroupRepo: categoryGroupRepo,
+		categoryRepo:      categoryRepo,
+		allocationRepo:    allocationRepo,
+		templateRepo:      templateRepo,
+		uow:               uow,
+	}
+}
+
+// Export describes the budget as a template
+// Each category's monthly amount is its average allocation over the given number of
+// months ending with through (YYYY-MM, "" for the current month). Credit card payment
+// categories are left out since they belong to accounts.
+func (s *BudgetTemplateService) Export(ctx context.Context, name, through string, months int) (*BudgetTemplate, error) {
+	if months == 0 {
+		months = DefaultBudgetTemplateMonths
+	}
+	if months < 1 || months > MaxBudgetTemplateMonths {
+		return nil, fmt.Errorf("months must be between 1 and %d", MaxBudgetTemplateMonths)
+	}
+	if through == "" {
+		through = time.Now().Format("2006-01")
+	}
+	end, err := time.Parse("2006-01", through)
+	if err != nil {
+		return nil, fmt.Errorf("invalid period format, expected YYYY-MM")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "My budget"
+	}
+
+	totals := make(map[string]int64)
+	for i := 0; i < months; i++ {
+		allocations, err := s.allocationRepo.ListByPeriod(ctx, end.AddDate(0, -i, 0).Format("2006-01"))
+		if err != nil {
+			return nil, err
+		}
+		for _, allocation := range allocations {
+			totals[allocation.CategoryID] += allocation.Amount
+		}
+	}
+
+	groups, err := s.categoryGroupRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byGroup := make(map[string][]BudgetTemplateCategory)
+	for _, category := range categories {
+		if category.PaymentForAccountID != nil {
+			continue
+		}
+		groupID := ""
+		if category.GroupID != nil {
+			groupID = *category.GroupID
+		}
+		byGroup[groupID] = append(byGroup[groupID], BudgetTemplateCategory{
+			Name:             category.Name,
+			Description:      category.Description,
+			Color:            category.Color,
+			Classification:   category.Classification,
+			OverspendingMode: category.OverspendingMode,
+			MonthlyAmount:    int64(math.Round(float64(totals[category.ID]) / float64(months))),
+		})
+	}
+
+	template := &BudgetTemplate{
+		Format:  BudgetTemplateFormat,
+		Version: BudgetTemplateVersion,
+		Name:    name,
+		Months:  months,
+		Groups:  []BudgetTemplateGroup{},
+	}
+	for _, group := range groups {
+		if group.Name == domain.CreditCardPaymentsGroupName || len(byGroup[group.ID]) == 0 {
+			continue
+		}
+		template.Groups = append(template.Groups, BudgetTemplateGroup{
+			Name:        group.Name,
+			Description: group.Description,
+			Categories:  byGroup[group.ID],
+		})
+	}
+	if ungrouped := byGroup[""]; len(ungrouped) > 0 {
+		template.Groups = append(template.Groups, BudgetTemplateGroup{Name: budgetTemplateOtherGroup, Categories: ungrouped})
+	}
+	return template, nil
+}
+
+// Import adds a template's groups and categories to the budget
+// Groups and categories are matched by name, ignoring case: existing groups are reused
+// and existing categories are skipped, so importing into a budget that's already set up
+// only fills in what's missing. The monthly amounts are saved as an allocation template
+// named after the budget template rather than assigned, so nothing touches Ready to
+// Assign until the user applies it to a period. Everything is imported or nothing is.
+func (s *BudgetTemplateService) Import(ctx context.Context, template *BudgetTemplate) (*BudgetTemplateImportResult, error) {
+	if err := validateBudgetTemplate(template); err != nil {
+		return nil, err
+	}
+
+	result := &BudgetTemplateImportResult{CategoriesSkipped: []string{}}
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		groups, err := s.categoryGroupRepo.List(ctx)
+		if err != nil {
+			return err
+		}
+		groupIDs := make(map[string]string, len(groups))
+		displayOrder := 0
+		for _, group := range groups {
+			groupIDs[strings.ToLower(group.Name)] = group.ID
+			displayOrder = max(displayOrder, group.DisplayOrder)
+		}
+		categories, err := s.categoryRepo.List(ctx)
+		if err != nil {
+			return err
+		}
+		existing := make(map[string]bool, len(categories))
+		for _, category := range categories {
+			existing[strings.ToLower(category.Name)] = true
+		}
+
+		now := time.Now()
+		var items []domain.AllocationTemplateItem
+		for _, templateGroup := range template.Groups {
+			groupName := strings.TrimSpace(templateGroup.Name)
+			groupID, ok := groupIDs[strings.ToLower(groupName)]
+			if !ok {
+				displayOrder++
+				group := &domain.CategoryGroup{
+					ID:           uuid.New().String(),
+					Name:         groupName,
+					Description:  templateGroup.Description,
+					DisplayOrder: displayOrder,
+					CreatedAt:    now,
+					UpdatedAt:    now,
+				}
+				if err := s.categoryGroupRepo.Create(ctx, group); err != nil {
+					return err
+				}
+				groupID = group.ID
+				groupIDs[strings.ToLower(groupName)] = groupID
+				result.GroupsCreated++
+			}
+
+			for _, templateCategory := range templateGroup.Categories {
+				name := strings.TrimSpace(templateCategory.Name)
+				if existing[strings.ToLower(name)] {
+					result.CategoriesSkipped = append(result.CategoriesSkipped, name)
+					continue
+				}
+				category := &domain.Category{
+					ID:               uuid.New().String(),
+					Name:             name,
+					Description:      templateCategory.Description,
+					Color:            templateCategory.Color,
+					GroupID:          &groupID,
+					Classification:   templateCategory.Classification,
+					OverspendingMode: templateCategory.OverspendingMode,
+					CreatedAt:        now,
+					UpdatedAt:        now,
+				}
+				if category.Classification == "" {
+					category.Classification = domain.ClassificationDiscretionary
+				}
+				if category.OverspendingMode == "" {
+					category.OverspendingMode = domain.OverspendingAuto
+				}
+				if err := s.categoryRepo.Create(ctx, category); err != nil {
+					return err
+				}
+				existing[strings.ToLower(name)] = true
+				result.CategoriesCreated++
+				if templateCategory.MonthlyAmount > 0 {
+					items = append(items, domain.AllocationTemplateItem{CategoryID: category.ID, Amount: templateCategory.MonthlyAmount})
+				}
+			}
+		}
+
+		if len(items) == 0 {
+			return nil
+		}
+		name, err := s.allocationTemplateName(ctx, strings.TrimSpace(template.Name))
+		if err != nil {
+			return err
+		}
+		result.AllocationTemplate = &domain.AllocationTemplate{
+			ID:        uuid.New().String(),
+			Name:      name,
+			Items:     items,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		return s.templateRepo.Create(ctx, result.AllocationTemplate)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// allocationTemplateName returns name, numbered if an allocation template already has it
+func (s *BudgetTemplateService) allocationTemplateName(ctx context.Context, name string) (string, error) {
+	templates, err := s.templateRepo.List(ctx)
+	if err != nil {
+		return "", err
+	}
+	taken := make(map[string]bool, len(templates))
+	for _, template := range templates {
+		taken[strings.ToLower(template.Name)] = true
+	}
+	candidate := name
+	for i := 2; taken[strings.ToLower(candidate)]; i++ {
+		candidate = fmt.Sprintf("%s (%d)", name, i)
+	}
+	return candidate, nil
+}
+
+func validateBudgetTemplate(template *BudgetTemplate) error {
+	if template.Format != BudgetTemplateFormat {
+		return fmt.Errorf("not a budget template")
+	}
+	if template.Version != BudgetTemplateVersion {
+		return fmt.Errorf("unsupported budget template version %d", template.Version)
+	}
+	if strings.TrimSpace(template.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	for _, group := range template.Groups {
+		if strings.TrimSpace(group.Name) == "" {
+			return fmt.Errorf("category group name is required")
+		}
+		if strings.EqualFold(strings.TrimSpace(group.Name), domain.CreditCardPaymentsGroupName) {
+			return fmt.Errorf("a budget template can't include the Credit Card Payments group")
+		}
+		for _, category := range group.Categories {
+			if strings.TrimSpace(category.Name) == "" {
+				return fmt.Errorf("category name is required")
+			}
+			if category.Classification != "" && !category.Classification.IsValid() {
+				return fmt.Errorf("invalid classification - must be essential, discretionary, savings, or debt")
+			}
+			if category.OverspendingMode != "" && !category.OverspendingMode.IsValid() {
+				return fmt.Errorf("invalid overspending_mode - must be auto, cash, or credit")
+			}
+			if category.MonthlyAmount < 0 {
+				return fmt.Errorf("monthly_amount cannot be negative")
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestBudgetTemplateService_ExportImport(t *testing.T) {
+	ctx := context.Background()
+	bills, payments, card := "bills", "payments", "card"
+
+	// The budget being shared
+	groupRepo := newMockCategoryGroupRepository()
+	groupRepo.groups = []*domain.CategoryGroup{
+		{ID: bills, Name: "Bills", Description: "Monthly bills", DisplayOrder: 1},
+		{ID: payments, Name: domain.CreditCardPaymentsGroupName, DisplayOrder: 2},
+	}
+	categoryRepo := newMockCategoryRepository()
+	categoryRepo.categories["rent"] = &domain.Category{ID: "rent", Name: "Rent", GroupID: &bills, Classification: domain.ClassificationEssential, OverspendingMode: domain.OverspendingAuto}
+	categoryRepo.categories["utilities"] = &domain.Category{ID: "utilities", Name: "Utilities", GroupID: &bills, Classification: domain.ClassificationEssential, OverspendingMode: domain.OverspendingCash}
+	categoryRepo.categories["visa"] = &domain.Category{ID: "visa", Name: "Visa Payment", GroupID: &payments, PaymentForAccountID: &card}
+	categoryRepo.categories["gifts"] = &domain.Category{ID: "gifts", Name: "Gifts"}
+	allocationRepo := newMockAllocationRepository()
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a1", CategoryID: "rent", Period: "2025-10", Amount: 100000})
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a2", CategoryID: "rent", Period: "2025-11", Amount: 100000})
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a3", CategoryID: "utilities", Period: "2025-11", Amount: 10001})
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a4", CategoryID: "visa", Period: "2025-11", Amount: 5000})
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a5", CategoryID: "rent", Period: "2025-09", Amount: 90000})
+	exporter := NewBudgetTemplateService(groupRepo, categoryRepo, allocationRepo, &mockAllocationTemplateRepository{},
+		&mockUnitOfWork{newMockAccountRepository(0), newMockTransactionRepository()})
+
+	if _, err := exporter.Export(ctx, "", "2025-11", MaxBudgetTemplateMonths+1); err == nil {
+		t.Error("expected too many months to be rejected")
+	}
+	template, err := exporter.Export(ctx, "Renter basics", "2025-11", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, _ := json.Marshal(template)
+	for _, private := range []string{`"rent"`, `"bills"`, "Visa", domain.CreditCardPaymentsGroupName} {
+		if strings.Contains(string(encoded), private) {
+			t.Errorf("expected %q to be left out of the template, got %s", private, encoded)
+		}
+	}
+	if len(template.Groups) != 2 || template.Groups[0].Name != "Bills" || template.Groups[1].Name != budgetTemplateOtherGroup {
+		t.Fatalf("expected the Bills group and ungrouped categories, got %+v", template.Groups)
+	}
+	amounts := make(map[string]int64)
+	for _, category := range template.Groups[0].Categories {
+		amounts[category.Name] = category.MonthlyAmount
+	}
+	if amounts["Rent"] != 100000 || amounts["Utilities"] != 5001 {
+		t.Errorf("expected two-month averages of 100000 and 5001, got %v", amounts)
+	}
+
+	// Another user's fresh budget
+	importGroups := newMockCategoryGroupRepository()
+	importCategories := newMockCategoryRepository()
+	templateRepo := &mockAllocationTemplateRepository{}
+	importer := NewBudgetTemplateService(importGroups, importCategories, newMockAllocationRepository(), templateRepo,
+		&mockUnitOfWork{newMockAccountRepository(0), newMockTransactionRepository()})
+
+	if _, err := importer.Import(ctx, &BudgetTemplate{Format: "something-else", Version: BudgetTemplateVersion, Name: "x"}); err == nil {
+		t.Error("expected a file that isn't a budget template to be rejected")
+	}
+
+	var shared BudgetTemplate
+	if err := json.Unmarshal(encoded, &shared); err != nil {
+		t.Fatal(err)
+	}
+	result, err := importer.Import(ctx, &shared)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.GroupsCreated != 2 || result.CategoriesCreated != 3 || len(result.CategoriesSkipped) != 0 {
+		t.Errorf("expected 2 groups and 3 categories, got %+v", result)
+	}
+	for _, category := range importCategories.categories {
+		if category.Name == "Utilities" && category.OverspendingMode != domain.OverspendingCash {
+			t.Errorf("expected the overspending mode to be kept, got %q", category.OverspendingMode)
+		}
+	}
+	plan := result.AllocationTemplate
+	if plan == nil || plan.Name != "Renter basics" || len(plan.Items) != 2 {
+		t.Fatalf("expected the monthly amounts as an allocation template, got %+v", plan)
+	}
+
+	// Importing again only fills in what's missing
+	result, err = importer.Import(ctx, &shared)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.GroupsCreated != 0 || result.CategoriesCreated != 0 || len(result.CategoriesSkipped) != 3 || result.AllocationTemplate != nil {
+		t.Errorf("expected everything to be skipped, got %+v", result)
+	}
+}
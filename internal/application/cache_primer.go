@@ -0,0 +1,97 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// CachePriming is what warming the caches at startup did and how long it took
+type CachePriming struct {
+	Period         string              `json:"period"` // Month primed, YYYY-MM
+	StartedAt      time.Time           `json:"started_at"`
+	DurationMillis int64               `json:"duration_ms"`
+	Steps          []*CachePrimingStep `json:"steps"`
+}
+
+// CachePrimingStep is one of the reads priming makes
+// A failed step is recorded and priming carries on; the request that needs it later
+// does the work instead.
+type CachePrimingStep struct {
+	Name           string `json:"name"`
+	DurationMillis int64  `json:"duration_ms"`
+	Error          string `json:"error,omitempty"`
+}
+
+// CachePrimer makes the reads behind the budget dashboard once at startup
+// Ready to Assign for the period goes into its cache; the account, category, group and
+// summary reads have no cache of their own but bring the database pages they touch into
+// memory, which is most of what makes the first request after a restart slow.
+type CachePrimer struct {
+	accountRepo       domain.AccountRepository
+	categoryRepo      domain.CategoryRepository
+	categoryGroupRepo domain.CategoryGroupRepository
+	allocationService *AllocationService
+
+	mu   sync.Mutex
+	last *CachePriming
+}
+
+// NewCachePrimer creates a new cache primer
+func NewCachePrimer(accountRepo domain.AccountRepository, categoryRepo domain.CategoryRepository, categoryGroupRepo domain.CategoryGroupRepository, allocationService *AllocationService) *CachePrimer {
+	return &CachePrimer{
+		accountRepo:       accountRepo,
+		categoryRepo:      categoryRepo,
+		categoryGroupRepo: categoryGroupRepo,
+		allocationService: allocationService,
+	}
+}
+
+// Prime warms the caches for the current month
+func (p *CachePrimer) Prime(ctx context.Context) *CachePriming {
+	return p.PrimePeriod(ctx, time.Now().Format("2006-01"))
+}
+
+// PrimePeriod warms the caches for period
+func (p *CachePrimer) PrimePeriod(ctx context.Context, period string) *CachePriming {
+	priming := &CachePriming{Period: period, StartedAt: time.Now()}
+	for _, step := range []struct {
+		name string
+		run  func() error
+	}{
+		{"accounts", func() error { _, err := p.accountRepo.List(ctx); return err }},
+		{"category_groups", func() error { _, err := p.categoryGroupRepo.List(ctx); return err }},
+		{"categories", func() error { _, err := p.categoryRepo.List(ctx); return err }},
+		{"ready_to_assign", func() error {
+			_, err := p.allocationService.CalculateReadyToAssignForPeriod(ctx, period)
+			return err
+		}},
+		{"allocation_summary", func() error {
+			_, err := p.allocationService.GetAllocationSummary(ctx, period)
+			return err
+		}},
+	} {
+		started := time.Now()
+		result := &CachePrimingStep{Name: step.name}
+		if err := step.run(); err != nil {
+			result.Error = err.Error()
+		}
+		result.DurationMillis = time.Since(started).Milliseconds()
+		priming.Steps = append(priming.Steps, result)
+	}
+	priming.DurationMillis = time.Since(priming.StartedAt).Milliseconds()
+
+	p.mu.Lock()
+	p.last = priming
+	p.mu.Unlock()
+	return priming
+}
+
+// Last returns the most recent priming, or nil if the caches haven't been primed
+func (p *CachePrimer) Last() *CachePriming {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.last
+}
@@ -0,0 +1,57 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestCachePrimer(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	categoryRepo := newMockCategoryRepository()
+	groupRepo := newMockCategoryGroupRepository()
+	budgetStateRepo := newMockBudgetStateRepository(0, 0)
+	transactionRepo := newMockTransactionRepository()
+	transactionRepo.Create(ctx, &domain.Transaction{ID: "pay", Type: domain.TransactionTypeNormal, Amount: 50000, Date: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)})
+
+	allocationService := NewAllocationService(newMockAllocationRepository(), categoryRepo, transactionRepo, budgetStateRepo, accountRepo, groupRepo, newMockGoalRepository())
+	cache := NewReadyToAssignCache()
+	allocationService.UseReadyToAssignCache(cache)
+	primer := NewCachePrimer(accountRepo, categoryRepo, groupRepo, allocationService)
+
+	if primer.Last() != nil {
+		t.Fatal("expected no priming before the first run")
+	}
+	priming := primer.PrimePeriod(ctx, "2025-03")
+	if priming.Period != "2025-03" || len(priming.Steps) != 5 {
+		t.Fatalf("unexpected priming %+v", priming)
+	}
+	for _, step := range priming.Steps {
+		if step.Error != "" {
+			t.Errorf("step %s failed: %s", step.Name, step.Error)
+		}
+	}
+	if amount, ok := cache.Get(budgetStateRepo.state.Revision, "2025-03"); !ok || amount != 50000 {
+		t.Errorf("expected Ready to Assign to be cached, got %d (%v)", amount, ok)
+	}
+
+	// A failing read is recorded and the rest still run
+	categoryRepo.listError = errors.New("disk I/O error")
+	priming = primer.PrimePeriod(ctx, "2025-03")
+	var failed []string
+	for _, step := range priming.Steps {
+		if step.Error != "" {
+			failed = append(failed, step.Name)
+		}
+	}
+	if len(priming.Steps) != 5 || len(failed) == 0 || failed[0] != "categories" {
+		t.Errorf("expected the categories step to fail without stopping priming, got %+v", priming.Steps)
+	}
+	if primer.Last() != priming {
+		t.Error("expected Last to return the latest priming")
+	}
+}
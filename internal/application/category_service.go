@@ -3,6 +3,7 @@ package application
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/billybbuffum/budget/internal/domain"
@@ -11,43 +12,164 @@ import (
 
 // CategoryService handles category-related business logic
 type CategoryService struct {
-	categoryRepo domain.CategoryRepository
+	categoryRepo      domain.CategoryRepository
+	categoryGroupRepo domain.CategoryGroupRepository
+	uow               domain.UnitOfWork
 }
 
 // NewCategoryService creates a new category service
-func NewCategoryService(categoryRepo domain.CategoryRepository) *CategoryService {
-	return &CategoryService{categoryRepo: categoryRepo}
+func NewCategoryService(categoryRepo domain.CategoryRepository, categoryGroupRepo domain.CategoryGroupRepository, uow domain.UnitOfWork) *CategoryService {
+	return &CategoryService{
+		categoryRepo:      categoryRepo,
+		categoryGroupRepo: categoryGroupRepo,
+		uow:               uow,
+	}
+}
+
+// MaxBulkCategories caps how many categories one CreateCategories call creates
+const MaxBulkCategories = 500
+
+// NewCategory is one category for CreateCategories
+type NewCategory struct {
+	Name             string                        `json:"name"`
+	Description      string                        `json:"description"`
+	Color            string                        `json:"color"`
+	GroupID          *string                       `json:"group_id"` // Defaults to the new group, if one is created
+	Classification   domain.CategoryClassification `json:"classification"`
+	OverspendingMode domain.OverspendingMode       `json:"overspending_mode"`
+}
+
+// NewCategoryGroup is a group for CreateCategories to create along with its categories
+type NewCategoryGroup struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// BulkCategoriesResult lists what CreateCategories created
+type BulkCategoriesResult struct {
+	Group      *domain.CategoryGroup `json:"group"` // nil when no group was asked for
+	Categories []*domain.Category    `json:"categories"`
 }
 
 // CreateCategory creates a new category
 // Note: groupID is required - all categories must belong to a group
 // Note: This method is called directly from the API handler for user-created categories
 // AccountService uses the repository directly to create payment categories
-func (s *CategoryService) CreateCategory(ctx context.Context, name, description, color string, groupID *string) (*domain.Category, error) {
+func (s *CategoryService) CreateCategory(ctx context.Context, name, description, color string, groupID *string, classification domain.CategoryClassification, overspendingMode domain.OverspendingMode) (*domain.Category, error) {
+	category, err := newCategory(name, description, color, groupID, classification, overspendingMode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.categoryRepo.Create(ctx, category); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// CreateCategories creates several categories at once, in order, for setup wizards, template
+// imports and migration tools
+// When group is non-nil it is created first, after the existing groups, and categories
+// without a group_id go in it. Every category is validated like CreateCategory, and either
+// all of them are created or none are.
+func (s *CategoryService) CreateCategories(ctx context.Context, group *NewCategoryGroup, categories []NewCategory) (*BulkCategoriesResult, error) {
+	if len(categories) == 0 {
+		return nil, fmt.Errorf("at least one category is required")
+	}
+	if len(categories) > MaxBulkCategories {
+		return nil, fmt.Errorf("at most %d categories can be created at once", MaxBulkCategories)
+	}
+	if group != nil && strings.TrimSpace(group.Name) == "" {
+		return nil, fmt.Errorf("category group name is required")
+	}
+
+	result := &BulkCategoriesResult{Categories: make([]*domain.Category, 0, len(categories))}
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if group != nil {
+			groups, err := s.categoryGroupRepo.List(ctx)
+			if err != nil {
+				return err
+			}
+			displayOrder := 0
+			for _, existing := range groups {
+				displayOrder = max(displayOrder, existing.DisplayOrder)
+			}
+			now := time.Now()
+			result.Group = &domain.CategoryGroup{
+				ID:           uuid.New().String(),
+				Name:         strings.TrimSpace(group.Name),
+				Description:  group.Description,
+				DisplayOrder: displayOrder + 1,
+				CreatedAt:    now,
+				UpdatedAt:    now,
+			}
+			if err := s.categoryGroupRepo.Create(ctx, result.Group); err != nil {
+				return err
+			}
+		}
+
+		for _, input := range categories {
+			groupID := input.GroupID
+			if (groupID == nil || *groupID == "") && result.Group != nil {
+				groupID = &result.Group.ID
+			}
+			category, err := newCategory(input.Name, input.Description, input.Color, groupID, input.Classification, input.OverspendingMode)
+			if err != nil {
+				return err
+			}
+			if err := s.categoryRepo.Create(ctx, category); err != nil {
+				return err
+			}
+			result.Categories = append(result.Categories, category)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// newCategory validates a user-created category and fills in its defaults
+// Note: groupID is required - all categories must belong to a group
+func newCategory(name, description, color string, groupID *string, classification domain.CategoryClassification, overspendingMode domain.OverspendingMode) (*domain.Category, error) {
 	if name == "" {
 		return nil, fmt.Errorf("category name is required")
 	}
 
-	// Require group_id for all user-created categories
-	if groupID == nil || *groupID == "" {
-		return nil, fmt.Errorf("group_id is required - all categories must belong to a group")
+	// Classification defaults to discretionary when not specified
+	if classification == "" {
+		classification = domain.ClassificationDiscretionary
+	}
+	if !classification.IsValid() {
+		return nil, fmt.Errorf("invalid classification - must be essential, discretionary, savings, or debt")
 	}
 
-	category := &domain.Category{
-		ID:          uuid.New().String(),
-		Name:        name,
-		Description: description,
-		Color:       color,
-		GroupID:     groupID,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+	// Overspending is split by how it was spent unless the category says otherwise
+	if overspendingMode == "" {
+		overspendingMode = domain.OverspendingAuto
+	}
+	if !overspendingMode.IsValid() {
+		return nil, fmt.Errorf("invalid overspending_mode - must be auto, cash, or credit")
 	}
 
-	if err := s.categoryRepo.Create(ctx, category); err != nil {
-		return nil, err
+	// Require group_id for all user-created categories
+	if groupID == nil || *groupID == "" {
+		return nil, fmt.Errorf("group_id is required - all categories must belong to a group")
 	}
 
-	return category, nil
+	return &domain.Category{
+		ID:               uuid.New().String(),
+		Name:             name,
+		Description:      description,
+		Color:            color,
+		GroupID:          groupID,
+		Classification:   classification,
+		OverspendingMode: overspendingMode,
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+	}, nil
 }
 
 // GetCategory retrieves a category by ID
@@ -61,7 +183,7 @@ func (s *CategoryService) ListCategories(ctx context.Context) ([]*domain.Categor
 }
 
 // UpdateCategory updates an existing category
-func (s *CategoryService) UpdateCategory(ctx context.Context, id, name, description, color string, groupID *string) (*domain.Category, error) {
+func (s *CategoryService) UpdateCategory(ctx context.Context, id, name, description, color string, groupID *string, classification domain.CategoryClassification, overspendingMode domain.OverspendingMode) (*domain.Category, error) {
 	category, err := s.categoryRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -83,6 +205,18 @@ func (s *CategoryService) UpdateCategory(ctx context.Context, id, name, descript
 		}
 		category.GroupID = groupID
 	}
+	if classification != "" {
+		if !classification.IsValid() {
+			return nil, fmt.Errorf("invalid classification - must be essential, discretionary, savings, or debt")
+		}
+		category.Classification = classification
+	}
+	if overspendingMode != "" {
+		if !overspendingMode.IsValid() {
+			return nil, fmt.Errorf("invalid overspending_mode - must be auto, cash, or credit")
+		}
+		category.OverspendingMode = overspendingMode
+	}
 	category.UpdatedAt = time.Now()
 
 	if err := s.categoryRepo.Update(ctx, category); err != nil {
@@ -0,0 +1,49 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestCategoryService_CreateCategories(t *testing.T) {
+	ctx := context.Background()
+	categoryRepo := newMockCategoryRepository()
+	groupRepo := newMockCategoryGroupRepository()
+	groupRepo.Create(ctx, &domain.CategoryGroup{ID: "bills", Name: "Bills", DisplayOrder: 3})
+	service := NewCategoryService(categoryRepo, groupRepo,
+		&mockUnitOfWork{newMockAccountRepository(0), newMockTransactionRepository()})
+
+	bills := "bills"
+	result, err := service.CreateCategories(ctx, &NewCategoryGroup{Name: " Fun "}, []NewCategory{
+		{Name: "Dining"},
+		{Name: "Rent", GroupID: &bills, Classification: domain.ClassificationEssential},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Group == nil || result.Group.Name != "Fun" || result.Group.DisplayOrder != 4 {
+		t.Fatalf("expected a new group after the existing ones, got %+v", result.Group)
+	}
+	if len(result.Categories) != 2 || *result.Categories[0].GroupID != result.Group.ID || *result.Categories[1].GroupID != bills {
+		t.Fatalf("expected categories without a group in the new one, got %+v", result.Categories)
+	}
+	if result.Categories[0].Classification != domain.ClassificationDiscretionary || result.Categories[0].OverspendingMode != domain.OverspendingAuto {
+		t.Errorf("expected the usual defaults, got %+v", result.Categories[0])
+	}
+	if len(categoryRepo.categories) != 2 {
+		t.Errorf("expected both categories saved, got %d", len(categoryRepo.categories))
+	}
+
+	// Without a new group every category needs one
+	if _, err := service.CreateCategories(ctx, nil, []NewCategory{{Name: "Travel"}}); err == nil {
+		t.Error("expected a category without a group to be rejected")
+	}
+	if _, err := service.CreateCategories(ctx, nil, nil); err == nil {
+		t.Error("expected an empty list to be rejected")
+	}
+	if _, err := service.CreateCategories(ctx, &NewCategoryGroup{Name: " "}, []NewCategory{{Name: "Travel"}}); err == nil {
+		t.Error("expected a group without a name to be rejected")
+	}
+}
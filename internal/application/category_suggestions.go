@@ -0,0 +1,205 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// MaxCategorySuggestions is how many categories are suggested for a transaction
+const MaxCategorySuggestions = 3
+
+// CategorySuggestion is a category a transaction probably belongs in
+type CategorySuggestion struct {
+	CategoryID   string  `json:"category_id"`
+	CategoryName string  `json:"category_name"`
+	Confidence   float64 `json:"confidence"` // Share of the likelihood among every category with similar transactions, 0 to 1
+	Matches      int     `json:"matches"`    // Past transactions in the category with a word or payee in common
+}
+
+// SuggestCategories proposes the categories a transaction most likely belongs in
+// A naive Bayes classifier is trained on the words and payees of every categorized
+// transaction, so the suggestions follow how similar descriptions were categorized before.
+// Only categories with at least one past transaction sharing a word or the payee are
+// suggested; a transaction like nothing seen before gets no suggestions.
+func (s *TransactionService) SuggestCategories(ctx context.Context, transaction *domain.Transaction) ([]CategorySuggestion, error) {
+	if transaction.Type != domain.TransactionTypeNormal {
+		return nil, fmt.Errorf("only normal transactions have categories")
+	}
+
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	names := make(map[string]string, len(categories))
+	for _, category := range categories {
+		names[category.ID] = category.Name
+	}
+	history, err := s.transactionRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	features := categoryFeatures(transaction)
+	model := newCategoryModel(features)
+	for _, past := range history {
+		if past.ID == transaction.ID || past.Type != domain.TransactionTypeNormal || past.CategoryID == nil {
+			continue
+		}
+		if _, ok := names[*past.CategoryID]; ok {
+			model.learn(*past.CategoryID, categoryFeatures(past))
+		}
+	}
+
+	suggestions := model.classify(features)
+	for i := range suggestions {
+		suggestions[i].CategoryName = names[suggestions[i].CategoryID]
+	}
+	if len(suggestions) > MaxCategorySuggestions {
+		suggestions = suggestions[:MaxCategorySuggestions]
+	}
+	return suggestions, nil
+}
+
+// categoryFeatures are the words of a transaction's description and note, its payee and
+// whether it's money in or out
+// Numbers are left out: they're store and reference numbers more often than not.
+func categoryFeatures(transaction *domain.Transaction) []string {
+	text := transaction.Description
+	if transaction.Note != nil {
+		text += " " + *transaction.Note
+	}
+	seen := make(map[string]bool)
+	var features []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) && r != '\'' }) {
+		word = strings.Trim(word, "'")
+		if len([]rune(word)) < 2 || seen[word] {
+			continue
+		}
+		seen[word] = true
+		features = append(features, word)
+	}
+	if transaction.PayeeID != nil {
+		features = append(features, "payee:"+*transaction.PayeeID)
+	}
+	// The direction only weighs categories up or down; it doesn't make a transaction similar
+	if transaction.Amount < 0 {
+		features = append(features, categoryFeatureOutflow)
+	} else {
+		features = append(features, categoryFeatureInflow)
+	}
+	return features
+}
+
+const (
+	categoryFeatureOutflow = "~outflow"
+	categoryFeatureInflow  = "~inflow"
+)
+
+// categoryModel counts how often each feature appears in each category's transactions,
+// and which of them are like the transaction being classified
+type categoryModel struct {
+	similarTo    map[string]bool // The classified transaction's words and payee
+	transactions int
+	byCategory   map[string]*categoryCounts
+	vocabulary   map[string]bool
+}
+
+type categoryCounts struct {
+	transactions int
+	similar      int // Transactions sharing a word or the payee with the classified one
+	features     int
+	counts       map[string]int
+}
+
+func newCategoryModel(features []string) *categoryModel {
+	similarTo := make(map[string]bool, len(features))
+	for _, feature := range features {
+		if feature != categoryFeatureOutflow && feature != categoryFeatureInflow {
+			similarTo[feature] = true
+		}
+	}
+	return &categoryModel{similarTo: similarTo, byCategory: make(map[string]*categoryCounts), vocabulary: make(map[string]bool)}
+}
+
+// learn adds a categorized transaction's features to the model
+func (m *categoryModel) learn(categoryID string, features []string) {
+	counts, ok := m.byCategory[categoryID]
+	if !ok {
+		counts = &categoryCounts{counts: make(map[string]int)}
+		m.byCategory[categoryID] = counts
+	}
+	m.transactions++
+	counts.transactions++
+	counts.features += len(features)
+	similar := false
+	for _, feature := range features {
+		counts.counts[feature]++
+		m.vocabulary[feature] = true
+		similar = similar || m.similarTo[feature]
+	}
+	if similar {
+		counts.similar++
+	}
+}
+
+// classify ranks the categories with transactions like this one, most likely first
+// Features never seen before are ignored, since they say nothing about any category. Each
+// category's log likelihood uses add-one smoothing, and the likelihoods are normalized
+// over the candidates to give each a confidence.
+func (m *categoryModel) classify(features []string) []CategorySuggestion {
+	var known []string
+	for _, feature := range features {
+		if m.vocabulary[feature] {
+			known = append(known, feature)
+		}
+	}
+
+	type candidate struct {
+		categoryID string
+		score      float64
+		similar    int
+	}
+	var candidates []candidate
+	for categoryID, counts := range m.byCategory {
+		if counts.similar == 0 {
+			continue
+		}
+		score := math.Log(float64(counts.transactions) / float64(m.transactions))
+		for _, feature := range known {
+			score += math.Log(float64(counts.counts[feature]+1) / float64(counts.features+len(m.vocabulary)))
+		}
+		candidates = append(candidates, candidate{categoryID: categoryID, score: score, similar: counts.similar})
+	}
+	if len(candidates) == 0 {
+		return []CategorySuggestion{}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].categoryID < candidates[j].categoryID
+	})
+
+	// Softmax relative to the best score, so the exponentials don't underflow
+	var total float64
+	weights := make([]float64, len(candidates))
+	for i, c := range candidates {
+		weights[i] = math.Exp(c.score - candidates[0].score)
+		total += weights[i]
+	}
+	suggestions := make([]CategorySuggestion, len(candidates))
+	for i, c := range candidates {
+		suggestions[i] = CategorySuggestion{
+			CategoryID: c.categoryID,
+			Confidence: math.Round(weights[i]/total*1000) / 1000,
+			Matches:    c.similar,
+		}
+	}
+	return suggestions
+}
@@ -0,0 +1,72 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestTransactionService_SuggestCategories(t *testing.T) {
+	ctx := context.Background()
+	categoryRepo := newMockCategoryRepository()
+	for id, name := range map[string]string{"coffee": "Coffee", "groceries": "Groceries", "fuel": "Fuel", "salary": "Salary", "dining": "Dining"} {
+		categoryRepo.categories[id] = &domain.Category{ID: id, Name: name}
+	}
+	transactionRepo := newMockTransactionRepository()
+	category := func(id string) *string { return &id }
+	for i, txn := range []*domain.Transaction{
+		{Description: "STARBUCKS STORE 00123", Amount: -450, CategoryID: category("coffee")},
+		{Description: "STARBUCKS STORE 00981", Amount: -525, CategoryID: category("coffee")},
+		{Description: "Starbucks Reserve", Amount: -700, CategoryID: category("coffee")},
+		{Description: "STARBUCKS STORE 00123", Amount: -3000, CategoryID: category("groceries")}, // Beans for home
+		{Description: "SAFEWAY STORE 1102", Amount: -8200, CategoryID: category("groceries")},
+		{Description: "SHELL OIL 57442156", Amount: -4000, CategoryID: category("fuel")},
+		{Description: "ACME CORP PAYROLL", Amount: 250000, CategoryID: category("salary")},
+		{Description: "STARBUCKS STORE 00123", Amount: -450, CategoryID: category("deleted")}, // Category since deleted
+		{Description: "STARBUCKS STORE 00123", Amount: -450, Type: domain.TransactionTypeAdjustment, CategoryID: category("dining")},
+	} {
+		txn.ID = string(rune('a' + i))
+		if txn.Type == "" {
+			txn.Type = domain.TransactionTypeNormal
+		}
+		transactionRepo.transactions = append(transactionRepo.transactions, txn)
+	}
+	service := NewTransactionService(transactionRepo, newMockAccountRepository(0), categoryRepo, newMockBudgetStateRepository(0, 0),
+		&mockUnitOfWork{newMockAccountRepository(0), transactionRepo}, DateBounds{})
+
+	suggestions, err := service.SuggestCategories(ctx, &domain.Transaction{ID: "new", Type: domain.TransactionTypeNormal, Description: "STARBUCKS STORE 04410", Amount: -480})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suggestions) != 2 || suggestions[0].CategoryID != "coffee" || suggestions[0].CategoryName != "Coffee" || suggestions[1].CategoryID != "groceries" {
+		t.Fatalf("expected coffee then groceries, got %+v", suggestions)
+	}
+	if suggestions[0].Confidence <= suggestions[1].Confidence || suggestions[0].Confidence+suggestions[1].Confidence < 0.999 {
+		t.Errorf("expected confidences summing to 1 with coffee ahead, got %+v", suggestions)
+	}
+	if suggestions[0].Matches != 3 || suggestions[1].Matches != 2 {
+		t.Errorf("expected 3 similar coffee and 2 similar grocery transactions, got %+v", suggestions)
+	}
+
+	// Words shared by every category still leave at most three suggestions
+	many, err := service.SuggestCategories(ctx, &domain.Transaction{ID: "new", Type: domain.TransactionTypeNormal, Description: "Starbucks Safeway Shell Acme", Amount: -100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(many) != MaxCategorySuggestions {
+		t.Errorf("expected %d suggestions, got %+v", MaxCategorySuggestions, many)
+	}
+
+	unknown, err := service.SuggestCategories(ctx, &domain.Transaction{ID: "new", Type: domain.TransactionTypeNormal, Description: "ZZYZX ROAD 12", Amount: -100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unknown) != 0 {
+		t.Errorf("expected nothing suggested for a transaction like no other, got %+v", unknown)
+	}
+
+	if _, err := service.SuggestCategories(ctx, &domain.Transaction{ID: "transfer", Type: domain.TransactionTypeTransfer, Description: "STARBUCKS"}); err == nil {
+		t.Error("expected transfers to get no suggestions")
+	}
+}
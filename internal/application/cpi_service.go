@@ -0,0 +1,136 @@
+package application
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// CPIProvider fetches consumer price index values from an external source
+// Implementations live in internal/infrastructure/cpi
+type CPIProvider interface {
+	Name() string
+	Fetch(ctx context.Context, startPeriod, endPeriod string) ([]*domain.CPIEntry, error)
+}
+
+// CPIService manages the CPI table used for inflation-adjusted reports
+type CPIService struct {
+	cpiRepo  domain.CPIRepository
+	provider CPIProvider // Optional - nil when no provider is configured
+}
+
+// NewCPIService creates a new CPI service
+// provider may be nil, in which case CPI data can only be uploaded manually
+func NewCPIService(cpiRepo domain.CPIRepository, provider CPIProvider) *CPIService {
+	return &CPIService{
+		cpiRepo:  cpiRepo,
+		provider: provider,
+	}
+}
+
+// ListCPI returns all CPI entries ordered by period
+func (s *CPIService) ListCPI(ctx context.Context) ([]*domain.CPIEntry, error) {
+	return s.cpiRepo.List(ctx)
+}
+
+// UploadCPI validates and saves manually supplied CPI entries
+// Existing values for the same period are replaced
+func (s *CPIService) UploadCPI(ctx context.Context, entries []*domain.CPIEntry) (int, error) {
+	if len(entries) == 0 {
+		return 0, fmt.Errorf("no CPI entries provided")
+	}
+
+	for _, entry := range entries {
+		if _, err := time.Parse("2006-01", entry.Period); err != nil {
+			return 0, fmt.Errorf("invalid period %q, expected YYYY-MM", entry.Period)
+		}
+		if entry.Value <= 0 {
+			return 0, fmt.Errorf("CPI value for %s must be positive", entry.Period)
+		}
+	}
+
+	now := time.Now()
+	for _, entry := range entries {
+		entry.Source = "manual"
+		entry.UpdatedAt = now
+		if err := s.cpiRepo.Upsert(ctx, entry); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(entries), nil
+}
+
+// ParseCPICSV reads "period,value" rows (an optional header row is skipped)
+func (s *CPIService) ParseCPICSV(reader io.Reader) ([]*domain.CPIEntry, error) {
+	rows, err := csv.NewReader(reader).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+
+	var entries []*domain.CPIEntry
+	for i, row := range rows {
+		if len(row) < 2 {
+			return nil, fmt.Errorf("line %d: expected period,value", i+1)
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(row[1]), 64)
+		if err != nil {
+			if i == 0 {
+				continue // Header row
+			}
+			return nil, fmt.Errorf("line %d: invalid CPI value %q", i+1, row[1])
+		}
+		entries = append(entries, &domain.CPIEntry{
+			Period: strings.TrimSpace(row[0]),
+			Value:  value,
+		})
+	}
+
+	return entries, nil
+}
+
+// FetchCPI pulls CPI values for the period range from the configured provider
+func (s *CPIService) FetchCPI(ctx context.Context, startPeriod, endPeriod string) (int, error) {
+	if s.provider == nil {
+		return 0, fmt.Errorf("no CPI provider configured")
+	}
+
+	start, err := time.Parse("2006-01", startPeriod)
+	if err != nil {
+		return 0, fmt.Errorf("invalid start period, expected YYYY-MM")
+	}
+	end, err := time.Parse("2006-01", endPeriod)
+	if err != nil {
+		return 0, fmt.Errorf("invalid end period, expected YYYY-MM")
+	}
+	if end.Before(start) {
+		return 0, fmt.Errorf("end period must not be before start period")
+	}
+
+	entries, err := s.provider.Fetch(ctx, startPeriod, endPeriod)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch CPI from %s: %w", s.provider.Name(), err)
+	}
+
+	now := time.Now()
+	for _, entry := range entries {
+		entry.Source = s.provider.Name()
+		entry.UpdatedAt = now
+		if err := s.cpiRepo.Upsert(ctx, entry); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(entries), nil
+}
+
+// DeleteCPI removes the CPI entry for a period
+func (s *CPIService) DeleteCPI(ctx context.Context, period string) error {
+	return s.cpiRepo.Delete(ctx, period)
+}
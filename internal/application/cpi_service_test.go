@@ -0,0 +1,127 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type mockCPIRepository struct {
+	entries map[string]*domain.CPIEntry
+}
+
+func newMockCPIRepository(values map[string]float64) *mockCPIRepository {
+	repo := &mockCPIRepository{entries: make(map[string]*domain.CPIEntry)}
+	for period, value := range values {
+		repo.entries[period] = &domain.CPIEntry{Period: period, Value: value}
+	}
+	return repo
+}
+
+func (m *mockCPIRepository) Upsert(ctx context.Context, entry *domain.CPIEntry) error {
+	m.entries[entry.Period] = entry
+	return nil
+}
+
+func (m *mockCPIRepository) List(ctx context.Context) ([]*domain.CPIEntry, error) {
+	entries := make([]*domain.CPIEntry, 0, len(m.entries))
+	for _, entry := range m.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Period < entries[j].Period })
+	return entries, nil
+}
+
+func (m *mockCPIRepository) Delete(ctx context.Context, period string) error {
+	delete(m.entries, period)
+	return nil
+}
+
+type fakeCPIProvider struct {
+	entries []*domain.CPIEntry
+	err     error
+}
+
+func (p *fakeCPIProvider) Name() string { return "fake" }
+
+func (p *fakeCPIProvider) Fetch(ctx context.Context, startPeriod, endPeriod string) ([]*domain.CPIEntry, error) {
+	return p.entries, p.err
+}
+
+func TestCPIService_ParseCPICSV(t *testing.T) {
+	service := NewCPIService(newMockCPIRepository(nil), nil)
+
+	entries, err := service.ParseCPICSV(strings.NewReader("period,value\n2025-01, 310.5\n 2025-02 ,311\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Period != "2025-01" || entries[0].Value != 310.5 || entries[1].Period != "2025-02" {
+		t.Errorf("expected the header skipped and both rows read, got %+v %+v", entries[0], entries[1])
+	}
+
+	// Only the first row may be a header
+	if _, err := service.ParseCPICSV(strings.NewReader("2025-01,310.5\n2025-02,high\n")); err == nil {
+		t.Error("expected an unreadable value to be refused")
+	}
+	if _, err := service.ParseCPICSV(strings.NewReader("2025-01\n")); err == nil {
+		t.Error("expected a row without a value to be refused")
+	}
+}
+
+func TestCPIService_UploadCPI(t *testing.T) {
+	ctx := context.Background()
+	repo := newMockCPIRepository(map[string]float64{"2025-01": 300})
+	service := NewCPIService(repo, nil)
+
+	count, err := service.UploadCPI(ctx, []*domain.CPIEntry{{Period: "2025-01", Value: 310}, {Period: "2025-02", Value: 311}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 || repo.entries["2025-01"].Value != 310 || repo.entries["2025-02"].Source != "manual" {
+		t.Errorf("expected both months saved as manual, replacing January, got %d %+v", count, repo.entries)
+	}
+
+	for _, entries := range [][]*domain.CPIEntry{
+		nil,
+		{{Period: "Jan 2025", Value: 310}},
+		{{Period: "2025-03", Value: 0}},
+	} {
+		if _, err := service.UploadCPI(ctx, entries); err == nil {
+			t.Errorf("expected %+v to be refused", entries)
+		}
+	}
+	if _, ok := repo.entries["2025-03"]; ok {
+		t.Error("expected nothing saved from a refused upload")
+	}
+}
+
+func TestCPIService_FetchCPI(t *testing.T) {
+	ctx := context.Background()
+	repo := newMockCPIRepository(nil)
+
+	if _, err := NewCPIService(repo, nil).FetchCPI(ctx, "2025-01", "2025-02"); err == nil {
+		t.Error("expected fetching without a provider to fail")
+	}
+
+	provider := &fakeCPIProvider{entries: []*domain.CPIEntry{{Period: "2025-01", Value: 310}}}
+	service := NewCPIService(repo, provider)
+	if _, err := service.FetchCPI(ctx, "2025-02", "2025-01"); err == nil {
+		t.Error("expected a backwards range to be refused")
+	}
+	count, err := service.FetchCPI(ctx, "2025-01", "2025-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 || repo.entries["2025-01"].Source != "fake" {
+		t.Errorf("expected the fetched month saved under the provider's name, got %+v", repo.entries)
+	}
+
+	provider.err = errors.New("unavailable")
+	if _, err := service.FetchCPI(ctx, "2025-01", "2025-01"); err == nil {
+		t.Error("expected a provider error to be returned")
+	}
+}
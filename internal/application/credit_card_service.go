@@ -0,0 +1,305 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/google/uuid"
+)
+
+// CreditCardService owns the money moved into credit card payment categories
+// Every move is an explicit entry in the category move ledger (see domain.CategoryMove):
+// budgeted card spending, and covering an underfunded card. Available amounts come from
+// the same category ledger the period summaries use.
+type CreditCardService struct {
+	moveRepo          domain.CategoryMoveRepository
+	allocationService *AllocationService
+	uow               domain.UnitOfWork
+	preferences       *UserPreferenceService // nil records spending in monthly periods
+}
+
+// NewCreditCardService creates a new credit card service
+// The allocation service should count the moves too; see AllocationService.UseCategoryMoves.
+func NewCreditCardService(moveRepo domain.CategoryMoveRepository, allocationService *AllocationService, uow domain.UnitOfWork) *CreditCardService {
+	return &CreditCardService{moveRepo: moveRepo, allocationService: allocationService, uow: uow}
+}
+
+// UsePreferences records spending and pays payments in the periods the signed-in user
+// budgets in, rather than always in months
+func (s *CreditCardService) UsePreferences(preferences *UserPreferenceService) {
+	s.preferences = preferences
+}
+
+// UnderfundedCover is what covering an underfunded payment category changed
+type UnderfundedCover struct {
+	Allocations       []*domain.Allocation   `json:"allocations"` // Overspent categories' allocations, raised to cover their card spending
+	Moves             []*domain.CategoryMove `json:"moves"`       // Money moved into the payment category
+	UnderfundedAmount int64                  `json:"underfunded_amount"`
+}
+
+// ListMoves lists the money moved into a payment category, oldest first
+func (s *CreditCardService) ListMoves(ctx context.Context, paymentCategoryID string) ([]*domain.CategoryMove, error) {
+	if _, err := s.paymentCategory(ctx, paymentCategoryID); err != nil {
+		return nil, err
+	}
+	moves, err := s.moveRepo.ListByCategory(ctx, paymentCategoryID)
+	if err != nil {
+		return nil, err
+	}
+	if moves == nil {
+		moves = []*domain.CategoryMove{}
+	}
+	return moves, nil
+}
+
+// RecordSpending moves what a card transaction's category had budgeted for it into the
+// card's payment category
+// Only money that's actually available in the category moves; the rest of the spending
+// leaves the category overspent and the payment category short. transaction must already
+// be saved. Anything but categorized spending on a credit card is ignored.
+func (s *CreditCardService) RecordSpending(ctx context.Context, transaction *domain.Transaction) error {
+	if transaction.Amount >= 0 || transaction.CategoryID == nil || *transaction.CategoryID == "" {
+		return nil
+	}
+	account, err := s.allocationService.accountRepo.GetByID(ctx, transaction.AccountID)
+	if err != nil {
+		return fmt.Errorf("account not found: %w", err)
+	}
+	if account.Type != domain.AccountTypeCredit {
+		return nil
+	}
+	category, err := s.allocationService.categoryRepo.GetByID(ctx, *transaction.CategoryID)
+	if err != nil {
+		return fmt.Errorf("category not found: %w", err)
+	}
+	if isPaymentCategory(category) {
+		return nil
+	}
+	paymentCategory, err := s.allocationService.categoryRepo.GetPaymentCategoryByAccountID(ctx, account.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get payment category: %w", err)
+	}
+
+	periodType := s.periodType(ctx)
+	ledger, err := s.allocationService.loadCategoryLedger(ctx, periodType)
+	if err != nil {
+		return err
+	}
+	// The transaction is already in the ledger: add it back for what was there before it
+	period := domain.PeriodContaining(periodType, transaction.Date).Key
+	available := ledger.roll(category.ID, period, false, category.OverspendingMode).available - transaction.Amount
+	amount := min(-transaction.Amount, available)
+	if amount <= 0 {
+		return nil
+	}
+
+	return s.moveRepo.Create(ctx, &domain.CategoryMove{
+		ID:             uuid.New().String(),
+		FromCategoryID: &category.ID,
+		ToCategoryID:   paymentCategory.ID,
+		Period:         period,
+		Amount:         amount,
+		Reason:         domain.CategoryMoveSpending,
+		TransactionID:  &transaction.ID,
+		CreatedAt:      time.Now(),
+	})
+}
+
+// ReverseSpending takes back what RecordSpending moved for a transaction
+func (s *CreditCardService) ReverseSpending(ctx context.Context, transactionID string) error {
+	return s.moveRepo.DeleteByTransaction(ctx, transactionID)
+}
+
+// PaymentCategoryFor returns the payment category a payment of amount to an account
+// on date is paid from, or nil when it shouldn't be categorized
+// Only credit cards have one, and only a payment the category has enough for is paid from
+// it, so an overpayment doesn't leave the category negative.
+func (s *CreditCardService) PaymentCategoryFor(ctx context.Context, accountID string, amount int64, date time.Time) (*string, error) {
+	account, err := s.allocationService.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("account not found: %w", err)
+	}
+	if account.Type != domain.AccountTypeCredit {
+		return nil, nil
+	}
+	paymentCategory, err := s.allocationService.categoryRepo.GetPaymentCategoryByAccountID(ctx, accountID)
+	if err != nil {
+		return nil, nil
+	}
+
+	periodType := s.periodType(ctx)
+	ledger, err := s.allocationService.loadCategoryLedger(ctx, periodType)
+	if err != nil {
+		return nil, err
+	}
+	available := ledger.roll(paymentCategory.ID, domain.PeriodContaining(periodType, date).Key, true, paymentCategory.OverspendingMode).available
+	if available < amount {
+		return nil, nil
+	}
+	return &paymentCategory.ID, nil
+}
+
+// CoverUnderfunded funds a payment category up to what its card owes, from Ready to Assign
+// Categories overspent on the card are allocated what they spent, and that money moves on
+// into the payment category, as if it had been budgeted before the spending. Any shortfall
+// left, like debt the card was opened with, moves straight from Ready to Assign.
+// Everything is covered in one unit of work, so a failure part way leaves nothing moved.
+func (s *CreditCardService) CoverUnderfunded(ctx context.Context, paymentCategoryID, period string) (*UnderfundedCover, error) {
+	category, err := s.paymentCategory(ctx, paymentCategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	var cover *UnderfundedCover
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		cover, err = s.coverUnderfunded(ctx, category, period)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cover, nil
+}
+
+// coverUnderfunded does the work of CoverUnderfunded
+func (s *CreditCardService) coverUnderfunded(ctx context.Context, category *domain.Category, period string) (*UnderfundedCover, error) {
+
+	summary, err := s.allocationService.summarizeCategory(ctx, category, period, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate allocation summary: %w", err)
+	}
+	if summary.Underfunded == nil || *summary.Underfunded <= 0 {
+		return nil, domain.ErrNotUnderfunded
+	}
+	underfunded := *summary.Underfunded
+
+	readyToAssign, err := s.allocationService.CalculateReadyToAssignForPeriod(ctx, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate Ready to Assign: %w", err)
+	}
+	if readyToAssign < underfunded {
+		return nil, fmt.Errorf(
+			"%w: Ready to Assign: %s, Underfunded: %s",
+			domain.ErrInsufficientFunds,
+			domain.Cents(readyToAssign),
+			domain.Cents(underfunded),
+		)
+	}
+
+	// What each category has spent on the card against everything it was ever allocated
+	transactions, err := s.allocationService.transactionRepo.ListByAccount(ctx, *category.PaymentForAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit card transactions: %w", err)
+	}
+	spending := make(map[string]int64)
+	var order []string
+	for _, txn := range transactions {
+		if txn.CategoryID == nil || *txn.CategoryID == "" || *txn.CategoryID == category.ID || txn.Amount >= 0 {
+			continue
+		}
+		if _, ok := spending[*txn.CategoryID]; !ok {
+			order = append(order, *txn.CategoryID)
+		}
+		spending[*txn.CategoryID] += -txn.Amount
+	}
+	allocated, err := s.allocationService.allocationRepo.SumByCategory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allocations: %w", err)
+	}
+
+	cover := &UnderfundedCover{
+		Allocations:       []*domain.Allocation{},
+		Moves:             []*domain.CategoryMove{},
+		UnderfundedAmount: underfunded,
+	}
+	remaining := underfunded
+	for _, categoryID := range order {
+		amount := min(spending[categoryID]-allocated[categoryID], remaining)
+		if amount <= 0 {
+			continue
+		}
+		allocation, err := s.raiseAllocation(ctx, categoryID, period, amount)
+		if err != nil {
+			return nil, err
+		}
+		cover.Allocations = append(cover.Allocations, allocation)
+		move, err := s.move(ctx, &categoryID, category.ID, period, amount)
+		if err != nil {
+			return nil, err
+		}
+		cover.Moves = append(cover.Moves, move)
+		remaining -= amount
+	}
+	if remaining > 0 {
+		move, err := s.move(ctx, nil, category.ID, period, remaining)
+		if err != nil {
+			return nil, err
+		}
+		cover.Moves = append(cover.Moves, move)
+	}
+	return cover, nil
+}
+
+// raiseAllocation adds amount to a category's allocation for period
+func (s *CreditCardService) raiseAllocation(ctx context.Context, categoryID, period string, amount int64) (*domain.Allocation, error) {
+	var current int64
+	if existing, err := s.allocationService.allocationRepo.GetByCategoryAndPeriod(ctx, categoryID, period); err == nil {
+		current = existing.Amount
+	}
+	allocation, err := s.allocationService.CreateAllocation(ctx, categoryID, current+amount, period, "Cover overspending on credit card")
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate to expense category: %w", err)
+	}
+	return allocation, nil
+}
+
+// move records a cover move into a payment category; from is nil for Ready to Assign
+func (s *CreditCardService) move(ctx context.Context, from *string, to, period string, amount int64) (*domain.CategoryMove, error) {
+	move := &domain.CategoryMove{
+		ID:             uuid.New().String(),
+		FromCategoryID: from,
+		ToCategoryID:   to,
+		Period:         period,
+		Amount:         amount,
+		Reason:         domain.CategoryMoveCover,
+		CreatedAt:      time.Now(),
+	}
+	if err := s.moveRepo.Create(ctx, move); err != nil {
+		return nil, fmt.Errorf("failed to move money to the payment category: %w", err)
+	}
+	return move, nil
+}
+
+// periodType is the period type the signed-in user budgets in
+// Without a user, like for bank sync, or without their preferences, it's months.
+func (s *CreditCardService) periodType(ctx context.Context) domain.PeriodType {
+	principal, ok := PrincipalFromContext(ctx)
+	if s.preferences == nil || !ok || principal.UserID() == nil {
+		return domain.PeriodMonth
+	}
+	prefs, err := s.preferences.GetPreferences(ctx, *principal.UserID())
+	if err != nil || !prefs.PeriodType.IsValid() {
+		return domain.PeriodMonth
+	}
+	return prefs.PeriodType
+}
+
+// paymentCategory gets a category that must be a payment category
+func (s *CreditCardService) paymentCategory(ctx context.Context, id string) (*domain.Category, error) {
+	category, err := s.allocationService.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, domain.ErrCategoryNotFound
+	}
+	if !isPaymentCategory(category) {
+		return nil, domain.ErrNotPaymentCategory
+	}
+	return category, nil
+}
+
+// isPaymentCategory reports whether category is a credit card's payment category
+func isPaymentCategory(category *domain.Category) bool {
+	return category.PaymentForAccountID != nil && *category.PaymentForAccountID != ""
+}
@@ -0,0 +1,795 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type mockCategoryMoveRepository struct {
+	moves []*domain.CategoryMove
+}
+
+func newMockCategoryMoveRepository() *mockCategoryMoveRepository {
+	return &mockCategoryMoveRepository{}
+}
+
+func (m *mockCategoryMoveRepository) Create(ctx context.Context, move *domain.CategoryMove) error {
+	m.moves = append(m.moves, move)
+	return nil
+}
+
+func (m *mockCategoryMoveRepository) List(ctx context.Context) ([]*domain.CategoryMove, error) {
+	return m.moves, nil
+}
+
+func (m *mockCategoryMoveRepository) ListByCategory(ctx context.Context, categoryID string) ([]*domain.CategoryMove, error) {
+	var moves []*domain.CategoryMove
+	for _, move := range m.moves {
+		if move.ToCategoryID == categoryID {
+			moves = append(moves, move)
+		}
+	}
+	return moves, nil
+}
+
+func (m *mockCategoryMoveRepository) DeleteByTransaction(ctx context.Context, transactionID string) error {
+	kept := m.moves[:0]
+	for _, move := range m.moves {
+		if move.TransactionID == nil || *move.TransactionID != transactionID {
+			kept = append(kept, move)
+		}
+	}
+	m.moves = kept
+	return nil
+}
+
+func TestCreditCardService_RecordSpending(t *testing.T) {
+	ctx := context.Background()
+	visaID, groceriesID, paymentID := "visa", "groceries", "visa-payment"
+
+	categoryRepo := newMockCategoryRepository()
+	categoryRepo.categories[groceriesID] = &domain.Category{ID: groceriesID, Name: "Groceries"}
+	categoryRepo.categories[paymentID] = &domain.Category{ID: paymentID, Name: "Visa Payment", PaymentForAccountID: &visaID}
+
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Type: domain.AccountTypeChecking, Balance: 50000}
+	accountRepo.accounts[visaID] = &domain.Account{ID: visaID, Type: domain.AccountTypeCredit, Balance: -10000}
+
+	allocationRepo := newMockAllocationRepository()
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a1", CategoryID: groceriesID, Period: "2025-10", Amount: 6000})
+
+	october := time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)
+	transactionRepo := newMockTransactionRepository()
+	spending := &domain.Transaction{ID: "groceries-run", AccountID: visaID, CategoryID: &groceriesID, Amount: -10000, Date: october}
+	transactionRepo.transactions = []*domain.Transaction{
+		{ID: "income", AccountID: "checking", Type: domain.TransactionTypeNormal, Amount: 50000, Date: october},
+		spending,
+	}
+
+	service := NewAllocationService(allocationRepo, categoryRepo, transactionRepo, newMockBudgetStateRepository(0, 0), accountRepo, newMockCategoryGroupRepository(), newMockGoalRepository())
+	moveRepo := newMockCategoryMoveRepository()
+	service.UseCategoryMoves(moveRepo)
+	creditCards := NewCreditCardService(moveRepo, service, &mockUnitOfWork{accountRepo, transactionRepo})
+
+	t.Run("moves only what was budgeted", func(t *testing.T) {
+		if err := creditCards.RecordSpending(ctx, spending); err != nil {
+			t.Fatalf("RecordSpending() unexpected error = %v", err)
+		}
+		moves, err := creditCards.ListMoves(ctx, paymentID)
+		if err != nil {
+			t.Fatalf("ListMoves() unexpected error = %v", err)
+		}
+		if len(moves) != 1 {
+			t.Fatalf("ListMoves() = %d moves, want 1", len(moves))
+		}
+		move := moves[0]
+		if move.Amount != 6000 || move.Reason != domain.CategoryMoveSpending || move.Period != "2025-10" {
+			t.Errorf("move = %d %s %s, want 6000 spending 2025-10", move.Amount, move.Reason, move.Period)
+		}
+		if move.TransactionID == nil || *move.TransactionID != spending.ID {
+			t.Errorf("move.TransactionID = %v, want %s", move.TransactionID, spending.ID)
+		}
+
+		// The $40 the groceries didn't have leaves the payment category short
+		summary, err := service.GetAllocationSummary(ctx, "2025-10")
+		if err != nil {
+			t.Fatalf("GetAllocationSummary() unexpected error = %v", err)
+		}
+		var payment *domain.AllocationSummary
+		for _, s := range summary {
+			if s.Category.ID == paymentID {
+				payment = s
+			}
+		}
+		if payment == nil || payment.Underfunded == nil || *payment.Underfunded != 4000 {
+			t.Errorf("payment category summary = %+v, want 4000 underfunded", payment)
+		}
+	})
+
+	t.Run("reversing removes the move", func(t *testing.T) {
+		if err := creditCards.ReverseSpending(ctx, spending.ID); err != nil {
+			t.Fatalf("ReverseSpending() unexpected error = %v", err)
+		}
+		if len(moveRepo.moves) != 0 {
+			t.Errorf("after reversing: %d moves, want 0", len(moveRepo.moves))
+		}
+	})
+
+	t.Run("ignores spending from other accounts", func(t *testing.T) {
+		cash := &domain.Transaction{ID: "cash", AccountID: "checking", CategoryID: &groceriesID, Amount: -1000, Date: october}
+		if err := creditCards.RecordSpending(ctx, cash); err != nil {
+			t.Fatalf("RecordSpending() unexpected error = %v", err)
+		}
+		if len(moveRepo.moves) != 0 {
+			t.Errorf("RecordSpending() moved money for checking spending")
+		}
+	})
+
+	t.Run("records spending in the user's period type", func(t *testing.T) {
+		settings := newMockUserSettingRepository()
+		settings.Set(ctx, &domain.UserSetting{UserID: "weekly", Key: domain.UserSettingKeyPeriodType, Value: string(domain.PeriodWeek)})
+		creditCards.UsePreferences(NewUserPreferenceService(settings))
+		defer creditCards.UsePreferences(nil)
+		defer creditCards.ReverseSpending(ctx, spending.ID)
+
+		weekly := WithPrincipal(ctx, &Principal{User: &domain.User{ID: "weekly"}})
+		if err := creditCards.RecordSpending(weekly, spending); err != nil {
+			t.Fatalf("RecordSpending() unexpected error = %v", err)
+		}
+		if len(moveRepo.moves) != 1 || moveRepo.moves[0].Period != "2025-W42" || moveRepo.moves[0].Amount != 6000 {
+			t.Errorf("moves = %+v, want 6000 moved in 2025-W42", moveRepo.moves)
+		}
+	})
+
+	t.Run("rejects categories that aren't payment categories", func(t *testing.T) {
+		if _, err := creditCards.ListMoves(ctx, groceriesID); err != domain.ErrNotPaymentCategory {
+			t.Errorf("ListMoves() error = %v, want %v", err, domain.ErrNotPaymentCategory)
+		}
+	})
+}
+
+func TestCreditCardService_CoverUnderfunded_Success(t *testing.T) {
+	// Setup
+	allocationRepo := newMockAllocationRepository()
+	categoryRepo := newMockCategoryRepository()
+	transactionRepo := newMockTransactionRepository()
+	budgetStateRepo := newMockBudgetStateRepository(100000, 50000) // $1000 balance, $500 RTA
+	accountRepo := newMockAccountRepository(100000)
+
+	// Create credit card account with $200 debt
+	accountID := "credit-card-account-id"
+	creditCardAccount := &domain.Account{
+		ID:        accountID,
+		Name:      "Test Credit Card",
+		Type:      "credit",
+		Balance:   -20000, // Owe $200
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	accountRepo.accounts[accountID] = creditCardAccount
+
+	// Create payment category
+	paymentCategoryID := "payment-category-id"
+	paymentCategory := &domain.Category{
+		ID:                  paymentCategoryID,
+		Name:                "Credit Card Payment",
+		PaymentForAccountID: &accountID,
+		CreatedAt:           time.Now(),
+		UpdatedAt:           time.Now(),
+	}
+	categoryRepo.categories[paymentCategoryID] = paymentCategory
+
+	// Create regular category with spending
+	regularCategoryID := "regular-category-id"
+	regularCategory := &domain.Category{
+		ID:        regularCategoryID,
+		Name:      "Groceries",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	categoryRepo.categories[regularCategoryID] = regularCategory
+
+	// Simulate $200 spent on credit card (underfunded)
+	transactionRepo.categoryActivityResult = 20000 // $200 in cents
+
+	// Add income transaction to provide RTA
+	incomeCategoryID := "income-category-id"
+	incomeCategory := &domain.Category{
+		ID:        incomeCategoryID,
+		Name:      "Salary",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	categoryRepo.categories[incomeCategoryID] = incomeCategory
+
+	incomeTransaction := &domain.Transaction{
+		ID:          "income-txn-id",
+		AccountID:   "checking-account-id",
+		CategoryID:  &incomeCategoryID,
+		Amount:      20000, // $200 income
+		Description: "Salary",
+		Date:        time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	transactionRepo.transactions = append(transactionRepo.transactions, incomeTransaction)
+
+	// Add credit card spending transaction
+	ccTransaction := &domain.Transaction{
+		ID:          "cc-txn-id",
+		AccountID:   accountID,
+		CategoryID:  &regularCategoryID,
+		Amount:      -20000, // $200 spending
+		Description: "Groceries",
+		Date:        time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	transactionRepo.transactions = append(transactionRepo.transactions, ccTransaction)
+
+	service := NewAllocationService(
+		allocationRepo,
+		categoryRepo,
+		transactionRepo,
+		budgetStateRepo,
+		accountRepo,
+		newMockCategoryGroupRepository(),
+		newMockGoalRepository(),
+	)
+	moveRepo := newMockCategoryMoveRepository()
+	service.UseCategoryMoves(moveRepo)
+	creditCards := NewCreditCardService(moveRepo, service, &mockUnitOfWork{accountRepo, transactionRepo})
+
+	// Act
+	cover, err := creditCards.CoverUnderfunded(
+		context.Background(),
+		paymentCategoryID,
+		"2025-10",
+	)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("CoverUnderfunded() unexpected error = %v", err)
+	}
+
+	if cover.UnderfundedAmount != 20000 {
+		t.Errorf("CoverUnderfunded() underfunded = %d, want 20000", cover.UnderfundedAmount)
+	}
+
+	// The overspent groceries are allocated what they spent...
+	if len(cover.Allocations) != 1 {
+		t.Fatalf("CoverUnderfunded() made %d allocations, want 1", len(cover.Allocations))
+	}
+	allocation := cover.Allocations[0]
+	if allocation.CategoryID != regularCategoryID || allocation.Period != "2025-10" || allocation.Amount != 20000 {
+		t.Errorf("CoverUnderfunded() allocation = %s %s %d, want %s 2025-10 20000",
+			allocation.CategoryID, allocation.Period, allocation.Amount, regularCategoryID)
+	}
+
+	// ...and that money moves on into the payment category
+	if len(cover.Moves) != 1 {
+		t.Fatalf("CoverUnderfunded() made %d moves, want 1", len(cover.Moves))
+	}
+	move := cover.Moves[0]
+	if move.FromCategoryID == nil || *move.FromCategoryID != regularCategoryID {
+		t.Errorf("CoverUnderfunded() move.FromCategoryID = %v, want %s", move.FromCategoryID, regularCategoryID)
+	}
+	if move.ToCategoryID != paymentCategoryID || move.Amount != 20000 || move.Reason != domain.CategoryMoveCover {
+		t.Errorf("CoverUnderfunded() move = %s %d %s, want %s 20000 cover",
+			move.ToCategoryID, move.Amount, move.Reason, paymentCategoryID)
+	}
+	if len(moveRepo.moves) != 1 {
+		t.Errorf("CoverUnderfunded() saved %d moves, want 1", len(moveRepo.moves))
+	}
+}
+
+func TestCreditCardService_CoverUnderfunded_CategoryNotFound(t *testing.T) {
+	// Setup
+	allocationRepo := newMockAllocationRepository()
+	categoryRepo := newMockCategoryRepository()
+	transactionRepo := newMockTransactionRepository()
+	budgetStateRepo := newMockBudgetStateRepository(100000, 50000)
+	accountRepo := newMockAccountRepository(100000)
+
+	service := NewAllocationService(
+		allocationRepo,
+		categoryRepo,
+		transactionRepo,
+		budgetStateRepo,
+		accountRepo,
+		newMockCategoryGroupRepository(),
+		newMockGoalRepository(),
+	)
+	moveRepo := newMockCategoryMoveRepository()
+	service.UseCategoryMoves(moveRepo)
+	creditCards := NewCreditCardService(moveRepo, service, &mockUnitOfWork{accountRepo, transactionRepo})
+
+	// Act
+	cover, err := creditCards.CoverUnderfunded(
+		context.Background(),
+		"non-existent-category-id",
+		"2025-10",
+	)
+
+	// Assert
+	if err == nil {
+		t.Fatal("CoverUnderfunded() expected error, got nil")
+	}
+
+	if err.Error() != "category not found" {
+		t.Errorf("CoverUnderfunded() error = %v, want 'category not found'", err)
+	}
+
+	if cover != nil {
+		t.Errorf("CoverUnderfunded() cover should be nil")
+	}
+}
+
+func TestCreditCardService_CoverUnderfunded_NotPaymentCategory(t *testing.T) {
+	// Setup
+	allocationRepo := newMockAllocationRepository()
+	categoryRepo := newMockCategoryRepository()
+	transactionRepo := newMockTransactionRepository()
+	budgetStateRepo := newMockBudgetStateRepository(100000, 50000)
+	accountRepo := newMockAccountRepository(100000)
+
+	// Create regular expense category (not a payment category)
+	regularCategoryID := "regular-category-id"
+	regularCategory := &domain.Category{
+		ID:                  regularCategoryID,
+		Name:                "Groceries",
+		PaymentForAccountID: nil, // NOT a payment category
+		CreatedAt:           time.Now(),
+		UpdatedAt:           time.Now(),
+	}
+	categoryRepo.categories[regularCategoryID] = regularCategory
+
+	service := NewAllocationService(
+		allocationRepo,
+		categoryRepo,
+		transactionRepo,
+		budgetStateRepo,
+		accountRepo,
+		newMockCategoryGroupRepository(),
+		newMockGoalRepository(),
+	)
+	moveRepo := newMockCategoryMoveRepository()
+	service.UseCategoryMoves(moveRepo)
+	creditCards := NewCreditCardService(moveRepo, service, &mockUnitOfWork{accountRepo, transactionRepo})
+
+	// Act
+	cover, err := creditCards.CoverUnderfunded(
+		context.Background(),
+		regularCategoryID,
+		"2025-10",
+	)
+
+	// Assert
+	if err == nil {
+		t.Fatal("CoverUnderfunded() expected error, got nil")
+	}
+
+	if err.Error() != "category is not a payment category" {
+		t.Errorf("CoverUnderfunded() error = %v, want 'category is not a payment category'", err)
+	}
+
+	if cover != nil {
+		t.Errorf("CoverUnderfunded() cover should be nil")
+	}
+}
+
+func TestCreditCardService_CoverUnderfunded_NotUnderfunded(t *testing.T) {
+	// Setup
+	allocationRepo := newMockAllocationRepository()
+	categoryRepo := newMockCategoryRepository()
+	transactionRepo := newMockTransactionRepository()
+	budgetStateRepo := newMockBudgetStateRepository(100000, 50000)
+	accountRepo := newMockAccountRepository(100000)
+
+	// Create payment category
+	accountID := "credit-card-account-id"
+	paymentCategoryID := "payment-category-id"
+	paymentCategory := &domain.Category{
+		ID:                  paymentCategoryID,
+		Name:                "Credit Card Payment",
+		PaymentForAccountID: &accountID,
+		CreatedAt:           time.Now(),
+		UpdatedAt:           time.Now(),
+	}
+	categoryRepo.categories[paymentCategoryID] = paymentCategory
+
+	// Create existing allocation that fully covers spending
+	existingAllocation := &domain.Allocation{
+		ID:         "existing-allocation-id",
+		CategoryID: paymentCategoryID,
+		Period:     "2025-10",
+		Amount:     30000, // $300 allocated
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	allocationRepo.allocations[existingAllocation.ID] = existingAllocation
+	key := fmt.Sprintf("%s:%s", existingAllocation.CategoryID, existingAllocation.Period)
+	allocationRepo.categoryPeriodMap[key] = existingAllocation
+
+	// Simulate $200 spent (less than allocated, so not underfunded)
+	transactionRepo.categoryActivityResult = 20000 // $200 in cents
+
+	service := NewAllocationService(
+		allocationRepo,
+		categoryRepo,
+		transactionRepo,
+		budgetStateRepo,
+		accountRepo,
+		newMockCategoryGroupRepository(),
+		newMockGoalRepository(),
+	)
+	moveRepo := newMockCategoryMoveRepository()
+	service.UseCategoryMoves(moveRepo)
+	creditCards := NewCreditCardService(moveRepo, service, &mockUnitOfWork{accountRepo, transactionRepo})
+
+	// Act
+	cover, err := creditCards.CoverUnderfunded(
+		context.Background(),
+		paymentCategoryID,
+		"2025-10",
+	)
+
+	// Assert
+	if err == nil {
+		t.Fatal("CoverUnderfunded() expected error, got nil")
+	}
+
+	if err.Error() != "payment category is not underfunded" {
+		t.Errorf("CoverUnderfunded() error = %v, want 'payment category is not underfunded'", err)
+	}
+
+	if cover != nil {
+		t.Errorf("CoverUnderfunded() cover should be nil")
+	}
+}
+
+func TestCreditCardService_CoverUnderfunded_InsufficientFunds(t *testing.T) {
+	// Setup
+	allocationRepo := newMockAllocationRepository()
+	categoryRepo := newMockCategoryRepository()
+	transactionRepo := newMockTransactionRepository()
+	budgetStateRepo := newMockBudgetStateRepository(100000, 10000) // Only $100 RTA
+	accountRepo := newMockAccountRepository(100000)
+
+	// Create credit card account with $500 debt
+	accountID := "credit-card-account-id"
+	creditCardAccount := &domain.Account{
+		ID:        accountID,
+		Name:      "Test Credit Card",
+		Type:      "credit",
+		Balance:   -50000, // Owe $500
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	accountRepo.accounts[accountID] = creditCardAccount
+
+	// Create payment category
+	paymentCategoryID := "payment-category-id"
+	paymentCategory := &domain.Category{
+		ID:                  paymentCategoryID,
+		Name:                "Credit Card Payment",
+		PaymentForAccountID: &accountID,
+		CreatedAt:           time.Now(),
+		UpdatedAt:           time.Now(),
+	}
+	categoryRepo.categories[paymentCategoryID] = paymentCategory
+
+	// Simulate $500 spent (underfunded = $500)
+	transactionRepo.categoryActivityResult = 50000 // $500 in cents
+
+	// Add income transaction - only $100, insufficient for $500 underfunded
+	incomeCategoryID := "income-category-id"
+	incomeCategory := &domain.Category{
+		ID:        incomeCategoryID,
+		Name:      "Salary",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	categoryRepo.categories[incomeCategoryID] = incomeCategory
+
+	incomeTransaction := &domain.Transaction{
+		ID:          "income-txn-id",
+		AccountID:   "checking-account-id",
+		CategoryID:  &incomeCategoryID,
+		Amount:      10000, // Only $100 income (insufficient)
+		Description: "Salary",
+		Date:        time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	transactionRepo.transactions = append(transactionRepo.transactions, incomeTransaction)
+
+	service := NewAllocationService(
+		allocationRepo,
+		categoryRepo,
+		transactionRepo,
+		budgetStateRepo,
+		accountRepo,
+		newMockCategoryGroupRepository(),
+		newMockGoalRepository(),
+	)
+	moveRepo := newMockCategoryMoveRepository()
+	service.UseCategoryMoves(moveRepo)
+	creditCards := NewCreditCardService(moveRepo, service, &mockUnitOfWork{accountRepo, transactionRepo})
+
+	// Act
+	cover, err := creditCards.CoverUnderfunded(
+		context.Background(),
+		paymentCategoryID,
+		"2025-10",
+	)
+
+	// Assert
+	if err == nil {
+		t.Fatal("CoverUnderfunded() expected error, got nil")
+	}
+
+	expectedErrMsg := "insufficient funds in Ready to Assign: Ready to Assign: $100.00, Underfunded: $500.00"
+	if err.Error() != expectedErrMsg {
+		t.Errorf("CoverUnderfunded() error = %v, want '%s'", err, expectedErrMsg)
+	}
+
+	if cover != nil {
+		t.Errorf("CoverUnderfunded() cover should be nil")
+	}
+}
+
+func TestCreditCardService_CoverUnderfunded_ExistingAllocation(t *testing.T) {
+	// Setup
+	allocationRepo := newMockAllocationRepository()
+	categoryRepo := newMockCategoryRepository()
+	transactionRepo := newMockTransactionRepository()
+	budgetStateRepo := newMockBudgetStateRepository(100000, 50000) // $1000 balance, $500 RTA
+	accountRepo := newMockAccountRepository(100000)
+
+	// Create credit card account with $150 debt
+	accountID := "credit-card-account-id"
+	creditCardAccount := &domain.Account{
+		ID:        accountID,
+		Name:      "Test Credit Card",
+		Type:      "credit",
+		Balance:   -15000, // Owe $150
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	accountRepo.accounts[accountID] = creditCardAccount
+
+	// Create payment category
+	paymentCategoryID := "payment-category-id"
+	paymentCategory := &domain.Category{
+		ID:                  paymentCategoryID,
+		Name:                "Credit Card Payment",
+		PaymentForAccountID: &accountID,
+		CreatedAt:           time.Now(),
+		UpdatedAt:           time.Now(),
+	}
+	categoryRepo.categories[paymentCategoryID] = paymentCategory
+
+	// Create regular category for spending
+	regularCategoryID := "regular-category-id"
+	regularCategory := &domain.Category{
+		ID:        regularCategoryID,
+		Name:      "Groceries",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	categoryRepo.categories[regularCategoryID] = regularCategory
+
+	// Create existing allocation of $100 to payment category
+	existingAllocation := &domain.Allocation{
+		ID:         "existing-allocation-id",
+		CategoryID: paymentCategoryID,
+		Period:     "2025-10",
+		Amount:     10000, // $100 allocated
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	allocationRepo.allocations[existingAllocation.ID] = existingAllocation
+	key := fmt.Sprintf("%s:%s", existingAllocation.CategoryID, existingAllocation.Period)
+	allocationRepo.categoryPeriodMap[key] = existingAllocation
+
+	// Simulate $150 spent (underfunded = $50 because $100 already allocated)
+	transactionRepo.categoryActivityResult = 15000 // $150 in cents
+
+	// Add income transaction - $150 to cover existing $100 allocation + $50 underfunded
+	incomeCategoryID := "income-category-id"
+	incomeCategory := &domain.Category{
+		ID:        incomeCategoryID,
+		Name:      "Salary",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	categoryRepo.categories[incomeCategoryID] = incomeCategory
+
+	incomeTransaction := &domain.Transaction{
+		ID:          "income-txn-id",
+		AccountID:   "checking-account-id",
+		CategoryID:  &incomeCategoryID,
+		Amount:      15000, // $150 income
+		Description: "Salary",
+		Date:        time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	transactionRepo.transactions = append(transactionRepo.transactions, incomeTransaction)
+
+	// Add credit card spending transaction
+	ccTransaction := &domain.Transaction{
+		ID:          "cc-txn-id",
+		AccountID:   accountID,
+		CategoryID:  &regularCategoryID,
+		Amount:      -15000, // $150 spending
+		Description: "Groceries",
+		Date:        time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	transactionRepo.transactions = append(transactionRepo.transactions, ccTransaction)
+
+	service := NewAllocationService(
+		allocationRepo,
+		categoryRepo,
+		transactionRepo,
+		budgetStateRepo,
+		accountRepo,
+		newMockCategoryGroupRepository(),
+		newMockGoalRepository(),
+	)
+	moveRepo := newMockCategoryMoveRepository()
+	service.UseCategoryMoves(moveRepo)
+	creditCards := NewCreditCardService(moveRepo, service, &mockUnitOfWork{accountRepo, transactionRepo})
+
+	// Act
+	cover, err := creditCards.CoverUnderfunded(
+		context.Background(),
+		paymentCategoryID,
+		"2025-10",
+	)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("CoverUnderfunded() unexpected error = %v", err)
+	}
+
+	if cover.UnderfundedAmount != 5000 {
+		t.Errorf("CoverUnderfunded() underfunded = %d, want 5000", cover.UnderfundedAmount)
+	}
+
+	// What was already set aside in the payment category stays as it was
+	if existing := allocationRepo.categoryPeriodMap[key]; existing.Amount != 10000 {
+		t.Errorf("CoverUnderfunded() payment category allocation = %d, want 10000", existing.Amount)
+	}
+
+	// Only the $50 shortfall moves in
+	var moved int64
+	for _, move := range moveRepo.moves {
+		if move.ToCategoryID == paymentCategoryID {
+			moved += move.Amount
+		}
+	}
+	if moved != 5000 {
+		t.Errorf("CoverUnderfunded() moved %d into the payment category, want 5000", moved)
+	}
+}
+
+func TestCreditCardService_CoverUnderfunded_ExactlyEnoughFunds(t *testing.T) {
+	// Setup
+	allocationRepo := newMockAllocationRepository()
+	categoryRepo := newMockCategoryRepository()
+	transactionRepo := newMockTransactionRepository()
+	budgetStateRepo := newMockBudgetStateRepository(100000, 20000) // Exactly $200 RTA
+	accountRepo := newMockAccountRepository(100000)
+
+	// Create credit card account with $200 debt
+	accountID := "credit-card-account-id"
+	creditCardAccount := &domain.Account{
+		ID:        accountID,
+		Name:      "Test Credit Card",
+		Type:      "credit",
+		Balance:   -20000, // Owe $200
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	accountRepo.accounts[accountID] = creditCardAccount
+
+	// Create payment category
+	paymentCategoryID := "payment-category-id"
+	paymentCategory := &domain.Category{
+		ID:                  paymentCategoryID,
+		Name:                "Credit Card Payment",
+		PaymentForAccountID: &accountID,
+		CreatedAt:           time.Now(),
+		UpdatedAt:           time.Now(),
+	}
+	categoryRepo.categories[paymentCategoryID] = paymentCategory
+
+	// Create regular category for spending
+	regularCategoryID := "regular-category-id"
+	regularCategory := &domain.Category{
+		ID:        regularCategoryID,
+		Name:      "Groceries",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	categoryRepo.categories[regularCategoryID] = regularCategory
+
+	// Simulate exactly $200 spent (underfunded = $200, exactly matches RTA)
+	transactionRepo.categoryActivityResult = 20000 // $200 in cents
+
+	// Add income transaction - exactly $200 to match underfunded amount
+	incomeCategoryID := "income-category-id"
+	incomeCategory := &domain.Category{
+		ID:        incomeCategoryID,
+		Name:      "Salary",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	categoryRepo.categories[incomeCategoryID] = incomeCategory
+
+	incomeTransaction := &domain.Transaction{
+		ID:          "income-txn-id",
+		AccountID:   "checking-account-id",
+		CategoryID:  &incomeCategoryID,
+		Amount:      20000, // Exactly $200 income
+		Description: "Salary",
+		Date:        time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	transactionRepo.transactions = append(transactionRepo.transactions, incomeTransaction)
+
+	// Add credit card spending transaction
+	ccTransaction := &domain.Transaction{
+		ID:          "cc-txn-id",
+		AccountID:   accountID,
+		CategoryID:  &regularCategoryID,
+		Amount:      -20000, // $200 spending
+		Description: "Groceries",
+		Date:        time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	transactionRepo.transactions = append(transactionRepo.transactions, ccTransaction)
+
+	service := NewAllocationService(
+		allocationRepo,
+		categoryRepo,
+		transactionRepo,
+		budgetStateRepo,
+		accountRepo,
+		newMockCategoryGroupRepository(),
+		newMockGoalRepository(),
+	)
+	moveRepo := newMockCategoryMoveRepository()
+	service.UseCategoryMoves(moveRepo)
+	creditCards := NewCreditCardService(moveRepo, service, &mockUnitOfWork{accountRepo, transactionRepo})
+
+	// Act
+	cover, err := creditCards.CoverUnderfunded(
+		context.Background(),
+		paymentCategoryID,
+		"2025-10",
+	)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("CoverUnderfunded() unexpected error = %v", err)
+	}
+
+	if cover.UnderfundedAmount != 20000 {
+		t.Errorf("CoverUnderfunded() underfunded = %d, want 20000", cover.UnderfundedAmount)
+	}
+
+	if len(cover.Moves) != 1 || cover.Moves[0].Amount != 20000 {
+		t.Errorf("CoverUnderfunded() moves = %v, want one of 20000", cover.Moves)
+	}
+}
@@ -0,0 +1,30 @@
+package application
+
+import (
+	"fmt"
+	"time"
+)
+
+// DateBounds limits how far from today a transaction can be dated
+// Dates outside are nearly always typos, like 2205 for 2025, that would otherwise skew
+// every report from then on. 0 leaves that side unlimited.
+type DateBounds struct {
+	PastYears  int // How many years back a transaction may be dated
+	FutureDays int // How many days ahead a transaction may be dated
+}
+
+// futureImportSlack is how far ahead an imported date may be before it is flagged
+// Banks date transactions in their own time zone, which can be a day ahead of UTC.
+const futureImportSlack = 24 * time.Hour
+
+// Check returns an error if date falls outside the bounds
+func (b DateBounds) Check(date time.Time) error {
+	now := time.Now()
+	if b.PastYears > 0 && date.Before(now.AddDate(-b.PastYears, 0, 0)) {
+		return fmt.Errorf("transaction date %s is more than %d years in the past", date.Format("2006-01-02"), b.PastYears)
+	}
+	if b.FutureDays > 0 && date.After(now.AddDate(0, 0, b.FutureDays)) {
+		return fmt.Errorf("transaction date %s is more than %d days in the future", date.Format("2006-01-02"), b.FutureDays)
+	}
+	return nil
+}
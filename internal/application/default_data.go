@@ -2,113 +2,326 @@ package application
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/billybbuffum/budget/internal/i18n"
 	"github.com/google/uuid"
 )
 
+// Starter template IDs
+const (
+	StarterTemplateRenter    = "renter"
+	StarterTemplateHomeowner = "homeowner"
+	StarterTemplateStudent   = "student"
+	StarterTemplateMinimal   = "minimal"
+	StarterTemplateEmpty     = "empty" // Start with no categories
+
+	// StarterTemplateNone skips setup at startup, leaving the choice to ApplyTemplate
+	StarterTemplateNone = "none"
+)
+
+// ErrBudgetAlreadySetUp is returned when applying a starter template to a budget that already has one
+var ErrBudgetAlreadySetUp = errors.New("the budget has already been set up")
+
 // DefaultCategoryGroup represents a default category group to be created
 type DefaultCategoryGroup struct {
-	Name         string
-	Description  string
-	DisplayOrder int
-	Categories   []DefaultCategory
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	DisplayOrder int               `json:"display_order"`
+	Categories   []DefaultCategory `json:"categories"`
 }
 
 // DefaultCategory represents a default category to be created
 type DefaultCategory struct {
-	Name        string
-	Description string
-	Color       string
+	Name           string                        `json:"name"`
+	Description    string                        `json:"description"`
+	Color          string                        `json:"color"`
+	Classification domain.CategoryClassification `json:"classification"`
 }
 
-// GetDefaultCategoryGroups returns the default category groups and categories
-func GetDefaultCategoryGroups() []DefaultCategoryGroup {
-	return []DefaultCategoryGroup{
+// StarterTemplate is a ready-made set of category groups a new budget can start from
+type StarterTemplate struct {
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Groups      []DefaultCategoryGroup `json:"groups"`
+}
+
+// Categories shared between templates
+var (
+	categoryRent          = DefaultCategory{Name: "Rent", Description: "Monthly rent payment", Color: "#3B82F6", Classification: domain.ClassificationEssential}
+	categoryUtilities     = DefaultCategory{Name: "Utilities", Description: "Electric, water, gas, internet", Color: "#EAB308", Classification: domain.ClassificationEssential}
+	categoryPhone         = DefaultCategory{Name: "Phone", Description: "Cell phone bill", Color: "#8B5CF6", Classification: domain.ClassificationEssential}
+	categoryFuel          = DefaultCategory{Name: "Gas/Fuel", Description: "Gasoline, fuel, public transit", Color: "#F59E0B", Classification: domain.ClassificationEssential}
+	categoryCar           = DefaultCategory{Name: "Car Payment & Insurance", Description: "Auto loan and insurance", Color: "#EF4444", Classification: domain.ClassificationEssential}
+	categoryGroceries     = DefaultCategory{Name: "Groceries", Description: "Supermarket and grocery stores", Color: "#10B981", Classification: domain.ClassificationEssential}
+	categoryRestaurants   = DefaultCategory{Name: "Restaurants", Description: "Dining out, coffee, etc.", Color: "#F59E0B", Classification: domain.ClassificationDiscretionary}
+	categoryShopping      = DefaultCategory{Name: "Shopping", Description: "Clothing, personal items", Color: "#EC4899", Classification: domain.ClassificationDiscretionary}
+	categoryEntertainment = DefaultCategory{Name: "Entertainment", Description: "Movies, hobbies, streaming services", Color: "#06B6D4", Classification: domain.ClassificationDiscretionary}
+	categoryEmergencyFund = DefaultCategory{Name: "Emergency Fund", Description: "Savings for unexpected expenses", Color: "#14B8A6", Classification: domain.ClassificationSavings}
+	categoryMiscellaneous = DefaultCategory{Name: "Miscellaneous", Description: "Uncategorized expenses", Color: "#6B7280", Classification: domain.ClassificationDiscretionary}
+)
+
+// starterTemplates returns the built-in templates in English
+func starterTemplates() []StarterTemplate {
+	transportation := DefaultCategoryGroup{
+		Name:        "Transportation",
+		Description: "Vehicle and travel expenses",
+		Categories:  []DefaultCategory{categoryFuel, categoryCar},
+	}
+	food := DefaultCategoryGroup{
+		Name:        "Food & Dining",
+		Description: "Groceries and eating out",
+		Categories:  []DefaultCategory{categoryGroceries, categoryRestaurants},
+	}
+	lifestyle := DefaultCategoryGroup{
+		Name:        "Personal & Lifestyle",
+		Description: "Personal care, entertainment, and subscriptions",
+		Categories:  []DefaultCategory{categoryShopping, categoryEntertainment},
+	}
+	savings := DefaultCategoryGroup{
+		Name:        "Savings",
+		Description: "Money set aside for later",
+		Categories:  []DefaultCategory{categoryEmergencyFund},
+	}
+	other := DefaultCategoryGroup{
+		Name:        "Other",
+		Description: "Everything else",
+		Categories:  []DefaultCategory{categoryMiscellaneous},
+	}
+
+	return []StarterTemplate{
 		{
-			Name:         "Housing & Bills",
-			Description:  "Home, utilities, and recurring bills",
-			DisplayOrder: 1,
-			Categories: []DefaultCategory{
-				{Name: "Rent/Mortgage", Description: "Monthly rent or mortgage payment", Color: "#3B82F6"},
-				{Name: "Utilities", Description: "Electric, water, gas, internet", Color: "#EAB308"},
-				{Name: "Phone", Description: "Cell phone bill", Color: "#8B5CF6"},
+			ID:          StarterTemplateRenter,
+			Name:        "Renter",
+			Description: "Rent, bills, a car and everyday spending",
+			Groups: []DefaultCategoryGroup{
+				{
+					Name:        "Housing & Bills",
+					Description: "Home, utilities, and recurring bills",
+					Categories: []DefaultCategory{
+						categoryRent,
+						{Name: "Renters Insurance", Description: "Coverage for your belongings", Color: "#6366F1", Classification: domain.ClassificationEssential},
+						categoryUtilities,
+						categoryPhone,
+					},
+				},
+				transportation, food, lifestyle, savings, other,
 			},
 		},
 		{
-			Name:         "Transportation",
-			Description:  "Vehicle and travel expenses",
-			DisplayOrder: 2,
-			Categories: []DefaultCategory{
-				{Name: "Gas/Fuel", Description: "Gasoline, fuel, public transit", Color: "#F59E0B"},
-				{Name: "Car Payment & Insurance", Description: "Auto loan and insurance", Color: "#EF4444"},
+			ID:          StarterTemplateHomeowner,
+			Name:        "Homeowner",
+			Description: "Mortgage, property costs and upkeep alongside everyday spending",
+			Groups: []DefaultCategoryGroup{
+				{
+					Name:        "Housing & Bills",
+					Description: "Home, utilities, and recurring bills",
+					Categories: []DefaultCategory{
+						{Name: "Mortgage", Description: "Monthly mortgage payment", Color: "#3B82F6", Classification: domain.ClassificationEssential},
+						{Name: "Property Taxes", Description: "Property tax installments", Color: "#0EA5E9", Classification: domain.ClassificationEssential},
+						{Name: "Home Insurance", Description: "Homeowners insurance", Color: "#6366F1", Classification: domain.ClassificationEssential},
+						categoryUtilities,
+						categoryPhone,
+					},
+				},
+				{
+					Name:        "Home Maintenance",
+					Description: "Keeping the house in shape",
+					Categories: []DefaultCategory{
+						{Name: "Repairs & Upkeep", Description: "Repairs, appliances and yard work", Color: "#84CC16", Classification: domain.ClassificationEssential},
+					},
+				},
+				transportation, food, lifestyle, savings, other,
 			},
 		},
 		{
-			Name:         "Food & Dining",
-			Description:  "Groceries and eating out",
-			DisplayOrder: 3,
-			Categories: []DefaultCategory{
-				{Name: "Groceries", Description: "Supermarket and grocery stores", Color: "#10B981"},
-				{Name: "Restaurants", Description: "Dining out, coffee, etc.", Color: "#F59E0B"},
+			ID:          StarterTemplateStudent,
+			Name:        "Student",
+			Description: "Tuition, books and a tight everyday budget",
+			Groups: []DefaultCategoryGroup{
+				{
+					Name:        "School",
+					Description: "Tuition and study costs",
+					Categories: []DefaultCategory{
+						{Name: "Tuition & Fees", Description: "Tuition, registration and lab fees", Color: "#3B82F6", Classification: domain.ClassificationEssential},
+						{Name: "Books & Supplies", Description: "Textbooks, software and supplies", Color: "#8B5CF6", Classification: domain.ClassificationEssential},
+					},
+				},
+				{
+					Name:        "Housing & Bills",
+					Description: "Home, utilities, and recurring bills",
+					Categories:  []DefaultCategory{categoryRent, categoryUtilities, categoryPhone},
+				},
+				food,
+				{
+					Name:        "Personal & Lifestyle",
+					Description: "Personal care, entertainment, and subscriptions",
+					Categories:  []DefaultCategory{categoryEntertainment},
+				},
+				other,
 			},
 		},
 		{
-			Name:         "Personal & Lifestyle",
-			Description:  "Personal care, entertainment, and subscriptions",
-			DisplayOrder: 4,
-			Categories: []DefaultCategory{
-				{Name: "Shopping", Description: "Clothing, personal items", Color: "#EC4899"},
-				{Name: "Entertainment", Description: "Movies, hobbies, streaming services", Color: "#06B6D4"},
+			ID:          StarterTemplateMinimal,
+			Name:        "Minimal",
+			Description: "A handful of broad categories to refine later",
+			Groups: []DefaultCategoryGroup{
+				{
+					Name:        "Essentials",
+					Description: "Bills and everyday needs",
+					Categories: []DefaultCategory{
+						{Name: "Bills", Description: "Rent, utilities and other regular bills", Color: "#3B82F6", Classification: domain.ClassificationEssential},
+						categoryGroceries,
+					},
+				},
+				{
+					Name:        "Spending",
+					Description: "Everything that isn't a bill",
+					Categories: []DefaultCategory{
+						{Name: "Spending Money", Description: "Eating out, shopping and fun", Color: "#EC4899", Classification: domain.ClassificationDiscretionary},
+					},
+				},
+				savings,
 			},
 		},
 		{
-			Name:         "Other",
-			Description:  "Everything else",
-			DisplayOrder: 5,
-			Categories: []DefaultCategory{
-				{Name: "Miscellaneous", Description: "Uncategorized expenses", Color: "#6B7280"},
-			},
+			ID:          StarterTemplateEmpty,
+			Name:        "Empty",
+			Description: "No categories; build your own",
+			Groups:      []DefaultCategoryGroup{},
 		},
 	}
 }
 
+// GetStarterTemplates returns the built-in starter templates
+// Category names and descriptions are translated into lang when a translation exists.
+func GetStarterTemplates(lang string) []StarterTemplate {
+	templates := starterTemplates()
+	for t := range templates {
+		// Shared groups are copied by value but share their category slices, so build new ones
+		groups := make([]DefaultCategoryGroup, len(templates[t].Groups))
+		for i, group := range templates[t].Groups {
+			group.DisplayOrder = i + 1
+			group.Name = i18n.Translate(lang, group.Name)
+			group.Description = i18n.Translate(lang, group.Description)
+			categories := make([]DefaultCategory, len(group.Categories))
+			for j, category := range group.Categories {
+				category.Name = i18n.Translate(lang, category.Name)
+				category.Description = i18n.Translate(lang, category.Description)
+				categories[j] = category
+			}
+			group.Categories = categories
+			groups[i] = group
+		}
+		templates[t].Groups = groups
+	}
+	return templates
+}
+
+// GetStarterTemplate returns the starter template with the given ID
+func GetStarterTemplate(id, lang string) (*StarterTemplate, error) {
+	for _, template := range GetStarterTemplates(lang) {
+		if template.ID == id {
+			return &template, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown starter template %q", id)
+}
+
+// BootstrapStatus reports whether the budget still needs a starter template
+type BootstrapStatus struct {
+	NeedsTemplate bool                            `json:"needs_template"`
+	Applied       *domain.StarterTemplateSettings `json:"applied,omitempty"` // nil for budgets set up before templates existed
+	Templates     []StarterTemplate               `json:"templates"`
+}
+
 // BootstrapService handles initialization of default data
 type BootstrapService struct {
 	categoryGroupRepo domain.CategoryGroupRepository
 	categoryRepo      domain.CategoryRepository
+	settingRepo       domain.SettingRepository
+	lang              string // Default language of seeded category names, one of i18n.Supported
+
+	mu sync.Mutex // Serializes applying templates
 }
 
 // NewBootstrapService creates a new bootstrap service
+// locale may be any language tag; it is matched to the closest supported language.
 func NewBootstrapService(
 	categoryGroupRepo domain.CategoryGroupRepository,
 	categoryRepo domain.CategoryRepository,
+	settingRepo domain.SettingRepository,
+	locale string,
 ) *BootstrapService {
 	return &BootstrapService{
 		categoryGroupRepo: categoryGroupRepo,
 		categoryRepo:      categoryRepo,
+		settingRepo:       settingRepo,
+		lang:              i18n.Negotiate(locale),
 	}
 }
 
-// InitializeDefaultData creates default category groups and categories if they don't exist
-func (s *BootstrapService) InitializeDefaultData(ctx context.Context) error {
-	// Check if any category groups already exist
-	existingGroups, err := s.categoryGroupRepo.List(ctx)
-	if err != nil {
+// InitializeDefaultData sets a new budget up from the given starter template
+// Budgets that are already set up are left alone; StarterTemplateNone leaves the
+// choice to ApplyTemplate.
+func (s *BootstrapService) InitializeDefaultData(ctx context.Context, templateID string) error {
+	if templateID == StarterTemplateNone {
+		return nil
+	}
+	if _, err := GetStarterTemplate(templateID, s.lang); err != nil {
 		return err
 	}
 
-	// If groups already exist, skip initialization
-	if len(existingGroups) > 0 {
+	_, err := s.ApplyTemplate(ctx, templateID, "")
+	if errors.Is(err, ErrBudgetAlreadySetUp) {
 		return nil
 	}
+	return err
+}
 
-	// Create default groups and categories
-	defaultGroups := GetDefaultCategoryGroups()
-	now := time.Now()
+// GetStatus reports whether a starter template still needs choosing and lists the
+// templates in the given language ("" for the default language)
+func (s *BootstrapService) GetStatus(ctx context.Context, locale string) (*BootstrapStatus, error) {
+	applied, needsTemplate, err := s.status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &BootstrapStatus{
+		NeedsTemplate: needsTemplate,
+		Applied:       applied,
+		Templates:     GetStarterTemplates(s.language(locale)),
+	}, nil
+}
+
+// ApplyTemplate creates the template's category groups and categories
+// locale selects the language of the names ("" for the default language).
+// Only a budget without categories can be set up.
+func (s *BootstrapService) ApplyTemplate(ctx context.Context, templateID, locale string) (*domain.StarterTemplateSettings, error) {
+	lang := s.language(locale)
+	template, err := GetStarterTemplate(templateID, lang)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, defaultGroup := range defaultGroups {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, needsTemplate, err := s.status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !needsTemplate {
+		return nil, ErrBudgetAlreadySetUp
+	}
+
+	now := time.Now()
+	for _, defaultGroup := range template.Groups {
 		// Create the group
 		groupID := uuid.New().String()
 		group := &domain.CategoryGroup{
@@ -121,26 +334,72 @@ func (s *BootstrapService) InitializeDefaultData(ctx context.Context) error {
 		}
 
 		if err := s.categoryGroupRepo.Create(ctx, group); err != nil {
-			return err
+			return nil, err
 		}
 
 		// Create categories for this group
 		for _, defaultCat := range defaultGroup.Categories {
 			category := &domain.Category{
-				ID:          uuid.New().String(),
-				Name:        defaultCat.Name,
-				Description: defaultCat.Description,
-				Color:       defaultCat.Color,
-				GroupID:     &groupID,
-				CreatedAt:   now,
-				UpdatedAt:   now,
+				ID:               uuid.New().String(),
+				Name:             defaultCat.Name,
+				Description:      defaultCat.Description,
+				Color:            defaultCat.Color,
+				GroupID:          &groupID,
+				Classification:   defaultCat.Classification,
+				OverspendingMode: domain.OverspendingAuto,
+				CreatedAt:        now,
+				UpdatedAt:        now,
 			}
 
 			if err := s.categoryRepo.Create(ctx, category); err != nil {
-				return err
+				return nil, err
 			}
 		}
 	}
 
-	return nil
+	applied := &domain.StarterTemplateSettings{Template: template.ID, Locale: lang, AppliedAt: now}
+	value, err := json.Marshal(applied)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode starter template settings: %w", err)
+	}
+	if err := s.settingRepo.Set(ctx, &domain.Setting{
+		Key:       domain.SettingKeyStarterTemplate,
+		Value:     string(value),
+		UpdatedAt: now,
+	}); err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+// status returns the applied template, if any, and whether the budget still needs one
+// Budgets with their own category groups (other than the auto-managed credit card
+// payments group) count as set up.
+func (s *BootstrapService) status(ctx context.Context) (*domain.StarterTemplateSettings, bool, error) {
+	if setting, err := s.settingRepo.Get(ctx, domain.SettingKeyStarterTemplate); err == nil {
+		applied := &domain.StarterTemplateSettings{}
+		if err := json.Unmarshal([]byte(setting.Value), applied); err != nil {
+			return nil, false, fmt.Errorf("failed to decode starter template settings: %w", err)
+		}
+		return applied, false, nil
+	}
+
+	groups, err := s.categoryGroupRepo.List(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, group := range groups {
+		if group.Name != domain.CreditCardPaymentsGroupName {
+			return nil, false, nil
+		}
+	}
+	return nil, true, nil
+}
+
+func (s *BootstrapService) language(locale string) string {
+	if locale == "" {
+		return s.lang
+	}
+	return i18n.Negotiate(locale)
 }
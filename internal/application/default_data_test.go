@@ -0,0 +1,52 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/billybbuffum/budget/internal/i18n"
+)
+
+func TestStarterTemplatesAreTranslated(t *testing.T) {
+	// Words that are spelled the same in some languages
+	loanwords := map[string]bool{"Restaurants": true}
+
+	for _, template := range starterTemplates() {
+		for _, group := range template.Groups {
+			texts := []string{group.Name, group.Description}
+			for _, category := range group.Categories {
+				texts = append(texts, category.Name, category.Description)
+			}
+			for _, lang := range i18n.Supported[1:] {
+				for _, text := range texts {
+					if i18n.Translate(lang, text) == text && !loanwords[text] {
+						t.Errorf("%s template: %q has no %s translation", template.ID, text, lang)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestGetStarterTemplate(t *testing.T) {
+	template, err := GetStarterTemplate(StarterTemplateStudent, "de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := template.Groups[0].Name; got != "Studium" {
+		t.Errorf("expected the first group in German, got %q", got)
+	}
+	for i, group := range template.Groups {
+		if group.DisplayOrder != i+1 {
+			t.Errorf("group %q has display order %d, want %d", group.Name, group.DisplayOrder, i+1)
+		}
+	}
+
+	// Translating one template must not rename the categories it shares with others
+	if english, _ := GetStarterTemplate(StarterTemplateRenter, "en"); english.Groups[2].Categories[0].Name != "Groceries" {
+		t.Errorf("shared category was modified: %q", english.Groups[2].Categories[0].Name)
+	}
+
+	if _, err := GetStarterTemplate("mansion", "en"); err == nil {
+		t.Error("expected an unknown template to be rejected")
+	}
+}
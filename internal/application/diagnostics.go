@@ -0,0 +1,72 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Diagnostics reports on the consistency of the budget's data, for admins
+type Diagnostics struct {
+	ReadyToAssign *ReadyToAssignCheck `json:"ready_to_assign"`
+	CachePriming  *CachePriming       `json:"cache_priming,omitempty"`  // When caches were primed at startup
+	SummaryErrors map[string]int64    `json:"summary_errors,omitempty"` // Period summaries each part was left out of since startup
+	Warnings      []string            `json:"warnings"`                 // Problems found; empty when all is well
+}
+
+// DiagnosticsService runs consistency checks over the budget's data
+type DiagnosticsService struct {
+	allocationService *AllocationService
+	cachePrimer       *CachePrimer
+}
+
+// NewDiagnosticsService creates a new diagnostics service
+func NewDiagnosticsService(allocationService *AllocationService) *DiagnosticsService {
+	return &DiagnosticsService{allocationService: allocationService}
+}
+
+// UseCachePrimer reports the primer's last run with the diagnostics
+func (s *DiagnosticsService) UseCachePrimer(cachePrimer *CachePrimer) {
+	s.cachePrimer = cachePrimer
+}
+
+// Run runs every check
+func (s *DiagnosticsService) Run(ctx context.Context) (*Diagnostics, error) {
+	check, err := s.allocationService.VerifyReadyToAssignTotals(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.newDiagnostics(check), nil
+}
+
+// RebuildReadyToAssign recalculates the kept Ready to Assign totals and checks them again
+func (s *DiagnosticsService) RebuildReadyToAssign(ctx context.Context) (*Diagnostics, error) {
+	check, err := s.allocationService.RebuildReadyToAssignTotals(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.newDiagnostics(check), nil
+}
+
+func (s *DiagnosticsService) newDiagnostics(check *ReadyToAssignCheck) *Diagnostics {
+	diagnostics := &Diagnostics{ReadyToAssign: check, Warnings: []string{}}
+	if check.Warning != "" {
+		diagnostics.Warnings = append(diagnostics.Warnings, check.Warning)
+	}
+	if s.cachePrimer != nil {
+		if priming := s.cachePrimer.Last(); priming != nil {
+			diagnostics.CachePriming = priming
+			for _, step := range priming.Steps {
+				if step.Error != "" {
+					diagnostics.Warnings = append(diagnostics.Warnings, "cache priming step "+step.Name+" failed: "+step.Error)
+				}
+			}
+		}
+	}
+	diagnostics.SummaryErrors = s.allocationService.SummaryErrorCounts()
+	for _, part := range slices.Sorted(maps.Keys(diagnostics.SummaryErrors)) {
+		diagnostics.Warnings = append(diagnostics.Warnings, fmt.Sprintf("%s left out of %d period summaries; see the log for why", part, diagnostics.SummaryErrors[part]))
+	}
+	return diagnostics
+}
@@ -0,0 +1,234 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// weeklyDigestInterval is how often the weekly budget digest is sent
+const weeklyDigestInterval = 7 * 24 * time.Hour
+
+// Kinds of item in the weekly budget digest
+const (
+	DigestItemUncategorized       = "uncategorized"
+	DigestItemPendingTransactions = "pending_transactions"
+	DigestItemOverspent           = "overspent"
+	DigestItemReadyToAssign       = "ready_to_assign"
+)
+
+// BudgetDigest lists what needs attention in the budget
+type BudgetDigest struct {
+	Period        string        `json:"period"`
+	ReadyToAssign int64         `json:"ready_to_assign"` // For the period (cents)
+	Items         []*DigestItem `json:"items"`           // Empty when nothing needs attention
+}
+
+// DigestItem is one thing to act on, with the API call that deals with it
+type DigestItem struct {
+	Kind    string         `json:"kind"`
+	Summary string         `json:"summary"`
+	Count   int            `json:"count,omitempty"`
+	Amount  int64          `json:"amount,omitempty"` // Cents; the shortfall of an overspent category
+	Method  string         `json:"method"`
+	Path    string         `json:"path"`
+	Body    map[string]any `json:"body,omitempty"` // Request body the action expects
+}
+
+// DigestService builds the weekly budget digest and sends it to the users who opted in
+type DigestService struct {
+	transactionRepo   domain.TransactionRepository
+	pendingRepo       domain.PendingTransactionRepository
+	allocationService *AllocationService
+	userRepo          domain.UserRepository
+	userSettingRepo   domain.UserSettingRepository
+	preferences       *UserPreferenceService
+	channel           NotificationChannel // nil when no channel is configured; digests can still be previewed
+}
+
+// NewDigestService creates a new digest service
+func NewDigestService(
+	transactionRepo domain.TransactionRepository,
+	pendingRepo domain.PendingTransactionRepository,
+	allocationService *AllocationService,
+	userRepo domain.UserRepository,
+	userSettingRepo domain.UserSettingRepository,
+	preferences *UserPreferenceService,
+	channel NotificationChannel,
+) *DigestService {
+	return &DigestService{
+		transactionRepo:   transactionRepo,
+		pendingRepo:       pendingRepo,
+		allocationService: allocationService,
+		userRepo:          userRepo,
+		userSettingRepo:   userSettingRepo,
+		preferences:       preferences,
+		channel:           channel,
+	}
+}
+
+// BuildDigest collects the items that need attention in the given period
+func (s *DigestService) BuildDigest(ctx context.Context, period string) (*BudgetDigest, error) {
+	digest := &BudgetDigest{Period: period, Items: []*DigestItem{}}
+
+	uncategorized, err := s.transactionRepo.ListUncategorized(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list uncategorized transactions: %w", err)
+	}
+	if len(uncategorized) > 0 {
+		digest.Items = append(digest.Items, &DigestItem{
+			Kind:    DigestItemUncategorized,
+			Summary: fmt.Sprintf("%d transactions need a category", len(uncategorized)),
+			Count:   len(uncategorized),
+			Method:  "GET",
+			Path:    "/api/transactions?uncategorized=true",
+		})
+	}
+
+	pending, err := s.pendingRepo.List(ctx, domain.PendingStatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending transactions: %w", err)
+	}
+	if len(pending) > 0 {
+		digest.Items = append(digest.Items, &DigestItem{
+			Kind:    DigestItemPendingTransactions,
+			Summary: fmt.Sprintf("%d transactions are waiting for approval", len(pending)),
+			Count:   len(pending),
+			Method:  "GET",
+			Path:    "/api/pending-transactions?status=pending",
+		})
+	}
+
+	summaries, err := s.allocationService.GetAllocationSummary(ctx, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize allocations: %w", err)
+	}
+	for _, summary := range summaries {
+		if summary.Available >= 0 {
+			continue
+		}
+		digest.Items = append(digest.Items, &DigestItem{
+			Kind:    DigestItemOverspent,
+			Summary: fmt.Sprintf("%s is overspent by %s", summary.Category.Name, formatBotAmount(-summary.Available)),
+			Amount:  -summary.Available,
+			Method:  "POST",
+			Path:    fmt.Sprintf("/api/categories/%s/cover-overspending", summary.Category.ID),
+			Body:    map[string]any{"period": period},
+		})
+	}
+
+	rta, err := s.allocationService.CalculateReadyToAssignForPeriod(ctx, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate ready to assign: %w", err)
+	}
+	digest.ReadyToAssign = rta
+	if rta != 0 {
+		summary := fmt.Sprintf("%s is ready to assign", formatBotAmount(rta))
+		if rta < 0 {
+			summary = fmt.Sprintf("%s more is assigned than you have", formatBotAmount(-rta))
+		}
+		digest.Items = append(digest.Items, &DigestItem{
+			Kind:    DigestItemReadyToAssign,
+			Summary: summary,
+			Amount:  rta,
+			Method:  "GET",
+			Path:    "/api/allocations/summary?period=" + period,
+		})
+	}
+
+	return digest, nil
+}
+
+// SendWeeklyDigests sends the budget digest to every user who opted in and hasn't had one this week
+// Run it from the scheduler. Users with nothing to act on are skipped until the following week.
+func (s *DigestService) SendWeeklyDigests(ctx context.Context) error {
+	if s.channel == nil {
+		return nil
+	}
+	users, err := s.userRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	var digest *BudgetDigest
+	for _, user := range users {
+		due, err := s.weeklyDigestDue(ctx, user, now)
+		if err != nil {
+			log.Printf("digest: %s: %v", user.Email, err)
+			continue
+		}
+		if !due {
+			continue
+		}
+
+		// The budget is shared, so everyone gets the same digest
+		if digest == nil {
+			if digest, err = s.BuildDigest(ctx, now.Format("2006-01")); err != nil {
+				return err
+			}
+		}
+		if len(digest.Items) > 0 {
+			if err := s.channel.Send(ctx, digestNotification(user, digest)); err != nil {
+				log.Printf("digest: failed to send to %s: %v", user.Email, err)
+				continue
+			}
+		}
+		if err := s.markWeeklyDigestSent(ctx, user.ID, now); err != nil {
+			log.Printf("digest: %s: %v", user.Email, err)
+		}
+	}
+	return nil
+}
+
+func (s *DigestService) weeklyDigestDue(ctx context.Context, user *domain.User, now time.Time) (bool, error) {
+	prefs, err := s.preferences.GetPreferences(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+	if !prefs.Notifications.WeeklyDigest {
+		return false, nil
+	}
+
+	settings, err := s.userSettingRepo.ListByUser(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, setting := range settings {
+		if setting.Key != domain.UserSettingKeyWeeklyDigestSentAt {
+			continue
+		}
+		sentAt, err := time.Parse(time.RFC3339Nano, setting.Value)
+		if err != nil {
+			return false, err
+		}
+		return now.Sub(sentAt) >= weeklyDigestInterval-digestSlack, nil
+	}
+	return true, nil
+}
+
+func (s *DigestService) markWeeklyDigestSent(ctx context.Context, userID string, at time.Time) error {
+	return s.userSettingRepo.Set(ctx, &domain.UserSetting{
+		UserID:    userID,
+		Key:       domain.UserSettingKeyWeeklyDigestSentAt,
+		Value:     at.Format(time.RFC3339Nano),
+		UpdatedAt: time.Now(),
+	})
+}
+
+func digestNotification(user *domain.User, digest *BudgetDigest) *Notification {
+	lines := make([]string, 0, len(digest.Items))
+	for _, item := range digest.Items {
+		lines = append(lines, fmt.Sprintf("- %s\n  %s %s", item.Summary, item.Method, item.Path))
+	}
+	return &Notification{
+		To:      user.Email,
+		Subject: fmt.Sprintf("Weekly budget digest: %d items to review", len(digest.Items)),
+		Body: fmt.Sprintf("Here's what needs attention in your budget for %s:\n\n%s\n",
+			digest.Period, strings.Join(lines, "\n")),
+	}
+}
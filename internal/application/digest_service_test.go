@@ -0,0 +1,79 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestDigestService(t *testing.T) {
+	ctx := context.Background()
+	groceriesID := "groceries-id"
+	categoryRepo := newMockCategoryRepository()
+	categoryRepo.categories[groceriesID] = &domain.Category{ID: groceriesID, Name: "Groceries"}
+	transactionRepo := newMockTransactionRepository()
+	transactionRepo.transactions = []*domain.Transaction{
+		{ID: "income", AccountID: "checking", Amount: 10000, Date: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "spend", AccountID: "checking", CategoryID: &groceriesID, Amount: -5000, Date: time.Date(2025, 10, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	allocationService := NewAllocationService(newMockAllocationRepository(), categoryRepo, transactionRepo,
+		newMockBudgetStateRepository(5000, 10000), newMockAccountRepository(5000),
+		newMockCategoryGroupRepository(), newMockGoalRepository())
+	pendingRepo := &mockPendingTransactionRepository{pending: []*domain.PendingTransaction{{ID: "p1", Status: domain.PendingStatusPending}}}
+
+	userRepo := &mockUserRepository{users: []*domain.User{
+		{ID: "alex", Email: "alex@example.com"},
+		{ID: "kim", Email: "kim@example.com"},
+	}}
+	settingRepo := newMockUserSettingRepository()
+	setNotificationOptIns(t, settingRepo, "kim", domain.NotificationOptIns{WeeklyDigest: true})
+	channel := &recordingChannel{}
+	service := NewDigestService(transactionRepo, pendingRepo, allocationService, userRepo, settingRepo, NewUserPreferenceService(settingRepo), channel)
+
+	digest, err := service.BuildDigest(ctx, "2025-10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	kinds := map[string]*DigestItem{}
+	for _, item := range digest.Items {
+		kinds[item.Kind] = item
+	}
+	if item := kinds[DigestItemUncategorized]; item == nil || item.Count != 1 || item.Path != "/api/transactions?uncategorized=true" {
+		t.Errorf("expected the uncategorized income to be listed, got %+v", item)
+	}
+	if item := kinds[DigestItemPendingTransactions]; item == nil || item.Count != 1 {
+		t.Errorf("expected the pending transaction to be listed, got %+v", item)
+	}
+	if item := kinds[DigestItemOverspent]; item == nil || item.Amount != 5000 ||
+		item.Method != "POST" || item.Path != "/api/categories/groceries-id/cover-overspending" || item.Body["period"] != "2025-10" {
+		t.Errorf("expected groceries to be listed with the action that covers it, got %+v", item)
+	}
+	if item := kinds[DigestItemReadyToAssign]; item == nil || item.Amount != digest.ReadyToAssign {
+		t.Errorf("expected ready to assign to be listed, got %+v", item)
+	}
+
+	// Only users who opted in get the digest, and only once a week
+	if err := service.SendWeeklyDigests(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(channel.sent) != 1 || channel.sent[0].To != "kim@example.com" {
+		t.Fatalf("expected one digest for the user who opted in, got %+v", channel.sent)
+	}
+	if err := service.SendWeeklyDigests(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(channel.sent) != 1 {
+		t.Errorf("expected no second digest within the week, got %d", len(channel.sent))
+	}
+
+	lastWeek := time.Now().Add(-weeklyDigestInterval)
+	settingRepo.Set(ctx, &domain.UserSetting{UserID: "kim", Key: domain.UserSettingKeyWeeklyDigestSentAt, Value: lastWeek.Format(time.RFC3339Nano)})
+	if err := service.SendWeeklyDigests(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(channel.sent) != 2 {
+		t.Errorf("expected the digest to go out again after a week, got %d", len(channel.sent))
+	}
+}
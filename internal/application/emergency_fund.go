@@ -0,0 +1,164 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// EmergencyFundCoverage reports how many months of essential spending the emergency fund covers
+type EmergencyFundCoverage struct {
+	Period                string   `json:"period"`
+	TrailingMonths        int      `json:"trailing_months"`
+	FundBalance           int64    `json:"fund_balance"`            // Category availables + account balances (cents)
+	AverageEssentialSpend int64    `json:"average_essential_spend"` // Trailing monthly average of essential spending (cents)
+	MonthsCovered         float64  `json:"months_covered"`          // FundBalance / AverageEssentialSpend (0 when there is no essential spending)
+	TargetMonths          int      `json:"target_months"`
+	TargetAmount          int64    `json:"target_amount"`    // TargetMonths * AverageEssentialSpend (cents)
+	ProgressPercent       float64  `json:"progress_percent"` // FundBalance as a percentage of TargetAmount, capped at 100
+	Shortfall             int64    `json:"shortfall"`        // Amount still needed to reach the target (cents, never negative)
+	CategoryIDs           []string `json:"category_ids"`
+	AccountIDs            []string `json:"account_ids"`
+}
+
+// GetEmergencyFundSettings returns the saved emergency fund settings, or defaults if none are saved
+func (s *ReportService) GetEmergencyFundSettings(ctx context.Context) (*domain.EmergencyFundSettings, error) {
+	settings := &domain.EmergencyFundSettings{
+		CategoryIDs:  []string{},
+		AccountIDs:   []string{},
+		TargetMonths: domain.DefaultEmergencyFundTargetMonths,
+	}
+
+	setting, err := s.settingRepo.Get(ctx, domain.SettingKeyEmergencyFund)
+	if err != nil {
+		// Nothing saved yet - use defaults
+		return settings, nil
+	}
+
+	if err := json.Unmarshal([]byte(setting.Value), settings); err != nil {
+		return nil, fmt.Errorf("failed to decode emergency fund settings: %w", err)
+	}
+	if settings.TargetMonths <= 0 {
+		settings.TargetMonths = domain.DefaultEmergencyFundTargetMonths
+	}
+	return settings, nil
+}
+
+// UpdateEmergencyFundSettings validates and saves which categories/accounts make up the emergency fund
+func (s *ReportService) UpdateEmergencyFundSettings(ctx context.Context, settings *domain.EmergencyFundSettings) (*domain.EmergencyFundSettings, error) {
+	if settings.TargetMonths < 1 || settings.TargetMonths > 60 {
+		return nil, fmt.Errorf("target_months must be between 1 and 60")
+	}
+	if settings.CategoryIDs == nil {
+		settings.CategoryIDs = []string{}
+	}
+	if settings.AccountIDs == nil {
+		settings.AccountIDs = []string{}
+	}
+
+	for _, id := range settings.CategoryIDs {
+		if _, err := s.categoryRepo.GetByID(ctx, id); err != nil {
+			return nil, fmt.Errorf("category not found: %s", id)
+		}
+	}
+	for _, id := range settings.AccountIDs {
+		if _, err := s.accountRepo.GetByID(ctx, id); err != nil {
+			return nil, fmt.Errorf("account not found: %s", id)
+		}
+	}
+
+	value, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode emergency fund settings: %w", err)
+	}
+
+	if err := s.settingRepo.Set(ctx, &domain.Setting{
+		Key:       domain.SettingKeyEmergencyFund,
+		Value:     string(value),
+		UpdatedAt: time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// GetEmergencyFundCoverage calculates emergency fund coverage as of the given period
+// Essential spending is averaged over the trailing months before period
+func (s *ReportService) GetEmergencyFundCoverage(ctx context.Context, period string, trailingMonths int) (*EmergencyFundCoverage, error) {
+	if trailingMonths <= 0 {
+		trailingMonths = 3
+	}
+
+	settings, err := s.GetEmergencyFundSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	coverage := &EmergencyFundCoverage{
+		Period:         period,
+		TrailingMonths: trailingMonths,
+		TargetMonths:   settings.TargetMonths,
+		CategoryIDs:    settings.CategoryIDs,
+		AccountIDs:     settings.AccountIDs,
+	}
+
+	// Fund balance from categories: what each has available at the end of period, the same
+	// figure the budget screen shows
+	if len(settings.CategoryIDs) > 0 {
+		available, err := s.allocations.CategoryAvailable(ctx, period, settings.CategoryIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, amount := range available {
+			coverage.FundBalance += amount
+		}
+	}
+
+	// Fund balance from accounts: current balance
+	for _, accountID := range settings.AccountIDs {
+		account, err := s.accountRepo.GetByID(ctx, accountID)
+		if err != nil {
+			// Account was deleted after being configured - skip it
+			continue
+		}
+		coverage.FundBalance += account.Balance
+	}
+
+	// Average essential spending
+	averages, err := s.averageMonthlySpending(ctx, period, trailingMonths)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	for _, category := range categories {
+		if category.Classification == domain.ClassificationEssential {
+			coverage.AverageEssentialSpend += averages[category.ID]
+		}
+	}
+
+	coverage.TargetAmount = coverage.AverageEssentialSpend * int64(coverage.TargetMonths)
+	if coverage.AverageEssentialSpend > 0 {
+		coverage.MonthsCovered = float64(coverage.FundBalance) / float64(coverage.AverageEssentialSpend)
+	}
+	if coverage.TargetAmount > 0 {
+		coverage.ProgressPercent = float64(coverage.FundBalance) / float64(coverage.TargetAmount) * 100
+		if coverage.ProgressPercent > 100 {
+			coverage.ProgressPercent = 100
+		}
+		if coverage.ProgressPercent < 0 {
+			coverage.ProgressPercent = 0
+		}
+	}
+	if coverage.TargetAmount > coverage.FundBalance {
+		coverage.Shortfall = coverage.TargetAmount - coverage.FundBalance
+	}
+
+	return coverage, nil
+}
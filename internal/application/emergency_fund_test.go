@@ -0,0 +1,85 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestReportService_GetEmergencyFundCoverage(t *testing.T) {
+	ctx := context.Background()
+	categoryRepo := newMockCategoryRepository()
+	for _, category := range []*domain.Category{
+		{ID: "fund", Name: "Emergency Fund", Classification: domain.ClassificationSavings},
+		{ID: "buffer", Name: "Buffer", Classification: domain.ClassificationSavings},
+		{ID: "rent", Name: "Rent", Classification: domain.ClassificationEssential},
+		{ID: "dining", Name: "Dining", Classification: domain.ClassificationDiscretionary},
+	} {
+		categoryRepo.categories[category.ID] = category
+	}
+
+	allocationRepo := newMockAllocationRepository()
+	for _, allocation := range []*domain.Allocation{
+		{ID: "a1", CategoryID: "fund", Period: "2025-01", Amount: 100000},
+		{ID: "a2", CategoryID: "fund", Period: "2025-02", Amount: 50000},
+		{ID: "a3", CategoryID: "fund", Period: "2025-05", Amount: 40000}, // After the period
+		{ID: "a4", CategoryID: "buffer", Period: "2025-02", Amount: 10000},
+	} {
+		allocationRepo.Create(ctx, allocation)
+	}
+
+	transactionRepo := &datedTransactionRepository{newMockTransactionRepository()}
+	spend := func(categoryID string, date string, amount int64) {
+		when, _ := time.Parse("2006-01-02", date)
+		transactionRepo.transactions = append(transactionRepo.transactions, &domain.Transaction{
+			ID: categoryID + date, AccountID: "checking", CategoryID: &categoryID, Amount: amount, Date: when, Type: domain.TransactionTypeNormal,
+		})
+	}
+	spend("fund", "2025-02-10", -20000)
+	spend("fund", "2025-05-01", -70000)  // After the period, so still in the fund then
+	spend("buffer", "2025-01-20", -5000) // Overspent in January and reset, not carried
+	for _, month := range []string{"2024-12", "2025-01", "2025-02"} {
+		spend("rent", month+"-01", -100000)
+		spend("dining", month+"-15", -30000)
+	}
+
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.Create(ctx, &domain.Account{ID: "savings", Name: "Savings", Type: domain.AccountTypeSavings, Balance: 200000})
+
+	allocations := NewAllocationService(allocationRepo, categoryRepo, transactionRepo, newMockBudgetStateRepository(0, 0), accountRepo, newMockCategoryGroupRepository(), newMockGoalRepository())
+	service := NewReportService(categoryRepo, allocationRepo, transactionRepo, accountRepo, newMockSettingRepository(), nil, allocations)
+
+	if _, err := service.UpdateEmergencyFundSettings(ctx, &domain.EmergencyFundSettings{
+		CategoryIDs:  []string{"fund", "buffer"},
+		AccountIDs:   []string{"savings"},
+		TargetMonths: 6,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	coverage, err := service.GetEmergencyFundCoverage(ctx, "2025-03", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// fund 130000 + buffer 10000 + savings 200000
+	if coverage.FundBalance != 340000 {
+		t.Errorf("expected a fund balance of 340000, got %d", coverage.FundBalance)
+	}
+	if coverage.AverageEssentialSpend != 100000 {
+		t.Errorf("expected only essential spending averaged, got %d", coverage.AverageEssentialSpend)
+	}
+	if coverage.MonthsCovered != 3.4 || coverage.TargetAmount != 600000 || coverage.Shortfall != 260000 {
+		t.Errorf("expected 3.4 months covered with 260000 short of 600000, got %+v", coverage)
+	}
+
+	// By May the later allocation and spending count
+	coverage, err = service.GetEmergencyFundCoverage(ctx, "2025-05", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if coverage.FundBalance != 310000 {
+		t.Errorf("expected a May fund balance of 310000, got %d", coverage.FundBalance)
+	}
+}
@@ -0,0 +1,365 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/billybbuffum/budget/internal/infrastructure/ynab"
+)
+
+// Budget export file format
+const (
+	BudgetExportFormat  = "budget-export"
+	BudgetExportVersion = 2 // Version 2 added category moves
+)
+
+// ErrBudgetNotEmpty is returned when importing an export into a budget that already has data
+var ErrBudgetNotEmpty = errors.New("exports can only be imported into a budget without accounts, transactions or allocations")
+
+// BudgetExport is everything needed to recreate a budget on another instance
+// IDs are kept so references between records survive the trip. Payees, rules, goals,
+// users and settings aren't included.
+type BudgetExport struct {
+	Format         string                  `json:"format"`  // Always BudgetExportFormat
+	Version        int                     `json:"version"` // BudgetExportVersion
+	ExportedAt     time.Time               `json:"exported_at"`
+	Accounts       []*domain.Account       `json:"accounts"`
+	CategoryGroups []*domain.CategoryGroup `json:"category_groups"`
+	Categories     []*domain.Category      `json:"categories"`
+	Allocations    []*domain.Allocation    `json:"allocations"`
+	Transactions   []*domain.Transaction   `json:"transactions"`
+	Moves          []*domain.CategoryMove  `json:"moves"` // Money moved into credit card payment categories
+}
+
+// BudgetExportImportResult counts what importing an export created
+type BudgetExportImportResult struct {
+	Accounts       int `json:"accounts"`
+	CategoryGroups int `json:"category_groups"`
+	Categories     int `json:"categories"`
+	Allocations    int `json:"allocations"`
+	Transactions   int `json:"transactions"`
+	Moves          int `json:"moves"`
+}
+
+// ExportService exports the whole budget and imports it elsewhere, or from YNAB
+type ExportService struct {
+	accountRepo       domain.AccountRepository
+	categoryGroupRepo domain.CategoryGroupRepository
+	categoryRepo      domain.CategoryRepository
+	allocationRepo    domain.AllocationRepository
+	transactionRepo   domain.TransactionRepository
+	categoryMoveRepo  domain.CategoryMoveRepository
+	uow               domain.UnitOfWork
+	ynabParser        *ynab.Parser
+}
+
+// NewExportService creates a new export service
+func NewExportService(
+	accountRepo domain.AccountRepository,
+	categoryGroupRepo domain.CategoryGroupRepository,
+	categoryRepo domain.CategoryRepository,
+	allocationRepo domain.AllocationRepository,
+	transactionRepo domain.TransactionRepository,
+	categoryMoveRepo domain.CategoryMoveRepository,
+	uow domain.UnitOfWork,
+	ynabParser *ynab.Parser,
+) *ExportService {
+	return &ExportService{
+		accountRepo:       accountRepo,
+		categoryGroupRepo: categoryGroupRepo,
+		categoryRepo:      categoryRepo,
+		allocationRepo:    allocationRepo,
+		transactionRepo:   transactionRepo,
+		categoryMoveRepo:  categoryMoveRepo,
+		uow:               uow,
+		ynabParser:        ynabParser,
+	}
+}
+
+// Export returns every account, category group, category, allocation, transaction and
+// category move
+func (s *ExportService) Export(ctx context.Context) (*BudgetExport, error) {
+	export := &BudgetExport{
+		Format:     BudgetExportFormat,
+		Version:    BudgetExportVersion,
+		ExportedAt: time.Now().UTC(),
+	}
+	// Read everything in one transaction so the parts agree with each other
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if export.Accounts, err = s.accountRepo.List(ctx); err != nil {
+			return err
+		}
+		if export.CategoryGroups, err = s.categoryGroupRepo.List(ctx); err != nil {
+			return err
+		}
+		if export.Categories, err = s.categoryRepo.List(ctx); err != nil {
+			return err
+		}
+		if export.Allocations, err = s.allocationRepo.List(ctx); err != nil {
+			return err
+		}
+		if export.Transactions, err = s.transactionRepo.List(ctx); err != nil {
+			return err
+		}
+		export.Moves, err = s.categoryMoveRepo.List(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Empty lists rather than nulls, so the document always has the same shape
+	if export.Accounts == nil {
+		export.Accounts = []*domain.Account{}
+	}
+	if export.CategoryGroups == nil {
+		export.CategoryGroups = []*domain.CategoryGroup{}
+	}
+	if export.Categories == nil {
+		export.Categories = []*domain.Category{}
+	}
+	if export.Allocations == nil {
+		export.Allocations = []*domain.Allocation{}
+	}
+	if export.Transactions == nil {
+		export.Transactions = []*domain.Transaction{}
+	}
+	if export.Moves == nil {
+		export.Moves = []*domain.CategoryMove{}
+	}
+	return export, nil
+}
+
+// Import recreates an exported budget
+// Only a budget without accounts, transactions or allocations can be imported into; the
+// category groups and categories it has, such as a starter template's, are replaced.
+// Transactions lose their payee and import links, since neither is exported. Exports from
+// before category moves were added import without them. Everything is imported or nothing is.
+func (s *ExportService) Import(ctx context.Context, export *BudgetExport) (*BudgetExportImportResult, error) {
+	if err := validateBudgetExport(export); err != nil {
+		return nil, err
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.checkEmpty(ctx); err != nil {
+			return err
+		}
+		if err := s.clearCategories(ctx); err != nil {
+			return err
+		}
+
+		for _, group := range export.CategoryGroups {
+			if err := s.categoryGroupRepo.Create(ctx, group); err != nil {
+				return err
+			}
+		}
+		// A loan's terms refer to its interest category, so they're set once the categories are in
+		loans := make(map[*domain.Account]*domain.LoanTerms)
+		for _, account := range export.Accounts {
+			// Create leaves the rewards balance at zero; it only changes by adjustment
+			rewards := account.RewardsBalance
+			account.RewardsBalance = 0
+			if account.Loan != nil {
+				loans[account], account.Loan = account.Loan, nil
+			}
+			if err := s.accountRepo.Create(ctx, account); err != nil {
+				return err
+			}
+			if rewards != 0 {
+				if err := s.accountRepo.AdjustRewardsBalance(ctx, account.ID, rewards); err != nil {
+					return err
+				}
+			}
+		}
+		for _, category := range export.Categories {
+			if err := s.categoryRepo.Create(ctx, category); err != nil {
+				return err
+			}
+		}
+		for account, terms := range loans {
+			account.Loan = terms
+			if err := s.accountRepo.Update(ctx, account); err != nil {
+				return err
+			}
+		}
+		for _, allocation := range export.Allocations {
+			if err := s.allocationRepo.Create(ctx, allocation); err != nil {
+				return err
+			}
+		}
+		for _, transaction := range export.Transactions {
+			transaction.PayeeID = nil
+			transaction.ImportID = nil
+			if err := s.transactionRepo.Create(ctx, transaction); err != nil {
+				return err
+			}
+		}
+		// Spending moves refer to their card transactions, so they go in last
+		for _, move := range export.Moves {
+			if err := s.categoryMoveRepo.Create(ctx, move); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &BudgetExportImportResult{
+		Accounts:       len(export.Accounts),
+		CategoryGroups: len(export.CategoryGroups),
+		Categories:     len(export.Categories),
+		Allocations:    len(export.Allocations),
+		Transactions:   len(export.Transactions),
+		Moves:          len(export.Moves),
+	}, nil
+}
+
+// checkEmpty returns ErrBudgetNotEmpty if the budget has accounts, transactions or allocations
+func (s *ExportService) checkEmpty(ctx context.Context) error {
+	accounts, err := s.accountRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	transactions, err := s.transactionRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	allocations, err := s.allocationRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	if len(accounts) > 0 || len(transactions) > 0 || len(allocations) > 0 {
+		return ErrBudgetNotEmpty
+	}
+	return nil
+}
+
+// clearCategories deletes every category and category group
+func (s *ExportService) clearCategories(ctx context.Context) error {
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, category := range categories {
+		if err := s.categoryRepo.Delete(ctx, category.ID); err != nil {
+			return err
+		}
+	}
+	groups, err := s.categoryGroupRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		if err := s.categoryGroupRepo.Delete(ctx, group.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateBudgetExport checks the export's format and that its records only refer to each other
+func validateBudgetExport(export *BudgetExport) error {
+	if export.Format != BudgetExportFormat {
+		return fmt.Errorf("not a budget export")
+	}
+	if export.Version < 1 || export.Version > BudgetExportVersion {
+		return fmt.Errorf("unsupported budget export version %d", export.Version)
+	}
+
+	ids := make(map[string]bool)
+	add := func(kind, id string) error {
+		if id == "" {
+			return fmt.Errorf("%s id is required", kind)
+		}
+		if ids[id] {
+			return fmt.Errorf("%s id %s is used more than once", kind, id)
+		}
+		ids[id] = true
+		return nil
+	}
+	accounts := make(map[string]bool, len(export.Accounts))
+	for _, account := range export.Accounts {
+		if err := add("account", account.ID); err != nil {
+			return err
+		}
+		switch account.Type {
+		case domain.AccountTypeChecking, domain.AccountTypeSavings, domain.AccountTypeCash, domain.AccountTypeCredit, domain.AccountTypeLoan, domain.AccountTypeTracking:
+		default:
+			return fmt.Errorf("account %s has invalid type %q", account.ID, account.Type)
+		}
+		accounts[account.ID] = true
+	}
+	groups := make(map[string]bool, len(export.CategoryGroups))
+	for _, group := range export.CategoryGroups {
+		if err := add("category group", group.ID); err != nil {
+			return err
+		}
+		groups[group.ID] = true
+	}
+	categories := make(map[string]bool, len(export.Categories))
+	for _, category := range export.Categories {
+		if err := add("category", category.ID); err != nil {
+			return err
+		}
+		if category.GroupID != nil && !groups[*category.GroupID] {
+			return fmt.Errorf("category %s refers to missing category group %s", category.ID, *category.GroupID)
+		}
+		if category.PaymentForAccountID != nil && !accounts[*category.PaymentForAccountID] {
+			return fmt.Errorf("category %s refers to missing account %s", category.ID, *category.PaymentForAccountID)
+		}
+		categories[category.ID] = true
+	}
+	for _, account := range export.Accounts {
+		if account.Loan != nil && account.Loan.InterestCategoryID != nil && !categories[*account.Loan.InterestCategoryID] {
+			return fmt.Errorf("account %s refers to missing category %s", account.ID, *account.Loan.InterestCategoryID)
+		}
+	}
+	for _, allocation := range export.Allocations {
+		if err := add("allocation", allocation.ID); err != nil {
+			return err
+		}
+		if !categories[allocation.CategoryID] {
+			return fmt.Errorf("allocation %s refers to missing category %s", allocation.ID, allocation.CategoryID)
+		}
+	}
+	transactions := make(map[string]bool, len(export.Transactions))
+	for _, transaction := range export.Transactions {
+		if err := add("transaction", transaction.ID); err != nil {
+			return err
+		}
+		transactions[transaction.ID] = true
+		if !accounts[transaction.AccountID] {
+			return fmt.Errorf("transaction %s refers to missing account %s", transaction.ID, transaction.AccountID)
+		}
+		if transaction.TransferToAccountID != nil && !accounts[*transaction.TransferToAccountID] {
+			return fmt.Errorf("transaction %s refers to missing account %s", transaction.ID, *transaction.TransferToAccountID)
+		}
+		if transaction.CategoryID != nil && !categories[*transaction.CategoryID] {
+			return fmt.Errorf("transaction %s refers to missing category %s", transaction.ID, *transaction.CategoryID)
+		}
+	}
+	for _, move := range export.Moves {
+		if err := add("category move", move.ID); err != nil {
+			return err
+		}
+		if move.Reason != domain.CategoryMoveSpending && move.Reason != domain.CategoryMoveCover {
+			return fmt.Errorf("category move %s has invalid reason %q", move.ID, move.Reason)
+		}
+		if !categories[move.ToCategoryID] {
+			return fmt.Errorf("category move %s refers to missing category %s", move.ID, move.ToCategoryID)
+		}
+		if move.FromCategoryID != nil && !categories[*move.FromCategoryID] {
+			return fmt.Errorf("category move %s refers to missing category %s", move.ID, *move.FromCategoryID)
+		}
+		if move.TransactionID != nil && !transactions[*move.TransactionID] {
+			return fmt.Errorf("category move %s refers to missing transaction %s", move.ID, *move.TransactionID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/billybbuffum/budget/internal/infrastructure/ynab"
+)
+
+func newTestExportService() (*ExportService, *mockAccountRepository, *mockCategoryGroupRepository, *mockCategoryRepository, *mockAllocationRepository, *mockTransactionRepository, *mockCategoryMoveRepository) {
+	accountRepo := newMockAccountRepository(0)
+	groupRepo := newMockCategoryGroupRepository()
+	categoryRepo := newMockCategoryRepository()
+	allocationRepo := newMockAllocationRepository()
+	transactionRepo := newMockTransactionRepository()
+	moveRepo := newMockCategoryMoveRepository()
+	service := NewExportService(accountRepo, groupRepo, categoryRepo, allocationRepo, transactionRepo, moveRepo, &mockUnitOfWork{accountRepo, transactionRepo}, ynab.NewParser())
+	return service, accountRepo, groupRepo, categoryRepo, allocationRepo, transactionRepo, moveRepo
+}
+
+func TestExportService_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source, accountRepo, groupRepo, categoryRepo, allocationRepo, transactionRepo, moveRepo := newTestExportService()
+	groupID, cardID, groceriesID, payee := "bills", "card", "groceries", "payee-1"
+	groupRepo.Create(ctx, &domain.CategoryGroup{ID: groupID, Name: "Bills"})
+	accountRepo.Create(ctx, &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking, Balance: 90000})
+	accountRepo.Create(ctx, &domain.Account{ID: cardID, Name: "Visa", Type: domain.AccountTypeCredit, Balance: -2500, RewardsBalance: 700})
+	accountRepo.Create(ctx, &domain.Account{ID: "car", Name: "Car loan", Type: domain.AccountTypeLoan, Balance: -500000,
+		Loan: &domain.LoanTerms{MonthlyPayment: 30000, InterestCategoryID: &groceriesID}})
+	categoryRepo.Create(ctx, &domain.Category{ID: groceriesID, Name: "Groceries", GroupID: &groupID})
+	categoryRepo.Create(ctx, &domain.Category{ID: "visa-payment", Name: "Visa", PaymentForAccountID: &cardID})
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a1", CategoryID: groceriesID, Period: "2025-03", Amount: 40000})
+	march := time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC)
+	transactionRepo.Create(ctx, &domain.Transaction{ID: "t1", Type: domain.TransactionTypeNormal, AccountID: cardID, CategoryID: &groceriesID, Amount: -2500, Description: "Market", Date: march, PayeeID: &payee})
+	transactionRepo.Create(ctx, &domain.Transaction{ID: "t2", Type: domain.TransactionTypeNormal, AccountID: "checking", Amount: 100000, Description: "Paycheck", Date: march})
+	// The card's payment category is funded by the budgeted spending and a cover from Ready to Assign
+	spent := "t1"
+	moveRepo.Create(ctx, &domain.CategoryMove{ID: "m1", FromCategoryID: &groceriesID, ToCategoryID: "visa-payment", Period: "2025-03", Amount: 2500,
+		Reason: domain.CategoryMoveSpending, TransactionID: &spent})
+	moveRepo.Create(ctx, &domain.CategoryMove{ID: "m2", ToCategoryID: "visa-payment", Period: "2025-03", Amount: 1000, Reason: domain.CategoryMoveCover})
+
+	export, err := source.Export(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if export.Format != BudgetExportFormat || len(export.Accounts) != 3 || len(export.Categories) != 2 || len(export.Allocations) != 1 || len(export.Transactions) != 2 || len(export.Moves) != 2 {
+		t.Fatalf("unexpected export %+v", export)
+	}
+
+	// The new instance has only its starter categories, which the import replaces
+	target, targetAccounts, targetGroups, targetCategories, targetAllocations, targetTransactions, targetMoves := newTestExportService()
+	targetGroups.Create(ctx, &domain.CategoryGroup{ID: "starter", Name: "Everyday"})
+	targetCategories.Create(ctx, &domain.Category{ID: "starter-food", Name: "Food", GroupID: &[]string{"starter"}[0]})
+	result, err := target.Import(ctx, export)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Accounts != 3 || result.CategoryGroups != 1 || result.Categories != 2 || result.Allocations != 1 || result.Transactions != 2 || result.Moves != 2 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if len(targetGroups.groups) != 1 || targetGroups.groups[0].ID != groupID {
+		t.Errorf("expected the starter group to be replaced, got %+v", targetGroups.groups)
+	}
+	if _, ok := targetCategories.categories["starter-food"]; ok || len(targetCategories.categories) != 2 {
+		t.Errorf("expected the starter categories to be replaced, got %v", targetCategories.categories)
+	}
+	if card := targetAccounts.accounts[cardID]; card == nil || card.Balance != -2500 || card.RewardsBalance != 700 {
+		t.Errorf("expected the card with its balances, got %+v", card)
+	}
+	if car := targetAccounts.accounts["car"]; car == nil || car.Loan == nil || *car.Loan.InterestCategoryID != groceriesID {
+		t.Errorf("expected the loan with its terms, got %+v", car)
+	}
+	if txn, _ := targetTransactions.GetByID(ctx, "t1"); txn == nil || txn.PayeeID != nil || *txn.CategoryID != groceriesID {
+		t.Errorf("expected t1 categorized without its payee, got %+v", txn)
+	}
+	if len(targetMoves.moves) != 2 || *targetMoves.moves[0].TransactionID != "t1" || targetMoves.moves[1].FromCategoryID != nil {
+		t.Errorf("expected both moves into the payment category, got %+v", targetMoves.moves)
+	}
+
+	// The payment category has the same money available after the trip
+	available := func(allocationRepo *mockAllocationRepository, categoryRepo *mockCategoryRepository, transactionRepo *mockTransactionRepository,
+		accountRepo *mockAccountRepository, groupRepo *mockCategoryGroupRepository, moveRepo *mockCategoryMoveRepository) int64 {
+		t.Helper()
+		allocations := NewAllocationService(allocationRepo, categoryRepo, transactionRepo, newMockBudgetStateRepository(0, 0), accountRepo, groupRepo, newMockGoalRepository())
+		allocations.UseCategoryMoves(moveRepo)
+		available, err := allocations.CategoryAvailable(ctx, "2025-03", []string{"visa-payment"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return available["visa-payment"]
+	}
+	before := available(allocationRepo, categoryRepo, transactionRepo, accountRepo, groupRepo, moveRepo)
+	after := available(targetAllocations, targetCategories, targetTransactions, targetAccounts, targetGroups, targetMoves)
+	if before != 3500 || after != before {
+		t.Errorf("expected 3500 available for the card payment before and after, got %d and %d", before, after)
+	}
+
+	// A second import would mix two budgets
+	if _, err := target.Import(ctx, export); !errors.Is(err, ErrBudgetNotEmpty) {
+		t.Errorf("expected ErrBudgetNotEmpty, got %v", err)
+	}
+}
+
+func TestExportService_ImportValidates(t *testing.T) {
+	ctx := context.Background()
+	service, _, _, categoryRepo, _, _, _ := newTestExportService()
+	categoryRepo.Create(ctx, &domain.Category{ID: "starter", Name: "Food"})
+	missing := "missing"
+
+	tests := []struct {
+		name   string
+		export BudgetExport
+		want   string
+	}{
+		{"wrong format", BudgetExport{Format: "budget-template", Version: BudgetExportVersion}, "not a budget export"},
+		{"newer version", BudgetExport{Format: BudgetExportFormat, Version: BudgetExportVersion + 1}, "unsupported"},
+		{"bad account type", BudgetExport{Format: BudgetExportFormat, Version: BudgetExportVersion,
+			Accounts: []*domain.Account{{ID: "a", Type: "brokerage"}}}, "invalid type"},
+		{"duplicate ID", BudgetExport{Format: BudgetExportFormat, Version: BudgetExportVersion,
+			Accounts:   []*domain.Account{{ID: "a", Type: domain.AccountTypeCash}},
+			Categories: []*domain.Category{{ID: "a"}}}, "more than once"},
+		{"dangling category", BudgetExport{Format: BudgetExportFormat, Version: BudgetExportVersion,
+			Accounts:     []*domain.Account{{ID: "a", Type: domain.AccountTypeCash}},
+			Transactions: []*domain.Transaction{{ID: "t", AccountID: "a", CategoryID: &missing}}}, "missing category"},
+		{"dangling interest category", BudgetExport{Format: BudgetExportFormat, Version: BudgetExportVersion,
+			Accounts: []*domain.Account{{ID: "a", Type: domain.AccountTypeLoan, Loan: &domain.LoanTerms{MonthlyPayment: 1, InterestCategoryID: &missing}}}}, "missing category"},
+		{"move to a dangling category", BudgetExport{Format: BudgetExportFormat, Version: BudgetExportVersion,
+			Moves: []*domain.CategoryMove{{ID: "m", ToCategoryID: missing, Amount: 1, Reason: domain.CategoryMoveCover}}}, "missing category"},
+		{"move from a dangling category", BudgetExport{Format: BudgetExportFormat, Version: BudgetExportVersion,
+			Categories: []*domain.Category{{ID: "c"}},
+			Moves:      []*domain.CategoryMove{{ID: "m", FromCategoryID: &missing, ToCategoryID: "c", Amount: 1, Reason: domain.CategoryMoveSpending}}}, "missing category"},
+		{"move for a dangling transaction", BudgetExport{Format: BudgetExportFormat, Version: BudgetExportVersion,
+			Categories: []*domain.Category{{ID: "c"}},
+			Moves:      []*domain.CategoryMove{{ID: "m", ToCategoryID: "c", Amount: 1, Reason: domain.CategoryMoveSpending, TransactionID: &missing}}}, "missing transaction"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.Import(ctx, &tt.export); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+	if _, ok := categoryRepo.categories["starter"]; !ok {
+		t.Error("expected a rejected import to leave the budget alone")
+	}
+}
+
+func TestExportService_ImportYNAB(t *testing.T) {
+	ctx := context.Background()
+	service, accountRepo, groupRepo, categoryRepo, allocationRepo, transactionRepo, _ := newTestExportService()
+
+	register := `"Account","Flag","Date","Payee","Category Group/Category","Category Group","Category","Memo","Outflow","Inflow","Cleared"
+"Checking","","01/01/2025","Starting Balance","Inflow: Ready to Assign","Inflow","Ready to Assign","",$0.00,$1000.00,"Reconciled"
+"Visa","","01/01/2025","Starting Balance","Inflow: Ready to Assign","Inflow","Ready to Assign","",$300.00,$0.00,"Reconciled"
+"Visa","","01/10/2025","Market","Everyday: Groceries","Everyday","Groceries","",$50.00,$0.00,"Cleared"
+"Checking","","01/15/2025","Employer","Inflow: Ready to Assign","Inflow","Ready to Assign","January",$0.00,$2000.00,"Cleared"
+"Checking","","01/20/2025","Transfer : Visa","","","","",$100.00,$0.00,"Cleared"
+"Visa","","01/20/2025","Transfer : Checking","","","","",$0.00,$100.00,"Cleared"
+"Checking","","01/21/2025","Landlord","Bills: Rent","Bills","Rent","",$900.00,$0.00,"Cleared"
+`
+	budget := `"Month","Category Group/Category","Category Group","Category","Assigned","Activity","Available"
+"Jan 2025","Credit Card Payments: Visa","Credit Card Payments","Visa",$100.00,$0.00,$0.00
+"Jan 2025","Everyday: Groceries","Everyday","Groceries",$200.00,-$50.00,$150.00
+"Jan 2025","Bills: Rent","Bills","Rent",$900.00,-$900.00,$0.00
+"Feb 2025","Everyday: Groceries","Everyday","Groceries",$0.00,$0.00,$150.00
+`
+	result, err := service.ImportYNAB(ctx, strings.NewReader(register), strings.NewReader(budget), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The starting debt and the transfer's inflow aren't transactions of their own
+	if result.Accounts != 2 || result.CategoryGroups != 3 || result.Categories != 3 || result.Allocations != 3 || result.Transactions != 6 {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	accounts := make(map[string]*domain.Account)
+	for _, account := range accountRepo.accounts {
+		accounts[account.Name] = account
+	}
+	if checking := accounts["Checking"]; checking == nil || checking.Type != domain.AccountTypeChecking || checking.Balance != 200000 {
+		t.Errorf("unexpected checking account %+v", checking)
+	}
+	// Visa has a payment category in the budget, so it's a credit card
+	visa := accounts["Visa"]
+	if visa == nil || visa.Type != domain.AccountTypeCredit || visa.Balance != -25000 {
+		t.Fatalf("unexpected card %+v", visa)
+	}
+	payment, err := categoryRepo.GetPaymentCategoryByAccountID(ctx, visa.ID)
+	if err != nil || payment == nil {
+		t.Fatalf("expected a payment category for the card, got %v", err)
+	}
+	if len(groupRepo.groups) != 3 {
+		t.Errorf("expected Credit Card Payments, Everyday and Bills groups, got %+v", groupRepo.groups)
+	}
+
+	var transfers, income int
+	var memoKept bool
+	for _, txn := range transactionRepo.transactions {
+		switch {
+		case txn.Type == domain.TransactionTypeTransfer:
+			transfers++
+			if txn.Amount < 0 && (txn.CategoryID == nil || *txn.CategoryID != payment.ID) {
+				t.Errorf("expected the card payment to come out of its payment category, got %+v", txn)
+			}
+		case txn.Amount > 0:
+			income++
+			if txn.CategoryID != nil {
+				t.Errorf("expected income to be uncategorized, got %+v", txn)
+			}
+		case txn.CategoryID == nil:
+			t.Errorf("expected spending to be categorized, got %+v", txn)
+		}
+		memoKept = memoKept || txn.Description == "Employer - January"
+	}
+	if transfers != 2 || income != 2 || !memoKept {
+		t.Errorf("expected 2 transfer sides and 2 income transactions with the memo kept, got %d and %d", transfers, income)
+	}
+
+	var paymentAllocated int64
+	for _, allocation := range allocationRepo.allocations {
+		if allocation.CategoryID == payment.ID {
+			paymentAllocated += allocation.Amount
+		}
+	}
+	if paymentAllocated != 10000 {
+		t.Errorf("expected the card's payment category to get its assigned amount, got %d", paymentAllocated)
+	}
+
+	if _, err := service.ImportYNAB(ctx, strings.NewReader(register), nil, nil); !errors.Is(err, ErrBudgetNotEmpty) {
+		t.Errorf("expected ErrBudgetNotEmpty, got %v", err)
+	}
+}
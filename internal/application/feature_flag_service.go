@@ -0,0 +1,147 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// FeatureFlagStatus describes a flag's configured default, runtime override and effective state
+type FeatureFlagStatus struct {
+	Flag        domain.FeatureFlag `json:"flag"`
+	Description string             `json:"description"`
+	Default     bool               `json:"default"`  // From FEATURE_FLAGS
+	Override    *bool              `json:"override"` // Set at runtime; nil when the default applies
+	Enabled     bool               `json:"enabled"`
+}
+
+// FeatureFlagService answers whether features are enabled
+// Defaults come from configuration; overrides set at runtime are stored in settings
+// and win over the defaults.
+type FeatureFlagService struct {
+	settingRepo domain.SettingRepository
+	defaults    map[domain.FeatureFlag]bool
+
+	mu        sync.Mutex
+	overrides map[domain.FeatureFlag]bool // nil until loaded from settings
+}
+
+// NewFeatureFlagService creates a new feature flag service
+// enabled lists the flags that are on by default.
+func NewFeatureFlagService(settingRepo domain.SettingRepository, enabled []domain.FeatureFlag) *FeatureFlagService {
+	defaults := make(map[domain.FeatureFlag]bool, len(enabled))
+	for _, flag := range enabled {
+		defaults[flag] = true
+	}
+	return &FeatureFlagService{settingRepo: settingRepo, defaults: defaults}
+}
+
+// Enabled reports whether a feature is on
+// If overrides can't be loaded the configured default is used.
+func (s *FeatureFlagService) Enabled(ctx context.Context, flag domain.FeatureFlag) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if overrides, err := s.loadOverrides(ctx); err == nil {
+		if enabled, ok := overrides[flag]; ok {
+			return enabled
+		}
+	}
+	return s.defaults[flag]
+}
+
+// List returns every known flag, sorted by name
+func (s *FeatureFlagService) List(ctx context.Context) ([]*FeatureFlagStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	overrides, err := s.loadOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*FeatureFlagStatus, 0, len(domain.FeatureFlags))
+	for flag, description := range domain.FeatureFlags {
+		statuses = append(statuses, s.status(flag, description, overrides))
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Flag < statuses[j].Flag })
+	return statuses, nil
+}
+
+// SetOverride switches a flag on or off at runtime; nil removes the override so the
+// configured default applies again
+func (s *FeatureFlagService) SetOverride(ctx context.Context, flag domain.FeatureFlag, enabled *bool) (*FeatureFlagStatus, error) {
+	if !flag.IsValid() {
+		return nil, fmt.Errorf("unknown feature flag %q", flag)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.loadOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := make(map[domain.FeatureFlag]bool, len(current)+1)
+	for f, on := range current {
+		overrides[f] = on
+	}
+	if enabled == nil {
+		delete(overrides, flag)
+	} else {
+		overrides[flag] = *enabled
+	}
+
+	value, err := json.Marshal(overrides)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode feature flags: %w", err)
+	}
+	if err := s.settingRepo.Set(ctx, &domain.Setting{
+		Key:       domain.SettingKeyFeatureFlags,
+		Value:     string(value),
+		UpdatedAt: time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+
+	s.overrides = overrides
+	return s.status(flag, domain.FeatureFlags[flag], overrides), nil
+}
+
+func (s *FeatureFlagService) status(flag domain.FeatureFlag, description string, overrides map[domain.FeatureFlag]bool) *FeatureFlagStatus {
+	status := &FeatureFlagStatus{
+		Flag:        flag,
+		Description: description,
+		Default:     s.defaults[flag],
+		Enabled:     s.defaults[flag],
+	}
+	if enabled, ok := overrides[flag]; ok {
+		status.Override = &enabled
+		status.Enabled = enabled
+	}
+	return status
+}
+
+// loadOverrides reads the overrides from settings once; callers hold s.mu
+func (s *FeatureFlagService) loadOverrides(ctx context.Context) (map[domain.FeatureFlag]bool, error) {
+	if s.overrides != nil {
+		return s.overrides, nil
+	}
+
+	// Nothing saved yet means no overrides
+	overrides := map[domain.FeatureFlag]bool{}
+	if setting, err := s.settingRepo.Get(ctx, domain.SettingKeyFeatureFlags); err == nil {
+		if err := json.Unmarshal([]byte(setting.Value), &overrides); err != nil {
+			return nil, fmt.Errorf("failed to decode feature flags: %w", err)
+		}
+	}
+
+	s.overrides = overrides
+	return overrides, nil
+}
@@ -0,0 +1,80 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type mockSettingRepository struct {
+	settings map[string]*domain.Setting
+}
+
+func newMockSettingRepository() *mockSettingRepository {
+	return &mockSettingRepository{settings: make(map[string]*domain.Setting)}
+}
+
+func (m *mockSettingRepository) Get(ctx context.Context, key string) (*domain.Setting, error) {
+	setting, ok := m.settings[key]
+	if !ok {
+		return nil, errors.New("setting not found")
+	}
+	return setting, nil
+}
+
+func (m *mockSettingRepository) Set(ctx context.Context, setting *domain.Setting) error {
+	m.settings[setting.Key] = setting
+	return nil
+}
+
+func (m *mockSettingRepository) List(ctx context.Context) ([]*domain.Setting, error) {
+	var settings []*domain.Setting
+	for _, setting := range m.settings {
+		settings = append(settings, setting)
+	}
+	return settings, nil
+}
+
+func TestFeatureFlagOverrides(t *testing.T) {
+	ctx := context.Background()
+	repo := newMockSettingRepository()
+	service := NewFeatureFlagService(repo, []domain.FeatureFlag{domain.FeatureSandboxMode})
+
+	if !service.Enabled(ctx, domain.FeatureSandboxMode) {
+		t.Error("expected configured flag to be on")
+	}
+	if service.Enabled(ctx, domain.FeatureBankSync) {
+		t.Error("expected other flags to be off by default")
+	}
+
+	off, on := false, true
+	if _, err := service.SetOverride(ctx, domain.FeatureSandboxMode, &off); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.SetOverride(ctx, domain.FeatureBankSync, &on); err != nil {
+		t.Fatal(err)
+	}
+	if service.Enabled(ctx, domain.FeatureSandboxMode) || !service.Enabled(ctx, domain.FeatureBankSync) {
+		t.Error("expected overrides to win over defaults")
+	}
+
+	// Overrides are stored, so a restarted service sees them
+	restarted := NewFeatureFlagService(repo, []domain.FeatureFlag{domain.FeatureSandboxMode})
+	if restarted.Enabled(ctx, domain.FeatureSandboxMode) || !restarted.Enabled(ctx, domain.FeatureBankSync) {
+		t.Error("expected overrides to survive a restart")
+	}
+
+	status, err := restarted.SetOverride(ctx, domain.FeatureSandboxMode, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Override != nil || !status.Enabled {
+		t.Errorf("expected clearing the override to restore the default, got %+v", status)
+	}
+
+	if _, err := service.SetOverride(ctx, "time_travel", &on); err == nil {
+		t.Error("expected unknown flag to be rejected")
+	}
+}
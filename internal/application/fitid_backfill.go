@@ -0,0 +1,166 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// Statuses of a FitIDBackfillRow
+const (
+	FitIDBackfillAssigned  = "assigned"  // The FitID was given to the matching transaction
+	FitIDBackfillExisting  = "existing"  // A transaction in the account already has the FitID
+	FitIDBackfillAmbiguous = "ambiguous" // More than one transaction matches; none was changed
+	FitIDBackfillUnmatched = "unmatched" // No transaction without a FitID matches, or the file's transaction has no FitID
+)
+
+// FitIDBackfillResult is what backfilling FitIDs from a file did, or would do on a dry run
+type FitIDBackfillResult struct {
+	DryRun            bool               `json:"dry_run"`
+	TotalTransactions int                `json:"total_transactions"`
+	Assigned          int                `json:"assigned"`
+	Existing          int                `json:"existing"`
+	Ambiguous         int                `json:"ambiguous"`
+	Unmatched         int                `json:"unmatched"`
+	Rows              []FitIDBackfillRow `json:"rows"` // One per transaction in the file
+}
+
+// FitIDBackfillRow is what became of one transaction in the file
+type FitIDBackfillRow struct {
+	FitID         string    `json:"fitid"`
+	Date          time.Time `json:"date"`
+	Amount        int64     `json:"amount"`
+	Description   string    `json:"description"`
+	Status        string    `json:"status"`
+	TransactionID string    `json:"transaction_id,omitempty"` // The transaction given the FitID, or the one that has it
+}
+
+// BackfillFitIDs gives the account's transactions without a FitID the FitIDs of the
+// matching transactions in an OFX file, so importing the bank's files finds them as
+// duplicates from then on
+// Transactions entered by hand or imported before FitIDs were kept have none. Only exact
+// matches count: same day and same amount, with the description breaking ties between
+// several. Transactions of any age in the file are matched. Nothing is imported and no
+// balance changes; with dryRun nothing is saved.
+func (s *ImportService) BackfillFitIDs(ctx context.Context, accountID string, reader io.Reader, dryRun bool) (*FitIDBackfillResult, error) {
+	// Long-time users' files go back further than an import would look
+	parsed, err := s.readOFX(ctx, accountID, reader, s.ofxParser.ParseAll)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &FitIDBackfillResult{
+		DryRun:            dryRun,
+		TotalTransactions: len(parsed.transactions),
+		Rows:              make([]FitIDBackfillRow, 0, len(parsed.transactions)),
+	}
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		transactions, err := s.transactionRepo.ListByAccount(ctx, accountID)
+		if err != nil {
+			return fmt.Errorf("failed to list transactions: %w", err)
+		}
+
+		existing := make(map[string]string) // Transaction IDs by FitID
+		var candidates []*domain.Transaction
+		starting := startingBalanceTransaction(transactions)
+		for _, transaction := range transactions {
+			switch {
+			case transaction.FitID != nil:
+				existing[*transaction.FitID] = transaction.ID
+			case transaction != starting:
+				candidates = append(candidates, transaction)
+			}
+		}
+
+		for _, txn := range parsed.transactions {
+			row := FitIDBackfillRow{FitID: txn.FitID, Date: txn.Date, Amount: txn.Amount, Description: txn.Description}
+			if txn.FitID == "" {
+				row.Status = FitIDBackfillUnmatched
+			} else if id, ok := existing[txn.FitID]; ok {
+				row.Status = FitIDBackfillExisting
+				row.TransactionID = id
+			} else {
+				match, ambiguous := matchFitIDCandidate(candidates, txn)
+				switch {
+				case ambiguous:
+					row.Status = FitIDBackfillAmbiguous
+				case match == nil:
+					row.Status = FitIDBackfillUnmatched
+				default:
+					row.Status = FitIDBackfillAssigned
+					row.TransactionID = match.ID
+					candidates = removeTransaction(candidates, match)
+					existing[txn.FitID] = match.ID
+					if !dryRun {
+						fitID := txn.FitID
+						match.FitID = &fitID
+						match.UpdatedAt = time.Now()
+						if err := s.transactionRepo.Update(ctx, match); err != nil {
+							return fmt.Errorf("failed to backfill FitID: %w", err)
+						}
+					}
+				}
+			}
+
+			switch row.Status {
+			case FitIDBackfillAssigned:
+				result.Assigned++
+			case FitIDBackfillExisting:
+				result.Existing++
+			case FitIDBackfillAmbiguous:
+				result.Ambiguous++
+			case FitIDBackfillUnmatched:
+				result.Unmatched++
+			}
+			result.Rows = append(result.Rows, row)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// matchFitIDCandidate finds the one candidate dated the same day as txn with the same
+// amount, or with the same description too when several are
+// ambiguous is true when the description doesn't settle it either.
+func matchFitIDCandidate(candidates []*domain.Transaction, txn ImportedTransaction) (match *domain.Transaction, ambiguous bool) {
+	day := txn.Date.Format("2006-01-02")
+	var matches []*domain.Transaction
+	for _, candidate := range candidates {
+		if candidate.Amount == txn.Amount && candidate.Date.UTC().Format("2006-01-02") == day {
+			matches = append(matches, candidate)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, false
+	case 1:
+		return matches[0], false
+	}
+
+	for _, candidate := range matches {
+		if candidate.Description != txn.Description {
+			continue
+		}
+		if match != nil {
+			return nil, true
+		}
+		match = candidate
+	}
+	return match, match == nil
+}
+
+// removeTransaction returns transactions without the given one
+func removeTransaction(transactions []*domain.Transaction, remove *domain.Transaction) []*domain.Transaction {
+	for i, transaction := range transactions {
+		if transaction == remove {
+			return append(transactions[:i], transactions[i+1:]...)
+		}
+	}
+	return transactions
+}
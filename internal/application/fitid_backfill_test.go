@@ -0,0 +1,111 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/billybbuffum/budget/internal/infrastructure/csv"
+	"github.com/billybbuffum/budget/internal/infrastructure/ofx"
+	"github.com/billybbuffum/budget/internal/infrastructure/qif"
+)
+
+// backfillOFX is a bank statement with one transaction per line of "FITID DATE AMOUNT NAME"
+func backfillOFX(transactions ...string) string {
+	var list strings.Builder
+	for _, txn := range transactions {
+		fields := strings.SplitN(txn, " ", 4)
+		fmt.Fprintf(&list, "<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>%s<TRNAMT>%s<FITID>%s<NAME>%s</STMTTRN>\n", fields[1], fields[2], fields[0], fields[3])
+	}
+	return `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+SECURITY:NONE
+ENCODING:USASCII
+CHARSET:1252
+COMPRESSION:NONE
+OLDFILEUID:NONE
+NEWFILEUID:NONE
+
+<OFX>
+<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0<SEVERITY>INFO</STATUS><DTSERVER>20250401<LANGUAGE>ENG</SONRS></SIGNONMSGSRSV1>
+<BANKMSGSRSV1><STMTTRNRS><TRNUID>1<STATUS><CODE>0<SEVERITY>INFO</STATUS>
+<STMTRS><CURDEF>USD<BANKACCTFROM><BANKID>1<ACCTID>1<ACCTTYPE>CHECKING</BANKACCTFROM>
+<BANKTRANLIST><DTSTART>20200101<DTEND>20250401
+` + list.String() + `</BANKTRANLIST>
+<LEDGERBAL><BALAMT>0<DTASOF>20250401</LEDGERBAL>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+}
+
+func TestImportService_BackfillFitIDs(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking}
+	imported := "OLD-1"
+	day := func(d int) time.Time { return time.Date(2021, 3, d, 0, 0, 0, 0, time.UTC) }
+	transactionRepo := newMockTransactionRepository()
+	transactionRepo.transactions = []*domain.Transaction{
+		{ID: "coffee", AccountID: "checking", Type: domain.TransactionTypeNormal, Amount: -450, Description: "Coffee", Date: day(1)},
+		{ID: "lunch-a", AccountID: "checking", Type: domain.TransactionTypeNormal, Amount: -1200, Description: "Lunch", Date: day(2)},
+		{ID: "lunch-b", AccountID: "checking", Type: domain.TransactionTypeNormal, Amount: -1200, Description: "Lunch", Date: day(2)},
+		{ID: "rent", AccountID: "checking", Type: domain.TransactionTypeNormal, Amount: -100000, Description: "Rent", Date: day(3), FitID: &imported},
+		{ID: "books", AccountID: "checking", Type: domain.TransactionTypeNormal, Amount: -2500, Description: "Books", Date: day(4)},
+	}
+	categoryRepo := newMockCategoryRepository()
+	service := NewImportService(transactionRepo, accountRepo, newMockBudgetStateRepository(0, 0), &mockImportFileRepository{}, newMockSettingRepository(),
+		ofx.NewParser(), csv.NewParser(), qif.NewParser(),
+		NewPluginService(categoryRepo, accountRepo, transactionRepo, newMockAllocationRepository(), nil, nil, nil),
+		NewPayeeService(newMockPayeeRepository(), newMockPayeeRuleRepository(), newMockAccountRepository(0), categoryRepo),
+		NewTransferHintService(&mockTransferHintRepository{}, accountRepo, transactionRepo),
+		&mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{})
+
+	// Years old, so an import would skip them all
+	file := backfillOFX(
+		"F1 20210301 -4.50 COFFEE SHOP",
+		"F2 20210302 -12.00 LUNCH PLACE",
+		"OLD-1 20210303 -1000.00 RENT",
+		"F4 20210305 -25.00 BOOKSTORE", // A day off isn't an exact match
+	)
+
+	preview, err := service.BackfillFitIDs(ctx, "checking", strings.NewReader(file), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Assigned != 1 || preview.Ambiguous != 1 || preview.Existing != 1 || preview.Unmatched != 1 {
+		t.Fatalf("unexpected dry run: %+v", preview)
+	}
+	if transactionRepo.transactions[0].FitID != nil {
+		t.Fatal("expected a dry run to save nothing")
+	}
+
+	result, err := service.BackfillFitIDs(ctx, "checking", strings.NewReader(file), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Assigned != 1 || result.Rows[0].Status != FitIDBackfillAssigned || result.Rows[0].TransactionID != "coffee" {
+		t.Fatalf("expected the coffee matched, got %+v", result.Rows)
+	}
+	if result.Rows[2].Status != FitIDBackfillExisting || result.Rows[2].TransactionID != "rent" {
+		t.Errorf("expected rent to already have its FitID, got %+v", result.Rows[2])
+	}
+	if coffee := transactionRepo.transactions[0]; coffee.FitID == nil || *coffee.FitID != "F1" || coffee.ImportID != nil {
+		t.Errorf("expected the coffee to take F1 without becoming imported, got %+v", coffee)
+	}
+	if transactionRepo.transactions[1].FitID != nil || transactionRepo.transactions[2].FitID != nil || transactionRepo.transactions[4].FitID != nil {
+		t.Error("expected the ambiguous and unmatched transactions left alone")
+	}
+
+	// Imports find the backfilled transaction as a duplicate from now on
+	if existing, err := transactionRepo.FindByFitID(ctx, "checking", "F1"); err != nil || existing == nil || existing.ID != "coffee" {
+		t.Errorf("expected F1 to find the coffee, got %+v, %v", existing, err)
+	}
+
+	if _, err := service.BackfillFitIDs(ctx, "missing", strings.NewReader(file), false); err == nil {
+		t.Error("expected an unknown account to be rejected")
+	}
+}
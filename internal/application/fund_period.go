@@ -0,0 +1,165 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/google/uuid"
+)
+
+// FundingBasis picks which of last month's numbers a period is funded from
+// The names match the quick budget amounts shown in the summary.
+type FundingBasis string
+
+const (
+	FundingBudgetedLastMonth FundingBasis = "budgeted_last_month" // Copy last month's allocations
+	FundingSpentLastMonth    FundingBasis = "spent_last_month"
+	FundingAverageSpent      FundingBasis = "average_spent" // Three-month average
+)
+
+// IsValid reports whether the funding basis is a known value
+func (b FundingBasis) IsValid() bool {
+	return b == FundingBudgetedLastMonth || b == FundingSpentLastMonth || b == FundingAverageSpent
+}
+
+// maxFundingScalePercent caps how far funding can be scaled up
+const maxFundingScalePercent = 1000
+
+// FundPeriodOptions controls how FundPeriod fills a period's allocations
+type FundPeriodOptions struct {
+	Basis        FundingBasis // Defaults to FundingBudgetedLastMonth
+	ScalePercent int          // Percentage of the basis to assign; 0 means 100
+	GroupIDs     []string     // Only fund these groups (domain.UngroupedCategoriesID for ungrouped); empty funds all
+	Overwrite    bool         // Replace allocations already made in the period instead of skipping those categories
+}
+
+// FundedCategory is one category FundPeriod assigned money to
+type FundedCategory struct {
+	Category   *domain.Category   `json:"category"`
+	Allocation *domain.Allocation `json:"allocation"`
+}
+
+// FundPeriodResult describes the allocations FundPeriod made
+type FundPeriodResult struct {
+	Period        string            `json:"period"`
+	Basis         FundingBasis      `json:"basis"`
+	Funded        []*FundedCategory `json:"funded"`
+	Skipped       []string          `json:"skipped"`        // IDs of categories that already had an allocation for the period
+	TotalAssigned int64             `json:"total_assigned"` // Sum of the funded allocations
+	ReadyToAssign int64             `json:"ready_to_assign"`
+}
+
+// FundPeriod assigns money to every category in one call, based on the month before period
+// Each category gets its basis amount scaled by ScalePercent, rounded to the cent.
+// Categories whose amount comes to nothing are left alone, as are categories already
+// funded in the period unless Overwrite is set. Ready to Assign isn't checked, matching
+// CreateAllocation; the result reports what is left.
+func (s *AllocationService) FundPeriod(ctx context.Context, period string, opts FundPeriodOptions) (*FundPeriodResult, error) {
+	if opts.Basis == "" {
+		opts.Basis = FundingBudgetedLastMonth
+	}
+	if !opts.Basis.IsValid() {
+		return nil, fmt.Errorf("basis must be budgeted_last_month, spent_last_month or average_spent")
+	}
+	if opts.ScalePercent == 0 {
+		opts.ScalePercent = 100
+	}
+	if opts.ScalePercent < 0 || opts.ScalePercent > maxFundingScalePercent {
+		return nil, fmt.Errorf("scale_percent must be between 1 and %d", maxFundingScalePercent)
+	}
+	for _, groupID := range opts.GroupIDs {
+		if groupID == domain.UngroupedCategoriesID {
+			continue
+		}
+		if _, err := s.groupRepo.GetByID(ctx, groupID); err != nil {
+			return nil, fmt.Errorf("category group not found")
+		}
+	}
+
+	quickBudget, err := s.quickBudgetAmounts(ctx, period)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	existing, err := s.allocationRepo.ListByPeriod(ctx, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list allocations: %w", err)
+	}
+	allocated := make(map[string]*domain.Allocation, len(existing))
+	for _, allocation := range existing {
+		allocated[allocation.CategoryID] = allocation
+	}
+
+	result := &FundPeriodResult{Period: period, Basis: opts.Basis, Funded: []*FundedCategory{}, Skipped: []string{}}
+	for _, category := range categories {
+		if len(opts.GroupIDs) > 0 && !slices.Contains(opts.GroupIDs, groupOf(category)) {
+			continue
+		}
+		basis := fundingAmount(quickBudget.forCategory(category.ID), opts.Basis)
+		amount := domain.Cents(basis).Percent(int64(opts.ScalePercent)).Amount // Rounded to the nearest cent
+		if basis <= 0 || amount == 0 {
+			continue
+		}
+
+		now := time.Now()
+		allocation := allocated[category.ID]
+		switch {
+		case allocation == nil:
+			allocation = &domain.Allocation{
+				ID:         uuid.New().String(),
+				CategoryID: category.ID,
+				Amount:     amount,
+				Period:     period,
+				Notes:      "Funded from " + string(opts.Basis),
+				CreatedAt:  now,
+				UpdatedAt:  now,
+			}
+			if err := s.allocationRepo.Create(ctx, allocation); err != nil {
+				return nil, fmt.Errorf("failed to create allocation: %w", err)
+			}
+		case opts.Overwrite:
+			allocation.Amount = amount
+			allocation.Notes = "Funded from " + string(opts.Basis)
+			allocation.UpdatedAt = now
+			if err := s.allocationRepo.Update(ctx, allocation); err != nil {
+				return nil, fmt.Errorf("failed to update allocation: %w", err)
+			}
+		default:
+			result.Skipped = append(result.Skipped, category.ID)
+			continue
+		}
+
+		result.Funded = append(result.Funded, &FundedCategory{Category: category, Allocation: allocation})
+		result.TotalAssigned += amount
+	}
+
+	result.ReadyToAssign, err = s.CalculateReadyToAssignForPeriod(ctx, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate ready to assign: %w", err)
+	}
+	return result, nil
+}
+
+// groupOf returns the ID of a category's group, or domain.UngroupedCategoriesID
+func groupOf(category *domain.Category) string {
+	if category.GroupID == nil || *category.GroupID == "" {
+		return domain.UngroupedCategoriesID
+	}
+	return *category.GroupID
+}
+
+func fundingAmount(amounts *domain.QuickBudgetAmounts, basis FundingBasis) int64 {
+	switch basis {
+	case FundingSpentLastMonth:
+		return amounts.SpentLastMonth
+	case FundingAverageSpent:
+		return amounts.AverageSpent
+	}
+	return amounts.BudgetedLastMonth
+}
@@ -0,0 +1,98 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestAllocationService_FundPeriod(t *testing.T) {
+	billsID := "bills"
+	groceriesID, rentID, funID := "groceries", "rent", "fun"
+	newService := func() (*AllocationService, *mockAllocationRepository) {
+		ctx := context.Background()
+		categoryRepo := newMockCategoryRepository()
+		categoryRepo.categories[groceriesID] = &domain.Category{ID: groceriesID, Name: "Groceries", GroupID: &billsID}
+		categoryRepo.categories[rentID] = &domain.Category{ID: rentID, Name: "Rent", GroupID: &billsID}
+		categoryRepo.categories[funID] = &domain.Category{ID: funID, Name: "Fun"}
+		groupRepo := newMockCategoryGroupRepository()
+		groupRepo.Create(ctx, &domain.CategoryGroup{ID: billsID, Name: "Bills"})
+
+		allocationRepo := newMockAllocationRepository()
+		allocationRepo.Create(ctx, &domain.Allocation{ID: "a1", CategoryID: groceriesID, Period: "2025-10", Amount: 40000})
+		allocationRepo.Create(ctx, &domain.Allocation{ID: "a2", CategoryID: rentID, Period: "2025-10", Amount: 150000})
+		allocationRepo.Create(ctx, &domain.Allocation{ID: "a3", CategoryID: funID, Period: "2025-10", Amount: 10000})
+		allocationRepo.Create(ctx, &domain.Allocation{ID: "a4", CategoryID: rentID, Period: "2025-11", Amount: 155000})
+
+		october := time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)
+		transactionRepo := newMockTransactionRepository()
+		transactionRepo.transactions = []*domain.Transaction{
+			{ID: "income", AccountID: "checking", Amount: 500000, Date: october},
+			{ID: "t1", AccountID: "checking", CategoryID: &groceriesID, Amount: -45050, Date: october},
+		}
+
+		service := NewAllocationService(allocationRepo, categoryRepo, transactionRepo, newMockBudgetStateRepository(0, 0),
+			newMockAccountRepository(0), groupRepo, newMockGoalRepository())
+		return service, allocationRepo
+	}
+	assigned := func(repo *mockAllocationRepository, categoryID string) int64 {
+		allocation, err := repo.GetByCategoryAndPeriod(context.Background(), categoryID, "2025-11")
+		if err != nil {
+			return 0
+		}
+		return allocation.Amount
+	}
+
+	t.Run("copies last month and keeps what is already assigned", func(t *testing.T) {
+		service, allocationRepo := newService()
+		result, err := service.FundPeriod(context.Background(), "2025-11", FundPeriodOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Funded) != 2 || result.TotalAssigned != 50000 || len(result.Skipped) != 1 || result.Skipped[0] != rentID {
+			t.Errorf("expected groceries and fun funded with rent skipped, got %+v", result)
+		}
+		if assigned(allocationRepo, groceriesID) != 40000 || assigned(allocationRepo, funID) != 10000 || assigned(allocationRepo, rentID) != 155000 {
+			t.Errorf("unexpected allocations: groceries %d, fun %d, rent %d",
+				assigned(allocationRepo, groceriesID), assigned(allocationRepo, funID), assigned(allocationRepo, rentID))
+		}
+	})
+
+	t.Run("scaled, for one group, overwriting", func(t *testing.T) {
+		service, allocationRepo := newService()
+		result, err := service.FundPeriod(context.Background(), "2025-11", FundPeriodOptions{ScalePercent: 110, GroupIDs: []string{billsID}, Overwrite: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Funded) != 2 || assigned(allocationRepo, groceriesID) != 44000 || assigned(allocationRepo, rentID) != 165000 || assigned(allocationRepo, funID) != 0 {
+			t.Errorf("expected only bills funded at 110%%, got groceries %d, rent %d, fun %d",
+				assigned(allocationRepo, groceriesID), assigned(allocationRepo, rentID), assigned(allocationRepo, funID))
+		}
+	})
+
+	t.Run("from last month's spending", func(t *testing.T) {
+		service, allocationRepo := newService()
+		result, err := service.FundPeriod(context.Background(), "2025-11", FundPeriodOptions{Basis: FundingSpentLastMonth})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Funded) != 1 || assigned(allocationRepo, groceriesID) != 45050 {
+			t.Errorf("expected only groceries funded with what it spent, got %+v", result)
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		service, _ := newService()
+		for _, opts := range []FundPeriodOptions{
+			{Basis: "everything"},
+			{ScalePercent: -10},
+			{GroupIDs: []string{"missing"}},
+		} {
+			if _, err := service.FundPeriod(context.Background(), "2025-11", opts); err == nil {
+				t.Errorf("expected %+v to be rejected", opts)
+			}
+		}
+	})
+}
@@ -0,0 +1,194 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/google/uuid"
+)
+
+// GoalService handles category goal business logic
+type GoalService struct {
+	goalRepo     domain.GoalRepository
+	categoryRepo domain.CategoryRepository
+}
+
+// NewGoalService creates a new goal service
+func NewGoalService(goalRepo domain.GoalRepository, categoryRepo domain.CategoryRepository) *GoalService {
+	return &GoalService{
+		goalRepo:     goalRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+// CreateGoal sets a funding target on a category
+func (s *GoalService) CreateGoal(ctx context.Context, categoryID string, targetAmount int64, targetDate *string, cadence domain.GoalCadence) (*domain.Goal, error) {
+	category, err := s.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil {
+		return nil, domain.ErrCategoryNotFound
+	}
+	if category.PaymentForAccountID != nil && *category.PaymentForAccountID != "" {
+		return nil, fmt.Errorf("payment categories can't have goals")
+	}
+	if _, err := s.goalRepo.GetByCategoryID(ctx, categoryID); err == nil {
+		return nil, domain.ErrGoalExists
+	} else if !errors.Is(err, domain.ErrGoalNotFound) {
+		return nil, err
+	}
+
+	if cadence == "" {
+		cadence = domain.GoalCadenceOnce
+	}
+	goal := &domain.Goal{
+		ID:           uuid.New().String(),
+		CategoryID:   categoryID,
+		TargetAmount: targetAmount,
+		TargetDate:   emptyToNil(targetDate),
+		Cadence:      cadence,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+	if err := validateGoal(goal); err != nil {
+		return nil, err
+	}
+
+	if err := s.goalRepo.Create(ctx, goal); err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+// GetGoal retrieves a goal by ID
+func (s *GoalService) GetGoal(ctx context.Context, id string) (*domain.Goal, error) {
+	return s.goalRepo.GetByID(ctx, id)
+}
+
+// ListGoals retrieves all goals
+func (s *GoalService) ListGoals(ctx context.Context) ([]*domain.Goal, error) {
+	return s.goalRepo.List(ctx)
+}
+
+// UpdateGoal changes a goal's target, date or cadence
+// Nil fields are left alone; an empty target date removes the date.
+func (s *GoalService) UpdateGoal(ctx context.Context, id string, targetAmount *int64, targetDate *string, cadence domain.GoalCadence) (*domain.Goal, error) {
+	goal, err := s.goalRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if targetAmount != nil {
+		goal.TargetAmount = *targetAmount
+	}
+	if targetDate != nil {
+		goal.TargetDate = emptyToNil(targetDate)
+	}
+	if cadence != "" {
+		goal.Cadence = cadence
+	}
+	if err := validateGoal(goal); err != nil {
+		return nil, err
+	}
+	goal.UpdatedAt = time.Now()
+
+	if err := s.goalRepo.Update(ctx, goal); err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+// DeleteGoal removes a goal; the category and its money are untouched
+func (s *GoalService) DeleteGoal(ctx context.Context, id string) error {
+	return s.goalRepo.Delete(ctx, id)
+}
+
+func validateGoal(goal *domain.Goal) error {
+	if goal.TargetAmount <= 0 {
+		return fmt.Errorf("target_amount must be positive")
+	}
+	switch goal.Cadence {
+	case domain.GoalCadenceOnce, domain.GoalCadenceMonthly:
+	case domain.GoalCadenceYearly:
+		if goal.TargetDate == nil {
+			return fmt.Errorf("yearly goals need a target_date")
+		}
+	default:
+		return fmt.Errorf("cadence must be once, monthly or yearly")
+	}
+	if goal.TargetDate != nil {
+		if _, err := time.Parse("2006-01", *goal.TargetDate); err != nil {
+			return fmt.Errorf("target_date must be YYYY-MM")
+		}
+	}
+	return nil
+}
+
+func emptyToNil(value *string) *string {
+	if value == nil || *value == "" {
+		return nil
+	}
+	return value
+}
+
+// goalProgress works out where a category stands against its goal in a period
+// carried is what the category brought into the period and assigned is this period's
+// allocation. Saving goals count both as funded, so spending from the category this
+// month doesn't make the goal look unfunded; monthly goals only count what's assigned.
+func goalProgress(goal *domain.Goal, period string, carried, assigned int64) *domain.GoalProgress {
+	progress := &domain.GoalProgress{Goal: goal}
+
+	if goal.Cadence == domain.GoalCadenceMonthly {
+		progress.Funded = assigned
+		progress.SuggestedMonthly = goal.TargetAmount
+	} else {
+		progress.Funded = max(0, carried) + assigned
+		needed := max(0, goal.TargetAmount-max(0, carried))
+		progress.SuggestedMonthly = needed
+
+		if goal.TargetDate != nil {
+			progress.DuePeriod = dueGoalPeriod(goal, period)
+			if months := monthsUntil(period, progress.DuePeriod); months > 1 {
+				// Spread what's left evenly, rounding up so the last month isn't short
+				progress.SuggestedMonthly = (needed + months - 1) / months
+			}
+		}
+	}
+
+	progress.Remaining = max(0, goal.TargetAmount-progress.Funded)
+	progress.PercentComplete = int(min(100, progress.Funded*100/goal.TargetAmount))
+	progress.NeededThisPeriod = max(0, progress.SuggestedMonthly-assigned)
+	progress.OnTrack = progress.NeededThisPeriod == 0
+	return progress
+}
+
+// dueGoalPeriod is the month a dated goal's current target falls due
+// Yearly goals come round again each year once their month has passed.
+func dueGoalPeriod(goal *domain.Goal, period string) string {
+	due := *goal.TargetDate
+	if goal.Cadence != domain.GoalCadenceYearly {
+		return due
+	}
+	dueMonth := due[len("2006-"):]
+	year := period[:len("2006")]
+	if dueMonth < period[len("2006-"):] {
+		current, _ := time.Parse("2006", year)
+		year = current.AddDate(1, 0, 0).Format("2006")
+	}
+	return year + "-" + dueMonth
+}
+
+// monthsUntil counts the months from period up to and including due (at least 1)
+func monthsUntil(period, due string) int64 {
+	from, err := time.Parse("2006-01", period)
+	if err != nil {
+		return 1
+	}
+	to, err := time.Parse("2006-01", due)
+	if err != nil {
+		return 1
+	}
+	months := int64(to.Year()-from.Year())*12 + int64(to.Month()-from.Month()) + 1
+	return max(1, months)
+}
@@ -0,0 +1,243 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// Budget concepts the in-app help explains
+const (
+	HelpConceptReadyToAssign      = "ready_to_assign"
+	HelpConceptAvailable          = "available"
+	HelpConceptUnderfunded        = "underfunded"
+	HelpConceptCreditOverspending = "credit_overspending"
+)
+
+// HelpConcepts explains the budget's concepts using the budget's own numbers for a period
+type HelpConcepts struct {
+	Period   string         `json:"period"`
+	Concepts []*HelpConcept `json:"concepts"`
+}
+
+// HelpConcept is one budget concept: a general explanation and an example worked from the budget
+type HelpConcept struct {
+	Key         string           `json:"key"`
+	Title       string           `json:"title"`
+	Explanation string           `json:"explanation"`
+	Example     string           `json:"example"`               // The explanation applied to this budget
+	Applies     bool             `json:"applies"`               // Whether the budget shows this right now, e.g. a card payment is underfunded
+	CategoryID  string           `json:"category_id,omitempty"` // Category the example is drawn from
+	Values      map[string]int64 `json:"values"`                // Amounts in the example (cents), for clients that write their own text
+}
+
+// HelpService builds the in-app help for budget concepts
+type HelpService struct {
+	allocationService *AllocationService
+}
+
+// NewHelpService creates a new help service
+func NewHelpService(allocationService *AllocationService) *HelpService {
+	return &HelpService{allocationService: allocationService}
+}
+
+// Concepts explains Ready to Assign, Available, underfunded card payments and credit
+// overspending, with examples worked from the budget's numbers in the given period
+func (s *HelpService) Concepts(ctx context.Context, period string) (*HelpConcepts, error) {
+	summaries, err := s.allocationService.GetAllocationSummary(ctx, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize allocations: %w", err)
+	}
+	readyToAssign, err := s.readyToAssignConcept(ctx, period)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HelpConcepts{
+		Period: period,
+		Concepts: []*HelpConcept{
+			readyToAssign,
+			availableConcept(summaries),
+			underfundedConcept(summaries),
+			creditOverspendingConcept(summaries),
+		},
+	}, nil
+}
+
+func (s *HelpService) readyToAssignConcept(ctx context.Context, period string) (*HelpConcept, error) {
+	rta, err := s.allocationService.CalculateReadyToAssignForPeriod(ctx, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate ready to assign: %w", err)
+	}
+	totals, err := s.allocationService.readyToAssignTotals(ctx, domain.PeriodMonth)
+	if err != nil {
+		return nil, err
+	}
+	var inflows, assigned int64
+	for _, total := range totals {
+		if total.Period <= period {
+			inflows += total.Inflows
+			assigned += total.Assigned
+		}
+	}
+	// Whatever the totals don't account for is cash overspending from earlier months
+	overspent := inflows - assigned - rta
+
+	example := fmt.Sprintf("Through %s you've received %s and assigned %s", period, formatBotAmount(inflows), formatBotAmount(assigned))
+	if overspent != 0 {
+		example += fmt.Sprintf(", and %s of cash overspending from earlier months came out of it", formatBotAmount(overspent))
+	}
+	if rta < 0 {
+		example += fmt.Sprintf(". That's %s more than you have, so take money back from categories until it's zero.", formatBotAmount(-rta))
+	} else {
+		example += fmt.Sprintf(", leaving %s ready to assign.", formatBotAmount(rta))
+	}
+
+	return &HelpConcept{
+		Key:   HelpConceptReadyToAssign,
+		Title: "Ready to Assign",
+		Explanation: "Ready to Assign is money you've received that doesn't have a job yet. Every inflow lands here, " +
+			"and assigning money to a category takes it out. Assign it until it reaches zero; " +
+			"if it goes negative, you've assigned more than you have.",
+		Example: example,
+		Applies: rta != 0,
+		Values: map[string]int64{
+			"inflows":           inflows,
+			"assigned":          assigned,
+			"cash_overspending": overspent,
+			"ready_to_assign":   rta,
+		},
+	}, nil
+}
+
+// availableConcept works its example from the category with the most spending, or failing
+// that the one with the most available
+func availableConcept(summaries []*domain.AllocationSummary) *HelpConcept {
+	concept := &HelpConcept{
+		Key:   HelpConceptAvailable,
+		Title: "Available",
+		Explanation: "Available is what a category can still spend: what you've assigned to it this month, " +
+			"plus whatever carried over from earlier months, less this month's spending. " +
+			"Money left at the end of the month rolls over to the next.",
+		Example: "Assign money to a category to see how much it has available.",
+		Values:  map[string]int64{},
+	}
+
+	var example *domain.AllocationSummary
+	for _, summary := range summaries {
+		if summary.Category.PaymentForAccountID != nil && *summary.Category.PaymentForAccountID != "" {
+			continue
+		}
+		if example == nil || summary.Activity < example.Activity ||
+			(summary.Activity == example.Activity && summary.Available > example.Available) {
+			example = summary
+		}
+	}
+	if example == nil || (example.Activity == 0 && example.Available == 0) {
+		return concept
+	}
+
+	var assigned int64
+	if example.Allocation != nil {
+		assigned = example.Allocation.Amount
+	}
+	carried := example.Available - assigned - example.Activity
+
+	parts := []string{fmt.Sprintf("%s assigned this month", formatBotAmount(assigned))}
+	if carried != 0 {
+		parts = append(parts, fmt.Sprintf("%s carried over", formatBotAmount(carried)))
+	}
+	if example.Activity < 0 {
+		parts = append(parts, fmt.Sprintf("%s spent", formatBotAmount(-example.Activity)))
+	} else if example.Activity > 0 {
+		parts = append(parts, fmt.Sprintf("%s in refunds", formatBotAmount(example.Activity)))
+	}
+	concept.Example = fmt.Sprintf("%s has %s, so %s is available.", example.Category.Name, strings.Join(parts, ", "), formatBotAmount(example.Available))
+	if example.Available < 0 {
+		concept.Example = fmt.Sprintf("%s has %s, so it's overspent by %s.", example.Category.Name, strings.Join(parts, ", "), formatBotAmount(-example.Available))
+	}
+	concept.Applies = true
+	concept.CategoryID = example.Category.ID
+	concept.Values = map[string]int64{
+		"assigned":     assigned,
+		"carried_over": carried,
+		"activity":     example.Activity,
+		"available":    example.Available,
+	}
+	return concept
+}
+
+// underfundedConcept works its example from the card payment category that's furthest short
+func underfundedConcept(summaries []*domain.AllocationSummary) *HelpConcept {
+	concept := &HelpConcept{
+		Key:   HelpConceptUnderfunded,
+		Title: "Underfunded credit card payment",
+		Explanation: "A credit card's payment category holds the money to pay what you charge to the card. " +
+			"It's underfunded when it has less than the card's balance, because purchases were charged " +
+			"from categories that didn't have the money or the card carried debt from before you started budgeting.",
+		Example: "None of your credit card payment categories are underfunded.",
+		Values:  map[string]int64{},
+	}
+
+	var example *domain.AllocationSummary
+	for _, summary := range summaries {
+		if summary.Underfunded == nil || *summary.Underfunded <= 0 {
+			continue
+		}
+		if example == nil || *summary.Underfunded > *example.Underfunded {
+			example = summary
+		}
+	}
+	if example == nil {
+		return concept
+	}
+
+	concept.Example = fmt.Sprintf("%s has %s but needs %s more to pay off the card.", example.Category.Name, formatBotAmount(example.Available), formatBotAmount(*example.Underfunded))
+	if len(example.UnderfundedCategories) > 0 {
+		concept.Example += fmt.Sprintf(" Spending from %s wasn't fully covered.", strings.Join(example.UnderfundedCategories, ", "))
+	}
+	concept.Applies = true
+	concept.CategoryID = example.Category.ID
+	concept.Values = map[string]int64{
+		"available":   example.Available,
+		"underfunded": *example.Underfunded,
+	}
+	return concept
+}
+
+// creditOverspendingConcept works its example from the category furthest overspent on credit
+func creditOverspendingConcept(summaries []*domain.AllocationSummary) *HelpConcept {
+	concept := &HelpConcept{
+		Key:   HelpConceptCreditOverspending,
+		Title: "Credit overspending",
+		Explanation: "Spending more on a credit card than a category has available is credit overspending. " +
+			"Unlike cash overspending, it doesn't come out of next month's Ready to Assign: it becomes debt on the card, " +
+			"and the card's payment category won't have enough to pay it off until you assign more.",
+		Example: "No categories are overspent on a credit card.",
+		Values:  map[string]int64{},
+	}
+
+	var example *domain.AllocationSummary
+	for _, summary := range summaries {
+		if summary.Status != domain.CategoryStatusOverspentCredit {
+			continue
+		}
+		if example == nil || summary.Available < example.Available {
+			example = summary
+		}
+	}
+	if example == nil {
+		return concept
+	}
+
+	concept.Example = fmt.Sprintf("%s is overspent by %s on a credit card. Assign %s to it to cover the purchases, or the card's balance grows by that much.",
+		example.Category.Name, formatBotAmount(-example.Available), formatBotAmount(-example.Available))
+	concept.Applies = true
+	concept.CategoryID = example.Category.ID
+	concept.Values = map[string]int64{
+		"overspent": -example.Available,
+	}
+	return concept
+}
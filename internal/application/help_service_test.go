@@ -0,0 +1,59 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestHelpService_Concepts(t *testing.T) {
+	ctx := context.Background()
+	cardID := "card"
+	groceriesID := "groceries-id"
+	paymentID := "card-payment-id"
+	categoryRepo := newMockCategoryRepository()
+	categoryRepo.categories[groceriesID] = &domain.Category{ID: groceriesID, Name: "Groceries"}
+	categoryRepo.categories[paymentID] = &domain.Category{ID: paymentID, Name: "Visa Payment", PaymentForAccountID: &cardID}
+	accountRepo := newMockAccountRepository(5000)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking, Balance: 10000}
+	accountRepo.accounts[cardID] = &domain.Account{ID: cardID, Name: "Visa", Type: domain.AccountTypeCredit, Balance: -5000}
+	transactionRepo := newMockTransactionRepository()
+	transactionRepo.transactions = []*domain.Transaction{
+		{ID: "income", AccountID: "checking", Amount: 10000, Date: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "spend", AccountID: cardID, CategoryID: &groceriesID, Amount: -5000, Date: time.Date(2025, 10, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	allocationRepo := newMockAllocationRepository()
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a1", CategoryID: groceriesID, Amount: 3000, Period: "2025-10"})
+	allocationService := NewAllocationService(allocationRepo, categoryRepo, transactionRepo,
+		newMockBudgetStateRepository(5000, 0), accountRepo,
+		newMockCategoryGroupRepository(), newMockGoalRepository())
+
+	help, err := NewHelpService(allocationService).Concepts(ctx, "2025-10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	concepts := map[string]*HelpConcept{}
+	for _, concept := range help.Concepts {
+		concepts[concept.Key] = concept
+	}
+	if len(concepts) != 4 {
+		t.Fatalf("expected four concepts, got %d", len(help.Concepts))
+	}
+
+	// Examples carry the budget's own numbers
+	if c := concepts[HelpConceptReadyToAssign]; c.Values["ready_to_assign"] != 7000 || !strings.Contains(c.Example, "$70.00 ready to assign") {
+		t.Errorf("expected $70.00 ready to assign, got %+v", c)
+	}
+	if c := concepts[HelpConceptAvailable]; c.CategoryID != groceriesID || c.Values["available"] != -2000 || !strings.Contains(c.Example, "overspent by $20.00") {
+		t.Errorf("expected groceries as the available example, got %+v", c)
+	}
+	if c := concepts[HelpConceptUnderfunded]; !c.Applies || c.CategoryID != paymentID || c.Values["underfunded"] <= 0 {
+		t.Errorf("expected the card payment to be underfunded, got %+v", c)
+	}
+	if c := concepts[HelpConceptCreditOverspending]; !c.Applies || c.CategoryID != groceriesID || c.Values["overspent"] != 2000 {
+		t.Errorf("expected groceries to be overspent on credit, got %+v", c)
+	}
+}
@@ -0,0 +1,121 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// maxImportMatchDays bounds how far apart a merged manual entry and import may be dated
+const maxImportMatchDays = 14
+
+// GetMatchingSettings returns the saved import matching settings, or defaults if none are saved
+// Merging is off until the user turns it on.
+func (s *ImportService) GetMatchingSettings(ctx context.Context) (*domain.ImportMatchingSettings, error) {
+	settings := &domain.ImportMatchingSettings{MatchDays: domain.DefaultImportMatchDays}
+
+	setting, err := s.settingRepo.Get(ctx, domain.SettingKeyImportMatching)
+	if err != nil {
+		// Nothing saved yet - use defaults
+		return settings, nil
+	}
+	if err := json.Unmarshal([]byte(setting.Value), settings); err != nil {
+		return nil, fmt.Errorf("failed to decode import matching settings: %w", err)
+	}
+	return settings, nil
+}
+
+// UpdateMatchingSettings validates and saves whether imports merge into manual entries
+func (s *ImportService) UpdateMatchingSettings(ctx context.Context, settings *domain.ImportMatchingSettings) (*domain.ImportMatchingSettings, error) {
+	if settings.MatchDays < 0 || settings.MatchDays > maxImportMatchDays {
+		return nil, fmt.Errorf("match_days must be between 0 and %d", maxImportMatchDays)
+	}
+
+	value, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode import matching settings: %w", err)
+	}
+	if err := s.settingRepo.Set(ctx, &domain.Setting{
+		Key:       domain.SettingKeyImportMatching,
+		Value:     string(value),
+		UpdatedAt: time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// manualMatcher merges an account's imported transactions into the ones entered by hand
+type manualMatcher struct {
+	transactionRepo domain.TransactionRepository
+	importID        string
+	matchDays       int
+	candidates      []*domain.Transaction // Manual entries not merged yet; nil when merging is off
+}
+
+// newManualMatcher collects the account's manual entries, if merging is turned on
+// Manual entries are normal transactions that didn't come from an import.
+func (s *ImportService) newManualMatcher(ctx context.Context, accountID, importID string) (*manualMatcher, error) {
+	settings, err := s.GetMatchingSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	matcher := &manualMatcher{transactionRepo: s.transactionRepo, importID: importID, matchDays: settings.MatchDays}
+	if !settings.AutoMerge {
+		return matcher, nil
+	}
+
+	transactions, err := s.transactionRepo.ListByAccount(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+	for _, transaction := range transactions {
+		if transaction.Type != domain.TransactionTypeNormal || transaction.FitID != nil || transaction.ImportID != nil {
+			continue
+		}
+		matcher.candidates = append(matcher.candidates, transaction)
+	}
+	return matcher, nil
+}
+
+// merge folds an imported transaction into the manual entry with the same amount and the
+// closest date, within the configured window
+// The entry keeps its category, description and note and takes the imported FitID, so
+// it is treated as imported from now on. Returns nil if nothing matches.
+func (m *manualMatcher) merge(ctx context.Context, txn ImportedTransaction) (*domain.Transaction, error) {
+	best := -1
+	var bestDays int
+	for i, candidate := range m.candidates {
+		if candidate.Amount != txn.Amount {
+			continue
+		}
+		days := domain.DaysApart(txn.Date, candidate.Date)
+		if days > m.matchDays {
+			continue
+		}
+		if best < 0 || days < bestDays {
+			best, bestDays = i, days
+		}
+	}
+	if best < 0 {
+		return nil, nil
+	}
+
+	manual := m.candidates[best]
+	m.candidates = append(m.candidates[:best], m.candidates[best+1:]...)
+
+	fitID := txn.FitID
+	manual.FitID = &fitID
+	manual.ImportID = &m.importID
+	if manual.OriginalCurrency == nil {
+		manual.OriginalCurrency, manual.OriginalAmount = txn.original()
+	}
+	manual.UpdatedAt = time.Now()
+	if err := m.transactionRepo.Update(ctx, manual); err != nil {
+		return nil, fmt.Errorf("failed to merge imported transaction: %w", err)
+	}
+	return manual, nil
+}
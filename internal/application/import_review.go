@@ -0,0 +1,425 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/billybbuffum/budget/internal/infrastructure/csv"
+)
+
+// ImportReview is an import and the transactions read from its file
+type ImportReview struct {
+	Import *domain.ImportFile      `json:"import"`
+	Rows   []*domain.ImportFileRow `json:"rows"`
+}
+
+// Stage reads an OFX, QFX or QIF file into an import waiting for review
+// Nothing reaches the ledger until the import is committed. Rows get the payees and
+// categories the import would give them, and duplicates and unreadable rows are marked
+// so they can be looked over but not committed. Staging a file that is already staged
+// or committed returns that import instead.
+func (s *ImportService) Stage(ctx context.Context, accountID string, reader io.Reader) (*ImportReview, error) {
+	return s.StageWithOptions(ctx, accountID, reader, ImportOptions{})
+}
+
+// StageWithOptions reads an OFX, QFX or QIF file into an import waiting for review like
+// Stage, with choices other than the defaults
+func (s *ImportService) StageWithOptions(ctx context.Context, accountID string, reader io.Reader, opts ImportOptions) (*ImportReview, error) {
+	parsed, err := s.parseDetected(ctx, accountID, reader, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.stageParsed(ctx, parsed)
+}
+
+// StageCSV reads a CSV file into an import waiting for review, using the given column mapping
+func (s *ImportService) StageCSV(ctx context.Context, accountID string, reader io.Reader, mapping csv.Mapping) (*ImportReview, error) {
+	parsed, err := s.parseCSV(ctx, accountID, reader, mapping)
+	if err != nil {
+		return nil, err
+	}
+	return s.stageParsed(ctx, parsed)
+}
+
+func (s *ImportService) stageParsed(ctx context.Context, parsed *parsedFile) (*ImportReview, error) {
+	if result, err := s.alreadyImported(ctx, parsed.account, parsed.fingerprint); err != nil {
+		return nil, err
+	} else if result != nil {
+		return s.GetImport(ctx, result.ImportID)
+	}
+	staged, err := s.importFileRepo.List(ctx, domain.ImportStatusStaged)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range staged {
+		if file.AccountID == parsed.account.ID && file.Fingerprint == parsed.fingerprint {
+			return s.GetImport(ctx, file.ID)
+		}
+	}
+
+	pending, err := s.prepareImport(ctx, parsed.account.ID, parsed.transactions)
+	if err != nil {
+		return nil, err
+	}
+	s.matchPayees(ctx, parsed.account.ID, pending)
+
+	file := s.newImportFile(parsed)
+	file.Status = domain.ImportStatusStaged
+	file.TotalTransactions = pending.result.TotalTransactions
+	file.SkippedDuplicates = pending.result.SkippedDuplicates
+	for i, record := range pending.records {
+		row := pending.result.Rows[i]
+		record.ImportID = file.ID
+		record.Status = row.Status
+		record.Error = row.Error
+		record.Warning = row.Warning
+		record.TransactionID = row.TransactionID
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.importFileRepo.Create(ctx, file); err != nil {
+			return err
+		}
+		return s.importFileRepo.CreateRows(ctx, pending.records)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ImportReview{Import: file, Rows: pending.records}, nil
+}
+
+// GetImport retrieves an import and its rows
+func (s *ImportService) GetImport(ctx context.Context, id string) (*ImportReview, error) {
+	file, err := s.importFileRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.importFileRepo.ListRows(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rows == nil {
+		rows = []*domain.ImportFileRow{}
+	}
+	return &ImportReview{Import: file, Rows: rows}, nil
+}
+
+// ListImports retrieves imports newest first, optionally only those with a status
+func (s *ImportService) ListImports(ctx context.Context, status string) ([]*domain.ImportFile, error) {
+	switch status {
+	case "", domain.ImportStatusStaged, domain.ImportStatusCommitted, domain.ImportStatusUndone:
+	default:
+		return nil, fmt.Errorf("status must be staged, committed or undone")
+	}
+	files, err := s.importFileRepo.List(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+	if files == nil {
+		files = []*domain.ImportFile{}
+	}
+	return files, nil
+}
+
+// UpdateImportRow changes the category and payee a staged row will be saved with, or
+// leaves it out of the import
+// An empty category or payee ID clears it.
+func (s *ImportService) UpdateImportRow(ctx context.Context, importID, rowID string, categoryID, payeeID *string, excluded bool) (*domain.ImportFileRow, error) {
+	file, err := s.importFileRepo.GetByID(ctx, importID)
+	if err != nil {
+		return nil, err
+	}
+	if file.Status != domain.ImportStatusStaged {
+		return nil, domain.ErrImportNotStaged
+	}
+	rows, err := s.importFileRepo.ListRows(ctx, importID)
+	if err != nil {
+		return nil, err
+	}
+	var row *domain.ImportFileRow
+	for _, candidate := range rows {
+		if candidate.ID == rowID {
+			row = candidate
+		}
+	}
+	if row == nil {
+		return nil, domain.ErrImportRowNotFound
+	}
+	if row.Status != ImportRowPending {
+		return nil, fmt.Errorf("only rows waiting to be imported can be changed")
+	}
+
+	row.CategoryID = emptyToNil(categoryID)
+	row.PayeeID = emptyToNil(payeeID)
+	row.Excluded = excluded
+	if err := s.payees.validateAssignment(ctx, row.CategoryID, row.PayeeID); err != nil {
+		return nil, err
+	}
+	if err := s.importFileRepo.UpdateRow(ctx, row); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+// CommitImport saves a staged import's rows to the ledger
+// Excluded rows are left out, and rows that reached the account since staging (from
+// another import, say) are skipped as duplicates. Everything else is saved as a direct
+// import would save it, with the categories and payees from the review.
+func (s *ImportService) CommitImport(ctx context.Context, id string) (*ImportResult, error) {
+	file, err := s.importFileRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if file.Status != domain.ImportStatusStaged {
+		return nil, domain.ErrImportNotStaged
+	}
+	records, err := s.importFileRepo.ListRows(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{
+		TotalTransactions:      file.TotalTransactions,
+		Errors:                 []string{},
+		ImportedTransactionIDs: []string{},
+		Rows:                   make([]ImportRow, 0, len(records)),
+	}
+	pending := &pendingImport{file: file, result: result, records: records, matched: true}
+	for _, record := range records {
+		row := ImportRow{
+			FitID:         record.FitID,
+			Date:          record.Date,
+			Amount:        record.Amount,
+			Description:   record.Description,
+			Status:        record.Status,
+			TransactionID: record.TransactionID,
+			Error:         record.Error,
+			Warning:       record.Warning,
+		}
+		if row.Status == ImportRowPending && record.Excluded {
+			row.Status = ImportRowExcluded
+		} else if row.Status == ImportRowPending {
+			existing, err := s.transactionRepo.FindByFitID(ctx, file.AccountID, record.FitID)
+			switch {
+			case err != nil:
+				return nil, fmt.Errorf("error checking duplicate for transaction: %w", err)
+			case existing != nil:
+				row.Status = ImportRowDuplicate
+				row.TransactionID = existing.ID
+				if existing.ImportID != nil {
+					row.SourceImportID = *existing.ImportID
+				}
+			default:
+				imported := ImportedTransaction{
+					Date:        record.Date,
+					Amount:      record.Amount,
+					Description: record.Description,
+					FitID:       record.FitID,
+				}
+				if record.OriginalCurrency != nil && record.OriginalAmount != nil {
+					imported.OriginalCurrency, imported.OriginalAmount = *record.OriginalCurrency, *record.OriginalAmount
+				}
+				pending.transactions = append(pending.transactions, imported)
+				pending.rows = append(pending.rows, len(result.Rows))
+			}
+		}
+
+		switch row.Status {
+		case ImportRowError:
+			result.Errors = append(result.Errors, row.Error)
+		case ImportRowDuplicate:
+			result.SkippedDuplicates++
+		case ImportRowPending:
+			if row.Warning != "" {
+				result.Warnings = append(result.Warnings, row.Warning)
+			}
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	return s.saveImport(ctx, pending)
+}
+
+// UndoImport takes a committed import back out of the ledger
+// The transactions it saved are deleted, along with transfer placeholders it added to
+// other accounts that haven't been imported since; transactions on the other side of a
+// transfer it linked go back to being plain, uncategorized transactions. Transfers it
+// claimed stay, but no longer count as imported. The account balance moves back by what
+// the import moved it, allowing for saved transactions changed or deleted since.
+func (s *ImportService) UndoImport(ctx context.Context, id string) (*domain.ImportFile, error) {
+	var file *domain.ImportFile
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		file, err = s.importFileRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if file.Status != domain.ImportStatusCommitted {
+			return fmt.Errorf("only committed imports can be undone")
+		}
+		if err := s.undoImport(ctx, file); err != nil {
+			return err
+		}
+		file.Status = domain.ImportStatusUndone
+		return s.importFileRepo.Update(ctx, file)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// undoImport takes a committed import's transactions and balance change back out of the ledger
+func (s *ImportService) undoImport(ctx context.Context, file *domain.ImportFile) error {
+	rows, err := s.importFileRepo.ListRows(ctx, file.ID)
+	if err != nil {
+		return err
+	}
+	account, err := s.accountRepo.GetByID(ctx, file.AccountID)
+	if err != nil {
+		return fmt.Errorf("account not found: %w", err)
+	}
+	if len(rows) == 0 && file.ImportedTransactions > 0 {
+		return s.undoUnrecordedImport(ctx, file, account)
+	}
+
+	change := file.BalanceChange
+	listed := make(map[string]bool)
+	for _, row := range rows {
+		if row.Status != ImportRowNew && row.Status != ImportRowTransfer && row.Status != ImportRowMerged {
+			continue
+		}
+		listed[row.TransactionID] = true
+		transaction, err := s.transactionRepo.GetByID(ctx, row.TransactionID)
+		if err != nil || transaction.ImportID == nil || *transaction.ImportID != file.ID || transaction.AccountID != file.AccountID {
+			// Deleted or moved since, which already took its amount out of the balance
+			if row.Status == ImportRowNew {
+				change -= row.Amount
+			}
+			continue
+		}
+
+		// Matched transfers and merged manual entries were there before the import
+		if row.Status == ImportRowTransfer || row.Status == ImportRowMerged {
+			transaction.FitID = nil
+			transaction.ImportID = nil
+			transaction.UpdatedAt = time.Now()
+			if err := s.transactionRepo.Update(ctx, transaction); err != nil {
+				return fmt.Errorf("failed to unmatch transaction: %w", err)
+			}
+			continue
+		}
+
+		// Edits since moved the balance by the difference
+		change += transaction.Amount - row.Amount
+		if row.TransferTransactionID != "" {
+			if err := s.unpairTransfer(ctx, row); err != nil {
+				return err
+			}
+		}
+		if err := s.transactionRepo.Delete(ctx, transaction.ID); err != nil {
+			return fmt.Errorf("failed to delete imported transaction: %w", err)
+		}
+	}
+
+	// Fees split off imported transfers aren't rows of their own; their amounts came out of
+	// the rows they were split from
+	transactions, err := s.transactionRepo.ListByImport(ctx, file.ID)
+	if err != nil {
+		return err
+	}
+	for _, transaction := range transactions {
+		if transaction.AccountID != file.AccountID || listed[transaction.ID] {
+			continue
+		}
+		if err := s.transactionRepo.Delete(ctx, transaction.ID); err != nil {
+			return fmt.Errorf("failed to delete imported transaction: %w", err)
+		}
+		change += transaction.Amount
+	}
+
+	if change != 0 {
+		return s.setImportedBalance(ctx, account, account.Balance-change)
+	}
+	return nil
+}
+
+// undoUnrecordedImport takes back an import made before imports kept their rows
+// Only the transactions tagged with the import are known, so each one still in the account
+// is deleted and taken out of the balance, as deleting it by hand would. Transfer
+// placeholders it added to other accounts are left for the user.
+func (s *ImportService) undoUnrecordedImport(ctx context.Context, file *domain.ImportFile, account *domain.Account) error {
+	transactions, err := s.transactionRepo.ListByImport(ctx, file.ID)
+	if err != nil {
+		return err
+	}
+	var change int64
+	for _, transaction := range transactions {
+		if transaction.AccountID != file.AccountID {
+			continue
+		}
+		if err := s.transactionRepo.Delete(ctx, transaction.ID); err != nil {
+			return fmt.Errorf("failed to delete imported transaction: %w", err)
+		}
+		change += transaction.Amount
+	}
+	if change != 0 {
+		return s.setImportedBalance(ctx, account, account.Balance-change)
+	}
+	return nil
+}
+
+// unpairTransfer undoes the other side of a transfer an import linked
+// A placeholder the import created is deleted, unless an import of its own account has
+// claimed it since; anything else goes back to being a plain transaction.
+func (s *ImportService) unpairTransfer(ctx context.Context, row *domain.ImportFileRow) error {
+	other, err := s.transactionRepo.GetByID(ctx, row.TransferTransactionID)
+	if err != nil || other.AccountID != row.TransferAccountID || other.Type != domain.TransactionTypeTransfer {
+		return nil // Changed since; leave it to the user
+	}
+
+	if row.PlaceholderCreated && other.FitID == nil {
+		target, err := s.accountRepo.GetByID(ctx, other.AccountID)
+		if err != nil {
+			return fmt.Errorf("account not found: %w", err)
+		}
+		if err := s.transactionRepo.Delete(ctx, other.ID); err != nil {
+			return fmt.Errorf("failed to delete transfer placeholder: %w", err)
+		}
+		// Money moving between accounts leaves Ready to Assign alone
+		target.Balance -= other.Amount
+		target.UpdatedAt = time.Now()
+		if err := s.accountRepo.Update(ctx, target); err != nil {
+			return fmt.Errorf("failed to update destination account balance: %w", err)
+		}
+		return nil
+	}
+
+	other.Type = domain.TransactionTypeNormal
+	other.TransferToAccountID = nil
+	other.UpdatedAt = time.Now()
+	if err := s.transactionRepo.Update(ctx, other); err != nil {
+		return fmt.Errorf("failed to unlink transfer: %w", err)
+	}
+	return nil
+}
+
+// DeleteImport removes an import
+// A staged import is discarded, and a committed one is taken back out of the ledger first,
+// as UndoImport does, so an import into the wrong account can be reversed in one call.
+func (s *ImportService) DeleteImport(ctx context.Context, id string) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		file, err := s.importFileRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if file.Status == domain.ImportStatusCommitted {
+			if err := s.undoImport(ctx, file); err != nil {
+				return err
+			}
+		}
+		return s.importFileRepo.Delete(ctx, id)
+	})
+}
@@ -1,13 +1,20 @@
 package application
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"time"
 
 	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/billybbuffum/budget/internal/infrastructure/csv"
 	"github.com/billybbuffum/budget/internal/infrastructure/ofx"
+	"github.com/billybbuffum/budget/internal/infrastructure/qif"
 	"github.com/google/uuid"
 )
 
@@ -16,7 +23,16 @@ type ImportService struct {
 	transactionRepo domain.TransactionRepository
 	accountRepo     domain.AccountRepository
 	budgetStateRepo domain.BudgetStateRepository
+	importFileRepo  domain.ImportFileRepository
+	settingRepo     domain.SettingRepository
 	ofxParser       *ofx.Parser
+	csvParser       *csv.Parser
+	qifParser       *qif.Parser
+	plugins         *PluginService
+	payees          *PayeeService
+	transfers       *TransferHintService
+	uow             domain.UnitOfWork
+	dates           DateBounds
 }
 
 // NewImportService creates a new import service
@@ -24,13 +40,31 @@ func NewImportService(
 	transactionRepo domain.TransactionRepository,
 	accountRepo domain.AccountRepository,
 	budgetStateRepo domain.BudgetStateRepository,
+	importFileRepo domain.ImportFileRepository,
+	settingRepo domain.SettingRepository,
 	ofxParser *ofx.Parser,
+	csvParser *csv.Parser,
+	qifParser *qif.Parser,
+	plugins *PluginService,
+	payees *PayeeService,
+	transfers *TransferHintService,
+	uow domain.UnitOfWork,
+	dates DateBounds,
 ) *ImportService {
 	return &ImportService{
 		transactionRepo: transactionRepo,
 		accountRepo:     accountRepo,
 		budgetStateRepo: budgetStateRepo,
+		importFileRepo:  importFileRepo,
+		settingRepo:     settingRepo,
 		ofxParser:       ofxParser,
+		csvParser:       csvParser,
+		qifParser:       qifParser,
+		plugins:         plugins,
+		payees:          payees,
+		transfers:       transfers,
+		uow:             uow,
+		dates:           dates,
 	}
 }
 
@@ -40,121 +74,699 @@ type ImportResult struct {
 	ImportedTransactions  int      `json:"imported_transactions"`
 	SkippedDuplicates     int      `json:"skipped_duplicates"`
 	Errors                []string `json:"errors,omitempty"`
+	Warnings              []string `json:"warnings,omitempty"` // Imported, but worth a look, e.g. dated in the future
 	NewAccountBalance     int64    `json:"new_account_balance"`
 	ImportedTransactionIDs []string `json:"imported_transaction_ids"`
+	ImportID               string      `json:"import_id"`                  // The ImportFile recording this file
+	AlreadyImported        bool        `json:"already_imported,omitempty"` // The same file was imported before; nothing changed
+	Rows                   []ImportRow `json:"rows"`                       // What became of each transaction in the file
+}
+
+// Statuses of an ImportRow
+const (
+	ImportRowNew       = "new"       // Saved by this import
+	ImportRowDuplicate = "duplicate" // Already in the account, from an earlier import or entered by hand
+	ImportRowTransfer  = "transfer"  // Matched to a transfer already in the account, e.g. a placeholder from the other side
+	ImportRowMerged    = "merged"    // Merged into a transaction entered by hand, which keeps its category and description
+	ImportRowError     = "error"     // Not saved; see Error
+	ImportRowPending   = "pending"   // Staged, waiting for the import to be committed
+	ImportRowExcluded  = "excluded"  // Left out of a staged import by the user
+)
+
+// ImportRow is what became of one transaction in an imported file
+type ImportRow struct {
+	FitID          string    `json:"fitid"`
+	Date           time.Time `json:"date"`
+	Amount         int64     `json:"amount"`
+	Description    string    `json:"description"`
+	Status         string    `json:"status"`
+	TransactionID  string    `json:"transaction_id,omitempty"`   // The saved transaction, or the one it duplicates
+	SourceImportID string    `json:"source_import_id,omitempty"` // For duplicates, the import that brought the transaction in
+	// For transfers, the other side and whether this import created it as a placeholder
+	TransferAccountID     string `json:"transfer_account_id,omitempty"`
+	TransferTransactionID string `json:"transfer_transaction_id,omitempty"`
+	PlaceholderCreated    bool   `json:"placeholder_created,omitempty"`
+	Error                 string `json:"error,omitempty"`
+	Warning               string `json:"warning,omitempty"` // Set on saved rows that look wrong, e.g. dated in the future
+}
+
+// Formats Import can detect
+const (
+	ImportFormatOFX = "ofx" // Also QFX, which is OFX with Quicken's headers
+	ImportFormatQIF = "qif"
+	ImportFormatCSV = "csv" // Only through ImportFromCSV, which needs a column mapping
+)
+
+// ImportFormatAggregator marks imports of transactions a bank aggregator reported, rather
+// than of a file
+const ImportFormatAggregator = "aggregator"
+
+// sniffLength is how much of a file DetectImportFormat needs to see
+const sniffLength = 512
+
+// DetectImportFormat tells OFX and QIF files apart from their first bytes
+// OFX has an OFXHEADER line (or processing instruction, in the XML version) or at least
+// the <OFX> tag near the top; QIF opens with a !Type, !Account or !Option header.
+func DetectImportFormat(head []byte) (string, error) {
+	if qif.IsQIF(head) {
+		return ImportFormatQIF, nil
+	}
+	upper := bytes.ToUpper(head)
+	if bytes.Contains(upper, []byte("OFXHEADER")) || bytes.Contains(upper, []byte("<OFX>")) {
+		return ImportFormatOFX, nil
+	}
+	return "", fmt.Errorf("unrecognized file format, expected OFX, QFX or QIF")
+}
+
+// ImportAllHistory as ImportOptions.LookbackDays imports a file's transactions of any age
+const ImportAllHistory = -1
+
+// ImportOptions are the choices made for one import of a file
+type ImportOptions struct {
+	// LookbackDays is how many days back an OFX file's transactions are imported from:
+	// 0 for ofx.DefaultLookbackDays, or ImportAllHistory, e.g. to backfill a new budget.
+	// QIF and CSV files are always imported whole.
+	LookbackDays int
+}
+
+// Import imports transactions from an OFX, QFX or QIF file, detecting which it is
+func (s *ImportService) Import(ctx context.Context, accountID string, reader io.Reader) (*ImportResult, error) {
+	return s.ImportWithOptions(ctx, accountID, reader, ImportOptions{})
+}
+
+// ImportWithOptions imports transactions from an OFX, QFX or QIF file like Import, with
+// choices other than the defaults
+func (s *ImportService) ImportWithOptions(ctx context.Context, accountID string, reader io.Reader, opts ImportOptions) (*ImportResult, error) {
+	parsed, err := s.parseDetected(ctx, accountID, reader, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.importParsed(ctx, parsed)
 }
 
 // ImportFromOFX imports transactions from an OFX file
 func (s *ImportService) ImportFromOFX(ctx context.Context, accountID string, reader io.Reader) (*ImportResult, error) {
+	parsed, err := s.parseOFX(ctx, accountID, reader, ImportOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return s.importParsed(ctx, parsed)
+}
+
+// ImportFromCSV imports transactions from a CSV file using the given column mapping
+// Rows without a FITID get one derived from their contents, so re-importing an
+// overlapping export skips the rows already imported. The account balance is taken from
+// the balance column when one is mapped; otherwise it moves by the imported amounts.
+func (s *ImportService) ImportFromCSV(ctx context.Context, accountID string, reader io.Reader, mapping csv.Mapping) (*ImportResult, error) {
+	parsed, err := s.parseCSV(ctx, accountID, reader, mapping)
+	if err != nil {
+		return nil, err
+	}
+	return s.importParsed(ctx, parsed)
+}
+
+// ImportFromQIF imports transactions from a QIF file
+// QIF has no transaction IDs or balances: IDs are derived from each record so re-imports
+// skip what was imported before, and the account balance moves by the imported amounts.
+func (s *ImportService) ImportFromQIF(ctx context.Context, accountID string, reader io.Reader) (*ImportResult, error) {
+	parsed, err := s.parseQIF(ctx, accountID, reader)
+	if err != nil {
+		return nil, err
+	}
+	return s.importParsed(ctx, parsed)
+}
+
+// parsedFile is an uploaded file read into transactions, not yet checked or saved
+type parsedFile struct {
+	account       *domain.Account
+	format        string
+	fingerprint   string
+	transactions  []ImportedTransaction
+	ledgerBalance *int64 // Statement balance from the file, if it has one
+}
+
+// parseDetected reads an OFX, QFX or QIF file, detecting which it is
+func (s *ImportService) parseDetected(ctx context.Context, accountID string, reader io.Reader, opts ImportOptions) (*parsedFile, error) {
+	buffered := bufio.NewReaderSize(reader, sniffLength)
+	head, err := buffered.Peek(sniffLength)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	format, err := DetectImportFormat(head)
+	if err != nil {
+		return nil, err
+	}
+	if format == ImportFormatQIF {
+		return s.parseQIF(ctx, accountID, buffered)
+	}
+	return s.parseOFX(ctx, accountID, buffered, opts)
+}
+
+func (s *ImportService) parseOFX(ctx context.Context, accountID string, reader io.Reader, opts ImportOptions) (*parsedFile, error) {
+	switch {
+	case opts.LookbackDays == 0:
+		return s.readOFX(ctx, accountID, reader, s.ofxParser.Parse)
+	case opts.LookbackDays == ImportAllHistory:
+		return s.readOFX(ctx, accountID, reader, s.ofxParser.ParseAll, "lookback=all")
+	case opts.LookbackDays < 0:
+		return nil, fmt.Errorf("lookback must be a positive number of days")
+	}
+	cutoff := time.Now().AddDate(0, 0, -opts.LookbackDays)
+	parse := func(reader io.Reader) (*ofx.ImportResult, error) {
+		return s.ofxParser.ParseSince(reader, cutoff)
+	}
+	return s.readOFX(ctx, accountID, reader, parse, fmt.Sprintf("lookback=%d", opts.LookbackDays))
+}
+
+// readOFX reads an OFX file for the account with the given parser function
+// Settings that change what is read from the file tell its imports apart.
+func (s *ImportService) readOFX(ctx context.Context, accountID string, reader io.Reader, parse func(io.Reader) (*ofx.ImportResult, error), settings ...string) (*parsedFile, error) {
 	// Validate account exists
 	account, err := s.accountRepo.GetByID(ctx, accountID)
 	if err != nil {
 		return nil, fmt.Errorf("account not found: %w", err)
 	}
 
-	// Parse OFX file (extracts ledger balance + last 90 days of transactions)
-	parseResult, err := s.ofxParser.Parse(reader)
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	// Parse OFX file (extracts ledger balance + transactions since the parser's cutoff)
+	parseResult, err := parse(bytes.NewReader(data))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse OFX file: %w", err)
 	}
 
+	// Normalize dates to midnight UTC to ensure consistent comparison
+	parsed := &parsedFile{account: account, format: ImportFormatOFX, fingerprint: importFingerprint(data, settings...)}
+	for _, ofxTxn := range parseResult.Transactions {
+		parsed.transactions = append(parsed.transactions, ImportedTransaction{
+			Date:        time.Date(ofxTxn.Date.Year(), ofxTxn.Date.Month(), ofxTxn.Date.Day(), 0, 0, 0, 0, time.UTC),
+			Amount:      ofxTxn.Amount,
+			Description: ofxTxn.Description,
+			FitID:       ofxTxn.FitID,
+
+			OriginalCurrency: ofxTxn.OriginalCurrency,
+			OriginalAmount:   ofxTxn.OriginalAmount,
+		})
+	}
+	if parseResult.LedgerBalance != 0 {
+		parsed.ledgerBalance = &parseResult.LedgerBalance
+	}
+	return parsed, nil
+}
+
+func (s *ImportService) parseCSV(ctx context.Context, accountID string, reader io.Reader, mapping csv.Mapping) (*parsedFile, error) {
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("account not found: %w", err)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	parseResult, err := s.csvParser.Parse(bytes.NewReader(data), mapping)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV file: %w", err)
+	}
+
+	// The same file read with another mapping gives other transactions
+	parsed := &parsedFile{account: account, format: ImportFormatCSV, fingerprint: importFingerprint(data, fmt.Sprintf("%+v", mapping))}
+	for _, csvTxn := range parseResult.Transactions {
+		parsed.transactions = append(parsed.transactions, ImportedTransaction{
+			Date:        csvTxn.Date,
+			Amount:      csvTxn.Amount,
+			Description: csvTxn.Description,
+			FitID:       csvTxn.FitID,
+		})
+	}
+	parsed.ledgerBalance = parseResult.LedgerBalance
+	return parsed, nil
+}
+
+func (s *ImportService) parseQIF(ctx context.Context, accountID string, reader io.Reader) (*parsedFile, error) {
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("account not found: %w", err)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	parseResult, err := s.qifParser.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse QIF file: %w", err)
+	}
+
+	parsed := &parsedFile{account: account, format: ImportFormatQIF, fingerprint: importFingerprint(data)}
+	for _, qifTxn := range parseResult.Transactions {
+		parsed.transactions = append(parsed.transactions, ImportedTransaction{
+			Date:        qifTxn.Date,
+			Amount:      qifTxn.Amount,
+			Description: qifTxn.Description,
+			FitID:       qifTxn.FitID,
+		})
+	}
+	return parsed, nil
+}
+
+// ImportAggregated imports an account's transactions as a bank aggregator reported them
+// The aggregator's transaction IDs serve as FITIDs, so transactions already imported are
+// skipped, and the balance is set to the one the aggregator reported.
+func (s *ImportService) ImportAggregated(ctx context.Context, accountID string, remote domain.AggregatedAccount) (*ImportResult, error) {
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("account not found: %w", err)
+	}
+
+	// The same report fetched again changes nothing
+	data, err := json.Marshal(remote)
+	if err != nil {
+		return nil, err
+	}
+	parsed := &parsedFile{account: account, format: ImportFormatAggregator, fingerprint: importFingerprint(data), ledgerBalance: &remote.Balance}
+	for _, txn := range remote.Transactions {
+		parsed.transactions = append(parsed.transactions, ImportedTransaction{
+			Date:        time.Date(txn.Date.Year(), txn.Date.Month(), txn.Date.Day(), 0, 0, 0, 0, time.UTC),
+			Amount:      txn.Amount,
+			Description: txn.Description,
+			FitID:       txn.ID,
+		})
+	}
+	return s.importParsed(ctx, parsed)
+}
+
+// importParsed saves a parsed file's new transactions straight to the ledger
+func (s *ImportService) importParsed(ctx context.Context, parsed *parsedFile) (*ImportResult, error) {
+	if result, err := s.alreadyImported(ctx, parsed.account, parsed.fingerprint); result != nil || err != nil {
+		return result, err
+	}
+
+	pending, err := s.prepareImport(ctx, parsed.account.ID, parsed.transactions)
+	if err != nil {
+		return nil, err
+	}
+	pending.file = s.newImportFile(parsed)
+	return s.saveImport(ctx, pending)
+}
+
+// pendingImport is an import ready to be saved: plugins have run and duplicates are gone
+type pendingImport struct {
+	file         *domain.ImportFile
+	result       *ImportResult
+	records      []*domain.ImportFileRow // Saved with the file, by row in result.Rows
+	transactions []ImportedTransaction
+	rows         []int // Index in result.Rows, by transaction
+	matched      bool  // Payee rules have run; their payees and categories are in records
+}
+
+// record returns the saved row for the i'th transaction to import
+func (p *pendingImport) record(i int) *domain.ImportFileRow {
+	return p.records[p.rows[i]]
+}
+
+// prepareImport runs plugins over parsed transactions and drops the ones imported before
+// Duplicates are detected by FitID, within the file as well as against the account.
+// Nothing is saved yet, so no database transaction is held open while plugins call out
+// to other services.
+func (s *ImportService) prepareImport(ctx context.Context, accountID string, imported []ImportedTransaction) (*pendingImport, error) {
+	// Let plugins clean up, drop or add transactions before anything is saved
+	imported, err := s.plugins.TransformImport(ctx, accountID, imported)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transform imported transactions: %w", err)
+	}
+
 	result := &ImportResult{
-		TotalTransactions:      len(parseResult.Transactions),
+		TotalTransactions:      len(imported),
 		ImportedTransactions:   0,
 		SkippedDuplicates:      0,
 		Errors:                 []string{},
 		ImportedTransactionIDs: []string{},
+		Rows:                   make([]ImportRow, 0, len(imported)),
 	}
+	pending := &pendingImport{result: result}
 
-	// Calculate balance delta using ledger balance from OFX file
-	// This is the authoritative balance from the bank
-	balanceDelta := int64(0)
-	if parseResult.LedgerBalance != 0 {
-		balanceDelta = parseResult.LedgerBalance - account.Balance
-	}
-
-	// Process each transaction (for categorization purposes only)
-	// These transactions do NOT affect account balance since we're using ledger balance
-	for _, ofxTxn := range parseResult.Transactions {
-		// Normalize date to midnight UTC to ensure consistent comparison
-		normalizedDate := time.Date(
-			ofxTxn.Date.Year(),
-			ofxTxn.Date.Month(),
-			ofxTxn.Date.Day(),
-			0, 0, 0, 0,
-			time.UTC,
-		)
-
-		// Check for duplicate using FitID (Financial Institution Transaction ID)
-		// FitID is a unique identifier from the bank, more reliable than date+amount+description
-		existing, err := s.transactionRepo.FindByFitID(ctx, accountID, ofxTxn.FitID)
-		if err != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("error checking duplicate for transaction: %v", err))
-			continue
+	// Keep only transactions that weren't imported before
+	seen := make(map[string]bool)
+	for _, txn := range imported {
+		row := ImportRow{FitID: txn.FitID, Date: txn.Date, Amount: txn.Amount, Description: txn.Description}
+		dateErr := s.dates.Check(txn.Date)
+		switch {
+		case txn.FitID == "":
+			row.Status = ImportRowError
+			row.Error = fmt.Sprintf("skipped transaction %q: missing FitID", txn.Description)
+		case seen[txn.FitID]:
+			row.Status = ImportRowDuplicate
+		case dateErr != nil:
+			row.Status = ImportRowError
+			row.Error = fmt.Sprintf("skipped transaction %q: %v", txn.Description, dateErr)
+		default:
+			// Check for duplicate using FitID (Financial Institution Transaction ID)
+			// FitID is a unique identifier from the bank, more reliable than date+amount+description
+			existing, err := s.transactionRepo.FindByFitID(ctx, accountID, txn.FitID)
+			if err != nil {
+				row.Status = ImportRowError
+				row.Error = fmt.Sprintf("error checking duplicate for transaction: %v", err)
+			} else if existing != nil {
+				row.Status = ImportRowDuplicate
+				row.TransactionID = existing.ID
+				if existing.ImportID != nil {
+					row.SourceImportID = *existing.ImportID
+				}
+			}
 		}
+		seen[txn.FitID] = true
 
-		if existing != nil {
+		switch row.Status {
+		case ImportRowError:
+			result.Errors = append(result.Errors, row.Error)
+		case ImportRowDuplicate:
 			result.SkippedDuplicates++
-			continue
+		default:
+			// Bank statements don't have future transactions; a date like that is likely misparsed
+			if txn.Date.After(time.Now().Add(futureImportSlack)) {
+				row.Warning = fmt.Sprintf("transaction %q is dated in the future (%s)", txn.Description, txn.Date.Format("2006-01-02"))
+				result.Warnings = append(result.Warnings, row.Warning)
+			}
+			row.Status = ImportRowPending
+			pending.transactions = append(pending.transactions, txn)
+			pending.rows = append(pending.rows, len(result.Rows))
 		}
-
-		// Create new transaction without category (uncategorized)
-		transaction := &domain.Transaction{
+		record := &domain.ImportFileRow{
 			ID:          uuid.New().String(),
-			Type:        domain.TransactionTypeNormal, // All imported transactions are normal type
-			AccountID:   accountID,
-			CategoryID:  nil, // Imported transactions start uncategorized
-			Amount:      ofxTxn.Amount,
-			Description: ofxTxn.Description,
-			Date:        normalizedDate,
-			FitID:       &ofxTxn.FitID, // Store FitID for duplicate detection
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
+			Position:    len(result.Rows),
+			FitID:       txn.FitID,
+			Date:        txn.Date,
+			Amount:      txn.Amount,
+			Description: txn.Description,
 		}
+		record.OriginalCurrency, record.OriginalAmount = txn.original()
+		pending.records = append(pending.records, record)
+		result.Rows = append(result.Rows, row)
+	}
+
+	for i, categoryID := range s.plugins.Categorize(ctx, pending.transactions) {
+		pending.record(i).CategoryID = categoryID
+	}
+	return pending, nil
+}
 
-		if err := s.transactionRepo.Create(ctx, transaction); err != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("failed to create transaction: %v", err))
-			continue
+// matchPayees gives each transaction imported into the account a payee, and a category
+// and memo where a payee rule gives them
+// Payee rules take precedence over categories suggested by plugins.
+func (s *ImportService) matchPayees(ctx context.Context, accountID string, pending *pendingImport) {
+	for i, match := range s.payees.MatchImported(ctx, accountID, pending.transactions) {
+		record := pending.record(i)
+		record.PayeeID = match.PayeeID
+		record.Note = match.Note
+		if match.CategoryID != nil {
+			record.CategoryID = match.CategoryID
 		}
+	}
+	pending.matched = true
+}
 
-		result.ImportedTransactions++
-		result.ImportedTransactionIDs = append(result.ImportedTransactionIDs, transaction.ID)
+// newImportFile starts the record of a file being imported
+func (s *ImportService) newImportFile(parsed *parsedFile) *domain.ImportFile {
+	return &domain.ImportFile{
+		ID:            uuid.New().String(),
+		AccountID:     parsed.account.ID,
+		Fingerprint:   parsed.fingerprint,
+		Format:        parsed.format,
+		Status:        domain.ImportStatusCommitted,
+		LedgerBalance: parsed.ledgerBalance,
+		CreatedAt:     time.Now(),
 	}
+}
+
+// importFingerprint hashes an imported file, along with anything else that decides what
+// gets imported from it
+func importFingerprint(data []byte, settings ...string) string {
+	hash := sha256.New()
+	for _, setting := range settings {
+		hash.Write([]byte(setting))
+		hash.Write([]byte{0})
+	}
+	hash.Write(data)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// importedBalance works out an account's balance after an import
+// A statement balance in the file wins. Otherwise the balance moves by the sum of the
+// saved amounts, except for OFX files, which leave it alone.
+func importedBalance(file *domain.ImportFile, balance, importedTotal int64) int64 {
+	switch {
+	case file.LedgerBalance != nil:
+		return *file.LedgerBalance
+	case file.Format == ImportFormatOFX:
+		return balance
+	default:
+		return balance + importedTotal
+	}
+}
+
+// alreadyImported returns the result of uploading a file the account has imported before,
+// or nil if the file is new to it
+// Re-uploading a file changes nothing; the result lists the transactions it brought in
+// as duplicates. A file whose transactions have all been deleted since counts as new, so
+// it can be imported again.
+func (s *ImportService) alreadyImported(ctx context.Context, account *domain.Account, fingerprint string) (*ImportResult, error) {
+	file, err := s.importFileRepo.FindByFingerprint(ctx, account.ID, fingerprint)
+	if err != nil || file == nil {
+		return nil, err
+	}
+	transactions, err := s.transactionRepo.ListByImport(ctx, file.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(transactions) == 0 && file.ImportedTransactions > 0 {
+		return nil, nil
+	}
+
+	result := &ImportResult{
+		TotalTransactions:      file.TotalTransactions,
+		SkippedDuplicates:      file.TotalTransactions,
+		Errors:                 []string{},
+		NewAccountBalance:      account.Balance,
+		ImportedTransactionIDs: []string{},
+		ImportID:               file.ID,
+		AlreadyImported:        true,
+		Rows:                   make([]ImportRow, 0, len(transactions)),
+	}
+	for _, txn := range transactions {
+		row := ImportRow{
+			Date:           txn.Date,
+			Amount:         txn.Amount,
+			Description:    txn.Description,
+			Status:         ImportRowDuplicate,
+			TransactionID:  txn.ID,
+			SourceImportID: file.ID,
+		}
+		if txn.FitID != nil {
+			row.FitID = *txn.FitID
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	return result, nil
+}
+
+// saveImport saves the new transactions and updates the account balance atomically
+// The balance follows importedBalance; Ready to Assign moves by the change. The file and
+// its rows are recorded with the transactions, or updated if the import was staged.
+func (s *ImportService) saveImport(ctx context.Context, pending *pendingImport) (*ImportResult, error) {
+	result := pending.result
+	file := pending.file
+	staged := file.Status == domain.ImportStatusStaged
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		account, err := s.accountRepo.GetByID(ctx, file.AccountID)
+		if err != nil {
+			return fmt.Errorf("account not found: %w", err)
+		}
+
+		// A tracking account is off budget: what's imported into it are balance updates,
+		// never income, spending or transfers
+		offBudget := account.Type.IsOffBudget()
+
+		// Payee rules categorize what they match; categorization plugins may suggest categories
+		// for the rest, and anything left stays uncategorized
+		if !pending.matched && !offBudget {
+			s.matchPayees(ctx, file.AccountID, pending)
+		}
+		transfers, err := s.transfers.newMatcher(ctx, file.AccountID, file.ID)
+		if err != nil {
+			return err
+		}
+		manual, err := s.newManualMatcher(ctx, file.AccountID, file.ID)
+		if err != nil {
+			return err
+		}
+
+		var total int64
+		for i, txn := range pending.transactions {
+			row := &result.Rows[pending.rows[i]]
+			record := pending.record(i)
+
+			// A transfer recorded before is already in the balance, so it isn't added to total
+			var claimed *domain.Transaction
+			if !offBudget {
+				claimed, err = transfers.claim(ctx, txn)
+				if err != nil {
+					return err
+				}
+			}
+			if claimed != nil {
+				row.Status = ImportRowTransfer
+				row.TransactionID = claimed.ID
+				row.TransferAccountID = *claimed.TransferToAccountID
+				result.ImportedTransactions++
+				result.ImportedTransactionIDs = append(result.ImportedTransactionIDs, claimed.ID)
+				continue
+			}
+
+			// So is a transaction entered by hand, when imports are set to merge into them
+			var merged *domain.Transaction
+			if !offBudget {
+				merged, err = manual.merge(ctx, txn)
+				if err != nil {
+					return err
+				}
+			}
+			if merged != nil {
+				row.Status = ImportRowMerged
+				row.TransactionID = merged.ID
+				result.ImportedTransactions++
+				result.ImportedTransactionIDs = append(result.ImportedTransactionIDs, merged.ID)
+				continue
+			}
+
+			fitID := txn.FitID
+			transaction := &domain.Transaction{
+				ID:          uuid.New().String(),
+				Type:        domain.TransactionTypeNormal, // All imported transactions are normal type
+				AccountID:   file.AccountID,
+				CategoryID:  record.CategoryID,
+				Amount:      txn.Amount,
+				Description: txn.Description,
+				Date:        txn.Date,
+				FitID:       &fitID, // Store FitID for duplicate detection
+				PayeeID:     record.PayeeID,
+				Note:        record.Note,
+				ImportID:    &file.ID,
+				CreatedAt:   time.Now(),
+				UpdatedAt:   time.Now(),
+			}
+			transaction.OriginalCurrency, transaction.OriginalAmount = txn.original()
+			if offBudget {
+				transaction.Type = domain.TransactionTypeAdjustment
+				transaction.CategoryID = nil
+			}
+
+			if err := s.transactionRepo.Create(ctx, transaction); err != nil {
+				row.Status = ImportRowError
+				row.Error = fmt.Sprintf("failed to create transaction: %v", err)
+				result.Errors = append(result.Errors, row.Error)
+				continue
+			}
+			row.Status = ImportRowNew
+			row.TransactionID = transaction.ID
+
+			var other *domain.Transaction
+			var placeholder bool
+			if !offBudget {
+				other, placeholder, err = transfers.pair(ctx, transaction)
+				if err != nil {
+					return err
+				}
+			}
+			if other != nil {
+				row.TransferAccountID = other.AccountID
+				row.TransferTransactionID = other.ID
+				row.PlaceholderCreated = placeholder
+			}
+
+			total += txn.Amount
+			result.ImportedTransactions++
+			result.ImportedTransactionIDs = append(result.ImportedTransactionIDs, transaction.ID)
+		}
 
-	// Update account balance to match OFX ledger balance (if available)
-	// and adjust Ready to Assign by the balance delta
-	if parseResult.LedgerBalance != 0 {
 		oldBalance := account.Balance
-		account.Balance = parseResult.LedgerBalance
-		account.UpdatedAt = time.Now()
+		if balance := importedBalance(file, account.Balance, total); balance != account.Balance {
+			if err := s.setImportedBalance(ctx, account, balance); err != nil {
+				return err
+			}
+		}
+		result.NewAccountBalance = account.Balance
 
-		if err := s.accountRepo.Update(ctx, account); err != nil {
-			// Rollback: delete imported transactions
-			for _, txnID := range result.ImportedTransactionIDs {
-				s.transactionRepo.Delete(ctx, txnID)
+		// Record the file and what became of each row, so uploading it again is recognized
+		// and the import can be undone
+		now := time.Now()
+		file.Status = domain.ImportStatusCommitted
+		file.CommittedAt = &now
+		file.BalanceChange = account.Balance - oldBalance
+		file.TotalTransactions = result.TotalTransactions
+		file.ImportedTransactions = result.ImportedTransactions
+		file.SkippedDuplicates = result.SkippedDuplicates
+		for i, record := range pending.records {
+			row := result.Rows[i]
+			record.ImportID = file.ID
+			record.Status = row.Status
+			record.Error = row.Error
+			record.Warning = row.Warning
+			record.TransactionID = row.TransactionID
+			record.TransferAccountID = row.TransferAccountID
+			record.TransferTransactionID = row.TransferTransactionID
+			record.PlaceholderCreated = row.PlaceholderCreated
+		}
+		result.ImportID = file.ID
+
+		if !staged {
+			if err := s.importFileRepo.Create(ctx, file); err != nil {
+				return err
 			}
-			return nil, fmt.Errorf("failed to update account balance: %w", err)
-		}
-
-		// Adjust Ready to Assign by the balance delta only
-		// This prevents double-counting when users have manually entered balances
-		// Delta = New Balance - Old Balance
-		// Example: OFX says $7,895.39, account had $0 -> add $7,895.39 to Ready to Assign
-		// Example: OFX says $7,895.39, account had $10,000 -> subtract $2,104.61 from Ready to Assign
-		if err := s.budgetStateRepo.AdjustReadyToAssign(ctx, balanceDelta); err != nil {
-			// Rollback: delete imported transactions and reverse account balance
-			for _, txnID := range result.ImportedTransactionIDs {
-				s.transactionRepo.Delete(ctx, txnID)
+			return s.importFileRepo.CreateRows(ctx, pending.records)
+		}
+		if err := s.importFileRepo.Update(ctx, file); err != nil {
+			return err
+		}
+		for _, record := range pending.records {
+			if err := s.importFileRepo.UpdateRow(ctx, record); err != nil {
+				return err
 			}
-			account.Balance = oldBalance
-			s.accountRepo.Update(ctx, account)
-			return nil, fmt.Errorf("failed to adjust ready to assign: %w", err)
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return result, nil
+}
 
-	result.NewAccountBalance = account.Balance
+// setImportedBalance sets the account balance after an import and adjusts Ready to Assign
+// by the change
+func (s *ImportService) setImportedBalance(ctx context.Context, account *domain.Account, newBalance int64) error {
+	balanceDelta := newBalance - account.Balance
+	account.Balance = newBalance
+	account.UpdatedAt = time.Now()
 
-	return result, nil
+	if err := s.accountRepo.Update(ctx, account); err != nil {
+		return fmt.Errorf("failed to update account balance: %w", err)
+	}
+
+	// Adjust Ready to Assign by the balance delta only
+	// This prevents double-counting when users have manually entered balances
+	// Delta = New Balance - Old Balance
+	// Example: OFX says $7,895.39, account had $0 -> add $7,895.39 to Ready to Assign
+	// Example: OFX says $7,895.39, account had $10,000 -> subtract $2,104.61 from Ready to Assign
+	if err := s.budgetStateRepo.AdjustReadyToAssign(ctx, balanceDelta); err != nil {
+		return fmt.Errorf("failed to adjust ready to assign: %w", err)
+	}
+	return nil
 }
 
 // ValidateOFXFile validates that a file is a valid OFX file
@@ -0,0 +1,477 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/billybbuffum/budget/internal/infrastructure/csv"
+	"github.com/billybbuffum/budget/internal/infrastructure/ofx"
+	"github.com/billybbuffum/budget/internal/infrastructure/qif"
+)
+
+type mockImportFileRepository struct {
+	files []*domain.ImportFile
+	rows  []*domain.ImportFileRow
+}
+
+func (m *mockImportFileRepository) Create(ctx context.Context, file *domain.ImportFile) error {
+	m.files = append(m.files, file)
+	return nil
+}
+
+func (m *mockImportFileRepository) GetByID(ctx context.Context, id string) (*domain.ImportFile, error) {
+	for _, file := range m.files {
+		if file.ID == id {
+			return file, nil
+		}
+	}
+	return nil, domain.ErrImportNotFound
+}
+
+func (m *mockImportFileRepository) FindByFingerprint(ctx context.Context, accountID, fingerprint string) (*domain.ImportFile, error) {
+	for i := len(m.files) - 1; i >= 0; i-- {
+		file := m.files[i]
+		if file.AccountID == accountID && file.Fingerprint == fingerprint && file.Status == domain.ImportStatusCommitted {
+			return file, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockImportFileRepository) List(ctx context.Context, status string) ([]*domain.ImportFile, error) {
+	var files []*domain.ImportFile
+	for i := len(m.files) - 1; i >= 0; i-- {
+		if status == "" || m.files[i].Status == status {
+			files = append(files, m.files[i])
+		}
+	}
+	return files, nil
+}
+
+func (m *mockImportFileRepository) Update(ctx context.Context, file *domain.ImportFile) error {
+	if _, err := m.GetByID(ctx, file.ID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *mockImportFileRepository) Delete(ctx context.Context, id string) error {
+	for i, file := range m.files {
+		if file.ID == id {
+			m.files = append(m.files[:i], m.files[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrImportNotFound
+}
+
+func (m *mockImportFileRepository) CreateRows(ctx context.Context, rows []*domain.ImportFileRow) error {
+	m.rows = append(m.rows, rows...)
+	return nil
+}
+
+func (m *mockImportFileRepository) ListRows(ctx context.Context, importID string) ([]*domain.ImportFileRow, error) {
+	var rows []*domain.ImportFileRow
+	for _, row := range m.rows {
+		if row.ImportID == importID {
+			copied := *row
+			rows = append(rows, &copied)
+		}
+	}
+	return rows, nil
+}
+
+func (m *mockImportFileRepository) UpdateRow(ctx context.Context, row *domain.ImportFileRow) error {
+	for i, existing := range m.rows {
+		if existing.ID == row.ID && existing.ImportID == row.ImportID {
+			copied := *row
+			m.rows[i] = &copied
+			return nil
+		}
+	}
+	return domain.ErrImportRowNotFound
+}
+
+func TestImportService_OverlappingFiles(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking}
+	transactionRepo := newMockTransactionRepository()
+	categoryRepo := newMockCategoryRepository()
+	importFiles := &mockImportFileRepository{}
+	service := NewImportService(transactionRepo, accountRepo, newMockBudgetStateRepository(0, 0), importFiles, newMockSettingRepository(),
+		ofx.NewParser(), csv.NewParser(), qif.NewParser(),
+		NewPluginService(categoryRepo, accountRepo, transactionRepo, newMockAllocationRepository(), nil, nil, nil),
+		NewPayeeService(newMockPayeeRepository(), newMockPayeeRuleRepository(), newMockAccountRepository(0), categoryRepo),
+		NewTransferHintService(&mockTransferHintRepository{}, accountRepo, transactionRepo),
+		&mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{})
+
+	march := "!Type:Bank\nD3/1/2025\nT-10.00\nPCOFFEE\n^\nD3/2/2025\nT-20.00\nPGROCERIES\n^\n"
+	first, err := service.Import(ctx, "checking", strings.NewReader(march))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ImportedTransactions != 2 || first.ImportID == "" || first.AlreadyImported {
+		t.Fatalf("expected both transactions imported, got %+v", first)
+	}
+
+	// The same file again is a no-op
+	again, err := service.Import(ctx, "checking", strings.NewReader(march))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !again.AlreadyImported || again.ImportID != first.ImportID || again.ImportedTransactions != 0 || len(again.Rows) != 2 {
+		t.Errorf("expected re-uploading the file to be recognized, got %+v", again)
+	}
+	if len(transactionRepo.transactions) != 2 || len(importFiles.files) != 1 || accountRepo.accounts["checking"].Balance != -3000 {
+		t.Errorf("expected re-uploading to change nothing, have %d transactions, %d files, balance %d",
+			len(transactionRepo.transactions), len(importFiles.files), accountRepo.accounts["checking"].Balance)
+	}
+
+	// A file overlapping the first reports which rows were new
+	overlap := march + "D3/3/2025\nT-5.00\nPPARKING\n^\n"
+	second, err := service.Import(ctx, "checking", strings.NewReader(overlap))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.AlreadyImported || second.ImportedTransactions != 1 || second.SkippedDuplicates != 2 || len(second.Rows) != 3 {
+		t.Fatalf("expected one new transaction and two duplicates, got %+v", second)
+	}
+	for i, want := range []string{ImportRowDuplicate, ImportRowDuplicate, ImportRowNew} {
+		if second.Rows[i].Status != want {
+			t.Errorf("row %d: expected %s, got %s", i, want, second.Rows[i].Status)
+		}
+	}
+	if second.Rows[0].SourceImportID != first.ImportID || second.Rows[0].TransactionID != first.ImportedTransactionIDs[0] {
+		t.Errorf("expected a duplicate to link to the transaction and import it came from, got %+v", second.Rows[0])
+	}
+	saved, _ := transactionRepo.GetByID(ctx, second.Rows[2].TransactionID)
+	if saved == nil || saved.ImportID == nil || *saved.ImportID != second.ImportID {
+		t.Errorf("expected the new transaction to link to its import, got %+v", saved)
+	}
+
+	// Once its transactions are gone, a file can be imported again
+	for _, id := range first.ImportedTransactionIDs {
+		transactionRepo.Delete(ctx, id)
+	}
+	redo, err := service.Import(ctx, "checking", strings.NewReader(march))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if redo.AlreadyImported || redo.ImportedTransactions != 2 {
+		t.Errorf("expected a file whose transactions were deleted to import again, got %+v", redo)
+	}
+}
+
+func TestImportService_LookbackDays(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking}
+	transactionRepo := newMockTransactionRepository()
+	categoryRepo := newMockCategoryRepository()
+	service := NewImportService(transactionRepo, accountRepo, newMockBudgetStateRepository(0, 0), &mockImportFileRepository{}, newMockSettingRepository(),
+		ofx.NewParser(), csv.NewParser(), qif.NewParser(),
+		NewPluginService(categoryRepo, accountRepo, transactionRepo, newMockAllocationRepository(), nil, nil, nil),
+		NewPayeeService(newMockPayeeRepository(), newMockPayeeRuleRepository(), newMockAccountRepository(0), categoryRepo),
+		NewTransferHintService(&mockTransferHintRepository{}, accountRepo, transactionRepo),
+		&mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{})
+
+	daysAgo := func(days int) string { return time.Now().AddDate(0, 0, -days).Format("20060102") }
+	file := backfillOFX(
+		"RECENT "+daysAgo(10)+" -10.00 COFFEE",
+		"LASTYEAR "+daysAgo(200)+" -20.00 GROCERIES",
+		"OLD "+daysAgo(800)+" -30.00 HARDWARE",
+	)
+
+	result, err := service.Import(ctx, "checking", strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ImportedTransactions != 1 {
+		t.Errorf("expected only the last 90 days imported by default, got %+v", result)
+	}
+
+	// Reaching further back imports the older transactions the earlier import left out
+	result, err = service.ImportWithOptions(ctx, "checking", strings.NewReader(file), ImportOptions{LookbackDays: 365})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.AlreadyImported || result.ImportedTransactions != 1 || result.SkippedDuplicates != 1 {
+		t.Errorf("expected the last year imported, skipping what was, got %+v", result)
+	}
+
+	result, err = service.ImportWithOptions(ctx, "checking", strings.NewReader(file), ImportOptions{LookbackDays: ImportAllHistory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ImportedTransactions != 1 || result.SkippedDuplicates != 2 || len(transactionRepo.transactions) != 3 {
+		t.Errorf("expected everything in the file imported, got %+v", result)
+	}
+
+	if _, err := service.ImportWithOptions(ctx, "checking", strings.NewReader(file), ImportOptions{LookbackDays: -5}); err == nil {
+		t.Error("expected a negative lookback to be rejected")
+	}
+}
+
+func TestImportService_DateBounds(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking}
+	transactionRepo := newMockTransactionRepository()
+	categoryRepo := newMockCategoryRepository()
+	service := NewImportService(transactionRepo, accountRepo, newMockBudgetStateRepository(0, 0), &mockImportFileRepository{}, newMockSettingRepository(),
+		ofx.NewParser(), csv.NewParser(), qif.NewParser(),
+		NewPluginService(categoryRepo, accountRepo, transactionRepo, newMockAllocationRepository(), nil, nil, nil),
+		NewPayeeService(newMockPayeeRepository(), newMockPayeeRuleRepository(), newMockAccountRepository(0), categoryRepo),
+		NewTransferHintService(&mockTransferHintRepository{}, accountRepo, transactionRepo),
+		&mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{PastYears: 30, FutureDays: 366})
+
+	nextWeek := time.Now().AddDate(0, 0, 7).Format("1/2/2006")
+	file := "!Type:Bank\nD3/1/2205\nT-10.00\nPTYPO\n^\nD" + nextWeek + "\nT-20.00\nPSCHEDULED\n^\nD3/2/2025\nT-30.00\nPGROCERIES\n^\n"
+	result, err := service.Import(ctx, "checking", strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ImportedTransactions != 2 || len(result.Errors) != 1 || len(result.Warnings) != 1 {
+		t.Fatalf("expected the typo skipped and the future row imported with a warning, got %+v", result)
+	}
+	if row := result.Rows[0]; row.Status != ImportRowError {
+		t.Errorf("expected the year 2205 row to be skipped, got %+v", row)
+	}
+	if row := result.Rows[1]; row.Status != ImportRowNew || row.Warning == "" {
+		t.Errorf("expected the future row to be flagged, got %+v", row)
+	}
+	if row := result.Rows[2]; row.Warning != "" {
+		t.Errorf("expected no warning on a past row, got %+v", row)
+	}
+}
+
+func TestImportService_ReviewCommitUndo(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking}
+	transactionRepo := newMockTransactionRepository()
+	categoryRepo := newMockCategoryRepository()
+	categoryRepo.categories["groceries"] = &domain.Category{ID: "groceries", Name: "Groceries"}
+	importFiles := &mockImportFileRepository{}
+	service := NewImportService(transactionRepo, accountRepo, newMockBudgetStateRepository(0, 0), importFiles, newMockSettingRepository(),
+		ofx.NewParser(), csv.NewParser(), qif.NewParser(),
+		NewPluginService(categoryRepo, accountRepo, transactionRepo, newMockAllocationRepository(), nil, nil, nil),
+		NewPayeeService(newMockPayeeRepository(), newMockPayeeRuleRepository(), newMockAccountRepository(0), categoryRepo),
+		NewTransferHintService(&mockTransferHintRepository{}, accountRepo, transactionRepo),
+		&mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{})
+
+	file := "!Type:Bank\nD3/1/2025\nT-10.00\nPCOFFEE\n^\nD3/2/2025\nT-20.00\nPGROCERIES\n^\nD3/3/2025\nT-5.00\nPMISTAKE\n^\n"
+	review, err := service.Stage(ctx, "checking", strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if review.Import.Status != domain.ImportStatusStaged || len(review.Rows) != 3 || review.Rows[0].Status != ImportRowPending {
+		t.Fatalf("expected three rows waiting for review, got %+v", review)
+	}
+	if len(transactionRepo.transactions) != 0 || accountRepo.accounts["checking"].Balance != 0 {
+		t.Fatal("expected staging to leave the ledger alone")
+	}
+	again, err := service.Stage(ctx, "checking", strings.NewReader(file))
+	if err != nil || again.Import.ID != review.Import.ID {
+		t.Errorf("expected staging the same file to return the staged import, got %+v, %v", again, err)
+	}
+
+	// Review: categorize one row and leave the last one out
+	importID := review.Import.ID
+	missing := "missing"
+	if _, err := service.UpdateImportRow(ctx, importID, review.Rows[0].ID, &missing, nil, false); err != domain.ErrCategoryNotFound {
+		t.Errorf("expected an unknown category to be rejected, got %v", err)
+	}
+	groceries := "groceries"
+	if _, err := service.UpdateImportRow(ctx, importID, review.Rows[1].ID, &groceries, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.UpdateImportRow(ctx, importID, review.Rows[2].ID, nil, nil, true); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := service.CommitImport(ctx, importID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ImportedTransactions != 2 || result.Rows[2].Status != ImportRowExcluded || result.NewAccountBalance != -3000 {
+		t.Fatalf("expected two transactions saved and one excluded, got %+v", result)
+	}
+	saved, _ := transactionRepo.GetByID(ctx, result.Rows[1].TransactionID)
+	if saved == nil || saved.CategoryID == nil || *saved.CategoryID != groceries {
+		t.Errorf("expected the reviewed category to be saved, got %+v", saved)
+	}
+	if _, err := service.CommitImport(ctx, importID); err != domain.ErrImportNotStaged {
+		t.Errorf("expected committing twice to fail, got %v", err)
+	}
+	if _, err := service.UpdateImportRow(ctx, importID, review.Rows[0].ID, nil, nil, true); err != domain.ErrImportNotStaged {
+		t.Errorf("expected a committed import's rows to be locked, got %v", err)
+	}
+
+	// Undo takes the whole import back out
+	undone, err := service.UndoImport(ctx, importID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if undone.Status != domain.ImportStatusUndone || len(transactionRepo.transactions) != 0 || accountRepo.accounts["checking"].Balance != 0 {
+		t.Errorf("expected the import's transactions and balance change reverted, got %+v with %d transactions, balance %d",
+			undone, len(transactionRepo.transactions), accountRepo.accounts["checking"].Balance)
+	}
+	if _, err := service.UndoImport(ctx, importID); err == nil {
+		t.Error("expected undoing twice to fail")
+	}
+
+	// An undone file can be staged again, and a staged import discarded
+	restaged, err := service.Stage(ctx, "checking", strings.NewReader(file))
+	if err != nil || restaged.Import.ID == importID {
+		t.Fatalf("expected an undone file to be staged afresh, got %+v, %v", restaged, err)
+	}
+	if err := service.DeleteImport(ctx, restaged.Import.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.GetImport(ctx, restaged.Import.ID); err != domain.ErrImportNotFound {
+		t.Errorf("expected the discarded import to be gone, got %v", err)
+	}
+}
+
+func TestImportService_DeleteImport(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking}
+	transactionRepo := newMockTransactionRepository()
+	categoryRepo := newMockCategoryRepository()
+	importFiles := &mockImportFileRepository{}
+	service := NewImportService(transactionRepo, accountRepo, newMockBudgetStateRepository(0, 0), importFiles, newMockSettingRepository(),
+		ofx.NewParser(), csv.NewParser(), qif.NewParser(),
+		NewPluginService(categoryRepo, accountRepo, transactionRepo, newMockAllocationRepository(), nil, nil, nil),
+		NewPayeeService(newMockPayeeRepository(), newMockPayeeRuleRepository(), newMockAccountRepository(0), categoryRepo),
+		NewTransferHintService(&mockTransferHintRepository{}, accountRepo, transactionRepo),
+		&mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{})
+
+	// Deleting a committed import takes it back out of the ledger
+	file := "!Type:Bank\nD3/1/2025\nT-10.00\nPCOFFEE\n^\nD3/2/2025\nT125.00\nPPAYCHECK\n^\n"
+	review, err := service.Stage(ctx, "checking", strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.CommitImport(ctx, review.Import.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(transactionRepo.transactions) != 2 || accountRepo.accounts["checking"].Balance != 11500 {
+		t.Fatalf("expected the import in the ledger, got %d transactions, balance %d", len(transactionRepo.transactions), accountRepo.accounts["checking"].Balance)
+	}
+	if err := service.DeleteImport(ctx, review.Import.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(transactionRepo.transactions) != 0 || accountRepo.accounts["checking"].Balance != 0 {
+		t.Errorf("expected the import's transactions and balance change reverted, got %d transactions, balance %d",
+			len(transactionRepo.transactions), accountRepo.accounts["checking"].Balance)
+	}
+	if _, err := service.GetImport(ctx, review.Import.ID); err != domain.ErrImportNotFound {
+		t.Errorf("expected the deleted import to be gone, got %v", err)
+	}
+
+	// Imports made before rows were kept are rolled back from the transactions tagged with them
+	legacyID := "legacy"
+	importFiles.Create(ctx, &domain.ImportFile{ID: legacyID, AccountID: "checking", Format: ImportFormatQIF,
+		Status: domain.ImportStatusCommitted, TotalTransactions: 1, ImportedTransactions: 1})
+	transactionRepo.transactions = []*domain.Transaction{
+		{ID: "old", AccountID: "checking", Amount: -4000, ImportID: &legacyID, Date: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "manual", AccountID: "checking", Amount: -500, Date: time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	accountRepo.accounts["checking"].Balance = -4500
+	if err := service.DeleteImport(ctx, legacyID); err != nil {
+		t.Fatal(err)
+	}
+	if len(transactionRepo.transactions) != 1 || transactionRepo.transactions[0].ID != "manual" || accountRepo.accounts["checking"].Balance != -500 {
+		t.Errorf("expected only the imported transaction taken out, got %+v, balance %d", transactionRepo.transactions, accountRepo.accounts["checking"].Balance)
+	}
+}
+
+func TestImportService_MergesManualEntries(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking, Balance: -1000}
+	groceries := "groceries"
+	transactionRepo := newMockTransactionRepository()
+	transactionRepo.transactions = []*domain.Transaction{
+		{ID: "manual", AccountID: "checking", CategoryID: &groceries, Amount: -1000, Description: "Market with Sam",
+			Type: domain.TransactionTypeNormal, Date: time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)},
+	}
+	categoryRepo := newMockCategoryRepository()
+	importFiles := &mockImportFileRepository{}
+	service := NewImportService(transactionRepo, accountRepo, newMockBudgetStateRepository(0, 0), importFiles, newMockSettingRepository(),
+		ofx.NewParser(), csv.NewParser(), qif.NewParser(),
+		NewPluginService(categoryRepo, accountRepo, transactionRepo, newMockAllocationRepository(), nil, nil, nil),
+		NewPayeeService(newMockPayeeRepository(), newMockPayeeRuleRepository(), newMockAccountRepository(0), categoryRepo),
+		NewTransferHintService(&mockTransferHintRepository{}, accountRepo, transactionRepo),
+		&mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{})
+
+	// Merging is off until it's turned on
+	if settings, err := service.GetMatchingSettings(ctx); err != nil || settings.AutoMerge || settings.MatchDays != domain.DefaultImportMatchDays {
+		t.Fatalf("expected merging off by default, got %+v, %v", settings, err)
+	}
+	if _, err := service.UpdateMatchingSettings(ctx, &domain.ImportMatchingSettings{AutoMerge: true, MatchDays: 30}); err == nil {
+		t.Error("expected a month-long window to be rejected")
+	}
+	if _, err := service.UpdateMatchingSettings(ctx, &domain.ImportMatchingSettings{AutoMerge: true, MatchDays: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	file := "!Type:Bank\nD3/1/2025\nT-10.00\nPFRESH MARKET\n^\nD3/2/2025\nT-20.00\nPGAS\n^\n"
+	result, err := service.Import(ctx, "checking", strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ImportedTransactions != 2 || result.Rows[0].Status != ImportRowMerged || result.Rows[0].TransactionID != "manual" || result.Rows[1].Status != ImportRowNew {
+		t.Fatalf("expected the first row merged into the manual entry, got %+v", result.Rows)
+	}
+	manual := transactionRepo.transactions[0]
+	if len(transactionRepo.transactions) != 2 || manual.FitID == nil || manual.ImportID == nil ||
+		*manual.CategoryID != groceries || manual.Description != "Market with Sam" {
+		t.Errorf("expected the manual entry to keep its category and description and take the FitID, got %+v", manual)
+	}
+	if balance := accountRepo.accounts["checking"].Balance; balance != -3000 {
+		t.Errorf("expected only the new transaction to move the balance, got %d", balance)
+	}
+
+	// Undoing the import leaves the manual entry as it was
+	if _, err := service.UndoImport(ctx, result.ImportID); err != nil {
+		t.Fatal(err)
+	}
+	if len(transactionRepo.transactions) != 1 || manual.FitID != nil || manual.ImportID != nil || accountRepo.accounts["checking"].Balance != -1000 {
+		t.Errorf("expected only the manual entry left, unmerged, got %+v, balance %d", transactionRepo.transactions, accountRepo.accounts["checking"].Balance)
+	}
+}
+
+func TestImportService_TrackingAccount(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["brokerage"] = &domain.Account{ID: "brokerage", Name: "Brokerage", Type: domain.AccountTypeTracking, Balance: 100000}
+	transactionRepo := newMockTransactionRepository()
+	categoryRepo := newMockCategoryRepository()
+	service := NewImportService(transactionRepo, accountRepo, newMockBudgetStateRepository(0, 0), &mockImportFileRepository{}, newMockSettingRepository(),
+		ofx.NewParser(), csv.NewParser(), qif.NewParser(),
+		NewPluginService(categoryRepo, accountRepo, transactionRepo, newMockAllocationRepository(), nil, nil, nil),
+		NewPayeeService(newMockPayeeRepository(), newMockPayeeRuleRepository(), newMockAccountRepository(0), categoryRepo),
+		NewTransferHintService(&mockTransferHintRepository{}, accountRepo, transactionRepo),
+		&mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{})
+
+	// Imported rows are balance updates, never income or transfers
+	result, err := service.Import(ctx, "brokerage", strings.NewReader("!Type:Bank\nD3/1/2025\nT25.00\nPCONTRIBUTION\n^\nD3/31/2025\nT1200.00\nPMARKET GAIN\n^\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ImportedTransactions != 2 || accountRepo.accounts["brokerage"].Balance != 222500 {
+		t.Fatalf("expected both rows imported into the balance, got %+v", result)
+	}
+	for _, id := range result.ImportedTransactionIDs {
+		if txn, _ := transactionRepo.GetByID(ctx, id); txn == nil || txn.Type != domain.TransactionTypeAdjustment || txn.CategoryID != nil {
+			t.Errorf("expected an uncategorized adjustment, got %+v", txn)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package application
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/google/uuid"
+)
+
+// maxFinishedJobs is how many finished jobs are kept for clients to look at
+const maxFinishedJobs = 50
+
+// JobStatus is where a background job is in its life
+type JobStatus string
+
+const (
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+// Job is a piece of background work and how far it has got
+type Job struct {
+	ID         string     `json:"id"`
+	Kind       string     `json:"kind"`
+	Status     JobStatus  `json:"status"`
+	Total      int        `json:"total"`     // Items the job will look at, once known
+	Processed  int        `json:"processed"` // Items looked at so far
+	Changed    int        `json:"changed"`   // Items the job changed
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// JobFunc does a job's work, reporting progress as it goes
+type JobFunc func(ctx context.Context, progress *JobProgress) error
+
+// JobProgress lets a running job report how far it has got
+type JobProgress struct {
+	service *JobService
+	job     *Job
+}
+
+// SetTotal records how many items the job will look at
+func (p *JobProgress) SetTotal(total int) {
+	p.service.mu.Lock()
+	defer p.service.mu.Unlock()
+	p.job.Total = total
+}
+
+// Advance records that more items were looked at, and how many of them were changed
+func (p *JobProgress) Advance(processed, changed int) {
+	p.service.mu.Lock()
+	defer p.service.mu.Unlock()
+	p.job.Processed += processed
+	p.job.Changed += changed
+}
+
+// JobService runs background jobs one at a time, in the order they were queued
+// Jobs live in memory: the list starts empty when the server restarts, and jobs that
+// were queued or running then don't run again.
+type JobService struct {
+	mu    sync.Mutex
+	jobs  []*Job // Oldest first
+	queue []queuedJob
+	wake  chan struct{}
+}
+
+type queuedJob struct {
+	job *Job
+	run JobFunc
+}
+
+// NewJobService creates a new job service; jobs run once Run is started
+func NewJobService() *JobService {
+	return &JobService{wake: make(chan struct{}, 1)}
+}
+
+// Enqueue queues a job and returns its ID
+func (s *JobService) Enqueue(kind string, run JobFunc) string {
+	job := &Job{
+		ID:        uuid.New().String(),
+		Kind:      kind,
+		Status:    JobStatusQueued,
+		CreatedAt: time.Now(),
+	}
+
+	s.mu.Lock()
+	s.jobs = append(s.jobs, job)
+	s.queue = append(s.queue, queuedJob{job: job, run: run})
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return job.ID
+}
+
+// Get returns a snapshot of a job
+func (s *JobService) Get(id string) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, job := range s.jobs {
+		if job.ID == id {
+			snapshot := *job
+			return &snapshot, nil
+		}
+	}
+	return nil, domain.ErrJobNotFound
+}
+
+// List returns snapshots of the queued, running and recently finished jobs, newest first
+func (s *JobService) List() []*Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := make([]*Job, 0, len(s.jobs))
+	for i := len(s.jobs) - 1; i >= 0; i-- {
+		snapshot := *s.jobs[i]
+		jobs = append(jobs, &snapshot)
+	}
+	return jobs
+}
+
+// Run runs queued jobs until ctx is cancelled
+func (s *JobService) Run(ctx context.Context) {
+	for {
+		next, ok := s.next()
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.wake:
+				continue
+			}
+		}
+		s.run(ctx, next)
+	}
+}
+
+// next takes the oldest queued job off the queue and marks it running
+func (s *JobService) next() (queuedJob, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.queue) == 0 {
+		return queuedJob{}, false
+	}
+	next := s.queue[0]
+	s.queue = s.queue[1:]
+
+	now := time.Now()
+	next.job.Status = JobStatusRunning
+	next.job.StartedAt = &now
+	return next, true
+}
+
+func (s *JobService) run(ctx context.Context, next queuedJob) {
+	err := next.run(ctx, &JobProgress{service: s, job: next.job})
+	if err != nil {
+		log.Printf("jobs: %s %s: %v", next.job.Kind, next.job.ID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	next.job.FinishedAt = &now
+	next.job.Status = JobStatusSucceeded
+	if err != nil {
+		next.job.Status = JobStatusFailed
+		next.job.Error = err.Error()
+	}
+	s.pruneFinished()
+}
+
+// pruneFinished drops the oldest finished jobs beyond maxFinishedJobs; callers hold s.mu
+func (s *JobService) pruneFinished() {
+	finished := 0
+	for _, job := range s.jobs {
+		if job.FinishedAt != nil {
+			finished++
+		}
+	}
+	kept := s.jobs[:0]
+	for _, job := range s.jobs {
+		if job.FinishedAt != nil && finished > maxFinishedJobs {
+			finished--
+			continue
+		}
+		kept = append(kept, job)
+	}
+	s.jobs = kept
+}
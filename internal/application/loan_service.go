@@ -0,0 +1,218 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// maxAmortizationMonths caps an amortization schedule at 50 years of payments
+const maxAmortizationMonths = 600
+
+// LoanService manages loan accounts: their repayment terms, amortization and payments
+// A payment is split the way the lender splits it: the interest is a categorized expense on
+// the account paying it and only the principal is transferred to the loan, so reports show
+// the interest as spending and the principal as debt paid down.
+type LoanService struct {
+	transactions *TransactionService
+}
+
+// NewLoanService creates a new loan service
+func NewLoanService(transactionService *TransactionService) *LoanService {
+	return &LoanService{transactions: transactionService}
+}
+
+// AmortizationPayment is one scheduled loan payment
+type AmortizationPayment struct {
+	Period    string `json:"period"` // Month the payment is made, YYYY-MM
+	Payment   int64  `json:"payment"`
+	Interest  int64  `json:"interest"`
+	Principal int64  `json:"principal"`
+	Owed      int64  `json:"owed"` // Principal still owed after the payment
+}
+
+// AmortizationSchedule is how a loan's remaining balance is paid off
+type AmortizationSchedule struct {
+	AccountID       string                `json:"account_id"`
+	Owed            int64                 `json:"owed"` // Principal owed now
+	InterestRateBps int64                 `json:"interest_rate_bps"`
+	MonthlyPayment  int64                 `json:"monthly_payment"`
+	TotalInterest   int64                 `json:"total_interest"`
+	PayoffPeriod    string                `json:"payoff_period,omitempty"` // Month of the last payment; empty when nothing is owed
+	Payments        []AmortizationPayment `json:"payments"`
+}
+
+// LoanPayment is a payment towards a loan, split into interest and principal
+type LoanPayment struct {
+	Interest        *domain.Transaction `json:"interest,omitempty"`  // Expense in the interest category; nil when there was no interest
+	Principal       *domain.Transaction `json:"principal,omitempty"` // Transfer to the loan, from the paying account's side; nil when it was all interest
+	InterestAmount  int64               `json:"interest_amount"`
+	PrincipalAmount int64               `json:"principal_amount"`
+	Balance         int64               `json:"balance"` // Loan balance after the payment
+}
+
+// SetTerms sets how a loan is repaid
+// The interest category is the expense category interest is spent from; empty clears it.
+func (s *LoanService) SetTerms(ctx context.Context, accountID string, monthlyPayment int64, interestCategoryID *string) (*domain.Account, error) {
+	account, err := s.loan(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if monthlyPayment <= 0 {
+		return nil, fmt.Errorf("monthly payment must be positive")
+	}
+	interestCategoryID = emptyToNil(interestCategoryID)
+	if interestCategoryID != nil {
+		category, err := s.transactions.categoryRepo.GetByID(ctx, *interestCategoryID)
+		if err != nil {
+			return nil, domain.ErrCategoryNotFound
+		}
+		if isPaymentCategory(category) {
+			return nil, fmt.Errorf("interest can't be spent from a payment category")
+		}
+	}
+
+	account.Loan = &domain.LoanTerms{MonthlyPayment: monthlyPayment, InterestCategoryID: interestCategoryID}
+	account.UpdatedAt = time.Now()
+	if err := s.transactions.accountRepo.Update(ctx, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// Amortization schedules a loan's remaining payments from its balance, the first in start's month
+// Interest accrues monthly at the account's interest rate, or not at all when it has none;
+// the last payment is only what's left.
+func (s *LoanService) Amortization(ctx context.Context, accountID string, start time.Time) (*AmortizationSchedule, error) {
+	account, err := s.loan(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.Loan == nil {
+		return nil, domain.ErrLoanTermsNotSet
+	}
+
+	schedule := &AmortizationSchedule{
+		AccountID:       account.ID,
+		Owed:            max(-account.Balance, 0),
+		InterestRateBps: loanInterestRate(account),
+		MonthlyPayment:  account.Loan.MonthlyPayment,
+		Payments:        []AmortizationPayment{},
+	}
+	month := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for owed := schedule.Owed; owed > 0; month = month.AddDate(0, 1, 0) {
+		interest := monthlyInterest(owed, schedule.InterestRateBps)
+		if interest >= schedule.MonthlyPayment || len(schedule.Payments) == maxAmortizationMonths {
+			return nil, domain.ErrLoanNeverRepaid
+		}
+		principal := min(schedule.MonthlyPayment-interest, owed)
+		owed -= principal
+		schedule.Payments = append(schedule.Payments, AmortizationPayment{
+			Period:    month.Format("2006-01"),
+			Payment:   interest + principal,
+			Interest:  interest,
+			Principal: principal,
+			Owed:      owed,
+		})
+		schedule.TotalInterest += interest
+		schedule.PayoffPeriod = month.Format("2006-01")
+	}
+	return schedule, nil
+}
+
+// RecordPayment pays amount towards a loan from another account
+// interest is how much of it is interest, as the lender's statement says; nil estimates a
+// month's interest on what's owed. The principal comes out of categoryID, the category the
+// payment was budgeted in, when one is given. Everything is written atomically.
+func (s *LoanService) RecordPayment(ctx context.Context, loanID, fromAccountID string, amount int64, interest *int64, categoryID *string, date time.Time) (*LoanPayment, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("payment amount must be positive")
+	}
+	loan, err := s.loan(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+	owed := max(-loan.Balance, 0)
+
+	payment := &LoanPayment{InterestAmount: monthlyInterest(owed, loanInterestRate(loan))}
+	if interest != nil {
+		payment.InterestAmount = *interest
+	}
+	if payment.InterestAmount < 0 || payment.InterestAmount > amount {
+		return nil, fmt.Errorf("interest must be between zero and the payment amount")
+	}
+	payment.PrincipalAmount = amount - payment.InterestAmount
+	if payment.PrincipalAmount > owed {
+		return nil, fmt.Errorf("payment is more than the %s owed", domain.Cents(owed))
+	}
+	if payment.InterestAmount > 0 && (loan.Loan == nil || loan.Loan.InterestCategoryID == nil) {
+		return nil, fmt.Errorf("set the loan's interest category before paying interest")
+	}
+	categoryID = emptyToNil(categoryID)
+	if categoryID != nil {
+		if _, err := s.transactions.categoryRepo.GetByID(ctx, *categoryID); err != nil {
+			return nil, domain.ErrCategoryNotFound
+		}
+	}
+
+	err = s.transactions.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if payment.InterestAmount > 0 {
+			payment.Interest, err = s.transactions.createTransaction(ctx, fromAccountID, loan.Loan.InterestCategoryID, -payment.InterestAmount, loan.Name+" interest", date)
+			if err != nil {
+				return err
+			}
+		}
+		if payment.PrincipalAmount > 0 {
+			payment.Principal, err = s.transactions.createTransfer(ctx, fromAccountID, loan.ID, payment.PrincipalAmount, loan.Name+" principal", date)
+			if err != nil {
+				return err
+			}
+			if categoryID != nil {
+				payment.Principal.CategoryID = categoryID
+				if err := s.transactions.transactionRepo.Update(ctx, payment.Principal); err != nil {
+					return fmt.Errorf("failed to categorize principal: %w", err)
+				}
+			}
+		}
+
+		paid, err := s.transactions.accountRepo.GetByID(ctx, loan.ID)
+		if err != nil {
+			return err
+		}
+		payment.Balance = paid.Balance
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// loan gets an account that must be a loan
+func (s *LoanService) loan(ctx context.Context, id string) (*domain.Account, error) {
+	account, err := s.transactions.accountRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, domain.ErrAccountNotFound
+	}
+	if account.Type != domain.AccountTypeLoan {
+		return nil, domain.ErrNotLoanAccount
+	}
+	return account, nil
+}
+
+// loanInterestRate returns a loan's APR in basis points, zero when it isn't known
+func loanInterestRate(account *domain.Account) int64 {
+	if account.Details.InterestRateBps == nil {
+		return 0
+	}
+	return *account.Details.InterestRateBps
+}
+
+// monthlyInterest is a month's interest on owed at an APR of rateBps, to the nearest cent
+func monthlyInterest(owed, rateBps int64) int64 {
+	return int64(math.Round(float64(owed) * float64(rateBps) / 10000 / 12))
+}
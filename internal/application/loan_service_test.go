@@ -0,0 +1,128 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func newTestLoanService() (*LoanService, *mockAccountRepository, *mockCategoryRepository, *mockTransactionRepository) {
+	rate := int64(1200)
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["car"] = &domain.Account{ID: "car", Name: "Car loan", Type: domain.AccountTypeLoan, Balance: -120000,
+		Details: domain.AccountDetails{InterestRateBps: &rate}}
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking, Balance: 100000}
+	categoryRepo := newMockCategoryRepository()
+	categoryRepo.categories["interest"] = &domain.Category{ID: "interest", Name: "Interest"}
+	categoryRepo.categories["car-payment"] = &domain.Category{ID: "car-payment", Name: "Car payment"}
+	transactionRepo := newMockTransactionRepository()
+	transactionService := NewTransactionService(transactionRepo, accountRepo, categoryRepo, newMockBudgetStateRepository(0, 0),
+		&mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{})
+	return NewLoanService(transactionService), accountRepo, categoryRepo, transactionRepo
+}
+
+func TestLoanService_Amortization(t *testing.T) {
+	ctx := context.Background()
+	service, accountRepo, _, _ := newTestLoanService()
+	start := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	if _, err := service.Amortization(ctx, "car", start); !errors.Is(err, domain.ErrLoanTermsNotSet) {
+		t.Errorf("expected %v without terms, got %v", domain.ErrLoanTermsNotSet, err)
+	}
+	if _, err := service.Amortization(ctx, "checking", start); !errors.Is(err, domain.ErrNotLoanAccount) {
+		t.Errorf("expected %v for a checking account, got %v", domain.ErrNotLoanAccount, err)
+	}
+
+	if _, err := service.SetTerms(ctx, "car", 50000, nil); err != nil {
+		t.Fatal(err)
+	}
+	schedule, err := service.Amortization(ctx, "car", start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1% a month on 1,200.00 owed: 12.00, then 7.12, then 2.19 with the last payment only what's left
+	want := []AmortizationPayment{
+		{Period: "2025-01", Payment: 50000, Interest: 1200, Principal: 48800, Owed: 71200},
+		{Period: "2025-02", Payment: 50000, Interest: 712, Principal: 49288, Owed: 21912},
+		{Period: "2025-03", Payment: 22131, Interest: 219, Principal: 21912, Owed: 0},
+	}
+	if len(schedule.Payments) != len(want) {
+		t.Fatalf("expected %d payments, got %+v", len(want), schedule.Payments)
+	}
+	for i, payment := range schedule.Payments {
+		if payment != want[i] {
+			t.Errorf("payment %d = %+v, want %+v", i, payment, want[i])
+		}
+	}
+	if schedule.Owed != 120000 || schedule.TotalInterest != 2131 || schedule.PayoffPeriod != "2025-03" {
+		t.Errorf("unexpected schedule totals: %+v", schedule)
+	}
+
+	// A payment that only covers the interest never pays the loan off
+	accountRepo.accounts["car"].Loan.MonthlyPayment = 1200
+	if _, err := service.Amortization(ctx, "car", start); !errors.Is(err, domain.ErrLoanNeverRepaid) {
+		t.Errorf("expected %v, got %v", domain.ErrLoanNeverRepaid, err)
+	}
+}
+
+func TestLoanService_RecordPayment(t *testing.T) {
+	ctx := context.Background()
+	service, accountRepo, categoryRepo, transactionRepo := newTestLoanService()
+	date := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	if _, err := service.RecordPayment(ctx, "car", "checking", 50000, nil, nil, date); err == nil {
+		t.Error("expected paying interest without an interest category to fail")
+	}
+	card, cardPayment := "card", "card-payment"
+	categoryRepo.categories[cardPayment] = &domain.Category{ID: cardPayment, Name: "Visa Payment", PaymentForAccountID: &card}
+	if _, err := service.SetTerms(ctx, "car", 50000, &cardPayment); err == nil {
+		t.Error("expected a payment category to be refused for interest")
+	}
+	interestCategory, carPayment := "interest", "car-payment"
+	if _, err := service.SetTerms(ctx, "car", 50000, &interestCategory); err != nil {
+		t.Fatal(err)
+	}
+
+	// The interest is estimated from the rate: 1% of 1,200.00
+	payment, err := service.RecordPayment(ctx, "car", "checking", 50000, nil, &carPayment, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payment.InterestAmount != 1200 || payment.PrincipalAmount != 48800 || payment.Balance != -71200 {
+		t.Errorf("unexpected split: %+v", payment)
+	}
+	if payment.Interest.AccountID != "checking" || payment.Interest.Amount != -1200 || *payment.Interest.CategoryID != "interest" {
+		t.Errorf("expected an interest expense on checking, got %+v", payment.Interest)
+	}
+	if payment.Principal.Type != domain.TransactionTypeTransfer || payment.Principal.Amount != -48800 || *payment.Principal.CategoryID != "car-payment" {
+		t.Errorf("expected a categorized principal transfer from checking, got %+v", payment.Principal)
+	}
+	if balance := accountRepo.accounts["checking"].Balance; balance != 50000 {
+		t.Errorf("expected checking to pay the whole 500.00, got balance %d", balance)
+	}
+
+	// The statement's interest wins over the estimate
+	statementInterest := int64(700)
+	payment, err = service.RecordPayment(ctx, "car", "checking", 20000, &statementInterest, nil, date.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payment.PrincipalAmount != 19300 || payment.Balance != -51900 || payment.Principal.CategoryID != nil {
+		t.Errorf("unexpected split: %+v", payment)
+	}
+
+	// Paying off more than is owed fails without writing anything
+	count := len(transactionRepo.transactions)
+	if _, err := service.RecordPayment(ctx, "car", "checking", 60000, nil, nil, date); err == nil {
+		t.Error("expected a payment of more than is owed to fail")
+	}
+	if _, err := service.RecordPayment(ctx, "car", "missing", 10000, nil, nil, date); err == nil {
+		t.Error("expected a payment from a missing account to fail")
+	}
+	if len(transactionRepo.transactions) != count || accountRepo.accounts["car"].Balance != -51900 {
+		t.Error("expected failed payments to leave the loan alone")
+	}
+}
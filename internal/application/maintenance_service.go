@@ -0,0 +1,124 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// ErrMaintenanceRunning is returned when maintenance is asked for while it's already running
+var ErrMaintenanceRunning = errors.New("database maintenance is already running")
+
+// MaintenanceWindow is the quiet hours scheduled maintenance runs in, once a day
+type MaintenanceWindow struct {
+	StartHour int  // Local hour (0-23) the window opens
+	EndHour   int  // Local hour it closes; earlier than StartHour for a window across midnight
+	Vacuum    bool // Scheduled runs vacuum too, which blocks writes while it runs
+}
+
+// contains reports whether t falls within the window
+func (w MaintenanceWindow) contains(t time.Time) bool {
+	hour := t.Hour()
+	if w.StartHour <= w.EndHour {
+		return hour >= w.StartHour && hour < w.EndHour
+	}
+	return hour >= w.StartHour || hour < w.EndHour
+}
+
+// length returns how long the window is open
+func (w MaintenanceWindow) length() time.Duration {
+	return time.Duration((w.EndHour-w.StartHour+24)%24) * time.Hour
+}
+
+// MaintenanceReport is what a maintenance run did
+type MaintenanceReport struct {
+	StartedAt      time.Time           `json:"started_at"`
+	DurationMS     int64               `json:"duration_ms"`
+	Vacuumed       bool                `json:"vacuumed"`
+	SizeBefore     domain.DatabaseSize `json:"size_before"`
+	SizeAfter      domain.DatabaseSize `json:"size_after"`
+	ReclaimedBytes int64               `json:"reclaimed_bytes"` // How much smaller the file got
+}
+
+// MaintenanceService keeps a long-lived database file healthy: it refreshes the query
+// planner's statistics and, optionally, vacuums the file to return freed space
+type MaintenanceService struct {
+	maintenanceRepo domain.MaintenanceRepository
+	window          MaintenanceWindow
+
+	running       sync.Mutex // Held while maintenance runs
+	mu            sync.Mutex
+	lastScheduled time.Time // When the scheduled run last ran
+}
+
+// NewMaintenanceService creates a new maintenance service
+func NewMaintenanceService(maintenanceRepo domain.MaintenanceRepository, window MaintenanceWindow) *MaintenanceService {
+	return &MaintenanceService{maintenanceRepo: maintenanceRepo, window: window}
+}
+
+// Run optimizes the database now, vacuuming it too if asked
+func (s *MaintenanceService) Run(ctx context.Context, vacuum bool) (*MaintenanceReport, error) {
+	if !s.running.TryLock() {
+		return nil, ErrMaintenanceRunning
+	}
+	defer s.running.Unlock()
+
+	report := &MaintenanceReport{StartedAt: time.Now()}
+	var err error
+	if report.SizeBefore, err = s.maintenanceRepo.Size(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.maintenanceRepo.Optimize(ctx); err != nil {
+		return nil, err
+	}
+	if vacuum {
+		if err := s.maintenanceRepo.Vacuum(ctx); err != nil {
+			return nil, err
+		}
+		report.Vacuumed = true
+	}
+	if report.SizeAfter, err = s.maintenanceRepo.Size(ctx); err != nil {
+		return nil, err
+	}
+	report.ReclaimedBytes = report.SizeBefore.Bytes - report.SizeAfter.Bytes
+	report.DurationMS = time.Since(report.StartedAt).Milliseconds()
+	return report, nil
+}
+
+// RunScheduled runs maintenance if it's within the quiet window and hasn't run there today
+// For the scheduler, which calls it every hour.
+func (s *MaintenanceService) RunScheduled(ctx context.Context) error {
+	report, err := s.runScheduled(ctx, time.Now())
+	if err != nil || report == nil {
+		return err
+	}
+	log.Printf("maintenance: optimized the database in %dms, reclaiming %d bytes", report.DurationMS, report.ReclaimedBytes)
+	return nil
+}
+
+// runScheduled runs maintenance if it's due at now, returning nil when it isn't
+func (s *MaintenanceService) runScheduled(ctx context.Context, now time.Time) (*MaintenanceReport, error) {
+	if !s.window.contains(now) {
+		return nil, nil
+	}
+	s.mu.Lock()
+	// Once a run's window has closed, the next time it's in the window is the next day's
+	due := s.lastScheduled.IsZero() || now.Sub(s.lastScheduled) >= s.window.length()
+	if due {
+		s.lastScheduled = now
+	}
+	s.mu.Unlock()
+	if !due {
+		return nil, nil
+	}
+
+	report, err := s.Run(ctx, s.window.Vacuum)
+	if errors.Is(err, ErrMaintenanceRunning) {
+		return nil, nil
+	}
+	return report, err
+}
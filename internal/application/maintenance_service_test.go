@@ -0,0 +1,97 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type fakeMaintenanceRepository struct {
+	size     domain.DatabaseSize
+	runs     int
+	vacuums  int
+	optimize func() // Called while optimizing, if set
+}
+
+func (m *fakeMaintenanceRepository) Size(ctx context.Context) (domain.DatabaseSize, error) {
+	return m.size, nil
+}
+
+func (m *fakeMaintenanceRepository) Optimize(ctx context.Context) error {
+	m.runs++
+	if m.optimize != nil {
+		m.optimize()
+	}
+	return nil
+}
+
+func (m *fakeMaintenanceRepository) Vacuum(ctx context.Context) error {
+	m.vacuums++
+	m.size = domain.DatabaseSize{Bytes: m.size.Bytes - m.size.FreeBytes}
+	return nil
+}
+
+func TestMaintenanceService_Run(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeMaintenanceRepository{size: domain.DatabaseSize{Bytes: 10 << 20, FreeBytes: 4 << 20}}
+	service := NewMaintenanceService(repo, MaintenanceWindow{StartHour: 3, EndHour: 5})
+
+	report, err := service.Run(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Vacuumed || report.ReclaimedBytes != 0 || repo.vacuums != 0 {
+		t.Errorf("expected an optimize without a vacuum to reclaim nothing, got %+v", report)
+	}
+
+	report, err = service.Run(ctx, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Vacuumed || report.ReclaimedBytes != 4<<20 || report.SizeAfter.FreeBytes != 0 {
+		t.Errorf("expected the vacuum to reclaim the free pages, got %+v", report)
+	}
+
+	// Asked for again while it's still running
+	repo.optimize = func() {
+		if _, err := service.Run(ctx, false); err != ErrMaintenanceRunning {
+			t.Errorf("expected a second run to be refused, got %v", err)
+		}
+	}
+	if _, err := service.Run(ctx, false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMaintenanceService_RunScheduled(t *testing.T) {
+	ctx := context.Background()
+	at := func(day, hour int) time.Time { return time.Date(2024, 3, day, hour, 30, 0, 0, time.Local) }
+
+	tests := []struct {
+		name   string
+		window MaintenanceWindow
+		times  []time.Time
+		runs   int
+	}{
+		{"outside the window", MaintenanceWindow{StartHour: 3, EndHour: 5}, []time.Time{at(1, 2), at(1, 5), at(1, 14)}, 0},
+		{"once a day in the window", MaintenanceWindow{StartHour: 3, EndHour: 5}, []time.Time{at(1, 3), at(1, 4), at(2, 3), at(2, 4)}, 2},
+		{"window across midnight", MaintenanceWindow{StartHour: 23, EndHour: 2}, []time.Time{at(1, 23), at(2, 0), at(2, 1), at(2, 22), at(2, 23)}, 2},
+		{"window turned off", MaintenanceWindow{StartHour: 3, EndHour: 3}, []time.Time{at(1, 3), at(2, 3)}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeMaintenanceRepository{}
+			service := NewMaintenanceService(repo, tt.window)
+			for _, now := range tt.times {
+				if _, err := service.runScheduled(ctx, now); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if repo.runs != tt.runs {
+				t.Errorf("expected %d runs, got %d", tt.runs, repo.runs)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// MetricsPublisher sends a payload to a topic on a message broker
+// Implemented by the MQTT client in internal/infrastructure/mqtt
+type MetricsPublisher interface {
+	Publish(topic string, payload []byte, retained bool) error
+}
+
+// MQTTService publishes budget metrics (account balances, Ready to Assign and
+// selected category availables) for home dashboards such as Home Assistant
+type MQTTService struct {
+	accountRepo       domain.AccountRepository
+	categoryRepo      domain.CategoryRepository
+	settingRepo       domain.SettingRepository
+	allocationService *AllocationService
+	publisher         MetricsPublisher // Optional - nil when MQTT is not configured
+	topicPrefix       string
+	discoveryPrefix   string
+
+	published  map[string]string // Last value sent per state topic
+	discovered map[string]bool   // Discovery configs already sent
+}
+
+// NewMQTTService creates a new MQTT service
+// publisher may be nil, in which case only the settings endpoints are usable.
+func NewMQTTService(
+	accountRepo domain.AccountRepository,
+	categoryRepo domain.CategoryRepository,
+	settingRepo domain.SettingRepository,
+	allocationService *AllocationService,
+	publisher MetricsPublisher,
+	topicPrefix, discoveryPrefix string,
+) *MQTTService {
+	return &MQTTService{
+		accountRepo:       accountRepo,
+		categoryRepo:      categoryRepo,
+		settingRepo:       settingRepo,
+		allocationService: allocationService,
+		publisher:         publisher,
+		topicPrefix:       strings.TrimSuffix(topicPrefix, "/"),
+		discoveryPrefix:   strings.TrimSuffix(discoveryPrefix, "/"),
+		published:         make(map[string]string),
+		discovered:        make(map[string]bool),
+	}
+}
+
+// budgetMetric is a single published value
+type budgetMetric struct {
+	ObjectID string // Unique, topic-safe identifier
+	Name     string // Human-readable name for discovery
+	Topic    string // State topic
+	Value    int64  // Cents
+}
+
+// haDiscoveryConfig is a Home Assistant MQTT discovery payload for a sensor
+type haDiscoveryConfig struct {
+	Name              string   `json:"name"`
+	UniqueID          string   `json:"unique_id"`
+	StateTopic        string   `json:"state_topic"`
+	UnitOfMeasurement string   `json:"unit_of_measurement"`
+	DeviceClass       string   `json:"device_class"`
+	Device            haDevice `json:"device"`
+}
+
+type haDevice struct {
+	Identifiers []string `json:"identifiers"`
+	Name        string   `json:"name"`
+}
+
+// GetSettings returns the saved MQTT settings, or defaults if none are saved
+func (s *MQTTService) GetSettings(ctx context.Context) (*domain.MQTTSettings, error) {
+	settings := &domain.MQTTSettings{CategoryIDs: []string{}}
+
+	setting, err := s.settingRepo.Get(ctx, domain.SettingKeyMQTT)
+	if err != nil {
+		// Nothing saved yet - use defaults
+		return settings, nil
+	}
+
+	if err := json.Unmarshal([]byte(setting.Value), settings); err != nil {
+		return nil, fmt.Errorf("failed to decode MQTT settings: %w", err)
+	}
+	return settings, nil
+}
+
+// UpdateSettings validates and saves which categories are published
+func (s *MQTTService) UpdateSettings(ctx context.Context, settings *domain.MQTTSettings) (*domain.MQTTSettings, error) {
+	if settings.CategoryIDs == nil {
+		settings.CategoryIDs = []string{}
+	}
+	for _, id := range settings.CategoryIDs {
+		if _, err := s.categoryRepo.GetByID(ctx, id); err != nil {
+			return nil, fmt.Errorf("category not found: %s", id)
+		}
+	}
+
+	value, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode MQTT settings: %w", err)
+	}
+
+	if err := s.settingRepo.Set(ctx, &domain.Setting{
+		Key:       domain.SettingKeyMQTT,
+		Value:     string(value),
+		UpdatedAt: time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// Run publishes changed metrics every interval until ctx is cancelled
+func (s *MQTTService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.PublishChanges(ctx); err != nil {
+			log.Printf("mqtt: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishChanges publishes every metric whose value changed since the last call
+// State is published retained so dashboards get the current value on connect.
+func (s *MQTTService) PublishChanges(ctx context.Context) error {
+	if s.publisher == nil {
+		return fmt.Errorf("MQTT is not configured")
+	}
+
+	metrics, err := s.collectMetrics(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, metric := range metrics {
+		if s.discoveryPrefix != "" && !s.discovered[metric.ObjectID] {
+			if err := s.publishDiscovery(metric); err != nil {
+				return err
+			}
+			s.discovered[metric.ObjectID] = true
+		}
+
+		value := domain.Cents(metric.Value).Decimal()
+		if s.published[metric.Topic] == value {
+			continue
+		}
+		if err := s.publisher.Publish(metric.Topic, []byte(value), true); err != nil {
+			return fmt.Errorf("failed to publish %s: %w", metric.Topic, err)
+		}
+		s.published[metric.Topic] = value
+	}
+
+	return nil
+}
+
+func (s *MQTTService) collectMetrics(ctx context.Context) ([]*budgetMetric, error) {
+	period := time.Now().Format("2006-01")
+	var metrics []*budgetMetric
+
+	rta, err := s.allocationService.CalculateReadyToAssignForPeriod(ctx, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate ready to assign: %w", err)
+	}
+	metrics = append(metrics, &budgetMetric{
+		ObjectID: "ready_to_assign",
+		Name:     "Ready to Assign",
+		Topic:    s.topicPrefix + "/ready_to_assign",
+		Value:    rta,
+	})
+
+	accounts, err := s.accountRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	for _, account := range accounts {
+		if account.HiddenFromDashboard {
+			continue
+		}
+		metrics = append(metrics, &budgetMetric{
+			ObjectID: "account_" + account.ID,
+			Name:     account.Name + " Balance",
+			Topic:    s.topicPrefix + "/account/" + account.ID + "/balance",
+			Value:    account.Balance,
+		})
+	}
+
+	settings, err := s.GetSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(settings.CategoryIDs) > 0 {
+		selected := make(map[string]bool)
+		for _, id := range settings.CategoryIDs {
+			selected[id] = true
+		}
+
+		summaries, err := s.allocationService.GetAllocationSummary(ctx, period)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get allocation summary: %w", err)
+		}
+		for _, summary := range summaries {
+			if !selected[summary.Category.ID] {
+				continue
+			}
+			metrics = append(metrics, &budgetMetric{
+				ObjectID: "category_" + summary.Category.ID,
+				Name:     summary.Category.Name + " Available",
+				Topic:    s.topicPrefix + "/category/" + summary.Category.ID + "/available",
+				Value:    summary.Available,
+			})
+		}
+	}
+
+	return metrics, nil
+}
+
+// publishDiscovery announces a metric as a Home Assistant sensor
+func (s *MQTTService) publishDiscovery(metric *budgetMetric) error {
+	objectID := "budget_" + strings.ReplaceAll(metric.ObjectID, "-", "_")
+	payload, err := json.Marshal(haDiscoveryConfig{
+		Name:              metric.Name,
+		UniqueID:          objectID,
+		StateTopic:        metric.Topic,
+		UnitOfMeasurement: domain.BudgetCurrency().Code,
+		DeviceClass:       "monetary",
+		Device:            haDevice{Identifiers: []string{"budget"}, Name: "Budget"},
+	})
+	if err != nil {
+		return err
+	}
+
+	topic := s.discoveryPrefix + "/sensor/" + objectID + "/config"
+	if err := s.publisher.Publish(topic, payload, true); err != nil {
+		return fmt.Errorf("failed to publish discovery for %s: %w", metric.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type publishedMessage struct {
+	topic    string
+	payload  string
+	retained bool
+}
+
+// fakePublisher records what would have been sent to the broker
+type fakePublisher struct {
+	messages []publishedMessage
+}
+
+func (p *fakePublisher) Publish(topic string, payload []byte, retained bool) error {
+	p.messages = append(p.messages, publishedMessage{topic: topic, payload: string(payload), retained: retained})
+	return nil
+}
+
+// take returns the messages published since the last call, by topic
+func (p *fakePublisher) take() map[string]publishedMessage {
+	byTopic := make(map[string]publishedMessage, len(p.messages))
+	for _, message := range p.messages {
+		byTopic[message.topic] = message
+	}
+	p.messages = nil
+	return byTopic
+}
+
+func TestMQTTService_PublishChanges(t *testing.T) {
+	ctx := context.Background()
+	period := time.Now().Format("2006-01")
+
+	categoryRepo := newMockCategoryRepository()
+	categoryRepo.categories["groceries"] = &domain.Category{ID: "groceries", Name: "Groceries"}
+	categoryRepo.categories["rent"] = &domain.Category{ID: "rent", Name: "Rent"}
+	allocationRepo := newMockAllocationRepository()
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a1", CategoryID: "groceries", Period: period, Amount: 10000})
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a2", CategoryID: "rent", Period: period, Amount: 120000})
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.Create(ctx, &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking, Balance: 50000})
+	accountRepo.Create(ctx, &domain.Account{ID: "savings", Name: "Savings", Type: domain.AccountTypeSavings, Balance: 90000, HiddenFromDashboard: true})
+
+	allocations := NewAllocationService(allocationRepo, categoryRepo, newMockTransactionRepository(), newMockBudgetStateRepository(0, 0), accountRepo, newMockCategoryGroupRepository(), newMockGoalRepository())
+	publisher := &fakePublisher{}
+	service := NewMQTTService(accountRepo, categoryRepo, newMockSettingRepository(), allocations, publisher, "budget/", "homeassistant")
+
+	if _, err := service.UpdateSettings(ctx, &domain.MQTTSettings{CategoryIDs: []string{"groceries"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.UpdateSettings(ctx, &domain.MQTTSettings{CategoryIDs: []string{"missing"}}); err == nil {
+		t.Error("expected an unknown category to be refused")
+	}
+
+	if err := service.PublishChanges(ctx); err != nil {
+		t.Fatal(err)
+	}
+	messages := publisher.take()
+	for topic, payload := range map[string]string{
+		"budget/account/checking/balance":     domain.Cents(50000).Decimal(),
+		"budget/category/groceries/available": domain.Cents(10000).Decimal(),
+	} {
+		if message, ok := messages[topic]; !ok || message.payload != payload {
+			t.Errorf("expected %s published as %s, got %+v", topic, payload, message)
+		}
+	}
+	if _, ok := messages["budget/ready_to_assign"]; !ok {
+		t.Error("expected Ready to Assign published")
+	}
+	for _, topic := range []string{"budget/account/savings/balance", "budget/category/rent/available"} {
+		if _, ok := messages[topic]; ok {
+			t.Errorf("expected %s left out", topic)
+		}
+	}
+	for _, objectID := range []string{"budget_ready_to_assign", "budget_account_checking", "budget_category_groceries"} {
+		message, ok := messages["homeassistant/sensor/"+objectID+"/config"]
+		if !ok {
+			t.Errorf("expected a discovery config for %s", objectID)
+			continue
+		}
+		var config haDiscoveryConfig
+		if err := json.Unmarshal([]byte(message.payload), &config); err != nil || config.UniqueID != objectID || config.DeviceClass != "monetary" {
+			t.Errorf("expected a monetary sensor config for %s, got %s", objectID, message.payload)
+		}
+	}
+	if len(messages) != 6 {
+		t.Errorf("expected three states and three discovery configs, got %d messages", len(messages))
+	}
+	for topic, message := range messages {
+		if !message.retained {
+			t.Errorf("expected %s retained", topic)
+		}
+	}
+
+	// Nothing changed, so nothing is sent again
+	if err := service.PublishChanges(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if messages := publisher.take(); len(messages) != 0 {
+		t.Errorf("expected nothing republished, got %v", messages)
+	}
+
+	// Only the changed balance is sent, without its discovery config again
+	accountRepo.accounts["checking"].Balance = 65000
+	if err := service.PublishChanges(ctx); err != nil {
+		t.Fatal(err)
+	}
+	messages = publisher.take()
+	if len(messages) != 1 || messages["budget/account/checking/balance"].payload != domain.Cents(65000).Decimal() {
+		t.Errorf("expected only the new checking balance published, got %v", messages)
+	}
+
+	// A newly selected category is announced and published
+	if _, err := service.UpdateSettings(ctx, &domain.MQTTSettings{CategoryIDs: []string{"groceries", "rent"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.PublishChanges(ctx); err != nil {
+		t.Fatal(err)
+	}
+	messages = publisher.take()
+	_, discovered := messages["homeassistant/sensor/budget_category_rent/config"]
+	if len(messages) != 2 || !discovered || messages["budget/category/rent/available"].payload != domain.Cents(120000).Decimal() {
+		t.Errorf("expected only rent announced and published, got %v", messages)
+	}
+
+	unconfigured := NewMQTTService(accountRepo, categoryRepo, newMockSettingRepository(), allocations, nil, "budget", "")
+	if err := unconfigured.PublishChanges(ctx); err == nil {
+		t.Error("expected publishing without a broker to fail")
+	}
+}
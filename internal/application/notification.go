@@ -0,0 +1,17 @@
+package application
+
+import "context"
+
+// Notification is a message addressed to one recipient
+type Notification struct {
+	To      string // Channel-specific address, e.g. an email address
+	Subject string
+	Body    string // Plain text
+}
+
+// NotificationChannel delivers notifications over one medium
+// Implemented by the SMTP channel in internal/infrastructure/notify
+type NotificationChannel interface {
+	Name() string
+	Send(ctx context.Context, notification *Notification) error
+}
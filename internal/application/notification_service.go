@@ -0,0 +1,231 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+const (
+	// maxDigestEntries caps how many events a single digest lists
+	maxDigestEntries = 200
+
+	// digestSlack lets a digest go out on the scheduler run closest to its due time
+	// instead of waiting a further hour when the run lands a moment early
+	digestSlack = time.Minute
+)
+
+// notificationEvent describes an audited change that users can be notified about
+type notificationEvent struct {
+	kind    domain.NotificationKind
+	summary string
+}
+
+// notificationEvents maps audited routes to the notifications they trigger
+// Security alerts go to the account the change was made to; other kinds go to every
+// user who opted in.
+var notificationEvents = map[string]notificationEvent{
+	"POST /api/integrations/email":             {domain.NotificationPendingTransactions, "A transaction arrived by email and is waiting for approval"},
+	"POST /api/auth/two-factor/enable":         {domain.NotificationSecurityAlerts, "Two-factor authentication was turned on"},
+	"POST /api/auth/two-factor/disable":        {domain.NotificationSecurityAlerts, "Two-factor authentication was turned off"},
+	"POST /api/auth/two-factor/recovery-codes": {domain.NotificationSecurityAlerts, "New two-factor recovery codes were generated"},
+	"PUT /api/users/{id}/two-factor":           {domain.NotificationSecurityAlerts, "An administrator changed your two-factor requirement"},
+	"DELETE /api/auth/sessions":                {domain.NotificationSecurityAlerts, "All other sessions were signed out"},
+	"POST /api/tokens":                         {domain.NotificationSecurityAlerts, "An API token was created"},
+	"DELETE /api/tokens/{id}":                  {domain.NotificationSecurityAlerts, "An API token was revoked"},
+}
+
+// NotificationService turns audited changes into notifications for the users who opted in
+// Each notification is sent right away unless the user chose a digest, in which case
+// SendDigests collects the events from the audit log and sends one summary per period.
+// Kinds the user marked urgent are always sent right away.
+type NotificationService struct {
+	auditRepo       domain.AuditRepository
+	userRepo        domain.UserRepository
+	userSettingRepo domain.UserSettingRepository
+	preferences     *UserPreferenceService
+	channel         NotificationChannel
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(
+	auditRepo domain.AuditRepository,
+	userRepo domain.UserRepository,
+	userSettingRepo domain.UserSettingRepository,
+	preferences *UserPreferenceService,
+	channel NotificationChannel,
+) *NotificationService {
+	return &NotificationService{
+		auditRepo:       auditRepo,
+		userRepo:        userRepo,
+		userSettingRepo: userSettingRepo,
+		preferences:     preferences,
+		channel:         channel,
+	}
+}
+
+// HandleAuditEntry sends immediate notifications for a recorded change
+// Subscribe it to the AuditService. Failures are logged.
+func (s *NotificationService) HandleAuditEntry(ctx context.Context, entry *domain.AuditEntry) {
+	event, ok := eventFor(entry)
+	if !ok {
+		return
+	}
+
+	users, err := s.userRepo.List(ctx)
+	if err != nil {
+		log.Printf("notifications: failed to list users: %v", err)
+		return
+	}
+	for _, user := range users {
+		if !notifies(entry, event, user) {
+			continue
+		}
+		prefs, err := s.preferences.GetPreferences(ctx, user.ID)
+		if err != nil {
+			log.Printf("notifications: %v", err)
+			continue
+		}
+		if !prefs.Notifications.Wants(event.kind) || !prefs.Notifications.SendsImmediately(event.kind) {
+			continue
+		}
+
+		notification := &Notification{
+			To:      user.Email,
+			Subject: event.summary,
+			Body:    fmt.Sprintf("%s.\n\n%s\n", event.summary, describeEntry(entry)),
+		}
+		if err := s.channel.Send(ctx, notification); err != nil {
+			log.Printf("notifications: failed to notify %s: %v", user.Email, err)
+		}
+	}
+}
+
+// SendDigests sends a summary to every user whose digest is due
+// Run it from the scheduler at least hourly. Each digest covers the events since the
+// previous one, leaving out kinds the user marked urgent since those were already sent.
+func (s *NotificationService) SendDigests(ctx context.Context) error {
+	users, err := s.userRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, user := range users {
+		if err := s.sendDigest(ctx, user, now); err != nil {
+			log.Printf("notifications: digest for %s: %v", user.Email, err)
+		}
+	}
+	return nil
+}
+
+func (s *NotificationService) sendDigest(ctx context.Context, user *domain.User, now time.Time) error {
+	prefs, err := s.preferences.GetPreferences(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	interval := prefs.Notifications.Digest.Interval()
+	if interval == 0 {
+		return nil
+	}
+
+	sentAt, err := s.digestSentAt(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if sentAt.IsZero() {
+		// Digests were just turned on; the first one covers the coming period
+		return s.markDigestSent(ctx, user.ID, now)
+	}
+	if now.Sub(sentAt) < interval-digestSlack {
+		return nil
+	}
+
+	// Don't reach back further than one period, e.g. after digests were off for a while
+	since := sentAt
+	if earliest := now.Add(-interval); since.Before(earliest) {
+		since = earliest
+	}
+	entries, err := s.auditRepo.List(ctx, domain.AuditQuery{Since: &since, Until: &now, Limit: maxDigestEntries})
+	if err != nil {
+		return err
+	}
+
+	var lines []string
+	for i := len(entries) - 1; i >= 0; i-- { // Oldest first
+		entry := entries[i]
+		event, ok := eventFor(entry)
+		if !ok || !notifies(entry, event, user) {
+			continue
+		}
+		if !prefs.Notifications.Wants(event.kind) || prefs.Notifications.SendsImmediately(event.kind) {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("- %s: %s (%s)", entry.CreatedAt.UTC().Format("2006-01-02 15:04 UTC"), event.summary, entry.Actor))
+	}
+
+	if len(lines) > 0 {
+		notification := &Notification{
+			To:      user.Email,
+			Subject: fmt.Sprintf("Budget digest: %d updates", len(lines)),
+			Body: fmt.Sprintf("Here's what happened since %s:\n\n%s\n",
+				since.Format("2006-01-02 15:04 UTC"), strings.Join(lines, "\n")),
+		}
+		if err := s.channel.Send(ctx, notification); err != nil {
+			return err
+		}
+	}
+	return s.markDigestSent(ctx, user.ID, now)
+}
+
+func (s *NotificationService) digestSentAt(ctx context.Context, userID string) (time.Time, error) {
+	settings, err := s.userSettingRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, setting := range settings {
+		if setting.Key == domain.UserSettingKeyDigestSentAt {
+			return time.Parse(time.RFC3339Nano, setting.Value)
+		}
+	}
+	return time.Time{}, nil
+}
+
+func (s *NotificationService) markDigestSent(ctx context.Context, userID string, at time.Time) error {
+	return s.userSettingRepo.Set(ctx, &domain.UserSetting{
+		UserID:    userID,
+		Key:       domain.UserSettingKeyDigestSentAt,
+		Value:     at.Format(time.RFC3339Nano),
+		UpdatedAt: time.Now(),
+	})
+}
+
+// eventFor returns the notification a successful change triggers, if any
+func eventFor(entry *domain.AuditEntry) (notificationEvent, bool) {
+	if entry.Status < 200 || entry.Status >= 300 {
+		return notificationEvent{}, false
+	}
+	event, ok := notificationEvents[entry.Route]
+	return event, ok
+}
+
+// notifies reports whether the user should hear about the event
+// Security alerts only concern the account that was changed.
+func notifies(entry *domain.AuditEntry, event notificationEvent, user *domain.User) bool {
+	if event.kind != domain.NotificationSecurityAlerts {
+		return true
+	}
+	if entry.Route == "PUT /api/users/{id}/two-factor" {
+		return strings.TrimSuffix(strings.TrimPrefix(entry.Path, "/api/users/"), "/two-factor") == user.ID
+	}
+	return entry.UserID != nil && *entry.UserID == user.ID
+}
+
+func describeEntry(entry *domain.AuditEntry) string {
+	return fmt.Sprintf("When: %s\nBy: %s\nFrom: %s",
+		entry.CreatedAt.UTC().Format("2006-01-02 15:04 UTC"), entry.Actor, entry.IPAddress)
+}
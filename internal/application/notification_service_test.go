@@ -0,0 +1,184 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type mockUserRepository struct {
+	users []*domain.User
+}
+
+func (m *mockUserRepository) Create(ctx context.Context, user *domain.User) error {
+	m.users = append(m.users, user)
+	return nil
+}
+
+func (m *mockUserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	for _, user := range m.users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+func (m *mockUserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for _, user := range m.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+func (m *mockUserRepository) List(ctx context.Context) ([]*domain.User, error) {
+	return m.users, nil
+}
+
+func (m *mockUserRepository) Update(ctx context.Context, user *domain.User) error {
+	return nil
+}
+
+func (m *mockUserRepository) Count(ctx context.Context) (int, error) {
+	return len(m.users), nil
+}
+
+type mockUserSettingRepository struct {
+	settings map[string]map[string]*domain.UserSetting
+}
+
+func newMockUserSettingRepository() *mockUserSettingRepository {
+	return &mockUserSettingRepository{settings: make(map[string]map[string]*domain.UserSetting)}
+}
+
+func (m *mockUserSettingRepository) Set(ctx context.Context, setting *domain.UserSetting) error {
+	if m.settings[setting.UserID] == nil {
+		m.settings[setting.UserID] = make(map[string]*domain.UserSetting)
+	}
+	m.settings[setting.UserID][setting.Key] = setting
+	return nil
+}
+
+func (m *mockUserSettingRepository) ListByUser(ctx context.Context, userID string) ([]*domain.UserSetting, error) {
+	var settings []*domain.UserSetting
+	for _, setting := range m.settings[userID] {
+		settings = append(settings, setting)
+	}
+	return settings, nil
+}
+
+// recordingChannel keeps sent notifications instead of delivering them
+type recordingChannel struct {
+	sent []*Notification
+}
+
+func (c *recordingChannel) Name() string { return "recording" }
+
+func (c *recordingChannel) Send(ctx context.Context, notification *Notification) error {
+	c.sent = append(c.sent, notification)
+	return nil
+}
+
+func setNotificationOptIns(t *testing.T, repo *mockUserSettingRepository, userID string, optIns domain.NotificationOptIns) {
+	t.Helper()
+	value, err := json.Marshal(optIns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo.Set(context.Background(), &domain.UserSetting{UserID: userID, Key: domain.UserSettingKeyNotifications, Value: string(value)})
+}
+
+func TestNotificationService_ImmediateAndUrgent(t *testing.T) {
+	ctx := context.Background()
+	userRepo := &mockUserRepository{users: []*domain.User{
+		{ID: "alex", Email: "alex@example.com"},
+		{ID: "kim", Email: "kim@example.com"},
+	}}
+	settingRepo := newMockUserSettingRepository()
+	setNotificationOptIns(t, settingRepo, "alex", domain.NotificationOptIns{PendingTransactions: true, SecurityAlerts: true, Digest: domain.DigestOff})
+	setNotificationOptIns(t, settingRepo, "kim", domain.NotificationOptIns{
+		PendingTransactions: true,
+		SecurityAlerts:      true,
+		Digest:              domain.DigestDaily,
+		Urgent:              []domain.NotificationKind{domain.NotificationSecurityAlerts},
+	})
+	channel := &recordingChannel{}
+	service := NewNotificationService(&mockAuditRepository{}, userRepo, settingRepo, NewUserPreferenceService(settingRepo), channel)
+
+	service.HandleAuditEntry(ctx, &domain.AuditEntry{Route: "POST /api/integrations/email", Status: 201, Actor: "anonymous"})
+	if len(channel.sent) != 1 || channel.sent[0].To != "alex@example.com" {
+		t.Fatalf("expected only the user without a digest to be notified right away, got %+v", channel.sent)
+	}
+
+	kim := "kim"
+	service.HandleAuditEntry(ctx, &domain.AuditEntry{Route: "POST /api/tokens", Status: 201, UserID: &kim, Actor: "kim@example.com"})
+	if len(channel.sent) != 2 || channel.sent[1].To != "kim@example.com" {
+		t.Fatalf("expected urgent security alert to skip the digest and reach only its user, got %+v", channel.sent)
+	}
+
+	service.HandleAuditEntry(ctx, &domain.AuditEntry{Route: "POST /api/tokens", Status: 400, UserID: &kim})
+	service.HandleAuditEntry(ctx, &domain.AuditEntry{Route: "POST /api/accounts", Status: 201})
+	if len(channel.sent) != 2 {
+		t.Errorf("expected failed and unrelated changes to be ignored, got %d notifications", len(channel.sent))
+	}
+}
+
+func TestNotificationService_SendDigests(t *testing.T) {
+	ctx := context.Background()
+	userRepo := &mockUserRepository{users: []*domain.User{{ID: "kim", Email: "kim@example.com"}}}
+	settingRepo := newMockUserSettingRepository()
+	setNotificationOptIns(t, settingRepo, "kim", domain.NotificationOptIns{
+		PendingTransactions: true,
+		SecurityAlerts:      true,
+		Digest:              domain.DigestHourly,
+		Urgent:              []domain.NotificationKind{domain.NotificationSecurityAlerts},
+	})
+	auditRepo := &mockAuditRepository{}
+	channel := &recordingChannel{}
+	service := NewNotificationService(auditRepo, userRepo, settingRepo, NewUserPreferenceService(settingRepo), channel)
+
+	// The first run only starts the digest period
+	if err := service.SendDigests(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(channel.sent) != 0 {
+		t.Fatalf("expected no digest on the first run, got %d", len(channel.sent))
+	}
+
+	kim := "kim"
+	start := time.Now().Add(-2 * time.Hour)
+	settingRepo.Set(ctx, &domain.UserSetting{UserID: "kim", Key: domain.UserSettingKeyDigestSentAt, Value: start.Format(time.RFC3339Nano)})
+	for _, entry := range []*domain.AuditEntry{
+		{Route: "POST /api/integrations/email", Status: 201, Actor: "anonymous", CreatedAt: time.Now().Add(-30 * time.Minute)},
+		{Route: "POST /api/integrations/email", Status: 201, Actor: "anonymous", CreatedAt: time.Now().Add(-20 * time.Minute)},
+		{Route: "POST /api/tokens", Status: 201, UserID: &kim, Actor: "kim@example.com", CreatedAt: time.Now().Add(-10 * time.Minute)},
+	} {
+		auditRepo.Create(ctx, entry)
+	}
+
+	if err := service.SendDigests(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(channel.sent) != 1 {
+		t.Fatalf("expected one digest, got %d", len(channel.sent))
+	}
+	digest := channel.sent[0]
+	if digest.Subject != "Budget digest: 2 updates" || strings.Contains(digest.Body, "API token") {
+		t.Errorf("expected a digest of the two pending transactions without the urgent alert, got %q\n%s", digest.Subject, digest.Body)
+	}
+
+	// Not due again until another hour has passed
+	if err := service.SendDigests(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(channel.sent) != 1 {
+		t.Errorf("expected no second digest within the hour, got %d", len(channel.sent))
+	}
+}
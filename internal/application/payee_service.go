@@ -0,0 +1,465 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/google/uuid"
+)
+
+// PayeeService handles payees and the rules that assign them to new transactions
+type PayeeService struct {
+	payeeRepo    domain.PayeeRepository
+	ruleRepo     domain.PayeeRuleRepository
+	accountRepo  domain.AccountRepository
+	categoryRepo domain.CategoryRepository
+
+	recategorizer *Recategorizer // nil unless rule changes are applied to past transactions
+}
+
+// NewPayeeService creates a new payee service
+func NewPayeeService(payeeRepo domain.PayeeRepository, ruleRepo domain.PayeeRuleRepository, accountRepo domain.AccountRepository, categoryRepo domain.CategoryRepository) *PayeeService {
+	return &PayeeService{
+		payeeRepo:    payeeRepo,
+		ruleRepo:     ruleRepo,
+		accountRepo:  accountRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+// UseRecategorizer applies created, changed and deleted rules to uncategorized
+// transactions in the background
+func (s *PayeeService) UseRecategorizer(recategorizer *Recategorizer) {
+	s.recategorizer = recategorizer
+}
+
+// ListPayees retrieves all payees
+func (s *PayeeService) ListPayees(ctx context.Context) ([]*domain.Payee, error) {
+	return s.payeeRepo.List(ctx)
+}
+
+// CreateRule adds a rule that assigns a payee, category and/or memo to matching transactions
+// The payee is looked up by name and created if it doesn't exist yet. A zero amount bound
+// is no bound.
+func (s *PayeeService) CreateRule(ctx context.Context, pattern string, matchType domain.PayeeMatchType, accountID *string, minAmount, maxAmount *int64, payeeName string, categoryID, note *string, priority int) (*domain.PayeeRule, error) {
+	if matchType == "" {
+		matchType = domain.PayeeMatchContains
+	}
+	rule := &domain.PayeeRule{
+		ID:         uuid.New().String(),
+		Pattern:    strings.TrimSpace(pattern),
+		MatchType:  matchType,
+		AccountID:  emptyToNil(accountID),
+		MinAmount:  zeroToNil(minAmount),
+		MaxAmount:  zeroToNil(maxAmount),
+		CategoryID: emptyToNil(categoryID),
+		Note:       trimmedNote(note),
+		Priority:   priority,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	payeeName = strings.Join(strings.Fields(payeeName), " ")
+	if err := s.validateRule(ctx, rule, payeeName != ""); err != nil {
+		return nil, err
+	}
+	if err := s.setRulePayee(ctx, rule, payeeName); err != nil {
+		return nil, err
+	}
+
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+	s.recategorize(rule)
+	return rule, nil
+}
+
+// GetRule retrieves a payee rule by ID
+func (s *PayeeService) GetRule(ctx context.Context, id string) (*domain.PayeeRule, error) {
+	return s.ruleRepo.GetByID(ctx, id)
+}
+
+// ListRules retrieves all payee rules in the order they're tried
+func (s *PayeeService) ListRules(ctx context.Context) ([]*domain.PayeeRule, error) {
+	return s.ruleRepo.List(ctx)
+}
+
+// UpdateRule changes a payee rule
+// Nil fields are left alone; an empty account ID, payee name, category ID or note, or a
+// zero amount bound, removes it from the rule.
+func (s *PayeeService) UpdateRule(ctx context.Context, id string, pattern *string, matchType domain.PayeeMatchType, accountID *string, minAmount, maxAmount *int64, payeeName, categoryID, note *string, priority *int) (*domain.PayeeRule, error) {
+	rule, err := s.ruleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *rule
+
+	if pattern != nil {
+		rule.Pattern = strings.TrimSpace(*pattern)
+	}
+	if matchType != "" {
+		rule.MatchType = matchType
+	}
+	if accountID != nil {
+		rule.AccountID = emptyToNil(accountID)
+	}
+	if minAmount != nil {
+		rule.MinAmount = zeroToNil(minAmount)
+	}
+	if maxAmount != nil {
+		rule.MaxAmount = zeroToNil(maxAmount)
+	}
+	if categoryID != nil {
+		rule.CategoryID = emptyToNil(categoryID)
+	}
+	if note != nil {
+		rule.Note = trimmedNote(note)
+	}
+	if priority != nil {
+		rule.Priority = *priority
+	}
+	hasPayee := rule.PayeeID != nil
+	if payeeName != nil {
+		*payeeName = strings.Join(strings.Fields(*payeeName), " ")
+		hasPayee = *payeeName != ""
+	}
+	if err := s.validateRule(ctx, rule, hasPayee); err != nil {
+		return nil, err
+	}
+	if payeeName != nil {
+		if err := s.setRulePayee(ctx, rule, *payeeName); err != nil {
+			return nil, err
+		}
+	}
+	rule.UpdatedAt = time.Now()
+
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		return nil, err
+	}
+	s.recategorize(&before, rule)
+	return rule, nil
+}
+
+// DeleteRule removes a payee rule; transactions it already categorized are untouched
+// Uncategorized transactions it matched are tried against the remaining rules, since it
+// may have been hiding a lower-priority rule that gives a category.
+func (s *PayeeService) DeleteRule(ctx context.Context, id string) error {
+	rule, err := s.ruleRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.ruleRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.recategorize(rule)
+	return nil
+}
+
+// RunRules queues a job applying every rule to the uncategorized transactions it matches
+// Returns the job's ID.
+func (s *PayeeService) RunRules(ctx context.Context) (string, error) {
+	if s.recategorizer == nil {
+		return "", fmt.Errorf("payee rules can't be run against past transactions here")
+	}
+	rules, err := s.ruleRepo.List(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(rules) == 0 {
+		return "", fmt.Errorf("there are no payee rules to run")
+	}
+	return s.recategorizer.Enqueue(rules...), nil
+}
+
+// MatchRule returns the first rule matching a transaction, or nil if none does
+func (s *PayeeService) MatchRule(ctx context.Context, accountID string, amount int64, description string) (*domain.PayeeRule, error) {
+	rules, err := s.ruleRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if ruleMatcher(rule)(accountID, amount, description) {
+			return rule, nil
+		}
+	}
+	return nil, nil
+}
+
+// recategorize queues a job applying the rules to uncategorized transactions the given rules match
+func (s *PayeeService) recategorize(rules ...*domain.PayeeRule) {
+	if s.recategorizer != nil {
+		s.recategorizer.Enqueue(rules...)
+	}
+}
+
+// setRulePayee points the rule at the named payee, creating the payee if needed
+func (s *PayeeService) setRulePayee(ctx context.Context, rule *domain.PayeeRule, payeeName string) error {
+	if payeeName == "" {
+		rule.PayeeID = nil
+		return nil
+	}
+	payee, err := s.findOrCreatePayee(ctx, payeeName)
+	if err != nil {
+		return err
+	}
+	rule.PayeeID = &payee.ID
+	return nil
+}
+
+// validateRule checks a rule before its payee is set, so a bad rule doesn't leave a new payee behind
+func (s *PayeeService) validateRule(ctx context.Context, rule *domain.PayeeRule, hasPayee bool) error {
+	if rule.Pattern == "" && rule.AccountID == nil && rule.MinAmount == nil && rule.MaxAmount == nil {
+		return fmt.Errorf("pattern is required unless the rule has an account or amount condition")
+	}
+	switch rule.MatchType {
+	case domain.PayeeMatchContains, domain.PayeeMatchExact:
+	case domain.PayeeMatchRegex:
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern: %v", err)
+		}
+	default:
+		return fmt.Errorf("match_type must be contains, exact or regex")
+	}
+	if (rule.MinAmount != nil && *rule.MinAmount < 0) || (rule.MaxAmount != nil && *rule.MaxAmount < 0) {
+		return fmt.Errorf("min_amount and max_amount cannot be negative")
+	}
+	if rule.MinAmount != nil && rule.MaxAmount != nil && *rule.MinAmount > *rule.MaxAmount {
+		return fmt.Errorf("min_amount cannot be more than max_amount")
+	}
+	if !hasPayee && rule.CategoryID == nil && rule.Note == nil {
+		return fmt.Errorf("a payee rule needs a payee_name, category_id or note")
+	}
+	if rule.AccountID != nil {
+		if _, err := s.accountRepo.GetByID(ctx, *rule.AccountID); err != nil {
+			return domain.ErrAccountNotFound
+		}
+	}
+	if rule.CategoryID != nil {
+		if _, err := s.categoryRepo.GetByID(ctx, *rule.CategoryID); err != nil {
+			return domain.ErrCategoryNotFound
+		}
+	}
+	return nil
+}
+
+// validateAssignment checks that the category and payee given to a transaction exist
+func (s *PayeeService) validateAssignment(ctx context.Context, categoryID, payeeID *string) error {
+	if categoryID != nil {
+		if _, err := s.categoryRepo.GetByID(ctx, *categoryID); err != nil {
+			return domain.ErrCategoryNotFound
+		}
+	}
+	if payeeID != nil {
+		if _, err := s.payeeRepo.GetByID(ctx, *payeeID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *PayeeService) findOrCreatePayee(ctx context.Context, name string) (*domain.Payee, error) {
+	payee, err := s.payeeRepo.GetByName(ctx, name)
+	if err == nil {
+		return payee, nil
+	}
+	if !errors.Is(err, domain.ErrPayeeNotFound) {
+		return nil, err
+	}
+
+	payee = &domain.Payee{
+		ID:        uuid.New().String(),
+		Name:      name,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := s.payeeRepo.Create(ctx, payee); err != nil {
+		return nil, err
+	}
+	return payee, nil
+}
+
+// payeeMatch is the payee, category and memo picked for one imported transaction
+type payeeMatch struct {
+	PayeeID    *string
+	CategoryID *string // Only set when a rule names a category
+	Note       *string // Only set when a rule gives a memo
+}
+
+// MatchImported picks a payee, and a category and memo where a rule gives them, for each
+// transaction imported into the account
+// The first matching rule wins. Transactions no rule names a payee for get one from their
+// normalized description. Failures are logged and leave the transaction without a payee,
+// so a bad rule never stops an import.
+func (s *PayeeService) MatchImported(ctx context.Context, accountID string, transactions []ImportedTransaction) []payeeMatch {
+	matches := make([]payeeMatch, len(transactions))
+	if len(transactions) == 0 {
+		return matches
+	}
+
+	rules, err := s.ruleRepo.List(ctx)
+	if err != nil {
+		log.Printf("payees: failed to list rules: %v", err)
+	}
+	matchers := make([]transactionMatcher, len(rules))
+	for i, rule := range rules {
+		matchers[i] = ruleMatcher(rule)
+	}
+
+	payeeIDs := make(map[string]*string)
+	for i, txn := range transactions {
+		for j, rule := range rules {
+			if matchers[j](accountID, txn.Amount, txn.Description) {
+				matches[i] = payeeMatch{PayeeID: rule.PayeeID, CategoryID: rule.CategoryID, Note: rule.Note}
+				break
+			}
+		}
+		if matches[i].PayeeID != nil {
+			continue
+		}
+
+		name := normalizePayeeName(txn.Description)
+		if name == "" {
+			continue
+		}
+		key := strings.ToLower(name)
+		if _, ok := payeeIDs[key]; !ok {
+			payee, err := s.findOrCreatePayee(ctx, name)
+			if err != nil {
+				log.Printf("payees: failed to save payee %q: %v", name, err)
+				payeeIDs[key] = nil
+			} else {
+				payeeIDs[key] = &payee.ID
+			}
+		}
+		matches[i].PayeeID = payeeIDs[key]
+	}
+	return matches
+}
+
+// transactionMatcher reports whether a transaction in the account matches a rule
+type transactionMatcher func(accountID string, amount int64, description string) bool
+
+// ruleMatcher returns a function reporting whether a transaction matches the rule
+// A regex that no longer compiles matches nothing.
+func ruleMatcher(rule *domain.PayeeRule) transactionMatcher {
+	matchesDescription := descriptionMatcher(rule)
+	return func(accountID string, amount int64, description string) bool {
+		if rule.AccountID != nil && *rule.AccountID != accountID {
+			return false
+		}
+		if amount < 0 {
+			amount = -amount
+		}
+		if (rule.MinAmount != nil && amount < *rule.MinAmount) || (rule.MaxAmount != nil && amount > *rule.MaxAmount) {
+			return false
+		}
+		return matchesDescription(description)
+	}
+}
+
+// descriptionMatcher returns a function reporting whether a description matches the rule's pattern
+func descriptionMatcher(rule *domain.PayeeRule) func(string) bool {
+	if rule.Pattern == "" {
+		return func(string) bool { return true }
+	}
+	switch rule.MatchType {
+	case domain.PayeeMatchExact:
+		return func(description string) bool {
+			return strings.EqualFold(strings.TrimSpace(description), rule.Pattern)
+		}
+	case domain.PayeeMatchRegex:
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			log.Printf("payees: rule %s: %v", rule.ID, err)
+			return func(string) bool { return false }
+		}
+		return re.MatchString
+	default:
+		pattern := strings.ToLower(rule.Pattern)
+		return func(description string) bool {
+			return strings.Contains(strings.ToLower(description), pattern)
+		}
+	}
+}
+
+// zeroToNil treats a missing or zero amount bound as no bound
+func zeroToNil(amount *int64) *int64 {
+	if amount == nil || *amount == 0 {
+		return nil
+	}
+	return amount
+}
+
+// trimmedNote returns the note without surrounding space, or nil if that leaves nothing
+func trimmedNote(note *string) *string {
+	if note == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*note)
+	return emptyToNil(&trimmed)
+}
+
+// payeeNoisePrefixes are what banks and card processors put in front of the merchant name
+var payeeNoisePrefixes = []string{
+	"POS PURCHASE ", "POS DEBIT ", "POS ", "DEBIT CARD PURCHASE ", "DEBIT PURCHASE ",
+	"CHECKCARD ", "CHECK CARD ", "VISA PURCHASE ", "PURCHASE AUTHORIZED ON ", "PURCHASE ",
+	"ACH DEBIT ", "ACH CREDIT ", "RECURRING PAYMENT ", "PREAUTHORIZED DEBIT ",
+	"SQ *", "SQ*", "TST* ", "TST*", "PAYPAL *", "PP*", "SP * ", "SP *",
+}
+
+var (
+	// Dates and card-processor dates like 01/15 that some banks put before the merchant
+	payeeLeadingDate = regexp.MustCompile(`^\d{1,2}/\d{1,2}(/\d{2,4})?\s+`)
+	// Store numbers, reference numbers and anything after them
+	payeeReference = regexp.MustCompile(`(\s*[#*]|\s+\d{3,}).*$`)
+)
+
+// normalizePayeeName turns a bank description into a tidy payee name
+// "POS PURCHASE SQ *BLUE BOTTLE COFFEE #0412 OAKLAND CA" becomes "Blue Bottle Coffee".
+// Only the part before " - " is used, since imports append the memo after it.
+func normalizePayeeName(description string) string {
+	name, _, _ := strings.Cut(description, " - ")
+	name = strings.Join(strings.Fields(name), " ")
+
+	upper := strings.ToUpper(name)
+	for trimmed := true; trimmed; {
+		trimmed = false
+		if loc := payeeLeadingDate.FindStringIndex(upper); loc != nil {
+			name, upper = name[loc[1]:], upper[loc[1]:]
+			trimmed = true
+		}
+		for _, prefix := range payeeNoisePrefixes {
+			if strings.HasPrefix(upper, prefix) {
+				name, upper = name[len(prefix):], upper[len(prefix):]
+				trimmed = true
+				break
+			}
+		}
+	}
+
+	if cleaned := payeeReference.ReplaceAllString(name, ""); cleaned != "" {
+		name = cleaned
+	}
+	name = strings.Trim(name, " -*#.,")
+	if name == "" || name == "Unknown Transaction" {
+		return ""
+	}
+
+	// Banks shout; turn all-caps names into title case and leave mixed case alone
+	if name == strings.ToUpper(name) {
+		words := strings.Fields(strings.ToLower(name))
+		for i, word := range words {
+			first, size := utf8.DecodeRuneInString(word)
+			words[i] = string(unicode.ToUpper(first)) + word[size:]
+		}
+		name = strings.Join(words, " ")
+	}
+	return name
+}
@@ -0,0 +1,191 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type mockPayeeRepository struct {
+	payees map[string]*domain.Payee
+}
+
+func newMockPayeeRepository() *mockPayeeRepository {
+	return &mockPayeeRepository{payees: make(map[string]*domain.Payee)}
+}
+
+func (m *mockPayeeRepository) Create(ctx context.Context, payee *domain.Payee) error {
+	m.payees[payee.ID] = payee
+	return nil
+}
+
+func (m *mockPayeeRepository) GetByID(ctx context.Context, id string) (*domain.Payee, error) {
+	if payee, ok := m.payees[id]; ok {
+		return payee, nil
+	}
+	return nil, domain.ErrPayeeNotFound
+}
+
+func (m *mockPayeeRepository) GetByName(ctx context.Context, name string) (*domain.Payee, error) {
+	for _, payee := range m.payees {
+		if strings.EqualFold(payee.Name, name) {
+			return payee, nil
+		}
+	}
+	return nil, domain.ErrPayeeNotFound
+}
+
+func (m *mockPayeeRepository) List(ctx context.Context) ([]*domain.Payee, error) {
+	var payees []*domain.Payee
+	for _, payee := range m.payees {
+		payees = append(payees, payee)
+	}
+	return payees, nil
+}
+
+type mockPayeeRuleRepository struct {
+	rules map[string]*domain.PayeeRule
+}
+
+func newMockPayeeRuleRepository() *mockPayeeRuleRepository {
+	return &mockPayeeRuleRepository{rules: make(map[string]*domain.PayeeRule)}
+}
+
+func (m *mockPayeeRuleRepository) Create(ctx context.Context, rule *domain.PayeeRule) error {
+	m.rules[rule.ID] = rule
+	return nil
+}
+
+func (m *mockPayeeRuleRepository) GetByID(ctx context.Context, id string) (*domain.PayeeRule, error) {
+	if rule, ok := m.rules[id]; ok {
+		return rule, nil
+	}
+	return nil, domain.ErrPayeeRuleNotFound
+}
+
+func (m *mockPayeeRuleRepository) List(ctx context.Context) ([]*domain.PayeeRule, error) {
+	var rules []*domain.PayeeRule
+	for _, rule := range m.rules {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Priority < rules[j].Priority })
+	return rules, nil
+}
+
+func (m *mockPayeeRuleRepository) Update(ctx context.Context, rule *domain.PayeeRule) error {
+	if _, ok := m.rules[rule.ID]; !ok {
+		return domain.ErrPayeeRuleNotFound
+	}
+	m.rules[rule.ID] = rule
+	return nil
+}
+
+func (m *mockPayeeRuleRepository) Delete(ctx context.Context, id string) error {
+	if _, ok := m.rules[id]; !ok {
+		return domain.ErrPayeeRuleNotFound
+	}
+	delete(m.rules, id)
+	return nil
+}
+
+func TestNormalizePayeeName(t *testing.T) {
+	tests := []struct {
+		description string
+		want        string
+	}{
+		{"POS PURCHASE SQ *BLUE BOTTLE COFFEE #0412 OAKLAND CA", "Blue Bottle Coffee"},
+		{"AMZN MKTP US*2K3LL9QW2", "Amzn Mktp Us"},
+		{"SHELL OIL 57442156", "Shell Oil"},
+		{"DEBIT CARD PURCHASE 01/15 TRADER JOE'S #552", "Trader Joe's"},
+		{"TST* Joe's Pizza", "Joe's Pizza"},
+		{"Netflix.com - Monthly subscription", "Netflix.com"},
+		{"24 HOUR FITNESS", "24 Hour Fitness"},
+		{"CAFÉ ÉCLAIR", "Café Éclair"},
+		{"Unknown Transaction", ""},
+		{"#1234", "1234"},
+	}
+	for _, tt := range tests {
+		if got := normalizePayeeName(tt.description); got != tt.want {
+			t.Errorf("normalizePayeeName(%q) = %q, want %q", tt.description, got, tt.want)
+		}
+	}
+}
+
+func TestPayeeService_MatchImported(t *testing.T) {
+	ctx := context.Background()
+	categoryRepo := newMockCategoryRepository()
+	categoryRepo.categories["coffee"] = &domain.Category{ID: "coffee", Name: "Coffee"}
+	categoryRepo.categories["fuel"] = &domain.Category{ID: "fuel", Name: "Fuel"}
+	payeeRepo := newMockPayeeRepository()
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking}
+	service := NewPayeeService(payeeRepo, newMockPayeeRuleRepository(), accountRepo, categoryRepo)
+
+	coffee := "coffee"
+	starbucks, err := service.CreateRule(ctx, "starbucks", "", nil, nil, nil, "Starbucks", &coffee, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fuel := "fuel"
+	if _, err := service.CreateRule(ctx, `^(SHELL|CHEVRON)\b`, domain.PayeeMatchRegex, nil, nil, nil, "", &fuel, nil, 1); err != nil {
+		t.Fatal(err)
+	}
+	// Big transfers out of checking get a memo, whatever they're called
+	checking, bigTransfer, memo := "checking", int64(100000), " Check with the landlord "
+	if _, err := service.CreateRule(ctx, "", "", &checking, &bigTransfer, nil, "", nil, &memo, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.CreateRule(ctx, "anything", "", nil, nil, nil, "", nil, nil, 0); err == nil {
+		t.Error("expected a rule with no payee, category or note to be rejected")
+	}
+	if _, err := service.CreateRule(ctx, "", "", nil, nil, nil, "", &coffee, nil, 0); err == nil {
+		t.Error("expected a rule with no conditions to be rejected")
+	}
+	if _, err := service.CreateRule(ctx, "(", domain.PayeeMatchRegex, nil, nil, nil, "Broken", nil, nil, 0); err == nil {
+		t.Error("expected an invalid regex to be rejected")
+	}
+	low, high := int64(500), int64(100)
+	if _, err := service.CreateRule(ctx, "cafe", "", nil, &low, &high, "", &coffee, nil, 0); err == nil {
+		t.Error("expected a min_amount above max_amount to be rejected")
+	}
+	missing := "missing"
+	if _, err := service.CreateRule(ctx, "cafe", "", &missing, nil, nil, "", &coffee, nil, 0); err == nil {
+		t.Error("expected a rule for a missing account to be rejected")
+	}
+
+	matches := service.MatchImported(ctx, "checking", []ImportedTransaction{
+		{Description: "STARBUCKS STORE 00123 SEATTLE WA", Amount: -450},
+		{Description: "SHELL OIL 57442156", Amount: -4000},
+		{Description: "TRADER JOE'S #552", Amount: -6000},
+		{Description: "Trader Joe's #101", Amount: -2500},
+		{Description: "ONLINE TRANSFER TO LANDLORD", Amount: -150000},
+	})
+
+	if matches[0].PayeeID == nil || *matches[0].PayeeID != *starbucks.PayeeID || matches[0].CategoryID == nil || *matches[0].CategoryID != "coffee" {
+		t.Errorf("expected the Starbucks rule to set payee and category, got %+v", matches[0])
+	}
+	if matches[1].CategoryID == nil || *matches[1].CategoryID != "fuel" {
+		t.Errorf("expected the regex rule to categorize fuel, got %+v", matches[1])
+	}
+	if matches[1].PayeeID == nil || payeeRepo.payees[*matches[1].PayeeID].Name != "Shell Oil" {
+		t.Errorf("expected a category-only rule to leave the normalized payee, got %+v", matches[1])
+	}
+	if matches[2].CategoryID != nil || matches[2].PayeeID == nil || matches[3].PayeeID == nil || *matches[2].PayeeID != *matches[3].PayeeID {
+		t.Errorf("expected both Trader Joe's transactions to share one uncategorized payee, got %+v and %+v", matches[2], matches[3])
+	}
+	if matches[4].Note == nil || *matches[4].Note != "Check with the landlord" || matches[4].CategoryID != nil {
+		t.Errorf("expected the account and amount rule to add its memo, got %+v", matches[4])
+	}
+	if matches[0].Note != nil || matches[2].Note != nil {
+		t.Errorf("expected only the landlord transfer to get a memo, got %+v and %+v", matches[0], matches[2])
+	}
+	if elsewhere := service.MatchImported(ctx, "savings", []ImportedTransaction{{Description: "ONLINE TRANSFER TO LANDLORD", Amount: -150000}}); elsewhere[0].Note != nil {
+		t.Errorf("expected the account condition to keep the memo off other accounts, got %+v", elsewhere[0])
+	}
+	if len(payeeRepo.payees) != 4 {
+		t.Errorf("expected 4 payees, got %d", len(payeeRepo.payees))
+	}
+}
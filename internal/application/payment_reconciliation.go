@@ -0,0 +1,127 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// CardPaymentReconciliation ties a credit card's statement to its payment and its payment category
+// Three numbers should agree: what the statement says is owed, what was paid to the card
+// this period, and what the payment category has set aside. Each gap is reported on its own.
+type CardPaymentReconciliation struct {
+	AccountID         string `json:"account_id"`
+	PaymentCategoryID string `json:"payment_category_id"`
+	Period            string `json:"period"`
+
+	StatementBalance         int64 `json:"statement_balance"` // Owed per the statement (positive)
+	CardBalance              int64 `json:"card_balance"`      // Owed now (positive)
+	Payments                 int64 `json:"payments"`          // Transfers to the card this period
+	LinkedPayments           int64 `json:"linked_payments"`   // The part paid from the payment category
+	PaymentCategoryAvailable int64 `json:"payment_category_available"`
+
+	// UnlinkedPaymentIDs are the outgoing sides of payments that aren't categorized with
+	// the payment category, so the budget never recorded them as paying the card
+	UnlinkedPaymentIDs []string `json:"unlinked_payment_ids"`
+
+	UnpaidStatement int64 `json:"unpaid_statement"` // Statement balance not yet paid this period
+	UnlinkedAmount  int64 `json:"unlinked_amount"`  // Payments made outside the payment category
+	Underfunded     int64 `json:"underfunded"`      // More the payment category needs once payments are linked
+	Matched         bool  `json:"matched"`          // No gaps
+}
+
+// ReconcileCardPayment compares a credit card's statement balance, its payments and its payment category
+// statementBalance is the amount owed on the statement as a positive number; nil uses the
+// card's current balance.
+func (s *CreditCardService) ReconcileCardPayment(ctx context.Context, accountID, period string, statementBalance *int64) (*CardPaymentReconciliation, error) {
+	start, err := time.Parse("2006-01", period)
+	if err != nil {
+		return nil, fmt.Errorf("invalid period format, expected YYYY-MM")
+	}
+
+	account, err := s.allocationService.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, domain.ErrAccountNotFound
+	}
+	if account.Type != domain.AccountTypeCredit {
+		return nil, domain.ErrNotCreditAccount
+	}
+	paymentCategory, err := s.allocationService.categoryRepo.GetPaymentCategoryByAccountID(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment category: %w", err)
+	}
+
+	reconciliation := &CardPaymentReconciliation{
+		AccountID:          accountID,
+		PaymentCategoryID:  paymentCategory.ID,
+		Period:             period,
+		CardBalance:        -account.Balance,
+		StatementBalance:   -account.Balance,
+		UnlinkedPaymentIDs: []string{},
+	}
+	if statementBalance != nil {
+		reconciliation.StatementBalance = *statementBalance
+	}
+
+	// Payments are the outgoing sides of transfers to the card
+	end := start.AddDate(0, 1, 0).Add(-time.Second)
+	transactions, err := s.allocationService.transactionRepo.ListByPeriod(ctx, start.Format(time.RFC3339), end.Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+	for _, txn := range transactions {
+		if txn.Type != domain.TransactionTypeTransfer || txn.Amount >= 0 ||
+			txn.TransferToAccountID == nil || *txn.TransferToAccountID != accountID {
+			continue
+		}
+		reconciliation.Payments += -txn.Amount
+		if txn.CategoryID != nil && *txn.CategoryID == paymentCategory.ID {
+			reconciliation.LinkedPayments += -txn.Amount
+		} else {
+			reconciliation.UnlinkedPaymentIDs = append(reconciliation.UnlinkedPaymentIDs, txn.ID)
+		}
+	}
+
+	summary, err := s.allocationService.summarizeCategory(ctx, paymentCategory, period, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate payment category summary: %w", err)
+	}
+	reconciliation.PaymentCategoryAvailable = summary.Available
+
+	reconciliation.UnpaidStatement = max(0, reconciliation.StatementBalance-reconciliation.Payments)
+	reconciliation.UnlinkedAmount = reconciliation.Payments - reconciliation.LinkedPayments
+	// Linking the unlinked payments spends them from the category too
+	reconciliation.Underfunded = max(0, reconciliation.UnpaidStatement+reconciliation.UnlinkedAmount-summary.Available)
+	reconciliation.Matched = reconciliation.UnpaidStatement == 0 && reconciliation.UnlinkedAmount == 0 && reconciliation.Underfunded == 0
+
+	return reconciliation, nil
+}
+
+// FixCardPayment closes the gaps ReconcileCardPayment finds where it can
+// Unlinked payments are categorized with the payment category, then an underfunded
+// payment category is covered from Ready to Assign as with cover-underfunded. An unpaid
+// statement is left alone - that takes an actual payment. Returns the reconciliation afterwards.
+func (s *CreditCardService) FixCardPayment(ctx context.Context, accountID, period string, statementBalance *int64) (*CardPaymentReconciliation, error) {
+	reconciliation, err := s.ReconcileCardPayment(ctx, accountID, period, statementBalance)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reconciliation.UnlinkedPaymentIDs) > 0 {
+		paymentCategoryID := reconciliation.PaymentCategoryID
+		if err := s.allocationService.transactionRepo.BulkUpdateCategory(ctx, reconciliation.UnlinkedPaymentIDs, &paymentCategoryID); err != nil {
+			return nil, fmt.Errorf("failed to link payments: %w", err)
+		}
+	}
+
+	if reconciliation.Underfunded > 0 {
+		if _, err := s.CoverUnderfunded(ctx, reconciliation.PaymentCategoryID, period); err != nil && !errors.Is(err, domain.ErrNotUnderfunded) {
+			return nil, err
+		}
+	}
+
+	return s.ReconcileCardPayment(ctx, accountID, period, statementBalance)
+}
@@ -0,0 +1,87 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestCreditCardService_ReconcileCardPayment(t *testing.T) {
+	ctx := context.Background()
+	visaID, groceriesID, paymentID := "visa", "groceries", "visa-payment"
+
+	categoryRepo := newMockCategoryRepository()
+	categoryRepo.categories[groceriesID] = &domain.Category{ID: groceriesID, Name: "Groceries"}
+	categoryRepo.categories[paymentID] = &domain.Category{ID: paymentID, Name: "Visa Payment", PaymentForAccountID: &visaID}
+
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Type: domain.AccountTypeChecking, Balance: 192000}
+	accountRepo.accounts[visaID] = &domain.Account{ID: visaID, Type: domain.AccountTypeCredit, Balance: -20000}
+
+	allocationRepo := newMockAllocationRepository()
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a1", CategoryID: paymentID, Period: "2025-10", Amount: 5000})
+
+	october := time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)
+	transactionRepo := newMockTransactionRepository()
+	transactionRepo.transactions = []*domain.Transaction{
+		{ID: "income", AccountID: "checking", Type: domain.TransactionTypeNormal, Amount: 200000, Date: october},
+		// A payment recorded without the payment category
+		{ID: "pay-out", AccountID: "checking", Type: domain.TransactionTypeTransfer, TransferToAccountID: &visaID, Amount: -8000, Date: october},
+		{ID: "pay-in", AccountID: visaID, Type: domain.TransactionTypeTransfer, Amount: 8000, Date: october},
+	}
+
+	service := NewAllocationService(allocationRepo, categoryRepo, transactionRepo, newMockBudgetStateRepository(0, 0), accountRepo, newMockCategoryGroupRepository(), newMockGoalRepository())
+	moveRepo := newMockCategoryMoveRepository()
+	service.UseCategoryMoves(moveRepo)
+	creditCards := NewCreditCardService(moveRepo, service, &mockUnitOfWork{accountRepo, transactionRepo})
+	statement := int64(15000)
+
+	t.Run("reports each gap", func(t *testing.T) {
+		reconciliation, err := creditCards.ReconcileCardPayment(ctx, visaID, "2025-10", &statement)
+		if err != nil {
+			t.Fatalf("ReconcileCardPayment() unexpected error = %v", err)
+		}
+		if reconciliation.Payments != 8000 || reconciliation.LinkedPayments != 0 {
+			t.Errorf("payments = %d (linked %d), want 8000 (linked 0)", reconciliation.Payments, reconciliation.LinkedPayments)
+		}
+		if len(reconciliation.UnlinkedPaymentIDs) != 1 || reconciliation.UnlinkedPaymentIDs[0] != "pay-out" {
+			t.Errorf("UnlinkedPaymentIDs = %v, want [pay-out]", reconciliation.UnlinkedPaymentIDs)
+		}
+		if reconciliation.UnpaidStatement != 7000 {
+			t.Errorf("UnpaidStatement = %d, want 7000", reconciliation.UnpaidStatement)
+		}
+		// $70 still to pay plus $80 to link, against $50 set aside
+		if reconciliation.Underfunded != 10000 {
+			t.Errorf("Underfunded = %d, want 10000", reconciliation.Underfunded)
+		}
+		if reconciliation.Matched {
+			t.Error("Matched = true, want false")
+		}
+	})
+
+	t.Run("fix links payments and funds the category", func(t *testing.T) {
+		reconciliation, err := creditCards.FixCardPayment(ctx, visaID, "2025-10", &statement)
+		if err != nil {
+			t.Fatalf("FixCardPayment() unexpected error = %v", err)
+		}
+		if len(reconciliation.UnlinkedPaymentIDs) != 0 || reconciliation.LinkedPayments != 8000 {
+			t.Errorf("after fix: unlinked %v, linked %d; want none unlinked, 8000 linked",
+				reconciliation.UnlinkedPaymentIDs, reconciliation.LinkedPayments)
+		}
+		if reconciliation.Underfunded != 0 {
+			t.Errorf("after fix: Underfunded = %d, want 0", reconciliation.Underfunded)
+		}
+		// Only a real payment closes the statement
+		if reconciliation.UnpaidStatement != 7000 || reconciliation.Matched {
+			t.Errorf("after fix: UnpaidStatement = %d, Matched = %v; want 7000, false", reconciliation.UnpaidStatement, reconciliation.Matched)
+		}
+	})
+
+	t.Run("rejects accounts that aren't credit cards", func(t *testing.T) {
+		if _, err := creditCards.ReconcileCardPayment(ctx, "checking", "2025-10", nil); err != domain.ErrNotCreditAccount {
+			t.Errorf("ReconcileCardPayment() error = %v, want %v", err, domain.ErrNotCreditAccount)
+		}
+	})
+}
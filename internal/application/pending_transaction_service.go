@@ -0,0 +1,155 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/billybbuffum/budget/internal/infrastructure/email"
+	"github.com/google/uuid"
+)
+
+// PendingTransactionService handles transactions captured from external sources
+// that wait for user approval before becoming real transactions
+type PendingTransactionService struct {
+	pendingRepo        domain.PendingTransactionRepository
+	accountRepo        domain.AccountRepository
+	transactionService *TransactionService
+	emailParser        *email.Parser
+}
+
+// NewPendingTransactionService creates a new pending transaction service
+func NewPendingTransactionService(
+	pendingRepo domain.PendingTransactionRepository,
+	accountRepo domain.AccountRepository,
+	transactionService *TransactionService,
+	emailParser *email.Parser,
+) *PendingTransactionService {
+	return &PendingTransactionService{
+		pendingRepo:        pendingRepo,
+		accountRepo:        accountRepo,
+		transactionService: transactionService,
+		emailParser:        emailParser,
+	}
+}
+
+// InboundEmail is a message delivered by the mail webhook
+type InboundEmail struct {
+	From       string
+	Subject    string
+	Body       string
+	ReceivedAt time.Time
+}
+
+// IngestEmail parses a forwarded bank alert into a pending transaction
+// accountID is optional; when empty the account is chosen at approval time
+func (s *PendingTransactionService) IngestEmail(ctx context.Context, msg *InboundEmail, accountID *string) (*domain.PendingTransaction, error) {
+	if accountID != nil && *accountID != "" {
+		if _, err := s.accountRepo.GetByID(ctx, *accountID); err != nil {
+			return nil, fmt.Errorf("account not found: %w", err)
+		}
+	} else {
+		accountID = nil
+	}
+
+	receivedAt := msg.ReceivedAt
+	if receivedAt.IsZero() {
+		receivedAt = time.Now()
+	}
+
+	alert, err := s.emailParser.Parse(msg.Subject, msg.Body, receivedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse email: %w", err)
+	}
+
+	description := alert.Merchant
+	if description == "" {
+		description = msg.Subject
+	}
+
+	now := time.Now()
+	pending := &domain.PendingTransaction{
+		ID:          uuid.New().String(),
+		Source:      "email",
+		AccountID:   accountID,
+		Amount:      alert.Amount,
+		Description: description,
+		Date:        alert.Date,
+		Sender:      msg.From,
+		Subject:     msg.Subject,
+		RawBody:     msg.Body,
+		Status:      domain.PendingStatusPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.pendingRepo.Create(ctx, pending); err != nil {
+		return nil, err
+	}
+
+	return pending, nil
+}
+
+// GetPendingTransaction retrieves a pending transaction by ID
+func (s *PendingTransactionService) GetPendingTransaction(ctx context.Context, id string) (*domain.PendingTransaction, error) {
+	return s.pendingRepo.GetByID(ctx, id)
+}
+
+// ListPendingTransactions lists pending transactions, optionally filtered by status
+func (s *PendingTransactionService) ListPendingTransactions(ctx context.Context, status domain.PendingTransactionStatus) ([]*domain.PendingTransaction, error) {
+	return s.pendingRepo.List(ctx, status)
+}
+
+// ApprovePendingTransaction turns a pending transaction into a real one
+// accountID overrides the suggested account; categoryID is required for outflows
+func (s *PendingTransactionService) ApprovePendingTransaction(ctx context.Context, id string, accountID, categoryID *string) (*domain.Transaction, error) {
+	pending, err := s.pendingRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if pending.Status != domain.PendingStatusPending {
+		return nil, fmt.Errorf("pending transaction is already %s", pending.Status)
+	}
+
+	if accountID == nil || *accountID == "" {
+		accountID = pending.AccountID
+	}
+	if accountID == nil {
+		return nil, fmt.Errorf("account is required to approve this transaction")
+	}
+
+	transaction, err := s.transactionService.CreateTransaction(ctx, *accountID, categoryID, pending.Amount, pending.Description, pending.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	pending.AccountID = accountID
+	pending.Status = domain.PendingStatusApproved
+	pending.TransactionID = &transaction.ID
+	pending.UpdatedAt = time.Now()
+	if err := s.pendingRepo.Update(ctx, pending); err != nil {
+		return nil, fmt.Errorf("transaction created but failed to mark pending transaction approved: %w", err)
+	}
+
+	return transaction, nil
+}
+
+// RejectPendingTransaction discards a pending transaction without creating anything
+func (s *PendingTransactionService) RejectPendingTransaction(ctx context.Context, id string) (*domain.PendingTransaction, error) {
+	pending, err := s.pendingRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if pending.Status != domain.PendingStatusPending {
+		return nil, fmt.Errorf("pending transaction is already %s", pending.Status)
+	}
+
+	pending.Status = domain.PendingStatusRejected
+	pending.UpdatedAt = time.Now()
+	if err := s.pendingRepo.Update(ctx, pending); err != nil {
+		return nil, err
+	}
+
+	return pending, nil
+}
@@ -0,0 +1,92 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// PeriodCloseCategory is what happens to one category when its month closes
+type PeriodCloseCategory struct {
+	Category           *domain.Category `json:"category"`
+	Available          int64            `json:"available"`           // At the end of the period
+	RollsOver          int64            `json:"rolls_over"`          // Carried into the next period
+	CashOverspending   int64            `json:"cash_overspending"`   // Reset, and taken from next period's Ready to Assign
+	CreditOverspending int64            `json:"credit_overspending"` // Reset, and left on the card as debt
+	Overspent          bool             `json:"overspent"`
+}
+
+// PeriodClosePreview is a dry run of moving from one budget month to the next
+// Nothing is written; the numbers are what the next period will show if nothing changes.
+type PeriodClosePreview struct {
+	Period                  string                 `json:"period"`
+	NextPeriod              string                 `json:"next_period"`
+	ReadyToAssign           int64                  `json:"ready_to_assign"`             // For the period being closed
+	ReadyToAssignNextPeriod int64                  `json:"ready_to_assign_next_period"` // After this period's cash overspending is taken out
+	TotalRollsOver          int64                  `json:"total_rolls_over"`
+	TotalCashOverspending   int64                  `json:"total_cash_overspending"`
+	TotalCreditOverspending int64                  `json:"total_credit_overspending"`
+	OverspentCount          int                    `json:"overspent_count"`
+	Categories              []*PeriodCloseCategory `json:"categories"`
+}
+
+// PreviewPeriodClose shows what closing a period would roll over, reset and leave in Ready to Assign
+// Positive balances roll over. Overspent categories start the next period at zero: the cash
+// part of their overspending comes out of next period's Ready to Assign, and the credit part
+// stays on the card for its payment category to cover.
+func (s *AllocationService) PreviewPeriodClose(ctx context.Context, period string) (*PeriodClosePreview, error) {
+	start, err := time.Parse("2006-01", period)
+	if err != nil {
+		return nil, fmt.Errorf("invalid period format, expected YYYY-MM")
+	}
+	nextPeriod := start.AddDate(0, 1, 0).Format("2006-01")
+
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ledger, err := s.loadCategoryLedger(ctx, domain.PeriodMonth)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &PeriodClosePreview{
+		Period:     period,
+		NextPeriod: nextPeriod,
+		Categories: make([]*PeriodCloseCategory, 0, len(categories)),
+	}
+	if preview.ReadyToAssign, err = s.CalculateReadyToAssignForPeriod(ctx, period); err != nil {
+		return nil, err
+	}
+
+	for _, category := range categories {
+		isPayment := category.PaymentForAccountID != nil && *category.PaymentForAccountID != ""
+		rolled := ledger.roll(category.ID, period, isPayment, category.OverspendingMode)
+
+		closing := &PeriodCloseCategory{
+			Category:  category,
+			Available: rolled.available,
+		}
+		if rolled.available >= 0 || isPayment {
+			closing.RollsOver = rolled.available
+		} else {
+			closing.Overspent = true
+			closing.CashOverspending, closing.CreditOverspending = splitOverspending(-rolled.available, rolled.creditSpent, category.OverspendingMode)
+			preview.OverspentCount++
+		}
+
+		preview.TotalRollsOver += closing.RollsOver
+		preview.TotalCashOverspending += closing.CashOverspending
+		preview.TotalCreditOverspending += closing.CreditOverspending
+		preview.Categories = append(preview.Categories, closing)
+	}
+
+	// Includes any income already recorded and money already assigned in the next period
+	if preview.ReadyToAssignNextPeriod, err = s.CalculateReadyToAssignForPeriod(ctx, nextPeriod); err != nil {
+		return nil, err
+	}
+
+	return preview, nil
+}
@@ -0,0 +1,70 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestAllocationService_PreviewPeriodClose(t *testing.T) {
+	ctx := context.Background()
+	groceriesID, diningID, rentID := "groceries", "dining", "rent"
+
+	categoryRepo := newMockCategoryRepository()
+	categoryRepo.categories[groceriesID] = &domain.Category{ID: groceriesID, Name: "Groceries"}
+	categoryRepo.categories[diningID] = &domain.Category{ID: diningID, Name: "Dining Out"}
+	categoryRepo.categories[rentID] = &domain.Category{ID: rentID, Name: "Rent"}
+
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Type: domain.AccountTypeChecking}
+	accountRepo.accounts["visa"] = &domain.Account{ID: "visa", Type: domain.AccountTypeCredit}
+
+	allocationRepo := newMockAllocationRepository()
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a1", CategoryID: groceriesID, Period: "2025-10", Amount: 10000})
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a2", CategoryID: diningID, Period: "2025-10", Amount: 5000})
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a3", CategoryID: rentID, Period: "2025-10", Amount: 100000})
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a4", CategoryID: rentID, Period: "2025-11", Amount: 20000})
+
+	october := time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)
+	transactionRepo := newMockTransactionRepository()
+	transactionRepo.transactions = []*domain.Transaction{
+		{ID: "income", AccountID: "checking", Amount: 200000, Date: october},
+		{ID: "t1", AccountID: "checking", CategoryID: &groceriesID, Amount: -13000, Date: october},
+		{ID: "t2", AccountID: "checking", CategoryID: &diningID, Amount: -2000, Date: october},
+		{ID: "t3", AccountID: "visa", CategoryID: &diningID, Amount: -7000, Date: october},
+		{ID: "t4", AccountID: "checking", CategoryID: &rentID, Amount: -90000, Date: october},
+	}
+
+	service := NewAllocationService(allocationRepo, categoryRepo, transactionRepo, newMockBudgetStateRepository(0, 0), accountRepo, newMockCategoryGroupRepository(), newMockGoalRepository())
+
+	preview, err := service.PreviewPeriodClose(ctx, "2025-10")
+	if err != nil {
+		t.Fatalf("PreviewPeriodClose() unexpected error = %v", err)
+	}
+
+	if preview.NextPeriod != "2025-11" {
+		t.Errorf("NextPeriod = %s, want 2025-11", preview.NextPeriod)
+	}
+	if preview.ReadyToAssign != 85000 {
+		t.Errorf("ReadyToAssign = %d, want 85000", preview.ReadyToAssign)
+	}
+	// $30 of groceries overspending was cash; November already has $200 assigned to rent
+	if preview.ReadyToAssignNextPeriod != 62000 {
+		t.Errorf("ReadyToAssignNextPeriod = %d, want 62000", preview.ReadyToAssignNextPeriod)
+	}
+	if preview.TotalRollsOver != 10000 || preview.TotalCashOverspending != 3000 || preview.TotalCreditOverspending != 4000 {
+		t.Errorf("totals = rolls over %d, cash %d, credit %d; want 10000, 3000, 4000",
+			preview.TotalRollsOver, preview.TotalCashOverspending, preview.TotalCreditOverspending)
+	}
+	if preview.OverspentCount != 2 {
+		t.Errorf("OverspentCount = %d, want 2", preview.OverspentCount)
+	}
+
+	for _, closing := range preview.Categories {
+		if closing.Category.ID == diningID && (!closing.Overspent || closing.CreditOverspending != 4000 || closing.CashOverspending != 0) {
+			t.Errorf("Dining Out = %+v, want $40 of credit overspending", closing)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// ErrPluginReportNotFound is returned when no plugin provides the requested report
+var ErrPluginReportNotFound = errors.New("plugin report not found")
+
+// Plugin hooks, as advertised by plugins
+const (
+	PluginHookImportTransform = "import_transform"
+	PluginHookCategorize      = "categorize"
+	PluginHookReports         = "reports"
+)
+
+// ImportedTransaction is a transaction read from an import file, before it is saved
+type ImportedTransaction struct {
+	Date        time.Time `json:"date"`
+	Amount      int64     `json:"amount"` // In cents
+	Description string    `json:"description"`
+	FitID       string    `json:"fitid"` // Used to skip transactions that were already imported
+
+	// What the bank charged, for transactions it converted from another currency
+	OriginalCurrency string `json:"original_currency,omitempty"` // ISO 4217 code
+	OriginalAmount   int64  `json:"original_amount,omitempty"`   // In cents of OriginalCurrency
+}
+
+// original returns the transaction's original currency and amount, or nils if it has none
+func (t ImportedTransaction) original() (*string, *int64) {
+	if t.OriginalCurrency == "" {
+		return nil, nil
+	}
+	currency, amount := t.OriginalCurrency, t.OriginalAmount
+	return &currency, &amount
+}
+
+// ImportTransformer rewrites imported transactions before they are saved
+// It may change, drop or add transactions, e.g. to clean up a bank's descriptions.
+type ImportTransformer interface {
+	Name() string
+	TransformImport(ctx context.Context, accountID string, transactions []ImportedTransaction) ([]ImportedTransaction, error)
+}
+
+// CategorizationProvider suggests categories for imported transactions
+// It returns one category ID per transaction, or "" when it has no suggestion.
+type CategorizationProvider interface {
+	Name() string
+	Categorize(ctx context.Context, categories []*domain.Category, transactions []ImportedTransaction) ([]string, error)
+}
+
+// PluginReport describes a report offered by a plugin
+type PluginReport struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PluginReportInput is the budget data handed to a report provider
+type PluginReportInput struct {
+	Period       string                `json:"period"`
+	Params       map[string]string     `json:"params"`
+	Accounts     []*domain.Account     `json:"accounts"`
+	Categories   []*domain.Category    `json:"categories"`
+	Allocations  []*domain.Allocation  `json:"allocations"`  // For the period
+	Transactions []*domain.Transaction `json:"transactions"` // Dated within the period
+}
+
+// ReportProvider builds custom reports from budget data
+// The report is returned as JSON and passed through unchanged.
+type ReportProvider interface {
+	Name() string
+	Reports() []PluginReport
+	RunReport(ctx context.Context, reportID string, input *PluginReportInput) (json.RawMessage, error)
+}
+
+// PluginInfo describes a registered plugin and the hooks it takes part in
+type PluginInfo struct {
+	Name    string         `json:"name"`
+	Hooks   []string       `json:"hooks"`
+	Reports []PluginReport `json:"reports,omitempty"`
+}
+
+// PluginService runs the registered plugins at their extension points
+// Plugins are called in registration order. Implementations live outside the core,
+// see internal/infrastructure/plugin.
+type PluginService struct {
+	categoryRepo    domain.CategoryRepository
+	accountRepo     domain.AccountRepository
+	transactionRepo domain.TransactionRepository
+	allocationRepo  domain.AllocationRepository
+	transformers    []ImportTransformer
+	categorizers    []CategorizationProvider
+	reporters       []ReportProvider
+}
+
+// NewPluginService creates a new plugin service
+func NewPluginService(
+	categoryRepo domain.CategoryRepository,
+	accountRepo domain.AccountRepository,
+	transactionRepo domain.TransactionRepository,
+	allocationRepo domain.AllocationRepository,
+	transformers []ImportTransformer,
+	categorizers []CategorizationProvider,
+	reporters []ReportProvider,
+) *PluginService {
+	return &PluginService{
+		categoryRepo:    categoryRepo,
+		accountRepo:     accountRepo,
+		transactionRepo: transactionRepo,
+		allocationRepo:  allocationRepo,
+		transformers:    transformers,
+		categorizers:    categorizers,
+		reporters:       reporters,
+	}
+}
+
+// ListPlugins returns the registered plugins in registration order, including the
+// built-in categorize script
+func (s *PluginService) ListPlugins() []*PluginInfo {
+	var plugins []*PluginInfo
+	byName := make(map[string]*PluginInfo)
+	info := func(name string) *PluginInfo {
+		if p, ok := byName[name]; ok {
+			return p
+		}
+		p := &PluginInfo{Name: name, Hooks: []string{}}
+		byName[name] = p
+		plugins = append(plugins, p)
+		return p
+	}
+
+	for _, t := range s.transformers {
+		p := info(t.Name())
+		p.Hooks = append(p.Hooks, PluginHookImportTransform)
+	}
+	for _, c := range s.categorizers {
+		p := info(c.Name())
+		p.Hooks = append(p.Hooks, PluginHookCategorize)
+	}
+	for _, r := range s.reporters {
+		p := info(r.Name())
+		p.Hooks = append(p.Hooks, PluginHookReports)
+		p.Reports = r.Reports()
+	}
+	return plugins
+}
+
+// TransformImport passes imported transactions through every import transformer in turn
+// A failing transformer fails the import, since skipping it could save transactions
+// the plugin meant to fix or drop.
+func (s *PluginService) TransformImport(ctx context.Context, accountID string, transactions []ImportedTransaction) ([]ImportedTransaction, error) {
+	for _, t := range s.transformers {
+		transformed, err := t.TransformImport(ctx, accountID, transactions)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %s: %w", t.Name(), err)
+		}
+		transactions = transformed
+	}
+	return transactions, nil
+}
+
+// Categorize asks the categorization providers for a category per transaction
+// The first provider to suggest an existing category wins. Provider failures are
+// logged and leave transactions uncategorized; nil means no suggestion.
+func (s *PluginService) Categorize(ctx context.Context, transactions []ImportedTransaction) []*string {
+	suggestions := make([]*string, len(transactions))
+	if len(s.categorizers) == 0 || len(transactions) == 0 {
+		return suggestions
+	}
+
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		log.Printf("plugins: failed to list categories: %v", err)
+		return suggestions
+	}
+	known := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		known[category.ID] = true
+	}
+
+	for _, c := range s.categorizers {
+		categoryIDs, err := c.Categorize(ctx, categories, transactions)
+		if err != nil {
+			log.Printf("plugins: %s: categorize: %v", c.Name(), err)
+			continue
+		}
+		for i, categoryID := range categoryIDs {
+			if i >= len(suggestions) || suggestions[i] != nil || categoryID == "" {
+				continue
+			}
+			if !known[categoryID] {
+				log.Printf("plugins: %s suggested unknown category %s", c.Name(), categoryID)
+				continue
+			}
+			id := categoryID
+			suggestions[i] = &id
+		}
+	}
+	return suggestions
+}
+
+// RunReport runs a plugin report for a period (YYYY-MM)
+// params are passed to the plugin as-is.
+func (s *PluginService) RunReport(ctx context.Context, pluginName, reportID, period string, params map[string]string) (json.RawMessage, error) {
+	provider := s.reportProvider(pluginName, reportID)
+	if provider == nil {
+		return nil, ErrPluginReportNotFound
+	}
+
+	start, err := time.Parse("2006-01", period)
+	if err != nil {
+		return nil, fmt.Errorf("invalid period format, expected YYYY-MM")
+	}
+	end := start.AddDate(0, 1, 0).Add(-time.Second)
+
+	accounts, err := s.accountRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	allocations, err := s.allocationRepo.ListByPeriod(ctx, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list allocations: %w", err)
+	}
+	transactions, err := s.transactionRepo.ListByPeriod(ctx, start.Format(time.RFC3339), end.Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	// Send empty lists rather than null so plugins don't have to special-case them
+	input := &PluginReportInput{
+		Period:       period,
+		Params:       params,
+		Accounts:     append([]*domain.Account{}, accounts...),
+		Categories:   append([]*domain.Category{}, categories...),
+		Allocations:  append([]*domain.Allocation{}, allocations...),
+		Transactions: append([]*domain.Transaction{}, transactions...),
+	}
+	if input.Params == nil {
+		input.Params = map[string]string{}
+	}
+
+	report, err := provider.RunReport(ctx, reportID, input)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", pluginName, err)
+	}
+	return report, nil
+}
+
+func (s *PluginService) reportProvider(pluginName, reportID string) ReportProvider {
+	for _, r := range s.reporters {
+		if r.Name() != pluginName {
+			continue
+		}
+		for _, report := range r.Reports() {
+			if report.ID == reportID {
+				return r
+			}
+		}
+	}
+	return nil
+}
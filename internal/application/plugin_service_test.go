@@ -0,0 +1,93 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type fakePlugin struct {
+	name        string
+	categoryIDs []string
+	err         error
+}
+
+func (p *fakePlugin) Name() string { return p.name }
+
+func (p *fakePlugin) TransformImport(ctx context.Context, accountID string, transactions []ImportedTransaction) ([]ImportedTransaction, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	var kept []ImportedTransaction
+	for _, txn := range transactions {
+		if strings.HasPrefix(txn.Description, "PENDING") {
+			continue
+		}
+		txn.Description = strings.ToUpper(txn.Description)
+		kept = append(kept, txn)
+	}
+	return kept, nil
+}
+
+func (p *fakePlugin) Categorize(ctx context.Context, categories []*domain.Category, transactions []ImportedTransaction) ([]string, error) {
+	return p.categoryIDs, p.err
+}
+
+func TestPluginTransformImport(t *testing.T) {
+	ctx := context.Background()
+	date := time.Date(2024, 11, 5, 0, 0, 0, 0, time.UTC)
+	transactions := []ImportedTransaction{
+		{Date: date, Amount: -500, Description: "coffee", FitID: "1"},
+		{Date: date, Amount: -900, Description: "PENDING lunch", FitID: "2"},
+	}
+
+	service := NewPluginService(nil, nil, nil, nil, []ImportTransformer{&fakePlugin{name: "cleanup"}}, nil, nil)
+	transformed, err := service.TransformImport(ctx, "acc-1", transactions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transformed) != 1 || transformed[0].Description != "COFFEE" {
+		t.Errorf("expected the pending transaction dropped and the other cleaned up, got %+v", transformed)
+	}
+
+	failing := NewPluginService(nil, nil, nil, nil, []ImportTransformer{&fakePlugin{name: "broken", err: errors.New("boom")}}, nil, nil)
+	if _, err := failing.TransformImport(ctx, "acc-1", transactions); err == nil {
+		t.Error("expected a failing transformer to fail the import")
+	}
+}
+
+func TestPluginCategorize(t *testing.T) {
+	ctx := context.Background()
+	categoryRepo := newMockCategoryRepository()
+	categoryRepo.Create(ctx, &domain.Category{ID: "groceries", Name: "Groceries"})
+	categoryRepo.Create(ctx, &domain.Category{ID: "dining", Name: "Dining Out"})
+
+	transactions := []ImportedTransaction{{Description: "ALDI"}, {Description: "Cafe"}, {Description: "Unknown"}}
+	service := NewPluginService(categoryRepo, nil, nil, nil, nil, []CategorizationProvider{
+		&fakePlugin{name: "broken", err: errors.New("unavailable")},
+		&fakePlugin{name: "first", categoryIDs: []string{"groceries", "", "deleted"}},
+		&fakePlugin{name: "second", categoryIDs: []string{"dining", "dining", ""}},
+	}, nil)
+
+	suggestions := service.Categorize(ctx, transactions)
+	if suggestions[0] == nil || *suggestions[0] != "groceries" {
+		t.Errorf("expected the first provider's suggestion to win, got %v", suggestions[0])
+	}
+	if suggestions[1] == nil || *suggestions[1] != "dining" {
+		t.Errorf("expected a later provider to fill gaps, got %v", suggestions[1])
+	}
+	if suggestions[2] != nil {
+		t.Errorf("expected unknown categories to be ignored, got %v", *suggestions[2])
+	}
+}
+
+func TestPluginRunReportUnknown(t *testing.T) {
+	service := NewPluginService(nil, nil, nil, nil, nil, nil, nil)
+	if _, err := service.RunReport(context.Background(), "nope", "summary", "2024-11", nil); !errors.Is(err, ErrPluginReportNotFound) {
+		t.Errorf("expected ErrPluginReportNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// proxyIssuer is the issuer recorded on identities asserted by a trusted reverse proxy
+const proxyIssuer = "proxy"
+
+// ProxyIdentity is the user a trusted reverse proxy says it authenticated
+type ProxyIdentity struct {
+	Username string // From Remote-User (or the configured header)
+	Email    string
+	Name     string
+	Groups   []string
+}
+
+// ProxyAuthService resolves users authenticated by an upstream reverse proxy
+// (Authelia, Authentik, oauth2-proxy, ...) that passes the username in a header
+type ProxyAuthService struct {
+	users *identityUsers
+}
+
+// NewProxyAuthService creates a new reverse-proxy auth service
+func NewProxyAuthService(
+	userRepo domain.UserRepository,
+	identityRepo domain.UserIdentityRepository,
+	membership SSOMembership,
+) *ProxyAuthService {
+	return &ProxyAuthService{
+		users: &identityUsers{userRepo: userRepo, identityRepo: identityRepo, membership: membership},
+	}
+}
+
+// Authenticate returns the budget user for a proxy-authenticated identity
+// The proxy is trusted to have verified the email, so existing users are linked by email.
+// A username that looks like an email is used as the email when none is given.
+func (s *ProxyAuthService) Authenticate(ctx context.Context, identity *ProxyIdentity) (*domain.User, error) {
+	username := strings.TrimSpace(identity.Username)
+	if username == "" {
+		return nil, fmt.Errorf("proxy did not send a username")
+	}
+
+	email := strings.TrimSpace(identity.Email)
+	if email == "" && strings.Contains(username, "@") {
+		email = username
+	}
+
+	name := strings.TrimSpace(identity.Name)
+	if name == "" {
+		name = username
+	}
+
+	return s.users.resolve(ctx, &IdentityClaims{
+		Issuer:        proxyIssuer,
+		Subject:       username,
+		Email:         email,
+		EmailVerified: email != "",
+		Name:          name,
+		Groups:        identity.Groups,
+	})
+}
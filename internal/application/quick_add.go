@@ -0,0 +1,246 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// QuickAddInput is the parsed form of a quick-add phrase such as
+// "spent 20 groceries at Kroger from cash" or "received 1500 paycheck"
+type QuickAddInput struct {
+	Amount       int64  // Amount in cents (positive=inflow, negative=outflow)
+	CategoryName string // Words naming the category (may be empty for inflows)
+	Payee        string // Text after "at" / "@"
+	AccountName  string // Text after "from" / "with"
+}
+
+var (
+	quickAddOutflowVerbs = map[string]bool{"spent": true, "spend": true, "paid": true, "pay": true, "bought": true, "buy": true}
+	quickAddInflowVerbs  = map[string]bool{"received": true, "receive": true, "got": true, "earned": true, "income": true, "deposit": true, "deposited": true}
+)
+
+// ParseQuickAdd parses a quick-add phrase
+// Grammar: [verb] amount [on|for] category [at|@ payee] [from|with account]
+// Amounts are outflows unless prefixed with "+" or preceded by an inflow verb.
+func ParseQuickAdd(text string) (*QuickAddInput, error) {
+	words := strings.Fields(strings.TrimSpace(text))
+	if len(words) == 0 {
+		return nil, fmt.Errorf("nothing to add")
+	}
+
+	sign := int64(-1)
+	explicitSign := false
+	if verb := strings.ToLower(words[0]); quickAddOutflowVerbs[verb] || quickAddInflowVerbs[verb] {
+		if quickAddInflowVerbs[verb] {
+			sign = 1
+		}
+		explicitSign = true
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("amount is required")
+	}
+
+	amountText := words[0]
+	words = words[1:]
+	if strings.HasPrefix(amountText, "+") || strings.HasPrefix(amountText, "-") {
+		if explicitSign {
+			return nil, fmt.Errorf("use either a verb or a +/- sign, not both")
+		}
+		if amountText[0] == '+' {
+			sign = 1
+		}
+		amountText = amountText[1:]
+	}
+	amountText = strings.TrimPrefix(strings.TrimPrefix(amountText, "$"), domain.BudgetCurrency().Symbol)
+	cents, err := parseQuickAddAmount(amountText)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &QuickAddInput{Amount: sign * cents}
+
+	// Split the remaining words on the payee/account markers
+	var category, payee, account []string
+	target := &category
+	for i, word := range words {
+		switch strings.ToLower(word) {
+		case "at", "@":
+			target = &payee
+			continue
+		case "from", "with":
+			target = &account
+			continue
+		case "on", "for":
+			if i == 0 {
+				continue
+			}
+		}
+		*target = append(*target, word)
+	}
+
+	input.CategoryName = strings.Join(category, " ")
+	input.Payee = strings.Join(payee, " ")
+	input.AccountName = strings.Join(account, " ")
+
+	if input.Amount < 0 && input.CategoryName == "" {
+		return nil, fmt.Errorf("category is required for spending")
+	}
+
+	return input, nil
+}
+
+// parseQuickAddAmount converts a decimal string like "20", "4.5" or "1,250.00" to minor
+// units of the budget's currency, e.g. cents
+func parseQuickAddAmount(s string) (int64, error) {
+	s = strings.ReplaceAll(s, ",", "")
+	if s == "" {
+		return 0, fmt.Errorf("amount is required")
+	}
+	if strings.HasPrefix(s, "-") {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	money, err := domain.ParseMoney(s, domain.BudgetCurrency().Code)
+	if err != nil {
+		return 0, err
+	}
+	if money.IsZero() {
+		return 0, fmt.Errorf("amount must be non-zero")
+	}
+	return money.Amount, nil
+}
+
+// QuickAddService turns quick-add phrases into transactions
+type QuickAddService struct {
+	categoryRepo       domain.CategoryRepository
+	accountRepo        domain.AccountRepository
+	transactionService *TransactionService
+}
+
+// NewQuickAddService creates a new quick-add service
+func NewQuickAddService(
+	categoryRepo domain.CategoryRepository,
+	accountRepo domain.AccountRepository,
+	transactionService *TransactionService,
+) *QuickAddService {
+	return &QuickAddService{
+		categoryRepo:       categoryRepo,
+		accountRepo:        accountRepo,
+		transactionService: transactionService,
+	}
+}
+
+// QuickAdd parses text and creates the transaction it describes
+// defaultAccountID is used when the phrase doesn't name an account.
+func (s *QuickAddService) QuickAdd(ctx context.Context, text string, defaultAccountID *string) (*domain.Transaction, error) {
+	input, err := ParseQuickAdd(text)
+	if err != nil {
+		return nil, err
+	}
+
+	accountID, err := s.resolveAccount(ctx, input.AccountName, defaultAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	var categoryID *string
+	description := input.Payee
+	if input.CategoryName != "" {
+		category, err := s.resolveCategory(ctx, input.CategoryName)
+		if err != nil {
+			// Inflows don't need a category; keep the words as the description instead
+			if input.Amount < 0 {
+				return nil, err
+			}
+			if description == "" {
+				description = input.CategoryName
+			}
+		} else {
+			categoryID = &category.ID
+			if description == "" {
+				description = category.Name
+			}
+		}
+	}
+
+	now := time.Now()
+	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	return s.transactionService.CreateTransaction(ctx, accountID, categoryID, input.Amount, description, date)
+}
+
+func (s *QuickAddService) resolveAccount(ctx context.Context, name string, defaultAccountID *string) (string, error) {
+	if name == "" {
+		if defaultAccountID == nil || *defaultAccountID == "" {
+			return "", fmt.Errorf("no account given and no default account set")
+		}
+		return *defaultAccountID, nil
+	}
+
+	accounts, err := s.accountRepo.List(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list accounts: %w", err)
+	}
+	names := make([]string, len(accounts))
+	for i, account := range accounts {
+		names[i] = account.Name
+	}
+
+	i, err := matchName(name, names, "account")
+	if err != nil {
+		return "", err
+	}
+	return accounts[i].ID, nil
+}
+
+func (s *QuickAddService) resolveCategory(ctx context.Context, name string) (*domain.Category, error) {
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	names := make([]string, len(categories))
+	for i, category := range categories {
+		names[i] = category.Name
+	}
+
+	i, err := matchName(name, names, "category")
+	if err != nil {
+		return nil, err
+	}
+	return categories[i], nil
+}
+
+// matchName finds name among candidates: an exact (case-insensitive) match wins,
+// then a unique prefix match, then a unique substring match
+func matchName(name string, candidates []string, kind string) (int, error) {
+	needle := strings.ToLower(strings.TrimSpace(name))
+
+	for _, match := range []func(string) bool{
+		func(c string) bool { return c == needle },
+		func(c string) bool { return strings.HasPrefix(c, needle) },
+		func(c string) bool { return strings.Contains(c, needle) },
+	} {
+		var found []int
+		for i, candidate := range candidates {
+			if match(strings.ToLower(candidate)) {
+				found = append(found, i)
+			}
+		}
+		if len(found) == 1 {
+			return found[0], nil
+		}
+		if len(found) > 1 {
+			matches := make([]string, len(found))
+			for j, i := range found {
+				matches[j] = candidates[i]
+			}
+			return 0, fmt.Errorf("%q matches more than one %s: %s", name, kind, strings.Join(matches, ", "))
+		}
+	}
+
+	return 0, fmt.Errorf("no %s matches %q", kind, name)
+}
@@ -0,0 +1,62 @@
+package application
+
+import "testing"
+
+func TestParseQuickAdd(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    QuickAddInput
+		wantErr bool
+	}{
+		{text: "spent 20 groceries", want: QuickAddInput{Amount: -2000, CategoryName: "groceries"}},
+		{text: "paid $4.5 on coffee at Blue Bottle", want: QuickAddInput{Amount: -450, CategoryName: "coffee", Payee: "Blue Bottle"}},
+		{text: "1,250.00 rent from checking", want: QuickAddInput{Amount: -125000, CategoryName: "rent", AccountName: "checking"}},
+		{text: "received 1500 paycheck", want: QuickAddInput{Amount: 150000, CategoryName: "paycheck"}},
+		{text: "+12.34", want: QuickAddInput{Amount: 1234}},
+		{text: "spent 20 dining out with amex", want: QuickAddInput{Amount: -2000, CategoryName: "dining out", AccountName: "amex"}},
+		{text: "spent 20", wantErr: true},
+		{text: "spent twenty groceries", wantErr: true},
+		{text: "spent 1.234 groceries", wantErr: true},
+		{text: "spent -20 groceries", wantErr: true},
+		{text: "0 groceries", wantErr: true},
+		{text: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := ParseQuickAdd(tt.text)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("got %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchName(t *testing.T) {
+	names := []string{"Groceries", "Dining Out", "Gas", "Gas & Electric"}
+
+	if i, err := matchName("gas", names, "category"); err != nil || i != 2 {
+		t.Errorf("exact match: got %d, %v", i, err)
+	}
+	if i, err := matchName("groc", names, "category"); err != nil || i != 0 {
+		t.Errorf("prefix match: got %d, %v", i, err)
+	}
+	if i, err := matchName("out", names, "category"); err != nil || i != 1 {
+		t.Errorf("substring match: got %d, %v", i, err)
+	}
+	if _, err := matchName("g", names, "category"); err == nil {
+		t.Error("expected ambiguous match error")
+	}
+	if _, err := matchName("rent", names, "category"); err == nil {
+		t.Error("expected no match error")
+	}
+}
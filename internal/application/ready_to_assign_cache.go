@@ -0,0 +1,44 @@
+package application
+
+import "sync"
+
+// maxCachedPeriods caps how many periods are kept; the cache starts over when full
+const maxCachedPeriods = 240
+
+// ReadyToAssignCache keeps Ready to Assign per period until the budget data changes
+// Amounts are stored against the budget revision, which the database changes whenever
+// transactions, allocations, categories or accounts do. Every writer (the API, imports,
+// the Telegram bot, scripts) therefore invalidates the cache without knowing about it.
+type ReadyToAssignCache struct {
+	mu       sync.Mutex
+	revision int64
+	periods  map[string]int64
+}
+
+// NewReadyToAssignCache creates an empty Ready to Assign cache
+func NewReadyToAssignCache() *ReadyToAssignCache {
+	return &ReadyToAssignCache{periods: make(map[string]int64)}
+}
+
+// Get returns the amount cached for period, if it was calculated at revision
+func (c *ReadyToAssignCache) Get(revision int64, period string) (int64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if revision != c.revision {
+		return 0, false
+	}
+	amount, ok := c.periods[period]
+	return amount, ok
+}
+
+// Put caches the amount calculated for period at revision
+// Amounts from other revisions are dropped.
+func (c *ReadyToAssignCache) Put(revision int64, period string, amount int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if revision != c.revision || len(c.periods) >= maxCachedPeriods {
+		c.revision = revision
+		c.periods = make(map[string]int64)
+	}
+	c.periods[period] = amount
+}
@@ -0,0 +1,40 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestAllocationService_ReadyToAssignCache(t *testing.T) {
+	ctx := context.Background()
+	transactionRepo := newMockTransactionRepository()
+	transactionRepo.transactions = []*domain.Transaction{
+		{ID: "pay", Type: domain.TransactionTypeNormal, AccountID: "checking", Amount: 300000, Date: time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)},
+	}
+	budgetStateRepo := newMockBudgetStateRepository(0, 0)
+	service := NewAllocationService(newMockAllocationRepository(), newMockCategoryRepository(), transactionRepo, budgetStateRepo,
+		newMockAccountRepository(0), newMockCategoryGroupRepository(), newMockGoalRepository())
+	service.UseReadyToAssignCache(NewReadyToAssignCache())
+
+	if rta, err := service.CalculateReadyToAssignForPeriod(ctx, "2025-11"); err != nil || rta != 300000 {
+		t.Fatalf("expected 300000, got %d (%v)", rta, err)
+	}
+
+	// Until the revision changes, the cached amount is served
+	transactionRepo.transactions = append(transactionRepo.transactions,
+		&domain.Transaction{ID: "bonus", Type: domain.TransactionTypeNormal, AccountID: "checking", Amount: 50000, Date: time.Date(2025, 11, 15, 12, 0, 0, 0, time.UTC)})
+	if rta, _ := service.CalculateReadyToAssignForPeriod(ctx, "2025-11"); rta != 300000 {
+		t.Errorf("expected the cached 300000, got %d", rta)
+	}
+	if rta, _ := service.CalculateReadyToAssignForPeriod(ctx, "2025-10"); rta != 0 {
+		t.Errorf("expected periods to be cached separately, got %d", rta)
+	}
+
+	budgetStateRepo.state.Revision = 42
+	if rta, _ := service.CalculateReadyToAssignForPeriod(ctx, "2025-11"); rta != 350000 {
+		t.Errorf("expected a new revision to recalculate 350000, got %d", rta)
+	}
+}
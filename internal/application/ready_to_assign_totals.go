@@ -0,0 +1,191 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// verifyAttempts is how many times a check is tried while the budget keeps changing under it
+const verifyAttempts = 3
+
+// ReadyToAssignCheck compares the Ready to Assign totals the database keeps with a full
+// recalculation from every transaction and allocation
+type ReadyToAssignCheck struct {
+	Maintained bool                    `json:"maintained"` // False when Ready to Assign is recalculated on every request, with nothing to check
+	Periods    int                     `json:"periods"`    // Months compared
+	Mismatches []ReadyToAssignMismatch `json:"mismatches"`
+	Warning    string                  `json:"warning,omitempty"`
+	CheckedAt  time.Time               `json:"checked_at"`
+}
+
+// ReadyToAssignMismatch is a month whose kept totals differ from the recalculated ones
+type ReadyToAssignMismatch struct {
+	Period               string `json:"period"`
+	MaintainedInflows    int64  `json:"maintained_inflows"`
+	RecalculatedInflows  int64  `json:"recalculated_inflows"`
+	MaintainedAssigned   int64  `json:"maintained_assigned"`
+	RecalculatedAssigned int64  `json:"recalculated_assigned"`
+}
+
+// readyToAssignTotals returns each period's inflows and assigned amounts, oldest first
+// Inflows are totalled by periodType. Assigned amounts are keyed by the periods they were
+// allocated in, which may be of another type; see periodKeyIn. The totals the database
+// keeps have monthly inflows, which add up to quarters but not weeks.
+func (s *AllocationService) readyToAssignTotals(ctx context.Context, periodType domain.PeriodType) ([]*domain.ReadyToAssignTotal, error) {
+	if s.rtaTotals != nil && periodType != domain.PeriodWeek {
+		return s.rtaTotals.List(ctx)
+	}
+	return s.recalculateReadyToAssignTotals(ctx, periodType)
+}
+
+// recalculateReadyToAssignTotals adds up each period's inflows and assigned amounts from
+// every transaction and allocation
+func (s *AllocationService) recalculateReadyToAssignTotals(ctx context.Context, periodType domain.PeriodType) ([]*domain.ReadyToAssignTotal, error) {
+	byPeriod := make(map[string]*domain.ReadyToAssignTotal)
+	total := func(period string) *domain.ReadyToAssignTotal {
+		if _, ok := byPeriod[period]; !ok {
+			byPeriod[period] = &domain.ReadyToAssignTotal{Period: period}
+		}
+		return byPeriod[period]
+	}
+
+	// Only count positive amounts (inflows), exclude transfers and balance adjustments
+	inflows, err := s.transactionRepo.SumInflowsByPeriod(ctx, periodType)
+	if err != nil {
+		return nil, err
+	}
+	for period, amount := range inflows {
+		total(period).Inflows += amount
+	}
+
+	allocations, err := s.allocationRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list allocations: %w", err)
+	}
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	paymentCategoryIDs := make(map[string]bool)
+	for _, cat := range categories {
+		if cat.PaymentForAccountID != nil && *cat.PaymentForAccountID != "" {
+			paymentCategoryIDs[cat.ID] = true
+		}
+	}
+	// EXCLUDE payment category allocations - they represent money transferred from expense categories,
+	// not new money allocated from RTA
+	for _, alloc := range allocations {
+		if !paymentCategoryIDs[alloc.CategoryID] {
+			total(alloc.Period).Assigned += alloc.Amount
+		}
+	}
+
+	// Moves into payment categories only come out of Ready to Assign when they have no source category
+	if s.moveRepo != nil {
+		moves, err := s.moveRepo.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, move := range moves {
+			if move.FromCategoryID == nil {
+				total(move.Period).Assigned += move.Amount
+			}
+		}
+	}
+
+	totals := make([]*domain.ReadyToAssignTotal, 0, len(byPeriod))
+	for _, t := range byPeriod {
+		totals = append(totals, t)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].Period < totals[j].Period })
+	return totals, nil
+}
+
+// VerifyReadyToAssignTotals checks the totals the database keeps against a full recalculation
+// Months missing on one side count as zero. If the budget changes while checking, the
+// check starts over, so a write landing between the two reads isn't taken for drift.
+func (s *AllocationService) VerifyReadyToAssignTotals(ctx context.Context) (*ReadyToAssignCheck, error) {
+	check := &ReadyToAssignCheck{Mismatches: []ReadyToAssignMismatch{}, CheckedAt: time.Now()}
+	if s.rtaTotals == nil {
+		return check, nil
+	}
+	check.Maintained = true
+
+	for attempt := 1; ; attempt++ {
+		before, err := s.budgetStateRepo.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		maintained, err := s.rtaTotals.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		recalculated, err := s.recalculateReadyToAssignTotals(ctx, domain.PeriodMonth)
+		if err != nil {
+			return nil, err
+		}
+		after, err := s.budgetStateRepo.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if before.Revision != after.Revision && attempt < verifyAttempts {
+			continue
+		}
+
+		check.Mismatches, check.Periods = compareReadyToAssignTotals(maintained, recalculated)
+		break
+	}
+
+	if len(check.Mismatches) > 0 {
+		check.Warning = fmt.Sprintf("Ready to Assign totals differ from a full recalculation in %d month(s); rebuild them to fix Ready to Assign", len(check.Mismatches))
+		log.Printf("ready to assign: totals differ from a full recalculation in %d month(s), first %s", len(check.Mismatches), check.Mismatches[0].Period)
+	}
+	return check, nil
+}
+
+// RebuildReadyToAssignTotals recalculates the totals the database keeps, then checks them
+func (s *AllocationService) RebuildReadyToAssignTotals(ctx context.Context) (*ReadyToAssignCheck, error) {
+	if s.rtaTotals == nil {
+		return nil, fmt.Errorf("ready to assign totals aren't kept, so there's nothing to rebuild")
+	}
+	if err := s.rtaTotals.Rebuild(ctx); err != nil {
+		return nil, err
+	}
+	return s.VerifyReadyToAssignTotals(ctx)
+}
+
+// compareReadyToAssignTotals returns the months where two sets of totals differ, in order,
+// and how many months were compared
+func compareReadyToAssignTotals(maintained, recalculated []*domain.ReadyToAssignTotal) ([]ReadyToAssignMismatch, int) {
+	byPeriod := make(map[string]*ReadyToAssignMismatch)
+	mismatch := func(period string) *ReadyToAssignMismatch {
+		if _, ok := byPeriod[period]; !ok {
+			byPeriod[period] = &ReadyToAssignMismatch{Period: period}
+		}
+		return byPeriod[period]
+	}
+	for _, total := range maintained {
+		m := mismatch(total.Period)
+		m.MaintainedInflows += total.Inflows
+		m.MaintainedAssigned += total.Assigned
+	}
+	for _, total := range recalculated {
+		m := mismatch(total.Period)
+		m.RecalculatedInflows += total.Inflows
+		m.RecalculatedAssigned += total.Assigned
+	}
+
+	mismatches := []ReadyToAssignMismatch{}
+	for _, m := range byPeriod {
+		if m.MaintainedInflows != m.RecalculatedInflows || m.MaintainedAssigned != m.RecalculatedAssigned {
+			mismatches = append(mismatches, *m)
+		}
+	}
+	sort.Slice(mismatches, func(i, j int) bool { return mismatches[i].Period < mismatches[j].Period })
+	return mismatches, len(byPeriod)
+}
@@ -0,0 +1,86 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type mockReadyToAssignTotalsRepository struct {
+	totals  []*domain.ReadyToAssignTotal
+	rebuilt []*domain.ReadyToAssignTotal // What Rebuild sets totals to
+}
+
+func (m *mockReadyToAssignTotalsRepository) List(ctx context.Context) ([]*domain.ReadyToAssignTotal, error) {
+	return m.totals, nil
+}
+
+func (m *mockReadyToAssignTotalsRepository) Rebuild(ctx context.Context) error {
+	m.totals = m.rebuilt
+	return nil
+}
+
+func TestAllocationService_ReadyToAssignTotals(t *testing.T) {
+	ctx := context.Background()
+	card := "card"
+	transactionRepo := newMockTransactionRepository()
+	transactionRepo.transactions = []*domain.Transaction{
+		{ID: "pay-oct", Type: domain.TransactionTypeNormal, AccountID: "checking", Amount: 200000, Date: time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)},
+		{ID: "pay-nov", Type: domain.TransactionTypeNormal, AccountID: "checking", Amount: 300000, Date: time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)},
+		{ID: "in", Type: domain.TransactionTypeTransfer, AccountID: "checking", Amount: 5000, Date: time.Date(2025, 11, 2, 12, 0, 0, 0, time.UTC)},
+	}
+	categoryRepo := newMockCategoryRepository()
+	categoryRepo.categories["rent"] = &domain.Category{ID: "rent", Name: "Rent"}
+	categoryRepo.categories["visa"] = &domain.Category{ID: "visa", Name: "Visa Payment", PaymentForAccountID: &card}
+	allocationRepo := newMockAllocationRepository()
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a1", CategoryID: "rent", Period: "2025-11", Amount: 150000})
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a2", CategoryID: "visa", Period: "2025-11", Amount: 20000})
+	service := NewAllocationService(allocationRepo, categoryRepo, transactionRepo, newMockBudgetStateRepository(0, 0),
+		newMockAccountRepository(0), newMockCategoryGroupRepository(), newMockGoalRepository())
+
+	recalculated, err := service.CalculateReadyToAssignForPeriod(ctx, "2025-11")
+	if err != nil || recalculated != 350000 {
+		t.Fatalf("expected 350000 recalculated, got %d (%v)", recalculated, err)
+	}
+
+	// Kept totals give the same answer, and pass the check
+	correct := []*domain.ReadyToAssignTotal{
+		{Period: "2025-10", Inflows: 200000},
+		{Period: "2025-11", Inflows: 300000, Assigned: 150000},
+	}
+	totals := &mockReadyToAssignTotalsRepository{totals: correct, rebuilt: correct}
+	service.UseReadyToAssignTotals(totals)
+	if rta, err := service.CalculateReadyToAssignForPeriod(ctx, "2025-11"); err != nil || rta != recalculated {
+		t.Errorf("expected kept totals to give %d, got %d (%v)", recalculated, rta, err)
+	}
+	check, err := service.VerifyReadyToAssignTotals(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !check.Maintained || check.Periods != 2 || len(check.Mismatches) != 0 || check.Warning != "" {
+		t.Errorf("expected the totals to match, got %+v", check)
+	}
+
+	// Drift is reported month by month, and a rebuild fixes it
+	totals.totals = []*domain.ReadyToAssignTotal{
+		{Period: "2025-09", Inflows: 1000},
+		{Period: "2025-10", Inflows: 200000},
+		{Period: "2025-11", Inflows: 300000, Assigned: 170000},
+	}
+	check, err = service.VerifyReadyToAssignTotals(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(check.Mismatches) != 2 || check.Mismatches[0].Period != "2025-09" || check.Mismatches[1].MaintainedAssigned != 170000 || check.Warning == "" {
+		t.Fatalf("expected mismatches in 2025-09 and 2025-11, got %+v", check)
+	}
+	check, err = service.RebuildReadyToAssignTotals(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(check.Mismatches) != 0 {
+		t.Errorf("expected a rebuild to clear the mismatches, got %+v", check.Mismatches)
+	}
+}
@@ -0,0 +1,126 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// JobKindRecategorize is the kind of the jobs that apply changed payee rules
+const JobKindRecategorize = "recategorize"
+
+// Recategorizer applies changed payee rules to uncategorized transactions in the background
+// Only uncategorized transactions a changed rule matches are looked at, a batch at a time
+// with a pause between batches, so a big history doesn't tie up the database. Rule changes
+// made while a job is still queued are folded into it.
+type Recategorizer struct {
+	ruleRepo           domain.PayeeRuleRepository
+	transactionService *TransactionService
+	jobService         *JobService
+	batchSize          int
+	batchDelay         time.Duration
+
+	mu           sync.Mutex
+	queuedJobID  string              // The job that will pick up changedRules, if it hasn't started yet
+	changedRules []*domain.PayeeRule // Rules changed since the last job started
+}
+
+// NewRecategorizer creates a new recategorizer
+func NewRecategorizer(ruleRepo domain.PayeeRuleRepository, transactionService *TransactionService, jobService *JobService, batchSize int, batchDelay time.Duration) *Recategorizer {
+	return &Recategorizer{
+		ruleRepo:           ruleRepo,
+		transactionService: transactionService,
+		jobService:         jobService,
+		batchSize:          batchSize,
+		batchDelay:         batchDelay,
+	}
+}
+
+// Enqueue queues a job applying the rules to the transactions the given rules match
+// rules are the changed rules as they are now, or as they were before being deleted.
+// Returns the ID of the job that will do it.
+func (r *Recategorizer) Enqueue(rules ...*domain.PayeeRule) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.changedRules = append(r.changedRules, rules...)
+	if r.queuedJobID == "" {
+		r.queuedJobID = r.jobService.Enqueue(JobKindRecategorize, r.run)
+	}
+	return r.queuedJobID
+}
+
+// run categorizes the uncategorized transactions matched by the rules changed so far
+// Every current rule is tried in order, as during import, so the first matching rule
+// decides; transactions it gives no category stay uncategorized.
+func (r *Recategorizer) run(ctx context.Context, progress *JobProgress) error {
+	r.mu.Lock()
+	changed := r.changedRules
+	r.changedRules = nil
+	r.queuedJobID = ""
+	r.mu.Unlock()
+
+	affected := make([]transactionMatcher, len(changed))
+	for i, rule := range changed {
+		affected[i] = ruleMatcher(rule)
+	}
+	rules, err := r.ruleRepo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list payee rules: %w", err)
+	}
+	matchers := make([]transactionMatcher, len(rules))
+	for i, rule := range rules {
+		matchers[i] = ruleMatcher(rule)
+	}
+
+	uncategorized, err := r.transactionService.ListUncategorizedTransactions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list uncategorized transactions: %w", err)
+	}
+	var targets []*domain.Transaction
+	for _, transaction := range uncategorized {
+		for _, matches := range affected {
+			if matches(transaction.AccountID, transaction.Amount, transaction.Description) {
+				targets = append(targets, transaction)
+				break
+			}
+		}
+	}
+	progress.SetTotal(len(targets))
+
+	for start := 0; start < len(targets); start += r.batchSize {
+		if start > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(r.batchDelay):
+			}
+		}
+		batch := targets[start:min(start+r.batchSize, len(targets))]
+
+		byCategory := make(map[string][]string)
+		for _, transaction := range batch {
+			for i, rule := range rules {
+				if !matchers[i](transaction.AccountID, transaction.Amount, transaction.Description) {
+					continue
+				}
+				if rule.CategoryID != nil {
+					byCategory[*rule.CategoryID] = append(byCategory[*rule.CategoryID], transaction.ID)
+				}
+				break
+			}
+		}
+
+		categorized := 0
+		for categoryID, ids := range byCategory {
+			if err := r.transactionService.BulkCategorizeTransactions(ctx, ids, &categoryID); err != nil {
+				return fmt.Errorf("failed to categorize transactions: %w", err)
+			}
+			categorized += len(ids)
+		}
+		progress.Advance(len(batch), categorized)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestRecategorizer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	categoryRepo := newMockCategoryRepository()
+	categoryRepo.categories["coffee"] = &domain.Category{ID: "coffee", Name: "Coffee"}
+	categoryRepo.categories["fuel"] = &domain.Category{ID: "fuel", Name: "Fuel"}
+	coffee, fuel, groceries := "coffee", "fuel", "groceries"
+
+	transactionRepo := newMockTransactionRepository()
+	for _, txn := range []*domain.Transaction{
+		{ID: "t1", Description: "STARBUCKS #12"},
+		{ID: "t2", Description: "Starbucks Reserve"},
+		{ID: "t3", Description: "STARBUCKS #40", CategoryID: &groceries}, // Already categorized
+		{ID: "t4", Description: "SHELL OIL"},
+		{ID: "t5", Description: "Corner Store"},
+	} {
+		transactionRepo.Create(ctx, txn)
+	}
+	transactionService := NewTransactionService(transactionRepo, newMockAccountRepository(0), categoryRepo, newMockBudgetStateRepository(0, 0),
+		&mockUnitOfWork{newMockAccountRepository(0), transactionRepo}, DateBounds{})
+
+	ruleRepo := newMockPayeeRuleRepository()
+	jobService := NewJobService()
+	recategorizer := NewRecategorizer(ruleRepo, transactionService, jobService, 1, 0)
+	payeeService := NewPayeeService(newMockPayeeRepository(), ruleRepo, newMockAccountRepository(0), categoryRepo)
+	payeeService.UseRecategorizer(recategorizer)
+
+	// Both changes land in one job, since it hasn't started yet
+	if _, err := payeeService.CreateRule(ctx, "starbucks", domain.PayeeMatchContains, nil, nil, nil, "", &coffee, nil, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := payeeService.CreateRule(ctx, "shell", domain.PayeeMatchContains, nil, nil, nil, "", &fuel, nil, 2); err != nil {
+		t.Fatal(err)
+	}
+	jobs := jobService.List()
+	if len(jobs) != 1 || jobs[0].Status != JobStatusQueued || jobs[0].Kind != JobKindRecategorize {
+		t.Fatalf("expected one queued recategorize job, got %+v", jobs)
+	}
+
+	go jobService.Run(ctx)
+	job := waitForJob(t, jobService, jobs[0].ID)
+	if job.Status != JobStatusSucceeded || job.Total != 3 || job.Processed != 3 || job.Changed != 3 {
+		t.Errorf("expected 3 of 3 transactions changed, got %+v", job)
+	}
+
+	want := map[string]*string{"t1": &coffee, "t2": &coffee, "t3": &groceries, "t4": &fuel, "t5": nil}
+	for _, txn := range transactionRepo.transactions {
+		if (txn.CategoryID == nil) != (want[txn.ID] == nil) || (txn.CategoryID != nil && *txn.CategoryID != *want[txn.ID]) {
+			t.Errorf("transaction %s: expected category %v, got %v", txn.ID, want[txn.ID], txn.CategoryID)
+		}
+	}
+
+	// Running every rule again finds nothing left that they categorize
+	jobID, err := payeeService.RunRules(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job := waitForJob(t, jobService, jobID); job.Status != JobStatusSucceeded || job.Total != 0 {
+		t.Errorf("expected a rerun with nothing to change, got %+v", job)
+	}
+}
+
+func TestJobServiceRecordsFailures(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	jobService := NewJobService()
+	go jobService.Run(ctx)
+	id := jobService.Enqueue("test", func(ctx context.Context, progress *JobProgress) error {
+		progress.SetTotal(2)
+		progress.Advance(1, 0)
+		return context.DeadlineExceeded
+	})
+
+	job := waitForJob(t, jobService, id)
+	if job.Status != JobStatusFailed || job.Error == "" || job.Processed != 1 || job.StartedAt == nil {
+		t.Errorf("expected a failed job with its progress, got %+v", job)
+	}
+	if _, err := jobService.Get("missing"); err != domain.ErrJobNotFound {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
+// waitForJob polls until the job has finished
+func waitForJob(t *testing.T, jobService *JobService, id string) *Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := jobService.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.FinishedAt != nil {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("job %s didn't finish", id)
+	return nil
+}
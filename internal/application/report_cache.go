@@ -0,0 +1,103 @@
+package application
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// maxCachedReports caps how many parameter sets are kept; the cache starts over when full
+const maxCachedReports = 256
+
+// CachedReport is a report rendered as JSON, with an ETag for conditional requests
+type CachedReport struct {
+	Body []byte
+	ETag string // Quoted, ready for the ETag header
+
+	builtAt time.Time
+}
+
+// ReportCache keeps rendered reports until the budget data behind them changes
+// Reports are keyed by their parameters and stored against the budget revision, which the
+// database changes whenever transactions, allocations, categories or accounts do, so every
+// writer (the API, imports, the Telegram bot, background jobs) invalidates the cache
+// without knowing about it. Settings the reports read aren't part of the revision, so
+// changing them calls Invalidate. Entries also expire after a TTL, which covers CPI data refreshing.
+type ReportCache struct {
+	ttl             time.Duration
+	budgetStateRepo domain.BudgetStateRepository
+
+	mu         sync.Mutex
+	revision   int64  // Budget revision the entries were built at
+	generation uint64 // Changed by Invalidate
+	entries    map[string]*CachedReport
+}
+
+// NewReportCache creates a report cache whose entries live at most ttl
+func NewReportCache(ttl time.Duration, budgetStateRepo domain.BudgetStateRepository) *ReportCache {
+	return &ReportCache{
+		ttl:             ttl,
+		budgetStateRepo: budgetStateRepo,
+		entries:         make(map[string]*CachedReport),
+	}
+}
+
+// Get returns the cached report for key, building and caching it if needed
+// build runs without the lock held; a report built while the data changed isn't served.
+func (c *ReportCache) Get(ctx context.Context, key string, build func() (any, error)) (*CachedReport, error) {
+	// Read the revision first: if the data changes while building, the report is
+	// stored against the old revision and never served
+	state, err := c.budgetStateRepo.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	revision := state.Revision
+
+	c.mu.Lock()
+	if cached, ok := c.entries[key]; ok && c.revision == revision && time.Since(cached.builtAt) < c.ttl {
+		c.mu.Unlock()
+		return cached, nil
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	report, err := build()
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(body)
+	cached := &CachedReport{
+		Body:    append(body, '\n'),
+		ETag:    `"` + hex.EncodeToString(sum[:16]) + `"`,
+		builtAt: time.Now(),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation != generation {
+		return cached, nil
+	}
+	if c.revision != revision || len(c.entries) >= maxCachedReports {
+		c.revision = revision
+		c.entries = make(map[string]*CachedReport)
+	}
+	c.entries[key] = cached
+	return cached, nil
+}
+
+// Invalidate drops every cached report
+func (c *ReportCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.entries = make(map[string]*CachedReport)
+}
@@ -0,0 +1,81 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestReportCache_InvalidatesOnChange(t *testing.T) {
+	ctx := context.Background()
+	budgetState := newMockBudgetStateRepository(0, 0)
+	cache := NewReportCache(time.Hour, budgetState)
+
+	builds := 0
+	build := func() (any, error) {
+		builds++
+		return map[string]int{"builds": builds}, nil
+	}
+
+	first, err := cache.Get(ctx, "trends?start=2025-01", build)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := cache.Get(ctx, "trends?start=2025-01", build)
+	if builds != 1 || second.ETag != first.ETag {
+		t.Errorf("expected the second request to be served from the cache, built %d times", builds)
+	}
+	cache.Get(ctx, "trends?start=2025-02", build)
+	if builds != 2 {
+		t.Errorf("expected other parameters to be cached separately, built %d times", builds)
+	}
+
+	// Any change to the budget data, through the API or not, changes the revision
+	budgetState.state.Revision = 42
+	third, _ := cache.Get(ctx, "trends?start=2025-01", build)
+	if builds != 3 || third.ETag == first.ETag {
+		t.Errorf("expected a change to rebuild the report with a new ETag, built %d times", builds)
+	}
+
+	// Settings aren't part of the revision
+	cache.Invalidate()
+	cache.Get(ctx, "trends?start=2025-01", build)
+	if builds != 4 {
+		t.Errorf("expected invalidating to rebuild the report, built %d times", builds)
+	}
+}
+
+func TestReportCache_SkipsReportsBuiltDuringAChange(t *testing.T) {
+	ctx := context.Background()
+	budgetState := newMockBudgetStateRepository(0, 0)
+	cache := NewReportCache(time.Hour, budgetState)
+	builds := 0
+	cache.Get(ctx, "key", func() (any, error) {
+		builds++
+		budgetState.state.Revision = 7
+		return builds, nil
+	})
+	cache.Get(ctx, "key", func() (any, error) {
+		builds++
+		return builds, nil
+	})
+	if builds != 2 {
+		t.Errorf("expected a report built while the data changed not to be served, built %d times", builds)
+	}
+}
+
+func TestReportCache_Expires(t *testing.T) {
+	ctx := context.Background()
+	cache := NewReportCache(time.Millisecond, newMockBudgetStateRepository(0, 0))
+	builds := 0
+	build := func() (any, error) {
+		builds++
+		return builds, nil
+	}
+	cache.Get(ctx, "key", build)
+	time.Sleep(5 * time.Millisecond)
+	cache.Get(ctx, "key", build)
+	if builds != 2 {
+		t.Errorf("expected an expired report to be rebuilt, built %d times", builds)
+	}
+}
@@ -0,0 +1,151 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// ReportService builds read-only reports from budget data
+type ReportService struct {
+	categoryRepo    domain.CategoryRepository
+	allocationRepo  domain.AllocationRepository
+	transactionRepo domain.TransactionRepository
+	accountRepo     domain.AccountRepository
+	settingRepo     domain.SettingRepository
+	cpiRepo         domain.CPIRepository
+	allocations     *AllocationService
+}
+
+// NewReportService creates a new report service
+func NewReportService(
+	categoryRepo domain.CategoryRepository,
+	allocationRepo domain.AllocationRepository,
+	transactionRepo domain.TransactionRepository,
+	accountRepo domain.AccountRepository,
+	settingRepo domain.SettingRepository,
+	cpiRepo domain.CPIRepository,
+	allocations *AllocationService,
+) *ReportService {
+	return &ReportService{
+		categoryRepo:    categoryRepo,
+		allocationRepo:  allocationRepo,
+		transactionRepo: transactionRepo,
+		accountRepo:     accountRepo,
+		settingRepo:     settingRepo,
+		cpiRepo:         cpiRepo,
+		allocations:     allocations,
+	}
+}
+
+// BareBonesCategory is a single category's contribution to the bare-bones budget
+type BareBonesCategory struct {
+	CategoryID     string                        `json:"category_id"`
+	Name           string                        `json:"name"`
+	Classification domain.CategoryClassification `json:"classification"`
+	AverageSpent   int64                         `json:"average_spent"` // Average monthly spending over the trailing months (cents)
+	Allocated      int64                         `json:"allocated"`     // Amount allocated for the period (cents)
+	MinimumNeed    int64                         `json:"minimum_need"`  // What this category needs in a bare-bones month (cents)
+}
+
+// BareBonesBudget shows the minimum monthly need if only essential and debt spending remained
+type BareBonesBudget struct {
+	Period             string               `json:"period"`
+	TrailingMonths     int                  `json:"trailing_months"`
+	Categories         []*BareBonesCategory `json:"categories"`
+	MinimumMonthlyNeed int64                `json:"minimum_monthly_need"` // Sum of essential + debt needs (cents)
+	CurrentAllocated   int64                `json:"current_allocated"`    // Everything allocated for the period (cents)
+	DiscretionaryCut   int64                `json:"discretionary_cut"`    // Discretionary + savings allocations that would be dropped (cents)
+}
+
+// GetBareBonesBudget simulates a "bare-bones" month for the given period
+// Essential and debt categories keep the larger of their allocation and their trailing
+// average spending; discretionary and savings categories drop to zero.
+func (s *ReportService) GetBareBonesBudget(ctx context.Context, period string, trailingMonths int) (*BareBonesBudget, error) {
+	if trailingMonths <= 0 {
+		trailingMonths = 3
+	}
+
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+
+	averages, err := s.averageMonthlySpending(ctx, period, trailingMonths)
+	if err != nil {
+		return nil, err
+	}
+
+	allocations, err := s.allocationRepo.ListByPeriod(ctx, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list allocations: %w", err)
+	}
+	allocated := make(map[string]int64)
+	for _, alloc := range allocations {
+		allocated[alloc.CategoryID] += alloc.Amount
+	}
+
+	result := &BareBonesBudget{
+		Period:         period,
+		TrailingMonths: trailingMonths,
+		Categories:     []*BareBonesCategory{},
+	}
+
+	for _, category := range categories {
+		result.CurrentAllocated += allocated[category.ID]
+
+		if category.Classification != domain.ClassificationEssential && category.Classification != domain.ClassificationDebt {
+			result.DiscretionaryCut += allocated[category.ID]
+			continue
+		}
+
+		need := averages[category.ID]
+		if allocated[category.ID] > need {
+			need = allocated[category.ID]
+		}
+
+		result.Categories = append(result.Categories, &BareBonesCategory{
+			CategoryID:     category.ID,
+			Name:           category.Name,
+			Classification: category.Classification,
+			AverageSpent:   averages[category.ID],
+			Allocated:      allocated[category.ID],
+			MinimumNeed:    need,
+		})
+		result.MinimumMonthlyNeed += need
+	}
+
+	return result, nil
+}
+
+// averageMonthlySpending returns average monthly spending per category over the
+// complete months immediately before period (spending is returned as a positive number)
+func (s *ReportService) averageMonthlySpending(ctx context.Context, period string, months int) (map[string]int64, error) {
+	periodStart, err := time.Parse("2006-01", period)
+	if err != nil {
+		return nil, fmt.Errorf("invalid period format, expected YYYY-MM")
+	}
+
+	start := periodStart.AddDate(0, -months, 0)
+	end := periodStart.Add(-time.Second)
+
+	transactions, err := s.transactionRepo.ListByPeriod(ctx, start.Format(time.RFC3339), end.Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	totals := make(map[string]int64)
+	for _, txn := range transactions {
+		if txn.CategoryID != nil && *txn.CategoryID != "" && txn.Amount < 0 {
+			totals[*txn.CategoryID] += -txn.Amount
+		}
+	}
+
+	for categoryID, total := range totals {
+		totals[categoryID] = total / int64(months)
+	}
+
+	return totals, nil
+}
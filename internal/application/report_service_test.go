@@ -0,0 +1,121 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// datedTransactionRepository bounds ListByPeriod by date, as the SQLite repository does
+type datedTransactionRepository struct {
+	*mockTransactionRepository
+}
+
+func (m *datedTransactionRepository) ListByPeriod(ctx context.Context, startDate, endDate string) ([]*domain.Transaction, error) {
+	start, _ := time.Parse(time.RFC3339, startDate)
+	end, _ := time.Parse(time.RFC3339, endDate)
+	var result []*domain.Transaction
+	for _, t := range m.transactions {
+		if !t.Date.Before(start) && !t.Date.After(end) {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+func TestReportService_GetBareBonesBudget(t *testing.T) {
+	ctx := context.Background()
+	card := "card"
+	categoryRepo := newMockCategoryRepository()
+	for _, category := range []*domain.Category{
+		{ID: "rent", Name: "Rent", Classification: domain.ClassificationEssential},
+		{ID: "groceries", Name: "Groceries", Classification: domain.ClassificationEssential},
+		{ID: "visa", Name: "Visa Payment", Classification: domain.ClassificationDebt, PaymentForAccountID: &card},
+		{ID: "dining", Name: "Dining", Classification: domain.ClassificationDiscretionary},
+		{ID: "vacation", Name: "Vacation", Classification: domain.ClassificationSavings},
+	} {
+		categoryRepo.categories[category.ID] = category
+	}
+
+	allocationRepo := newMockAllocationRepository()
+	for _, allocation := range []*domain.Allocation{
+		{ID: "a1", CategoryID: "rent", Period: "2025-04", Amount: 120000},
+		{ID: "a2", CategoryID: "groceries", Period: "2025-04", Amount: 30000},
+		{ID: "a3", CategoryID: "visa", Period: "2025-04", Amount: 20000},
+		{ID: "a4", CategoryID: "dining", Period: "2025-04", Amount: 15000},
+		{ID: "a5", CategoryID: "vacation", Period: "2025-04", Amount: 50000},
+		{ID: "a6", CategoryID: "rent", Period: "2025-03", Amount: 999999}, // Another period
+	} {
+		allocationRepo.Create(ctx, allocation)
+	}
+
+	transactionRepo := &datedTransactionRepository{newMockTransactionRepository()}
+	spend := func(categoryID string, date string, amount int64) {
+		when, _ := time.Parse("2006-01-02", date)
+		transactionRepo.transactions = append(transactionRepo.transactions, &domain.Transaction{
+			ID: categoryID + date, CategoryID: &categoryID, Amount: amount, Date: when, Type: domain.TransactionTypeNormal,
+		})
+	}
+	for _, month := range []string{"2025-01", "2025-02", "2025-03"} {
+		spend("rent", month+"-01", -100000)
+		spend("groceries", month+"-15", -45000)
+		spend("dining", month+"-20", -30000)
+	}
+	spend("groceries", "2024-12-15", -85000)  // Only counted when looking back four months
+	spend("groceries", "2025-04-02", -500000) // The reported period itself isn't trailing spending
+	spend("groceries", "2025-03-30", 5000)    // Refunds aren't spending
+
+	service := NewReportService(categoryRepo, allocationRepo, transactionRepo, nil, nil, nil, nil)
+
+	report, err := service.GetBareBonesBudget(ctx, "2025-04", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.TrailingMonths != 3 {
+		t.Errorf("expected 3 trailing months by default, got %d", report.TrailingMonths)
+	}
+
+	needs := make(map[string]*BareBonesCategory)
+	for _, category := range report.Categories {
+		needs[category.CategoryID] = category
+	}
+	if len(needs) != 3 || needs["dining"] != nil || needs["vacation"] != nil {
+		t.Fatalf("expected only essential and debt categories funded, got %+v", report.Categories)
+	}
+	// The larger of the allocation and the trailing average is needed
+	if rent := needs["rent"]; rent.AverageSpent != 100000 || rent.Allocated != 120000 || rent.MinimumNeed != 120000 {
+		t.Errorf("expected rent to keep its larger allocation, got %+v", rent)
+	}
+	if groceries := needs["groceries"]; groceries.AverageSpent != 45000 || groceries.MinimumNeed != 45000 {
+		t.Errorf("expected groceries to need their average spending, got %+v", groceries)
+	}
+	if visa := needs["visa"]; visa.Classification != domain.ClassificationDebt || visa.MinimumNeed != 20000 {
+		t.Errorf("expected the card payment kept as debt, got %+v", visa)
+	}
+	if report.MinimumMonthlyNeed != 185000 {
+		t.Errorf("expected a minimum need of 185000, got %d", report.MinimumMonthlyNeed)
+	}
+	if report.CurrentAllocated != 235000 || report.DiscretionaryCut != 65000 {
+		t.Errorf("expected 235000 allocated with 65000 cut, got %d and %d", report.CurrentAllocated, report.DiscretionaryCut)
+	}
+
+	// A longer look back takes in December and averages over four months
+	report, err = service.GetBareBonesBudget(ctx, "2025-04", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, category := range report.Categories {
+		if category.CategoryID == "groceries" && category.AverageSpent != (3*45000+85000)/4 {
+			t.Errorf("expected December counted over four months, got %+v", category)
+		}
+		if category.CategoryID == "rent" && category.AverageSpent != 75000 {
+			t.Errorf("expected rent averaged over four months, got %+v", category)
+		}
+	}
+
+	if _, err := service.GetBareBonesBudget(ctx, "April", 3); err == nil {
+		t.Error("expected an invalid period to be refused")
+	}
+}
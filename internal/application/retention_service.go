@@ -0,0 +1,162 @@
+package application
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// Retention rule names, as shown in retention reports
+const (
+	RetentionAuditLog          = "audit_log"
+	RetentionRejectedPending   = "rejected_pending_transactions"
+	RetentionExpiredSessions   = "expired_sessions"
+	RetentionExpiredUserTokens = "expired_user_tokens"
+)
+
+// RetentionPolicy sets how many days each kind of derived data is kept
+// 0 keeps that kind of data forever.
+type RetentionPolicy struct {
+	AuditDays           int // Audit log entries, by when they were written
+	RejectedPendingDays int // Rejected pending transactions, by when they were rejected
+	ExpiredTokenDays    int // Sessions and emailed tokens, by when they expired
+}
+
+// RetentionResult is what one retention rule removed, or would remove
+type RetentionResult struct {
+	Name          string    `json:"name"`
+	RetentionDays int       `json:"retention_days"`
+	Cutoff        time.Time `json:"cutoff"` // Data older than this is removed
+	Count         int64     `json:"count"`
+}
+
+// RetentionReport lists the outcome of every enabled retention rule
+type RetentionReport struct {
+	DryRun  bool              `json:"dry_run"`
+	RanAt   time.Time         `json:"ran_at"`
+	Results []RetentionResult `json:"results"`
+}
+
+// RetentionService removes derived data once it is older than the retention policy allows
+// Budget data itself (accounts, transactions, allocations) is never pruned.
+type RetentionService struct {
+	auditRepo     domain.AuditRepository
+	pendingRepo   domain.PendingTransactionRepository
+	sessionRepo   domain.SessionRepository
+	userTokenRepo domain.UserTokenRepository
+	policy        RetentionPolicy
+}
+
+// NewRetentionService creates a new retention service
+func NewRetentionService(
+	auditRepo domain.AuditRepository,
+	pendingRepo domain.PendingTransactionRepository,
+	sessionRepo domain.SessionRepository,
+	userTokenRepo domain.UserTokenRepository,
+	policy RetentionPolicy,
+) *RetentionService {
+	return &RetentionService{
+		auditRepo:     auditRepo,
+		pendingRepo:   pendingRepo,
+		sessionRepo:   sessionRepo,
+		userTokenRepo: userTokenRepo,
+		policy:        policy,
+	}
+}
+
+// retentionRule counts or removes one kind of data older than a cutoff
+type retentionRule struct {
+	name  string
+	days  int
+	count func(ctx context.Context, cutoff time.Time) (int64, error)
+	prune func(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+func (s *RetentionService) rules() []retentionRule {
+	return []retentionRule{
+		{
+			name:  RetentionAuditLog,
+			days:  s.policy.AuditDays,
+			count: s.auditRepo.CountBefore,
+			prune: s.auditRepo.DeleteBefore,
+		},
+		{
+			name: RetentionRejectedPending,
+			days: s.policy.RejectedPendingDays,
+			count: func(ctx context.Context, cutoff time.Time) (int64, error) {
+				return s.pendingRepo.CountByStatusBefore(ctx, domain.PendingStatusRejected, cutoff)
+			},
+			prune: func(ctx context.Context, cutoff time.Time) (int64, error) {
+				return s.pendingRepo.DeleteByStatusBefore(ctx, domain.PendingStatusRejected, cutoff)
+			},
+		},
+		{
+			name:  RetentionExpiredSessions,
+			days:  s.policy.ExpiredTokenDays,
+			count: s.sessionRepo.CountExpired,
+			prune: func(ctx context.Context, cutoff time.Time) (int64, error) {
+				count, err := s.sessionRepo.CountExpired(ctx, cutoff)
+				if err != nil {
+					return 0, err
+				}
+				return count, s.sessionRepo.DeleteExpired(ctx, cutoff)
+			},
+		},
+		{
+			name:  RetentionExpiredUserTokens,
+			days:  s.policy.ExpiredTokenDays,
+			count: s.userTokenRepo.CountExpired,
+			prune: s.userTokenRepo.DeleteExpired,
+		},
+	}
+}
+
+// Report returns what Prune would remove right now, without removing anything
+func (s *RetentionService) Report(ctx context.Context) (*RetentionReport, error) {
+	return s.run(ctx, true)
+}
+
+// Prune removes everything past its retention period
+// Suitable as a scheduler job.
+func (s *RetentionService) Prune(ctx context.Context) error {
+	report, err := s.run(ctx, false)
+	if err != nil {
+		return err
+	}
+	for _, result := range report.Results {
+		if result.Count > 0 {
+			log.Printf("retention: removed %d %s older than %d days", result.Count, result.Name, result.RetentionDays)
+		}
+	}
+	return nil
+}
+
+func (s *RetentionService) run(ctx context.Context, dryRun bool) (*RetentionReport, error) {
+	now := time.Now().UTC()
+	report := &RetentionReport{DryRun: dryRun, RanAt: now, Results: []RetentionResult{}}
+
+	for _, rule := range s.rules() {
+		if rule.days <= 0 {
+			continue
+		}
+		cutoff := now.AddDate(0, 0, -rule.days)
+
+		apply := rule.prune
+		if dryRun {
+			apply = rule.count
+		}
+		count, err := apply(ctx, cutoff)
+		if err != nil {
+			return nil, err
+		}
+		report.Results = append(report.Results, RetentionResult{
+			Name:          rule.name,
+			RetentionDays: rule.days,
+			Cutoff:        cutoff,
+			Count:         count,
+		})
+	}
+	return report, nil
+}
@@ -0,0 +1,241 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type mockPendingTransactionRepository struct {
+	pending []*domain.PendingTransaction
+}
+
+func (m *mockPendingTransactionRepository) Create(ctx context.Context, pending *domain.PendingTransaction) error {
+	m.pending = append(m.pending, pending)
+	return nil
+}
+
+func (m *mockPendingTransactionRepository) GetByID(ctx context.Context, id string) (*domain.PendingTransaction, error) {
+	for _, pending := range m.pending {
+		if pending.ID == id {
+			return pending, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockPendingTransactionRepository) List(ctx context.Context, status domain.PendingTransactionStatus) ([]*domain.PendingTransaction, error) {
+	return m.pending, nil
+}
+
+func (m *mockPendingTransactionRepository) Update(ctx context.Context, pending *domain.PendingTransaction) error {
+	return nil
+}
+
+func (m *mockPendingTransactionRepository) Delete(ctx context.Context, id string) error {
+	return nil
+}
+
+func (m *mockPendingTransactionRepository) CountByStatusBefore(ctx context.Context, status domain.PendingTransactionStatus, before time.Time) (int64, error) {
+	var count int64
+	for _, pending := range m.pending {
+		if pending.Status == status && pending.UpdatedAt.Before(before) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockPendingTransactionRepository) DeleteByStatusBefore(ctx context.Context, status domain.PendingTransactionStatus, before time.Time) (int64, error) {
+	var kept []*domain.PendingTransaction
+	for _, pending := range m.pending {
+		if pending.Status != status || !pending.UpdatedAt.Before(before) {
+			kept = append(kept, pending)
+		}
+	}
+	deleted := int64(len(m.pending) - len(kept))
+	m.pending = kept
+	return deleted, nil
+}
+
+type mockSessionRepository struct {
+	sessions []*domain.Session
+}
+
+func (m *mockSessionRepository) Create(ctx context.Context, session *domain.Session) error {
+	m.sessions = append(m.sessions, session)
+	return nil
+}
+
+func (m *mockSessionRepository) GetByHash(ctx context.Context, tokenHash string) (*domain.Session, error) {
+	for _, session := range m.sessions {
+		if session.TokenHash == tokenHash {
+			return session, nil
+		}
+	}
+	return nil, errors.New("session not found")
+}
+
+func (m *mockSessionRepository) ListByUser(ctx context.Context, userID string) ([]*domain.Session, error) {
+	return m.sessions, nil
+}
+
+func (m *mockSessionRepository) UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error {
+	return nil
+}
+
+func (m *mockSessionRepository) Delete(ctx context.Context, id string) error {
+	m.sessions = slices.DeleteFunc(m.sessions, func(session *domain.Session) bool { return session.ID == id })
+	return nil
+}
+
+func (m *mockSessionRepository) DeleteByUser(ctx context.Context, userID, exceptID string) error {
+	m.sessions = slices.DeleteFunc(m.sessions, func(session *domain.Session) bool {
+		return session.UserID == userID && session.ID != exceptID
+	})
+	return nil
+}
+
+func (m *mockSessionRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	var kept []*domain.Session
+	for _, session := range m.sessions {
+		if !session.ExpiresAt.Before(now) {
+			kept = append(kept, session)
+		}
+	}
+	m.sessions = kept
+	return nil
+}
+
+func (m *mockSessionRepository) CountExpired(ctx context.Context, now time.Time) (int64, error) {
+	var count int64
+	for _, session := range m.sessions {
+		if session.ExpiresAt.Before(now) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+type mockUserTokenRepository struct {
+	tokens []*domain.UserToken
+}
+
+func (m *mockUserTokenRepository) Create(ctx context.Context, token *domain.UserToken) error {
+	m.tokens = append(m.tokens, token)
+	return nil
+}
+
+func (m *mockUserTokenRepository) GetByHash(ctx context.Context, purpose domain.UserTokenPurpose, tokenHash string) (*domain.UserToken, error) {
+	for _, token := range m.tokens {
+		if token.Purpose == purpose && token.TokenHash == tokenHash {
+			return token, nil
+		}
+	}
+	return nil, errors.New("token not found")
+}
+
+func (m *mockUserTokenRepository) MarkUsed(ctx context.Context, id string, usedAt time.Time) error {
+	for _, token := range m.tokens {
+		if token.ID == id {
+			token.UsedAt = &usedAt
+		}
+	}
+	return nil
+}
+
+func (m *mockUserTokenRepository) DeleteByUser(ctx context.Context, userID string, purpose domain.UserTokenPurpose) error {
+	return nil
+}
+
+func (m *mockUserTokenRepository) CountExpired(ctx context.Context, before time.Time) (int64, error) {
+	var count int64
+	for _, token := range m.tokens {
+		if token.ExpiresAt.Before(before) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockUserTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	var kept []*domain.UserToken
+	for _, token := range m.tokens {
+		if !token.ExpiresAt.Before(before) {
+			kept = append(kept, token)
+		}
+	}
+	deleted := int64(len(m.tokens) - len(kept))
+	m.tokens = kept
+	return deleted, nil
+}
+
+func TestRetentionService(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+
+	auditRepo := &mockAuditRepository{}
+	auditRepo.Create(ctx, &domain.AuditEntry{CreatedAt: daysAgo(40)})
+	auditRepo.Create(ctx, &domain.AuditEntry{CreatedAt: daysAgo(1)})
+
+	pendingRepo := &mockPendingTransactionRepository{}
+	pendingRepo.Create(ctx, &domain.PendingTransaction{ID: "old-rejected", Status: domain.PendingStatusRejected, UpdatedAt: daysAgo(20)})
+	pendingRepo.Create(ctx, &domain.PendingTransaction{ID: "new-rejected", Status: domain.PendingStatusRejected, UpdatedAt: daysAgo(2)})
+	pendingRepo.Create(ctx, &domain.PendingTransaction{ID: "old-pending", Status: domain.PendingStatusPending, UpdatedAt: daysAgo(20)})
+
+	sessionRepo := &mockSessionRepository{}
+	sessionRepo.Create(ctx, &domain.Session{ID: "long-expired", ExpiresAt: daysAgo(10)})
+	sessionRepo.Create(ctx, &domain.Session{ID: "just-expired", ExpiresAt: daysAgo(1)})
+
+	userTokenRepo := &mockUserTokenRepository{}
+	userTokenRepo.Create(ctx, &domain.UserToken{ID: "long-expired", ExpiresAt: daysAgo(10)})
+
+	// Audit entries are kept forever, so there is no rule for them
+	service := NewRetentionService(auditRepo, pendingRepo, sessionRepo, userTokenRepo, RetentionPolicy{
+		RejectedPendingDays: 14,
+		ExpiredTokenDays:    7,
+	})
+
+	report, err := service.Report(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int64)
+	for _, result := range report.Results {
+		counts[result.Name] = result.Count
+	}
+	if _, ok := counts[RetentionAuditLog]; ok || len(counts) != 3 {
+		t.Errorf("expected only enabled rules to be reported, got %v", counts)
+	}
+	if counts[RetentionRejectedPending] != 1 || counts[RetentionExpiredSessions] != 1 || counts[RetentionExpiredUserTokens] != 1 {
+		t.Errorf("unexpected dry run counts: %v", counts)
+	}
+	if !report.DryRun || len(pendingRepo.pending) != 3 || len(sessionRepo.sessions) != 2 || len(userTokenRepo.tokens) != 1 {
+		t.Fatal("expected a dry run to leave data alone")
+	}
+
+	if err := service.Prune(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(pendingRepo.pending) != 2 || pendingRepo.pending[0].ID != "new-rejected" || pendingRepo.pending[1].ID != "old-pending" {
+		t.Errorf("expected only the old rejected transaction to be removed, got %d left", len(pendingRepo.pending))
+	}
+	if len(sessionRepo.sessions) != 1 || sessionRepo.sessions[0].ID != "just-expired" {
+		t.Errorf("expected only the long-expired session to be removed, got %d left", len(sessionRepo.sessions))
+	}
+	if len(userTokenRepo.tokens) != 0 || len(auditRepo.entries) != 2 {
+		t.Errorf("unexpected data left: %d tokens, %d audit entries", len(userTokenRepo.tokens), len(auditRepo.entries))
+	}
+
+	if err := NewRetentionService(auditRepo, pendingRepo, sessionRepo, userTokenRepo, RetentionPolicy{AuditDays: 30}).Prune(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(auditRepo.entries) != 1 || auditRepo.entries[0].ID != 2 {
+		t.Errorf("expected only the recent audit entry to remain, got %d", len(auditRepo.entries))
+	}
+}
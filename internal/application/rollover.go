@@ -0,0 +1,233 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// categoryMonth is one category's budget activity within a single period, usually a month
+type categoryMonth struct {
+	assigned    int64
+	moved       int64 // Moved in from other categories or Ready to Assign, see domain.CategoryMove
+	spent       int64 // All outflows, as a positive number
+	creditSpent int64 // The part of spent that went on credit cards
+}
+
+// categoryLedger holds each category's periods, keyed by category ID then period key
+// A ledger is built for one period type. Periods are taken in UTC, matching how the
+// repositories bound them.
+type categoryLedger map[string]map[string]*categoryMonth
+
+// rolledCategory is a category's position after walking its months up to a period
+type rolledCategory struct {
+	available int64 // Carried balance + this period's assigned - this period's spending
+	carried   int64 // What rolled in from earlier months (never negative)
+
+	// cashOverspent is the cash overspending left at the end of earlier months. Each
+	// month's overspending resets the category to zero and comes out of the next month's
+	// Ready to Assign instead. Credit overspending resets too, but stays on the card as
+	// debt the payment category has to cover.
+	cashOverspent int64
+
+	creditSpent int64 // This period's credit card spending, to split its own overspending
+}
+
+// loadCategoryLedger reads every allocation, category move and spending total into a ledger
+// of periodType periods
+func (s *AllocationService) loadCategoryLedger(ctx context.Context, periodType domain.PeriodType) (categoryLedger, error) {
+	allocations, err := s.allocationRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list allocations: %w", err)
+	}
+	spending, err := s.transactionRepo.SumSpendingByCategory(ctx, periodType)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := s.accountRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	ledger := buildCategoryLedger(periodType, allocations, spending, accounts)
+	if err := s.addCategoryMoves(ctx, ledger, periodType); err != nil {
+		return nil, err
+	}
+	return ledger, nil
+}
+
+// CategoryAvailable returns what each category has available at the end of period, as
+// the budget screen shows it: rolled over, with earlier overspending reset and moves counted
+// Categories that no longer exist are left out.
+func (s *AllocationService) CategoryAvailable(ctx context.Context, period string, categoryIDs []string) (map[string]int64, error) {
+	p, err := domain.ParsePeriod(period)
+	if err != nil {
+		return nil, err
+	}
+	ledger, err := s.loadCategoryLedger(ctx, p.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	available := make(map[string]int64, len(categoryIDs))
+	for _, id := range categoryIDs {
+		category, err := s.categoryRepo.GetByID(ctx, id)
+		if err != nil {
+			continue
+		}
+		isPayment := category.PaymentForAccountID != nil && *category.PaymentForAccountID != ""
+		available[id] = ledger.roll(id, p.Key, isPayment, category.OverspendingMode).available
+	}
+	return available, nil
+}
+
+// addCategoryMoves adds the moves into each category to ledger, when moves are kept
+func (s *AllocationService) addCategoryMoves(ctx context.Context, ledger categoryLedger, periodType domain.PeriodType) error {
+	if s.moveRepo == nil {
+		return nil
+	}
+	moves, err := s.moveRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	ledger.addMoves(periodType, moves)
+	return nil
+}
+
+// periodKeyIn returns the key of the period of type t that the period key starts in
+// Keys that can't be read are returned as they are.
+func periodKeyIn(t domain.PeriodType, key string) string {
+	period, err := domain.ParsePeriod(key)
+	if err != nil {
+		return key
+	}
+	return period.In(t).Key
+}
+
+// buildCategoryLedger builds a ledger of periodType periods
+// spending must already be totalled by periodType; allocations of other period types count
+// from the period they start in.
+func buildCategoryLedger(periodType domain.PeriodType, allocations []*domain.Allocation, spending []*domain.CategorySpending, accounts []*domain.Account) categoryLedger {
+	creditAccounts := make(map[string]bool)
+	for _, account := range accounts {
+		if account.Type == domain.AccountTypeCredit {
+			creditAccounts[account.ID] = true
+		}
+	}
+
+	ledger := make(categoryLedger)
+	month := func(categoryID, period string) *categoryMonth {
+		months, ok := ledger[categoryID]
+		if !ok {
+			months = make(map[string]*categoryMonth)
+			ledger[categoryID] = months
+		}
+		m, ok := months[period]
+		if !ok {
+			m = &categoryMonth{}
+			months[period] = m
+		}
+		return m
+	}
+
+	for _, alloc := range allocations {
+		month(alloc.CategoryID, periodKeyIn(periodType, alloc.Period)).assigned += alloc.Amount
+	}
+	// Transfers count too: moving money out of a category spends it
+	for _, total := range spending {
+		m := month(total.CategoryID, total.Period)
+		m.spent += total.Spent
+		if creditAccounts[total.AccountID] {
+			m.creditSpent += total.Spent
+		}
+	}
+	return ledger
+}
+
+// addMoves adds each move to the category it moved money into
+// The category it came from, if any, already spent it - see domain.CategoryMove.
+func (l categoryLedger) addMoves(periodType domain.PeriodType, moves []*domain.CategoryMove) {
+	for _, move := range moves {
+		period := periodKeyIn(periodType, move.Period)
+		months, ok := l[move.ToCategoryID]
+		if !ok {
+			months = make(map[string]*categoryMonth)
+			l[move.ToCategoryID] = months
+		}
+		if months[period] == nil {
+			months[period] = &categoryMonth{}
+		}
+		months[period].moved += move.Amount
+	}
+}
+
+// roll walks a category's periods in order up to and including period
+// Payment categories roll over without resetting: their balance is checked against the
+// card instead (see summarizeCategory). mode splits overspending at each month's end.
+func (l categoryLedger) roll(categoryID, period string, isPayment bool, mode domain.OverspendingMode) rolledCategory {
+	months := l[categoryID]
+	periods := make([]string, 0, len(months))
+	for p := range months {
+		if p <= period {
+			periods = append(periods, p)
+		}
+	}
+	sort.Strings(periods)
+
+	var rolled rolledCategory
+	for _, p := range periods {
+		m := months[p]
+		balance := rolled.carried + m.assigned + m.moved - m.spent
+		if p == period {
+			rolled.available = balance
+			rolled.creditSpent = m.creditSpent
+			break
+		}
+		if balance >= 0 || isPayment {
+			rolled.carried = balance
+			continue
+		}
+
+		// Overspent at the end of the month: the category starts the next month at zero
+		cash, _ := splitOverspending(-balance, m.creditSpent, mode)
+		rolled.cashOverspent += cash
+		rolled.carried = 0
+	}
+	if _, ok := months[period]; !ok {
+		rolled.available = rolled.carried
+	}
+	return rolled
+}
+
+// splitOverspending divides a month's overspending into cash and credit parts
+// In auto mode (or with no mode), overspending up to the month's credit spending is card
+// debt and the rest was cash. The other modes put all of it on one side.
+func splitOverspending(overspent, creditSpent int64, mode domain.OverspendingMode) (cash, credit int64) {
+	switch mode {
+	case domain.OverspendingCash:
+		return overspent, 0
+	case domain.OverspendingCredit:
+		return 0, overspent
+	}
+	credit = min(overspent, creditSpent)
+	return overspent - credit, credit
+}
+
+// cashOverspentBefore totals cash overspending from the months before period
+// That's how much past overspending has been taken out of Ready to Assign by period.
+// Categories missing from categories, such as deleted ones, are split in auto mode.
+func (l categoryLedger) cashOverspentBefore(period string, categories map[string]*domain.Category) int64 {
+	var total int64
+	for categoryID := range l {
+		var mode domain.OverspendingMode
+		if category, ok := categories[categoryID]; ok {
+			if category.PaymentForAccountID != nil && *category.PaymentForAccountID != "" {
+				continue
+			}
+			mode = category.OverspendingMode
+		}
+		total += l.roll(categoryID, period, false, mode).cashOverspent
+	}
+	return total
+}
@@ -0,0 +1,58 @@
+package application
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Scheduler runs background jobs at fixed intervals
+// Each job runs once when the scheduler starts and then every interval.
+type Scheduler struct {
+	jobs []scheduledJob
+}
+
+type scheduledJob struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+}
+
+// NewScheduler creates an empty scheduler
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Every registers a job; call before Run
+func (s *Scheduler) Every(name string, interval time.Duration, run func(ctx context.Context) error) {
+	s.jobs = append(s.jobs, scheduledJob{name: name, interval: interval, run: run})
+}
+
+// Run runs the jobs until ctx is cancelled
+// Failures are logged and the job runs again at its next interval.
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range s.jobs {
+		wg.Add(1)
+		go func(job scheduledJob) {
+			defer wg.Done()
+
+			ticker := time.NewTicker(job.interval)
+			defer ticker.Stop()
+
+			for {
+				if err := job.run(ctx); err != nil {
+					log.Printf("scheduler: %s: %v", job.name, err)
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(job)
+	}
+	wg.Wait()
+}
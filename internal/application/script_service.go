@@ -0,0 +1,227 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// ScriptHookCategorize is the function a categorize script defines: categorize(txn) -> category
+const ScriptHookCategorize = "categorize"
+
+// ScriptRunner loads user scripts into a sandbox without I/O
+// Implemented by the Starlark runner in internal/infrastructure/script
+type ScriptRunner interface {
+	Load(ctx context.Context, source string) (Script, error)
+}
+
+// Script is a loaded user script
+// Calls are bounded by the runner's timeout and step limit.
+type Script interface {
+	Has(function string) bool
+	Call(ctx context.Context, function string, arg map[string]interface{}) (interface{}, error)
+}
+
+// CategorizeScript is the stored categorize script
+type CategorizeScript struct {
+	Source    string    `json:"source"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// ScriptTestResult is the outcome of running a categorize script against one transaction
+type ScriptTestResult struct {
+	Result   interface{}      `json:"result"`   // What categorize returned
+	Category *domain.Category `json:"category"` // The category it resolved to, if any
+}
+
+// ScriptService runs the user's categorize script on imported transactions
+// It takes part in import categorization alongside categorization plugins.
+type ScriptService struct {
+	settingRepo  domain.SettingRepository
+	categoryRepo domain.CategoryRepository
+	runner       ScriptRunner
+
+	mu     sync.Mutex
+	source string // Source the cached script was loaded from
+	script Script
+}
+
+// NewScriptService creates a new script service
+func NewScriptService(settingRepo domain.SettingRepository, categoryRepo domain.CategoryRepository, runner ScriptRunner) *ScriptService {
+	return &ScriptService{settingRepo: settingRepo, categoryRepo: categoryRepo, runner: runner}
+}
+
+// Name identifies the script among categorization providers
+func (s *ScriptService) Name() string {
+	return "script"
+}
+
+// GetCategorizeScript returns the stored categorize script; Source is empty when none is set
+func (s *ScriptService) GetCategorizeScript(ctx context.Context) (*CategorizeScript, error) {
+	setting, err := s.settingRepo.Get(ctx, domain.SettingKeyCategorizeScript)
+	if err != nil {
+		// Nothing saved yet
+		return &CategorizeScript{}, nil
+	}
+	return &CategorizeScript{Source: setting.Value, UpdatedAt: setting.UpdatedAt}, nil
+}
+
+// SetCategorizeScript checks and stores the categorize script; an empty source removes it
+func (s *ScriptService) SetCategorizeScript(ctx context.Context, source string) (*CategorizeScript, error) {
+	if strings.TrimSpace(source) != "" {
+		if _, err := s.load(ctx, source); err != nil {
+			return nil, err
+		}
+	}
+
+	setting := &domain.Setting{
+		Key:       domain.SettingKeyCategorizeScript,
+		Value:     source,
+		UpdatedAt: time.Now(),
+	}
+	if err := s.settingRepo.Set(ctx, setting); err != nil {
+		return nil, err
+	}
+	return &CategorizeScript{Source: setting.Value, UpdatedAt: setting.UpdatedAt}, nil
+}
+
+// TestCategorize runs a categorize script against one transaction without saving anything
+// An empty source tests the stored script.
+func (s *ScriptService) TestCategorize(ctx context.Context, source string, txn ImportedTransaction) (*ScriptTestResult, error) {
+	if strings.TrimSpace(source) == "" {
+		stored, err := s.GetCategorizeScript(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if stored.Source == "" {
+			return nil, fmt.Errorf("no categorize script is set")
+		}
+		source = stored.Source
+	}
+
+	script, err := s.load(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+
+	result, err := script.Call(ctx, ScriptHookCategorize, scriptTransaction(txn))
+	if err != nil {
+		return nil, err
+	}
+	category, err := resolveScriptCategory(result, categories)
+	if err != nil {
+		return nil, err
+	}
+	return &ScriptTestResult{Result: result, Category: category}, nil
+}
+
+// Categorize runs the stored script on each transaction
+// The script returns a category ID or name, or None to leave the transaction alone.
+// A failing call is logged and leaves that transaction uncategorized.
+func (s *ScriptService) Categorize(ctx context.Context, categories []*domain.Category, transactions []ImportedTransaction) ([]string, error) {
+	categoryIDs := make([]string, len(transactions))
+
+	script, err := s.storedScript(ctx)
+	if err != nil || script == nil {
+		return categoryIDs, err
+	}
+
+	for i, txn := range transactions {
+		result, err := script.Call(ctx, ScriptHookCategorize, scriptTransaction(txn))
+		if err != nil {
+			log.Printf("script: categorize %q: %v", txn.Description, err)
+			continue
+		}
+		category, err := resolveScriptCategory(result, categories)
+		if err != nil {
+			log.Printf("script: categorize %q: %v", txn.Description, err)
+			continue
+		}
+		if category != nil {
+			categoryIDs[i] = category.ID
+		}
+	}
+	return categoryIDs, nil
+}
+
+// storedScript returns the loaded stored script, or nil when none is set
+// The loaded script is reused until the stored source changes.
+func (s *ScriptService) storedScript(ctx context.Context) (Script, error) {
+	stored, err := s.GetCategorizeScript(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(stored.Source) == "" {
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.script != nil && s.source == stored.Source {
+		return s.script, nil
+	}
+
+	script, err := s.load(ctx, stored.Source)
+	if err != nil {
+		return nil, err
+	}
+	s.source, s.script = stored.Source, script
+	return script, nil
+}
+
+// load loads a script and checks that it defines the categorize hook
+func (s *ScriptService) load(ctx context.Context, source string) (Script, error) {
+	script, err := s.runner.Load(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	if !script.Has(ScriptHookCategorize) {
+		return nil, fmt.Errorf("script must define %s(txn)", ScriptHookCategorize)
+	}
+	return script, nil
+}
+
+// scriptTransaction is the txn argument scripts receive
+func scriptTransaction(txn ImportedTransaction) map[string]interface{} {
+	return map[string]interface{}{
+		"description": txn.Description,
+		"amount":      txn.Amount,
+		"date":        txn.Date.Format("2006-01-02"),
+		"fitid":       txn.FitID,
+	}
+}
+
+// resolveScriptCategory finds the category a script returned, by ID or by name
+func resolveScriptCategory(result interface{}, categories []*domain.Category) (*domain.Category, error) {
+	if result == nil {
+		return nil, nil
+	}
+	ref, ok := result.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s must return a category name, ID or None, got %v", ScriptHookCategorize, result)
+	}
+	if ref == "" {
+		return nil, nil
+	}
+
+	for _, category := range categories {
+		if category.ID == ref {
+			return category, nil
+		}
+	}
+	for _, category := range categories {
+		if strings.EqualFold(category.Name, ref) {
+			return category, nil
+		}
+	}
+	return nil, fmt.Errorf("category not found: %s", ref)
+}
@@ -0,0 +1,132 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// ErrSetupStepDone is returned when a first-run setup step has already been completed
+var ErrSetupStepDone = errors.New("this setup step is already complete")
+
+// SetupStatus reports which first-run setup steps are left
+// The admin user step is optional unless authentication is required.
+type SetupStatus struct {
+	Complete       bool              `json:"complete"`
+	NeedsAdminUser bool              `json:"needs_admin_user"` // No users exist yet
+	NeedsTemplate  bool              `json:"needs_template"`   // No starter template chosen and no categories exist
+	NeedsAccount   bool              `json:"needs_account"`    // No accounts exist yet
+	AuthRequired   bool              `json:"auth_required"`
+	Templates      []StarterTemplate `json:"templates,omitempty"` // Only while a template is needed
+}
+
+// SetupService drives first-run setup: creating the admin user, choosing a
+// starter template and adding the first account
+type SetupService struct {
+	userRepo         domain.UserRepository
+	accountRepo      domain.AccountRepository
+	authService      *AuthService
+	accountService   *AccountService
+	bootstrapService *BootstrapService
+	authRequired     bool
+
+	mu sync.Mutex // Serializes creating the admin user
+}
+
+// NewSetupService creates a new setup service
+func NewSetupService(
+	userRepo domain.UserRepository,
+	accountRepo domain.AccountRepository,
+	authService *AuthService,
+	accountService *AccountService,
+	bootstrapService *BootstrapService,
+	authRequired bool,
+) *SetupService {
+	return &SetupService{
+		userRepo:         userRepo,
+		accountRepo:      accountRepo,
+		authService:      authService,
+		accountService:   accountService,
+		bootstrapService: bootstrapService,
+		authRequired:     authRequired,
+	}
+}
+
+// GetStatus reports the remaining setup steps
+// Templates are described in the given language ("" for the default language).
+func (s *SetupService) GetStatus(ctx context.Context, locale string) (*SetupStatus, error) {
+	users, err := s.userRepo.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := s.accountRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bootstrap, err := s.bootstrapService.GetStatus(ctx, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &SetupStatus{
+		NeedsAdminUser: users == 0,
+		NeedsTemplate:  bootstrap.NeedsTemplate,
+		NeedsAccount:   len(accounts) == 0,
+		AuthRequired:   s.authRequired,
+	}
+	if status.NeedsTemplate {
+		status.Templates = bootstrap.Templates
+	}
+	status.Complete = !status.NeedsTemplate && !status.NeedsAccount &&
+		!(status.NeedsAdminUser && s.authRequired)
+	return status, nil
+}
+
+// CreateAdmin creates the first user as an admin and signs them in
+// Only allowed while no users exist.
+func (s *SetupService) CreateAdmin(ctx context.Context, email, name, password, userAgent, ipAddress string) (*domain.Session, string, *domain.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count, err := s.userRepo.Count(ctx)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if count > 0 {
+		return nil, "", nil, ErrSetupStepDone
+	}
+
+	user, err := s.authService.CreateUser(ctx, email, name, password, domain.UserRoleAdmin)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	session, raw, err := s.authService.StartSession(ctx, user, userAgent, ipAddress)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return session, raw, user, nil
+}
+
+// ChooseTemplate sets the budget up from a starter template
+func (s *SetupService) ChooseTemplate(ctx context.Context, templateID, locale string) (*domain.StarterTemplateSettings, error) {
+	applied, err := s.bootstrapService.ApplyTemplate(ctx, templateID, locale)
+	if errors.Is(err, ErrBudgetAlreadySetUp) {
+		return nil, ErrSetupStepDone
+	}
+	return applied, err
+}
+
+// CreateFirstAccount adds the budget's first account
+func (s *SetupService) CreateFirstAccount(ctx context.Context, name string, balance int64, accountType domain.AccountType) (*domain.Account, error) {
+	accounts, err := s.accountRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(accounts) > 0 {
+		return nil, ErrSetupStepDone
+	}
+	return s.accountService.CreateAccount(ctx, name, balance, accountType, nil)
+}
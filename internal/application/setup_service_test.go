@@ -0,0 +1,80 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestSetupService(t *testing.T) {
+	ctx := context.Background()
+	newService := func(authRequired bool) *SetupService {
+		userRepo := &mockUserRepository{}
+		accountRepo := newMockAccountRepository(0)
+		transactionRepo := newMockTransactionRepository()
+		categoryRepo := newMockCategoryRepository()
+		groupRepo := newMockCategoryGroupRepository()
+		authService := NewAuthService(userRepo, &mockSessionRepository{}, &mockAPITokenRepository{tokens: map[string]*domain.APIToken{}}, &mockRecoveryCodeRepository{codes: map[string][]*domain.RecoveryCode{}})
+		accountService := NewAccountService(accountRepo, categoryRepo, newMockBudgetStateRepository(0, 0), transactionRepo,
+			NewCategoryGroupService(groupRepo, categoryRepo), &mockUnitOfWork{accountRepo, transactionRepo})
+		bootstrapService := NewBootstrapService(groupRepo, categoryRepo, newMockSettingRepository(), "")
+		return NewSetupService(userRepo, accountRepo, authService, accountService, bootstrapService, authRequired)
+	}
+	status := func(service *SetupService) *SetupStatus {
+		t.Helper()
+		status, err := service.GetStatus(ctx, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return status
+	}
+
+	service := newService(true)
+	if s := status(service); s.Complete || !s.NeedsAdminUser || !s.NeedsTemplate || !s.NeedsAccount || !s.AuthRequired || len(s.Templates) == 0 {
+		t.Errorf("expected every step left on a new budget, got %+v", s)
+	}
+
+	if _, _, _, err := service.CreateAdmin(ctx, "admin@example.com", "Admin", "password123", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := service.CreateAdmin(ctx, "other@example.com", "Other", "password123", "", ""); !errors.Is(err, ErrSetupStepDone) {
+		t.Errorf("expected a second admin refused as done, got %v", err)
+	}
+
+	if _, err := service.ChooseTemplate(ctx, "nonexistent", ""); err == nil || errors.Is(err, ErrSetupStepDone) {
+		t.Errorf("expected an unknown template refused, got %v", err)
+	}
+	if _, err := service.ChooseTemplate(ctx, StarterTemplateMinimal, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.ChooseTemplate(ctx, StarterTemplateRenter, ""); !errors.Is(err, ErrSetupStepDone) {
+		t.Errorf("expected a second template refused as done, got %v", err)
+	}
+	if s := status(service); s.Complete || s.NeedsAdminUser || s.NeedsTemplate || !s.NeedsAccount || len(s.Templates) != 0 {
+		t.Errorf("expected only the account left, without templates listed, got %+v", s)
+	}
+
+	if _, err := service.CreateFirstAccount(ctx, "Checking", 100000, domain.AccountTypeChecking); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.CreateFirstAccount(ctx, "Savings", 0, domain.AccountTypeSavings); !errors.Is(err, ErrSetupStepDone) {
+		t.Errorf("expected a second first account refused as done, got %v", err)
+	}
+	if s := status(service); !s.Complete {
+		t.Errorf("expected setup complete, got %+v", s)
+	}
+
+	// Without required authentication the admin user can be skipped
+	service = newService(false)
+	if _, err := service.ChooseTemplate(ctx, StarterTemplateEmpty, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.CreateFirstAccount(ctx, "Checking", 0, domain.AccountTypeChecking); err != nil {
+		t.Fatal(err)
+	}
+	if s := status(service); !s.Complete || !s.NeedsAdminUser {
+		t.Errorf("expected setup complete without an admin user, got %+v", s)
+	}
+}
@@ -0,0 +1,357 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/google/uuid"
+)
+
+// IdentityClaims is what an external identity provider asserts about a signed-in user
+type IdentityClaims struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Groups        []string // Values of the provider's membership claim, e.g. "groups"
+}
+
+// IdentityProvider runs the OIDC authorization code flow against an external provider
+// Implemented in internal/infrastructure/oidc
+type IdentityProvider interface {
+	Name() string
+	AuthCodeURL(state, nonce, codeVerifier string) string
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*IdentityClaims, error)
+}
+
+// SSOMembership maps identity provider claims to budget membership
+// When AdminGroups or MemberGroups are set, the role is managed by the provider
+// and updated on every sign-in.
+type SSOMembership struct {
+	AutoProvision bool            // Create users on first sign-in
+	DefaultRole   domain.UserRole // Role for users when no groups are configured
+	AdminGroups   []string        // Claim values that grant the admin role
+	MemberGroups  []string        // Claim values that grant the member role; when set, anyone else is refused
+}
+
+// SSOState holds the per-attempt values that must round-trip through the browser
+type SSOState struct {
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+const (
+	// SSOChallengeLifetime is how long a user has to enter their two-factor code after
+	// signing in at the identity provider
+	SSOChallengeLifetime = 5 * time.Minute
+
+	// ssoChallengeAttempts is how many codes can be tried before signing in again
+	ssoChallengeAttempts = 5
+)
+
+// ErrSSOChallengeExpired is returned by CompleteTwoFactor when the challenge is unknown,
+// expired or out of attempts
+var ErrSSOChallengeExpired = errors.New("sign-in expired, please try again")
+
+// SSOSignIn is the outcome of a single sign-on callback
+// Users with two-factor enabled get a Challenge instead of a session, to be finished
+// with CompleteTwoFactor; everyone else gets the session and its raw token.
+type SSOSignIn struct {
+	Session   *domain.Session
+	Token     string
+	User      *domain.User
+	Challenge string
+}
+
+// ssoChallenge is a sign-in the identity provider vouched for that still needs a second factor
+type ssoChallenge struct {
+	userID    string
+	expiresAt time.Time
+	attempts  int
+}
+
+// SSOService signs users in through an external identity provider
+type SSOService struct {
+	users       *identityUsers
+	authService *AuthService
+	provider    IdentityProvider // nil when single sign-on isn't configured
+
+	mu         sync.Mutex
+	challenges map[string]*ssoChallenge // By challenge token
+}
+
+// NewSSOService creates a new single sign-on service
+func NewSSOService(
+	userRepo domain.UserRepository,
+	identityRepo domain.UserIdentityRepository,
+	authService *AuthService,
+	provider IdentityProvider,
+	membership SSOMembership,
+) *SSOService {
+	return &SSOService{
+		users:       &identityUsers{userRepo: userRepo, identityRepo: identityRepo, membership: membership},
+		authService: authService,
+		provider:    provider,
+		challenges:  make(map[string]*ssoChallenge),
+	}
+}
+
+// Enabled reports whether an identity provider is configured
+func (s *SSOService) Enabled() bool {
+	return s.provider != nil
+}
+
+// ProviderName returns the display name of the identity provider
+func (s *SSOService) ProviderName() string {
+	if s.provider == nil {
+		return ""
+	}
+	return s.provider.Name()
+}
+
+// BeginSignIn returns the provider URL to redirect to and the state to keep until the callback
+func (s *SSOService) BeginSignIn() (string, *SSOState, error) {
+	if s.provider == nil {
+		return "", nil, fmt.Errorf("single sign-on is not configured")
+	}
+
+	state := &SSOState{}
+	for _, value := range []*string{&state.State, &state.Nonce, &state.CodeVerifier} {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return "", nil, fmt.Errorf("failed to generate sign-in state: %w", err)
+		}
+		*value = base64.RawURLEncoding.EncodeToString(secret)
+	}
+
+	return s.provider.AuthCodeURL(state.State, state.Nonce, state.CodeVerifier), state, nil
+}
+
+// CompleteSignIn exchanges the authorization code and signs in the matching user
+// Users are matched by linked identity, then by verified email; unknown users are created
+// when auto-provisioning is on. The identity provider stands in for the password only:
+// users with two-factor enabled get a challenge to finish with CompleteTwoFactor.
+func (s *SSOService) CompleteSignIn(ctx context.Context, code string, state *SSOState, userAgent, ipAddress string) (*SSOSignIn, error) {
+	if s.provider == nil {
+		return nil, fmt.Errorf("single sign-on is not configured")
+	}
+
+	claims, err := s.provider.Exchange(ctx, code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.resolve(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		challenge, err := s.issueChallenge(user)
+		if err != nil {
+			return nil, err
+		}
+		return &SSOSignIn{User: user, Challenge: challenge}, nil
+	}
+
+	session, raw, err := s.authService.StartSession(ctx, user, userAgent, ipAddress)
+	if err != nil {
+		return nil, err
+	}
+	return &SSOSignIn{Session: session, Token: raw, User: user}, nil
+}
+
+// CompleteTwoFactor checks a TOTP or recovery code against a single sign-on challenge
+// and starts the session
+// A wrong code leaves the challenge open until it runs out of attempts.
+func (s *SSOService) CompleteTwoFactor(ctx context.Context, challenge, code, userAgent, ipAddress string) (*domain.Session, string, *domain.User, error) {
+	s.mu.Lock()
+	pending, ok := s.challenges[challenge]
+	if ok && (time.Now().After(pending.expiresAt) || pending.attempts >= ssoChallengeAttempts) {
+		delete(s.challenges, challenge)
+		ok = false
+	}
+	if ok {
+		pending.attempts++
+	}
+	s.mu.Unlock()
+	if !ok {
+		return nil, "", nil, ErrSSOChallengeExpired
+	}
+
+	// Reloaded so a user disabled, or with two-factor reset, in the meantime is seen
+	user, err := s.users.userRepo.GetByID(ctx, pending.userID)
+	if err != nil {
+		return nil, "", nil, ErrSSOChallengeExpired
+	}
+	if user.IsDisabled() {
+		return nil, "", nil, ErrUserDisabled
+	}
+	if user.TOTPEnabled {
+		if strings.TrimSpace(code) == "" {
+			return nil, "", nil, ErrTwoFactorRequired
+		}
+		if err := s.authService.verifySecondFactor(ctx, user, code); err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	s.mu.Lock()
+	delete(s.challenges, challenge)
+	s.mu.Unlock()
+
+	session, raw, err := s.authService.StartSession(ctx, user, userAgent, ipAddress)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return session, raw, user, nil
+}
+
+// issueChallenge remembers a sign-in awaiting its second factor, dropping expired ones
+func (s *SSOService) issueChallenge(user *domain.User) (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate sign-in challenge: %w", err)
+	}
+	challenge := base64.RawURLEncoding.EncodeToString(secret)
+
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for token, pending := range s.challenges {
+		if now.After(pending.expiresAt) {
+			delete(s.challenges, token)
+		}
+	}
+	s.challenges[challenge] = &ssoChallenge{userID: user.ID, expiresAt: now.Add(SSOChallengeLifetime)}
+	return challenge, nil
+}
+
+// identityUsers maps identities asserted by an external system (an OIDC provider or a
+// trusted proxy) to budget users, linking and provisioning them as configured
+type identityUsers struct {
+	userRepo     domain.UserRepository
+	identityRepo domain.UserIdentityRepository
+	membership   SSOMembership
+}
+
+// resolve returns the user for the claims, applying membership and role mapping
+func (u *identityUsers) resolve(ctx context.Context, claims *IdentityClaims) (*domain.User, error) {
+	role, ok := u.membership.resolveRole(claims.Groups)
+	if !ok {
+		return nil, fmt.Errorf("your account is not a member of this budget")
+	}
+
+	user, err := u.findOrCreate(ctx, claims, role)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsDisabled() {
+		return nil, ErrUserDisabled
+	}
+
+	if u.membership.managesRoles() && user.Role != role {
+		user.Role = role
+		user.UpdatedAt = time.Now()
+		if err := u.userRepo.Update(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+func (u *identityUsers) findOrCreate(ctx context.Context, claims *IdentityClaims, role domain.UserRole) (*domain.User, error) {
+	if identity, err := u.identityRepo.GetBySubject(ctx, claims.Issuer, claims.Subject); err == nil {
+		return u.userRepo.GetByID(ctx, identity.UserID)
+	}
+
+	// Link an existing account only when the provider vouches for the address
+	if claims.EmailVerified && claims.Email != "" {
+		if user, err := u.userRepo.GetByEmail(ctx, claims.Email); err == nil {
+			if err := u.link(ctx, user, claims); err != nil {
+				return nil, err
+			}
+			return user, nil
+		}
+	}
+
+	if !u.membership.AutoProvision {
+		return nil, fmt.Errorf("no budget account exists for this user")
+	}
+	if claims.Email == "" {
+		return nil, fmt.Errorf("the identity provider did not share an email address")
+	}
+	if _, err := u.userRepo.GetByEmail(ctx, claims.Email); err == nil {
+		return nil, fmt.Errorf("a user with this email already exists; verify the email with your identity provider to link it")
+	}
+
+	now := time.Now()
+	user := &domain.User{
+		ID:        uuid.New().String(),
+		Email:     claims.Email,
+		Name:      claims.Name,
+		Role:      role,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if claims.EmailVerified {
+		user.EmailVerifiedAt = &now
+	}
+	if err := u.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	if err := u.link(ctx, user, claims); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (u *identityUsers) link(ctx context.Context, user *domain.User, claims *IdentityClaims) error {
+	return u.identityRepo.Create(ctx, &domain.UserIdentity{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		Issuer:    claims.Issuer,
+		Subject:   claims.Subject,
+		CreatedAt: time.Now(),
+	})
+}
+
+// managesRoles reports whether roles come from provider groups
+func (m SSOMembership) managesRoles() bool {
+	return len(m.AdminGroups) > 0 || len(m.MemberGroups) > 0
+}
+
+// resolveRole maps the user's groups to a role; false means the user isn't a member
+func (m SSOMembership) resolveRole(groups []string) (domain.UserRole, bool) {
+	if containsAny(groups, m.AdminGroups) {
+		return domain.UserRoleAdmin, true
+	}
+	if len(m.MemberGroups) > 0 {
+		return domain.UserRoleMember, containsAny(groups, m.MemberGroups)
+	}
+	if len(m.AdminGroups) > 0 {
+		return domain.UserRoleMember, true
+	}
+	return m.DefaultRole, true
+}
+
+// containsAny reports whether any value appears in wanted, ignoring case
+func containsAny(values, wanted []string) bool {
+	for _, v := range values {
+		for _, w := range wanted {
+			if strings.EqualFold(v, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,217 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type fakeIdentityProvider struct {
+	claims map[string]*IdentityClaims // By authorization code
+}
+
+func (p *fakeIdentityProvider) Name() string { return "Fake" }
+
+func (p *fakeIdentityProvider) AuthCodeURL(state, nonce, codeVerifier string) string {
+	return "https://idp.example.com/authorize?state=" + state
+}
+
+func (p *fakeIdentityProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*IdentityClaims, error) {
+	claims, ok := p.claims[code]
+	if !ok {
+		return nil, errors.New("invalid authorization code")
+	}
+	return claims, nil
+}
+
+type mockUserIdentityRepository struct {
+	identities []*domain.UserIdentity
+}
+
+func (m *mockUserIdentityRepository) Create(ctx context.Context, identity *domain.UserIdentity) error {
+	m.identities = append(m.identities, identity)
+	return nil
+}
+
+func (m *mockUserIdentityRepository) GetBySubject(ctx context.Context, issuer, subject string) (*domain.UserIdentity, error) {
+	for _, identity := range m.identities {
+		if identity.Issuer == issuer && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, errors.New("identity not found")
+}
+
+func (m *mockUserIdentityRepository) ListByUser(ctx context.Context, userID string) ([]*domain.UserIdentity, error) {
+	var identities []*domain.UserIdentity
+	for _, identity := range m.identities {
+		if identity.UserID == userID {
+			identities = append(identities, identity)
+		}
+	}
+	return identities, nil
+}
+
+func TestSSOService_CompleteSignInWithTwoFactor(t *testing.T) {
+	ctx := context.Background()
+	userRepo := &mockUserRepository{}
+	sessionRepo := &mockSessionRepository{}
+	authService := NewAuthService(userRepo, sessionRepo, &mockAPITokenRepository{tokens: map[string]*domain.APIToken{}}, &mockRecoveryCodeRepository{codes: map[string][]*domain.RecoveryCode{}})
+	provider := &fakeIdentityProvider{claims: map[string]*IdentityClaims{
+		"parent": {Issuer: "https://idp.example.com", Subject: "1", Email: "parent@example.com", EmailVerified: true},
+		"kid":    {Issuer: "https://idp.example.com", Subject: "2", Email: "kid@example.com", EmailVerified: true},
+	}}
+	service := NewSSOService(userRepo, &mockUserIdentityRepository{}, authService, provider, SSOMembership{DefaultRole: domain.UserRoleMember})
+
+	parent, err := authService.CreateUser(ctx, "parent@example.com", "Parent", "password123", domain.UserRoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent.TOTPSecret, err = generateTOTPSecret(); err != nil {
+		t.Fatal(err)
+	}
+	parent.TOTPEnabled = true
+	if _, err := authService.CreateUser(ctx, "kid@example.com", "Kid", "password123", domain.UserRoleMember); err != nil {
+		t.Fatal(err)
+	}
+	state := &SSOState{State: "s", Nonce: "n", CodeVerifier: "v"}
+
+	// Without two-factor the provider's word is enough
+	signIn, err := service.CompleteSignIn(ctx, "kid", state, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signIn.Session == nil || signIn.Token == "" || signIn.Challenge != "" {
+		t.Fatalf("expected a session for a user without two-factor, got %+v", signIn)
+	}
+
+	// With two-factor the provider only stands in for the password
+	signIn, err = service.CompleteSignIn(ctx, "parent", state, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signIn.Session != nil || signIn.Token != "" || signIn.Challenge == "" {
+		t.Fatalf("expected a two-factor challenge instead of a session, got %+v", signIn)
+	}
+	if len(sessionRepo.sessions) != 1 {
+		t.Fatalf("expected no session started before the second factor, got %d sessions", len(sessionRepo.sessions))
+	}
+
+	key, err := totpEncoding.DecodeString(parent.TOTPSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := totpCode(key, time.Now().Unix()/totpPeriod)
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	if _, _, _, err := service.CompleteTwoFactor(ctx, signIn.Challenge, wrong, "", ""); err == nil {
+		t.Fatal("expected a wrong code to be refused")
+	}
+	session, raw, user, err := service.CompleteTwoFactor(ctx, signIn.Challenge, code, "", "")
+	if err != nil {
+		t.Fatalf("expected the code to finish signing in after a wrong one, got %v", err)
+	}
+	if session == nil || raw == "" || user.ID != parent.ID {
+		t.Errorf("expected a session for %s, got %+v, %v", parent.ID, session, user)
+	}
+	if _, _, _, err := service.CompleteTwoFactor(ctx, signIn.Challenge, code, "", ""); !errors.Is(err, ErrSSOChallengeExpired) {
+		t.Errorf("expected a used challenge to be refused, got %v", err)
+	}
+	if _, _, _, err := service.CompleteTwoFactor(ctx, "made-up", code, "", ""); !errors.Is(err, ErrSSOChallengeExpired) {
+		t.Errorf("expected an unknown challenge to be refused, got %v", err)
+	}
+
+	// Guessing is limited to a few attempts per sign-in
+	signIn, err = service.CompleteSignIn(ctx, "parent", state, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < ssoChallengeAttempts; i++ {
+		if _, _, _, err := service.CompleteTwoFactor(ctx, signIn.Challenge, wrong, "", ""); errors.Is(err, ErrSSOChallengeExpired) {
+			t.Fatalf("expected attempt %d to be allowed, got %v", i+1, err)
+		}
+	}
+	if _, _, _, err := service.CompleteTwoFactor(ctx, signIn.Challenge, wrong, "", ""); !errors.Is(err, ErrSSOChallengeExpired) {
+		t.Errorf("expected the challenge to close after %d attempts, got %v", ssoChallengeAttempts, err)
+	}
+
+	// A user disabled while entering their code doesn't get in
+	signIn, err = service.CompleteSignIn(ctx, "parent", state, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	parent.DisabledAt = &now
+	if _, _, _, err := service.CompleteTwoFactor(ctx, signIn.Challenge, code, "", ""); !errors.Is(err, ErrUserDisabled) {
+		t.Errorf("expected a disabled user to be refused, got %v", err)
+	}
+}
+
+func TestSSOMembershipResolveRole(t *testing.T) {
+	tests := []struct {
+		name       string
+		membership SSOMembership
+		groups     []string
+		wantRole   domain.UserRole
+		wantOK     bool
+	}{
+		{
+			name:       "no groups configured uses default role",
+			membership: SSOMembership{DefaultRole: domain.UserRoleMember},
+			groups:     []string{"anything"},
+			wantRole:   domain.UserRoleMember,
+			wantOK:     true,
+		},
+		{
+			name:       "admin group grants admin",
+			membership: SSOMembership{DefaultRole: domain.UserRoleMember, AdminGroups: []string{"budget-admins"}},
+			groups:     []string{"users", "Budget-Admins"},
+			wantRole:   domain.UserRoleAdmin,
+			wantOK:     true,
+		},
+		{
+			name:       "only admin groups configured lets everyone else in as member",
+			membership: SSOMembership{DefaultRole: domain.UserRoleMember, AdminGroups: []string{"budget-admins"}},
+			groups:     []string{"users"},
+			wantRole:   domain.UserRoleMember,
+			wantOK:     true,
+		},
+		{
+			name:       "member group grants member",
+			membership: SSOMembership{AdminGroups: []string{"budget-admins"}, MemberGroups: []string{"family"}},
+			groups:     []string{"family"},
+			wantRole:   domain.UserRoleMember,
+			wantOK:     true,
+		},
+		{
+			name:       "member groups configured refuses outsiders",
+			membership: SSOMembership{MemberGroups: []string{"family"}},
+			groups:     []string{"guests"},
+			wantOK:     false,
+		},
+		{
+			name:       "admin group wins over member group",
+			membership: SSOMembership{AdminGroups: []string{"budget-admins"}, MemberGroups: []string{"family"}},
+			groups:     []string{"family", "budget-admins"},
+			wantRole:   domain.UserRoleAdmin,
+			wantOK:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role, ok := tt.membership.resolveRole(tt.groups)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && role != tt.wantRole {
+				t.Errorf("role = %s, want %s", role, tt.wantRole)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/google/uuid"
+)
+
+// startingBalanceDescription is what an account's starting balance transaction is called
+const startingBalanceDescription = "Starting balance"
+
+// StartingBalanceChange is the result of correcting an account's starting balance
+type StartingBalanceChange struct {
+	Account                 *domain.Account `json:"account"`
+	StartingBalance         int64           `json:"starting_balance"`
+	PreviousStartingBalance int64           `json:"previous_starting_balance"`
+	Period                  string          `json:"period"` // Month the starting balance counts in; it and every later month change
+}
+
+// UpdateStartingBalance corrects the balance an account was opened with
+// The starting balance transaction is changed in place, keeping its original date, so the
+// month the account was opened in and every month after it show the corrected amount; the
+// account balance moves by the difference. Everything is written atomically.
+// A credit card or loan's opening debt isn't income, so like CreateAccount it's kept on the
+// balance without a transaction: it's whatever of the balance its transactions don't explain.
+// A tracking account has no starting balance in the budget; its balance is updated instead.
+func (s *AccountService) UpdateStartingBalance(ctx context.Context, id string, amount int64) (*StartingBalanceChange, error) {
+	var change *StartingBalanceChange
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		account, err := s.accountRepo.GetByID(ctx, id)
+		if err != nil {
+			return domain.ErrAccountNotFound
+		}
+		if account.Type.IsOffBudget() {
+			return domain.ErrOffBudgetAccount
+		}
+		transactions, err := s.transactionRepo.ListByAccount(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to list account transactions: %w", err)
+		}
+
+		starting := startingBalanceTransaction(transactions)
+		var previous int64
+		switch {
+		case starting != nil:
+			previous = starting.Amount
+		case account.Type.IsDebt():
+			previous = account.Balance
+			for _, transaction := range transactions {
+				previous -= transaction.Amount
+			}
+		}
+
+		opened := account.CreatedAt
+		if starting != nil {
+			opened = starting.Date
+		}
+		change = &StartingBalanceChange{
+			Account:                 account,
+			StartingBalance:         amount,
+			PreviousStartingBalance: previous,
+			Period:                  opened.Format("2006-01"),
+		}
+		if amount == previous {
+			return nil
+		}
+
+		inflow := amount
+		if account.Type.IsDebt() && amount < 0 {
+			inflow = 0
+		}
+		now := time.Now()
+		switch {
+		case starting != nil && inflow == 0:
+			if err := s.transactionRepo.Delete(ctx, starting.ID); err != nil {
+				return fmt.Errorf("failed to delete starting balance transaction: %w", err)
+			}
+		case starting != nil:
+			starting.Amount = inflow
+			starting.UpdatedAt = now
+			if err := s.transactionRepo.Update(ctx, starting); err != nil {
+				return fmt.Errorf("failed to update starting balance transaction: %w", err)
+			}
+		case inflow != 0:
+			if err := s.transactionRepo.Create(ctx, newStartingBalanceTransaction(account.ID, inflow, opened)); err != nil {
+				return fmt.Errorf("failed to create starting balance transaction: %w", err)
+			}
+		}
+
+		account.Balance += amount - previous
+		account.UpdatedAt = now
+		return s.accountRepo.Update(ctx, account)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// newStartingBalanceTransaction records the balance an account was opened with on date
+func newStartingBalanceTransaction(accountID string, amount int64, date time.Time) *domain.Transaction {
+	now := time.Now()
+	return &domain.Transaction{
+		ID:          uuid.New().String(),
+		Type:        domain.TransactionTypeStartingBalance,
+		AccountID:   accountID,
+		Amount:      amount,
+		Description: startingBalanceDescription,
+		Date:        date,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// startingBalanceTransaction returns the transaction an account's opening balance was
+// recorded with, or nil if it was opened empty
+func startingBalanceTransaction(transactions []*domain.Transaction) *domain.Transaction {
+	var starting *domain.Transaction
+	for _, transaction := range transactions {
+		if transaction.Type != domain.TransactionTypeStartingBalance {
+			continue
+		}
+		if starting == nil || transaction.CreatedAt.Before(starting.CreatedAt) {
+			starting = transaction
+		}
+	}
+	return starting
+}
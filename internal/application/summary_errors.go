@@ -0,0 +1,27 @@
+package application
+
+import (
+	"log"
+	"maps"
+)
+
+// RecordSummaryError notes that part of a period summary couldn't be calculated
+// The summary goes out without the part; the error is logged and counted for diagnostics.
+func (s *AllocationService) RecordSummaryError(part string, err error) {
+	log.Printf("Period summary left out %s: %v", part, err)
+
+	s.summaryErrorsMu.Lock()
+	defer s.summaryErrorsMu.Unlock()
+	if s.summaryErrors == nil {
+		s.summaryErrors = make(map[string]int64)
+	}
+	s.summaryErrors[part]++
+}
+
+// SummaryErrorCounts returns how many period summaries each part has been left out of
+// since startup
+func (s *AllocationService) SummaryErrorCounts() map[string]int64 {
+	s.summaryErrorsMu.Lock()
+	defer s.summaryErrorsMu.Unlock()
+	return maps.Clone(s.summaryErrors)
+}
@@ -0,0 +1,45 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestAllocationService_GetAllocationSummary_Degraded(t *testing.T) {
+	ctx := context.Background()
+	allocationRepo := newMockAllocationRepository()
+	categoryRepo := newMockCategoryRepository()
+	goalRepo := newMockGoalRepository()
+
+	categoryRepo.Create(ctx, &domain.Category{ID: "rent", Name: "Rent"})
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a1", CategoryID: "rent", Period: "2025-10", Amount: 100000})
+	goalRepo.listError = errors.New("database is locked")
+
+	service := NewAllocationService(allocationRepo, categoryRepo, newMockTransactionRepository(), newMockBudgetStateRepository(0, 0), newMockAccountRepository(0), newMockCategoryGroupRepository(), goalRepo)
+	summaries, err := service.GetAllocationSummary(ctx, "2025-10")
+	if err != nil {
+		t.Fatalf("expected goals failing to leave them out, got %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].Available != 100000 {
+		t.Fatalf("expected Rent with its totals, got %+v", summaries)
+	}
+	if !slices.Equal(summaries[0].Errors, []string{domain.SummaryPartGoal}) || summaries[0].Goal != nil {
+		t.Errorf("expected Rent marked as missing its goal, got errors %v", summaries[0].Errors)
+	}
+	if summaries[0].QuickBudget == nil {
+		t.Error("expected quick-budget figures to be kept")
+	}
+	if counts := service.SummaryErrorCounts(); counts[domain.SummaryPartGoal] != 1 || len(counts) != 1 {
+		t.Errorf("expected one goal error counted, got %v", counts)
+	}
+
+	// The totals can't be left out
+	categoryRepo.listError = errors.New("database is locked")
+	if _, err := service.GetAllocationSummary(ctx, "2025-10"); err == nil {
+		t.Error("expected failing to list categories to fail the summary")
+	}
+}
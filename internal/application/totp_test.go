@@ -0,0 +1,68 @@
+package application
+
+import (
+	"testing"
+	"time"
+)
+
+// RFC 6238 appendix B test secret ("12345678901234567890"), base32 encoded
+const rfcTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCodeRFCVectors(t *testing.T) {
+	// The RFC lists 8-digit codes; 6-digit codes are their last six digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	key, err := totpEncoding.DecodeString(rfcTOTPSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		if got := totpCode(key, tt.unix/totpPeriod); got != tt.want {
+			t.Errorf("totpCode at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	step := now.Unix() / totpPeriod
+
+	if got, ok := verifyTOTP(rfcTOTPSecret, "081804", now, 0); !ok || got != step {
+		t.Fatalf("expected current code to verify at step %d, got %d %v", step, got, ok)
+	}
+
+	// One step of clock drift either way is accepted
+	if _, ok := verifyTOTP(rfcTOTPSecret, "081804", now.Add(totpPeriod*time.Second), 0); !ok {
+		t.Error("expected code from previous step to verify")
+	}
+	if _, ok := verifyTOTP(rfcTOTPSecret, "081804", now.Add(-totpPeriod*time.Second), 0); !ok {
+		t.Error("expected code from next step to verify")
+	}
+	if _, ok := verifyTOTP(rfcTOTPSecret, "081804", now.Add(3*totpPeriod*time.Second), 0); ok {
+		t.Error("expected stale code to be rejected")
+	}
+
+	// A code can't be used twice
+	if _, ok := verifyTOTP(rfcTOTPSecret, "081804", now, step); ok {
+		t.Error("expected replayed code to be rejected")
+	}
+
+	for _, code := range []string{"", "81804", "0818040", "abcdef", "000000"} {
+		if _, ok := verifyTOTP(rfcTOTPSecret, code, now, 0); ok {
+			t.Errorf("expected %q to be rejected", code)
+		}
+	}
+
+	if _, ok := verifyTOTP(rfcTOTPSecret, "081 804", now, 0); !ok {
+		t.Error("expected spaces in the code to be ignored")
+	}
+}
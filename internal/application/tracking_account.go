@@ -0,0 +1,83 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/google/uuid"
+)
+
+// balanceUpdateDescription is what a tracking account's balance changes are called
+const balanceUpdateDescription = "Balance update"
+
+// TrackingBalanceUpdate is the result of setting a tracking account's balance
+type TrackingBalanceUpdate struct {
+	Account     *domain.Account     `json:"account"`
+	Transaction *domain.Transaction `json:"transaction,omitempty"` // Adjustment recording the change; nil when the balance was already right
+	Change      int64               `json:"change"`
+}
+
+// UpdateTrackingBalance sets an off-budget tracking account's balance, e.g. a brokerage
+// account's value from its latest statement
+// The change is recorded as an adjustment on date, so the account's history adds up to its
+// balance but neither Ready to Assign nor any category moves. Everything is written atomically.
+func (s *TransactionService) UpdateTrackingBalance(ctx context.Context, accountID string, balance int64, note string, date time.Time) (*TrackingBalanceUpdate, error) {
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, domain.ErrAccountNotFound
+	}
+	if !account.Type.IsOffBudget() {
+		return nil, domain.ErrNotTrackingAccount
+	}
+	if date.IsZero() {
+		date = time.Now()
+	}
+	if err := s.dates.Check(date); err != nil {
+		return nil, err
+	}
+
+	update := &TrackingBalanceUpdate{Account: account, Change: balance - account.Balance}
+	if update.Change == 0 {
+		return update, nil
+	}
+	update.Transaction = newBalanceUpdateTransaction(account.ID, update.Change, balanceUpdateDescription, note, date)
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.transactionRepo.Create(ctx, update.Transaction); err != nil {
+			return err
+		}
+		account.Balance = balance
+		account.UpdatedAt = time.Now()
+		if err := s.accountRepo.Update(ctx, account); err != nil {
+			return fmt.Errorf("failed to update account balance: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return update, nil
+}
+
+// newBalanceUpdateTransaction records a change in a tracking account's balance on date
+// It's an adjustment, so it's never income or spending; an empty note is left out.
+func newBalanceUpdateTransaction(accountID string, amount int64, description, note string, date time.Time) *domain.Transaction {
+	now := time.Now()
+	transaction := &domain.Transaction{
+		ID:          uuid.New().String(),
+		Type:        domain.TransactionTypeAdjustment,
+		AccountID:   accountID,
+		Amount:      amount,
+		Description: description,
+		Date:        date,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if note = strings.TrimSpace(note); note != "" {
+		transaction.Note = &note
+	}
+	return transaction
+}
@@ -0,0 +1,92 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestTrackingAccount_StaysOffBudget(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	transactionRepo := newMockTransactionRepository()
+	categoryRepo := newMockCategoryRepository()
+	categoryRepo.categories["investing"] = &domain.Category{ID: "investing", Name: "Investing"}
+	accounts := NewAccountService(accountRepo, categoryRepo, newMockBudgetStateRepository(0, 0), transactionRepo,
+		NewCategoryGroupService(newMockCategoryGroupRepository(), categoryRepo), &mockUnitOfWork{accountRepo, transactionRepo})
+	transactions := NewTransactionService(transactionRepo, accountRepo, categoryRepo, newMockBudgetStateRepository(0, 0),
+		&mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{})
+
+	// The opening balance is a balance update, not a starting balance counted as income
+	brokerage, err := accounts.CreateAccount(ctx, "Brokerage", 1000000, domain.AccountTypeTracking, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transactionRepo.transactions) != 1 || transactionRepo.transactions[0].Type != domain.TransactionTypeAdjustment {
+		t.Fatalf("expected the opening balance as an adjustment, got %+v", transactionRepo.transactions)
+	}
+	if _, err := accounts.UpdateStartingBalance(ctx, brokerage.ID, 5000); !errors.Is(err, domain.ErrOffBudgetAccount) {
+		t.Errorf("expected %v correcting the starting balance, got %v", domain.ErrOffBudgetAccount, err)
+	}
+	checking, err := accounts.CreateAccount(ctx, "Checking", 200000, domain.AccountTypeChecking, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	date := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	update, err := transactions.UpdateTrackingBalance(ctx, brokerage.ID, 1045000, " Q2 statement ", date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txn := update.Transaction
+	if update.Change != 45000 || update.Account.Balance != 1045000 || txn.Type != domain.TransactionTypeAdjustment ||
+		txn.Amount != 45000 || txn.CategoryID != nil || txn.Note == nil || *txn.Note != "Q2 statement" || !txn.Date.Equal(date) {
+		t.Errorf("unexpected balance update %+v %+v", update, txn)
+	}
+	if update, err := transactions.UpdateTrackingBalance(ctx, brokerage.ID, 1045000, "", date); err != nil || update.Transaction != nil {
+		t.Errorf("expected an unchanged balance to record nothing, got %+v, %v", update, err)
+	}
+	if _, err := transactions.UpdateTrackingBalance(ctx, checking.ID, 0, "", date); !errors.Is(err, domain.ErrNotTrackingAccount) {
+		t.Errorf("expected %v for a checking account, got %v", domain.ErrNotTrackingAccount, err)
+	}
+
+	// Income and spending belong to budget accounts
+	investing := "investing"
+	if _, err := transactions.CreateTransaction(ctx, brokerage.ID, &investing, -1000, "Fees", date); !errors.Is(err, domain.ErrOffBudgetAccount) {
+		t.Errorf("expected %v for spending, got %v", domain.ErrOffBudgetAccount, err)
+	}
+	if _, err := transactions.CreateExternalTransfer(ctx, brokerage.ID, nil, 1000, "Withdrawal", date); !errors.Is(err, domain.ErrOffBudgetAccount) {
+		t.Errorf("expected %v for a one-sided transfer, got %v", domain.ErrOffBudgetAccount, err)
+	}
+
+	// Money leaves the budget for a tracking account, but doesn't come back as a transfer
+	if _, err := transactions.CreateTransfer(ctx, checking.ID, brokerage.ID, 50000, "Contribution", date); err != nil {
+		t.Fatal(err)
+	}
+	if accountRepo.accounts[brokerage.ID].Balance != 1095000 || accountRepo.accounts[checking.ID].Balance != 150000 {
+		t.Error("expected the contribution to move between the balances")
+	}
+	if _, err := transactions.CreateTransfer(ctx, brokerage.ID, checking.ID, 50000, "Withdrawal", date); err == nil {
+		t.Error("expected a transfer from a tracking account into the budget to fail")
+	}
+
+	// A contribution recorded before the account was added keeps the category it came out of
+	contribution, err := transactions.CreateExternalTransfer(ctx, checking.ID, &investing, 20000, "Contribution", date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	linked, err := transactions.LinkTransfer(ctx, contribution.ID, brokerage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if linked.CategoryID == nil || *linked.CategoryID != investing {
+		t.Errorf("expected the linked contribution to keep its category, got %+v", linked)
+	}
+
+	if _, err := accounts.ConvertAccountType(ctx, brokerage.ID, domain.AccountTypeSavings, AccountConversionOptions{}); err == nil {
+		t.Error("expected converting a tracking account into a budget account to fail")
+	}
+}
@@ -0,0 +1,174 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// MaxBulkEditTransactions caps how many transactions one BulkEditTransactions call edits
+const MaxBulkEditTransactions = 1000
+
+// TransactionBulkEditResult reports what BulkEditTransactions changed
+type TransactionBulkEditResult struct {
+	Updated []*domain.Transaction       `json:"updated"`
+	Errors  []*TransactionBulkEditError `json:"errors"` // Transactions left as they were
+}
+
+// TransactionBulkEditError is why one transaction in a bulk edit was left as it was
+type TransactionBulkEditError struct {
+	TransactionID string `json:"transaction_id"`
+	Error         string `json:"error"`
+}
+
+// UsePayees applies payee rules to new transactions and lets bulk edits set payees
+func (s *TransactionService) UsePayees(payees *PayeeService) {
+	s.payees = payees
+}
+
+// BulkEditTransactions changes the payee, account or date of many transactions at once
+// Each transaction is checked on its own: one that doesn't exist, can't move to the account
+// or would be dated outside the allowed range is reported and left alone, and the rest are
+// changed in a single statement. Only normal transactions move between accounts, as the
+// others belong to theirs; balances follow them, and credit card payment categories are
+// worked out again for the transactions that moved or changed dates. Nothing is saved
+// unless every change succeeds.
+func (s *TransactionService) BulkEditTransactions(ctx context.Context, transactionIDs []string, edit domain.TransactionBulkEdit) (*TransactionBulkEditResult, error) {
+	if len(transactionIDs) == 0 {
+		return nil, fmt.Errorf("no transaction IDs provided")
+	}
+	if len(transactionIDs) > MaxBulkEditTransactions {
+		return nil, fmt.Errorf("at most %d transactions can be edited at once", MaxBulkEditTransactions)
+	}
+	if edit.PayeeID == nil && edit.AccountID == nil && edit.ShiftDays == 0 {
+		return nil, fmt.Errorf("nothing to change: set payee_id, account_id or shift_days")
+	}
+	if edit.PayeeID != nil && *edit.PayeeID != "" {
+		if s.payees == nil {
+			return nil, fmt.Errorf("payees can't be set here")
+		}
+		if _, err := s.payees.payeeRepo.GetByID(ctx, *edit.PayeeID); err != nil {
+			return nil, err
+		}
+	}
+	if edit.AccountID != nil {
+		if _, err := s.accountRepo.GetByID(ctx, *edit.AccountID); err != nil {
+			return nil, fmt.Errorf("account not found: %w", err)
+		}
+	}
+
+	result := &TransactionBulkEditResult{Updated: []*domain.Transaction{}, Errors: []*TransactionBulkEditError{}}
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		accounts := make(map[string]*domain.Account)
+		account := func(ctx context.Context, id string) (*domain.Account, error) {
+			if account, ok := accounts[id]; ok {
+				return account, nil
+			}
+			account, err := s.accountRepo.GetByID(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("account not found: %w", err)
+			}
+			accounts[id] = account
+			return account, nil
+		}
+
+		var valid []string
+		moved := make(map[string]int64) // What leaves each account the transactions move out of
+		seen := make(map[string]bool)
+		for _, id := range transactionIDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			transaction, err := s.transactionRepo.GetByID(ctx, id)
+			if err == nil {
+				err = s.checkBulkEdit(ctx, transaction, edit, account)
+			}
+			if err != nil {
+				result.Errors = append(result.Errors, &TransactionBulkEditError{TransactionID: id, Error: err.Error()})
+				continue
+			}
+			valid = append(valid, id)
+			if edit.AccountID != nil && *edit.AccountID != transaction.AccountID {
+				moved[transaction.AccountID] += transaction.Amount
+			}
+		}
+		if len(valid) == 0 {
+			return nil
+		}
+
+		respend := s.creditCards != nil && (edit.AccountID != nil || edit.ShiftDays != 0)
+		if respend {
+			for _, id := range valid {
+				if err := s.creditCards.ReverseSpending(ctx, id); err != nil {
+					return err
+				}
+			}
+		}
+		if err := s.transactionRepo.BulkUpdate(ctx, valid, edit); err != nil {
+			return err
+		}
+
+		// Money that moved leaves one balance and lands in the other
+		now := time.Now()
+		changed := make(map[string]*domain.Account)
+		for accountID, amount := range moved {
+			from, to := accounts[accountID], accounts[*edit.AccountID]
+			from.Balance -= amount
+			to.Balance += amount
+			from.UpdatedAt, to.UpdatedAt = now, now
+			changed[from.ID], changed[to.ID] = from, to
+		}
+		for _, account := range changed {
+			if err := s.accountRepo.Update(ctx, account); err != nil {
+				return fmt.Errorf("failed to update account balance: %w", err)
+			}
+		}
+
+		for _, id := range valid {
+			transaction, err := s.transactionRepo.GetByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			if respend {
+				if err := s.creditCards.RecordSpending(ctx, transaction); err != nil {
+					return err
+				}
+			}
+			result.Updated = append(result.Updated, transaction)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// checkBulkEdit reports why a bulk edit can't be made to a transaction, or nil if it can
+func (s *TransactionService) checkBulkEdit(ctx context.Context, transaction *domain.Transaction, edit domain.TransactionBulkEdit, account func(context.Context, string) (*domain.Account, error)) error {
+	if edit.AccountID != nil && *edit.AccountID != transaction.AccountID {
+		if transaction.Type != domain.TransactionTypeNormal {
+			return fmt.Errorf("only normal transactions can be moved to another account")
+		}
+		from, err := account(ctx, transaction.AccountID)
+		if err != nil {
+			return err
+		}
+		to, err := account(ctx, *edit.AccountID)
+		if err != nil {
+			return err
+		}
+		if from.Type.IsOffBudget() != to.Type.IsOffBudget() {
+			return domain.ErrOffBudgetAccount
+		}
+	}
+	if edit.ShiftDays != 0 {
+		if err := s.dates.Check(transaction.Date.AddDate(0, 0, edit.ShiftDays)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
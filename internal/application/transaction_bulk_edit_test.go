@@ -0,0 +1,80 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestTransactionService_BulkEditTransactions(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking, Balance: -8000}
+	accountRepo.accounts["savings"] = &domain.Account{ID: "savings", Name: "Savings", Type: domain.AccountTypeSavings}
+	accountRepo.accounts["brokerage"] = &domain.Account{ID: "brokerage", Name: "Brokerage", Type: domain.AccountTypeTracking}
+	transactionRepo := newMockTransactionRepository()
+	payeeRepo := newMockPayeeRepository()
+	payeeRepo.payees["grocer"] = &domain.Payee{ID: "grocer", Name: "Grocer"}
+	transactions := NewTransactionService(transactionRepo, accountRepo, newMockCategoryRepository(), newMockBudgetStateRepository(0, 0),
+		&mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{FutureDays: 30})
+	transactions.UsePayees(NewPayeeService(payeeRepo, newMockPayeeRuleRepository(), accountRepo, newMockCategoryRepository()))
+
+	date := time.Now().Truncate(24 * time.Hour)
+	add := func(id string, transactionType domain.TransactionType, amount int64) *domain.Transaction {
+		transaction := &domain.Transaction{ID: id, Type: transactionType, AccountID: "checking", Amount: amount, Date: date}
+		transactionRepo.transactions = append(transactionRepo.transactions, transaction)
+		return transaction
+	}
+	first := add("first", domain.TransactionTypeNormal, -5000)
+	second := add("second", domain.TransactionTypeNormal, -3000)
+	adjustment := add("adjustment", domain.TransactionTypeAdjustment, 1000)
+
+	if _, err := transactions.BulkEditTransactions(ctx, []string{"first"}, domain.TransactionBulkEdit{}); err == nil {
+		t.Error("expected an edit that changes nothing to fail")
+	}
+	missing := "missing"
+	if _, err := transactions.BulkEditTransactions(ctx, []string{"first"}, domain.TransactionBulkEdit{PayeeID: &missing}); !errors.Is(err, domain.ErrPayeeNotFound) {
+		t.Errorf("expected %v, got %v", domain.ErrPayeeNotFound, err)
+	}
+
+	// The adjustment stays in its account and the unknown ID is reported; the rest move
+	savings, grocer := "savings", "grocer"
+	result, err := transactions.BulkEditTransactions(ctx, []string{"first", "second", "adjustment", "unknown", "first"},
+		domain.TransactionBulkEdit{PayeeID: &grocer, AccountID: &savings, ShiftDays: -2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Updated) != 2 || len(result.Errors) != 2 || result.Errors[0].TransactionID != "adjustment" || result.Errors[1].TransactionID != "unknown" {
+		t.Fatalf("unexpected result: updated %+v, errors %+v", result.Updated, result.Errors)
+	}
+	for _, transaction := range []*domain.Transaction{first, second} {
+		if transaction.AccountID != "savings" || transaction.PayeeID == nil || *transaction.PayeeID != "grocer" || !transaction.Date.Equal(date.AddDate(0, 0, -2)) {
+			t.Errorf("expected %s moved, paid to the grocer and two days earlier, got %+v", transaction.ID, transaction)
+		}
+	}
+	if adjustment.AccountID != "checking" || adjustment.PayeeID != nil || !adjustment.Date.Equal(date) {
+		t.Errorf("expected the adjustment left alone, got %+v", adjustment)
+	}
+	if accountRepo.accounts["checking"].Balance != 0 || accountRepo.accounts["savings"].Balance != -8000 {
+		t.Errorf("expected the balances to follow the transactions, got checking %d, savings %d",
+			accountRepo.accounts["checking"].Balance, accountRepo.accounts["savings"].Balance)
+	}
+
+	// Each transaction's date is checked on its own
+	second.Date = date.AddDate(0, 0, 25)
+	empty, brokerage := "", "brokerage"
+	result, err = transactions.BulkEditTransactions(ctx, []string{"first", "second"}, domain.TransactionBulkEdit{PayeeID: &empty, ShiftDays: 10})
+	if err != nil || len(result.Updated) != 1 || len(result.Errors) != 1 || result.Errors[0].TransactionID != "second" {
+		t.Fatalf("expected only the date that stays in range shifted, got %+v, %v", result, err)
+	}
+	if first.PayeeID != nil || !first.Date.Equal(date.AddDate(0, 0, 8)) || second.PayeeID == nil {
+		t.Errorf("unexpected transactions after the shift: %+v %+v", first, second)
+	}
+	result, err = transactions.BulkEditTransactions(ctx, []string{"first"}, domain.TransactionBulkEdit{AccountID: &brokerage})
+	if err != nil || len(result.Errors) != 1 || result.Errors[0].Error != domain.ErrOffBudgetAccount.Error() || first.AccountID != "savings" {
+		t.Errorf("expected moving into a tracking account to be refused, got %+v, %v", result, err)
+	}
+}
@@ -3,6 +3,7 @@ package application
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/billybbuffum/budget/internal/domain"
@@ -14,8 +15,12 @@ type TransactionService struct {
 	transactionRepo   domain.TransactionRepository
 	accountRepo       domain.AccountRepository
 	categoryRepo      domain.CategoryRepository
-	allocationRepo    domain.AllocationRepository
 	budgetStateRepo   domain.BudgetStateRepository
+	uow               domain.UnitOfWork
+	dates             DateBounds
+	creditCards       *CreditCardService // nil leaves payment categories alone
+	payees            *PayeeService      // nil skips payee rules and leaves bulk edits unable to set payees
+	transfers         *TransferSuggestionService // nil leaves transfers entered by hand to the scheduled scan
 }
 
 // NewTransactionService creates a new transaction service
@@ -23,33 +28,88 @@ func NewTransactionService(
 	transactionRepo domain.TransactionRepository,
 	accountRepo domain.AccountRepository,
 	categoryRepo domain.CategoryRepository,
-	allocationRepo domain.AllocationRepository,
 	budgetStateRepo domain.BudgetStateRepository,
+	uow domain.UnitOfWork,
+	dates DateBounds,
 ) *TransactionService {
 	return &TransactionService{
 		transactionRepo: transactionRepo,
 		accountRepo:     accountRepo,
 		categoryRepo:    categoryRepo,
-		allocationRepo:  allocationRepo,
 		budgetStateRepo: budgetStateRepo,
+		uow:             uow,
+		dates:           dates,
 	}
 }
 
+// UseCreditCards funds credit card payment categories as cards are spent on and paid
+func (s *TransactionService) UseCreditCards(creditCards *CreditCardService) {
+	s.creditCards = creditCards
+}
+
+// UseTransferSuggestions scans for the other side of transactions entered or changed by hand
+func (s *TransactionService) UseTransferSuggestions(transfers *TransferSuggestionService) {
+	s.transfers = transfers
+}
+
 // CreateTransaction creates a new transaction and updates account balance
 // Handles three types of transactions:
 // 1. Normal inflow (positive amount): Increases account and Ready to Assign
 // 2. Normal outflow (negative amount): Decreases account, requires category
 // 3. Credit card outflow: Decreases card balance, moves budget from spending category to payment category
+// The transaction, balance update and payment category move are saved atomically.
 func (s *TransactionService) CreateTransaction(ctx context.Context, accountID string, categoryID *string, amount int64, description string, date time.Time) (*domain.Transaction, error) {
+	var transaction *domain.Transaction
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		transaction, err = s.createTransaction(ctx, accountID, categoryID, amount, description, date)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.queueTransferScan(transaction)
+	return transaction, nil
+}
+
+// queueTransferScan has a transaction entered or changed by hand checked for being one side
+// of a transfer, when transfer suggestions are used
+func (s *TransactionService) queueTransferScan(transaction *domain.Transaction) {
+	if s.transfers != nil && transaction.Type == domain.TransactionTypeNormal {
+		s.transfers.QueueScan(transaction.Date)
+	}
+}
+
+func (s *TransactionService) createTransaction(ctx context.Context, accountID string, categoryID *string, amount int64, description string, date time.Time) (*domain.Transaction, error) {
 	// Validate account exists
 	account, err := s.accountRepo.GetByID(ctx, accountID)
 	if err != nil {
 		return nil, fmt.Errorf("account not found: %w", err)
 	}
+	if account.Type.IsOffBudget() {
+		return nil, domain.ErrOffBudgetAccount
+	}
 
 	if amount == 0 {
 		return nil, fmt.Errorf("amount must be non-zero")
 	}
+	if date.IsZero() {
+		date = time.Now()
+	}
+	if err := s.dates.Check(date); err != nil {
+		return nil, err
+	}
+
+	// The first matching payee rule gives the payee and memo, and the category if none was chosen
+	var rule *domain.PayeeRule
+	if s.payees != nil {
+		if rule, err = s.payees.MatchRule(ctx, accountID, amount, description); err != nil {
+			return nil, fmt.Errorf("failed to match payee rules: %w", err)
+		}
+		if rule != nil && (categoryID == nil || *categoryID == "") && rule.CategoryID != nil {
+			categoryID = rule.CategoryID
+		}
+	}
 
 	// For outflows (negative amounts), category is required
 	if amount < 0 && (categoryID == nil || *categoryID == "") {
@@ -74,6 +134,10 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, accountID st
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
+	if rule != nil {
+		transaction.PayeeID = rule.PayeeID
+		transaction.Note = rule.Note
+	}
 
 	if err := s.transactionRepo.Create(ctx, transaction); err != nil {
 		return nil, err
@@ -83,114 +147,13 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, accountID st
 	account.Balance += amount
 	account.UpdatedAt = time.Now()
 	if err := s.accountRepo.Update(ctx, account); err != nil {
-		// Rollback transaction creation if balance update fails
-		s.transactionRepo.Delete(ctx, transaction.ID)
 		return nil, fmt.Errorf("failed to update account balance: %w", err)
 	}
 
-	// CREDIT CARD SPENDING: Move budgeted money from expense category to payment category
-	// Only move money that's actually budgeted in the expense category
-	if account.Type == domain.AccountTypeCredit && categoryID != nil && amount < 0 {
-		// Get the payment category for this credit card
-		paymentCategory, err := s.categoryRepo.GetPaymentCategoryByAccountID(ctx, account.ID)
-		if err != nil {
-			// Rollback
-			s.accountRepo.Update(ctx, &domain.Account{
-				ID:        account.ID,
-				Balance:   account.Balance - amount,
-				UpdatedAt: time.Now(),
-			})
-			s.transactionRepo.Delete(ctx, transaction.ID)
-			return nil, fmt.Errorf("failed to get payment category: %w", err)
-		}
-
-		// Get current period (YYYY-MM format)
-		period := date.Format("2006-01")
-
-		// Get the expense category's allocation to see how much budget is available
-		expenseAlloc, err := s.allocationRepo.GetByCategoryAndPeriod(ctx, *categoryID, period)
-
-		// Calculate how much money to move to payment category
-		// We only move money that's actually budgeted
-		amountToMove := int64(0)
-
-		if err == nil && expenseAlloc != nil && expenseAlloc.Amount > 0 {
-			// Get activity (spending) in the expense category for this period
-			// BEFORE the current transaction (we need to check what's available NOW)
-			startDate := date.AddDate(0, 0, -date.Day()+1) // First day of month
-			endDate := startDate.AddDate(0, 1, -1)          // Last day of month
-
-			transactions, err := s.transactionRepo.ListByCategory(ctx, *categoryID)
-			if err == nil {
-				var totalActivity int64 = 0
-				for _, txn := range transactions {
-					txnDate := txn.Date
-					// Only include transactions BEFORE the one we just created
-					// (exclude the current transaction ID)
-					if txn.ID != transaction.ID &&
-						(txnDate.After(startDate) || txnDate.Equal(startDate)) &&
-						(txnDate.Before(endDate) || txnDate.Equal(endDate)) {
-						totalActivity += txn.Amount
-					}
-				}
-
-				// Calculate available budget in expense category BEFORE this transaction
-				// Available = Allocated + Activity (activity is negative for expenses)
-				available := expenseAlloc.Amount + totalActivity
-
-				// Move the minimum of: spending amount or available budget
-				spendingAmount := -amount // Convert negative amount to positive
-				if available >= spendingAmount {
-					// Enough budget available, move full amount
-					amountToMove = spendingAmount
-				} else if available > 0 {
-					// Some budget available, move only what's available
-					amountToMove = available
-				}
-				// If available <= 0, amountToMove stays 0 (no budget to move)
-			}
-		}
-		// If expense category has no allocation, amountToMove stays 0
-
-		// Only update payment category allocation if there's money to move
-		if amountToMove > 0 {
-			// Get or create allocation for payment category
-			paymentAlloc, err := s.allocationRepo.GetByCategoryAndPeriod(ctx, paymentCategory.ID, period)
-			if err != nil {
-				// Create new allocation
-				paymentAlloc = &domain.Allocation{
-					ID:         uuid.New().String(),
-					CategoryID: paymentCategory.ID,
-					Amount:     amountToMove,
-					Period:     period,
-					CreatedAt:  time.Now(),
-					UpdatedAt:  time.Now(),
-				}
-				if err := s.allocationRepo.Create(ctx, paymentAlloc); err != nil {
-					// Rollback
-					s.accountRepo.Update(ctx, &domain.Account{
-						ID:        account.ID,
-						Balance:   account.Balance - amount,
-						UpdatedAt: time.Now(),
-					})
-					s.transactionRepo.Delete(ctx, transaction.ID)
-					return nil, fmt.Errorf("failed to create payment allocation: %w", err)
-				}
-			} else {
-				// Update existing allocation
-				paymentAlloc.Amount += amountToMove
-				paymentAlloc.UpdatedAt = time.Now()
-				if err := s.allocationRepo.Update(ctx, paymentAlloc); err != nil {
-					// Rollback
-					s.accountRepo.Update(ctx, &domain.Account{
-						ID:        account.ID,
-						Balance:   account.Balance - amount,
-						UpdatedAt: time.Now(),
-					})
-					s.transactionRepo.Delete(ctx, transaction.ID)
-					return nil, fmt.Errorf("failed to update payment allocation: %w", err)
-				}
-			}
+	// Credit card spending moves what the category had budgeted for it to the payment category
+	if s.creditCards != nil {
+		if err := s.creditCards.RecordSpending(ctx, transaction); err != nil {
+			return nil, err
 		}
 	}
 
@@ -200,7 +163,21 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, accountID st
 // CreateTransfer creates a transfer between two accounts
 // Transfers move money between accounts without affecting Ready to Assign
 // Amount should be positive (the amount to transfer)
+// Both sides of the transfer and both balance updates are saved atomically.
 func (s *TransactionService) CreateTransfer(ctx context.Context, fromAccountID, toAccountID string, amount int64, description string, date time.Time) (*domain.Transaction, error) {
+	var transaction *domain.Transaction
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		transaction, err = s.createTransfer(ctx, fromAccountID, toAccountID, amount, description, date)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+func (s *TransactionService) createTransfer(ctx context.Context, fromAccountID, toAccountID string, amount int64, description string, date time.Time) (*domain.Transaction, error) {
 	if amount <= 0 {
 		return nil, fmt.Errorf("transfer amount must be positive")
 	}
@@ -208,6 +185,12 @@ func (s *TransactionService) CreateTransfer(ctx context.Context, fromAccountID,
 	if fromAccountID == toAccountID {
 		return nil, fmt.Errorf("cannot transfer to the same account")
 	}
+	if date.IsZero() {
+		date = time.Now()
+	}
+	if err := s.dates.Check(date); err != nil {
+		return nil, err
+	}
 
 	// Validate both accounts exist
 	fromAccount, err := s.accountRepo.GetByID(ctx, fromAccountID)
@@ -219,44 +202,17 @@ func (s *TransactionService) CreateTransfer(ctx context.Context, fromAccountID,
 	if err != nil {
 		return nil, fmt.Errorf("destination account not found: %w", err)
 	}
+	// Money moved to a tracking account leaves the budget like a one-sided transfer, but
+	// money coming back is income to budget
+	if fromAccount.Type.IsOffBudget() && !toAccount.Type.IsOffBudget() {
+		return nil, fmt.Errorf("money from a tracking account is income: record it as an inflow and update the tracking account's balance")
+	}
 
-	// If transferring TO a credit card, check if we should categorize with payment category
-	// Only categorize if there's money allocated (don't categorize overpayments)
+	// Paying a credit card comes out of its payment category, if it has enough for it
 	var outboundCategoryID *string
-	if toAccount.Type == domain.AccountTypeCredit {
-		paymentCategory, err := s.categoryRepo.GetPaymentCategoryByAccountID(ctx, toAccountID)
-		if err == nil && paymentCategory != nil {
-			// Check if payment category has any allocation
-			// Get all allocations for this payment category
-			allAllocations, err := s.allocationRepo.List(ctx)
-			if err == nil {
-				var totalAllocated int64
-				for _, alloc := range allAllocations {
-					if alloc.CategoryID == paymentCategory.ID {
-						totalAllocated += alloc.Amount
-					}
-				}
-
-				// Get all transactions already categorized with this payment category
-				allTransactions, err := s.transactionRepo.ListByCategory(ctx, paymentCategory.ID)
-				if err == nil {
-					var totalSpent int64
-					for _, txn := range allTransactions {
-						if txn.Amount < 0 {
-							totalSpent += -txn.Amount // Convert to positive
-						}
-					}
-
-					// Available = Allocated - Already Spent
-					available := totalAllocated - totalSpent
-
-					// Only categorize if payment <= available
-					// This prevents showing negative available when overpaying
-					if available >= amount {
-						outboundCategoryID = &paymentCategory.ID
-					}
-				}
-			}
+	if s.creditCards != nil {
+		if outboundCategoryID, err = s.creditCards.PaymentCategoryFor(ctx, toAccount.ID, amount, date); err != nil {
+			return nil, err
 		}
 	}
 
@@ -292,8 +248,6 @@ func (s *TransactionService) CreateTransfer(ctx context.Context, fromAccountID,
 	}
 
 	if err := s.transactionRepo.Create(ctx, inboundTxn); err != nil {
-		// Rollback outbound transaction
-		s.transactionRepo.Delete(ctx, outboundTxn.ID)
 		return nil, err
 	}
 
@@ -301,9 +255,6 @@ func (s *TransactionService) CreateTransfer(ctx context.Context, fromAccountID,
 	fromAccount.Balance -= amount
 	fromAccount.UpdatedAt = time.Now()
 	if err := s.accountRepo.Update(ctx, fromAccount); err != nil {
-		// Rollback both transactions
-		s.transactionRepo.Delete(ctx, inboundTxn.ID)
-		s.transactionRepo.Delete(ctx, outboundTxn.ID)
 		return nil, fmt.Errorf("failed to update source account balance: %w", err)
 	}
 
@@ -311,11 +262,6 @@ func (s *TransactionService) CreateTransfer(ctx context.Context, fromAccountID,
 	toAccount.Balance += amount
 	toAccount.UpdatedAt = time.Now()
 	if err := s.accountRepo.Update(ctx, toAccount); err != nil {
-		// Rollback everything
-		fromAccount.Balance += amount // Restore source balance
-		s.accountRepo.Update(ctx, fromAccount)
-		s.transactionRepo.Delete(ctx, inboundTxn.ID)
-		s.transactionRepo.Delete(ctx, outboundTxn.ID)
 		return nil, fmt.Errorf("failed to update destination account balance: %w", err)
 	}
 
@@ -326,6 +272,263 @@ func (s *TransactionService) CreateTransfer(ctx context.Context, fromAccountID,
 	return outboundTxn, nil
 }
 
+// CreateExternalTransfer sends money from an account to somewhere the budget doesn't track
+// The result is a one-sided transfer: the account's balance goes down, but it isn't
+// spending and Ready to Assign is untouched. An optional category says which budget
+// the money came out of, like a contribution from an "Investing" category.
+// LinkTransfer turns it into a full transfer once the other account is added.
+func (s *TransactionService) CreateExternalTransfer(ctx context.Context, accountID string, categoryID *string, amount int64, description string, date time.Time) (*domain.Transaction, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("transfer amount must be positive")
+	}
+	if categoryID != nil && *categoryID == "" {
+		categoryID = nil
+	}
+	if date.IsZero() {
+		date = time.Now()
+	}
+	if err := s.dates.Check(date); err != nil {
+		return nil, err
+	}
+
+	var transaction *domain.Transaction
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		account, err := s.accountRepo.GetByID(ctx, accountID)
+		if err != nil {
+			return fmt.Errorf("source account not found: %w", err)
+		}
+		if account.Type.IsOffBudget() {
+			return domain.ErrOffBudgetAccount
+		}
+		if categoryID != nil {
+			if _, err := s.categoryRepo.GetByID(ctx, *categoryID); err != nil {
+				return fmt.Errorf("category not found: %w", err)
+			}
+		}
+
+		transaction = &domain.Transaction{
+			ID:          uuid.New().String(),
+			Type:        domain.TransactionTypeTransfer,
+			AccountID:   accountID,
+			CategoryID:  categoryID,
+			Amount:      -amount,
+			Description: description,
+			Date:        date,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+		if err := s.transactionRepo.Create(ctx, transaction); err != nil {
+			return err
+		}
+
+		account.Balance -= amount
+		account.UpdatedAt = time.Now()
+		if err := s.accountRepo.Update(ctx, account); err != nil {
+			return fmt.Errorf("failed to update source account balance: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// LinkTransfer completes a one-sided transfer once its destination account is in the budget
+// The other side is added to toAccountID and moves its balance, as CreateTransfer would
+// have. The money never left the budget after all, so the transfer drops its category,
+// unless it went to an off-budget tracking account.
+func (s *TransactionService) LinkTransfer(ctx context.Context, id, toAccountID string) (*domain.Transaction, error) {
+	var transaction *domain.Transaction
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		transaction, err = s.transactionRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if transaction.Type != domain.TransactionTypeTransfer || transaction.TransferToAccountID != nil {
+			return fmt.Errorf("transaction is not a one-sided transfer")
+		}
+		if toAccountID == transaction.AccountID {
+			return fmt.Errorf("cannot transfer to the same account")
+		}
+		toAccount, err := s.accountRepo.GetByID(ctx, toAccountID)
+		if err != nil {
+			return fmt.Errorf("destination account not found: %w", err)
+		}
+
+		now := time.Now()
+		inbound := &domain.Transaction{
+			ID:                  uuid.New().String(),
+			Type:                domain.TransactionTypeTransfer,
+			AccountID:           toAccountID,
+			TransferToAccountID: &transaction.AccountID, // Link back to source
+			Amount:              -transaction.Amount,
+			Description:         transaction.Description,
+			Date:                transaction.Date,
+			CreatedAt:           now,
+			UpdatedAt:           now,
+		}
+		if err := s.transactionRepo.Create(ctx, inbound); err != nil {
+			return err
+		}
+
+		toAccount.Balance += inbound.Amount
+		toAccount.UpdatedAt = now
+		if err := s.accountRepo.Update(ctx, toAccount); err != nil {
+			return fmt.Errorf("failed to update destination account balance: %w", err)
+		}
+
+		transaction.TransferToAccountID = &toAccountID
+		if !toAccount.Type.IsOffBudget() {
+			transaction.CategoryID = nil
+		}
+		transaction.UpdatedAt = now
+		return s.transactionRepo.Update(ctx, transaction)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// SetOriginalAmount records what a foreign-currency transaction was before conversion
+// currency is an ISO 4217 code, and amount is in its cents with the same sign as the
+// transaction; an empty currency clears it. Only the converted amount counts toward
+// balances and the budget, so reconciling against statements is unaffected.
+func (s *TransactionService) SetOriginalAmount(ctx context.Context, id, currency string, amount int64) (*domain.Transaction, error) {
+	transaction, err := s.transactionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		transaction.OriginalCurrency, transaction.OriginalAmount = nil, nil
+	} else {
+		if !isCurrencyCode(currency) {
+			return nil, fmt.Errorf("original_currency must be a 3-letter ISO 4217 code")
+		}
+		if amount == 0 || (amount < 0) != (transaction.Amount < 0) {
+			return nil, fmt.Errorf("original_amount must be non-zero and have the same sign as the amount")
+		}
+		transaction.OriginalCurrency, transaction.OriginalAmount = &currency, &amount
+	}
+	transaction.UpdatedAt = time.Now()
+
+	if err := s.transactionRepo.Update(ctx, transaction); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// isCurrencyCode reports whether code looks like an ISO 4217 code, e.g. EUR
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// CreateAdjustment records a balance correction on an account
+// Adjustments change the account balance like any transaction, but aren't income or
+// spending: they have no category and don't add to Ready to Assign. Reconciliation
+// differences and write-offs belong here rather than in a made-up category.
+func (s *TransactionService) CreateAdjustment(ctx context.Context, accountID string, amount int64, note string, date time.Time) (*domain.Transaction, error) {
+	if amount == 0 {
+		return nil, fmt.Errorf("amount must be non-zero")
+	}
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return nil, fmt.Errorf("a note explaining the adjustment is required")
+	}
+
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("account not found: %w", err)
+	}
+	if date.IsZero() {
+		date = time.Now()
+	}
+	if err := s.dates.Check(date); err != nil {
+		return nil, err
+	}
+
+	transaction := &domain.Transaction{
+		ID:          uuid.New().String(),
+		Type:        domain.TransactionTypeAdjustment,
+		AccountID:   accountID,
+		Amount:      amount,
+		Description: "Balance adjustment",
+		Date:        date,
+		Note:        &note,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.transactionRepo.Create(ctx, transaction); err != nil {
+			return err
+		}
+
+		account.Balance += amount
+		account.UpdatedAt = time.Now()
+		if err := s.accountRepo.Update(ctx, account); err != nil {
+			return fmt.Errorf("failed to update account balance: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// RedeemRewards turns part of a credit account's rewards balance into a normal inflow
+// The inflow lands on the credit account itself (a statement credit) unless toAccountID
+// names another account (e.g. cash back deposited to checking), and counts towards
+// Ready to Assign like any other income.
+func (s *TransactionService) RedeemRewards(ctx context.Context, accountID string, amount int64, toAccountID, description string, date time.Time) (*domain.Transaction, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("redemption amount must be positive")
+	}
+
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.Type != domain.AccountTypeCredit {
+		return nil, fmt.Errorf("rewards are only tracked on credit accounts")
+	}
+	if toAccountID == "" {
+		toAccountID = accountID
+	}
+	if description == "" {
+		description = "Rewards redemption: " + account.Name
+	}
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	var transaction *domain.Transaction
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.accountRepo.AdjustRewardsBalance(ctx, accountID, -amount); err != nil {
+			return err
+		}
+		transaction, err = s.createTransaction(ctx, toAccountID, nil, amount, description, date)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
 // GetTransaction retrieves a transaction by ID
 func (s *TransactionService) GetTransaction(ctx context.Context, id string) (*domain.Transaction, error) {
 	return s.transactionRepo.GetByID(ctx, id)
@@ -336,6 +539,43 @@ func (s *TransactionService) ListTransactions(ctx context.Context) ([]*domain.Tr
 	return s.transactionRepo.List(ctx)
 }
 
+// MaxTransactionPageSize caps how many transactions SearchTransactions returns at once
+const MaxTransactionPageSize = 1000
+
+// SearchTransactions returns the transactions matching query and how many match in all
+// Without a limit every match is returned; a larger limit is capped at MaxTransactionPageSize.
+func (s *TransactionService) SearchTransactions(ctx context.Context, query domain.TransactionQuery) ([]*domain.Transaction, int, error) {
+	query.Text = strings.TrimSpace(query.Text)
+	if query.Sort == "" {
+		query.Sort = domain.TransactionSortDate
+		if query.Text != "" {
+			query.Sort = domain.TransactionSortRelevance
+		}
+	}
+	if !query.Sort.IsValid() {
+		return nil, 0, fmt.Errorf("invalid sort - must be date, amount, description, created_at, or relevance")
+	}
+	if query.Limit < 0 || query.Offset < 0 {
+		return nil, 0, fmt.Errorf("limit and offset cannot be negative")
+	}
+	if query.Limit > MaxTransactionPageSize {
+		query.Limit = MaxTransactionPageSize
+	}
+	if query.MinAmount != nil && query.MaxAmount != nil && *query.MinAmount > *query.MaxAmount {
+		return nil, 0, fmt.Errorf("min_amount cannot be more than max_amount")
+	}
+	if query.Since != nil && query.Until != nil && query.Since.After(*query.Until) {
+		return nil, 0, fmt.Errorf("start_date must be before end_date")
+	}
+	query.Search = strings.TrimSpace(query.Search)
+
+	transactions, total, err := s.transactionRepo.Search(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	return transactions, total, nil
+}
+
 // ListTransactionsByAccount retrieves transactions for a specific account
 func (s *TransactionService) ListTransactionsByAccount(ctx context.Context, accountID string) ([]*domain.Transaction, error) {
 	return s.transactionRepo.ListByAccount(ctx, accountID)
@@ -352,12 +592,40 @@ func (s *TransactionService) ListTransactionsByPeriod(ctx context.Context, start
 }
 
 // UpdateTransaction updates an existing transaction and adjusts account balance
+// Nothing is saved unless the whole update succeeds.
 func (s *TransactionService) UpdateTransaction(ctx context.Context, id, accountID string, categoryID *string, amount int64, description string, date time.Time) (*domain.Transaction, error) {
+	var transaction *domain.Transaction
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		transaction, err = s.updateTransaction(ctx, id, accountID, categoryID, amount, description, date)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.queueTransferScan(transaction)
+	return transaction, nil
+}
+
+func (s *TransactionService) updateTransaction(ctx context.Context, id, accountID string, categoryID *string, amount int64, description string, date time.Time) (*domain.Transaction, error) {
 	// Get existing transaction
 	oldTransaction, err := s.transactionRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	isAdjustment := oldTransaction.Type == domain.TransactionTypeAdjustment
+	if isAdjustment && categoryID != nil && *categoryID != "" {
+		return nil, fmt.Errorf("adjustments can't have a category")
+	}
+	isStartingBalance := oldTransaction.Type == domain.TransactionTypeStartingBalance
+	if isStartingBalance && categoryID != nil && *categoryID != "" {
+		return nil, fmt.Errorf("a starting balance can't have a category")
+	}
+	if !date.IsZero() {
+		if err := s.dates.Check(date); err != nil {
+			return nil, err
+		}
+	}
 
 	// Get old account to reverse balance change
 	oldAccount, err := s.accountRepo.GetByID(ctx, oldTransaction.AccountID)
@@ -376,6 +644,9 @@ func (s *TransactionService) UpdateTransaction(ctx context.Context, id, accountI
 		if err != nil {
 			return nil, fmt.Errorf("new account not found: %w", err)
 		}
+		if newAccount.Type.IsOffBudget() != oldAccount.Type.IsOffBudget() {
+			return nil, domain.ErrOffBudgetAccount
+		}
 		// Update old account (remove old transaction amount)
 		if err := s.accountRepo.Update(ctx, oldAccount); err != nil {
 			return nil, err
@@ -407,7 +678,7 @@ func (s *TransactionService) UpdateTransaction(ctx context.Context, id, accountI
 
 	if amount != 0 {
 		// Validate category requirement for expenses
-		if amount < 0 && (oldTransaction.CategoryID == nil || *oldTransaction.CategoryID == "") {
+		if amount < 0 && !isAdjustment && !isStartingBalance && (oldTransaction.CategoryID == nil || *oldTransaction.CategoryID == "") {
 			return nil, fmt.Errorf("category is required for expense transactions")
 		}
 		oldTransaction.Amount = amount
@@ -427,37 +698,45 @@ func (s *TransactionService) UpdateTransaction(ctx context.Context, id, accountI
 		return nil, err
 	}
 
+	// What was moved to a payment category is worked out again for the updated transaction
+	if s.creditCards != nil {
+		if err := s.creditCards.ReverseSpending(ctx, oldTransaction.ID); err != nil {
+			return nil, err
+		}
+		if err := s.creditCards.RecordSpending(ctx, oldTransaction); err != nil {
+			return nil, err
+		}
+	}
+
 	return oldTransaction, nil
 }
 
 // DeleteTransaction deletes a transaction and reverses its effect on account balance
 func (s *TransactionService) DeleteTransaction(ctx context.Context, id string) error {
-	// Get transaction to know the account and amount
-	transaction, err := s.transactionRepo.GetByID(ctx, id)
-	if err != nil {
-		return err
-	}
-
-	// Get account and reverse balance change
-	account, err := s.accountRepo.GetByID(ctx, transaction.AccountID)
-	if err != nil {
-		return fmt.Errorf("account not found: %w", err)
-	}
-
-	account.Balance -= transaction.Amount
-	account.UpdatedAt = time.Now()
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		// Get transaction to know the account and amount
+		transaction, err := s.transactionRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
 
-	// Delete transaction first
-	if err := s.transactionRepo.Delete(ctx, id); err != nil {
-		return err
-	}
+		// Get account and reverse balance change
+		account, err := s.accountRepo.GetByID(ctx, transaction.AccountID)
+		if err != nil {
+			return fmt.Errorf("account not found: %w", err)
+		}
 
-	// Then update account balance
-	if err := s.accountRepo.Update(ctx, account); err != nil {
-		return fmt.Errorf("failed to update account balance: %w", err)
-	}
+		account.Balance -= transaction.Amount
+		account.UpdatedAt = time.Now()
 
-	return nil
+		if err := s.transactionRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		if err := s.accountRepo.Update(ctx, account); err != nil {
+			return fmt.Errorf("failed to update account balance: %w", err)
+		}
+		return nil
+	})
 }
 
 // ListUncategorizedTransactions returns all transactions that don't have a category assigned
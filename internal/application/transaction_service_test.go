@@ -0,0 +1,340 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// mockUnitOfWork stands in for a database transaction over the mock account and
+// transaction repositories: it puts back what they held if fn fails
+type mockUnitOfWork struct {
+	accounts     *mockAccountRepository
+	transactions *mockTransactionRepository
+}
+
+func (u *mockUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	accounts := make(map[string]domain.Account, len(u.accounts.accounts))
+	for id, account := range u.accounts.accounts {
+		accounts[id] = *account
+	}
+	transactions := make([]domain.Transaction, len(u.transactions.transactions))
+	for i, transaction := range u.transactions.transactions {
+		transactions[i] = *transaction
+	}
+
+	err := fn(ctx)
+	if err == nil {
+		return nil
+	}
+	u.accounts.accounts = make(map[string]*domain.Account, len(accounts))
+	for id, account := range accounts {
+		u.accounts.accounts[id] = &account
+	}
+	u.transactions.transactions = make([]*domain.Transaction, len(transactions))
+	for i := range transactions {
+		u.transactions.transactions[i] = &transactions[i]
+	}
+	return err
+}
+
+func TestTransactionService_RedeemRewards(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["card"] = &domain.Account{ID: "card", Name: "Visa", Type: domain.AccountTypeCredit, Balance: -50000, RewardsBalance: 3000}
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking, Balance: 100000}
+	transactionRepo := newMockTransactionRepository()
+	service := NewTransactionService(transactionRepo, accountRepo, newMockCategoryRepository(), newMockBudgetStateRepository(0, 0),
+		&mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{})
+
+	txn, err := service.RedeemRewards(ctx, "card", 2000, "", "", time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txn.AccountID != "card" || txn.Amount != 2000 || txn.Type != domain.TransactionTypeNormal || txn.Description != "Rewards redemption: Visa" {
+		t.Errorf("expected a statement credit on the card, got %+v", txn)
+	}
+	if card := accountRepo.accounts["card"]; card.RewardsBalance != 1000 || card.Balance != -48000 {
+		t.Errorf("expected rewards to move into the balance, got rewards %d balance %d", card.RewardsBalance, card.Balance)
+	}
+
+	if _, err := service.RedeemRewards(ctx, "card", 1000, "checking", "Cash back", time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if accountRepo.accounts["checking"].Balance != 101000 || accountRepo.accounts["card"].RewardsBalance != 0 {
+		t.Error("expected cash back to be deposited to checking")
+	}
+
+	if _, err := service.RedeemRewards(ctx, "card", 1, "", "", time.Time{}); err == nil {
+		t.Error("expected redeeming more than the rewards balance to fail")
+	}
+	if _, err := service.RedeemRewards(ctx, "checking", 1, "", "", time.Time{}); err == nil {
+		t.Error("expected rewards on a non-credit account to fail")
+	}
+
+	// A failed inflow leaves the rewards balance unchanged
+	accountRepo.accounts["card"].RewardsBalance = 500
+	if _, err := service.RedeemRewards(ctx, "card", 500, "missing", "", time.Time{}); err == nil {
+		t.Fatal("expected redeeming to an unknown account to fail")
+	}
+	if accountRepo.accounts["card"].RewardsBalance != 500 {
+		t.Errorf("expected the rewards to be restored, got %d", accountRepo.accounts["card"].RewardsBalance)
+	}
+}
+
+func TestTransactionService_CreateAdjustment(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking, Balance: 100000}
+	transactionRepo := newMockTransactionRepository()
+	categoryRepo := newMockCategoryRepository()
+	categoryRepo.categories["groceries"] = &domain.Category{ID: "groceries", Name: "Groceries"}
+	allocationRepo := newMockAllocationRepository()
+	service := NewTransactionService(transactionRepo, accountRepo, categoryRepo, newMockBudgetStateRepository(0, 0),
+		&mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{})
+
+	if _, err := service.CreateAdjustment(ctx, "checking", 500, "  ", time.Time{}); err == nil {
+		t.Error("expected an adjustment without a note to fail")
+	}
+
+	march := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	txn, err := service.CreateAdjustment(ctx, "checking", 1234, "Bank interest missing from register", march)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txn.Type != domain.TransactionTypeAdjustment || txn.CategoryID != nil || txn.Note == nil || *txn.Note != "Bank interest missing from register" {
+		t.Errorf("expected an uncategorized adjustment with its note, got %+v", txn)
+	}
+	if accountRepo.accounts["checking"].Balance != 101234 {
+		t.Errorf("expected the adjustment in the balance, got %d", accountRepo.accounts["checking"].Balance)
+	}
+
+	// Adjustments aren't income, so Ready to Assign doesn't move
+	allocationService := NewAllocationService(allocationRepo, categoryRepo, transactionRepo, newMockBudgetStateRepository(0, 0), accountRepo, newMockCategoryGroupRepository(), newMockGoalRepository())
+	if rta, err := allocationService.CalculateReadyToAssignForPeriod(ctx, "2025-03"); err != nil || rta != 0 {
+		t.Errorf("expected Ready to Assign 0, got %d (%v)", rta, err)
+	}
+
+	// An outflow adjustment needs no category, and can't be given one
+	if _, err := service.UpdateTransaction(ctx, txn.ID, "", nil, -300, "", time.Time{}); err != nil {
+		t.Errorf("expected a negative adjustment without a category to be allowed, got %v", err)
+	}
+	groceries := "groceries"
+	if _, err := service.UpdateTransaction(ctx, txn.ID, "", &groceries, 0, "", time.Time{}); err == nil {
+		t.Error("expected categorizing an adjustment to fail")
+	}
+	if accountRepo.accounts["checking"].Balance != 99700 {
+		t.Errorf("expected the rejected update to leave the balance alone, got %d", accountRepo.accounts["checking"].Balance)
+	}
+}
+
+func TestTransactionService_ExternalTransfer(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking, Balance: 100000}
+	transactionRepo := newMockTransactionRepository()
+	categoryRepo := newMockCategoryRepository()
+	categoryRepo.categories["investing"] = &domain.Category{ID: "investing", Name: "Investing"}
+	allocationRepo := newMockAllocationRepository()
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a1", CategoryID: "investing", Period: "2025-03", Amount: 50000})
+	service := NewTransactionService(transactionRepo, accountRepo, categoryRepo, newMockBudgetStateRepository(0, 0),
+		&mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{})
+	allocationService := NewAllocationService(allocationRepo, categoryRepo, transactionRepo, newMockBudgetStateRepository(0, 0), accountRepo, newMockCategoryGroupRepository(), newMockGoalRepository())
+
+	investing := "investing"
+	march := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	txn, err := service.CreateExternalTransfer(ctx, "checking", &investing, 30000, "Brokerage contribution", march)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txn.Type != domain.TransactionTypeTransfer || txn.TransferToAccountID != nil || txn.Amount != -30000 {
+		t.Errorf("expected a one-sided transfer out, got %+v", txn)
+	}
+	if accountRepo.accounts["checking"].Balance != 70000 {
+		t.Errorf("expected the transfer to leave the balance, got %d", accountRepo.accounts["checking"].Balance)
+	}
+	summaries, err := allocationService.GetAllocationSummary(ctx, "2025-03")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summaries[0].Available != 20000 {
+		t.Errorf("expected the transfer to come out of its category, got %d available", summaries[0].Available)
+	}
+
+	// Once the brokerage is in the budget, the transfer gets its other side
+	if _, err := service.LinkTransfer(ctx, txn.ID, "brokerage"); err == nil {
+		t.Error("expected linking to a missing account to fail")
+	}
+	accountRepo.accounts["brokerage"] = &domain.Account{ID: "brokerage", Name: "Brokerage", Type: domain.AccountTypeSavings}
+	linked, err := service.LinkTransfer(ctx, txn.ID, "brokerage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if linked.TransferToAccountID == nil || *linked.TransferToAccountID != "brokerage" || linked.CategoryID != nil {
+		t.Errorf("expected the transfer to point at the brokerage without a category, got %+v", linked)
+	}
+	if accountRepo.accounts["brokerage"].Balance != 30000 || accountRepo.accounts["checking"].Balance != 70000 {
+		t.Errorf("expected the money to arrive in the brokerage, got checking %d brokerage %d",
+			accountRepo.accounts["checking"].Balance, accountRepo.accounts["brokerage"].Balance)
+	}
+	if _, err := service.LinkTransfer(ctx, txn.ID, "brokerage"); err == nil {
+		t.Error("expected linking a full transfer again to fail")
+	}
+}
+
+func TestTransactionService_DateBounds(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking}
+	transactionRepo := newMockTransactionRepository()
+	service := NewTransactionService(transactionRepo, accountRepo, newMockCategoryRepository(), newMockBudgetStateRepository(0, 0),
+		&mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{PastYears: 30, FutureDays: 366})
+
+	typo := time.Date(2205, 3, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := service.CreateTransaction(ctx, "checking", nil, 5000, "Paycheck", typo); err == nil {
+		t.Error("expected a transaction dated 2205 to be rejected")
+	}
+	if _, err := service.CreateAdjustment(ctx, "checking", 5000, "Opening balance", time.Date(1925, 3, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Error("expected an adjustment dated 1925 to be rejected")
+	}
+
+	// A missing date means today
+	txn, err := service.CreateTransaction(ctx, "checking", nil, 5000, "Paycheck", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(txn.Date) > time.Minute {
+		t.Errorf("expected a transaction without a date to be dated today, got %v", txn.Date)
+	}
+	if _, err := service.UpdateTransaction(ctx, txn.ID, "", nil, 0, "", typo); err == nil {
+		t.Error("expected moving a transaction to 2205 to be rejected")
+	}
+	if accountRepo.accounts["checking"].Balance != 5000 {
+		t.Errorf("expected only the valid transaction in the balance, got %d", accountRepo.accounts["checking"].Balance)
+	}
+}
+
+func TestTransactionService_SearchTransactions(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	transactionRepo := newMockTransactionRepository()
+	for i := 0; i < 5; i++ {
+		transactionRepo.transactions = append(transactionRepo.transactions, &domain.Transaction{
+			ID: fmt.Sprintf("t%d", i), AccountID: "checking", Amount: -1000, Description: "Coffee",
+		})
+	}
+	transactionRepo.transactions = append(transactionRepo.transactions, &domain.Transaction{ID: "other", AccountID: "savings", Amount: 500, Description: "Interest"})
+	service := NewTransactionService(transactionRepo, accountRepo, newMockCategoryRepository(), newMockBudgetStateRepository(0, 0),
+		&mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{})
+
+	page, total, err := service.SearchTransactions(ctx, domain.TransactionQuery{AccountID: "checking", Search: "  coffee ", Limit: 2, Offset: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 5 || len(page) != 1 || page[0].ID != "t4" {
+		t.Errorf("expected the last of 5 matches, got %d of %d", len(page), total)
+	}
+
+	// Text searches rank best matches first unless told otherwise
+	matches, total, err := service.SearchTransactions(ctx, domain.TransactionQuery{Text: " interest "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || matches[0].ID != "other" {
+		t.Errorf("expected the interest transaction, got %d matches", total)
+	}
+	if transactionRepo.lastQuery.Sort != domain.TransactionSortRelevance || transactionRepo.lastQuery.Text != "interest" {
+		t.Errorf("expected a trimmed relevance search, got %+v", transactionRepo.lastQuery)
+	}
+
+	low, high := int64(5000), int64(1000)
+	for _, query := range []domain.TransactionQuery{
+		{Sort: "payee"},
+		{Limit: -1},
+		{MinAmount: &low, MaxAmount: &high},
+	} {
+		if _, _, err := service.SearchTransactions(ctx, query); err == nil {
+			t.Errorf("expected query %+v to be rejected", query)
+		}
+	}
+}
+
+func TestTransactionService_SetOriginalAmount(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking, Balance: 100000}
+	transactionRepo := newMockTransactionRepository()
+	transactionRepo.Create(ctx, &domain.Transaction{ID: "t1", Type: domain.TransactionTypeNormal, AccountID: "checking", Amount: -5430, Description: "Hotel Lisboa"})
+	service := NewTransactionService(transactionRepo, accountRepo, newMockCategoryRepository(), newMockBudgetStateRepository(0, 0),
+		&mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{})
+
+	txn, err := service.SetOriginalAmount(ctx, "t1", " eur ", -5000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txn.OriginalCurrency == nil || *txn.OriginalCurrency != "EUR" || *txn.OriginalAmount != -5000 || txn.Amount != -5430 {
+		t.Errorf("expected EUR -5000 kept beside the converted amount, got %+v", txn)
+	}
+	if accountRepo.accounts["checking"].Balance != 100000 {
+		t.Errorf("expected the balance to be left alone, got %d", accountRepo.accounts["checking"].Balance)
+	}
+
+	for _, bad := range []struct {
+		currency string
+		amount   int64
+	}{{"EURO", -5000}, {"E1R", -5000}, {"EUR", 5000}, {"EUR", 0}} {
+		if _, err := service.SetOriginalAmount(ctx, "t1", bad.currency, bad.amount); err == nil {
+			t.Errorf("expected %s %d to be rejected", bad.currency, bad.amount)
+		}
+	}
+
+	if txn, err = service.SetOriginalAmount(ctx, "t1", "", 0); err != nil || txn.OriginalCurrency != nil || txn.OriginalAmount != nil {
+		t.Errorf("expected the original amount to be cleared, got %+v (%v)", txn, err)
+	}
+}
+
+func TestTransactionService_CreateAppliesPayeeRules(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking}
+	categoryRepo := newMockCategoryRepository()
+	categoryRepo.categories["rent"] = &domain.Category{ID: "rent", Name: "Rent"}
+	categoryRepo.categories["fun"] = &domain.Category{ID: "fun", Name: "Fun"}
+	transactionRepo := newMockTransactionRepository()
+	transactions := NewTransactionService(transactionRepo, accountRepo, categoryRepo, newMockBudgetStateRepository(0, 0),
+		&mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{})
+	payees := NewPayeeService(newMockPayeeRepository(), newMockPayeeRuleRepository(), accountRepo, categoryRepo)
+	transactions.UsePayees(payees)
+
+	rent, memo, minimum := "rent", "Monthly rent", int64(100000)
+	rule, err := payees.CreateRule(ctx, "landlord", "", nil, &minimum, nil, "Landlord", &rent, &memo, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := transactions.CreateTransaction(ctx, "checking", nil, -150000, "LANDLORD LLC", time.Now())
+	if err != nil {
+		t.Fatalf("expected the rule to give the outflow its category, got %v", err)
+	}
+	if created.CategoryID == nil || *created.CategoryID != "rent" || created.PayeeID == nil || *created.PayeeID != *rule.PayeeID ||
+		created.Note == nil || *created.Note != memo {
+		t.Errorf("expected the rule's category, payee and memo, got %+v", created)
+	}
+
+	fun := "fun"
+	chosen, err := transactions.CreateTransaction(ctx, "checking", &fun, -200000, "Landlord party", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *chosen.CategoryID != "fun" || chosen.PayeeID == nil {
+		t.Errorf("expected the chosen category to win over the rule's, got %+v", chosen)
+	}
+
+	// Below the rule's minimum, so nothing gives the outflow a category
+	if _, err := transactions.CreateTransaction(ctx, "checking", nil, -5000, "LANDLORD LLC", time.Now()); err == nil {
+		t.Error("expected an outflow the rule doesn't match to still need a category")
+	}
+}
@@ -0,0 +1,376 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/google/uuid"
+)
+
+// transferAmountPenalty is what an amount off by the whole tolerance costs a candidate's score
+// Any difference costs at least a point, so an exact amount beats a near one on the same day.
+const transferAmountPenalty = 2
+
+// TransferMatching sets how far apart the two sides of a transfer may be
+// A candidate scores Days less the days between the two dates, less up to
+// transferAmountPenalty for an amount that's off, plus a hint boost of Days when a hint
+// points at its account. Transfers already recorded in the account count for half a hint,
+// and a candidate needs a score of Days to match. Without a hint a recorded transfer
+// matches up to half the window apart; with one, anything inside the window does.
+type TransferMatching struct {
+	Days         int   `json:"days"`          // How many days apart the two sides may be dated
+	ToleranceBps int64 `json:"tolerance_bps"` // How far the amounts may differ, in basis points of the amount
+	FeeTolerance int64 `json:"fee_tolerance"` // How far the amounts may differ in cents, e.g. a wire fee; the larger tolerance applies
+}
+
+// DefaultTransferMatching matches exact amounts up to four days apart
+var DefaultTransferMatching = TransferMatching{Days: 4}
+
+// Validate checks the window is at least a day and the tolerances aren't negative
+func (m TransferMatching) Validate() error {
+	if m.Days < 1 {
+		return fmt.Errorf("transfer match window must be at least 1 day")
+	}
+	if m.ToleranceBps < 0 || m.FeeTolerance < 0 {
+		return fmt.Errorf("transfer amount tolerance cannot be negative")
+	}
+	return nil
+}
+
+// exact is the same window without the amount tolerance
+func (m TransferMatching) exact() TransferMatching {
+	m.ToleranceBps, m.FeeTolerance = 0, 0
+	return m
+}
+
+func (m TransferMatching) hintBoost() int     { return m.Days }
+func (m TransferMatching) recordedBoost() int { return m.Days / 2 }
+func (m TransferMatching) learnedBoost() int  { return m.Days / 2 }
+func (m TransferMatching) threshold() int     { return m.Days }
+
+// score scores a candidate for the other side of a transfer of amount on date, before any
+// boost; ok is false when it's outside the window or the amount tolerance
+// Dates are compared by calendar day, so the time of day doesn't change the ranking.
+func (m TransferMatching) score(date time.Time, amount int64, candidate *domain.Transaction) (score int, ok bool) {
+	days := domain.DaysApart(date, candidate.Date)
+	if days > m.Days {
+		return 0, false
+	}
+	difference := candidate.Amount - amount
+	if difference < 0 {
+		difference = -difference
+	}
+	if difference == 0 {
+		return m.Days - days, true
+	}
+	if (candidate.Amount < 0) != (amount < 0) {
+		return 0, false
+	}
+	magnitude := amount
+	if magnitude < 0 {
+		magnitude = -magnitude
+	}
+	tolerance := max(m.FeeTolerance, magnitude*m.ToleranceBps/10000)
+	if difference > tolerance {
+		return 0, false
+	}
+	penalty := int((difference*transferAmountPenalty + tolerance - 1) / tolerance)
+	return m.Days - days - penalty, true
+}
+
+// TransferHintService handles the hints that mark imported transactions as transfers
+type TransferHintService struct {
+	hintRepo        domain.TransferHintRepository
+	accountRepo     domain.AccountRepository
+	transactionRepo domain.TransactionRepository
+	matching        TransferMatching
+}
+
+// NewTransferHintService creates a new transfer hint service
+func NewTransferHintService(hintRepo domain.TransferHintRepository, accountRepo domain.AccountRepository, transactionRepo domain.TransactionRepository) *TransferHintService {
+	return &TransferHintService{
+		hintRepo:        hintRepo,
+		accountRepo:     accountRepo,
+		transactionRepo: transactionRepo,
+		matching:        DefaultTransferMatching,
+	}
+}
+
+// UseMatching sets how far apart imported transfers may be from their other side
+func (s *TransferHintService) UseMatching(matching TransferMatching) {
+	s.matching = matching
+}
+
+// CreateHint marks descriptions containing pattern, imported into the account, as transfers to the target account
+func (s *TransferHintService) CreateHint(ctx context.Context, accountID, pattern, targetAccountID string) (*domain.TransferHint, error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return nil, fmt.Errorf("pattern is required")
+	}
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if targetAccountID == accountID {
+		return nil, fmt.Errorf("cannot transfer to the same account")
+	}
+	target, err := s.accountRepo.GetByID(ctx, targetAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("destination account not found: %w", err)
+	}
+	// Imports into a tracking account are balance updates, and never paired with another account
+	if account.Type.IsOffBudget() || target.Type.IsOffBudget() {
+		return nil, domain.ErrOffBudgetAccount
+	}
+
+	hint := &domain.TransferHint{
+		ID:              uuid.New().String(),
+		AccountID:       accountID,
+		Pattern:         pattern,
+		TargetAccountID: targetAccountID,
+		CreatedAt:       time.Now(),
+	}
+	if err := s.hintRepo.Create(ctx, hint); err != nil {
+		return nil, err
+	}
+	return hint, nil
+}
+
+// ListHints retrieves an account's transfer hints
+func (s *TransferHintService) ListHints(ctx context.Context, accountID string) ([]*domain.TransferHint, error) {
+	return s.hintRepo.ListByAccount(ctx, accountID)
+}
+
+// DeleteHint removes one of an account's transfer hints; transfers it already linked stay linked
+func (s *TransferHintService) DeleteHint(ctx context.Context, accountID, id string) error {
+	hint, err := s.hintRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if hint.AccountID != accountID {
+		return domain.ErrTransferHintNotFound
+	}
+	return s.hintRepo.Delete(ctx, id)
+}
+
+// transferMatcher finds the other side of transfers in one account's import
+type transferMatcher struct {
+	service   *TransferHintService
+	accountID string
+	importID  string
+	hints     []*domain.TransferHint
+	byAccount map[string][]*domain.Transaction // Transactions of the accounts looked at so far
+	matched   map[string]bool                  // Transactions already matched by this import
+}
+
+func (s *TransferHintService) newMatcher(ctx context.Context, accountID, importID string) (*transferMatcher, error) {
+	hints, err := s.hintRepo.ListByAccount(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transfer hints: %w", err)
+	}
+	return &transferMatcher{
+		service:   s,
+		accountID: accountID,
+		importID:  importID,
+		hints:     hints,
+		byAccount: make(map[string][]*domain.Transaction),
+		matched:   make(map[string]bool),
+	}, nil
+}
+
+// claim matches an imported transaction to a transfer already recorded in the account
+// Those are placeholders left by importing the other account, or transfers entered by
+// hand. The transfer takes the imported FitID, so it is treated as imported from now on.
+// The amount has to be exact, as the transfer is already in the balance. Returns nil if
+// nothing matches.
+func (m *transferMatcher) claim(ctx context.Context, txn ImportedTransaction) (*domain.Transaction, error) {
+	transactions, err := m.transactions(ctx, m.accountID)
+	if err != nil {
+		return nil, err
+	}
+	hint := m.hintFor(txn.Description)
+	matching := m.service.matching
+	transfer := m.best(matching.exact(), transactions, txn.Date, txn.Amount, func(candidate *domain.Transaction) int {
+		if candidate.Type != domain.TransactionTypeTransfer || candidate.FitID != nil || candidate.TransferToAccountID == nil {
+			return -1
+		}
+		if hint != nil && hint.TargetAccountID == *candidate.TransferToAccountID {
+			return matching.recordedBoost() + matching.hintBoost()
+		}
+		return matching.recordedBoost()
+	})
+	if transfer == nil {
+		return nil, nil
+	}
+
+	fitID := txn.FitID
+	transfer.FitID = &fitID
+	transfer.ImportID = &m.importID
+	transfer.UpdatedAt = time.Now()
+	if err := m.service.transactionRepo.Update(ctx, transfer); err != nil {
+		return nil, fmt.Errorf("failed to match transfer: %w", err)
+	}
+	return transfer, nil
+}
+
+// pair turns a saved import into a transfer if one of the account's hints matches it
+// The other side is the best-scoring transaction in the hinted account; when its amount is
+// off, like a wire that arrived less a fee, the difference is split off the import. If that
+// account has nothing to match yet, a placeholder is added to it and its balance moves, so
+// importing it later claims the placeholder rather than duplicating it. Returns the other
+// side, or nil if no hint matches.
+func (m *transferMatcher) pair(ctx context.Context, transaction *domain.Transaction) (other *domain.Transaction, placeholder bool, err error) {
+	hint := m.hintFor(transaction.Description)
+	if hint == nil {
+		return nil, false, nil
+	}
+	target, err := m.service.accountRepo.GetByID(ctx, hint.TargetAccountID)
+	if err != nil {
+		return nil, false, nil // The hint outlived its account
+	}
+	candidates, err := m.transactions(ctx, target.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	now := time.Now()
+	matching := m.service.matching
+	other = m.best(matching, candidates, transaction.Date, -transaction.Amount, func(candidate *domain.Transaction) int {
+		if candidate.Type != domain.TransactionTypeNormal {
+			return -1
+		}
+		return matching.hintBoost()
+	})
+	if other != nil {
+		if _, err := splitTransferDifference(ctx, m.service.transactionRepo, transaction, other.Amount); err != nil {
+			return nil, false, err
+		}
+		other.Type = domain.TransactionTypeTransfer
+		other.TransferToAccountID = &m.accountID
+		other.CategoryID = nil
+		other.UpdatedAt = now
+		if err := m.service.transactionRepo.Update(ctx, other); err != nil {
+			return nil, false, fmt.Errorf("failed to link transfer: %w", err)
+		}
+	} else {
+		other = &domain.Transaction{
+			ID:                  uuid.New().String(),
+			Type:                domain.TransactionTypeTransfer,
+			AccountID:           target.ID,
+			TransferToAccountID: &m.accountID,
+			Amount:              -transaction.Amount,
+			Description:         transaction.Description,
+			Date:                transaction.Date,
+			CreatedAt:           now,
+			UpdatedAt:           now,
+		}
+		if err := m.service.transactionRepo.Create(ctx, other); err != nil {
+			return nil, false, fmt.Errorf("failed to create transfer placeholder: %w", err)
+		}
+
+		// Money moving between accounts leaves Ready to Assign alone
+		target.Balance += other.Amount
+		target.UpdatedAt = now
+		if err := m.service.accountRepo.Update(ctx, target); err != nil {
+			return nil, false, fmt.Errorf("failed to update destination account balance: %w", err)
+		}
+		placeholder = true
+	}
+
+	transaction.Type = domain.TransactionTypeTransfer
+	transaction.TransferToAccountID = &target.ID
+	transaction.CategoryID = nil
+	transaction.UpdatedAt = now
+	if err := m.service.transactionRepo.Update(ctx, transaction); err != nil {
+		return nil, false, fmt.Errorf("failed to link transfer: %w", err)
+	}
+	return other, placeholder, nil
+}
+
+// hintFor returns the first of the account's hints matching a description, or nil
+func (m *transferMatcher) hintFor(description string) *domain.TransferHint {
+	description = strings.ToLower(description)
+	for _, hint := range m.hints {
+		if strings.Contains(description, strings.ToLower(hint.Pattern)) {
+			return hint
+		}
+	}
+	return nil
+}
+
+// best returns the highest-scoring candidate for the amount and date, or nil if none reaches the threshold
+// bonus adds to a candidate's score, or rules it out by returning a negative number.
+// Ties go to the earlier candidate.
+func (m *transferMatcher) best(matching TransferMatching, candidates []*domain.Transaction, date time.Time, amount int64, bonus func(*domain.Transaction) int) *domain.Transaction {
+	var best *domain.Transaction
+	bestScore := matching.threshold() - 1
+	for _, candidate := range candidates {
+		if m.matched[candidate.ID] {
+			continue
+		}
+		score, ok := matching.score(date, amount, candidate)
+		if !ok {
+			continue
+		}
+		extra := bonus(candidate)
+		if extra < 0 {
+			continue
+		}
+		if score += extra; score > bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+	if best != nil {
+		m.matched[best.ID] = true
+	}
+	return best
+}
+
+func (m *transferMatcher) transactions(ctx context.Context, accountID string) ([]*domain.Transaction, error) {
+	if transactions, ok := m.byAccount[accountID]; ok {
+		return transactions, nil
+	}
+	transactions, err := m.service.transactionRepo.ListByAccount(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+	m.byAccount[accountID] = transactions
+	return transactions, nil
+}
+
+// splitTransferDifference evens out a transfer whose two sides the banks recorded differently
+// side becomes the opposite of otherAmount, and what's left of it, like a wire fee, is split
+// off into a normal, uncategorized transaction of its own in the same account and import, so
+// the account's balance doesn't move. side must be saved by the caller. Returns the split-off
+// transaction, or nil when the sides already match.
+func splitTransferDifference(ctx context.Context, transactionRepo domain.TransactionRepository, side *domain.Transaction, otherAmount int64) (*domain.Transaction, error) {
+	difference := side.Amount + otherAmount
+	if difference == 0 {
+		return nil, nil
+	}
+	description := "Transfer fee"
+	if difference > 0 {
+		description = "Transfer difference"
+	}
+	now := time.Now()
+	split := &domain.Transaction{
+		ID:          uuid.New().String(),
+		Type:        domain.TransactionTypeNormal,
+		AccountID:   side.AccountID,
+		Amount:      difference,
+		Description: description + ": " + side.Description,
+		Date:        side.Date,
+		ImportID:    side.ImportID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := transactionRepo.Create(ctx, split); err != nil {
+		return nil, fmt.Errorf("failed to split off the transfer difference: %w", err)
+	}
+	side.Amount = -otherAmount
+	side.UpdatedAt = now
+	return split, nil
+}
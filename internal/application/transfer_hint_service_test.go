@@ -0,0 +1,208 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/billybbuffum/budget/internal/infrastructure/csv"
+	"github.com/billybbuffum/budget/internal/infrastructure/ofx"
+	"github.com/billybbuffum/budget/internal/infrastructure/qif"
+)
+
+type mockTransferHintRepository struct {
+	hints []*domain.TransferHint
+}
+
+func (m *mockTransferHintRepository) Create(ctx context.Context, hint *domain.TransferHint) error {
+	m.hints = append(m.hints, hint)
+	return nil
+}
+
+func (m *mockTransferHintRepository) GetByID(ctx context.Context, id string) (*domain.TransferHint, error) {
+	for _, hint := range m.hints {
+		if hint.ID == id {
+			return hint, nil
+		}
+	}
+	return nil, domain.ErrTransferHintNotFound
+}
+
+func (m *mockTransferHintRepository) ListByAccount(ctx context.Context, accountID string) ([]*domain.TransferHint, error) {
+	var result []*domain.TransferHint
+	for _, hint := range m.hints {
+		if hint.AccountID == accountID {
+			result = append(result, hint)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockTransferHintRepository) Delete(ctx context.Context, id string) error {
+	return nil
+}
+
+func TestImportService_TransferHints(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking}
+	accountRepo.accounts["savings"] = &domain.Account{ID: "savings", Name: "Savings", Type: domain.AccountTypeSavings}
+	transactionRepo := newMockTransactionRepository()
+	categoryRepo := newMockCategoryRepository()
+	transfers := NewTransferHintService(&mockTransferHintRepository{}, accountRepo, transactionRepo)
+	service := NewImportService(transactionRepo, accountRepo, newMockBudgetStateRepository(0, 0), &mockImportFileRepository{}, newMockSettingRepository(),
+		ofx.NewParser(), csv.NewParser(), qif.NewParser(),
+		NewPluginService(categoryRepo, accountRepo, transactionRepo, newMockAllocationRepository(), nil, nil, nil),
+		NewPayeeService(newMockPayeeRepository(), newMockPayeeRuleRepository(), newMockAccountRepository(0), categoryRepo),
+		transfers, &mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{})
+
+	if _, err := transfers.CreateHint(ctx, "checking", "transfer to sav", "checking"); err == nil {
+		t.Error("expected a hint pointing at its own account to be rejected")
+	}
+	if _, err := transfers.CreateHint(ctx, "checking", "transfer to sav", "savings"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Savings isn't imported yet, so the transfer leaves a placeholder there
+	checking := "!Type:Bank\nD3/5/2025\nT-100.00\nPONLINE TRANSFER TO SAV 1234\n^\nD3/5/2025\nT-20.00\nPGROCERIES\n^\n"
+	result, err := service.Import(ctx, "checking", strings.NewReader(checking))
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := result.Rows[0]
+	if row.Status != ImportRowNew || row.TransferAccountID != "savings" || !row.PlaceholderCreated {
+		t.Fatalf("expected the hinted row to become a transfer with a placeholder, got %+v", row)
+	}
+	if result.Rows[1].TransferAccountID != "" {
+		t.Errorf("expected the unhinted row to stay a normal transaction, got %+v", result.Rows[1])
+	}
+	placeholderID := row.TransferTransactionID
+	transfer, _ := transactionRepo.GetByID(ctx, row.TransactionID)
+	if transfer.Type != domain.TransactionTypeTransfer || *transfer.TransferToAccountID != "savings" {
+		t.Errorf("expected the imported transaction to be a transfer to savings, got %+v", transfer)
+	}
+	if accountRepo.accounts["savings"].Balance != 10000 || accountRepo.accounts["checking"].Balance != -12000 {
+		t.Errorf("expected the transfer in both balances, got checking %d, savings %d",
+			accountRepo.accounts["checking"].Balance, accountRepo.accounts["savings"].Balance)
+	}
+
+	// Importing savings a day later claims the placeholder instead of duplicating it
+	savings := "!Type:Bank\nD3/6/2025\nT100.00\nPTRANSFER FROM CHK\n^\n"
+	result, err = service.Import(ctx, "savings", strings.NewReader(savings))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row := result.Rows[0]; row.Status != ImportRowTransfer || row.TransactionID != placeholderID || row.TransferAccountID != "checking" {
+		t.Errorf("expected the placeholder to be claimed, got %+v", row)
+	}
+	placeholder, _ := transactionRepo.GetByID(ctx, placeholderID)
+	if placeholder.FitID == nil || placeholder.ImportID == nil || placeholder.Amount != 10000 {
+		t.Errorf("expected the placeholder to take the imported FitID, got %+v", placeholder)
+	}
+	if accountRepo.accounts["savings"].Balance != 10000 {
+		t.Errorf("expected the claimed transfer not to be counted twice, got savings %d", accountRepo.accounts["savings"].Balance)
+	}
+
+	// When savings is imported first, the transfer is linked to what's already there
+	deposit := "!Type:Bank\nD3/20/2025\nT50.00\nPDEPOSIT\n^\n"
+	if _, err := service.Import(ctx, "savings", strings.NewReader(deposit)); err != nil {
+		t.Fatal(err)
+	}
+	result, err = service.Import(ctx, "checking", strings.NewReader("!Type:Bank\nD3/18/2025\nT-50.00\nPONLINE TRANSFER TO SAV 1234\n^\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	row = result.Rows[0]
+	if row.TransferAccountID != "savings" || row.PlaceholderCreated {
+		t.Fatalf("expected the transfer to be linked to the savings deposit, got %+v", row)
+	}
+	other, _ := transactionRepo.GetByID(ctx, row.TransferTransactionID)
+	if other.Type != domain.TransactionTypeTransfer || *other.TransferToAccountID != "checking" || other.Amount != 5000 {
+		t.Errorf("expected the savings deposit to become the other side, got %+v", other)
+	}
+	if accountRepo.accounts["savings"].Balance != 15000 {
+		t.Errorf("expected linking to leave the savings balance alone, got %d", accountRepo.accounts["savings"].Balance)
+	}
+}
+
+func TestImportService_TransferHintWithFee(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking}
+	accountRepo.accounts["savings"] = &domain.Account{ID: "savings", Name: "Savings", Type: domain.AccountTypeSavings, Balance: 99000}
+	transactionRepo := newMockTransactionRepository()
+	deposit := &domain.Transaction{ID: "deposit", Type: domain.TransactionTypeNormal, AccountID: "savings", Amount: 99000,
+		Description: "Incoming wire", Date: time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC)}
+	transactionRepo.transactions = append(transactionRepo.transactions, deposit)
+	categoryRepo := newMockCategoryRepository()
+	transfers := NewTransferHintService(&mockTransferHintRepository{}, accountRepo, transactionRepo)
+	service := NewImportService(transactionRepo, accountRepo, newMockBudgetStateRepository(0, 0), &mockImportFileRepository{}, newMockSettingRepository(),
+		ofx.NewParser(), csv.NewParser(), qif.NewParser(),
+		NewPluginService(categoryRepo, accountRepo, transactionRepo, newMockAllocationRepository(), nil, nil, nil),
+		NewPayeeService(newMockPayeeRepository(), newMockPayeeRuleRepository(), newMockAccountRepository(0), categoryRepo),
+		transfers, &mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{})
+	if _, err := transfers.CreateHint(ctx, "checking", "wire to sav", "savings"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The wire arrived 10.00 short: with a fee tolerance it's still the other side
+	transfers.UseMatching(TransferMatching{Days: 4, FeeTolerance: 2500})
+	wire := "!Type:Bank\nD3/5/2025\nT-1000.00\nPWIRE TO SAV\n^\n"
+	result, err := service.Import(ctx, "checking", strings.NewReader(wire))
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := result.Rows[0]
+	if row.TransferTransactionID != "deposit" || row.PlaceholderCreated {
+		t.Fatalf("expected the wire linked to the deposit, got %+v", row)
+	}
+	transfer, _ := transactionRepo.GetByID(ctx, row.TransactionID)
+	if transfer.Type != domain.TransactionTypeTransfer || transfer.Amount != -99000 {
+		t.Errorf("expected the transfer to match the deposit, got %+v", transfer)
+	}
+	imported, _ := transactionRepo.ListByImport(ctx, result.ImportID)
+	var fee *domain.Transaction
+	for _, transaction := range imported {
+		if transaction.ID != transfer.ID {
+			fee = transaction
+		}
+	}
+	if fee == nil || fee.Type != domain.TransactionTypeNormal || fee.Amount != -1000 || fee.AccountID != "checking" || fee.CategoryID != nil {
+		t.Fatalf("expected the fee split off as an uncategorized outflow, got %+v", fee)
+	}
+	if accountRepo.accounts["checking"].Balance != -100000 || accountRepo.accounts["savings"].Balance != 99000 {
+		t.Errorf("expected splitting the fee to leave balances alone, got checking %d, savings %d",
+			accountRepo.accounts["checking"].Balance, accountRepo.accounts["savings"].Balance)
+	}
+
+	// Undoing the import takes the fee with it
+	if _, err := service.UndoImport(ctx, result.ImportID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := transactionRepo.GetByID(ctx, fee.ID); err == nil {
+		t.Error("expected undoing the import to delete the fee")
+	}
+	if accountRepo.accounts["checking"].Balance != 0 || deposit.Type != domain.TransactionTypeNormal {
+		t.Errorf("expected the import fully undone, got checking %d, deposit %+v", accountRepo.accounts["checking"].Balance, deposit)
+	}
+}
+
+func TestTransferMatching_ScoresCalendarDays(t *testing.T) {
+	matching := TransferMatching{Days: 4}
+	evening := time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC)
+	nextMorning := &domain.Transaction{Amount: 5000, Date: time.Date(2026, 3, 2, 22, 0, 0, 0, time.UTC)}
+	sameDay := &domain.Transaction{Amount: 5000, Date: time.Date(2026, 3, 1, 1, 0, 0, 0, time.UTC)}
+
+	if score, ok := matching.score(evening, 5000, nextMorning); !ok || score != 3 {
+		t.Errorf("expected the next day, 23 hours later, to score 3, got %d (ok %v)", score, ok)
+	}
+	if score, ok := matching.score(evening, 5000, sameDay); !ok || score != 4 {
+		t.Errorf("expected the same day, 22 hours earlier, to score 4, got %d (ok %v)", score, ok)
+	}
+	lastDay := &domain.Transaction{Amount: 5000, Date: time.Date(2026, 3, 5, 23, 59, 0, 0, time.UTC)}
+	if _, ok := matching.score(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), 5000, lastDay); !ok {
+		t.Error("expected a transaction on the window's last day to match whatever its time")
+	}
+}
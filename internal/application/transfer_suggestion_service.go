@@ -0,0 +1,477 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/google/uuid"
+)
+
+// transferSuggestionThreshold is the score a pair of transactions needs to be suggested
+// Pairs are scored like imported transfers, but a person reviews each suggestion, so the
+// bar is lower: without a hint, two sides of the same amount may be up to a day less than
+// the window apart. Payees paired by accepted suggestions before add half a hint.
+const transferSuggestionThreshold = 1
+
+// transferScanDelay is how long after a transaction is entered or changed by hand the days
+// around it are scanned; more edits in the meantime push the scan back, so a batch of
+// entries is scanned once
+const transferScanDelay = 5 * time.Second
+
+// TransferSuggestionService finds transactions entered as an outflow in one account and an
+// inflow in another that are really both sides of one transfer
+// Imports pair transfers as they're saved; scans catch the ones entered by hand, suggesting
+// each pair once. Accepting a suggestion links the pair as a transfer; a rejected pair isn't
+// suggested again, though either transaction may still be paired with another. Reviews are
+// remembered by payee too, so a recurring pair rejected before stays rejected when it's
+// entered or imported again.
+type TransferSuggestionService struct {
+	suggestionRepo domain.TransferSuggestionRepository
+	pairingRepo    domain.TransferPairingRepository
+	hintRepo       domain.TransferHintRepository
+	transactions   *TransactionService
+	matching       TransferMatching
+	scanDays       int
+	mu             sync.Mutex // Keeps scans from suggesting the same transaction twice
+
+	// The scan queued by QueueScan, until its timer fires
+	scanDelay   time.Duration
+	queueMu     sync.Mutex
+	queuedStart time.Time
+	queuedEnd   time.Time
+	queueTimer  *time.Timer
+}
+
+// NewTransferSuggestionService creates a new transfer suggestion service
+// Scans match with matching unless told otherwise; ScanRecent looks back scanDays days.
+func NewTransferSuggestionService(suggestionRepo domain.TransferSuggestionRepository, pairingRepo domain.TransferPairingRepository, hintRepo domain.TransferHintRepository, transactionService *TransactionService, matching TransferMatching, scanDays int) *TransferSuggestionService {
+	return &TransferSuggestionService{
+		suggestionRepo: suggestionRepo,
+		pairingRepo:    pairingRepo,
+		hintRepo:       hintRepo,
+		transactions:   transactionService,
+		matching:       matching,
+		scanDays:       scanDays,
+		scanDelay:      transferScanDelay,
+	}
+}
+
+// Matching returns how scans match unless told otherwise
+func (s *TransferSuggestionService) Matching() TransferMatching {
+	return s.matching
+}
+
+// TransferSuggestionDetail is a suggestion with both of its transactions
+type TransferSuggestionDetail struct {
+	*domain.TransferSuggestion
+	From *domain.Transaction `json:"from"`
+	To   *domain.Transaction `json:"to"`
+	Fee  *domain.Transaction `json:"fee,omitempty"` // What accepting split off the outflow when the amounts differed
+}
+
+// TransferScan is the result of scanning a date range for transfers
+type TransferScan struct {
+	Start        time.Time                   `json:"start"`
+	End          time.Time                   `json:"end"`
+	Matching     TransferMatching            `json:"matching"`
+	Transactions int                         `json:"transactions"` // Transactions that could be one side of a transfer
+	Suggestions  []*TransferSuggestionDetail `json:"suggestions"`  // Suggestions the scan added
+}
+
+// Scan suggests transfers for outflows dated from start to end, in every account
+// The inflow may fall up to matching's window outside the range, and its amount may be off
+// by matching's tolerance, costing it score. Transactions waiting in a suggestion are left
+// out, so scanning the same range again only adds what's new, and payees rejected more often
+// than accepted aren't paired. A zero end is now, and a zero start looks back as far as the
+// scheduled scan does.
+func (s *TransferSuggestionService) Scan(ctx context.Context, start, end time.Time, matching TransferMatching) (*TransferScan, error) {
+	if err := matching.Validate(); err != nil {
+		return nil, err
+	}
+	if end.IsZero() {
+		end = time.Now()
+	}
+	if start.IsZero() {
+		start = end.AddDate(0, 0, -s.scanDays)
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("end must not be before start")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// A day wider than the window, so candidates on the window's last calendar day are
+	// listed whatever their time of day; scoring leaves out the ones too far apart
+	windowStart, windowEnd := start.AddDate(0, 0, -(matching.Days+1)), end.AddDate(0, 0, matching.Days+1)
+	transactions, err := s.transactions.transactionRepo.ListByPeriod(ctx, windowStart.Format(time.RFC3339), windowEnd.Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+	existing, err := s.suggestionRepo.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[string]bool)
+	rejected := make(map[[2]string]bool)
+	for _, suggestion := range existing {
+		if suggestion.Status == domain.TransferSuggestionRejected {
+			rejected[[2]string{suggestion.FromTransactionID, suggestion.ToTransactionID}] = true
+			continue
+		}
+		used[suggestion.FromTransactionID] = true
+		used[suggestion.ToTransactionID] = true
+	}
+	learned, err := s.pairingRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pairings := make(map[[4]string]*domain.TransferPairing)
+	for _, pairing := range learned {
+		pairings[[4]string{pairing.FromAccountID, pairing.FromPayee, pairing.ToAccountID, pairing.ToPayee}] = pairing
+	}
+	accounts, err := s.transactions.accountRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	onBudget := make(map[string]bool)
+	for _, account := range accounts {
+		onBudget[account.ID] = !account.Type.IsOffBudget()
+	}
+
+	scan := &TransferScan{Start: start, End: end, Matching: matching, Suggestions: []*TransferSuggestionDetail{}}
+	var outflows, inflows []*domain.Transaction
+	for _, transaction := range transactions {
+		if transaction.Type != domain.TransactionTypeNormal || transaction.Amount == 0 || used[transaction.ID] || !onBudget[transaction.AccountID] {
+			continue
+		}
+		if transaction.Date.Before(windowStart) || transaction.Date.After(windowEnd) {
+			continue
+		}
+		scan.Transactions++
+		if transaction.Amount > 0 {
+			inflows = append(inflows, transaction)
+		} else if !transaction.Date.Before(start) && !transaction.Date.After(end) {
+			outflows = append(outflows, transaction)
+		}
+	}
+
+	hints := make(map[string][]*domain.TransferHint)
+	hinted := func(transaction *domain.Transaction, targetAccountID string) (bool, error) {
+		accountHints, ok := hints[transaction.AccountID]
+		if !ok {
+			var err error
+			if accountHints, err = s.hintRepo.ListByAccount(ctx, transaction.AccountID); err != nil {
+				return false, fmt.Errorf("failed to list transfer hints: %w", err)
+			}
+			hints[transaction.AccountID] = accountHints
+		}
+		description := strings.ToLower(transaction.Description)
+		for _, hint := range accountHints {
+			if hint.TargetAccountID == targetAccountID && strings.Contains(description, strings.ToLower(hint.Pattern)) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	type pair struct {
+		from, to *domain.Transaction
+		score    int
+	}
+	var pairs []pair
+	for _, from := range outflows {
+		for _, to := range inflows {
+			if to.AccountID == from.AccountID || rejected[[2]string{from.ID, to.ID}] {
+				continue
+			}
+			pairing := pairings[[4]string{from.AccountID, transferPayee(from), to.AccountID, transferPayee(to)}]
+			if pairing != nil && pairing.Rejected > pairing.Accepted {
+				continue
+			}
+			score, ok := matching.score(from.Date, -from.Amount, to)
+			if !ok {
+				continue
+			}
+			fromHinted, err := hinted(from, to.AccountID)
+			if err != nil {
+				return nil, err
+			}
+			toHinted, err := hinted(to, from.AccountID)
+			if err != nil {
+				return nil, err
+			}
+			if fromHinted || toHinted {
+				score += matching.hintBoost()
+			}
+			if pairing != nil && pairing.Accepted > pairing.Rejected {
+				score += matching.learnedBoost()
+			}
+			if score >= transferSuggestionThreshold {
+				pairs = append(pairs, pair{from, to, score})
+			}
+		}
+	}
+
+	// Best pairs first, so each transaction goes to the pair it's likeliest to belong to
+	sort.SliceStable(pairs, func(i, j int) bool {
+		if pairs[i].score != pairs[j].score {
+			return pairs[i].score > pairs[j].score
+		}
+		if !pairs[i].from.Date.Equal(pairs[j].from.Date) {
+			return pairs[i].from.Date.Before(pairs[j].from.Date)
+		}
+		if pairs[i].from.ID != pairs[j].from.ID {
+			return pairs[i].from.ID < pairs[j].from.ID
+		}
+		return pairs[i].to.ID < pairs[j].to.ID
+	})
+	for _, pair := range pairs {
+		if used[pair.from.ID] || used[pair.to.ID] {
+			continue
+		}
+		now := time.Now()
+		suggestion := &domain.TransferSuggestion{
+			ID:                uuid.New().String(),
+			FromTransactionID: pair.from.ID,
+			ToTransactionID:   pair.to.ID,
+			Score:             pair.score,
+			Status:            domain.TransferSuggestionPending,
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		}
+		if err := s.suggestionRepo.Create(ctx, suggestion); err != nil {
+			return nil, err
+		}
+		used[pair.from.ID], used[pair.to.ID] = true, true
+		scan.Suggestions = append(scan.Suggestions, &TransferSuggestionDetail{TransferSuggestion: suggestion, From: pair.from, To: pair.to})
+	}
+	return scan, nil
+}
+
+// ScanRecent scans the last scanDays days for transfers; the scheduler runs it periodically
+func (s *TransferSuggestionService) ScanRecent(ctx context.Context) error {
+	_, err := s.Scan(ctx, time.Time{}, time.Time{}, s.matching)
+	return err
+}
+
+// QueueScan scans the days around date for transfers shortly, after a transaction dated
+// then was entered or changed by hand
+// Only outflows within the matching window of date are scanned, not every recent
+// transaction. Calls made before the scan runs widen its range rather than adding scans.
+func (s *TransferSuggestionService) QueueScan(date time.Time) {
+	start, end := date.AddDate(0, 0, -s.matching.Days), date.AddDate(0, 0, s.matching.Days)
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+	if s.queueTimer == nil {
+		s.queuedStart, s.queuedEnd = start, end
+		s.queueTimer = time.AfterFunc(s.scanDelay, s.runQueuedScan)
+		return
+	}
+
+	if start.Before(s.queuedStart) {
+		s.queuedStart = start
+	}
+	if end.After(s.queuedEnd) {
+		s.queuedEnd = end
+	}
+	// A timer that has already fired picks the wider range up when it runs
+	if s.queueTimer.Stop() {
+		s.queueTimer.Reset(s.scanDelay)
+	}
+}
+
+// runQueuedScan runs the scan QueueScan queued
+func (s *TransferSuggestionService) runQueuedScan() {
+	s.queueMu.Lock()
+	start, end := s.queuedStart, s.queuedEnd
+	s.queueTimer = nil
+	s.queueMu.Unlock()
+
+	if _, err := s.Scan(context.Background(), start, end, s.matching); err != nil {
+		log.Printf("transfer scan: %v", err)
+	}
+}
+
+// ListSuggestions retrieves suggestions with a status, or all of them for an empty status, best first
+func (s *TransferSuggestionService) ListSuggestions(ctx context.Context, status domain.TransferSuggestionStatus) ([]*TransferSuggestionDetail, error) {
+	suggestions, err := s.suggestionRepo.List(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+	details := make([]*TransferSuggestionDetail, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		detail, err := s.detail(ctx, suggestion)
+		if err != nil {
+			return nil, err
+		}
+		details = append(details, detail)
+	}
+	return details, nil
+}
+
+// AcceptSuggestion links a suggestion's transactions as the two sides of a transfer
+// Both drop their category, as the money never left the budget; paying a credit card comes
+// out of its payment category, as CreateTransfer would. When the inflow is less than the
+// outflow, the difference is split off the outflow as a fee to categorize. Balances are
+// already right. A suggestion whose transactions have changed since the scan is removed
+// instead.
+func (s *TransferSuggestionService) AcceptSuggestion(ctx context.Context, id string) (*TransferSuggestionDetail, error) {
+	suggestion, err := s.pending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	detail, err := s.detail(ctx, suggestion)
+	if err != nil {
+		return nil, err
+	}
+	from, to := detail.From, detail.To
+	if from.Type != domain.TransactionTypeNormal || to.Type != domain.TransactionTypeNormal ||
+		from.UpdatedAt.After(suggestion.CreatedAt) || to.UpdatedAt.After(suggestion.CreatedAt) {
+		if err := s.suggestionRepo.Delete(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrTransferSuggestionStale
+	}
+
+	err = s.transactions.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		var paymentCategoryID *string
+		if s.transactions.creditCards != nil {
+			for _, transaction := range []*domain.Transaction{from, to} {
+				if err := s.transactions.creditCards.ReverseSpending(ctx, transaction.ID); err != nil {
+					return fmt.Errorf("failed to reverse card spending: %w", err)
+				}
+			}
+			if paymentCategoryID, err = s.transactions.creditCards.PaymentCategoryFor(ctx, to.AccountID, to.Amount, from.Date); err != nil {
+				return err
+			}
+		}
+		if err := s.learn(ctx, from, to, true); err != nil {
+			return err
+		}
+		if detail.Fee, err = splitTransferDifference(ctx, s.transactions.transactionRepo, from, to.Amount); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		from.Type, from.TransferToAccountID, from.CategoryID, from.UpdatedAt = domain.TransactionTypeTransfer, &to.AccountID, paymentCategoryID, now
+		to.Type, to.TransferToAccountID, to.CategoryID, to.UpdatedAt = domain.TransactionTypeTransfer, &from.AccountID, nil, now
+		for _, transaction := range []*domain.Transaction{from, to} {
+			if err := s.transactions.transactionRepo.Update(ctx, transaction); err != nil {
+				return fmt.Errorf("failed to link transfer: %w", err)
+			}
+		}
+		suggestion.Status, suggestion.UpdatedAt = domain.TransferSuggestionAccepted, now
+		return s.suggestionRepo.Update(ctx, suggestion)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// RejectSuggestion dismisses a suggestion, leaving its transactions as they are
+// The rejection counts against pairing the two payees again.
+func (s *TransferSuggestionService) RejectSuggestion(ctx context.Context, id string) (*TransferSuggestionDetail, error) {
+	suggestion, err := s.pending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	detail, err := s.detail(ctx, suggestion)
+	if err != nil {
+		return nil, err
+	}
+	err = s.transactions.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.learn(ctx, detail.From, detail.To, false); err != nil {
+			return err
+		}
+		suggestion.Status, suggestion.UpdatedAt = domain.TransferSuggestionRejected, time.Now()
+		return s.suggestionRepo.Update(ctx, suggestion)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// GetSuggestion retrieves a suggestion with its transactions
+func (s *TransferSuggestionService) GetSuggestion(ctx context.Context, id string) (*TransferSuggestionDetail, error) {
+	suggestion, err := s.suggestionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.detail(ctx, suggestion)
+}
+
+// ListPairings retrieves what reviewing suggestions has taught scans, most recent first
+func (s *TransferSuggestionService) ListPairings(ctx context.Context) ([]*domain.TransferPairing, error) {
+	return s.pairingRepo.List(ctx)
+}
+
+// ForgetPairing removes a learned pairing, so its payees are scored like any others again
+func (s *TransferSuggestionService) ForgetPairing(ctx context.Context, id string) error {
+	return s.pairingRepo.Delete(ctx, id)
+}
+
+// learn counts a review of a suggestion between from and to's payees
+// Transactions without a payee name teach nothing.
+func (s *TransferSuggestionService) learn(ctx context.Context, from, to *domain.Transaction, accepted bool) error {
+	fromPayee, toPayee := transferPayee(from), transferPayee(to)
+	if fromPayee == "" || toPayee == "" {
+		return nil
+	}
+	now := time.Now()
+	pairing := &domain.TransferPairing{
+		ID:            uuid.New().String(),
+		FromAccountID: from.AccountID,
+		FromPayee:     fromPayee,
+		ToAccountID:   to.AccountID,
+		ToPayee:       toPayee,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if accepted {
+		pairing.Accepted = 1
+	} else {
+		pairing.Rejected = 1
+	}
+	return s.pairingRepo.Record(ctx, pairing)
+}
+
+// transferPayee is who one side of a transfer was paid to or from, as pairings remember it
+// Reference numbers and card-processor noise are dropped, so the next month's transfer
+// between the same two payees has the same name.
+func transferPayee(transaction *domain.Transaction) string {
+	return strings.ToLower(normalizePayeeName(transaction.Description))
+}
+
+// pending gets a suggestion that is still waiting for review
+func (s *TransferSuggestionService) pending(ctx context.Context, id string) (*domain.TransferSuggestion, error) {
+	suggestion, err := s.suggestionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if suggestion.Status != domain.TransferSuggestionPending {
+		return nil, fmt.Errorf("transfer suggestion is already %s", suggestion.Status)
+	}
+	return suggestion, nil
+}
+
+// detail looks up a suggestion's transactions
+func (s *TransferSuggestionService) detail(ctx context.Context, suggestion *domain.TransferSuggestion) (*TransferSuggestionDetail, error) {
+	from, err := s.transactions.transactionRepo.GetByID(ctx, suggestion.FromTransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	to, err := s.transactions.transactionRepo.GetByID(ctx, suggestion.ToTransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	return &TransferSuggestionDetail{TransferSuggestion: suggestion, From: from, To: to}, nil
+}
@@ -0,0 +1,336 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type mockTransferSuggestionRepository struct {
+	suggestions []*domain.TransferSuggestion
+}
+
+func (m *mockTransferSuggestionRepository) Create(ctx context.Context, suggestion *domain.TransferSuggestion) error {
+	m.suggestions = append(m.suggestions, suggestion)
+	return nil
+}
+
+func (m *mockTransferSuggestionRepository) GetByID(ctx context.Context, id string) (*domain.TransferSuggestion, error) {
+	for _, suggestion := range m.suggestions {
+		if suggestion.ID == id {
+			return suggestion, nil
+		}
+	}
+	return nil, domain.ErrTransferSuggestionNotFound
+}
+
+func (m *mockTransferSuggestionRepository) List(ctx context.Context, status domain.TransferSuggestionStatus) ([]*domain.TransferSuggestion, error) {
+	var result []*domain.TransferSuggestion
+	for _, suggestion := range m.suggestions {
+		if status == "" || suggestion.Status == status {
+			result = append(result, suggestion)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Score > result[j].Score })
+	return result, nil
+}
+
+func (m *mockTransferSuggestionRepository) Update(ctx context.Context, suggestion *domain.TransferSuggestion) error {
+	return nil
+}
+
+func (m *mockTransferSuggestionRepository) Delete(ctx context.Context, id string) error {
+	for i, suggestion := range m.suggestions {
+		if suggestion.ID == id {
+			m.suggestions = append(m.suggestions[:i], m.suggestions[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrTransferSuggestionNotFound
+}
+
+type mockTransferPairingRepository struct {
+	pairings []*domain.TransferPairing
+}
+
+func (m *mockTransferPairingRepository) Record(ctx context.Context, pairing *domain.TransferPairing) error {
+	for _, existing := range m.pairings {
+		if existing.FromAccountID == pairing.FromAccountID && existing.FromPayee == pairing.FromPayee &&
+			existing.ToAccountID == pairing.ToAccountID && existing.ToPayee == pairing.ToPayee {
+			existing.Accepted += pairing.Accepted
+			existing.Rejected += pairing.Rejected
+			existing.UpdatedAt = pairing.UpdatedAt
+			return nil
+		}
+	}
+	m.pairings = append(m.pairings, pairing)
+	return nil
+}
+
+func (m *mockTransferPairingRepository) GetByID(ctx context.Context, id string) (*domain.TransferPairing, error) {
+	for _, pairing := range m.pairings {
+		if pairing.ID == id {
+			return pairing, nil
+		}
+	}
+	return nil, domain.ErrTransferPairingNotFound
+}
+
+func (m *mockTransferPairingRepository) List(ctx context.Context) ([]*domain.TransferPairing, error) {
+	return m.pairings, nil
+}
+
+func (m *mockTransferPairingRepository) Delete(ctx context.Context, id string) error {
+	for i, pairing := range m.pairings {
+		if pairing.ID == id {
+			m.pairings = append(m.pairings[:i], m.pairings[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrTransferPairingNotFound
+}
+
+func TestTransferSuggestionService_Scan(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking}
+	accountRepo.accounts["savings"] = &domain.Account{ID: "savings", Name: "Savings", Type: domain.AccountTypeSavings}
+	accountRepo.accounts["brokerage"] = &domain.Account{ID: "brokerage", Name: "Brokerage", Type: domain.AccountTypeTracking}
+	transactionRepo := newMockTransactionRepository()
+	hintRepo := &mockTransferHintRepository{}
+	suggestionRepo := &mockTransferSuggestionRepository{}
+	transactions := NewTransactionService(transactionRepo, accountRepo, newMockCategoryRepository(), newMockBudgetStateRepository(0, 0),
+		&mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{})
+	service := NewTransferSuggestionService(suggestionRepo, &mockTransferPairingRepository{}, hintRepo, transactions, DefaultTransferMatching, 30)
+
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC) }
+	savingsCategory := "savings-goal"
+	add := func(id, accountID string, amount int64, description string, date time.Time) *domain.Transaction {
+		transaction := &domain.Transaction{ID: id, Type: domain.TransactionTypeNormal, AccountID: accountID, Amount: amount, Description: description, Date: date}
+		transactionRepo.transactions = append(transactionRepo.transactions, transaction)
+		return transaction
+	}
+	out := add("out", "checking", -50000, "To savings", day(10))
+	out.CategoryID = &savingsCategory
+	add("in", "savings", 50000, "From checking", day(11))
+	add("same-account", "checking", 50000, "Refund", day(10))
+	add("too-far", "savings", 20000, "Interest", day(20))
+	add("rent", "checking", -20000, "Rent", day(10))
+	add("hinted-out", "checking", -7500, "ONLINE TRANSFER TO SAV", day(12))
+	add("hinted-in", "savings", 7500, "Deposit", day(16))
+	add("unhinted-out", "checking", -3000, "Coffee", day(12))
+	add("unhinted-in", "savings", 3000, "Deposit", day(16))
+	add("off-budget", "brokerage", 20000, "Dividend", day(10))
+	hintRepo.hints = append(hintRepo.hints, &domain.TransferHint{ID: "hint", AccountID: "checking", Pattern: "transfer to sav", TargetAccountID: "savings"})
+
+	if _, err := service.Scan(ctx, day(30), day(1), DefaultTransferMatching); err == nil {
+		t.Error("expected a range ending before it starts to fail")
+	}
+	scan, err := service.Scan(ctx, day(1), day(30), DefaultTransferMatching)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Four days apart only matches with a hint; the hinted pair scores highest
+	if len(scan.Suggestions) != 2 || scan.Suggestions[0].FromTransactionID != "hinted-out" || scan.Suggestions[0].ToTransactionID != "hinted-in" ||
+		scan.Suggestions[1].FromTransactionID != "out" || scan.Suggestions[1].ToTransactionID != "in" || scan.Suggestions[1].Score != DefaultTransferMatching.Days-1 {
+		t.Fatalf("unexpected suggestions: %+v", suggestionRepo.suggestions)
+	}
+	if scan, err := service.Scan(ctx, day(1), day(30), DefaultTransferMatching); err != nil || len(scan.Suggestions) != 0 {
+		t.Fatalf("expected scanning again to add nothing, got %+v, %v", scan, err)
+	}
+
+	// Accepting links the pair; the outflow's category goes, as the money stayed in the budget
+	accepted, err := service.AcceptSuggestion(ctx, scan.Suggestions[1].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accepted.Status != domain.TransferSuggestionAccepted || accepted.From.Type != domain.TransactionTypeTransfer || accepted.From.CategoryID != nil ||
+		*accepted.From.TransferToAccountID != "savings" || *accepted.To.TransferToAccountID != "checking" {
+		t.Errorf("expected the pair linked as a transfer, got %+v %+v", accepted.From, accepted.To)
+	}
+	if _, err := service.RejectSuggestion(ctx, accepted.ID); err == nil {
+		t.Error("expected rejecting an accepted suggestion to fail")
+	}
+
+	// A rejected pair isn't suggested again
+	if _, err := service.RejectSuggestion(ctx, scan.Suggestions[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if scan, err := service.Scan(ctx, day(1), day(30), DefaultTransferMatching); err != nil || len(scan.Suggestions) != 0 {
+		t.Errorf("expected the rejected pair to stay rejected, got %+v, %v", scan, err)
+	}
+
+	// A pair edited since the scan is dropped rather than linked
+	add("late-out", "checking", -1000, "Move", day(25))
+	late := add("late-in", "savings", 1000, "Move", day(25))
+	scan, err = service.Scan(ctx, day(20), day(30), DefaultTransferMatching)
+	if err != nil || len(scan.Suggestions) != 1 {
+		t.Fatalf("expected the new pair suggested, got %+v, %v", scan, err)
+	}
+	late.Amount, late.UpdatedAt = 1200, time.Now().Add(time.Second)
+	if _, err := service.AcceptSuggestion(ctx, scan.Suggestions[0].ID); !errors.Is(err, domain.ErrTransferSuggestionStale) {
+		t.Errorf("expected %v, got %v", domain.ErrTransferSuggestionStale, err)
+	}
+	if _, err := service.GetSuggestion(ctx, scan.Suggestions[0].ID); !errors.Is(err, domain.ErrTransferSuggestionNotFound) {
+		t.Errorf("expected the stale suggestion removed, got %v", err)
+	}
+
+	// A wire that arrived less a fee only matches within a tolerance, and scores lower
+	add("wire-out", "checking", -100000, "Wire", day(5))
+	add("wire-in", "savings", 99000, "Incoming wire", day(6))
+	if scan, err := service.Scan(ctx, day(1), day(9), DefaultTransferMatching); err != nil || len(scan.Suggestions) != 0 {
+		t.Fatalf("expected amounts that differ not to match exactly, got %+v, %v", scan, err)
+	}
+	if _, err := service.Scan(ctx, day(1), day(9), TransferMatching{Days: 4, FeeTolerance: -1}); err == nil {
+		t.Error("expected a negative tolerance to fail")
+	}
+	scan, err = service.Scan(ctx, day(1), day(9), TransferMatching{Days: 4, ToleranceBps: 100})
+	if err != nil || len(scan.Suggestions) != 1 || scan.Suggestions[0].Score != 1 {
+		t.Fatalf("expected the wire suggested a day apart and a whole tolerance off, got %+v, %v", scan, err)
+	}
+	accepted, err = service.AcceptSuggestion(ctx, scan.Suggestions[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accepted.From.Amount != -99000 || accepted.Fee == nil || accepted.Fee.Amount != -1000 || accepted.Fee.AccountID != "checking" ||
+		accepted.Fee.Type != domain.TransactionTypeNormal {
+		t.Errorf("expected the fee split off the outflow, got %+v, fee %+v", accepted.From, accepted.Fee)
+	}
+}
+
+func TestTransferSuggestionService_QueueScan(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking}
+	accountRepo.accounts["savings"] = &domain.Account{ID: "savings", Name: "Savings", Type: domain.AccountTypeSavings}
+	transactionRepo := newMockTransactionRepository()
+	categoryRepo := newMockCategoryRepository()
+	suggestionRepo := &mockTransferSuggestionRepository{}
+	transactions := NewTransactionService(transactionRepo, accountRepo, categoryRepo, newMockBudgetStateRepository(0, 0),
+		&mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{})
+	service := NewTransferSuggestionService(suggestionRepo, &mockTransferPairingRepository{}, &mockTransferHintRepository{}, transactions, DefaultTransferMatching, 30)
+	// Long enough that the scan only runs when the test runs it
+	service.scanDelay = time.Hour
+	transactions.UseTransferSuggestions(service)
+
+	categoryRepo.categories["savings-goal"] = &domain.Category{ID: "savings-goal", Name: "Savings goal"}
+	savingsCategory := "savings-goal"
+
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC) }
+	out, err := transactions.CreateTransaction(ctx, "checking", &savingsCategory, -50000, "To savings", day(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	in, err := transactions.CreateTransaction(ctx, "savings", nil, 40000, "From checking", day(14))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := transactions.UpdateTransaction(ctx, in.ID, "savings", nil, 50000, "From checking", day(11)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The three edits share one scan, covering the days around each of them
+	service.queueMu.Lock()
+	timer, start, end := service.queueTimer, service.queuedStart, service.queuedEnd
+	service.queueMu.Unlock()
+	days := DefaultTransferMatching.Days
+	if timer == nil || !start.Equal(day(10).AddDate(0, 0, -days)) || !end.Equal(day(14).AddDate(0, 0, days)) {
+		t.Fatalf("expected one scan queued from %v to %v, got %v to %v", day(10).AddDate(0, 0, -days), day(14).AddDate(0, 0, days), start, end)
+	}
+	if len(suggestionRepo.suggestions) != 0 {
+		t.Fatalf("expected nothing suggested before the scan runs, got %+v", suggestionRepo.suggestions)
+	}
+	if !timer.Stop() {
+		t.Fatal("expected the queued scan still waiting")
+	}
+	service.runQueuedScan()
+	if len(suggestionRepo.suggestions) != 1 || suggestionRepo.suggestions[0].FromTransactionID != out.ID || suggestionRepo.suggestions[0].ToTransactionID != in.ID {
+		t.Fatalf("expected the hand-entered pair suggested, got %+v", suggestionRepo.suggestions)
+	}
+
+	// Transactions entered without suggestions in use queue nothing
+	transactions.UseTransferSuggestions(nil)
+	if _, err := transactions.CreateTransaction(ctx, "checking", &savingsCategory, -1000, "Coffee", day(12)); err != nil {
+		t.Fatal(err)
+	}
+	service.queueMu.Lock()
+	defer service.queueMu.Unlock()
+	if service.queueTimer != nil {
+		t.Error("expected no scan queued")
+	}
+}
+
+func TestTransferSuggestionService_LearnsPairings(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.accounts["checking"] = &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking}
+	accountRepo.accounts["savings"] = &domain.Account{ID: "savings", Name: "Savings", Type: domain.AccountTypeSavings}
+	transactionRepo := newMockTransactionRepository()
+	pairingRepo := &mockTransferPairingRepository{}
+	transactions := NewTransactionService(transactionRepo, accountRepo, newMockCategoryRepository(), newMockBudgetStateRepository(0, 0),
+		&mockUnitOfWork{accountRepo, transactionRepo}, DateBounds{})
+	service := NewTransferSuggestionService(&mockTransferSuggestionRepository{}, pairingRepo, &mockTransferHintRepository{}, transactions, DefaultTransferMatching, 30)
+
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC) }
+	add := func(id, accountID string, amount int64, description string, date time.Time) {
+		transactionRepo.transactions = append(transactionRepo.transactions,
+			&domain.Transaction{ID: id, Type: domain.TransactionTypeNormal, AccountID: accountID, Amount: amount, Description: description, Date: date})
+	}
+
+	// Rent paid from checking the day the landlord's deposit refund lands in savings
+	add("rent-1", "checking", -120000, "ACH DEBIT ACME PROPERTIES 000123", day(1))
+	add("refund-1", "savings", 120000, "DEPOSIT ACME REFUNDS 000456", day(1))
+	// A transfer between the same two accounts, three days apart
+	add("move-1", "checking", -5000, "Online Transfer To Savings 1111", day(10))
+	add("in-1", "savings", 5000, "Online Transfer From Checking 2222", day(13))
+	scan, err := service.Scan(ctx, day(1), day(15), DefaultTransferMatching)
+	if err != nil || len(scan.Suggestions) != 2 {
+		t.Fatalf("expected both pairs suggested, got %+v, %v", scan, err)
+	}
+	for _, suggestion := range scan.Suggestions {
+		if suggestion.FromTransactionID == "rent-1" {
+			if _, err := service.RejectSuggestion(ctx, suggestion.ID); err != nil {
+				t.Fatal(err)
+			}
+		} else if _, err := service.AcceptSuggestion(ctx, suggestion.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pairings, err := service.ListPairings(ctx)
+	if err != nil || len(pairings) != 2 {
+		t.Fatalf("expected both reviews remembered, got %+v, %v", pairings, err)
+	}
+
+	// Next month's transactions have new IDs and reference numbers, but the same payees
+	add("rent-2", "checking", -120000, "ACH DEBIT ACME PROPERTIES 000789", day(20))
+	add("refund-2", "savings", 120000, "DEPOSIT ACME REFUNDS 000987", day(20))
+	add("move-2", "checking", -5000, "Online Transfer To Savings 3333", day(20))
+	add("in-2", "savings", 5000, "Online Transfer From Checking 4444", day(24))
+	scan, err = service.Scan(ctx, day(16), day(30), DefaultTransferMatching)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Four days apart only reaches the threshold with the boost from the accepted pairing
+	if len(scan.Suggestions) != 1 || scan.Suggestions[0].FromTransactionID != "move-2" || scan.Suggestions[0].Score != DefaultTransferMatching.learnedBoost() {
+		t.Fatalf("expected only the accepted payees suggested again, got %+v", scan.Suggestions)
+	}
+
+	// Forgetting the rejection lets the payees be suggested again
+	for _, pairing := range pairings {
+		if pairing.FromPayee == "acme properties" {
+			if err := service.ForgetPairing(ctx, pairing.ID); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := service.ForgetPairing(ctx, "missing"); !errors.Is(err, domain.ErrTransferPairingNotFound) {
+		t.Errorf("expected %v, got %v", domain.ErrTransferPairingNotFound, err)
+	}
+	if scan, err := service.Scan(ctx, day(16), day(30), DefaultTransferMatching); err != nil || len(scan.Suggestions) != 1 || scan.Suggestions[0].FromTransactionID != "rent-2" {
+		t.Errorf("expected the forgotten payees suggested, got %+v, %v", scan, err)
+	}
+}
@@ -0,0 +1,156 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// maxTrendMonths caps the span of a single trend report (10 years)
+const maxTrendMonths = 120
+
+// TrendMonth is one month of income and spending in a trend report
+type TrendMonth struct {
+	Period      string `json:"period"`
+	Income      int64  `json:"income"`       // Non-transfer inflows (cents)
+	Spending    int64  `json:"spending"`     // Categorized outflows, as a positive number (cents)
+	Net         int64  `json:"net"`          // Income - Spending (cents)
+	DebtPaydown int64  `json:"debt_paydown"` // Principal transferred to loans (cents); loan interest is spending
+}
+
+// TrendReport shows monthly income and spending across a range of periods
+// When AdjustedForInflation is set, amounts are restated in ReferencePeriod dollars
+type TrendReport struct {
+	StartPeriod          string        `json:"start_period"`
+	EndPeriod            string        `json:"end_period"`
+	AdjustedForInflation bool          `json:"adjusted_for_inflation"`
+	ReferencePeriod      string        `json:"reference_period,omitempty"` // CPI period amounts are expressed in
+	MissingCPI           []string      `json:"missing_cpi,omitempty"`      // Months with no CPI at or before them (left unadjusted)
+	Months               []*TrendMonth `json:"months"`
+}
+
+// GetTrendReport builds a month-by-month income/spending report for [startPeriod, endPeriod]
+// When adjustForInflation is true, each month is scaled by CPI(reference) / CPI(month),
+// using the latest CPI value at or before each month.
+func (s *ReportService) GetTrendReport(ctx context.Context, startPeriod, endPeriod string, adjustForInflation bool) (*TrendReport, error) {
+	start, err := time.Parse("2006-01", startPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start period format, expected YYYY-MM")
+	}
+	end, err := time.Parse("2006-01", endPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end period format, expected YYYY-MM")
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("end period must not be before start period")
+	}
+
+	monthCount := (end.Year()-start.Year())*12 + int(end.Month()-start.Month()) + 1
+	if monthCount > maxTrendMonths {
+		return nil, fmt.Errorf("trend reports are limited to %d months", maxTrendMonths)
+	}
+
+	report := &TrendReport{
+		StartPeriod:          startPeriod,
+		EndPeriod:            endPeriod,
+		AdjustedForInflation: adjustForInflation,
+		Months:               make([]*TrendMonth, 0, monthCount),
+	}
+	byPeriod := make(map[string]*TrendMonth)
+	for i := 0; i < monthCount; i++ {
+		month := &TrendMonth{Period: start.AddDate(0, i, 0).Format("2006-01")}
+		report.Months = append(report.Months, month)
+		byPeriod[month.Period] = month
+	}
+
+	rangeEnd := end.AddDate(0, 1, 0).Add(-time.Second)
+	transactions, err := s.transactionRepo.ListByPeriod(ctx, start.Format(time.RFC3339), rangeEnd.Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+	accounts, err := s.accountRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	loans := make(map[string]bool)
+	for _, account := range accounts {
+		if account.Type == domain.AccountTypeLoan {
+			loans[account.ID] = true
+		}
+	}
+
+	for _, txn := range transactions {
+		if txn.Type == domain.TransactionTypeTransfer && txn.Amount > 0 && loans[txn.AccountID] {
+			if month, ok := byPeriod[txn.Date.Format("2006-01")]; ok {
+				month.DebtPaydown += txn.Amount
+			}
+		}
+		// A starting balance is money the account already had, not income
+		if txn.Type == domain.TransactionTypeTransfer || txn.Type == domain.TransactionTypeAdjustment ||
+			txn.Type == domain.TransactionTypeStartingBalance {
+			continue
+		}
+		month, ok := byPeriod[txn.Date.Format("2006-01")]
+		if !ok {
+			continue
+		}
+		if txn.Amount > 0 {
+			month.Income += txn.Amount
+		} else if txn.CategoryID != nil && *txn.CategoryID != "" {
+			month.Spending += -txn.Amount
+		}
+	}
+
+	if adjustForInflation {
+		if err := s.adjustTrendForInflation(ctx, report); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, month := range report.Months {
+		month.Net = month.Income - month.Spending
+	}
+
+	return report, nil
+}
+
+// adjustTrendForInflation restates the report's amounts in end-period dollars
+func (s *ReportService) adjustTrendForInflation(ctx context.Context, report *TrendReport) error {
+	entries, err := s.cpiRepo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list CPI entries: %w", err)
+	}
+
+	// Entries are ordered by period; find the latest value at or before a period
+	lookup := func(period string) (string, float64, bool) {
+		i := sort.Search(len(entries), func(i int) bool { return entries[i].Period > period })
+		if i == 0 {
+			return "", 0, false
+		}
+		return entries[i-1].Period, entries[i-1].Value, true
+	}
+
+	refPeriod, refValue, ok := lookup(report.EndPeriod)
+	if !ok {
+		return fmt.Errorf("no CPI data available at or before %s", report.EndPeriod)
+	}
+	report.ReferencePeriod = refPeriod
+
+	for _, month := range report.Months {
+		_, value, ok := lookup(month.Period)
+		if !ok {
+			report.MissingCPI = append(report.MissingCPI, month.Period)
+			continue
+		}
+		factor := refValue / value
+		month.Income = int64(math.Round(float64(month.Income) * factor))
+		month.Spending = int64(math.Round(float64(month.Spending) * factor))
+		month.DebtPaydown = int64(math.Round(float64(month.DebtPaydown) * factor))
+	}
+
+	return nil
+}
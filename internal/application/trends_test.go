@@ -0,0 +1,93 @@
+package application
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestReportService_GetTrendReport(t *testing.T) {
+	ctx := context.Background()
+	groceries := "groceries"
+
+	transactionRepo := &datedTransactionRepository{newMockTransactionRepository()}
+	add := func(accountID, date string, amount int64, txnType domain.TransactionType, categoryID *string) {
+		when, _ := time.Parse("2006-01-02", date)
+		transactionRepo.transactions = append(transactionRepo.transactions, &domain.Transaction{
+			ID: accountID + date + string(txnType), AccountID: accountID, CategoryID: categoryID, Amount: amount, Date: when, Type: txnType,
+		})
+	}
+	add("checking", "2024-11-20", -99000, domain.TransactionTypeNormal, &groceries) // Before the range
+	add("checking", "2024-12-05", -40000, domain.TransactionTypeNormal, &groceries)
+	add("checking", "2025-01-01", 500000, domain.TransactionTypeStartingBalance, nil)
+	add("checking", "2025-01-02", 300000, domain.TransactionTypeNormal, nil)
+	add("checking", "2025-01-10", -100000, domain.TransactionTypeNormal, &groceries)
+	add("checking", "2025-01-12", -2000, domain.TransactionTypeAdjustment, &groceries)
+	add("checking", "2025-01-15", -50000, domain.TransactionTypeTransfer, nil)
+	add("mortgage", "2025-01-15", 50000, domain.TransactionTypeTransfer, nil)
+	add("checking", "2025-02-02", 300000, domain.TransactionTypeNormal, nil)
+	add("checking", "2025-02-10", -120000, domain.TransactionTypeNormal, &groceries)
+	add("checking", "2025-03-02", 330000, domain.TransactionTypeNormal, nil)
+	add("checking", "2025-03-10", -110000, domain.TransactionTypeNormal, &groceries)
+	add("checking", "2025-03-11", -7000, domain.TransactionTypeNormal, nil) // Uncategorized isn't spending
+
+	accountRepo := newMockAccountRepository(0)
+	accountRepo.Create(ctx, &domain.Account{ID: "checking", Name: "Checking", Type: domain.AccountTypeChecking})
+	accountRepo.Create(ctx, &domain.Account{ID: "mortgage", Name: "Mortgage", Type: domain.AccountTypeLoan})
+
+	// No CPI for December, and February falls back to January's
+	cpiRepo := newMockCPIRepository(map[string]float64{"2025-01": 100, "2025-03": 110})
+	service := NewReportService(newMockCategoryRepository(), newMockAllocationRepository(), transactionRepo, accountRepo, nil, cpiRepo, nil)
+
+	report, err := service.GetTrendReport(ctx, "2024-12", "2025-03", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []TrendMonth{
+		{Period: "2024-12", Spending: 40000, Net: -40000},
+		{Period: "2025-01", Income: 300000, Spending: 100000, Net: 200000, DebtPaydown: 50000},
+		{Period: "2025-02", Income: 300000, Spending: 120000, Net: 180000},
+		{Period: "2025-03", Income: 330000, Spending: 110000, Net: 220000},
+	}
+	assertTrendMonths(t, report, expected)
+
+	report, err = service.GetTrendReport(ctx, "2024-12", "2025-03", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.ReferencePeriod != "2025-03" || !reflect.DeepEqual(report.MissingCPI, []string{"2024-12"}) {
+		t.Errorf("expected March dollars with December missing CPI, got %q and %v", report.ReferencePeriod, report.MissingCPI)
+	}
+	expected = []TrendMonth{
+		{Period: "2024-12", Spending: 40000, Net: -40000}, // Left unadjusted
+		{Period: "2025-01", Income: 330000, Spending: 110000, Net: 220000, DebtPaydown: 55000},
+		{Period: "2025-02", Income: 330000, Spending: 132000, Net: 198000},
+		{Period: "2025-03", Income: 330000, Spending: 110000, Net: 220000},
+	}
+	assertTrendMonths(t, report, expected)
+
+	if _, err := service.GetTrendReport(ctx, "2025-03", "2025-01", false); err == nil {
+		t.Error("expected a backwards range to be refused")
+	}
+	if _, err := service.GetTrendReport(ctx, "2015-01", "2025-03", false); err == nil {
+		t.Error("expected a range over the month limit to be refused")
+	}
+	if _, err := service.GetTrendReport(ctx, "2024-10", "2024-12", true); err == nil {
+		t.Error("expected adjusting without CPI at or before the end period to fail")
+	}
+}
+
+func assertTrendMonths(t *testing.T, report *TrendReport, expected []TrendMonth) {
+	t.Helper()
+	if len(report.Months) != len(expected) {
+		t.Fatalf("expected %d months, got %d", len(expected), len(report.Months))
+	}
+	for i, month := range report.Months {
+		if *month != expected[i] {
+			t.Errorf("expected %+v, got %+v", expected[i], *month)
+		}
+	}
+}
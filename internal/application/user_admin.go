@@ -0,0 +1,166 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// ErrUserDisabled is returned when a user an admin has disabled tries to sign in
+var ErrUserDisabled = errors.New("this account has been disabled")
+
+// ListUsers lists every user, oldest first
+func (s *AuthService) ListUsers(ctx context.Context) ([]*domain.User, error) {
+	return s.userRepo.List(ctx)
+}
+
+// DisableUser stops a user signing in, ending their sessions and switching off their API tokens
+// actorID is the admin doing it, if known; admins can't disable themselves, and the last
+// enabled admin can't be disabled. Enabling the user again leaves their tokens off.
+func (s *AuthService) DisableUser(ctx context.Context, actorID *string, userID string) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if actorID != nil && *actorID == user.ID {
+		return nil, fmt.Errorf("you can't disable your own account")
+	}
+	if user.IsDisabled() {
+		return user, nil
+	}
+	if user.Role == domain.UserRoleAdmin {
+		admins, err := s.enabledAdmins(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if admins <= 1 {
+			return nil, fmt.Errorf("the last enabled admin can't be disabled")
+		}
+	}
+
+	now := time.Now()
+	user.DisabledAt = &now
+	user.UpdatedAt = now
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	if err := s.sessionRepo.DeleteByUser(ctx, user.ID, ""); err != nil {
+		return nil, err
+	}
+	tokens, err := s.userTokens(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		if token.IsDisabled() {
+			continue
+		}
+		if err := s.tokenRepo.SetDisabled(ctx, token.ID, &now); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+// EnableUser lets a disabled user sign in again
+func (s *AuthService) EnableUser(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsDisabled() {
+		return user, nil
+	}
+
+	user.DisabledAt = nil
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ResetTwoFactor turns off a user's two-factor authentication, e.g. after they lose their
+// authenticator, and ends their sessions
+// A user required to use two-factor sets it up again after signing in with their password.
+func (s *AuthService) ResetTwoFactor(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TOTPEnabled && user.TOTPSecret == "" {
+		return nil, fmt.Errorf("two-factor authentication is not enabled")
+	}
+
+	if err := s.recoveryRepo.DeleteByUser(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	user.TOTPEnabled = false
+	user.TOTPSecret = ""
+	user.TOTPLastStep = 0
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	if err := s.sessionRepo.DeleteByUser(ctx, user.ID, ""); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// TransferOwnership hands the budget over to another user: they become an admin and the
+// admin handing it over, actorID, becomes a member
+// Only a user can hand the budget over, not the configured admin token.
+func (s *AuthService) TransferOwnership(ctx context.Context, actorID *string, userID string) (*domain.User, error) {
+	if actorID == nil {
+		return nil, fmt.Errorf("ownership can only be transferred by a signed-in admin")
+	}
+	actor, err := s.userRepo.GetByID(ctx, *actorID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case actor.Role != domain.UserRoleAdmin:
+		return nil, fmt.Errorf("%s access is required", domain.TokenAccessAdmin)
+	case user.ID == actor.ID:
+		return nil, fmt.Errorf("you already own the budget")
+	case user.IsDisabled():
+		return nil, ErrUserDisabled
+	}
+
+	// Promoted first, so a failure part way leaves two admins rather than none
+	now := time.Now()
+	user.Role = domain.UserRoleAdmin
+	user.UpdatedAt = now
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	actor.Role = domain.UserRoleMember
+	actor.UpdatedAt = now
+	if err := s.userRepo.Update(ctx, actor); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// enabledAdmins counts the admins who can still sign in
+func (s *AuthService) enabledAdmins(ctx context.Context) (int, error) {
+	users, err := s.userRepo.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	admins := 0
+	for _, user := range users {
+		if user.Role == domain.UserRoleAdmin && !user.IsDisabled() {
+			admins++
+		}
+	}
+	return admins, nil
+}
@@ -0,0 +1,98 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type mockRecoveryCodeRepository struct {
+	codes map[string][]*domain.RecoveryCode // By user ID
+}
+
+func (m *mockRecoveryCodeRepository) ReplaceForUser(ctx context.Context, userID string, codes []*domain.RecoveryCode) error {
+	m.codes[userID] = codes
+	return nil
+}
+
+func (m *mockRecoveryCodeRepository) Use(ctx context.Context, userID, codeHash string, usedAt time.Time) error {
+	return errors.New("recovery code not found")
+}
+
+func (m *mockRecoveryCodeRepository) CountUnused(ctx context.Context, userID string) (int, error) {
+	return len(m.codes[userID]), nil
+}
+
+func (m *mockRecoveryCodeRepository) DeleteByUser(ctx context.Context, userID string) error {
+	delete(m.codes, userID)
+	return nil
+}
+
+func TestAuthService_ManageUsers(t *testing.T) {
+	ctx := context.Background()
+	userRepo := &mockUserRepository{}
+	tokenRepo := &mockAPITokenRepository{tokens: map[string]*domain.APIToken{}}
+	recoveryRepo := &mockRecoveryCodeRepository{codes: map[string][]*domain.RecoveryCode{}}
+	service := NewAuthService(userRepo, &mockSessionRepository{}, tokenRepo, recoveryRepo)
+
+	admin, err := service.CreateUser(ctx, "admin@example.com", "Admin", "password123", domain.UserRoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	member, err := service.CreateUser(ctx, "kid@example.com", "Kid", "password123", domain.UserRoleMember)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenRepo.tokens["t1"] = &domain.APIToken{ID: "t1", UserID: &member.ID, Access: domain.TokenAccessWrite}
+
+	// Admins can't lock themselves, or everyone, out
+	if _, err := service.DisableUser(ctx, &admin.ID, admin.ID); err == nil {
+		t.Error("expected an admin disabling themselves to be refused")
+	}
+	if _, err := service.DisableUser(ctx, nil, admin.ID); err == nil {
+		t.Error("expected the last enabled admin to stay enabled")
+	}
+
+	if _, err := service.DisableUser(ctx, &admin.ID, member.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := service.Login(ctx, "kid@example.com", "password123", "", "", ""); !errors.Is(err, ErrUserDisabled) {
+		t.Errorf("expected a disabled user to be refused sign-in, got %v", err)
+	}
+	if !tokenRepo.tokens["t1"].IsDisabled() {
+		t.Error("expected the disabled user's API tokens switched off")
+	}
+
+	if _, err := service.EnableUser(ctx, member.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := service.Login(ctx, "kid@example.com", "password123", "", "", ""); err != nil {
+		t.Errorf("expected an enabled user to sign in again, got %v", err)
+	}
+
+	// A lost authenticator: the admin turns two-factor off so the user can sign in and set it up again
+	member.TOTPEnabled, member.TOTPSecret = true, "JBSWY3DPEHPK3PXP"
+	recoveryRepo.codes[member.ID] = []*domain.RecoveryCode{{ID: "c1", UserID: member.ID}}
+	if _, err := service.ResetTwoFactor(ctx, member.ID); err != nil {
+		t.Fatal(err)
+	}
+	if member.TOTPEnabled || member.TOTPSecret != "" || len(recoveryRepo.codes[member.ID]) != 0 {
+		t.Errorf("expected two-factor and its recovery codes gone, got %+v", member)
+	}
+	if _, err := service.ResetTwoFactor(ctx, member.ID); err == nil {
+		t.Error("expected resetting two-factor that's off to be refused")
+	}
+
+	if _, err := service.TransferOwnership(ctx, nil, member.ID); err == nil {
+		t.Error("expected ownership to need a signed-in admin to hand it over")
+	}
+	if _, err := service.TransferOwnership(ctx, &admin.ID, member.ID); err != nil {
+		t.Fatal(err)
+	}
+	if member.Role != domain.UserRoleAdmin || admin.Role != domain.UserRoleMember {
+		t.Errorf("expected the roles swapped, got %s and %s", member.Role, admin.Role)
+	}
+}
@@ -0,0 +1,289 @@
+package application
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	// PasswordResetLifetime is how long a password reset link stays valid
+	PasswordResetLifetime = time.Hour
+
+	// EmailVerificationLifetime is how long an email verification link stays valid
+	EmailVerificationLifetime = 24 * time.Hour
+)
+
+// ErrEmailNotConfigured is returned when an email needs sending but no email channel is set up
+var ErrEmailNotConfigured = errors.New("email is not configured")
+
+// UserEmailService handles password reset and email verification
+// Links carry a random token; only an HMAC of it is stored. With a key from the server
+// configuration (see UseSigningKey), a copy of the database alone can't be used to redeem
+// tokens. Without one, a generated key is kept in settings, in the same database.
+type UserEmailService struct {
+	userRepo      domain.UserRepository
+	sessionRepo   domain.SessionRepository
+	userTokenRepo domain.UserTokenRepository
+	settingRepo   domain.SettingRepository
+	channel       NotificationChannel // Email channel; nil when email isn't configured
+	publicURL     string              // Base URL used in emailed links, e.g. https://budget.example.com
+
+	keyMu sync.Mutex
+	key   []byte
+}
+
+// NewUserEmailService creates a new user email service
+func NewUserEmailService(
+	userRepo domain.UserRepository,
+	sessionRepo domain.SessionRepository,
+	userTokenRepo domain.UserTokenRepository,
+	settingRepo domain.SettingRepository,
+	channel NotificationChannel,
+	publicURL string,
+) *UserEmailService {
+	return &UserEmailService{
+		userRepo:      userRepo,
+		sessionRepo:   sessionRepo,
+		userTokenRepo: userTokenRepo,
+		settingRepo:   settingRepo,
+		channel:       channel,
+		publicURL:     strings.TrimRight(publicURL, "/"),
+	}
+}
+
+// UseSigningKey keys token HMACs with a secret from the server configuration instead of
+// one generated and kept in settings
+// Changing the key invalidates the links already sent.
+func (s *UserEmailService) UseSigningKey(key string) {
+	s.keyMu.Lock()
+	defer s.keyMu.Unlock()
+	s.key = []byte(key)
+}
+
+// RequestPasswordReset emails a password reset link to the user with this email
+// Unknown addresses are silently ignored so the endpoint can't be used to probe for accounts.
+func (s *UserEmailService) RequestPasswordReset(ctx context.Context, email string) error {
+	if s.channel == nil {
+		return ErrEmailNotConfigured
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, strings.TrimSpace(email))
+	if err != nil {
+		return nil
+	}
+
+	raw, err := s.issueToken(ctx, user, domain.UserTokenPasswordReset, PasswordResetLifetime)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(`Someone asked to reset the password for %s.
+
+To choose a new password, open this link within the next hour:
+
+%s
+
+If you didn't ask for this, you can ignore this email; your password won't change.
+`, user.Email, s.link("/reset-password.html", raw))
+
+	return s.channel.Send(ctx, &Notification{To: user.Email, Subject: "Reset your budget password", Body: body})
+}
+
+// ResetPassword sets a new password using a reset token
+// All of the user's sessions are signed out.
+func (s *UserEmailService) ResetPassword(ctx context.Context, raw, password string) error {
+	token, user, err := s.redeemToken(ctx, domain.UserTokenPasswordReset, raw)
+	if err != nil {
+		return err
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	if err := s.userTokenRepo.MarkUsed(ctx, token.ID, time.Now()); err != nil {
+		return fmt.Errorf("invalid or expired link")
+	}
+
+	now := time.Now()
+	user.PasswordHash = hash
+	// Receiving the email proves the address belongs to the user
+	if user.EmailVerifiedAt == nil && strings.EqualFold(token.Email, user.Email) {
+		user.EmailVerifiedAt = &now
+	}
+	user.UpdatedAt = now
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	if err := s.userTokenRepo.DeleteByUser(ctx, user.ID, domain.UserTokenPasswordReset); err != nil {
+		return err
+	}
+	return s.sessionRepo.DeleteByUser(ctx, user.ID, "")
+}
+
+// SendVerificationEmail emails the user a link that confirms their address
+func (s *UserEmailService) SendVerificationEmail(ctx context.Context, user *domain.User) error {
+	if s.channel == nil {
+		return ErrEmailNotConfigured
+	}
+	if user.EmailVerifiedAt != nil {
+		return fmt.Errorf("email is already verified")
+	}
+
+	raw, err := s.issueToken(ctx, user, domain.UserTokenEmailVerification, EmailVerificationLifetime)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(`Please confirm that %s is your email address by opening this link within 24 hours:
+
+%s
+`, user.Email, s.link("/verify-email.html", raw))
+
+	return s.channel.Send(ctx, &Notification{To: user.Email, Subject: "Verify your email address", Body: body})
+}
+
+// VerifyEmail marks the user's email as verified using a verification token
+// The token only counts if the user's email hasn't changed since it was sent.
+func (s *UserEmailService) VerifyEmail(ctx context.Context, raw string) (*domain.User, error) {
+	token, user, err := s.redeemToken(ctx, domain.UserTokenEmailVerification, raw)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(token.Email, user.Email) {
+		return nil, fmt.Errorf("invalid or expired link")
+	}
+	if err := s.userTokenRepo.MarkUsed(ctx, token.ID, time.Now()); err != nil {
+		return nil, fmt.Errorf("invalid or expired link")
+	}
+
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	user.UpdatedAt = now
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// issueToken replaces any outstanding token of this purpose with a new one
+func (s *UserEmailService) issueToken(ctx context.Context, user *domain.User, purpose domain.UserTokenPurpose, lifetime time.Duration) (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	raw := hex.EncodeToString(secret)
+
+	hash, err := s.signToken(ctx, purpose, raw)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.userTokenRepo.DeleteByUser(ctx, user.ID, purpose); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	token := &domain.UserToken{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: hash,
+		Email:     user.Email,
+		ExpiresAt: now.Add(lifetime),
+		CreatedAt: now,
+	}
+	if err := s.userTokenRepo.Create(ctx, token); err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// redeemToken looks up an unused, unexpired token and its user
+func (s *UserEmailService) redeemToken(ctx context.Context, purpose domain.UserTokenPurpose, raw string) (*domain.UserToken, *domain.User, error) {
+	invalid := fmt.Errorf("invalid or expired link")
+
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil, invalid
+	}
+
+	hash, err := s.signToken(ctx, purpose, raw)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	token, err := s.userTokenRepo.GetByHash(ctx, purpose, hash)
+	if err != nil || token.UsedAt != nil || time.Now().After(token.ExpiresAt) {
+		return nil, nil, invalid
+	}
+
+	user, err := s.userRepo.GetByID(ctx, token.UserID)
+	if err != nil {
+		return nil, nil, invalid
+	}
+	return token, user, nil
+}
+
+// signToken returns the HMAC of the token (bound to its purpose) under the server key
+func (s *UserEmailService) signToken(ctx context.Context, purpose domain.UserTokenPurpose, raw string) (string, error) {
+	key, err := s.signingKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(string(purpose) + ":" + raw))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// signingKey returns the configured server key, or loads it from settings, generating it
+// on first use
+func (s *UserEmailService) signingKey(ctx context.Context) ([]byte, error) {
+	s.keyMu.Lock()
+	defer s.keyMu.Unlock()
+
+	if s.key != nil {
+		return s.key, nil
+	}
+
+	if setting, err := s.settingRepo.Get(ctx, domain.SettingKeyAuthTokenKey); err == nil {
+		key, err := hex.DecodeString(setting.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode token signing key: %w", err)
+		}
+		s.key = key
+		return key, nil
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate token signing key: %w", err)
+	}
+	setting := &domain.Setting{
+		Key:       domain.SettingKeyAuthTokenKey,
+		Value:     hex.EncodeToString(key),
+		UpdatedAt: time.Now(),
+	}
+	if err := s.settingRepo.Set(ctx, setting); err != nil {
+		return nil, err
+	}
+	s.key = key
+	return key, nil
+}
+
+func (s *UserEmailService) link(path, token string) string {
+	return s.publicURL + path + "?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,64 @@
+package application
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestUserEmailService_ResetPassword(t *testing.T) {
+	ctx := context.Background()
+	userRepo := &mockUserRepository{}
+	sessionRepo := &mockSessionRepository{}
+	settingRepo := newMockSettingRepository()
+	auth := NewAuthService(userRepo, sessionRepo, &mockAPITokenRepository{tokens: map[string]*domain.APIToken{}}, &mockRecoveryCodeRepository{codes: map[string][]*domain.RecoveryCode{}})
+	channel := &recordingChannel{}
+	service := NewUserEmailService(userRepo, sessionRepo, &mockUserTokenRepository{}, settingRepo, channel, "https://budget.example.com")
+	service.UseSigningKey("configured-secret")
+
+	user, err := auth.CreateUser(ctx, "kid@example.com", "Kid", "password123", domain.UserRoleMember)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, laptop, err := auth.StartSession(ctx, user, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, phone, err := auth.StartSession(ctx, user, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := service.RequestPasswordReset(ctx, "kid@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if len(channel.sent) != 1 {
+		t.Fatalf("expected a reset email, got %d", len(channel.sent))
+	}
+	link := regexp.MustCompile(`token=([0-9a-f]+)`).FindStringSubmatch(channel.sent[0].Body)
+	if link == nil {
+		t.Fatalf("expected a reset link in %q", channel.sent[0].Body)
+	}
+
+	if err := service.ResetPassword(ctx, link[1], "new-password"); err != nil {
+		t.Fatal(err)
+	}
+	for _, raw := range []string{laptop, phone} {
+		if _, _, err := auth.AuthenticateSession(ctx, raw); err == nil {
+			t.Error("expected sessions from before the reset to be signed out")
+		}
+	}
+	if _, _, _, err := auth.Login(ctx, "kid@example.com", "new-password", "", "", ""); err != nil {
+		t.Errorf("expected the new password to sign in, got %v", err)
+	}
+	if err := service.ResetPassword(ctx, link[1], "another-password"); err == nil {
+		t.Error("expected a reset link to work only once")
+	}
+
+	// The configured key is never written to the database
+	if _, ok := settingRepo.settings[domain.SettingKeyAuthTokenKey]; ok {
+		t.Error("expected the configured signing key to stay out of settings")
+	}
+}
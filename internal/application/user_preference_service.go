@@ -0,0 +1,103 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// localePattern accepts simple BCP 47 tags such as "en", "en-US" or "pt-BR"
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z]{2})?$`)
+
+// UserPreferenceService manages per-user preferences
+type UserPreferenceService struct {
+	userSettingRepo domain.UserSettingRepository
+}
+
+// NewUserPreferenceService creates a new user preference service
+func NewUserPreferenceService(userSettingRepo domain.UserSettingRepository) *UserPreferenceService {
+	return &UserPreferenceService{userSettingRepo: userSettingRepo}
+}
+
+// GetPreferences returns the user's preferences, with defaults for anything not yet set
+func (s *UserPreferenceService) GetPreferences(ctx context.Context, userID string) (*domain.UserPreferences, error) {
+	settings, err := s.userSettingRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs := domain.DefaultUserPreferences()
+	for _, setting := range settings {
+		switch setting.Key {
+		case domain.UserSettingKeyLocale:
+			prefs.Locale = setting.Value
+		case domain.UserSettingKeyDateFormat:
+			prefs.DateFormat = setting.Value
+		case domain.UserSettingKeyDefaultPeriod:
+			prefs.DefaultPeriod = domain.DefaultPeriod(setting.Value)
+		case domain.UserSettingKeyPeriodType:
+			prefs.PeriodType = domain.PeriodType(setting.Value)
+		case domain.UserSettingKeyNotifications:
+			if err := json.Unmarshal([]byte(setting.Value), &prefs.Notifications); err != nil {
+				return nil, fmt.Errorf("failed to decode notification preferences: %w", err)
+			}
+		}
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences validates and saves the user's preferences
+func (s *UserPreferenceService) UpdatePreferences(ctx context.Context, userID string, prefs *domain.UserPreferences) (*domain.UserPreferences, error) {
+	if !localePattern.MatchString(prefs.Locale) {
+		return nil, fmt.Errorf("locale must be a language tag such as en-US")
+	}
+	if !slices.Contains(domain.DateFormats, prefs.DateFormat) {
+		return nil, fmt.Errorf("date_format must be one of %v", domain.DateFormats)
+	}
+	if !prefs.DefaultPeriod.IsValid() {
+		return nil, fmt.Errorf("default_period must be current, previous or next")
+	}
+	if prefs.PeriodType == "" {
+		prefs.PeriodType = domain.PeriodMonth
+	}
+	if !prefs.PeriodType.IsValid() {
+		return nil, fmt.Errorf("period_type must be month, week or quarter")
+	}
+	if prefs.Notifications.Digest == "" {
+		prefs.Notifications.Digest = domain.DigestOff
+	}
+	if !prefs.Notifications.Digest.IsValid() {
+		return nil, fmt.Errorf("notifications.digest must be off, hourly or daily")
+	}
+	for _, kind := range prefs.Notifications.Urgent {
+		if !kind.IsValid() {
+			return nil, fmt.Errorf("notifications.urgent may only list pending_transactions and security_alerts")
+		}
+	}
+
+	notifications, err := json.Marshal(prefs.Notifications)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode notification preferences: %w", err)
+	}
+
+	now := time.Now()
+	for key, value := range map[string]string{
+		domain.UserSettingKeyLocale:        prefs.Locale,
+		domain.UserSettingKeyDateFormat:    prefs.DateFormat,
+		domain.UserSettingKeyDefaultPeriod: string(prefs.DefaultPeriod),
+		domain.UserSettingKeyPeriodType:    string(prefs.PeriodType),
+		domain.UserSettingKeyNotifications: string(notifications),
+	} {
+		setting := &domain.UserSetting{UserID: userID, Key: key, Value: value, UpdatedAt: now}
+		if err := s.userSettingRepo.Set(ctx, setting); err != nil {
+			return nil, err
+		}
+	}
+
+	return prefs, nil
+}
@@ -0,0 +1,66 @@
+package application
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestUserPreferenceService_UpdatePreferences(t *testing.T) {
+	ctx := context.Background()
+	service := NewUserPreferenceService(newMockUserSettingRepository())
+
+	prefs, err := service.GetPreferences(ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(prefs, domain.DefaultUserPreferences()) {
+		t.Errorf("expected defaults before anything is saved, got %+v", prefs)
+	}
+
+	prefs.Locale = "de-DE"
+	prefs.DateFormat = "DD.MM.YYYY"
+	prefs.PeriodType = ""
+	prefs.Notifications.Digest = ""
+	prefs.Notifications.Urgent = []domain.NotificationKind{domain.NotificationSecurityAlerts}
+	if _, err := service.UpdatePreferences(ctx, "u1", prefs); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := service.GetPreferences(ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Locale != "de-DE" || saved.DateFormat != "DD.MM.YYYY" || saved.PeriodType != domain.PeriodMonth || saved.Notifications.Digest != domain.DigestOff {
+		t.Errorf("expected the new locale and date format saved with blank fields defaulted, got %+v", saved)
+	}
+	if len(saved.Notifications.Urgent) != 1 || saved.Notifications.Urgent[0] != domain.NotificationSecurityAlerts {
+		t.Errorf("expected the urgent notifications saved, got %v", saved.Notifications.Urgent)
+	}
+	if other, _ := service.GetPreferences(ctx, "u2"); other.Locale != "en-US" {
+		t.Errorf("expected another user's preferences untouched, got %+v", other)
+	}
+
+	invalid := []func(*domain.UserPreferences){
+		func(p *domain.UserPreferences) { p.Locale = "german" },
+		func(p *domain.UserPreferences) { p.Locale = "DE-de" },
+		func(p *domain.UserPreferences) { p.Locale = "" },
+		func(p *domain.UserPreferences) { p.DateFormat = "YYYY/MM/DD" },
+		func(p *domain.UserPreferences) { p.DefaultPeriod = "last" },
+		func(p *domain.UserPreferences) { p.PeriodType = "year" },
+		func(p *domain.UserPreferences) { p.Notifications.Digest = "weekly" },
+		func(p *domain.UserPreferences) { p.Notifications.Urgent = []domain.NotificationKind{"budget_tips"} },
+	}
+	for i, change := range invalid {
+		prefs, _ := service.GetPreferences(ctx, "u1")
+		change(prefs)
+		if _, err := service.UpdatePreferences(ctx, "u1", prefs); err == nil {
+			t.Errorf("expected invalid preferences %d (%+v) to be refused", i, prefs)
+		}
+	}
+	if saved, _ := service.GetPreferences(ctx, "u1"); saved.Locale != "de-DE" || saved.DateFormat != "DD.MM.YYYY" {
+		t.Errorf("expected refused updates to save nothing, got %+v", saved)
+	}
+}
@@ -0,0 +1,244 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ErrWidgetRateLimited is returned when a widget's token has been used too often this minute
+var ErrWidgetRateLimited = errors.New("too many requests for this widget; try again in a minute")
+
+const (
+	// widgetRequestsPerMinute is how often a widget's token may be used each minute, which
+	// is plenty for an overlay refreshing every few seconds
+	widgetRequestsPerMinute = 12
+
+	// widgetDataTTL is how long a widget's data is served before it's read again
+	widgetDataTTL = time.Minute
+)
+
+// WidgetData is what a widget shows: only the fields it was given, by name
+type WidgetData map[string]any
+
+// WidgetService manages widgets and serves their data to anyone with their token
+type WidgetService struct {
+	widgetRepo        domain.WidgetRepository
+	categoryRepo      domain.CategoryRepository
+	allocationService *AllocationService
+
+	mu       sync.Mutex
+	window   time.Time                   // Minute the request counts are for
+	requests map[string]int              // Requests this minute, by token hash, known or not
+	cached   map[string]cachedWidgetData // By widget ID
+}
+
+type cachedWidgetData struct {
+	data WidgetData
+	at   time.Time
+}
+
+// NewWidgetService creates a new widget service
+func NewWidgetService(widgetRepo domain.WidgetRepository, categoryRepo domain.CategoryRepository, allocationService *AllocationService) *WidgetService {
+	return &WidgetService{
+		widgetRepo:        widgetRepo,
+		categoryRepo:      categoryRepo,
+		allocationService: allocationService,
+		requests:          make(map[string]int),
+		cached:            make(map[string]cachedWidgetData),
+	}
+}
+
+// CreateWidget creates a widget showing fields of a category and returns it with its token
+// Without fields it shows just the goal's percentage. The token is only available here;
+// only its hash is stored.
+func (s *WidgetService) CreateWidget(ctx context.Context, name, categoryID string, fields []string) (*domain.Widget, string, error) {
+	if len(fields) == 0 {
+		fields = []string{domain.WidgetFieldGoalPercent}
+	}
+	widget := &domain.Widget{ID: uuid.New().String(), Name: strings.TrimSpace(name), CategoryID: categoryID, Fields: fields}
+	if err := s.validateWidget(ctx, widget); err != nil {
+		return nil, "", err
+	}
+
+	token, err := generateBotToken()
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now()
+	widget.TokenHash = hashBotToken(token)
+	widget.CreatedAt = now
+	widget.UpdatedAt = now
+	if err := s.widgetRepo.Create(ctx, widget); err != nil {
+		return nil, "", err
+	}
+	return widget, token, nil
+}
+
+// ListWidgets lists every widget
+func (s *WidgetService) ListWidgets(ctx context.Context) ([]*domain.Widget, error) {
+	return s.widgetRepo.List(ctx)
+}
+
+// UpdateWidget renames a widget or changes what it shows; nil leaves that part as it is
+// The change shows in embeds once their data next expires.
+func (s *WidgetService) UpdateWidget(ctx context.Context, id string, name, categoryID *string, fields []string) (*domain.Widget, error) {
+	widget, err := s.widgetRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if name != nil {
+		widget.Name = strings.TrimSpace(*name)
+	}
+	if categoryID != nil {
+		widget.CategoryID = *categoryID
+	}
+	if fields != nil {
+		widget.Fields = fields
+	}
+	if err := s.validateWidget(ctx, widget); err != nil {
+		return nil, err
+	}
+
+	widget.UpdatedAt = time.Now()
+	if err := s.widgetRepo.Update(ctx, widget); err != nil {
+		return nil, err
+	}
+	s.forget(widget.ID)
+	return widget, nil
+}
+
+// DeleteWidget deletes a widget; its token stops working at once
+func (s *WidgetService) DeleteWidget(ctx context.Context, id string) error {
+	if err := s.widgetRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.forget(id)
+	return nil
+}
+
+// WidgetData returns the data of the widget with token, at most widgetRequestsPerMinute
+// times a minute per token
+// It's read fresh at most every widgetDataTTL. Amounts are in the budget currency's minor units.
+func (s *WidgetService) WidgetData(ctx context.Context, token string) (WidgetData, error) {
+	if token == "" {
+		return nil, domain.ErrWidgetNotFound
+	}
+	tokenHash := hashBotToken(token)
+	if !s.allow(tokenHash) {
+		return nil, ErrWidgetRateLimited
+	}
+
+	widget, err := s.widgetRepo.GetByTokenHash(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	cached, ok := s.cached[widget.ID]
+	s.mu.Unlock()
+	if ok && time.Since(cached.at) < widgetDataTTL {
+		return cached.data, nil
+	}
+
+	data, err := s.widgetData(ctx, widget)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.cached[widget.ID] = cachedWidgetData{data: data, at: time.Now()}
+	s.mu.Unlock()
+	return data, nil
+}
+
+// widgetData reads the widget's fields from this month's summary of its category
+func (s *WidgetService) widgetData(ctx context.Context, widget *domain.Widget) (WidgetData, error) {
+	summaries, err := s.allocationService.GetAllocationSummary(ctx, time.Now().Format("2006-01"))
+	if err != nil {
+		return nil, err
+	}
+	index := slices.IndexFunc(summaries, func(summary *domain.AllocationSummary) bool {
+		return summary.Category.ID == widget.CategoryID
+	})
+	if index < 0 {
+		return nil, domain.ErrCategoryNotFound
+	}
+	summary := summaries[index]
+
+	goal := summary.Goal // Goal fields are null for a category without one
+	data := make(WidgetData, len(widget.Fields))
+	for _, field := range widget.Fields {
+		switch field {
+		case domain.WidgetFieldCategoryName:
+			data[field] = summary.Category.Name
+		case domain.WidgetFieldAvailable:
+			data[field] = summary.Available
+		case domain.WidgetFieldGoalPercent:
+			data[field] = goalField(goal, func(goal *domain.GoalProgress) any { return goal.PercentComplete })
+		case domain.WidgetFieldGoalOnTrack:
+			data[field] = goalField(goal, func(goal *domain.GoalProgress) any { return goal.OnTrack })
+		case domain.WidgetFieldGoalDuePeriod:
+			data[field] = goalField(goal, func(goal *domain.GoalProgress) any { return goal.DuePeriod })
+		case domain.WidgetFieldGoalFunded:
+			data[field] = goalField(goal, func(goal *domain.GoalProgress) any { return goal.Funded })
+		case domain.WidgetFieldGoalTarget:
+			data[field] = goalField(goal, func(goal *domain.GoalProgress) any { return goal.Goal.TargetAmount })
+		}
+	}
+	return data, nil
+}
+
+// goalField returns value of goal, or nil without one
+func goalField(goal *domain.GoalProgress, value func(*domain.GoalProgress) any) any {
+	if goal == nil {
+		return nil
+	}
+	return value(goal)
+}
+
+// validateWidget checks a widget's name, category and fields
+func (s *WidgetService) validateWidget(ctx context.Context, widget *domain.Widget) error {
+	if widget.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if _, err := s.categoryRepo.GetByID(ctx, widget.CategoryID); err != nil {
+		return domain.ErrCategoryNotFound
+	}
+	if len(widget.Fields) == 0 {
+		return fmt.Errorf("a widget needs at least one field")
+	}
+	for i, field := range widget.Fields {
+		if !slices.Contains(domain.WidgetFields, field) {
+			return fmt.Errorf("unknown widget field %q; fields are %s", field, strings.Join(domain.WidgetFields, ", "))
+		}
+		if slices.Contains(widget.Fields[:i], field) {
+			return fmt.Errorf("widget field %q is listed twice", field)
+		}
+	}
+	return nil
+}
+
+// allow counts a request for tokenHash, reporting whether it's within this minute's limit
+func (s *WidgetService) allow(tokenHash string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if window := time.Now().Truncate(time.Minute); !window.Equal(s.window) {
+		s.window = window
+		clear(s.requests)
+	}
+	s.requests[tokenHash]++
+	return s.requests[tokenHash] <= widgetRequestsPerMinute
+}
+
+// forget drops a widget's cached data after it changes
+func (s *WidgetService) forget(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.cached, id)
+}
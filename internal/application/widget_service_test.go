@@ -0,0 +1,140 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type mockWidgetRepository struct {
+	widgets map[string]*domain.Widget
+}
+
+func (m *mockWidgetRepository) Create(ctx context.Context, widget *domain.Widget) error {
+	m.widgets[widget.ID] = widget
+	return nil
+}
+
+func (m *mockWidgetRepository) GetByID(ctx context.Context, id string) (*domain.Widget, error) {
+	widget, ok := m.widgets[id]
+	if !ok {
+		return nil, domain.ErrWidgetNotFound
+	}
+	return widget, nil
+}
+
+func (m *mockWidgetRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.Widget, error) {
+	for _, widget := range m.widgets {
+		if widget.TokenHash == tokenHash {
+			return widget, nil
+		}
+	}
+	return nil, domain.ErrWidgetNotFound
+}
+
+func (m *mockWidgetRepository) List(ctx context.Context) ([]*domain.Widget, error) {
+	var widgets []*domain.Widget
+	for _, widget := range m.widgets {
+		widgets = append(widgets, widget)
+	}
+	return widgets, nil
+}
+
+func (m *mockWidgetRepository) Update(ctx context.Context, widget *domain.Widget) error {
+	m.widgets[widget.ID] = widget
+	return nil
+}
+
+func (m *mockWidgetRepository) Delete(ctx context.Context, id string) error {
+	if _, ok := m.widgets[id]; !ok {
+		return domain.ErrWidgetNotFound
+	}
+	delete(m.widgets, id)
+	return nil
+}
+
+func TestWidgetService_WidgetData(t *testing.T) {
+	ctx := context.Background()
+	categoryRepo := newMockCategoryRepository()
+	categoryRepo.categories["vacation"] = &domain.Category{ID: "vacation", Name: "Vacation"}
+	allocationRepo := newMockAllocationRepository()
+	allocationRepo.Create(ctx, &domain.Allocation{ID: "a1", CategoryID: "vacation", Amount: 30000, Period: time.Now().Format("2006-01")})
+	goalRepo := newMockGoalRepository()
+	goalRepo.Create(ctx, &domain.Goal{ID: "g1", CategoryID: "vacation", TargetAmount: 120000, Cadence: domain.GoalCadenceOnce})
+	allocationService := NewAllocationService(allocationRepo, categoryRepo, newMockTransactionRepository(), newMockBudgetStateRepository(0, 0),
+		newMockAccountRepository(0), newMockCategoryGroupRepository(), goalRepo)
+	service := NewWidgetService(&mockWidgetRepository{widgets: map[string]*domain.Widget{}}, categoryRepo, allocationService)
+
+	if _, _, err := service.CreateWidget(ctx, "Vacation", "vacation", []string{"balance"}); err == nil {
+		t.Error("expected an unknown field to be refused")
+	}
+	if _, _, err := service.CreateWidget(ctx, "Vacation", "missing", nil); !errors.Is(err, domain.ErrCategoryNotFound) {
+		t.Errorf("expected the category to have to exist, got %v", err)
+	}
+
+	widget, token, err := service.CreateWidget(ctx, "Vacation", "vacation", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token == "" || widget.TokenHash == token {
+		t.Error("expected a token stored only as a hash")
+	}
+
+	// Only the chosen fields are shown; by default just the goal's percentage
+	data, err := service.WidgetData(ctx, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 || data[domain.WidgetFieldGoalPercent] != 25 {
+		t.Errorf("expected only goal_percent of 25, got %v", data)
+	}
+
+	fields := []string{domain.WidgetFieldCategoryName, domain.WidgetFieldGoalFunded}
+	if _, err := service.UpdateWidget(ctx, widget.ID, nil, nil, fields); err != nil {
+		t.Fatal(err)
+	}
+	data, err = service.WidgetData(ctx, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 2 || data[domain.WidgetFieldCategoryName] != "Vacation" || data[domain.WidgetFieldGoalFunded] != int64(30000) {
+		t.Errorf("expected the changed fields right away, got %v", data)
+	}
+
+	if _, err := service.WidgetData(ctx, "not-a-token"); !errors.Is(err, domain.ErrWidgetNotFound) {
+		t.Errorf("expected an unknown token to be refused, got %v", err)
+	}
+}
+
+func TestWidgetService_RateLimit(t *testing.T) {
+	ctx := context.Background()
+	categoryRepo := newMockCategoryRepository()
+	categoryRepo.categories["vacation"] = &domain.Category{ID: "vacation", Name: "Vacation"}
+	allocationService := NewAllocationService(newMockAllocationRepository(), categoryRepo, newMockTransactionRepository(), newMockBudgetStateRepository(0, 0),
+		newMockAccountRepository(0), newMockCategoryGroupRepository(), newMockGoalRepository())
+	service := NewWidgetService(&mockWidgetRepository{widgets: map[string]*domain.Widget{}}, categoryRepo, allocationService)
+	_, token, err := service.CreateWidget(ctx, "Vacation", "vacation", []string{domain.WidgetFieldCategoryName})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Pinned to the current minute, so the window doesn't roll over mid-test
+	service.window = time.Now().Truncate(time.Minute)
+	for i := 0; i < widgetRequestsPerMinute; i++ {
+		if _, err := service.WidgetData(ctx, token); err != nil {
+			t.Fatalf("request %d: %v", i+1, err)
+		}
+	}
+	if _, err := service.WidgetData(ctx, token); !errors.Is(err, ErrWidgetRateLimited) {
+		t.Errorf("expected the request over the limit to be refused, got %v", err)
+	}
+
+	// The next minute starts afresh
+	service.window = service.window.Add(-time.Minute)
+	if _, err := service.WidgetData(ctx, token); err != nil {
+		t.Errorf("expected a new minute to allow requests again, got %v", err)
+	}
+}
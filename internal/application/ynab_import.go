@@ -0,0 +1,364 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/billybbuffum/budget/internal/infrastructure/ynab"
+	"github.com/google/uuid"
+)
+
+// ynabCategory names a category by its YNAB group and name
+type ynabCategory struct {
+	group, name string
+}
+
+// ImportYNAB recreates a budget from YNAB's register and budget CSV exports
+// Accounts come from the register; YNAB doesn't export their types, so accountTypes maps
+// account names to types, and accounts it doesn't name are credit cards if the budget has
+// a credit card payment category for them and checking otherwise. Categories come from both
+// files, transactions from the register and allocations from the budget's assigned amounts.
+// Transfers between imported accounts become transfers, YNAB's income categories become
+// uncategorized inflows and its credit card payment categories become the cards' payment
+// categories. budget may be nil to import transactions only.
+// Like Import, only a budget without accounts, transactions or allocations can be
+// imported into, its categories are replaced, and everything is imported or nothing is.
+func (s *ExportService) ImportYNAB(ctx context.Context, register, budget io.Reader, accountTypes map[string]domain.AccountType) (*BudgetExportImportResult, error) {
+	registerRows, err := s.ynabParser.ParseRegister(register)
+	if err != nil {
+		return nil, fmt.Errorf("register: %w", err)
+	}
+	var budgetRows []ynab.BudgetRow
+	if budget != nil {
+		if budgetRows, err = s.ynabParser.ParseBudget(budget); err != nil {
+			return nil, fmt.Errorf("budget: %w", err)
+		}
+	}
+	for name, accountType := range accountTypes {
+		switch accountType {
+		case domain.AccountTypeChecking, domain.AccountTypeSavings, domain.AccountTypeCash, domain.AccountTypeCredit:
+		default:
+			return nil, fmt.Errorf("account %s has invalid type %q", name, accountType)
+		}
+	}
+
+	var result *BudgetExportImportResult
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.checkEmpty(ctx); err != nil {
+			return err
+		}
+		if err := s.clearCategories(ctx); err != nil {
+			return err
+		}
+
+		importer := &ynabImporter{
+			ExportService: s,
+			result:        &BudgetExportImportResult{},
+			accounts:      make(map[string]*domain.Account),
+			groups:        make(map[string]*domain.CategoryGroup),
+			categories:    make(map[ynabCategory]string),
+			payments:      make(map[string]string),
+		}
+		if err := importer.createAccounts(ctx, registerRows, budgetRows, accountTypes); err != nil {
+			return err
+		}
+		if err := importer.createCategories(ctx, registerRows, budgetRows); err != nil {
+			return err
+		}
+		if err := importer.createTransactions(ctx, registerRows); err != nil {
+			return err
+		}
+		if err := importer.createAllocations(ctx, budgetRows); err != nil {
+			return err
+		}
+		result = importer.result
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ynabImporter keeps track of what a YNAB import has created so far
+type ynabImporter struct {
+	*ExportService
+	result     *BudgetExportImportResult
+	accounts   map[string]*domain.Account       // By YNAB account name
+	groups     map[string]*domain.CategoryGroup // By YNAB group name
+	categories map[ynabCategory]string          // Category IDs
+	payments   map[string]string                // Payment category IDs, by credit card name
+}
+
+// createAccounts creates each account in the register with the balance its rows add up to
+// A credit card's opening debt is kept on the balance without a transaction, as when a
+// card is added by hand.
+func (im *ynabImporter) createAccounts(ctx context.Context, register []ynab.RegisterRow, budget []ynab.BudgetRow, accountTypes map[string]domain.AccountType) error {
+	var names []string
+	balances := make(map[string]int64)
+	for _, row := range register {
+		if _, ok := balances[row.Account]; !ok {
+			names = append(names, row.Account)
+		}
+		balances[row.Account] += row.Amount
+	}
+	cards := make(map[string]bool)
+	for _, row := range budget {
+		if row.CategoryGroup == domain.CreditCardPaymentsGroupName {
+			cards[row.Category] = true
+		}
+	}
+
+	var paymentsGroup *domain.CategoryGroup
+	now := time.Now()
+	for _, name := range names {
+		accountType, ok := accountTypes[name]
+		if !ok {
+			accountType = domain.AccountTypeChecking
+			if cards[name] {
+				accountType = domain.AccountTypeCredit
+			}
+		}
+		account := &domain.Account{
+			ID:        uuid.New().String(),
+			Name:      name,
+			Balance:   balances[name],
+			Type:      accountType,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := im.accountRepo.Create(ctx, account); err != nil {
+			return err
+		}
+		im.accounts[name] = account
+		im.result.Accounts++
+
+		if accountType != domain.AccountTypeCredit {
+			continue
+		}
+		if paymentsGroup == nil {
+			paymentsGroup = &domain.CategoryGroup{
+				ID:           uuid.New().String(),
+				Name:         domain.CreditCardPaymentsGroupName,
+				Description:  "Payment categories for credit card accounts",
+				DisplayOrder: 9999,
+				CreatedAt:    now,
+				UpdatedAt:    now,
+			}
+			if err := im.categoryGroupRepo.Create(ctx, paymentsGroup); err != nil {
+				return err
+			}
+			im.result.CategoryGroups++
+		}
+		payment := &domain.Category{
+			ID:                  uuid.New().String(),
+			Name:                name + " Payment",
+			Description:         "Payment category for " + name,
+			Color:               "#FF6B6B",
+			GroupID:             &paymentsGroup.ID,
+			PaymentForAccountID: &account.ID,
+			Classification:      domain.ClassificationDebt,
+			OverspendingMode:    domain.OverspendingAuto,
+			CreatedAt:           now,
+			UpdatedAt:           now,
+		}
+		if err := im.categoryRepo.Create(ctx, payment); err != nil {
+			return err
+		}
+		im.payments[name] = payment.ID
+		im.categories[ynabCategory{domain.CreditCardPaymentsGroupName, name}] = payment.ID
+		im.result.Categories++
+	}
+	return nil
+}
+
+// createCategories creates the groups and categories used in either file, in the order
+// they first appear
+func (im *ynabImporter) createCategories(ctx context.Context, register []ynab.RegisterRow, budget []ynab.BudgetRow) error {
+	var used []ynabCategory
+	for _, row := range budget {
+		used = append(used, ynabCategory{row.CategoryGroup, row.Category})
+	}
+	for _, row := range register {
+		if row.TransferAccount() == "" {
+			used = append(used, ynabCategory{row.CategoryGroup, row.Category})
+		}
+	}
+
+	now := time.Now()
+	for _, key := range used {
+		if key.group == "" || key.name == "" || ynab.IsIncomeGroup(key.group) || key.group == domain.CreditCardPaymentsGroupName {
+			continue
+		}
+		if _, ok := im.categories[key]; ok {
+			continue
+		}
+
+		group, ok := im.groups[key.group]
+		if !ok {
+			group = &domain.CategoryGroup{
+				ID:           uuid.New().String(),
+				Name:         key.group,
+				DisplayOrder: len(im.groups) + 1,
+				CreatedAt:    now,
+				UpdatedAt:    now,
+			}
+			if err := im.categoryGroupRepo.Create(ctx, group); err != nil {
+				return err
+			}
+			im.groups[key.group] = group
+			im.result.CategoryGroups++
+		}
+
+		category := &domain.Category{
+			ID:               uuid.New().String(),
+			Name:             key.name,
+			GroupID:          &group.ID,
+			Classification:   domain.ClassificationDiscretionary,
+			OverspendingMode: domain.OverspendingAuto,
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		}
+		if err := im.categoryRepo.Create(ctx, category); err != nil {
+			return err
+		}
+		im.categories[key] = category.ID
+		im.result.Categories++
+	}
+	return nil
+}
+
+// createTransactions creates the register's transactions
+// YNAB lists both sides of a transfer; the pair is created from its outflow and the
+// inflow is skipped. Zero amounts are skipped.
+func (im *ynabImporter) createTransactions(ctx context.Context, register []ynab.RegisterRow) error {
+	now := time.Now()
+	for _, row := range register {
+		if row.Amount == 0 {
+			continue
+		}
+		account := im.accounts[row.Account]
+		transaction := &domain.Transaction{
+			ID:          uuid.New().String(),
+			Type:        domain.TransactionTypeNormal,
+			AccountID:   account.ID,
+			Amount:      row.Amount,
+			Description: ynabDescription(row),
+			Date:        row.Date,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+
+		other := row.TransferAccount()
+		switch {
+		case other != "":
+			to, ok := im.accounts[other]
+			if !ok {
+				// The other account wasn't exported: money out leaves the budget, money in is income
+				if row.Amount < 0 {
+					transaction.Type = domain.TransactionTypeTransfer
+				}
+				break
+			}
+			if row.Amount > 0 {
+				continue
+			}
+			transaction.Type = domain.TransactionTypeTransfer
+			transaction.TransferToAccountID = &to.ID
+			if payment, ok := im.payments[other]; ok {
+				transaction.CategoryID = &payment
+			}
+			inbound := &domain.Transaction{
+				ID:                  uuid.New().String(),
+				Type:                domain.TransactionTypeTransfer,
+				AccountID:           to.ID,
+				TransferToAccountID: &account.ID,
+				Amount:              -row.Amount,
+				Description:         transaction.Description,
+				Date:                row.Date,
+				CreatedAt:           now,
+				UpdatedAt:           now,
+			}
+			if err := im.transactionRepo.Create(ctx, inbound); err != nil {
+				return err
+			}
+			im.result.Transactions++
+		case row.Payee == ynab.StartingBalancePayee:
+			if account.Type == domain.AccountTypeCredit && row.Amount < 0 {
+				continue
+			}
+			transaction.Type = domain.TransactionTypeStartingBalance
+			transaction.Description = startingBalanceDescription
+		case !row.IsIncome():
+			if id, ok := im.categories[ynabCategory{row.CategoryGroup, row.Category}]; ok {
+				transaction.CategoryID = &id
+			}
+		}
+
+		if err := im.transactionRepo.Create(ctx, transaction); err != nil {
+			return err
+		}
+		im.result.Transactions++
+	}
+	return nil
+}
+
+// createAllocations creates an allocation for each category's assigned amount each month
+func (im *ynabImporter) createAllocations(ctx context.Context, budget []ynab.BudgetRow) error {
+	type key struct{ categoryID, period string }
+	amounts := make(map[key]int64)
+	for _, row := range budget {
+		categoryID, ok := im.categories[ynabCategory{row.CategoryGroup, row.Category}]
+		if !ok || row.Budgeted == 0 {
+			continue
+		}
+		amounts[key{categoryID, row.Month}] += row.Budgeted
+	}
+
+	keys := make([]key, 0, len(amounts))
+	for k := range amounts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].period != keys[j].period {
+			return keys[i].period < keys[j].period
+		}
+		return keys[i].categoryID < keys[j].categoryID
+	})
+
+	now := time.Now()
+	for _, k := range keys {
+		if amounts[k] == 0 {
+			continue
+		}
+		allocation := &domain.Allocation{
+			ID:         uuid.New().String(),
+			CategoryID: k.categoryID,
+			Period:     k.period,
+			Amount:     amounts[k],
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		if err := im.allocationRepo.Create(ctx, allocation); err != nil {
+			return err
+		}
+		im.result.Allocations++
+	}
+	return nil
+}
+
+// ynabDescription is a row's payee, followed by its memo when it has one
+func ynabDescription(row ynab.RegisterRow) string {
+	switch {
+	case row.Memo == "":
+		return row.Payee
+	case row.Payee == "":
+		return row.Memo
+	}
+	return strings.TrimSpace(row.Payee + " - " + row.Memo)
+}
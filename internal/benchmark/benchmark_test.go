@@ -0,0 +1,252 @@
+package benchmark
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/infrastructure/csv"
+	"github.com/billybbuffum/budget/internal/infrastructure/database"
+	"github.com/billybbuffum/budget/internal/infrastructure/ofx"
+	"github.com/billybbuffum/budget/internal/infrastructure/qif"
+	"github.com/billybbuffum/budget/internal/infrastructure/repository"
+)
+
+// importBatch is how many transactions each import benchmark iteration brings in
+const importBatch = 1000
+
+// budget is the slowest each operation may be on the Large dataset
+// Set at roughly twice what the month views take today (about 1.2s each, since they
+// read every transaction) so only real regressions fail. Tighten them when a redesign
+// like SQL aggregation lands.
+var budget = map[string]time.Duration{
+	"budget month":    2500 * time.Millisecond,
+	"summary":         2500 * time.Millisecond,
+	"ready to assign": 2500 * time.Millisecond,
+	"import 1000":     3 * time.Second,
+}
+
+type fixture struct {
+	dataset     *Dataset
+	allocations *application.AllocationService
+	imports     *application.ImportService
+}
+
+var (
+	largeOnce    sync.Once
+	largeDir     string
+	largeFixture *fixture
+	largeErr     error
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if largeDir != "" {
+		os.RemoveAll(largeDir)
+	}
+	os.Exit(code)
+}
+
+// large seeds the Large dataset once per test binary; seeding takes a few seconds
+func large(tb testing.TB) *fixture {
+	tb.Helper()
+	largeOnce.Do(func() {
+		largeDir, largeErr = os.MkdirTemp("", "budget-bench")
+		if largeErr != nil {
+			return
+		}
+		largeFixture, largeErr = newFixture(filepath.Join(largeDir, "bench.db"), Large)
+	})
+	if largeErr != nil {
+		tb.Fatal(largeErr)
+	}
+	return largeFixture
+}
+
+func newFixture(path string, size Size) (*fixture, error) {
+	db, err := database.NewSQLiteDB(path)
+	if err != nil {
+		return nil, err
+	}
+	dataset, err := Seed(db, size)
+	if err != nil {
+		return nil, err
+	}
+	return &fixture{
+		dataset:     dataset,
+		allocations: newAllocationService(db),
+		imports:     newImportService(db),
+	}, nil
+}
+
+func newAllocationService(db *sql.DB) *application.AllocationService {
+	service := application.NewAllocationService(
+		repository.NewAllocationRepository(db),
+		repository.NewCategoryRepository(db),
+		repository.NewTransactionRepository(db),
+		repository.NewBudgetStateRepository(db),
+		repository.NewAccountRepository(db),
+		repository.NewCategoryGroupRepository(db),
+		repository.NewGoalRepository(db),
+	)
+	service.UseReadyToAssignTotals(repository.NewReadyToAssignTotalsRepository(db))
+	return service
+}
+
+func newImportService(db *sql.DB) *application.ImportService {
+	categoryRepo := repository.NewCategoryRepository(db)
+	accountRepo := repository.NewAccountRepository(db)
+	transactionRepo := repository.NewTransactionRepository(db)
+	plugins := application.NewPluginService(categoryRepo, accountRepo, transactionRepo, repository.NewAllocationRepository(db), nil, nil, nil)
+	payees := application.NewPayeeService(repository.NewPayeeRepository(db), repository.NewPayeeRuleRepository(db), accountRepo, categoryRepo)
+	transfers := application.NewTransferHintService(repository.NewTransferHintRepository(db), accountRepo, transactionRepo)
+	return application.NewImportService(transactionRepo, accountRepo, repository.NewBudgetStateRepository(db),
+		repository.NewImportFileRepository(db), repository.NewSettingRepository(db), ofx.NewParser(), csv.NewParser(), qif.NewParser(), plugins, payees, transfers, repository.NewUnitOfWork(db), application.DateBounds{})
+}
+
+// importedBatches numbers import files; the benchmark framework reruns benchmarks as it
+// picks b.N, so each file needs transactions no earlier run has imported
+var importedBatches int
+
+// qifFile builds a QIF file of n transactions that no earlier file has had
+func qifFile(n int) []byte {
+	importedBatches++
+	batch := importedBatches
+	var buf bytes.Buffer
+	buf.WriteString("!Type:Bank\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&buf, "D%s\nT-%d.%02d\nPIMPORTED MERCHANT %d #%d-%d\n^\n",
+			LastPeriod.AddDate(0, 0, i%28).Format("1/2/2006"), 1+i%90, i%100, i%50, batch, i)
+	}
+	return buf.Bytes()
+}
+
+func BenchmarkBudgetMonth(b *testing.B) {
+	f := large(b)
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := f.allocations.GetAllocationGroupSummary(ctx, f.dataset.Period, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAllocationSummary(b *testing.B) {
+	f := large(b)
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := f.allocations.GetAllocationSummary(ctx, f.dataset.Period); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadyToAssign(b *testing.B) {
+	f := large(b)
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := f.allocations.CalculateReadyToAssignForPeriod(ctx, f.dataset.Period); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkImport reports throughput as transactions per second
+func BenchmarkImport(b *testing.B) {
+	f := large(b)
+	ctx := context.Background()
+	files := make([][]byte, b.N)
+	for i := range files {
+		files[i] = qifFile(importBatch)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result, err := f.imports.Import(ctx, f.dataset.AccountIDs[0], bytes.NewReader(files[i]))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if result.ImportedTransactions != importBatch {
+			b.Fatalf("imported %d of %d transactions: %v", result.ImportedTransactions, importBatch, result.Errors)
+		}
+	}
+	b.ReportMetric(float64(b.N*importBatch)/b.Elapsed().Seconds(), "txns/s")
+}
+
+// TestPerformanceBudget checks the key operations against their budgets on the Large dataset
+// Each is timed as the best of a few runs, to keep one slow run from failing the test.
+func TestPerformanceBudget(t *testing.T) {
+	if os.Getenv("BUDGET_PERF") != "1" {
+		t.Skip("set BUDGET_PERF=1 to seed the large dataset and check the performance budget")
+	}
+	f := large(t)
+	ctx := context.Background()
+
+	operations := map[string]func() error{
+		"budget month": func() error {
+			_, err := f.allocations.GetAllocationGroupSummary(ctx, f.dataset.Period, nil)
+			return err
+		},
+		"summary": func() error {
+			_, err := f.allocations.GetAllocationSummary(ctx, f.dataset.Period)
+			return err
+		},
+		"ready to assign": func() error {
+			_, err := f.allocations.CalculateReadyToAssignForPeriod(ctx, f.dataset.Period)
+			return err
+		},
+		"import 1000": func() error {
+			_, err := f.imports.Import(ctx, f.dataset.AccountIDs[0], bytes.NewReader(qifFile(importBatch)))
+			return err
+		},
+	}
+
+	for name, run := range operations {
+		best := time.Duration(1<<63 - 1)
+		for i := 0; i < 3; i++ {
+			start := time.Now()
+			if err := run(); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			best = min(best, time.Since(start))
+		}
+		t.Logf("%s: %v (budget %v)", name, best, budget[name])
+		if best > budget[name] {
+			t.Errorf("%s took %v, over its %v budget", name, best, budget[name])
+		}
+	}
+}
+
+// TestSeed makes sure the seeding the benchmarks rely on keeps working as the schema changes
+func TestSeed(t *testing.T) {
+	f, err := newFixture(filepath.Join(t.TempDir(), "seed.db"), Size{
+		Transactions: 500,
+		Categories:   12,
+		Groups:       3,
+		Accounts:     3,
+		Months:       3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	summaries, err := f.allocations.GetAllocationSummary(context.Background(), f.dataset.Period)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) < 12 {
+		t.Errorf("expected a summary for every seeded category, got %d", len(summaries))
+	}
+	result, err := f.imports.Import(context.Background(), f.dataset.AccountIDs[0], bytes.NewReader(qifFile(10)))
+	if err != nil || result.ImportedTransactions != 10 {
+		t.Errorf("expected the import benchmark's file to import, got %+v, %v", result, err)
+	}
+}
@@ -0,0 +1,162 @@
+// Package benchmark seeds a large budget and measures the endpoints that scale with it.
+//
+// The benchmarks run against a real SQLite database so that changes to queries and
+// indexes show up in the numbers:
+//
+//	go test ./internal/benchmark -bench . -benchtime 20x
+//
+// TestPerformanceBudget fails when a key operation gets slower than its budget. It seeds
+// the full dataset, so it only runs when BUDGET_PERF=1 is set.
+package benchmark
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Size describes how much data to seed
+type Size struct {
+	Transactions int
+	Categories   int
+	Groups       int
+	Accounts     int // The last one or two are credit cards, with payment categories
+	Months       int // Transactions and allocations are spread over this many months
+}
+
+// Large is the dataset the performance budget is measured against
+var Large = Size{
+	Transactions: 100_000,
+	Categories:   200,
+	Groups:       20,
+	Accounts:     6,
+	Months:       24,
+}
+
+// Dataset is what Seed created
+type Dataset struct {
+	Period       string   // Latest budget month with data, YYYY-MM
+	AccountIDs   []string // Checking account first
+	CategoryIDs  []string // Regular categories only
+	Transactions int
+}
+
+// LastPeriod is the newest month seeded; fixed so runs are comparable
+var LastPeriod = time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+// Seed fills an empty database with a budget of the given size
+// Rows are inserted directly in one transaction, since going through the services would
+// take minutes at this size. Values are pseudo-random but the same on every run.
+func Seed(db *sql.DB, size Size) (*Dataset, error) {
+	if size.Accounts < 2 || size.Groups < 1 || size.Categories < size.Groups || size.Months < 1 {
+		return nil, fmt.Errorf("invalid dataset size %+v", size)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rng := rand.New(rand.NewSource(1))
+	now := time.Now()
+	first := LastPeriod.AddDate(0, -(size.Months - 1), 0)
+	dataset := &Dataset{Period: LastPeriod.Format("2006-01"), Transactions: size.Transactions}
+
+	credit := 1 + size.Accounts/4
+	var groupIDs []string
+	for i := 0; i < size.Groups; i++ {
+		id := uuid.New().String()
+		if _, err := tx.Exec(`INSERT INTO category_groups (id, name, description, display_order, created_at, updated_at) VALUES (?, ?, '', ?, ?, ?)`,
+			id, fmt.Sprintf("Group %d", i+1), i, now, now); err != nil {
+			return nil, fmt.Errorf("failed to seed category groups: %w", err)
+		}
+		groupIDs = append(groupIDs, id)
+	}
+
+	balances := make(map[string]int64)
+	for i := 0; i < size.Accounts; i++ {
+		id := uuid.New().String()
+		accountType := "checking"
+		if i >= size.Accounts-credit {
+			accountType = "credit"
+		} else if i > 0 {
+			accountType = "savings"
+		}
+		if _, err := tx.Exec(`INSERT INTO accounts (id, name, balance, type, created_at, updated_at) VALUES (?, ?, 0, ?, ?, ?)`,
+			id, fmt.Sprintf("Account %d", i+1), accountType, now, now); err != nil {
+			return nil, fmt.Errorf("failed to seed accounts: %w", err)
+		}
+		if accountType == "credit" {
+			if _, err := tx.Exec(`INSERT INTO categories (id, name, description, color, group_id, payment_for_account_id, classification, created_at, updated_at) VALUES (?, ?, '', '', ?, ?, 'debt', ?, ?)`,
+				uuid.New().String(), fmt.Sprintf("Account %d Payment", i+1), groupIDs[0], id, now, now); err != nil {
+				return nil, fmt.Errorf("failed to seed payment categories: %w", err)
+			}
+		}
+		dataset.AccountIDs = append(dataset.AccountIDs, id)
+	}
+
+	classifications := []string{"essential", "discretionary", "savings", "debt"}
+	for i := 0; i < size.Categories; i++ {
+		id := uuid.New().String()
+		if _, err := tx.Exec(`INSERT INTO categories (id, name, description, color, group_id, payment_for_account_id, classification, created_at, updated_at) VALUES (?, ?, '', '', ?, NULL, ?, ?, ?)`,
+			id, fmt.Sprintf("Category %d", i+1), groupIDs[i%len(groupIDs)], classifications[i%len(classifications)], now, now); err != nil {
+			return nil, fmt.Errorf("failed to seed categories: %w", err)
+		}
+		dataset.CategoryIDs = append(dataset.CategoryIDs, id)
+	}
+
+	allocate, err := tx.Prepare(`INSERT INTO allocations (id, category_id, amount, period, notes, created_at, updated_at) VALUES (?, ?, ?, ?, '', ?, ?)`)
+	if err != nil {
+		return nil, err
+	}
+	defer allocate.Close()
+	for m := 0; m < size.Months; m++ {
+		period := first.AddDate(0, m, 0).Format("2006-01")
+		for _, categoryID := range dataset.CategoryIDs {
+			if _, err := allocate.Exec(uuid.New().String(), categoryID, 1000+rng.Int63n(50000), period, now, now); err != nil {
+				return nil, fmt.Errorf("failed to seed allocations: %w", err)
+			}
+		}
+	}
+
+	insert, err := tx.Prepare(`INSERT INTO transactions (id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, note, payee_id, created_at, updated_at) VALUES (?, 'normal', ?, NULL, ?, ?, ?, ?, ?, NULL, NULL, ?, ?)`)
+	if err != nil {
+		return nil, err
+	}
+	defer insert.Close()
+	days := int(LastPeriod.AddDate(0, 1, 0).Sub(first).Hours() / 24)
+	for i := 0; i < size.Transactions; i++ {
+		accountID := dataset.AccountIDs[rng.Intn(len(dataset.AccountIDs))]
+		date := first.AddDate(0, 0, rng.Intn(days)).UTC()
+
+		// Roughly one in ten is income; the rest is categorized spending
+		var categoryID *string
+		amount := 100_000 + rng.Int63n(400_000)
+		description := "Payroll"
+		if rng.Intn(10) > 0 {
+			categoryID = &dataset.CategoryIDs[rng.Intn(len(dataset.CategoryIDs))]
+			amount = -(100 + rng.Int63n(20_000))
+			description = fmt.Sprintf("Merchant %d", rng.Intn(500))
+		}
+		if _, err := insert.Exec(uuid.New().String(), accountID, categoryID, amount, description, date,
+			fmt.Sprintf("seed-%d", i), now, now); err != nil {
+			return nil, fmt.Errorf("failed to seed transactions: %w", err)
+		}
+		balances[accountID] += amount
+	}
+
+	for id, balance := range balances {
+		if _, err := tx.Exec(`UPDATE accounts SET balance = ? WHERE id = ?`, balance, id); err != nil {
+			return nil, fmt.Errorf("failed to set account balances: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return dataset, nil
+}
@@ -9,15 +9,49 @@ const (
 	AccountTypeChecking AccountType = "checking"
 	AccountTypeSavings  AccountType = "savings"
 	AccountTypeCash     AccountType = "cash"
-	AccountTypeCredit   AccountType = "credit" // Credit cards - negative balance = debt
+	AccountTypeCredit   AccountType = "credit"   // Credit cards - negative balance = debt
+	AccountTypeLoan     AccountType = "loan"     // Mortgages, car loans - negative balance = principal owed
+	AccountTypeTracking AccountType = "tracking" // Off budget: brokerage, retirement - tracked for net worth only
 )
 
+// IsDebt reports whether accounts of this type hold debt
+// A debt account's opening balance isn't income, so it's kept on the balance without a
+// starting balance transaction.
+func (t AccountType) IsDebt() bool {
+	return t == AccountTypeCredit || t == AccountTypeLoan
+}
+
+// IsOffBudget reports whether accounts of this type are kept out of the budget
+// An off-budget account's balance changes are adjustments: they count towards net worth
+// but never towards Ready to Assign or a category.
+func (t AccountType) IsOffBudget() bool {
+	return t == AccountTypeTracking
+}
+
 // Account represents a financial account that holds money
 type Account struct {
-	ID        string      `json:"id"`
-	Name      string      `json:"name"`
-	Balance   int64       `json:"balance"`    // Balance in cents
-	Type      AccountType `json:"type"`
-	CreatedAt time.Time   `json:"created_at"`
-	UpdatedAt time.Time   `json:"updated_at"`
+	ID                  string         `json:"id"`
+	Name                string         `json:"name"`
+	Balance             int64          `json:"balance"` // Balance in cents
+	Type                AccountType    `json:"type"`
+	RewardsBalance      int64          `json:"rewards_balance"` // Unredeemed credit card rewards in cents; not part of Balance or the budget
+	Details             AccountDetails `json:"details"`
+	HiddenFromDashboard bool           `json:"hidden_from_dashboard"` // Left out of the dashboard and summaries, but still listed and reported on
+	Loan                *LoanTerms     `json:"loan,omitempty"`        // How a loan is repaid; nil until set, and for other accounts
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+}
+
+// AccountDetails is reference information kept with an account; none of it affects the budget
+type AccountDetails struct {
+	Notes              string `json:"notes"`
+	AccountNumberLast4 string `json:"account_number_last4,omitempty"` // Full account numbers are never stored
+	InterestRateBps    *int64 `json:"interest_rate_bps,omitempty"`    // APR in basis points, e.g. 1999 for 19.99%
+}
+
+// LoanTerms are how a loan account is repaid
+// The interest rate is the account's Details.InterestRateBps.
+type LoanTerms struct {
+	MonthlyPayment     int64   `json:"monthly_payment"`                // Scheduled payment in cents, interest included
+	InterestCategoryID *string `json:"interest_category_id,omitempty"` // Category interest is spent from
 }
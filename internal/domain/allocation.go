@@ -21,4 +21,71 @@ type AllocationSummary struct {
 	Available            int64       `json:"available"`             // Allocated + Activity (Activity is negative)
 	Underfunded          *int64      `json:"underfunded"`           // For payment categories: amount needed to cover CC balance (nil if not underfunded)
 	UnderfundedCategories []string    `json:"underfunded_categories"` // For payment categories: list of category names that need more allocation
+	QuickBudget          *QuickBudgetAmounts `json:"quick_budget,omitempty"` // Filled in by the period summaries
+	Goal                 *GoalProgress       `json:"goal,omitempty"`         // Filled in by the period summaries for categories with a goal
+	Status               CategoryStatus      `json:"status"`
+	Errors               []string            `json:"errors,omitempty"` // Parts that couldn't be calculated, e.g. SummaryPartGoal; they're left out rather than guessed
+}
+
+// Parts of a period summary that are left out, rather than failing the summary, when they can't be read
+const (
+	SummaryPartQuickBudget   = "quick_budget"
+	SummaryPartGoal          = "goal"
+	SummaryPartReadyToAssign = "ready_to_assign"
+)
+
+// CategoryStatus classifies a category's funding in a period, so every client shows it the same way
+type CategoryStatus string
+
+const (
+	CategoryStatusFullyFunded     CategoryStatus = "fully_funded"     // Has what its goal needs this period
+	CategoryStatusPartiallyFunded CategoryStatus = "partially_funded" // Short of what its goal needs this period
+	CategoryStatusOverspentCash   CategoryStatus = "overspent_cash"   // Overspent; at least part of it comes out of next month's Ready to Assign
+	CategoryStatusOverspentCredit CategoryStatus = "overspent_credit" // Overspent only on credit cards; the card's payment category is short
+	CategoryStatusNoGoal          CategoryStatus = "no_goal"          // Not overspent and has no goal to measure funding against
+)
+
+// UngroupedCategoriesID stands in for the group ID of categories that aren't in a group
+const UngroupedCategoriesID = "ungrouped"
+
+// AllocationGroupSummary rolls up the allocation summaries of a category group
+// Categories holds the per-category detail, and is only filled in for expanded groups.
+type AllocationGroupSummary struct {
+	GroupID        string               `json:"group_id"` // UngroupedCategoriesID for categories without a group
+	Name           string               `json:"name"`
+	DisplayOrder   int                  `json:"display_order"`
+	Assigned       int64                `json:"assigned"`  // Allocated this period
+	Activity       int64                `json:"activity"`  // Sum of this period's transactions
+	Available      int64                `json:"available"` // Includes rollover from previous periods
+	CategoryCount  int                  `json:"category_count"`
+	OverspentCount int                  `json:"overspent_count"` // Categories with negative available
+	Expanded       bool                 `json:"expanded"`
+	Categories     []*AllocationSummary `json:"categories,omitempty"`
+}
+
+// SpendingAverages is a category's average monthly spending over the months before a period
+// Amounts are positive cents; refunds in the same months are netted out.
+type SpendingAverages struct {
+	ThreeMonths  int64 `json:"three_months"`
+	SixMonths    int64 `json:"six_months"`
+	TwelveMonths int64 `json:"twelve_months"`
+}
+
+// QuickBudgetAmounts are the suggested amounts behind the inspector's quick-budget buttons
+type QuickBudgetAmounts struct {
+	BudgetedLastMonth int64 `json:"budgeted_last_month"`
+	SpentLastMonth    int64 `json:"spent_last_month"`
+	AverageSpent      int64 `json:"average_spent"` // Three-month average
+}
+
+// CategoryInspector gathers everything the budget inspector panel shows for one category
+type CategoryInspector struct {
+	Period             string             `json:"period"`
+	Category           *Category          `json:"category"`
+	Allocation         *Allocation        `json:"allocation"` // nil when nothing is assigned this period
+	Activity           int64              `json:"activity"`
+	Available          int64              `json:"available"`
+	AverageSpent       SpendingAverages   `json:"average_spent"`
+	QuickBudget        QuickBudgetAmounts `json:"quick_budget"`
+	RecentTransactions []*Transaction     `json:"recent_transactions"` // Newest first
 }
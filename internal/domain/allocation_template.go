@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// AllocationTemplate is a named budget plan, such as what each paycheck is split into
+// Applying it to a period assigns each item's amount to its category.
+type AllocationTemplate struct {
+	ID        string                   `json:"id"`
+	Name      string                   `json:"name"`
+	Items     []AllocationTemplateItem `json:"items"`
+	CreatedAt time.Time                `json:"created_at"`
+	UpdatedAt time.Time                `json:"updated_at"`
+}
+
+// AllocationTemplateItem is the amount a template assigns to one category
+type AllocationTemplateItem struct {
+	CategoryID string `json:"category_id"`
+	Amount     int64  `json:"amount"` // In cents
+}
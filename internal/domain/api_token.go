@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// TokenAccess is the level of access an API token grants
+// Each level includes the ones below it: admin > write > read.
+type TokenAccess string
+
+const (
+	TokenAccessRead  TokenAccess = "read"  // GET requests only
+	TokenAccessWrite TokenAccess = "write" // Read plus creating/updating/deleting budget data
+	TokenAccessAdmin TokenAccess = "admin" // Write plus managing API tokens
+)
+
+// IsValid reports whether the access level is a known value
+func (a TokenAccess) IsValid() bool {
+	switch a {
+	case TokenAccessRead, TokenAccessWrite, TokenAccessAdmin:
+		return true
+	}
+	return false
+}
+
+// Includes reports whether a grants at least the access of other
+func (a TokenAccess) Includes(other TokenAccess) bool {
+	rank := map[TokenAccess]int{TokenAccessRead: 1, TokenAccessWrite: 2, TokenAccessAdmin: 3}
+	return rank[a] >= rank[other]
+}
+
+// APIToken authenticates an integration against the API
+// Only a hash of the token is stored; the token itself is shown once at creation.
+// A token with AccountIDs or CategoryIDs is "scoped": it may only use endpoints
+// whose target account/category can be checked against those lists.
+type APIToken struct {
+	ID          string      `json:"id"`
+	UserID      *string     `json:"user_id,omitempty"` // User who created the token, if created while signed in
+	Name        string      `json:"name"`
+	TokenPrefix string      `json:"token_prefix"` // First characters of the token, to help identify it
+	TokenHash   string      `json:"-"`
+	Access      TokenAccess `json:"access"`
+	AccountIDs  []string    `json:"account_ids"`  // Empty = all accounts
+	CategoryIDs []string    `json:"category_ids"` // Empty = all categories
+	ExpiresAt   *time.Time  `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time  `json:"last_used_at,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+
+	// Guardrails against runaway integrations
+	DailyWriteQuota int        `json:"daily_write_quota"`     // Writes allowed per UTC day; 0 = the instance default
+	WriteCount      int        `json:"write_count"`           // Writes made on WriteCountDate
+	WriteCountDate  string     `json:"write_count_date"`      // YYYY-MM-DD (UTC) of the latest write
+	DisabledAt      *time.Time `json:"disabled_at,omitempty"` // Set by an admin to switch the token off
+}
+
+// IsScoped reports whether the token is restricted to specific accounts or categories
+func (t *APIToken) IsScoped() bool {
+	return len(t.AccountIDs) > 0 || len(t.CategoryIDs) > 0
+}
+
+// AllowsAccount reports whether the token may act on the account
+func (t *APIToken) AllowsAccount(accountID string) bool {
+	return len(t.AccountIDs) == 0 || slices.Contains(t.AccountIDs, accountID)
+}
+
+// AllowsCategory reports whether the token may act on the category
+func (t *APIToken) AllowsCategory(categoryID string) bool {
+	return len(t.CategoryIDs) == 0 || slices.Contains(t.CategoryIDs, categoryID)
+}
+
+// IsDisabled reports whether an admin has switched the token off
+func (t *APIToken) IsDisabled() bool {
+	return t.DisabledAt != nil
+}
+
+// IsExpired reports whether the token has passed its expiry time
+func (t *APIToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && now.After(*t.ExpiresAt)
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// AuditEntry records one change made through the API
+// Entries are numbered in the order they were written; the ID doubles as the
+// pagination cursor.
+type AuditEntry struct {
+	ID        int64     `json:"id"`
+	UserID    *string   `json:"user_id,omitempty"`  // Signed-in user, or the token's owner
+	TokenID   *string   `json:"token_id,omitempty"` // API token used, if any
+	Actor     string    `json:"actor"`              // Email or token name, for display; "anonymous" without a credential
+	Method    string    `json:"method"`
+	Route     string    `json:"route"` // Matched route pattern, e.g. "PUT /api/accounts/{id}"
+	Path      string    `json:"path"`
+	Status    int       `json:"status"`
+	IPAddress string    `json:"ip_address"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AuditQuery selects a page of audit entries, newest first
+type AuditQuery struct {
+	BeforeID int64      // Only entries older than this ID; 0 starts from the newest
+	Since    *time.Time // Inclusive
+	Until    *time.Time // Exclusive
+	Limit    int
+}
@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// Bank aggregators accounts can be synced through
+const (
+	BankAggregatorSimpleFIN = "simplefin" // SimpleFIN Bridge, https://beta-bridge.simplefin.org
+)
+
+// BankAggregation is a connection to a bank aggregator, through which accounts at any
+// number of banks are synced
+// The aggregator's access credentials are only kept sealed in Credentials, which never
+// leaves the server.
+type BankAggregation struct {
+	ID           string     `json:"id"`
+	Provider     string     `json:"provider"` // One of the BankAggregator providers
+	Name         string     `json:"name"`
+	Credentials  []byte     `json:"-"` // Sealed credentials the provider fetches with
+	LastSyncedAt *time.Time `json:"last_synced_at,omitempty"`
+	LastError    string     `json:"last_error,omitempty"` // Why the last sync failed, or what the aggregator asked to show
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// BankAggregationAccount is an account an aggregator reports, as of its last sync
+// Only accounts linked to one of the budget's accounts have their transactions imported.
+type BankAggregationAccount struct {
+	AggregationID string    `json:"aggregation_id"`
+	RemoteID      string    `json:"remote_id"` // The aggregator's ID for the account
+	Name          string    `json:"name"`
+	Institution   string    `json:"institution,omitempty"`
+	Currency      string    `json:"currency,omitempty"`
+	Balance       int64     `json:"balance"`
+	AccountID     *string   `json:"account_id,omitempty"` // Budget account it syncs into; nil when unlinked
+	LastError     string    `json:"last_error,omitempty"` // Why its last import failed
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// AggregatedAccount is an account as an aggregator reports it, with its transactions
+// posted since the time asked for
+type AggregatedAccount struct {
+	ID           string
+	Name         string
+	Institution  string
+	Currency     string
+	Balance      int64
+	Transactions []AggregatedTransaction
+}
+
+// AggregatedTransaction is a posted transaction an aggregator reports
+type AggregatedTransaction struct {
+	ID          string // Stable across syncs, so it's imported once
+	Date        time.Time
+	Amount      int64
+	Description string
+}
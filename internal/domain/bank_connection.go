@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// Account types of an OFX DirectConnect statement request
+const (
+	BankAccountChecking   = "CHECKING"
+	BankAccountSavings    = "SAVINGS"
+	BankAccountMoneyMrkt  = "MONEYMRKT"
+	BankAccountCreditLine = "CREDITLINE"
+	BankAccountCreditCard = "CREDITCARD" // Requested as a credit card statement, without a bank ID
+)
+
+// BankConnection is how an account's statements are downloaded from its bank's OFX
+// DirectConnect server
+// The password and full account number are only kept sealed in Credentials, which never
+// leaves the server.
+type BankConnection struct {
+	AccountID          string     `json:"account_id"`        // One connection per account
+	URL                string     `json:"url"`               // The bank's OFX server, e.g. https://ofx.example.com/ofx
+	Org                string     `json:"org"`               // FI>ORG the bank expects
+	FID                string     `json:"fid"`               // FI>FID the bank expects
+	BankID             string     `json:"bank_id,omitempty"` // Routing number; not used for credit cards
+	AccountType        string     `json:"account_type"`      // One of the BankAccount types
+	Username           string     `json:"username"`
+	AccountNumberLast4 string     `json:"account_number_last4"`
+	AppID              string     `json:"app_id,omitempty"` // Client the bank is told it's talking to; some only answer known ones
+	AppVer             string     `json:"app_ver,omitempty"`
+	OFXVersion         string     `json:"ofx_version,omitempty"` // e.g. "102" for banks that only speak SGML OFX
+	Credentials        []byte     `json:"-"`                     // Sealed BankCredentials
+	LastSyncedAt       *time.Time `json:"last_synced_at,omitempty"`
+	LastError          string     `json:"last_error,omitempty"` // Why the last sync failed; empty once one succeeds
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// BankCredentials are the secrets a BankConnection signs on with
+type BankCredentials struct {
+	Password      string `json:"password"`
+	AccountNumber string `json:"account_number"`
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// BotPlatform identifies the chat platform a bot chat belongs to
+type BotPlatform string
+
+const (
+	BotPlatformTelegram BotPlatform = "telegram"
+	BotPlatformDiscord  BotPlatform = "discord"
+	BotPlatformAPI      BotPlatform = "api" // Any other client calling the bot API directly
+)
+
+// IsValid reports whether the platform is a known value
+func (p BotPlatform) IsValid() bool {
+	switch p {
+	case BotPlatformTelegram, BotPlatformDiscord, BotPlatformAPI:
+		return true
+	}
+	return false
+}
+
+// BotChat is a chat authorized to talk to the budget through the bot API
+// Each chat has its own token; only a hash of the token is stored.
+type BotChat struct {
+	ID               string      `json:"id"`
+	Platform         BotPlatform `json:"platform"`
+	ChatID           string      `json:"chat_id,omitempty"` // Platform chat ID, set when a chat links itself with its token
+	Name             string      `json:"name"`
+	TokenHash        string      `json:"-"`
+	DefaultAccountID *string     `json:"default_account_id,omitempty"` // Account used by quick-add when none is named
+	DailySummary     bool        `json:"daily_summary"`                // Send a daily summary (built-in bots only)
+	LastUsedAt       *time.Time  `json:"last_used_at,omitempty"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+}
@@ -7,5 +7,15 @@ import "time"
 type BudgetState struct {
 	ID            string    `json:"id"`
 	ReadyToAssign int64     `json:"ready_to_assign"` // Amount available to allocate (in cents)
+	Revision      int64     `json:"revision"`        // Changes whenever transactions, allocations, categories or accounts do
 	UpdatedAt     time.Time `json:"updated_at"`
 }
+
+// ReadyToAssignTotal is one month's income and assigned money, as counted in Ready to Assign
+// Allocations to credit card payment categories aren't included; they move money that was
+// already assigned.
+type ReadyToAssignTotal struct {
+	Period   string `json:"period"`   // YYYY-MM
+	Inflows  int64  `json:"inflows"`  // Income, excluding transfers and balance adjustments
+	Assigned int64  `json:"assigned"` // Allocations, excluding payment categories
+}
@@ -11,17 +11,58 @@ const (
 	CategoryTypeExpense CategoryType = "expense"
 )
 
+// CategoryClassification describes how necessary spending in a category is
+// Used by reports to separate must-pay spending from optional spending
+type CategoryClassification string
+
+const (
+	ClassificationEssential     CategoryClassification = "essential"     // Rent, utilities, groceries - needed to get by
+	ClassificationDiscretionary CategoryClassification = "discretionary" // Dining out, entertainment - can be cut
+	ClassificationSavings       CategoryClassification = "savings"       // Emergency fund, sinking funds
+	ClassificationDebt          CategoryClassification = "debt"          // Credit card payments, loans
+)
+
+// IsValid reports whether the classification is one of the known values
+func (c CategoryClassification) IsValid() bool {
+	switch c {
+	case ClassificationEssential, ClassificationDiscretionary, ClassificationSavings, ClassificationDebt:
+		return true
+	}
+	return false
+}
+
+// OverspendingMode says where a category's overspending goes when the month ends
+// Either way the category starts the next month at zero.
+type OverspendingMode string
+
+const (
+	OverspendingAuto   OverspendingMode = "auto"   // Split by how it was spent: cash comes out of Ready to Assign, credit stays on the card
+	OverspendingCash   OverspendingMode = "cash"   // All of it comes out of next month's Ready to Assign
+	OverspendingCredit OverspendingMode = "credit" // All of it rolls into the card's payment category as debt to cover
+)
+
+// IsValid reports whether the overspending mode is one of the known values
+func (m OverspendingMode) IsValid() bool {
+	switch m {
+	case OverspendingAuto, OverspendingCash, OverspendingCredit:
+		return true
+	}
+	return false
+}
+
 // Category represents a budget category for spending tracking and budgeting
 // All categories can receive budget allocations
 // Inflow transactions don't require a category - they just increase Ready to Assign
 // Payment categories are automatically created for credit card accounts
 type Category struct {
-	ID                  string    `json:"id"`
-	Name                string    `json:"name"`
-	Description         string    `json:"description"`
-	Color               string    `json:"color"`                                    // Hex color for UI
-	GroupID             *string   `json:"group_id,omitempty"`                       // Optional reference to category group
-	PaymentForAccountID *string   `json:"payment_for_account_id,omitempty"`         // If set, this is a payment category for a credit card
-	CreatedAt           time.Time `json:"created_at"`
-	UpdatedAt           time.Time `json:"updated_at"`
+	ID                  string                 `json:"id"`
+	Name                string                 `json:"name"`
+	Description         string                 `json:"description"`
+	Color               string                 `json:"color"`                            // Hex color for UI
+	GroupID             *string                `json:"group_id,omitempty"`               // Optional reference to category group
+	PaymentForAccountID *string                `json:"payment_for_account_id,omitempty"` // If set, this is a payment category for a credit card
+	Classification      CategoryClassification `json:"classification"`                   // essential, discretionary, savings, or debt
+	OverspendingMode    OverspendingMode       `json:"overspending_mode"`                // auto, cash or credit
+	CreatedAt           time.Time              `json:"created_at"`
+	UpdatedAt           time.Time              `json:"updated_at"`
 }
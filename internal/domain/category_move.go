@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// Reasons money is moved into a credit card's payment category
+const (
+	CategoryMoveSpending = "spending" // Budgeted card spending; the card now owes what the category had set aside
+	CategoryMoveCover    = "cover"    // Covering an underfunded payment category
+)
+
+// CategoryMove is an entry in the ledger of money moved into a credit card's payment category
+// When FromCategoryID is set, the money was budgeted in that category and the card spending
+// there has already taken it out, so the move only adds to ToCategoryID. A move without
+// one comes out of Ready to Assign.
+type CategoryMove struct {
+	ID             string    `json:"id"`
+	FromCategoryID *string   `json:"from_category_id,omitempty"`
+	ToCategoryID   string    `json:"to_category_id"`           // The payment category
+	Period         string    `json:"period"`                   // Format: YYYY-MM
+	Amount         int64     `json:"amount"`                   // Always positive
+	Reason         string    `json:"reason"`                   // One of the CategoryMove reasons
+	TransactionID  *string   `json:"transaction_id,omitempty"` // The card transaction a spending move is for; deleted with it
+	CreatedAt      time.Time `json:"created_at"`
+}
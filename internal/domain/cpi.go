@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// CPIEntry is a consumer price index value for a single month
+// Used to restate historical amounts in real (inflation-adjusted) terms
+type CPIEntry struct {
+	Period    string    `json:"period"` // Format: YYYY-MM
+	Value     float64   `json:"value"`  // Index value (e.g., 310.326 for CPI-U)
+	Source    string    `json:"source"` // "manual" or the provider name
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -13,6 +13,99 @@ var (
 	// ErrNotUnderfunded indicates the payment category is not underfunded
 	ErrNotUnderfunded = errors.New("payment category is not underfunded")
 
+	// ErrInvalidPeriod indicates a period key in none of the supported formats
+	ErrInvalidPeriod = errors.New("invalid period format, expected YYYY-MM, YYYY-Www or YYYY-Qn")
+
 	// ErrCategoryNotFound indicates the category doesn't exist
 	ErrCategoryNotFound = errors.New("category not found")
+
+	// ErrNotOverspent indicates the category has no overspending to cover
+	ErrNotOverspent = errors.New("category is not overspent")
+
+	// ErrPaymentCategory indicates a payment category was used where it isn't allowed
+	ErrPaymentCategory = errors.New("payment categories can't be used here; use cover-underfunded instead")
+
+	// ErrInsufficientCategoryFunds indicates a donor category doesn't have enough available
+	ErrInsufficientCategoryFunds = errors.New("insufficient funds in donor category")
+
+	// ErrAccountNotFound indicates the account doesn't exist
+	ErrAccountNotFound = errors.New("account not found")
+
+	// ErrNotCreditAccount indicates a credit card account was required
+	ErrNotCreditAccount = errors.New("account is not a credit card")
+
+	// ErrGoalNotFound indicates the goal doesn't exist
+	ErrGoalNotFound = errors.New("goal not found")
+
+	// ErrGoalExists indicates the category already has a goal
+	ErrGoalExists = errors.New("category already has a goal")
+
+	// ErrPayeeNotFound indicates the payee doesn't exist
+	ErrPayeeNotFound = errors.New("payee not found")
+
+	// ErrPayeeRuleNotFound indicates the payee rule doesn't exist
+	ErrPayeeRuleNotFound = errors.New("payee rule not found")
+
+	// ErrAllocationTemplateNotFound indicates the allocation template doesn't exist
+	ErrAllocationTemplateNotFound = errors.New("allocation template not found")
+
+	// ErrAllocationTemplateExists indicates another allocation template has the name
+	ErrAllocationTemplateExists = errors.New("an allocation template with this name already exists")
+
+	// ErrTransferHintNotFound indicates the transfer hint doesn't exist
+	ErrTransferHintNotFound = errors.New("transfer hint not found")
+
+	// ErrTransferSuggestionNotFound indicates the transfer suggestion doesn't exist
+	ErrTransferSuggestionNotFound = errors.New("transfer suggestion not found")
+
+	// ErrTransferSuggestionStale indicates a suggestion's transactions changed after it was made
+	ErrTransferSuggestionStale = errors.New("the suggested transactions no longer look like a transfer")
+
+	// ErrTransferPairingNotFound indicates the transfer pairing doesn't exist
+	ErrTransferPairingNotFound = errors.New("transfer pairing not found")
+
+	// ErrUserNotFound indicates the user doesn't exist
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrBankConnectionNotFound indicates the account has no bank connection
+	ErrBankConnectionNotFound = errors.New("bank connection not found")
+
+	// ErrBankAggregationNotFound indicates the bank aggregation doesn't exist
+	ErrBankAggregationNotFound = errors.New("bank aggregation not found")
+
+	// ErrBankAggregationAccountNotFound indicates the aggregator doesn't report the account
+	ErrBankAggregationAccountNotFound = errors.New("bank aggregation account not found")
+
+	// ErrWidgetNotFound indicates the widget doesn't exist, or its token is wrong
+	ErrWidgetNotFound = errors.New("widget not found")
+
+	// ErrImportNotFound indicates the import doesn't exist
+	ErrImportNotFound = errors.New("import not found")
+
+	// ErrImportRowNotFound indicates the row isn't part of the import
+	ErrImportRowNotFound = errors.New("import row not found")
+
+	// ErrImportNotStaged indicates an import was changed after it left review
+	ErrImportNotStaged = errors.New("import is no longer waiting for review")
+
+	// ErrWriteQuotaExceeded indicates an API token has used up its writes for the day
+	ErrWriteQuotaExceeded = errors.New("daily write quota exceeded for this API token")
+
+	// ErrNotLoanAccount indicates a loan operation was attempted on another kind of account
+	ErrNotLoanAccount = errors.New("account is not a loan")
+
+	// ErrLoanTermsNotSet indicates a loan has no monthly payment to schedule
+	ErrLoanTermsNotSet = errors.New("loan has no monthly payment set")
+
+	// ErrLoanNeverRepaid indicates a loan's monthly payment doesn't cover its interest
+	ErrLoanNeverRepaid = errors.New("monthly payment doesn't cover the interest, so the loan would never be repaid")
+
+	// ErrOffBudgetAccount indicates budget activity on an off-budget tracking account
+	ErrOffBudgetAccount = errors.New("tracking accounts are off budget; update their balance instead")
+
+	// ErrNotTrackingAccount indicates a tracking account was required
+	ErrNotTrackingAccount = errors.New("account is not a tracking account")
+
+	// ErrJobNotFound indicates the background job doesn't exist, or finished long ago
+	ErrJobNotFound = errors.New("job not found")
 )
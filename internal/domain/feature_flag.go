@@ -0,0 +1,27 @@
+package domain
+
+// FeatureFlag names a feature that can be switched on or off at runtime
+// Experimental features ship behind a flag, disabled by default.
+type FeatureFlag string
+
+const (
+	FeatureBankSync           FeatureFlag = "bank_sync"
+	FeatureAutoAcceptMatching FeatureFlag = "auto_accept_matching"
+	FeatureSandboxMode        FeatureFlag = "sandbox_mode"
+)
+
+// FeatureFlags lists every known flag with a short description
+var FeatureFlags = map[FeatureFlag]string{
+	FeatureBankSync:           "Pull transactions directly from banks",
+	FeatureAutoAcceptMatching: "Approve imports that match an existing transaction without review",
+	FeatureSandboxMode:        "Try changes against a throwaway copy of the budget",
+}
+
+// IsValid reports whether the flag is known
+func (f FeatureFlag) IsValid() bool {
+	_, ok := FeatureFlags[f]
+	return ok
+}
+
+// SettingKeyFeatureFlags stores the runtime overrides, a JSON object of flag name to enabled
+const SettingKeyFeatureFlags = "feature_flags"
@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// GoalCadence says how often a goal's target comes due
+type GoalCadence string
+
+const (
+	GoalCadenceOnce    GoalCadence = "once"    // Save up to the target, by TargetDate if there is one
+	GoalCadenceMonthly GoalCadence = "monthly" // Assign the target every month
+	GoalCadenceYearly  GoalCadence = "yearly"  // Have the target saved by TargetDate's month every year
+)
+
+// Goal is a funding target for a category
+// A category has at most one goal. Examples: save $1,200 for a vacation by June,
+// assign $400 to groceries every month, have $900 ready for insurance every March.
+type Goal struct {
+	ID           string      `json:"id"`
+	CategoryID   string      `json:"category_id"`
+	TargetAmount int64       `json:"target_amount"`         // In cents
+	TargetDate   *string     `json:"target_date,omitempty"` // Format: YYYY-MM; required for yearly goals
+	Cadence      GoalCadence `json:"cadence"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+// GoalProgress is how far a category is towards its goal in a period
+type GoalProgress struct {
+	Goal            *Goal  `json:"goal"`
+	DuePeriod       string `json:"due_period,omitempty"` // Month the current target is due; empty for undated goals
+	Funded          int64  `json:"funded"`               // Towards the current target
+	Remaining       int64  `json:"remaining"`
+	PercentComplete int    `json:"percent_complete"` // 0-100
+
+	// SuggestedMonthly is what to assign each month, starting with this one, to hit the target
+	SuggestedMonthly int64 `json:"suggested_monthly"`
+	NeededThisPeriod int64 `json:"needed_this_period"` // SuggestedMonthly less what's already assigned
+	OnTrack          bool  `json:"on_track"`
+}
@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+// Statuses of an ImportFile
+const (
+	ImportStatusStaged    = "staged"    // Parsed and waiting for review; nothing is in the ledger yet
+	ImportStatusCommitted = "committed" // Saved to the ledger
+	ImportStatusUndone    = "undone"    // Committed, then taken back out of the ledger
+)
+
+// ImportFile records a file imported into an account
+// The fingerprint is a hash of the file's contents (and, for CSV, the column mapping), so
+// uploading the same file again can be recognized without looking at its transactions.
+// Transactions saved from the file link back to it through their ImportID.
+type ImportFile struct {
+	ID                   string     `json:"id"`
+	AccountID            string     `json:"account_id"`
+	Fingerprint          string     `json:"fingerprint"`              // Hex SHA-256
+	Format               string     `json:"format"`                   // ofx, qif or csv
+	Status               string     `json:"status"`                   // staged, committed or undone
+	LedgerBalance        *int64     `json:"ledger_balance,omitempty"` // Statement balance the account is set to on commit; nil moves it by the imported amounts
+	BalanceChange        int64      `json:"balance_change"`           // How far committing moved the account balance; undoing moves it back
+	TotalTransactions    int        `json:"total_transactions"`
+	ImportedTransactions int        `json:"imported_transactions"`
+	SkippedDuplicates    int        `json:"skipped_duplicates"`
+	CreatedAt            time.Time  `json:"created_at"`
+	CommittedAt          *time.Time `json:"committed_at,omitempty"`
+}
+
+// ImportFileRow is one transaction read from an imported file, and what became of it
+// Staged rows can be recategorized or excluded before the import is committed; committed
+// rows record the transactions they created, so the import can be undone.
+type ImportFileRow struct {
+	ID          string    `json:"id"`
+	ImportID    string    `json:"import_id"`
+	Position    int       `json:"position"` // Order in the file, from 0
+	FitID       string    `json:"fitid"`
+	Date        time.Time `json:"date"`
+	Amount      int64     `json:"amount"`
+	Description string    `json:"description"`
+	CategoryID  *string   `json:"category_id,omitempty"`
+	PayeeID     *string   `json:"payee_id,omitempty"`
+	Note        *string   `json:"note,omitempty"` // Memo from a payee rule
+	Status      string    `json:"status"`         // pending while staged, then new, duplicate, transfer or error
+	Excluded    bool      `json:"excluded"`       // Left out when the import is committed
+	Error       string    `json:"error,omitempty"`
+	Warning     string    `json:"warning,omitempty"`
+	// The saved transaction, and for transfers the other side and whether the import created it
+	TransactionID         string `json:"transaction_id,omitempty"`
+	TransferAccountID     string `json:"transfer_account_id,omitempty"`
+	TransferTransactionID string `json:"transfer_transaction_id,omitempty"`
+	PlaceholderCreated    bool   `json:"placeholder_created,omitempty"`
+	// What the bank charged before converting, for foreign-currency transactions
+	OriginalCurrency *string `json:"original_currency,omitempty"`
+	OriginalAmount   *int64  `json:"original_amount,omitempty"`
+}
@@ -0,0 +1,7 @@
+package domain
+
+// DatabaseSize is how big the database file is and how much of it is unused
+type DatabaseSize struct {
+	Bytes     int64 `json:"bytes"`
+	FreeBytes int64 `json:"free_bytes"` // Pages freed by deletes, reused before the file grows; VACUUM returns them
+}
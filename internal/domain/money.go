@@ -0,0 +1,294 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency the budget's amounts are kept in unless configured otherwise
+const DefaultCurrency = "USD"
+
+// maxDecimals is the most decimal places a currency can be kept with
+const maxDecimals = 4
+
+// ErrCurrencyMismatch is returned when combining amounts in different currencies
+var ErrCurrencyMismatch = errors.New("amounts are in different currencies")
+
+// currencySymbols are written before amounts in these currencies; others get their code after
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"CAD": "$",
+	"AUD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+	"KRW": "₩",
+	"INR": "₹",
+}
+
+// currencyDecimals are the ISO 4217 currencies that don't have two decimal places
+var currencyDecimals = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// Currency is how amounts in a currency are kept and written
+type Currency struct {
+	Code     string `json:"code"` // ISO 4217 code
+	Symbol   string `json:"symbol,omitempty"`
+	Decimals int    `json:"decimals"` // Digits after the decimal point; a unit is 10^Decimals minor units
+}
+
+// budgetCurrency is the currency the budget's amounts are kept in; see SetBudgetCurrency
+var budgetCurrency = standardCurrency(DefaultCurrency)
+
+// SetBudgetCurrency sets the currency the budget's amounts are kept in, at startup
+// decimals overrides the currency's standard number of decimal places; -1 keeps it.
+// Amounts are stored in the currency's minor units, so changing it once amounts have
+// been entered changes what they are worth.
+func SetBudgetCurrency(code string, decimals int) error {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 || strings.Trim(code, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return fmt.Errorf("currency must be a three-letter ISO 4217 code, got %q", code)
+	}
+	if decimals < -1 || decimals > maxDecimals {
+		return fmt.Errorf("currency decimals must be between 0 and %d", maxDecimals)
+	}
+
+	currency := standardCurrency(code)
+	if decimals >= 0 {
+		currency.Decimals = decimals
+	}
+	budgetCurrency = currency
+	return nil
+}
+
+// BudgetCurrency returns the currency the budget's amounts are kept in
+func BudgetCurrency() Currency {
+	return budgetCurrency
+}
+
+// LookupCurrency returns how amounts in a currency are kept and written
+// The budget's currency is as configured; others have their ISO 4217 decimal places.
+func LookupCurrency(code string) Currency {
+	code = strings.ToUpper(code)
+	if code == budgetCurrency.Code {
+		return budgetCurrency
+	}
+	return standardCurrency(code)
+}
+
+func standardCurrency(code string) Currency {
+	decimals, ok := currencyDecimals[code]
+	if !ok {
+		decimals = 2
+	}
+	return Currency{Code: code, Symbol: currencySymbols[code], Decimals: decimals}
+}
+
+// unit returns how many minor units make up one unit of the currency
+func (c Currency) unit() int64 {
+	unit := int64(1)
+	for range c.Decimals {
+		unit *= 10
+	}
+	return unit
+}
+
+// Money is an amount in a currency's minor units, e.g. cents, or whole yen
+// Entities keep amounts as int64 minor units of the budget's currency; Money is for doing
+// arithmetic and formatting on them without floating point, and without mixing currencies.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"` // ISO 4217 code
+}
+
+// NewMoney creates an amount of minor units in a currency
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// Cents creates an amount of minor units in the budget's currency, as entities keep them
+func Cents(amount int64) Money {
+	return Money{Amount: amount, Currency: budgetCurrency.Code}
+}
+
+// MoneyFromRat converts an amount in whole currency units, e.g. 12.345, to minor units
+// Halves are rounded away from zero.
+func MoneyFromRat(amount *big.Rat, currency string) Money {
+	money := NewMoney(0, currency)
+	scaled := new(big.Rat).Mul(amount, new(big.Rat).SetInt64(LookupCurrency(money.Currency).unit()))
+	money.Amount, _ = strconv.ParseInt(scaled.FloatString(0), 10, 64)
+	return money
+}
+
+// ParseMoney reads an amount in whole currency units written as a plain decimal, e.g.
+// "-1234.5", exactly
+// More decimal places than the currency has are only accepted when they are zeros.
+func ParseMoney(value, currency string) (Money, error) {
+	money := NewMoney(0, currency)
+	decimals := LookupCurrency(money.Currency).Decimals
+
+	s, negative := strings.CutPrefix(value, "-")
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" && fraction == "" || strings.Trim(whole+fraction, "0123456789") != "" {
+		return Money{}, fmt.Errorf("invalid amount %q", value)
+	}
+	if len(fraction) > decimals {
+		if strings.Trim(fraction[decimals:], "0") != "" {
+			return Money{}, fmt.Errorf("amount %q has more than %d decimal places", value, decimals)
+		}
+		fraction = fraction[:decimals]
+	}
+	digits := strings.TrimLeft(whole+fraction+strings.Repeat("0", decimals-len(fraction)), "0")
+	if digits == "" {
+		return money, nil
+	}
+	amount, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid amount %q", value)
+	}
+	if negative {
+		amount = -amount
+	}
+	money.Amount = amount
+	return money, nil
+}
+
+// Add returns m plus other, which must be in the same currency
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+// Sub returns m minus other, which must be in the same currency
+func (m Money) Sub(other Money) (Money, error) {
+	return m.Add(other.Neg())
+}
+
+// Neg returns m with its sign flipped
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.Currency}
+}
+
+// IsZero reports whether m is nothing
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsNegative reports whether m is below zero, e.g. spending or money owed
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Percent returns percent of m, rounded to the nearest minor unit with halves away from zero
+func (m Money) Percent(percent int64) Money {
+	return m.MulRat(big.NewRat(percent, 100))
+}
+
+// MulRat returns m times ratio, rounded to the nearest minor unit with halves away from zero
+func (m Money) MulRat(ratio *big.Rat) Money {
+	product := new(big.Rat).Mul(new(big.Rat).SetFrac64(m.Amount, LookupCurrency(m.Currency).unit()), ratio)
+	return MoneyFromRat(product, m.Currency)
+}
+
+// Allocate splits m in proportion to weights, which mustn't be negative, without losing
+// or inventing a minor unit
+// Each share is rounded down and the minor units left over go one each to the earliest
+// shares, so the shares always add up to m. All zero weights split m evenly.
+func (m Money) Allocate(weights ...int64) []Money {
+	shares := make([]Money, len(weights))
+	if len(weights) == 0 {
+		return shares
+	}
+	var total int64
+	for _, weight := range weights {
+		total += weight
+	}
+	if total == 0 {
+		weights = make([]int64, len(weights))
+		for i := range weights {
+			weights[i] = 1
+		}
+		total = int64(len(weights))
+	}
+
+	amount, sign := m.Amount, int64(1)
+	if amount < 0 {
+		amount, sign = -amount, -1
+	}
+	remainder := amount
+	for i, weight := range weights {
+		share := new(big.Int).Div(new(big.Int).Mul(big.NewInt(amount), big.NewInt(weight)), big.NewInt(total)).Int64()
+		shares[i] = Money{Amount: share, Currency: m.Currency}
+		remainder -= share
+	}
+	for i := 0; remainder > 0; i = (i + 1) % len(shares) {
+		if weights[i] > 0 {
+			shares[i].Amount++
+			remainder--
+		}
+	}
+	for i := range shares {
+		shares[i].Amount *= sign
+	}
+	return shares
+}
+
+// Split divides m into n nearly equal shares that add up to m
+func (m Money) Split(n int) []Money {
+	return m.Allocate(make([]int64, n)...)
+}
+
+// Decimal writes m in whole units without a symbol or grouping, e.g. "-1234.50", or
+// "-1234" in a currency without decimal places
+func (m Money) Decimal() string {
+	sign, units, fraction := m.parts()
+	if fraction == "" {
+		return sign + units
+	}
+	return sign + units + "." + fraction
+}
+
+// String writes m for people, e.g. "-$1,234.50", or "1,234.500 KWD" for currencies
+// without a symbol here
+func (m Money) String() string {
+	sign, units, fraction := m.parts()
+	for i := len(units) - 3; i > 0; i -= 3 {
+		units = units[:i] + "," + units[i:]
+	}
+	number := units
+	if fraction != "" {
+		number += "." + fraction
+	}
+
+	if symbol, ok := currencySymbols[m.Currency]; ok {
+		return sign + symbol + number
+	}
+	if m.Currency == "" {
+		return sign + number
+	}
+	return sign + number + " " + m.Currency
+}
+
+// parts splits m into its sign, whole units and the digits of its fraction, which are
+// empty for currencies without decimal places
+func (m Money) parts() (sign, units, fraction string) {
+	amount := m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	currency := LookupCurrency(m.Currency)
+	unit := currency.unit()
+	units = strconv.FormatInt(amount/unit, 10)
+	if currency.Decimals > 0 {
+		fraction = fmt.Sprintf("%0*d", currency.Decimals, amount%unit)
+	}
+	return sign, units, fraction
+}
@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestMoneyFromRat(t *testing.T) {
+	tests := []struct {
+		amount string
+		want   int64
+	}{
+		{"0.29", 29}, // 0.29 * 100 is 28.999... as a float
+		{"-4.50", -450},
+		{"1.005", 101},
+		{"-1.005", -101},
+		{"1234567.89", 123456789},
+	}
+	for _, tt := range tests {
+		amount, _ := new(big.Rat).SetString(tt.amount)
+		if got := MoneyFromRat(amount, "usd"); got != Cents(tt.want) {
+			t.Errorf("MoneyFromRat(%s) = %+v, want %d USD", tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	sum, err := Cents(1050).Add(Cents(-2000))
+	if err != nil || sum != Cents(-950) {
+		t.Errorf("Add = %+v, %v", sum, err)
+	}
+	if _, err := Cents(100).Sub(NewMoney(100, "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("expected mixing currencies to fail, got %v", err)
+	}
+	if got := Cents(1999).Percent(50); got != Cents(1000) {
+		t.Errorf("Percent rounds halves away from zero, got %+v", got)
+	}
+	if got := Cents(-1999).Percent(50); got != Cents(-1000) {
+		t.Errorf("Percent rounds negative halves away from zero, got %+v", got)
+	}
+}
+
+func TestMoney_Allocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  int64
+		weights []int64
+		want    []int64
+	}{
+		{"even thirds", 100, []int64{1, 1, 1}, []int64{34, 33, 33}},
+		{"weighted", 1000, []int64{1, 2, 0, 1}, []int64{250, 500, 0, 250}},
+		{"leftover skips zero weights", 101, []int64{0, 1, 1}, []int64{0, 51, 50}},
+		{"negative", -100, []int64{1, 1, 1}, []int64{-34, -33, -33}},
+		{"no weights splits evenly", 5, []int64{0, 0}, []int64{3, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares := Cents(tt.amount).Allocate(tt.weights...)
+			var total int64
+			for i, share := range shares {
+				if share.Amount != tt.want[i] || share.Currency != DefaultCurrency {
+					t.Errorf("share %d = %+v, want %d", i, share, tt.want[i])
+				}
+				total += share.Amount
+			}
+			if total != tt.amount {
+				t.Errorf("shares add up to %d, want %d", total, tt.amount)
+			}
+		})
+	}
+	if shares := Cents(10).Split(3); shares[0].Amount != 4 || shares[2].Amount != 3 {
+		t.Errorf("Split(3) = %+v", shares)
+	}
+}
+
+func TestMoney_Format(t *testing.T) {
+	tests := []struct {
+		money   Money
+		str     string
+		decimal string
+	}{
+		{Cents(-205050), "-$2,050.50", "-2050.50"},
+		{Cents(5), "$0.05", "0.05"},
+		{NewMoney(123456789, "EUR"), "€1,234,567.89", "1234567.89"},
+		{NewMoney(-1000, "sek"), "-10.00 SEK", "-10.00"},
+	}
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.str {
+			t.Errorf("String() = %q, want %q", got, tt.str)
+		}
+		if got := tt.money.Decimal(); got != tt.decimal {
+			t.Errorf("Decimal() = %q, want %q", got, tt.decimal)
+		}
+	}
+}
+
+func TestMoney_CurrencyDecimals(t *testing.T) {
+	tests := []struct {
+		money   Money
+		str     string
+		decimal string
+	}{
+		{NewMoney(-123456, "JPY"), "-¥123,456", "-123456"},
+		{NewMoney(1234500, "kwd"), "1,234.500 KWD", "1234.500"},
+		{NewMoney(5, "BHD"), "0.005 BHD", "0.005"},
+	}
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.str {
+			t.Errorf("String() = %q, want %q", got, tt.str)
+		}
+		if got := tt.money.Decimal(); got != tt.decimal {
+			t.Errorf("Decimal() = %q, want %q", got, tt.decimal)
+		}
+	}
+
+	amount, _ := new(big.Rat).SetString("1.2345")
+	if got := MoneyFromRat(amount, "KWD"); got.Amount != 1235 {
+		t.Errorf("MoneyFromRat(1.2345 KWD) = %+v, want 1235 fils", got)
+	}
+	if got := MoneyFromRat(amount, "JPY"); got.Amount != 1 {
+		t.Errorf("MoneyFromRat(1.2345 JPY) = %+v, want 1 yen", got)
+	}
+	if got := NewMoney(1000, "JPY").Percent(15); got.Amount != 150 {
+		t.Errorf("Percent in yen = %+v", got)
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		value    string
+		currency string
+		want     int64
+		wantErr  bool
+	}{
+		{"12.34", "USD", 1234, false},
+		{"-.5", "USD", -50, false},
+		{"12.340", "USD", 1234, false},
+		{"12.345", "USD", 0, true},
+		{"1500", "JPY", 1500, false},
+		{"1500.00", "JPY", 1500, false},
+		{"1500.5", "JPY", 0, true},
+		{"1.005", "KWD", 1005, false},
+		{"", "USD", 0, true},
+		{"1,000", "USD", 0, true},
+		{"99999999999999999999", "USD", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.value, tt.currency)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMoney(%q, %s) error = %v, wantErr %v", tt.value, tt.currency, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got.Amount != tt.want {
+			t.Errorf("ParseMoney(%q, %s) = %d, want %d", tt.value, tt.currency, got.Amount, tt.want)
+		}
+	}
+}
+
+func TestSetBudgetCurrency(t *testing.T) {
+	defer SetBudgetCurrency(DefaultCurrency, -1)
+
+	if err := SetBudgetCurrency("jpy", -1); err != nil {
+		t.Fatal(err)
+	}
+	if got := Cents(1500).String(); got != "¥1,500" {
+		t.Errorf("Cents(1500) in a yen budget = %q", got)
+	}
+
+	// The standard precision can be overridden, e.g. to keep a currency's subunits
+	if err := SetBudgetCurrency("JPY", 2); err != nil {
+		t.Fatal(err)
+	}
+	if got := Cents(1500).String(); got != "¥15.00" {
+		t.Errorf("Cents(1500) with two decimals = %q", got)
+	}
+
+	for _, code := range []string{"", "US", "U$D", "EURO"} {
+		if err := SetBudgetCurrency(code, -1); err == nil {
+			t.Errorf("expected %q to be rejected", code)
+		}
+	}
+	if err := SetBudgetCurrency("USD", 5); err == nil {
+		t.Error("expected more than four decimals to be rejected")
+	}
+	if BudgetCurrency().Code != "JPY" {
+		t.Errorf("expected a rejected currency to leave the budget's alone, got %+v", BudgetCurrency())
+	}
+}
@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// Payee is who a transaction was paid to or received from
+// Imports turn bank descriptions like "SQ *BLUE BOTTLE COFFEE #123 OAKLAND CA" into
+// a payee ("Blue Bottle Coffee") so the same merchant reads the same way every time.
+type Payee struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PayeeMatchType says how a payee rule's pattern is compared to a description
+type PayeeMatchType string
+
+const (
+	PayeeMatchContains PayeeMatchType = "contains" // Case-insensitive substring
+	PayeeMatchExact    PayeeMatchType = "exact"    // Case-insensitive, whole description
+	PayeeMatchRegex    PayeeMatchType = "regex"    // Go regular expression
+)
+
+// PayeeRule gives transactions that match its conditions a payee, category and/or memo
+// A transaction matches when its description matches the pattern, as the bank sent it, and
+// it's in the rule's account and amount range, if the rule has them; an empty pattern
+// matches any description. Rules are tried in priority order (lowest first) on import and
+// when transactions are created; the first match wins.
+type PayeeRule struct {
+	ID         string         `json:"id"`
+	Pattern    string         `json:"pattern"`
+	MatchType  PayeeMatchType `json:"match_type"`
+	AccountID  *string        `json:"account_id,omitempty"` // Only transactions in this account, if set
+	MinAmount  *int64         `json:"min_amount,omitempty"` // In cents, compared with the amount's size so outflows match too
+	MaxAmount  *int64         `json:"max_amount,omitempty"`
+	PayeeID    *string        `json:"payee_id,omitempty"`    // Payee to assign, if any
+	CategoryID *string        `json:"category_id,omitempty"` // Category to assign, if any
+	Note       *string        `json:"note,omitempty"`        // Memo to put on the transaction, if any
+	Priority   int            `json:"priority"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
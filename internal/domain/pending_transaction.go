@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// PendingTransactionStatus represents where a pending transaction is in the review flow
+type PendingTransactionStatus string
+
+const (
+	PendingStatusPending  PendingTransactionStatus = "pending"  // Awaiting review
+	PendingStatusApproved PendingTransactionStatus = "approved" // Turned into a real transaction
+	PendingStatusRejected PendingTransactionStatus = "rejected" // Discarded by the user
+)
+
+// PendingTransaction is a transaction captured from an external source (e.g. a forwarded
+// bank alert email) that must be approved before it affects balances or the budget
+type PendingTransaction struct {
+	ID            string                   `json:"id"`
+	Source        string                   `json:"source"`               // Where it came from (e.g. "email")
+	AccountID     *string                  `json:"account_id,omitempty"` // Suggested account, may be chosen at approval
+	Amount        int64                    `json:"amount"`               // Amount in cents (positive=inflow, negative=outflow)
+	Description   string                   `json:"description"`          // Merchant or payee parsed from the source
+	Date          time.Time                `json:"date"`
+	Sender        string                   `json:"sender,omitempty"`   // Email sender
+	Subject       string                   `json:"subject,omitempty"`  // Email subject
+	RawBody       string                   `json:"raw_body,omitempty"` // Original message body, kept for review
+	Status        PendingTransactionStatus `json:"status"`
+	TransactionID *string                  `json:"transaction_id,omitempty"` // Created transaction once approved
+	CreatedAt     time.Time                `json:"created_at"`
+	UpdatedAt     time.Time                `json:"updated_at"`
+}
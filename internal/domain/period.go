@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PeriodType is how long a budgeting period is
+type PeriodType string
+
+const (
+	PeriodMonth   PeriodType = "month"   // Key YYYY-MM, e.g. 2025-10
+	PeriodWeek    PeriodType = "week"    // ISO week, Monday to Sunday; key YYYY-Www, e.g. 2025-W42
+	PeriodQuarter PeriodType = "quarter" // Key YYYY-Qn, e.g. 2025-Q4
+)
+
+// IsValid reports whether the period type is a known value
+func (t PeriodType) IsValid() bool {
+	return t == PeriodMonth || t == PeriodWeek || t == PeriodQuarter
+}
+
+var (
+	monthKeyPattern   = regexp.MustCompile(`^(\d{4})-(0[1-9]|1[0-2])$`)
+	weekKeyPattern    = regexp.MustCompile(`^(\d{4})-W(\d{2})$`)
+	quarterKeyPattern = regexp.MustCompile(`^(\d{4})-Q([1-4])$`)
+)
+
+// Period is one budgeting period: a month, an ISO week or a quarter
+// Keys of one type sort in date order, so they can be compared as strings. Periods are
+// taken in UTC, matching how the repositories bound them.
+type Period struct {
+	Type  PeriodType `json:"type"`
+	Key   string     `json:"key"`
+	Start time.Time  `json:"start"` // First instant of the period
+	End   time.Time  `json:"end"`   // First instant of the next period
+}
+
+// ParsePeriod reads a period key, working out its type from the format
+func ParsePeriod(key string) (Period, error) {
+	if m := monthKeyPattern.FindStringSubmatch(key); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		return PeriodContaining(PeriodMonth, time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)), nil
+	}
+	if m := quarterKeyPattern.FindStringSubmatch(key); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		quarter, _ := strconv.Atoi(m[2])
+		return PeriodContaining(PeriodQuarter, time.Date(year, time.Month(quarter*3-2), 1, 0, 0, 0, 0, time.UTC)), nil
+	}
+	if m := weekKeyPattern.FindStringSubmatch(key); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		week, _ := strconv.Atoi(m[2])
+		// January 4th is always in week 1
+		jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+		monday := jan4.AddDate(0, 0, -(int(jan4.Weekday())+6)%7+(week-1)*7)
+		// Only some years have a week 53
+		if isoYear, isoWeek := monday.ISOWeek(); week < 1 || isoYear != year || isoWeek != week {
+			return Period{}, ErrInvalidPeriod
+		}
+		return PeriodContaining(PeriodWeek, monday), nil
+	}
+	return Period{}, ErrInvalidPeriod
+}
+
+// PeriodContaining returns the period of type t that date falls in
+// Unknown types are taken as months.
+func PeriodContaining(t PeriodType, date time.Time) Period {
+	date = date.UTC()
+	switch t {
+	case PeriodWeek:
+		day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+		start := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+		year, week := start.ISOWeek()
+		return Period{Type: t, Key: fmt.Sprintf("%04d-W%02d", year, week), Start: start, End: start.AddDate(0, 0, 7)}
+	case PeriodQuarter:
+		quarter := (int(date.Month())-1)/3 + 1
+		start := time.Date(date.Year(), time.Month(quarter*3-2), 1, 0, 0, 0, 0, time.UTC)
+		return Period{Type: t, Key: fmt.Sprintf("%04d-Q%d", date.Year(), quarter), Start: start, End: start.AddDate(0, 3, 0)}
+	}
+	start := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return Period{Type: PeriodMonth, Key: start.Format("2006-01"), Start: start, End: start.AddDate(0, 1, 0)}
+}
+
+// Next returns the period straight after p
+func (p Period) Next() Period {
+	return PeriodContaining(p.Type, p.End)
+}
+
+// Prev returns the period straight before p
+func (p Period) Prev() Period {
+	return PeriodContaining(p.Type, p.Start.Add(-time.Second))
+}
+
+// In returns the period of type t that p starts in
+// A monthly allocation seen week by week counts from the week of the 1st, for example.
+func (p Period) In(t PeriodType) Period {
+	if p.Type == t {
+		return p
+	}
+	return PeriodContaining(t, p.Start)
+}
+
+// budgetLocation is the timezone the budget's calendar days are taken in; see SetBudgetTimezone
+var budgetLocation = time.UTC
+
+// SetBudgetTimezone sets the timezone the budget's calendar days are taken in, at startup
+// name is an IANA name such as America/New_York; "" is UTC.
+func SetBudgetTimezone(name string) error {
+	location, err := time.LoadLocation(strings.TrimSpace(name))
+	if err != nil {
+		return fmt.Errorf("unknown timezone %q", name)
+	}
+	budgetLocation = location
+	return nil
+}
+
+// BudgetLocation returns the timezone the budget's calendar days are taken in
+func BudgetLocation() *time.Location {
+	return budgetLocation
+}
+
+// DaysApart counts the calendar days between two dates in the budget's timezone, ignoring
+// the time of day
+// Transactions 23 hours apart on adjacent days are a day apart; two on the same day are
+// none apart however many hours separate them.
+func DaysApart(a, b time.Time) int {
+	a, b = a.In(budgetLocation), b.In(budgetLocation)
+	dayA := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	dayB := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(dayA.Sub(dayB).Abs().Hours() / 24)
+}
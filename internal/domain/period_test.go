@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		key   string
+		typ   PeriodType
+		start string
+		end   string
+	}{
+		{"2025-10", PeriodMonth, "2025-10-01", "2025-11-01"},
+		{"2025-12", PeriodMonth, "2025-12-01", "2026-01-01"},
+		{"2025-Q4", PeriodQuarter, "2025-10-01", "2026-01-01"},
+		{"2025-W42", PeriodWeek, "2025-10-13", "2025-10-20"},
+		{"2026-W01", PeriodWeek, "2025-12-29", "2026-01-05"}, // Week 1 starts in the year before
+		{"2020-W53", PeriodWeek, "2020-12-28", "2021-01-04"},
+	}
+	for _, tt := range tests {
+		p, err := ParsePeriod(tt.key)
+		if err != nil {
+			t.Errorf("ParsePeriod(%s) unexpected error = %v", tt.key, err)
+			continue
+		}
+		if p.Type != tt.typ || p.Key != tt.key {
+			t.Errorf("ParsePeriod(%s) = %s %s, want %s %s", tt.key, p.Type, p.Key, tt.typ, tt.key)
+		}
+		if got := p.Start.Format("2006-01-02"); got != tt.start {
+			t.Errorf("ParsePeriod(%s).Start = %s, want %s", tt.key, got, tt.start)
+		}
+		if got := p.End.Format("2006-01-02"); got != tt.end {
+			t.Errorf("ParsePeriod(%s).End = %s, want %s", tt.key, got, tt.end)
+		}
+	}
+
+	for _, key := range []string{"", "2025-13", "2025-1", "2025-Q5", "2025-W00", "2025-W53", "2025-W7", "25-10"} {
+		if _, err := ParsePeriod(key); !errors.Is(err, ErrInvalidPeriod) {
+			t.Errorf("ParsePeriod(%q) error = %v, want %v", key, err, ErrInvalidPeriod)
+		}
+	}
+}
+
+func TestPeriod_Navigation(t *testing.T) {
+	week, _ := ParsePeriod("2025-W52")
+	if next := week.Next().Key; next != "2026-W01" {
+		t.Errorf("2025-W52 Next = %s, want 2026-W01", next)
+	}
+	quarter, _ := ParsePeriod("2025-Q1")
+	if prev := quarter.Prev().Key; prev != "2024-Q4" {
+		t.Errorf("2025-Q1 Prev = %s, want 2024-Q4", prev)
+	}
+
+	// Periods of another type count from where they start
+	month, _ := ParsePeriod("2025-10")
+	if got := month.In(PeriodWeek).Key; got != "2025-W40" {
+		t.Errorf("2025-10 in weeks = %s, want 2025-W40", got)
+	}
+	if got := week.In(PeriodMonth).Key; got != "2025-12" {
+		t.Errorf("2025-W52 in months = %s, want 2025-12", got)
+	}
+
+	sunday := time.Date(2025, 10, 19, 23, 30, 0, 0, time.UTC)
+	if got := PeriodContaining(PeriodWeek, sunday).Key; got != "2025-W42" {
+		t.Errorf("week containing Sunday 2025-10-19 = %s, want 2025-W42", got)
+	}
+}
+
+func TestDaysApart(t *testing.T) {
+	tests := []struct {
+		a, b time.Time
+		want int
+	}{
+		{time.Date(2026, 3, 1, 23, 30, 0, 0, time.UTC), time.Date(2026, 3, 2, 0, 30, 0, 0, time.UTC), 1},
+		{time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 2, 23, 59, 0, 0, time.UTC), 0},
+		{time.Date(2026, 3, 5, 1, 0, 0, 0, time.UTC), time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC), 4},
+		// Compared in UTC by default: 20:00 in New York on the 1st is already the 2nd
+		{time.Date(2026, 3, 1, 20, 0, 0, 0, time.FixedZone("EST", -5*3600)), time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), 0},
+	}
+	for _, tt := range tests {
+		if got := DaysApart(tt.a, tt.b); got != tt.want {
+			t.Errorf("DaysApart(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestDaysApartInBudgetTimezone(t *testing.T) {
+	defer SetBudgetTimezone("")
+	if err := SetBudgetTimezone("America/New_York"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		a, b time.Time
+		want int
+	}{
+		// 01:00 UTC on the 2nd is still the evening of the 1st in New York
+		{time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC), time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC), 0},
+		{time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC), time.Date(2026, 3, 1, 6, 0, 0, 0, time.UTC), 1},
+		// Across the spring clock change, which has a 23-hour day
+		{time.Date(2026, 3, 7, 12, 0, 0, 0, time.UTC), time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC), 2},
+	}
+	for _, tt := range tests {
+		if got := DaysApart(tt.a, tt.b); got != tt.want {
+			t.Errorf("DaysApart(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	if err := SetBudgetTimezone("Mars/Olympus_Mons"); err == nil {
+		t.Error("expected an unknown timezone to be refused")
+	}
+	if BudgetLocation().String() != "America/New_York" {
+		t.Errorf("expected a refused timezone to leave the budget's alone, got %s", BudgetLocation())
+	}
+}
@@ -11,8 +11,9 @@ type AccountRepository interface {
 	GetByID(ctx context.Context, id string) (*Account, error)
 	List(ctx context.Context) ([]*Account, error)
 	Update(ctx context.Context, account *Account) error
+	AdjustRewardsBalance(ctx context.Context, id string, delta int64) error
 	Delete(ctx context.Context, id string) error
-	GetTotalBalance(ctx context.Context) (int64, error)
+	GetTotalBalance(ctx context.Context) (int64, error) // Leaves out accounts hidden from the dashboard
 }
 
 // CategoryRepository defines the interface for category data operations
@@ -44,11 +45,17 @@ type TransactionRepository interface {
 	ListByCategory(ctx context.Context, categoryID string) ([]*Transaction, error)
 	ListByPeriod(ctx context.Context, startDate, endDate string) ([]*Transaction, error)
 	ListUncategorized(ctx context.Context) ([]*Transaction, error)
+	Search(ctx context.Context, query TransactionQuery) (transactions []*Transaction, total int, err error) // total counts every match, ignoring Limit and Offset
 	GetCategoryActivity(ctx context.Context, categoryID, period string) (int64, error)
+	SumActivityByCategory(ctx context.Context, startDate, endDate string) (map[string]int64, error)
+	SumSpendingByCategory(ctx context.Context, periodType PeriodType) ([]*CategorySpending, error)
+	SumInflowsByPeriod(ctx context.Context, periodType PeriodType) (map[string]int64, error) // Budgetable income by period key; transfers and adjustments are left out
 	FindDuplicate(ctx context.Context, accountID string, date time.Time, amount int64, description string) (*Transaction, error)
 	FindByFitID(ctx context.Context, accountID string, fitID string) (*Transaction, error)
+	ListByImport(ctx context.Context, importID string) ([]*Transaction, error)
 	Update(ctx context.Context, transaction *Transaction) error
 	BulkUpdateCategory(ctx context.Context, transactionIDs []string, categoryID *string) error
+	BulkUpdate(ctx context.Context, transactionIDs []string, edit TransactionBulkEdit) error // Balances are the caller's to adjust
 	Delete(ctx context.Context, id string) error
 }
 
@@ -59,13 +66,258 @@ type AllocationRepository interface {
 	GetByCategoryAndPeriod(ctx context.Context, categoryID, period string) (*Allocation, error)
 	ListByPeriod(ctx context.Context, period string) ([]*Allocation, error)
 	List(ctx context.Context) ([]*Allocation, error)
+	SumByCategory(ctx context.Context) (map[string]int64, error) // Every period's allocations, by category ID
 	Update(ctx context.Context, allocation *Allocation) error
 	Delete(ctx context.Context, id string) error
 }
 
+// CategoryMoveRepository defines the interface for the payment category funding ledger
+type CategoryMoveRepository interface {
+	Create(ctx context.Context, move *CategoryMove) error
+	List(ctx context.Context) ([]*CategoryMove, error)
+	ListByCategory(ctx context.Context, categoryID string) ([]*CategoryMove, error) // Moves into the category, oldest first
+	DeleteByTransaction(ctx context.Context, transactionID string) error
+}
+
+// GoalRepository defines the interface for category goal data operations
+type GoalRepository interface {
+	Create(ctx context.Context, goal *Goal) error
+	GetByID(ctx context.Context, id string) (*Goal, error)
+	GetByCategoryID(ctx context.Context, categoryID string) (*Goal, error)
+	List(ctx context.Context) ([]*Goal, error)
+	Update(ctx context.Context, goal *Goal) error
+	Delete(ctx context.Context, id string) error
+}
+
+// PayeeRepository defines the interface for payee data operations
+type PayeeRepository interface {
+	Create(ctx context.Context, payee *Payee) error
+	GetByID(ctx context.Context, id string) (*Payee, error)
+	GetByName(ctx context.Context, name string) (*Payee, error) // Case-insensitive
+	List(ctx context.Context) ([]*Payee, error)
+}
+
+// PayeeRuleRepository defines the interface for payee rule data operations
+type PayeeRuleRepository interface {
+	Create(ctx context.Context, rule *PayeeRule) error
+	GetByID(ctx context.Context, id string) (*PayeeRule, error)
+	List(ctx context.Context) ([]*PayeeRule, error) // In priority order
+	Update(ctx context.Context, rule *PayeeRule) error
+	Delete(ctx context.Context, id string) error
+}
+
+// AllocationTemplateRepository defines the interface for allocation template data operations
+// Templates are stored and returned with their items.
+type AllocationTemplateRepository interface {
+	Create(ctx context.Context, template *AllocationTemplate) error
+	GetByID(ctx context.Context, id string) (*AllocationTemplate, error)
+	List(ctx context.Context) ([]*AllocationTemplate, error)        // By name
+	Update(ctx context.Context, template *AllocationTemplate) error // Replaces the items
+	Delete(ctx context.Context, id string) error
+}
+
+// TransferHintRepository defines the interface for transfer hint data operations
+type TransferHintRepository interface {
+	Create(ctx context.Context, hint *TransferHint) error
+	GetByID(ctx context.Context, id string) (*TransferHint, error)
+	ListByAccount(ctx context.Context, accountID string) ([]*TransferHint, error) // Oldest first
+	Delete(ctx context.Context, id string) error
+}
+
+// TransferSuggestionRepository defines the interface for transfer suggestion data operations
+type TransferSuggestionRepository interface {
+	Create(ctx context.Context, suggestion *TransferSuggestion) error
+	GetByID(ctx context.Context, id string) (*TransferSuggestion, error)
+	List(ctx context.Context, status TransferSuggestionStatus) ([]*TransferSuggestion, error) // Best first; an empty status lists them all
+	Update(ctx context.Context, suggestion *TransferSuggestion) error
+	Delete(ctx context.Context, id string) error
+}
+
+// TransferPairingRepository defines the interface for transfer pairing data operations
+type TransferPairingRepository interface {
+	Record(ctx context.Context, pairing *TransferPairing) error // Adds its counts to the same accounts and payees' pairing, creating it if needed
+	GetByID(ctx context.Context, id string) (*TransferPairing, error)
+	List(ctx context.Context) ([]*TransferPairing, error) // Most recently reviewed first
+	Delete(ctx context.Context, id string) error
+}
+
+// BankConnectionRepository defines the interface for bank connection data operations
+type BankConnectionRepository interface {
+	Save(ctx context.Context, connection *BankConnection) error // Creates or replaces the account's connection
+	GetByAccountID(ctx context.Context, accountID string) (*BankConnection, error)
+	Delete(ctx context.Context, accountID string) error
+}
+
+// BankAggregationRepository defines the interface for bank aggregation data operations
+type BankAggregationRepository interface {
+	Create(ctx context.Context, aggregation *BankAggregation) error
+	GetByID(ctx context.Context, id string) (*BankAggregation, error)
+	List(ctx context.Context) ([]*BankAggregation, error) // Oldest first
+	Update(ctx context.Context, aggregation *BankAggregation) error
+	Delete(ctx context.Context, id string) error // Also deletes its accounts
+
+	SaveAccount(ctx context.Context, account *BankAggregationAccount) error // Creates or replaces the account
+	GetAccount(ctx context.Context, aggregationID, remoteID string) (*BankAggregationAccount, error)
+	ListAccounts(ctx context.Context, aggregationID string) ([]*BankAggregationAccount, error) // By name
+}
+
+// MaintenanceRepository defines the interface for keeping the database file healthy
+type MaintenanceRepository interface {
+	Size(ctx context.Context) (DatabaseSize, error)
+	Optimize(ctx context.Context) error // Refreshes the statistics the query planner relies on
+	Vacuum(ctx context.Context) error   // Rebuilds the file, returning free pages to the filesystem
+}
+
+// WidgetRepository defines the interface for widget data operations
+type WidgetRepository interface {
+	Create(ctx context.Context, widget *Widget) error
+	GetByID(ctx context.Context, id string) (*Widget, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*Widget, error)
+	List(ctx context.Context) ([]*Widget, error) // Oldest first
+	Update(ctx context.Context, widget *Widget) error
+	Delete(ctx context.Context, id string) error
+}
+
 // BudgetStateRepository defines the interface for budget state operations
 type BudgetStateRepository interface {
 	Get(ctx context.Context) (*BudgetState, error)
 	Update(ctx context.Context, state *BudgetState) error
 	AdjustReadyToAssign(ctx context.Context, delta int64) error
 }
+
+// ReadyToAssignTotalsRepository reads the per-period totals the database keeps up to date
+// as transactions, allocations and categories change
+type ReadyToAssignTotalsRepository interface {
+	List(ctx context.Context) ([]*ReadyToAssignTotal, error)
+	Rebuild(ctx context.Context) error // Recalculates every period from the transactions and allocations
+}
+
+// SettingRepository defines the interface for budget-wide settings
+type SettingRepository interface {
+	Get(ctx context.Context, key string) (*Setting, error)
+	Set(ctx context.Context, setting *Setting) error
+	List(ctx context.Context) ([]*Setting, error)
+}
+
+// UserSettingRepository defines the interface for per-user settings
+type UserSettingRepository interface {
+	Set(ctx context.Context, setting *UserSetting) error
+	ListByUser(ctx context.Context, userID string) ([]*UserSetting, error)
+}
+
+// CPIRepository defines the interface for consumer price index data
+type CPIRepository interface {
+	Upsert(ctx context.Context, entry *CPIEntry) error
+	List(ctx context.Context) ([]*CPIEntry, error)
+	Delete(ctx context.Context, period string) error
+}
+
+// PendingTransactionRepository defines the interface for transactions awaiting approval
+type PendingTransactionRepository interface {
+	Create(ctx context.Context, pending *PendingTransaction) error
+	GetByID(ctx context.Context, id string) (*PendingTransaction, error)
+	List(ctx context.Context, status PendingTransactionStatus) ([]*PendingTransaction, error)
+	Update(ctx context.Context, pending *PendingTransaction) error
+	Delete(ctx context.Context, id string) error
+	CountByStatusBefore(ctx context.Context, status PendingTransactionStatus, before time.Time) (int64, error)
+	DeleteByStatusBefore(ctx context.Context, status PendingTransactionStatus, before time.Time) (int64, error)
+}
+
+// BotChatRepository defines the interface for bot chat operations
+type BotChatRepository interface {
+	Create(ctx context.Context, chat *BotChat) error
+	GetByID(ctx context.Context, id string) (*BotChat, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*BotChat, error)
+	GetByPlatformChatID(ctx context.Context, platform BotPlatform, chatID string) (*BotChat, error)
+	List(ctx context.Context) ([]*BotChat, error)
+	Update(ctx context.Context, chat *BotChat) error
+	Delete(ctx context.Context, id string) error
+}
+
+// APITokenRepository defines the interface for API token operations
+type APITokenRepository interface {
+	Create(ctx context.Context, token *APIToken) error
+	GetByID(ctx context.Context, id string) (*APIToken, error)
+	GetByHash(ctx context.Context, tokenHash string) (*APIToken, error)
+	List(ctx context.Context) ([]*APIToken, error)
+	UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error
+	RecordWrite(ctx context.Context, id, day string, quota int) (allowed bool, err error) // Counts a write unless quota writes were already made on day
+	SetDisabled(ctx context.Context, id string, disabledAt *time.Time) error              // nil enables the token again
+	Delete(ctx context.Context, id string) error
+}
+
+// UserRepository defines the interface for user operations
+type UserRepository interface {
+	Create(ctx context.Context, user *User) error
+	GetByID(ctx context.Context, id string) (*User, error)
+	GetByEmail(ctx context.Context, email string) (*User, error)
+	List(ctx context.Context) ([]*User, error)
+	Update(ctx context.Context, user *User) error
+	Count(ctx context.Context) (int, error)
+}
+
+// UserIdentityRepository defines the interface for external identity links
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *UserIdentity) error
+	GetBySubject(ctx context.Context, issuer, subject string) (*UserIdentity, error)
+	ListByUser(ctx context.Context, userID string) ([]*UserIdentity, error)
+}
+
+// RecoveryCodeRepository defines the interface for two-factor recovery code operations
+type RecoveryCodeRepository interface {
+	ReplaceForUser(ctx context.Context, userID string, codes []*RecoveryCode) error
+	Use(ctx context.Context, userID, codeHash string, usedAt time.Time) error
+	CountUnused(ctx context.Context, userID string) (int, error)
+	DeleteByUser(ctx context.Context, userID string) error
+}
+
+// UserTokenRepository defines the interface for emailed single-use token operations
+type UserTokenRepository interface {
+	Create(ctx context.Context, token *UserToken) error
+	GetByHash(ctx context.Context, purpose UserTokenPurpose, tokenHash string) (*UserToken, error)
+	MarkUsed(ctx context.Context, id string, usedAt time.Time) error
+	DeleteByUser(ctx context.Context, userID string, purpose UserTokenPurpose) error
+	CountExpired(ctx context.Context, before time.Time) (int64, error)
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// SessionRepository defines the interface for sign-in session operations
+type SessionRepository interface {
+	Create(ctx context.Context, session *Session) error
+	GetByHash(ctx context.Context, tokenHash string) (*Session, error)
+	ListByUser(ctx context.Context, userID string) ([]*Session, error)
+	UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error
+	Delete(ctx context.Context, id string) error
+	DeleteByUser(ctx context.Context, userID, exceptID string) error
+	DeleteExpired(ctx context.Context, now time.Time) error
+	CountExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// AuditRepository defines the interface for the audit log
+type AuditRepository interface {
+	Create(ctx context.Context, entry *AuditEntry) error
+	List(ctx context.Context, query AuditQuery) ([]*AuditEntry, error)
+	CountBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// ImportFileRepository defines the interface for records of imported files
+type ImportFileRepository interface {
+	Create(ctx context.Context, file *ImportFile) error
+	GetByID(ctx context.Context, id string) (*ImportFile, error)
+	// FindByFingerprint returns the account's latest committed import of a file, or nil if it has none
+	FindByFingerprint(ctx context.Context, accountID, fingerprint string) (*ImportFile, error)
+	List(ctx context.Context, status string) ([]*ImportFile, error) // Newest first; "" lists every status
+	Update(ctx context.Context, file *ImportFile) error
+	Delete(ctx context.Context, id string) error
+	CreateRows(ctx context.Context, rows []*ImportFileRow) error
+	ListRows(ctx context.Context, importID string) ([]*ImportFileRow, error) // In file order
+	UpdateRow(ctx context.Context, row *ImportFileRow) error
+}
+
+// UnitOfWork runs a sequence of repository calls atomically
+// Repositories called with the ctx handed to fn take part in one database transaction,
+// which commits if fn returns nil and rolls back otherwise. A Do inside another joins it.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,67 @@
+package domain
+
+import "time"
+
+// Setting is a budget-wide key/value setting
+// Values are stored as strings; structured settings are JSON encoded
+type Setting struct {
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SettingKeyEmergencyFund stores the EmergencyFundSettings JSON document
+const SettingKeyEmergencyFund = "emergency_fund"
+
+// DefaultEmergencyFundTargetMonths is the coverage goal used until the user picks one
+const DefaultEmergencyFundTargetMonths = 6
+
+// EmergencyFundSettings identifies which categories and accounts make up the emergency fund
+type EmergencyFundSettings struct {
+	CategoryIDs  []string `json:"category_ids"`  // Categories whose Available counts toward the fund
+	AccountIDs   []string `json:"account_ids"`   // Accounts whose balance counts toward the fund
+	TargetMonths int      `json:"target_months"` // Months of essential spending the fund should cover
+}
+
+// SettingKeyMQTT stores the MQTTSettings JSON document
+const SettingKeyMQTT = "mqtt"
+
+// MQTTSettings selects which category availables are published over MQTT
+// Account balances and Ready to Assign are always published.
+type MQTTSettings struct {
+	CategoryIDs []string `json:"category_ids"`
+}
+
+// SettingKeyAuthTokenKey stores the hex-encoded server key used to sign emailed
+// password reset and verification tokens; generated on first use
+const SettingKeyAuthTokenKey = "auth_token_key"
+
+// SettingKeyStarterTemplate stores the StarterTemplateSettings JSON document,
+// written once the budget has been set up from a starter template
+const SettingKeyStarterTemplate = "starter_template"
+
+// StarterTemplateSettings records which starter template a budget was set up from
+type StarterTemplateSettings struct {
+	Template  string    `json:"template"`
+	Locale    string    `json:"locale"` // Language the category names were created in
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// SettingKeyCategorizeScript stores the source of the user's categorize script;
+// empty when no script is set
+const SettingKeyCategorizeScript = "categorize_script"
+
+// SettingKeyImportMatching stores the ImportMatchingSettings JSON document
+const SettingKeyImportMatching = "import_matching"
+
+// DefaultImportMatchDays is how far apart a manual entry and an imported transaction's
+// dates may be until the user picks a window
+const DefaultImportMatchDays = 3
+
+// ImportMatchingSettings decides whether imports merge into transactions entered by hand
+// An imported transaction with exactly the amount of a manual entry in the same account,
+// dated within MatchDays of it, is merged into the entry rather than added beside it.
+type ImportMatchingSettings struct {
+	AutoMerge bool `json:"auto_merge"`
+	MatchDays int  `json:"match_days"`
+}
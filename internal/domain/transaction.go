@@ -6,8 +6,10 @@ import "time"
 type TransactionType string
 
 const (
-	TransactionTypeNormal   TransactionType = "normal"   // Regular inflow or outflow
-	TransactionTypeTransfer TransactionType = "transfer" // Transfer between accounts
+	TransactionTypeNormal          TransactionType = "normal"           // Regular inflow or outflow
+	TransactionTypeTransfer        TransactionType = "transfer"         // Transfer between accounts
+	TransactionTypeAdjustment      TransactionType = "adjustment"       // Balance correction, e.g. a write-off
+	TransactionTypeStartingBalance TransactionType = "starting_balance" // The balance an account was opened with
 )
 
 // Transaction represents a single financial transaction
@@ -18,16 +20,86 @@ const (
 //   - Move money between accounts
 //   - No category needed
 //   - Amount is negative on source account
+//   - One-sided transfers send money somewhere the budget doesn't track (e.g. a
+//     brokerage): TransferToAccountID is nil, the amount leaves the account's balance
+//     without counting as spending, and linking an account later completes them
+// Adjustment transactions:
+//   - Correct an account's balance (reconciliation differences, write-offs)
+//   - Count in balances but not as income, spending or Ready to Assign inflows
+//   - No category; a note explaining the adjustment is required
+// Starting balance transactions:
+//   - The balance an account was opened with, at most one per account
+//   - A positive one is a Ready to Assign inflow, but isn't income in reports
+//   - No category
 type Transaction struct {
 	ID                  string           `json:"id"`
-	Type                TransactionType  `json:"type"`                             // normal or transfer
+	Type                TransactionType  `json:"type"`                             // normal, transfer, adjustment or starting_balance
 	AccountID           string           `json:"account_id"`                       // Source account
-	TransferToAccountID *string          `json:"transfer_to_account_id,omitempty"` // Destination account (transfers only)
+	TransferToAccountID *string          `json:"transfer_to_account_id,omitempty"` // Destination account (transfers only, nil for one-sided transfers)
 	CategoryID          *string          `json:"category_id,omitempty"`            // Category (normal transactions only, nullable for imports)
 	Amount              int64            `json:"amount"`                           // Amount in cents (positive=inflow, negative=outflow)
 	Description         string           `json:"description"`
 	Date                time.Time        `json:"date"`                             // When the transaction occurred
 	FitID               *string          `json:"fitid,omitempty"`                  // Financial Institution Transaction ID (for OFX imports, duplicate detection)
+	Note                *string          `json:"note,omitempty"`                   // Why an adjustment was made, or a memo from a payee rule
+	PayeeID             *string          `json:"payee_id,omitempty"`               // Who was paid or paid in (set by imports)
+	ImportID            *string          `json:"import_id,omitempty"`              // The imported file this came from (see ImportFile)
+	OriginalCurrency    *string          `json:"original_currency,omitempty"`      // ISO 4217 code the bank charged in, when it wasn't the account's currency
+	OriginalAmount      *int64           `json:"original_amount,omitempty"`        // Amount in OriginalCurrency's minor units, same sign as Amount
 	CreatedAt           time.Time        `json:"created_at"`
 	UpdatedAt           time.Time        `json:"updated_at"`
 }
+
+// TransactionSort is a field TransactionQuery results can be ordered by
+type TransactionSort string
+
+const (
+	TransactionSortDate        TransactionSort = "date"
+	TransactionSortAmount      TransactionSort = "amount"
+	TransactionSortDescription TransactionSort = "description"
+	TransactionSortCreatedAt   TransactionSort = "created_at"
+	TransactionSortRelevance   TransactionSort = "relevance" // Best Text matches first; date order without Text
+)
+
+// IsValid reports whether the sort is one of the known fields
+func (s TransactionSort) IsValid() bool {
+	switch s {
+	case TransactionSortDate, TransactionSortAmount, TransactionSortDescription, TransactionSortCreatedAt, TransactionSortRelevance:
+		return true
+	}
+	return false
+}
+
+// TransactionQuery selects, orders and pages transactions
+// Every filter that's set must match; zero values don't filter.
+type TransactionQuery struct {
+	AccountID     string
+	CategoryID    string
+	Uncategorized bool       // Only normal transactions without a category
+	Since         *time.Time // Inclusive
+	Until         *time.Time // Inclusive
+	MinAmount     *int64     // In cents, compared with the amount's size so outflows match too
+	MaxAmount     *int64
+	Search        string          // Case-insensitive text in the description or note
+	Text          string          // Words that must all appear in the description, note or payee name
+	Sort          TransactionSort // Defaults to date
+	Ascending     bool            // Defaults to newest or largest first
+	Limit         int             // 0 returns every match
+	Offset        int
+}
+
+// TransactionBulkEdit is a change made to many transactions in one statement
+// Fields left nil, and a zero ShiftDays, leave the transactions as they are.
+type TransactionBulkEdit struct {
+	PayeeID   *string // An empty string clears the payee
+	AccountID *string
+	ShiftDays int // Moves each date by this many days, earlier when negative
+}
+
+// CategorySpending totals one category's outflows from one account within a month
+type CategorySpending struct {
+	CategoryID string
+	AccountID  string
+	Period     string // Period key, in UTC
+	Spent      int64  // Outflows, transfers included, as a positive number
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// TransferHint marks imported descriptions on an account as transfers to another account
+// A checking account's "ONLINE TRANSFER TO SAV" points at the savings account, so the
+// import links it to the savings side instead of treating it as spending.
+type TransferHint struct {
+	ID              string    `json:"id"`
+	AccountID       string    `json:"account_id"`        // Account whose imports the hint applies to
+	Pattern         string    `json:"pattern"`           // Case-insensitive substring of the description
+	TargetAccountID string    `json:"target_account_id"` // The other side of the transfer
+	CreatedAt       time.Time `json:"created_at"`
+}
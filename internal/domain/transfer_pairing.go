@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// TransferPairing remembers how transfer suggestions between two payees were reviewed
+// Transactions are re-imported and re-entered with new IDs, so scans look at who the two
+// sides were paid to or from: a pairing rejected more often than accepted isn't suggested
+// again, and one accepted more often is suggested ahead of other candidates.
+type TransferPairing struct {
+	ID            string    `json:"id"`
+	FromAccountID string    `json:"from_account_id"`
+	FromPayee     string    `json:"from_payee"` // The outflow's payee name, in lower case
+	ToAccountID   string    `json:"to_account_id"`
+	ToPayee       string    `json:"to_payee"` // The inflow's payee name, in lower case
+	Accepted      int       `json:"accepted"` // Suggestions accepted between the two
+	Rejected      int       `json:"rejected"` // Suggestions rejected between the two
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// TransferSuggestionStatus is where a transfer suggestion is in its review
+type TransferSuggestionStatus string
+
+const (
+	TransferSuggestionPending  TransferSuggestionStatus = "pending"
+	TransferSuggestionAccepted TransferSuggestionStatus = "accepted" // The transactions were linked as a transfer
+	TransferSuggestionRejected TransferSuggestionStatus = "rejected" // The pair isn't suggested again
+)
+
+// TransferSuggestion pairs two transactions that look like both sides of one transfer
+// Transfers entered by hand are often recorded as an outflow in one account and an inflow in
+// another; a scan finds those pairs and suggests linking them. Nothing changes until the
+// suggestion is accepted.
+type TransferSuggestion struct {
+	ID                string                   `json:"id"`
+	FromTransactionID string                   `json:"from_transaction_id"` // The outflow
+	ToTransactionID   string                   `json:"to_transaction_id"`   // The matching inflow in another account
+	Score             int                      `json:"score"`               // Higher is more likely
+	Status            TransferSuggestionStatus `json:"status"`
+	CreatedAt         time.Time                `json:"created_at"`
+	UpdatedAt         time.Time                `json:"updated_at"`
+}
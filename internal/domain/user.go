@@ -0,0 +1,106 @@
+package domain
+
+import "time"
+
+// UserRole determines what a signed-in user may do
+type UserRole string
+
+const (
+	UserRoleAdmin  UserRole = "admin"  // Full access, including tokens and user management
+	UserRoleMember UserRole = "member" // Read and write budget data
+)
+
+// IsValid reports whether the role is a known value
+func (r UserRole) IsValid() bool {
+	return r == UserRoleAdmin || r == UserRoleMember
+}
+
+// Access returns the API access level granted to the role
+func (r UserRole) Access() TokenAccess {
+	if r == UserRoleAdmin {
+		return TokenAccessAdmin
+	}
+	return TokenAccessWrite
+}
+
+// User is a person who can sign in to the budget
+type User struct {
+	ID              string     `json:"id"`
+	Email           string     `json:"email"`
+	Name            string     `json:"name"`
+	PasswordHash    string     `json:"-"`
+	Role            UserRole   `json:"role"`
+	TOTPSecret      string     `json:"-"`                           // Base32 TOTP secret; set during enrollment, before it is enabled
+	TOTPEnabled     bool       `json:"totp_enabled"`                // Sign-in requires a TOTP or recovery code
+	TOTPRequired    bool       `json:"totp_required"`               // The user must enroll before using the API
+	TOTPLastStep    int64      `json:"-"`                           // Last accepted TOTP time step, to reject replayed codes
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"` // Set once the user follows a verification link
+	DisabledAt      *time.Time `json:"disabled_at,omitempty"`       // Set by an admin to stop the user signing in
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// NeedsTOTPEnrollment reports whether two-factor is required but not yet set up
+func (u *User) NeedsTOTPEnrollment() bool {
+	return u.TOTPRequired && !u.TOTPEnabled
+}
+
+// IsDisabled reports whether an admin has stopped the user signing in
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
+// UserIdentity links a user to an account at an external identity provider
+type UserIdentity struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Issuer    string    `json:"issuer"`  // Identity provider, e.g. the OIDC issuer URL
+	Subject   string    `json:"subject"` // The user's stable ID at the provider
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RecoveryCode is a single-use code that can stand in for a TOTP code
+// Only a hash of the code is stored.
+type RecoveryCode struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	CodeHash  string     `json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// UserTokenPurpose identifies what a single-use emailed token is for
+type UserTokenPurpose string
+
+const (
+	UserTokenPasswordReset     UserTokenPurpose = "password_reset"
+	UserTokenEmailVerification UserTokenPurpose = "email_verification"
+)
+
+// UserToken is a single-use, expiring token sent to a user by email
+// Only a keyed hash of the token is stored.
+type UserToken struct {
+	ID        string           `json:"id"`
+	UserID    string           `json:"user_id"`
+	Purpose   UserTokenPurpose `json:"purpose"`
+	TokenHash string           `json:"-"`
+	Email     string           `json:"email"` // Address the token was sent to
+	ExpiresAt time.Time        `json:"expires_at"`
+	UsedAt    *time.Time       `json:"used_at,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// Session is a signed-in browser or device
+// Only a hash of the session token is stored.
+type Session struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"user_id"`
+	TokenHash  string    `json:"-"`
+	Device     string    `json:"device"` // Short description derived from the user agent, e.g. "Firefox on Linux"
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	Current    bool      `json:"current"` // Set when listing: true for the session making the request
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
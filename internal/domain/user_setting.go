@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// UserSetting is a per-user key/value setting
+// Values are stored as strings; structured settings are JSON encoded
+type UserSetting struct {
+	UserID    string    `json:"user_id"`
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// User setting keys that make up UserPreferences
+const (
+	UserSettingKeyLocale        = "locale"
+	UserSettingKeyDateFormat    = "date_format"
+	UserSettingKeyDefaultPeriod = "default_period"
+	UserSettingKeyPeriodType    = "period_type"
+	UserSettingKeyNotifications = "notifications"  // NotificationOptIns JSON document
+	UserSettingKeyDigestSentAt  = "digest_sent_at" // RFC3339 time the last notification digest covered up to
+
+	UserSettingKeyWeeklyDigestSentAt = "weekly_digest_sent_at" // RFC3339 time the last weekly budget digest went out
+)
+
+// DefaultPeriod selects which period the app opens on
+type DefaultPeriod string
+
+const (
+	DefaultPeriodCurrent  DefaultPeriod = "current"
+	DefaultPeriodPrevious DefaultPeriod = "previous"
+	DefaultPeriodNext     DefaultPeriod = "next"
+)
+
+// IsValid reports whether the default period is a known value
+func (p DefaultPeriod) IsValid() bool {
+	return p == DefaultPeriodCurrent || p == DefaultPeriodPrevious || p == DefaultPeriodNext
+}
+
+// DateFormats lists the supported date display formats
+var DateFormats = []string{"YYYY-MM-DD", "MM/DD/YYYY", "DD/MM/YYYY", "DD.MM.YYYY"}
+
+// UserPreferences are a user's personal display and notification preferences
+// Budget-wide settings live in Setting instead.
+type UserPreferences struct {
+	Locale        string             `json:"locale"`         // BCP 47 tag, e.g. "en-US"
+	DateFormat    string             `json:"date_format"`    // One of DateFormats
+	DefaultPeriod DefaultPeriod      `json:"default_period"` // Period shown when the app opens
+	PeriodType    PeriodType         `json:"period_type"`    // Budgeting by month, week (per paycheck) or quarter
+	Notifications NotificationOptIns `json:"notifications"`
+}
+
+// NotificationOptIns records which notifications a user wants to receive
+type NotificationOptIns struct {
+	WeeklyDigest        bool `json:"weekly_digest"`
+	Overspending        bool `json:"overspending"`
+	PendingTransactions bool `json:"pending_transactions"` // Imported transactions awaiting approval
+	SecurityAlerts      bool `json:"security_alerts"`      // New sign-ins, password and two-factor changes
+
+	Digest DigestFrequency    `json:"digest"`           // Batches notifications into one summary per period
+	Urgent []NotificationKind `json:"urgent,omitempty"` // Kinds still sent right away in digest mode
+}
+
+// Wants reports whether the user opted in to notifications of this kind
+func (o NotificationOptIns) Wants(kind NotificationKind) bool {
+	switch kind {
+	case NotificationPendingTransactions:
+		return o.PendingTransactions
+	case NotificationSecurityAlerts:
+		return o.SecurityAlerts
+	}
+	return false
+}
+
+// SendsImmediately reports whether notifications of this kind skip the digest
+func (o NotificationOptIns) SendsImmediately(kind NotificationKind) bool {
+	return o.Digest == "" || o.Digest == DigestOff || slices.Contains(o.Urgent, kind)
+}
+
+// NotificationKind names an event-driven notification, matching its opt-in field
+type NotificationKind string
+
+const (
+	NotificationPendingTransactions NotificationKind = "pending_transactions"
+	NotificationSecurityAlerts      NotificationKind = "security_alerts"
+)
+
+// IsValid reports whether the notification kind is known
+func (k NotificationKind) IsValid() bool {
+	return k == NotificationPendingTransactions || k == NotificationSecurityAlerts
+}
+
+// DigestFrequency sets how often batched notifications are sent
+type DigestFrequency string
+
+const (
+	DigestOff    DigestFrequency = "off" // Every notification is sent right away
+	DigestHourly DigestFrequency = "hourly"
+	DigestDaily  DigestFrequency = "daily"
+)
+
+// IsValid reports whether the digest frequency is a known value
+func (f DigestFrequency) IsValid() bool {
+	return f == DigestOff || f == DigestHourly || f == DigestDaily
+}
+
+// Interval returns the time between digests, or 0 when digests are off
+func (f DigestFrequency) Interval() time.Duration {
+	switch f {
+	case DigestHourly:
+		return time.Hour
+	case DigestDaily:
+		return 24 * time.Hour
+	}
+	return 0
+}
+
+// DefaultUserPreferences returns the preferences used until a user changes them
+func DefaultUserPreferences() *UserPreferences {
+	return &UserPreferences{
+		Locale:        "en-US",
+		DateFormat:    "YYYY-MM-DD",
+		DefaultPeriod: DefaultPeriodCurrent,
+		PeriodType:    PeriodMonth,
+		Notifications: NotificationOptIns{SecurityAlerts: true, Digest: DigestOff},
+	}
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// Fields a widget can show, chosen per widget
+// Percentages and names are the default kind; the amount fields reveal money and have to
+// be picked on purpose.
+const (
+	WidgetFieldCategoryName  = "category_name"
+	WidgetFieldGoalPercent   = "goal_percent"    // 0-100 towards the category's goal
+	WidgetFieldGoalOnTrack   = "goal_on_track"   // Whether this month's assignment keeps the goal on track
+	WidgetFieldGoalDuePeriod = "goal_due_period" // YYYY-MM the current target is due
+	WidgetFieldGoalFunded    = "goal_funded"     // Amount towards the target
+	WidgetFieldGoalTarget    = "goal_target"     // Amount of the target
+	WidgetFieldAvailable     = "available"       // Amount available in the category this month
+)
+
+// WidgetFields are the fields a widget can show, in the order they're documented
+var WidgetFields = []string{
+	WidgetFieldCategoryName, WidgetFieldGoalPercent, WidgetFieldGoalOnTrack, WidgetFieldGoalDuePeriod,
+	WidgetFieldGoalFunded, WidgetFieldGoalTarget, WidgetFieldAvailable,
+}
+
+// Widget is a read-only view of one category, such as a savings goal's progress, for
+// embedding in a website or stream overlay
+// Anyone with its token can read the fields it was given and nothing else; only a hash
+// of the token is stored.
+type Widget struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	CategoryID string    `json:"category_id"`
+	Fields     []string  `json:"fields"` // WidgetField values
+	TokenHash  string    `json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
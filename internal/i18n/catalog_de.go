@@ -0,0 +1,318 @@
+package i18n
+
+var german = map[string]string{
+	// Requests
+	"invalid request body":                                         "Ungültiger Anfrageinhalt",
+	"invalid form body":                                            "Ungültige Formulardaten",
+	"failed to read request body":                                  "Anfrageinhalt konnte nicht gelesen werden",
+	"account id is required":                                       "Konto-ID ist erforderlich",
+	"account_id is required":                                       "account_id ist erforderlich",
+	"category id is required":                                      "Kategorie-ID ist erforderlich",
+	"category group id is required":                                "Kategoriegruppen-ID ist erforderlich",
+	"transaction id is required":                                   "Buchungs-ID ist erforderlich",
+	"allocation id is required":                                    "Zuteilungs-ID ist erforderlich",
+	"transaction_ids is required":                                  "transaction_ids ist erforderlich",
+	"category_id and group_id are required":                        "category_id und group_id sind erforderlich",
+	"period query parameter is required":                           "Der Abfrageparameter period ist erforderlich",
+	"period is required (e.g., '2024-11')":                         "Zeitraum ist erforderlich (z. B. '2024-11')",
+	"invalid period format, expected YYYY-MM":                      "Ungültiges Zeitraumformat, erwartet JJJJ-MM",
+	"invalid period format, expected YYYY-MM, YYYY-Www or YYYY-Qn": "Ungültiges Zeitraumformat, erwartet JJJJ-MM, JJJJ-Www oder JJJJ-Qn",
+	"invalid period %q, expected YYYY-MM":                          "Ungültiger Zeitraum %q, erwartet JJJJ-MM",
+	"invalid date format, expected YYYY-MM-DD":                     "Ungültiges Datumsformat, erwartet JJJJ-MM-TT",
+	"invalid date format, use RFC3339":                             "Ungültiges Datumsformat, bitte RFC3339 verwenden",
+	"end period must not be before start period":                   "Der Endzeitraum darf nicht vor dem Startzeitraum liegen",
+	"view must be categories or groups":                            "view muss categories oder groups sein",
+	"invalid cursor":                                               "ungültiger Cursor",
+	"limit must be a positive number":                              "limit muss eine positive Zahl sein",
+	"invalid since, expected RFC3339 time":                         "ungültiges since, erwartet wird eine RFC3339-Zeit",
+	"invalid until, expected RFC3339 time":                         "ungültiges until, erwartet wird eine RFC3339-Zeit",
+	"file too large (max 10MB)":                                    "Datei zu groß (max. 10 MB)",
+	"invalid file type, must be .ofx, .qfx or .qif":                "Ungültiger Dateityp, erlaubt sind .ofx, .qfx und .qif",
+	"unrecognized file format, expected OFX, QFX or QIF":           "Unbekanntes Dateiformat, erwartet wird OFX, QFX oder QIF",
+	"invalid file type, must be .csv":                              "Ungültiger Dateityp, erlaubt ist .csv",
+	"invert_amounts must be true or false":                         "invert_amounts muss true oder false sein",
+	"failed to read uploaded file":                                 "Hochgeladene Datei konnte nicht gelesen werden",
+	"Failed to process allocation request":                         "Zuteilung konnte nicht verarbeitet werden",
+	"Failed to load category inspector":                            "Kategorie-Inspektor konnte nicht geladen werden",
+	"Failed to preview period close":                               "Monatsabschluss-Vorschau konnte nicht erstellt werden",
+	"Failed to reconcile card payment":                             "Kartenzahlung konnte nicht abgeglichen werden",
+	"statement_balance must be a whole number of cents":            "statement_balance muss eine ganze Zahl in Cent sein",
+	"statement_balance must not be negative":                       "statement_balance darf nicht negativ sein",
+	"account is not a credit card":                                 "Konto ist keine Kreditkarte",
+
+	// Not found
+	"account not found":             "Konto nicht gefunden",
+	"account not found: %s":         "Konto nicht gefunden: %s",
+	"category not found":            "Kategorie nicht gefunden",
+	"category not found: %s":        "Kategorie nicht gefunden: %s",
+	"category group not found":      "Kategoriegruppe nicht gefunden",
+	"transaction not found":         "Buchung nicht gefunden",
+	"allocation not found":          "Zuteilung nicht gefunden",
+	"pending transaction not found": "Ausstehende Buchung nicht gefunden",
+	"user not found":                "Benutzer nicht gefunden",
+	"session not found":             "Sitzung nicht gefunden",
+	"API token not found":           "API-Token nicht gefunden",
+	"plugin report not found":       "Plugin-Bericht nicht gefunden",
+
+	// Validation
+	"name is required":                            "Name ist erforderlich",
+	"account name is required":                    "Kontoname ist erforderlich",
+	"category name is required":                   "Kategoriename ist erforderlich",
+	"category group name is required":             "Name der Kategoriegruppe ist erforderlich",
+	"invalid account type":                        "Ungültiger Kontotyp",
+	"amount is required":                          "Betrag ist erforderlich",
+	"amount must be non-zero":                     "Betrag darf nicht null sein",
+	"invalid amount %q":                           "Ungültiger Betrag %q",
+	"allocation amount must be non-negative":      "Zuteilungsbetrag darf nicht negativ sein",
+	"cannot transfer to the same account":         "Umbuchung auf dasselbe Konto nicht möglich",
+	"transfer amount must be positive":            "Umbuchungsbetrag muss positiv sein",
+	"rewards are only tracked on credit accounts": "Prämien werden nur bei Kreditkartenkonten geführt",
+	"insufficient rewards balance":                "Prämienguthaben reicht nicht aus",
+	"category is not overspent":                   "Kategorie ist nicht überzogen",
+	"payment categories can't be used here; use cover-underfunded instead":                                "Zahlungskategorien können hier nicht verwendet werden; verwende stattdessen cover-underfunded",
+	"insufficient funds in donor category":                                                                "Nicht genügend Geld in der Spenderkategorie",
+	"a category can't cover its own overspending":                                                         "Eine Kategorie kann ihre eigene Überziehung nicht decken",
+	"redemption amount must be positive":                                                                  "Einlösebetrag muss positiv sein",
+	"category is required for outflow transactions":                                                       "Für Ausgaben ist eine Kategorie erforderlich",
+	"a note explaining the adjustment is required":                                                        "Eine Notiz, die die Korrektur erklärt, ist erforderlich",
+	"adjustments can't have a category":                                                                   "Korrekturen können keine Kategorie haben",
+	"a starting balance can't have a category":                                                            "Ein Anfangssaldo kann keine Kategorie haben",
+	"cannot delete the Credit Card Payments group":                                                        "Die Gruppe Kreditkartenzahlungen kann nicht gelöscht werden",
+	"cannot delete category group: it contains %d categories. Please move or delete all categories first": "Kategoriegruppe kann nicht gelöscht werden: Sie enthält %d Kategorien. Bitte verschieben oder löschen Sie zuerst alle Kategorien",
+	"pending transaction is already %s":                                                                   "Ausstehende Buchung ist bereits %s",
+	"account is required to approve this transaction":                                                     "Zum Bestätigen dieser Buchung ist ein Konto erforderlich",
+	"no categorize script is set":                                                                         "Es ist kein Kategorisierungsskript hinterlegt",
+	"script must define %s(txn)":                                                                          "Das Skript muss %s(txn) definieren",
+	"%s must return a category name, ID or None, got %v":                                                  "%s muss einen Kategorienamen, eine ID oder None zurückgeben, erhalten: %v",
+
+	// Category goals
+	"goal not found":                          "Ziel nicht gefunden",
+	"category already has a goal":             "Die Kategorie hat bereits ein Ziel",
+	"payment categories can't have goals":     "Zahlungskategorien können keine Ziele haben",
+	"target_amount must be positive":          "target_amount muss positiv sein",
+	"yearly goals need a target_date":         "Jährliche Ziele brauchen ein target_date",
+	"cadence must be once, monthly or yearly": "cadence muss once, monthly oder yearly sein",
+	"target_date must be YYYY-MM":             "target_date muss das Format JJJJ-MM haben",
+
+	// Payees
+	"payee not found":                                      "Zahlungsempfänger nicht gefunden",
+	"payee rule not found":                                 "Empfängerregel nicht gefunden",
+	"pattern is required":                                  "Muster ist erforderlich",
+	"invalid pattern: %v":                                  "Ungültiges Muster: %v",
+	"match_type must be contains, exact or regex":          "match_type muss contains, exact oder regex sein",
+	"a payee rule needs a payee_name, category_id or note": "Eine Empfängerregel braucht payee_name, category_id oder note",
+	"pattern is required unless the rule has an account or amount condition": "Muster ist erforderlich, außer die Regel hat eine Konto- oder Betragsbedingung",
+	"min_amount and max_amount cannot be negative":                           "min_amount und max_amount dürfen nicht negativ sein",
+	"payee rules can't be run against past transactions here":                "Empfängerregeln können hier nicht auf frühere Buchungen angewendet werden",
+	"there are no payee rules to run":                                        "Es gibt keine Empfängerregeln zum Ausführen",
+
+	// Funding a period
+	"basis must be budgeted_last_month, spent_last_month or average_spent": "basis muss budgeted_last_month, spent_last_month oder average_spent sein",
+	"scale_percent must be between 1 and %d":                               "scale_percent muss zwischen 1 und %d liegen",
+
+	// Allocation templates
+	"allocation template not found":                        "Zuteilungsvorlage nicht gefunden",
+	"an allocation template with this name already exists": "Es gibt bereits eine Zuteilungsvorlage mit diesem Namen",
+	"a template needs at least one category":               "Eine Vorlage braucht mindestens eine Kategorie",
+	"template amounts must be positive":                    "Vorlagenbeträge müssen positiv sein",
+	"a category can only appear once in a template":        "Eine Kategorie darf in einer Vorlage nur einmal vorkommen",
+
+	// Account details
+	"account number may only contain digits":        "Die Kontonummer darf nur Ziffern enthalten",
+	"account number must have at least four digits": "Die Kontonummer muss mindestens vier Ziffern haben",
+	"interest rate must be between 0 and 100%":      "Der Zinssatz muss zwischen 0 und 100 % liegen",
+
+	// Transfer hints
+	"transfer hint not found": "Umbuchungshinweis nicht gefunden",
+
+	// Overspending modes
+	"invalid overspending_mode - must be auto, cash, or credit": "Ungültiger overspending_mode - erlaubt sind auto, cash oder credit",
+
+	// One-sided transfers
+	"transaction is not a one-sided transfer": "Buchung ist keine einseitige Umbuchung",
+
+	// Transaction dates
+	"transaction date %s is more than %d years in the past":  "Buchungsdatum %s liegt mehr als %d Jahre in der Vergangenheit",
+	"transaction date %s is more than %d days in the future": "Buchungsdatum %s liegt mehr als %d Tage in der Zukunft",
+
+	// Transaction search
+	"invalid sort - must be date, amount, description, created_at, or relevance": "Ungültige Sortierung - erlaubt sind date, amount, description, created_at oder relevance",
+	"limit and offset cannot be negative":                                        "limit und offset dürfen nicht negativ sein",
+	"min_amount cannot be more than max_amount":                                  "min_amount darf nicht größer als max_amount sein",
+	"start_date must be before end_date":                                         "start_date muss vor end_date liegen",
+	"order must be asc or desc":                                                  "order muss asc oder desc sein",
+	"%s must be a whole number of cents":                                         "%s muss eine ganze Zahl von Cent sein",
+	"%s must be a number":                                                        "%s muss eine Zahl sein",
+	"q is required":                                                              "q ist erforderlich",
+
+	// API token guardrails
+	"API token has been disabled":                   "API-Token wurde deaktiviert",
+	"daily write quota exceeded for this API token": "Tägliches Schreibkontingent für diesen API-Token ist aufgebraucht",
+	"daily_write_quota cannot be negative":          "daily_write_quota darf nicht negativ sein",
+
+	// Budget templates
+	"not a budget template":                                          "Keine Budgetvorlage",
+	"unsupported budget template version %d":                         "Nicht unterstützte Version %d der Budgetvorlage",
+	"a budget template can't include the Credit Card Payments group": "Eine Budgetvorlage darf die Gruppe Kreditkartenzahlungen nicht enthalten",
+	"monthly_amount cannot be negative":                              "monthly_amount darf nicht negativ sein",
+	"months must be between 1 and %d":                                "months muss zwischen 1 und %d liegen",
+
+	// Import review
+	"import not found":                                "Import nicht gefunden",
+	"import row not found":                            "Importzeile nicht gefunden",
+	"import is no longer waiting for review":          "Der Import wartet nicht mehr auf Prüfung",
+	"only rows waiting to be imported can be changed": "Nur Zeilen, die auf den Import warten, können geändert werden",
+	"only committed imports can be undone":            "Nur übernommene Importe können rückgängig gemacht werden",
+	"status must be staged, committed or undone":      "status muss staged, committed oder undone sein",
+
+	// Diagnostics
+	"ready to assign totals aren't kept, so there's nothing to rebuild": "Ready-to-Assign-Summen werden nicht geführt, daher gibt es nichts neu zu berechnen",
+
+	// Bulk category creation
+	"at least one category is required":            "Mindestens eine Kategorie ist erforderlich",
+	"at most %d categories can be created at once": "Es können höchstens %d Kategorien auf einmal erstellt werden",
+
+	// Loans
+	"account is not a loan":           "Konto ist kein Kredit",
+	"loan has no monthly payment set": "Für den Kredit ist keine Monatsrate festgelegt",
+	"monthly payment doesn't cover the interest, so the loan would never be repaid": "Die Monatsrate deckt die Zinsen nicht, der Kredit würde also nie getilgt",
+	"monthly payment must be positive":                                              "Die Monatsrate muss positiv sein",
+	"payment amount must be positive":                                               "Der Zahlungsbetrag muss positiv sein",
+	"interest must be between zero and the payment amount":                          "Die Zinsen müssen zwischen null und dem Zahlungsbetrag liegen",
+	"payment is more than the %s owed":                                              "Die Zahlung ist höher als die geschuldeten %s",
+	"set the loan's interest category before paying interest":                       "Lege die Zinskategorie des Kredits fest, bevor du Zinsen zahlst",
+	"interest can't be spent from a payment category":                               "Zinsen können nicht aus einer Zahlungskategorie ausgegeben werden",
+	"from_account_id is required":                                                   "from_account_id ist erforderlich",
+	"invalid start, expected YYYY-MM":                                               "Ungültiger start, erwartet JJJJ-MM",
+
+	// Tracking accounts
+	"tracking accounts are off budget; update their balance instead":                                            "Tracking-Konten sind außerhalb des Budgets; aktualisiere stattdessen ihren Saldo",
+	"account is not a tracking account":                                                                         "Konto ist kein Tracking-Konto",
+	"money from a tracking account is income: record it as an inflow and update the tracking account's balance": "Geld von einem Tracking-Konto ist Einkommen: erfasse es als Zufluss und aktualisiere den Saldo des Tracking-Kontos",
+	"accounts can't be converted between budget and tracking accounts":                                          "Konten können nicht zwischen Budget- und Tracking-Konten umgewandelt werden",
+
+	// Transfer suggestions
+	"transfer suggestion not found":                             "Umbuchungsvorschlag nicht gefunden",
+	"the suggested transactions no longer look like a transfer": "Die vorgeschlagenen Buchungen sehen nicht mehr wie eine Umbuchung aus",
+	"transfer pairing not found":                                "Umbuchungspaar nicht gefunden",
+	"transfer suggestion is already %s":                         "Umbuchungsvorschlag ist bereits %s",
+	"status must be pending, accepted or rejected":              "Der Status muss pending, accepted oder rejected sein",
+	"end must not be before start":                              "Das Ende darf nicht vor dem Anfang liegen",
+	"transfer match window must be at least 1 day":              "Das Zeitfenster für Umbuchungen muss mindestens 1 Tag betragen",
+	"transfer amount tolerance cannot be negative":              "Die Betragstoleranz für Umbuchungen darf nicht negativ sein",
+
+	// Bulk transaction edits
+	"no transaction IDs provided":                               "Keine Buchungs-IDs angegeben",
+	"at most %d transactions can be edited at once":             "Es können höchstens %d Buchungen auf einmal bearbeitet werden",
+	"nothing to change: set payee_id, account_id or shift_days": "Nichts zu ändern: payee_id, account_id oder shift_days angeben",
+	"payees can't be set here":                                  "Zahlungsempfänger können hier nicht gesetzt werden",
+	"only normal transactions can be moved to another account":  "Nur normale Buchungen können in ein anderes Konto verschoben werden",
+
+	// Category suggestions
+	"only normal transactions have categories": "Nur normale Buchungen haben Kategorien",
+
+	// Sign-in and accounts
+	"authentication required":                                            "Anmeldung erforderlich",
+	"sign in required":                                                   "Anmeldung erforderlich",
+	"invalid email or password":                                          "E-Mail-Adresse oder Passwort ist falsch",
+	"invalid password":                                                   "Falsches Passwort",
+	"invalid session":                                                    "Ungültige Sitzung",
+	"session has expired":                                                "Sitzung ist abgelaufen",
+	"this account has been disabled":                                     "Dieses Konto wurde deaktiviert",
+	"invalid API token":                                                  "Ungültiges API-Token",
+	"API token has expired":                                              "API-Token ist abgelaufen",
+	"%s access is required":                                              "%s-Zugriff ist erforderlich",
+	"a valid email is required":                                          "Eine gültige E-Mail-Adresse ist erforderlich",
+	"a user with this email already exists":                              "Es gibt bereits einen Benutzer mit dieser E-Mail-Adresse",
+	"role must be admin or member":                                       "Rolle muss admin oder member sein",
+	"password must be at least %d characters":                            "Das Passwort muss mindestens %d Zeichen lang sein",
+	"two-factor code required":                                           "Bestätigungscode erforderlich",
+	"invalid two-factor code":                                            "Ungültiger Bestätigungscode",
+	"sign-in expired, please try again":                                  "Anmeldung abgelaufen, bitte erneut versuchen",
+	"start two-factor setup first":                                       "Bitte zuerst die Zwei-Faktor-Einrichtung starten",
+	"two-factor authentication is not enabled":                           "Zwei-Faktor-Authentifizierung ist nicht aktiviert",
+	"two-factor authentication is already enabled":                       "Zwei-Faktor-Authentifizierung ist bereits aktiviert",
+	"two-factor authentication is required for this account":             "Für dieses Konto ist Zwei-Faktor-Authentifizierung vorgeschrieben",
+	"two-factor authentication must be set up before using this account": "Richten Sie zuerst die Zwei-Faktor-Authentifizierung ein, um dieses Konto zu nutzen",
+	"invalid or expired link":                                            "Ungültiger oder abgelaufener Link",
+	"email is not configured":                                            "E-Mail-Versand ist nicht eingerichtet",
+	"email is already verified":                                          "E-Mail-Adresse ist bereits bestätigt",
+	"single sign-on is not configured":                                   "Single Sign-on ist nicht eingerichtet",
+	"your account is not a member of this budget":                        "Ihr Konto gehört nicht zu diesem Budget",
+	"no budget account exists for this user":                             "Für diesen Benutzer gibt es kein Budget-Konto",
+	"token name is required":                                             "Token-Name ist erforderlich",
+	"access must be read, write, or admin":                               "Zugriff muss read, write oder admin sein",
+	"expires_at must be in the future":                                   "expires_at muss in der Zukunft liegen",
+	"locale must be a language tag such as en-US":                        "Die Sprache muss ein Sprachcode wie de-DE sein",
+	"default_period must be current, previous or next":                   "default_period muss current, previous oder next sein",
+	"period_type must be month, week or quarter":                         "period_type muss month, week oder quarter sein",
+	"notifications.digest must be off, hourly or daily":                  "notifications.digest muss off, hourly oder daily sein",
+	"notifications.urgent may only list pending_transactions and security_alerts": "notifications.urgent darf nur pending_transactions und security_alerts enthalten",
+	"too many sign-in attempts, please try again later":                           "Zu viele Anmeldeversuche, bitte später erneut versuchen",
+	"too many wrong two-factor codes, please try again later":                     "Zu viele falsche Bestätigungscodes, bitte später erneut versuchen",
+	"the budget has already been set up":                                          "Das Budget wurde bereits eingerichtet",
+	"unknown starter template %q":                                                 "Unbekannte Startvorlage %q",
+	"this setup step is already complete":                                         "Dieser Einrichtungsschritt ist bereits abgeschlossen",
+
+	// Starter template categories
+	"Housing & Bills":                      "Wohnen & Rechnungen",
+	"Home, utilities, and recurring bills": "Wohnung, Nebenkosten und laufende Rechnungen",
+	"Utilities":                            "Nebenkosten",
+	"Electric, water, gas, internet":       "Strom, Wasser, Gas, Internet",
+	"Phone":                                "Telefon",
+	"Cell phone bill":                      "Handyrechnung",
+	"Transportation":                       "Mobilität",
+	"Vehicle and travel expenses":          "Fahrzeug- und Fahrtkosten",
+	"Gas/Fuel":                             "Kraftstoff",
+	"Gasoline, fuel, public transit":       "Benzin, Diesel, öffentlicher Nahverkehr",
+	"Car Payment & Insurance":              "Autokredit & Versicherung",
+	"Auto loan and insurance":              "Autofinanzierung und Versicherung",
+	"Food & Dining":                        "Essen & Trinken",
+	"Groceries and eating out":             "Lebensmittel und Auswärtsessen",
+	"Groceries":                            "Lebensmittel",
+	"Supermarket and grocery stores":       "Supermarkt und Lebensmittelgeschäfte",
+	"Restaurants":                          "Restaurants",
+	"Dining out, coffee, etc.":             "Auswärts essen, Kaffee usw.",
+	"Personal & Lifestyle":                 "Persönliches & Freizeit",
+	"Personal care, entertainment, and subscriptions": "Körperpflege, Unterhaltung und Abonnements",
+	"Shopping":                                "Einkäufe",
+	"Clothing, personal items":                "Kleidung, persönliche Dinge",
+	"Entertainment":                           "Unterhaltung",
+	"Movies, hobbies, streaming services":     "Kino, Hobbys, Streamingdienste",
+	"Other":                                   "Sonstiges",
+	"Everything else":                         "Alles andere",
+	"Miscellaneous":                           "Verschiedenes",
+	"Uncategorized expenses":                  "Nicht zugeordnete Ausgaben",
+	"Rent":                                    "Miete",
+	"Monthly rent payment":                    "Monatliche Miete",
+	"Renters Insurance":                       "Hausratversicherung",
+	"Coverage for your belongings":            "Versicherung für Ihr Hab und Gut",
+	"Mortgage":                                "Immobilienkredit",
+	"Monthly mortgage payment":                "Monatliche Kreditrate",
+	"Property Taxes":                          "Grundsteuer",
+	"Property tax installments":               "Grundsteuerzahlungen",
+	"Home Insurance":                          "Wohngebäudeversicherung",
+	"Homeowners insurance":                    "Versicherung für das eigene Haus",
+	"Home Maintenance":                        "Instandhaltung",
+	"Keeping the house in shape":              "Damit das Haus in Schuss bleibt",
+	"Repairs & Upkeep":                        "Reparaturen & Pflege",
+	"Repairs, appliances and yard work":       "Reparaturen, Haushaltsgeräte und Garten",
+	"Savings":                                 "Sparen",
+	"Money set aside for later":               "Geld für später",
+	"Emergency Fund":                          "Notgroschen",
+	"Savings for unexpected expenses":         "Rücklage für unerwartete Ausgaben",
+	"School":                                  "Studium",
+	"Tuition and study costs":                 "Studiengebühren und Lernkosten",
+	"Tuition & Fees":                          "Studiengebühren",
+	"Tuition, registration and lab fees":      "Studien-, Einschreibe- und Laborgebühren",
+	"Books & Supplies":                        "Bücher & Material",
+	"Textbooks, software and supplies":        "Lehrbücher, Software und Material",
+	"Essentials":                              "Grundbedarf",
+	"Bills and everyday needs":                "Rechnungen und täglicher Bedarf",
+	"Bills":                                   "Rechnungen",
+	"Rent, utilities and other regular bills": "Miete, Nebenkosten und andere regelmäßige Rechnungen",
+	"Spending":                                "Ausgaben",
+	"Everything that isn't a bill":            "Alles, was keine Rechnung ist",
+	"Spending Money":                          "Taschengeld",
+	"Eating out, shopping and fun":            "Auswärts essen, Einkaufen und Freizeit",
+}
@@ -0,0 +1,318 @@
+package i18n
+
+var spanish = map[string]string{
+	// Requests
+	"invalid request body":                                         "Cuerpo de la solicitud no válido",
+	"invalid form body":                                            "Datos del formulario no válidos",
+	"failed to read request body":                                  "No se pudo leer el cuerpo de la solicitud",
+	"account id is required":                                       "El ID de la cuenta es obligatorio",
+	"account_id is required":                                       "account_id es obligatorio",
+	"category id is required":                                      "El ID de la categoría es obligatorio",
+	"category group id is required":                                "El ID del grupo de categorías es obligatorio",
+	"transaction id is required":                                   "El ID de la transacción es obligatorio",
+	"allocation id is required":                                    "El ID de la asignación es obligatorio",
+	"transaction_ids is required":                                  "transaction_ids es obligatorio",
+	"category_id and group_id are required":                        "category_id y group_id son obligatorios",
+	"period query parameter is required":                           "El parámetro de consulta period es obligatorio",
+	"period is required (e.g., '2024-11')":                         "El periodo es obligatorio (p. ej., '2024-11')",
+	"invalid period format, expected YYYY-MM":                      "Formato de periodo no válido, se esperaba AAAA-MM",
+	"invalid period format, expected YYYY-MM, YYYY-Www or YYYY-Qn": "Formato de periodo no válido, se esperaba AAAA-MM, AAAA-Www o AAAA-Qn",
+	"invalid period %q, expected YYYY-MM":                          "Periodo %q no válido, se esperaba AAAA-MM",
+	"invalid date format, expected YYYY-MM-DD":                     "Formato de fecha no válido, se esperaba AAAA-MM-DD",
+	"invalid date format, use RFC3339":                             "Formato de fecha no válido, use RFC3339",
+	"end period must not be before start period":                   "El periodo final no puede ser anterior al inicial",
+	"view must be categories or groups":                            "view debe ser categories o groups",
+	"invalid cursor":                                               "cursor no válido",
+	"limit must be a positive number":                              "limit debe ser un número positivo",
+	"invalid since, expected RFC3339 time":                         "since no válido, se esperaba una hora RFC3339",
+	"invalid until, expected RFC3339 time":                         "until no válido, se esperaba una hora RFC3339",
+	"file too large (max 10MB)":                                    "Archivo demasiado grande (máx. 10 MB)",
+	"invalid file type, must be .ofx, .qfx or .qif":                "Tipo de archivo no válido, debe ser .ofx, .qfx o .qif",
+	"unrecognized file format, expected OFX, QFX or QIF":           "Formato de archivo no reconocido, se esperaba OFX, QFX o QIF",
+	"invalid file type, must be .csv":                              "Tipo de archivo no válido, debe ser .csv",
+	"invert_amounts must be true or false":                         "invert_amounts debe ser true o false",
+	"failed to read uploaded file":                                 "No se pudo leer el archivo subido",
+	"Failed to process allocation request":                         "No se pudo procesar la asignación",
+	"Failed to load category inspector":                            "No se pudo cargar el inspector de la categoría",
+	"Failed to preview period close":                               "No se pudo generar la vista previa del cierre del periodo",
+	"Failed to reconcile card payment":                             "No se pudo conciliar el pago de la tarjeta",
+	"statement_balance must be a whole number of cents":            "statement_balance debe ser un número entero de centavos",
+	"statement_balance must not be negative":                       "statement_balance no puede ser negativo",
+	"account is not a credit card":                                 "la cuenta no es una tarjeta de crédito",
+
+	// Not found
+	"account not found":             "Cuenta no encontrada",
+	"account not found: %s":         "Cuenta no encontrada: %s",
+	"category not found":            "Categoría no encontrada",
+	"category not found: %s":        "Categoría no encontrada: %s",
+	"category group not found":      "Grupo de categorías no encontrado",
+	"transaction not found":         "Transacción no encontrada",
+	"allocation not found":          "Asignación no encontrada",
+	"pending transaction not found": "Transacción pendiente no encontrada",
+	"user not found":                "Usuario no encontrado",
+	"session not found":             "Sesión no encontrada",
+	"API token not found":           "Token de API no encontrado",
+	"plugin report not found":       "Informe del plugin no encontrado",
+
+	// Validation
+	"name is required":                            "El nombre es obligatorio",
+	"account name is required":                    "El nombre de la cuenta es obligatorio",
+	"category name is required":                   "El nombre de la categoría es obligatorio",
+	"category group name is required":             "El nombre del grupo de categorías es obligatorio",
+	"invalid account type":                        "Tipo de cuenta no válido",
+	"amount is required":                          "El importe es obligatorio",
+	"amount must be non-zero":                     "El importe no puede ser cero",
+	"invalid amount %q":                           "Importe %q no válido",
+	"allocation amount must be non-negative":      "El importe asignado no puede ser negativo",
+	"cannot transfer to the same account":         "No se puede transferir a la misma cuenta",
+	"transfer amount must be positive":            "El importe de la transferencia debe ser positivo",
+	"rewards are only tracked on credit accounts": "las recompensas solo se registran en cuentas de crédito",
+	"insufficient rewards balance":                "saldo de recompensas insuficiente",
+	"category is not overspent":                   "la categoría no está en descubierto",
+	"payment categories can't be used here; use cover-underfunded instead":                                "las categorías de pago no se pueden usar aquí; usa cover-underfunded en su lugar",
+	"insufficient funds in donor category":                                                                "fondos insuficientes en la categoría donante",
+	"a category can't cover its own overspending":                                                         "una categoría no puede cubrir su propio descubierto",
+	"redemption amount must be positive":                                                                  "el importe a canjear debe ser positivo",
+	"category is required for outflow transactions":                                                       "Los gastos necesitan una categoría",
+	"a note explaining the adjustment is required":                                                        "Se requiere una nota que explique el ajuste",
+	"adjustments can't have a category":                                                                   "Los ajustes no pueden tener categoría",
+	"a starting balance can't have a category":                                                            "Un saldo inicial no puede tener categoría",
+	"cannot delete the Credit Card Payments group":                                                        "No se puede eliminar el grupo de pagos de tarjetas de crédito",
+	"cannot delete category group: it contains %d categories. Please move or delete all categories first": "No se puede eliminar el grupo: contiene %d categorías. Mueva o elimine primero todas las categorías",
+	"pending transaction is already %s":                                                                   "La transacción pendiente ya está %s",
+	"account is required to approve this transaction":                                                     "Se necesita una cuenta para aprobar esta transacción",
+	"no categorize script is set":                                                                         "No hay ningún script de categorización configurado",
+	"script must define %s(txn)":                                                                          "El script debe definir %s(txn)",
+	"%s must return a category name, ID or None, got %v":                                                  "%s debe devolver un nombre de categoría, un ID o None; se obtuvo %v",
+
+	// Category goals
+	"goal not found":                          "Objetivo no encontrado",
+	"category already has a goal":             "La categoría ya tiene un objetivo",
+	"payment categories can't have goals":     "Las categorías de pago no pueden tener objetivos",
+	"target_amount must be positive":          "target_amount debe ser positivo",
+	"yearly goals need a target_date":         "Los objetivos anuales necesitan un target_date",
+	"cadence must be once, monthly or yearly": "cadence debe ser once, monthly o yearly",
+	"target_date must be YYYY-MM":             "target_date debe tener el formato AAAA-MM",
+
+	// Payees
+	"payee not found":                                      "Beneficiario no encontrado",
+	"payee rule not found":                                 "Regla de beneficiario no encontrada",
+	"pattern is required":                                  "El patrón es obligatorio",
+	"invalid pattern: %v":                                  "Patrón no válido: %v",
+	"match_type must be contains, exact or regex":          "match_type debe ser contains, exact o regex",
+	"a payee rule needs a payee_name, category_id or note": "Una regla de beneficiario necesita payee_name, category_id o note",
+	"pattern is required unless the rule has an account or amount condition": "El patrón es obligatorio salvo que la regla tenga una condición de cuenta o importe",
+	"min_amount and max_amount cannot be negative":                           "min_amount y max_amount no pueden ser negativos",
+	"payee rules can't be run against past transactions here":                "Las reglas de beneficiario no se pueden aplicar aquí a transacciones anteriores",
+	"there are no payee rules to run":                                        "No hay reglas de beneficiario que aplicar",
+
+	// Funding a period
+	"basis must be budgeted_last_month, spent_last_month or average_spent": "basis debe ser budgeted_last_month, spent_last_month o average_spent",
+	"scale_percent must be between 1 and %d":                               "scale_percent debe estar entre 1 y %d",
+
+	// Allocation templates
+	"allocation template not found":                        "plantilla de asignación no encontrada",
+	"an allocation template with this name already exists": "Ya existe una plantilla de asignación con este nombre",
+	"a template needs at least one category":               "Una plantilla necesita al menos una categoría",
+	"template amounts must be positive":                    "Los importes de la plantilla deben ser positivos",
+	"a category can only appear once in a template":        "Una categoría solo puede aparecer una vez en una plantilla",
+
+	// Account details
+	"account number may only contain digits":        "El número de cuenta solo puede contener dígitos",
+	"account number must have at least four digits": "El número de cuenta debe tener al menos cuatro dígitos",
+	"interest rate must be between 0 and 100%":      "La tasa de interés debe estar entre 0 y 100 %",
+
+	// Transfer hints
+	"transfer hint not found": "Pista de transferencia no encontrada",
+
+	// Overspending modes
+	"invalid overspending_mode - must be auto, cash, or credit": "overspending_mode no válido: debe ser auto, cash o credit",
+
+	// One-sided transfers
+	"transaction is not a one-sided transfer": "La transacción no es una transferencia unilateral",
+
+	// Transaction dates
+	"transaction date %s is more than %d years in the past":  "La fecha de la transacción %s es de hace más de %d años",
+	"transaction date %s is more than %d days in the future": "La fecha de la transacción %s está a más de %d días en el futuro",
+
+	// Transaction search
+	"invalid sort - must be date, amount, description, created_at, or relevance": "Orden no válido: debe ser date, amount, description, created_at o relevance",
+	"limit and offset cannot be negative":                                        "limit y offset no pueden ser negativos",
+	"min_amount cannot be more than max_amount":                                  "min_amount no puede ser mayor que max_amount",
+	"start_date must be before end_date":                                         "start_date debe ser anterior a end_date",
+	"order must be asc or desc":                                                  "order debe ser asc o desc",
+	"%s must be a whole number of cents":                                         "%s debe ser un número entero de céntimos",
+	"%s must be a number":                                                        "%s debe ser un número",
+	"q is required":                                                              "q es obligatorio",
+
+	// API token guardrails
+	"API token has been disabled":                   "El token de API ha sido desactivado",
+	"daily write quota exceeded for this API token": "Se ha superado la cuota diaria de escrituras de este token de API",
+	"daily_write_quota cannot be negative":          "daily_write_quota no puede ser negativo",
+
+	// Budget templates
+	"not a budget template":                                          "No es una plantilla de presupuesto",
+	"unsupported budget template version %d":                         "Versión %d de la plantilla de presupuesto no compatible",
+	"a budget template can't include the Credit Card Payments group": "Una plantilla de presupuesto no puede incluir el grupo de pagos de tarjetas de crédito",
+	"monthly_amount cannot be negative":                              "monthly_amount no puede ser negativo",
+	"months must be between 1 and %d":                                "months debe estar entre 1 y %d",
+
+	// Import review
+	"import not found":                                "Importación no encontrada",
+	"import row not found":                            "Fila de importación no encontrada",
+	"import is no longer waiting for review":          "La importación ya no está pendiente de revisión",
+	"only rows waiting to be imported can be changed": "Solo se pueden cambiar las filas pendientes de importar",
+	"only committed imports can be undone":            "Solo se pueden deshacer las importaciones confirmadas",
+	"status must be staged, committed or undone":      "status debe ser staged, committed o undone",
+
+	// Diagnostics
+	"ready to assign totals aren't kept, so there's nothing to rebuild": "Los totales de Listo para asignar no se mantienen, así que no hay nada que reconstruir",
+
+	// Bulk category creation
+	"at least one category is required":            "Se requiere al menos una categoría",
+	"at most %d categories can be created at once": "Se pueden crear como máximo %d categorías a la vez",
+
+	// Loans
+	"account is not a loan":           "La cuenta no es un préstamo",
+	"loan has no monthly payment set": "El préstamo no tiene una cuota mensual definida",
+	"monthly payment doesn't cover the interest, so the loan would never be repaid": "La cuota mensual no cubre los intereses, así que el préstamo nunca se pagaría",
+	"monthly payment must be positive":                                              "La cuota mensual debe ser positiva",
+	"payment amount must be positive":                                               "El importe del pago debe ser positivo",
+	"interest must be between zero and the payment amount":                          "Los intereses deben estar entre cero y el importe del pago",
+	"payment is more than the %s owed":                                              "El pago supera los %s adeudados",
+	"set the loan's interest category before paying interest":                       "Define la categoría de intereses del préstamo antes de pagar intereses",
+	"interest can't be spent from a payment category":                               "Los intereses no pueden gastarse de una categoría de pago",
+	"from_account_id is required":                                                   "from_account_id es obligatorio",
+	"invalid start, expected YYYY-MM":                                               "start no válido, se esperaba AAAA-MM",
+
+	// Tracking accounts
+	"tracking accounts are off budget; update their balance instead":                                            "Las cuentas de seguimiento están fuera del presupuesto; actualiza su saldo en su lugar",
+	"account is not a tracking account":                                                                         "La cuenta no es una cuenta de seguimiento",
+	"money from a tracking account is income: record it as an inflow and update the tracking account's balance": "El dinero de una cuenta de seguimiento es un ingreso: regístralo como entrada y actualiza el saldo de la cuenta de seguimiento",
+	"accounts can't be converted between budget and tracking accounts":                                          "Las cuentas no pueden convertirse entre cuentas de presupuesto y de seguimiento",
+
+	// Transfer suggestions
+	"transfer suggestion not found":                             "Sugerencia de transferencia no encontrada",
+	"the suggested transactions no longer look like a transfer": "Las transacciones sugeridas ya no parecen una transferencia",
+	"transfer pairing not found":                                "Emparejamiento de transferencia no encontrado",
+	"transfer suggestion is already %s":                         "La sugerencia de transferencia ya está %s",
+	"status must be pending, accepted or rejected":              "El estado debe ser pending, accepted o rejected",
+	"end must not be before start":                              "El final no puede ser anterior al inicio",
+	"transfer match window must be at least 1 day":              "La ventana de emparejamiento de transferencias debe ser de al menos 1 día",
+	"transfer amount tolerance cannot be negative":              "La tolerancia de importe de las transferencias no puede ser negativa",
+
+	// Bulk transaction edits
+	"no transaction IDs provided":                               "No se proporcionaron IDs de transacción",
+	"at most %d transactions can be edited at once":             "Se pueden editar como máximo %d transacciones a la vez",
+	"nothing to change: set payee_id, account_id or shift_days": "Nada que cambiar: indica payee_id, account_id o shift_days",
+	"payees can't be set here":                                  "Aquí no se pueden asignar beneficiarios",
+	"only normal transactions can be moved to another account":  "Solo las transacciones normales se pueden mover a otra cuenta",
+
+	// Category suggestions
+	"only normal transactions have categories": "Solo las transacciones normales tienen categorías",
+
+	// Sign-in and accounts
+	"authentication required":                                            "Se requiere autenticación",
+	"sign in required":                                                   "Inicie sesión",
+	"invalid email or password":                                          "Correo electrónico o contraseña incorrectos",
+	"invalid password":                                                   "Contraseña incorrecta",
+	"invalid session":                                                    "Sesión no válida",
+	"session has expired":                                                "La sesión ha caducado",
+	"this account has been disabled":                                     "Esta cuenta ha sido desactivada",
+	"invalid API token":                                                  "Token de API no válido",
+	"API token has expired":                                              "El token de API ha caducado",
+	"%s access is required":                                              "Se requiere acceso %s",
+	"a valid email is required":                                          "Se requiere un correo electrónico válido",
+	"a user with this email already exists":                              "Ya existe un usuario con este correo electrónico",
+	"role must be admin or member":                                       "El rol debe ser admin o member",
+	"password must be at least %d characters":                            "La contraseña debe tener al menos %d caracteres",
+	"two-factor code required":                                           "Se requiere el código de verificación",
+	"invalid two-factor code":                                            "Código de verificación no válido",
+	"sign-in expired, please try again":                                  "El inicio de sesión ha caducado, inténtelo de nuevo",
+	"start two-factor setup first":                                       "Inicie primero la configuración en dos pasos",
+	"two-factor authentication is not enabled":                           "La verificación en dos pasos no está activada",
+	"two-factor authentication is already enabled":                       "La verificación en dos pasos ya está activada",
+	"two-factor authentication is required for this account":             "Esta cuenta requiere verificación en dos pasos",
+	"two-factor authentication must be set up before using this account": "Configure la verificación en dos pasos antes de usar esta cuenta",
+	"invalid or expired link":                                            "Enlace no válido o caducado",
+	"email is not configured":                                            "El correo electrónico no está configurado",
+	"email is already verified":                                          "El correo electrónico ya está verificado",
+	"single sign-on is not configured":                                   "El inicio de sesión único no está configurado",
+	"your account is not a member of this budget":                        "Su cuenta no pertenece a este presupuesto",
+	"no budget account exists for this user":                             "No existe una cuenta de presupuesto para este usuario",
+	"token name is required":                                             "El nombre del token es obligatorio",
+	"access must be read, write, or admin":                               "El acceso debe ser read, write o admin",
+	"expires_at must be in the future":                                   "expires_at debe estar en el futuro",
+	"locale must be a language tag such as en-US":                        "El idioma debe ser una etiqueta como es-ES",
+	"default_period must be current, previous or next":                   "default_period debe ser current, previous o next",
+	"period_type must be month, week or quarter":                         "period_type debe ser month, week o quarter",
+	"notifications.digest must be off, hourly or daily":                  "notifications.digest debe ser off, hourly o daily",
+	"notifications.urgent may only list pending_transactions and security_alerts": "notifications.urgent solo puede incluir pending_transactions y security_alerts",
+	"too many sign-in attempts, please try again later":                           "Demasiados intentos de inicio de sesión, inténtalo más tarde",
+	"too many wrong two-factor codes, please try again later":                     "Demasiados códigos de verificación incorrectos, inténtalo más tarde",
+	"the budget has already been set up":                                          "El presupuesto ya está configurado",
+	"unknown starter template %q":                                                 "Plantilla inicial %q desconocida",
+	"this setup step is already complete":                                         "Este paso de la configuración ya está completado",
+
+	// Starter template categories
+	"Housing & Bills":                      "Vivienda y facturas",
+	"Home, utilities, and recurring bills": "Hogar, suministros y facturas periódicas",
+	"Utilities":                            "Suministros",
+	"Electric, water, gas, internet":       "Luz, agua, gas, internet",
+	"Phone":                                "Teléfono",
+	"Cell phone bill":                      "Factura del móvil",
+	"Transportation":                       "Transporte",
+	"Vehicle and travel expenses":          "Gastos de vehículo y desplazamientos",
+	"Gas/Fuel":                             "Combustible",
+	"Gasoline, fuel, public transit":       "Gasolina, combustible, transporte público",
+	"Car Payment & Insurance":              "Cuota y seguro del coche",
+	"Auto loan and insurance":              "Préstamo del coche y seguro",
+	"Food & Dining":                        "Comida",
+	"Groceries and eating out":             "Supermercado y comer fuera",
+	"Groceries":                            "Supermercado",
+	"Supermarket and grocery stores":       "Supermercados y tiendas de alimentación",
+	"Restaurants":                          "Restaurantes",
+	"Dining out, coffee, etc.":             "Comer fuera, café, etc.",
+	"Personal & Lifestyle":                 "Personal y ocio",
+	"Personal care, entertainment, and subscriptions": "Cuidado personal, ocio y suscripciones",
+	"Shopping":                                "Compras",
+	"Clothing, personal items":                "Ropa, artículos personales",
+	"Entertainment":                           "Ocio",
+	"Movies, hobbies, streaming services":     "Cine, aficiones, servicios de streaming",
+	"Other":                                   "Otros",
+	"Everything else":                         "Todo lo demás",
+	"Miscellaneous":                           "Varios",
+	"Uncategorized expenses":                  "Gastos sin categoría",
+	"Rent":                                    "Alquiler",
+	"Monthly rent payment":                    "Pago mensual del alquiler",
+	"Renters Insurance":                       "Seguro de inquilino",
+	"Coverage for your belongings":            "Cobertura para tus pertenencias",
+	"Mortgage":                                "Hipoteca",
+	"Monthly mortgage payment":                "Cuota mensual de la hipoteca",
+	"Property Taxes":                          "Impuesto sobre bienes inmuebles",
+	"Property tax installments":               "Pagos del impuesto sobre bienes inmuebles",
+	"Home Insurance":                          "Seguro del hogar",
+	"Homeowners insurance":                    "Seguro de la vivienda en propiedad",
+	"Home Maintenance":                        "Mantenimiento del hogar",
+	"Keeping the house in shape":              "Mantener la casa en buen estado",
+	"Repairs & Upkeep":                        "Reparaciones y mantenimiento",
+	"Repairs, appliances and yard work":       "Reparaciones, electrodomésticos y jardín",
+	"Savings":                                 "Ahorro",
+	"Money set aside for later":               "Dinero reservado para más adelante",
+	"Emergency Fund":                          "Fondo de emergencia",
+	"Savings for unexpected expenses":         "Ahorro para gastos imprevistos",
+	"School":                                  "Estudios",
+	"Tuition and study costs":                 "Matrícula y gastos de estudio",
+	"Tuition & Fees":                          "Matrícula y tasas",
+	"Tuition, registration and lab fees":      "Matrícula, inscripción y laboratorio",
+	"Books & Supplies":                        "Libros y material",
+	"Textbooks, software and supplies":        "Libros de texto, software y material",
+	"Essentials":                              "Básicos",
+	"Bills and everyday needs":                "Facturas y necesidades diarias",
+	"Bills":                                   "Facturas",
+	"Rent, utilities and other regular bills": "Alquiler, suministros y otras facturas periódicas",
+	"Spending":                                "Gastos",
+	"Everything that isn't a bill":            "Todo lo que no es una factura",
+	"Spending Money":                          "Dinero para gastos",
+	"Eating out, shopping and fun":            "Comer fuera, compras y ocio",
+}
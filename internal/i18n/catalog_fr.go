@@ -0,0 +1,318 @@
+package i18n
+
+var french = map[string]string{
+	// Requests
+	"invalid request body":                                         "Corps de requête invalide",
+	"invalid form body":                                            "Données de formulaire invalides",
+	"failed to read request body":                                  "Impossible de lire le corps de la requête",
+	"account id is required":                                       "L'identifiant du compte est obligatoire",
+	"account_id is required":                                       "account_id est obligatoire",
+	"category id is required":                                      "L'identifiant de la catégorie est obligatoire",
+	"category group id is required":                                "L'identifiant du groupe de catégories est obligatoire",
+	"transaction id is required":                                   "L'identifiant de l'opération est obligatoire",
+	"allocation id is required":                                    "L'identifiant de l'affectation est obligatoire",
+	"transaction_ids is required":                                  "transaction_ids est obligatoire",
+	"category_id and group_id are required":                        "category_id et group_id sont obligatoires",
+	"period query parameter is required":                           "Le paramètre period est obligatoire",
+	"period is required (e.g., '2024-11')":                         "La période est obligatoire (p. ex. '2024-11')",
+	"invalid period format, expected YYYY-MM":                      "Format de période invalide, AAAA-MM attendu",
+	"invalid period format, expected YYYY-MM, YYYY-Www or YYYY-Qn": "Format de période invalide, AAAA-MM, AAAA-Www ou AAAA-Qn attendu",
+	"invalid period %q, expected YYYY-MM":                          "Période %q invalide, AAAA-MM attendu",
+	"invalid date format, expected YYYY-MM-DD":                     "Format de date invalide, AAAA-MM-JJ attendu",
+	"invalid date format, use RFC3339":                             "Format de date invalide, utilisez RFC3339",
+	"end period must not be before start period":                   "La période de fin ne peut pas précéder la période de début",
+	"view must be categories or groups":                            "view doit valoir categories ou groups",
+	"invalid cursor":                                               "curseur invalide",
+	"limit must be a positive number":                              "limit doit être un nombre positif",
+	"invalid since, expected RFC3339 time":                         "since invalide, heure RFC3339 attendue",
+	"invalid until, expected RFC3339 time":                         "until invalide, heure RFC3339 attendue",
+	"file too large (max 10MB)":                                    "Fichier trop volumineux (10 Mo max.)",
+	"invalid file type, must be .ofx, .qfx or .qif":                "Type de fichier invalide, .ofx, .qfx ou .qif attendu",
+	"unrecognized file format, expected OFX, QFX or QIF":           "Format de fichier non reconnu, OFX, QFX ou QIF attendu",
+	"invalid file type, must be .csv":                              "Type de fichier non valide, doit être .csv",
+	"invert_amounts must be true or false":                         "invert_amounts doit valoir true ou false",
+	"failed to read uploaded file":                                 "Impossible de lire le fichier envoyé",
+	"Failed to process allocation request":                         "Impossible de traiter l'affectation",
+	"Failed to load category inspector":                            "Impossible de charger l'inspecteur de catégorie",
+	"Failed to preview period close":                               "Impossible de prévisualiser la clôture de la période",
+	"Failed to reconcile card payment":                             "Impossible de rapprocher le paiement de la carte",
+	"statement_balance must be a whole number of cents":            "statement_balance doit être un nombre entier de centimes",
+	"statement_balance must not be negative":                       "statement_balance ne doit pas être négatif",
+	"account is not a credit card":                                 "le compte n'est pas une carte de crédit",
+
+	// Not found
+	"account not found":             "Compte introuvable",
+	"account not found: %s":         "Compte introuvable : %s",
+	"category not found":            "Catégorie introuvable",
+	"category not found: %s":        "Catégorie introuvable : %s",
+	"category group not found":      "Groupe de catégories introuvable",
+	"transaction not found":         "Opération introuvable",
+	"allocation not found":          "Affectation introuvable",
+	"pending transaction not found": "Opération en attente introuvable",
+	"user not found":                "Utilisateur introuvable",
+	"session not found":             "Session introuvable",
+	"API token not found":           "Jeton d'API introuvable",
+	"plugin report not found":       "Rapport du plugin introuvable",
+
+	// Validation
+	"name is required":                            "Le nom est obligatoire",
+	"account name is required":                    "Le nom du compte est obligatoire",
+	"category name is required":                   "Le nom de la catégorie est obligatoire",
+	"category group name is required":             "Le nom du groupe de catégories est obligatoire",
+	"invalid account type":                        "Type de compte invalide",
+	"amount is required":                          "Le montant est obligatoire",
+	"amount must be non-zero":                     "Le montant ne peut pas être nul",
+	"invalid amount %q":                           "Montant %q invalide",
+	"allocation amount must be non-negative":      "Le montant affecté ne peut pas être négatif",
+	"cannot transfer to the same account":         "Impossible de virer vers le même compte",
+	"transfer amount must be positive":            "Le montant du virement doit être positif",
+	"rewards are only tracked on credit accounts": "les récompenses ne sont suivies que sur les comptes de crédit",
+	"insufficient rewards balance":                "solde de récompenses insuffisant",
+	"category is not overspent":                   "la catégorie n'est pas à découvert",
+	"payment categories can't be used here; use cover-underfunded instead":                                "les catégories de paiement ne peuvent pas être utilisées ici ; utilisez plutôt cover-underfunded",
+	"insufficient funds in donor category":                                                                "fonds insuffisants dans la catégorie donatrice",
+	"a category can't cover its own overspending":                                                         "une catégorie ne peut pas couvrir son propre découvert",
+	"redemption amount must be positive":                                                                  "le montant à échanger doit être positif",
+	"category is required for outflow transactions":                                                       "Une catégorie est obligatoire pour les dépenses",
+	"a note explaining the adjustment is required":                                                        "Une note expliquant l'ajustement est requise",
+	"adjustments can't have a category":                                                                   "Les ajustements ne peuvent pas avoir de catégorie",
+	"a starting balance can't have a category":                                                            "Un solde initial ne peut pas avoir de catégorie",
+	"cannot delete the Credit Card Payments group":                                                        "Impossible de supprimer le groupe des paiements par carte de crédit",
+	"cannot delete category group: it contains %d categories. Please move or delete all categories first": "Impossible de supprimer le groupe : il contient %d catégories. Déplacez ou supprimez d'abord toutes les catégories",
+	"pending transaction is already %s":                                                                   "L'opération en attente est déjà %s",
+	"account is required to approve this transaction":                                                     "Un compte est nécessaire pour valider cette opération",
+	"no categorize script is set":                                                                         "Aucun script de catégorisation n'est défini",
+	"script must define %s(txn)":                                                                          "Le script doit définir %s(txn)",
+	"%s must return a category name, ID or None, got %v":                                                  "%s doit renvoyer un nom de catégorie, un ID ou None, reçu %v",
+
+	// Category goals
+	"goal not found":                          "Objectif introuvable",
+	"category already has a goal":             "La catégorie a déjà un objectif",
+	"payment categories can't have goals":     "Les catégories de paiement ne peuvent pas avoir d'objectif",
+	"target_amount must be positive":          "target_amount doit être positif",
+	"yearly goals need a target_date":         "Les objectifs annuels nécessitent un target_date",
+	"cadence must be once, monthly or yearly": "cadence doit être once, monthly ou yearly",
+	"target_date must be YYYY-MM":             "target_date doit être au format AAAA-MM",
+
+	// Payees
+	"payee not found":                                      "Bénéficiaire introuvable",
+	"payee rule not found":                                 "Règle de bénéficiaire introuvable",
+	"pattern is required":                                  "Le motif est obligatoire",
+	"invalid pattern: %v":                                  "Motif invalide : %v",
+	"match_type must be contains, exact or regex":          "match_type doit être contains, exact ou regex",
+	"a payee rule needs a payee_name, category_id or note": "Une règle de bénéficiaire nécessite payee_name, category_id ou note",
+	"pattern is required unless the rule has an account or amount condition": "Le motif est obligatoire sauf si la règle a une condition de compte ou de montant",
+	"min_amount and max_amount cannot be negative":                           "min_amount et max_amount ne peuvent pas être négatifs",
+	"payee rules can't be run against past transactions here":                "Les règles de bénéficiaire ne peuvent pas être appliquées ici aux transactions passées",
+	"there are no payee rules to run":                                        "Il n'y a aucune règle de bénéficiaire à appliquer",
+
+	// Funding a period
+	"basis must be budgeted_last_month, spent_last_month or average_spent": "basis doit être budgeted_last_month, spent_last_month ou average_spent",
+	"scale_percent must be between 1 and %d":                               "scale_percent doit être compris entre 1 et %d",
+
+	// Allocation templates
+	"allocation template not found":                        "modèle de répartition introuvable",
+	"an allocation template with this name already exists": "Un modèle de répartition porte déjà ce nom",
+	"a template needs at least one category":               "Un modèle doit contenir au moins une catégorie",
+	"template amounts must be positive":                    "Les montants du modèle doivent être positifs",
+	"a category can only appear once in a template":        "Une catégorie ne peut figurer qu'une fois dans un modèle",
+
+	// Account details
+	"account number may only contain digits":        "Le numéro de compte ne peut contenir que des chiffres",
+	"account number must have at least four digits": "Le numéro de compte doit comporter au moins quatre chiffres",
+	"interest rate must be between 0 and 100%":      "Le taux d'intérêt doit être compris entre 0 et 100 %",
+
+	// Transfer hints
+	"transfer hint not found": "Indice de virement introuvable",
+
+	// Overspending modes
+	"invalid overspending_mode - must be auto, cash, or credit": "overspending_mode invalide : doit être auto, cash ou credit",
+
+	// One-sided transfers
+	"transaction is not a one-sided transfer": "La transaction n'est pas un virement unilatéral",
+
+	// Transaction dates
+	"transaction date %s is more than %d years in the past":  "La date de transaction %s remonte à plus de %d ans",
+	"transaction date %s is more than %d days in the future": "La date de transaction %s est à plus de %d jours dans le futur",
+
+	// Transaction search
+	"invalid sort - must be date, amount, description, created_at, or relevance": "Tri invalide : doit être date, amount, description, created_at ou relevance",
+	"limit and offset cannot be negative":                                        "limit et offset ne peuvent pas être négatifs",
+	"min_amount cannot be more than max_amount":                                  "min_amount ne peut pas dépasser max_amount",
+	"start_date must be before end_date":                                         "start_date doit précéder end_date",
+	"order must be asc or desc":                                                  "order doit être asc ou desc",
+	"%s must be a whole number of cents":                                         "%s doit être un nombre entier de centimes",
+	"%s must be a number":                                                        "%s doit être un nombre",
+	"q is required":                                                              "q est obligatoire",
+
+	// API token guardrails
+	"API token has been disabled":                   "Le jeton d'API a été désactivé",
+	"daily write quota exceeded for this API token": "Quota quotidien d'écritures dépassé pour ce jeton d'API",
+	"daily_write_quota cannot be negative":          "daily_write_quota ne peut pas être négatif",
+
+	// Budget templates
+	"not a budget template":                                          "Ce n'est pas un modèle de budget",
+	"unsupported budget template version %d":                         "Version %d du modèle de budget non prise en charge",
+	"a budget template can't include the Credit Card Payments group": "Un modèle de budget ne peut pas inclure le groupe des paiements par carte de crédit",
+	"monthly_amount cannot be negative":                              "monthly_amount ne peut pas être négatif",
+	"months must be between 1 and %d":                                "months doit être compris entre 1 et %d",
+
+	// Import review
+	"import not found":                                "Import introuvable",
+	"import row not found":                            "Ligne d'import introuvable",
+	"import is no longer waiting for review":          "L'import n'est plus en attente de vérification",
+	"only rows waiting to be imported can be changed": "Seules les lignes en attente d'import peuvent être modifiées",
+	"only committed imports can be undone":            "Seuls les imports validés peuvent être annulés",
+	"status must be staged, committed or undone":      "status doit être staged, committed ou undone",
+
+	// Diagnostics
+	"ready to assign totals aren't kept, so there's nothing to rebuild": "Les totaux de Prêt à attribuer ne sont pas tenus à jour, il n'y a donc rien à reconstruire",
+
+	// Bulk category creation
+	"at least one category is required":            "Au moins une catégorie est requise",
+	"at most %d categories can be created at once": "Au plus %d catégories peuvent être créées à la fois",
+
+	// Loans
+	"account is not a loan":           "Le compte n'est pas un prêt",
+	"loan has no monthly payment set": "Aucune mensualité n'est définie pour le prêt",
+	"monthly payment doesn't cover the interest, so the loan would never be repaid": "La mensualité ne couvre pas les intérêts, le prêt ne serait donc jamais remboursé",
+	"monthly payment must be positive":                                              "La mensualité doit être positive",
+	"payment amount must be positive":                                               "Le montant du paiement doit être positif",
+	"interest must be between zero and the payment amount":                          "Les intérêts doivent être compris entre zéro et le montant du paiement",
+	"payment is more than the %s owed":                                              "Le paiement dépasse les %s dus",
+	"set the loan's interest category before paying interest":                       "Définissez la catégorie d'intérêts du prêt avant de payer des intérêts",
+	"interest can't be spent from a payment category":                               "Les intérêts ne peuvent pas être dépensés depuis une catégorie de paiement",
+	"from_account_id is required":                                                   "from_account_id est obligatoire",
+	"invalid start, expected YYYY-MM":                                               "start invalide, format attendu AAAA-MM",
+
+	// Tracking accounts
+	"tracking accounts are off budget; update their balance instead":                                            "Les comptes de suivi sont hors budget ; mettez plutôt à jour leur solde",
+	"account is not a tracking account":                                                                         "Le compte n'est pas un compte de suivi",
+	"money from a tracking account is income: record it as an inflow and update the tracking account's balance": "L'argent d'un compte de suivi est un revenu : enregistrez-le comme une entrée et mettez à jour le solde du compte de suivi",
+	"accounts can't be converted between budget and tracking accounts":                                          "Les comptes ne peuvent pas être convertis entre comptes budgétaires et comptes de suivi",
+
+	// Transfer suggestions
+	"transfer suggestion not found":                             "Suggestion de virement introuvable",
+	"the suggested transactions no longer look like a transfer": "Les transactions suggérées ne ressemblent plus à un virement",
+	"transfer pairing not found":                                "Appariement de virement introuvable",
+	"transfer suggestion is already %s":                         "La suggestion de virement est déjà %s",
+	"status must be pending, accepted or rejected":              "Le statut doit être pending, accepted ou rejected",
+	"end must not be before start":                              "La fin ne peut pas précéder le début",
+	"transfer match window must be at least 1 day":              "La fenêtre de rapprochement des virements doit être d'au moins 1 jour",
+	"transfer amount tolerance cannot be negative":              "La tolérance de montant des virements ne peut pas être négative",
+
+	// Bulk transaction edits
+	"no transaction IDs provided":                               "Aucun identifiant d'opération fourni",
+	"at most %d transactions can be edited at once":             "Au plus %d opérations peuvent être modifiées à la fois",
+	"nothing to change: set payee_id, account_id or shift_days": "Rien à modifier : indiquez payee_id, account_id ou shift_days",
+	"payees can't be set here":                                  "Les bénéficiaires ne peuvent pas être définis ici",
+	"only normal transactions can be moved to another account":  "Seules les opérations normales peuvent être déplacées vers un autre compte",
+
+	// Category suggestions
+	"only normal transactions have categories": "Seules les opérations normales ont des catégories",
+
+	// Sign-in and accounts
+	"authentication required":                                            "Authentification requise",
+	"sign in required":                                                   "Veuillez vous connecter",
+	"invalid email or password":                                          "E-mail ou mot de passe incorrect",
+	"invalid password":                                                   "Mot de passe incorrect",
+	"invalid session":                                                    "Session invalide",
+	"session has expired":                                                "La session a expiré",
+	"this account has been disabled":                                     "Ce compte a été désactivé",
+	"invalid API token":                                                  "Jeton d'API invalide",
+	"API token has expired":                                              "Le jeton d'API a expiré",
+	"%s access is required":                                              "Un accès %s est requis",
+	"a valid email is required":                                          "Une adresse e-mail valide est obligatoire",
+	"a user with this email already exists":                              "Un utilisateur avec cet e-mail existe déjà",
+	"role must be admin or member":                                       "Le rôle doit être admin ou member",
+	"password must be at least %d characters":                            "Le mot de passe doit contenir au moins %d caractères",
+	"two-factor code required":                                           "Code de vérification requis",
+	"invalid two-factor code":                                            "Code de vérification invalide",
+	"sign-in expired, please try again":                                  "La connexion a expiré, veuillez réessayer",
+	"start two-factor setup first":                                       "Commencez d'abord la configuration de la double authentification",
+	"two-factor authentication is not enabled":                           "La double authentification n'est pas activée",
+	"two-factor authentication is already enabled":                       "La double authentification est déjà activée",
+	"two-factor authentication is required for this account":             "La double authentification est obligatoire pour ce compte",
+	"two-factor authentication must be set up before using this account": "Configurez la double authentification avant d'utiliser ce compte",
+	"invalid or expired link":                                            "Lien invalide ou expiré",
+	"email is not configured":                                            "L'envoi d'e-mails n'est pas configuré",
+	"email is already verified":                                          "L'adresse e-mail est déjà vérifiée",
+	"single sign-on is not configured":                                   "L'authentification unique n'est pas configurée",
+	"your account is not a member of this budget":                        "Votre compte ne fait pas partie de ce budget",
+	"no budget account exists for this user":                             "Aucun compte budget n'existe pour cet utilisateur",
+	"token name is required":                                             "Le nom du jeton est obligatoire",
+	"access must be read, write, or admin":                               "L'accès doit être read, write ou admin",
+	"expires_at must be in the future":                                   "expires_at doit être dans le futur",
+	"locale must be a language tag such as en-US":                        "La langue doit être un code tel que fr-FR",
+	"default_period must be current, previous or next":                   "default_period doit être current, previous ou next",
+	"period_type must be month, week or quarter":                         "period_type doit être month, week ou quarter",
+	"notifications.digest must be off, hourly or daily":                  "notifications.digest doit valoir off, hourly ou daily",
+	"notifications.urgent may only list pending_transactions and security_alerts": "notifications.urgent ne peut contenir que pending_transactions et security_alerts",
+	"too many sign-in attempts, please try again later":                           "Trop de tentatives de connexion, veuillez réessayer plus tard",
+	"too many wrong two-factor codes, please try again later":                     "Trop de codes de vérification incorrects, veuillez réessayer plus tard",
+	"the budget has already been set up":                                          "Le budget est déjà configuré",
+	"unknown starter template %q":                                                 "Modèle de départ %q inconnu",
+	"this setup step is already complete":                                         "Cette étape de configuration est déjà terminée",
+
+	// Starter template categories
+	"Housing & Bills":                      "Logement et factures",
+	"Home, utilities, and recurring bills": "Logement, énergie et factures récurrentes",
+	"Utilities":                            "Énergie et eau",
+	"Electric, water, gas, internet":       "Électricité, eau, gaz, internet",
+	"Phone":                                "Téléphone",
+	"Cell phone bill":                      "Forfait mobile",
+	"Transportation":                       "Transport",
+	"Vehicle and travel expenses":          "Véhicule et déplacements",
+	"Gas/Fuel":                             "Carburant",
+	"Gasoline, fuel, public transit":       "Essence, carburant, transports en commun",
+	"Car Payment & Insurance":              "Crédit auto et assurance",
+	"Auto loan and insurance":              "Prêt automobile et assurance",
+	"Food & Dining":                        "Alimentation",
+	"Groceries and eating out":             "Courses et repas à l'extérieur",
+	"Groceries":                            "Courses",
+	"Supermarket and grocery stores":       "Supermarchés et épiceries",
+	"Restaurants":                          "Restaurants",
+	"Dining out, coffee, etc.":             "Restaurants, cafés, etc.",
+	"Personal & Lifestyle":                 "Vie personnelle et loisirs",
+	"Personal care, entertainment, and subscriptions": "Soins, loisirs et abonnements",
+	"Shopping":                                "Achats",
+	"Clothing, personal items":                "Vêtements, effets personnels",
+	"Entertainment":                           "Loisirs",
+	"Movies, hobbies, streaming services":     "Cinéma, hobbies, streaming",
+	"Other":                                   "Autres",
+	"Everything else":                         "Tout le reste",
+	"Miscellaneous":                           "Divers",
+	"Uncategorized expenses":                  "Dépenses non classées",
+	"Rent":                                    "Loyer",
+	"Monthly rent payment":                    "Loyer mensuel",
+	"Renters Insurance":                       "Assurance habitation",
+	"Coverage for your belongings":            "Couverture de vos biens",
+	"Mortgage":                                "Prêt immobilier",
+	"Monthly mortgage payment":                "Mensualité du prêt immobilier",
+	"Property Taxes":                          "Taxe foncière",
+	"Property tax installments":               "Échéances de la taxe foncière",
+	"Home Insurance":                          "Assurance propriétaire",
+	"Homeowners insurance":                    "Assurance du logement",
+	"Home Maintenance":                        "Entretien de la maison",
+	"Keeping the house in shape":              "Garder la maison en bon état",
+	"Repairs & Upkeep":                        "Réparations et entretien",
+	"Repairs, appliances and yard work":       "Réparations, électroménager et jardin",
+	"Savings":                                 "Épargne",
+	"Money set aside for later":               "Argent mis de côté",
+	"Emergency Fund":                          "Épargne de précaution",
+	"Savings for unexpected expenses":         "Épargne pour les imprévus",
+	"School":                                  "Études",
+	"Tuition and study costs":                 "Frais de scolarité et d'études",
+	"Tuition & Fees":                          "Frais de scolarité",
+	"Tuition, registration and lab fees":      "Scolarité, inscription et travaux pratiques",
+	"Books & Supplies":                        "Livres et fournitures",
+	"Textbooks, software and supplies":        "Manuels, logiciels et fournitures",
+	"Essentials":                              "Essentiel",
+	"Bills and everyday needs":                "Factures et besoins du quotidien",
+	"Bills":                                   "Factures",
+	"Rent, utilities and other regular bills": "Loyer, énergie et autres factures régulières",
+	"Spending":                                "Dépenses",
+	"Everything that isn't a bill":            "Tout ce qui n'est pas une facture",
+	"Spending Money":                          "Argent de poche",
+	"Eating out, shopping and fun":            "Restaurants, achats et loisirs",
+}
@@ -0,0 +1,121 @@
+// Package i18n translates user-facing text into the languages the budget supports.
+//
+// Catalogs are keyed by the English text itself, so code keeps writing plain English
+// messages and anything missing from a catalog is shown untranslated. Keys may contain
+// fmt verbs (%s, %d, %q, %v); the values filled in are carried over to the translation
+// in the same order.
+package i18n
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Default is the language messages are written in
+const Default = "en"
+
+// Supported lists the languages with a catalog, Default first
+var Supported = []string{Default, "de", "es", "fr"}
+
+var catalogs = map[string]map[string]string{
+	"de": german,
+	"es": spanish,
+	"fr": french,
+}
+
+var matcher = language.NewMatcher([]language.Tag{
+	language.English,
+	language.German,
+	language.Spanish,
+	language.French,
+})
+
+// Negotiate returns the supported language that best matches an Accept-Language
+// header value or a single language tag such as "de-AT"
+// Falls back to Default when nothing matches.
+func Negotiate(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+	return Supported[index]
+}
+
+// Translate returns message in the given language, or message unchanged when the
+// language or message has no translation
+func Translate(lang, message string) string {
+	catalog, ok := catalogs[lang]
+	if !ok {
+		return message
+	}
+	if translated, ok := catalog[message]; ok {
+		return translated
+	}
+	for _, p := range patterns[lang] {
+		if args := p.match.FindStringSubmatch(message); args != nil {
+			return fill(p.translation, args[1:])
+		}
+	}
+	return message
+}
+
+// verb matches the fmt verbs allowed in catalog keys
+var verb = regexp.MustCompile(`%[sdqv]`)
+
+// pattern is a catalog entry whose key has fmt verbs
+type pattern struct {
+	key         string
+	match       *regexp.Regexp
+	translation string
+}
+
+var patterns = compilePatterns()
+
+func compilePatterns() map[string][]pattern {
+	compiled := make(map[string][]pattern, len(catalogs))
+	for lang, catalog := range catalogs {
+		for key, translation := range catalog {
+			if !verb.MatchString(key) {
+				continue
+			}
+			literals := verb.Split(key, -1)
+			for i := range literals {
+				literals[i] = regexp.QuoteMeta(literals[i])
+			}
+			expr := "^" + strings.Join(literals, "(.+?)") + "$"
+			compiled[lang] = append(compiled[lang], pattern{
+				key:         key,
+				match:       regexp.MustCompile(expr),
+				translation: translation,
+			})
+		}
+		// Try the most specific keys first so overlapping patterns resolve the same way every time
+		sort.Slice(compiled[lang], func(i, j int) bool {
+			a, b := compiled[lang][i].key, compiled[lang][j].key
+			if len(a) != len(b) {
+				return len(a) > len(b)
+			}
+			return a < b
+		})
+	}
+	return compiled
+}
+
+// fill replaces the verbs in translation with the values captured from the message
+func fill(translation string, args []string) string {
+	next := 0
+	return verb.ReplaceAllStringFunc(translation, func(v string) string {
+		if next >= len(args) {
+			return v
+		}
+		next++
+		return args[next-1]
+	})
+}
@@ -0,0 +1,61 @@
+package i18n
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "en"},
+		{"de", "de"},
+		{"de-AT", "de"},
+		{"fr-CA,fr;q=0.9,en;q=0.8", "fr"},
+		{"ja, es;q=0.5", "es"},
+		{"en-GB,de;q=0.9", "en"},
+		{"ja", "en"},
+		{"not a tag;;", "en"},
+	}
+	for _, tt := range tests {
+		if got := Negotiate(tt.header); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		lang    string
+		message string
+		want    string
+	}{
+		{"de", "account not found", "Konto nicht gefunden"},
+		{"es", "password must be at least 8 characters", "La contraseña debe tener al menos 8 caracteres"},
+		{"fr", `invalid amount "12,x"`, `Montant "12,x" invalide`},
+		{"de", "category not found: abc-123", "Kategorie nicht gefunden: abc-123"},
+		{"en", "account not found", "account not found"},
+		{"de", "some message nobody translated", "some message nobody translated"},
+		{"xx", "account not found", "account not found"},
+	}
+	for _, tt := range tests {
+		if got := Translate(tt.lang, tt.message); got != tt.want {
+			t.Errorf("Translate(%q, %q) = %q, want %q", tt.lang, tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestCatalogsAreComplete(t *testing.T) {
+	// Every catalog translates the same messages, keeping the values they carry
+	for lang, catalog := range catalogs {
+		for key, translation := range catalog {
+			if got, want := len(verb.FindAllString(translation, -1)), len(verb.FindAllString(key, -1)); got != want {
+				t.Errorf("%s: %q has %d values, translation has %d", lang, key, want, got)
+			}
+			for other, otherCatalog := range catalogs {
+				if _, ok := otherCatalog[key]; !ok {
+					t.Errorf("%s translates %q but %s does not", lang, key, other)
+				}
+			}
+		}
+	}
+}
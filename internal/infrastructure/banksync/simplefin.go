@@ -0,0 +1,177 @@
+package banksync
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// maxSimpleFINResponse caps how much of a SimpleFIN Bridge answer is read
+const maxSimpleFINResponse = 32 << 20
+
+// SimpleFINProvider syncs accounts through SimpleFIN Bridge, https://www.simplefin.org/protocol.html
+// The user gets a setup token from the bridge, which is claimed once for an access URL;
+// the access URL, with its credentials, is what's kept to fetch with.
+type SimpleFINProvider struct {
+	client *http.Client
+}
+
+// NewSimpleFINProvider creates a SimpleFIN Bridge provider that waits up to timeout for an answer
+func NewSimpleFINProvider(timeout time.Duration) *SimpleFINProvider {
+	return &SimpleFINProvider{client: &http.Client{Timeout: timeout}}
+}
+
+// Name returns the provider name aggregations are recorded with
+func (p *SimpleFINProvider) Name() string {
+	return domain.BankAggregatorSimpleFIN
+}
+
+// Claim exchanges a setup token for the access URL to fetch with
+// A setup token can only be claimed once.
+func (p *SimpleFINProvider) Claim(ctx context.Context, setupToken string) (string, error) {
+	claimURL, err := base64.StdEncoding.DecodeString(strings.TrimSpace(setupToken))
+	if err != nil {
+		return "", fmt.Errorf("setup token is not valid")
+	}
+	if !strings.HasPrefix(string(claimURL), "https://") {
+		return "", fmt.Errorf("setup token is not valid")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, string(claimURL), nil)
+	if err != nil {
+		return "", fmt.Errorf("setup token is not valid")
+	}
+	body, err := p.do(req)
+	if err != nil {
+		return "", err
+	}
+
+	accessURL := strings.TrimSpace(string(body))
+	if parsed, err := url.Parse(accessURL); err != nil || parsed.Scheme != "https" || parsed.User == nil {
+		return "", fmt.Errorf("SimpleFIN Bridge answered with an invalid access URL")
+	}
+	return accessURL, nil
+}
+
+type simpleFINAccountSet struct {
+	Errors   []string `json:"errors"`
+	Accounts []struct {
+		Org struct {
+			Name   string `json:"name"`
+			Domain string `json:"domain"`
+		} `json:"org"`
+		ID           string `json:"id"`
+		Name         string `json:"name"`
+		Currency     string `json:"currency"` // ISO 4217 code, or a URL for a custom currency
+		Balance      string `json:"balance"`
+		Transactions []struct {
+			ID          string `json:"id"`
+			Posted      int64  `json:"posted"` // Unix time; 0 while pending
+			Amount      string `json:"amount"`
+			Description string `json:"description"`
+			Pending     bool   `json:"pending"`
+		} `json:"transactions"`
+	} `json:"accounts"`
+}
+
+// Accounts fetches every account the access URL can see, with its transactions posted since then
+// Pending transactions are left out until they post. Messages are what the bridge asks to
+// be shown to the user, such as a bank needing to be reconnected there.
+func (p *SimpleFINProvider) Accounts(ctx context.Context, accessURL string, since time.Time) ([]domain.AggregatedAccount, []string, error) {
+	parsed, err := url.Parse(accessURL)
+	if err != nil || parsed.Scheme != "https" || parsed.User == nil {
+		return nil, nil, fmt.Errorf("access URL is not valid")
+	}
+	username := parsed.User.Username()
+	password, _ := parsed.User.Password()
+	parsed.User = nil // Sent as a header, so it stays out of errors
+	parsed = parsed.JoinPath("accounts")
+	parsed.RawQuery = url.Values{"start-date": {strconv.FormatInt(since.Unix(), 10)}}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.SetBasicAuth(username, password)
+	body, err := p.do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var set simpleFINAccountSet
+	if err := json.Unmarshal(body, &set); err != nil {
+		return nil, nil, fmt.Errorf("SimpleFIN Bridge answered with invalid JSON: %w", err)
+	}
+
+	accounts := make([]domain.AggregatedAccount, 0, len(set.Accounts))
+	for _, remote := range set.Accounts {
+		currency := remote.Currency
+		if len(currency) != 3 {
+			currency = domain.BudgetCurrency().Code // Custom currencies are taken as the budget's
+		}
+		balance, err := domain.ParseMoney(remote.Balance, currency)
+		if err != nil {
+			return nil, nil, fmt.Errorf("account %s: balance: %w", remote.Name, err)
+		}
+		account := domain.AggregatedAccount{
+			ID:          remote.ID,
+			Name:        remote.Name,
+			Institution: remote.Org.Name,
+			Currency:    strings.ToUpper(currency),
+			Balance:     balance.Amount,
+		}
+		if account.Institution == "" {
+			account.Institution = remote.Org.Domain
+		}
+		for _, txn := range remote.Transactions {
+			if txn.Pending || txn.Posted == 0 {
+				continue
+			}
+			amount, err := domain.ParseMoney(txn.Amount, currency)
+			if err != nil {
+				return nil, nil, fmt.Errorf("account %s: transaction %s: %w", remote.Name, txn.ID, err)
+			}
+			account.Transactions = append(account.Transactions, domain.AggregatedTransaction{
+				ID:          txn.ID,
+				Date:        time.Unix(txn.Posted, 0),
+				Amount:      amount.Amount,
+				Description: txn.Description,
+			})
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, set.Errors, nil
+}
+
+// do sends the request, returning the body of a successful answer
+func (p *SimpleFINProvider) do(req *http.Request) ([]byte, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("SimpleFIN Bridge can't be reached: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSimpleFINResponse))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SimpleFIN Bridge's answer: %w", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return body, nil
+	case http.StatusForbidden:
+		return nil, fmt.Errorf("SimpleFIN Bridge refused access; the setup token was already claimed or access was revoked")
+	case http.StatusPaymentRequired:
+		return nil, fmt.Errorf("SimpleFIN Bridge refused access; the subscription needs paying for")
+	default:
+		return nil, fmt.Errorf("SimpleFIN Bridge answered %s", resp.Status)
+	}
+}
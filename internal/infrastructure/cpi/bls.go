@@ -0,0 +1,152 @@
+package cpi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+const (
+	blsEndpoint = "https://api.bls.gov/publicAPI/v2/timeseries/data/"
+
+	// blsSeriesCPIU is CPI for All Urban Consumers, U.S. city average, all items, not seasonally adjusted
+	blsSeriesCPIU = "CUUR0000SA0"
+
+	// blsMaxYears is the maximum span the BLS API accepts in a single request
+	blsMaxYears = 10
+)
+
+// BLSProvider fetches CPI-U values from the U.S. Bureau of Labor Statistics public API
+type BLSProvider struct {
+	apiKey string // Optional registration key (raises rate limits)
+	client *http.Client
+}
+
+// NewBLSProvider creates a new BLS CPI provider
+func NewBLSProvider(apiKey string) *BLSProvider {
+	return &BLSProvider{
+		apiKey: apiKey,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name returns the provider name recorded as the source of fetched entries
+func (p *BLSProvider) Name() string {
+	return "bls"
+}
+
+type blsRequest struct {
+	SeriesID        []string `json:"seriesid"`
+	StartYear       string   `json:"startyear"`
+	EndYear         string   `json:"endyear"`
+	RegistrationKey string   `json:"registrationkey,omitempty"`
+}
+
+type blsResponse struct {
+	Status  string   `json:"status"`
+	Message []string `json:"message"`
+	Results struct {
+		Series []struct {
+			Data []struct {
+				Year   string `json:"year"`
+				Period string `json:"period"` // M01-M12, M13 is the annual average
+				Value  string `json:"value"`
+			} `json:"data"`
+		} `json:"series"`
+	} `json:"Results"`
+}
+
+// Fetch returns monthly CPI-U values between startPeriod and endPeriod (inclusive, YYYY-MM)
+func (p *BLSProvider) Fetch(ctx context.Context, startPeriod, endPeriod string) ([]*domain.CPIEntry, error) {
+	startYear, err := strconv.Atoi(startPeriod[:4])
+	if err != nil {
+		return nil, fmt.Errorf("invalid start period")
+	}
+	endYear, err := strconv.Atoi(endPeriod[:4])
+	if err != nil {
+		return nil, fmt.Errorf("invalid end period")
+	}
+
+	var entries []*domain.CPIEntry
+	for year := startYear; year <= endYear; year += blsMaxYears {
+		chunkEnd := year + blsMaxYears - 1
+		if chunkEnd > endYear {
+			chunkEnd = endYear
+		}
+
+		chunk, err := p.fetchYears(ctx, year, chunkEnd)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range chunk {
+			if entry.Period >= startPeriod && entry.Period <= endPeriod {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+func (p *BLSProvider) fetchYears(ctx context.Context, startYear, endYear int) ([]*domain.CPIEntry, error) {
+	body, err := json.Marshal(blsRequest{
+		SeriesID:        []string{blsSeriesCPIU},
+		StartYear:       strconv.Itoa(startYear),
+		EndYear:         strconv.Itoa(endYear),
+		RegistrationKey: p.apiKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, blsEndpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("BLS request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("BLS returned status %d", resp.StatusCode)
+	}
+
+	var parsed blsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode BLS response: %w", err)
+	}
+	if parsed.Status != "REQUEST_SUCCEEDED" {
+		return nil, fmt.Errorf("BLS request failed: %s", strings.Join(parsed.Message, "; "))
+	}
+
+	var entries []*domain.CPIEntry
+	for _, series := range parsed.Results.Series {
+		for _, point := range series.Data {
+			// Skip annual averages (M13) and anything that isn't a month
+			if !strings.HasPrefix(point.Period, "M") || point.Period == "M13" {
+				continue
+			}
+			value, err := strconv.ParseFloat(point.Value, 64)
+			if err != nil {
+				continue
+			}
+			entries = append(entries, &domain.CPIEntry{
+				Period: fmt.Sprintf("%s-%s", point.Year, strings.TrimPrefix(point.Period, "M")),
+				Value:  value,
+			})
+		}
+	}
+
+	return entries, nil
+}
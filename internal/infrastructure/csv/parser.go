@@ -0,0 +1,286 @@
+package csv
+
+import (
+	"crypto/sha256"
+	stdcsv "encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// ParsedTransaction represents a transaction parsed from a CSV file
+type ParsedTransaction struct {
+	Date        time.Time
+	Amount      int64 // In cents (positive=inflow, negative=outflow)
+	Description string
+	FitID       string // From the FITID column, or derived from the row when there isn't one
+}
+
+// ImportResult contains the result of parsing a CSV file
+type ImportResult struct {
+	Transactions  []ParsedTransaction
+	DateFormat    string // Format the dates were read with, e.g. "MM/DD/YYYY"
+	LedgerBalance *int64 // Balance on the latest row, if a balance column was mapped
+}
+
+// Mapping says which columns hold which fields
+// Columns are header names (matched case-insensitively) or 1-based column numbers.
+type Mapping struct {
+	Date        string
+	Amount      string
+	Description string
+	FitID       string // Optional
+	Balance     string // Optional; running balance, used as the account's new balance
+
+	// DateFormat is one of DateFormats; empty detects it from the file
+	DateFormat string
+
+	// InvertAmounts flips signs, for exports that list charges as positive numbers
+	InvertAmounts bool
+}
+
+// DefaultMapping is used for columns the caller doesn't map
+var DefaultMapping = Mapping{
+	Date:        "Date",
+	Amount:      "Amount",
+	Description: "Description",
+}
+
+// DateFormats maps the date formats users can pick to Go layouts
+// Detection tries them in this order, so month-first wins when a file could be either.
+var DateFormats = []struct {
+	Name   string
+	Layout string
+}{
+	{"YYYY-MM-DD", "2006-01-02"},
+	{"MM/DD/YYYY", "1/2/2006"},
+	{"DD/MM/YYYY", "2/1/2006"},
+	{"MM/DD/YY", "1/2/06"},
+	{"DD/MM/YY", "2/1/06"},
+	{"YYYY/MM/DD", "2006/1/2"},
+	{"DD.MM.YYYY", "2.1.2006"},
+	{"MM-DD-YYYY", "1-2-2006"},
+	{"DD-MM-YYYY", "2-1-2006"},
+	{"DD MMM YYYY", "2 Jan 2006"},
+	{"MMM D, YYYY", "Jan 2, 2006"},
+}
+
+// Parser handles CSV file parsing
+type Parser struct{}
+
+// NewParser creates a new CSV parser
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// Parse reads transactions from a CSV file with a header row
+func (p *Parser) Parse(reader io.Reader, mapping Mapping) (*ImportResult, error) {
+	r := stdcsv.NewReader(reader)
+	r.FieldsPerRecord = -1 // Some banks pad rows or add trailing commas
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV file: %w", err)
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("CSV file has no transactions")
+	}
+
+	header := records[0]
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff") // Excel's byte order mark
+	}
+	columns := struct{ date, amount, description, fitID, balance int }{}
+	for _, c := range []struct {
+		name     string
+		value    string
+		fallback string
+		index    *int
+	}{
+		{"date", mapping.Date, DefaultMapping.Date, &columns.date},
+		{"amount", mapping.Amount, DefaultMapping.Amount, &columns.amount},
+		{"description", mapping.Description, DefaultMapping.Description, &columns.description},
+		{"fitid", mapping.FitID, "", &columns.fitID},
+		{"balance", mapping.Balance, "", &columns.balance},
+	} {
+		value := c.value
+		if value == "" {
+			value = c.fallback
+		}
+		if value == "" {
+			*c.index = -1
+			continue
+		}
+		if *c.index, err = findColumn(header, value); err != nil {
+			return nil, fmt.Errorf("%s column: %w", c.name, err)
+		}
+	}
+
+	rows := records[1:]
+	layout, formatName, err := p.dateLayout(rows, columns.date, mapping.DateFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{
+		Transactions: make([]ParsedTransaction, 0, len(rows)),
+		DateFormat:   formatName,
+	}
+	var latest time.Time
+	seen := make(map[string]int)
+	for i, row := range rows {
+		line := i + 2
+		if isBlank(row) {
+			continue
+		}
+
+		date, err := time.Parse(layout, field(row, columns.date))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q", line, field(row, columns.date))
+		}
+		date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+		amount, err := ParseAmount(field(row, columns.amount))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if mapping.InvertAmounts {
+			amount = -amount
+		}
+
+		txn := ParsedTransaction{
+			Date:        date,
+			Amount:      amount,
+			Description: strings.Join(strings.Fields(field(row, columns.description)), " "),
+		}
+		if columns.fitID >= 0 {
+			txn.FitID = field(row, columns.fitID)
+		}
+		if txn.FitID == "" {
+			// Same row contents give the same ID on every import; repeats within the file
+			// (two identical coffees on one day) are told apart by their occurrence
+			key := fmt.Sprintf("%s|%d|%s", date.Format("2006-01-02"), amount, txn.Description)
+			seen[key]++
+			sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d", key, seen[key])))
+			txn.FitID = "csv-" + hex.EncodeToString(sum[:10])
+		}
+
+		if columns.balance >= 0 && !date.Before(latest) {
+			if balance, err := ParseAmount(field(row, columns.balance)); err == nil {
+				latest = date
+				result.LedgerBalance = &balance
+			}
+		}
+
+		result.Transactions = append(result.Transactions, txn)
+	}
+	if len(result.Transactions) == 0 {
+		return nil, fmt.Errorf("CSV file has no transactions")
+	}
+
+	return result, nil
+}
+
+// dateLayout returns the layout for the date column, detecting it when no format was given
+// A format is only picked if it reads every date in the file.
+func (p *Parser) dateLayout(rows [][]string, column int, format string) (string, string, error) {
+	if format != "" {
+		for _, f := range DateFormats {
+			if strings.EqualFold(f.Name, format) {
+				return f.Layout, f.Name, nil
+			}
+		}
+		return "", "", fmt.Errorf("unsupported date format %q", format)
+	}
+
+	for _, f := range DateFormats {
+		matched := false
+		for _, row := range rows {
+			if isBlank(row) {
+				continue
+			}
+			if _, err := time.Parse(f.Layout, field(row, column)); err != nil {
+				matched = false
+				break
+			}
+			matched = true
+		}
+		if matched {
+			return f.Layout, f.Name, nil
+		}
+	}
+	return "", "", fmt.Errorf("could not detect the date format; pass date_format")
+}
+
+// ParseAmount converts an amount such as "-1,234.56", "$12.00" or "(12.00)" to minor units
+// of the budget's currency, e.g. cents, or whole yen
+func ParseAmount(value string) (int64, error) {
+	currency := domain.BudgetCurrency()
+	s := strings.ReplaceAll(value, "$", "")
+	if currency.Symbol != "" {
+		s = strings.ReplaceAll(s, currency.Symbol, "")
+	}
+	s = strings.TrimSpace(s)
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	if strings.HasPrefix(s, "-") {
+		negative = !negative
+		s = s[1:]
+	} else if strings.HasSuffix(s, "-") { // Trailing minus, as some exports write debits
+		negative = !negative
+		s = s[:len(s)-1]
+	}
+	s = strings.ReplaceAll(strings.TrimPrefix(s, "+"), ",", "")
+	if s == "" || strings.ContainsAny(s, "+-") {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+
+	money, err := domain.ParseMoney(s, currency.Code)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	if negative {
+		return -money.Amount, nil
+	}
+	return money.Amount, nil
+}
+
+// findColumn finds a column by header name or 1-based number
+func findColumn(header []string, name string) (int, error) {
+	for i, h := range header {
+		if strings.EqualFold(strings.TrimSpace(h), strings.TrimSpace(name)) {
+			return i, nil
+		}
+	}
+	if n, err := strconv.Atoi(name); err == nil {
+		if n < 1 || n > len(header) {
+			return 0, fmt.Errorf("column %d is out of range (file has %d columns)", n, len(header))
+		}
+		return n - 1, nil
+	}
+	return 0, fmt.Errorf("no column named %q", name)
+}
+
+func field(row []string, index int) string {
+	if index < 0 || index >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[index])
+}
+
+func isBlank(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,178 @@
+package csv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{"12.34", 1234, false},
+		{"-1,234.56", -123456, false},
+		{"$5", 500, false},
+		{"-$5.5", -550, false},
+		{"$-5.50", -550, false},
+		{"(42.00)", -4200, false},
+		{"42.00-", -4200, false},
+		{"+.99", 99, false},
+		{"", 0, true},
+		{"1.234", 0, true},
+		{"12-34", 0, true},
+		{"abc", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseAmount(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseAmount(%q) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseAmount_BudgetCurrency(t *testing.T) {
+	defer domain.SetBudgetCurrency(domain.DefaultCurrency, -1)
+
+	if err := domain.SetBudgetCurrency("JPY", -1); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ParseAmount("-¥1,500"); err != nil || got != -1500 {
+		t.Errorf("ParseAmount(-¥1,500) = %d, %v, want -1500 yen", got, err)
+	}
+	if _, err := ParseAmount("1500.50"); err == nil {
+		t.Error("expected sen to be rejected in a yen budget")
+	}
+
+	if err := domain.SetBudgetCurrency("KWD", -1); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ParseAmount("(12.345)"); err != nil || got != -12345 {
+		t.Errorf("ParseAmount((12.345)) = %d, %v, want -12345 fils", got, err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		mapping     Mapping
+		wantFormat  string
+		wantDates   []string
+		wantAmounts []int64
+		wantBalance *int64
+		wantErr     string
+	}{
+		{
+			name:        "default columns with ISO dates",
+			file:        "Date,Description,Amount\n2025-03-01,Coffee Shop,-4.50\n2025-03-02,Paycheck,\"2,000.00\"\n",
+			wantFormat:  "YYYY-MM-DD",
+			wantDates:   []string{"2025-03-01", "2025-03-02"},
+			wantAmounts: []int64{-450, 200000},
+		},
+		{
+			name:        "day-first dates are detected when a day is over 12",
+			file:        "Posted,Payee,Value\n03/04/2025,A,-1\n25/04/2025,B,-2\n",
+			mapping:     Mapping{Date: "posted", Description: "Payee", Amount: "Value"},
+			wantFormat:  "DD/MM/YYYY",
+			wantDates:   []string{"2025-04-03", "2025-04-25"},
+			wantAmounts: []int64{-100, -200},
+		},
+		{
+			name:        "ambiguous dates read month first",
+			file:        "Date,Description,Amount\n03/04/2025,A,-1\n",
+			wantFormat:  "MM/DD/YYYY",
+			wantDates:   []string{"2025-03-04"},
+			wantAmounts: []int64{-100},
+		},
+		{
+			name:        "column numbers, inverted amounts and a balance column",
+			file:        "\ufeffwhen,what,charge,balance\n1/5/2025,Groceries,25.00,-125.00\n1/3/2025,Gas,100.00,-100.00\n\n",
+			mapping:     Mapping{Date: "1", Description: "2", Amount: "3", Balance: "balance", InvertAmounts: true},
+			wantFormat:  "MM/DD/YYYY",
+			wantDates:   []string{"2025-01-05", "2025-01-03"},
+			wantAmounts: []int64{-2500, -10000},
+			wantBalance: func() *int64 { b := int64(-12500); return &b }(),
+		},
+		{
+			name:    "unknown column",
+			file:    "Date,Amount\n2025-03-01,1\n",
+			wantErr: `description column: no column named "Description"`,
+		},
+		{
+			name:    "explicit format that doesn't match",
+			file:    "Date,Description,Amount\n2025-03-01,A,1\n",
+			mapping: Mapping{DateFormat: "DD.MM.YYYY"},
+			wantErr: `line 2: invalid date "2025-03-01"`,
+		},
+		{
+			name:    "header only",
+			file:    "Date,Description,Amount\n",
+			wantErr: "CSV file has no transactions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewParser().Parse(strings.NewReader(tt.file), tt.mapping)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() unexpected error = %v", err)
+			}
+			if result.DateFormat != tt.wantFormat {
+				t.Errorf("DateFormat = %s, want %s", result.DateFormat, tt.wantFormat)
+			}
+			if len(result.Transactions) != len(tt.wantDates) {
+				t.Fatalf("got %d transactions, want %d", len(result.Transactions), len(tt.wantDates))
+			}
+			for i, txn := range result.Transactions {
+				if got := txn.Date.Format("2006-01-02"); got != tt.wantDates[i] {
+					t.Errorf("transaction %d date = %s, want %s", i, got, tt.wantDates[i])
+				}
+				if txn.Amount != tt.wantAmounts[i] {
+					t.Errorf("transaction %d amount = %d, want %d", i, txn.Amount, tt.wantAmounts[i])
+				}
+			}
+			if (result.LedgerBalance == nil) != (tt.wantBalance == nil) ||
+				(result.LedgerBalance != nil && *result.LedgerBalance != *tt.wantBalance) {
+				t.Errorf("LedgerBalance = %v, want %v", result.LedgerBalance, tt.wantBalance)
+			}
+		})
+	}
+}
+
+func TestParse_DerivedFitIDs(t *testing.T) {
+	file := "Date,Description,Amount\n2025-03-01,Coffee,-4.50\n2025-03-01,Coffee,-4.50\n2025-03-02,Coffee,-4.50\n"
+
+	first, err := NewParser().Parse(strings.NewReader(file), Mapping{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := NewParser().Parse(strings.NewReader(file), Mapping{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := make(map[string]bool)
+	for i, txn := range first.Transactions {
+		if seen[txn.FitID] {
+			t.Errorf("transaction %d reuses FitID %s", i, txn.FitID)
+		}
+		seen[txn.FitID] = true
+		if again.Transactions[i].FitID != txn.FitID {
+			t.Errorf("transaction %d FitID changed between imports: %s then %s", i, txn.FitID, again.Transactions[i].FitID)
+		}
+	}
+}
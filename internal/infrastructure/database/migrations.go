@@ -1,9 +1,11 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 )
 
@@ -59,6 +61,240 @@ var migrations = []Migration{
 		Up:          migrateRequireGroupID,
 		Down:        rollbackRequireGroupID,
 	},
+	{
+		Version:     "008_add_category_classification",
+		Description: "Add classification column to categories (essential, discretionary, savings, debt)",
+		Up:          migrateAddCategoryClassification,
+		Down:        rollbackAddCategoryClassification,
+	},
+	{
+		Version:     "009_add_settings",
+		Description: "Add settings table for budget-wide key/value settings",
+		Up:          migrateAddSettings,
+		Down:        rollbackAddSettings,
+	},
+	{
+		Version:     "010_add_cpi_index",
+		Description: "Add cpi_index table for inflation-adjusted reporting",
+		Up:          migrateAddCPIIndex,
+		Down:        rollbackAddCPIIndex,
+	},
+	{
+		Version:     "011_add_pending_transactions",
+		Description: "Add pending_transactions table for imports awaiting approval",
+		Up:          migrateAddPendingTransactions,
+		Down:        rollbackAddPendingTransactions,
+	},
+	{
+		Version:     "012_add_bot_chats",
+		Description: "Add bot_chats table for per-chat bot tokens",
+		Up:          migrateAddBotChats,
+		Down:        rollbackAddBotChats,
+	},
+	{
+		Version:     "013_add_api_tokens",
+		Description: "Add api_tokens table for scoped API access",
+		Up:          migrateAddAPITokens,
+		Down:        rollbackAddAPITokens,
+	},
+	{
+		Version:     "014_add_users_and_sessions",
+		Description: "Add users and sessions tables and link API tokens to users",
+		Up:          migrateAddUsersAndSessions,
+		Down:        rollbackAddUsersAndSessions,
+	},
+	{
+		Version:     "015_add_two_factor",
+		Description: "Add TOTP columns to users and a recovery_codes table for two-factor authentication",
+		Up:          migrateAddTwoFactor,
+		Down:        rollbackAddTwoFactor,
+	},
+	{
+		Version:     "016_add_user_tokens",
+		Description: "Add user_tokens table for password reset and email verification, and users.email_verified_at",
+		Up:          migrateAddUserTokens,
+		Down:        rollbackAddUserTokens,
+	},
+	{
+		Version:     "017_add_user_identities",
+		Description: "Add user_identities table linking users to external identity providers",
+		Up:          migrateAddUserIdentities,
+		Down:        rollbackAddUserIdentities,
+	},
+	{
+		Version:     "018_add_user_settings",
+		Description: "Add user_settings table for per-user preferences",
+		Up:          migrateAddUserSettings,
+		Down:        rollbackAddUserSettings,
+	},
+	{
+		Version:     "019_add_audit_log",
+		Description: "Add audit_log table recording changes made through the API",
+		Up:          migrateAddAuditLog,
+		Down:        rollbackAddAuditLog,
+	},
+	{
+		Version:     "020_add_account_rewards",
+		Description: "Add accounts.rewards_balance for credit card rewards",
+		Up:          migrateAddAccountRewards,
+		Down:        rollbackAddAccountRewards,
+	},
+	{
+		Version:     "021_normalize_transaction_dates",
+		Description: "Store transaction dates in UTC and index them by category for month-by-month rollover",
+		Up:          migrateNormalizeTransactionDates,
+		Down:        rollbackNormalizeTransactionDates,
+	},
+	{
+		Version:     "022_add_adjustment_transactions",
+		Description: "Allow 'adjustment' transactions and add transactions.note to explain them",
+		Up:          migrateAddAdjustmentTransactions,
+		Down:        rollbackAddAdjustmentTransactions,
+	},
+	{
+		Version:     "023_add_goals",
+		Description: "Add goals table for category funding targets",
+		Up:          migrateAddGoals,
+		Down:        rollbackAddGoals,
+	},
+	{
+		Version:     "024_add_payees",
+		Description: "Add payees and payee_rules tables and transactions.payee_id for import auto-categorization",
+		Up:          migrateAddPayees,
+		Down:        rollbackAddPayees,
+	},
+	{
+		Version:     "025_add_import_files",
+		Description: "Add import_files table and transactions.import_id to recognize re-uploaded files",
+		Up:          migrateAddImportFiles,
+		Down:        rollbackAddImportFiles,
+	},
+	{
+		Version:     "026_add_allocation_templates",
+		Description: "Add allocation_templates and allocation_template_items for reusable budget plans",
+		Up:          migrateAddAllocationTemplates,
+		Down:        rollbackAddAllocationTemplates,
+	},
+	{
+		Version:     "027_add_account_details",
+		Description: "Add notes, account number last four and interest rate to accounts",
+		Up:          migrateAddAccountDetails,
+		Down:        rollbackAddAccountDetails,
+	},
+	{
+		Version:     "028_add_transfer_hints",
+		Description: "Add transfer_hints to link imported transfers between accounts",
+		Up:          migrateAddTransferHints,
+		Down:        rollbackAddTransferHints,
+	},
+	{
+		Version:     "029_add_category_overspending_mode",
+		Description: "Add overspending_mode to categories (auto, cash, credit)",
+		Up:          migrateAddCategoryOverspendingMode,
+		Down:        rollbackAddCategoryOverspendingMode,
+	},
+	{
+		Version:     "030_add_api_token_guardrails",
+		Description: "Add daily write quotas, write counters and a disabled flag to api_tokens",
+		Up:          migrateAddAPITokenGuardrails,
+		Down:        rollbackAddAPITokenGuardrails,
+	},
+	{
+		Version:     "031_add_budget_revision",
+		Description: "Add budget_state.revision, changed by triggers whenever budget data changes",
+		Up:          migrateAddBudgetRevision,
+		Down:        rollbackAddBudgetRevision,
+	},
+	{
+		Version:     "032_add_import_review",
+		Description: "Add import_files status, balances and commit time, and import_file_rows for reviewing imports",
+		Up:          migrateAddImportReview,
+		Down:        rollbackAddImportReview,
+	},
+	{
+		Version:     "033_add_ready_to_assign_totals",
+		Description: "Add ready_to_assign_totals, per-period inflows and assigned amounts kept up to date by triggers",
+		Up:          migrateAddReadyToAssignTotals,
+		Down:        rollbackAddReadyToAssignTotals,
+	},
+	{
+		Version:     "034_add_transaction_original_amount",
+		Description: "Add original_currency and original_amount to transactions and import_file_rows for foreign-currency charges",
+		Up:          migrateAddTransactionOriginalAmount,
+		Down:        rollbackAddTransactionOriginalAmount,
+	},
+	{
+		Version:     "035_add_account_hidden_from_dashboard",
+		Description: "Add hidden_from_dashboard to accounts",
+		Up:          migrateAddAccountHiddenFromDashboard,
+		Down:        rollbackAddAccountHiddenFromDashboard,
+	},
+	{
+		Version:     "036_add_bank_connections",
+		Description: "Add bank_connections for downloading statements over OFX DirectConnect",
+		Up:          migrateAddBankConnections,
+		Down:        rollbackAddBankConnections,
+	},
+	{
+		Version:     "037_add_widgets",
+		Description: "Add widgets, token-protected read-only views of a category for embedding",
+		Up:          migrateAddWidgets,
+		Down:        rollbackAddWidgets,
+	},
+	{
+		Version:     "038_add_bank_aggregations",
+		Description: "Add bank_aggregations and their accounts for syncing through an aggregator such as SimpleFIN",
+		Up:          migrateAddBankAggregations,
+		Down:        rollbackAddBankAggregations,
+	},
+	{
+		Version:     "039_add_user_disabled_at",
+		Description: "Add disabled_at to users so admins can stop a user signing in",
+		Up:          migrateAddUserDisabledAt,
+		Down:        rollbackAddUserDisabledAt,
+	},
+	{
+		Version:     "040_add_category_moves",
+		Description: "Add category_moves, the ledger of money moved into credit card payment categories",
+		Up:          migrateAddCategoryMoves,
+		Down:        rollbackAddCategoryMoves,
+	},
+	{
+		Version:     "041_add_starting_balance_transactions",
+		Description: "Add the starting_balance transaction type and mark each account's existing starting balance with it",
+		Up:          migrateAddStartingBalanceTransactions,
+		Down:        rollbackAddStartingBalanceTransactions,
+	},
+	{
+		Version:     "042_add_loan_accounts",
+		Description: "Add the loan account type and accounts.loan_monthly_payment and loan_interest_category_id for loan terms",
+		Up:          migrateAddLoanAccounts,
+		Down:        rollbackAddLoanAccounts,
+	},
+	{
+		Version:     "043_add_tracking_accounts",
+		Description: "Add the off-budget tracking account type",
+		Up:          migrateAddTrackingAccounts,
+		Down:        rollbackAddTrackingAccounts,
+	},
+	{
+		Version:     "044_add_transfer_suggestions",
+		Description: "Add transfer_suggestions for transactions that look like both sides of a transfer",
+		Up:          migrateAddTransferSuggestions,
+		Down:        rollbackAddTransferSuggestions,
+	},
+	{
+		Version:     "045_add_transfer_pairings",
+		Description: "Add transfer_pairings to remember reviewed transfer suggestions by payee",
+		Up:          migrateAddTransferPairings,
+		Down:        rollbackAddTransferPairings,
+	},
+	{
+		Version:     "046_add_payee_rule_conditions",
+		Description: "Add account and amount conditions and a memo to payee_rules",
+		Up:          migrateAddPayeeRuleConditions,
+		Down:        rollbackAddPayeeRuleConditions,
+	},
 }
 
 // migrateCategoryIDNullable makes the category_id column nullable in transactions table
@@ -961,3 +1197,1663 @@ func rollbackRequireGroupID(db *sql.DB) error {
 
 	return nil
 }
+
+// migrateAddCategoryClassification adds the classification column to categories
+// Payment categories are classified as debt, everything else starts as discretionary
+func migrateAddCategoryClassification(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var columnExists int
+	err = tx.QueryRow("SELECT COUNT(*) FROM pragma_table_info('categories') WHERE name='classification'").Scan(&columnExists)
+	if err != nil {
+		return fmt.Errorf("failed to check for classification column: %w", err)
+	}
+
+	if columnExists == 0 {
+		_, err = tx.Exec(`
+			ALTER TABLE categories ADD COLUMN classification TEXT NOT NULL DEFAULT 'discretionary'
+			CHECK(classification IN ('essential', 'discretionary', 'savings', 'debt'))
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to add classification column: %w", err)
+		}
+	}
+
+	_, err = tx.Exec("UPDATE categories SET classification = 'debt' WHERE payment_for_account_id IS NOT NULL")
+	if err != nil {
+		return fmt.Errorf("failed to classify payment categories: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// rollbackAddCategoryClassification removes the classification column from categories
+func rollbackAddCategoryClassification(db *sql.DB) error {
+	_, err := db.Exec("ALTER TABLE categories DROP COLUMN classification")
+	return err
+}
+
+// migrateAddSettings creates the settings key/value table
+func migrateAddSettings(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS settings (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL,
+			updated_at DATETIME NOT NULL
+		)
+	`)
+	return err
+}
+
+// rollbackAddSettings drops the settings table
+func rollbackAddSettings(db *sql.DB) error {
+	_, err := db.Exec("DROP TABLE IF EXISTS settings")
+	return err
+}
+
+// migrateAddCPIIndex creates the cpi_index table
+func migrateAddCPIIndex(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS cpi_index (
+			period TEXT PRIMARY KEY,
+			value REAL NOT NULL CHECK(value > 0),
+			source TEXT NOT NULL DEFAULT 'manual',
+			updated_at DATETIME NOT NULL
+		)
+	`)
+	return err
+}
+
+// rollbackAddCPIIndex drops the cpi_index table
+func rollbackAddCPIIndex(db *sql.DB) error {
+	_, err := db.Exec("DROP TABLE IF EXISTS cpi_index")
+	return err
+}
+
+// migrateAddPendingTransactions creates the pending_transactions table
+func migrateAddPendingTransactions(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS pending_transactions (
+			id TEXT PRIMARY KEY,
+			source TEXT NOT NULL,
+			account_id TEXT,
+			amount INTEGER NOT NULL,
+			description TEXT NOT NULL DEFAULT '',
+			date DATETIME NOT NULL,
+			sender TEXT NOT NULL DEFAULT '',
+			subject TEXT NOT NULL DEFAULT '',
+			raw_body TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'approved', 'rejected')),
+			transaction_id TEXT,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE SET NULL
+		)
+	`)
+	return err
+}
+
+// rollbackAddPendingTransactions drops the pending_transactions table
+func rollbackAddPendingTransactions(db *sql.DB) error {
+	_, err := db.Exec("DROP TABLE IF EXISTS pending_transactions")
+	return err
+}
+
+// migrateAddBotChats creates the bot_chats table
+func migrateAddBotChats(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS bot_chats (
+			id TEXT PRIMARY KEY,
+			platform TEXT NOT NULL CHECK(platform IN ('telegram', 'discord', 'api')),
+			chat_id TEXT,
+			name TEXT NOT NULL,
+			token_hash TEXT NOT NULL UNIQUE,
+			default_account_id TEXT,
+			daily_summary INTEGER NOT NULL DEFAULT 0,
+			last_used_at DATETIME,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			UNIQUE(platform, chat_id),
+			FOREIGN KEY (default_account_id) REFERENCES accounts(id) ON DELETE SET NULL
+		)
+	`)
+	return err
+}
+
+// rollbackAddBotChats drops the bot_chats table
+func rollbackAddBotChats(db *sql.DB) error {
+	_, err := db.Exec("DROP TABLE IF EXISTS bot_chats")
+	return err
+}
+
+// migrateAddAPITokens creates the api_tokens table
+func migrateAddAPITokens(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS api_tokens (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			token_prefix TEXT NOT NULL,
+			token_hash TEXT NOT NULL UNIQUE,
+			access TEXT NOT NULL CHECK(access IN ('read', 'write', 'admin')),
+			account_ids TEXT NOT NULL DEFAULT '[]',
+			category_ids TEXT NOT NULL DEFAULT '[]',
+			expires_at DATETIME,
+			last_used_at DATETIME,
+			created_at DATETIME NOT NULL
+		)
+	`)
+	return err
+}
+
+// rollbackAddAPITokens drops the api_tokens table
+func rollbackAddAPITokens(db *sql.DB) error {
+	_, err := db.Exec("DROP TABLE IF EXISTS api_tokens")
+	return err
+}
+
+// migrateAddUsersAndSessions creates the users and sessions tables and adds api_tokens.user_id
+func migrateAddUsersAndSessions(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS users (
+			id TEXT PRIMARY KEY,
+			email TEXT NOT NULL UNIQUE COLLATE NOCASE,
+			name TEXT NOT NULL DEFAULT '',
+			password_hash TEXT NOT NULL DEFAULT '',
+			role TEXT NOT NULL DEFAULT 'member' CHECK(role IN ('admin', 'member')),
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create users table: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS sessions (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			token_hash TEXT NOT NULL UNIQUE,
+			device TEXT NOT NULL DEFAULT '',
+			user_agent TEXT NOT NULL DEFAULT '',
+			ip_address TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL,
+			last_used_at DATETIME NOT NULL,
+			expires_at DATETIME NOT NULL,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create sessions table: %w", err)
+	}
+
+	if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id)`); err != nil {
+		return fmt.Errorf("failed to create sessions index: %w", err)
+	}
+
+	var hasUserID int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('api_tokens') WHERE name = 'user_id'`).Scan(&hasUserID); err != nil {
+		return fmt.Errorf("failed to inspect api_tokens: %w", err)
+	}
+	if hasUserID == 0 {
+		if _, err := tx.Exec(`ALTER TABLE api_tokens ADD COLUMN user_id TEXT`); err != nil {
+			return fmt.Errorf("failed to add api_tokens.user_id: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// rollbackAddUsersAndSessions drops the sessions and users tables and api_tokens.user_id
+func rollbackAddUsersAndSessions(db *sql.DB) error {
+	if _, err := db.Exec("ALTER TABLE api_tokens DROP COLUMN user_id"); err != nil {
+		return err
+	}
+	if _, err := db.Exec("DROP TABLE IF EXISTS sessions"); err != nil {
+		return err
+	}
+	_, err := db.Exec("DROP TABLE IF EXISTS users")
+	return err
+}
+
+// migrateAddTwoFactor adds the TOTP columns to users and creates the recovery_codes table
+func migrateAddTwoFactor(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, column := range []struct{ name, definition string }{
+		{"totp_secret", "TEXT NOT NULL DEFAULT ''"},
+		{"totp_enabled", "INTEGER NOT NULL DEFAULT 0"},
+		{"totp_required", "INTEGER NOT NULL DEFAULT 0"},
+		{"totp_last_step", "INTEGER NOT NULL DEFAULT 0"},
+	} {
+		var columnExists int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('users') WHERE name = ?`, column.name).Scan(&columnExists); err != nil {
+			return fmt.Errorf("failed to inspect users: %w", err)
+		}
+		if columnExists == 0 {
+			if _, err := tx.Exec(`ALTER TABLE users ADD COLUMN ` + column.name + ` ` + column.definition); err != nil {
+				return fmt.Errorf("failed to add users.%s: %w", column.name, err)
+			}
+		}
+	}
+
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS recovery_codes (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			code_hash TEXT NOT NULL,
+			used_at DATETIME,
+			created_at DATETIME NOT NULL,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create recovery_codes table: %w", err)
+	}
+
+	if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_recovery_codes_user_id ON recovery_codes(user_id)`); err != nil {
+		return fmt.Errorf("failed to create recovery_codes index: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// rollbackAddTwoFactor drops the recovery_codes table and the TOTP columns
+func rollbackAddTwoFactor(db *sql.DB) error {
+	if _, err := db.Exec("DROP TABLE IF EXISTS recovery_codes"); err != nil {
+		return err
+	}
+	for _, column := range []string{"totp_secret", "totp_enabled", "totp_required", "totp_last_step"} {
+		if _, err := db.Exec("ALTER TABLE users DROP COLUMN " + column); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateAddUserTokens creates the user_tokens table and adds users.email_verified_at
+func migrateAddUserTokens(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var columnExists int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('users') WHERE name = 'email_verified_at'`).Scan(&columnExists); err != nil {
+		return fmt.Errorf("failed to inspect users: %w", err)
+	}
+	if columnExists == 0 {
+		if _, err := tx.Exec(`ALTER TABLE users ADD COLUMN email_verified_at DATETIME`); err != nil {
+			return fmt.Errorf("failed to add users.email_verified_at: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS user_tokens (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			purpose TEXT NOT NULL CHECK(purpose IN ('password_reset', 'email_verification')),
+			token_hash TEXT NOT NULL UNIQUE,
+			email TEXT NOT NULL,
+			expires_at DATETIME NOT NULL,
+			used_at DATETIME,
+			created_at DATETIME NOT NULL,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create user_tokens table: %w", err)
+	}
+
+	if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_user_tokens_user_id ON user_tokens(user_id)`); err != nil {
+		return fmt.Errorf("failed to create user_tokens index: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// rollbackAddUserTokens drops the user_tokens table and users.email_verified_at
+func rollbackAddUserTokens(db *sql.DB) error {
+	if _, err := db.Exec("DROP TABLE IF EXISTS user_tokens"); err != nil {
+		return err
+	}
+	_, err := db.Exec("ALTER TABLE users DROP COLUMN email_verified_at")
+	return err
+}
+
+// migrateAddUserIdentities creates the user_identities table
+func migrateAddUserIdentities(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS user_identities (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			issuer TEXT NOT NULL,
+			subject TEXT NOT NULL,
+			created_at DATETIME NOT NULL,
+			UNIQUE(issuer, subject),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create user_identities table: %w", err)
+	}
+
+	if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id)`); err != nil {
+		return fmt.Errorf("failed to create user_identities index: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// rollbackAddUserIdentities drops the user_identities table
+func rollbackAddUserIdentities(db *sql.DB) error {
+	_, err := db.Exec("DROP TABLE IF EXISTS user_identities")
+	return err
+}
+
+// migrateAddUserSettings creates the per-user user_settings key/value table
+func migrateAddUserSettings(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS user_settings (
+			user_id TEXT NOT NULL,
+			key TEXT NOT NULL,
+			value TEXT NOT NULL,
+			updated_at DATETIME NOT NULL,
+			PRIMARY KEY (user_id, key),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)
+	`)
+	return err
+}
+
+// rollbackAddUserSettings drops the user_settings table
+func rollbackAddUserSettings(db *sql.DB) error {
+	_, err := db.Exec("DROP TABLE IF EXISTS user_settings")
+	return err
+}
+
+// migrateAddAuditLog creates the audit_log table
+func migrateAddAuditLog(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS audit_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id TEXT,
+			token_id TEXT,
+			actor TEXT NOT NULL,
+			method TEXT NOT NULL,
+			route TEXT NOT NULL,
+			path TEXT NOT NULL,
+			status INTEGER NOT NULL,
+			ip_address TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+	`)
+	return err
+}
+
+// rollbackAddAuditLog drops the audit_log table
+func rollbackAddAuditLog(db *sql.DB) error {
+	_, err := db.Exec("DROP TABLE IF EXISTS audit_log")
+	return err
+}
+
+// migrateAddAccountRewards adds accounts.rewards_balance
+func migrateAddAccountRewards(db *sql.DB) error {
+	var columnExists int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('accounts') WHERE name = 'rewards_balance'`).Scan(&columnExists); err != nil {
+		return fmt.Errorf("failed to inspect accounts: %w", err)
+	}
+	if columnExists > 0 {
+		return nil
+	}
+	_, err := db.Exec(`ALTER TABLE accounts ADD COLUMN rewards_balance INTEGER NOT NULL DEFAULT 0`)
+	return err
+}
+
+// rollbackAddAccountRewards drops accounts.rewards_balance
+func rollbackAddAccountRewards(db *sql.DB) error {
+	_, err := db.Exec("ALTER TABLE accounts DROP COLUMN rewards_balance")
+	return err
+}
+
+// migrateNormalizeTransactionDates rewrites transaction dates stored with a UTC offset in UTC
+// Dates saved with another offset could land in one month for string comparisons and in
+// the next for datetime() ones, so budget months disagreed at the boundary.
+func migrateNormalizeTransactionDates(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		UPDATE transactions
+		SET date = strftime('%Y-%m-%d %H:%M:%S+00:00', date)
+		WHERE datetime(date) IS NOT NULL AND substr(date, -6) != '+00:00'
+	`); err != nil {
+		return fmt.Errorf("failed to normalize transaction dates: %w", err)
+	}
+	if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_transactions_category_date ON transactions(category_id, date)`); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// rollbackNormalizeTransactionDates drops the category/date index
+// The dates stay in UTC; they describe the same instants as before.
+func rollbackNormalizeTransactionDates(db *sql.DB) error {
+	_, err := db.Exec("DROP INDEX IF EXISTS idx_transactions_category_date")
+	return err
+}
+
+// migrateAddAdjustmentTransactions rebuilds transactions to widen the type CHECK constraint
+// SQLite can't alter a constraint in place. New databases already have the final schema.
+func migrateAddAdjustmentTransactions(db *sql.DB) error {
+	var columnExists int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('transactions') WHERE name = 'note'`).Scan(&columnExists); err != nil {
+		return fmt.Errorf("failed to inspect transactions: %w", err)
+	}
+	if columnExists > 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		CREATE TABLE transactions_new (
+			id TEXT PRIMARY KEY,
+			type TEXT NOT NULL DEFAULT 'normal' CHECK(type IN ('normal', 'transfer', 'adjustment')),
+			account_id TEXT NOT NULL,
+			transfer_to_account_id TEXT,
+			category_id TEXT,
+			amount INTEGER NOT NULL,
+			description TEXT,
+			date DATETIME NOT NULL,
+			fitid TEXT,
+			note TEXT,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+			FOREIGN KEY (transfer_to_account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+			FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create new transactions table: %w", err)
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO transactions_new (id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, created_at, updated_at)
+		SELECT id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, created_at, updated_at
+		FROM transactions
+	`); err != nil {
+		return fmt.Errorf("failed to copy data: %w", err)
+	}
+	if _, err := tx.Exec("DROP TABLE transactions"); err != nil {
+		return fmt.Errorf("failed to drop old table: %w", err)
+	}
+	if _, err := tx.Exec("ALTER TABLE transactions_new RENAME TO transactions"); err != nil {
+		return fmt.Errorf("failed to rename table: %w", err)
+	}
+	if _, err := tx.Exec(`
+		CREATE INDEX idx_transactions_account_id ON transactions(account_id);
+		CREATE INDEX idx_transactions_category_id ON transactions(category_id);
+		CREATE INDEX idx_transactions_date ON transactions(date);
+		CREATE INDEX idx_transactions_category_date ON transactions(category_id, date);
+		CREATE INDEX idx_transactions_fitid ON transactions(fitid);
+		CREATE INDEX idx_transactions_transfer_to_account_id ON transactions(transfer_to_account_id);
+	`); err != nil {
+		return fmt.Errorf("failed to recreate indexes: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// rollbackAddAdjustmentTransactions turns adjustments back into normal transactions and
+// drops transactions.note
+// The old CHECK constraint isn't restored; it would only reject adjustments.
+func rollbackAddAdjustmentTransactions(db *sql.DB) error {
+	if _, err := db.Exec("UPDATE transactions SET type = 'normal' WHERE type = 'adjustment'"); err != nil {
+		return err
+	}
+	_, err := db.Exec("ALTER TABLE transactions DROP COLUMN note")
+	return err
+}
+
+// migrateAddGoals creates the goals table
+func migrateAddGoals(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS goals (
+			id TEXT PRIMARY KEY,
+			category_id TEXT NOT NULL UNIQUE,
+			target_amount INTEGER NOT NULL,
+			target_date TEXT,
+			cadence TEXT NOT NULL CHECK(cadence IN ('once', 'monthly', 'yearly')),
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE
+		)
+	`)
+	return err
+}
+
+// rollbackAddGoals drops the goals table
+func rollbackAddGoals(db *sql.DB) error {
+	_, err := db.Exec("DROP TABLE IF EXISTS goals")
+	return err
+}
+
+// migrateAddPayees adds payees, payee_rules and transactions.payee_id
+func migrateAddPayees(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS payees (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create payees: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS payee_rules (
+			id TEXT PRIMARY KEY,
+			pattern TEXT NOT NULL,
+			match_type TEXT NOT NULL CHECK(match_type IN ('contains', 'exact', 'regex')),
+			payee_id TEXT,
+			category_id TEXT,
+			priority INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			FOREIGN KEY (payee_id) REFERENCES payees(id) ON DELETE CASCADE,
+			FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE SET NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create payee_rules: %w", err)
+	}
+
+	var columnExists int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('transactions') WHERE name = 'payee_id'`).Scan(&columnExists); err != nil {
+		return fmt.Errorf("failed to inspect transactions: %w", err)
+	}
+	if columnExists == 0 {
+		if _, err := tx.Exec(`ALTER TABLE transactions ADD COLUMN payee_id TEXT`); err != nil {
+			return fmt.Errorf("failed to add transactions.payee_id: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// rollbackAddPayees drops transactions.payee_id and the payee tables
+func rollbackAddPayees(db *sql.DB) error {
+	if _, err := db.Exec("ALTER TABLE transactions DROP COLUMN payee_id"); err != nil {
+		return err
+	}
+	if _, err := db.Exec("DROP TABLE IF EXISTS payee_rules"); err != nil {
+		return err
+	}
+	_, err := db.Exec("DROP TABLE IF EXISTS payees")
+	return err
+}
+
+// migrateAddImportFiles adds import_files and transactions.import_id
+func migrateAddImportFiles(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS import_files (
+			id TEXT PRIMARY KEY,
+			account_id TEXT NOT NULL,
+			fingerprint TEXT NOT NULL,
+			format TEXT NOT NULL,
+			total_transactions INTEGER NOT NULL DEFAULT 0,
+			imported_transactions INTEGER NOT NULL DEFAULT 0,
+			skipped_duplicates INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL,
+			FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create import_files: %w", err)
+	}
+
+	var columnExists int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('transactions') WHERE name = 'import_id'`).Scan(&columnExists); err != nil {
+		return fmt.Errorf("failed to inspect transactions: %w", err)
+	}
+	if columnExists == 0 {
+		if _, err := tx.Exec(`ALTER TABLE transactions ADD COLUMN import_id TEXT`); err != nil {
+			return fmt.Errorf("failed to add transactions.import_id: %w", err)
+		}
+	}
+
+	for _, index := range []string{
+		`CREATE INDEX IF NOT EXISTS idx_transactions_import_id ON transactions(import_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_import_files_fingerprint ON import_files(account_id, fingerprint)`,
+	} {
+		if _, err := tx.Exec(index); err != nil {
+			return fmt.Errorf("failed to create index: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// rollbackAddImportFiles drops transactions.import_id and import_files
+func rollbackAddImportFiles(db *sql.DB) error {
+	if _, err := db.Exec("DROP INDEX IF EXISTS idx_transactions_import_id"); err != nil {
+		return err
+	}
+	if _, err := db.Exec("ALTER TABLE transactions DROP COLUMN import_id"); err != nil {
+		return err
+	}
+	_, err := db.Exec("DROP TABLE IF EXISTS import_files")
+	return err
+}
+
+// migrateAddAllocationTemplates creates the allocation template tables
+func migrateAddAllocationTemplates(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS allocation_templates (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create allocation_templates: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS allocation_template_items (
+			template_id TEXT NOT NULL,
+			category_id TEXT NOT NULL,
+			amount INTEGER NOT NULL,
+			PRIMARY KEY (template_id, category_id),
+			FOREIGN KEY (template_id) REFERENCES allocation_templates(id) ON DELETE CASCADE,
+			FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create allocation_template_items: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// rollbackAddAllocationTemplates drops the allocation template tables
+func rollbackAddAllocationTemplates(db *sql.DB) error {
+	if _, err := db.Exec("DROP TABLE IF EXISTS allocation_template_items"); err != nil {
+		return err
+	}
+	_, err := db.Exec("DROP TABLE IF EXISTS allocation_templates")
+	return err
+}
+
+// migrateAddAccountDetails adds the reference columns to accounts
+func migrateAddAccountDetails(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, column := range []struct{ name, definition string }{
+		{"notes", "TEXT NOT NULL DEFAULT ''"},
+		{"account_number_last4", "TEXT NOT NULL DEFAULT ''"},
+		{"interest_rate_bps", "INTEGER"},
+	} {
+		var columnExists int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('accounts') WHERE name = ?`, column.name).Scan(&columnExists); err != nil {
+			return fmt.Errorf("failed to inspect accounts: %w", err)
+		}
+		if columnExists == 0 {
+			if _, err := tx.Exec(`ALTER TABLE accounts ADD COLUMN ` + column.name + ` ` + column.definition); err != nil {
+				return fmt.Errorf("failed to add accounts.%s: %w", column.name, err)
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// rollbackAddAccountDetails drops the account reference columns
+func rollbackAddAccountDetails(db *sql.DB) error {
+	for _, column := range []string{"notes", "account_number_last4", "interest_rate_bps"} {
+		if _, err := db.Exec("ALTER TABLE accounts DROP COLUMN " + column); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateAddTransferHints creates the transfer_hints table
+func migrateAddTransferHints(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS transfer_hints (
+			id TEXT PRIMARY KEY,
+			account_id TEXT NOT NULL,
+			pattern TEXT NOT NULL,
+			target_account_id TEXT NOT NULL,
+			created_at DATETIME NOT NULL,
+			FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+			FOREIGN KEY (target_account_id) REFERENCES accounts(id) ON DELETE CASCADE
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create transfer_hints: %w", err)
+	}
+
+	if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_transfer_hints_account_id ON transfer_hints(account_id)`); err != nil {
+		return fmt.Errorf("failed to create transfer_hints index: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// rollbackAddTransferHints drops the transfer_hints table
+func rollbackAddTransferHints(db *sql.DB) error {
+	_, err := db.Exec("DROP TABLE IF EXISTS transfer_hints")
+	return err
+}
+
+// migrateAddCategoryOverspendingMode adds the overspending_mode column to categories
+// Existing categories keep splitting overspending by how it was spent
+func migrateAddCategoryOverspendingMode(db *sql.DB) error {
+	var columnExists int
+	if err := db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('categories') WHERE name='overspending_mode'").Scan(&columnExists); err != nil {
+		return fmt.Errorf("failed to check for overspending_mode column: %w", err)
+	}
+	if columnExists > 0 {
+		return nil
+	}
+
+	if _, err := db.Exec(`
+		ALTER TABLE categories ADD COLUMN overspending_mode TEXT NOT NULL DEFAULT 'auto'
+		CHECK(overspending_mode IN ('auto', 'cash', 'credit'))
+	`); err != nil {
+		return fmt.Errorf("failed to add overspending_mode column: %w", err)
+	}
+	return nil
+}
+
+// rollbackAddCategoryOverspendingMode removes the overspending_mode column from categories
+func rollbackAddCategoryOverspendingMode(db *sql.DB) error {
+	_, err := db.Exec("ALTER TABLE categories DROP COLUMN overspending_mode")
+	return err
+}
+
+// migrateAddAPITokenGuardrails adds the quota, write counter and disabled columns to api_tokens
+func migrateAddAPITokenGuardrails(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, column := range []struct{ name, definition string }{
+		{"daily_write_quota", "INTEGER NOT NULL DEFAULT 0"},
+		{"write_count", "INTEGER NOT NULL DEFAULT 0"},
+		{"write_count_date", "TEXT NOT NULL DEFAULT ''"},
+		{"disabled_at", "DATETIME"},
+	} {
+		var columnExists int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('api_tokens') WHERE name = ?`, column.name).Scan(&columnExists); err != nil {
+			return fmt.Errorf("failed to inspect api_tokens: %w", err)
+		}
+		if columnExists == 0 {
+			if _, err := tx.Exec(`ALTER TABLE api_tokens ADD COLUMN ` + column.name + ` ` + column.definition); err != nil {
+				return fmt.Errorf("failed to add api_tokens.%s: %w", column.name, err)
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// rollbackAddAPITokenGuardrails drops the api_tokens guardrail columns
+func rollbackAddAPITokenGuardrails(db *sql.DB) error {
+	for _, column := range []string{"daily_write_quota", "write_count", "write_count_date", "disabled_at"} {
+		if _, err := db.Exec("ALTER TABLE api_tokens DROP COLUMN " + column); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// budgetRevisionTables are the tables whose changes can move Ready to Assign
+var budgetRevisionTables = []string{"transactions", "allocations", "categories", "accounts"}
+
+// migrateAddBudgetRevision adds budget_state.revision and the triggers that change it
+// The revision is set to a random value rather than incremented, so a change that's rolled
+// back can't be mistaken for a later one that commits.
+func migrateAddBudgetRevision(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var columnExists int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM pragma_table_info('budget_state') WHERE name = 'revision'").Scan(&columnExists); err != nil {
+		return fmt.Errorf("failed to inspect budget_state: %w", err)
+	}
+	if columnExists == 0 {
+		if _, err := tx.Exec("ALTER TABLE budget_state ADD COLUMN revision INTEGER NOT NULL DEFAULT 0"); err != nil {
+			return fmt.Errorf("failed to add revision column: %w", err)
+		}
+	}
+
+	for _, table := range budgetRevisionTables {
+		for _, event := range []string{"insert", "update", "delete"} {
+			trigger := fmt.Sprintf(`
+				CREATE TRIGGER IF NOT EXISTS budget_revision_%s_%s AFTER %s ON %s BEGIN
+					UPDATE budget_state SET revision = random() WHERE id = 'singleton';
+				END
+			`, table, event, strings.ToUpper(event), table)
+			if _, err := tx.Exec(trigger); err != nil {
+				return fmt.Errorf("failed to create %s %s trigger: %w", table, event, err)
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// rollbackAddBudgetRevision drops the revision triggers and column
+func rollbackAddBudgetRevision(db *sql.DB) error {
+	for _, table := range budgetRevisionTables {
+		for _, event := range []string{"insert", "update", "delete"} {
+			if _, err := db.Exec(fmt.Sprintf("DROP TRIGGER IF EXISTS budget_revision_%s_%s", table, event)); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := db.Exec("ALTER TABLE budget_state DROP COLUMN revision")
+	return err
+}
+
+// migrateAddImportReview lets imports wait for review before they reach the ledger
+// Existing imports were saved straight away, so they count as committed.
+func migrateAddImportReview(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, column := range []struct{ name, definition string }{
+		{"status", "TEXT NOT NULL DEFAULT 'committed' CHECK(status IN ('staged', 'committed', 'undone'))"},
+		{"ledger_balance", "INTEGER"},
+		{"balance_change", "INTEGER NOT NULL DEFAULT 0"},
+		{"committed_at", "DATETIME"},
+	} {
+		var columnExists int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('import_files') WHERE name = ?`, column.name).Scan(&columnExists); err != nil {
+			return fmt.Errorf("failed to inspect import_files: %w", err)
+		}
+		if columnExists == 0 {
+			if _, err := tx.Exec(`ALTER TABLE import_files ADD COLUMN ` + column.name + ` ` + column.definition); err != nil {
+				return fmt.Errorf("failed to add import_files.%s: %w", column.name, err)
+			}
+		}
+	}
+	if _, err := tx.Exec(`UPDATE import_files SET committed_at = created_at WHERE status = 'committed' AND committed_at IS NULL`); err != nil {
+		return fmt.Errorf("failed to backfill import commit times: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS import_file_rows (
+			id TEXT PRIMARY KEY,
+			import_id TEXT NOT NULL,
+			position INTEGER NOT NULL,
+			fitid TEXT NOT NULL,
+			date DATETIME NOT NULL,
+			amount INTEGER NOT NULL,
+			description TEXT NOT NULL,
+			category_id TEXT,
+			payee_id TEXT,
+			status TEXT NOT NULL,
+			excluded BOOLEAN NOT NULL DEFAULT 0,
+			error TEXT NOT NULL DEFAULT '',
+			warning TEXT NOT NULL DEFAULT '',
+			transaction_id TEXT NOT NULL DEFAULT '',
+			transfer_account_id TEXT NOT NULL DEFAULT '',
+			transfer_transaction_id TEXT NOT NULL DEFAULT '',
+			placeholder_created BOOLEAN NOT NULL DEFAULT 0,
+			FOREIGN KEY (import_id) REFERENCES import_files(id) ON DELETE CASCADE,
+			FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE SET NULL,
+			FOREIGN KEY (payee_id) REFERENCES payees(id) ON DELETE SET NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create import_file_rows: %w", err)
+	}
+	if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_import_file_rows_import_id ON import_file_rows(import_id, position)`); err != nil {
+		return fmt.Errorf("failed to create import_file_rows index: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// rollbackAddImportReview drops import_file_rows and the import_files review columns
+func rollbackAddImportReview(db *sql.DB) error {
+	if _, err := db.Exec("DROP TABLE IF EXISTS import_file_rows"); err != nil {
+		return err
+	}
+	if _, err := db.Exec("DELETE FROM import_files WHERE status = 'staged'"); err != nil {
+		return err
+	}
+	for _, column := range []string{"status", "ledger_balance", "balance_change", "committed_at"} {
+		if _, err := db.Exec("ALTER TABLE import_files DROP COLUMN " + column); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readyToAssignInflow is true for transactions counted as income towards Ready to Assign
+// row is NEW or OLD inside a trigger.
+func readyToAssignInflow(row string) string {
+	return fmt.Sprintf("%[1]s.amount > 0 AND %[1]s.type NOT IN ('transfer', 'adjustment')", row)
+}
+
+// readyToAssignPayment is true when category ID expression names a credit card payment
+// category, whose allocations don't come out of Ready to Assign
+func readyToAssignPayment(categoryID string) string {
+	return fmt.Sprintf("EXISTS (SELECT 1 FROM categories WHERE id = %s AND payment_for_account_id IS NOT NULL AND payment_for_account_id != '')", categoryID)
+}
+
+// readyToAssignAdd adds inflows and assigned amounts to a period's totals, if condition holds
+func readyToAssignAdd(period, inflows, assigned, condition string) string {
+	return fmt.Sprintf(`
+		INSERT INTO ready_to_assign_totals (period, inflows, assigned)
+		SELECT %s, %s, %s WHERE %s
+		ON CONFLICT(period) DO UPDATE SET inflows = inflows + excluded.inflows, assigned = assigned + excluded.assigned;`,
+		period, inflows, assigned, condition)
+}
+
+// readyToAssignTriggers keep ready_to_assign_totals up to date, by name
+var readyToAssignTriggers = map[string]string{
+	"rta_transactions_insert": `AFTER INSERT ON transactions BEGIN` +
+		readyToAssignAdd("strftime('%Y-%m', datetime(NEW.date))", "NEW.amount", "0", readyToAssignInflow("NEW")) + `
+	END`,
+	"rta_transactions_delete": `AFTER DELETE ON transactions BEGIN` +
+		readyToAssignAdd("strftime('%Y-%m', datetime(OLD.date))", "-OLD.amount", "0", readyToAssignInflow("OLD")) + `
+	END`,
+	"rta_transactions_update": `AFTER UPDATE OF amount, type, date ON transactions BEGIN` +
+		readyToAssignAdd("strftime('%Y-%m', datetime(OLD.date))", "-OLD.amount", "0", readyToAssignInflow("OLD")) +
+		readyToAssignAdd("strftime('%Y-%m', datetime(NEW.date))", "NEW.amount", "0", readyToAssignInflow("NEW")) + `
+	END`,
+	"rta_allocations_insert": `AFTER INSERT ON allocations BEGIN` +
+		readyToAssignAdd("NEW.period", "0", "NEW.amount", "NOT "+readyToAssignPayment("NEW.category_id")) + `
+	END`,
+	// A payment category's allocations are deleted after it, when it can't be looked up any
+	// more; rta_categories_delete adds them back first so the two cancel out
+	"rta_allocations_delete": `AFTER DELETE ON allocations BEGIN` +
+		readyToAssignAdd("OLD.period", "0", "-OLD.amount", "NOT "+readyToAssignPayment("OLD.category_id")) + `
+	END`,
+	"rta_allocations_update": `AFTER UPDATE OF amount, period, category_id ON allocations BEGIN` +
+		readyToAssignAdd("OLD.period", "0", "-OLD.amount", "NOT "+readyToAssignPayment("OLD.category_id")) +
+		readyToAssignAdd("NEW.period", "0", "NEW.amount", "NOT "+readyToAssignPayment("NEW.category_id")) + `
+	END`,
+	"rta_categories_update": `AFTER UPDATE OF payment_for_account_id ON categories
+	WHEN (COALESCE(OLD.payment_for_account_id, '') = '') != (COALESCE(NEW.payment_for_account_id, '') = '') BEGIN
+		INSERT INTO ready_to_assign_totals (period, inflows, assigned)
+		SELECT period, 0, CASE WHEN COALESCE(NEW.payment_for_account_id, '') = '' THEN SUM(amount) ELSE -SUM(amount) END
+		FROM allocations WHERE category_id = NEW.id GROUP BY period
+		ON CONFLICT(period) DO UPDATE SET assigned = assigned + excluded.assigned;
+	END`,
+	"rta_categories_delete": `BEFORE DELETE ON categories WHEN COALESCE(OLD.payment_for_account_id, '') != '' BEGIN
+		INSERT INTO ready_to_assign_totals (period, inflows, assigned)
+		SELECT period, 0, SUM(amount) FROM allocations WHERE category_id = OLD.id GROUP BY period
+		ON CONFLICT(period) DO UPDATE SET assigned = assigned + excluded.assigned;
+	END`,
+}
+
+// migrateAddReadyToAssignTotals keeps per-period Ready to Assign totals in the database
+// Triggers update each month's inflows and assigned amounts in the same transaction as the
+// write that changes them, so every writer keeps them right. The totals are filled in from
+// the existing transactions and allocations.
+func migrateAddReadyToAssignTotals(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS ready_to_assign_totals (
+			period TEXT PRIMARY KEY,
+			inflows INTEGER NOT NULL DEFAULT 0,
+			assigned INTEGER NOT NULL DEFAULT 0
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create ready_to_assign_totals: %w", err)
+	}
+	for name, trigger := range readyToAssignTriggers {
+		if _, err := tx.Exec(fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s %s", name, trigger)); err != nil {
+			return fmt.Errorf("failed to create %s trigger: %w", name, err)
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM ready_to_assign_totals`); err != nil {
+		return fmt.Errorf("failed to clear ready_to_assign_totals: %w", err)
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO ready_to_assign_totals (period, inflows, assigned)
+		SELECT period, SUM(inflows), SUM(assigned) FROM (
+			SELECT strftime('%Y-%m', datetime(date)) AS period, amount AS inflows, 0 AS assigned
+			FROM transactions
+			WHERE amount > 0 AND type NOT IN ('transfer', 'adjustment')
+			UNION ALL
+			SELECT a.period, 0, a.amount
+			FROM allocations a
+			LEFT JOIN categories c ON c.id = a.category_id
+			WHERE COALESCE(c.payment_for_account_id, '') = ''
+		)
+		GROUP BY period
+	`); err != nil {
+		return fmt.Errorf("failed to fill in ready_to_assign_totals: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// rollbackAddReadyToAssignTotals drops the Ready to Assign totals and their triggers
+func rollbackAddReadyToAssignTotals(db *sql.DB) error {
+	for name := range readyToAssignTriggers {
+		if _, err := db.Exec("DROP TRIGGER IF EXISTS " + name); err != nil {
+			return err
+		}
+	}
+	_, err := db.Exec("DROP TABLE IF EXISTS ready_to_assign_totals")
+	return err
+}
+
+// migrateAddTransactionOriginalAmount adds the columns keeping what foreign-currency charges were before conversion
+func migrateAddTransactionOriginalAmount(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"transactions", "import_file_rows"} {
+		for _, column := range []struct{ name, definition string }{
+			{"original_currency", "TEXT"},
+			{"original_amount", "INTEGER"},
+		} {
+			var columnExists int
+			if err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column.name).Scan(&columnExists); err != nil {
+				return fmt.Errorf("failed to inspect %s: %w", table, err)
+			}
+			if columnExists == 0 {
+				if _, err := tx.Exec(`ALTER TABLE ` + table + ` ADD COLUMN ` + column.name + ` ` + column.definition); err != nil {
+					return fmt.Errorf("failed to add %s.%s: %w", table, column.name, err)
+				}
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// rollbackAddTransactionOriginalAmount drops the original amount columns
+func rollbackAddTransactionOriginalAmount(db *sql.DB) error {
+	for _, table := range []string{"transactions", "import_file_rows"} {
+		for _, column := range []string{"original_currency", "original_amount"} {
+			if _, err := db.Exec("ALTER TABLE " + table + " DROP COLUMN " + column); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// migrateAddAccountHiddenFromDashboard adds the flag that keeps an account off the dashboard
+func migrateAddAccountHiddenFromDashboard(db *sql.DB) error {
+	var columnExists int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('accounts') WHERE name = 'hidden_from_dashboard'`).Scan(&columnExists); err != nil {
+		return fmt.Errorf("failed to inspect accounts: %w", err)
+	}
+	if columnExists > 0 {
+		return nil
+	}
+	if _, err := db.Exec(`ALTER TABLE accounts ADD COLUMN hidden_from_dashboard INTEGER NOT NULL DEFAULT 0`); err != nil {
+		return fmt.Errorf("failed to add accounts.hidden_from_dashboard: %w", err)
+	}
+	return nil
+}
+
+// rollbackAddAccountHiddenFromDashboard drops the hidden_from_dashboard column
+func rollbackAddAccountHiddenFromDashboard(db *sql.DB) error {
+	_, err := db.Exec("ALTER TABLE accounts DROP COLUMN hidden_from_dashboard")
+	return err
+}
+
+// migrateAddBankConnections creates the bank_connections table
+func migrateAddBankConnections(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS bank_connections (
+			account_id TEXT PRIMARY KEY,
+			url TEXT NOT NULL,
+			org TEXT NOT NULL,
+			fid TEXT NOT NULL,
+			bank_id TEXT NOT NULL DEFAULT '',
+			account_type TEXT NOT NULL,
+			username TEXT NOT NULL,
+			account_number_last4 TEXT NOT NULL,
+			app_id TEXT NOT NULL DEFAULT '',
+			app_ver TEXT NOT NULL DEFAULT '',
+			ofx_version TEXT NOT NULL DEFAULT '',
+			credentials BLOB NOT NULL,
+			last_synced_at DATETIME,
+			last_error TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create bank_connections: %w", err)
+	}
+	return nil
+}
+
+// rollbackAddBankConnections drops the bank_connections table
+func rollbackAddBankConnections(db *sql.DB) error {
+	_, err := db.Exec("DROP TABLE IF EXISTS bank_connections")
+	return err
+}
+
+// migrateAddWidgets creates the widgets table
+func migrateAddWidgets(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS widgets (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			category_id TEXT NOT NULL,
+			fields TEXT NOT NULL,
+			token_hash TEXT NOT NULL UNIQUE,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create widgets: %w", err)
+	}
+	return nil
+}
+
+// rollbackAddWidgets drops the widgets table
+func rollbackAddWidgets(db *sql.DB) error {
+	_, err := db.Exec("DROP TABLE IF EXISTS widgets")
+	return err
+}
+
+// migrateAddBankAggregations creates the bank_aggregations and bank_aggregation_accounts tables
+func migrateAddBankAggregations(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS bank_aggregations (
+			id TEXT PRIMARY KEY,
+			provider TEXT NOT NULL,
+			name TEXT NOT NULL,
+			credentials BLOB NOT NULL,
+			last_synced_at DATETIME,
+			last_error TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create bank_aggregations: %w", err)
+	}
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS bank_aggregation_accounts (
+			aggregation_id TEXT NOT NULL,
+			remote_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			institution TEXT NOT NULL DEFAULT '',
+			currency TEXT NOT NULL DEFAULT '',
+			balance INTEGER NOT NULL DEFAULT 0,
+			account_id TEXT UNIQUE,
+			last_error TEXT NOT NULL DEFAULT '',
+			updated_at DATETIME NOT NULL,
+			PRIMARY KEY (aggregation_id, remote_id),
+			FOREIGN KEY (aggregation_id) REFERENCES bank_aggregations(id) ON DELETE CASCADE,
+			FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE SET NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create bank_aggregation_accounts: %w", err)
+	}
+	return nil
+}
+
+// rollbackAddBankAggregations drops the bank_aggregation_accounts and bank_aggregations tables
+func rollbackAddBankAggregations(db *sql.DB) error {
+	if _, err := db.Exec("DROP TABLE IF EXISTS bank_aggregation_accounts"); err != nil {
+		return err
+	}
+	_, err := db.Exec("DROP TABLE IF EXISTS bank_aggregations")
+	return err
+}
+
+// migrateAddUserDisabledAt adds the time an admin disabled a user
+func migrateAddUserDisabledAt(db *sql.DB) error {
+	var columnExists int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('users') WHERE name = 'disabled_at'`).Scan(&columnExists); err != nil {
+		return fmt.Errorf("failed to inspect users: %w", err)
+	}
+	if columnExists > 0 {
+		return nil
+	}
+	if _, err := db.Exec(`ALTER TABLE users ADD COLUMN disabled_at DATETIME`); err != nil {
+		return fmt.Errorf("failed to add users.disabled_at: %w", err)
+	}
+	return nil
+}
+
+// rollbackAddUserDisabledAt drops the disabled_at column
+func rollbackAddUserDisabledAt(db *sql.DB) error {
+	_, err := db.Exec("ALTER TABLE users DROP COLUMN disabled_at")
+	return err
+}
+
+// categoryMoveTriggers change the budget revision with category_moves, and keep
+// ready_to_assign_totals up to date with moves out of Ready to Assign, by name; moves
+// between categories don't change it. Moves are only ever inserted and deleted.
+var categoryMoveTriggers = map[string]string{
+	"budget_revision_category_moves_insert": `AFTER INSERT ON category_moves BEGIN
+		UPDATE budget_state SET revision = random() WHERE id = 'singleton';
+	END`,
+	"budget_revision_category_moves_delete": `AFTER DELETE ON category_moves BEGIN
+		UPDATE budget_state SET revision = random() WHERE id = 'singleton';
+	END`,
+	"rta_category_moves_insert": `AFTER INSERT ON category_moves BEGIN` +
+		readyToAssignAdd("NEW.period", "0", "NEW.amount", "NEW.from_category_id IS NULL") + `
+	END`,
+	"rta_category_moves_delete": `AFTER DELETE ON category_moves BEGIN` +
+		readyToAssignAdd("OLD.period", "0", "-OLD.amount", "OLD.from_category_id IS NULL") + `
+	END`,
+}
+
+// migrateAddCategoryMoves creates the category_moves table and its Ready to Assign triggers
+// Payment category allocations made before it are left as they are.
+func migrateAddCategoryMoves(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS category_moves (
+			id TEXT PRIMARY KEY,
+			from_category_id TEXT,
+			to_category_id TEXT NOT NULL,
+			period TEXT NOT NULL,
+			amount INTEGER NOT NULL,
+			reason TEXT NOT NULL,
+			transaction_id TEXT,
+			created_at DATETIME NOT NULL,
+			FOREIGN KEY (from_category_id) REFERENCES categories(id) ON DELETE CASCADE,
+			FOREIGN KEY (to_category_id) REFERENCES categories(id) ON DELETE CASCADE,
+			FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create category_moves: %w", err)
+	}
+	for _, index := range []string{
+		`CREATE INDEX IF NOT EXISTS idx_category_moves_to_category ON category_moves(to_category_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_category_moves_transaction ON category_moves(transaction_id)`,
+	} {
+		if _, err := tx.Exec(index); err != nil {
+			return fmt.Errorf("failed to create category_moves index: %w", err)
+		}
+	}
+	for name, trigger := range categoryMoveTriggers {
+		if _, err := tx.Exec(fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s %s", name, trigger)); err != nil {
+			return fmt.Errorf("failed to create %s trigger: %w", name, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// rollbackAddCategoryMoves takes the moves out of Ready to Assign and drops category_moves
+func rollbackAddCategoryMoves(db *sql.DB) error {
+	for name := range categoryMoveTriggers {
+		if _, err := db.Exec("DROP TRIGGER IF EXISTS " + name); err != nil {
+			return err
+		}
+	}
+	if _, err := db.Exec(`
+		INSERT INTO ready_to_assign_totals (period, inflows, assigned)
+		SELECT period, 0, -SUM(amount) FROM category_moves WHERE from_category_id IS NULL GROUP BY period
+		ON CONFLICT(period) DO UPDATE SET assigned = assigned + excluded.assigned
+	`); err != nil {
+		return err
+	}
+	_, err := db.Exec("DROP TABLE IF EXISTS category_moves")
+	return err
+}
+
+// widenCheck rebuilds table with its oldCheck constraint replaced by newCheck, then runs update
+// SQLite can't alter a constraint in place, so the table is rebuilt from its own definition
+// and its indexes and triggers recreated. Foreign keys are off while it's rebuilt, or
+// dropping the old table would delete the rows that refer to it. A table that doesn't have
+// oldCheck, like a new database's, is left as it is; update, if any, runs either way.
+func widenCheck(db *sql.DB, table, oldCheck, newCheck string, update func(*sql.Tx) error) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return fmt.Errorf("failed to turn off foreign keys: %w", err)
+	}
+	defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var definition string
+	if err := tx.QueryRow("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&definition); err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", table, err)
+	}
+	if strings.Contains(definition, oldCheck) {
+		rows, err := tx.Query("SELECT sql FROM sqlite_master WHERE type IN ('index', 'trigger') AND tbl_name = ? AND sql IS NOT NULL", table)
+		if err != nil {
+			return fmt.Errorf("failed to list %s indexes and triggers: %w", table, err)
+		}
+		var dependents []string
+		for rows.Next() {
+			var dependent string
+			if err := rows.Scan(&dependent); err != nil {
+				rows.Close()
+				return err
+			}
+			dependents = append(dependents, dependent)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		columns := strings.Replace(definition[strings.Index(definition, "("):], oldCheck, newCheck, 1)
+		if _, err := tx.Exec("CREATE TABLE " + table + "_new " + columns); err != nil {
+			return fmt.Errorf("failed to create new %s table: %w", table, err)
+		}
+		if _, err := tx.Exec("INSERT INTO " + table + "_new SELECT * FROM " + table); err != nil {
+			return fmt.Errorf("failed to copy data: %w", err)
+		}
+		if _, err := tx.Exec("DROP TABLE " + table); err != nil {
+			return fmt.Errorf("failed to drop old table: %w", err)
+		}
+		if _, err := tx.Exec("ALTER TABLE " + table + "_new RENAME TO " + table); err != nil {
+			return fmt.Errorf("failed to rename table: %w", err)
+		}
+		for _, dependent := range dependents {
+			if _, err := tx.Exec(dependent); err != nil {
+				return fmt.Errorf("failed to recreate %q: %w", dependent, err)
+			}
+		}
+	}
+
+	if update != nil {
+		if err := update(tx); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// migrateAddStartingBalanceTransactions widens the transactions type CHECK constraint and
+// turns the "Starting balance" transactions accounts were opened with into starting balances
+// Only each account's first matching transaction is taken, as AccountService did.
+func migrateAddStartingBalanceTransactions(db *sql.DB) error {
+	return widenCheck(db, "transactions",
+		"CHECK(type IN ('normal', 'transfer', 'adjustment'))",
+		"CHECK(type IN ('normal', 'transfer', 'adjustment', 'starting_balance'))",
+		func(tx *sql.Tx) error {
+			if _, err := tx.Exec(`
+				UPDATE transactions SET type = 'starting_balance'
+				WHERE id IN (
+					SELECT (
+						SELECT id FROM transactions t
+						WHERE t.account_id = a.id AND t.type = 'normal' AND t.description = 'Starting balance'
+							AND t.category_id IS NULL AND t.import_id IS NULL AND t.fitid IS NULL
+						ORDER BY t.created_at LIMIT 1
+					)
+					FROM accounts a
+				)
+			`); err != nil {
+				return fmt.Errorf("failed to mark starting balances: %w", err)
+			}
+			return nil
+		})
+}
+
+// rollbackAddStartingBalanceTransactions turns starting balances back into normal transactions
+// The old CHECK constraint isn't restored; it would only reject starting balances.
+func rollbackAddStartingBalanceTransactions(db *sql.DB) error {
+	_, err := db.Exec("UPDATE transactions SET type = 'normal' WHERE type = 'starting_balance'")
+	return err
+}
+
+// migrateAddLoanAccounts widens the accounts type CHECK constraint for loans and adds the
+// columns keeping their terms
+func migrateAddLoanAccounts(db *sql.DB) error {
+	return widenCheck(db, "accounts",
+		"CHECK(type IN ('checking', 'savings', 'cash', 'credit'))",
+		"CHECK(type IN ('checking', 'savings', 'cash', 'credit', 'loan'))",
+		func(tx *sql.Tx) error {
+			for _, column := range []struct{ name, definition string }{
+				{"loan_monthly_payment", "INTEGER"},
+				{"loan_interest_category_id", "TEXT REFERENCES categories(id) ON DELETE SET NULL"},
+			} {
+				var columnExists int
+				if err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('accounts') WHERE name = ?`, column.name).Scan(&columnExists); err != nil {
+					return fmt.Errorf("failed to inspect accounts: %w", err)
+				}
+				if columnExists == 0 {
+					if _, err := tx.Exec(`ALTER TABLE accounts ADD COLUMN ` + column.name + ` ` + column.definition); err != nil {
+						return fmt.Errorf("failed to add accounts.%s: %w", column.name, err)
+					}
+				}
+			}
+			return nil
+		})
+}
+
+// rollbackAddLoanAccounts turns loans into checking accounts
+// The loan columns and widened CHECK constraint are left; SQLite can't drop a column with a
+// foreign key, and the constraint would only reject loans.
+func rollbackAddLoanAccounts(db *sql.DB) error {
+	_, err := db.Exec("UPDATE accounts SET type = 'checking' WHERE type = 'loan'")
+	return err
+}
+
+// migrateAddTrackingAccounts allows the off-budget tracking account type
+func migrateAddTrackingAccounts(db *sql.DB) error {
+	return widenCheck(db, "accounts",
+		"CHECK(type IN ('checking', 'savings', 'cash', 'credit', 'loan'))",
+		"CHECK(type IN ('checking', 'savings', 'cash', 'credit', 'loan', 'tracking'))", nil)
+}
+
+// rollbackAddTrackingAccounts turns tracking accounts into savings accounts
+// Their balance changes stay adjustments, so Ready to Assign is unchanged; the widened
+// CHECK constraint is left.
+func rollbackAddTrackingAccounts(db *sql.DB) error {
+	_, err := db.Exec("UPDATE accounts SET type = 'savings' WHERE type = 'tracking'")
+	return err
+}
+
+// migrateAddTransferSuggestions creates the transfer_suggestions table
+func migrateAddTransferSuggestions(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS transfer_suggestions (
+			id TEXT PRIMARY KEY,
+			from_transaction_id TEXT NOT NULL,
+			to_transaction_id TEXT NOT NULL,
+			score INTEGER NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'accepted', 'rejected')),
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			FOREIGN KEY (from_transaction_id) REFERENCES transactions(id) ON DELETE CASCADE,
+			FOREIGN KEY (to_transaction_id) REFERENCES transactions(id) ON DELETE CASCADE
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create transfer_suggestions: %w", err)
+	}
+
+	for _, index := range []string{
+		`CREATE INDEX IF NOT EXISTS idx_transfer_suggestions_from ON transfer_suggestions(from_transaction_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_transfer_suggestions_to ON transfer_suggestions(to_transaction_id)`,
+	} {
+		if _, err := tx.Exec(index); err != nil {
+			return fmt.Errorf("failed to create transfer_suggestions index: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// rollbackAddTransferSuggestions drops the transfer_suggestions table
+func rollbackAddTransferSuggestions(db *sql.DB) error {
+	_, err := db.Exec("DROP TABLE IF EXISTS transfer_suggestions")
+	return err
+}
+
+// migrateAddTransferPairings creates the transfer_pairings table
+func migrateAddTransferPairings(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS transfer_pairings (
+			id TEXT PRIMARY KEY,
+			from_account_id TEXT NOT NULL,
+			from_payee TEXT NOT NULL,
+			to_account_id TEXT NOT NULL,
+			to_payee TEXT NOT NULL,
+			accepted INTEGER NOT NULL DEFAULT 0,
+			rejected INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			UNIQUE(from_account_id, from_payee, to_account_id, to_payee),
+			FOREIGN KEY (from_account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+			FOREIGN KEY (to_account_id) REFERENCES accounts(id) ON DELETE CASCADE
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create transfer_pairings: %w", err)
+	}
+	return nil
+}
+
+// rollbackAddTransferPairings drops the transfer_pairings table
+func rollbackAddTransferPairings(db *sql.DB) error {
+	_, err := db.Exec("DROP TABLE IF EXISTS transfer_pairings")
+	return err
+}
+
+// migrateAddPayeeRuleConditions adds payee_rules' account and amount conditions and memo,
+// and import_file_rows.note for the memo a rule gives a staged row
+func migrateAddPayeeRuleConditions(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, column := range []struct{ table, name, definition string }{
+		{"payee_rules", "account_id", "TEXT REFERENCES accounts(id) ON DELETE CASCADE"},
+		{"payee_rules", "min_amount", "INTEGER"},
+		{"payee_rules", "max_amount", "INTEGER"},
+		{"payee_rules", "note", "TEXT"},
+		{"import_file_rows", "note", "TEXT"},
+	} {
+		var columnExists int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, column.table, column.name).Scan(&columnExists); err != nil {
+			return fmt.Errorf("failed to inspect %s: %w", column.table, err)
+		}
+		if columnExists == 0 {
+			if _, err := tx.Exec(`ALTER TABLE ` + column.table + ` ADD COLUMN ` + column.name + ` ` + column.definition); err != nil {
+				return fmt.Errorf("failed to add %s.%s: %w", column.table, column.name, err)
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// rollbackAddPayeeRuleConditions drops the memo and amount columns
+// Rules with conditions the older schema can't express are deleted rather than left to
+// match every transaction. payee_rules.account_id is left; SQLite can't drop a column with
+// a foreign key.
+func rollbackAddPayeeRuleConditions(db *sql.DB) error {
+	if _, err := db.Exec(`DELETE FROM payee_rules WHERE account_id IS NOT NULL OR min_amount IS NOT NULL OR max_amount IS NOT NULL OR pattern = ''`); err != nil {
+		return err
+	}
+	for _, statement := range []string{
+		"ALTER TABLE payee_rules DROP COLUMN min_amount",
+		"ALTER TABLE payee_rules DROP COLUMN max_amount",
+		"ALTER TABLE payee_rules DROP COLUMN note",
+		"ALTER TABLE import_file_rows DROP COLUMN note",
+	} {
+		if _, err := db.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
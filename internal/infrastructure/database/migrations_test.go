@@ -0,0 +1,186 @@
+package database
+
+import (
+	"database/sql"
+	"slices"
+	"testing"
+)
+
+// openOldDB opens an empty in-memory database to build an older schema in
+func openOldDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:?"+sqliteOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1) // Each connection to :memory: is a database of its own
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func execAll(t *testing.T, db *sql.DB, statements ...string) {
+	t.Helper()
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("%v: %s", err, statement)
+		}
+	}
+}
+
+func TestMigrateAddCategoryClassification(t *testing.T) {
+	db := openOldDB(t)
+	execAll(t, db,
+		`CREATE TABLE categories (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			payment_for_account_id TEXT
+		)`,
+		`INSERT INTO categories (id, name, payment_for_account_id) VALUES
+			('groceries', 'Groceries', NULL),
+			('visa', 'Visa Payment', 'card')`,
+	)
+
+	// Running it again, as after a failed partial run, must be harmless
+	for range 2 {
+		if err := migrateAddCategoryClassification(db); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	classifications := make(map[string]string)
+	rows, err := db.Query("SELECT id, classification FROM categories")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, classification string
+		if err := rows.Scan(&id, &classification); err != nil {
+			t.Fatal(err)
+		}
+		classifications[id] = classification
+	}
+	if classifications["groceries"] != "discretionary" || classifications["visa"] != "debt" {
+		t.Errorf("expected payment categories backfilled as debt and the rest discretionary, got %v", classifications)
+	}
+
+	if _, err := db.Exec("INSERT INTO categories (id, name, classification) VALUES ('x', 'X', 'luxury')"); err == nil {
+		t.Error("expected an unknown classification to be refused")
+	}
+}
+
+func TestMigrateAddStartingBalanceTransactions(t *testing.T) {
+	db := openOldDB(t)
+	execAll(t, db,
+		`CREATE TABLE accounts (id TEXT PRIMARY KEY, name TEXT NOT NULL)`,
+		`CREATE TABLE categories (id TEXT PRIMARY KEY, name TEXT NOT NULL, payment_for_account_id TEXT)`,
+		`CREATE TABLE allocations (id TEXT PRIMARY KEY, category_id TEXT NOT NULL, amount INTEGER NOT NULL, period TEXT NOT NULL)`,
+		`CREATE TABLE budget_state (id TEXT PRIMARY KEY, ready_to_assign INTEGER NOT NULL DEFAULT 0, updated_at DATETIME NOT NULL)`,
+		`CREATE TABLE transactions (
+			id TEXT PRIMARY KEY,
+			type TEXT NOT NULL DEFAULT 'normal' CHECK(type IN ('normal', 'transfer', 'adjustment')),
+			account_id TEXT NOT NULL,
+			transfer_to_account_id TEXT,
+			category_id TEXT,
+			amount INTEGER NOT NULL,
+			description TEXT,
+			date DATETIME NOT NULL,
+			fitid TEXT,
+			import_id TEXT,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+			FOREIGN KEY (transfer_to_account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+			FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX idx_transactions_account_id ON transactions(account_id)`,
+		`CREATE INDEX idx_transactions_category_date ON transactions(category_id, date)`,
+		`CREATE INDEX idx_transactions_import_id ON transactions(import_id)`,
+	)
+	for _, migrate := range []func(*sql.DB) error{migrateAddBudgetRevision, migrateAddReadyToAssignTotals, migrateAddCategoryMoves} {
+		if err := migrate(db); err != nil {
+			t.Fatal(err)
+		}
+	}
+	execAll(t, db,
+		`INSERT INTO budget_state (id, updated_at) VALUES ('singleton', '2025-01-01')`,
+		`INSERT INTO accounts (id, name) VALUES ('checking', 'Checking'), ('savings', 'Savings'), ('card', 'Visa')`,
+		`INSERT INTO categories (id, name, payment_for_account_id) VALUES ('groceries', 'Groceries', NULL), ('visa', 'Visa Payment', 'card')`,
+		`INSERT INTO transactions (id, account_id, category_id, amount, description, date, import_id, created_at, updated_at) VALUES
+			('checking-opened', 'checking', NULL, 100000, 'Starting balance', '2025-01-01 00:00:00', NULL, '2025-01-01 09:00:00', '2025-01-01 09:00:00'),
+			('checking-later', 'checking', NULL, 5000, 'Starting balance', '2025-01-20 00:00:00', NULL, '2025-01-20 09:00:00', '2025-01-20 09:00:00'),
+			('savings-opened', 'savings', NULL, 20000, 'Starting balance', '2025-01-01 00:00:00', NULL, '2025-01-01 09:00:00', '2025-01-01 09:00:00'),
+			('card-imported', 'card', NULL, -30000, 'Starting balance', '2025-01-01 00:00:00', 'import-1', '2025-01-01 09:00:00', '2025-01-01 09:00:00'),
+			('market', 'card', 'groceries', -2500, 'Market', '2025-01-10 00:00:00', NULL, '2025-01-10 09:00:00', '2025-01-10 09:00:00')`,
+		`INSERT INTO category_moves (id, from_category_id, to_category_id, period, amount, reason, transaction_id, created_at) VALUES
+			('spending', 'groceries', 'visa', '2025-01', 2500, 'spending', 'market', '2025-01-10 09:00:00'),
+			('cover', NULL, 'visa', '2025-01', 1000, 'cover', NULL, '2025-01-11 09:00:00')`,
+	)
+
+	dependents := func() []string {
+		t.Helper()
+		return queryStrings(t, db, "SELECT name FROM sqlite_master WHERE type IN ('index', 'trigger') AND tbl_name = 'transactions' AND sql IS NOT NULL ORDER BY name")
+	}
+	totals := func() []string {
+		t.Helper()
+		return queryStrings(t, db, "SELECT period || ':' || inflows || ':' || assigned FROM ready_to_assign_totals ORDER BY period")
+	}
+	dependentsBefore, totalsBefore := dependents(), totals()
+
+	if err := migrateAddStartingBalanceTransactions(db); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rebuilding the table with foreign keys off keeps the moves referring to its transactions
+	if moves := queryStrings(t, db, "SELECT id || ':' || COALESCE(transaction_id, '') FROM category_moves ORDER BY id"); !slices.Equal(moves, []string{"cover:", "spending:market"}) {
+		t.Errorf("expected both moves kept, got %v", moves)
+	}
+	if broken := queryStrings(t, db, "SELECT \"table\" FROM pragma_foreign_key_check"); len(broken) != 0 {
+		t.Errorf("expected no broken foreign keys, got %v", broken)
+	}
+	if after := dependents(); !slices.Equal(after, dependentsBefore) || !slices.Contains(after, "rta_transactions_update") {
+		t.Errorf("expected the indexes and triggers %v recreated, got %v", dependentsBefore, after)
+	}
+
+	// Only each account's first hand-entered starting balance is retyped; trends skip that type
+	types := queryStrings(t, db, "SELECT id || ':' || type FROM transactions ORDER BY id")
+	want := []string{"card-imported:normal", "checking-later:normal", "checking-opened:starting_balance", "market:normal", "savings-opened:starting_balance"}
+	if !slices.Equal(types, want) {
+		t.Errorf("expected types %v, got %v", want, types)
+	}
+
+	// Ready to Assign still counts starting balances, and the recreated triggers keep it up to date
+	if after := totals(); !slices.Equal(after, totalsBefore) || !slices.Equal(after, []string{"2025-01:125000:1000"}) {
+		t.Errorf("expected Ready to Assign totals %v unchanged, got %v", totalsBefore, after)
+	}
+	execAll(t, db, `INSERT INTO transactions (id, type, account_id, amount, description, date, created_at, updated_at) VALUES
+		('opened-later', 'starting_balance', 'card', 4000, 'Starting balance', '2025-02-01 00:00:00', '2025-02-01 09:00:00', '2025-02-01 09:00:00')`)
+	if after := totals(); !slices.Equal(after, []string{"2025-01:125000:1000", "2025-02:4000:0"}) {
+		t.Errorf("expected a new starting balance counted towards Ready to Assign, got %v", after)
+	}
+	if _, err := db.Exec(`INSERT INTO transactions (id, type, account_id, amount, date, created_at, updated_at) VALUES
+		('bogus', 'refund', 'card', 1, '2025-02-01', '2025-02-01', '2025-02-01')`); err == nil {
+		t.Error("expected an unknown type to be refused")
+	}
+}
+
+func queryStrings(t *testing.T, db *sql.DB, query string) []string {
+	t.Helper()
+	rows, err := db.Query(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			t.Fatal(err)
+		}
+		values = append(values, value)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return values
+}
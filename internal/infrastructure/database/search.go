@@ -0,0 +1,101 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// searchTriggers keep transactions_fts in step with transactions and payee names
+var searchTriggers = map[string]string{
+	"transactions_fts_insert": `
+		CREATE TRIGGER transactions_fts_insert AFTER INSERT ON transactions BEGIN
+			INSERT INTO transactions_fts (transaction_id, description, note, payee)
+			VALUES (new.id, COALESCE(new.description, ''), COALESCE(new.note, ''),
+				COALESCE((SELECT name FROM payees WHERE id = new.payee_id), ''));
+		END`,
+	"transactions_fts_update": `
+		CREATE TRIGGER transactions_fts_update AFTER UPDATE ON transactions BEGIN
+			DELETE FROM transactions_fts WHERE transaction_id = old.id;
+			INSERT INTO transactions_fts (transaction_id, description, note, payee)
+			VALUES (new.id, COALESCE(new.description, ''), COALESCE(new.note, ''),
+				COALESCE((SELECT name FROM payees WHERE id = new.payee_id), ''));
+		END`,
+	"transactions_fts_delete": `
+		CREATE TRIGGER transactions_fts_delete AFTER DELETE ON transactions BEGIN
+			DELETE FROM transactions_fts WHERE transaction_id = old.id;
+		END`,
+	"transactions_fts_payee_rename": `
+		CREATE TRIGGER transactions_fts_payee_rename AFTER UPDATE OF name ON payees BEGIN
+			UPDATE transactions_fts SET payee = new.name
+			WHERE transaction_id IN (SELECT id FROM transactions WHERE payee_id = new.id);
+		END`,
+}
+
+// ensureSearchIndex sets up full-text search over transactions when SQLite has FTS5
+// FTS5 is only compiled in with the sqlite_fts5 build tag, so this runs on every start
+// rather than as a migration: the same database may be opened by builds with and without
+// it. Without FTS5 the triggers are dropped, as they would make every transaction write
+// fail, and searches fall back to LIKE. When FTS5 is back the index is rebuilt, since it
+// missed whatever changed in between.
+func ensureSearchIndex(db *sql.DB) error {
+	var available bool
+	if err := db.QueryRow("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&available); err != nil {
+		return fmt.Errorf("failed to check for FTS5: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	missing := 0
+	for name := range searchTriggers {
+		var exists int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name = ?", name).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check for search trigger: %w", err)
+		}
+		if exists == 0 {
+			missing++
+		} else if !available {
+			if _, err := tx.Exec("DROP TRIGGER " + name); err != nil {
+				return fmt.Errorf("failed to drop search trigger: %w", err)
+			}
+		}
+	}
+	if !available {
+		return tx.Commit()
+	}
+	if missing == 0 {
+		return nil
+	}
+
+	if _, err := tx.Exec(`
+		CREATE VIRTUAL TABLE IF NOT EXISTS transactions_fts USING fts5(
+			transaction_id UNINDEXED, description, note, payee
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create transactions_fts: %w", err)
+	}
+	for name, trigger := range searchTriggers {
+		if _, err := tx.Exec("DROP TRIGGER IF EXISTS " + name); err != nil {
+			return fmt.Errorf("failed to drop search trigger: %w", err)
+		}
+		if _, err := tx.Exec(trigger); err != nil {
+			return fmt.Errorf("failed to create search trigger %s: %w", name, err)
+		}
+	}
+
+	if _, err := tx.Exec("DELETE FROM transactions_fts"); err != nil {
+		return fmt.Errorf("failed to clear transactions_fts: %w", err)
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO transactions_fts (transaction_id, description, note, payee)
+		SELECT t.id, COALESCE(t.description, ''), COALESCE(t.note, ''), COALESCE(p.name, '')
+		FROM transactions t
+		LEFT JOIN payees p ON p.id = t.payee_id
+	`); err != nil {
+		return fmt.Errorf("failed to index transactions: %w", err)
+	}
+	return tx.Commit()
+}
@@ -3,13 +3,25 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
+// sqliteOptions are set on every pooled connection, not just the first
+// Foreign keys are per connection in SQLite, and the cascades depend on them. Background
+// writers (bank sync, scans, the bot, the recategorizer) run alongside request
+// transactions, so writers wait for the lock instead of failing with SQLITE_BUSY, and
+// transactions take the write lock when they begin rather than failing to upgrade to it.
+const sqliteOptions = "_busy_timeout=5000&_txlock=immediate&_foreign_keys=on"
+
 // NewSQLiteDB creates a new SQLite database connection
 func NewSQLiteDB(dbPath string) (*sql.DB, error) {
-	db, err := sql.Open("sqlite3", dbPath)
+	separator := "?"
+	if strings.Contains(dbPath, "?") {
+		separator = "&"
+	}
+	db, err := sql.Open("sqlite3", dbPath+separator+sqliteOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -19,11 +31,6 @@ func NewSQLiteDB(dbPath string) (*sql.DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	// Enable foreign keys
-	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
-		return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
-	}
-
 	// Initialize schema
 	if err := initSchema(db); err != nil {
 		return nil, fmt.Errorf("failed to initialize schema: %w", err)
@@ -34,6 +41,11 @@ func NewSQLiteDB(dbPath string) (*sql.DB, error) {
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
 
+	// Full-text search, when this build of SQLite supports it
+	if err := ensureSearchIndex(db); err != nil {
+		return nil, fmt.Errorf("failed to set up search: %w", err)
+	}
+
 	return db, nil
 }
 
@@ -45,9 +57,17 @@ func initSchema(db *sql.DB) error {
 		id TEXT PRIMARY KEY,
 		name TEXT NOT NULL,
 		balance INTEGER NOT NULL,
-		type TEXT NOT NULL CHECK(type IN ('checking', 'savings', 'cash', 'credit')),
+		type TEXT NOT NULL CHECK(type IN ('checking', 'savings', 'cash', 'credit', 'loan', 'tracking')),
+		rewards_balance INTEGER NOT NULL DEFAULT 0,
+		notes TEXT NOT NULL DEFAULT '',
+		account_number_last4 TEXT NOT NULL DEFAULT '',
+		interest_rate_bps INTEGER,
+		hidden_from_dashboard INTEGER NOT NULL DEFAULT 0,
+		loan_monthly_payment INTEGER,
+		loan_interest_category_id TEXT,
 		created_at DATETIME NOT NULL,
-		updated_at DATETIME NOT NULL
+		updated_at DATETIME NOT NULL,
+		FOREIGN KEY (loan_interest_category_id) REFERENCES categories(id) ON DELETE SET NULL
 	);
 
 	CREATE TABLE IF NOT EXISTS category_groups (
@@ -66,6 +86,8 @@ func initSchema(db *sql.DB) error {
 		color TEXT,
 		group_id TEXT NOT NULL,
 		payment_for_account_id TEXT,
+		classification TEXT NOT NULL DEFAULT 'discretionary' CHECK(classification IN ('essential', 'discretionary', 'savings', 'debt')),
+		overspending_mode TEXT NOT NULL DEFAULT 'auto' CHECK(overspending_mode IN ('auto', 'cash', 'credit')),
 		created_at DATETIME NOT NULL,
 		updated_at DATETIME NOT NULL,
 		FOREIGN KEY (group_id) REFERENCES category_groups(id) ON DELETE RESTRICT,
@@ -74,7 +96,7 @@ func initSchema(db *sql.DB) error {
 
 	CREATE TABLE IF NOT EXISTS transactions (
 		id TEXT PRIMARY KEY,
-		type TEXT NOT NULL DEFAULT 'normal' CHECK(type IN ('normal', 'transfer')),
+		type TEXT NOT NULL DEFAULT 'normal' CHECK(type IN ('normal', 'transfer', 'adjustment', 'starting_balance')),
 		account_id TEXT NOT NULL,
 		transfer_to_account_id TEXT,
 		category_id TEXT,
@@ -82,6 +104,11 @@ func initSchema(db *sql.DB) error {
 		description TEXT,
 		date DATETIME NOT NULL,
 		fitid TEXT,
+		note TEXT,
+		payee_id TEXT,
+		import_id TEXT,
+		original_currency TEXT,
+		original_amount INTEGER,
 		created_at DATETIME NOT NULL,
 		updated_at DATETIME NOT NULL,
 		FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE,
@@ -101,21 +128,390 @@ func initSchema(db *sql.DB) error {
 		UNIQUE(category_id, period)
 	);
 
+	CREATE TABLE IF NOT EXISTS category_moves (
+		id TEXT PRIMARY KEY,
+		from_category_id TEXT,
+		to_category_id TEXT NOT NULL,
+		period TEXT NOT NULL,
+		amount INTEGER NOT NULL,
+		reason TEXT NOT NULL,
+		transaction_id TEXT,
+		created_at DATETIME NOT NULL,
+		FOREIGN KEY (from_category_id) REFERENCES categories(id) ON DELETE CASCADE,
+		FOREIGN KEY (to_category_id) REFERENCES categories(id) ON DELETE CASCADE,
+		FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE
+	);
+
 	CREATE TABLE IF NOT EXISTS budget_state (
 		id TEXT PRIMARY KEY,
 		ready_to_assign INTEGER NOT NULL DEFAULT 0,
+		revision INTEGER NOT NULL DEFAULT 0, -- Changed by triggers (migration 031) whenever budget data changes
+		updated_at DATETIME NOT NULL
+	);
+
+	-- Per-period Ready to Assign totals, kept up to date by triggers (migration 033)
+	CREATE TABLE IF NOT EXISTS ready_to_assign_totals (
+		period TEXT PRIMARY KEY,
+		inflows INTEGER NOT NULL DEFAULT 0,
+		assigned INTEGER NOT NULL DEFAULT 0
+	);
+
+	CREATE TABLE IF NOT EXISTS settings (
+		key TEXT PRIMARY KEY,
+		value TEXT NOT NULL,
+		updated_at DATETIME NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS cpi_index (
+		period TEXT PRIMARY KEY,
+		value REAL NOT NULL CHECK(value > 0),
+		source TEXT NOT NULL DEFAULT 'manual',
+		updated_at DATETIME NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS pending_transactions (
+		id TEXT PRIMARY KEY,
+		source TEXT NOT NULL,
+		account_id TEXT,
+		amount INTEGER NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		date DATETIME NOT NULL,
+		sender TEXT NOT NULL DEFAULT '',
+		subject TEXT NOT NULL DEFAULT '',
+		raw_body TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'approved', 'rejected')),
+		transaction_id TEXT,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE SET NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS bot_chats (
+		id TEXT PRIMARY KEY,
+		platform TEXT NOT NULL CHECK(platform IN ('telegram', 'discord', 'api')),
+		chat_id TEXT,
+		name TEXT NOT NULL,
+		token_hash TEXT NOT NULL UNIQUE,
+		default_account_id TEXT,
+		daily_summary INTEGER NOT NULL DEFAULT 0,
+		last_used_at DATETIME,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		UNIQUE(platform, chat_id),
+		FOREIGN KEY (default_account_id) REFERENCES accounts(id) ON DELETE SET NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS users (
+		id TEXT PRIMARY KEY,
+		email TEXT NOT NULL UNIQUE COLLATE NOCASE,
+		name TEXT NOT NULL DEFAULT '',
+		password_hash TEXT NOT NULL DEFAULT '',
+		role TEXT NOT NULL DEFAULT 'member' CHECK(role IN ('admin', 'member')),
+		totp_secret TEXT NOT NULL DEFAULT '',
+		totp_enabled INTEGER NOT NULL DEFAULT 0,
+		totp_required INTEGER NOT NULL DEFAULT 0,
+		totp_last_step INTEGER NOT NULL DEFAULT 0,
+		email_verified_at DATETIME,
+		disabled_at DATETIME,
+		created_at DATETIME NOT NULL,
 		updated_at DATETIME NOT NULL
 	);
 
+	CREATE TABLE IF NOT EXISTS recovery_codes (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		code_hash TEXT NOT NULL,
+		used_at DATETIME,
+		created_at DATETIME NOT NULL,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS user_settings (
+		user_id TEXT NOT NULL,
+		key TEXT NOT NULL,
+		value TEXT NOT NULL,
+		updated_at DATETIME NOT NULL,
+		PRIMARY KEY (user_id, key),
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS user_identities (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		issuer TEXT NOT NULL,
+		subject TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		UNIQUE(issuer, subject),
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS user_tokens (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		purpose TEXT NOT NULL CHECK(purpose IN ('password_reset', 'email_verification')),
+		token_hash TEXT NOT NULL UNIQUE,
+		email TEXT NOT NULL,
+		expires_at DATETIME NOT NULL,
+		used_at DATETIME,
+		created_at DATETIME NOT NULL,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS sessions (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		token_hash TEXT NOT NULL UNIQUE,
+		device TEXT NOT NULL DEFAULT '',
+		user_agent TEXT NOT NULL DEFAULT '',
+		ip_address TEXT NOT NULL DEFAULT '',
+		created_at DATETIME NOT NULL,
+		last_used_at DATETIME NOT NULL,
+		expires_at DATETIME NOT NULL,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS api_tokens (
+		id TEXT PRIMARY KEY,
+		user_id TEXT,
+		name TEXT NOT NULL,
+		token_prefix TEXT NOT NULL,
+		token_hash TEXT NOT NULL UNIQUE,
+		access TEXT NOT NULL CHECK(access IN ('read', 'write', 'admin')),
+		account_ids TEXT NOT NULL DEFAULT '[]',
+		category_ids TEXT NOT NULL DEFAULT '[]',
+		expires_at DATETIME,
+		last_used_at DATETIME,
+		created_at DATETIME NOT NULL,
+		daily_write_quota INTEGER NOT NULL DEFAULT 0,
+		write_count INTEGER NOT NULL DEFAULT 0,
+		write_count_date TEXT NOT NULL DEFAULT '',
+		disabled_at DATETIME
+	);
+
+	CREATE TABLE IF NOT EXISTS audit_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id TEXT,
+		token_id TEXT,
+		actor TEXT NOT NULL,
+		method TEXT NOT NULL,
+		route TEXT NOT NULL,
+		path TEXT NOT NULL,
+		status INTEGER NOT NULL,
+		ip_address TEXT NOT NULL DEFAULT '',
+		created_at DATETIME NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS goals (
+		id TEXT PRIMARY KEY,
+		category_id TEXT NOT NULL UNIQUE,
+		target_amount INTEGER NOT NULL,
+		target_date TEXT,
+		cadence TEXT NOT NULL CHECK(cadence IN ('once', 'monthly', 'yearly')),
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS payees (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS payee_rules (
+		id TEXT PRIMARY KEY,
+		pattern TEXT NOT NULL,
+		match_type TEXT NOT NULL CHECK(match_type IN ('contains', 'exact', 'regex')),
+		account_id TEXT,
+		min_amount INTEGER,
+		max_amount INTEGER,
+		payee_id TEXT,
+		category_id TEXT,
+		note TEXT,
+		priority INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+		FOREIGN KEY (payee_id) REFERENCES payees(id) ON DELETE CASCADE,
+		FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE SET NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS import_files (
+		id TEXT PRIMARY KEY,
+		account_id TEXT NOT NULL,
+		fingerprint TEXT NOT NULL,
+		format TEXT NOT NULL,
+		total_transactions INTEGER NOT NULL DEFAULT 0,
+		imported_transactions INTEGER NOT NULL DEFAULT 0,
+		skipped_duplicates INTEGER NOT NULL DEFAULT 0,
+		status TEXT NOT NULL DEFAULT 'committed' CHECK(status IN ('staged', 'committed', 'undone')),
+		ledger_balance INTEGER,
+		balance_change INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME NOT NULL,
+		committed_at DATETIME,
+		FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS import_file_rows (
+		id TEXT PRIMARY KEY,
+		import_id TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		fitid TEXT NOT NULL,
+		date DATETIME NOT NULL,
+		amount INTEGER NOT NULL,
+		description TEXT NOT NULL,
+		category_id TEXT,
+		payee_id TEXT,
+		note TEXT,
+		original_currency TEXT,
+		original_amount INTEGER,
+		status TEXT NOT NULL,
+		excluded BOOLEAN NOT NULL DEFAULT 0,
+		error TEXT NOT NULL DEFAULT '',
+		warning TEXT NOT NULL DEFAULT '',
+		transaction_id TEXT NOT NULL DEFAULT '',
+		transfer_account_id TEXT NOT NULL DEFAULT '',
+		transfer_transaction_id TEXT NOT NULL DEFAULT '',
+		placeholder_created BOOLEAN NOT NULL DEFAULT 0,
+		FOREIGN KEY (import_id) REFERENCES import_files(id) ON DELETE CASCADE,
+		FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE SET NULL,
+		FOREIGN KEY (payee_id) REFERENCES payees(id) ON DELETE SET NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS allocation_templates (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS allocation_template_items (
+		template_id TEXT NOT NULL,
+		category_id TEXT NOT NULL,
+		amount INTEGER NOT NULL,
+		PRIMARY KEY (template_id, category_id),
+		FOREIGN KEY (template_id) REFERENCES allocation_templates(id) ON DELETE CASCADE,
+		FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS transfer_hints (
+		id TEXT PRIMARY KEY,
+		account_id TEXT NOT NULL,
+		pattern TEXT NOT NULL,
+		target_account_id TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+		FOREIGN KEY (target_account_id) REFERENCES accounts(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS transfer_suggestions (
+		id TEXT PRIMARY KEY,
+		from_transaction_id TEXT NOT NULL,
+		to_transaction_id TEXT NOT NULL,
+		score INTEGER NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'accepted', 'rejected')),
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		FOREIGN KEY (from_transaction_id) REFERENCES transactions(id) ON DELETE CASCADE,
+		FOREIGN KEY (to_transaction_id) REFERENCES transactions(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS transfer_pairings (
+		id TEXT PRIMARY KEY,
+		from_account_id TEXT NOT NULL,
+		from_payee TEXT NOT NULL,
+		to_account_id TEXT NOT NULL,
+		to_payee TEXT NOT NULL,
+		accepted INTEGER NOT NULL DEFAULT 0,
+		rejected INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		UNIQUE(from_account_id, from_payee, to_account_id, to_payee),
+		FOREIGN KEY (from_account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+		FOREIGN KEY (to_account_id) REFERENCES accounts(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS bank_connections (
+		account_id TEXT PRIMARY KEY,
+		url TEXT NOT NULL,
+		org TEXT NOT NULL,
+		fid TEXT NOT NULL,
+		bank_id TEXT NOT NULL DEFAULT '',
+		account_type TEXT NOT NULL,
+		username TEXT NOT NULL,
+		account_number_last4 TEXT NOT NULL,
+		app_id TEXT NOT NULL DEFAULT '',
+		app_ver TEXT NOT NULL DEFAULT '',
+		ofx_version TEXT NOT NULL DEFAULT '',
+		credentials BLOB NOT NULL,
+		last_synced_at DATETIME,
+		last_error TEXT NOT NULL DEFAULT '',
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS widgets (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		category_id TEXT NOT NULL,
+		fields TEXT NOT NULL,
+		token_hash TEXT NOT NULL UNIQUE,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS bank_aggregations (
+		id TEXT PRIMARY KEY,
+		provider TEXT NOT NULL,
+		name TEXT NOT NULL,
+		credentials BLOB NOT NULL,
+		last_synced_at DATETIME,
+		last_error TEXT NOT NULL DEFAULT '',
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS bank_aggregation_accounts (
+		aggregation_id TEXT NOT NULL,
+		remote_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		institution TEXT NOT NULL DEFAULT '',
+		currency TEXT NOT NULL DEFAULT '',
+		balance INTEGER NOT NULL DEFAULT 0,
+		account_id TEXT UNIQUE,
+		last_error TEXT NOT NULL DEFAULT '',
+		updated_at DATETIME NOT NULL,
+		PRIMARY KEY (aggregation_id, remote_id),
+		FOREIGN KEY (aggregation_id) REFERENCES bank_aggregations(id) ON DELETE CASCADE,
+		FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE SET NULL
+	);
+
 	CREATE INDEX IF NOT EXISTS idx_categories_group_id ON categories(group_id);
+	CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
+	CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+	CREATE INDEX IF NOT EXISTS idx_recovery_codes_user_id ON recovery_codes(user_id);
+	CREATE INDEX IF NOT EXISTS idx_user_tokens_user_id ON user_tokens(user_id);
+	CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id);
 	CREATE INDEX IF NOT EXISTS idx_transactions_account_id ON transactions(account_id);
 	CREATE INDEX IF NOT EXISTS idx_transactions_category_id ON transactions(category_id);
 	CREATE INDEX IF NOT EXISTS idx_transactions_date ON transactions(date);
+	CREATE INDEX IF NOT EXISTS idx_transactions_category_date ON transactions(category_id, date);
 	CREATE INDEX IF NOT EXISTS idx_transactions_fitid ON transactions(fitid);
+	CREATE INDEX IF NOT EXISTS idx_import_files_fingerprint ON import_files(account_id, fingerprint);
+	CREATE INDEX IF NOT EXISTS idx_import_file_rows_import_id ON import_file_rows(import_id, position);
+	CREATE INDEX IF NOT EXISTS idx_transfer_hints_account_id ON transfer_hints(account_id);
+	CREATE INDEX IF NOT EXISTS idx_transfer_suggestions_from ON transfer_suggestions(from_transaction_id);
+	CREATE INDEX IF NOT EXISTS idx_transfer_suggestions_to ON transfer_suggestions(to_transaction_id);
 	CREATE INDEX IF NOT EXISTS idx_transactions_transfer_to_account_id ON transactions(transfer_to_account_id);
 	CREATE INDEX IF NOT EXISTS idx_allocations_period ON allocations(period);
 	CREATE INDEX IF NOT EXISTS idx_allocations_category_id ON allocations(category_id);
+	CREATE INDEX IF NOT EXISTS idx_category_moves_to_category ON category_moves(to_category_id);
+	CREATE INDEX IF NOT EXISTS idx_category_moves_transaction ON category_moves(transaction_id);
 	CREATE INDEX IF NOT EXISTS idx_categories_group_id ON categories(group_id);
+	CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
 
 	-- Insert default budget state if it doesn't exist
 	INSERT OR IGNORE INTO budget_state (id, ready_to_assign, updated_at)
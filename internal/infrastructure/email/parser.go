@@ -0,0 +1,106 @@
+package email
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// Parser extracts transactions from bank/card alert emails
+// It targets the common "You spent $X at MERCHANT" style of alert rather than
+// any one bank's format, so it is deliberately lenient.
+type Parser struct{}
+
+// ParsedAlert is a transaction extracted from an alert email
+type ParsedAlert struct {
+	Amount   int64 // Amount in the budget currency's minor units (positive=inflow, negative=outflow)
+	Merchant string
+	Date     time.Time
+}
+
+var (
+	amountPattern = regexp.MustCompile(`(?:\$|USD\s?)\s?(\d{1,3}(?:,\d{3})+|\d+)(?:\.(\d{2}))?`)
+	inflowPattern = regexp.MustCompile(`(?i)\b(deposit|deposited|refund|refunded|credited|received|incoming)\b`)
+	tagPattern    = regexp.MustCompile(`(?s)<[^>]*>`)
+	spacePattern  = regexp.MustCompile(`[ \t]+`)
+
+	// Tried in order; the first capture group is the merchant
+	merchantPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?im)^\s*(?:merchant|payee|description|where)\s*:\s*(.+?)\s*$`),
+		regexp.MustCompile(`(?i)\bat\s+([A-Za-z0-9][A-Za-z0-9&'*#./\- ]{1,60}?)(?:\s+on\s+|\s+for\s+|\s+was\s+|\s+has\s+|[.,;!\n]|$)`),
+		regexp.MustCompile(`(?i)\b(?:to|from)\s+([A-Za-z0-9][A-Za-z0-9&'*#./\- ]{1,60}?)(?:\s+on\s+|\s+for\s+|\s+was\s+|\s+has\s+|[.,;!\n]|$)`),
+	}
+
+	datePatterns = []struct {
+		re     *regexp.Regexp
+		layout string
+	}{
+		{regexp.MustCompile(`\b(\d{4}-\d{2}-\d{2})\b`), "2006-01-02"},
+		{regexp.MustCompile(`\b(\d{1,2}/\d{1,2}/\d{4})\b`), "1/2/2006"},
+		{regexp.MustCompile(`\b(\d{1,2}/\d{1,2}/\d{2})\b`), "1/2/06"},
+	}
+)
+
+// NewParser creates a new alert email parser
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// Parse extracts the amount, merchant and date from an alert email
+// received is used as the transaction date when the message doesn't contain one.
+func (p *Parser) Parse(subject, body string, received time.Time) (*ParsedAlert, error) {
+	text := subject + "\n" + normalizeBody(body)
+
+	match := amountPattern.FindStringSubmatch(text)
+	if match == nil {
+		return nil, fmt.Errorf("no amount found in message")
+	}
+	money, err := domain.ParseMoney(strings.ReplaceAll(match[1], ",", "")+"."+match[2], domain.BudgetCurrency().Code)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount %q", match[0])
+	}
+	amount := money.Amount
+	if amount == 0 {
+		return nil, fmt.Errorf("amount must be non-zero")
+	}
+	if !inflowPattern.MatchString(text) {
+		amount = -amount
+	}
+
+	alert := &ParsedAlert{
+		Amount: amount,
+		Date:   time.Date(received.Year(), received.Month(), received.Day(), 0, 0, 0, 0, time.UTC),
+	}
+
+	for _, re := range merchantPatterns {
+		if m := re.FindStringSubmatch(text); m != nil {
+			alert.Merchant = strings.TrimSpace(m[1])
+			break
+		}
+	}
+
+	for _, dp := range datePatterns {
+		if m := dp.re.FindStringSubmatch(text); m != nil {
+			if date, err := time.Parse(dp.layout, m[1]); err == nil {
+				alert.Date = date
+				break
+			}
+		}
+	}
+
+	return alert, nil
+}
+
+// normalizeBody strips HTML tags and entities and collapses whitespace
+func normalizeBody(body string) string {
+	if strings.Contains(body, "<") {
+		body = tagPattern.ReplaceAllString(body, "\n")
+	}
+	body = html.UnescapeString(body)
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	return spacePattern.ReplaceAllString(body, " ")
+}
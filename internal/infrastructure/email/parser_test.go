@@ -0,0 +1,84 @@
+package email
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	received := time.Date(2025, 3, 14, 18, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		subject      string
+		body         string
+		wantAmount   int64
+		wantMerchant string
+		wantDate     string
+		wantErr      bool
+	}{
+		{
+			name:         "card purchase alert",
+			subject:      "Transaction alert",
+			body:         "A charge of $42.17 was made at TRADER JOE'S #123 on 03/12/2025.",
+			wantAmount:   -4217,
+			wantMerchant: "TRADER JOE'S #123",
+			wantDate:     "2025-03-12",
+		},
+		{
+			name:         "labelled merchant with thousands separator",
+			subject:      "You made a purchase",
+			body:         "Amount: $1,250.00\nMerchant: Acme Furniture\nDate: 2025-03-10",
+			wantAmount:   -125000,
+			wantMerchant: "Acme Furniture",
+			wantDate:     "2025-03-10",
+		},
+		{
+			name:         "deposit is an inflow",
+			subject:      "Direct deposit received",
+			body:         "A deposit of $2,000.00 from ACME PAYROLL was credited to your account.",
+			wantAmount:   200000,
+			wantMerchant: "ACME PAYROLL",
+			wantDate:     "2025-03-14",
+		},
+		{
+			name:         "html body without cents",
+			subject:      "Purchase notification",
+			body:         "<p>You spent <b>$15</b> at <span>Corner Cafe</span>.</p>",
+			wantAmount:   -1500,
+			wantMerchant: "Corner Cafe",
+			wantDate:     "2025-03-14",
+		},
+		{
+			name:    "no amount",
+			subject: "Your statement is ready",
+			body:    "Log in to view your statement.",
+			wantErr: true,
+		},
+	}
+
+	p := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert, err := p.Parse(tt.subject, tt.body, received)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", alert)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if alert.Amount != tt.wantAmount {
+				t.Errorf("amount = %d, want %d", alert.Amount, tt.wantAmount)
+			}
+			if alert.Merchant != tt.wantMerchant {
+				t.Errorf("merchant = %q, want %q", alert.Merchant, tt.wantMerchant)
+			}
+			if got := alert.Date.Format("2006-01-02"); got != tt.wantDate {
+				t.Errorf("date = %s, want %s", got, tt.wantDate)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	budgetv1 "github.com/billybbuffum/budget/api/budget/v1"
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Authenticator resolves a bearer credential to a principal
+// The HTTP auth middleware is one, so both APIs accept the same tokens.
+type Authenticator interface {
+	Authenticate(ctx context.Context, raw string) (*application.Principal, error)
+}
+
+// Auth authenticates gRPC calls and applies the same access rules as the REST API
+// Each call is checked as the REST route it mirrors, so token access levels, account
+// and category scopes, write quotas and the audit log behave the same on both.
+type Auth struct {
+	authenticator Authenticator
+	tokenService  *application.APITokenService
+	auditService  *application.AuditService // nil unless the audit log is enabled
+	required      bool
+}
+
+// NewAuth creates the gRPC authentication interceptor
+// When required is false, calls without a credential are let through, as on HTTP.
+func NewAuth(authenticator Authenticator, tokenService *application.APITokenService, auditService *application.AuditService, required bool) *Auth {
+	return &Auth{
+		authenticator: authenticator,
+		tokenService:  tokenService,
+		auditService:  auditService,
+		required:      required,
+	}
+}
+
+// Intercept is a grpc.UnaryServerInterceptor
+func (a *Auth) Intercept(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	pattern, path, ok := restRoute(req)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "%s is not available", info.FullMethod)
+	}
+	method, _, _ := strings.Cut(pattern, " ")
+
+	var principal *application.Principal
+	raw := credential(ctx)
+	switch {
+	case raw == "" && a.required:
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	case raw != "":
+		var err error
+		principal, err = a.authenticator.Authenticate(ctx, raw)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		if err := authorize(req, pattern, principal); err != nil {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		if err := a.recordWrite(ctx, method, principal); err != nil {
+			return nil, err
+		}
+		ctx = application.WithPrincipal(ctx, principal)
+	}
+
+	resp, err := handler(ctx, req)
+	if a.auditService != nil && method != http.MethodGet {
+		a.auditService.Record(ctx, principal, method, pattern, path, httpStatus(err), peerAddress(ctx))
+	}
+	return resp, err
+}
+
+// recordWrite counts writes made with an API token against its daily quota
+func (a *Auth) recordWrite(ctx context.Context, method string, principal *application.Principal) error {
+	if principal.Token == nil || method == http.MethodGet {
+		return nil
+	}
+
+	err := a.tokenService.RecordWrite(ctx, principal.Token)
+	if errors.Is(err, domain.ErrWriteQuotaExceeded) {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+// restRoute returns the REST route pattern a call is equivalent to, and the path it would use
+func restRoute(req any) (pattern, path string, ok bool) {
+	switch req := req.(type) {
+	case *budgetv1.ListAccountsRequest:
+		return "GET /api/accounts", "/api/accounts", true
+	case *budgetv1.GetAccountRequest:
+		return "GET /api/accounts/{id}", "/api/accounts/" + req.Id, true
+	case *budgetv1.CreateAccountRequest:
+		return "POST /api/accounts", "/api/accounts", true
+	case *budgetv1.DeleteAccountRequest:
+		return "DELETE /api/accounts/{id}", "/api/accounts/" + req.Id, true
+	case *budgetv1.ListTransactionsRequest:
+		if req.AccountId != "" {
+			return "GET /api/accounts/{id}/transactions", "/api/accounts/" + req.AccountId + "/transactions", true
+		}
+		return "GET /api/transactions", "/api/transactions", true
+	case *budgetv1.GetTransactionRequest:
+		return "GET /api/transactions/{id}", "/api/transactions/" + req.Id, true
+	case *budgetv1.CreateTransactionRequest:
+		return "POST /api/transactions", "/api/transactions", true
+	case *budgetv1.CreateTransferRequest:
+		return "POST /api/transactions/transfer", "/api/transactions/transfer", true
+	case *budgetv1.DeleteTransactionRequest:
+		return "DELETE /api/transactions/{id}", "/api/transactions/" + req.Id, true
+	case *budgetv1.ListAllocationsRequest:
+		return "GET /api/allocations", "/api/allocations", true
+	case *budgetv1.CreateAllocationRequest:
+		return "POST /api/allocations", "/api/allocations", true
+	case *budgetv1.GetAllocationSummaryRequest:
+		return "GET /api/allocations/summary", "/api/allocations/summary", true
+	}
+	return "", "", false
+}
+
+// authorize checks a call against the principal's access, as the REST route it mirrors
+// See application.Principal.Can.
+func authorize(req any, pattern string, principal *application.Principal) error {
+	op := application.Operation{Pattern: pattern}
+	switch req := req.(type) {
+	case *budgetv1.GetAccountRequest:
+		op.AccountID = req.Id
+	case *budgetv1.ListTransactionsRequest:
+		op.AccountID = req.AccountId
+	case *budgetv1.CreateTransactionRequest:
+		op.AccountID, op.CategoryID = req.AccountId, req.CategoryId
+	}
+	return principal.Can(op)
+}
+
+// credential returns the bearer token from the call's authorization metadata
+func credential(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		if raw, ok := strings.CutPrefix(value, "Bearer "); ok && raw != "" {
+			return raw
+		}
+	}
+	return ""
+}
+
+// peerAddress returns the caller's IP address
+func peerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// httpStatus is the status the REST route would have answered with, for the audit log
+func httpStatus(err error) int {
+	switch status.Code(err) {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
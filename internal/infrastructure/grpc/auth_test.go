@@ -0,0 +1,87 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	budgetv1 "github.com/billybbuffum/budget/api/budget/v1"
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type fakeAuthenticator map[string]*application.Principal
+
+func (f fakeAuthenticator) Authenticate(ctx context.Context, raw string) (*application.Principal, error) {
+	if principal, ok := f[raw]; ok {
+		return principal, nil
+	}
+	return nil, errors.New("invalid token")
+}
+
+func TestAuthIntercept(t *testing.T) {
+	auth := NewAuth(fakeAuthenticator{
+		"member": {User: &domain.User{ID: "u1", Role: domain.UserRoleMember}},
+		"reader": {User: &domain.User{ID: "u2", Role: domain.UserRoleMember}, Token: &domain.APIToken{Access: domain.TokenAccessRead}},
+		"scoped": {Token: &domain.APIToken{Access: domain.TokenAccessRead, AccountIDs: []string{"acct-1"}}},
+	}, nil, nil, true)
+
+	var handled *application.Principal
+	handler := func(ctx context.Context, req any) (any, error) {
+		handled, _ = application.PrincipalFromContext(ctx)
+		return nil, nil
+	}
+	call := func(credential string, req any) error {
+		handled = nil
+		ctx := context.Background()
+		if credential != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+credential))
+		}
+		_, err := auth.Intercept(ctx, req, &grpc.UnaryServerInfo{FullMethod: "/test"}, handler)
+		return err
+	}
+
+	tests := []struct {
+		name       string
+		credential string
+		req        any
+		want       codes.Code
+	}{
+		{"credential required", "", &budgetv1.ListAccountsRequest{}, codes.Unauthenticated},
+		{"unknown token", "nope", &budgetv1.ListAccountsRequest{}, codes.Unauthenticated},
+		{"member writes", "member", &budgetv1.CreateAllocationRequest{}, codes.OK},
+		{"read token can't write", "reader", &budgetv1.DeleteTransactionRequest{Id: "t1"}, codes.PermissionDenied},
+		{"scoped token reads its account", "scoped", &budgetv1.GetAccountRequest{Id: "acct-1"}, codes.OK},
+		{"scoped token lists its account's transactions", "scoped", &budgetv1.ListTransactionsRequest{AccountId: "acct-1"}, codes.OK},
+		{"scoped token reads another account", "scoped", &budgetv1.GetAccountRequest{Id: "acct-2"}, codes.PermissionDenied},
+		{"scoped token lists every transaction", "scoped", &budgetv1.ListTransactionsRequest{}, codes.PermissionDenied},
+		{"scoped token uses an unchecked method", "scoped", &budgetv1.ListAccountsRequest{}, codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := call(tt.credential, tt.req)
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("expected %s, got %s (%v)", tt.want, got, err)
+			}
+			if tt.want == codes.OK && handled == nil {
+				t.Error("expected the handler to see the principal")
+			}
+		})
+	}
+}
+
+func TestAuthInterceptOptional(t *testing.T) {
+	auth := NewAuth(fakeAuthenticator{}, nil, nil, false)
+	called := false
+	_, err := auth.Intercept(context.Background(), &budgetv1.CreateAccountRequest{}, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+		called = true
+		return nil, nil
+	})
+	if err != nil || !called {
+		t.Fatalf("expected calls without a credential to go through, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	budgetv1 "github.com/billybbuffum/budget/api/budget/v1"
+	"github.com/billybbuffum/budget/internal/application"
+	"google.golang.org/grpc"
+)
+
+// Server serves the gRPC API on its own listener, next to the HTTP server
+type Server struct {
+	addr       string
+	grpcServer *grpc.Server
+}
+
+// NewServer creates a gRPC server for the account, transaction and allocation services
+// Calls are authenticated, authorized and audited like the REST routes they mirror.
+func NewServer(addr string, auth *Auth, accountService *application.AccountService, transactionService *application.TransactionService, allocationService *application.AllocationService) *Server {
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(auth.Intercept))
+	budgetv1.RegisterAccountServiceServer(grpcServer, &accountServer{accountService: accountService})
+	budgetv1.RegisterTransactionServiceServer(grpcServer, &transactionServer{transactionService: transactionService})
+	budgetv1.RegisterAllocationServiceServer(grpcServer, &allocationServer{allocationService: allocationService})
+
+	return &Server{addr: addr, grpcServer: grpcServer}
+}
+
+// Start starts the gRPC server
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	log.Printf("Starting gRPC server on %s", s.addr)
+	if err := s.grpcServer.Serve(listener); err != nil {
+		return fmt.Errorf("failed to start gRPC server: %w", err)
+	}
+	return nil
+}
+
+// Shutdown waits for in-flight calls to finish, stopping them outright once ctx is done
+func (s *Server) Shutdown(ctx context.Context) error {
+	log.Println("Shutting down gRPC server...")
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,234 @@
+package grpc
+
+import (
+	"context"
+
+	budgetv1 "github.com/billybbuffum/budget/api/budget/v1"
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Errors map to the codes matching the statuses the REST handlers answer with
+
+type accountServer struct {
+	budgetv1.UnimplementedAccountServiceServer
+	accountService *application.AccountService
+}
+
+func (s *accountServer) ListAccounts(ctx context.Context, req *budgetv1.ListAccountsRequest) (*budgetv1.ListAccountsResponse, error) {
+	accounts, err := s.accountService.ListAccounts(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &budgetv1.ListAccountsResponse{Accounts: make([]*budgetv1.Account, 0, len(accounts))}
+	for _, account := range accounts {
+		resp.Accounts = append(resp.Accounts, accountMessage(account))
+	}
+	return resp, nil
+}
+
+func (s *accountServer) GetAccount(ctx context.Context, req *budgetv1.GetAccountRequest) (*budgetv1.Account, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "account id is required")
+	}
+	account, err := s.accountService.GetAccount(ctx, req.Id)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return accountMessage(account), nil
+}
+
+func (s *accountServer) CreateAccount(ctx context.Context, req *budgetv1.CreateAccountRequest) (*budgetv1.Account, error) {
+	account, err := s.accountService.CreateAccount(ctx, req.Name, req.Balance, domain.AccountType(req.Type), nil)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return accountMessage(account), nil
+}
+
+func (s *accountServer) DeleteAccount(ctx context.Context, req *budgetv1.DeleteAccountRequest) (*budgetv1.DeleteAccountResponse, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "account id is required")
+	}
+	if err := s.accountService.DeleteAccount(ctx, req.Id); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &budgetv1.DeleteAccountResponse{}, nil
+}
+
+type transactionServer struct {
+	budgetv1.UnimplementedTransactionServiceServer
+	transactionService *application.TransactionService
+}
+
+func (s *transactionServer) ListTransactions(ctx context.Context, req *budgetv1.ListTransactionsRequest) (*budgetv1.ListTransactionsResponse, error) {
+	var transactions []*domain.Transaction
+	var err error
+	if req.AccountId != "" {
+		transactions, err = s.transactionService.ListTransactionsByAccount(ctx, req.AccountId)
+	} else {
+		transactions, err = s.transactionService.ListTransactions(ctx)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &budgetv1.ListTransactionsResponse{Transactions: make([]*budgetv1.Transaction, 0, len(transactions))}
+	for _, transaction := range transactions {
+		resp.Transactions = append(resp.Transactions, transactionMessage(transaction))
+	}
+	return resp, nil
+}
+
+func (s *transactionServer) GetTransaction(ctx context.Context, req *budgetv1.GetTransactionRequest) (*budgetv1.Transaction, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "transaction id is required")
+	}
+	transaction, err := s.transactionService.GetTransaction(ctx, req.Id)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return transactionMessage(transaction), nil
+}
+
+func (s *transactionServer) CreateTransaction(ctx context.Context, req *budgetv1.CreateTransactionRequest) (*budgetv1.Transaction, error) {
+	if req.Date == nil {
+		return nil, status.Error(codes.InvalidArgument, "date is required")
+	}
+	transaction, err := s.transactionService.CreateTransaction(ctx, req.AccountId, req.CategoryId, req.Amount, req.Description, req.Date.AsTime())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return transactionMessage(transaction), nil
+}
+
+func (s *transactionServer) CreateTransfer(ctx context.Context, req *budgetv1.CreateTransferRequest) (*budgetv1.Transaction, error) {
+	if req.Date == nil {
+		return nil, status.Error(codes.InvalidArgument, "date is required")
+	}
+	transaction, err := s.transactionService.CreateTransfer(ctx, req.FromAccountId, req.ToAccountId, req.Amount, req.Description, req.Date.AsTime())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return transactionMessage(transaction), nil
+}
+
+func (s *transactionServer) DeleteTransaction(ctx context.Context, req *budgetv1.DeleteTransactionRequest) (*budgetv1.DeleteTransactionResponse, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "transaction id is required")
+	}
+	if err := s.transactionService.DeleteTransaction(ctx, req.Id); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &budgetv1.DeleteTransactionResponse{}, nil
+}
+
+type allocationServer struct {
+	budgetv1.UnimplementedAllocationServiceServer
+	allocationService *application.AllocationService
+}
+
+func (s *allocationServer) ListAllocations(ctx context.Context, req *budgetv1.ListAllocationsRequest) (*budgetv1.ListAllocationsResponse, error) {
+	var allocations []*domain.Allocation
+	var err error
+	if req.Period != "" {
+		allocations, err = s.allocationService.ListAllocationsByPeriod(ctx, req.Period)
+	} else {
+		allocations, err = s.allocationService.ListAllocations(ctx)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &budgetv1.ListAllocationsResponse{Allocations: make([]*budgetv1.Allocation, 0, len(allocations))}
+	for _, allocation := range allocations {
+		resp.Allocations = append(resp.Allocations, allocationMessage(allocation))
+	}
+	return resp, nil
+}
+
+func (s *allocationServer) CreateAllocation(ctx context.Context, req *budgetv1.CreateAllocationRequest) (*budgetv1.Allocation, error) {
+	allocation, err := s.allocationService.CreateAllocation(ctx, req.CategoryId, req.Amount, req.Period, req.Notes)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return allocationMessage(allocation), nil
+}
+
+func (s *allocationServer) GetAllocationSummary(ctx context.Context, req *budgetv1.GetAllocationSummaryRequest) (*budgetv1.GetAllocationSummaryResponse, error) {
+	if req.Period == "" {
+		return nil, status.Error(codes.InvalidArgument, "period is required")
+	}
+	readyToAssign, err := s.allocationService.CalculateReadyToAssignForPeriod(ctx, req.Period)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	summaries, err := s.allocationService.GetAllocationSummary(ctx, req.Period)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &budgetv1.GetAllocationSummaryResponse{
+		ReadyToAssign: readyToAssign,
+		Categories:    make([]*budgetv1.CategorySummary, 0, len(summaries)),
+	}
+	for _, summary := range summaries {
+		category := &budgetv1.CategorySummary{
+			CategoryId:   summary.Category.ID,
+			CategoryName: summary.Category.Name,
+			Activity:     summary.Activity,
+			Available:    summary.Available,
+			Underfunded:  summary.Underfunded,
+			Status:       string(summary.Status),
+		}
+		if summary.Allocation != nil {
+			category.Allocated = summary.Allocation.Amount
+		}
+		resp.Categories = append(resp.Categories, category)
+	}
+	return resp, nil
+}
+
+func accountMessage(account *domain.Account) *budgetv1.Account {
+	return &budgetv1.Account{
+		Id:             account.ID,
+		Name:           account.Name,
+		Balance:        account.Balance,
+		Type:           string(account.Type),
+		RewardsBalance: account.RewardsBalance,
+		CreatedAt:      timestamppb.New(account.CreatedAt),
+		UpdatedAt:      timestamppb.New(account.UpdatedAt),
+	}
+}
+
+func transactionMessage(transaction *domain.Transaction) *budgetv1.Transaction {
+	return &budgetv1.Transaction{
+		Id:                  transaction.ID,
+		Type:                string(transaction.Type),
+		AccountId:           transaction.AccountID,
+		TransferToAccountId: transaction.TransferToAccountID,
+		CategoryId:          transaction.CategoryID,
+		Amount:              transaction.Amount,
+		Description:         transaction.Description,
+		Date:                timestamppb.New(transaction.Date),
+		PayeeId:             transaction.PayeeID,
+		Note:                transaction.Note,
+		CreatedAt:           timestamppb.New(transaction.CreatedAt),
+		UpdatedAt:           timestamppb.New(transaction.UpdatedAt),
+	}
+}
+
+func allocationMessage(allocation *domain.Allocation) *budgetv1.Allocation {
+	return &budgetv1.Allocation{
+		Id:         allocation.ID,
+		CategoryId: allocation.CategoryID,
+		Period:     allocation.Period,
+		Amount:     allocation.Amount,
+		Notes:      allocation.Notes,
+		CreatedAt:  timestamppb.New(allocation.CreatedAt),
+		UpdatedAt:  timestamppb.New(allocation.UpdatedAt),
+	}
+}
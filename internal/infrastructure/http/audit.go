@@ -0,0 +1,57 @@
+package http
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/billybbuffum/budget/internal/application"
+)
+
+// EnableAudit records every API request that changes data in the audit log
+func (m *AuthMiddleware) EnableAudit(service *application.AuditService) {
+	m.auditService = service
+}
+
+// serve passes an API request to the router, recording it in the audit log when it
+// could have changed something. principal is nil for requests without a credential.
+func (m *AuthMiddleware) serve(w http.ResponseWriter, r *http.Request, pattern string, principal *application.Principal) {
+	if principal != nil {
+		r = r.WithContext(application.WithPrincipal(r.Context(), principal))
+	}
+
+	if m.auditService == nil || r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+		m.mux.ServeHTTP(w, r)
+		return
+	}
+
+	recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	m.mux.ServeHTTP(recorder, r)
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	m.auditService.Record(r.Context(), principal, r.Method, pattern, r.URL.Path, recorder.status, host)
+}
+
+// statusRecorder remembers the status code a handler responded with
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,298 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/billybbuffum/budget/internal/infrastructure/http/handlers"
+)
+
+// publicAPIRoutes either need no credential or authenticate themselves
+// (emailed token, SSO state, webhook secret, bot chat or widget token). Creating the
+// admin user during setup only works while no users exist.
+var publicAPIRoutes = map[string]bool{
+	"GET /api/setup":                        true,
+	"POST /api/setup/admin":                 true,
+	"POST /api/auth/login":                  true,
+	"POST /api/auth/password-reset":         true,
+	"POST /api/auth/password-reset/confirm": true,
+	"POST /api/auth/verify-email/confirm":   true,
+	"GET /api/auth/oidc":                    true,
+	"GET /api/auth/oidc/login":              true,
+	"GET /api/auth/oidc/callback":           true,
+	"POST /api/auth/oidc/two-factor":        true,
+	"POST /api/integrations/email":          true,
+	"POST /api/bot/message":                 true,
+	"GET /api/bot/summary":                  true,
+	"GET /api/public/widgets/{token}":       true,
+	"GET /api/openapi.json":                 true,
+}
+
+// AuthMiddleware authenticates /api requests with sessions or API tokens and enforces token scopes
+// When required is false, requests without a credential are let through unchanged so
+// existing single-user setups keep working; requests with a credential are always checked.
+type AuthMiddleware struct {
+	mux          *http.ServeMux
+	authService  *application.AuthService
+	tokenService *application.APITokenService
+	required     bool
+	adminToken   string // Static admin token from configuration; empty disables it
+
+	proxyService *application.ProxyAuthService // nil unless reverse-proxy auth is enabled
+	proxyConfig  ProxyAuthConfig
+
+	auditService *application.AuditService // nil unless the audit log is enabled
+}
+
+// ProxyAuthConfig configures trusted reverse-proxy authentication
+type ProxyAuthConfig struct {
+	UserHeader     string // e.g. Remote-User
+	EmailHeader    string // Optional, e.g. Remote-Email
+	NameHeader     string // Optional, e.g. Remote-Name
+	GroupsHeader   string // Optional comma-separated groups, e.g. Remote-Groups
+	TrustedProxies []netip.Prefix
+}
+
+// NewAuthMiddleware wraps the router with session and API token authentication
+func NewAuthMiddleware(mux *http.ServeMux, authService *application.AuthService, tokenService *application.APITokenService, required bool, adminToken string) *AuthMiddleware {
+	return &AuthMiddleware{
+		mux:          mux,
+		authService:  authService,
+		tokenService: tokenService,
+		required:     required,
+		adminToken:   adminToken,
+	}
+}
+
+// EnableProxyAuth accepts users authenticated by an upstream reverse proxy
+// The user header is only honoured on connections from a trusted proxy address;
+// anywhere else it is ignored, so clients can't sign themselves in by setting it.
+func (m *AuthMiddleware) EnableProxyAuth(service *application.ProxyAuthService, cfg ProxyAuthConfig) {
+	m.proxyService = service
+	m.proxyConfig = cfg
+}
+
+// ParseTrustedProxies parses CIDRs (or single IP addresses) of trusted reverse proxies
+func ParseTrustedProxies(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		if addr, err := netip.ParseAddr(value); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", value)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+func (m *AuthMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, "/api/") {
+		m.mux.ServeHTTP(w, r)
+		return
+	}
+
+	_, pattern := m.mux.Handler(r)
+	if publicAPIRoutes[pattern] {
+		m.serve(w, r, pattern, nil)
+		return
+	}
+
+	raw := credential(r)
+	if raw == "" && m.proxyService != nil && m.fromTrustedProxy(r) && r.Header.Get(m.proxyConfig.UserHeader) != "" {
+		principal, err := m.proxyPrincipal(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if err := authorize(r, pattern, principal); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		m.serve(w, r, pattern, principal)
+		return
+	}
+	if raw == "" {
+		if m.required {
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+			return
+		}
+		m.serve(w, r, pattern, nil)
+		return
+	}
+
+	principal, err := m.Authenticate(r.Context(), raw)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if err := authorize(r, pattern, principal); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if !m.recordWrite(w, r, principal) {
+		return
+	}
+
+	m.serve(w, r, pattern, principal)
+}
+
+// recordWrite counts write requests made with an API token against its daily quota
+// Over the quota it answers 429 until the next UTC day and returns false.
+func (m *AuthMiddleware) recordWrite(w http.ResponseWriter, r *http.Request, principal *application.Principal) bool {
+	if principal.Token == nil || r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return true
+	}
+
+	err := m.tokenService.RecordWrite(r.Context(), principal.Token)
+	if errors.Is(err, domain.ErrWriteQuotaExceeded) {
+		now := time.Now().UTC()
+		tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+		w.Header().Set("Retry-After", strconv.Itoa(int(tomorrow.Sub(now).Seconds())+1))
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return false
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
+// credential returns the bearer token, falling back to the session cookie
+func credential(r *http.Request) string {
+	if raw, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && raw != "" {
+		return raw
+	}
+	if cookie, err := r.Cookie(handlers.SessionCookieName); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+// Authenticate resolves a credential to a principal
+// The configured admin token is accepted as an unscoped admin API token. The gRPC
+// server checks its credentials here too.
+func (m *AuthMiddleware) Authenticate(ctx context.Context, raw string) (*application.Principal, error) {
+	if m.adminToken != "" && subtle.ConstantTimeCompare([]byte(raw), []byte(m.adminToken)) == 1 {
+		return &application.Principal{
+			Token: &domain.APIToken{Name: "admin (configured)", Access: domain.TokenAccessAdmin},
+		}, nil
+	}
+
+	if application.IsSessionToken(raw) {
+		session, user, err := m.authService.AuthenticateSession(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		return &application.Principal{User: user, Session: session}, nil
+	}
+
+	token, err := m.tokenService.Authenticate(ctx, raw)
+	if err != nil {
+		return nil, err
+	}
+	return &application.Principal{Token: token}, nil
+}
+
+// fromTrustedProxy reports whether the connection comes from a trusted proxy address
+func (m *AuthMiddleware) fromTrustedProxy(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, prefix := range m.proxyConfig.TrustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// proxyPrincipal resolves the user named in the proxy's headers
+func (m *AuthMiddleware) proxyPrincipal(r *http.Request) (*application.Principal, error) {
+	identity := &application.ProxyIdentity{Username: r.Header.Get(m.proxyConfig.UserHeader)}
+	if m.proxyConfig.EmailHeader != "" {
+		identity.Email = r.Header.Get(m.proxyConfig.EmailHeader)
+	}
+	if m.proxyConfig.NameHeader != "" {
+		identity.Name = r.Header.Get(m.proxyConfig.NameHeader)
+	}
+	if m.proxyConfig.GroupsHeader != "" {
+		for _, group := range strings.Split(r.Header.Get(m.proxyConfig.GroupsHeader), ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				identity.Groups = append(identity.Groups, group)
+			}
+		}
+	}
+
+	user, err := m.proxyService.Authenticate(r.Context(), identity)
+	if err != nil {
+		return nil, err
+	}
+	return &application.Principal{User: user}, nil
+}
+
+// authorize checks the request against the principal's access, see application.Principal.Can
+// The account and category a scoped token is checked against come from the path, or from
+// the body of a new transaction.
+func authorize(r *http.Request, pattern string, principal *application.Principal) error {
+	op := application.Operation{Pattern: pattern}
+	switch pattern {
+	case "GET /api/accounts/{id}", "GET /api/accounts/{id}/transactions":
+		op.AccountID = pathSegment(r, 1)
+	case "GET /api/categories/{id}":
+		categoryID := pathSegment(r, 1)
+		op.CategoryID = &categoryID
+	case "POST /api/transactions":
+		if principal.Token == nil || !principal.Token.IsScoped() {
+			break
+		}
+		var body struct {
+			AccountID  string  `json:"account_id"`
+			CategoryID *string `json:"category_id"`
+		}
+		raw, err := io.ReadAll(r.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read request body")
+		}
+		r.Body = io.NopCloser(bytes.NewReader(raw))
+		if err := json.Unmarshal(raw, &body); err != nil {
+			return fmt.Errorf("invalid request body")
+		}
+		op.AccountID, op.CategoryID = body.AccountID, body.CategoryID
+	}
+	return principal.Can(op)
+}
+
+// pathSegment returns the n-th path segment after /api/ (0 = "accounts" in /api/accounts/{id})
+func pathSegment(r *http.Request, n int) string {
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/"), "/")
+	if n >= len(segments) {
+		return ""
+	}
+	return segments[n]
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestAuthorize(t *testing.T) {
+	token := func(access domain.TokenAccess, accountIDs, categoryIDs []string) *application.Principal {
+		return &application.Principal{Token: &domain.APIToken{Access: access, AccountIDs: accountIDs, CategoryIDs: categoryIDs}}
+	}
+	user := func(role domain.UserRole) *application.Principal {
+		return &application.Principal{User: &domain.User{ID: "u1", Role: role}}
+	}
+	unenrolled := &application.Principal{User: &domain.User{ID: "u2", Role: domain.UserRoleMember, TOTPRequired: true}}
+
+	tests := []struct {
+		name      string
+		principal *application.Principal
+		method    string
+		pattern   string
+		path      string
+		body      string
+		allowed   bool
+	}{
+		{"read token reads", token(domain.TokenAccessRead, nil, nil), "GET", "GET /api/accounts", "/api/accounts", "", true},
+		{"read token can't write", token(domain.TokenAccessRead, nil, nil), "POST", "POST /api/transactions", "/api/transactions", `{}`, false},
+		{"write token writes", token(domain.TokenAccessWrite, nil, nil), "POST", "POST /api/transactions", "/api/transactions", `{}`, true},
+		{"write token can't manage tokens", token(domain.TokenAccessWrite, nil, nil), "GET", "GET /api/tokens", "/api/tokens", "", false},
+		{"write token can't read admin routes", token(domain.TokenAccessWrite, nil, nil), "GET", "GET /api/admin/audit", "/api/admin/audit", "", false},
+		{"admin token manages tokens", token(domain.TokenAccessAdmin, nil, nil), "GET", "GET /api/tokens", "/api/tokens", "", true},
+
+		{"member reads", user(domain.UserRoleMember), "GET", "GET /api/accounts", "/api/accounts", "", true},
+		{"member writes", user(domain.UserRoleMember), "DELETE", "DELETE /api/accounts/{id}", "/api/accounts/a1", "", true},
+		{"member can't list users", user(domain.UserRoleMember), "GET", "GET /api/users", "/api/users", "", false},
+		{"member can't use admin routes", user(domain.UserRoleMember), "GET", "GET /api/admin/flags", "/api/admin/flags", "", false},
+		{"admin lists users", user(domain.UserRoleAdmin), "GET", "GET /api/users", "/api/users", "", true},
+		{"admin uses admin routes", user(domain.UserRoleAdmin), "GET", "GET /api/admin/flags", "/api/admin/flags", "", true},
+		{"unenrolled user can't read budget", unenrolled, "GET", "GET /api/accounts", "/api/accounts", "", false},
+		{"unenrolled user sets up two-factor", unenrolled, "POST", "POST /api/auth/two-factor/setup", "/api/auth/two-factor/setup", "", true},
+
+		{"scoped token reads its account", token(domain.TokenAccessRead, []string{"a1"}, nil), "GET", "GET /api/accounts/{id}", "/api/accounts/a1", "", true},
+		{"scoped token reads its account's transactions", token(domain.TokenAccessRead, []string{"a1"}, nil), "GET", "GET /api/accounts/{id}/transactions", "/api/accounts/a1/transactions", "", true},
+		{"scoped token can't read other accounts", token(domain.TokenAccessRead, []string{"a1"}, nil), "GET", "GET /api/accounts/{id}", "/api/accounts/a2", "", false},
+		{"scoped token reads its category", token(domain.TokenAccessRead, nil, []string{"c1"}), "GET", "GET /api/categories/{id}", "/api/categories/c1", "", true},
+		{"scoped token can't read other categories", token(domain.TokenAccessRead, nil, []string{"c1"}), "GET", "GET /api/categories/{id}", "/api/categories/c2", "", false},
+		{"scoped token creates in its account", token(domain.TokenAccessWrite, []string{"a1"}, nil), "POST", "POST /api/transactions", "/api/transactions", `{"account_id":"a1"}`, true},
+		{"scoped token can't create in other accounts", token(domain.TokenAccessWrite, []string{"a1"}, nil), "POST", "POST /api/transactions", "/api/transactions", `{"account_id":"a2"}`, false},
+		{"category scoped token creates in its category", token(domain.TokenAccessWrite, nil, []string{"c1"}), "POST", "POST /api/transactions", "/api/transactions", `{"account_id":"a1","category_id":"c1"}`, true},
+		{"category scoped token must name a category", token(domain.TokenAccessWrite, nil, []string{"c1"}), "POST", "POST /api/transactions", "/api/transactions", `{"account_id":"a1"}`, false},
+		{"scoped token can't read lists", token(domain.TokenAccessRead, []string{"a1"}, nil), "GET", "GET /api/transactions", "/api/transactions", "", false},
+		{"scoped token can't use other endpoints", token(domain.TokenAccessAdmin, []string{"a1"}, nil), "DELETE", "DELETE /api/accounts/{id}", "/api/accounts/a1", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			err := authorize(r, tt.pattern, tt.principal)
+			if tt.allowed && err != nil {
+				t.Errorf("authorize() unexpected error = %v", err)
+			}
+			if !tt.allowed && err == nil {
+				t.Errorf("authorize() allowed %s %s", tt.method, tt.path)
+			}
+		})
+	}
+}
+
+func TestPublicAPIRoutesNeedNoCredential(t *testing.T) {
+	mux := http.NewServeMux()
+	for pattern := range publicAPIRoutes {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	}
+	mux.HandleFunc("GET /api/accounts", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	middleware := NewAuthMiddleware(mux, nil, nil, true, "")
+
+	for pattern := range publicAPIRoutes {
+		method, path, _ := strings.Cut(pattern, " ")
+		w := httptest.NewRecorder()
+		middleware.ServeHTTP(w, httptest.NewRequest(method, strings.ReplaceAll(path, "{token}", "abc"), nil))
+		if w.Code != http.StatusNoContent {
+			t.Errorf("%s = %d, want it served without a credential", pattern, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	middleware.ServeHTTP(w, httptest.NewRequest("GET", "/api/accounts", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("GET /api/accounts = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestFromTrustedProxy(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.0/24", "192.168.1.5", "fd00::/64"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	middleware := NewAuthMiddleware(http.NewServeMux(), nil, nil, true, "")
+	middleware.EnableProxyAuth(new(application.ProxyAuthService), ProxyAuthConfig{UserHeader: "Remote-User", TrustedProxies: trusted})
+
+	tests := []struct {
+		remoteAddr string
+		trusted    bool
+	}{
+		{"10.0.0.7:51234", true},
+		{"10.0.1.7:51234", false},
+		{"192.168.1.5:443", true},
+		{"192.168.1.6:443", false},
+		{"[::ffff:10.0.0.7]:51234", true}, // IPv4 over an IPv6 socket
+		{"[fd00::1]:51234", true},
+		{"[fd00:0:0:1::1]:51234", false},
+		{"10.0.0.7", true}, // No port
+		{"proxy.local:80", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/api/accounts", nil)
+		r.RemoteAddr = tt.remoteAddr
+		if got := middleware.fromTrustedProxy(r); got != tt.trusted {
+			t.Errorf("fromTrustedProxy(%s) = %v, want %v", tt.remoteAddr, got, tt.trusted)
+		}
+	}
+
+	// Outside the trusted proxies the user header is ignored, leaving the request unauthenticated
+	r := httptest.NewRequest("GET", "/api/accounts", nil)
+	r.RemoteAddr = "203.0.113.9:51234"
+	r.Header.Set("Remote-User", "admin")
+	w := httptest.NewRecorder()
+	middleware.ServeHTTP(w, r)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("proxy header from an untrusted address = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
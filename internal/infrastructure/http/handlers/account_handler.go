@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/billybbuffum/budget/internal/application"
@@ -17,15 +18,34 @@ func NewAccountHandler(accountService *application.AccountService) *AccountHandl
 }
 
 type CreateAccountRequest struct {
-	Name    string `json:"name"`
-	Balance int64  `json:"balance"` // in cents
-	Type    string `json:"type"`    // checking, savings, cash
+	Name    string                           `json:"name"`
+	Balance int64                            `json:"balance"` // in cents
+	Type    string                           `json:"type"`    // checking, savings, cash, credit, loan, tracking
+	Details *application.AccountDetailsInput `json:"details,omitempty"`
 }
 
 type UpdateAccountRequest struct {
-	Name    string `json:"name"`
-	Balance int64  `json:"balance"`
-	Type    string `json:"type"`
+	Name    string                           `json:"name"`
+	Balance int64                            `json:"balance"`
+	Type    string                           `json:"type"`
+	Details *application.AccountDetailsInput `json:"details,omitempty"` // Replaces the account's details when present
+}
+
+type UpdateStartingBalanceRequest struct {
+	Amount int64 `json:"amount"` // in cents; negative for a credit card's opening debt
+}
+
+type SetHiddenFromDashboardRequest struct {
+	Hidden bool `json:"hidden"`
+}
+
+type ConvertAccountTypeRequest struct {
+	Type              string `json:"type"`
+	MoveAllocationsTo string `json:"move_allocations_to,omitempty"` // Category for a credit card payment category's allocations; omitted deletes them
+}
+
+type AdjustRewardsRequest struct {
+	Amount int64 `json:"amount"` // in cents; positive for earned rewards, negative to remove them
 }
 
 func (h *AccountHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
@@ -35,7 +55,7 @@ func (h *AccountHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	account, err := h.accountService.CreateAccount(r.Context(), req.Name, req.Balance, domain.AccountType(req.Type))
+	account, err := h.accountService.CreateAccount(r.Context(), req.Name, req.Balance, domain.AccountType(req.Type), req.Details)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -87,7 +107,7 @@ func (h *AccountHandler) UpdateAccount(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	account, err := h.accountService.UpdateAccount(r.Context(), id, req.Name, req.Balance, domain.AccountType(req.Type))
+	account, err := h.accountService.UpdateAccount(r.Context(), id, req.Name, req.Balance, domain.AccountType(req.Type), req.Details)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -126,3 +146,90 @@ func (h *AccountHandler) GetSummary(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(summary)
 }
+
+// UpdateStartingBalance handles PUT /api/accounts/{id}/starting-balance
+// Corrects the balance the account was opened with, rather than deleting and recreating it
+func (h *AccountHandler) UpdateStartingBalance(w http.ResponseWriter, r *http.Request) {
+	var req UpdateStartingBalanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	change, err := h.accountService.UpdateStartingBalance(r.Context(), r.PathValue("id"), req.Amount)
+	if err != nil {
+		if errors.Is(err, domain.ErrAccountNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, domain.ErrOffBudgetAccount) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(change)
+}
+
+// SetHiddenFromDashboard handles PUT /api/accounts/{id}/hidden
+func (h *AccountHandler) SetHiddenFromDashboard(w http.ResponseWriter, r *http.Request) {
+	var req SetHiddenFromDashboardRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	account, err := h.accountService.SetHiddenFromDashboard(r.Context(), r.PathValue("id"), req.Hidden)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(account)
+}
+
+// ConvertAccountType handles POST /api/accounts/{id}/convert
+// Changes the account's type along with its payment category and allocations
+func (h *AccountHandler) ConvertAccountType(w http.ResponseWriter, r *http.Request) {
+	var req ConvertAccountTypeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	opts := application.AccountConversionOptions{MoveAllocationsTo: req.MoveAllocationsTo}
+	conversion, err := h.accountService.ConvertAccountType(r.Context(), r.PathValue("id"), domain.AccountType(req.Type), opts)
+	if err != nil {
+		if errors.Is(err, domain.ErrAccountNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(conversion)
+}
+
+// AdjustRewards handles POST /api/accounts/{id}/rewards
+func (h *AccountHandler) AdjustRewards(w http.ResponseWriter, r *http.Request) {
+	var req AdjustRewardsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	account, err := h.accountService.AdjustRewards(r.Context(), r.PathValue("id"), req.Amount)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(account)
+}
@@ -6,7 +6,11 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 
+	"github.com/billybbuffum/budget/internal/application"
 	"github.com/billybbuffum/budget/internal/domain"
 	"github.com/billybbuffum/budget/internal/infrastructure/http/validators"
 )
@@ -19,17 +23,32 @@ type AllocationServiceInterface interface {
 	ListAllocationsByPeriod(ctx context.Context, period string) ([]*domain.Allocation, error)
 	DeleteAllocation(ctx context.Context, id string) error
 	GetAllocationSummary(ctx context.Context, period string) ([]*domain.AllocationSummary, error)
-	AllocateToCoverUnderfunded(ctx context.Context, paymentCategoryID string, period string) (*domain.Allocation, int64, error)
+	GetAllocationGroupSummary(ctx context.Context, period string, expand []string) ([]*domain.AllocationGroupSummary, error)
+	CoverOverspending(ctx context.Context, categoryID, period, fromCategoryID string) (*application.CoverOverspendingResult, error)
 	CalculateReadyToAssignForPeriod(ctx context.Context, period string) (int64, error)
+	RecordSummaryError(part string, err error)
+	InspectCategory(ctx context.Context, categoryID, period string) (*domain.CategoryInspector, error)
+	PreviewPeriodClose(ctx context.Context, period string) (*application.PeriodClosePreview, error)
+	FundPeriod(ctx context.Context, period string, opts application.FundPeriodOptions) (*application.FundPeriodResult, error)
+}
+
+// CreditCardServiceInterface defines the interface for credit card payment category operations
+type CreditCardServiceInterface interface {
+	CoverUnderfunded(ctx context.Context, paymentCategoryID, period string) (*application.UnderfundedCover, error)
+	ListMoves(ctx context.Context, paymentCategoryID string) ([]*domain.CategoryMove, error)
+	ReconcileCardPayment(ctx context.Context, accountID, period string, statementBalance *int64) (*application.CardPaymentReconciliation, error)
+	FixCardPayment(ctx context.Context, accountID, period string, statementBalance *int64) (*application.CardPaymentReconciliation, error)
 }
 
 type AllocationHandler struct {
 	allocationService AllocationServiceInterface
+	creditCardService CreditCardServiceInterface
 }
 
-func NewAllocationHandler(allocationService AllocationServiceInterface) *AllocationHandler {
+func NewAllocationHandler(allocationService AllocationServiceInterface, creditCardService CreditCardServiceInterface) *AllocationHandler {
 	return &AllocationHandler{
 		allocationService: allocationService,
+		creditCardService: creditCardService,
 	}
 }
 
@@ -78,7 +97,7 @@ func (h *AllocationHandler) GetAllocation(w http.ResponseWriter, r *http.Request
 func (h *AllocationHandler) ListAllocations(w http.ResponseWriter, r *http.Request) {
 	period := r.URL.Query().Get("period")
 
-	var allocations interface{}
+	var allocations []*domain.Allocation
 	var err error
 
 	if period != "" {
@@ -92,35 +111,82 @@ func (h *AllocationHandler) ListAllocations(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(allocations)
+	writeJSONArray(w, allocations)
 }
 
+// GetAllocationSummary handles GET /api/allocations/summary?period=YYYY-MM
+// period may also be a week (YYYY-Www) or a quarter (YYYY-Qn), for budgeting per paycheck
+// or per quarter; the response describes it in period.
+// With view=groups only group rollups are returned, plus category detail for the
+// groups listed in expand (comma-separated group IDs, "ungrouped" for categories
+// without a group).
+// Parts that can't be calculated are left out rather than failing the request:
+// ready_to_assign is null when it can't be, categories list what they're missing in
+// errors, and degraded is true with errors listing every part left out.
 func (h *AllocationHandler) GetAllocationSummary(w http.ResponseWriter, r *http.Request) {
-	period := r.URL.Query().Get("period")
+	query := r.URL.Query()
+	period := query.Get("period")
 	if period == "" {
 		http.Error(w, "period query parameter is required", http.StatusBadRequest)
 		return
 	}
-
-	summary, err := h.allocationService.GetAllocationSummary(r.Context(), period)
+	budgetPeriod, err := domain.ParsePeriod(period)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Calculate Ready to Assign for this period
-	readyToAssign, err := h.allocationService.CalculateReadyToAssignForPeriod(r.Context(), period)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	var response map[string]interface{}
+	var details []*domain.AllocationSummary
+	switch query.Get("view") {
+	case "", "categories":
+		summary, err := h.allocationService.GetAllocationSummary(r.Context(), period)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		response = map[string]interface{}{"categories": summary}
+		details = summary
+	case "groups":
+		var expand []string
+		for _, id := range strings.Split(query.Get("expand"), ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				expand = append(expand, id)
+			}
+		}
+		groups, err := h.allocationService.GetAllocationGroupSummary(r.Context(), period, expand)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		response = map[string]interface{}{"groups": groups}
+		for _, group := range groups {
+			details = append(details, group.Categories...)
+		}
+	default:
+		http.Error(w, "view must be categories or groups", http.StatusBadRequest)
 		return
 	}
 
-	// Include Ready to Assign in response
-	response := map[string]interface{}{
-		"categories":      summary,
-		"ready_to_assign": readyToAssign,
+	// Calculate Ready to Assign for this period
+	errs := []string{}
+	if readyToAssign, err := h.allocationService.CalculateReadyToAssignForPeriod(r.Context(), period); err != nil {
+		h.allocationService.RecordSummaryError(domain.SummaryPartReadyToAssign, err)
+		response["ready_to_assign"] = nil
+		errs = append(errs, domain.SummaryPartReadyToAssign)
+	} else {
+		response["ready_to_assign"] = readyToAssign
+	}
+	for _, detail := range details {
+		for _, part := range detail.Errors {
+			if !slices.Contains(errs, part) {
+				errs = append(errs, part)
+			}
+		}
 	}
+	response["period"] = budgetPeriod
+	response["degraded"] = len(errs) > 0
+	response["errors"] = errs
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
@@ -133,6 +199,11 @@ func (h *AllocationHandler) GetReadyToAssign(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	if err := validators.ValidateBudgetPeriod(period); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	readyToAssign, err := h.allocationService.CalculateReadyToAssignForPeriod(r.Context(), period)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -184,19 +255,14 @@ func (h *AllocationHandler) CoverUnderfunded(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Validate period format and range
-	if err := validators.ValidatePeriodFormat(req.Period); err != nil {
+	// Validate period format and range; any budgeting period works
+	if err := validators.ValidateBudgetPeriodRange(req.Period); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	if err := validators.ValidatePeriodRange(req.Period); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	// Call service method to allocate to cover underfunded
-	allocation, underfundedAmount, err := h.allocationService.AllocateToCoverUnderfunded(
+	// Move money into the payment category to cover what the card owes
+	cover, err := h.creditCardService.CoverUnderfunded(
 		r.Context(),
 		req.PaymentCategoryID,
 		req.Period,
@@ -234,8 +300,9 @@ func (h *AllocationHandler) CoverUnderfunded(w http.ResponseWriter, r *http.Requ
 
 	// Prepare successful response
 	response := map[string]interface{}{
-		"allocation":            allocation,
-		"underfunded_amount":    underfundedAmount,
+		"allocations":           cover.Allocations,
+		"moves":                 cover.Moves,
+		"underfunded_amount":    cover.UnderfundedAmount,
 		"ready_to_assign_after": readyToAssignAfter,
 	}
 
@@ -243,3 +310,289 @@ func (h *AllocationHandler) CoverUnderfunded(w http.ResponseWriter, r *http.Requ
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(response)
 }
+
+// CoverOverspendingRequest represents the request body for covering an overspent category
+type CoverOverspendingRequest struct {
+	Period         string `json:"period"`                     // YYYY-MM
+	FromCategoryID string `json:"from_category_id,omitempty"` // Donor category; empty takes the money from Ready to Assign
+}
+
+// CoverOverspending handles POST /api/categories/{id}/cover-overspending
+// Allocates the category's shortfall from Ready to Assign or a donor category
+func (h *AllocationHandler) CoverOverspending(w http.ResponseWriter, r *http.Request) {
+	categoryID := r.PathValue("id")
+
+	var req CoverOverspendingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("ERROR: Failed to decode request body: %v", err)
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Validate UUID format
+	if err := validators.ValidateUUID(categoryID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.FromCategoryID != "" {
+		if err := validators.ValidateUUID(req.FromCategoryID); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Validate period format and range; any budgeting period works
+	if err := validators.ValidateBudgetPeriodRange(req.Period); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.allocationService.CoverOverspending(r.Context(), categoryID, req.Period, req.FromCategoryID)
+	if err != nil {
+		// Log detailed error internally
+		log.Printf("ERROR: Failed to cover overspending for category %s: %v", categoryID, err)
+
+		// Use typed error checking for appropriate status codes
+		if errors.Is(err, domain.ErrCategoryNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		if errors.Is(err, domain.ErrPaymentCategory) ||
+			errors.Is(err, domain.ErrNotOverspent) ||
+			errors.Is(err, domain.ErrInsufficientFunds) ||
+			errors.Is(err, domain.ErrInsufficientCategoryFunds) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// For all other errors, return generic internal server error
+		http.Error(w, "Failed to process allocation request", http.StatusInternalServerError)
+		return
+	}
+
+	// Calculate Ready to Assign after the allocation
+	readyToAssignAfter, err := h.allocationService.CalculateReadyToAssignForPeriod(r.Context(), req.Period)
+	if err != nil {
+		log.Printf("WARNING: Failed to calculate Ready to Assign after allocation: %v", err)
+		// Continue with response even if RTA calculation fails
+		readyToAssignAfter = 0
+	}
+
+	response := map[string]interface{}{
+		"allocation":            result.Allocation,
+		"covered_amount":        result.CoveredAmount,
+		"ready_to_assign_after": readyToAssignAfter,
+	}
+	if result.DonorAllocation != nil {
+		response["donor_allocation"] = result.DonorAllocation
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetCategoryInspector handles GET /api/categories/{id}/inspector?period=YYYY-MM
+// Returns the category's numbers for the period, spending averages, quick-budget amounts,
+// and recent transactions in a single response
+func (h *AllocationHandler) GetCategoryInspector(w http.ResponseWriter, r *http.Request) {
+	categoryID := r.PathValue("id")
+	if err := validators.ValidateUUID(categoryID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	period := r.URL.Query().Get("period")
+	if period == "" {
+		http.Error(w, "period query parameter is required", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateBudgetPeriod(period); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	inspector, err := h.allocationService.InspectCategory(r.Context(), categoryID, period)
+	if err != nil {
+		if errors.Is(err, domain.ErrCategoryNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		log.Printf("ERROR: Failed to inspect category %s: %v", categoryID, err)
+		http.Error(w, "Failed to load category inspector", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(inspector)
+}
+
+// ListCategoryMoves handles GET /api/categories/{id}/moves
+// Lists the money moved into a credit card payment category
+func (h *AllocationHandler) ListCategoryMoves(w http.ResponseWriter, r *http.Request) {
+	categoryID := r.PathValue("id")
+	if err := validators.ValidateUUID(categoryID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	moves, err := h.creditCardService.ListMoves(r.Context(), categoryID)
+	if err != nil {
+		if errors.Is(err, domain.ErrCategoryNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, domain.ErrNotPaymentCategory) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("ERROR: Failed to list moves for category %s: %v", categoryID, err)
+		http.Error(w, "Failed to list category moves", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(moves)
+}
+
+// PreviewPeriodClose handles GET /api/periods/{period}/close-preview
+// A dry run of the month rolling over: what carries forward, what resets, and where
+// Ready to Assign ends up, so overspending can be fixed first
+func (h *AllocationHandler) PreviewPeriodClose(w http.ResponseWriter, r *http.Request) {
+	period := r.PathValue("period")
+	if err := validators.ValidatePeriodFormat(period); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	preview, err := h.allocationService.PreviewPeriodClose(r.Context(), period)
+	if err != nil {
+		log.Printf("ERROR: Failed to preview close of period %s: %v", period, err)
+		http.Error(w, "Failed to preview period close", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(preview)
+}
+
+// FundPeriodRequest represents the request body for funding a period from the month before
+type FundPeriodRequest struct {
+	Basis        application.FundingBasis `json:"basis,omitempty"`         // budgeted_last_month (default), spent_last_month or average_spent
+	ScalePercent int                      `json:"scale_percent,omitempty"` // Defaults to 100
+	GroupIDs     []string                 `json:"group_ids,omitempty"`     // Only fund these groups; "ungrouped" for categories without one
+	Overwrite    bool                     `json:"overwrite,omitempty"`     // Replace allocations already made in the period
+}
+
+// FundPeriod handles POST /api/periods/{period}/fund
+// Fills in the period's allocations in one call from last month's budget or spending
+func (h *AllocationHandler) FundPeriod(w http.ResponseWriter, r *http.Request) {
+	period := r.PathValue("period")
+	if err := validators.ValidatePeriodFormat(period); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidatePeriodRange(period); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req FundPeriodRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	result, err := h.allocationService.FundPeriod(r.Context(), period, application.FundPeriodOptions{
+		Basis:        req.Basis,
+		ScalePercent: req.ScalePercent,
+		GroupIDs:     req.GroupIDs,
+		Overwrite:    req.Overwrite,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// FixCardPaymentRequest represents the request body for correcting a card payment reconciliation
+type FixCardPaymentRequest struct {
+	Period           string `json:"period"`                      // YYYY-MM
+	StatementBalance *int64 `json:"statement_balance,omitempty"` // Owed per the statement in cents; defaults to the card balance
+}
+
+// ReconcileCardPayment handles GET /api/accounts/{id}/payment-reconciliation?period=YYYY-MM&statement_balance=
+// Compares the statement balance, the period's payments to the card and its payment category
+func (h *AllocationHandler) ReconcileCardPayment(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := FixCardPaymentRequest{Period: query.Get("period")}
+	if value := query.Get("statement_balance"); value != "" {
+		balance, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			http.Error(w, "statement_balance must be a whole number of cents", http.StatusBadRequest)
+			return
+		}
+		req.StatementBalance = &balance
+	}
+	h.reconcileCardPayment(w, r, req, h.creditCardService.ReconcileCardPayment)
+}
+
+// FixCardPayment handles POST /api/accounts/{id}/payment-reconciliation
+// Links payments made outside the payment category and covers an underfunded payment category
+func (h *AllocationHandler) FixCardPayment(w http.ResponseWriter, r *http.Request) {
+	var req FixCardPaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	h.reconcileCardPayment(w, r, req, h.creditCardService.FixCardPayment)
+}
+
+func (h *AllocationHandler) reconcileCardPayment(
+	w http.ResponseWriter,
+	r *http.Request,
+	req FixCardPaymentRequest,
+	reconcile func(ctx context.Context, accountID, period string, statementBalance *int64) (*application.CardPaymentReconciliation, error),
+) {
+	accountID := r.PathValue("id")
+	if err := validators.ValidateUUID(accountID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Period == "" {
+		http.Error(w, "period is required (e.g., '2024-11')", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidatePeriodFormat(req.Period); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.StatementBalance != nil && *req.StatementBalance < 0 {
+		http.Error(w, "statement_balance must not be negative", http.StatusBadRequest)
+		return
+	}
+
+	reconciliation, err := reconcile(r.Context(), accountID, req.Period, req.StatementBalance)
+	if err != nil {
+		if errors.Is(err, domain.ErrAccountNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, domain.ErrNotCreditAccount) || errors.Is(err, domain.ErrInsufficientFunds) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("ERROR: Failed to reconcile card payment for account %s: %v", accountID, err)
+		http.Error(w, "Failed to reconcile card payment", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reconciliation)
+}
@@ -11,28 +11,39 @@ import (
 	"testing"
 	"time"
 
+	"github.com/billybbuffum/budget/internal/application"
 	"github.com/billybbuffum/budget/internal/domain"
 )
 
 // Mock AllocationService for handler tests
 
 type mockAllocationService struct {
-	allocateToCoverUnderfundedResult    *domain.Allocation
-	allocateToCoverUnderfundedUnderfunded int64
-	allocateToCoverUnderfundedError     error
+	coverUnderfundedResult              *application.UnderfundedCover
+	coverUnderfundedError               error
 	calculateReadyToAssignResult        int64
 	calculateReadyToAssignError         error
+	coverOverspendingResult             *application.CoverOverspendingResult
+	coverOverspendingError              error
+	allocationSummaryResult             []*domain.AllocationSummary
+	recordedSummaryErrors               []string
 }
 
-func (m *mockAllocationService) AllocateToCoverUnderfunded(
-	ctx context.Context,
-	paymentCategoryID string,
-	period string,
-) (*domain.Allocation, int64, error) {
-	if m.allocateToCoverUnderfundedError != nil {
-		return nil, 0, m.allocateToCoverUnderfundedError
+func (m *mockAllocationService) CoverOverspending(ctx context.Context, categoryID, period, fromCategoryID string) (*application.CoverOverspendingResult, error) {
+	if m.coverOverspendingError != nil {
+		return nil, m.coverOverspendingError
 	}
-	return m.allocateToCoverUnderfundedResult, m.allocateToCoverUnderfundedUnderfunded, nil
+	return m.coverOverspendingResult, nil
+}
+
+func (m *mockAllocationService) CoverUnderfunded(ctx context.Context, paymentCategoryID, period string) (*application.UnderfundedCover, error) {
+	if m.coverUnderfundedError != nil {
+		return nil, m.coverUnderfundedError
+	}
+	return m.coverUnderfundedResult, nil
+}
+
+func (m *mockAllocationService) ListMoves(ctx context.Context, paymentCategoryID string) ([]*domain.CategoryMove, error) {
+	return nil, nil
 }
 
 func (m *mockAllocationService) CalculateReadyToAssignForPeriod(ctx context.Context, period string) (int64, error) {
@@ -42,6 +53,10 @@ func (m *mockAllocationService) CalculateReadyToAssignForPeriod(ctx context.Cont
 	return m.calculateReadyToAssignResult, nil
 }
 
+func (m *mockAllocationService) RecordSummaryError(part string, err error) {
+	m.recordedSummaryErrors = append(m.recordedSummaryErrors, part)
+}
+
 func (m *mockAllocationService) CreateAllocation(ctx context.Context, categoryID string, amount int64, period, notes string) (*domain.Allocation, error) {
 	return nil, nil
 }
@@ -63,6 +78,30 @@ func (m *mockAllocationService) DeleteAllocation(ctx context.Context, id string)
 }
 
 func (m *mockAllocationService) GetAllocationSummary(ctx context.Context, period string) ([]*domain.AllocationSummary, error) {
+	return m.allocationSummaryResult, nil
+}
+
+func (m *mockAllocationService) GetAllocationGroupSummary(ctx context.Context, period string, expand []string) ([]*domain.AllocationGroupSummary, error) {
+	return nil, nil
+}
+
+func (m *mockAllocationService) InspectCategory(ctx context.Context, categoryID, period string) (*domain.CategoryInspector, error) {
+	return nil, nil
+}
+
+func (m *mockAllocationService) PreviewPeriodClose(ctx context.Context, period string) (*application.PeriodClosePreview, error) {
+	return nil, nil
+}
+
+func (m *mockAllocationService) FundPeriod(ctx context.Context, period string, opts application.FundPeriodOptions) (*application.FundPeriodResult, error) {
+	return nil, nil
+}
+
+func (m *mockAllocationService) ReconcileCardPayment(ctx context.Context, accountID, period string, statementBalance *int64) (*application.CardPaymentReconciliation, error) {
+	return nil, nil
+}
+
+func (m *mockAllocationService) FixCardPayment(ctx context.Context, accountID, period string, statementBalance *int64) (*application.CardPaymentReconciliation, error) {
 	return nil, nil
 }
 
@@ -75,23 +114,21 @@ func TestAllocationHandler_CoverUnderfunded_Success(t *testing.T) {
 	underfundedAmount := int64(20000)    // $200
 	readyToAssignAfter := int64(330000)  // $3300
 
-	allocation := &domain.Allocation{
-		ID:         "allocation-id",
-		CategoryID: paymentCategoryID,
-		Period:     period,
-		Amount:     underfundedAmount,
-		Notes:      "Cover underfunded credit card spending",
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
+	move := &domain.CategoryMove{
+		ID:           "move-id",
+		ToCategoryID: paymentCategoryID,
+		Period:       period,
+		Amount:       underfundedAmount,
+		Reason:       domain.CategoryMoveCover,
+		CreatedAt:    time.Now(),
 	}
 
 	mockService := &mockAllocationService{
-		allocateToCoverUnderfundedResult:      allocation,
-		allocateToCoverUnderfundedUnderfunded: underfundedAmount,
+		coverUnderfundedResult: &application.UnderfundedCover{Moves: []*domain.CategoryMove{move}, UnderfundedAmount: underfundedAmount},
 		calculateReadyToAssignResult:          readyToAssignAfter,
 	}
 
-	handler := NewAllocationHandler(mockService)
+	handler := NewAllocationHandler(mockService, mockService)
 
 	requestBody := CoverUnderfundedRequest{
 		PaymentCategoryID: paymentCategoryID,
@@ -124,24 +161,25 @@ func TestAllocationHandler_CoverUnderfunded_Success(t *testing.T) {
 		t.Errorf("Response ready_to_assign_after = %v, want %v", response["ready_to_assign_after"], readyToAssignAfter)
 	}
 
-	allocationData, ok := response["allocation"].(map[string]interface{})
-	if !ok {
-		t.Fatal("Response allocation is not a map")
+	moves, ok := response["moves"].([]interface{})
+	if !ok || len(moves) != 1 {
+		t.Fatalf("Response moves = %v, want one move", response["moves"])
 	}
 
-	if allocationData["id"] != allocation.ID {
-		t.Errorf("Response allocation.id = %v, want %v", allocationData["id"], allocation.ID)
+	moveData := moves[0].(map[string]interface{})
+	if moveData["id"] != move.ID {
+		t.Errorf("Response moves[0].id = %v, want %v", moveData["id"], move.ID)
 	}
 
-	if allocationData["category_id"] != paymentCategoryID {
-		t.Errorf("Response allocation.category_id = %v, want %v", allocationData["category_id"], paymentCategoryID)
+	if moveData["to_category_id"] != paymentCategoryID {
+		t.Errorf("Response moves[0].to_category_id = %v, want %v", moveData["to_category_id"], paymentCategoryID)
 	}
 }
 
 func TestAllocationHandler_CoverUnderfunded_InvalidJSON(t *testing.T) {
 	// Setup
 	mockService := &mockAllocationService{}
-	handler := NewAllocationHandler(mockService)
+	handler := NewAllocationHandler(mockService, mockService)
 
 	req := httptest.NewRequest("POST", "/api/allocations/cover-underfunded", bytes.NewReader([]byte("invalid json")))
 	req.Header.Set("Content-Type", "application/json")
@@ -163,7 +201,7 @@ func TestAllocationHandler_CoverUnderfunded_InvalidJSON(t *testing.T) {
 func TestAllocationHandler_CoverUnderfunded_InvalidUUID(t *testing.T) {
 	// Setup
 	mockService := &mockAllocationService{}
-	handler := NewAllocationHandler(mockService)
+	handler := NewAllocationHandler(mockService, mockService)
 
 	requestBody := CoverUnderfundedRequest{
 		PaymentCategoryID: "not-a-uuid",
@@ -205,7 +243,7 @@ func TestAllocationHandler_CoverUnderfunded_InvalidPeriodFormat(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Setup
 			mockService := &mockAllocationService{}
-			handler := NewAllocationHandler(mockService)
+			handler := NewAllocationHandler(mockService, mockService)
 
 			requestBody := CoverUnderfundedRequest{
 				PaymentCategoryID: "550e8400-e29b-41d4-a716-446655440000",
@@ -235,7 +273,7 @@ func TestAllocationHandler_CoverUnderfunded_InvalidPeriodFormat(t *testing.T) {
 func TestAllocationHandler_CoverUnderfunded_PeriodOutOfRange(t *testing.T) {
 	// Setup - period too far in the past
 	mockService := &mockAllocationService{}
-	handler := NewAllocationHandler(mockService)
+	handler := NewAllocationHandler(mockService, mockService)
 
 	// Calculate a period 3 years ago (should fail)
 	threeYearsAgo := time.Now().AddDate(-3, 0, 0).Format("2006-01")
@@ -266,9 +304,9 @@ func TestAllocationHandler_CoverUnderfunded_PeriodOutOfRange(t *testing.T) {
 func TestAllocationHandler_CoverUnderfunded_CategoryNotFound(t *testing.T) {
 	// Setup
 	mockService := &mockAllocationService{
-		allocateToCoverUnderfundedError: domain.ErrCategoryNotFound,
+		coverUnderfundedError: domain.ErrCategoryNotFound,
 	}
-	handler := NewAllocationHandler(mockService)
+	handler := NewAllocationHandler(mockService, mockService)
 
 	requestBody := CoverUnderfundedRequest{
 		PaymentCategoryID: "550e8400-e29b-41d4-a716-446655440000",
@@ -296,9 +334,9 @@ func TestAllocationHandler_CoverUnderfunded_CategoryNotFound(t *testing.T) {
 func TestAllocationHandler_CoverUnderfunded_NotPaymentCategory(t *testing.T) {
 	// Setup
 	mockService := &mockAllocationService{
-		allocateToCoverUnderfundedError: domain.ErrNotPaymentCategory,
+		coverUnderfundedError: domain.ErrNotPaymentCategory,
 	}
-	handler := NewAllocationHandler(mockService)
+	handler := NewAllocationHandler(mockService, mockService)
 
 	requestBody := CoverUnderfundedRequest{
 		PaymentCategoryID: "550e8400-e29b-41d4-a716-446655440000",
@@ -326,9 +364,9 @@ func TestAllocationHandler_CoverUnderfunded_NotPaymentCategory(t *testing.T) {
 func TestAllocationHandler_CoverUnderfunded_NotUnderfunded(t *testing.T) {
 	// Setup
 	mockService := &mockAllocationService{
-		allocateToCoverUnderfundedError: domain.ErrNotUnderfunded,
+		coverUnderfundedError: domain.ErrNotUnderfunded,
 	}
-	handler := NewAllocationHandler(mockService)
+	handler := NewAllocationHandler(mockService, mockService)
 
 	requestBody := CoverUnderfundedRequest{
 		PaymentCategoryID: "550e8400-e29b-41d4-a716-446655440000",
@@ -362,9 +400,9 @@ func TestAllocationHandler_CoverUnderfunded_InsufficientFunds(t *testing.T) {
 		5.00,
 	)
 	mockService := &mockAllocationService{
-		allocateToCoverUnderfundedError: insufficientFundsErr,
+		coverUnderfundedError: insufficientFundsErr,
 	}
-	handler := NewAllocationHandler(mockService)
+	handler := NewAllocationHandler(mockService, mockService)
 
 	requestBody := CoverUnderfundedRequest{
 		PaymentCategoryID: "550e8400-e29b-41d4-a716-446655440000",
@@ -397,9 +435,9 @@ func TestAllocationHandler_CoverUnderfunded_InsufficientFunds(t *testing.T) {
 func TestAllocationHandler_CoverUnderfunded_InternalServerError(t *testing.T) {
 	// Setup - simulate an unexpected error
 	mockService := &mockAllocationService{
-		allocateToCoverUnderfundedError: errors.New("database connection failed"),
+		coverUnderfundedError: errors.New("database connection failed"),
 	}
-	handler := NewAllocationHandler(mockService)
+	handler := NewAllocationHandler(mockService, mockService)
 
 	requestBody := CoverUnderfundedRequest{
 		PaymentCategoryID: "550e8400-e29b-41d4-a716-446655440000",
@@ -436,23 +474,21 @@ func TestAllocationHandler_CoverUnderfunded_RTACalculationFailure(t *testing.T)
 	period := "2025-10"
 	underfundedAmount := int64(20000)
 
-	allocation := &domain.Allocation{
-		ID:         "allocation-id",
-		CategoryID: paymentCategoryID,
-		Period:     period,
-		Amount:     underfundedAmount,
-		Notes:      "Cover underfunded credit card spending",
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
+	move := &domain.CategoryMove{
+		ID:           "move-id",
+		ToCategoryID: paymentCategoryID,
+		Period:       period,
+		Amount:       underfundedAmount,
+		Reason:       domain.CategoryMoveCover,
+		CreatedAt:    time.Now(),
 	}
 
 	mockService := &mockAllocationService{
-		allocateToCoverUnderfundedResult:      allocation,
-		allocateToCoverUnderfundedUnderfunded: underfundedAmount,
+		coverUnderfundedResult: &application.UnderfundedCover{Moves: []*domain.CategoryMove{move}, UnderfundedAmount: underfundedAmount},
 		calculateReadyToAssignError:           errors.New("failed to calculate RTA"),
 	}
 
-	handler := NewAllocationHandler(mockService)
+	handler := NewAllocationHandler(mockService, mockService)
 
 	requestBody := CoverUnderfundedRequest{
 		PaymentCategoryID: paymentCategoryID,
@@ -483,16 +519,16 @@ func TestAllocationHandler_CoverUnderfunded_RTACalculationFailure(t *testing.T)
 		t.Errorf("Response ready_to_assign_after = %v, want 0", response["ready_to_assign_after"])
 	}
 
-	// But allocation should still be present
-	if response["allocation"] == nil {
-		t.Error("Response allocation should not be nil")
+	// But the moves should still be present
+	if response["moves"] == nil {
+		t.Error("Response moves should not be nil")
 	}
 }
 
 func TestAllocationHandler_CoverUnderfunded_EmptyRequestBody(t *testing.T) {
 	// Setup
 	mockService := &mockAllocationService{}
-	handler := NewAllocationHandler(mockService)
+	handler := NewAllocationHandler(mockService, mockService)
 
 	req := httptest.NewRequest("POST", "/api/allocations/cover-underfunded", bytes.NewReader([]byte("{}")))
 	req.Header.Set("Content-Type", "application/json")
@@ -518,22 +554,21 @@ func TestAllocationHandler_CoverUnderfunded_ContentTypeJSON(t *testing.T) {
 	period := "2025-10"
 	underfundedAmount := int64(20000)
 
-	allocation := &domain.Allocation{
-		ID:         "allocation-id",
-		CategoryID: paymentCategoryID,
-		Period:     period,
-		Amount:     underfundedAmount,
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
+	move := &domain.CategoryMove{
+		ID:           "move-id",
+		ToCategoryID: paymentCategoryID,
+		Period:       period,
+		Amount:       underfundedAmount,
+		Reason:       domain.CategoryMoveCover,
+		CreatedAt:    time.Now(),
 	}
 
 	mockService := &mockAllocationService{
-		allocateToCoverUnderfundedResult:      allocation,
-		allocateToCoverUnderfundedUnderfunded: underfundedAmount,
+		coverUnderfundedResult: &application.UnderfundedCover{Moves: []*domain.CategoryMove{move}, UnderfundedAmount: underfundedAmount},
 		calculateReadyToAssignResult:          330000,
 	}
 
-	handler := NewAllocationHandler(mockService)
+	handler := NewAllocationHandler(mockService, mockService)
 
 	requestBody := CoverUnderfundedRequest{
 		PaymentCategoryID: paymentCategoryID,
@@ -559,3 +594,111 @@ func TestAllocationHandler_CoverUnderfunded_ContentTypeJSON(t *testing.T) {
 		t.Errorf("CoverUnderfunded() Content-Type = %s, want application/json", contentType)
 	}
 }
+
+// Tests for CoverOverspending handler
+
+func TestAllocationHandler_CoverOverspending_FromDonor(t *testing.T) {
+	categoryID := "550e8400-e29b-41d4-a716-446655440000"
+	donorID := "660e8400-e29b-41d4-a716-446655440000"
+
+	mockService := &mockAllocationService{
+		coverOverspendingResult: &application.CoverOverspendingResult{
+			Allocation:      &domain.Allocation{ID: "allocation-id", CategoryID: categoryID, Amount: 5000},
+			DonorAllocation: &domain.Allocation{ID: "donor-allocation-id", CategoryID: donorID, Amount: -5000},
+			CoveredAmount:   5000,
+		},
+		calculateReadyToAssignResult: 10000,
+	}
+	handler := NewAllocationHandler(mockService, mockService)
+
+	body, _ := json.Marshal(CoverOverspendingRequest{Period: "2025-10", FromCategoryID: donorID})
+	req := httptest.NewRequest("POST", "/api/categories/"+categoryID+"/cover-overspending", bytes.NewReader(body))
+	req.SetPathValue("id", categoryID)
+	w := httptest.NewRecorder()
+
+	handler.CoverOverspending(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("CoverOverspending() status = %d, want %d", w.Code, http.StatusCreated)
+	}
+	var response map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response["covered_amount"] != float64(5000) || response["donor_allocation"] == nil {
+		t.Errorf("unexpected response: %v", response)
+	}
+}
+
+func TestAllocationHandler_CoverOverspending_Errors(t *testing.T) {
+	categoryID := "550e8400-e29b-41d4-a716-446655440000"
+
+	tests := []struct {
+		name       string
+		id         string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{"invalid category id", "not-a-uuid", `{"period":"2025-10"}`, nil, http.StatusBadRequest},
+		{"invalid donor id", categoryID, `{"period":"2025-10","from_category_id":"nope"}`, nil, http.StatusBadRequest},
+		{"invalid period", categoryID, `{"period":"October"}`, nil, http.StatusBadRequest},
+		{"not found", categoryID, `{"period":"2025-10"}`, domain.ErrCategoryNotFound, http.StatusNotFound},
+		{"not overspent", categoryID, `{"period":"2025-10"}`, domain.ErrNotOverspent, http.StatusBadRequest},
+		{"payment category", categoryID, `{"period":"2025-10"}`, domain.ErrPaymentCategory, http.StatusBadRequest},
+		{"donor too small", categoryID, `{"period":"2025-10"}`, fmt.Errorf("%w: details", domain.ErrInsufficientCategoryFunds), http.StatusBadRequest},
+		{"unexpected", categoryID, `{"period":"2025-10"}`, errors.New("database is locked"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAllocationHandler(&mockAllocationService{coverOverspendingError: tt.err}, nil)
+			req := httptest.NewRequest("POST", "/api/categories/"+tt.id+"/cover-overspending", bytes.NewReader([]byte(tt.body)))
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+
+			handler.CoverOverspending(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("CoverOverspending() status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestAllocationHandler_GetAllocationSummary_Degraded(t *testing.T) {
+	mockService := &mockAllocationService{
+		calculateReadyToAssignError: errors.New("database is locked"),
+		allocationSummaryResult: []*domain.AllocationSummary{
+			{Category: &domain.Category{ID: "groceries"}, Errors: []string{domain.SummaryPartGoal}},
+			{Category: &domain.Category{ID: "rent"}, Errors: []string{domain.SummaryPartGoal}},
+		},
+	}
+	handler := NewAllocationHandler(mockService, mockService)
+
+	req := httptest.NewRequest("GET", "/api/allocations/summary?period=2025-10", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetAllocationSummary(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetAllocationSummary() status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var response struct {
+		ReadyToAssign *int64                      `json:"ready_to_assign"`
+		Degraded      bool                        `json:"degraded"`
+		Errors        []string                    `json:"errors"`
+		Categories    []*domain.AllocationSummary `json:"categories"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.ReadyToAssign != nil || !response.Degraded || len(response.Categories) != 2 {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	if fmt.Sprint(response.Errors) != "[ready_to_assign goal]" {
+		t.Errorf("errors = %v, want [ready_to_assign goal]", response.Errors)
+	}
+	if fmt.Sprint(mockService.recordedSummaryErrors) != "[ready_to_assign]" {
+		t.Errorf("recorded errors = %v, want [ready_to_assign]", mockService.recordedSummaryErrors)
+	}
+}
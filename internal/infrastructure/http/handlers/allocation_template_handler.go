@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/billybbuffum/budget/internal/infrastructure/http/validators"
+)
+
+type AllocationTemplateHandler struct {
+	templateService *application.AllocationTemplateService
+}
+
+func NewAllocationTemplateHandler(templateService *application.AllocationTemplateService) *AllocationTemplateHandler {
+	return &AllocationTemplateHandler{templateService: templateService}
+}
+
+type CreateAllocationTemplateRequest struct {
+	Name  string                          `json:"name"`
+	Items []domain.AllocationTemplateItem `json:"items"` // Category and amount in cents
+}
+
+type UpdateAllocationTemplateRequest struct {
+	Name  *string                         `json:"name,omitempty"`
+	Items []domain.AllocationTemplateItem `json:"items,omitempty"` // Replaces every item when set
+}
+
+type ApplyAllocationTemplateRequest struct {
+	Period string `json:"period"`        // YYYY-MM
+	Add    bool   `json:"add,omitempty"` // Add to existing allocations instead of replacing them
+}
+
+func (h *AllocationTemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	var req CreateAllocationTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	template, err := h.templateService.CreateTemplate(r.Context(), req.Name, req.Items)
+	if err != nil {
+		writeAllocationTemplateError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(template)
+}
+
+func (h *AllocationTemplateHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	template, err := h.templateService.GetTemplate(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeAllocationTemplateError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(template)
+}
+
+func (h *AllocationTemplateHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.templateService.ListTemplates(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if templates == nil {
+		templates = []*domain.AllocationTemplate{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(templates)
+}
+
+func (h *AllocationTemplateHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	var req UpdateAllocationTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	template, err := h.templateService.UpdateTemplate(r.Context(), r.PathValue("id"), req.Name, req.Items)
+	if err != nil {
+		writeAllocationTemplateError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(template)
+}
+
+func (h *AllocationTemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	if err := h.templateService.DeleteTemplate(r.Context(), r.PathValue("id")); err != nil {
+		writeAllocationTemplateError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ApplyTemplate handles POST /api/allocation-templates/{id}/apply
+// Assigns the template's amounts to its categories for the period in one step
+func (h *AllocationTemplateHandler) ApplyTemplate(w http.ResponseWriter, r *http.Request) {
+	var req ApplyAllocationTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidatePeriodFormat(req.Period); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidatePeriodRange(req.Period); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.templateService.ApplyTemplate(r.Context(), r.PathValue("id"), req.Period, req.Add)
+	if err != nil {
+		writeAllocationTemplateError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// writeAllocationTemplateError maps known errors to their status, and anything else to status
+func writeAllocationTemplateError(w http.ResponseWriter, err error, status int) {
+	switch {
+	case errors.Is(err, domain.ErrAllocationTemplateNotFound), errors.Is(err, domain.ErrCategoryNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrAllocationTemplateExists):
+		status = http.StatusConflict
+	}
+	http.Error(w, err.Error(), status)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type APITokenHandler struct {
+	tokenService *application.APITokenService
+}
+
+func NewAPITokenHandler(tokenService *application.APITokenService) *APITokenHandler {
+	return &APITokenHandler{tokenService: tokenService}
+}
+
+type CreateAPITokenRequest struct {
+	Name            string             `json:"name"`
+	Access          domain.TokenAccess `json:"access"`
+	AccountIDs      []string           `json:"account_ids"`  // Optional - restrict to these accounts
+	CategoryIDs     []string           `json:"category_ids"` // Optional - restrict to these categories
+	ExpiresAt       *time.Time         `json:"expires_at,omitempty"`
+	DailyWriteQuota int                `json:"daily_write_quota"` // Optional - writes per UTC day, 0 = instance default
+}
+
+type CreateAPITokenResponse struct {
+	*domain.APIToken
+	Token string `json:"token"` // Only returned once
+}
+
+// CreateToken handles POST /api/tokens
+// Returns the token; it cannot be retrieved again
+func (h *APITokenHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	var req CreateAPITokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Tokens created while signed in belong to that user, so they show up in their sessions list
+	var userID *string
+	if principal, ok := application.PrincipalFromContext(r.Context()); ok {
+		userID = principal.UserID()
+	}
+
+	token, raw, err := h.tokenService.CreateToken(r.Context(), userID, req.Name, req.Access, req.AccountIDs, req.CategoryIDs, req.ExpiresAt, req.DailyWriteQuota)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(CreateAPITokenResponse{APIToken: token, Token: raw})
+}
+
+func (h *APITokenHandler) ListTokens(w http.ResponseWriter, r *http.Request) {
+	tokens, err := h.tokenService.ListTokens(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tokens)
+}
+
+func (h *APITokenHandler) DeleteToken(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if err := h.tokenService.DeleteToken(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DisableToken handles POST /api/tokens/{id}/disable
+// The kill switch for a misbehaving integration: requests with the token fail until it's enabled again
+func (h *APITokenHandler) DisableToken(w http.ResponseWriter, r *http.Request) {
+	token, err := h.tokenService.DisableToken(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(token)
+}
+
+// EnableToken handles POST /api/tokens/{id}/enable
+func (h *APITokenHandler) EnableToken(w http.ResponseWriter, r *http.Request) {
+	token, err := h.tokenService.EnableToken(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(token)
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/application"
+)
+
+type AuditHandler struct {
+	auditService *application.AuditService
+}
+
+func NewAuditHandler(auditService *application.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// ListAuditLog handles GET /api/admin/audit
+// Optional query parameters: cursor (from next_cursor), limit, and since/until as RFC3339 times
+func (h *AuditHandler) ListAuditLog(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := 0
+	if l := query.Get("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed < 1 {
+			http.Error(w, "limit must be a positive number", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	since, err := parseOptionalTime(query.Get("since"))
+	if err != nil {
+		http.Error(w, "invalid since, expected RFC3339 time", http.StatusBadRequest)
+		return
+	}
+	until, err := parseOptionalTime(query.Get("until"))
+	if err != nil {
+		http.Error(w, "invalid until, expected RFC3339 time", http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.auditService.List(r.Context(), query.Get("cursor"), limit, since, until)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// ListActivity handles GET /api/activity
+// Optional query parameters: cursor (from next_cursor), limit, and since as an RFC3339 time
+func (h *AuditHandler) ListActivity(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := 0
+	if l := query.Get("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed < 1 {
+			http.Error(w, "limit must be a positive number", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	since, err := parseOptionalTime(query.Get("since"))
+	if err != nil {
+		http.Error(w, "invalid since, expected RFC3339 time", http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.auditService.Activity(r.Context(), query.Get("cursor"), limit, since)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+func parseOptionalTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
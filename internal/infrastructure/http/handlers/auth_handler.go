@@ -0,0 +1,580 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// SessionCookieName is the cookie that carries a browser's session token
+const SessionCookieName = "budget_session"
+
+type AuthHandler struct {
+	authService       *application.AuthService
+	userEmailService  *application.UserEmailService
+	preferenceService *application.UserPreferenceService
+	secureCookies     bool // Set the Secure flag on session cookies (serve over HTTPS)
+}
+
+func NewAuthHandler(
+	authService *application.AuthService,
+	userEmailService *application.UserEmailService,
+	preferenceService *application.UserPreferenceService,
+	secureCookies bool,
+) *AuthHandler {
+	return &AuthHandler{
+		authService:       authService,
+		userEmailService:  userEmailService,
+		preferenceService: preferenceService,
+		secureCookies:     secureCookies,
+	}
+}
+
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Code     string `json:"code"` // TOTP or recovery code, when two-factor is enabled
+}
+
+type LoginResponse struct {
+	Token     string       `json:"token"` // Session token, also set as a cookie; usable as a bearer token
+	ExpiresAt time.Time    `json:"expires_at"`
+	User      *domain.User `json:"user"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type DisableTwoFactorRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code"` // TOTP or recovery code
+}
+
+type TwoFactorRequirementRequest struct {
+	Required bool `json:"required"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type PasswordResetRequest struct {
+	Email string `json:"email"`
+}
+
+type ConfirmPasswordResetRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// ProfileResponse is the signed-in user together with their preferences
+type ProfileResponse struct {
+	*domain.User
+	Preferences *domain.UserPreferences `json:"preferences"`
+}
+
+type SessionListResponse struct {
+	Sessions []*domain.Session  `json:"sessions"`
+	Tokens   []*domain.APIToken `json:"tokens"`
+}
+
+// Login handles POST /api/auth/login
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	session, token, user, err := h.authService.Login(r.Context(), req.Email, req.Password, req.Code, r.UserAgent(), clientIP(r))
+	if err != nil {
+		// Lets the sign-in page ask for a code without matching the (translated) message
+		if errors.Is(err, application.ErrTwoFactorRequired) {
+			w.Header().Set("X-Two-Factor-Required", "true")
+		}
+		if errors.Is(err, application.ErrUserDisabled) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, application.ErrTooManySignInAttempts) || errors.Is(err, application.ErrTooManyTwoFactorAttempts) {
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	h.setSessionCookie(w, token, session.ExpiresAt)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(LoginResponse{Token: token, ExpiresAt: session.ExpiresAt, User: user})
+}
+
+// Logout handles POST /api/auth/logout and ends the current session
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	// Proxy-authenticated users have no session here; signing out happens at the proxy
+	if principal.Session != nil {
+		if err := h.authService.Logout(r.Context(), principal.Session.ID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	h.setSessionCookie(w, "", time.Unix(0, 0))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Me handles GET /api/auth/me and returns the signed-in user with their preferences
+func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	prefs, err := h.preferenceService.GetPreferences(r.Context(), principal.User.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ProfileResponse{User: principal.User, Preferences: prefs})
+}
+
+// GetPreferences handles GET /api/auth/preferences
+func (h *AuthHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	prefs, err := h.preferenceService.GetPreferences(r.Context(), principal.User.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// UpdatePreferences handles PUT /api/auth/preferences
+// Fields left out of the request keep their current values
+func (h *AuthHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	prefs, err := h.preferenceService.GetPreferences(r.Context(), principal.User.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(prefs); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	prefs, err = h.preferenceService.UpdatePreferences(r.Context(), principal.User.ID, prefs)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// ListSessions handles GET /api/auth/sessions
+// Returns the signed-in user's sessions (with device and last-used time) and API tokens
+func (h *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	sessions, tokens, err := h.authService.ListSessions(r.Context(), principal.User.ID, principal.SessionID())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(SessionListResponse{Sessions: sessions, Tokens: tokens})
+}
+
+// RevokeSession handles DELETE /api/auth/sessions/{id} (a session or API token ID)
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.authService.RevokeSession(r.Context(), principal.User.ID, r.PathValue("id")); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeAllSessions handles DELETE /api/auth/sessions?include_current=false
+// Revokes every session and API token of the user; the current session is kept
+// unless include_current=true
+func (h *AuthHandler) RevokeAllSessions(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	except := principal.SessionID()
+	if r.URL.Query().Get("include_current") == "true" {
+		except = ""
+		h.setSessionCookie(w, "", time.Unix(0, 0))
+	}
+
+	if err := h.authService.RevokeAllSessions(r.Context(), principal.User.ID, except); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetTwoFactorStatus handles GET /api/auth/two-factor
+func (h *AuthHandler) GetTwoFactorStatus(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	status, err := h.authService.GetTOTPStatus(r.Context(), principal.User)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// SetupTwoFactor handles POST /api/auth/two-factor/setup
+// Returns a new TOTP secret and otpauth:// URI; confirm it with POST /api/auth/two-factor/enable
+func (h *AuthHandler) SetupTwoFactor(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	enrollment, err := h.authService.BeginTOTPEnrollment(r.Context(), principal.User)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(enrollment)
+}
+
+// EnableTwoFactor handles POST /api/auth/two-factor/enable and returns recovery codes
+func (h *AuthHandler) EnableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	codes, err := h.authService.EnableTOTP(r.Context(), principal.User, req.Code)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTwoFactor handles POST /api/auth/two-factor/disable
+func (h *AuthHandler) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req DisableTwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.authService.DisableTOTP(r.Context(), principal.User, req.Password, req.Code); err != nil {
+		if errors.Is(err, application.ErrTooManyTwoFactorAttempts) {
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RegenerateRecoveryCodes handles POST /api/auth/two-factor/recovery-codes
+func (h *AuthHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	codes, err := h.authService.RegenerateRecoveryCodes(r.Context(), principal.User, req.Code)
+	if err != nil {
+		if errors.Is(err, application.ErrTooManyTwoFactorAttempts) {
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// SetTwoFactorRequirement handles PUT /api/users/{id}/two-factor (admin only)
+// Requires (or stops requiring) the user to use two-factor authentication
+func (h *AuthHandler) SetTwoFactorRequirement(w http.ResponseWriter, r *http.Request) {
+	var req TwoFactorRequirementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.authService.SetTOTPRequired(r.Context(), r.PathValue("id"), req.Required)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// ListUsers handles GET /api/users (admin only)
+func (h *AuthHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := h.authService.ListUsers(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONArray(w, users)
+}
+
+// DisableUser handles POST /api/users/{id}/disable (admin only)
+// The user is signed out everywhere and their API tokens are switched off
+func (h *AuthHandler) DisableUser(w http.ResponseWriter, r *http.Request) {
+	user, err := h.authService.DisableUser(r.Context(), actingUserID(r), r.PathValue("id"))
+	if err != nil {
+		writeUserAdminError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// EnableUser handles POST /api/users/{id}/enable (admin only)
+func (h *AuthHandler) EnableUser(w http.ResponseWriter, r *http.Request) {
+	user, err := h.authService.EnableUser(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeUserAdminError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// ResetTwoFactor handles POST /api/users/{id}/two-factor/reset (admin only)
+// Turns off the user's two-factor authentication, e.g. after they lose their authenticator
+func (h *AuthHandler) ResetTwoFactor(w http.ResponseWriter, r *http.Request) {
+	user, err := h.authService.ResetTwoFactor(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeUserAdminError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// TransferOwnership handles POST /api/users/{id}/transfer-ownership (admin only)
+// The user becomes an admin and the signed-in admin a member
+func (h *AuthHandler) TransferOwnership(w http.ResponseWriter, r *http.Request) {
+	user, err := h.authService.TransferOwnership(r.Context(), actingUserID(r), r.PathValue("id"))
+	if err != nil {
+		writeUserAdminError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// RequestPasswordReset handles POST /api/auth/password-reset
+// Always responds 202 for a well-formed request, whether or not the email belongs to a user
+func (h *AuthHandler) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var req PasswordResetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userEmailService.RequestPasswordReset(r.Context(), req.Email); err != nil {
+		writeUserEmailError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ConfirmPasswordReset handles POST /api/auth/password-reset/confirm
+func (h *AuthHandler) ConfirmPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var req ConfirmPasswordResetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userEmailService.ResetPassword(r.Context(), req.Token, req.Password); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SendVerificationEmail handles POST /api/auth/verify-email for the signed-in user
+func (h *AuthHandler) SendVerificationEmail(w http.ResponseWriter, r *http.Request) {
+	principal, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.userEmailService.SendVerificationEmail(r.Context(), principal.User); err != nil {
+		writeUserEmailError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// VerifyEmail handles POST /api/auth/verify-email/confirm
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.userEmailService.VerifyEmail(r.Context(), req.Token)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+func (h *AuthHandler) setSessionCookie(w http.ResponseWriter, token string, expires time.Time) {
+	setSessionCookie(w, token, expires, h.secureCookies)
+}
+
+// setSessionCookie sets (or, with an empty token and past expiry, clears) the session cookie
+func setSessionCookie(w http.ResponseWriter, token string, expires time.Time, secure bool) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    token,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// requireUser returns the principal for requests made by a signed-in user
+// (with a session, or authenticated by a trusted proxy) rather than an API token
+func requireUser(w http.ResponseWriter, r *http.Request) (*application.Principal, bool) {
+	principal, ok := application.PrincipalFromContext(r.Context())
+	if !ok || principal.User == nil {
+		http.Error(w, "sign in required", http.StatusUnauthorized)
+		return nil, false
+	}
+	return principal, true
+}
+
+// actingUserID returns the ID of the user making the request, or the owner of its API
+// token, if any
+func actingUserID(r *http.Request) *string {
+	principal, ok := application.PrincipalFromContext(r.Context())
+	if !ok {
+		return nil
+	}
+	return principal.UserID()
+}
+
+// writeUserAdminError maps errors from managing users to a status code
+func writeUserAdminError(w http.ResponseWriter, err error) {
+	if errors.Is(err, domain.ErrUserNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
+
+// writeUserEmailError maps errors from sending account emails to a status code
+func writeUserEmailError(w http.ResponseWriter, err error) {
+	if errors.Is(err, application.ErrEmailNotConfigured) {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
+
+// clientIP returns the remote IP of the request
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
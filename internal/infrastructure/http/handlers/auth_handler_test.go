@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type mockUserSettingRepository struct {
+	settings map[string]map[string]*domain.UserSetting
+}
+
+func (m *mockUserSettingRepository) Set(ctx context.Context, setting *domain.UserSetting) error {
+	if m.settings[setting.UserID] == nil {
+		m.settings[setting.UserID] = make(map[string]*domain.UserSetting)
+	}
+	m.settings[setting.UserID][setting.Key] = setting
+	return nil
+}
+
+func (m *mockUserSettingRepository) ListByUser(ctx context.Context, userID string) ([]*domain.UserSetting, error) {
+	var settings []*domain.UserSetting
+	for _, setting := range m.settings[userID] {
+		settings = append(settings, setting)
+	}
+	return settings, nil
+}
+
+func TestAuthHandler_Preferences(t *testing.T) {
+	user := &domain.User{ID: "u1", Email: "admin@example.com", Name: "Admin", Role: domain.UserRoleAdmin}
+	repo := &mockUserSettingRepository{settings: make(map[string]map[string]*domain.UserSetting)}
+	handler := NewAuthHandler(nil, nil, application.NewUserPreferenceService(repo), false)
+
+	send := func(handle http.HandlerFunc, method, body string, principal *application.Principal) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/auth/preferences", strings.NewReader(body))
+		if principal != nil {
+			req = req.WithContext(application.WithPrincipal(req.Context(), principal))
+		}
+		w := httptest.NewRecorder()
+		handle(w, req)
+		return w
+	}
+	signedIn := &application.Principal{User: user}
+
+	if w := send(handler.UpdatePreferences, http.MethodPut, `{"locale":"de-DE"}`, signedIn); w.Code != http.StatusOK {
+		t.Fatalf("expected the locale saved, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Only the date format is sent, so the locale set before is kept
+	w := send(handler.UpdatePreferences, http.MethodPut, `{"date_format":"DD.MM.YYYY"}`, signedIn)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected the date format saved, got %d: %s", w.Code, w.Body.String())
+	}
+	var prefs domain.UserPreferences
+	if err := json.NewDecoder(w.Body).Decode(&prefs); err != nil {
+		t.Fatal(err)
+	}
+	if prefs.Locale != "de-DE" || prefs.DateFormat != "DD.MM.YYYY" || prefs.DefaultPeriod != domain.DefaultPeriodCurrent {
+		t.Errorf("expected omitted fields left as they were, got %+v", prefs)
+	}
+
+	for _, body := range []string{`{"locale":"German"}`, `{"date_format":"YYYY/MM/DD"}`, `{"locale":`} {
+		if w := send(handler.UpdatePreferences, http.MethodPut, body, signedIn); w.Code != http.StatusBadRequest {
+			t.Errorf("expected %s refused, got %d", body, w.Code)
+		}
+	}
+
+	// The profile carries the saved preferences
+	w = send(handler.Me, http.MethodGet, "", signedIn)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected the profile, got %d: %s", w.Code, w.Body.String())
+	}
+	var profile ProfileResponse
+	if err := json.NewDecoder(w.Body).Decode(&profile); err != nil {
+		t.Fatal(err)
+	}
+	if profile.User == nil || profile.Email != user.Email || profile.Preferences == nil ||
+		profile.Preferences.Locale != "de-DE" || profile.Preferences.DateFormat != "DD.MM.YYYY" {
+		t.Errorf("expected the user with their saved preferences, got %+v %+v", profile.User, profile.Preferences)
+	}
+
+	if w := send(handler.Me, http.MethodGet, "", nil); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected signing in required, got %d", w.Code)
+	}
+}
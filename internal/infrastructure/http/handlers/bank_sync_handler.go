@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type BankSyncHandler struct {
+	bankSyncService *application.BankSyncService
+}
+
+func NewBankSyncHandler(bankSyncService *application.BankSyncService) *BankSyncHandler {
+	return &BankSyncHandler{bankSyncService: bankSyncService}
+}
+
+func (h *BankSyncHandler) SaveBankConnection(w http.ResponseWriter, r *http.Request) {
+	var req application.BankConnectionInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	connection, err := h.bankSyncService.SaveConnection(r.Context(), r.PathValue("id"), req)
+	if err != nil {
+		writeBankSyncError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(connection)
+}
+
+func (h *BankSyncHandler) GetBankConnection(w http.ResponseWriter, r *http.Request) {
+	connection, err := h.bankSyncService.GetConnection(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeBankSyncError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(connection)
+}
+
+func (h *BankSyncHandler) DeleteBankConnection(w http.ResponseWriter, r *http.Request) {
+	if err := h.bankSyncService.DeleteConnection(r.Context(), r.PathValue("id")); err != nil {
+		writeBankSyncError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *BankSyncHandler) SyncAccount(w http.ResponseWriter, r *http.Request) {
+	result, err := h.bankSyncService.SyncNow(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeBankSyncError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+type ConnectAggregationRequest struct {
+	Provider   string `json:"provider"`       // e.g. simplefin
+	Name       string `json:"name,omitempty"` // Defaults to the provider
+	SetupToken string `json:"setup_token"`    // One-time token from the aggregator
+}
+
+type ConnectAggregationResponse struct {
+	*domain.BankAggregation
+	Accounts []*domain.BankAggregationAccount `json:"accounts"` // To link to the budget's accounts
+}
+
+type LinkAggregationAccountRequest struct {
+	AccountID *string `json:"account_id"` // null unlinks
+}
+
+// ConnectAggregation handles POST /api/bank-aggregations
+func (h *BankSyncHandler) ConnectAggregation(w http.ResponseWriter, r *http.Request) {
+	var req ConnectAggregationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	aggregation, accounts, err := h.bankSyncService.ConnectAggregation(r.Context(), req.Provider, req.Name, req.SetupToken)
+	if err != nil {
+		writeBankSyncError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(ConnectAggregationResponse{BankAggregation: aggregation, Accounts: accounts})
+}
+
+func (h *BankSyncHandler) ListAggregations(w http.ResponseWriter, r *http.Request) {
+	aggregations, err := h.bankSyncService.ListAggregations(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONArray(w, aggregations)
+}
+
+func (h *BankSyncHandler) DeleteAggregation(w http.ResponseWriter, r *http.Request) {
+	if err := h.bankSyncService.DeleteAggregation(r.Context(), r.PathValue("id")); err != nil {
+		writeBankSyncError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *BankSyncHandler) ListAggregationAccounts(w http.ResponseWriter, r *http.Request) {
+	accounts, err := h.bankSyncService.ListAggregationAccounts(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeBankSyncError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONArray(w, accounts)
+}
+
+// LinkAggregationAccount handles PUT /api/bank-aggregations/{id}/accounts/{remoteID}
+func (h *BankSyncHandler) LinkAggregationAccount(w http.ResponseWriter, r *http.Request) {
+	var req LinkAggregationAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	account, err := h.bankSyncService.LinkAggregationAccount(r.Context(), r.PathValue("id"), r.PathValue("remoteID"), req.AccountID)
+	if err != nil {
+		writeBankSyncError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(account)
+}
+
+func (h *BankSyncHandler) SyncAggregation(w http.ResponseWriter, r *http.Request) {
+	result, err := h.bankSyncService.SyncAggregation(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeBankSyncError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// writeBankSyncError answers with the status err calls for, or status
+func writeBankSyncError(w http.ResponseWriter, err error, status int) {
+	switch {
+	case errors.Is(err, domain.ErrAccountNotFound), errors.Is(err, domain.ErrBankConnectionNotFound),
+		errors.Is(err, domain.ErrBankAggregationNotFound), errors.Is(err, domain.ErrBankAggregationAccountNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, application.ErrBankSyncNotConfigured):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, application.ErrBankSyncFailed):
+		status = http.StatusBadGateway
+	}
+	http.Error(w, err.Error(), status)
+}
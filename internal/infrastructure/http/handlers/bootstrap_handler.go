@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/billybbuffum/budget/internal/application"
+)
+
+type BootstrapHandler struct {
+	bootstrapService *application.BootstrapService
+}
+
+func NewBootstrapHandler(bootstrapService *application.BootstrapService) *BootstrapHandler {
+	return &BootstrapHandler{bootstrapService: bootstrapService}
+}
+
+type ApplyTemplateRequest struct {
+	Template string `json:"template"`
+	Locale   string `json:"locale"` // Optional; defaults to the server's DEFAULT_LOCALE
+}
+
+// GetStatus lists the starter templates and whether the budget still needs one
+// Templates are described in the language given by ?locale=, if any.
+func (h *BootstrapHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.bootstrapService.GetStatus(r.Context(), r.URL.Query().Get("locale"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// ApplyTemplate sets a new budget up from a starter template
+func (h *BootstrapHandler) ApplyTemplate(w http.ResponseWriter, r *http.Request) {
+	var req ApplyTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	applied, err := h.bootstrapService.ApplyTemplate(r.Context(), req.Template, req.Locale)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, application.ErrBudgetAlreadySetUp) {
+			status = http.StatusConflict
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(applied)
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type BotHandler struct {
+	botService *application.BotService
+}
+
+func NewBotHandler(botService *application.BotService) *BotHandler {
+	return &BotHandler{botService: botService}
+}
+
+type CreateBotChatRequest struct {
+	Platform         domain.BotPlatform `json:"platform"`
+	ChatID           string             `json:"chat_id"` // Optional - built-in bots link the chat with the token
+	Name             string             `json:"name"`
+	DefaultAccountID *string            `json:"default_account_id,omitempty"`
+	DailySummary     bool               `json:"daily_summary"`
+}
+
+type CreateBotChatResponse struct {
+	*domain.BotChat
+	Token string `json:"token"` // Only returned once
+}
+
+type BotMessageRequest struct {
+	Text string `json:"text"`
+}
+
+// CreateChat handles POST /api/bot/chats
+// Returns the chat's token; it cannot be retrieved again
+func (h *BotHandler) CreateChat(w http.ResponseWriter, r *http.Request) {
+	var req CreateBotChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	chat, token, err := h.botService.CreateChat(r.Context(), req.Platform, req.ChatID, req.Name, req.DefaultAccountID, req.DailySummary)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(CreateBotChatResponse{BotChat: chat, Token: token})
+}
+
+func (h *BotHandler) ListChats(w http.ResponseWriter, r *http.Request) {
+	chats, err := h.botService.ListChats(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(chats)
+}
+
+func (h *BotHandler) DeleteChat(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if err := h.botService.DeleteChat(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleMessage handles POST /api/bot/message (Authorization: Bearer <chat token>)
+// The text is answered exactly as the built-in bot would answer it
+func (h *BotHandler) HandleMessage(w http.ResponseWriter, r *http.Request) {
+	chat, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	var req BotMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	reply, err := h.botService.HandleMessage(r.Context(), chat, req.Text)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reply)
+}
+
+// GetSummary handles GET /api/bot/summary?date=YYYY-MM-DD (Authorization: Bearer <chat token>)
+func (h *BotHandler) GetSummary(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.authenticate(w, r); !ok {
+		return
+	}
+
+	day := time.Now()
+	if d := r.URL.Query().Get("date"); d != "" {
+		parsed, err := time.Parse("2006-01-02", d)
+		if err != nil {
+			http.Error(w, "invalid date format, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		day = parsed
+	}
+
+	summary, err := h.botService.DailySummary(r.Context(), day)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
+
+// authenticate resolves the bearer token to a bot chat, writing a 401 on failure
+func (h *BotHandler) authenticate(w http.ResponseWriter, r *http.Request) (*domain.BotChat, bool) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	chat, err := h.botService.Authenticate(r.Context(), token)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return nil, false
+	}
+	return chat, true
+}
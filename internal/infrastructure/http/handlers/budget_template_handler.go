@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/billybbuffum/budget/internal/application"
+)
+
+type BudgetTemplateHandler struct {
+	budgetTemplateService *application.BudgetTemplateService
+}
+
+func NewBudgetTemplateHandler(budgetTemplateService *application.BudgetTemplateService) *BudgetTemplateHandler {
+	return &BudgetTemplateHandler{budgetTemplateService: budgetTemplateService}
+}
+
+// ExportTemplate handles GET /api/budget-template?name=...&period=YYYY-MM&months=3
+// The template is sent as a download; amounts are averaged over the months ending with period.
+func (h *BudgetTemplateHandler) ExportTemplate(w http.ResponseWriter, r *http.Request) {
+	months, err := parseTrailingMonths(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	template, err := h.budgetTemplateService.Export(r.Context(), r.URL.Query().Get("name"), r.URL.Query().Get("period"), months)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="budget-template.json"`)
+	json.NewEncoder(w).Encode(template)
+}
+
+// ImportTemplate handles POST /api/budget-template/import with an exported template as the body
+func (h *BudgetTemplateHandler) ImportTemplate(w http.ResponseWriter, r *http.Request) {
+	var template application.BudgetTemplate
+	if err := json.NewDecoder(r.Body).Decode(&template); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.budgetTemplateService.Import(r.Context(), &template)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
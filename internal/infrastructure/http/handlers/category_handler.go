@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
 )
 
 type CategoryHandler struct {
@@ -16,17 +17,26 @@ func NewCategoryHandler(categoryService *application.CategoryService) *CategoryH
 }
 
 type CreateCategoryRequest struct {
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Color       string  `json:"color"`
-	GroupID     *string `json:"group_id"`
+	Name             string  `json:"name"`
+	Description      string  `json:"description"`
+	Color            string  `json:"color"`
+	GroupID          *string `json:"group_id"`
+	Classification   string  `json:"classification"`    // essential, discretionary, savings, debt
+	OverspendingMode string  `json:"overspending_mode"` // auto, cash, credit
+}
+
+type CreateCategoriesRequest struct {
+	Group      *application.NewCategoryGroup `json:"group"` // Optional group to create; categories without a group_id go in it
+	Categories []application.NewCategory     `json:"categories"`
 }
 
 type UpdateCategoryRequest struct {
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Color       string  `json:"color"`
-	GroupID     *string `json:"group_id"`
+	Name             string  `json:"name"`
+	Description      string  `json:"description"`
+	Color            string  `json:"color"`
+	GroupID          *string `json:"group_id"`
+	Classification   string  `json:"classification"`    // essential, discretionary, savings, debt
+	OverspendingMode string  `json:"overspending_mode"` // auto, cash, credit
 }
 
 func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
@@ -36,7 +46,7 @@ func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	category, err := h.categoryService.CreateCategory(r.Context(), req.Name, req.Description, req.Color, req.GroupID)
+	category, err := h.categoryService.CreateCategory(r.Context(), req.Name, req.Description, req.Color, req.GroupID, domain.CategoryClassification(req.Classification), domain.OverspendingMode(req.OverspendingMode))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -47,6 +57,26 @@ func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(category)
 }
 
+// CreateCategories handles POST /api/categories/bulk
+// Creates every category in the request, and optionally a group for them, or none of them
+func (h *CategoryHandler) CreateCategories(w http.ResponseWriter, r *http.Request) {
+	var req CreateCategoriesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.categoryService.CreateCategories(r.Context(), req.Group, req.Categories)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
 func (h *CategoryHandler) GetCategory(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	if id == "" {
@@ -88,7 +118,7 @@ func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	category, err := h.categoryService.UpdateCategory(r.Context(), id, req.Name, req.Description, req.Color, req.GroupID)
+	category, err := h.categoryService.UpdateCategory(r.Context(), id, req.Name, req.Description, req.Color, req.GroupID, domain.CategoryClassification(req.Classification), domain.OverspendingMode(req.OverspendingMode))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
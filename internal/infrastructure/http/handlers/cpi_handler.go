@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/billybbuffum/budget/internal/infrastructure/http/validators"
+)
+
+type CPIHandler struct {
+	cpiService *application.CPIService
+}
+
+func NewCPIHandler(cpiService *application.CPIService) *CPIHandler {
+	return &CPIHandler{cpiService: cpiService}
+}
+
+type FetchCPIRequest struct {
+	StartPeriod string `json:"start_period"`
+	EndPeriod   string `json:"end_period"`
+}
+
+func (h *CPIHandler) ListCPI(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.cpiService.ListCPI(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+// UploadCPI handles POST /api/cpi
+// Accepts a JSON array of {"period","value"} or a text/csv body of "period,value" rows
+func (h *CPIHandler) UploadCPI(w http.ResponseWriter, r *http.Request) {
+	var entries []*domain.CPIEntry
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "text/csv") {
+		parsed, err := h.cpiService.ParseCPICSV(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		entries = parsed
+	} else if err := json.NewDecoder(r.Body).Decode(&entries); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	count, err := h.cpiService.UploadCPI(r.Context(), entries)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"saved": count})
+}
+
+// FetchCPI handles POST /api/cpi/fetch
+// Pulls CPI values for the requested range from the configured provider
+func (h *CPIHandler) FetchCPI(w http.ResponseWriter, r *http.Request) {
+	var req FetchCPIRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidatePeriodFormat(req.StartPeriod); err != nil {
+		http.Error(w, "start_period: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidatePeriodFormat(req.EndPeriod); err != nil {
+		http.Error(w, "end_period: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	count, err := h.cpiService.FetchCPI(r.Context(), req.StartPeriod, req.EndPeriod)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"saved": count})
+}
+
+func (h *CPIHandler) DeleteCPI(w http.ResponseWriter, r *http.Request) {
+	period := r.PathValue("period")
+
+	if err := h.cpiService.DeleteCPI(r.Context(), period); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type mockCPIRepository struct {
+	entries map[string]*domain.CPIEntry
+}
+
+func (m *mockCPIRepository) Upsert(ctx context.Context, entry *domain.CPIEntry) error {
+	m.entries[entry.Period] = entry
+	return nil
+}
+
+func (m *mockCPIRepository) List(ctx context.Context) ([]*domain.CPIEntry, error) {
+	return nil, nil
+}
+
+func (m *mockCPIRepository) Delete(ctx context.Context, period string) error {
+	delete(m.entries, period)
+	return nil
+}
+
+func TestCPIHandler_UploadCPI(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		saved       map[string]float64
+	}{
+		{
+			name:        "JSON array",
+			contentType: "application/json",
+			body:        `[{"period":"2025-01","value":310.5},{"period":"2025-02","value":311}]`,
+			status:      http.StatusOK,
+			saved:       map[string]float64{"2025-01": 310.5, "2025-02": 311},
+		},
+		{
+			name:        "CSV with header",
+			contentType: "text/csv; charset=utf-8",
+			body:        "period,value\n2025-01,310.5\n",
+			status:      http.StatusOK,
+			saved:       map[string]float64{"2025-01": 310.5},
+		},
+		{
+			name:        "malformed JSON",
+			contentType: "application/json",
+			body:        `{"period":"2025-01"`,
+			status:      http.StatusBadRequest,
+		},
+		{
+			name:        "malformed CSV",
+			contentType: "text/csv",
+			body:        "2025-01,310.5\n2025-02,high\n",
+			status:      http.StatusBadRequest,
+		},
+		{
+			name:        "invalid period",
+			contentType: "application/json",
+			body:        `[{"period":"January","value":310.5}]`,
+			status:      http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockCPIRepository{entries: make(map[string]*domain.CPIEntry)}
+			handler := NewCPIHandler(application.NewCPIService(repo, nil))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/cpi", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			handler.UploadCPI(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if len(repo.entries) != len(tt.saved) {
+				t.Fatalf("expected %d entries saved, got %d", len(tt.saved), len(repo.entries))
+			}
+			for period, value := range tt.saved {
+				if entry := repo.entries[period]; entry == nil || entry.Value != value || entry.Source != "manual" {
+					t.Errorf("expected %s saved as %v, got %+v", period, value, entry)
+				}
+			}
+		})
+	}
+}
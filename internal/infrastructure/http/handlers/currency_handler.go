@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// GetCurrency handles GET /api/currency
+// Reports the currency the budget's amounts are kept in, so clients know how many decimal
+// places its minor units have when showing and entering amounts
+func GetCurrency(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(domain.BudgetCurrency())
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/billybbuffum/budget/internal/application"
+)
+
+type DiagnosticsHandler struct {
+	diagnosticsService *application.DiagnosticsService
+}
+
+func NewDiagnosticsHandler(diagnosticsService *application.DiagnosticsService) *DiagnosticsHandler {
+	return &DiagnosticsHandler{diagnosticsService: diagnosticsService}
+}
+
+// GetDiagnostics handles GET /api/admin/diagnostics
+// Checks the Ready to Assign totals kept by the database against a full recalculation.
+func (h *DiagnosticsHandler) GetDiagnostics(w http.ResponseWriter, r *http.Request) {
+	diagnostics, err := h.diagnosticsService.Run(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(diagnostics)
+}
+
+// RebuildReadyToAssign handles POST /api/admin/diagnostics/ready-to-assign/rebuild
+func (h *DiagnosticsHandler) RebuildReadyToAssign(w http.ResponseWriter, r *http.Request) {
+	diagnostics, err := h.diagnosticsService.RebuildReadyToAssign(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(diagnostics)
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/infrastructure/http/validators"
+)
+
+type DigestHandler struct {
+	digestService *application.DigestService
+}
+
+func NewDigestHandler(digestService *application.DigestService) *DigestHandler {
+	return &DigestHandler{digestService: digestService}
+}
+
+// GetDigest handles GET /api/digest?period=YYYY-MM
+// Shows what the weekly budget digest would list; the period defaults to the current month
+func (h *DigestHandler) GetDigest(w http.ResponseWriter, r *http.Request) {
+	period := r.URL.Query().Get("period")
+	if period == "" {
+		period = time.Now().Format("2006-01")
+	} else if err := validators.ValidatePeriodFormat(period); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	digest, err := h.digestService.BuildDigest(r.Context(), period)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(digest)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type ExportHandler struct {
+	exportService *application.ExportService
+}
+
+func NewExportHandler(exportService *application.ExportService) *ExportHandler {
+	return &ExportHandler{exportService: exportService}
+}
+
+// Export handles GET /api/export
+// The whole budget is sent as a download, ready to import into another instance.
+func (h *ExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	export, err := h.exportService.Export(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="budget-export.json"`)
+	json.NewEncoder(w).Encode(export)
+}
+
+// Import handles POST /api/export/import with an export as the body
+func (h *ExportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	var export application.BudgetExport
+	if err := json.NewDecoder(r.Body).Decode(&export); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.exportService.Import(r.Context(), &export)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, application.ErrBudgetNotEmpty) {
+			status = http.StatusConflict
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+// ImportYNAB handles POST /api/import/ynab
+// Form fields: file (YNAB's register export), optionally budget_file (its budget export,
+// for allocations) and account_types, a JSON object of account names to types, e.g.
+// {"Visa": "credit"}.
+func (h *ExportHandler) ImportYNAB(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		http.Error(w, "file too large (max 10MB)", http.StatusBadRequest)
+		return
+	}
+
+	register, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "failed to read uploaded file", http.StatusBadRequest)
+		return
+	}
+	defer register.Close()
+
+	var budget io.Reader
+	if file, _, err := r.FormFile("budget_file"); err == nil {
+		defer file.Close()
+		budget = file
+	}
+
+	var accountTypes map[string]domain.AccountType
+	if value := r.FormValue("account_types"); value != "" {
+		if err := json.Unmarshal([]byte(value), &accountTypes); err != nil {
+			http.Error(w, "account_types must be a JSON object of account names to types", http.StatusBadRequest)
+			return
+		}
+	}
+
+	result, err := h.exportService.ImportYNAB(r.Context(), register, budget, accountTypes)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, application.ErrBudgetNotEmpty) {
+			status = http.StatusConflict
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type FeatureFlagHandler struct {
+	featureFlagService *application.FeatureFlagService
+}
+
+func NewFeatureFlagHandler(featureFlagService *application.FeatureFlagService) *FeatureFlagHandler {
+	return &FeatureFlagHandler{featureFlagService: featureFlagService}
+}
+
+type SetFeatureFlagRequest struct {
+	Enabled *bool `json:"enabled"` // null clears the override
+}
+
+// GetEnabled handles GET /api/features and reports which features are on
+func (h *FeatureFlagHandler) GetEnabled(w http.ResponseWriter, r *http.Request) {
+	flags, err := h.featureFlagService.List(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	enabled := make(map[domain.FeatureFlag]bool, len(flags))
+	for _, flag := range flags {
+		enabled[flag.Flag] = flag.Enabled
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(enabled)
+}
+
+// ListFlags handles GET /api/admin/flags
+func (h *FeatureFlagHandler) ListFlags(w http.ResponseWriter, r *http.Request) {
+	flags, err := h.featureFlagService.List(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(flags)
+}
+
+// SetFlag handles PUT /api/admin/flags/{flag}
+func (h *FeatureFlagHandler) SetFlag(w http.ResponseWriter, r *http.Request) {
+	var req SetFeatureFlagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	flag, err := h.featureFlagService.SetOverride(r.Context(), domain.FeatureFlag(r.PathValue("flag")), req.Enabled)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(flag)
+}
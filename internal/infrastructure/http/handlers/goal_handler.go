@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type GoalHandler struct {
+	goalService *application.GoalService
+}
+
+func NewGoalHandler(goalService *application.GoalService) *GoalHandler {
+	return &GoalHandler{goalService: goalService}
+}
+
+type CreateGoalRequest struct {
+	CategoryID   string             `json:"category_id"`
+	TargetAmount int64              `json:"target_amount"`         // in cents
+	TargetDate   *string            `json:"target_date,omitempty"` // YYYY-MM
+	Cadence      domain.GoalCadence `json:"cadence"`               // once (default), monthly or yearly
+}
+
+type UpdateGoalRequest struct {
+	TargetAmount *int64             `json:"target_amount,omitempty"`
+	TargetDate   *string            `json:"target_date,omitempty"` // "" removes the date
+	Cadence      domain.GoalCadence `json:"cadence,omitempty"`
+}
+
+func (h *GoalHandler) CreateGoal(w http.ResponseWriter, r *http.Request) {
+	var req CreateGoalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	goal, err := h.goalService.CreateGoal(r.Context(), req.CategoryID, req.TargetAmount, req.TargetDate, req.Cadence)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrCategoryNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, domain.ErrGoalExists):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(goal)
+}
+
+func (h *GoalHandler) GetGoal(w http.ResponseWriter, r *http.Request) {
+	goal, err := h.goalService.GetGoal(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeGoalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(goal)
+}
+
+func (h *GoalHandler) ListGoals(w http.ResponseWriter, r *http.Request) {
+	goals, err := h.goalService.ListGoals(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if goals == nil {
+		goals = []*domain.Goal{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(goals)
+}
+
+func (h *GoalHandler) UpdateGoal(w http.ResponseWriter, r *http.Request) {
+	var req UpdateGoalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	goal, err := h.goalService.UpdateGoal(r.Context(), r.PathValue("id"), req.TargetAmount, req.TargetDate, req.Cadence)
+	if err != nil {
+		if errors.Is(err, domain.ErrGoalNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(goal)
+}
+
+func (h *GoalHandler) DeleteGoal(w http.ResponseWriter, r *http.Request) {
+	if err := h.goalService.DeleteGoal(r.Context(), r.PathValue("id")); err != nil {
+		writeGoalError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeGoalError(w http.ResponseWriter, err error) {
+	if errors.Is(err, domain.ErrGoalNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
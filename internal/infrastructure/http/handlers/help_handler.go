@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/infrastructure/http/validators"
+)
+
+type HelpHandler struct {
+	helpService *application.HelpService
+}
+
+func NewHelpHandler(helpService *application.HelpService) *HelpHandler {
+	return &HelpHandler{helpService: helpService}
+}
+
+// GetConcepts handles GET /api/help/concepts?period=YYYY-MM
+// Explains the budget's concepts with examples worked from its own numbers; the period
+// defaults to the current month
+func (h *HelpHandler) GetConcepts(w http.ResponseWriter, r *http.Request) {
+	period := r.URL.Query().Get("period")
+	if period == "" {
+		period = time.Now().Format("2006-01")
+	} else if err := validators.ValidatePeriodFormat(period); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	concepts, err := h.helpService.Concepts(r.Context(), period)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(concepts)
+}
@@ -2,13 +2,17 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/billybbuffum/budget/internal/infrastructure/csv"
 )
 
 type ImportHandler struct {
@@ -23,7 +27,9 @@ const (
 	maxUploadSize = 10 << 20 // 10 MB
 )
 
-// ImportTransactions handles OFX/QFX file upload and import
+// ImportTransactions handles OFX/QFX/QIF file upload and import
+// The format is detected from the file's contents rather than its extension. An optional
+// lookback_days form field sets how far back an OFX file is imported from, or "all".
 func (h *ImportHandler) ImportTransactions(w http.ResponseWriter, r *http.Request) {
 	// Parse multipart form with size limit
 	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
@@ -38,6 +44,12 @@ func (h *ImportHandler) ImportTransactions(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	opts, err := importOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Get uploaded file
 	file, header, err := r.FormFile("file")
 	if err != nil {
@@ -48,8 +60,8 @@ func (h *ImportHandler) ImportTransactions(w http.ResponseWriter, r *http.Reques
 
 	// Validate file extension
 	ext := strings.ToLower(filepath.Ext(header.Filename))
-	if ext != ".ofx" && ext != ".qfx" {
-		http.Error(w, "invalid file type, must be .ofx or .qfx", http.StatusBadRequest)
+	if ext != ".ofx" && ext != ".qfx" && ext != ".qif" {
+		http.Error(w, "invalid file type, must be .ofx, .qfx or .qif", http.StatusBadRequest)
 		return
 	}
 
@@ -60,20 +72,28 @@ func (h *ImportHandler) ImportTransactions(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	format, err := application.DetectImportFormat(fileContent)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Reset file reader for parsing
 	reader := strings.NewReader(string(fileContent))
 
 	// Validate OFX file
-	if err := h.importService.ValidateOFXFile(reader); err != nil {
-		http.Error(w, fmt.Sprintf("invalid OFX file: %v", err), http.StatusBadRequest)
-		return
-	}
+	if format == application.ImportFormatOFX {
+		if err := h.importService.ValidateOFXFile(reader); err != nil {
+			http.Error(w, fmt.Sprintf("invalid OFX file: %v", err), http.StatusBadRequest)
+			return
+		}
 
-	// Reset reader for import
-	reader.Seek(0, io.SeekStart)
+		// Reset reader for import
+		reader.Seek(0, io.SeekStart)
+	}
 
 	// Import transactions
-	result, err := h.importService.ImportFromOFX(r.Context(), accountID, reader)
+	result, err := h.importService.ImportWithOptions(r.Context(), accountID, reader, opts)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("import failed: %v", err), http.StatusInternalServerError)
 		return
@@ -84,3 +104,298 @@ func (h *ImportHandler) ImportTransactions(w http.ResponseWriter, r *http.Reques
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(result)
 }
+
+// ImportCSV handles CSV file upload and import
+// Form fields: account_id, file, and optionally date_column, amount_column,
+// description_column, fitid_column, balance_column (header names or 1-based numbers),
+// date_format (e.g. MM/DD/YYYY; detected when empty) and invert_amounts.
+func (h *ImportHandler) ImportCSV(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		http.Error(w, "file too large (max 10MB)", http.StatusBadRequest)
+		return
+	}
+
+	accountID := r.FormValue("account_id")
+	if accountID == "" {
+		http.Error(w, "account_id is required", http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "failed to read uploaded file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	if ext := strings.ToLower(filepath.Ext(header.Filename)); ext != ".csv" && ext != ".txt" {
+		http.Error(w, "invalid file type, must be .csv", http.StatusBadRequest)
+		return
+	}
+
+	mapping, err := csvMapping(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.importService.ImportFromCSV(r.Context(), accountID, file, mapping)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("import failed: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
+
+// importOptions reads the lookback_days form field: a number of days, or "all"
+func importOptions(r *http.Request) (application.ImportOptions, error) {
+	var opts application.ImportOptions
+	switch value := r.FormValue("lookback_days"); value {
+	case "":
+	case "all":
+		opts.LookbackDays = application.ImportAllHistory
+	default:
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 1 {
+			return opts, fmt.Errorf("lookback_days must be a positive number of days or \"all\"")
+		}
+		opts.LookbackDays = days
+	}
+	return opts, nil
+}
+
+// csvMapping reads a CSV column mapping from an upload's form fields
+func csvMapping(r *http.Request) (csv.Mapping, error) {
+	mapping := csv.Mapping{
+		Date:        r.FormValue("date_column"),
+		Amount:      r.FormValue("amount_column"),
+		Description: r.FormValue("description_column"),
+		FitID:       r.FormValue("fitid_column"),
+		Balance:     r.FormValue("balance_column"),
+		DateFormat:  r.FormValue("date_format"),
+	}
+	if value := r.FormValue("invert_amounts"); value != "" {
+		invert, err := strconv.ParseBool(value)
+		if err != nil {
+			return mapping, fmt.Errorf("invert_amounts must be true or false")
+		}
+		mapping.InvertAmounts = invert
+	}
+	return mapping, nil
+}
+
+// StageImport handles POST /api/imports
+// Form fields: account_id and file. OFX, QFX and QIF files are detected from their
+// contents and take the same lookback_days field as ImportTransactions; .csv files take
+// the same mapping fields as ImportCSV. Nothing is saved to the ledger until the import
+// is committed.
+func (h *ImportHandler) StageImport(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		http.Error(w, "file too large (max 10MB)", http.StatusBadRequest)
+		return
+	}
+
+	accountID := r.FormValue("account_id")
+	if accountID == "" {
+		http.Error(w, "account_id is required", http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "failed to read uploaded file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	var review *application.ImportReview
+	switch strings.ToLower(filepath.Ext(header.Filename)) {
+	case ".ofx", ".qfx", ".qif":
+		opts, optsErr := importOptions(r)
+		if optsErr != nil {
+			http.Error(w, optsErr.Error(), http.StatusBadRequest)
+			return
+		}
+		review, err = h.importService.StageWithOptions(r.Context(), accountID, file, opts)
+	case ".csv", ".txt":
+		mapping, mappingErr := csvMapping(r)
+		if mappingErr != nil {
+			http.Error(w, mappingErr.Error(), http.StatusBadRequest)
+			return
+		}
+		review, err = h.importService.StageCSV(r.Context(), accountID, file, mapping)
+	default:
+		http.Error(w, "invalid file type, must be .ofx, .qfx, .qif or .csv", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("import failed: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(review)
+}
+
+// ListImports handles GET /api/imports?status=staged|committed|undone
+func (h *ImportHandler) ListImports(w http.ResponseWriter, r *http.Request) {
+	files, err := h.importService.ListImports(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(files)
+}
+
+// GetImport handles GET /api/imports/{id}
+func (h *ImportHandler) GetImport(w http.ResponseWriter, r *http.Request) {
+	review, err := h.importService.GetImport(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeImportError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
+
+type UpdateImportRowRequest struct {
+	CategoryID *string `json:"category_id"` // Empty or null to leave uncategorized
+	PayeeID    *string `json:"payee_id"`
+	Excluded   bool    `json:"excluded"`
+}
+
+// UpdateImportRow handles PUT /api/imports/{id}/rows/{rowId}
+func (h *ImportHandler) UpdateImportRow(w http.ResponseWriter, r *http.Request) {
+	var req UpdateImportRowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	row, err := h.importService.UpdateImportRow(r.Context(), r.PathValue("id"), r.PathValue("rowId"), req.CategoryID, req.PayeeID, req.Excluded)
+	if err != nil {
+		writeImportError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(row)
+}
+
+// CommitImport handles POST /api/imports/{id}/commit
+func (h *ImportHandler) CommitImport(w http.ResponseWriter, r *http.Request) {
+	result, err := h.importService.CommitImport(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeImportError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// UndoImport handles POST /api/imports/{id}/undo
+func (h *ImportHandler) UndoImport(w http.ResponseWriter, r *http.Request) {
+	file, err := h.importService.UndoImport(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeImportError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(file)
+}
+
+// DeleteImport handles DELETE /api/imports/{id}
+// Discards a staged import, or takes a committed one back out of the ledger and removes it
+func (h *ImportHandler) DeleteImport(w http.ResponseWriter, r *http.Request) {
+	if err := h.importService.DeleteImport(r.Context(), r.PathValue("id")); err != nil {
+		writeImportError(w, err, http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeImportError maps known errors to their status, and anything else to status
+func writeImportError(w http.ResponseWriter, err error, status int) {
+	switch {
+	case errors.Is(err, domain.ErrImportNotFound), errors.Is(err, domain.ErrImportRowNotFound),
+		errors.Is(err, domain.ErrCategoryNotFound), errors.Is(err, domain.ErrPayeeNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrImportNotStaged):
+		status = http.StatusConflict
+	}
+	http.Error(w, err.Error(), status)
+}
+
+// GetMatchingSettings handles GET /api/imports/settings
+func (h *ImportHandler) GetMatchingSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.importService.GetMatchingSettings(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// UpdateMatchingSettings handles PUT /api/imports/settings
+// Turns merging imported transactions into matching manual entries on or off
+func (h *ImportHandler) UpdateMatchingSettings(w http.ResponseWriter, r *http.Request) {
+	var req domain.ImportMatchingSettings
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.importService.UpdateMatchingSettings(r.Context(), &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// BackfillFitIDs handles POST /api/admin/accounts/{id}/backfill-fitids
+// Form fields: file, an OFX or QFX file from the account's bank, and optionally dry_run
+// to see what would be matched without saving it.
+func (h *ImportHandler) BackfillFitIDs(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		http.Error(w, "file too large (max 10MB)", http.StatusBadRequest)
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "failed to read uploaded file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	var dryRun bool
+	if value := r.FormValue("dry_run"); value != "" {
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			http.Error(w, "dry_run must be true or false", http.StatusBadRequest)
+			return
+		}
+	}
+
+	result, err := h.importService.BackfillFitIDs(r.Context(), r.PathValue("id"), file, dryRun)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
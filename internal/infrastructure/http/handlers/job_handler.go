@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type JobHandler struct {
+	jobService *application.JobService
+}
+
+func NewJobHandler(jobService *application.JobService) *JobHandler {
+	return &JobHandler{jobService: jobService}
+}
+
+// ListJobs handles GET /api/jobs
+// Queued, running and recently finished background jobs, newest first
+func (h *JobHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.jobService.List())
+}
+
+// GetJob handles GET /api/jobs/{id}
+func (h *JobHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	job, err := h.jobService.Get(r.PathValue("id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrJobNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// streamBufferSize is how much of a streamed response is buffered before it's written out
+const streamBufferSize = 32 * 1024
+
+// writeJSONArray writes items as a JSON array, encoding one item at a time
+// Encoding the whole slice at once builds the entire response in memory next to the
+// items themselves, which doubles the memory a large list takes. Items are still loaded
+// up front: streaming rows straight from the database would hold SQLite's read lock, and
+// so block writes, for as long as a slow client takes to read the response.
+// An empty or nil slice is written as [].
+func writeJSONArray[T any](w http.ResponseWriter, items []T) {
+	w.Header().Set("Content-Type", "application/json")
+	buf := bufio.NewWriterSize(w, streamBufferSize)
+	encoder := json.NewEncoder(buf)
+
+	buf.WriteByte('[')
+	for i, item := range items {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		// The status is already sent, so all that can be done is to stop
+		if err := encoder.Encode(item); err != nil {
+			log.Printf("ERROR: Failed to encode list item %d: %v", i, err)
+			return
+		}
+	}
+	buf.WriteString("]\n")
+	if err := buf.Flush(); err != nil {
+		log.Printf("WARNING: Failed to write list response: %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+func TestWriteJSONArray(t *testing.T) {
+	var transactions []*domain.Transaction
+	for i := 0; i < 5000; i++ {
+		transactions = append(transactions, &domain.Transaction{ID: strings.Repeat("x", i%40), Amount: int64(i)})
+	}
+
+	rec := httptest.NewRecorder()
+	writeJSONArray(rec, transactions)
+	if rec.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("expected a JSON content type, got %q", rec.Header().Get("Content-Type"))
+	}
+	var decoded []*domain.Transaction
+	if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("expected a valid JSON array: %v", err)
+	}
+	if len(decoded) != len(transactions) || decoded[4999].Amount != 4999 {
+		t.Errorf("expected every item in order, got %d items", len(decoded))
+	}
+
+	rec = httptest.NewRecorder()
+	writeJSONArray[*domain.Transaction](rec, nil)
+	if body := strings.TrimSpace(rec.Body.String()); body != "[]" {
+		t.Errorf("expected an empty list to be written as [], got %q", body)
+	}
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type LoanHandler struct {
+	loanService *application.LoanService
+}
+
+func NewLoanHandler(loanService *application.LoanService) *LoanHandler {
+	return &LoanHandler{loanService: loanService}
+}
+
+type SetLoanTermsRequest struct {
+	MonthlyPayment     int64   `json:"monthly_payment"`                // in cents, interest included
+	InterestCategoryID *string `json:"interest_category_id,omitempty"` // Category interest is spent from; omitted or empty clears it
+}
+
+type RecordLoanPaymentRequest struct {
+	FromAccountID string    `json:"from_account_id"`
+	Amount        int64     `json:"amount"`                // in cents, interest included
+	Interest      *int64    `json:"interest,omitempty"`    // in cents, from the lender's statement; omitted estimates a month's interest
+	CategoryID    *string   `json:"category_id,omitempty"` // Optional: the budget the principal came out of
+	Date          time.Time `json:"date"`
+}
+
+// SetLoanTerms handles PUT /api/accounts/{id}/loan
+func (h *LoanHandler) SetLoanTerms(w http.ResponseWriter, r *http.Request) {
+	var req SetLoanTermsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	account, err := h.loanService.SetTerms(r.Context(), r.PathValue("id"), req.MonthlyPayment, req.InterestCategoryID)
+	if err != nil {
+		writeLoanError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(account)
+}
+
+// GetAmortization handles GET /api/accounts/{id}/amortization
+// Optional query parameter start=YYYY-MM, the month of the first payment; defaults to next month
+func (h *LoanHandler) GetAmortization(w http.ResponseWriter, r *http.Request) {
+	start := time.Now().AddDate(0, 1, 0)
+	if value := r.URL.Query().Get("start"); value != "" {
+		parsed, err := time.Parse("2006-01", value)
+		if err != nil {
+			http.Error(w, "invalid start, expected YYYY-MM", http.StatusBadRequest)
+			return
+		}
+		start = parsed
+	}
+
+	schedule, err := h.loanService.Amortization(r.Context(), r.PathValue("id"), start)
+	if err != nil {
+		writeLoanError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schedule)
+}
+
+// RecordLoanPayment handles POST /api/accounts/{id}/loan-payments
+// Splits the payment into an interest expense and a principal transfer to the loan
+func (h *LoanHandler) RecordLoanPayment(w http.ResponseWriter, r *http.Request) {
+	var req RecordLoanPaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.FromAccountID == "" {
+		http.Error(w, "from_account_id is required", http.StatusBadRequest)
+		return
+	}
+
+	payment, err := h.loanService.RecordPayment(r.Context(), r.PathValue("id"), req.FromAccountID, req.Amount, req.Interest, req.CategoryID, req.Date)
+	if err != nil {
+		writeLoanError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(payment)
+}
+
+// writeLoanError writes a loan service error with its status
+func writeLoanError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrAccountNotFound), errors.Is(err, domain.ErrCategoryNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, domain.ErrNotLoanAccount), errors.Is(err, domain.ErrLoanTermsNotSet), errors.Is(err, domain.ErrLoanNeverRepaid):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/billybbuffum/budget/internal/application"
+)
+
+type MaintenanceHandler struct {
+	maintenanceService *application.MaintenanceService
+}
+
+func NewMaintenanceHandler(maintenanceService *application.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{maintenanceService: maintenanceService}
+}
+
+type RunMaintenanceRequest struct {
+	Vacuum bool `json:"vacuum"` // Also vacuum; blocks writes while it runs
+}
+
+// RunMaintenance handles POST /api/admin/maintenance
+// The body is optional; without it the database is only optimized
+func (h *MaintenanceHandler) RunMaintenance(w http.ResponseWriter, r *http.Request) {
+	var req RunMaintenanceRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	report, err := h.maintenanceService.Run(r.Context(), req.Vacuum)
+	if err != nil {
+		if errors.Is(err, application.ErrMaintenanceRunning) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type MQTTHandler struct {
+	mqttService *application.MQTTService
+}
+
+func NewMQTTHandler(mqttService *application.MQTTService) *MQTTHandler {
+	return &MQTTHandler{mqttService: mqttService}
+}
+
+func (h *MQTTHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.mqttService.GetSettings(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+func (h *MQTTHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var req domain.MQTTSettings
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.mqttService.UpdateSettings(r.Context(), &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type PayeeHandler struct {
+	payeeService *application.PayeeService
+}
+
+func NewPayeeHandler(payeeService *application.PayeeService) *PayeeHandler {
+	return &PayeeHandler{payeeService: payeeService}
+}
+
+type CreatePayeeRuleRequest struct {
+	Pattern    string                `json:"pattern"`              // Optional when there's an account or amount condition
+	MatchType  domain.PayeeMatchType `json:"match_type"`           // contains (default), exact or regex
+	AccountID  *string               `json:"account_id,omitempty"` // Only match transactions in this account
+	MinAmount  *int64                `json:"min_amount,omitempty"` // In cents, ignoring sign
+	MaxAmount  *int64                `json:"max_amount,omitempty"`
+	PayeeName  string                `json:"payee_name,omitempty"` // Created if it doesn't exist
+	CategoryID *string               `json:"category_id,omitempty"`
+	Note       *string               `json:"note,omitempty"` // Memo put on matching transactions
+	Priority   int                   `json:"priority"`       // Lower runs first
+}
+
+type UpdatePayeeRuleRequest struct {
+	Pattern    *string               `json:"pattern,omitempty"`
+	MatchType  domain.PayeeMatchType `json:"match_type,omitempty"`
+	AccountID  *string               `json:"account_id,omitempty"`  // "" removes the condition
+	MinAmount  *int64                `json:"min_amount,omitempty"`  // 0 removes the bound
+	MaxAmount  *int64                `json:"max_amount,omitempty"`  // 0 removes the bound
+	PayeeName  *string               `json:"payee_name,omitempty"`  // "" removes the payee
+	CategoryID *string               `json:"category_id,omitempty"` // "" removes the category
+	Note       *string               `json:"note,omitempty"`        // "" removes the memo
+	Priority   *int                  `json:"priority,omitempty"`
+}
+
+type RunPayeeRulesResponse struct {
+	JobID string `json:"job_id"` // Follow it at /api/jobs/{id}
+}
+
+func (h *PayeeHandler) ListPayees(w http.ResponseWriter, r *http.Request) {
+	payees, err := h.payeeService.ListPayees(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONArray(w, payees)
+}
+
+func (h *PayeeHandler) CreatePayeeRule(w http.ResponseWriter, r *http.Request) {
+	var req CreatePayeeRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.payeeService.CreateRule(r.Context(), req.Pattern, req.MatchType, req.AccountID, req.MinAmount, req.MaxAmount,
+		req.PayeeName, req.CategoryID, req.Note, req.Priority)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rule)
+}
+
+func (h *PayeeHandler) GetPayeeRule(w http.ResponseWriter, r *http.Request) {
+	rule, err := h.payeeService.GetRule(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writePayeeRuleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+func (h *PayeeHandler) ListPayeeRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.payeeService.ListRules(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if rules == nil {
+		rules = []*domain.PayeeRule{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rules)
+}
+
+func (h *PayeeHandler) UpdatePayeeRule(w http.ResponseWriter, r *http.Request) {
+	var req UpdatePayeeRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.payeeService.UpdateRule(r.Context(), r.PathValue("id"), req.Pattern, req.MatchType, req.AccountID, req.MinAmount, req.MaxAmount,
+		req.PayeeName, req.CategoryID, req.Note, req.Priority)
+	if err != nil {
+		if errors.Is(err, domain.ErrPayeeRuleNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+func (h *PayeeHandler) DeletePayeeRule(w http.ResponseWriter, r *http.Request) {
+	if err := h.payeeService.DeleteRule(r.Context(), r.PathValue("id")); err != nil {
+		writePayeeRuleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RunPayeeRules applies every rule to uncategorized transactions in the background
+func (h *PayeeHandler) RunPayeeRules(w http.ResponseWriter, r *http.Request) {
+	jobID, err := h.payeeService.RunRules(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(RunPayeeRulesResponse{JobID: jobID})
+}
+
+func writePayeeRuleError(w http.ResponseWriter, err error) {
+	if errors.Is(err, domain.ErrPayeeRuleNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
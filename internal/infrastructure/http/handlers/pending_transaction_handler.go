@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type PendingTransactionHandler struct {
+	pendingService *application.PendingTransactionService
+	webhookSecret  string // Shared secret required by the inbound email webhook; empty disables it
+}
+
+func NewPendingTransactionHandler(pendingService *application.PendingTransactionService, webhookSecret string) *PendingTransactionHandler {
+	return &PendingTransactionHandler{
+		pendingService: pendingService,
+		webhookSecret:  webhookSecret,
+	}
+}
+
+type InboundEmailRequest struct {
+	From      string  `json:"from"`
+	Subject   string  `json:"subject"`
+	Text      string  `json:"text"`
+	HTML      string  `json:"html"`
+	AccountID *string `json:"account_id,omitempty"`
+}
+
+type ApprovePendingTransactionRequest struct {
+	AccountID  *string `json:"account_id,omitempty"`  // Overrides the suggested account
+	CategoryID *string `json:"category_id,omitempty"` // Required for outflows
+}
+
+// ReceiveEmail handles POST /api/integrations/email
+// Accepts JSON ({from, subject, text, html, account_id}) or the form posts sent by
+// mail services (sender/from, subject, body-plain/text, body-html/html).
+// The shared secret is read from the X-Webhook-Secret header or the token query parameter.
+func (h *PendingTransactionHandler) ReceiveEmail(w http.ResponseWriter, r *http.Request) {
+	if h.webhookSecret == "" {
+		http.Error(w, "email import is not configured", http.StatusNotFound)
+		return
+	}
+	secret := r.Header.Get("X-Webhook-Secret")
+	if secret == "" {
+		secret = r.URL.Query().Get("token")
+	}
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(h.webhookSecret)) != 1 {
+		http.Error(w, "invalid webhook secret", http.StatusUnauthorized)
+		return
+	}
+
+	var req InboundEmailRequest
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+	} else {
+		if err := r.ParseMultipartForm(maxUploadSize); err != nil && err != http.ErrNotMultipart {
+			http.Error(w, "invalid form body", http.StatusBadRequest)
+			return
+		}
+		req.From = firstFormValue(r, "sender", "from")
+		req.Subject = r.FormValue("subject")
+		req.Text = firstFormValue(r, "body-plain", "text")
+		req.HTML = firstFormValue(r, "body-html", "html")
+	}
+	if req.AccountID == nil {
+		if accountID := r.URL.Query().Get("account_id"); accountID != "" {
+			req.AccountID = &accountID
+		}
+	}
+
+	body := req.Text
+	if body == "" {
+		body = req.HTML
+	}
+
+	pending, err := h.pendingService.IngestEmail(r.Context(), &application.InboundEmail{
+		From:       req.From,
+		Subject:    req.Subject,
+		Body:       body,
+		ReceivedAt: time.Now(),
+	}, req.AccountID)
+	if err != nil {
+		// Unparseable messages are a normal outcome for forwarded mail; 422 tells the
+		// mail service not to retry
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(pending)
+}
+
+// ListPendingTransactions handles GET /api/pending-transactions?status=pending
+func (h *PendingTransactionHandler) ListPendingTransactions(w http.ResponseWriter, r *http.Request) {
+	status := domain.PendingTransactionStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "", domain.PendingStatusPending, domain.PendingStatusApproved, domain.PendingStatusRejected:
+	default:
+		http.Error(w, "status must be pending, approved or rejected", http.StatusBadRequest)
+		return
+	}
+
+	pendings, err := h.pendingService.ListPendingTransactions(r.Context(), status)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pendings)
+}
+
+func (h *PendingTransactionHandler) GetPendingTransaction(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	pending, err := h.pendingService.GetPendingTransaction(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pending)
+}
+
+// ApprovePendingTransaction handles POST /api/pending-transactions/{id}/approve
+func (h *PendingTransactionHandler) ApprovePendingTransaction(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	var req ApprovePendingTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	transaction, err := h.pendingService.ApprovePendingTransaction(r.Context(), id, req.AccountID, req.CategoryID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(transaction)
+}
+
+// RejectPendingTransaction handles POST /api/pending-transactions/{id}/reject
+func (h *PendingTransactionHandler) RejectPendingTransaction(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	pending, err := h.pendingService.RejectPendingTransaction(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pending)
+}
+
+// firstFormValue returns the first non-empty form value among keys
+func firstFormValue(r *http.Request, keys ...string) string {
+	for _, key := range keys {
+		if v := r.FormValue(key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
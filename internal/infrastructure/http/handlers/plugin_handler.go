@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/infrastructure/http/validators"
+)
+
+type PluginHandler struct {
+	pluginService *application.PluginService
+}
+
+func NewPluginHandler(pluginService *application.PluginService) *PluginHandler {
+	return &PluginHandler{pluginService: pluginService}
+}
+
+// ListPlugins handles GET /api/plugins and lists the registered plugins with their hooks
+func (h *PluginHandler) ListPlugins(w http.ResponseWriter, r *http.Request) {
+	plugins := h.pluginService.ListPlugins()
+	if plugins == nil {
+		plugins = []*application.PluginInfo{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(plugins)
+}
+
+// RunReport handles GET /api/reports/plugins/{plugin}/{report}?period=YYYY-MM
+// Other query parameters are passed to the plugin.
+func (h *PluginHandler) RunReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	period := query.Get("period")
+	if err := validators.ValidatePeriodFormat(period); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	params := make(map[string]string)
+	for key := range query {
+		if key != "period" {
+			params[key] = query.Get(key)
+		}
+	}
+
+	report, err := h.pluginService.RunReport(r.Context(), r.PathValue("plugin"), r.PathValue("report"), period, params)
+	if err != nil {
+		if errors.Is(err, application.ErrPluginReportNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(report)
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/billybbuffum/budget/internal/infrastructure/http/validators"
+)
+
+type ReportHandler struct {
+	reportService *application.ReportService
+	cache         *application.ReportCache
+}
+
+func NewReportHandler(reportService *application.ReportService, cache *application.ReportCache) *ReportHandler {
+	return &ReportHandler{reportService: reportService, cache: cache}
+}
+
+// GetBareBonesBudget handles GET /api/reports/bare-bones?period=YYYY-MM&months=3
+// Returns the minimum monthly need if only essential and debt categories were funded
+func (h *ReportHandler) GetBareBonesBudget(w http.ResponseWriter, r *http.Request) {
+	period := r.URL.Query().Get("period")
+	if err := validators.ValidatePeriodFormat(period); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	months, err := parseTrailingMonths(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.writeReport(w, r, http.StatusInternalServerError, func() (any, error) {
+		return h.reportService.GetBareBonesBudget(r.Context(), period, months)
+	})
+}
+
+// GetEmergencyFundCoverage handles GET /api/reports/emergency-fund?period=YYYY-MM&months=3
+// Returns how many months of essential spending the configured emergency fund covers
+func (h *ReportHandler) GetEmergencyFundCoverage(w http.ResponseWriter, r *http.Request) {
+	period := r.URL.Query().Get("period")
+	if err := validators.ValidatePeriodFormat(period); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	months, err := parseTrailingMonths(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.writeReport(w, r, http.StatusInternalServerError, func() (any, error) {
+		return h.reportService.GetEmergencyFundCoverage(r.Context(), period, months)
+	})
+}
+
+func (h *ReportHandler) GetEmergencyFundSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.reportService.GetEmergencyFundSettings(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+func (h *ReportHandler) UpdateEmergencyFundSettings(w http.ResponseWriter, r *http.Request) {
+	var req domain.EmergencyFundSettings
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.reportService.UpdateEmergencyFundSettings(r.Context(), &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.cache.Invalidate() // Coverage reports use the settings
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// GetTrendReport handles GET /api/reports/trends?start=YYYY-MM&end=YYYY-MM&adjust_for_inflation=true
+// Returns monthly income and spending, optionally restated in real (CPI-adjusted) terms
+func (h *ReportHandler) GetTrendReport(w http.ResponseWriter, r *http.Request) {
+	start := r.URL.Query().Get("start")
+	if err := validators.ValidatePeriodFormat(start); err != nil {
+		http.Error(w, "start: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	end := r.URL.Query().Get("end")
+	if err := validators.ValidatePeriodFormat(end); err != nil {
+		http.Error(w, "end: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	adjust := false
+	if a := r.URL.Query().Get("adjust_for_inflation"); a != "" {
+		parsed, err := strconv.ParseBool(a)
+		if err != nil {
+			http.Error(w, "adjust_for_inflation must be true or false", http.StatusBadRequest)
+			return
+		}
+		adjust = parsed
+	}
+
+	h.writeReport(w, r, http.StatusBadRequest, func() (any, error) {
+		return h.reportService.GetTrendReport(r.Context(), start, end, adjust)
+	})
+}
+
+// writeReport serves a report from the cache, building it on a miss
+// Clients get an ETag and must revalidate each time; an unchanged report is a 304.
+// errStatus is the status used if building the report fails.
+func (h *ReportHandler) writeReport(w http.ResponseWriter, r *http.Request, errStatus int, build func() (any, error)) {
+	key := r.URL.Path + "?" + r.URL.Query().Encode()
+	report, err := h.cache.Get(r.Context(), key, build)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Set("ETag", report.ETag)
+	if match := r.Header.Get("If-None-Match"); match != "" && etagMatches(match, report.ETag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(report.Body)
+}
+
+// etagMatches reports whether an If-None-Match header lists etag (or is "*")
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTrailingMonths reads the optional "months" query parameter (default 3)
+func parseTrailingMonths(r *http.Request) (int, error) {
+	m := r.URL.Query().Get("months")
+	if m == "" {
+		return 3, nil
+	}
+	months, err := strconv.Atoi(m)
+	if err != nil || months < 1 || months > 24 {
+		return 0, fmt.Errorf("months must be between 1 and 24")
+	}
+	return months, nil
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/billybbuffum/budget/internal/application"
+)
+
+type RetentionHandler struct {
+	retentionService *application.RetentionService
+}
+
+func NewRetentionHandler(retentionService *application.RetentionService) *RetentionHandler {
+	return &RetentionHandler{retentionService: retentionService}
+}
+
+// GetReport handles GET /api/admin/retention
+// A dry run: reports what the cleanup job would remove now, without removing anything
+func (h *RetentionHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	report, err := h.retentionService.Report(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/application"
+)
+
+type ScriptHandler struct {
+	scriptService *application.ScriptService
+}
+
+func NewScriptHandler(scriptService *application.ScriptService) *ScriptHandler {
+	return &ScriptHandler{scriptService: scriptService}
+}
+
+type SetScriptRequest struct {
+	Source string `json:"source"` // Empty removes the script
+}
+
+type TestScriptRequest struct {
+	Source      string `json:"source"` // Empty tests the stored script
+	Transaction struct {
+		Description string `json:"description"`
+		Amount      int64  `json:"amount"`
+		Date        string `json:"date"` // YYYY-MM-DD, defaults to today
+	} `json:"transaction"`
+}
+
+// GetCategorizeScript handles GET /api/admin/scripts/categorize
+func (h *ScriptHandler) GetCategorizeScript(w http.ResponseWriter, r *http.Request) {
+	script, err := h.scriptService.GetCategorizeScript(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(script)
+}
+
+// SetCategorizeScript handles PUT /api/admin/scripts/categorize
+// The script is checked before it is saved.
+func (h *ScriptHandler) SetCategorizeScript(w http.ResponseWriter, r *http.Request) {
+	var req SetScriptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	script, err := h.scriptService.SetCategorizeScript(r.Context(), req.Source)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(script)
+}
+
+// TestCategorizeScript handles POST /api/admin/scripts/categorize/test
+// Runs a script against a sample transaction without saving anything
+func (h *ScriptHandler) TestCategorizeScript(w http.ResponseWriter, r *http.Request) {
+	var req TestScriptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	date := time.Now().UTC().Truncate(24 * time.Hour)
+	if req.Transaction.Date != "" {
+		parsed, err := time.Parse("2006-01-02", req.Transaction.Date)
+		if err != nil {
+			http.Error(w, "invalid date format, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		date = parsed
+	}
+
+	result, err := h.scriptService.TestCategorize(r.Context(), req.Source, application.ImportedTransaction{
+		Date:        date,
+		Amount:      req.Transaction.Amount,
+		Description: req.Transaction.Description,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
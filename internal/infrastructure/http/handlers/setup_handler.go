@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type SetupHandler struct {
+	setupService  *application.SetupService
+	secureCookies bool
+}
+
+func NewSetupHandler(setupService *application.SetupService, secureCookies bool) *SetupHandler {
+	return &SetupHandler{setupService: setupService, secureCookies: secureCookies}
+}
+
+type SetupAdminRequest struct {
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+// GetStatus handles GET /api/setup and reports the remaining first-run steps
+// Templates are described in the language given by ?locale=, if any.
+func (h *SetupHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.setupService.GetStatus(r.Context(), r.URL.Query().Get("locale"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// CreateAdmin handles POST /api/setup/admin, creating the first user and signing them in
+func (h *SetupHandler) CreateAdmin(w http.ResponseWriter, r *http.Request) {
+	var req SetupAdminRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	session, token, user, err := h.setupService.CreateAdmin(r.Context(), req.Email, req.Name, req.Password, r.UserAgent(), clientIP(r))
+	if err != nil {
+		setupError(w, err)
+		return
+	}
+
+	setSessionCookie(w, token, session.ExpiresAt, h.secureCookies)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(LoginResponse{Token: token, ExpiresAt: session.ExpiresAt, User: user})
+}
+
+// ChooseTemplate handles POST /api/setup/template
+func (h *SetupHandler) ChooseTemplate(w http.ResponseWriter, r *http.Request) {
+	var req ApplyTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	applied, err := h.setupService.ChooseTemplate(r.Context(), req.Template, req.Locale)
+	if err != nil {
+		setupError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(applied)
+}
+
+// CreateAccount handles POST /api/setup/account, adding the first account
+func (h *SetupHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
+	var req CreateAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	account, err := h.setupService.CreateFirstAccount(r.Context(), req.Name, req.Balance, domain.AccountType(req.Type))
+	if err != nil {
+		setupError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(account)
+}
+
+// setupError reports a failed setup step; repeating a finished step is a conflict
+func setupError(w http.ResponseWriter, err error) {
+	if errors.Is(err, application.ErrSetupStepDone) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/infrastructure/database"
+	"github.com/billybbuffum/budget/internal/infrastructure/repository"
+)
+
+func TestSetupHandler(t *testing.T) {
+	db, err := database.NewSQLiteDB(filepath.Join(t.TempDir(), "budget.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	accountRepo := repository.NewAccountRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	categoryGroupRepo := repository.NewCategoryGroupRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	authService := application.NewAuthService(userRepo, repository.NewSessionRepository(db), repository.NewAPITokenRepository(db), repository.NewRecoveryCodeRepository(db))
+	accountService := application.NewAccountService(accountRepo, categoryRepo, repository.NewBudgetStateRepository(db), repository.NewTransactionRepository(db),
+		application.NewCategoryGroupService(categoryGroupRepo, categoryRepo), repository.NewUnitOfWork(db))
+	bootstrapService := application.NewBootstrapService(categoryGroupRepo, categoryRepo, repository.NewSettingRepository(db), "")
+	handler := NewSetupHandler(application.NewSetupService(userRepo, accountRepo, authService, accountService, bootstrapService, true), false)
+
+	send := func(handle http.HandlerFunc, method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handle(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+	status := func() application.SetupStatus {
+		t.Helper()
+		w := send(handler.GetStatus, http.MethodGet, "/api/setup", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected the setup status, got %d: %s", w.Code, w.Body.String())
+		}
+		var status application.SetupStatus
+		if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+			t.Fatal(err)
+		}
+		return status
+	}
+
+	if s := status(); s.Complete || !s.NeedsAdminUser || !s.NeedsTemplate || !s.NeedsAccount || !s.AuthRequired {
+		t.Errorf("expected every step left, got %+v", s)
+	}
+
+	steps := []struct {
+		name   string
+		handle http.HandlerFunc
+		path   string
+		body   string
+		repeat string
+	}{
+		{"admin", handler.CreateAdmin, "/api/setup/admin",
+			`{"email":"admin@example.com","name":"Admin","password":"password123"}`,
+			`{"email":"other@example.com","name":"Other","password":"password123"}`},
+		{"template", handler.ChooseTemplate, "/api/setup/template", `{"template":"minimal"}`, `{"template":"renter"}`},
+		{"account", handler.CreateAccount, "/api/setup/account",
+			`{"name":"Checking","balance":100000,"type":"checking"}`,
+			`{"name":"Savings","balance":0,"type":"savings"}`},
+	}
+	for _, step := range steps {
+		w := send(step.handle, http.MethodPost, step.path, step.body)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected the %s step done, got %d: %s", step.name, w.Code, w.Body.String())
+		}
+		if step.name == "admin" {
+			if cookies := w.Result().Cookies(); len(cookies) == 0 || cookies[0].Name != SessionCookieName || cookies[0].Value == "" {
+				t.Errorf("expected the new admin signed in, got cookies %v", cookies)
+			}
+		}
+		if w := send(step.handle, http.MethodPost, step.path, step.repeat); w.Code != http.StatusConflict {
+			t.Errorf("expected repeating the %s step to conflict, got %d: %s", step.name, w.Code, w.Body.String())
+		}
+	}
+
+	if s := status(); !s.Complete || s.NeedsAdminUser || s.NeedsTemplate || s.NeedsAccount || len(s.Templates) != 0 {
+		t.Errorf("expected setup complete, got %+v", s)
+	}
+	if w := send(handler.CreateAdmin, http.MethodPost, "/api/setup/admin", `{"email":`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected a malformed body refused, got %d", w.Code)
+	}
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/application"
+)
+
+// ssoStateCookieName carries the state, nonce and PKCE verifier between the
+// redirect to the identity provider and the callback
+const ssoStateCookieName = "budget_sso_state"
+
+// ssoStateLifetime is how long a user has to finish signing in at the provider
+const ssoStateLifetime = 10 * time.Minute
+
+// ssoChallengeCookieName carries the two-factor challenge from the callback to the
+// code entered on the login page
+const ssoChallengeCookieName = "budget_sso_challenge"
+
+type SSOHandler struct {
+	ssoService    *application.SSOService
+	secureCookies bool
+}
+
+func NewSSOHandler(ssoService *application.SSOService, secureCookies bool) *SSOHandler {
+	return &SSOHandler{
+		ssoService:    ssoService,
+		secureCookies: secureCookies,
+	}
+}
+
+type SSOInfoResponse struct {
+	Enabled bool   `json:"enabled"`
+	Name    string `json:"name,omitempty"` // Provider name for the sign-in button
+}
+
+// GetInfo handles GET /api/auth/oidc and tells the login page whether to offer single sign-on
+func (h *SSOHandler) GetInfo(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(SSOInfoResponse{
+		Enabled: h.ssoService.Enabled(),
+		Name:    h.ssoService.ProviderName(),
+	})
+}
+
+// Login handles GET /api/auth/oidc/login and redirects to the identity provider
+func (h *SSOHandler) Login(w http.ResponseWriter, r *http.Request) {
+	authURL, state, err := h.ssoService.BeginSignIn()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoStateCookieName,
+		Value:    strings.Join([]string{state.State, state.Nonce, state.CodeVerifier}, "."),
+		Path:     "/api/auth/oidc",
+		MaxAge:   int(ssoStateLifetime.Seconds()),
+		HttpOnly: true,
+		Secure:   h.secureCookies,
+		SameSite: http.SameSiteLaxMode, // Sent on the provider's top-level redirect back to us
+	})
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// Callback handles GET /api/auth/oidc/callback, signs the user in and returns to the app
+// Failures go back to the login page with an error message.
+func (h *SSOHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoStateCookieName,
+		Value:    "",
+		Path:     "/api/auth/oidc",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   h.secureCookies,
+	})
+
+	if msg := r.URL.Query().Get("error"); msg != "" {
+		if desc := r.URL.Query().Get("error_description"); desc != "" {
+			msg = desc
+		}
+		h.loginError(w, r, msg)
+		return
+	}
+
+	cookie, err := r.Cookie(ssoStateCookieName)
+	if err != nil {
+		h.loginError(w, r, "sign-in expired, please try again")
+		return
+	}
+	parts := strings.Split(cookie.Value, ".")
+	if len(parts) != 3 || parts[0] != r.URL.Query().Get("state") {
+		h.loginError(w, r, "sign-in expired, please try again")
+		return
+	}
+	state := &application.SSOState{State: parts[0], Nonce: parts[1], CodeVerifier: parts[2]}
+
+	signIn, err := h.ssoService.CompleteSignIn(r.Context(), r.URL.Query().Get("code"), state, r.UserAgent(), clientIP(r))
+	if err != nil {
+		h.loginError(w, r, err.Error())
+		return
+	}
+
+	// Two-factor users finish on the login page, which posts their code to TwoFactor
+	if signIn.Challenge != "" {
+		h.setChallengeCookie(w, signIn.Challenge, int(application.SSOChallengeLifetime.Seconds()))
+		http.Redirect(w, r, "/login.html?two_factor=sso", http.StatusFound)
+		return
+	}
+
+	setSessionCookie(w, signIn.Token, signIn.Session.ExpiresAt, h.secureCookies)
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+// TwoFactor handles POST /api/auth/oidc/two-factor and finishes a single sign-on for a
+// user with two-factor enabled
+func (h *SSOHandler) TwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	cookie, err := r.Cookie(ssoChallengeCookieName)
+	if err != nil {
+		http.Error(w, application.ErrSSOChallengeExpired.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	session, token, user, err := h.ssoService.CompleteTwoFactor(r.Context(), cookie.Value, req.Code, r.UserAgent(), clientIP(r))
+	if err != nil {
+		if errors.Is(err, application.ErrSSOChallengeExpired) || errors.Is(err, application.ErrUserDisabled) {
+			h.setChallengeCookie(w, "", -1)
+		}
+		if errors.Is(err, application.ErrUserDisabled) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, application.ErrTooManyTwoFactorAttempts) {
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	h.setChallengeCookie(w, "", -1)
+	setSessionCookie(w, token, session.ExpiresAt, h.secureCookies)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(LoginResponse{Token: token, ExpiresAt: session.ExpiresAt, User: user})
+}
+
+// setChallengeCookie sets (or, with a negative maxAge, clears) the two-factor challenge cookie
+func (h *SSOHandler) setChallengeCookie(w http.ResponseWriter, challenge string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoChallengeCookieName,
+		Value:    challenge,
+		Path:     "/api/auth/oidc",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   h.secureCookies,
+		SameSite: http.SameSiteStrictMode, // Only ever posted from the login page
+	})
+}
+
+func (h *SSOHandler) loginError(w http.ResponseWriter, r *http.Request, msg string) {
+	http.Redirect(w, r, "/login.html?error="+url.QueryEscape(msg), http.StatusFound)
+}
@@ -2,10 +2,16 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
 )
 
 type TransactionHandler struct {
@@ -32,6 +38,43 @@ type CreateTransferRequest struct {
 	Date          time.Time `json:"date"`
 }
 
+type CreateExternalTransferRequest struct {
+	AccountID   string    `json:"account_id"`
+	CategoryID  *string   `json:"category_id,omitempty"` // Optional: the budget the money came out of
+	Amount      int64     `json:"amount"`                // in cents (must be positive)
+	Description string    `json:"description"`
+	Date        time.Time `json:"date"`
+}
+
+type LinkTransferRequest struct {
+	ToAccountID string `json:"to_account_id"`
+}
+
+type SetOriginalAmountRequest struct {
+	OriginalCurrency string `json:"original_currency"` // ISO 4217 code, e.g. EUR; "" clears it
+	OriginalAmount   int64  `json:"original_amount"`   // in cents of original_currency, same sign as the amount
+}
+
+type CreateAdjustmentRequest struct {
+	AccountID string    `json:"account_id"`
+	Amount    int64     `json:"amount"` // in cents (positive raises the balance, negative lowers it)
+	Note      string    `json:"note"`   // Required: why the balance needed adjusting
+	Date      time.Time `json:"date"`
+}
+
+type RedeemRewardsRequest struct {
+	Amount      int64     `json:"amount"`                  // in cents (must be positive)
+	ToAccountID string    `json:"to_account_id,omitempty"` // Defaults to the credit account itself
+	Description string    `json:"description,omitempty"`
+	Date        time.Time `json:"date"`
+}
+
+type UpdateTrackingBalanceRequest struct {
+	Balance int64     `json:"balance"`        // in cents, the account's value now
+	Note    string    `json:"note,omitempty"` // Optional, e.g. "Q3 statement"
+	Date    time.Time `json:"date"`
+}
+
 type UpdateTransactionRequest struct {
 	AccountID   string    `json:"account_id"`
 	CategoryID  *string   `json:"category_id,omitempty"`
@@ -59,6 +102,69 @@ func (h *TransactionHandler) CreateTransaction(w http.ResponseWriter, r *http.Re
 	json.NewEncoder(w).Encode(transaction)
 }
 
+// CreateAdjustment handles POST /api/transactions/adjustment
+func (h *TransactionHandler) CreateAdjustment(w http.ResponseWriter, r *http.Request) {
+	var req CreateAdjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	transaction, err := h.transactionService.CreateAdjustment(
+		r.Context(), req.AccountID, req.Amount, req.Note, req.Date)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(transaction)
+}
+
+// RedeemRewards handles POST /api/accounts/{id}/rewards/redeem
+func (h *TransactionHandler) RedeemRewards(w http.ResponseWriter, r *http.Request) {
+	var req RedeemRewardsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	transaction, err := h.transactionService.RedeemRewards(
+		r.Context(), r.PathValue("id"), req.Amount, req.ToAccountID, req.Description, req.Date)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(transaction)
+}
+
+// UpdateTrackingBalance handles PUT /api/accounts/{id}/balance
+func (h *TransactionHandler) UpdateTrackingBalance(w http.ResponseWriter, r *http.Request) {
+	var req UpdateTrackingBalanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	update, err := h.transactionService.UpdateTrackingBalance(
+		r.Context(), r.PathValue("id"), req.Balance, req.Note, req.Date)
+	if err != nil {
+		if errors.Is(err, domain.ErrAccountNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(update)
+}
+
 func (h *TransactionHandler) GetTransaction(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	if id == "" {
@@ -76,41 +182,91 @@ func (h *TransactionHandler) GetTransaction(w http.ResponseWriter, r *http.Reque
 	json.NewEncoder(w).Encode(transaction)
 }
 
+// ListTransactions handles GET /api/transactions
+// Optional query parameters, all combined: account_id, category_id, uncategorized=true,
+// start_date and end_date as RFC3339 times, min_amount and max_amount in cents (matching
+// outflows by their size), search, sort (date, amount, description or created_at),
+// order (asc or desc), limit and offset. The body is the page of transactions; the
+// X-Total-Count header says how many match in all.
 func (h *TransactionHandler) ListTransactions(w http.ResponseWriter, r *http.Request) {
-	accountID := r.URL.Query().Get("account_id")
-	categoryID := r.URL.Query().Get("category_id")
-	startDate := r.URL.Query().Get("start_date")
-	endDate := r.URL.Query().Get("end_date")
-	uncategorized := r.URL.Query().Get("uncategorized")
-
-	var transactions interface{}
-	var err error
+	query, err := transactionQueryFromParams(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.writeTransactionPage(w, r, query)
+}
 
-	if uncategorized == "true" {
-		transactions, err = h.transactionService.ListUncategorizedTransactions(r.Context())
-	} else if accountID != "" {
-		transactions, err = h.transactionService.ListTransactionsByAccount(r.Context(), accountID)
-	} else if categoryID != "" {
-		transactions, err = h.transactionService.ListTransactionsByCategory(r.Context(), categoryID)
-	} else if startDate != "" && endDate != "" {
-		start, err1 := time.Parse(time.RFC3339, startDate)
-		end, err2 := time.Parse(time.RFC3339, endDate)
-		if err1 != nil || err2 != nil {
-			http.Error(w, "invalid date format, use RFC3339", http.StatusBadRequest)
-			return
-		}
-		transactions, err = h.transactionService.ListTransactionsByPeriod(r.Context(), start, end)
-	} else {
-		transactions, err = h.transactionService.ListTransactions(r.Context())
+// SearchTransactions finds transactions whose description, note or payee has every word in q
+// It takes the same filters and paging as ListTransactions and sorts best matches first.
+func (h *TransactionHandler) SearchTransactions(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	text := strings.TrimSpace(params.Get("q"))
+	if text == "" {
+		http.Error(w, "q is required", http.StatusBadRequest)
+		return
+	}
+	query, err := transactionQueryFromParams(params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
+	query.Text = text
+	h.writeTransactionPage(w, r, query)
+}
 
+func (h *TransactionHandler) writeTransactionPage(w http.ResponseWriter, r *http.Request, query domain.TransactionQuery) {
+	transactions, total, err := h.transactionService.SearchTransactions(r.Context(), query)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(transactions)
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	writeJSONArray(w, transactions)
+}
+
+// transactionQueryFromParams reads the filter, sort and paging query parameters
+func transactionQueryFromParams(params url.Values) (domain.TransactionQuery, error) {
+	query := domain.TransactionQuery{
+		AccountID:     params.Get("account_id"),
+		CategoryID:    params.Get("category_id"),
+		Uncategorized: params.Get("uncategorized") == "true",
+		Search:        params.Get("search"),
+		Sort:          domain.TransactionSort(params.Get("sort")),
+	}
+
+	var err error
+	if query.Since, err = parseOptionalTime(params.Get("start_date")); err != nil {
+		return query, fmt.Errorf("invalid date format, use RFC3339")
+	}
+	if query.Until, err = parseOptionalTime(params.Get("end_date")); err != nil {
+		return query, fmt.Errorf("invalid date format, use RFC3339")
+	}
+	for name, target := range map[string]**int64{"min_amount": &query.MinAmount, "max_amount": &query.MaxAmount} {
+		if value := params.Get(name); value != "" {
+			amount, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return query, fmt.Errorf("%s must be a whole number of cents", name)
+			}
+			*target = &amount
+		}
+	}
+	for name, target := range map[string]*int{"limit": &query.Limit, "offset": &query.Offset} {
+		if value := params.Get(name); value != "" {
+			if *target, err = strconv.Atoi(value); err != nil {
+				return query, fmt.Errorf("%s must be a number", name)
+			}
+		}
+	}
+	switch params.Get("order") {
+	case "", "desc":
+	case "asc":
+		query.Ascending = true
+	default:
+		return query, fmt.Errorf("order must be asc or desc")
+	}
+	return query, nil
 }
 
 func (h *TransactionHandler) UpdateTransaction(w http.ResponseWriter, r *http.Request) {
@@ -171,6 +327,72 @@ func (h *TransactionHandler) CreateTransfer(w http.ResponseWriter, r *http.Reque
 	json.NewEncoder(w).Encode(transaction)
 }
 
+func (h *TransactionHandler) CreateExternalTransfer(w http.ResponseWriter, r *http.Request) {
+	var req CreateExternalTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	transaction, err := h.transactionService.CreateExternalTransfer(
+		r.Context(), req.AccountID, req.CategoryID, req.Amount, req.Description, req.Date)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(transaction)
+}
+
+func (h *TransactionHandler) LinkTransfer(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "transaction id is required", http.StatusBadRequest)
+		return
+	}
+
+	var req LinkTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	transaction, err := h.transactionService.LinkTransfer(r.Context(), id, req.ToAccountID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transaction)
+}
+
+// SetOriginalAmount handles PUT /api/transactions/{id}/original-amount
+func (h *TransactionHandler) SetOriginalAmount(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "transaction id is required", http.StatusBadRequest)
+		return
+	}
+
+	var req SetOriginalAmountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	transaction, err := h.transactionService.SetOriginalAmount(r.Context(), id, req.OriginalCurrency, req.OriginalAmount)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transaction)
+}
+
 type BulkCategorizeRequest struct {
 	TransactionIDs []string `json:"transaction_ids"`
 	CategoryID     *string  `json:"category_id,omitempty"`
@@ -196,6 +418,64 @@ func (h *TransactionHandler) BulkCategorizeTransactions(w http.ResponseWriter, r
 	w.WriteHeader(http.StatusNoContent)
 }
 
+type BulkEditTransactionsRequest struct {
+	TransactionIDs []string `json:"transaction_ids"`
+	PayeeID        *string  `json:"payee_id,omitempty"`   // Optional: an empty string clears the payee
+	AccountID      *string  `json:"account_id,omitempty"` // Optional: moves normal transactions to another account
+	ShiftDays      int      `json:"shift_days,omitempty"` // Optional: moves each date by this many days
+}
+
+// BulkEditTransactions handles PATCH /api/transactions/bulk
+// Transactions that can't be changed are listed with the reason; the rest are changed
+func (h *TransactionHandler) BulkEditTransactions(w http.ResponseWriter, r *http.Request) {
+	var req BulkEditTransactionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.TransactionIDs) == 0 {
+		http.Error(w, "transaction_ids is required", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.transactionService.BulkEditTransactions(r.Context(), req.TransactionIDs, domain.TransactionBulkEdit{
+		PayeeID:   req.PayeeID,
+		AccountID: req.AccountID,
+		ShiftDays: req.ShiftDays,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrPayeeNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// SuggestCategories handles GET /api/transactions/{id}/category-suggestions
+// Lists up to three categories, most likely first, going by how similar past transactions
+// were categorized
+func (h *TransactionHandler) SuggestCategories(w http.ResponseWriter, r *http.Request) {
+	transaction, err := h.transactionService.GetTransaction(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	suggestions, err := h.transactionService.SuggestCategories(r.Context(), transaction)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeJSONArray(w, suggestions)
+}
+
 func (h *TransactionHandler) GetAccountTransactions(w http.ResponseWriter, r *http.Request) {
 	accountID := r.PathValue("id")
 	if accountID == "" {
@@ -209,6 +489,5 @@ func (h *TransactionHandler) GetAccountTransactions(w http.ResponseWriter, r *ht
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(transactions)
+	writeJSONArray(w, transactions)
 }
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type TransferHintHandler struct {
+	transferHintService *application.TransferHintService
+}
+
+func NewTransferHintHandler(transferHintService *application.TransferHintService) *TransferHintHandler {
+	return &TransferHintHandler{transferHintService: transferHintService}
+}
+
+type CreateTransferHintRequest struct {
+	Pattern         string `json:"pattern"`           // Case-insensitive text in the imported description, e.g. "ONLINE TRANSFER TO SAV"
+	TargetAccountID string `json:"target_account_id"` // The account on the other side
+}
+
+func (h *TransferHintHandler) CreateTransferHint(w http.ResponseWriter, r *http.Request) {
+	var req CreateTransferHintRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	hint, err := h.transferHintService.CreateHint(r.Context(), r.PathValue("id"), req.Pattern, req.TargetAccountID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(hint)
+}
+
+func (h *TransferHintHandler) ListTransferHints(w http.ResponseWriter, r *http.Request) {
+	hints, err := h.transferHintService.ListHints(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONArray(w, hints)
+}
+
+func (h *TransferHintHandler) DeleteTransferHint(w http.ResponseWriter, r *http.Request) {
+	err := h.transferHintService.DeleteHint(r.Context(), r.PathValue("id"), r.PathValue("hintID"))
+	if err != nil {
+		if errors.Is(err, domain.ErrTransferHintNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
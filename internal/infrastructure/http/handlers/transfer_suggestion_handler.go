@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type TransferSuggestionHandler struct {
+	transferSuggestionService *application.TransferSuggestionService
+}
+
+func NewTransferSuggestionHandler(transferSuggestionService *application.TransferSuggestionService) *TransferSuggestionHandler {
+	return &TransferSuggestionHandler{transferSuggestionService: transferSuggestionService}
+}
+
+type ScanTransfersRequest struct {
+	Start        time.Time `json:"start"`                   // Optional: defaults to as far back as the scheduled scan looks
+	End          time.Time `json:"end"`                     // Optional: defaults to now
+	Days         *int      `json:"days,omitempty"`          // Optional: how many days apart the two sides may be; defaults to the server's setting
+	ToleranceBps *int64    `json:"tolerance_bps,omitempty"` // Optional: how far the amounts may differ, in basis points; defaults to the server's setting
+	FeeTolerance *int64    `json:"fee_tolerance,omitempty"` // Optional: how far the amounts may differ, in cents; defaults to the server's setting
+}
+
+// ScanTransfers handles POST /api/transfer-suggestions/scan
+// Suggests transfers for outflows in the date range, across every account; the matching
+// window and amount tolerance can be widened or narrowed for this scan
+func (h *TransferSuggestionHandler) ScanTransfers(w http.ResponseWriter, r *http.Request) {
+	var req ScanTransfersRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	matching := h.transferSuggestionService.Matching()
+	if req.Days != nil {
+		matching.Days = *req.Days
+	}
+	if req.ToleranceBps != nil {
+		matching.ToleranceBps = *req.ToleranceBps
+	}
+	if req.FeeTolerance != nil {
+		matching.FeeTolerance = *req.FeeTolerance
+	}
+
+	scan, err := h.transferSuggestionService.Scan(r.Context(), req.Start, req.End, matching)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scan)
+}
+
+// ListTransferSuggestions handles GET /api/transfer-suggestions
+// Optional query parameter status=pending|accepted|rejected; defaults to all of them
+func (h *TransferSuggestionHandler) ListTransferSuggestions(w http.ResponseWriter, r *http.Request) {
+	status := domain.TransferSuggestionStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "", domain.TransferSuggestionPending, domain.TransferSuggestionAccepted, domain.TransferSuggestionRejected:
+	default:
+		http.Error(w, "status must be pending, accepted or rejected", http.StatusBadRequest)
+		return
+	}
+
+	suggestions, err := h.transferSuggestionService.ListSuggestions(r.Context(), status)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONArray(w, suggestions)
+}
+
+// GetTransferSuggestion handles GET /api/transfer-suggestions/{id}
+func (h *TransferSuggestionHandler) GetTransferSuggestion(w http.ResponseWriter, r *http.Request) {
+	suggestion, err := h.transferSuggestionService.GetSuggestion(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeTransferSuggestionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(suggestion)
+}
+
+// AcceptTransferSuggestion handles POST /api/transfer-suggestions/{id}/accept
+// Links the suggested transactions as a transfer
+func (h *TransferSuggestionHandler) AcceptTransferSuggestion(w http.ResponseWriter, r *http.Request) {
+	suggestion, err := h.transferSuggestionService.AcceptSuggestion(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeTransferSuggestionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(suggestion)
+}
+
+// RejectTransferSuggestion handles POST /api/transfer-suggestions/{id}/reject
+// Leaves its transactions alone; the pair isn't suggested again
+func (h *TransferSuggestionHandler) RejectTransferSuggestion(w http.ResponseWriter, r *http.Request) {
+	suggestion, err := h.transferSuggestionService.RejectSuggestion(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeTransferSuggestionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(suggestion)
+}
+
+// ListTransferPairings handles GET /api/transfer-pairings
+// Lists the payees reviewing suggestions has paired or kept apart, most recent first
+func (h *TransferSuggestionHandler) ListTransferPairings(w http.ResponseWriter, r *http.Request) {
+	pairings, err := h.transferSuggestionService.ListPairings(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONArray(w, pairings)
+}
+
+// DeleteTransferPairing handles DELETE /api/transfer-pairings/{id}
+// Forgets a pairing, so scans suggest its payees again
+func (h *TransferSuggestionHandler) DeleteTransferPairing(w http.ResponseWriter, r *http.Request) {
+	if err := h.transferSuggestionService.ForgetPairing(r.Context(), r.PathValue("id")); err != nil {
+		writeTransferSuggestionError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeTransferSuggestionError writes a transfer suggestion error with its status
+func writeTransferSuggestionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrTransferSuggestionNotFound), errors.Is(err, domain.ErrTransferPairingNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, domain.ErrTransferSuggestionStale):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type WidgetHandler struct {
+	widgetService *application.WidgetService
+}
+
+func NewWidgetHandler(widgetService *application.WidgetService) *WidgetHandler {
+	return &WidgetHandler{widgetService: widgetService}
+}
+
+type CreateWidgetRequest struct {
+	Name       string   `json:"name"`
+	CategoryID string   `json:"category_id"`
+	Fields     []string `json:"fields,omitempty"` // Defaults to goal_percent
+}
+
+type CreateWidgetResponse struct {
+	*domain.Widget
+	Token string `json:"token"` // Only returned once
+	URL   string `json:"url"`   // Path to embed, with the token
+}
+
+type UpdateWidgetRequest struct {
+	Name       *string  `json:"name,omitempty"`
+	CategoryID *string  `json:"category_id,omitempty"`
+	Fields     []string `json:"fields,omitempty"`
+}
+
+// CreateWidget handles POST /api/widgets
+// Returns the widget's token; it cannot be retrieved again
+func (h *WidgetHandler) CreateWidget(w http.ResponseWriter, r *http.Request) {
+	var req CreateWidgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	widget, token, err := h.widgetService.CreateWidget(r.Context(), req.Name, req.CategoryID, req.Fields)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(CreateWidgetResponse{Widget: widget, Token: token, URL: "/api/public/widgets/" + token})
+}
+
+func (h *WidgetHandler) ListWidgets(w http.ResponseWriter, r *http.Request) {
+	widgets, err := h.widgetService.ListWidgets(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONArray(w, widgets)
+}
+
+func (h *WidgetHandler) UpdateWidget(w http.ResponseWriter, r *http.Request) {
+	var req UpdateWidgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	widget, err := h.widgetService.UpdateWidget(r.Context(), r.PathValue("id"), req.Name, req.CategoryID, req.Fields)
+	if err != nil {
+		if errors.Is(err, domain.ErrWidgetNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(widget)
+}
+
+func (h *WidgetHandler) DeleteWidget(w http.ResponseWriter, r *http.Request) {
+	if err := h.widgetService.DeleteWidget(r.Context(), r.PathValue("id")); err != nil {
+		if errors.Is(err, domain.ErrWidgetNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetWidgetData handles GET /api/public/widgets/{token}
+// Needs no other credential and may be fetched from any site, so it can be embedded.
+func (h *WidgetHandler) GetWidgetData(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	data, err := h.widgetService.WidgetData(r.Context(), r.PathValue("token"))
+	if err != nil {
+		switch {
+		case errors.Is(err, application.ErrWidgetRateLimited):
+			w.Header().Set("Retry-After", "60")
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+		case errors.Is(err, domain.ErrWidgetNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, "widget data is unavailable", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"github.com/billybbuffum/budget/internal/i18n"
+)
+
+// LocalizeErrors translates plain-text /api error responses into the language
+// negotiated from the request's Accept-Language header
+// Handlers keep reporting errors with http.Error in English; messages without a
+// translation are passed through unchanged.
+func LocalizeErrors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Accept-Language")
+		lang := i18n.Negotiate(r.Header.Get("Accept-Language"))
+		if lang == i18n.Default {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		lw := &localizedErrorWriter{ResponseWriter: w, lang: lang}
+		next.ServeHTTP(lw, r)
+		lw.finish()
+	})
+}
+
+// localizedErrorWriter holds back plain-text error bodies so they can be translated
+// as a whole; every other response is written straight through
+type localizedErrorWriter struct {
+	http.ResponseWriter
+	lang      string
+	status    int
+	buffering bool
+	body      bytes.Buffer
+}
+
+func (w *localizedErrorWriter) WriteHeader(status int) {
+	if w.status != 0 {
+		return
+	}
+	w.status = status
+	if status >= http.StatusBadRequest && strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain") {
+		w.buffering = true
+		return
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *localizedErrorWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.buffering {
+		return w.body.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush sends what has been written so far, unless it's an error being held back
+func (w *localizedErrorWriter) Flush() {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.buffering {
+		return
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *localizedErrorWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// finish writes the held-back error, translated
+func (w *localizedErrorWriter) finish() {
+	if !w.buffering {
+		return
+	}
+	message := strings.TrimSuffix(w.body.String(), "\n")
+	translated := i18n.Translate(w.lang, message)
+	if translated != message {
+		w.Header().Set("Content-Language", w.lang)
+	}
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.Write([]byte(translated + "\n"))
+}
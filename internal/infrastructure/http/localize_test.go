@@ -0,0 +1,46 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLocalizeErrorsPassesFlushesThrough(t *testing.T) {
+	handler := LocalizeErrors(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			t.Fatal("expected the localized writer to be an http.Flusher")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("["))
+		flusher.Flush()
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("expected the response controller to flush, got %v", err)
+		}
+		w.Write([]byte("]"))
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "/api/transactions", nil)
+	request.Header.Set("Accept-Language", "de")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	if !recorder.Flushed || recorder.Body.String() != "[]" {
+		t.Errorf("expected a flushed [] response, got flushed=%v body %q", recorder.Flushed, recorder.Body.String())
+	}
+}
+
+func TestLocalizeErrorsHoldsBackErrorsWhenFlushed(t *testing.T) {
+	handler := LocalizeErrors(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "account not found", http.StatusNotFound)
+		w.(http.Flusher).Flush()
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "/api/accounts/missing", nil)
+	request.Header.Set("Accept-Language", "de")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusNotFound || recorder.Body.String() != "Konto nicht gefunden\n" {
+		t.Errorf("expected the translated error, got %d %q", recorder.Code, recorder.Body.String())
+	}
+}
@@ -0,0 +1,341 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/billybbuffum/budget/internal/infrastructure/http/handlers"
+)
+
+// apiOperation documents one /api route in the OpenAPI document
+// Request and Response hold a value of the type the handler decodes or encodes; their
+// schemas are generated from the type's fields and json tags.
+type apiOperation struct {
+	Summary  string
+	Query    []string // Optional query parameters
+	Required []string // Required query parameters
+	Request  any      // JSON request body, nil when the route takes none
+	Form     []string // Multipart form fields; "file" is the uploaded file, other "_file" fields optional uploads
+	CSV      bool     // The body may also be sent as text/csv
+	Response any      // JSON response body, nil when the route sends none
+	Status   int      // Success status; 200 when zero
+}
+
+// ServeOpenAPI handles GET /api/openapi.json
+// The document is built once from apiOperations and describes the routes under /api/v1.
+func ServeOpenAPI(w http.ResponseWriter, r *http.Request) {
+	document, err := openAPIDocument()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(document)
+}
+
+var openAPIDocument = sync.OnceValues(func() ([]byte, error) {
+	return json.Marshal(buildOpenAPI())
+})
+
+// buildOpenAPI assembles the OpenAPI 3.1 document for every documented route
+func buildOpenAPI() map[string]any {
+	schemas := newOpenAPISchemas()
+	paths := make(map[string]map[string]any)
+
+	patterns := make([]string, 0, len(apiOperations))
+	for pattern := range apiOperations {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns) // Component names are assigned in a stable order
+
+	for _, pattern := range patterns {
+		method, route, _ := strings.Cut(pattern, " ")
+		path := strings.TrimPrefix(route, "/api")
+		if paths[path] == nil {
+			paths[path] = make(map[string]any)
+		}
+		paths[path][strings.ToLower(method)] = schemas.operation(pattern, apiOperations[pattern])
+	}
+
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":       "Budget API",
+			"version":     APIVersion,
+			"description": "Amounts are in cents and periods are months written YYYY-MM. Errors are plain-text messages.",
+		},
+		"servers": []any{map[string]any{"url": versionedAPIPrefix}},
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas": schemas.components,
+			"securitySchemes": map[string]any{
+				"bearerAuth":    map[string]any{"type": "http", "scheme": "bearer", "description": "API token or session token"},
+				"sessionCookie": map[string]any{"type": "apiKey", "in": "cookie", "name": handlers.SessionCookieName},
+			},
+		},
+		"security": []any{
+			map[string]any{"bearerAuth": []any{}},
+			map[string]any{"sessionCookie": []any{}},
+		},
+	}
+}
+
+// operation builds the OpenAPI operation object for a route
+func (s *openAPISchemas) operation(pattern string, op apiOperation) map[string]any {
+	method, route, _ := strings.Cut(pattern, " ")
+	segments := strings.Split(strings.TrimPrefix(route, "/api/"), "/")
+
+	operation := map[string]any{
+		"summary":     op.Summary,
+		"operationId": operationID(method, segments),
+		"tags":        []string{segments[0]},
+	}
+	if publicAPIRoutes[pattern] {
+		operation["security"] = []any{}
+	}
+
+	var parameters []any
+	for _, segment := range segments {
+		if name, ok := strings.CutPrefix(segment, "{"); ok {
+			name = strings.TrimSuffix(strings.TrimSuffix(name, "}"), "...")
+			parameters = append(parameters, parameter(name, "path", true))
+		}
+	}
+	for _, name := range op.Required {
+		parameters = append(parameters, parameter(name, "query", true))
+	}
+	for _, name := range op.Query {
+		parameters = append(parameters, parameter(name, "query", false))
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+
+	content := make(map[string]any)
+	if op.Request != nil {
+		content["application/json"] = map[string]any{"schema": s.schema(reflect.TypeOf(op.Request))}
+	}
+	if op.CSV {
+		content["text/csv"] = map[string]any{"schema": map[string]any{"type": "string"}}
+	}
+	if len(op.Form) > 0 {
+		properties := make(map[string]any)
+		var required []string
+		for _, field := range op.Form {
+			if field == "file" {
+				properties[field] = map[string]any{"type": "string", "format": "binary"}
+				required = append(required, field)
+				continue
+			}
+			if strings.HasSuffix(field, "_file") {
+				properties[field] = map[string]any{"type": "string", "format": "binary"}
+				continue
+			}
+			properties[field] = map[string]any{"type": "string"}
+		}
+		form := map[string]any{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			form["required"] = required
+		}
+		content["multipart/form-data"] = map[string]any{"schema": form}
+	}
+	if len(content) > 0 {
+		operation["requestBody"] = map[string]any{"required": true, "content": content}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := map[string]any{"description": http.StatusText(status)}
+	if op.Response != nil {
+		success["content"] = map[string]any{
+			"application/json": map[string]any{"schema": s.schema(reflect.TypeOf(op.Response))},
+		}
+	}
+	operation["responses"] = map[string]any{
+		strconv.Itoa(status): success,
+		"default": map[string]any{
+			"description": "Error",
+			"content": map[string]any{
+				"text/plain": map[string]any{"schema": map[string]any{"type": "string"}},
+			},
+		},
+	}
+	return operation
+}
+
+func parameter(name, in string, required bool) map[string]any {
+	return map[string]any{
+		"name":     name,
+		"in":       in,
+		"required": required,
+		"schema":   map[string]any{"type": "string"},
+	}
+}
+
+// operationID names an operation from its method and path, e.g. getAccountsByIdTransactions
+func operationID(method string, segments []string) string {
+	var id strings.Builder
+	id.WriteString(strings.ToLower(method))
+	for _, segment := range segments {
+		if name, ok := strings.CutPrefix(segment, "{"); ok {
+			id.WriteString("By")
+			segment = strings.TrimSuffix(name, "}")
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			id.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return id.String()
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// openAPISchemas generates JSON schemas from Go types the way encoding/json encodes them
+// Named structs become shared components; everything else is described inline.
+type openAPISchemas struct {
+	components map[string]any
+	names      map[reflect.Type]string
+}
+
+func newOpenAPISchemas() *openAPISchemas {
+	return &openAPISchemas{
+		components: make(map[string]any),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+func (s *openAPISchemas) schema(t reflect.Type) map[string]any {
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case rawMessageType:
+		return map[string]any{} // Any JSON value
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return s.schema(t.Elem())
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + s.component(t)}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": s.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": s.schema(t.Elem())}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]any{"type": "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	default:
+		return map[string]any{} // Interfaces hold any JSON value
+	}
+}
+
+// component registers a named struct under components/schemas and returns its name
+// Types from different packages that share a name are told apart by their package.
+func (s *openAPISchemas) component(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+	name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+	if _, taken := s.components[name]; taken {
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	s.names[t] = name
+	s.components[name] = map[string]any{} // Placeholder, so recursive types refer to themselves
+	s.components[name] = s.object(t)
+	return name
+}
+
+// object describes a struct's JSON fields
+// Fields without omitempty are always sent, so they're required; pointer fields among
+// them may be null.
+func (s *openAPISchemas) object(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+	s.fields(t, properties, &required)
+
+	object := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		object["required"] = required
+	}
+	return object
+}
+
+func (s *openAPISchemas) fields(t reflect.Type, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		fieldType := field.Type
+		if field.Anonymous && name == "" {
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				s.fields(fieldType, properties, required) // Embedded fields are promoted
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := s.schema(fieldType)
+		if strings.Contains(","+options+",", ",string,") {
+			schema = map[string]any{"type": "string"}
+		}
+		if strings.Contains(","+options+",", ",omitempty,") {
+			properties[name] = schema
+			continue
+		}
+		if fieldType.Kind() == reflect.Pointer {
+			schema = nullable(schema)
+		}
+		properties[name] = schema
+		*required = append(*required, name)
+	}
+}
+
+// nullable allows null as well as the schema's own values
+func nullable(schema map[string]any) map[string]any {
+	if typ, ok := schema["type"].(string); ok {
+		schema["type"] = []string{typ, "null"}
+		return schema
+	}
+	return map[string]any{"anyOf": []any{schema, map[string]any{"type": "null"}}}
+}
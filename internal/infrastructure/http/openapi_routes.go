@@ -0,0 +1,306 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+	"github.com/billybbuffum/budget/internal/infrastructure/http/handlers"
+)
+
+// transactionFilters are the filter, sort and paging parameters transaction lists take
+var transactionFilters = []string{
+	"account_id", "category_id", "uncategorized", "search", "start_date", "end_date",
+	"min_amount", "max_amount", "sort", "order", "limit", "offset",
+}
+
+// csvMappingFields are the form fields that map a CSV file's columns
+var csvMappingFields = []string{
+	"date_column", "amount_column", "description_column", "fitid_column",
+	"balance_column", "date_format", "invert_amounts",
+}
+
+// apiOperations documents every /api route, keyed by its router pattern
+// A route registered in router.go without an entry here fails the tests.
+var apiOperations = map[string]apiOperation{
+	"GET /api/openapi.json": {Summary: "This OpenAPI document", Response: json.RawMessage{}},
+
+	// Accounts
+	"POST /api/accounts":                                {Summary: "Create an account", Request: handlers.CreateAccountRequest{}, Response: domain.Account{}, Status: http.StatusCreated},
+	"GET /api/accounts":                                 {Summary: "List accounts", Response: []domain.Account{}},
+	"GET /api/accounts/summary":                         {Summary: "Total balance across all accounts", Response: accountSummaryResponse{}},
+	"GET /api/accounts/{id}":                            {Summary: "Get an account", Response: domain.Account{}},
+	"GET /api/accounts/{id}/transactions":               {Summary: "List an account's transactions", Response: []domain.Transaction{}},
+	"PUT /api/accounts/{id}":                            {Summary: "Update an account", Request: handlers.UpdateAccountRequest{}, Response: domain.Account{}},
+	"PUT /api/accounts/{id}/starting-balance":           {Summary: "Correct an account's starting balance", Request: handlers.UpdateStartingBalanceRequest{}, Response: application.StartingBalanceChange{}},
+	"PUT /api/accounts/{id}/balance":                    {Summary: "Set an off-budget tracking account's balance", Request: handlers.UpdateTrackingBalanceRequest{}, Response: application.TrackingBalanceUpdate{}},
+	"PUT /api/accounts/{id}/hidden":                     {Summary: "Hide an account from the dashboard, or show it again", Request: handlers.SetHiddenFromDashboardRequest{}, Response: domain.Account{}},
+	"POST /api/accounts/{id}/convert":                   {Summary: "Convert an account to another type, moving its payment category and allocations along", Request: handlers.ConvertAccountTypeRequest{}, Response: application.AccountConversion{}},
+	"POST /api/accounts/{id}/rewards":                   {Summary: "Adjust a credit card's rewards balance", Request: handlers.AdjustRewardsRequest{}, Response: domain.Account{}},
+	"POST /api/accounts/{id}/rewards/redeem":            {Summary: "Redeem credit card rewards", Request: handlers.RedeemRewardsRequest{}, Response: domain.Transaction{}, Status: http.StatusCreated},
+	"DELETE /api/accounts/{id}":                         {Summary: "Delete an account", Status: http.StatusNoContent},
+	"PUT /api/accounts/{id}/loan":                       {Summary: "Set a loan's monthly payment and interest category", Request: handlers.SetLoanTermsRequest{}, Response: domain.Account{}},
+	"GET /api/accounts/{id}/amortization":               {Summary: "Amortization schedule for a loan's remaining balance", Query: []string{"start"}, Response: application.AmortizationSchedule{}},
+	"POST /api/accounts/{id}/loan-payments":             {Summary: "Pay towards a loan, split into an interest expense and a principal transfer", Request: handlers.RecordLoanPaymentRequest{}, Response: application.LoanPayment{}, Status: http.StatusCreated},
+	"GET /api/accounts/{id}/payment-reconciliation":     {Summary: "Compare a credit card's payment category with its balance", Required: []string{"period"}, Query: []string{"statement_balance"}, Response: application.CardPaymentReconciliation{}},
+	"POST /api/accounts/{id}/payment-reconciliation":    {Summary: "Fix a credit card's payment category", Request: handlers.FixCardPaymentRequest{}, Response: application.CardPaymentReconciliation{}},
+	"POST /api/accounts/{id}/transfer-hints":            {Summary: "Add a transfer hint for imports into an account", Request: handlers.CreateTransferHintRequest{}, Response: domain.TransferHint{}, Status: http.StatusCreated},
+	"GET /api/accounts/{id}/transfer-hints":             {Summary: "List an account's transfer hints", Response: []domain.TransferHint{}},
+	"DELETE /api/accounts/{id}/transfer-hints/{hintID}": {Summary: "Delete a transfer hint", Status: http.StatusNoContent},
+	"POST /api/transfer-suggestions/scan":               {Summary: "Scan a date range for transactions that look like both sides of a transfer", Request: handlers.ScanTransfersRequest{}, Response: application.TransferScan{}},
+	"GET /api/transfer-suggestions":                     {Summary: "List transfer suggestions", Query: []string{"status"}, Response: []application.TransferSuggestionDetail{}},
+	"GET /api/transfer-suggestions/{id}":                {Summary: "Get a transfer suggestion", Response: application.TransferSuggestionDetail{}},
+	"POST /api/transfer-suggestions/{id}/accept":        {Summary: "Link a suggestion's transactions as a transfer", Response: application.TransferSuggestionDetail{}},
+	"POST /api/transfer-suggestions/{id}/reject":        {Summary: "Reject a transfer suggestion", Response: application.TransferSuggestionDetail{}},
+	"GET /api/transfer-pairings":                        {Summary: "List payees that reviewed transfer suggestions paired or kept apart", Response: []domain.TransferPairing{}},
+	"DELETE /api/transfer-pairings/{id}":                {Summary: "Forget a transfer pairing", Status: http.StatusNoContent},
+	"PUT /api/accounts/{id}/bank-connection":            {Summary: "Connect an account to its bank's OFX DirectConnect server", Request: application.BankConnectionInput{}, Response: domain.BankConnection{}},
+	"GET /api/accounts/{id}/bank-connection":            {Summary: "Get how an account is connected to its bank", Response: domain.BankConnection{}},
+	"DELETE /api/accounts/{id}/bank-connection":         {Summary: "Disconnect an account from its bank", Status: http.StatusNoContent},
+	"POST /api/accounts/{id}/sync":                      {Summary: "Download and import an account's new transactions from its bank", Response: application.ImportResult{}},
+
+	// Bank aggregations
+	"POST /api/bank-aggregations":                         {Summary: "Connect to a bank aggregator such as SimpleFIN Bridge with a setup token", Request: handlers.ConnectAggregationRequest{}, Response: handlers.ConnectAggregationResponse{}, Status: http.StatusCreated},
+	"GET /api/bank-aggregations":                          {Summary: "List bank aggregations", Response: []domain.BankAggregation{}},
+	"DELETE /api/bank-aggregations/{id}":                  {Summary: "Disconnect from a bank aggregator", Status: http.StatusNoContent},
+	"GET /api/bank-aggregations/{id}/accounts":            {Summary: "List the accounts a bank aggregator reports", Response: []domain.BankAggregationAccount{}},
+	"PUT /api/bank-aggregations/{id}/accounts/{remoteID}": {Summary: "Link an aggregator's account to a budget account to sync into, or unlink it", Request: handlers.LinkAggregationAccountRequest{}, Response: domain.BankAggregationAccount{}},
+	"POST /api/bank-aggregations/{id}/sync":               {Summary: "Fetch and import the linked accounts' new transactions from a bank aggregator", Response: application.AggregationSyncResult{}},
+
+	// Categories
+	"POST /api/categories":                         {Summary: "Create a category", Request: handlers.CreateCategoryRequest{}, Response: domain.Category{}, Status: http.StatusCreated},
+	"POST /api/categories/bulk":                    {Summary: "Create several categories, and optionally their group, at once", Request: handlers.CreateCategoriesRequest{}, Response: application.BulkCategoriesResult{}, Status: http.StatusCreated},
+	"GET /api/categories":                          {Summary: "List categories", Response: []domain.Category{}},
+	"GET /api/categories/{id}":                     {Summary: "Get a category", Response: domain.Category{}},
+	"PUT /api/categories/{id}":                     {Summary: "Update a category", Request: handlers.UpdateCategoryRequest{}, Response: domain.Category{}},
+	"DELETE /api/categories/{id}":                  {Summary: "Delete a category", Status: http.StatusNoContent},
+	"POST /api/categories/{id}/cover-overspending": {Summary: "Cover a category's overspending", Request: handlers.CoverOverspendingRequest{}, Response: coverOverspendingResponse{}, Status: http.StatusCreated},
+	"GET /api/categories/{id}/inspector":           {Summary: "A category's assigned, activity and available history", Required: []string{"period"}, Response: domain.CategoryInspector{}},
+	"GET /api/categories/{id}/moves":               {Summary: "Money moved into a credit card payment category", Response: []domain.CategoryMove{}},
+
+	// Category groups
+	"POST /api/category-groups":               {Summary: "Create a category group", Request: handlers.CreateCategoryGroupRequest{}, Response: domain.CategoryGroup{}, Status: http.StatusCreated},
+	"GET /api/category-groups":                {Summary: "List category groups", Response: []domain.CategoryGroup{}},
+	"GET /api/category-groups/{id}":           {Summary: "Get a category group", Response: domain.CategoryGroup{}},
+	"PUT /api/category-groups/{id}":           {Summary: "Update a category group", Request: handlers.UpdateCategoryGroupRequest{}, Response: domain.CategoryGroup{}},
+	"DELETE /api/category-groups/{id}":        {Summary: "Delete a category group", Status: http.StatusNoContent},
+	"POST /api/category-groups/assign":        {Summary: "Move a category into a group", Request: handlers.AssignCategoryRequest{}, Status: http.StatusNoContent},
+	"POST /api/category-groups/unassign/{id}": {Summary: "Take a category out of its group", Status: http.StatusNoContent},
+
+	// Goals
+	"POST /api/goals":        {Summary: "Create a category goal", Request: handlers.CreateGoalRequest{}, Response: domain.Goal{}, Status: http.StatusCreated},
+	"GET /api/goals":         {Summary: "List goals", Response: []domain.Goal{}},
+	"GET /api/goals/{id}":    {Summary: "Get a goal", Response: domain.Goal{}},
+	"PUT /api/goals/{id}":    {Summary: "Update a goal", Request: handlers.UpdateGoalRequest{}, Response: domain.Goal{}},
+	"DELETE /api/goals/{id}": {Summary: "Delete a goal", Status: http.StatusNoContent},
+
+	// Payees
+	"GET /api/payees":              {Summary: "List payees", Response: []domain.Payee{}},
+	"POST /api/payee-rules":        {Summary: "Create a payee rule", Request: handlers.CreatePayeeRuleRequest{}, Response: domain.PayeeRule{}, Status: http.StatusCreated},
+	"GET /api/payee-rules":         {Summary: "List payee rules", Response: []domain.PayeeRule{}},
+	"GET /api/payee-rules/{id}":    {Summary: "Get a payee rule", Response: domain.PayeeRule{}},
+	"PUT /api/payee-rules/{id}":    {Summary: "Update a payee rule", Request: handlers.UpdatePayeeRuleRequest{}, Response: domain.PayeeRule{}},
+	"DELETE /api/payee-rules/{id}": {Summary: "Delete a payee rule", Status: http.StatusNoContent},
+	"POST /api/payee-rules/run":    {Summary: "Apply the payee rules to uncategorized transactions in the background", Response: handlers.RunPayeeRulesResponse{}, Status: http.StatusAccepted},
+	"GET /api/jobs":                {Summary: "List background jobs", Response: []application.Job{}},
+	"GET /api/jobs/{id}":           {Summary: "Get a background job", Response: application.Job{}},
+
+	// Digest and help
+	"GET /api/digest":        {Summary: "Preview the weekly budget digest", Query: []string{"period"}, Response: application.BudgetDigest{}},
+	"GET /api/help/concepts": {Summary: "Budget concepts explained with the budget's own numbers", Query: []string{"period"}, Response: application.HelpConcepts{}},
+
+	// Transactions (the total count is sent in the X-Total-Count header of lists)
+	"POST /api/transactions":                          {Summary: "Create a transaction", Request: handlers.CreateTransactionRequest{}, Response: domain.Transaction{}, Status: http.StatusCreated},
+	"POST /api/transactions/transfer":                 {Summary: "Transfer between accounts", Request: handlers.CreateTransferRequest{}, Response: domain.Transaction{}, Status: http.StatusCreated},
+	"POST /api/transactions/external-transfer":        {Summary: "Record a transfer with an untracked account", Request: handlers.CreateExternalTransferRequest{}, Response: domain.Transaction{}, Status: http.StatusCreated},
+	"POST /api/transactions/{id}/link-transfer":       {Summary: "Link two transactions as a transfer", Request: handlers.LinkTransferRequest{}, Response: domain.Transaction{}},
+	"PUT /api/transactions/{id}/original-amount":      {Summary: "Set what a foreign-currency transaction was before conversion", Request: handlers.SetOriginalAmountRequest{}, Response: domain.Transaction{}},
+	"POST /api/transactions/adjustment":               {Summary: "Adjust an account's balance", Request: handlers.CreateAdjustmentRequest{}, Response: domain.Transaction{}, Status: http.StatusCreated},
+	"GET /api/transactions":                           {Summary: "List transactions", Query: transactionFilters, Response: []domain.Transaction{}},
+	"GET /api/transactions/search":                    {Summary: "Search transactions by description, note or payee", Required: []string{"q"}, Query: transactionFilters, Response: []domain.Transaction{}},
+	"GET /api/transactions/{id}":                      {Summary: "Get a transaction", Response: domain.Transaction{}},
+	"GET /api/transactions/{id}/category-suggestions": {Summary: "Suggest up to three categories from how similar transactions were categorized", Response: []application.CategorySuggestion{}},
+	"PUT /api/transactions/{id}":                      {Summary: "Update a transaction", Request: handlers.UpdateTransactionRequest{}, Response: domain.Transaction{}},
+	"DELETE /api/transactions/{id}":                   {Summary: "Delete a transaction", Status: http.StatusNoContent},
+	"POST /api/transactions/bulk-categorize":          {Summary: "Categorize several transactions", Request: handlers.BulkCategorizeRequest{}, Status: http.StatusNoContent},
+	"PATCH /api/transactions/bulk":                    {Summary: "Change the payee, account or date of several transactions", Request: handlers.BulkEditTransactionsRequest{}, Response: application.TransactionBulkEditResult{}},
+
+	// Imports
+	"POST /api/transactions/import":      {Summary: "Import an OFX, QFX or QIF file", Form: []string{"account_id", "file", "lookback_days"}, Response: application.ImportResult{}},
+	"POST /api/import/csv":               {Summary: "Import a CSV file", Form: append([]string{"account_id", "file"}, csvMappingFields...), Response: application.ImportResult{}},
+	"POST /api/imports":                  {Summary: "Stage a file for review before importing it", Form: append([]string{"account_id", "file", "lookback_days"}, csvMappingFields...), Response: application.ImportReview{}, Status: http.StatusCreated},
+	"GET /api/imports":                   {Summary: "List imports", Query: []string{"status"}, Response: []domain.ImportFile{}},
+	"GET /api/imports/{id}":              {Summary: "Review an import", Response: application.ImportReview{}},
+	"PUT /api/imports/{id}/rows/{rowId}": {Summary: "Change a staged import row", Request: handlers.UpdateImportRowRequest{}, Response: domain.ImportFileRow{}},
+	"POST /api/imports/{id}/commit":      {Summary: "Commit a staged import to the ledger", Response: application.ImportResult{}},
+	"POST /api/imports/{id}/undo":        {Summary: "Undo a committed import", Response: domain.ImportFile{}},
+	"DELETE /api/imports/{id}":           {Summary: "Delete an import, undoing it if it was committed", Status: http.StatusNoContent},
+	"GET /api/imports/settings":          {Summary: "Get import matching settings", Response: domain.ImportMatchingSettings{}},
+	"PUT /api/imports/settings":          {Summary: "Turn merging imports into matching manual entries on or off", Request: domain.ImportMatchingSettings{}, Response: domain.ImportMatchingSettings{}},
+
+	// Pending transactions and integrations
+	"GET /api/pending-transactions":               {Summary: "List pending transactions", Query: []string{"status"}, Response: []domain.PendingTransaction{}},
+	"GET /api/pending-transactions/{id}":          {Summary: "Get a pending transaction", Response: domain.PendingTransaction{}},
+	"POST /api/pending-transactions/{id}/approve": {Summary: "Approve a pending transaction", Request: handlers.ApprovePendingTransactionRequest{}, Response: domain.Transaction{}, Status: http.StatusCreated},
+	"POST /api/pending-transactions/{id}/reject":  {Summary: "Reject a pending transaction", Response: domain.PendingTransaction{}},
+	"POST /api/integrations/email":                {Summary: "Receive a transaction alert email (X-Webhook-Secret header or token parameter)", Query: []string{"token", "account_id"}, Request: handlers.InboundEmailRequest{}, Form: []string{"sender", "from", "subject", "body-plain", "text", "body-html", "html"}, Response: domain.PendingTransaction{}, Status: http.StatusCreated},
+	"GET /api/integrations/mqtt/settings":         {Summary: "Get MQTT settings", Response: domain.MQTTSettings{}},
+	"PUT /api/integrations/mqtt/settings":         {Summary: "Update MQTT settings", Request: domain.MQTTSettings{}, Response: domain.MQTTSettings{}},
+
+	// Bot
+	"POST /api/bot/chats":        {Summary: "Register a bot chat", Request: handlers.CreateBotChatRequest{}, Response: handlers.CreateBotChatResponse{}, Status: http.StatusCreated},
+	"GET /api/bot/chats":         {Summary: "List bot chats", Response: []domain.BotChat{}},
+	"DELETE /api/bot/chats/{id}": {Summary: "Delete a bot chat", Status: http.StatusNoContent},
+	"POST /api/bot/message":      {Summary: "Send a message to the bot (bearer chat token)", Request: handlers.BotMessageRequest{}, Response: application.BotReply{}},
+	"GET /api/bot/summary":       {Summary: "Daily summary for a bot chat (bearer chat token)", Query: []string{"date"}, Response: application.BotDailySummary{}},
+
+	// Widgets
+	"POST /api/widgets":               {Summary: "Create a widget showing chosen fields of a category", Request: handlers.CreateWidgetRequest{}, Response: handlers.CreateWidgetResponse{}, Status: http.StatusCreated},
+	"GET /api/widgets":                {Summary: "List widgets", Response: []domain.Widget{}},
+	"PUT /api/widgets/{id}":           {Summary: "Change a widget's name, category or fields", Request: handlers.UpdateWidgetRequest{}, Response: domain.Widget{}},
+	"DELETE /api/widgets/{id}":        {Summary: "Delete a widget, revoking its token", Status: http.StatusNoContent},
+	"GET /api/public/widgets/{token}": {Summary: "A widget's fields, for embedding (the token is the credential; rate-limited)", Response: application.WidgetData{}},
+
+	// Allocations
+	"POST /api/allocations":                   {Summary: "Assign money to a category for a period", Request: handlers.CreateAllocationRequest{}, Response: domain.Allocation{}, Status: http.StatusCreated},
+	"POST /api/allocations/cover-underfunded": {Summary: "Cover an underfunded credit card payment category", Request: handlers.CoverUnderfundedRequest{}, Response: coverUnderfundedResponse{}, Status: http.StatusCreated},
+	"GET /api/allocations":                    {Summary: "List allocations", Query: []string{"period"}, Response: []domain.Allocation{}},
+	"GET /api/allocations/summary":            {Summary: "Budget summary for a period, by category or by group (view=groups)", Required: []string{"period"}, Query: []string{"view", "expand"}, Response: allocationSummaryResponse{}},
+	"GET /api/allocations/ready-to-assign":    {Summary: "Ready to Assign for a period", Required: []string{"period"}, Response: readyToAssignResponse{}},
+	"GET /api/allocations/{id}":               {Summary: "Get an allocation", Response: domain.Allocation{}},
+	"DELETE /api/allocations/{id}":            {Summary: "Delete an allocation", Status: http.StatusNoContent},
+	"GET /api/periods/{period}/close-preview": {Summary: "Preview what carries over when a period closes", Response: application.PeriodClosePreview{}},
+	"POST /api/periods/{period}/fund":         {Summary: "Fund a period's categories", Request: handlers.FundPeriodRequest{}, Response: application.FundPeriodResult{}},
+
+	// Allocation templates
+	"POST /api/allocation-templates":            {Summary: "Create an allocation template", Request: handlers.CreateAllocationTemplateRequest{}, Response: domain.AllocationTemplate{}, Status: http.StatusCreated},
+	"GET /api/allocation-templates":             {Summary: "List allocation templates", Response: []domain.AllocationTemplate{}},
+	"GET /api/allocation-templates/{id}":        {Summary: "Get an allocation template", Response: domain.AllocationTemplate{}},
+	"PUT /api/allocation-templates/{id}":        {Summary: "Update an allocation template", Request: handlers.UpdateAllocationTemplateRequest{}, Response: domain.AllocationTemplate{}},
+	"DELETE /api/allocation-templates/{id}":     {Summary: "Delete an allocation template", Status: http.StatusNoContent},
+	"POST /api/allocation-templates/{id}/apply": {Summary: "Apply an allocation template to a period", Request: handlers.ApplyAllocationTemplateRequest{}, Response: application.ApplyTemplateResult{}},
+
+	// Budget templates
+	"GET /api/budget-template":         {Summary: "Export the budget as a shareable template", Query: []string{"name", "period", "months"}, Response: application.BudgetTemplate{}},
+	"POST /api/budget-template/import": {Summary: "Import a budget template", Request: application.BudgetTemplate{}, Response: application.BudgetTemplateImportResult{}, Status: http.StatusCreated},
+
+	// Full export
+	"GET /api/export":         {Summary: "Export the whole budget", Response: application.BudgetExport{}},
+	"POST /api/export/import": {Summary: "Import a full export into an empty budget", Request: application.BudgetExport{}, Response: application.BudgetExportImportResult{}, Status: http.StatusCreated},
+	"POST /api/import/ynab":   {Summary: "Import YNAB's register and budget CSV exports into an empty budget", Form: []string{"file", "budget_file", "account_types"}, Response: application.BudgetExportImportResult{}, Status: http.StatusCreated},
+
+	// Reports (sent with an ETag; If-None-Match gets a 304 while the report is unchanged)
+	"GET /api/reports/bare-bones":                {Summary: "Minimum monthly need from essential and debt categories", Required: []string{"period"}, Query: []string{"months"}, Response: application.BareBonesBudget{}},
+	"GET /api/reports/emergency-fund":            {Summary: "Months of essential spending the emergency fund covers", Required: []string{"period"}, Query: []string{"months"}, Response: application.EmergencyFundCoverage{}},
+	"GET /api/reports/emergency-fund/settings":   {Summary: "Get emergency fund settings", Response: domain.EmergencyFundSettings{}},
+	"PUT /api/reports/emergency-fund/settings":   {Summary: "Update emergency fund settings", Request: domain.EmergencyFundSettings{}, Response: domain.EmergencyFundSettings{}},
+	"GET /api/reports/trends":                    {Summary: "Monthly income and spending, optionally adjusted for inflation", Required: []string{"start", "end"}, Query: []string{"adjust_for_inflation"}, Response: application.TrendReport{}},
+	"GET /api/reports/plugins/{plugin}/{report}": {Summary: "Run a plugin report; other query parameters are passed to the plugin", Required: []string{"period"}, Response: json.RawMessage{}},
+
+	// CPI
+	"GET /api/cpi":             {Summary: "List CPI values", Response: []domain.CPIEntry{}},
+	"POST /api/cpi":            {Summary: "Upload CPI values as JSON or CSV", Request: []domain.CPIEntry{}, CSV: true, Response: savedCPIResponse{}},
+	"POST /api/cpi/fetch":      {Summary: "Fetch CPI values from the configured provider", Request: handlers.FetchCPIRequest{}, Response: savedCPIResponse{}},
+	"DELETE /api/cpi/{period}": {Summary: "Delete a CPI value", Status: http.StatusNoContent},
+
+	// Sign-in and accounts
+	"POST /api/auth/login":                     {Summary: "Sign in", Request: handlers.LoginRequest{}, Response: handlers.LoginResponse{}},
+	"POST /api/auth/logout":                    {Summary: "Sign out", Status: http.StatusNoContent},
+	"GET /api/auth/me":                         {Summary: "The signed-in user and their preferences", Response: handlers.ProfileResponse{}},
+	"GET /api/auth/preferences":                {Summary: "Get the signed-in user's preferences", Response: domain.UserPreferences{}},
+	"PUT /api/auth/preferences":                {Summary: "Update the signed-in user's preferences", Request: domain.UserPreferences{}, Response: domain.UserPreferences{}},
+	"GET /api/auth/sessions":                   {Summary: "List the signed-in user's sessions and tokens", Response: handlers.SessionListResponse{}},
+	"DELETE /api/auth/sessions":                {Summary: "Sign out other sessions", Query: []string{"include_current"}, Status: http.StatusNoContent},
+	"DELETE /api/auth/sessions/{id}":           {Summary: "Sign out a session", Status: http.StatusNoContent},
+	"GET /api/auth/two-factor":                 {Summary: "Two-factor status", Response: application.TOTPStatus{}},
+	"POST /api/auth/two-factor/setup":          {Summary: "Start two-factor enrollment", Response: application.TOTPEnrollment{}},
+	"POST /api/auth/two-factor/enable":         {Summary: "Confirm two-factor enrollment", Request: handlers.TwoFactorCodeRequest{}, Response: handlers.RecoveryCodesResponse{}},
+	"POST /api/auth/two-factor/disable":        {Summary: "Turn off two-factor", Request: handlers.DisableTwoFactorRequest{}, Status: http.StatusNoContent},
+	"POST /api/auth/two-factor/recovery-codes": {Summary: "Replace two-factor recovery codes", Request: handlers.TwoFactorCodeRequest{}, Response: handlers.RecoveryCodesResponse{}},
+	"POST /api/auth/password-reset":            {Summary: "Email a password reset link", Request: handlers.PasswordResetRequest{}, Status: http.StatusAccepted},
+	"POST /api/auth/password-reset/confirm":    {Summary: "Reset a password", Request: handlers.ConfirmPasswordResetRequest{}, Status: http.StatusNoContent},
+	"POST /api/auth/verify-email":              {Summary: "Email a verification link to the signed-in user", Status: http.StatusAccepted},
+	"POST /api/auth/verify-email/confirm":      {Summary: "Verify an email address", Request: handlers.VerifyEmailRequest{}, Response: domain.User{}},
+	"PUT /api/users/{id}/two-factor":           {Summary: "Require a user to use two-factor", Request: handlers.TwoFactorRequirementRequest{}, Response: domain.User{}},
+	"GET /api/users":                           {Summary: "List users", Response: []domain.User{}},
+	"POST /api/users/{id}/disable":             {Summary: "Stop a user signing in, ending their sessions and switching off their API tokens", Response: domain.User{}},
+	"POST /api/users/{id}/enable":              {Summary: "Let a disabled user sign in again", Response: domain.User{}},
+	"POST /api/users/{id}/two-factor/reset":    {Summary: "Turn off a user's two-factor, e.g. after they lose their authenticator", Response: domain.User{}},
+	"POST /api/users/{id}/transfer-ownership":  {Summary: "Make a user the budget's admin in place of the signed-in admin", Response: domain.User{}},
+
+	// Single sign-on (the browser follows the redirects)
+	"GET /api/auth/oidc":             {Summary: "Single sign-on provider", Response: handlers.SSOInfoResponse{}},
+	"GET /api/auth/oidc/login":       {Summary: "Start single sign-on", Status: http.StatusFound},
+	"GET /api/auth/oidc/callback":    {Summary: "Finish single sign-on", Query: []string{"code", "state", "error", "error_description"}, Status: http.StatusFound},
+	"POST /api/auth/oidc/two-factor": {Summary: "Finish single sign-on with a two-factor code", Request: handlers.TwoFactorCodeRequest{}, Response: handlers.LoginResponse{}},
+
+	// API tokens
+	"POST /api/tokens":              {Summary: "Create an API token", Request: handlers.CreateAPITokenRequest{}, Response: handlers.CreateAPITokenResponse{}, Status: http.StatusCreated},
+	"GET /api/tokens":               {Summary: "List API tokens", Response: []domain.APIToken{}},
+	"DELETE /api/tokens/{id}":       {Summary: "Delete an API token", Status: http.StatusNoContent},
+	"POST /api/tokens/{id}/disable": {Summary: "Disable an API token", Response: domain.APIToken{}},
+	"POST /api/tokens/{id}/enable":  {Summary: "Enable an API token", Response: domain.APIToken{}},
+
+	// First-run setup
+	"GET /api/bootstrap":       {Summary: "Starter template status", Query: []string{"locale"}, Response: application.BootstrapStatus{}},
+	"POST /api/bootstrap":      {Summary: "Apply a starter template", Request: handlers.ApplyTemplateRequest{}, Response: domain.StarterTemplateSettings{}, Status: http.StatusCreated},
+	"GET /api/setup":           {Summary: "Setup wizard status", Query: []string{"locale"}, Response: application.SetupStatus{}},
+	"POST /api/setup/admin":    {Summary: "Create the first admin user", Request: handlers.SetupAdminRequest{}, Response: handlers.LoginResponse{}, Status: http.StatusCreated},
+	"POST /api/setup/template": {Summary: "Choose a starter template", Request: handlers.ApplyTemplateRequest{}, Response: domain.StarterTemplateSettings{}, Status: http.StatusCreated},
+	"POST /api/setup/account":  {Summary: "Create the first account", Request: handlers.CreateAccountRequest{}, Response: domain.Account{}, Status: http.StatusCreated},
+
+	// Currency
+	"GET /api/currency": {Summary: "The currency amounts are kept in, and its decimal places", Response: domain.Currency{}},
+
+	// Feature flags and plugins
+	"GET /api/features":           {Summary: "Which features are enabled", Response: map[domain.FeatureFlag]bool{}},
+	"GET /api/admin/flags":        {Summary: "List feature flags", Response: []application.FeatureFlagStatus{}},
+	"PUT /api/admin/flags/{flag}": {Summary: "Turn a feature flag on or off", Request: handlers.SetFeatureFlagRequest{}, Response: application.FeatureFlagStatus{}},
+	"GET /api/plugins":            {Summary: "List plugins", Response: []application.PluginInfo{}},
+
+	// Administration
+	"GET /api/admin/scripts/categorize":                   {Summary: "Get the categorize script", Response: application.CategorizeScript{}},
+	"PUT /api/admin/scripts/categorize":                   {Summary: "Set the categorize script", Request: handlers.SetScriptRequest{}, Response: application.CategorizeScript{}},
+	"POST /api/admin/scripts/categorize/test":             {Summary: "Try a categorize script on a sample transaction", Request: handlers.TestScriptRequest{}, Response: application.ScriptTestResult{}},
+	"GET /api/admin/audit":                                {Summary: "Audit log", Query: []string{"cursor", "limit", "since", "until"}, Response: application.AuditPage{}},
+	"GET /api/activity":                                   {Summary: "Recent budget changes", Query: []string{"cursor", "limit", "since"}, Response: application.ActivityPage{}},
+	"POST /api/admin/maintenance":                         {Summary: "Optimize the database, optionally vacuuming it", Request: handlers.RunMaintenanceRequest{}, Response: application.MaintenanceReport{}},
+	"GET /api/admin/retention":                            {Summary: "Data retention report", Response: application.RetentionReport{}},
+	"GET /api/admin/diagnostics":                          {Summary: "Check the budget's data for inconsistencies", Response: application.Diagnostics{}},
+	"POST /api/admin/diagnostics/ready-to-assign/rebuild": {Summary: "Rebuild the Ready to Assign totals", Response: application.Diagnostics{}},
+	"POST /api/admin/accounts/{id}/backfill-fitids":       {Summary: "Give an account's transactions without a FitID the FitIDs of matching transactions in an OFX file", Form: []string{"file", "dry_run"}, Response: application.FitIDBackfillResult{}},
+}
+
+// Responses the handlers build as maps
+
+type accountSummaryResponse struct {
+	TotalBalance int64 `json:"total_balance"`
+}
+
+type readyToAssignResponse struct {
+	ReadyToAssign int64 `json:"ready_to_assign"`
+}
+
+type coverUnderfundedResponse struct {
+	application.UnderfundedCover
+	ReadyToAssignAfter int64 `json:"ready_to_assign_after"`
+}
+
+type coverOverspendingResponse struct {
+	application.CoverOverspendingResult
+	ReadyToAssignAfter int64 `json:"ready_to_assign_after"`
+}
+
+type allocationSummaryResponse struct {
+	Categories    []*domain.AllocationSummary      `json:"categories,omitempty"` // view=categories, the default
+	Groups        []*domain.AllocationGroupSummary `json:"groups,omitempty"`     // view=groups
+	Period        domain.Period                    `json:"period"`               // The month, week or quarter summarized
+	ReadyToAssign *int64                           `json:"ready_to_assign"`      // null when it can't be calculated
+	Degraded      bool                             `json:"degraded"`             // Some parts were left out; see errors
+	Errors        []string                         `json:"errors"`               // Parts left out, e.g. ready_to_assign or goal
+}
+
+type savedCPIResponse struct {
+	Saved int `json:"saved"`
+}
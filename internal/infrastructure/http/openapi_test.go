@@ -0,0 +1,129 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestAPIOperationsCoverRoutes(t *testing.T) {
+	source, err := os.ReadFile("router.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes := make(map[string]bool)
+	for _, match := range regexp.MustCompile(`mux\.HandleFunc\("([A-Z]+ /api/[^"]*)"`).FindAllStringSubmatch(string(source), -1) {
+		routes[match[1]] = true
+	}
+
+	for route := range routes {
+		if _, ok := apiOperations[route]; !ok {
+			t.Errorf("%s is missing from the OpenAPI document", route)
+		}
+	}
+	for pattern := range apiOperations {
+		if !routes[pattern] {
+			t.Errorf("%s is documented but not routed", pattern)
+		}
+	}
+}
+
+func TestBuildOpenAPI(t *testing.T) {
+	raw, err := json.Marshal(buildOpenAPI())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var document struct {
+		Paths      map[string]map[string]map[string]any `json:"paths"`
+		Components struct {
+			Schemas map[string]map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(raw, &document); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every reference resolves to a component
+	for _, ref := range regexp.MustCompile(`"\$ref":"#/components/schemas/([^"]+)"`).FindAllStringSubmatch(string(raw), -1) {
+		if document.Components.Schemas[ref[1]] == nil {
+			t.Errorf("reference to missing schema %s", ref[1])
+		}
+	}
+
+	get := document.Paths["/accounts/{id}"]["get"]
+	if !strings.Contains(mustJSON(t, get["responses"]), `"$ref":"#/components/schemas/Account"`) {
+		t.Errorf("expected an account response, got %s", mustJSON(t, get["responses"]))
+	}
+	if !strings.Contains(mustJSON(t, get["parameters"]), `"in":"path"`) {
+		t.Errorf("expected the id path parameter, got %s", mustJSON(t, get["parameters"]))
+	}
+	if _, ok := document.Paths["/openapi.json"]["get"]["security"]; !ok {
+		t.Error("expected the document itself to need no credential")
+	}
+
+	// json tags decide names, omitempty decides what's required and pointers may be null
+	account := document.Components.Schemas["Account"]
+	if !strings.Contains(mustJSON(t, account["required"]), `"created_at"`) || strings.Contains(mustJSON(t, account["properties"]), "PasswordHash") {
+		t.Errorf("unexpected account schema %s", mustJSON(t, account))
+	}
+	change := mustJSON(t, document.Components.Schemas["StartingBalanceChange"])
+	if !strings.Contains(change, `"anyOf":[{"$ref":"#/components/schemas/Account"},{"type":"null"}]`) {
+		t.Errorf("expected the account to be nullable, got %s", change)
+	}
+	if user := mustJSON(t, document.Components.Schemas["User"]); strings.Contains(user, "password") || strings.Contains(user, "totp_secret") {
+		t.Errorf("expected fields hidden from JSON to be left out, got %s", user)
+	}
+}
+
+func TestVersionedAPI(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/admin/flags", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Pattern + " " + r.URL.Path))
+	})
+	mux.HandleFunc("GET /api/openapi.json", ServeOpenAPI)
+	handler := VersionedAPI(NewAuthMiddleware(mux, nil, nil, true, "admin-secret"))
+
+	serve := func(path, credential string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		if credential != "" {
+			r.Header.Set("Authorization", "Bearer "+credential)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	// Versioned paths reach the same routes, behind the same checks
+	if w := serve("/api/v1/admin/flags", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected admin routes to need a credential under /api/v1, got %d", w.Code)
+	}
+	w := serve("/api/v1/admin/flags", "admin-secret")
+	if w.Code != http.StatusOK || w.Body.String() != "GET /api/admin/flags /api/admin/flags" || w.Header().Get("API-Version") != APIVersion {
+		t.Errorf("expected /api/v1 to serve the /api route, got %d %q", w.Code, w.Body.String())
+	}
+	if w := serve("/api/admin/flags", "admin-secret"); w.Code != http.StatusOK {
+		t.Errorf("expected the unversioned path to keep working, got %d", w.Code)
+	}
+	if w := serve("/api/v10/admin/flags", "admin-secret"); w.Code != http.StatusNotFound {
+		t.Errorf("expected an unknown version not to match, got %d", w.Code)
+	}
+
+	w = serve("/api/v1/openapi.json", "")
+	var document map[string]any
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &document) != nil || document["openapi"] != "3.1.0" {
+		t.Errorf("expected the OpenAPI document without a credential, got %d", w.Code)
+	}
+}
+
+func mustJSON(t *testing.T, v any) string {
+	t.Helper()
+	raw, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(raw)
+}
@@ -0,0 +1,314 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ValidateContract logs /api requests and responses that don't match the OpenAPI document
+// It's meant for development and staging, to catch handlers drifting from what the
+// document says: requests are checked before they're handled and responses after, and
+// nothing is ever rejected or changed. JSON response bodies are held in memory to be
+// checked. mux is the router, used to find each request's route pattern.
+func ValidateContract(mux *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		_, pattern := mux.Handler(r)
+		contract, err := openAPIContract()
+		if err != nil {
+			log.Printf("contract: %v", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+		operation, ok := contract.operation(pattern)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		for _, violation := range contract.checkRequest(r, operation) {
+			log.Printf("contract: %s %s: request %s", r.Method, r.URL.Path, violation)
+		}
+		recorder := &contractRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+		for _, violation := range contract.checkResponse(recorder, operation) {
+			log.Printf("contract: %s %s: response %s", r.Method, r.URL.Path, violation)
+		}
+	})
+}
+
+// openAPIContract is the OpenAPI document read back as JSON, the way clients see it
+var openAPIContract = sync.OnceValues(func() (*contract, error) {
+	document, err := openAPIDocument()
+	if err != nil {
+		return nil, err
+	}
+	var parsed struct {
+		Paths      map[string]map[string]map[string]any `json:"paths"`
+		Components struct {
+			Schemas map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(document, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to read the OpenAPI document: %w", err)
+	}
+	return &contract{paths: parsed.Paths, schemas: parsed.Components.Schemas}, nil
+})
+
+// contract checks requests and responses against the OpenAPI document
+type contract struct {
+	paths   map[string]map[string]map[string]any
+	schemas map[string]any
+}
+
+// operation finds the documented operation for a router pattern
+func (c *contract) operation(pattern string) (map[string]any, bool) {
+	method, route, ok := strings.Cut(pattern, " ")
+	if !ok {
+		return nil, false
+	}
+	operation, ok := c.paths[strings.TrimPrefix(route, "/api")][strings.ToLower(method)]
+	return operation, ok
+}
+
+// checkRequest checks a request's required query parameters and JSON body
+// The body is read and put back for the handler.
+func (c *contract) checkRequest(r *http.Request, operation map[string]any) []string {
+	var violations []string
+	parameters, _ := operation["parameters"].([]any)
+	for _, p := range parameters {
+		parameter, _ := p.(map[string]any)
+		if parameter["in"] == "query" && parameter["required"] == true && !r.URL.Query().Has(parameter["name"].(string)) {
+			violations = append(violations, fmt.Sprintf("is missing the %s query parameter", parameter["name"]))
+		}
+	}
+
+	schema, ok := contentSchema(operation["requestBody"], "application/json")
+	if !ok || r.Body == nil || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		return violations
+	}
+	raw, err := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(raw))
+	if err != nil {
+		return violations
+	}
+	value, err := decodeJSON(raw)
+	if err != nil {
+		return append(violations, "body isn't JSON: "+err.Error())
+	}
+	return append(violations, c.check(schema, value, "body", false)...)
+}
+
+// checkResponse checks a response's status and, for JSON responses, its body
+// Errors must be plain text; successful responses must use the documented status.
+func (c *contract) checkResponse(recorder *contractRecorder, operation map[string]any) []string {
+	status := recorder.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	contentType := recorder.Header().Get("Content-Type")
+	if status >= http.StatusBadRequest {
+		if !strings.HasPrefix(contentType, "text/plain") {
+			return []string{fmt.Sprintf("error %d is %q, not plain text", status, contentType)}
+		}
+		return nil
+	}
+
+	responses, _ := operation["responses"].(map[string]any)
+	response, ok := responses[strconv.Itoa(status)]
+	if !ok {
+		var documented []string
+		for code := range responses {
+			if code != "default" {
+				documented = append(documented, code)
+			}
+		}
+		sort.Strings(documented)
+		return []string{fmt.Sprintf("status %d isn't documented, only %s", status, strings.Join(documented, ", "))}
+	}
+	schema, ok := contentSchema(response, "application/json")
+	if !ok || !strings.HasPrefix(contentType, "application/json") || recorder.skipped {
+		return nil
+	}
+	value, err := decodeJSON(recorder.body.Bytes())
+	if err != nil {
+		return []string{"body isn't JSON: " + err.Error()}
+	}
+	return c.check(schema, value, "body", true)
+}
+
+// check validates a JSON value against a schema, returning where it doesn't match
+// Strict checking is for responses: "required" in the document means a field is always
+// sent, so only responses must have them, and only responses may not add properties
+// the schema doesn't list.
+func (c *contract) check(schema map[string]any, value any, at string, strict bool) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		component, _ := c.schemas[strings.TrimPrefix(ref, "#/components/schemas/")].(map[string]any)
+		return c.check(component, value, at, strict)
+	}
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		for _, alternative := range anyOf {
+			if alternative, _ := alternative.(map[string]any); len(c.check(alternative, value, at, strict)) == 0 {
+				return nil
+			}
+		}
+		return []string{fmt.Sprintf("%s matches none of its allowed schemas", at)}
+	}
+
+	types := schemaTypes(schema["type"])
+	if len(types) == 0 {
+		return nil // Any JSON value
+	}
+	actual := jsonType(value)
+	if !types[actual] && !(actual == "integer" && types["number"]) {
+		return []string{fmt.Sprintf("%s is %s, want %s", at, actual, strings.Join(sortedKeys(types), " or "))}
+	}
+
+	var violations []string
+	switch value := value.(type) {
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, ok := value[name.(string)]; strict && !ok {
+				violations = append(violations, fmt.Sprintf("%s.%s is missing", at, name))
+			}
+		}
+		additional, _ := schema["additionalProperties"].(map[string]any)
+		for _, name := range sortedKeys(value) {
+			property, documented := properties[name].(map[string]any)
+			switch {
+			case documented:
+				violations = append(violations, c.check(property, value[name], at+"."+name, strict)...)
+			case additional != nil:
+				violations = append(violations, c.check(additional, value[name], at+"."+name, strict)...)
+			case strict && properties != nil:
+				violations = append(violations, fmt.Sprintf("%s.%s isn't documented", at, name))
+			}
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range value {
+				violations = append(violations, c.check(items, item, fmt.Sprintf("%s[%d]", at, i), strict)...)
+			}
+		}
+	}
+	return violations
+}
+
+// contentSchema returns the schema of a request body or response's media type
+func contentSchema(body any, mediaType string) (map[string]any, bool) {
+	object, _ := body.(map[string]any)
+	content, _ := object["content"].(map[string]any)
+	media, _ := content[mediaType].(map[string]any)
+	schema, ok := media["schema"].(map[string]any)
+	return schema, ok
+}
+
+// schemaTypes returns the JSON types a schema's "type" allows
+func schemaTypes(value any) map[string]bool {
+	types := make(map[string]bool)
+	switch value := value.(type) {
+	case string:
+		types[value] = true
+	case []any:
+		for _, t := range value {
+			if t, ok := t.(string); ok {
+				types[t] = true
+			}
+		}
+	}
+	return types
+}
+
+// jsonType names the JSON type of a value decoded by decodeJSON
+func jsonType(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// decodeJSON decodes a JSON document, keeping numbers as written so integers can be told apart
+func decodeJSON(raw []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// contractRecorder passes a response through, keeping a copy of JSON bodies to check
+// Streamed responses aren't checked: once flushed, the copy is dropped.
+type contractRecorder struct {
+	http.ResponseWriter
+	status  int
+	body    bytes.Buffer
+	skipped bool
+}
+
+func (w *contractRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *contractRecorder) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.skipped && strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		w.body.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush sends what has been written so far and stops keeping a copy of the body
+func (w *contractRecorder) Flush() {
+	w.skipped = true
+	w.body.Reset()
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *contractRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
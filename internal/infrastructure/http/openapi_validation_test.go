@@ -0,0 +1,68 @@
+package http
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidateContract(t *testing.T) {
+	var logged bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&logged)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/accounts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.PathValue("id") == "drifted" {
+			w.Write([]byte(`{"id":"drifted","name":7,"nickname":"x"}`))
+			return
+		}
+		w.Write([]byte(`{"id":"a1","name":"Checking","type":"checking","balance":1200,"rewards_balance":0,"details":{"notes":""},"hidden_from_dashboard":false,"created_at":"2025-10-01T00:00:00Z","updated_at":"2025-10-01T00:00:00Z"}`))
+	})
+	mux.HandleFunc("POST /api/accounts", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+	})
+	mux.HandleFunc("GET /api/allocations/ready-to-assign", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	handler := ValidateContract(mux, mux)
+
+	serve := func(method, path, body string) string {
+		logged.Reset()
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		if body != "" {
+			r.Header.Set("Content-Type", "application/json")
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+		return logged.String()
+	}
+
+	if got := serve("GET", "/api/accounts/a1", ""); got != "" {
+		t.Errorf("expected a documented account to pass, got %q", got)
+	}
+	drifted := serve("GET", "/api/accounts/drifted", "")
+	for _, want := range []string{"body.name is integer, want string", "body.balance is missing", "body.nickname isn't documented"} {
+		if !strings.Contains(drifted, want) {
+			t.Errorf("expected %q logged, got %q", want, drifted)
+		}
+	}
+
+	request := serve("POST", "/api/accounts", `{"name":"Checking","balance":"12"}`)
+	if !strings.Contains(request, "request body.balance is string, want integer") {
+		t.Errorf("expected the request body checked, got %q", request)
+	}
+	if strings.Contains(request, "body.type is missing") {
+		t.Errorf("expected fields left out of requests to be let through, got %q", request)
+	}
+
+	status := serve("GET", "/api/allocations/ready-to-assign", "")
+	for _, want := range []string{"missing the period query parameter", "status 202 isn't documented"} {
+		if !strings.Contains(status, want) {
+			t.Errorf("expected %q logged, got %q", want, status)
+		}
+	}
+}
@@ -14,6 +14,36 @@ func NewRouter(
 	transactionHandler *handlers.TransactionHandler,
 	allocationHandler *handlers.AllocationHandler,
 	importHandler *handlers.ImportHandler,
+	reportHandler *handlers.ReportHandler,
+	cpiHandler *handlers.CPIHandler,
+	pendingTransactionHandler *handlers.PendingTransactionHandler,
+	botHandler *handlers.BotHandler,
+	mqttHandler *handlers.MQTTHandler,
+	apiTokenHandler *handlers.APITokenHandler,
+	authHandler *handlers.AuthHandler,
+	ssoHandler *handlers.SSOHandler,
+	bootstrapHandler *handlers.BootstrapHandler,
+	setupHandler *handlers.SetupHandler,
+	featureFlagHandler *handlers.FeatureFlagHandler,
+	pluginHandler *handlers.PluginHandler,
+	scriptHandler *handlers.ScriptHandler,
+	auditHandler *handlers.AuditHandler,
+	retentionHandler *handlers.RetentionHandler,
+	goalHandler *handlers.GoalHandler,
+	payeeHandler *handlers.PayeeHandler,
+	digestHandler *handlers.DigestHandler,
+	allocationTemplateHandler *handlers.AllocationTemplateHandler,
+	transferHintHandler *handlers.TransferHintHandler,
+	budgetTemplateHandler *handlers.BudgetTemplateHandler,
+	diagnosticsHandler *handlers.DiagnosticsHandler,
+	helpHandler *handlers.HelpHandler,
+	jobHandler *handlers.JobHandler,
+	exportHandler *handlers.ExportHandler,
+	bankSyncHandler *handlers.BankSyncHandler,
+	widgetHandler *handlers.WidgetHandler,
+	maintenanceHandler *handlers.MaintenanceHandler,
+	loanHandler *handlers.LoanHandler,
+	transferSuggestionHandler *handlers.TransferSuggestionHandler,
 ) *http.ServeMux {
 	mux := http.NewServeMux()
 
@@ -27,6 +57,9 @@ func NewRouter(
 		w.Write([]byte("OK"))
 	})
 
+	// OpenAPI document for the versioned API (/api/v1/openapi.json)
+	mux.HandleFunc("GET /api/openapi.json", ServeOpenAPI)
+
 	// Account routes
 	mux.HandleFunc("POST /api/accounts", accountHandler.CreateAccount)
 	mux.HandleFunc("GET /api/accounts", accountHandler.ListAccounts)
@@ -34,10 +67,22 @@ func NewRouter(
 	mux.HandleFunc("GET /api/accounts/{id}", accountHandler.GetAccount)
 	mux.HandleFunc("GET /api/accounts/{id}/transactions", transactionHandler.GetAccountTransactions)
 	mux.HandleFunc("PUT /api/accounts/{id}", accountHandler.UpdateAccount)
+	mux.HandleFunc("PUT /api/accounts/{id}/starting-balance", accountHandler.UpdateStartingBalance)
+	mux.HandleFunc("PUT /api/accounts/{id}/balance", transactionHandler.UpdateTrackingBalance)
+	mux.HandleFunc("PUT /api/accounts/{id}/hidden", accountHandler.SetHiddenFromDashboard)
+	mux.HandleFunc("POST /api/accounts/{id}/convert", accountHandler.ConvertAccountType)
+	mux.HandleFunc("POST /api/accounts/{id}/rewards", accountHandler.AdjustRewards)
+	mux.HandleFunc("POST /api/accounts/{id}/rewards/redeem", transactionHandler.RedeemRewards)
 	mux.HandleFunc("DELETE /api/accounts/{id}", accountHandler.DeleteAccount)
 
+	// Loan routes
+	mux.HandleFunc("PUT /api/accounts/{id}/loan", loanHandler.SetLoanTerms)
+	mux.HandleFunc("GET /api/accounts/{id}/amortization", loanHandler.GetAmortization)
+	mux.HandleFunc("POST /api/accounts/{id}/loan-payments", loanHandler.RecordLoanPayment)
+
 	// Category routes
 	mux.HandleFunc("POST /api/categories", categoryHandler.CreateCategory)
+	mux.HandleFunc("POST /api/categories/bulk", categoryHandler.CreateCategories)
 	mux.HandleFunc("GET /api/categories", categoryHandler.ListCategories)
 	mux.HandleFunc("GET /api/categories/{id}", categoryHandler.GetCategory)
 	mux.HandleFunc("PUT /api/categories/{id}", categoryHandler.UpdateCategory)
@@ -52,26 +97,243 @@ func NewRouter(
 	mux.HandleFunc("POST /api/category-groups/assign", categoryGroupHandler.AssignCategoryToGroup)
 	mux.HandleFunc("POST /api/category-groups/unassign/{id}", categoryGroupHandler.UnassignCategoryFromGroup)
 
+	// Category goal routes
+	mux.HandleFunc("POST /api/goals", goalHandler.CreateGoal)
+	mux.HandleFunc("GET /api/goals", goalHandler.ListGoals)
+	mux.HandleFunc("GET /api/goals/{id}", goalHandler.GetGoal)
+	mux.HandleFunc("PUT /api/goals/{id}", goalHandler.UpdateGoal)
+	mux.HandleFunc("DELETE /api/goals/{id}", goalHandler.DeleteGoal)
+
+	// Payee routes (rules assign payees and categories to imported transactions)
+	mux.HandleFunc("GET /api/payees", payeeHandler.ListPayees)
+	mux.HandleFunc("POST /api/payee-rules", payeeHandler.CreatePayeeRule)
+	mux.HandleFunc("POST /api/payee-rules/run", payeeHandler.RunPayeeRules)
+	mux.HandleFunc("GET /api/payee-rules", payeeHandler.ListPayeeRules)
+	mux.HandleFunc("GET /api/payee-rules/{id}", payeeHandler.GetPayeeRule)
+	mux.HandleFunc("PUT /api/payee-rules/{id}", payeeHandler.UpdatePayeeRule)
+	mux.HandleFunc("DELETE /api/payee-rules/{id}", payeeHandler.DeletePayeeRule)
+
+	// Background job routes (e.g. applying changed payee rules to past transactions)
+	mux.HandleFunc("GET /api/jobs", jobHandler.ListJobs)
+	mux.HandleFunc("GET /api/jobs/{id}", jobHandler.GetJob)
+
+	// Transfer hint routes (imported descriptions that are transfers to another account)
+	mux.HandleFunc("POST /api/accounts/{id}/transfer-hints", transferHintHandler.CreateTransferHint)
+	mux.HandleFunc("GET /api/accounts/{id}/transfer-hints", transferHintHandler.ListTransferHints)
+	mux.HandleFunc("DELETE /api/accounts/{id}/transfer-hints/{hintID}", transferHintHandler.DeleteTransferHint)
+
+	// Transfer suggestion routes (hand-entered transactions that look like both sides of a transfer)
+	mux.HandleFunc("POST /api/transfer-suggestions/scan", transferSuggestionHandler.ScanTransfers)
+	mux.HandleFunc("GET /api/transfer-suggestions", transferSuggestionHandler.ListTransferSuggestions)
+	mux.HandleFunc("GET /api/transfer-suggestions/{id}", transferSuggestionHandler.GetTransferSuggestion)
+	mux.HandleFunc("POST /api/transfer-suggestions/{id}/accept", transferSuggestionHandler.AcceptTransferSuggestion)
+	mux.HandleFunc("POST /api/transfer-suggestions/{id}/reject", transferSuggestionHandler.RejectTransferSuggestion)
+	mux.HandleFunc("GET /api/transfer-pairings", transferSuggestionHandler.ListTransferPairings)
+	mux.HandleFunc("DELETE /api/transfer-pairings/{id}", transferSuggestionHandler.DeleteTransferPairing)
+
+	// Bank sync routes (statements downloaded from the bank over OFX DirectConnect)
+	mux.HandleFunc("PUT /api/accounts/{id}/bank-connection", bankSyncHandler.SaveBankConnection)
+	mux.HandleFunc("GET /api/accounts/{id}/bank-connection", bankSyncHandler.GetBankConnection)
+	mux.HandleFunc("DELETE /api/accounts/{id}/bank-connection", bankSyncHandler.DeleteBankConnection)
+	mux.HandleFunc("POST /api/accounts/{id}/sync", bankSyncHandler.SyncAccount)
+
+	// Bank aggregation routes (accounts synced through an aggregator such as SimpleFIN Bridge)
+	mux.HandleFunc("POST /api/bank-aggregations", bankSyncHandler.ConnectAggregation)
+	mux.HandleFunc("GET /api/bank-aggregations", bankSyncHandler.ListAggregations)
+	mux.HandleFunc("DELETE /api/bank-aggregations/{id}", bankSyncHandler.DeleteAggregation)
+	mux.HandleFunc("GET /api/bank-aggregations/{id}/accounts", bankSyncHandler.ListAggregationAccounts)
+	mux.HandleFunc("PUT /api/bank-aggregations/{id}/accounts/{remoteID}", bankSyncHandler.LinkAggregationAccount)
+	mux.HandleFunc("POST /api/bank-aggregations/{id}/sync", bankSyncHandler.SyncAggregation)
+
+	// Weekly budget digest preview
+	mux.HandleFunc("GET /api/digest", digestHandler.GetDigest)
+
+	// In-app help for budget concepts, with examples from the budget's own numbers
+	mux.HandleFunc("GET /api/help/concepts", helpHandler.GetConcepts)
+
 	// Transaction routes
 	mux.HandleFunc("POST /api/transactions", transactionHandler.CreateTransaction)
 	mux.HandleFunc("POST /api/transactions/transfer", transactionHandler.CreateTransfer)
+	mux.HandleFunc("POST /api/transactions/external-transfer", transactionHandler.CreateExternalTransfer)
+	mux.HandleFunc("POST /api/transactions/{id}/link-transfer", transactionHandler.LinkTransfer)
+	mux.HandleFunc("PUT /api/transactions/{id}/original-amount", transactionHandler.SetOriginalAmount)
+	mux.HandleFunc("POST /api/transactions/adjustment", transactionHandler.CreateAdjustment)
 	mux.HandleFunc("GET /api/transactions", transactionHandler.ListTransactions)
+	mux.HandleFunc("GET /api/transactions/search", transactionHandler.SearchTransactions)
 	mux.HandleFunc("GET /api/transactions/{id}", transactionHandler.GetTransaction)
+	mux.HandleFunc("GET /api/transactions/{id}/category-suggestions", transactionHandler.SuggestCategories)
 	mux.HandleFunc("PUT /api/transactions/{id}", transactionHandler.UpdateTransaction)
 	mux.HandleFunc("DELETE /api/transactions/{id}", transactionHandler.DeleteTransaction)
 	mux.HandleFunc("POST /api/transactions/bulk-categorize", transactionHandler.BulkCategorizeTransactions)
+	mux.HandleFunc("PATCH /api/transactions/bulk", transactionHandler.BulkEditTransactions)
 
 	// Import routes
 	mux.HandleFunc("POST /api/transactions/import", importHandler.ImportTransactions)
+	mux.HandleFunc("POST /api/import/csv", importHandler.ImportCSV)
+
+	// Import review routes (files staged for review before they reach the ledger)
+	mux.HandleFunc("POST /api/imports", importHandler.StageImport)
+	mux.HandleFunc("GET /api/imports", importHandler.ListImports)
+	mux.HandleFunc("GET /api/imports/{id}", importHandler.GetImport)
+	mux.HandleFunc("PUT /api/imports/{id}/rows/{rowId}", importHandler.UpdateImportRow)
+	mux.HandleFunc("POST /api/imports/{id}/commit", importHandler.CommitImport)
+	mux.HandleFunc("POST /api/imports/{id}/undo", importHandler.UndoImport)
+	mux.HandleFunc("DELETE /api/imports/{id}", importHandler.DeleteImport)
+	mux.HandleFunc("GET /api/imports/settings", importHandler.GetMatchingSettings)
+	mux.HandleFunc("PUT /api/imports/settings", importHandler.UpdateMatchingSettings)
+
+	// Pending transaction routes (imports awaiting approval)
+	mux.HandleFunc("GET /api/pending-transactions", pendingTransactionHandler.ListPendingTransactions)
+	mux.HandleFunc("GET /api/pending-transactions/{id}", pendingTransactionHandler.GetPendingTransaction)
+	mux.HandleFunc("POST /api/pending-transactions/{id}/approve", pendingTransactionHandler.ApprovePendingTransaction)
+	mux.HandleFunc("POST /api/pending-transactions/{id}/reject", pendingTransactionHandler.RejectPendingTransaction)
+
+	// Integration routes
+	mux.HandleFunc("POST /api/integrations/email", pendingTransactionHandler.ReceiveEmail)
+	mux.HandleFunc("GET /api/integrations/mqtt/settings", mqttHandler.GetSettings)
+	mux.HandleFunc("PUT /api/integrations/mqtt/settings", mqttHandler.UpdateSettings)
+
+	// Bot routes (chat management, and the token-authenticated bot API)
+	mux.HandleFunc("POST /api/bot/chats", botHandler.CreateChat)
+	mux.HandleFunc("GET /api/bot/chats", botHandler.ListChats)
+	mux.HandleFunc("DELETE /api/bot/chats/{id}", botHandler.DeleteChat)
+	mux.HandleFunc("POST /api/bot/message", botHandler.HandleMessage)
+	mux.HandleFunc("GET /api/bot/summary", botHandler.GetSummary)
+
+	// Widget routes (token-protected read-only data for embedding in a website or overlay)
+	mux.HandleFunc("POST /api/widgets", widgetHandler.CreateWidget)
+	mux.HandleFunc("GET /api/widgets", widgetHandler.ListWidgets)
+	mux.HandleFunc("PUT /api/widgets/{id}", widgetHandler.UpdateWidget)
+	mux.HandleFunc("DELETE /api/widgets/{id}", widgetHandler.DeleteWidget)
+	mux.HandleFunc("GET /api/public/widgets/{token}", widgetHandler.GetWidgetData)
 
 	// Allocation routes
 	mux.HandleFunc("POST /api/allocations", allocationHandler.CreateAllocation)
 	mux.HandleFunc("POST /api/allocations/cover-underfunded", allocationHandler.CoverUnderfunded)
+	mux.HandleFunc("POST /api/categories/{id}/cover-overspending", allocationHandler.CoverOverspending)
+	mux.HandleFunc("GET /api/categories/{id}/inspector", allocationHandler.GetCategoryInspector)
+	mux.HandleFunc("GET /api/categories/{id}/moves", allocationHandler.ListCategoryMoves)
+	mux.HandleFunc("GET /api/periods/{period}/close-preview", allocationHandler.PreviewPeriodClose)
+	mux.HandleFunc("POST /api/periods/{period}/fund", allocationHandler.FundPeriod)
+	mux.HandleFunc("GET /api/accounts/{id}/payment-reconciliation", allocationHandler.ReconcileCardPayment)
+	mux.HandleFunc("POST /api/accounts/{id}/payment-reconciliation", allocationHandler.FixCardPayment)
 	mux.HandleFunc("GET /api/allocations", allocationHandler.ListAllocations)
 	mux.HandleFunc("GET /api/allocations/summary", allocationHandler.GetAllocationSummary)
 	mux.HandleFunc("GET /api/allocations/ready-to-assign", allocationHandler.GetReadyToAssign)
 	mux.HandleFunc("GET /api/allocations/{id}", allocationHandler.GetAllocation)
 	mux.HandleFunc("DELETE /api/allocations/{id}", allocationHandler.DeleteAllocation)
 
+	// Allocation template routes (named budget plans applied to any period)
+	mux.HandleFunc("POST /api/allocation-templates", allocationTemplateHandler.CreateTemplate)
+	mux.HandleFunc("GET /api/allocation-templates", allocationTemplateHandler.ListTemplates)
+	mux.HandleFunc("GET /api/allocation-templates/{id}", allocationTemplateHandler.GetTemplate)
+	mux.HandleFunc("PUT /api/allocation-templates/{id}", allocationTemplateHandler.UpdateTemplate)
+	mux.HandleFunc("DELETE /api/allocation-templates/{id}", allocationTemplateHandler.DeleteTemplate)
+	mux.HandleFunc("POST /api/allocation-templates/{id}/apply", allocationTemplateHandler.ApplyTemplate)
+
+	// Budget template routes (shareable category structure and monthly amounts)
+	mux.HandleFunc("GET /api/budget-template", budgetTemplateHandler.ExportTemplate)
+	mux.HandleFunc("POST /api/budget-template/import", budgetTemplateHandler.ImportTemplate)
+
+	// Full export routes (the whole budget, for moving it to another instance or from YNAB)
+	mux.HandleFunc("GET /api/export", exportHandler.Export)
+	mux.HandleFunc("POST /api/export/import", exportHandler.Import)
+	mux.HandleFunc("POST /api/import/ynab", exportHandler.ImportYNAB)
+
+	// Report routes
+	mux.HandleFunc("GET /api/reports/bare-bones", reportHandler.GetBareBonesBudget)
+	mux.HandleFunc("GET /api/reports/emergency-fund", reportHandler.GetEmergencyFundCoverage)
+	mux.HandleFunc("GET /api/reports/emergency-fund/settings", reportHandler.GetEmergencyFundSettings)
+	mux.HandleFunc("PUT /api/reports/emergency-fund/settings", reportHandler.UpdateEmergencyFundSettings)
+	mux.HandleFunc("GET /api/reports/trends", reportHandler.GetTrendReport)
+	mux.HandleFunc("GET /api/reports/plugins/{plugin}/{report}", pluginHandler.RunReport)
+
+	// CPI routes (inflation adjustment data)
+	mux.HandleFunc("GET /api/cpi", cpiHandler.ListCPI)
+	mux.HandleFunc("POST /api/cpi", cpiHandler.UploadCPI)
+	mux.HandleFunc("POST /api/cpi/fetch", cpiHandler.FetchCPI)
+	mux.HandleFunc("DELETE /api/cpi/{period}", cpiHandler.DeleteCPI)
+
+	// Auth routes
+	mux.HandleFunc("POST /api/auth/login", authHandler.Login)
+	mux.HandleFunc("POST /api/auth/logout", authHandler.Logout)
+	mux.HandleFunc("GET /api/auth/me", authHandler.Me)
+	mux.HandleFunc("GET /api/auth/preferences", authHandler.GetPreferences)
+	mux.HandleFunc("PUT /api/auth/preferences", authHandler.UpdatePreferences)
+	mux.HandleFunc("GET /api/auth/sessions", authHandler.ListSessions)
+	mux.HandleFunc("DELETE /api/auth/sessions", authHandler.RevokeAllSessions)
+	mux.HandleFunc("DELETE /api/auth/sessions/{id}", authHandler.RevokeSession)
+	mux.HandleFunc("GET /api/auth/two-factor", authHandler.GetTwoFactorStatus)
+	mux.HandleFunc("POST /api/auth/two-factor/setup", authHandler.SetupTwoFactor)
+	mux.HandleFunc("POST /api/auth/two-factor/enable", authHandler.EnableTwoFactor)
+	mux.HandleFunc("POST /api/auth/two-factor/disable", authHandler.DisableTwoFactor)
+	mux.HandleFunc("POST /api/auth/two-factor/recovery-codes", authHandler.RegenerateRecoveryCodes)
+	mux.HandleFunc("POST /api/auth/password-reset", authHandler.RequestPasswordReset)
+	mux.HandleFunc("POST /api/auth/password-reset/confirm", authHandler.ConfirmPasswordReset)
+	mux.HandleFunc("POST /api/auth/verify-email", authHandler.SendVerificationEmail)
+	mux.HandleFunc("POST /api/auth/verify-email/confirm", authHandler.VerifyEmail)
+	mux.HandleFunc("PUT /api/users/{id}/two-factor", authHandler.SetTwoFactorRequirement)
+	mux.HandleFunc("GET /api/users", authHandler.ListUsers)
+	mux.HandleFunc("POST /api/users/{id}/disable", authHandler.DisableUser)
+	mux.HandleFunc("POST /api/users/{id}/enable", authHandler.EnableUser)
+	mux.HandleFunc("POST /api/users/{id}/two-factor/reset", authHandler.ResetTwoFactor)
+	mux.HandleFunc("POST /api/users/{id}/transfer-ownership", authHandler.TransferOwnership)
+
+	// Single sign-on routes (OIDC)
+	mux.HandleFunc("GET /api/auth/oidc", ssoHandler.GetInfo)
+	mux.HandleFunc("GET /api/auth/oidc/login", ssoHandler.Login)
+	mux.HandleFunc("GET /api/auth/oidc/callback", ssoHandler.Callback)
+	mux.HandleFunc("POST /api/auth/oidc/two-factor", ssoHandler.TwoFactor)
+
+	// API token routes
+	mux.HandleFunc("POST /api/tokens", apiTokenHandler.CreateToken)
+	mux.HandleFunc("GET /api/tokens", apiTokenHandler.ListTokens)
+	mux.HandleFunc("DELETE /api/tokens/{id}", apiTokenHandler.DeleteToken)
+	mux.HandleFunc("POST /api/tokens/{id}/disable", apiTokenHandler.DisableToken)
+	mux.HandleFunc("POST /api/tokens/{id}/enable", apiTokenHandler.EnableToken)
+
+	// Starter template routes (first-run setup)
+	mux.HandleFunc("GET /api/bootstrap", bootstrapHandler.GetStatus)
+	mux.HandleFunc("POST /api/bootstrap", bootstrapHandler.ApplyTemplate)
+
+	// First-run setup wizard routes
+	mux.HandleFunc("GET /api/setup", setupHandler.GetStatus)
+	mux.HandleFunc("POST /api/setup/admin", setupHandler.CreateAdmin)
+	mux.HandleFunc("POST /api/setup/template", setupHandler.ChooseTemplate)
+	mux.HandleFunc("POST /api/setup/account", setupHandler.CreateAccount)
+
+	// Currency the budget's amounts are kept in
+	mux.HandleFunc("GET /api/currency", handlers.GetCurrency)
+
+	// Feature flag routes
+	mux.HandleFunc("GET /api/features", featureFlagHandler.GetEnabled)
+	mux.HandleFunc("GET /api/admin/flags", featureFlagHandler.ListFlags)
+	mux.HandleFunc("PUT /api/admin/flags/{flag}", featureFlagHandler.SetFlag)
+
+	// Plugin routes
+	mux.HandleFunc("GET /api/plugins", pluginHandler.ListPlugins)
+
+	// Scripting hook routes
+	mux.HandleFunc("GET /api/admin/scripts/categorize", scriptHandler.GetCategorizeScript)
+	mux.HandleFunc("PUT /api/admin/scripts/categorize", scriptHandler.SetCategorizeScript)
+	mux.HandleFunc("POST /api/admin/scripts/categorize/test", scriptHandler.TestCategorizeScript)
+
+	// Audit log routes
+	mux.HandleFunc("GET /api/admin/audit", auditHandler.ListAuditLog)
+
+	// Activity feed (recent budget changes from the audit log, for everyone sharing the budget)
+	mux.HandleFunc("GET /api/activity", auditHandler.ListActivity)
+
+	// Data retention routes
+	mux.HandleFunc("GET /api/admin/retention", retentionHandler.GetReport)
+
+	// Diagnostics routes (consistency checks on the budget's data)
+	mux.HandleFunc("GET /api/admin/diagnostics", diagnosticsHandler.GetDiagnostics)
+	mux.HandleFunc("POST /api/admin/diagnostics/ready-to-assign/rebuild", diagnosticsHandler.RebuildReadyToAssign)
+
+	// Maintenance routes
+	mux.HandleFunc("POST /api/admin/accounts/{id}/backfill-fitids", importHandler.BackfillFitIDs)
+	mux.HandleFunc("POST /api/admin/maintenance", maintenanceHandler.RunMaintenance)
+
 	return mux
 }
@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"time"
 
+	"github.com/billybbuffum/budget/internal/domain"
 	"github.com/google/uuid"
 )
 
@@ -45,7 +46,28 @@ func ValidatePeriodRange(period string) error {
 	if err != nil {
 		return fmt.Errorf("invalid period format")
 	}
+	return validatePeriodStart(periodTime)
+}
+
+// ValidateBudgetPeriod checks if the provided string is a budgeting period key:
+// a month (YYYY-MM), an ISO week (YYYY-Www) or a quarter (YYYY-Qn)
+func ValidateBudgetPeriod(period string) error {
+	_, err := domain.ParsePeriod(period)
+	return err
+}
+
+// ValidateBudgetPeriodRange checks a budgeting period key is within the same bounds as
+// ValidatePeriodRange, going by the day the period starts
+func ValidateBudgetPeriodRange(period string) error {
+	p, err := domain.ParsePeriod(period)
+	if err != nil {
+		return err
+	}
+	return validatePeriodStart(p.Start)
+}
 
+// validatePeriodStart checks a period starting at periodTime is within reasonable bounds
+func validatePeriodStart(periodTime time.Time) error {
 	// Calculate acceptable range
 	now := time.Now()
 	// Normalize to first day of month for fair comparison since periods are month-granular
@@ -1,6 +1,7 @@
 package validators
 
 import (
+	"fmt"
 	"testing"
 	"time"
 )
@@ -349,3 +350,27 @@ func TestValidateAmountBounds(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateBudgetPeriodRange(t *testing.T) {
+	now := time.Now()
+	year, week := now.ISOWeek()
+
+	for _, period := range []string{
+		now.Format("2006-01"),
+		fmt.Sprintf("%04d-W%02d", year, week),
+		fmt.Sprintf("%04d-Q%d", now.Year(), (int(now.Month())+2)/3),
+	} {
+		if err := ValidateBudgetPeriodRange(period); err != nil {
+			t.Errorf("ValidateBudgetPeriodRange(%s) unexpected error = %v", period, err)
+		}
+	}
+
+	if err := ValidateBudgetPeriodRange(fmt.Sprintf("%04d-Q1", now.Year()-3)); err == nil {
+		t.Error("ValidateBudgetPeriodRange() accepted a quarter three years ago")
+	}
+	for _, period := range []string{"", "2025-W54", "2025-Q0", "2025-13"} {
+		if err := ValidateBudgetPeriod(period); err == nil {
+			t.Errorf("ValidateBudgetPeriod(%q) expected error, got nil", period)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+)
+
+// APIVersion is the current version of the REST API
+// Clients use /api/v1; the unversioned /api paths the bundled web app calls are an
+// alias for the current version.
+const APIVersion = "v1"
+
+// versionedAPIPrefix is the path prefix of the current API version
+const versionedAPIPrefix = "/api/" + APIVersion
+
+// VersionedAPI serves /api/v1 requests from the routes registered under /api
+// It must run ahead of authentication, so route matching, token scopes and admin
+// checks see the same pattern whichever path a client used.
+func VersionedAPI(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest, ok := strings.CutPrefix(r.URL.Path, versionedAPIPrefix)
+		if !ok || (rest != "" && rest[0] != '/') {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		url := *r.URL
+		url.Path = "/api" + rest
+		if raw, ok := strings.CutPrefix(url.RawPath, versionedAPIPrefix); ok {
+			url.RawPath = "/api" + raw
+		}
+		versioned := r.Clone(r.Context())
+		versioned.URL = &url
+		versioned.RequestURI = url.RequestURI()
+
+		w.Header().Set("API-Version", APIVersion)
+		next.ServeHTTP(w, versioned)
+	})
+}
@@ -0,0 +1,51 @@
+package mqtt
+
+import (
+	"fmt"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+// Client publishes messages to an MQTT broker
+type Client struct {
+	client paho.Client
+}
+
+// NewClient connects to the broker at brokerURL (e.g. tcp://localhost:1883)
+// The connection is re-established automatically if it drops.
+func NewClient(brokerURL, clientID, username, password string) (*Client, error) {
+	opts := paho.NewClientOptions().
+		AddBroker(brokerURL).
+		SetClientID(clientID).
+		SetUsername(username).
+		SetPassword(password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectTimeout(10 * time.Second)
+
+	client := paho.NewClient(opts)
+	token := client.Connect()
+	if !token.WaitTimeout(15 * time.Second) {
+		return nil, fmt.Errorf("timed out connecting to MQTT broker")
+	}
+	if err := token.Error(); err != nil {
+		return nil, fmt.Errorf("failed to connect to MQTT broker: %w", err)
+	}
+
+	return &Client{client: client}, nil
+}
+
+// Publish sends payload to topic with QoS 1
+func (c *Client) Publish(topic string, payload []byte, retained bool) error {
+	token := c.client.Publish(topic, 1, retained, payload)
+	if !token.WaitTimeout(10 * time.Second) {
+		return fmt.Errorf("timed out publishing to %s", topic)
+	}
+	return token.Error()
+}
+
+// Close disconnects from the broker
+func (c *Client) Close() {
+	c.client.Disconnect(250)
+}
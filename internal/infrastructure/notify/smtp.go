@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/application"
+)
+
+// SMTPChannel sends notifications as plain-text email
+// STARTTLS is used automatically when the server offers it.
+type SMTPChannel struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+}
+
+// NewSMTPChannel creates an SMTP notification channel
+// Authentication is skipped when username is empty.
+func NewSMTPChannel(host string, port int, username, password, from string) *SMTPChannel {
+	return &SMTPChannel{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+func (c *SMTPChannel) Name() string {
+	return "smtp"
+}
+
+// Send delivers the notification to the email address in To
+func (c *SMTPChannel) Send(ctx context.Context, notification *application.Notification) error {
+	if strings.ContainsAny(notification.To, "\r\n") || strings.ContainsAny(notification.Subject, "\r\n") {
+		return fmt.Errorf("invalid email header")
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", c.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", notification.To)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", notification.Subject)
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(notification.Body, "\n", "\r\n"))
+
+	var auth smtp.Auth
+	if c.username != "" {
+		auth = smtp.PlainAuth("", c.username, c.password, c.host)
+	}
+
+	addr := net.JoinHostPort(c.host, fmt.Sprint(c.port))
+	if err := smtp.SendMail(addr, auth, c.from, []string{notification.To}, []byte(msg.String())); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package ofx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aclindsa/ofxgo"
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// maxStatementSize bounds how much of a bank's answer is read
+const maxStatementSize = 16 << 20
+
+// Client downloads statements from banks' OFX DirectConnect servers
+type Client struct {
+	http *http.Client
+}
+
+// NewClient creates an OFX DirectConnect client that gives banks timeout to answer
+func NewClient(timeout time.Duration) *Client {
+	return &Client{http: &http.Client{Timeout: timeout}}
+}
+
+// DownloadStatement signs on to the connection's bank and requests the account's statement
+// with the transactions posted since then
+// It returns the bank's answer as an OFX file, once the sign-on and statement have been
+// accepted. The password is only ever sent over https.
+func (c *Client) DownloadStatement(ctx context.Context, connection *domain.BankConnection, credentials domain.BankCredentials, since time.Time) ([]byte, error) {
+	if !strings.HasPrefix(connection.URL, "https://") {
+		return nil, fmt.Errorf("refusing to send the password to a server that isn't https")
+	}
+	client := &ofxgo.BasicClient{AppID: connection.AppID, AppVer: connection.AppVer}
+	if connection.OFXVersion != "" {
+		version, err := ofxgo.NewOfxVersion(connection.OFXVersion)
+		if err != nil {
+			return nil, err
+		}
+		client.SpecVersion = version
+	}
+
+	request, err := statementRequest(connection, credentials, since)
+	if err != nil {
+		return nil, err
+	}
+	request.SetClientFields(client)
+	body, err := request.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OFX request: %w", err)
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, connection.URL, body)
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header.Set("Content-Type", "application/x-ofx")
+	httpRequest.Header.Set("Accept", "application/ofx, application/x-ofx")
+	response, err := c.http.Do(httpRequest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach the bank: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bank answered %s", response.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(response.Body, maxStatementSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the bank's answer: %w", err)
+	}
+	if err := checkStatementResponse(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// statementRequest builds the sign-on and statement request for the connection's account
+func statementRequest(connection *domain.BankConnection, credentials domain.BankCredentials, since time.Time) (*ofxgo.Request, error) {
+	uid, err := ofxgo.RandomUID()
+	if err != nil {
+		return nil, err
+	}
+	request := &ofxgo.Request{
+		URL: connection.URL,
+		Signon: ofxgo.SignonRequest{
+			UserID:   ofxgo.String(connection.Username),
+			UserPass: ofxgo.String(credentials.Password),
+			Org:      ofxgo.String(connection.Org),
+			Fid:      ofxgo.String(connection.FID),
+		},
+	}
+	start := &ofxgo.Date{Time: since}
+
+	if connection.AccountType == domain.BankAccountCreditCard {
+		request.CreditCard = append(request.CreditCard, &ofxgo.CCStatementRequest{
+			TrnUID:     *uid,
+			CCAcctFrom: ofxgo.CCAcct{AcctID: ofxgo.String(credentials.AccountNumber)},
+			DtStart:    start,
+			Include:    true,
+		})
+		return request, nil
+	}
+
+	accountType, err := ofxgo.NewAcctType(connection.AccountType)
+	if err != nil {
+		return nil, err
+	}
+	request.Bank = append(request.Bank, &ofxgo.StatementRequest{
+		TrnUID: *uid,
+		BankAcctFrom: ofxgo.BankAcct{
+			BankID:   ofxgo.String(connection.BankID),
+			AcctID:   ofxgo.String(credentials.AccountNumber),
+			AcctType: accountType,
+		},
+		DtStart: start,
+		Include: true,
+	})
+	return request, nil
+}
+
+// checkStatementResponse returns the bank's reason for refusing the sign-on or statement
+// request, e.g. a wrong password, if it did
+func checkStatementResponse(data []byte) error {
+	preprocessed, err := NewParser().preprocessOFX(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("bank answered with something other than OFX: %w", err)
+	}
+	response, err := ofxgo.ParseResponse(preprocessed)
+	if err != nil {
+		return fmt.Errorf("bank answered with something other than OFX: %w", err)
+	}
+
+	statuses := []*ofxgo.Status{&response.Signon.Status}
+	for _, message := range response.Bank {
+		if statement, ok := message.(*ofxgo.StatementResponse); ok {
+			statuses = append(statuses, &statement.Status)
+		}
+	}
+	for _, message := range response.CreditCard {
+		if statement, ok := message.(*ofxgo.CCStatementResponse); ok {
+			statuses = append(statuses, &statement.Status)
+		}
+	}
+	for _, status := range statuses {
+		// Informational and warning statuses come with the statement
+		if status.Code == 0 || status.Severity != "ERROR" {
+			continue
+		}
+		message := string(status.Message)
+		if message == "" {
+			message, _ = status.CodeMeaning()
+		}
+		return fmt.Errorf("bank refused the request (code %d): %s", status.Code, message)
+	}
+	return nil
+}
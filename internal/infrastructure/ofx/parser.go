@@ -4,18 +4,24 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"math/big"
 	"strings"
 	"time"
 
 	"github.com/aclindsa/ofxgo"
+	"github.com/billybbuffum/budget/internal/domain"
 )
 
 // ParsedTransaction represents a transaction parsed from an OFX file
 type ParsedTransaction struct {
 	Date        time.Time
-	Amount      int64  // In cents
+	Amount      int64  // In the budget currency's minor units, e.g. cents
 	Description string
 	FitID       string // Financial institution transaction ID (for duplicate detection)
+
+	// For transactions in another currency: what was charged, in that currency's minor units
+	OriginalCurrency string // ISO 4217 code, empty for transactions in the statement's currency
+	OriginalAmount   int64
 }
 
 // ImportResult contains the result of parsing an OFX file
@@ -23,7 +29,7 @@ type ImportResult struct {
 	Transactions  []ParsedTransaction
 	AccountID     string // OFX account ID
 	Currency      string
-	LedgerBalance int64  // Current balance from OFX file (in cents), 0 if not available
+	LedgerBalance int64  // Current balance from OFX file (in minor units), 0 if not available
 }
 
 // Parser handles OFX file parsing
@@ -34,9 +40,22 @@ func NewParser() *Parser {
 	return &Parser{}
 }
 
+// DefaultLookbackDays is how many days of a file's transactions Parse keeps
+const DefaultLookbackDays = 90
+
 // Parse parses an OFX file and extracts transaction data
 // Only imports transactions from the last 90 days to avoid processing years of historical data
 func (p *Parser) Parse(reader io.Reader) (*ImportResult, error) {
+	return p.ParseSince(reader, time.Now().AddDate(0, 0, -DefaultLookbackDays))
+}
+
+// ParseAll parses an OFX file like Parse, keeping transactions of any age
+func (p *Parser) ParseAll(reader io.Reader) (*ImportResult, error) {
+	return p.ParseSince(reader, time.Time{})
+}
+
+// ParseSince parses an OFX file like Parse, keeping the transactions posted on or after cutoffDate
+func (p *Parser) ParseSince(reader io.Reader, cutoffDate time.Time) (*ImportResult, error) {
 	// Preprocess the file to handle non-standard line endings (e.g., OnPoint's \r\r\n)
 	preprocessed, err := p.preprocessOFX(reader)
 	if err != nil {
@@ -53,9 +72,6 @@ func (p *Parser) Parse(reader io.Reader) (*ImportResult, error) {
 		Transactions: []ParsedTransaction{},
 	}
 
-	// Calculate cutoff date (90 days ago)
-	cutoffDate := time.Now().AddDate(0, 0, -90)
-
 	// Process banking statements
 	if len(response.Bank) > 0 {
 		for _, msg := range response.Bank {
@@ -96,13 +112,10 @@ func (p *Parser) processBankStatement(stmt *ofxgo.StatementResponse, result *Imp
 
 	// Extract ledger balance if available
 	if stmt.BalAmt.Rat.Sign() != 0 {
-		balanceFloat := stmt.BalAmt.Rat.FloatString(2)
-		var balanceVal float64
-		fmt.Sscanf(balanceFloat, "%f", &balanceVal)
-		result.LedgerBalance = int64(balanceVal * 100)
+		result.LedgerBalance = toMinorUnits(&stmt.BalAmt.Rat, domain.BudgetCurrency().Code)
 	}
 
-	// Process transactions (only last 90 days)
+	// Process transactions (only those since the cutoff)
 	txList := stmt.BankTranList
 	if txList == nil {
 		return nil
@@ -137,13 +150,10 @@ func (p *Parser) processCreditCardStatement(stmt *ofxgo.CCStatementResponse, res
 
 	// Extract ledger balance if available
 	if stmt.BalAmt.Rat.Sign() != 0 {
-		balanceFloat := stmt.BalAmt.Rat.FloatString(2)
-		var balanceVal float64
-		fmt.Sscanf(balanceFloat, "%f", &balanceVal)
-		result.LedgerBalance = int64(balanceVal * 100)
+		result.LedgerBalance = toMinorUnits(&stmt.BalAmt.Rat, domain.BudgetCurrency().Code)
 	}
 
-	// Process transactions (only last 90 days)
+	// Process transactions (only those since the cutoff)
 	txList := stmt.BankTranList
 	if txList == nil {
 		return nil
@@ -171,12 +181,10 @@ func (p *Parser) parseTransaction(txn ofxgo.Transaction) (*ParsedTransaction, er
 	// Parse date
 	date := txn.DtPosted.Time
 
-	// Parse amount - convert from dollars to cents
-	// OFX amounts are in dollars as floating point
-	amountFloat := txn.TrnAmt.Rat.FloatString(2)
-	var amountVal float64
-	fmt.Sscanf(amountFloat, "%f", &amountVal)
-	amountCents := int64(amountVal * 100)
+	// Parse amount - convert from currency units to the budget currency's minor units
+	// OFX amounts are decimals; they're kept exact rather than going through a float,
+	// which can lose a cent (0.29 * 100 is 28.999...)
+	amountCents := toMinorUnits(&txn.TrnAmt.Rat, domain.BudgetCurrency().Code)
 
 	// Build description from Name and Memo
 	description := p.buildDescription(txn)
@@ -184,12 +192,40 @@ func (p *Parser) parseTransaction(txn ofxgo.Transaction) (*ParsedTransaction, er
 	// Get FiTID for duplicate detection
 	fitID := string(txn.FiTID)
 
-	return &ParsedTransaction{
+	parsed := &ParsedTransaction{
 		Date:        date,
 		Amount:      amountCents,
 		Description: description,
 		FitID:       fitID,
-	}, nil
+	}
+	p.convertCurrency(txn, parsed)
+	return parsed, nil
+}
+
+// convertCurrency records a foreign-currency transaction's original amount
+// CURRENCY means TRNAMT is in the foreign currency, so it's converted to the statement's
+// currency with CURRATE; ORIGCURRENCY means the bank already converted it.
+func (p *Parser) convertCurrency(txn ofxgo.Transaction, parsed *ParsedTransaction) {
+	if txn.Currency != nil {
+		if valid, _ := txn.Currency.Valid(); valid {
+			parsed.OriginalCurrency = txn.Currency.CurSym.String()
+			parsed.OriginalAmount = toMinorUnits(&txn.TrnAmt.Rat, parsed.OriginalCurrency)
+			parsed.Amount = toMinorUnits(new(big.Rat).Mul(&txn.TrnAmt.Rat, &txn.Currency.CurRate.Rat), domain.BudgetCurrency().Code)
+			return
+		}
+	}
+	if txn.OrigCurrency != nil {
+		if valid, _ := txn.OrigCurrency.Valid(); valid {
+			parsed.OriginalCurrency = txn.OrigCurrency.CurSym.String()
+			parsed.OriginalAmount = toMinorUnits(new(big.Rat).Quo(&txn.TrnAmt.Rat, &txn.OrigCurrency.CurRate.Rat), parsed.OriginalCurrency)
+		}
+	}
+}
+
+// toMinorUnits rounds an amount in currency units to the nearest minor unit of the currency,
+// e.g. cents, or whole yen
+func toMinorUnits(amount *big.Rat, currency string) int64 {
+	return domain.MoneyFromRat(amount, currency).Amount
 }
 
 // buildDescription creates a transaction description from OFX Name and Memo fields
@@ -213,9 +249,8 @@ func (p *Parser) buildDescription(txn ofxgo.Transaction) string {
 	}
 
 	// If neither exists, use transaction type
-	trnType := string(txn.TrnType)
-	if trnType != "" {
-		return trnType
+	if txn.TrnType.Valid() {
+		return txn.TrnType.String()
 	}
 
 	return "Unknown Transaction"
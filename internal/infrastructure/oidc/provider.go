@@ -0,0 +1,128 @@
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+
+	"github.com/billybbuffum/budget/internal/application"
+)
+
+// Provider signs users in with an OpenID Connect provider (Authelia, Keycloak, Google, ...)
+// using the authorization code flow with PKCE.
+type Provider struct {
+	name        string
+	issuer      string
+	config      oauth2.Config
+	verifier    *gooidc.IDTokenVerifier
+	groupsClaim string // ID token claim holding group/role values; empty to ignore groups
+}
+
+// NewProvider discovers the provider's endpoints from its issuer URL
+func NewProvider(ctx context.Context, name, issuer, clientID, clientSecret, redirectURL string, scopes []string, groupsClaim string) (*Provider, error) {
+	provider, err := gooidc.NewProvider(ctx, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover OIDC provider: %w", err)
+	}
+
+	return &Provider{
+		name:   name,
+		issuer: issuer,
+		config: oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       scopes,
+		},
+		verifier:    provider.Verifier(&gooidc.Config{ClientID: clientID}),
+		groupsClaim: groupsClaim,
+	}, nil
+}
+
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// AuthCodeURL returns the provider's sign-in URL for this attempt
+func (p *Provider) AuthCodeURL(state, nonce, codeVerifier string) string {
+	return p.config.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(codeVerifier))
+}
+
+// Exchange trades the authorization code for tokens and returns the verified ID token claims
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*application.IdentityClaims, error) {
+	token, err := p.config.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, fmt.Errorf("identity provider did not return an ID token")
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, fmt.Errorf("invalid ID token nonce")
+	}
+
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("failed to read ID token claims: %w", err)
+	}
+
+	result := &application.IdentityClaims{
+		Issuer:        p.issuer,
+		Subject:       idToken.Subject,
+		Email:         stringClaim(claims, "email"),
+		EmailVerified: boolClaim(claims, "email_verified"),
+		Name:          stringClaim(claims, "name"),
+	}
+	if result.Name == "" {
+		result.Name = stringClaim(claims, "preferred_username")
+	}
+	if p.groupsClaim != "" {
+		result.Groups = listClaim(claims, p.groupsClaim)
+	}
+	return result, nil
+}
+
+func stringClaim(claims map[string]any, name string) string {
+	s, _ := claims[name].(string)
+	return s
+}
+
+// boolClaim reads a boolean claim; some providers send "true" as a string
+func boolClaim(claims map[string]any, name string) bool {
+	switch v := claims[name].(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	}
+	return false
+}
+
+// listClaim reads a claim that may be a single string or a list of strings
+func listClaim(claims map[string]any, name string) []string {
+	switch v := claims[name].(type) {
+	case string:
+		return []string{v}
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
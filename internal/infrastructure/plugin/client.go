@@ -0,0 +1,222 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+// maxResponseSize limits how much of a plugin response is read
+const maxResponseSize = 10 << 20 // 10 MB
+
+// Client talks to a plugin running as a separate process over HTTP with JSON bodies
+//
+// The plugin protocol:
+//
+//	GET  /manifest          -> {"name", "hooks": [...], "reports": [{"id", "name", "description"}]}
+//	POST /import/transform  {"account_id", "transactions"} -> {"transactions"}
+//	POST /categorize        {"categories", "transactions"} -> {"category_ids"} (one per transaction, "" for none)
+//	POST /reports/{id}      {"period", "params", "accounts", ...} -> any JSON document
+//
+// Transaction dates are sent as YYYY-MM-DD and amounts in cents.
+type Client struct {
+	name     string
+	baseURL  string
+	client   *http.Client
+	manifest manifest
+}
+
+type manifest struct {
+	Name    string                     `json:"name"`
+	Hooks   []string                   `json:"hooks"`
+	Reports []application.PluginReport `json:"reports"`
+}
+
+type wireTransaction struct {
+	Date        string `json:"date"` // YYYY-MM-DD
+	Amount      int64  `json:"amount"`
+	Description string `json:"description"`
+	FitID       string `json:"fitid"`
+}
+
+type wireCategory struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	GroupID     string `json:"group_id,omitempty"`
+}
+
+type transformRequest struct {
+	AccountID    string            `json:"account_id"`
+	Transactions []wireTransaction `json:"transactions"`
+}
+
+type transformResponse struct {
+	Transactions []wireTransaction `json:"transactions"`
+}
+
+type categorizeRequest struct {
+	Categories   []wireCategory    `json:"categories"`
+	Transactions []wireTransaction `json:"transactions"`
+}
+
+type categorizeResponse struct {
+	CategoryIDs []string `json:"category_ids"`
+}
+
+// NewClient connects to the plugin at baseURL and reads its manifest
+func NewClient(ctx context.Context, name, baseURL string, timeout time.Duration) (*Client, error) {
+	if _, err := url.ParseRequestURI(baseURL); err != nil {
+		return nil, fmt.Errorf("invalid plugin URL %q: %w", baseURL, err)
+	}
+
+	c := &Client{
+		name:    name,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/manifest", nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.do(req, &c.manifest); err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	return c, nil
+}
+
+// Name returns the name the plugin was registered under
+func (c *Client) Name() string {
+	return c.name
+}
+
+// Supports reports whether the plugin's manifest lists the hook
+func (c *Client) Supports(hook string) bool {
+	for _, h := range c.manifest.Hooks {
+		if h == hook {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports returns the reports listed in the plugin's manifest
+func (c *Client) Reports() []application.PluginReport {
+	return c.manifest.Reports
+}
+
+// TransformImport sends imported transactions to the plugin and returns its version of them
+func (c *Client) TransformImport(ctx context.Context, accountID string, transactions []application.ImportedTransaction) ([]application.ImportedTransaction, error) {
+	var resp transformResponse
+	if err := c.post(ctx, "/import/transform", transformRequest{
+		AccountID:    accountID,
+		Transactions: toWire(transactions),
+	}, &resp); err != nil {
+		return nil, err
+	}
+
+	transformed := make([]application.ImportedTransaction, 0, len(resp.Transactions))
+	for _, txn := range resp.Transactions {
+		date, err := time.Parse("2006-01-02", txn.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q in transformed transaction", txn.Date)
+		}
+		transformed = append(transformed, application.ImportedTransaction{
+			Date:        date,
+			Amount:      txn.Amount,
+			Description: txn.Description,
+			FitID:       txn.FitID,
+		})
+	}
+	return transformed, nil
+}
+
+// Categorize asks the plugin for a category ID per transaction
+func (c *Client) Categorize(ctx context.Context, categories []*domain.Category, transactions []application.ImportedTransaction) ([]string, error) {
+	wireCategories := make([]wireCategory, 0, len(categories))
+	for _, category := range categories {
+		wc := wireCategory{ID: category.ID, Name: category.Name, Description: category.Description}
+		if category.GroupID != nil {
+			wc.GroupID = *category.GroupID
+		}
+		wireCategories = append(wireCategories, wc)
+	}
+
+	var resp categorizeResponse
+	if err := c.post(ctx, "/categorize", categorizeRequest{
+		Categories:   wireCategories,
+		Transactions: toWire(transactions),
+	}, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.CategoryIDs) != len(transactions) {
+		return nil, fmt.Errorf("expected %d category IDs, got %d", len(transactions), len(resp.CategoryIDs))
+	}
+	return resp.CategoryIDs, nil
+}
+
+// RunReport runs one of the plugin's reports and returns its JSON output
+func (c *Client) RunReport(ctx context.Context, reportID string, input *application.PluginReportInput) (json.RawMessage, error) {
+	var report json.RawMessage
+	if err := c.post(ctx, "/reports/"+url.PathEscape(reportID), input, &report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (c *Client) post(ctx context.Context, path string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.do(req, out)
+}
+
+func (c *Client) do(req *http.Request, out interface{}) error {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s returned %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+func toWire(transactions []application.ImportedTransaction) []wireTransaction {
+	wire := make([]wireTransaction, 0, len(transactions))
+	for _, txn := range transactions {
+		wire = append(wire, wireTransaction{
+			Date:        txn.Date.Format("2006-01-02"),
+			Amount:      txn.Amount,
+			Description: txn.Description,
+			FitID:       txn.FitID,
+		})
+	}
+	return wire
+}
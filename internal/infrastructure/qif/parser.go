@@ -0,0 +1,242 @@
+package qif
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/infrastructure/csv"
+)
+
+// ParsedTransaction represents a transaction parsed from a QIF file
+type ParsedTransaction struct {
+	Date        time.Time
+	Amount      int64 // In cents (positive=inflow, negative=outflow)
+	Description string
+	FitID       string // QIF has no transaction IDs, so this is derived from the record
+}
+
+// ImportResult contains the result of parsing a QIF file
+type ImportResult struct {
+	Transactions []ParsedTransaction
+	AccountType  string // From the !Type header, e.g. "Bank" or "CCard"
+}
+
+// accountTypes are the !Type sections that hold account transactions
+// Investment, category, class and memorized lists are skipped.
+var accountTypes = map[string]bool{
+	"bank":    true,
+	"cash":    true,
+	"ccard":   true,
+	"oth a":   true,
+	"oth l":   true,
+	"invoice": true,
+}
+
+// dateLayouts are tried in order; a layout is only used if it reads every date in the file
+// Month-first wins when a file could be either, as Quicken writes US dates.
+var dateLayouts = []string{
+	"1/2/2006",
+	"1/2/06",
+	"2/1/2006",
+	"2/1/06",
+	"2006-01-02",
+	"1-2-2006",
+	"2.1.2006",
+}
+
+// Parser handles QIF file parsing
+type Parser struct{}
+
+// NewParser creates a new QIF parser
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// record is one transaction's fields before dates and amounts are converted
+type record struct {
+	line   int
+	date   string
+	amount string
+	payee  string
+	memo   string
+	number string
+}
+
+// Parse reads the transactions from a QIF file
+func (p *Parser) Parse(reader io.Reader) (*ImportResult, error) {
+	scanner := bufio.NewScanner(reader)
+	result := &ImportResult{}
+
+	var records []record
+	var current record
+	inTransactions := false
+	started := false
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+
+		if strings.HasPrefix(text, "!") {
+			header := strings.ToLower(text)
+			if section, ok := strings.CutPrefix(header, "!type:"); ok {
+				section = strings.TrimSpace(section)
+				inTransactions = accountTypes[section]
+				if inTransactions && result.AccountType == "" {
+					result.AccountType = strings.TrimSpace(text[len("!type:"):])
+				}
+			} else if header != "!option:autoswitch" && header != "!clear:autoswitch" {
+				// !Account blocks describe the account the next !Type section belongs to
+				inTransactions = false
+			}
+			current = record{}
+			started = false
+			continue
+		}
+		if !inTransactions {
+			continue
+		}
+
+		if !started {
+			current = record{line: line}
+			started = true
+		}
+		value := strings.TrimSpace(text[1:])
+		switch text[0] {
+		case '^':
+			if current.date != "" || current.amount != "" {
+				records = append(records, current)
+			}
+			started = false
+		case 'D':
+			current.date = value
+		case 'T', 'U':
+			// U repeats T in newer Quicken exports; keep the first
+			if current.amount == "" {
+				current.amount = value
+			}
+		case 'P':
+			current.payee = value
+		case 'M':
+			current.memo = value
+		case 'N':
+			current.number = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read QIF file: %w", err)
+	}
+	if started && (current.date != "" || current.amount != "") {
+		// Some exports leave off the final ^
+		records = append(records, current)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("QIF file has no transactions")
+	}
+
+	layout, err := detectDateLayout(records)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Transactions = make([]ParsedTransaction, 0, len(records))
+	seen := make(map[string]int)
+	for _, rec := range records {
+		date, err := time.Parse(layout, normalizeDate(rec.date))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q", rec.line, rec.date)
+		}
+		date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+		amount, err := csv.ParseAmount(rec.amount)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", rec.line, err)
+		}
+
+		txn := ParsedTransaction{
+			Date:        date,
+			Amount:      amount,
+			Description: description(rec),
+		}
+
+		// Same record contents give the same ID on every import; repeats within the file
+		// are told apart by their occurrence
+		key := fmt.Sprintf("%s|%d|%s|%s", date.Format("2006-01-02"), amount, txn.Description, rec.number)
+		seen[key]++
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d", key, seen[key])))
+		txn.FitID = "qif-" + hex.EncodeToString(sum[:10])
+
+		result.Transactions = append(result.Transactions, txn)
+	}
+
+	return result, nil
+}
+
+// normalizeDate rewrites Quicken's dates into plain slashes
+// Quicken writes years after 1999 with an apostrophe ("1/15'25") and pads
+// single digits with spaces ("1/ 5' 5").
+func normalizeDate(value string) string {
+	value = strings.ReplaceAll(value, " ", "")
+	if monthDay, year, ok := strings.Cut(value, "'"); ok {
+		if len(year) == 1 {
+			year = "0" + year
+		}
+		return monthDay + "/" + year
+	}
+	return value
+}
+
+func detectDateLayout(records []record) (string, error) {
+	for _, layout := range dateLayouts {
+		matched := true
+		for _, rec := range records {
+			if _, err := time.Parse(layout, normalizeDate(rec.date)); err != nil {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return layout, nil
+		}
+	}
+	return "", fmt.Errorf("could not read the dates in the QIF file")
+}
+
+// description combines payee and memo the same way the OFX parser combines name and memo
+func description(rec record) string {
+	payee := strings.Join(strings.Fields(rec.payee), " ")
+	memo := strings.Join(strings.Fields(rec.memo), " ")
+
+	if payee != "" && memo != "" && payee != memo {
+		return fmt.Sprintf("%s - %s", payee, memo)
+	}
+	if payee != "" {
+		return payee
+	}
+	if memo != "" {
+		return memo
+	}
+	if rec.number != "" {
+		return "Check " + rec.number
+	}
+	return "Unknown Transaction"
+}
+
+// IsQIF reports whether the start of a file looks like QIF
+// QIF files open with a "!Type:", "!Account" or "!Option" header line.
+func IsQIF(head []byte) bool {
+	text := strings.TrimLeft(strings.TrimPrefix(string(head), "\ufeff"), " \t\r\n")
+	lower := strings.ToLower(text)
+	return strings.HasPrefix(lower, "!type:") ||
+		strings.HasPrefix(lower, "!account") ||
+		strings.HasPrefix(lower, "!option:")
+}
@@ -0,0 +1,107 @@
+package qif
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name         string
+		file         string
+		wantType     string
+		wantDates    []string
+		wantAmounts  []int64
+		wantDescribe []string
+		wantErr      string
+	}{
+		{
+			name:         "bank export",
+			file:         "!Type:Bank\r\nD01/15/2025\r\nT-1,234.56\r\nPLandlord\r\nMJanuary rent\r\nN1042\r\n^\r\nD01/16/2025\r\nT2,000.00\r\nPPaycheck\r\n^\r\n",
+			wantType:     "Bank",
+			wantDates:    []string{"2025-01-15", "2025-01-16"},
+			wantAmounts:  []int64{-123456, 200000},
+			wantDescribe: []string{"Landlord - January rent", "Paycheck"},
+		},
+		{
+			name:         "quicken apostrophe years and a missing final caret",
+			file:         "!Type:CCard\nD1/ 5' 5\nU-20.00\nT-20.00\nPGas\n^\nD12/31'24\nT-3.5\nMCoffee\n",
+			wantType:     "CCard",
+			wantDates:    []string{"2005-01-05", "2024-12-31"},
+			wantAmounts:  []int64{-2000, -350},
+			wantDescribe: []string{"Gas", "Coffee"},
+		},
+		{
+			name:         "day-first dates and account list before transactions",
+			file:         "!Account\nNChecking\nTBank\n^\n!Type:Bank\nD25/03/2025\nT-10\nPA\n^\nD03/04/2025\nT-20\nPB\n^\n",
+			wantType:     "Bank",
+			wantDates:    []string{"2025-03-25", "2025-04-03"},
+			wantAmounts:  []int64{-1000, -2000},
+			wantDescribe: []string{"A", "B"},
+		},
+		{
+			name:    "investment accounts only",
+			file:    "!Type:Invst\nD1/1/2025\nNBuy\nT100\n^\n",
+			wantErr: "QIF file has no transactions",
+		},
+		{
+			name:    "unreadable amount",
+			file:    "!Type:Bank\nD1/1/2025\nTabc\n^\n",
+			wantErr: `line 2: invalid amount "abc"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewParser().Parse(strings.NewReader(tt.file))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() unexpected error = %v", err)
+			}
+			if result.AccountType != tt.wantType {
+				t.Errorf("AccountType = %s, want %s", result.AccountType, tt.wantType)
+			}
+			if len(result.Transactions) != len(tt.wantDates) {
+				t.Fatalf("got %d transactions, want %d", len(result.Transactions), len(tt.wantDates))
+			}
+			for i, txn := range result.Transactions {
+				if got := txn.Date.Format("2006-01-02"); got != tt.wantDates[i] {
+					t.Errorf("transaction %d date = %s, want %s", i, got, tt.wantDates[i])
+				}
+				if txn.Amount != tt.wantAmounts[i] {
+					t.Errorf("transaction %d amount = %d, want %d", i, txn.Amount, tt.wantAmounts[i])
+				}
+				if txn.Description != tt.wantDescribe[i] {
+					t.Errorf("transaction %d description = %q, want %q", i, txn.Description, tt.wantDescribe[i])
+				}
+				if !strings.HasPrefix(txn.FitID, "qif-") {
+					t.Errorf("transaction %d FitID = %q, want a derived qif- ID", i, txn.FitID)
+				}
+			}
+		})
+	}
+}
+
+func TestIsQIF(t *testing.T) {
+	tests := []struct {
+		head string
+		want bool
+	}{
+		{"!Type:Bank\n", true},
+		{"\ufeff\r\n!type:CCard", true},
+		{"!Account\nNChecking", true},
+		{"!Option:AutoSwitch\n", true},
+		{"OFXHEADER:100\nDATA:OFXSGML", false},
+		{"Date,Description,Amount", false},
+	}
+	for _, tt := range tests {
+		if got := IsQIF([]byte(tt.head)); got != tt.want {
+			t.Errorf("IsQIF(%q) = %v, want %v", tt.head, got, tt.want)
+		}
+	}
+}
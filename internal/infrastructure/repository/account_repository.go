@@ -17,14 +17,18 @@ func NewAccountRepository(db *sql.DB) domain.AccountRepository {
 	return &accountRepository{db: db}
 }
 
+const accountColumns = `id, name, balance, type, rewards_balance, notes, account_number_last4, interest_rate_bps, hidden_from_dashboard, loan_monthly_payment, loan_interest_category_id, created_at, updated_at`
+
 func (r *accountRepository) Create(ctx context.Context, account *domain.Account) error {
 	query := `
-		INSERT INTO accounts (id, name, balance, type, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO accounts (id, name, balance, type, notes, account_number_last4, interest_rate_bps, hidden_from_dashboard, loan_monthly_payment, loan_interest_category_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	monthlyPayment, interestCategoryID := loanColumns(account.Loan)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
 		account.ID, account.Name, account.Balance, account.Type,
-		account.CreatedAt, account.UpdatedAt)
+		account.Details.Notes, account.Details.AccountNumberLast4, account.Details.InterestRateBps, account.HiddenFromDashboard,
+		monthlyPayment, interestCategoryID, account.CreatedAt, account.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create account: %w", err)
 	}
@@ -33,14 +37,11 @@ func (r *accountRepository) Create(ctx context.Context, account *domain.Account)
 
 func (r *accountRepository) GetByID(ctx context.Context, id string) (*domain.Account, error) {
 	query := `
-		SELECT id, name, balance, type, created_at, updated_at
+		SELECT ` + accountColumns + `
 		FROM accounts
 		WHERE id = ?
 	`
-	account := &domain.Account{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&account.ID, &account.Name, &account.Balance, &account.Type,
-		&account.CreatedAt, &account.UpdatedAt)
+	account, err := scanAccount(conn(ctx, r.db).QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("account not found")
 	}
@@ -52,11 +53,11 @@ func (r *accountRepository) GetByID(ctx context.Context, id string) (*domain.Acc
 
 func (r *accountRepository) List(ctx context.Context) ([]*domain.Account, error) {
 	query := `
-		SELECT id, name, balance, type, created_at, updated_at
+		SELECT ` + accountColumns + `
 		FROM accounts
 		ORDER BY created_at DESC
 	`
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list accounts: %w", err)
 	}
@@ -64,9 +65,8 @@ func (r *accountRepository) List(ctx context.Context) ([]*domain.Account, error)
 
 	var accounts []*domain.Account
 	for rows.Next() {
-		account := &domain.Account{}
-		if err := rows.Scan(&account.ID, &account.Name, &account.Balance, &account.Type,
-			&account.CreatedAt, &account.UpdatedAt); err != nil {
+		account, err := scanAccount(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan account: %w", err)
 		}
 		accounts = append(accounts, account)
@@ -77,11 +77,15 @@ func (r *accountRepository) List(ctx context.Context) ([]*domain.Account, error)
 func (r *accountRepository) Update(ctx context.Context, account *domain.Account) error {
 	query := `
 		UPDATE accounts
-		SET name = ?, balance = ?, type = ?, updated_at = ?
+		SET name = ?, balance = ?, type = ?, notes = ?, account_number_last4 = ?, interest_rate_bps = ?, hidden_from_dashboard = ?,
+			loan_monthly_payment = ?, loan_interest_category_id = ?, updated_at = ?
 		WHERE id = ?
 	`
-	result, err := r.db.ExecContext(ctx, query,
-		account.Name, account.Balance, account.Type, account.UpdatedAt, account.ID)
+	monthlyPayment, interestCategoryID := loanColumns(account.Loan)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		account.Name, account.Balance, account.Type,
+		account.Details.Notes, account.Details.AccountNumberLast4, account.Details.InterestRateBps, account.HiddenFromDashboard,
+		monthlyPayment, interestCategoryID, account.UpdatedAt, account.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update account: %w", err)
 	}
@@ -95,9 +99,31 @@ func (r *accountRepository) Update(ctx context.Context, account *domain.Account)
 	return nil
 }
 
+// AdjustRewardsBalance adds delta to the account's rewards balance
+// Fails without changing anything if the balance would drop below zero. Update leaves the
+// rewards balance alone, so it is only ever changed here.
+func (r *accountRepository) AdjustRewardsBalance(ctx context.Context, id string, delta int64) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE accounts
+		SET rewards_balance = rewards_balance + ?
+		WHERE id = ? AND rewards_balance + ? >= 0
+	`, delta, id, delta)
+	if err != nil {
+		return fmt.Errorf("failed to update rewards balance: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("insufficient rewards balance")
+	}
+	return nil
+}
+
 func (r *accountRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM accounts WHERE id = ?`
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete account: %w", err)
 	}
@@ -112,11 +138,40 @@ func (r *accountRepository) Delete(ctx context.Context, id string) error {
 }
 
 func (r *accountRepository) GetTotalBalance(ctx context.Context) (int64, error) {
-	query := `SELECT COALESCE(SUM(balance), 0) FROM accounts`
+	query := `SELECT COALESCE(SUM(balance), 0) FROM accounts WHERE hidden_from_dashboard = 0`
 	var total int64
-	err := r.db.QueryRowContext(ctx, query).Scan(&total)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query).Scan(&total)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get total balance: %w", err)
 	}
 	return total, nil
 }
+
+// loanColumns returns the loan_monthly_payment and loan_interest_category_id values for terms
+func loanColumns(terms *domain.LoanTerms) (*int64, *string) {
+	if terms == nil {
+		return nil, nil
+	}
+	return &terms.MonthlyPayment, terms.InterestCategoryID
+}
+
+func scanAccount(row rowScanner) (*domain.Account, error) {
+	account := &domain.Account{}
+	var interestRate, monthlyPayment sql.NullInt64
+	var interestCategoryID sql.NullString
+	if err := row.Scan(&account.ID, &account.Name, &account.Balance, &account.Type, &account.RewardsBalance,
+		&account.Details.Notes, &account.Details.AccountNumberLast4, &interestRate, &account.HiddenFromDashboard,
+		&monthlyPayment, &interestCategoryID, &account.CreatedAt, &account.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if interestRate.Valid {
+		account.Details.InterestRateBps = &interestRate.Int64
+	}
+	if monthlyPayment.Valid {
+		account.Loan = &domain.LoanTerms{MonthlyPayment: monthlyPayment.Int64}
+		if interestCategoryID.Valid {
+			account.Loan.InterestCategoryID = &interestCategoryID.String
+		}
+	}
+	return account, nil
+}
@@ -22,7 +22,7 @@ func (r *allocationRepository) Create(ctx context.Context, allocation *domain.Al
 		INSERT INTO allocations (id, category_id, amount, period, notes, created_at, updated_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
 		allocation.ID, allocation.CategoryID, allocation.Amount, allocation.Period,
 		allocation.Notes, allocation.CreatedAt, allocation.UpdatedAt)
 	if err != nil {
@@ -38,7 +38,7 @@ func (r *allocationRepository) GetByID(ctx context.Context, id string) (*domain.
 		WHERE id = ?
 	`
 	allocation := &domain.Allocation{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
 		&allocation.ID, &allocation.CategoryID, &allocation.Amount, &allocation.Period,
 		&allocation.Notes, &allocation.CreatedAt, &allocation.UpdatedAt)
 	if err == sql.ErrNoRows {
@@ -57,7 +57,7 @@ func (r *allocationRepository) GetByCategoryAndPeriod(ctx context.Context, categ
 		WHERE category_id = ? AND period = ?
 	`
 	allocation := &domain.Allocation{}
-	err := r.db.QueryRowContext(ctx, query, categoryID, period).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, categoryID, period).Scan(
 		&allocation.ID, &allocation.CategoryID, &allocation.Amount, &allocation.Period,
 		&allocation.Notes, &allocation.CreatedAt, &allocation.UpdatedAt)
 	if err == sql.ErrNoRows {
@@ -76,7 +76,7 @@ func (r *allocationRepository) ListByPeriod(ctx context.Context, period string)
 		WHERE period = ?
 		ORDER BY created_at DESC
 	`
-	rows, err := r.db.QueryContext(ctx, query, period)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, period)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list allocations by period: %w", err)
 	}
@@ -91,7 +91,7 @@ func (r *allocationRepository) List(ctx context.Context) ([]*domain.Allocation,
 		FROM allocations
 		ORDER BY period DESC, created_at DESC
 	`
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list allocations: %w", err)
 	}
@@ -100,13 +100,38 @@ func (r *allocationRepository) List(ctx context.Context) ([]*domain.Allocation,
 	return r.scanAllocations(rows)
 }
 
+// SumByCategory totals every period's allocations per category
+func (r *allocationRepository) SumByCategory(ctx context.Context) (map[string]int64, error) {
+	query := `
+		SELECT category_id, SUM(amount)
+		FROM allocations
+		GROUP BY category_id
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum allocations: %w", err)
+	}
+	defer rows.Close()
+
+	totals := make(map[string]int64)
+	for rows.Next() {
+		var categoryID string
+		var total int64
+		if err := rows.Scan(&categoryID, &total); err != nil {
+			return nil, fmt.Errorf("failed to scan allocation total: %w", err)
+		}
+		totals[categoryID] = total
+	}
+	return totals, rows.Err()
+}
+
 func (r *allocationRepository) Update(ctx context.Context, allocation *domain.Allocation) error {
 	query := `
 		UPDATE allocations
 		SET category_id = ?, amount = ?, period = ?, notes = ?, updated_at = ?
 		WHERE id = ?
 	`
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
 		allocation.CategoryID, allocation.Amount, allocation.Period,
 		allocation.Notes, allocation.UpdatedAt, allocation.ID)
 	if err != nil {
@@ -124,7 +149,7 @@ func (r *allocationRepository) Update(ctx context.Context, allocation *domain.Al
 
 func (r *allocationRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM allocations WHERE id = ?`
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete allocation: %w", err)
 	}
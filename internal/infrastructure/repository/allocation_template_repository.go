@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type allocationTemplateRepository struct {
+	db *sql.DB
+}
+
+// NewAllocationTemplateRepository creates a new allocation template repository
+func NewAllocationTemplateRepository(db *sql.DB) domain.AllocationTemplateRepository {
+	return &allocationTemplateRepository{db: db}
+}
+
+func (r *allocationTemplateRepository) Create(ctx context.Context, template *domain.AllocationTemplate) error {
+	return inTx(ctx, r.db, func(ctx context.Context) error {
+		query := `
+			INSERT INTO allocation_templates (id, name, created_at, updated_at)
+			VALUES (?, ?, ?, ?)
+		`
+		if _, err := conn(ctx, r.db).ExecContext(ctx, query, template.ID, template.Name, template.CreatedAt, template.UpdatedAt); err != nil {
+			return fmt.Errorf("failed to create allocation template: %w", err)
+		}
+		return r.insertItems(ctx, template)
+	})
+}
+
+func (r *allocationTemplateRepository) GetByID(ctx context.Context, id string) (*domain.AllocationTemplate, error) {
+	template := &domain.AllocationTemplate{}
+	err := conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT id, name, created_at, updated_at FROM allocation_templates WHERE id = ?`, id,
+	).Scan(&template.ID, &template.Name, &template.CreatedAt, &template.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrAllocationTemplateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allocation template: %w", err)
+	}
+
+	items, err := r.listItems(ctx, `WHERE i.template_id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	template.Items = items[id]
+	if template.Items == nil {
+		template.Items = []domain.AllocationTemplateItem{}
+	}
+	return template, nil
+}
+
+func (r *allocationTemplateRepository) List(ctx context.Context) ([]*domain.AllocationTemplate, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT id, name, created_at, updated_at FROM allocation_templates ORDER BY name COLLATE NOCASE`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list allocation templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []*domain.AllocationTemplate
+	for rows.Next() {
+		template := &domain.AllocationTemplate{}
+		if err := rows.Scan(&template.ID, &template.Name, &template.CreatedAt, &template.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan allocation template: %w", err)
+		}
+		templates = append(templates, template)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	items, err := r.listItems(ctx, ``)
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range templates {
+		template.Items = items[template.ID]
+		if template.Items == nil {
+			template.Items = []domain.AllocationTemplateItem{}
+		}
+	}
+	return templates, nil
+}
+
+func (r *allocationTemplateRepository) Update(ctx context.Context, template *domain.AllocationTemplate) error {
+	return inTx(ctx, r.db, func(ctx context.Context) error {
+		result, err := conn(ctx, r.db).ExecContext(ctx,
+			`UPDATE allocation_templates SET name = ?, updated_at = ? WHERE id = ?`,
+			template.Name, template.UpdatedAt, template.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update allocation template: %w", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return domain.ErrAllocationTemplateNotFound
+		}
+
+		if _, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM allocation_template_items WHERE template_id = ?`, template.ID); err != nil {
+			return fmt.Errorf("failed to clear allocation template items: %w", err)
+		}
+		return r.insertItems(ctx, template)
+	})
+}
+
+func (r *allocationTemplateRepository) Delete(ctx context.Context, id string) error {
+	return inTx(ctx, r.db, func(ctx context.Context) error {
+		// Foreign keys aren't enforced on every connection, so the items go explicitly
+		if _, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM allocation_template_items WHERE template_id = ?`, id); err != nil {
+			return fmt.Errorf("failed to delete allocation template items: %w", err)
+		}
+		result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM allocation_templates WHERE id = ?`, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete allocation template: %w", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return domain.ErrAllocationTemplateNotFound
+		}
+		return nil
+	})
+}
+
+func (r *allocationTemplateRepository) insertItems(ctx context.Context, template *domain.AllocationTemplate) error {
+	for _, item := range template.Items {
+		if _, err := conn(ctx, r.db).ExecContext(ctx,
+			`INSERT INTO allocation_template_items (template_id, category_id, amount) VALUES (?, ?, ?)`,
+			template.ID, item.CategoryID, item.Amount); err != nil {
+			return fmt.Errorf("failed to save allocation template item: %w", err)
+		}
+	}
+	return nil
+}
+
+// listItems loads template items keyed by template ID, in the order of their categories
+func (r *allocationTemplateRepository) listItems(ctx context.Context, where string, args ...any) (map[string][]domain.AllocationTemplateItem, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT i.template_id, i.category_id, i.amount
+		FROM allocation_template_items i
+		LEFT JOIN categories c ON c.id = i.category_id
+		`+where+`
+		ORDER BY c.name`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list allocation template items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make(map[string][]domain.AllocationTemplateItem)
+	for rows.Next() {
+		var templateID string
+		var item domain.AllocationTemplateItem
+		if err := rows.Scan(&templateID, &item.CategoryID, &item.Amount); err != nil {
+			return nil, fmt.Errorf("failed to scan allocation template item: %w", err)
+		}
+		items[templateID] = append(items[templateID], item)
+	}
+	return items, rows.Err()
+}
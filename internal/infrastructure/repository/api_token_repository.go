@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type apiTokenRepository struct {
+	db *sql.DB
+}
+
+// NewAPITokenRepository creates a new API token repository
+func NewAPITokenRepository(db *sql.DB) domain.APITokenRepository {
+	return &apiTokenRepository{db: db}
+}
+
+const apiTokenColumns = `id, user_id, name, token_prefix, token_hash, access, account_ids, category_ids, expires_at, last_used_at, created_at,
+	daily_write_quota, write_count, write_count_date, disabled_at`
+
+func (r *apiTokenRepository) Create(ctx context.Context, token *domain.APIToken) error {
+	accountIDs, err := json.Marshal(token.AccountIDs)
+	if err != nil {
+		return fmt.Errorf("failed to encode account scope: %w", err)
+	}
+	categoryIDs, err := json.Marshal(token.CategoryIDs)
+	if err != nil {
+		return fmt.Errorf("failed to encode category scope: %w", err)
+	}
+
+	query := `
+		INSERT INTO api_tokens (` + apiTokenColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = conn(ctx, r.db).ExecContext(ctx, query,
+		token.ID, token.UserID, token.Name, token.TokenPrefix, token.TokenHash, token.Access, string(accountIDs), string(categoryIDs),
+		token.ExpiresAt, token.LastUsedAt, token.CreatedAt,
+		token.DailyWriteQuota, token.WriteCount, token.WriteCountDate, token.DisabledAt)
+	if err != nil {
+		return fmt.Errorf("failed to create API token: %w", err)
+	}
+	return nil
+}
+
+func (r *apiTokenRepository) GetByID(ctx context.Context, id string) (*domain.APIToken, error) {
+	return r.getOne(ctx, `SELECT `+apiTokenColumns+` FROM api_tokens WHERE id = ?`, id)
+}
+
+func (r *apiTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*domain.APIToken, error) {
+	return r.getOne(ctx, `SELECT `+apiTokenColumns+` FROM api_tokens WHERE token_hash = ?`, tokenHash)
+}
+
+func (r *apiTokenRepository) getOne(ctx context.Context, query string, args ...any) (*domain.APIToken, error) {
+	token, err := scanAPIToken(conn(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("API token not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API token: %w", err)
+	}
+	return token, nil
+}
+
+func (r *apiTokenRepository) List(ctx context.Context) ([]*domain.APIToken, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `SELECT `+apiTokenColumns+` FROM api_tokens ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []*domain.APIToken
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan API token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+func (r *apiTokenRepository) UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE api_tokens SET last_used_at = ? WHERE id = ?`, lastUsedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to update API token: %w", err)
+	}
+	return nil
+}
+
+// RecordWrite counts a write against the token's allowance for day (YYYY-MM-DD)
+// The count starts again on a new day. It reports false, counting nothing, when quota
+// writes have already been made that day; a quota of 0 never refuses.
+func (r *apiTokenRepository) RecordWrite(ctx context.Context, id, day string, quota int) (bool, error) {
+	query := `
+		UPDATE api_tokens
+		SET write_count = CASE WHEN write_count_date = ? THEN write_count + 1 ELSE 1 END,
+			write_count_date = ?
+		WHERE id = ? AND (? = 0 OR write_count_date != ? OR write_count < ?)
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, day, day, id, quota, day, quota)
+	if err != nil {
+		return false, fmt.Errorf("failed to record API token write: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+func (r *apiTokenRepository) SetDisabled(ctx context.Context, id string, disabledAt *time.Time) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE api_tokens SET disabled_at = ? WHERE id = ?`, disabledAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to update API token: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("API token not found")
+	}
+	return nil
+}
+
+func (r *apiTokenRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM api_tokens WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete API token: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("API token not found")
+	}
+	return nil
+}
+
+func scanAPIToken(row rowScanner) (*domain.APIToken, error) {
+	token := &domain.APIToken{}
+	var userID sql.NullString
+	var accountIDs, categoryIDs string
+	var expiresAt, lastUsedAt, disabledAt sql.NullTime
+	err := row.Scan(
+		&token.ID, &userID, &token.Name, &token.TokenPrefix, &token.TokenHash, &token.Access, &accountIDs, &categoryIDs,
+		&expiresAt, &lastUsedAt, &token.CreatedAt,
+		&token.DailyWriteQuota, &token.WriteCount, &token.WriteCountDate, &disabledAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(accountIDs), &token.AccountIDs); err != nil {
+		return nil, fmt.Errorf("failed to decode account scope: %w", err)
+	}
+	if err := json.Unmarshal([]byte(categoryIDs), &token.CategoryIDs); err != nil {
+		return nil, fmt.Errorf("failed to decode category scope: %w", err)
+	}
+	if userID.Valid {
+		token.UserID = &userID.String
+	}
+	if expiresAt.Valid {
+		token.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		token.LastUsedAt = &lastUsedAt.Time
+	}
+	if disabledAt.Valid {
+		token.DisabledAt = &disabledAt.Time
+	}
+	return token, nil
+}
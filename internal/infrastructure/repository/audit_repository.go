@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+// NewAuditRepository creates a new audit log repository
+func NewAuditRepository(db *sql.DB) domain.AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Create(ctx context.Context, entry *domain.AuditEntry) error {
+	query := `
+		INSERT INTO audit_log (user_id, token_id, actor, method, route, path, status, ip_address, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		entry.UserID, entry.TokenID, entry.Actor, entry.Method, entry.Route, entry.Path, entry.Status,
+		entry.IPAddress, entry.CreatedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to create audit entry: %w", err)
+	}
+	entry.ID, err = result.LastInsertId()
+	return err
+}
+
+// List returns entries newest first
+func (r *auditRepository) List(ctx context.Context, query domain.AuditQuery) ([]*domain.AuditEntry, error) {
+	var conditions []string
+	var args []interface{}
+	if query.BeforeID > 0 {
+		conditions = append(conditions, "id < ?")
+		args = append(args, query.BeforeID)
+	}
+	if query.Since != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, query.Since.UTC())
+	}
+	if query.Until != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, query.Until.UTC())
+	}
+
+	sqlQuery := `
+		SELECT id, user_id, token_id, actor, method, route, path, status, ip_address, created_at
+		FROM audit_log
+	`
+	if len(conditions) > 0 {
+		sqlQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	sqlQuery += " ORDER BY id DESC LIMIT ?"
+	args = append(args, query.Limit)
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*domain.AuditEntry
+	for rows.Next() {
+		entry := &domain.AuditEntry{}
+		var userID, tokenID sql.NullString
+		if err := rows.Scan(&entry.ID, &userID, &tokenID, &entry.Actor, &entry.Method, &entry.Route, &entry.Path,
+			&entry.Status, &entry.IPAddress, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		if userID.Valid {
+			entry.UserID = &userID.String
+		}
+		if tokenID.Valid {
+			entry.TokenID = &tokenID.String
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// CountBefore counts entries written before cutoff
+func (r *auditRepository) CountBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	var count int64
+	if err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT COUNT(*) FROM audit_log WHERE created_at < ?`, cutoff.UTC()).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count audit log entries: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteBefore removes entries written before cutoff and returns how many were removed
+func (r *auditRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM audit_log WHERE created_at < ?`, cutoff.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune audit log: %w", err)
+	}
+	return result.RowsAffected()
+}
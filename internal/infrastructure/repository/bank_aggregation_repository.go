@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type bankAggregationRepository struct {
+	db *sql.DB
+}
+
+// NewBankAggregationRepository creates a new bank aggregation repository
+func NewBankAggregationRepository(db *sql.DB) domain.BankAggregationRepository {
+	return &bankAggregationRepository{db: db}
+}
+
+const bankAggregationColumns = `id, provider, name, credentials, last_synced_at, last_error, created_at, updated_at`
+
+const bankAggregationAccountColumns = `aggregation_id, remote_id, name, institution, currency, balance, account_id, last_error, updated_at`
+
+func (r *bankAggregationRepository) Create(ctx context.Context, aggregation *domain.BankAggregation) error {
+	query := `
+		INSERT INTO bank_aggregations (` + bankAggregationColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		aggregation.ID, aggregation.Provider, aggregation.Name, aggregation.Credentials,
+		aggregation.LastSyncedAt, aggregation.LastError, aggregation.CreatedAt, aggregation.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create bank aggregation: %w", err)
+	}
+	return nil
+}
+
+func (r *bankAggregationRepository) GetByID(ctx context.Context, id string) (*domain.BankAggregation, error) {
+	query := `SELECT ` + bankAggregationColumns + ` FROM bank_aggregations WHERE id = ?`
+	aggregation, err := scanBankAggregation(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrBankAggregationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank aggregation: %w", err)
+	}
+	return aggregation, nil
+}
+
+func (r *bankAggregationRepository) List(ctx context.Context) ([]*domain.BankAggregation, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `SELECT `+bankAggregationColumns+` FROM bank_aggregations ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bank aggregations: %w", err)
+	}
+	defer rows.Close()
+
+	var aggregations []*domain.BankAggregation
+	for rows.Next() {
+		aggregation, err := scanBankAggregation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan bank aggregation: %w", err)
+		}
+		aggregations = append(aggregations, aggregation)
+	}
+	return aggregations, rows.Err()
+}
+
+func (r *bankAggregationRepository) Update(ctx context.Context, aggregation *domain.BankAggregation) error {
+	query := `
+		UPDATE bank_aggregations
+		SET name = ?, credentials = ?, last_synced_at = ?, last_error = ?, updated_at = ?
+		WHERE id = ?
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		aggregation.Name, aggregation.Credentials, aggregation.LastSyncedAt, aggregation.LastError,
+		aggregation.UpdatedAt, aggregation.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update bank aggregation: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrBankAggregationNotFound
+	}
+	return nil
+}
+
+func (r *bankAggregationRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM bank_aggregations WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete bank aggregation: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrBankAggregationNotFound
+	}
+	return nil
+}
+
+func (r *bankAggregationRepository) SaveAccount(ctx context.Context, account *domain.BankAggregationAccount) error {
+	query := `
+		INSERT INTO bank_aggregation_accounts (` + bankAggregationAccountColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(aggregation_id, remote_id) DO UPDATE SET
+			name = excluded.name, institution = excluded.institution, currency = excluded.currency,
+			balance = excluded.balance, account_id = excluded.account_id, last_error = excluded.last_error,
+			updated_at = excluded.updated_at
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		account.AggregationID, account.RemoteID, account.Name, account.Institution, account.Currency,
+		account.Balance, account.AccountID, account.LastError, account.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save bank aggregation account: %w", err)
+	}
+	return nil
+}
+
+func (r *bankAggregationRepository) GetAccount(ctx context.Context, aggregationID, remoteID string) (*domain.BankAggregationAccount, error) {
+	query := `SELECT ` + bankAggregationAccountColumns + ` FROM bank_aggregation_accounts WHERE aggregation_id = ? AND remote_id = ?`
+	account, err := scanBankAggregationAccount(conn(ctx, r.db).QueryRowContext(ctx, query, aggregationID, remoteID))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrBankAggregationAccountNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank aggregation account: %w", err)
+	}
+	return account, nil
+}
+
+func (r *bankAggregationRepository) ListAccounts(ctx context.Context, aggregationID string) ([]*domain.BankAggregationAccount, error) {
+	query := `SELECT ` + bankAggregationAccountColumns + ` FROM bank_aggregation_accounts WHERE aggregation_id = ? ORDER BY name`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, aggregationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bank aggregation accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []*domain.BankAggregationAccount
+	for rows.Next() {
+		account, err := scanBankAggregationAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan bank aggregation account: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, rows.Err()
+}
+
+func scanBankAggregation(row rowScanner) (*domain.BankAggregation, error) {
+	aggregation := &domain.BankAggregation{}
+	var lastSyncedAt sql.NullTime
+	err := row.Scan(
+		&aggregation.ID, &aggregation.Provider, &aggregation.Name, &aggregation.Credentials,
+		&lastSyncedAt, &aggregation.LastError, &aggregation.CreatedAt, &aggregation.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if lastSyncedAt.Valid {
+		aggregation.LastSyncedAt = &lastSyncedAt.Time
+	}
+	return aggregation, nil
+}
+
+func scanBankAggregationAccount(row rowScanner) (*domain.BankAggregationAccount, error) {
+	account := &domain.BankAggregationAccount{}
+	var accountID sql.NullString
+	err := row.Scan(
+		&account.AggregationID, &account.RemoteID, &account.Name, &account.Institution, &account.Currency,
+		&account.Balance, &accountID, &account.LastError, &account.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if accountID.Valid {
+		account.AccountID = &accountID.String
+	}
+	return account, nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type bankConnectionRepository struct {
+	db *sql.DB
+}
+
+// NewBankConnectionRepository creates a new bank connection repository
+func NewBankConnectionRepository(db *sql.DB) domain.BankConnectionRepository {
+	return &bankConnectionRepository{db: db}
+}
+
+const bankConnectionColumns = `account_id, url, org, fid, bank_id, account_type, username, account_number_last4,
+		app_id, app_ver, ofx_version, credentials, last_synced_at, last_error, created_at, updated_at`
+
+func (r *bankConnectionRepository) Save(ctx context.Context, connection *domain.BankConnection) error {
+	query := `
+		INSERT INTO bank_connections (` + bankConnectionColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(account_id) DO UPDATE SET
+			url = excluded.url, org = excluded.org, fid = excluded.fid, bank_id = excluded.bank_id,
+			account_type = excluded.account_type, username = excluded.username,
+			account_number_last4 = excluded.account_number_last4, app_id = excluded.app_id,
+			app_ver = excluded.app_ver, ofx_version = excluded.ofx_version, credentials = excluded.credentials,
+			last_synced_at = excluded.last_synced_at, last_error = excluded.last_error, updated_at = excluded.updated_at
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		connection.AccountID, connection.URL, connection.Org, connection.FID, connection.BankID,
+		connection.AccountType, connection.Username, connection.AccountNumberLast4,
+		connection.AppID, connection.AppVer, connection.OFXVersion, connection.Credentials,
+		connection.LastSyncedAt, connection.LastError, connection.CreatedAt, connection.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save bank connection: %w", err)
+	}
+	return nil
+}
+
+func (r *bankConnectionRepository) GetByAccountID(ctx context.Context, accountID string) (*domain.BankConnection, error) {
+	query := `SELECT ` + bankConnectionColumns + ` FROM bank_connections WHERE account_id = ?`
+	connection, err := scanBankConnection(conn(ctx, r.db).QueryRowContext(ctx, query, accountID))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrBankConnectionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank connection: %w", err)
+	}
+	return connection, nil
+}
+
+func (r *bankConnectionRepository) Delete(ctx context.Context, accountID string) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM bank_connections WHERE account_id = ?`, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to delete bank connection: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrBankConnectionNotFound
+	}
+	return nil
+}
+
+func scanBankConnection(row rowScanner) (*domain.BankConnection, error) {
+	connection := &domain.BankConnection{}
+	var lastSyncedAt sql.NullTime
+	err := row.Scan(
+		&connection.AccountID, &connection.URL, &connection.Org, &connection.FID, &connection.BankID,
+		&connection.AccountType, &connection.Username, &connection.AccountNumberLast4,
+		&connection.AppID, &connection.AppVer, &connection.OFXVersion, &connection.Credentials,
+		&lastSyncedAt, &connection.LastError, &connection.CreatedAt, &connection.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if lastSyncedAt.Valid {
+		connection.LastSyncedAt = &lastSyncedAt.Time
+	}
+	return connection, nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type botChatRepository struct {
+	db *sql.DB
+}
+
+// NewBotChatRepository creates a new bot chat repository
+func NewBotChatRepository(db *sql.DB) domain.BotChatRepository {
+	return &botChatRepository{db: db}
+}
+
+const botChatColumns = `id, platform, chat_id, name, token_hash, default_account_id, daily_summary, last_used_at, created_at, updated_at`
+
+func (r *botChatRepository) Create(ctx context.Context, chat *domain.BotChat) error {
+	query := `
+		INSERT INTO bot_chats (` + botChatColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		chat.ID, chat.Platform, nullIfEmpty(chat.ChatID), chat.Name, chat.TokenHash, chat.DefaultAccountID,
+		chat.DailySummary, chat.LastUsedAt, chat.CreatedAt, chat.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create bot chat: %w", err)
+	}
+	return nil
+}
+
+func (r *botChatRepository) GetByID(ctx context.Context, id string) (*domain.BotChat, error) {
+	return r.getOne(ctx, `SELECT `+botChatColumns+` FROM bot_chats WHERE id = ?`, id)
+}
+
+func (r *botChatRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.BotChat, error) {
+	return r.getOne(ctx, `SELECT `+botChatColumns+` FROM bot_chats WHERE token_hash = ?`, tokenHash)
+}
+
+func (r *botChatRepository) GetByPlatformChatID(ctx context.Context, platform domain.BotPlatform, chatID string) (*domain.BotChat, error) {
+	return r.getOne(ctx, `SELECT `+botChatColumns+` FROM bot_chats WHERE platform = ? AND chat_id = ?`, platform, chatID)
+}
+
+func (r *botChatRepository) getOne(ctx context.Context, query string, args ...any) (*domain.BotChat, error) {
+	chat, err := scanBotChat(conn(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("bot chat not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bot chat: %w", err)
+	}
+	return chat, nil
+}
+
+func (r *botChatRepository) List(ctx context.Context) ([]*domain.BotChat, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `SELECT `+botChatColumns+` FROM bot_chats ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bot chats: %w", err)
+	}
+	defer rows.Close()
+
+	var chats []*domain.BotChat
+	for rows.Next() {
+		chat, err := scanBotChat(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan bot chat: %w", err)
+		}
+		chats = append(chats, chat)
+	}
+	return chats, nil
+}
+
+func (r *botChatRepository) Update(ctx context.Context, chat *domain.BotChat) error {
+	query := `
+		UPDATE bot_chats
+		SET chat_id = ?, name = ?, default_account_id = ?, daily_summary = ?, last_used_at = ?, updated_at = ?
+		WHERE id = ?
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		nullIfEmpty(chat.ChatID), chat.Name, chat.DefaultAccountID, chat.DailySummary, chat.LastUsedAt,
+		chat.UpdatedAt, chat.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update bot chat: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("bot chat not found")
+	}
+	return nil
+}
+
+func (r *botChatRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM bot_chats WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete bot chat: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("bot chat not found")
+	}
+	return nil
+}
+
+func scanBotChat(row rowScanner) (*domain.BotChat, error) {
+	chat := &domain.BotChat{}
+	var chatID, defaultAccountID sql.NullString
+	var lastUsedAt sql.NullTime
+	err := row.Scan(
+		&chat.ID, &chat.Platform, &chatID, &chat.Name, &chat.TokenHash, &defaultAccountID,
+		&chat.DailySummary, &lastUsedAt, &chat.CreatedAt, &chat.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	chat.ChatID = chatID.String
+	if defaultAccountID.Valid {
+		chat.DefaultAccountID = &defaultAccountID.String
+	}
+	if lastUsedAt.Valid {
+		chat.LastUsedAt = &lastUsedAt.Time
+	}
+	return chat, nil
+}
+
+// nullIfEmpty stores empty strings as NULL so UNIQUE constraints ignore them
+func nullIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -20,13 +20,13 @@ func NewBudgetStateRepository(db *sql.DB) domain.BudgetStateRepository {
 
 func (r *budgetStateRepository) Get(ctx context.Context) (*domain.BudgetState, error) {
 	query := `
-		SELECT id, ready_to_assign, updated_at
+		SELECT id, ready_to_assign, revision, updated_at
 		FROM budget_state
 		WHERE id = 'singleton'
 	`
 	state := &domain.BudgetState{}
-	err := r.db.QueryRowContext(ctx, query).Scan(
-		&state.ID, &state.ReadyToAssign, &state.UpdatedAt)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query).Scan(
+		&state.ID, &state.ReadyToAssign, &state.Revision, &state.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("budget state not found")
 	}
@@ -43,7 +43,7 @@ func (r *budgetStateRepository) Update(ctx context.Context, state *domain.Budget
 		WHERE id = 'singleton'
 	`
 	state.UpdatedAt = time.Now()
-	result, err := r.db.ExecContext(ctx, query, state.ReadyToAssign, state.UpdatedAt)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, state.ReadyToAssign, state.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to update budget state: %w", err)
 	}
@@ -63,7 +63,7 @@ func (r *budgetStateRepository) AdjustReadyToAssign(ctx context.Context, delta i
 		SET ready_to_assign = ready_to_assign + ?, updated_at = ?
 		WHERE id = 'singleton'
 	`
-	result, err := r.db.ExecContext(ctx, query, delta, time.Now())
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, delta, time.Now())
 	if err != nil {
 		return fmt.Errorf("failed to adjust ready to assign: %w", err)
 	}
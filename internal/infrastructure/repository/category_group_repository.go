@@ -22,7 +22,7 @@ func (r *categoryGroupRepository) Create(ctx context.Context, group *domain.Cate
 		INSERT INTO category_groups (id, name, description, display_order, created_at, updated_at)
 		VALUES (?, ?, ?, ?, ?, ?)
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
 		group.ID, group.Name, group.Description,
 		group.DisplayOrder, group.CreatedAt, group.UpdatedAt)
 	if err != nil {
@@ -38,7 +38,7 @@ func (r *categoryGroupRepository) GetByID(ctx context.Context, id string) (*doma
 		WHERE id = ?
 	`
 	group := &domain.CategoryGroup{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
 		&group.ID, &group.Name, &group.Description,
 		&group.DisplayOrder, &group.CreatedAt, &group.UpdatedAt)
 	if err == sql.ErrNoRows {
@@ -56,7 +56,7 @@ func (r *categoryGroupRepository) List(ctx context.Context) ([]*domain.CategoryG
 		FROM category_groups
 		ORDER BY display_order, name
 	`
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list category groups: %w", err)
 	}
@@ -80,7 +80,7 @@ func (r *categoryGroupRepository) Update(ctx context.Context, group *domain.Cate
 		SET name = ?, description = ?, display_order = ?, updated_at = ?
 		WHERE id = ?
 	`
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
 		group.Name, group.Description,
 		group.DisplayOrder, group.UpdatedAt, group.ID)
 	if err != nil {
@@ -98,7 +98,7 @@ func (r *categoryGroupRepository) Update(ctx context.Context, group *domain.Cate
 
 func (r *categoryGroupRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM category_groups WHERE id = ?`
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete category group: %w", err)
 	}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type categoryMoveRepository struct {
+	db *sql.DB
+}
+
+// NewCategoryMoveRepository creates a new category move repository
+func NewCategoryMoveRepository(db *sql.DB) domain.CategoryMoveRepository {
+	return &categoryMoveRepository{db: db}
+}
+
+const categoryMoveColumns = `id, from_category_id, to_category_id, period, amount, reason, transaction_id, created_at`
+
+func (r *categoryMoveRepository) Create(ctx context.Context, move *domain.CategoryMove) error {
+	query := `
+		INSERT INTO category_moves (` + categoryMoveColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		move.ID, move.FromCategoryID, move.ToCategoryID, move.Period, move.Amount, move.Reason, move.TransactionID, move.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create category move: %w", err)
+	}
+	return nil
+}
+
+func (r *categoryMoveRepository) List(ctx context.Context) ([]*domain.CategoryMove, error) {
+	return r.list(ctx, `SELECT `+categoryMoveColumns+` FROM category_moves ORDER BY period, created_at`)
+}
+
+func (r *categoryMoveRepository) ListByCategory(ctx context.Context, categoryID string) ([]*domain.CategoryMove, error) {
+	return r.list(ctx, `SELECT `+categoryMoveColumns+` FROM category_moves WHERE to_category_id = ? ORDER BY period, created_at`, categoryID)
+}
+
+func (r *categoryMoveRepository) list(ctx context.Context, query string, args ...any) ([]*domain.CategoryMove, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list category moves: %w", err)
+	}
+	defer rows.Close()
+
+	var moves []*domain.CategoryMove
+	for rows.Next() {
+		move, err := scanCategoryMove(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan category move: %w", err)
+		}
+		moves = append(moves, move)
+	}
+	return moves, rows.Err()
+}
+
+func (r *categoryMoveRepository) DeleteByTransaction(ctx context.Context, transactionID string) error {
+	if _, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM category_moves WHERE transaction_id = ?`, transactionID); err != nil {
+		return fmt.Errorf("failed to delete category moves: %w", err)
+	}
+	return nil
+}
+
+func scanCategoryMove(row rowScanner) (*domain.CategoryMove, error) {
+	var move domain.CategoryMove
+	var fromCategoryID, transactionID sql.NullString
+	if err := row.Scan(&move.ID, &fromCategoryID, &move.ToCategoryID, &move.Period, &move.Amount, &move.Reason, &transactionID, &move.CreatedAt); err != nil {
+		return nil, err
+	}
+	if fromCategoryID.Valid {
+		move.FromCategoryID = &fromCategoryID.String
+	}
+	if transactionID.Valid {
+		move.TransactionID = &transactionID.String
+	}
+	return &move, nil
+}
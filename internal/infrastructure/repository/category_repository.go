@@ -19,12 +19,12 @@ func NewCategoryRepository(db *sql.DB) domain.CategoryRepository {
 
 func (r *categoryRepository) Create(ctx context.Context, category *domain.Category) error {
 	query := `
-		INSERT INTO categories (id, name, description, color, group_id, payment_for_account_id, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO categories (id, name, description, color, group_id, payment_for_account_id, classification, overspending_mode, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
 		category.ID, category.Name, category.Description,
-		category.Color, category.GroupID, category.PaymentForAccountID, category.CreatedAt, category.UpdatedAt)
+		category.Color, category.GroupID, category.PaymentForAccountID, category.Classification, category.OverspendingMode, category.CreatedAt, category.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create category: %w", err)
 	}
@@ -33,15 +33,15 @@ func (r *categoryRepository) Create(ctx context.Context, category *domain.Catego
 
 func (r *categoryRepository) GetByID(ctx context.Context, id string) (*domain.Category, error) {
 	query := `
-		SELECT id, name, description, color, group_id, payment_for_account_id, created_at, updated_at
+		SELECT id, name, description, color, group_id, payment_for_account_id, classification, overspending_mode, created_at, updated_at
 		FROM categories
 		WHERE id = ?
 	`
 	category := &domain.Category{}
 	var groupID, paymentForAccountID sql.NullString
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
 		&category.ID, &category.Name, &category.Description,
-		&category.Color, &groupID, &paymentForAccountID, &category.CreatedAt, &category.UpdatedAt)
+		&category.Color, &groupID, &paymentForAccountID, &category.Classification, &category.OverspendingMode, &category.CreatedAt, &category.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("category not found")
 	}
@@ -59,11 +59,11 @@ func (r *categoryRepository) GetByID(ctx context.Context, id string) (*domain.Ca
 
 func (r *categoryRepository) List(ctx context.Context) ([]*domain.Category, error) {
 	query := `
-		SELECT id, name, description, color, group_id, payment_for_account_id, created_at, updated_at
+		SELECT id, name, description, color, group_id, payment_for_account_id, classification, overspending_mode, created_at, updated_at
 		FROM categories
 		ORDER BY name
 	`
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list categories: %w", err)
 	}
@@ -74,7 +74,7 @@ func (r *categoryRepository) List(ctx context.Context) ([]*domain.Category, erro
 		category := &domain.Category{}
 		var groupID, paymentForAccountID sql.NullString
 		if err := rows.Scan(&category.ID, &category.Name,
-			&category.Description, &category.Color, &groupID, &paymentForAccountID, &category.CreatedAt, &category.UpdatedAt); err != nil {
+			&category.Description, &category.Color, &groupID, &paymentForAccountID, &category.Classification, &category.OverspendingMode, &category.CreatedAt, &category.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan category: %w", err)
 		}
 		if groupID.Valid {
@@ -90,12 +90,12 @@ func (r *categoryRepository) List(ctx context.Context) ([]*domain.Category, erro
 
 func (r *categoryRepository) ListByGroup(ctx context.Context, groupID string) ([]*domain.Category, error) {
 	query := `
-		SELECT id, name, description, color, group_id, payment_for_account_id, created_at, updated_at
+		SELECT id, name, description, color, group_id, payment_for_account_id, classification, overspending_mode, created_at, updated_at
 		FROM categories
 		WHERE group_id = ?
 		ORDER BY name
 	`
-	rows, err := r.db.QueryContext(ctx, query, groupID)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, groupID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list categories by group: %w", err)
 	}
@@ -106,7 +106,7 @@ func (r *categoryRepository) ListByGroup(ctx context.Context, groupID string) ([
 		category := &domain.Category{}
 		var grpID, paymentForAccountID sql.NullString
 		if err := rows.Scan(&category.ID, &category.Name,
-			&category.Description, &category.Color, &grpID, &paymentForAccountID, &category.CreatedAt, &category.UpdatedAt); err != nil {
+			&category.Description, &category.Color, &grpID, &paymentForAccountID, &category.Classification, &category.OverspendingMode, &category.CreatedAt, &category.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan category: %w", err)
 		}
 		if grpID.Valid {
@@ -123,12 +123,12 @@ func (r *categoryRepository) ListByGroup(ctx context.Context, groupID string) ([
 func (r *categoryRepository) Update(ctx context.Context, category *domain.Category) error {
 	query := `
 		UPDATE categories
-		SET name = ?, description = ?, color = ?, group_id = ?, payment_for_account_id = ?, updated_at = ?
+		SET name = ?, description = ?, color = ?, group_id = ?, payment_for_account_id = ?, classification = ?, overspending_mode = ?, updated_at = ?
 		WHERE id = ?
 	`
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
 		category.Name, category.Description,
-		category.Color, category.GroupID, category.PaymentForAccountID, category.UpdatedAt, category.ID)
+		category.Color, category.GroupID, category.PaymentForAccountID, category.Classification, category.OverspendingMode, category.UpdatedAt, category.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update category: %w", err)
 	}
@@ -144,15 +144,15 @@ func (r *categoryRepository) Update(ctx context.Context, category *domain.Catego
 
 func (r *categoryRepository) GetPaymentCategoryByAccountID(ctx context.Context, accountID string) (*domain.Category, error) {
 	query := `
-		SELECT id, name, description, color, group_id, payment_for_account_id, created_at, updated_at
+		SELECT id, name, description, color, group_id, payment_for_account_id, classification, overspending_mode, created_at, updated_at
 		FROM categories
 		WHERE payment_for_account_id = ?
 	`
 	category := &domain.Category{}
 	var groupID, paymentForAccountID sql.NullString
-	err := r.db.QueryRowContext(ctx, query, accountID).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, accountID).Scan(
 		&category.ID, &category.Name, &category.Description,
-		&category.Color, &groupID, &paymentForAccountID, &category.CreatedAt, &category.UpdatedAt)
+		&category.Color, &groupID, &paymentForAccountID, &category.Classification, &category.OverspendingMode, &category.CreatedAt, &category.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("payment category not found for account")
 	}
@@ -170,7 +170,7 @@ func (r *categoryRepository) GetPaymentCategoryByAccountID(ctx context.Context,
 
 func (r *categoryRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM categories WHERE id = ?`
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete category: %w", err)
 	}
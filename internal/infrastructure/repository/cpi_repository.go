@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type cpiRepository struct {
+	db *sql.DB
+}
+
+// NewCPIRepository creates a new CPI repository
+func NewCPIRepository(db *sql.DB) domain.CPIRepository {
+	return &cpiRepository{db: db}
+}
+
+// Upsert inserts the CPI entry or replaces the existing value for its period
+func (r *cpiRepository) Upsert(ctx context.Context, entry *domain.CPIEntry) error {
+	query := `
+		INSERT INTO cpi_index (period, value, source, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(period) DO UPDATE SET value = excluded.value, source = excluded.source, updated_at = excluded.updated_at
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, entry.Period, entry.Value, entry.Source, entry.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save CPI entry: %w", err)
+	}
+	return nil
+}
+
+func (r *cpiRepository) List(ctx context.Context) ([]*domain.CPIEntry, error) {
+	query := `
+		SELECT period, value, source, updated_at
+		FROM cpi_index
+		ORDER BY period
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CPI entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*domain.CPIEntry
+	for rows.Next() {
+		entry := &domain.CPIEntry{}
+		if err := rows.Scan(&entry.Period, &entry.Value, &entry.Source, &entry.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan CPI entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (r *cpiRepository) Delete(ctx context.Context, period string) error {
+	query := `DELETE FROM cpi_index WHERE period = ?`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, period)
+	if err != nil {
+		return fmt.Errorf("failed to delete CPI entry: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("CPI entry not found")
+	}
+	return nil
+}
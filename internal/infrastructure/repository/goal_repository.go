@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type goalRepository struct {
+	db *sql.DB
+}
+
+// NewGoalRepository creates a new category goal repository
+func NewGoalRepository(db *sql.DB) domain.GoalRepository {
+	return &goalRepository{db: db}
+}
+
+func (r *goalRepository) Create(ctx context.Context, goal *domain.Goal) error {
+	query := `
+		INSERT INTO goals (id, category_id, target_amount, target_date, cadence, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		goal.ID, goal.CategoryID, goal.TargetAmount, goal.TargetDate, goal.Cadence,
+		goal.CreatedAt, goal.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create goal: %w", err)
+	}
+	return nil
+}
+
+func (r *goalRepository) GetByID(ctx context.Context, id string) (*domain.Goal, error) {
+	return r.get(ctx, "id", id)
+}
+
+func (r *goalRepository) GetByCategoryID(ctx context.Context, categoryID string) (*domain.Goal, error) {
+	return r.get(ctx, "category_id", categoryID)
+}
+
+func (r *goalRepository) get(ctx context.Context, column, value string) (*domain.Goal, error) {
+	query := `
+		SELECT id, category_id, target_amount, target_date, cadence, created_at, updated_at
+		FROM goals
+		WHERE ` + column + ` = ?
+	`
+	goal := &domain.Goal{}
+	var targetDate sql.NullString
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, value).Scan(
+		&goal.ID, &goal.CategoryID, &goal.TargetAmount, &targetDate, &goal.Cadence,
+		&goal.CreatedAt, &goal.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrGoalNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get goal: %w", err)
+	}
+	if targetDate.Valid {
+		goal.TargetDate = &targetDate.String
+	}
+	return goal, nil
+}
+
+func (r *goalRepository) List(ctx context.Context) ([]*domain.Goal, error) {
+	query := `
+		SELECT id, category_id, target_amount, target_date, cadence, created_at, updated_at
+		FROM goals
+		ORDER BY created_at
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list goals: %w", err)
+	}
+	defer rows.Close()
+
+	var goals []*domain.Goal
+	for rows.Next() {
+		goal := &domain.Goal{}
+		var targetDate sql.NullString
+		if err := rows.Scan(&goal.ID, &goal.CategoryID, &goal.TargetAmount, &targetDate, &goal.Cadence,
+			&goal.CreatedAt, &goal.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan goal: %w", err)
+		}
+		if targetDate.Valid {
+			goal.TargetDate = &targetDate.String
+		}
+		goals = append(goals, goal)
+	}
+	return goals, rows.Err()
+}
+
+func (r *goalRepository) Update(ctx context.Context, goal *domain.Goal) error {
+	query := `
+		UPDATE goals
+		SET target_amount = ?, target_date = ?, cadence = ?, updated_at = ?
+		WHERE id = ?
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		goal.TargetAmount, goal.TargetDate, goal.Cadence, goal.UpdatedAt, goal.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update goal: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrGoalNotFound
+	}
+	return nil
+}
+
+func (r *goalRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM goals WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete goal: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrGoalNotFound
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type importFileRepository struct {
+	db *sql.DB
+}
+
+// NewImportFileRepository creates a new imported file repository
+func NewImportFileRepository(db *sql.DB) domain.ImportFileRepository {
+	return &importFileRepository{db: db}
+}
+
+const importFileColumns = `id, account_id, fingerprint, format, status, ledger_balance, balance_change, total_transactions, imported_transactions, skipped_duplicates, created_at, committed_at`
+
+func (r *importFileRepository) Create(ctx context.Context, file *domain.ImportFile) error {
+	query := `
+		INSERT INTO import_files (` + importFileColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		file.ID, file.AccountID, file.Fingerprint, file.Format, file.Status, file.LedgerBalance, file.BalanceChange,
+		file.TotalTransactions, file.ImportedTransactions, file.SkippedDuplicates, file.CreatedAt, file.CommittedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create import file: %w", err)
+	}
+	return nil
+}
+
+func (r *importFileRepository) GetByID(ctx context.Context, id string) (*domain.ImportFile, error) {
+	query := `SELECT ` + importFileColumns + ` FROM import_files WHERE id = ?`
+	file, err := scanImportFile(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrImportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import file: %w", err)
+	}
+	return file, nil
+}
+
+func (r *importFileRepository) FindByFingerprint(ctx context.Context, accountID, fingerprint string) (*domain.ImportFile, error) {
+	query := `
+		SELECT ` + importFileColumns + `
+		FROM import_files
+		WHERE account_id = ? AND fingerprint = ? AND status = ?
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+	file, err := scanImportFile(conn(ctx, r.db).QueryRowContext(ctx, query, accountID, fingerprint, domain.ImportStatusCommitted))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find import file: %w", err)
+	}
+	return file, nil
+}
+
+func (r *importFileRepository) List(ctx context.Context, status string) ([]*domain.ImportFile, error) {
+	query := `SELECT ` + importFileColumns + ` FROM import_files`
+	var args []any
+	if status != "" {
+		query += ` WHERE status = ?`
+		args = append(args, status)
+	}
+	query += ` ORDER BY created_at DESC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list import files: %w", err)
+	}
+	defer rows.Close()
+
+	var files []*domain.ImportFile
+	for rows.Next() {
+		file, err := scanImportFile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan import file: %w", err)
+		}
+		files = append(files, file)
+	}
+	return files, rows.Err()
+}
+
+func (r *importFileRepository) Update(ctx context.Context, file *domain.ImportFile) error {
+	query := `
+		UPDATE import_files
+		SET status = ?, ledger_balance = ?, balance_change = ?, total_transactions = ?, imported_transactions = ?, skipped_duplicates = ?, committed_at = ?
+		WHERE id = ?
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		file.Status, file.LedgerBalance, file.BalanceChange, file.TotalTransactions, file.ImportedTransactions, file.SkippedDuplicates, file.CommittedAt, file.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update import file: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrImportNotFound
+	}
+	return nil
+}
+
+// Delete removes an import file; its rows go with it
+func (r *importFileRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM import_files WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete import file: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrImportNotFound
+	}
+	return nil
+}
+
+const importFileRowColumns = `id, import_id, position, fitid, date, amount, description, category_id, payee_id, note, status, excluded, error, warning,
+	transaction_id, transfer_account_id, transfer_transaction_id, placeholder_created, original_currency, original_amount`
+
+func (r *importFileRepository) CreateRows(ctx context.Context, rows []*domain.ImportFileRow) error {
+	return inTx(ctx, r.db, func(ctx context.Context) error {
+		query := `
+			INSERT INTO import_file_rows (` + importFileRowColumns + `)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`
+		for _, row := range rows {
+			if _, err := conn(ctx, r.db).ExecContext(ctx, query,
+				row.ID, row.ImportID, row.Position, row.FitID, row.Date, row.Amount, row.Description, row.CategoryID, row.PayeeID,
+				row.Note, row.Status, row.Excluded, row.Error, row.Warning,
+				row.TransactionID, row.TransferAccountID, row.TransferTransactionID, row.PlaceholderCreated,
+				row.OriginalCurrency, row.OriginalAmount); err != nil {
+				return fmt.Errorf("failed to create import row: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *importFileRepository) ListRows(ctx context.Context, importID string) ([]*domain.ImportFileRow, error) {
+	query := `SELECT ` + importFileRowColumns + ` FROM import_file_rows WHERE import_id = ? ORDER BY position`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, importID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list import rows: %w", err)
+	}
+	defer rows.Close()
+
+	var importRows []*domain.ImportFileRow
+	for rows.Next() {
+		row := &domain.ImportFileRow{}
+		var categoryID, payeeID, note, originalCurrency sql.NullString
+		var originalAmount sql.NullInt64
+		if err := rows.Scan(&row.ID, &row.ImportID, &row.Position, &row.FitID, &row.Date, &row.Amount, &row.Description,
+			&categoryID, &payeeID, &note, &row.Status, &row.Excluded, &row.Error, &row.Warning,
+			&row.TransactionID, &row.TransferAccountID, &row.TransferTransactionID, &row.PlaceholderCreated,
+			&originalCurrency, &originalAmount); err != nil {
+			return nil, fmt.Errorf("failed to scan import row: %w", err)
+		}
+		if categoryID.Valid {
+			row.CategoryID = &categoryID.String
+		}
+		if payeeID.Valid {
+			row.PayeeID = &payeeID.String
+		}
+		if note.Valid {
+			row.Note = &note.String
+		}
+		if originalCurrency.Valid && originalAmount.Valid {
+			row.OriginalCurrency = &originalCurrency.String
+			row.OriginalAmount = &originalAmount.Int64
+		}
+		importRows = append(importRows, row)
+	}
+	return importRows, rows.Err()
+}
+
+func (r *importFileRepository) UpdateRow(ctx context.Context, row *domain.ImportFileRow) error {
+	query := `
+		UPDATE import_file_rows
+		SET category_id = ?, payee_id = ?, note = ?, status = ?, excluded = ?, error = ?, warning = ?,
+			transaction_id = ?, transfer_account_id = ?, transfer_transaction_id = ?, placeholder_created = ?
+		WHERE id = ? AND import_id = ?
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		row.CategoryID, row.PayeeID, row.Note, row.Status, row.Excluded, row.Error, row.Warning,
+		row.TransactionID, row.TransferAccountID, row.TransferTransactionID, row.PlaceholderCreated, row.ID, row.ImportID)
+	if err != nil {
+		return fmt.Errorf("failed to update import row: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrImportRowNotFound
+	}
+	return nil
+}
+
+func scanImportFile(row rowScanner) (*domain.ImportFile, error) {
+	file := &domain.ImportFile{}
+	var ledgerBalance sql.NullInt64
+	var committedAt sql.NullTime
+	if err := row.Scan(&file.ID, &file.AccountID, &file.Fingerprint, &file.Format, &file.Status, &ledgerBalance, &file.BalanceChange,
+		&file.TotalTransactions, &file.ImportedTransactions, &file.SkippedDuplicates, &file.CreatedAt, &committedAt); err != nil {
+		return nil, err
+	}
+	if ledgerBalance.Valid {
+		file.LedgerBalance = &ledgerBalance.Int64
+	}
+	if committedAt.Valid {
+		file.CommittedAt = &committedAt.Time
+	}
+	return file, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type maintenanceRepository struct {
+	db *sql.DB
+}
+
+// NewMaintenanceRepository creates a new maintenance repository
+func NewMaintenanceRepository(db *sql.DB) domain.MaintenanceRepository {
+	return &maintenanceRepository{db: db}
+}
+
+func (r *maintenanceRepository) Size(ctx context.Context) (domain.DatabaseSize, error) {
+	var pageSize, pageCount, freelistCount int64
+	query := `SELECT page_size, page_count, freelist_count FROM pragma_page_size, pragma_page_count, pragma_freelist_count`
+	if err := r.db.QueryRowContext(ctx, query).Scan(&pageSize, &pageCount, &freelistCount); err != nil {
+		return domain.DatabaseSize{}, fmt.Errorf("failed to get database size: %w", err)
+	}
+	return domain.DatabaseSize{Bytes: pageSize * pageCount, FreeBytes: pageSize * freelistCount}, nil
+}
+
+func (r *maintenanceRepository) Optimize(ctx context.Context) error {
+	// ANALYZE covers every table; optimize then only redoes what changed a lot since
+	if _, err := r.db.ExecContext(ctx, `ANALYZE`); err != nil {
+		return fmt.Errorf("failed to analyze database: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, `PRAGMA optimize`); err != nil {
+		return fmt.Errorf("failed to optimize database: %w", err)
+	}
+	return nil
+}
+
+// Vacuum can't run inside a transaction, so it never uses the context's
+func (r *maintenanceRepository) Vacuum(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, `VACUUM`); err != nil {
+		return fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type payeeRepository struct {
+	db *sql.DB
+}
+
+// NewPayeeRepository creates a new payee repository
+func NewPayeeRepository(db *sql.DB) domain.PayeeRepository {
+	return &payeeRepository{db: db}
+}
+
+func (r *payeeRepository) Create(ctx context.Context, payee *domain.Payee) error {
+	query := `
+		INSERT INTO payees (id, name, created_at, updated_at)
+		VALUES (?, ?, ?, ?)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, payee.ID, payee.Name, payee.CreatedAt, payee.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create payee: %w", err)
+	}
+	return nil
+}
+
+func (r *payeeRepository) GetByID(ctx context.Context, id string) (*domain.Payee, error) {
+	return r.get(ctx, "id", id)
+}
+
+// GetByName relies on the name column's NOCASE collation for case-insensitive matching
+func (r *payeeRepository) GetByName(ctx context.Context, name string) (*domain.Payee, error) {
+	return r.get(ctx, "name", name)
+}
+
+func (r *payeeRepository) get(ctx context.Context, column, value string) (*domain.Payee, error) {
+	query := `
+		SELECT id, name, created_at, updated_at
+		FROM payees
+		WHERE ` + column + ` = ?
+	`
+	payee := &domain.Payee{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, value).Scan(&payee.ID, &payee.Name, &payee.CreatedAt, &payee.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrPayeeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payee: %w", err)
+	}
+	return payee, nil
+}
+
+func (r *payeeRepository) List(ctx context.Context) ([]*domain.Payee, error) {
+	query := `
+		SELECT id, name, created_at, updated_at
+		FROM payees
+		ORDER BY name
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payees: %w", err)
+	}
+	defer rows.Close()
+
+	var payees []*domain.Payee
+	for rows.Next() {
+		payee := &domain.Payee{}
+		if err := rows.Scan(&payee.ID, &payee.Name, &payee.CreatedAt, &payee.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan payee: %w", err)
+		}
+		payees = append(payees, payee)
+	}
+	return payees, rows.Err()
+}
+
+type payeeRuleRepository struct {
+	db *sql.DB
+}
+
+// NewPayeeRuleRepository creates a new payee rule repository
+func NewPayeeRuleRepository(db *sql.DB) domain.PayeeRuleRepository {
+	return &payeeRuleRepository{db: db}
+}
+
+func (r *payeeRuleRepository) Create(ctx context.Context, rule *domain.PayeeRule) error {
+	query := `
+		INSERT INTO payee_rules (id, pattern, match_type, account_id, min_amount, max_amount, payee_id, category_id, note,
+			priority, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		rule.ID, rule.Pattern, rule.MatchType, rule.AccountID, rule.MinAmount, rule.MaxAmount, rule.PayeeID, rule.CategoryID,
+		rule.Note, rule.Priority, rule.CreatedAt, rule.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create payee rule: %w", err)
+	}
+	return nil
+}
+
+func (r *payeeRuleRepository) GetByID(ctx context.Context, id string) (*domain.PayeeRule, error) {
+	query := `
+		SELECT id, pattern, match_type, account_id, min_amount, max_amount, payee_id, category_id, note,
+			priority, created_at, updated_at
+		FROM payee_rules
+		WHERE id = ?
+	`
+	rule, err := scanPayeeRule(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrPayeeRuleNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payee rule: %w", err)
+	}
+	return rule, nil
+}
+
+func (r *payeeRuleRepository) List(ctx context.Context) ([]*domain.PayeeRule, error) {
+	query := `
+		SELECT id, pattern, match_type, account_id, min_amount, max_amount, payee_id, category_id, note,
+			priority, created_at, updated_at
+		FROM payee_rules
+		ORDER BY priority, created_at
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payee rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*domain.PayeeRule
+	for rows.Next() {
+		rule, err := scanPayeeRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan payee rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *payeeRuleRepository) Update(ctx context.Context, rule *domain.PayeeRule) error {
+	query := `
+		UPDATE payee_rules
+		SET pattern = ?, match_type = ?, account_id = ?, min_amount = ?, max_amount = ?, payee_id = ?, category_id = ?,
+			note = ?, priority = ?, updated_at = ?
+		WHERE id = ?
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		rule.Pattern, rule.MatchType, rule.AccountID, rule.MinAmount, rule.MaxAmount, rule.PayeeID, rule.CategoryID,
+		rule.Note, rule.Priority, rule.UpdatedAt, rule.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update payee rule: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrPayeeRuleNotFound
+	}
+	return nil
+}
+
+func (r *payeeRuleRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM payee_rules WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete payee rule: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrPayeeRuleNotFound
+	}
+	return nil
+}
+
+func scanPayeeRule(row interface{ Scan(...any) error }) (*domain.PayeeRule, error) {
+	rule := &domain.PayeeRule{}
+	var accountID, payeeID, categoryID, note sql.NullString
+	var minAmount, maxAmount sql.NullInt64
+	if err := row.Scan(&rule.ID, &rule.Pattern, &rule.MatchType, &accountID, &minAmount, &maxAmount, &payeeID, &categoryID,
+		&note, &rule.Priority, &rule.CreatedAt, &rule.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if accountID.Valid {
+		rule.AccountID = &accountID.String
+	}
+	if minAmount.Valid {
+		rule.MinAmount = &minAmount.Int64
+	}
+	if maxAmount.Valid {
+		rule.MaxAmount = &maxAmount.Int64
+	}
+	if payeeID.Valid {
+		rule.PayeeID = &payeeID.String
+	}
+	if categoryID.Valid {
+		rule.CategoryID = &categoryID.String
+	}
+	if note.Valid {
+		rule.Note = &note.String
+	}
+	return rule, nil
+}
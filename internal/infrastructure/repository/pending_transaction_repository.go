@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type pendingTransactionRepository struct {
+	db *sql.DB
+}
+
+// NewPendingTransactionRepository creates a new pending transaction repository
+func NewPendingTransactionRepository(db *sql.DB) domain.PendingTransactionRepository {
+	return &pendingTransactionRepository{db: db}
+}
+
+func (r *pendingTransactionRepository) Create(ctx context.Context, pending *domain.PendingTransaction) error {
+	query := `
+		INSERT INTO pending_transactions (id, source, account_id, amount, description, date, sender, subject, raw_body, status, transaction_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		pending.ID, pending.Source, pending.AccountID, pending.Amount, pending.Description, pending.Date,
+		pending.Sender, pending.Subject, pending.RawBody, pending.Status, pending.TransactionID,
+		pending.CreatedAt, pending.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create pending transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *pendingTransactionRepository) GetByID(ctx context.Context, id string) (*domain.PendingTransaction, error) {
+	query := `
+		SELECT id, source, account_id, amount, description, date, sender, subject, raw_body, status, transaction_id, created_at, updated_at
+		FROM pending_transactions
+		WHERE id = ?
+	`
+	pending, err := scanPendingTransaction(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("pending transaction not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending transaction: %w", err)
+	}
+	return pending, nil
+}
+
+// List returns pending transactions with the given status (all statuses when empty), newest first
+func (r *pendingTransactionRepository) List(ctx context.Context, status domain.PendingTransactionStatus) ([]*domain.PendingTransaction, error) {
+	query := `
+		SELECT id, source, account_id, amount, description, date, sender, subject, raw_body, status, transaction_id, created_at, updated_at
+		FROM pending_transactions
+		WHERE ? = '' OR status = ?
+		ORDER BY date DESC, created_at DESC
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, status, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var pendings []*domain.PendingTransaction
+	for rows.Next() {
+		pending, err := scanPendingTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pending transaction: %w", err)
+		}
+		pendings = append(pendings, pending)
+	}
+	return pendings, nil
+}
+
+func (r *pendingTransactionRepository) Update(ctx context.Context, pending *domain.PendingTransaction) error {
+	query := `
+		UPDATE pending_transactions
+		SET account_id = ?, amount = ?, description = ?, date = ?, status = ?, transaction_id = ?, updated_at = ?
+		WHERE id = ?
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		pending.AccountID, pending.Amount, pending.Description, pending.Date, pending.Status,
+		pending.TransactionID, pending.UpdatedAt, pending.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update pending transaction: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("pending transaction not found")
+	}
+	return nil
+}
+
+func (r *pendingTransactionRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM pending_transactions WHERE id = ?`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete pending transaction: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("pending transaction not found")
+	}
+	return nil
+}
+
+// CountByStatusBefore counts pending transactions with the status that were last updated before the given time
+func (r *pendingTransactionRepository) CountByStatusBefore(ctx context.Context, status domain.PendingTransactionStatus, before time.Time) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM pending_transactions WHERE status = ? AND updated_at < ?`, status, before).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count pending transactions: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteByStatusBefore removes pending transactions with the status that were last updated before the given time
+func (r *pendingTransactionRepository) DeleteByStatusBefore(ctx context.Context, status domain.PendingTransactionStatus, before time.Time) (int64, error) {
+	result, err := conn(ctx, r.db).ExecContext(ctx,
+		`DELETE FROM pending_transactions WHERE status = ? AND updated_at < ?`, status, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete pending transactions: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPendingTransaction(row rowScanner) (*domain.PendingTransaction, error) {
+	pending := &domain.PendingTransaction{}
+	var accountID, transactionID sql.NullString
+	err := row.Scan(
+		&pending.ID, &pending.Source, &accountID, &pending.Amount, &pending.Description, &pending.Date,
+		&pending.Sender, &pending.Subject, &pending.RawBody, &pending.Status, &transactionID,
+		&pending.CreatedAt, &pending.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if accountID.Valid {
+		pending.AccountID = &accountID.String
+	}
+	if transactionID.Valid {
+		pending.TransactionID = &transactionID.String
+	}
+	return pending, nil
+}
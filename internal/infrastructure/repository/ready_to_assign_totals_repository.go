@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type readyToAssignTotalsRepository struct {
+	db *sql.DB
+}
+
+// NewReadyToAssignTotalsRepository creates a new Ready to Assign totals repository
+func NewReadyToAssignTotalsRepository(db *sql.DB) domain.ReadyToAssignTotalsRepository {
+	return &readyToAssignTotalsRepository{db: db}
+}
+
+// List returns every period's totals in order
+func (r *readyToAssignTotalsRepository) List(ctx context.Context) ([]*domain.ReadyToAssignTotal, error) {
+	query := `SELECT period, inflows, assigned FROM ready_to_assign_totals ORDER BY period`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ready to assign totals: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []*domain.ReadyToAssignTotal
+	for rows.Next() {
+		total := &domain.ReadyToAssignTotal{}
+		if err := rows.Scan(&total.Period, &total.Inflows, &total.Assigned); err != nil {
+			return nil, fmt.Errorf("failed to scan ready to assign total: %w", err)
+		}
+		totals = append(totals, total)
+	}
+	return totals, rows.Err()
+}
+
+// Rebuild recalculates the totals from scratch, the way migration 033 filled them in
+// The budget revision changes too, so amounts cached from the old totals are dropped.
+func (r *readyToAssignTotalsRepository) Rebuild(ctx context.Context) error {
+	return inTx(ctx, r.db, func(ctx context.Context) error {
+		if _, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM ready_to_assign_totals`); err != nil {
+			return fmt.Errorf("failed to clear ready to assign totals: %w", err)
+		}
+		query := `
+			INSERT INTO ready_to_assign_totals (period, inflows, assigned)
+			SELECT period, SUM(inflows), SUM(assigned) FROM (
+				SELECT strftime('%Y-%m', datetime(date)) AS period, amount AS inflows, 0 AS assigned
+				FROM transactions
+				WHERE amount > 0 AND type NOT IN (?, ?)
+				UNION ALL
+				SELECT a.period, 0, a.amount
+				FROM allocations a
+				LEFT JOIN categories c ON c.id = a.category_id
+				WHERE COALESCE(c.payment_for_account_id, '') = ''
+			)
+			GROUP BY period
+		`
+		if _, err := conn(ctx, r.db).ExecContext(ctx, query, domain.TransactionTypeTransfer, domain.TransactionTypeAdjustment); err != nil {
+			return fmt.Errorf("failed to rebuild ready to assign totals: %w", err)
+		}
+		if _, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE budget_state SET revision = random() WHERE id = 'singleton'`); err != nil {
+			return fmt.Errorf("failed to update budget revision: %w", err)
+		}
+		return nil
+	})
+}
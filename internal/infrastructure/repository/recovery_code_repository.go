@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type recoveryCodeRepository struct {
+	db *sql.DB
+}
+
+// NewRecoveryCodeRepository creates a new recovery code repository
+func NewRecoveryCodeRepository(db *sql.DB) domain.RecoveryCodeRepository {
+	return &recoveryCodeRepository{db: db}
+}
+
+// ReplaceForUser deletes the user's existing recovery codes and stores the new set
+func (r *recoveryCodeRepository) ReplaceForUser(ctx context.Context, userID string, codes []*domain.RecoveryCode) error {
+	return inTx(ctx, r.db, func(ctx context.Context) error {
+		if _, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id = ?`, userID); err != nil {
+			return fmt.Errorf("failed to delete recovery codes: %w", err)
+		}
+
+		for _, code := range codes {
+			_, err := conn(ctx, r.db).ExecContext(ctx, `
+				INSERT INTO recovery_codes (id, user_id, code_hash, used_at, created_at)
+				VALUES (?, ?, ?, ?, ?)
+			`, code.ID, code.UserID, code.CodeHash, code.UsedAt, code.CreatedAt)
+			if err != nil {
+				return fmt.Errorf("failed to create recovery code: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// Use marks an unused recovery code as used
+// Returns an error if the user has no unused code with that hash.
+func (r *recoveryCodeRepository) Use(ctx context.Context, userID, codeHash string, usedAt time.Time) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE recovery_codes SET used_at = ?
+		WHERE user_id = ? AND code_hash = ? AND used_at IS NULL
+	`, usedAt, userID, codeHash)
+	if err != nil {
+		return fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("recovery code not found")
+	}
+	return nil
+}
+
+func (r *recoveryCodeRepository) CountUnused(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM recovery_codes WHERE user_id = ? AND used_at IS NULL`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+	return count, nil
+}
+
+func (r *recoveryCodeRepository) DeleteByUser(ctx context.Context, userID string) error {
+	if _, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	return nil
+}
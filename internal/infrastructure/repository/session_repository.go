@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type sessionRepository struct {
+	db *sql.DB
+}
+
+// NewSessionRepository creates a new session repository
+func NewSessionRepository(db *sql.DB) domain.SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+const sessionColumns = `id, user_id, token_hash, device, user_agent, ip_address, created_at, last_used_at, expires_at`
+
+func (r *sessionRepository) Create(ctx context.Context, session *domain.Session) error {
+	query := `
+		INSERT INTO sessions (` + sessionColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		session.ID, session.UserID, session.TokenHash, session.Device, session.UserAgent, session.IPAddress,
+		session.CreatedAt, session.LastUsedAt, session.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	return nil
+}
+
+func (r *sessionRepository) GetByHash(ctx context.Context, tokenHash string) (*domain.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE token_hash = ?`
+	session, err := scanSession(conn(ctx, r.db).QueryRowContext(ctx, query, tokenHash))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("session not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return session, nil
+}
+
+// ListByUser returns the user's sessions, most recently used first
+func (r *sessionRepository) ListByUser(ctx context.Context, userID string) ([]*domain.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE user_id = ? ORDER BY last_used_at DESC`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*domain.Session
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+func (r *sessionRepository) UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE sessions SET last_used_at = ? WHERE id = ?`, lastUsedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+	return nil
+}
+
+func (r *sessionRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM sessions WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("session not found")
+	}
+	return nil
+}
+
+// DeleteByUser deletes all of a user's sessions except exceptID (which may be empty)
+func (r *sessionRepository) DeleteByUser(ctx context.Context, userID, exceptID string) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM sessions WHERE user_id = ? AND id != ?`, userID, exceptID)
+	if err != nil {
+		return fmt.Errorf("failed to delete sessions: %w", err)
+	}
+	return nil
+}
+
+func (r *sessionRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM sessions WHERE expires_at < ?`, now)
+	if err != nil {
+		return fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+	return nil
+}
+
+func (r *sessionRepository) CountExpired(ctx context.Context, now time.Time) (int64, error) {
+	var count int64
+	if err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT COUNT(*) FROM sessions WHERE expires_at < ?`, now).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count expired sessions: %w", err)
+	}
+	return count, nil
+}
+
+func scanSession(row rowScanner) (*domain.Session, error) {
+	session := &domain.Session{}
+	err := row.Scan(
+		&session.ID, &session.UserID, &session.TokenHash, &session.Device, &session.UserAgent, &session.IPAddress,
+		&session.CreatedAt, &session.LastUsedAt, &session.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
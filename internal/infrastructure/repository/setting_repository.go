@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type settingRepository struct {
+	db *sql.DB
+}
+
+// NewSettingRepository creates a new setting repository
+func NewSettingRepository(db *sql.DB) domain.SettingRepository {
+	return &settingRepository{db: db}
+}
+
+func (r *settingRepository) Get(ctx context.Context, key string) (*domain.Setting, error) {
+	query := `
+		SELECT key, value, updated_at
+		FROM settings
+		WHERE key = ?
+	`
+	setting := &domain.Setting{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, key).Scan(&setting.Key, &setting.Value, &setting.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("setting not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get setting: %w", err)
+	}
+	return setting, nil
+}
+
+// Set inserts the setting or replaces the existing value for its key
+func (r *settingRepository) Set(ctx context.Context, setting *domain.Setting) error {
+	query := `
+		INSERT INTO settings (key, value, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, setting.Key, setting.Value, setting.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save setting: %w", err)
+	}
+	return nil
+}
+
+func (r *settingRepository) List(ctx context.Context) ([]*domain.Setting, error) {
+	query := `
+		SELECT key, value, updated_at
+		FROM settings
+		ORDER BY key
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list settings: %w", err)
+	}
+	defer rows.Close()
+
+	var settings []*domain.Setting
+	for rows.Next() {
+		setting := &domain.Setting{}
+		if err := rows.Scan(&setting.Key, &setting.Value, &setting.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan setting: %w", err)
+		}
+		settings = append(settings, setting)
+	}
+	return settings, nil
+}
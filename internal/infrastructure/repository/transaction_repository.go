@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/billybbuffum/budget/internal/domain"
@@ -20,12 +21,13 @@ func NewTransactionRepository(db *sql.DB) domain.TransactionRepository {
 
 func (r *transactionRepository) Create(ctx context.Context, transaction *domain.Transaction) error {
 	query := `
-		INSERT INTO transactions (id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO transactions (id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, note, payee_id, import_id, original_currency, original_amount, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
 		transaction.ID, transaction.Type, transaction.AccountID, transaction.TransferToAccountID, transaction.CategoryID,
-		transaction.Amount, transaction.Description, transaction.Date, transaction.FitID,
+		transaction.Amount, transaction.Description, transaction.Date.UTC(), transaction.FitID, transaction.Note,
+		transaction.PayeeID, transaction.ImportID, transaction.OriginalCurrency, transaction.OriginalAmount,
 		transaction.CreatedAt, transaction.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create transaction: %w", err)
@@ -35,15 +37,16 @@ func (r *transactionRepository) Create(ctx context.Context, transaction *domain.
 
 func (r *transactionRepository) GetByID(ctx context.Context, id string) (*domain.Transaction, error) {
 	query := `
-		SELECT id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, created_at, updated_at
+		SELECT id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, note, payee_id, import_id, original_currency, original_amount, created_at, updated_at
 		FROM transactions
 		WHERE id = ?
 	`
 	transaction := &domain.Transaction{}
-	var categoryID, transferToAccountID, fitID sql.NullString
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	var categoryID, transferToAccountID, fitID, note, payeeID, importID, originalCurrency sql.NullString
+	var originalAmount sql.NullInt64
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
 		&transaction.ID, &transaction.Type, &transaction.AccountID, &transferToAccountID, &categoryID,
-		&transaction.Amount, &transaction.Description, &transaction.Date, &fitID,
+		&transaction.Amount, &transaction.Description, &transaction.Date, &fitID, &note, &payeeID, &importID, &originalCurrency, &originalAmount,
 		&transaction.CreatedAt, &transaction.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("transaction not found")
@@ -60,16 +63,29 @@ func (r *transactionRepository) GetByID(ctx context.Context, id string) (*domain
 	if fitID.Valid {
 		transaction.FitID = &fitID.String
 	}
+	if note.Valid {
+		transaction.Note = &note.String
+	}
+	if payeeID.Valid {
+		transaction.PayeeID = &payeeID.String
+	}
+	if importID.Valid {
+		transaction.ImportID = &importID.String
+	}
+	if originalCurrency.Valid && originalAmount.Valid {
+		transaction.OriginalCurrency = &originalCurrency.String
+		transaction.OriginalAmount = &originalAmount.Int64
+	}
 	return transaction, nil
 }
 
 func (r *transactionRepository) List(ctx context.Context) ([]*domain.Transaction, error) {
 	query := `
-		SELECT id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, created_at, updated_at
+		SELECT id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, note, payee_id, import_id, original_currency, original_amount, created_at, updated_at
 		FROM transactions
 		ORDER BY date DESC
 	`
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list transactions: %w", err)
 	}
@@ -80,12 +96,12 @@ func (r *transactionRepository) List(ctx context.Context) ([]*domain.Transaction
 
 func (r *transactionRepository) ListByAccount(ctx context.Context, accountID string) ([]*domain.Transaction, error) {
 	query := `
-		SELECT id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, created_at, updated_at
+		SELECT id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, note, payee_id, import_id, original_currency, original_amount, created_at, updated_at
 		FROM transactions
 		WHERE account_id = ?
 		ORDER BY date DESC
 	`
-	rows, err := r.db.QueryContext(ctx, query, accountID)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, accountID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list transactions by account: %w", err)
 	}
@@ -94,14 +110,31 @@ func (r *transactionRepository) ListByAccount(ctx context.Context, accountID str
 	return r.scanTransactions(rows)
 }
 
+// ListByImport returns the transactions saved from an imported file, in date order
+func (r *transactionRepository) ListByImport(ctx context.Context, importID string) ([]*domain.Transaction, error) {
+	query := `
+		SELECT id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, note, payee_id, import_id, original_currency, original_amount, created_at, updated_at
+		FROM transactions
+		WHERE import_id = ?
+		ORDER BY date, created_at
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, importID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions by import: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanTransactions(rows)
+}
+
 func (r *transactionRepository) ListByCategory(ctx context.Context, categoryID string) ([]*domain.Transaction, error) {
 	query := `
-		SELECT id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, created_at, updated_at
+		SELECT id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, note, payee_id, import_id, original_currency, original_amount, created_at, updated_at
 		FROM transactions
 		WHERE category_id = ?
 		ORDER BY date DESC
 	`
-	rows, err := r.db.QueryContext(ctx, query, categoryID)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, categoryID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list transactions by category: %w", err)
 	}
@@ -112,12 +145,14 @@ func (r *transactionRepository) ListByCategory(ctx context.Context, categoryID s
 
 func (r *transactionRepository) ListByPeriod(ctx context.Context, startDate, endDate string) ([]*domain.Transaction, error) {
 	query := `
-		SELECT id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, created_at, updated_at
+		SELECT id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, note, payee_id, import_id, original_currency, original_amount, created_at, updated_at
 		FROM transactions
-		WHERE date >= ? AND date <= ?
+		WHERE datetime(date) >= datetime(?) AND datetime(date) <= datetime(?)
 		ORDER BY date DESC
 	`
-	rows, err := r.db.QueryContext(ctx, query, startDate, endDate)
+	// Compare through datetime() - dates are stored as "YYYY-MM-DD HH:MM:SS+00:00" while callers
+	// pass RFC3339, so a plain string comparison drops transactions at the exact start time
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, startDate, endDate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list transactions by period: %w", err)
 	}
@@ -127,38 +162,131 @@ func (r *transactionRepository) ListByPeriod(ctx context.Context, startDate, end
 }
 
 func (r *transactionRepository) GetCategoryActivity(ctx context.Context, categoryID, period string) (int64, error) {
-	// Parse period to get date range (YYYY-MM format)
-	t, err := time.Parse("2006-01", period)
+	// Parse period to get date range (a month, week or quarter key)
+	p, err := domain.ParsePeriod(period)
 	if err != nil {
 		return 0, fmt.Errorf("invalid period format: %w", err)
 	}
 
-	t = t.UTC()
-	startDate := t.Format(time.RFC3339)
-	endDate := t.AddDate(0, 1, 0).Add(-time.Second).Format(time.RFC3339)
+	// Compare through datetime() so the period boundary is the same one ListByPeriod and
+	// the budget's period-by-period rollover use, whatever offset a date was stored with
+	startDate := p.Start.Format(time.RFC3339)
+	endDate := p.End.Format(time.RFC3339)
 
 	query := `
 		SELECT COALESCE(SUM(amount), 0)
 		FROM transactions
-		WHERE category_id = ? AND date >= ? AND date <= ?
+		WHERE category_id = ? AND datetime(date) >= datetime(?) AND datetime(date) < datetime(?)
 	`
 	var activity int64
-	err = r.db.QueryRowContext(ctx, query, categoryID, startDate, endDate).Scan(&activity)
+	err = conn(ctx, r.db).QueryRowContext(ctx, query, categoryID, startDate, endDate).Scan(&activity)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get category activity: %w", err)
 	}
 	return activity, nil
 }
 
+// SumActivityByCategory totals categorized transactions per category between two RFC3339 times
+// Both ends are inclusive, as in ListByPeriod
+func (r *transactionRepository) SumActivityByCategory(ctx context.Context, startDate, endDate string) (map[string]int64, error) {
+	query := `
+		SELECT category_id, COALESCE(SUM(amount), 0)
+		FROM transactions
+		WHERE category_id IS NOT NULL AND datetime(date) >= datetime(?) AND datetime(date) <= datetime(?)
+		GROUP BY category_id
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum category activity: %w", err)
+	}
+	defer rows.Close()
+
+	totals := make(map[string]int64)
+	for rows.Next() {
+		var categoryID string
+		var total int64
+		if err := rows.Scan(&categoryID, &total); err != nil {
+			return nil, fmt.Errorf("failed to scan category activity: %w", err)
+		}
+		totals[categoryID] = total
+	}
+	return totals, rows.Err()
+}
+
+// periodKeyExpr is the SQL for the key of the period of periodType a date column falls in
+// Dates are taken in UTC through datetime(), as in GetCategoryActivity.
+func periodKeyExpr(periodType domain.PeriodType, column string) string {
+	date := "datetime(" + column + ")"
+	switch periodType {
+	case domain.PeriodWeek:
+		return "strftime('%G-W%V', " + date + ")"
+	case domain.PeriodQuarter:
+		return "strftime('%Y', " + date + ") || '-Q' || ((CAST(strftime('%m', " + date + ") AS INTEGER) + 2) / 3)"
+	}
+	return "strftime('%Y-%m', " + date + ")"
+}
+
+// SumSpendingByCategory totals categorized outflows per category, account and period
+func (r *transactionRepository) SumSpendingByCategory(ctx context.Context, periodType domain.PeriodType) ([]*domain.CategorySpending, error) {
+	query := `
+		SELECT category_id, account_id, ` + periodKeyExpr(periodType, "date") + ` AS period, SUM(-amount)
+		FROM transactions
+		WHERE category_id IS NOT NULL AND category_id != '' AND amount < 0
+		GROUP BY category_id, account_id, period
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum category spending: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []*domain.CategorySpending
+	for rows.Next() {
+		total := &domain.CategorySpending{}
+		if err := rows.Scan(&total.CategoryID, &total.AccountID, &total.Period, &total.Spent); err != nil {
+			return nil, fmt.Errorf("failed to scan category spending: %w", err)
+		}
+		totals = append(totals, total)
+	}
+	return totals, rows.Err()
+}
+
+// SumInflowsByPeriod totals income per period, as counted towards Ready to Assign
+func (r *transactionRepository) SumInflowsByPeriod(ctx context.Context, periodType domain.PeriodType) (map[string]int64, error) {
+	query := `
+		SELECT ` + periodKeyExpr(periodType, "date") + ` AS period, SUM(amount)
+		FROM transactions
+		WHERE amount > 0 AND type NOT IN (?, ?)
+		GROUP BY period
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, domain.TransactionTypeTransfer, domain.TransactionTypeAdjustment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum inflows: %w", err)
+	}
+	defer rows.Close()
+
+	totals := make(map[string]int64)
+	for rows.Next() {
+		var period string
+		var total int64
+		if err := rows.Scan(&period, &total); err != nil {
+			return nil, fmt.Errorf("failed to scan inflows: %w", err)
+		}
+		totals[period] = total
+	}
+	return totals, rows.Err()
+}
+
 func (r *transactionRepository) Update(ctx context.Context, transaction *domain.Transaction) error {
 	query := `
 		UPDATE transactions
-		SET type = ?, account_id = ?, transfer_to_account_id = ?, category_id = ?, amount = ?, description = ?, date = ?, fitid = ?, updated_at = ?
+		SET type = ?, account_id = ?, transfer_to_account_id = ?, category_id = ?, amount = ?, description = ?, date = ?, fitid = ?, note = ?, payee_id = ?, import_id = ?, original_currency = ?, original_amount = ?, updated_at = ?
 		WHERE id = ?
 	`
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
 		transaction.Type, transaction.AccountID, transaction.TransferToAccountID, transaction.CategoryID, transaction.Amount,
-		transaction.Description, transaction.Date, transaction.FitID, transaction.UpdatedAt, transaction.ID)
+		transaction.Description, transaction.Date.UTC(), transaction.FitID, transaction.Note, transaction.PayeeID, transaction.ImportID,
+		transaction.OriginalCurrency, transaction.OriginalAmount, transaction.UpdatedAt, transaction.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update transaction: %w", err)
 	}
@@ -174,7 +302,7 @@ func (r *transactionRepository) Update(ctx context.Context, transaction *domain.
 
 func (r *transactionRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM transactions WHERE id = ?`
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete transaction: %w", err)
 	}
@@ -190,12 +318,12 @@ func (r *transactionRepository) Delete(ctx context.Context, id string) error {
 
 func (r *transactionRepository) ListUncategorized(ctx context.Context) ([]*domain.Transaction, error) {
 	query := `
-		SELECT id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, created_at, updated_at
+		SELECT id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, note, payee_id, import_id, original_currency, original_amount, created_at, updated_at
 		FROM transactions
 		WHERE category_id IS NULL AND type = 'normal'
 		ORDER BY date DESC
 	`
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list uncategorized transactions: %w", err)
 	}
@@ -204,9 +332,149 @@ func (r *transactionRepository) ListUncategorized(ctx context.Context) ([]*domai
 	return r.scanTransactions(rows)
 }
 
+func (r *transactionRepository) Search(ctx context.Context, query domain.TransactionQuery) ([]*domain.Transaction, int, error) {
+	var conditions []string
+	var args []interface{}
+	if query.AccountID != "" {
+		conditions = append(conditions, "account_id = ?")
+		args = append(args, query.AccountID)
+	}
+	if query.CategoryID != "" {
+		conditions = append(conditions, "category_id = ?")
+		args = append(args, query.CategoryID)
+	}
+	if query.Uncategorized {
+		conditions = append(conditions, "category_id IS NULL AND type = 'normal'")
+	}
+	// Compare through datetime(), as in ListByPeriod
+	if query.Since != nil {
+		conditions = append(conditions, "datetime(date) >= datetime(?)")
+		args = append(args, query.Since.UTC().Format(time.RFC3339))
+	}
+	if query.Until != nil {
+		conditions = append(conditions, "datetime(date) <= datetime(?)")
+		args = append(args, query.Until.UTC().Format(time.RFC3339))
+	}
+	if query.MinAmount != nil {
+		conditions = append(conditions, "ABS(amount) >= ?")
+		args = append(args, *query.MinAmount)
+	}
+	if query.MaxAmount != nil {
+		conditions = append(conditions, "ABS(amount) <= ?")
+		args = append(args, *query.MaxAmount)
+	}
+	if query.Search != "" {
+		pattern := likePattern(query.Search)
+		conditions = append(conditions, `(description LIKE ? ESCAPE '\' OR note LIKE ? ESCAPE '\')`)
+		args = append(args, pattern, pattern)
+	}
+	from := " FROM transactions"
+	ranked := false
+	if words := strings.Fields(query.Text); len(words) > 0 {
+		indexed, err := r.hasSearchIndex(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		if indexed {
+			// Every word as a quoted prefix, so input can't be read as FTS5 query syntax
+			terms := make([]string, len(words))
+			for i, word := range words {
+				terms[i] = `"` + strings.ReplaceAll(word, `"`, `""`) + `"*`
+			}
+			from += `
+				JOIN (
+					SELECT transaction_id, bm25(transactions_fts) AS score
+					FROM transactions_fts
+					WHERE transactions_fts MATCH ?
+				) AS fts ON fts.transaction_id = transactions.id`
+			args = append([]interface{}{strings.Join(terms, " ")}, args...)
+			ranked = true
+		} else {
+			// No FTS5 in this build: every word somewhere in the description, note or payee
+			for _, word := range words {
+				pattern := likePattern(word)
+				conditions = append(conditions, `(description LIKE ? ESCAPE '\' OR note LIKE ? ESCAPE '\' OR payee_id IN (SELECT id FROM payees WHERE name LIKE ? ESCAPE '\'))`)
+				args = append(args, pattern, pattern, pattern)
+			}
+		}
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, "SELECT COUNT(*)"+from+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count transactions: %w", err)
+	}
+
+	column := "datetime(date)"
+	switch query.Sort {
+	case domain.TransactionSortAmount:
+		column = "amount"
+	case domain.TransactionSortDescription:
+		column = "description COLLATE NOCASE"
+	case domain.TransactionSortCreatedAt:
+		column = "created_at"
+	}
+	direction := "DESC"
+	if query.Ascending {
+		direction = "ASC"
+	}
+	order := column + " " + direction
+	if query.Sort == domain.TransactionSortRelevance && ranked {
+		// bm25 scores better matches lower, so best first is ascending
+		rankDirection := "ASC"
+		if query.Ascending {
+			rankDirection = "DESC"
+		}
+		order = "fts.score " + rankDirection + ", datetime(date) DESC"
+	}
+	// id breaks ties so pages don't overlap
+	sqlQuery := `
+		SELECT id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, note, payee_id, import_id, original_currency, original_amount, created_at, updated_at` +
+		from + where + `
+		ORDER BY ` + order + `, id ` + direction
+	if query.Limit > 0 {
+		sqlQuery += " LIMIT ? OFFSET ?"
+		args = append(args, query.Limit, query.Offset)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search transactions: %w", err)
+	}
+	defer rows.Close()
+
+	transactions, err := r.scanTransactions(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return transactions, total, nil
+}
+
+// likePattern matches text anywhere in a column with LIKE ... ESCAPE '\'
+// LIKE is case-insensitive for ASCII in SQLite; the text's own wildcards are escaped.
+func likePattern(text string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(text) + "%"
+}
+
+// hasSearchIndex reports whether the FTS5 index is being kept up to date
+// It only is when SQLite was built with FTS5; see database.ensureSearchIndex.
+func (r *transactionRepository) hasSearchIndex(ctx context.Context) (bool, error) {
+	var count int
+	err := conn(ctx, r.db).QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name = 'transactions_fts_insert'",
+	).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check for search index: %w", err)
+	}
+	return count > 0, nil
+}
+
 func (r *transactionRepository) FindDuplicate(ctx context.Context, accountID string, date time.Time, amount int64, description string) (*domain.Transaction, error) {
 	query := `
-		SELECT id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, created_at, updated_at
+		SELECT id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, note, payee_id, import_id, original_currency, original_amount, created_at, updated_at
 		FROM transactions
 		WHERE account_id = ?
 			AND date(date) = date(?)
@@ -215,10 +483,11 @@ func (r *transactionRepository) FindDuplicate(ctx context.Context, accountID str
 		LIMIT 1
 	`
 	transaction := &domain.Transaction{}
-	var categoryID, transferToAccountID, fitID sql.NullString
-	err := r.db.QueryRowContext(ctx, query, accountID, date, amount, description).Scan(
+	var categoryID, transferToAccountID, fitID, note, payeeID, importID, originalCurrency sql.NullString
+	var originalAmount sql.NullInt64
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, accountID, date, amount, description).Scan(
 		&transaction.ID, &transaction.Type, &transaction.AccountID, &transferToAccountID, &categoryID,
-		&transaction.Amount, &transaction.Description, &transaction.Date, &fitID,
+		&transaction.Amount, &transaction.Description, &transaction.Date, &fitID, &note, &payeeID, &importID, &originalCurrency, &originalAmount,
 		&transaction.CreatedAt, &transaction.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil // No duplicate found
@@ -235,22 +504,36 @@ func (r *transactionRepository) FindDuplicate(ctx context.Context, accountID str
 	if fitID.Valid {
 		transaction.FitID = &fitID.String
 	}
+	if note.Valid {
+		transaction.Note = &note.String
+	}
+	if payeeID.Valid {
+		transaction.PayeeID = &payeeID.String
+	}
+	if importID.Valid {
+		transaction.ImportID = &importID.String
+	}
+	if originalCurrency.Valid && originalAmount.Valid {
+		transaction.OriginalCurrency = &originalCurrency.String
+		transaction.OriginalAmount = &originalAmount.Int64
+	}
 	return transaction, nil
 }
 
 // FindByFitID finds a transaction by account ID and FitID (for OFX import duplicate detection)
 func (r *transactionRepository) FindByFitID(ctx context.Context, accountID string, fitID string) (*domain.Transaction, error) {
 	query := `
-		SELECT id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, created_at, updated_at
+		SELECT id, type, account_id, transfer_to_account_id, category_id, amount, description, date, fitid, note, payee_id, import_id, original_currency, original_amount, created_at, updated_at
 		FROM transactions
 		WHERE account_id = ? AND fitid = ?
 		LIMIT 1
 	`
 	transaction := &domain.Transaction{}
-	var categoryID, transferToAccountID, fitIDNull sql.NullString
-	err := r.db.QueryRowContext(ctx, query, accountID, fitID).Scan(
+	var categoryID, transferToAccountID, fitIDNull, note, payeeID, importID, originalCurrency sql.NullString
+	var originalAmount sql.NullInt64
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, accountID, fitID).Scan(
 		&transaction.ID, &transaction.Type, &transaction.AccountID, &transferToAccountID, &categoryID,
-		&transaction.Amount, &transaction.Description, &transaction.Date, &fitIDNull,
+		&transaction.Amount, &transaction.Description, &transaction.Date, &fitIDNull, &note, &payeeID, &importID, &originalCurrency, &originalAmount,
 		&transaction.CreatedAt, &transaction.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil // No duplicate found
@@ -267,6 +550,19 @@ func (r *transactionRepository) FindByFitID(ctx context.Context, accountID strin
 	if fitIDNull.Valid {
 		transaction.FitID = &fitIDNull.String
 	}
+	if note.Valid {
+		transaction.Note = &note.String
+	}
+	if payeeID.Valid {
+		transaction.PayeeID = &payeeID.String
+	}
+	if importID.Valid {
+		transaction.ImportID = &importID.String
+	}
+	if originalCurrency.Valid && originalAmount.Valid {
+		transaction.OriginalCurrency = &originalCurrency.String
+		transaction.OriginalAmount = &originalAmount.Int64
+	}
 	return transaction, nil
 }
 
@@ -275,31 +571,51 @@ func (r *transactionRepository) BulkUpdateCategory(ctx context.Context, transact
 		return nil
 	}
 
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback()
+	return inTx(ctx, r.db, func(ctx context.Context) error {
+		// Adjustments and starting balances never take a category
+		query := `UPDATE transactions SET category_id = ?, updated_at = ? WHERE id = ? AND type NOT IN ('adjustment', 'starting_balance')`
+		stmt, err := conn(ctx, r.db).PrepareContext(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to prepare statement: %w", err)
+		}
+		defer stmt.Close()
 
-	query := `UPDATE transactions SET category_id = ?, updated_at = ? WHERE id = ?`
-	stmt, err := tx.PrepareContext(ctx, query)
-	if err != nil {
-		return fmt.Errorf("failed to prepare statement: %w", err)
+		now := time.Now()
+		for _, id := range transactionIDs {
+			_, err := stmt.ExecContext(ctx, categoryID, now, id)
+			if err != nil {
+				return fmt.Errorf("failed to update transaction %s: %w", id, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *transactionRepository) BulkUpdate(ctx context.Context, transactionIDs []string, edit domain.TransactionBulkEdit) error {
+	if len(transactionIDs) == 0 {
+		return nil
 	}
-	defer stmt.Close()
 
-	now := time.Now()
+	var payeeID any
+	if edit.PayeeID != nil && *edit.PayeeID != "" {
+		payeeID = *edit.PayeeID
+	}
+	// Dates are stored in UTC, so shifting keeps the format migrateNormalizeTransactionDates left
+	query := `
+		UPDATE transactions
+		SET payee_id = CASE WHEN ? THEN ? ELSE payee_id END,
+			account_id = COALESCE(?, account_id),
+			date = CASE WHEN ? = 0 THEN date ELSE strftime('%Y-%m-%d %H:%M:%S+00:00', date, ? || ' days') END,
+			updated_at = ?
+		WHERE id IN (` + strings.TrimSuffix(strings.Repeat("?, ", len(transactionIDs)), ", ") + `)
+	`
+	args := []any{edit.PayeeID != nil, payeeID, edit.AccountID, edit.ShiftDays, edit.ShiftDays, time.Now()}
 	for _, id := range transactionIDs {
-		_, err := stmt.ExecContext(ctx, categoryID, now, id)
-		if err != nil {
-			return fmt.Errorf("failed to update transaction %s: %w", id, err)
-		}
+		args = append(args, id)
 	}
-
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to update transactions: %w", err)
 	}
-
 	return nil
 }
 
@@ -307,9 +623,10 @@ func (r *transactionRepository) scanTransactions(rows *sql.Rows) ([]*domain.Tran
 	var transactions []*domain.Transaction
 	for rows.Next() {
 		transaction := &domain.Transaction{}
-		var categoryID, transferToAccountID, fitID sql.NullString
+		var categoryID, transferToAccountID, fitID, note, payeeID, importID, originalCurrency sql.NullString
+		var originalAmount sql.NullInt64
 		if err := rows.Scan(&transaction.ID, &transaction.Type, &transaction.AccountID, &transferToAccountID, &categoryID,
-			&transaction.Amount, &transaction.Description, &transaction.Date, &fitID,
+			&transaction.Amount, &transaction.Description, &transaction.Date, &fitID, &note, &payeeID, &importID, &originalCurrency, &originalAmount,
 			&transaction.CreatedAt, &transaction.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan transaction: %w", err)
 		}
@@ -322,6 +639,19 @@ func (r *transactionRepository) scanTransactions(rows *sql.Rows) ([]*domain.Tran
 		if fitID.Valid {
 			transaction.FitID = &fitID.String
 		}
+		if note.Valid {
+			transaction.Note = &note.String
+		}
+		if payeeID.Valid {
+			transaction.PayeeID = &payeeID.String
+		}
+		if importID.Valid {
+			transaction.ImportID = &importID.String
+		}
+		if originalCurrency.Valid && originalAmount.Valid {
+			transaction.OriginalCurrency = &originalCurrency.String
+			transaction.OriginalAmount = &originalAmount.Int64
+		}
 		transactions = append(transactions, transaction)
 	}
 	return transactions, nil
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type transferHintRepository struct {
+	db *sql.DB
+}
+
+// NewTransferHintRepository creates a new transfer hint repository
+func NewTransferHintRepository(db *sql.DB) domain.TransferHintRepository {
+	return &transferHintRepository{db: db}
+}
+
+func (r *transferHintRepository) Create(ctx context.Context, hint *domain.TransferHint) error {
+	query := `
+		INSERT INTO transfer_hints (id, account_id, pattern, target_account_id, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		hint.ID, hint.AccountID, hint.Pattern, hint.TargetAccountID, hint.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create transfer hint: %w", err)
+	}
+	return nil
+}
+
+func (r *transferHintRepository) GetByID(ctx context.Context, id string) (*domain.TransferHint, error) {
+	query := `
+		SELECT id, account_id, pattern, target_account_id, created_at
+		FROM transfer_hints
+		WHERE id = ?
+	`
+	hint := &domain.TransferHint{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
+		&hint.ID, &hint.AccountID, &hint.Pattern, &hint.TargetAccountID, &hint.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrTransferHintNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer hint: %w", err)
+	}
+	return hint, nil
+}
+
+func (r *transferHintRepository) ListByAccount(ctx context.Context, accountID string) ([]*domain.TransferHint, error) {
+	query := `
+		SELECT id, account_id, pattern, target_account_id, created_at
+		FROM transfer_hints
+		WHERE account_id = ?
+		ORDER BY created_at
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transfer hints: %w", err)
+	}
+	defer rows.Close()
+
+	var hints []*domain.TransferHint
+	for rows.Next() {
+		hint := &domain.TransferHint{}
+		if err := rows.Scan(&hint.ID, &hint.AccountID, &hint.Pattern, &hint.TargetAccountID, &hint.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan transfer hint: %w", err)
+		}
+		hints = append(hints, hint)
+	}
+	return hints, rows.Err()
+}
+
+func (r *transferHintRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM transfer_hints WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete transfer hint: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrTransferHintNotFound
+	}
+	return nil
+}
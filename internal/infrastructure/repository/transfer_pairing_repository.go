@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type transferPairingRepository struct {
+	db *sql.DB
+}
+
+// NewTransferPairingRepository creates a new transfer pairing repository
+func NewTransferPairingRepository(db *sql.DB) domain.TransferPairingRepository {
+	return &transferPairingRepository{db: db}
+}
+
+func (r *transferPairingRepository) Record(ctx context.Context, pairing *domain.TransferPairing) error {
+	query := `
+		INSERT INTO transfer_pairings (id, from_account_id, from_payee, to_account_id, to_payee, accepted, rejected, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(from_account_id, from_payee, to_account_id, to_payee) DO UPDATE SET
+			accepted = accepted + excluded.accepted,
+			rejected = rejected + excluded.rejected,
+			updated_at = excluded.updated_at
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		pairing.ID, pairing.FromAccountID, pairing.FromPayee, pairing.ToAccountID, pairing.ToPayee,
+		pairing.Accepted, pairing.Rejected, pairing.CreatedAt, pairing.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record transfer pairing: %w", err)
+	}
+	return nil
+}
+
+func (r *transferPairingRepository) GetByID(ctx context.Context, id string) (*domain.TransferPairing, error) {
+	query := `
+		SELECT id, from_account_id, from_payee, to_account_id, to_payee, accepted, rejected, created_at, updated_at
+		FROM transfer_pairings
+		WHERE id = ?
+	`
+	pairing := &domain.TransferPairing{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
+		&pairing.ID, &pairing.FromAccountID, &pairing.FromPayee, &pairing.ToAccountID, &pairing.ToPayee,
+		&pairing.Accepted, &pairing.Rejected, &pairing.CreatedAt, &pairing.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrTransferPairingNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer pairing: %w", err)
+	}
+	return pairing, nil
+}
+
+func (r *transferPairingRepository) List(ctx context.Context) ([]*domain.TransferPairing, error) {
+	query := `
+		SELECT id, from_account_id, from_payee, to_account_id, to_payee, accepted, rejected, created_at, updated_at
+		FROM transfer_pairings
+		ORDER BY updated_at DESC
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transfer pairings: %w", err)
+	}
+	defer rows.Close()
+
+	var pairings []*domain.TransferPairing
+	for rows.Next() {
+		pairing := &domain.TransferPairing{}
+		if err := rows.Scan(&pairing.ID, &pairing.FromAccountID, &pairing.FromPayee, &pairing.ToAccountID, &pairing.ToPayee,
+			&pairing.Accepted, &pairing.Rejected, &pairing.CreatedAt, &pairing.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan transfer pairing: %w", err)
+		}
+		pairings = append(pairings, pairing)
+	}
+	return pairings, rows.Err()
+}
+
+func (r *transferPairingRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM transfer_pairings WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete transfer pairing: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrTransferPairingNotFound
+	}
+	return nil
+}
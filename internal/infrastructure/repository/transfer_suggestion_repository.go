@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type transferSuggestionRepository struct {
+	db *sql.DB
+}
+
+// NewTransferSuggestionRepository creates a new transfer suggestion repository
+func NewTransferSuggestionRepository(db *sql.DB) domain.TransferSuggestionRepository {
+	return &transferSuggestionRepository{db: db}
+}
+
+func (r *transferSuggestionRepository) Create(ctx context.Context, suggestion *domain.TransferSuggestion) error {
+	query := `
+		INSERT INTO transfer_suggestions (id, from_transaction_id, to_transaction_id, score, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		suggestion.ID, suggestion.FromTransactionID, suggestion.ToTransactionID, suggestion.Score, suggestion.Status,
+		suggestion.CreatedAt, suggestion.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create transfer suggestion: %w", err)
+	}
+	return nil
+}
+
+func (r *transferSuggestionRepository) GetByID(ctx context.Context, id string) (*domain.TransferSuggestion, error) {
+	query := `
+		SELECT id, from_transaction_id, to_transaction_id, score, status, created_at, updated_at
+		FROM transfer_suggestions
+		WHERE id = ?
+	`
+	suggestion := &domain.TransferSuggestion{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
+		&suggestion.ID, &suggestion.FromTransactionID, &suggestion.ToTransactionID, &suggestion.Score, &suggestion.Status,
+		&suggestion.CreatedAt, &suggestion.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrTransferSuggestionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer suggestion: %w", err)
+	}
+	return suggestion, nil
+}
+
+func (r *transferSuggestionRepository) List(ctx context.Context, status domain.TransferSuggestionStatus) ([]*domain.TransferSuggestion, error) {
+	query := `
+		SELECT id, from_transaction_id, to_transaction_id, score, status, created_at, updated_at
+		FROM transfer_suggestions
+		WHERE ? = '' OR status = ?
+		ORDER BY score DESC, created_at
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, status, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transfer suggestions: %w", err)
+	}
+	defer rows.Close()
+
+	var suggestions []*domain.TransferSuggestion
+	for rows.Next() {
+		suggestion := &domain.TransferSuggestion{}
+		if err := rows.Scan(&suggestion.ID, &suggestion.FromTransactionID, &suggestion.ToTransactionID, &suggestion.Score, &suggestion.Status,
+			&suggestion.CreatedAt, &suggestion.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan transfer suggestion: %w", err)
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions, rows.Err()
+}
+
+func (r *transferSuggestionRepository) Update(ctx context.Context, suggestion *domain.TransferSuggestion) error {
+	query := `
+		UPDATE transfer_suggestions
+		SET score = ?, status = ?, updated_at = ?
+		WHERE id = ?
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, suggestion.Score, suggestion.Status, suggestion.UpdatedAt, suggestion.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update transfer suggestion: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrTransferSuggestionNotFound
+	}
+	return nil
+}
+
+func (r *transferSuggestionRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM transfer_suggestions WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete transfer suggestion: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrTransferSuggestionNotFound
+	}
+	return nil
+}
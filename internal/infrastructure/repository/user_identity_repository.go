@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type userIdentityRepository struct {
+	db *sql.DB
+}
+
+// NewUserIdentityRepository creates a new user identity repository
+func NewUserIdentityRepository(db *sql.DB) domain.UserIdentityRepository {
+	return &userIdentityRepository{db: db}
+}
+
+const userIdentityColumns = `id, user_id, issuer, subject, created_at`
+
+func (r *userIdentityRepository) Create(ctx context.Context, identity *domain.UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (` + userIdentityColumns + `)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		identity.ID, identity.UserID, identity.Issuer, identity.Subject, identity.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create user identity: %w", err)
+	}
+	return nil
+}
+
+func (r *userIdentityRepository) GetBySubject(ctx context.Context, issuer, subject string) (*domain.UserIdentity, error) {
+	query := `SELECT ` + userIdentityColumns + ` FROM user_identities WHERE issuer = ? AND subject = ?`
+	identity, err := scanUserIdentity(conn(ctx, r.db).QueryRowContext(ctx, query, issuer, subject))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user identity not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user identity: %w", err)
+	}
+	return identity, nil
+}
+
+func (r *userIdentityRepository) ListByUser(ctx context.Context, userID string) ([]*domain.UserIdentity, error) {
+	query := `SELECT ` + userIdentityColumns + ` FROM user_identities WHERE user_id = ? ORDER BY created_at`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user identities: %w", err)
+	}
+	defer rows.Close()
+
+	var identities []*domain.UserIdentity
+	for rows.Next() {
+		identity, err := scanUserIdentity(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user identity: %w", err)
+		}
+		identities = append(identities, identity)
+	}
+	return identities, nil
+}
+
+func scanUserIdentity(row rowScanner) (*domain.UserIdentity, error) {
+	identity := &domain.UserIdentity{}
+	err := row.Scan(&identity.ID, &identity.UserID, &identity.Issuer, &identity.Subject, &identity.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
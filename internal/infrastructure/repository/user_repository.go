@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type userRepository struct {
+	db *sql.DB
+}
+
+// NewUserRepository creates a new user repository
+func NewUserRepository(db *sql.DB) domain.UserRepository {
+	return &userRepository{db: db}
+}
+
+const userColumns = `id, email, name, password_hash, role, totp_secret, totp_enabled, totp_required, totp_last_step, email_verified_at, disabled_at, created_at, updated_at`
+
+func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
+	query := `
+		INSERT INTO users (` + userColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		user.ID, user.Email, user.Name, user.PasswordHash, user.Role,
+		user.TOTPSecret, user.TOTPEnabled, user.TOTPRequired, user.TOTPLastStep, user.EmailVerifiedAt,
+		user.DisabledAt, user.CreatedAt, user.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	return nil
+}
+
+func (r *userRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	return r.getOne(ctx, `SELECT `+userColumns+` FROM users WHERE id = ?`, id)
+}
+
+// GetByEmail looks up a user by email, ignoring case
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return r.getOne(ctx, `SELECT `+userColumns+` FROM users WHERE email = ? COLLATE NOCASE`, email)
+}
+
+func (r *userRepository) getOne(ctx context.Context, query string, args ...any) (*domain.User, error) {
+	user, err := scanUser(conn(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
+
+func (r *userRepository) List(ctx context.Context) ([]*domain.User, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `SELECT `+userColumns+` FROM users ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*domain.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
+	query := `
+		UPDATE users
+		SET email = ?, name = ?, password_hash = ?, role = ?,
+			totp_secret = ?, totp_enabled = ?, totp_required = ?, totp_last_step = ?,
+			email_verified_at = ?, disabled_at = ?, updated_at = ?
+		WHERE id = ?
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		user.Email, user.Name, user.PasswordHash, user.Role,
+		user.TOTPSecret, user.TOTPEnabled, user.TOTPRequired, user.TOTPLastStep,
+		user.EmailVerifiedAt, user.DisabledAt, user.UpdatedAt, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) Count(ctx context.Context) (int, error) {
+	var count int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT COUNT(*) FROM users`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return count, nil
+}
+
+func scanUser(row rowScanner) (*domain.User, error) {
+	user := &domain.User{}
+	var emailVerifiedAt, disabledAt sql.NullTime
+	err := row.Scan(&user.ID, &user.Email, &user.Name, &user.PasswordHash, &user.Role,
+		&user.TOTPSecret, &user.TOTPEnabled, &user.TOTPRequired, &user.TOTPLastStep, &emailVerifiedAt,
+		&disabledAt, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if emailVerifiedAt.Valid {
+		user.EmailVerifiedAt = &emailVerifiedAt.Time
+	}
+	if disabledAt.Valid {
+		user.DisabledAt = &disabledAt.Time
+	}
+	return user, nil
+}
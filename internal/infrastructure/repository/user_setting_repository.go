@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type userSettingRepository struct {
+	db *sql.DB
+}
+
+// NewUserSettingRepository creates a new user setting repository
+func NewUserSettingRepository(db *sql.DB) domain.UserSettingRepository {
+	return &userSettingRepository{db: db}
+}
+
+// Set inserts the setting or replaces the user's existing value for its key
+func (r *userSettingRepository) Set(ctx context.Context, setting *domain.UserSetting) error {
+	query := `
+		INSERT INTO user_settings (user_id, key, value, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(user_id, key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, setting.UserID, setting.Key, setting.Value, setting.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save user setting: %w", err)
+	}
+	return nil
+}
+
+func (r *userSettingRepository) ListByUser(ctx context.Context, userID string) ([]*domain.UserSetting, error) {
+	query := `
+		SELECT user_id, key, value, updated_at
+		FROM user_settings
+		WHERE user_id = ?
+		ORDER BY key
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user settings: %w", err)
+	}
+	defer rows.Close()
+
+	var settings []*domain.UserSetting
+	for rows.Next() {
+		setting := &domain.UserSetting{}
+		if err := rows.Scan(&setting.UserID, &setting.Key, &setting.Value, &setting.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan user setting: %w", err)
+		}
+		settings = append(settings, setting)
+	}
+	return settings, nil
+}
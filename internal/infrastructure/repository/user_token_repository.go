@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type userTokenRepository struct {
+	db *sql.DB
+}
+
+// NewUserTokenRepository creates a new user token repository
+func NewUserTokenRepository(db *sql.DB) domain.UserTokenRepository {
+	return &userTokenRepository{db: db}
+}
+
+const userTokenColumns = `id, user_id, purpose, token_hash, email, expires_at, used_at, created_at`
+
+func (r *userTokenRepository) Create(ctx context.Context, token *domain.UserToken) error {
+	query := `
+		INSERT INTO user_tokens (` + userTokenColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		token.ID, token.UserID, token.Purpose, token.TokenHash, token.Email, token.ExpiresAt, token.UsedAt, token.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create user token: %w", err)
+	}
+	return nil
+}
+
+func (r *userTokenRepository) GetByHash(ctx context.Context, purpose domain.UserTokenPurpose, tokenHash string) (*domain.UserToken, error) {
+	query := `SELECT ` + userTokenColumns + ` FROM user_tokens WHERE purpose = ? AND token_hash = ?`
+	token := &domain.UserToken{}
+	var usedAt sql.NullTime
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, purpose, tokenHash).Scan(
+		&token.ID, &token.UserID, &token.Purpose, &token.TokenHash, &token.Email, &token.ExpiresAt, &usedAt, &token.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user token not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user token: %w", err)
+	}
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+	return token, nil
+}
+
+// MarkUsed records that an unused token has been used
+// Returns an error if the token was already used, so a token can't be redeemed twice.
+func (r *userTokenRepository) MarkUsed(ctx context.Context, id string, usedAt time.Time) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE user_tokens SET used_at = ? WHERE id = ? AND used_at IS NULL`, usedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark user token used: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("user token not found")
+	}
+	return nil
+}
+
+func (r *userTokenRepository) DeleteByUser(ctx context.Context, userID string, purpose domain.UserTokenPurpose) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM user_tokens WHERE user_id = ? AND purpose = ?`, userID, purpose)
+	if err != nil {
+		return fmt.Errorf("failed to delete user tokens: %w", err)
+	}
+	return nil
+}
+
+// CountExpired counts tokens that expired before the given time
+func (r *userTokenRepository) CountExpired(ctx context.Context, before time.Time) (int64, error) {
+	var count int64
+	if err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT COUNT(*) FROM user_tokens WHERE expires_at < ?`, before).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count expired user tokens: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteExpired removes tokens that expired before the given time, used or not
+func (r *userTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM user_tokens WHERE expires_at < ?`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired user tokens: %w", err)
+	}
+	return result.RowsAffected()
+}
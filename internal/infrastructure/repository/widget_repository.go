@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+type widgetRepository struct {
+	db *sql.DB
+}
+
+// NewWidgetRepository creates a new widget repository
+func NewWidgetRepository(db *sql.DB) domain.WidgetRepository {
+	return &widgetRepository{db: db}
+}
+
+const widgetColumns = `id, name, category_id, fields, token_hash, created_at, updated_at`
+
+func (r *widgetRepository) Create(ctx context.Context, widget *domain.Widget) error {
+	fields, err := json.Marshal(widget.Fields)
+	if err != nil {
+		return fmt.Errorf("failed to encode widget fields: %w", err)
+	}
+
+	query := `
+		INSERT INTO widgets (` + widgetColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = conn(ctx, r.db).ExecContext(ctx, query,
+		widget.ID, widget.Name, widget.CategoryID, string(fields), widget.TokenHash, widget.CreatedAt, widget.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create widget: %w", err)
+	}
+	return nil
+}
+
+func (r *widgetRepository) GetByID(ctx context.Context, id string) (*domain.Widget, error) {
+	return r.getOne(ctx, `SELECT `+widgetColumns+` FROM widgets WHERE id = ?`, id)
+}
+
+func (r *widgetRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.Widget, error) {
+	return r.getOne(ctx, `SELECT `+widgetColumns+` FROM widgets WHERE token_hash = ?`, tokenHash)
+}
+
+func (r *widgetRepository) getOne(ctx context.Context, query string, args ...any) (*domain.Widget, error) {
+	widget, err := scanWidget(conn(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrWidgetNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get widget: %w", err)
+	}
+	return widget, nil
+}
+
+func (r *widgetRepository) List(ctx context.Context) ([]*domain.Widget, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `SELECT `+widgetColumns+` FROM widgets ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list widgets: %w", err)
+	}
+	defer rows.Close()
+
+	var widgets []*domain.Widget
+	for rows.Next() {
+		widget, err := scanWidget(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan widget: %w", err)
+		}
+		widgets = append(widgets, widget)
+	}
+	return widgets, rows.Err()
+}
+
+func (r *widgetRepository) Update(ctx context.Context, widget *domain.Widget) error {
+	fields, err := json.Marshal(widget.Fields)
+	if err != nil {
+		return fmt.Errorf("failed to encode widget fields: %w", err)
+	}
+
+	query := `
+		UPDATE widgets
+		SET name = ?, category_id = ?, fields = ?, updated_at = ?
+		WHERE id = ?
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, widget.Name, widget.CategoryID, string(fields), widget.UpdatedAt, widget.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update widget: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrWidgetNotFound
+	}
+	return nil
+}
+
+func (r *widgetRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM widgets WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete widget: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrWidgetNotFound
+	}
+	return nil
+}
+
+func scanWidget(row rowScanner) (*domain.Widget, error) {
+	widget := &domain.Widget{}
+	var fields string
+	err := row.Scan(&widget.ID, &widget.Name, &widget.CategoryID, &fields, &widget.TokenHash, &widget.CreatedAt, &widget.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(fields), &widget.Fields); err != nil {
+		return nil, fmt.Errorf("failed to decode widget fields: %w", err)
+	}
+	return widget, nil
+}
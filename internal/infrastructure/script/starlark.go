@@ -0,0 +1,151 @@
+package script
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+
+	"github.com/billybbuffum/budget/internal/application"
+)
+
+// maxSteps bounds the work a single load or call may do, whatever the timeout
+const maxSteps = 1_000_000
+
+// Runner runs Starlark scripts (https://github.com/bazelbuild/starlark)
+// Scripts only see the Starlark built-ins: there is no load(), file, network or clock
+// access. while loops and recursion are not allowed, and every load or call is
+// cancelled after the timeout or maxSteps execution steps.
+type Runner struct {
+	timeout time.Duration
+}
+
+// NewRunner creates a new Starlark runner
+func NewRunner(timeout time.Duration) *Runner {
+	return &Runner{timeout: timeout}
+}
+
+// Load runs the script's top level and returns its functions
+func (r *Runner) Load(ctx context.Context, source string) (application.Script, error) {
+	thread, done := r.thread(ctx, "load")
+	defer done()
+
+	globals, err := starlark.ExecFileOptions(&syntax.FileOptions{}, thread, "script.star", source, nil)
+	if err != nil {
+		return nil, scriptError(err)
+	}
+	return &loadedScript{runner: r, globals: globals}, nil
+}
+
+type loadedScript struct {
+	runner  *Runner
+	globals starlark.StringDict // Frozen once loaded, so calls can't change them
+}
+
+func (s *loadedScript) Has(function string) bool {
+	_, ok := s.globals[function].(starlark.Callable)
+	return ok
+}
+
+func (s *loadedScript) Call(ctx context.Context, function string, arg map[string]interface{}) (interface{}, error) {
+	fn, ok := s.globals[function].(starlark.Callable)
+	if !ok {
+		return nil, fmt.Errorf("script does not define %s", function)
+	}
+
+	value, err := toStarlark(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	thread, done := s.runner.thread(ctx, function)
+	defer done()
+
+	result, err := starlark.Call(thread, fn, starlark.Tuple{value}, nil)
+	if err != nil {
+		return nil, scriptError(err)
+	}
+	return fromStarlark(result)
+}
+
+// thread creates a sandboxed thread that is cancelled after the timeout or when ctx ends
+func (r *Runner) thread(ctx context.Context, name string) (*starlark.Thread, func()) {
+	thread := &starlark.Thread{
+		Name:  name,
+		Print: func(*starlark.Thread, string) {}, // Scripts have no output
+	}
+	thread.SetMaxExecutionSteps(maxSteps)
+
+	timer := time.AfterFunc(r.timeout, func() { thread.Cancel("timed out") })
+	stop := context.AfterFunc(ctx, func() { thread.Cancel(ctx.Err().Error()) })
+	return thread, func() {
+		timer.Stop()
+		stop()
+	}
+}
+
+// scriptError reports a Starlark error with its script backtrace
+func scriptError(err error) error {
+	if evalErr, ok := err.(*starlark.EvalError); ok {
+		return fmt.Errorf("script error: %s", evalErr.Backtrace())
+	}
+	return fmt.Errorf("script error: %w", err)
+}
+
+// toStarlark converts call arguments; maps become structs so scripts can write txn.amount
+func toStarlark(v interface{}) (starlark.Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return starlark.None, nil
+	case string:
+		return starlark.String(v), nil
+	case bool:
+		return starlark.Bool(v), nil
+	case int:
+		return starlark.MakeInt(v), nil
+	case int64:
+		return starlark.MakeInt64(v), nil
+	case float64:
+		return starlark.Float(v), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		fields := make(starlark.StringDict, len(v))
+		for _, key := range keys {
+			field, err := toStarlark(v[key])
+			if err != nil {
+				return nil, err
+			}
+			fields[key] = field
+		}
+		return starlarkstruct.FromStringDict(starlarkstruct.Default, fields), nil
+	}
+	return nil, fmt.Errorf("unsupported script argument type %T", v)
+}
+
+// fromStarlark converts a script's return value
+func fromStarlark(v starlark.Value) (interface{}, error) {
+	switch v := v.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.String:
+		return string(v), nil
+	case starlark.Bool:
+		return bool(v), nil
+	case starlark.Int:
+		if i, ok := v.Int64(); ok {
+			return i, nil
+		}
+	case starlark.Float:
+		return float64(v), nil
+	}
+	return nil, fmt.Errorf("script returned unsupported %s value", v.Type())
+}
@@ -0,0 +1,95 @@
+package script
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+const categorizeScript = `
+GROCERS = ["ALDI", "TRADER JOE"]
+
+def categorize(txn):
+    for grocer in GROCERS:
+        if grocer in txn.description.upper():
+            return "Groceries"
+    if txn.amount > 0:
+        return None
+    return "Shopping"
+`
+
+func TestRunnerCategorize(t *testing.T) {
+	ctx := context.Background()
+	script, err := NewRunner(time.Second).Load(ctx, categorizeScript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !script.Has("categorize") || script.Has("GROCERS") {
+		t.Error("expected only functions to count as hooks")
+	}
+
+	tests := []struct {
+		description string
+		amount      int64
+		want        interface{}
+	}{
+		{"Aldi Store 12", -1250, "Groceries"},
+		{"Paycheck", 250000, nil},
+		{"Bookshop", -900, "Shopping"},
+	}
+	for _, tt := range tests {
+		got, err := script.Call(ctx, "categorize", map[string]interface{}{"description": tt.description, "amount": tt.amount})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.description, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.description, got, tt.want)
+		}
+	}
+}
+
+func TestRunnerSandbox(t *testing.T) {
+	ctx := context.Background()
+	runner := NewRunner(50 * time.Millisecond)
+
+	tests := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{
+		{"no load", `load("os.star", "open")`, "load"},
+		{"no while loops", "def f():\n    while True:\n        pass\n", "while"},
+		{"no I/O builtins", `x = open("/etc/passwd")`, "undefined"},
+		{"step limit", "def spin():\n    for i in range(100000000):\n        pass\nspin()\n", "too many steps"},
+	}
+	for _, tt := range tests {
+		_, err := runner.Load(ctx, tt.source)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestRunnerTimeout(t *testing.T) {
+	ctx := context.Background()
+	script, err := NewRunner(20*time.Millisecond).Load(ctx, `
+def categorize(txn):
+    s = ""
+    for i in range(200000):
+        s = "x" * (i % 1000)
+    return s
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	_, err = script.Call(ctx, "categorize", map[string]interface{}{})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected the call to time out, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the call to stop promptly, took %v", elapsed)
+	}
+}
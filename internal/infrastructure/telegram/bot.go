@@ -0,0 +1,186 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/application"
+	"github.com/billybbuffum/budget/internal/domain"
+)
+
+const apiBase = "https://api.telegram.org/bot"
+
+// Bot is a built-in Telegram bot that relays chat messages to the BotService
+// It long-polls getUpdates, so no public webhook URL is needed.
+type Bot struct {
+	token        string
+	botService   *application.BotService
+	summaryHour  int // Local hour (0-23) at which daily summaries are sent
+	client       *http.Client
+	lastSummary  string // Date (YYYY-MM-DD) the last daily summary went out
+	pollInterval time.Duration
+}
+
+// NewBot creates a new Telegram bot
+func NewBot(token string, botService *application.BotService, summaryHour int) *Bot {
+	return &Bot{
+		token:        token,
+		botService:   botService,
+		summaryHour:  summaryHour,
+		client:       &http.Client{Timeout: 60 * time.Second},
+		pollInterval: 5 * time.Second,
+	}
+}
+
+type update struct {
+	UpdateID int64 `json:"update_id"`
+	Message  *struct {
+		Text string `json:"text"`
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+	} `json:"message"`
+}
+
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Description string          `json:"description"`
+	Result      json.RawMessage `json:"result"`
+}
+
+// Run polls for messages and sends daily summaries until ctx is cancelled
+func (b *Bot) Run(ctx context.Context) {
+	var offset int64
+	for ctx.Err() == nil {
+		b.maybeSendDailySummaries(ctx)
+
+		updates, err := b.getUpdates(ctx, offset)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("telegram: %v", err)
+				time.Sleep(b.pollInterval)
+			}
+			continue
+		}
+
+		for _, u := range updates {
+			offset = u.UpdateID + 1
+			if u.Message == nil || u.Message.Text == "" {
+				continue
+			}
+			b.handleMessage(ctx, u.Message.Chat.ID, u.Message.Text)
+		}
+	}
+}
+
+func (b *Bot) handleMessage(ctx context.Context, chatID int64, text string) {
+	id := strconv.FormatInt(chatID, 10)
+
+	// "/start <token>" or "/link <token>" links this chat to a bot chat created in the app
+	command, token, _ := strings.Cut(strings.TrimSpace(text), " ")
+	if (command == "/start" || command == "/link") && token != "" {
+		if _, err := b.botService.LinkChat(ctx, strings.TrimSpace(token), domain.BotPlatformTelegram, id); err != nil {
+			b.send(ctx, chatID, "Could not link this chat: "+err.Error())
+			return
+		}
+		b.send(ctx, chatID, "This chat is now linked to your budget. Send \"help\" for commands.")
+		return
+	}
+
+	chat, err := b.botService.GetLinkedChat(ctx, domain.BotPlatformTelegram, id)
+	if err != nil {
+		b.send(ctx, chatID, "This chat isn't linked yet. Create a Telegram bot chat in the budget app and send /start <token>.")
+		return
+	}
+
+	reply, err := b.botService.HandleMessage(ctx, chat, text)
+	if err != nil {
+		b.send(ctx, chatID, err.Error())
+		return
+	}
+	b.send(ctx, chatID, reply.Text)
+}
+
+// maybeSendDailySummaries sends the daily summary once per day after summaryHour
+func (b *Bot) maybeSendDailySummaries(ctx context.Context) {
+	now := time.Now()
+	today := now.Format("2006-01-02")
+	if now.Hour() < b.summaryHour || b.lastSummary == today {
+		return
+	}
+	b.lastSummary = today
+
+	chats, err := b.botService.ListChatsForPlatform(ctx, domain.BotPlatformTelegram)
+	if err != nil {
+		log.Printf("telegram: failed to list chats: %v", err)
+		return
+	}
+
+	var summary *application.BotDailySummary
+	for _, chat := range chats {
+		if !chat.DailySummary {
+			continue
+		}
+		if summary == nil {
+			if summary, err = b.botService.DailySummary(ctx, now); err != nil {
+				log.Printf("telegram: failed to build daily summary: %v", err)
+				return
+			}
+		}
+		chatID, err := strconv.ParseInt(chat.ChatID, 10, 64)
+		if err != nil {
+			continue
+		}
+		b.send(ctx, chatID, summary.Text)
+	}
+}
+
+func (b *Bot) getUpdates(ctx context.Context, offset int64) ([]update, error) {
+	var updates []update
+	err := b.call(ctx, "getUpdates", map[string]any{"offset": offset, "timeout": 30}, &updates)
+	return updates, err
+}
+
+func (b *Bot) send(ctx context.Context, chatID int64, text string) {
+	if err := b.call(ctx, "sendMessage", map[string]any{"chat_id": chatID, "text": text}, nil); err != nil {
+		log.Printf("telegram: failed to send message: %v", err)
+	}
+}
+
+func (b *Bot) call(ctx context.Context, method string, params map[string]any, result any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiBase+b.token+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var parsed apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return fmt.Errorf("%s: failed to decode response: %w", method, err)
+	}
+	if !parsed.OK {
+		return fmt.Errorf("%s failed: %s", method, parsed.Description)
+	}
+	if result != nil {
+		return json.Unmarshal(parsed.Result, result)
+	}
+	return nil
+}
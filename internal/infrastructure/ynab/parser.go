@@ -0,0 +1,306 @@
+package ynab
+
+import (
+	"bufio"
+	stdcsv "encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/billybbuffum/budget/internal/infrastructure/csv"
+)
+
+// RegisterRow is one transaction from a YNAB register export
+// Split transactions are exported as one row per split.
+type RegisterRow struct {
+	Account       string
+	Date          time.Time
+	Payee         string
+	CategoryGroup string // Empty when uncategorized
+	Category      string
+	Memo          string
+	Amount        int64 // Inflow minus outflow, in cents
+}
+
+// BudgetRow is one category's month from a YNAB budget export
+type BudgetRow struct {
+	Month         string // YYYY-MM
+	CategoryGroup string
+	Category      string
+	Budgeted      int64 // In cents
+}
+
+// transferPrefix starts the payee of a transfer, e.g. "Transfer : Savings"
+const transferPrefix = "Transfer :"
+
+// StartingBalancePayee is the payee YNAB gives an account's opening balance
+const StartingBalancePayee = "Starting Balance"
+
+// incomeCategories are where income goes, by group; they aren't budget categories
+// New YNAB uses "Inflow: Ready to Assign" (earlier "To be Budgeted"); YNAB 4 uses
+// "Income: Available this month" or "next month".
+var incomeCategories = map[string]bool{
+	"inflow":                   true,
+	"income":                   true,
+	"internal master category": true,
+}
+
+// TransferAccount returns the other account of a transfer, or "" if the row isn't one
+func (r RegisterRow) TransferAccount() string {
+	if !strings.HasPrefix(r.Payee, transferPrefix) {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(r.Payee, transferPrefix))
+}
+
+// IsIncome reports whether the row is income for the budget rather than categorized
+func (r RegisterRow) IsIncome() bool {
+	return IsIncomeGroup(r.CategoryGroup)
+}
+
+// IsIncomeGroup reports whether a category group is YNAB's income pseudo-group
+func IsIncomeGroup(group string) bool {
+	return incomeCategories[strings.ToLower(group)]
+}
+
+// dateLayouts are the date formats YNAB can export, tried in order
+// A layout is only used if it reads every date in the file; month-first wins when a file
+// could be either, as that is YNAB's default.
+var dateLayouts = []string{
+	"01/02/2006",
+	"02/01/2006",
+	"2006-01-02",
+	"2006/01/02",
+	"02.01.2006",
+	"02-01-2006",
+	"1/2/2006",
+	"2/1/2006",
+}
+
+// monthLayouts are the ways a budget export writes its month column
+var monthLayouts = []string{
+	"Jan 2006",
+	"January 2006",
+	"2006-01",
+	"01/2006",
+}
+
+// Parser reads YNAB's register and budget CSV exports
+// Both the current YNAB and YNAB 4 column names are understood.
+type Parser struct{}
+
+// NewParser creates a new YNAB parser
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// ParseRegister reads a register export
+func (p *Parser) ParseRegister(reader io.Reader) ([]RegisterRow, error) {
+	header, rows, err := readCSV(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := struct{ account, date, payee, group, category, memo, outflow, inflow int }{}
+	for _, c := range []struct {
+		names []string
+		index *int
+	}{
+		{[]string{"Account"}, &columns.account},
+		{[]string{"Date"}, &columns.date},
+		{[]string{"Payee"}, &columns.payee},
+		{[]string{"Category Group", "Master Category"}, &columns.group},
+		{[]string{"Sub Category", "Category"}, &columns.category}, // YNAB 4's "Category" is "Group: Category"
+		{[]string{"Memo"}, &columns.memo},
+		{[]string{"Outflow"}, &columns.outflow},
+		{[]string{"Inflow"}, &columns.inflow},
+	} {
+		if *c.index, err = findColumn(header, c.names); err != nil {
+			return nil, fmt.Errorf("not a YNAB register export: %w", err)
+		}
+	}
+
+	layout, err := dateLayout(rows, columns.date)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]RegisterRow, 0, len(rows))
+	for i, row := range rows {
+		line := i + 2
+		if isBlank(row) {
+			continue
+		}
+
+		date, err := time.Parse(layout, field(row, columns.date))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q", line, field(row, columns.date))
+		}
+		outflow, err := parseAmount(field(row, columns.outflow))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		inflow, err := parseAmount(field(row, columns.inflow))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		account := field(row, columns.account)
+		if account == "" {
+			return nil, fmt.Errorf("line %d: account is required", line)
+		}
+		result = append(result, RegisterRow{
+			Account:       account,
+			Date:          date.UTC(),
+			Payee:         field(row, columns.payee),
+			CategoryGroup: field(row, columns.group),
+			Category:      field(row, columns.category),
+			Memo:          field(row, columns.memo),
+			Amount:        inflow - outflow,
+		})
+	}
+	return result, nil
+}
+
+// ParseBudget reads a budget export
+func (p *Parser) ParseBudget(reader io.Reader) ([]BudgetRow, error) {
+	header, rows, err := readCSV(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := struct{ month, group, category, budgeted int }{}
+	for _, c := range []struct {
+		names []string
+		index *int
+	}{
+		{[]string{"Month"}, &columns.month},
+		{[]string{"Category Group", "Master Category"}, &columns.group},
+		{[]string{"Sub Category", "Category"}, &columns.category},
+		{[]string{"Assigned", "Budgeted"}, &columns.budgeted},
+	} {
+		if *c.index, err = findColumn(header, c.names); err != nil {
+			return nil, fmt.Errorf("not a YNAB budget export: %w", err)
+		}
+	}
+
+	result := make([]BudgetRow, 0, len(rows))
+	for i, row := range rows {
+		line := i + 2
+		if isBlank(row) {
+			continue
+		}
+
+		month, err := parseMonth(field(row, columns.month))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		budgeted, err := parseAmount(field(row, columns.budgeted))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		result = append(result, BudgetRow{
+			Month:         month,
+			CategoryGroup: field(row, columns.group),
+			Category:      field(row, columns.category),
+			Budgeted:      budgeted,
+		})
+	}
+	return result, nil
+}
+
+// readCSV reads a whole export, returning its header and rows
+func readCSV(reader io.Reader) ([]string, [][]string, error) {
+	// Drop Excel's byte order mark first; left in front of a quoted header it spoils the quoting
+	buffered := bufio.NewReader(reader)
+	if bom, err := buffered.Peek(3); err == nil && string(bom) == "\ufeff" {
+		buffered.Discard(3)
+	}
+
+	r := stdcsv.NewReader(buffered)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV file: %w", err)
+	}
+	if len(records) < 1 {
+		return nil, nil, fmt.Errorf("CSV file is empty")
+	}
+
+	return records[0], records[1:], nil
+}
+
+// dateLayout returns the first layout that reads every date in the column
+func dateLayout(rows [][]string, column int) (string, error) {
+	for _, layout := range dateLayouts {
+		matched := true
+		for _, row := range rows {
+			if isBlank(row) {
+				continue
+			}
+			if _, err := time.Parse(layout, field(row, column)); err != nil {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return layout, nil
+		}
+	}
+	return "", fmt.Errorf("unrecognized date format")
+}
+
+// parseMonth converts a budget export's month, e.g. "Jan 2024", to YYYY-MM
+func parseMonth(value string) (string, error) {
+	for _, layout := range monthLayouts {
+		if month, err := time.Parse(layout, value); err == nil {
+			return month.Format("2006-01"), nil
+		}
+	}
+	return "", fmt.Errorf("invalid month %q", value)
+}
+
+// parseAmount converts an amount in the budget's currency format to cents
+// Currency symbols are dropped; empty means zero.
+func parseAmount(value string) (int64, error) {
+	value = strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || strings.ContainsRune(".,-()", r) {
+			return r
+		}
+		return -1
+	}, value)
+	if value == "" {
+		return 0, nil
+	}
+	return csv.ParseAmount(value)
+}
+
+// findColumn returns the first of names found in the header
+func findColumn(header []string, names []string) (int, error) {
+	for _, name := range names {
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), name) {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("no %q column", names[0])
+}
+
+func field(row []string, index int) string {
+	if index < 0 || index >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[index])
+}
+
+func isBlank(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
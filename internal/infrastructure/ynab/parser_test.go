@@ -0,0 +1,63 @@
+package ynab
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRegister(t *testing.T) {
+	file := "\ufeff\"Account\",\"Flag\",\"Date\",\"Payee\",\"Category Group/Category\",\"Category Group\",\"Category\",\"Memo\",\"Outflow\",\"Inflow\",\"Cleared\"\n" +
+		"\"Checking\",\"\",\"01/02/2025\",\"Starting Balance\",\"Inflow: Ready to Assign\",\"Inflow\",\"Ready to Assign\",\"\",$0.00,\"$1,500.00\",\"Reconciled\"\n" +
+		"\"Checking\",\"Red\",\"01/15/2025\",\"Market\",\"Everyday: Groceries\",\"Everyday\",\"Groceries\",\"weekly shop\",$84.20,$0.00,\"Cleared\"\n" +
+		"\"Checking\",\"\",\"01/20/2025\",\"Transfer : Savings\",\"\",\"\",\"\",\"\",$200.00,$0.00,\"Cleared\"\n"
+
+	rows, err := NewParser().ParseRegister(strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(rows))
+	}
+	if rows[0].Amount != 150000 || !rows[0].IsIncome() || rows[0].Payee != StartingBalancePayee {
+		t.Errorf("unexpected starting balance %+v", rows[0])
+	}
+	if rows[1].Amount != -8420 || rows[1].CategoryGroup != "Everyday" || rows[1].Category != "Groceries" || rows[1].Memo != "weekly shop" || rows[1].Date.Format("2006-01-02") != "2025-01-15" {
+		t.Errorf("unexpected outflow %+v", rows[1])
+	}
+	if rows[2].TransferAccount() != "Savings" || rows[1].TransferAccount() != "" {
+		t.Errorf("expected only the last row to be a transfer to Savings, got %q", rows[2].TransferAccount())
+	}
+
+	// YNAB 4 names its columns differently and writes day-first dates in some locales
+	ynab4 := "Account,Flag,Check Number,Date,Payee,Category,Master Category,Sub Category,Memo,Outflow,Inflow,Cleared,Running Balance\n" +
+		"Cash,,,25/03/2024,Cafe,Fun: Coffee,Fun,Coffee,,€3.50,€0.00,U,-€3.50\n"
+	rows, err = NewParser().ParseRegister(strings.NewReader(ynab4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows[0].Category != "Coffee" || rows[0].CategoryGroup != "Fun" || rows[0].Amount != -350 || rows[0].Date.Format("2006-01-02") != "2024-03-25" {
+		t.Errorf("unexpected YNAB 4 row %+v", rows[0])
+	}
+
+	if _, err := NewParser().ParseRegister(strings.NewReader("Date,Amount\n01/02/2025,1\n")); err == nil {
+		t.Error("expected a file without YNAB's columns to be rejected")
+	}
+}
+
+func TestParseBudget(t *testing.T) {
+	file := "\"Month\",\"Category Group/Category\",\"Category Group\",\"Category\",\"Assigned\",\"Activity\",\"Available\"\n" +
+		"\"Jan 2025\",\"Everyday: Groceries\",\"Everyday\",\"Groceries\",$400.00,-$84.20,$315.80\n" +
+		"\"Feb 2025\",\"Everyday: Groceries\",\"Everyday\",\"Groceries\",$0.00,$0.00,$315.80\n"
+
+	rows, err := NewParser().ParseBudget(strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0].Month != "2025-01" || rows[0].Budgeted != 40000 || rows[1].Month != "2025-02" || rows[1].Budgeted != 0 {
+		t.Errorf("unexpected rows %+v", rows)
+	}
+
+	if _, err := NewParser().ParseBudget(strings.NewReader("Month,Category Group,Category,Assigned\nsoon,A,B,$1.00\n")); err == nil {
+		t.Error("expected an invalid month to be rejected")
+	}
+}
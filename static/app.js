@@ -39,12 +39,47 @@ function updateThemeIcon(theme) {
 // Make toggleTheme available globally for onclick handler
 window.toggleTheme = toggleTheme;
 
+// Currency amounts are kept in, as minor units (cents, or whole yen with 0 decimals)
+let budgetCurrency = { code: 'USD', decimals: 2 };
+
+async function loadCurrency() {
+    try {
+        const response = await fetch('/api/currency');
+        if (response.ok) {
+            budgetCurrency = await response.json();
+        }
+    } catch (error) {
+        console.error('Failed to load currency:', error);
+    }
+    const step = (1 / 10 ** budgetCurrency.decimals).toFixed(budgetCurrency.decimals);
+    document.querySelectorAll('input[type="number"][step="0.01"]').forEach(input => {
+        input.step = step;
+        if (input.min === '0.01') {
+            input.min = step;
+        }
+    });
+}
+
 // Utility functions
+function toMinorUnits(amount) {
+    return Math.round(amount * 10 ** budgetCurrency.decimals);
+}
+
+function fromMinorUnits(minor) {
+    return minor / 10 ** budgetCurrency.decimals;
+}
+
+function formatAmountInput(minor) {
+    return fromMinorUnits(minor).toFixed(budgetCurrency.decimals);
+}
+
 function formatCurrency(cents) {
     return new Intl.NumberFormat('en-US', {
         style: 'currency',
-        currency: 'USD'
-    }).format(cents / 100);
+        currency: budgetCurrency.code,
+        minimumFractionDigits: budgetCurrency.decimals,
+        maximumFractionDigits: budgetCurrency.decimals
+    }).format(fromMinorUnits(cents));
 }
 
 function formatDate(dateString) {
@@ -92,6 +127,12 @@ async function apiCall(endpoint, options = {}) {
             ...options
         });
 
+        // Signed out (or session revoked) while authentication is required
+        if (response.status === 401 && !endpoint.startsWith('/auth/')) {
+            window.location.href = '/login.html';
+            return null;
+        }
+
         if (!response.ok) {
             const error = await response.text();
             throw new Error(error || `HTTP ${response.status}`);
@@ -171,7 +212,18 @@ async function loadBudgetView() {
 
         readyToAssignEl.textContent = formatCurrency(readyToAssign);
 
-        if (readyToAssign === 0) {
+        if (summaryData?.degraded) {
+            showToast(`Some of this month couldn't be calculated (${summaryData.errors.join(', ')}). Try reloading.`, 'error');
+        }
+
+        if (summaryData && summaryData.ready_to_assign === null) {
+            // Couldn't be calculated - don't claim everything is assigned
+            readyToAssignEl.textContent = '—';
+            readyToAssignEl.className = 'text-3xl font-bold text-gray-500 dark:text-gray-400';
+            readyToAssignBox.className = 'bg-gray-50 dark:bg-gray-800 border border-gray-200 dark:border-gray-700 rounded-lg p-4 mb-6 transition-colors';
+            readyToAssignCheckmark.className = 'text-3xl hidden';
+            readyToAssignMessage.textContent = "Ready to Assign couldn't be calculated";
+        } else if (readyToAssign === 0) {
             // All money assigned - show green with checkmark
             readyToAssignEl.className = 'text-3xl font-bold text-green-600 dark:text-green-400';
             readyToAssignBox.className = 'bg-green-50 dark:bg-green-900/30 border border-green-200 dark:border-green-800 rounded-lg p-4 mb-6 transition-colors';
@@ -191,6 +243,8 @@ async function loadBudgetView() {
             readyToAssignMessage.textContent = 'Money available to allocate to categories';
         }
 
+        renderEmergencyFund();
+
         const budgetCategories = document.getElementById('budget-categories');
 
         if (categories.length === 0) {
@@ -216,6 +270,28 @@ async function loadBudgetView() {
     }
 }
 
+// Emergency fund coverage card (hidden until a fund category or account is configured)
+async function renderEmergencyFund() {
+    const box = document.getElementById('emergency-fund-box');
+    try {
+        const coverage = await apiCall(`/reports/emergency-fund?period=${getCurrentPeriod()}`);
+        if (!coverage || (coverage.category_ids.length === 0 && coverage.account_ids.length === 0)) {
+            box.classList.add('hidden');
+            return;
+        }
+
+        document.getElementById('emergency-fund-months').textContent =
+            `${coverage.months_covered.toFixed(1)} of ${coverage.target_months} months`;
+        document.getElementById('emergency-fund-progress').style.width = `${coverage.progress_percent}%`;
+        document.getElementById('emergency-fund-message').textContent = coverage.shortfall > 0
+            ? `${formatCurrency(coverage.shortfall)} to go (${formatCurrency(coverage.average_essential_spend)}/month essential spending)`
+            : 'Target reached!';
+        box.classList.remove('hidden');
+    } catch (error) {
+        box.classList.add('hidden');
+    }
+}
+
 function renderBudgetWithGroups(summary) {
     let html = '';
 
@@ -763,10 +839,13 @@ async function loadAccountsView() {
                     <div class="flex justify-between items-center">
                         <div>
                             <div class="font-semibold text-gray-800 dark:text-gray-100">${account.name}</div>
-                            <div class="text-sm text-gray-500 dark:text-gray-400 capitalize">${account.type}</div>
+                            <div class="text-sm text-gray-500 dark:text-gray-400 capitalize">${account.type}${account.hidden_from_dashboard ? ' · hidden from dashboard' : ''}</div>
                         </div>
                         <div class="text-right">
                             <div class="text-xl font-bold ${balanceClass}">${balanceDisplay}</div>
+                            <button onclick="toggleAccountHidden('${account.id}', ${!account.hidden_from_dashboard})" class="text-xs text-blue-600 dark:text-blue-400 hover:underline">
+                                ${account.hidden_from_dashboard ? 'Show on dashboard' : 'Hide from dashboard'}
+                            </button>
                         </div>
                     </div>
                 </div>
@@ -777,6 +856,23 @@ async function loadAccountsView() {
     }
 }
 
+// Hide an account from the dashboard, or show it again
+async function toggleAccountHidden(accountId, hidden) {
+    try {
+        await apiCall(`/accounts/${accountId}/hidden`, {
+            method: 'PUT',
+            body: JSON.stringify({ hidden })
+        });
+        showToast(hidden ? 'Account hidden from dashboard' : 'Account shown on dashboard');
+        await loadAccountsView();
+        await renderAccountsSidebar();
+    } catch (error) {
+        console.error('Failed to change account visibility:', error);
+    }
+}
+
+window.toggleAccountHidden = toggleAccountHidden;
+
 // Transactions view
 async function loadTransactionsView() {
     try {
@@ -1028,7 +1124,7 @@ function showAllocateModal(categoryId, categoryName, currentAmount = 0) {
     document.getElementById('allocation-category-id').value = categoryId;
     document.getElementById('allocation-current-amount').value = currentAmount; // Store in cents
     document.getElementById('allocation-category-name').textContent = categoryName;
-    document.getElementById('allocation-amount').value = (currentAmount / 100).toFixed(2);
+    document.getElementById('allocation-amount').value = formatAmountInput(currentAmount);
     document.getElementById('allocation-notes').value = '';
     showModal('allocation-modal');
 }
@@ -1048,7 +1144,7 @@ function parseAllocationInput(inputValue, currentAmountInCents) {
             return { valid: false, error: 'Invalid number after operator' };
         }
 
-        const currentAmountInDollars = currentAmountInCents / 100;
+        const currentAmountInDollars = fromMinorUnits(currentAmountInCents);
         let result;
 
         switch (operator) {
@@ -1073,7 +1169,7 @@ function parseAllocationInput(inputValue, currentAmountInCents) {
             return { valid: false, error: 'Result cannot be negative' };
         }
 
-        return { valid: true, amountInCents: Math.round(result * 100) };
+        return { valid: true, amountInCents: toMinorUnits(result) };
     }
 
     // No operator, treat as absolute value
@@ -1083,7 +1179,7 @@ function parseAllocationInput(inputValue, currentAmountInCents) {
         return { valid: false, error: 'Please enter a valid amount' };
     }
 
-    return { valid: true, amountInCents: Math.round(amount * 100) };
+    return { valid: true, amountInCents: toMinorUnits(amount) };
 }
 
 // Inline editing for budget allocation
@@ -1098,7 +1194,7 @@ async function startInlineEdit(categoryId, categoryName, currentAmount) {
     // Create input element
     const input = document.createElement('input');
     input.type = 'text';
-    input.value = (currentAmount / 100).toFixed(2);
+    input.value = formatAmountInput(currentAmount);
     input.className = 'w-24 border border-blue-500 dark:border-blue-400 rounded px-2 py-1 text-center font-semibold bg-white dark:bg-gray-700 text-gray-800 dark:text-gray-100 focus:outline-none focus:ring-2 focus:ring-blue-500 dark:focus:ring-blue-400';
     input.placeholder = 'e.g. +50, -25, 100';
 
@@ -1533,6 +1629,13 @@ document.addEventListener('DOMContentLoaded', function() {
     // Initialize theme
     initializeTheme();
 
+    // New budgets start in the setup wizard
+    fetch('/api/setup').then(r => r.json()).then(setup => {
+        if (setup.needs_template) {
+            window.location.href = '/setup.html';
+        }
+    });
+
     // Add listener for account change to update amount hint text
     document.getElementById('transaction-account').addEventListener('change', function() {
         const amountLabel = document.getElementById('amount-label');
@@ -1630,7 +1733,7 @@ document.addEventListener('DOMContentLoaded', function() {
         }
 
         // Convert amount to cents
-        const amountInCents = Math.round(amount * 100);
+        const amountInCents = toMinorUnits(amount);
 
         try {
             await apiCall('/transactions', {
@@ -1678,7 +1781,7 @@ document.addEventListener('DOMContentLoaded', function() {
         }
 
         // Convert amount to cents
-        const amountInCents = Math.round(amount * 100);
+        const amountInCents = toMinorUnits(amount);
 
         try {
             await apiCall('/transactions/transfer', {
@@ -1719,7 +1822,7 @@ document.addEventListener('DOMContentLoaded', function() {
                 body: JSON.stringify({
                     name,
                     type,
-                    balance: Math.round(balance * 100)
+                    balance: toMinorUnits(balance)
                 })
             });
 
@@ -1823,6 +1926,10 @@ document.addEventListener('DOMContentLoaded', function() {
         const formData = new FormData();
         formData.append('account_id', accountId);
         formData.append('file', file);
+        const lookback = document.getElementById('import-lookback').value;
+        if (lookback) {
+            formData.append('lookback_days', lookback);
+        }
 
         try {
             const button = e.target.querySelector('button[type="submit"]');
@@ -1845,7 +1952,11 @@ document.addEventListener('DOMContentLoaded', function() {
             button.textContent = 'Import Transactions';
             fileInput.value = '';
 
-            showToast(`Imported ${result.imported_transactions} transactions (${result.skipped_duplicates} duplicates skipped)`);
+            if (result.already_imported) {
+                showToast('This file was already imported; nothing changed');
+            } else {
+                showToast(`Imported ${result.imported_transactions} transactions (${result.skipped_duplicates} duplicates skipped)`);
+            }
 
             // Reload data
             await loadAccounts();
@@ -1910,8 +2021,11 @@ async function renderAccountsSidebar() {
         return;
     }
 
+    // Hidden accounts stay in the Accounts view but not here
+    const shownAccounts = accounts.filter(acc => !acc.hidden_from_dashboard);
+
     // Calculate total balance
-    const totalBalance = accounts.reduce((sum, acc) => sum + acc.balance, 0);
+    const totalBalance = shownAccounts.reduce((sum, acc) => sum + acc.balance, 0);
 
     let html = `
         <div class="account-item cursor-pointer p-2 rounded hover:bg-gray-100 dark:hover:bg-gray-700 border-2 border-blue-500 dark:border-blue-400" onclick="loadAccountTransactionsPanel(null)">
@@ -1920,7 +2034,7 @@ async function renderAccountsSidebar() {
         </div>
     `;
 
-    accounts.forEach(account => {
+    shownAccounts.forEach(account => {
         const isCreditCard = account.type === 'credit';
         const balanceClass = account.balance >= 0 ? 'text-green-600 dark:text-green-400' : 'text-red-600 dark:text-red-400';
 
@@ -2231,6 +2345,7 @@ window.closeImportView = closeImportView;
 // Initialize the app
 async function init() {
     try {
+        await loadCurrency();
         await loadAccounts();
         await loadCategories();
         await loadBudgetView();